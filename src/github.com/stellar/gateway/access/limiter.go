@@ -0,0 +1,66 @@
+package access
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter of every key. Buckets are kept in
+// memory, so every instance of the server limits requests separately.
+type Limiter struct {
+	lock    sync.Mutex
+	buckets map[string]*bucket
+	pruned  time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewLimiter creates a new Limiter
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: map[string]*bucket{}}
+}
+
+// Allow takes a token from the bucket of key refilled with perMinute tokens
+// per minute (up to perMinute). When the bucket is empty it returns false and
+// the time after which the next request is allowed.
+func (l *Limiter) Allow(key string, perMinute int, now time.Time) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.prune(now)
+
+	capacity := float64(perMinute)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+
+	rate := capacity / float64(time.Minute)
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updated))*rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / rate)
+	}
+	b.tokens--
+	return true, 0
+}
+
+// prune removes buckets not used for a minute. They are full again, so
+// keys with many values (ex. IP addresses) don't grow the map forever.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.pruned) < time.Minute {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+	l.pruned = now
+}
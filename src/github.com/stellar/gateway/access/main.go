@@ -0,0 +1,138 @@
+// Package access restricts who can reach the bridge API: IPFilter rejects
+// requests from IP addresses not on the allowlist (or on the denylist) and
+// RateLimiter limits requests to endpoints per API key or IP address.
+package access
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+var ipDenied = metrics.NewCounter("bridge_access_ip_denied_total", "Number of requests rejected because of the IP allowlist or denylist.")
+
+// Networks is a list of IP networks
+type Networks []*net.IPNet
+
+// ParseNetworks parses a list of networks in CIDR notation (ex.
+// "10.0.0.0/8") or single IP addresses
+func ParseNetworks(values []string) (Networks, error) {
+	networks := make(Networks, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("Invalid IP address: %s", value)
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			value = fmt.Sprintf("%s/%d", value, bits)
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid network: %s", value)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// Contains returns true when ip belongs to one of the networks
+func (n Networks) Contains(ip net.IP) bool {
+	for _, network := range n {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the IP address of the client sending r. When the request
+// comes from one of trustedProxies, the last address in X-Forwarded-For that
+// does not belong to a trusted proxy is returned. It returns nil when the
+// address cannot be parsed.
+func ClientIP(r *http.Request, trustedProxies Networks) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !trustedProxies.Contains(ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header["X-Forwarded-For"], ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if forwardedIP == nil {
+			// Addresses before an invalid one cannot be trusted
+			return ip
+		}
+		ip = forwardedIP
+		if !trustedProxies.Contains(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// IPFilter rejects requests from IP addresses not on Allow list or on Deny
+// list with 403 Forbidden
+type IPFilter struct {
+	// Allow contains allowed networks, all addresses are allowed when empty
+	Allow Networks
+	// Deny contains denied networks, it has precedence over Allow
+	Deny           Networks
+	TrustedProxies Networks
+	// OnDenied is called (when not nil) for every rejected request
+	OnDenied func(r *http.Request, ip net.IP)
+	log      *logrus.Entry
+}
+
+// NewIPFilter creates a new IPFilter
+func NewIPFilter(allow, deny, trustedProxies Networks) *IPFilter {
+	return &IPFilter{
+		Allow:          allow,
+		Deny:           deny,
+		TrustedProxies: trustedProxies,
+		log:            logrus.WithFields(logrus.Fields{"service": "IPFilter"}),
+	}
+}
+
+// Allowed returns true when requests from ip are allowed
+func (f *IPFilter) Allowed(ip net.IP) bool {
+	if ip == nil {
+		return len(f.Allow) == 0 && len(f.Deny) == 0
+	}
+	if f.Deny.Contains(ip) {
+		return false
+	}
+	return len(f.Allow) == 0 || f.Allow.Contains(ip)
+}
+
+// Middleware rejects requests from IP addresses that are not allowed
+func (f *IPFilter) Middleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ip := ClientIP(r, f.TrustedProxies)
+		if !f.Allowed(ip) {
+			ipDenied.Inc()
+			f.log.WithFields(logrus.Fields{"ip": ip.String(), "path": r.URL.Path}).Warn("Request from IP address not allowed")
+			if f.OnDenied != nil {
+				f.OnDenied(r, ip)
+			}
+			server.Write(w, protocols.IPNotAllowedError)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
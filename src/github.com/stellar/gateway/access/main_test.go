@@ -0,0 +1,177 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func request(path, remoteAddr, forwardedFor string) *http.Request {
+	r := httptest.NewRequest("POST", path, nil)
+	r.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	return r
+}
+
+func mustParse(t *testing.T, values ...string) Networks {
+	networks, err := ParseNetworks(values)
+	require.NoError(t, err)
+	return networks
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks := mustParse(t, "10.0.0.0/8", "203.0.113.7", "2001:db8::/32")
+	assert.True(t, networks.Contains(net.ParseIP("10.1.2.3")))
+	assert.True(t, networks.Contains(net.ParseIP("203.0.113.7")))
+	assert.False(t, networks.Contains(net.ParseIP("203.0.113.8")))
+	assert.True(t, networks.Contains(net.ParseIP("2001:db8::1")))
+
+	_, err := ParseNetworks([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseNetworks([]string{"example.com"})
+	assert.Error(t, err)
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := mustParse(t, "10.0.0.0/8")
+
+	assert.Equal(t, "203.0.113.7", ClientIP(request("/", "203.0.113.7:1234", ""), proxies).String())
+	// X-Forwarded-For of untrusted clients is ignored
+	assert.Equal(t, "203.0.113.7", ClientIP(request("/", "203.0.113.7:1234", "198.51.100.1"), proxies).String())
+	assert.Equal(t, "198.51.100.1", ClientIP(request("/", "10.0.0.1:1234", "198.51.100.1"), proxies).String())
+	// Addresses added by the client before trusted proxies are ignored
+	assert.Equal(t, "198.51.100.1", ClientIP(request("/", "10.0.0.1:1234", "192.0.2.1, 198.51.100.1, 10.0.0.2"), proxies).String())
+	assert.Equal(t, "10.0.0.1", ClientIP(request("/", "10.0.0.1:1234", "invalid"), proxies).String())
+}
+
+func TestIPFilter(t *testing.T) {
+	filter := NewIPFilter(mustParse(t, "10.0.0.0/8"), mustParse(t, "10.1.0.0/16"), nil)
+	var denied []string
+	filter.OnDenied = func(r *http.Request, ip net.IP) {
+		denied = append(denied, ip.String())
+	}
+	handler := filter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, test := range []struct {
+		remoteAddr string
+		status     int
+	}{
+		{"10.2.3.4:1234", http.StatusOK},
+		{"10.1.3.4:1234", http.StatusForbidden},
+		{"203.0.113.7:1234", http.StatusForbidden},
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request("/payment", test.remoteAddr, ""))
+		assert.Equal(t, test.status, recorder.Code, test.remoteAddr)
+		if test.status == http.StatusForbidden {
+			assert.Contains(t, recorder.Body.String(), `"code": "ip_not_allowed"`)
+		}
+	}
+	assert.Equal(t, []string{"10.1.3.4", "203.0.113.7"}, denied)
+
+	// Deny list only
+	filter = NewIPFilter(nil, mustParse(t, "203.0.113.7"), nil)
+	assert.True(t, filter.Allowed(net.ParseIP("10.2.3.4")))
+	assert.False(t, filter.Allowed(net.ParseIP("203.0.113.7")))
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter([]Rule{
+		{Path: "/payment", RateLimit: 2, Key: KeyAPIKey},
+		{Path: "/builder", RateLimit: 1, Key: KeyIP},
+	}, nil)
+	limiter.now = func() time.Time { return now }
+	limiter.APIKey = func(r *http.Request) string { return r.Header.Get("X-API-Key") }
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(path, remoteAddr, apiKey string) *httptest.ResponseRecorder {
+		r := request(path, remoteAddr, "")
+		if apiKey != "" {
+			r.Header.Set("X-API-Key", apiKey)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	// Requests of the same API key from different IP addresses share a limit
+	assert.Equal(t, http.StatusOK, send("/payment", "203.0.113.1:1", "wallet").Code)
+	assert.Equal(t, http.StatusOK, send("/payment", "203.0.113.2:1", "wallet").Code)
+	resp := send("/payment", "203.0.113.3:1", "wallet")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "30", resp.Header().Get("Retry-After"))
+	assert.Contains(t, resp.Body.String(), `"code": "rate_limit_exceeded"`)
+	assert.Equal(t, http.StatusOK, send("/payment", "203.0.113.3:1", "exchange").Code)
+
+	// Requests without an API key are limited per IP address
+	assert.Equal(t, http.StatusOK, send("/payment", "203.0.113.1:1", "").Code)
+
+	assert.Equal(t, http.StatusOK, send("/builder", "203.0.113.1:1", "wallet").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send("/builder", "203.0.113.1:2", "exchange").Code)
+	assert.Equal(t, http.StatusOK, send("/builder", "203.0.113.2:1", "wallet").Code)
+
+	// Paths without rules are not limited
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, send("/create-keypair", "203.0.113.1:1", "").Code)
+	}
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, http.StatusOK, send("/builder", "203.0.113.1:1", "").Code)
+
+	// Rules are replaced when the config is reloaded
+	limiter.SetRules([]Rule{{Path: "/create-keypair", RateLimit: 1, Key: KeyIP}})
+	assert.Equal(t, http.StatusOK, send("/create-keypair", "203.0.113.1:1", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send("/create-keypair", "203.0.113.1:1", "").Code)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, send("/builder", "203.0.113.1:1", "").Code)
+	}
+}
+
+// sharedBuckets rejects every request or fails when err is set
+type sharedBuckets struct {
+	keys []string
+	err  error
+}
+
+func (b *sharedBuckets) Allow(ctx context.Context, key string, perMinute int, now time.Time) (bool, time.Duration, error) {
+	b.keys = append(b.keys, key)
+	return false, 1500 * time.Millisecond, b.err
+}
+
+func TestRateLimiterShared(t *testing.T) {
+	limiter := NewRateLimiter([]Rule{{Path: "/payment", RateLimit: 1, Key: KeyIP}}, nil)
+	shared := &sharedBuckets{}
+	limiter.Shared = shared
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request("/payment", "203.0.113.1:1", ""))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, []string{"/payment ip:203.0.113.1"}, shared.keys)
+
+	assert.NoError(t, limiter.Check(context.Background()))
+
+	// Local buckets are used when shared ones are unavailable, the replica is
+	// not ready for a minute
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	shared.err = errors.New("connection refused")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request("/payment", "203.0.113.1:1", ""))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, shared.err, limiter.Check(context.Background()))
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, limiter.Check(context.Background()))
+}
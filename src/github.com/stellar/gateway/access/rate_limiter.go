@@ -0,0 +1,169 @@
+package access
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+const (
+	// KeyIP limits requests of every client IP address separately
+	KeyIP = "ip"
+	// KeyAPIKey limits requests of every API key separately. Requests not
+	// authenticated with an API key are limited per IP address.
+	KeyAPIKey = "api_key"
+)
+
+// Rule limits requests to a single path
+type Rule struct {
+	Path string
+	// RateLimit is the max number of requests per minute
+	RateLimit int
+	// Key is KeyIP or KeyAPIKey
+	Key string
+}
+
+// sharedErrorWindow is the time a replica is not ready after shared buckets
+// failed
+const sharedErrorWindow = time.Minute
+
+// SharedBuckets keeps token buckets shared by bridge servers, see
+// redis.Limiter
+type SharedBuckets interface {
+	Allow(ctx context.Context, key string, perMinute int, now time.Time) (bool, time.Duration, error)
+}
+
+// RateLimiter rejects requests exceeding the rate limit of their path with
+// 429 Too Many Requests and Retry-After header. Paths without a rule are not
+// limited.
+type RateLimiter struct {
+	TrustedProxies Networks
+	// APIKey returns the ID of the API key that authenticated the request or
+	// an empty string, see auth.FromContext
+	APIKey func(r *http.Request) string
+	// Shared limits requests of all bridge servers together when set. When
+	// it fails requests are limited by buckets of this server and Check
+	// reports the replica as not ready.
+	Shared SharedBuckets
+
+	mutex   sync.RWMutex
+	rules   map[string]Rule
+	limiter *Limiter
+	limited map[string]*metrics.Counter
+	log     *logrus.Entry
+	now     func() time.Time
+	// sharedErr is the last error of Shared, returned by Check until
+	// sharedErrorWindow after sharedErrAt
+	sharedErr   error
+	sharedErrAt time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter of rules. Later rules replace
+// earlier ones with the same path.
+func NewRateLimiter(rules []Rule, trustedProxies Networks) *RateLimiter {
+	l := &RateLimiter{
+		TrustedProxies: trustedProxies,
+		limiter:        NewLimiter(),
+		limited:        map[string]*metrics.Counter{},
+		log:            logrus.WithFields(logrus.Fields{"service": "RateLimiter"}),
+		now:            clock.Now,
+	}
+	l.SetRules(rules)
+	return l
+}
+
+// SetRules replaces rules when the config is reloaded. Requests already
+// counted are not reset.
+func (l *RateLimiter) SetRules(rules []Rule) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.rules = map[string]Rule{}
+	for _, rule := range rules {
+		l.rules[rule.Path] = rule
+		l.limited[rule.Path] = metrics.NewCounter(
+			metrics.Label("bridge_access_rate_limited_total", "path", rule.Path),
+			"Number of requests rejected because of the rate limit of the endpoint.",
+		)
+	}
+}
+
+// rule returns the rule of path and the counter of requests it rejected
+func (l *RateLimiter) rule(path string) (Rule, *metrics.Counter, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	rule, ok := l.rules[path]
+	return rule, l.limited[path], ok
+}
+
+// Middleware rejects requests exceeding rate limits
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		rule, limited, ok := l.rule(r.URL.Path)
+		if !ok || rule.RateLimit <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := l.key(r, rule)
+		allowed, retryAfter := l.allow(r.Context(), rule.Path+" "+key, rule.RateLimit)
+		if !allowed {
+			limited.Inc()
+			l.log.WithFields(logrus.Fields{"path": rule.Path, "key": key}).Warn("Rate limit of endpoint exceeded")
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			server.Write(w, protocols.EndpointRateLimitExceededError)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// allow takes a token from the bucket of key, shared by bridge servers when
+// possible
+func (l *RateLimiter) allow(ctx context.Context, key string, perMinute int) (bool, time.Duration) {
+	now := l.now()
+	if l.Shared != nil {
+		allowed, retryAfter, err := l.Shared.Allow(ctx, key, perMinute, now)
+		if err == nil {
+			return allowed, retryAfter
+		}
+		l.log.WithFields(logrus.Fields{"err": err}).Error("Error checking shared rate limit, using local one and reporting the replica as not ready")
+
+		l.mutex.Lock()
+		l.sharedErr, l.sharedErrAt = err, now
+		l.mutex.Unlock()
+	}
+	return l.limiter.Allow(key, perMinute, now)
+}
+
+// Check returns the last error of shared buckets for a minute after it, so
+// /readyz takes the replica limiting requests on its own out of the load
+// balancer. It's a health.Check.
+func (l *RateLimiter) Check(ctx context.Context) error {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if l.sharedErr != nil && l.now().Sub(l.sharedErrAt) < sharedErrorWindow {
+		return l.sharedErr
+	}
+	return nil
+}
+
+// key returns the key requests are limited by: "api_key:<id>" or "ip:<ip>"
+func (l *RateLimiter) key(r *http.Request, rule Rule) string {
+	if rule.Key == KeyAPIKey && l.APIKey != nil {
+		if id := l.APIKey(r); id != "" {
+			return "api_key:" + id
+		}
+	}
+	return "ip:" + ClientIP(r, l.TrustedProxies).String()
+}
@@ -0,0 +1,589 @@
+// Package amqp implements a minimal AMQP 0-9-1 client publishing persistent
+// messages to a RabbitMQ exchange with publisher confirms: a message is
+// published once the broker acknowledged it, so it was written to every
+// queue it was routed to. Messages are published as mandatory and fail when
+// they are not routed to any queue.
+package amqp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Frame types
+const (
+	frameMethod    = 1
+	frameHeader    = 2
+	frameBody      = 3
+	frameHeartbeat = 8
+	frameEnd       = 0xCE
+)
+
+// Class and method IDs
+const (
+	classConnection = 10
+	classChannel    = 20
+	classBasic      = 60
+	classConfirm    = 85
+
+	methodConnectionStart   = 10
+	methodConnectionStartOk = 11
+	methodConnectionTune    = 30
+	methodConnectionTuneOk  = 31
+	methodConnectionOpen    = 40
+	methodConnectionOpenOk  = 41
+	methodConnectionClose   = 50
+	methodConnectionCloseOk = 51
+
+	methodChannelOpen    = 10
+	methodChannelOpenOk  = 11
+	methodChannelClose   = 40
+	methodChannelCloseOk = 41
+
+	methodBasicPublish = 40
+	methodBasicReturn  = 50
+	methodBasicAck     = 80
+	methodBasicNack    = 120
+
+	methodConfirmSelect   = 10
+	methodConfirmSelectOk = 11
+)
+
+// channelID is the only channel used by the client
+const channelID = 1
+
+var protocolHeader = []byte{'A', 'M', 'Q', 'P', 0, 0, 9, 1}
+
+// ErrNotRouted is returned when a message was not routed to any queue
+var ErrNotRouted = errors.New("AMQP message was not routed to any queue")
+
+// Message is a message published to the exchange
+type Message struct {
+	// MessageID identifies the message, ex. for deduplication by consumers
+	MessageID   string
+	ContentType string
+	// Headers are sent as string values of message headers table
+	Headers map[string]string
+	Body    []byte
+}
+
+// Publisher publishes messages to an exchange. A single connection is kept
+// open and reestablished on the next Publish after an error. Publish is
+// safe for concurrent use, messages are published one at a time.
+type Publisher struct {
+	address    string
+	username   string
+	password   string
+	vhost      string
+	exchange   string
+	routingKey string
+	tls        *tls.Config
+	timeout    time.Duration
+
+	mutex       sync.Mutex
+	conn        net.Conn
+	reader      *bufio.Reader
+	frameMax    uint32
+	deliveryTag uint64
+}
+
+// NewPublisher creates a Publisher to exchange of broker at rawURL:
+// amqp://[user:password@]host[:port][/vhost], amqps:// for TLS connections
+// using tlsConfig (can be nil). Messages are published with routingKey,
+// every Publish is limited by timeout.
+func NewPublisher(rawURL, exchange, routingKey string, tlsConfig *tls.Config, timeout time.Duration) (*Publisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Publisher{
+		address:    u.Host,
+		username:   "guest",
+		password:   "guest",
+		vhost:      "/",
+		exchange:   exchange,
+		routingKey: routingKey,
+		timeout:    timeout,
+	}
+
+	port := "5672"
+	switch u.Scheme {
+	case "amqp":
+	case "amqps":
+		port = "5671"
+		p.tls = &tls.Config{}
+		if tlsConfig != nil {
+			p.tls = tlsConfig.Clone()
+		}
+		if p.tls.ServerName == "" {
+			p.tls.ServerName = u.Hostname()
+		}
+	default:
+		return nil, errors.New("AMQP URL scheme must be amqp or amqps")
+	}
+
+	if u.Port() == "" {
+		p.address = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	if u.User != nil {
+		p.username = u.User.Username()
+		p.password, _ = u.User.Password()
+	}
+
+	// Path is the vhost without the leading slash, default vhost is "/"
+	if len(u.Path) > 1 {
+		p.vhost = u.Path[1:]
+	}
+	return p, nil
+}
+
+// SetCredentials overrides user and password of the URL
+func (p *Publisher) SetCredentials(username, password string) {
+	p.username = username
+	p.password = password
+}
+
+// SetVHost overrides virtual host of the URL
+func (p *Publisher) SetVHost(vhost string) {
+	p.vhost = vhost
+}
+
+// Publish publishes message and waits until the broker confirms it
+func (p *Publisher) Publish(message Message) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.conn == nil {
+		err := p.connect()
+		if err != nil {
+			p.close()
+			return fmt.Errorf("Error connecting to AMQP broker: %s", err)
+		}
+	}
+
+	err := p.publish(message)
+	if err != nil && err != ErrNotRouted {
+		// Connection is in unknown state
+		p.close()
+	}
+	return err
+}
+
+// Close closes the connection
+func (p *Publisher) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.conn != nil {
+		// Best effort, the broker closes the connection anyway
+		p.writeMethod(0, classConnection, methodConnectionClose, func(b *buffer) {
+			b.short(200)
+			b.shortstr("Goodbye")
+			b.short(0)
+			b.short(0)
+		})
+	}
+	p.close()
+}
+
+func (p *Publisher) close() {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn = nil
+	p.reader = nil
+}
+
+func (p *Publisher) connect() error {
+	dialer := &net.Dialer{Timeout: p.timeout}
+	var err error
+	if p.tls != nil {
+		p.conn, err = tls.DialWithDialer(dialer, "tcp", p.address, p.tls)
+	} else {
+		p.conn, err = dialer.Dial("tcp", p.address)
+	}
+	if err != nil {
+		return err
+	}
+	p.reader = bufio.NewReader(p.conn)
+	p.frameMax = 0
+	p.deliveryTag = 0
+	p.setDeadline()
+
+	_, err = p.conn.Write(protocolHeader)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.expect(0, classConnection, methodConnectionStart)
+	if err != nil {
+		return err
+	}
+
+	err = p.writeMethod(0, classConnection, methodConnectionStartOk, func(b *buffer) {
+		b.table(map[string]interface{}{
+			"product":      "bridge",
+			"capabilities": map[string]interface{}{"publisher_confirms": true},
+		})
+		b.shortstr("PLAIN")
+		b.longstr("\x00" + p.username + "\x00" + p.password)
+		b.shortstr("en_US")
+	})
+	if err != nil {
+		return err
+	}
+
+	args, err := p.expect(0, classConnection, methodConnectionTune)
+	if err != nil {
+		return err
+	}
+	channelMax, _ := args.readShort()
+	frameMax, _ := args.readLong()
+	if frameMax == 0 || frameMax > 131072 {
+		frameMax = 131072
+	}
+	p.frameMax = frameMax
+
+	// Heartbeats are disabled, connections are short-lived between
+	// payments and reestablished after errors
+	err = p.writeMethod(0, classConnection, methodConnectionTuneOk, func(b *buffer) {
+		b.short(channelMax)
+		b.long(frameMax)
+		b.short(0)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.writeMethod(0, classConnection, methodConnectionOpen, func(b *buffer) {
+		b.shortstr(p.vhost)
+		b.shortstr("")
+		b.octet(0)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.expect(0, classConnection, methodConnectionOpenOk)
+	if err != nil {
+		return err
+	}
+
+	err = p.writeMethod(channelID, classChannel, methodChannelOpen, func(b *buffer) {
+		b.shortstr("")
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.expect(channelID, classChannel, methodChannelOpenOk)
+	if err != nil {
+		return err
+	}
+
+	err = p.writeMethod(channelID, classConfirm, methodConfirmSelect, func(b *buffer) {
+		b.octet(0)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.expect(channelID, classConfirm, methodConfirmSelectOk)
+	return err
+}
+
+func (p *Publisher) publish(message Message) error {
+	p.setDeadline()
+
+	// mandatory flag, unroutable messages are returned
+	err := p.writeMethod(channelID, classBasic, methodBasicPublish, func(b *buffer) {
+		b.short(0)
+		b.shortstr(p.exchange)
+		b.shortstr(p.routingKey)
+		b.octet(1)
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]interface{}, len(message.Headers))
+	for key, value := range message.Headers {
+		headers[key] = value
+	}
+
+	// Properties: content-type, headers, delivery-mode, message-id
+	header := &buffer{}
+	header.short(classBasic)
+	header.short(0)
+	header.longlong(uint64(len(message.Body)))
+	header.short(1<<15 | 1<<13 | 1<<12 | 1<<7)
+	header.shortstr(message.ContentType)
+	header.table(headers)
+	header.octet(2) // persistent
+	header.shortstr(message.MessageID)
+	err = p.writeFrame(frameHeader, channelID, header.Bytes())
+	if err != nil {
+		return err
+	}
+
+	maxBody := int(p.frameMax) - 8
+	for body := message.Body; len(body) > 0; {
+		size := len(body)
+		if size > maxBody {
+			size = maxBody
+		}
+		err = p.writeFrame(frameBody, channelID, body[:size])
+		if err != nil {
+			return err
+		}
+		body = body[size:]
+	}
+	p.deliveryTag++
+
+	returned := false
+	for {
+		frameType, channel, payload, err := p.readFrame()
+		if err != nil {
+			return err
+		}
+		if frameType != frameMethod {
+			// Content of a returned message
+			continue
+		}
+
+		args := &reader{payload[4:]}
+		class, method := binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:])
+		switch {
+		case class == classBasic && method == methodBasicReturn:
+			returned = true
+		case class == classBasic && (method == methodBasicAck || method == methodBasicNack):
+			tag, err := args.readLonglong()
+			if err != nil {
+				return err
+			}
+			if tag < p.deliveryTag {
+				// Confirm of a message abandoned after an error
+				continue
+			}
+			if method == methodBasicNack {
+				return errors.New("AMQP broker rejected the message")
+			}
+			if returned {
+				return ErrNotRouted
+			}
+			return nil
+		default:
+			return p.closed(channel, class, method, args)
+		}
+	}
+}
+
+// expect reads the next method frame and returns its arguments. It fails
+// when it's not the method of class on channel.
+func (p *Publisher) expect(channel uint16, class, method uint16) (*reader, error) {
+	for {
+		frameType, frameChannel, payload, err := p.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		if frameType != frameMethod {
+			continue
+		}
+
+		args := &reader{payload[4:]}
+		frameClass, frameMethod := binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:])
+		if frameChannel == channel && frameClass == class && frameMethod == method {
+			return args, nil
+		}
+		return nil, p.closed(frameChannel, frameClass, frameMethod, args)
+	}
+}
+
+// closed returns an error of an unexpected method, ex. Channel.Close sent
+// by the broker when the exchange does not exist
+func (p *Publisher) closed(channel, class, method uint16, args *reader) error {
+	if method == methodConnectionClose && class == classConnection || method == methodChannelClose && class == classChannel {
+		code, _ := args.readShort()
+		text, _ := args.readShortstr()
+		return fmt.Errorf("AMQP broker closed the %s: %d %s", map[uint16]string{classConnection: "connection", classChannel: "channel"}[class], code, text)
+	}
+	return fmt.Errorf("Unexpected AMQP method %d.%d on channel %d", class, method, channel)
+}
+
+func (p *Publisher) setDeadline() {
+	if p.timeout > 0 {
+		p.conn.SetDeadline(time.Now().Add(p.timeout))
+	}
+}
+
+func (p *Publisher) writeMethod(channel uint16, class, method uint16, args func(b *buffer)) error {
+	b := &buffer{}
+	b.short(class)
+	b.short(method)
+	args(b)
+	return p.writeFrame(frameMethod, channel, b.Bytes())
+}
+
+func (p *Publisher) writeFrame(frameType byte, channel uint16, payload []byte) error {
+	frame := make([]byte, 7, 8+len(payload))
+	frame[0] = frameType
+	binary.BigEndian.PutUint16(frame[1:], channel)
+	binary.BigEndian.PutUint32(frame[3:], uint32(len(payload)))
+	frame = append(frame, payload...)
+	frame = append(frame, frameEnd)
+	_, err := p.conn.Write(frame)
+	return err
+}
+
+func (p *Publisher) readFrame() (frameType byte, channel uint16, payload []byte, err error) {
+	for {
+		header := make([]byte, 7)
+		_, err = io.ReadFull(p.reader, header)
+		if err != nil {
+			return
+		}
+		frameType = header[0]
+		channel = binary.BigEndian.Uint16(header[1:])
+		size := binary.BigEndian.Uint32(header[3:])
+		if p.frameMax > 0 && size > p.frameMax {
+			err = errors.New("AMQP frame too large")
+			return
+		}
+
+		payload = make([]byte, size+1)
+		_, err = io.ReadFull(p.reader, payload)
+		if err != nil {
+			return
+		}
+		if payload[size] != frameEnd {
+			err = errors.New("Invalid AMQP frame end")
+			return
+		}
+		payload = payload[:size]
+
+		if frameType == frameHeartbeat {
+			continue
+		}
+		if frameType == frameMethod && len(payload) < 4 {
+			err = errors.New("Invalid AMQP method frame")
+			return
+		}
+		return
+	}
+}
+
+// buffer encodes AMQP data types
+type buffer struct {
+	bytes.Buffer
+}
+
+func (b *buffer) octet(v byte) {
+	b.WriteByte(v)
+}
+
+func (b *buffer) short(v uint16) {
+	binary.Write(b, binary.BigEndian, v)
+}
+
+func (b *buffer) long(v uint32) {
+	binary.Write(b, binary.BigEndian, v)
+}
+
+func (b *buffer) longlong(v uint64) {
+	binary.Write(b, binary.BigEndian, v)
+}
+
+func (b *buffer) shortstr(s string) {
+	if len(s) > 255 {
+		s = s[:255]
+	}
+	b.octet(byte(len(s)))
+	b.WriteString(s)
+}
+
+func (b *buffer) longstr(s string) {
+	b.long(uint32(len(s)))
+	b.WriteString(s)
+}
+
+// table encodes a field table of string, bool and nested table values
+func (b *buffer) table(fields map[string]interface{}) {
+	t := &buffer{}
+	for name, value := range fields {
+		t.shortstr(name)
+		switch value := value.(type) {
+		case string:
+			t.octet('S')
+			t.longstr(value)
+		case bool:
+			t.octet('t')
+			if value {
+				t.octet(1)
+			} else {
+				t.octet(0)
+			}
+		case map[string]interface{}:
+			t.octet('F')
+			t.table(value)
+		}
+	}
+	b.long(uint32(t.Len()))
+	b.Write(t.Bytes())
+}
+
+// reader decodes AMQP data types
+type reader struct {
+	data []byte
+}
+
+var errShortFrame = errors.New("AMQP frame too short")
+
+func (r *reader) next(n int) ([]byte, error) {
+	if len(r.data) < n {
+		return nil, errShortFrame
+	}
+	v := r.data[:n]
+	r.data = r.data[n:]
+	return v, nil
+}
+
+func (r *reader) readShort() (uint16, error) {
+	v, err := r.next(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(v), nil
+}
+
+func (r *reader) readLong() (uint32, error) {
+	v, err := r.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(v), nil
+}
+
+func (r *reader) readLonglong() (uint64, error) {
+	v, err := r.next(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (r *reader) readShortstr() (string, error) {
+	size, err := r.next(1)
+	if err != nil {
+		return "", err
+	}
+	v, err := r.next(int(size[0]))
+	return string(v), err
+}
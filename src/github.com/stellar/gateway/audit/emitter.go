@@ -0,0 +1,270 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+)
+
+const (
+	// deliveryBatchSize is the max number of events loaded from DB at once
+	deliveryBatchSize = 100
+	// minRetryDelay is the delay after the first failed delivery attempt,
+	// doubled after every next attempt up to maxRetryDelay
+	minRetryDelay = 10 * time.Second
+	maxRetryDelay = time.Hour
+)
+
+var emitterMetrics = struct {
+	emitted     *metrics.Counter
+	delivered   *metrics.Counter
+	failures    *metrics.Counter
+	storeErrors *metrics.Counter
+}{
+	emitted:     metrics.NewCounter("audit_events_emitted_total", "Number of security events stored for delivery."),
+	delivered:   metrics.NewCounter("audit_events_delivered_total", "Number of security events delivered to SIEM endpoint."),
+	failures:    metrics.NewCounter("audit_delivery_failures_total", "Number of failed attempts to deliver a security event."),
+	storeErrors: metrics.NewCounter("audit_events_store_errors_total", "Number of security events that could not be stored for delivery."),
+}
+
+// HTTP represents an http client that Emitter can use to make HTTP requests
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// Emitter stores security events in DB and delivers them to a SIEM endpoint.
+// Every event is sent in a separate POST request. Events are sent again
+// (with growing delays) until the endpoint responds with 2xx status, so an
+// event can be delivered more than once; X-Event-ID header allows the SIEM to
+// drop duplicates. A nil Emitter drops all events, so it can be used when
+// security events are not configured.
+type Emitter struct {
+	URL    string
+	Format string
+	// Secret is used to authenticate request bodies with HMAC-SHA256 sent in
+	// X-Signature header. Requests are not signed when empty.
+	Secret  string
+	Version string
+	Client  HTTP
+
+	entityManager db.EntityManagerInterface
+	repository    db.RepositoryInterface
+	now           func() time.Time
+	wake          chan struct{}
+	log           *logrus.Entry
+}
+
+// NewEmitter creates a new Emitter
+func NewEmitter(
+	url, format, secret, version string,
+	client HTTP,
+	entityManager db.EntityManagerInterface,
+	repository db.RepositoryInterface,
+	now func() time.Time,
+) *Emitter {
+	return &Emitter{
+		URL:           url,
+		Format:        format,
+		Secret:        secret,
+		Version:       version,
+		Client:        client,
+		entityManager: entityManager,
+		repository:    repository,
+		now:           now,
+		wake:          make(chan struct{}, 1),
+		log:           logrus.WithFields(logrus.Fields{"service": "AuditEmitter"}),
+	}
+}
+
+// Emit stores the event for delivery. Delivery errors are not returned, the
+// event is sent in the background by the loop started with Start.
+func (e *Emitter) Emit(event Event) {
+	if e == nil {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.Time.IsZero() {
+		event.Time = e.now()
+	}
+	if event.Severity == 0 {
+		event.Severity = severities[event.Type]
+	}
+	if event.Outcome == "" {
+		event.Outcome = OutcomeFailure
+		if event.Type == KeyUsage || event.Type == AdminAction {
+			event.Outcome = OutcomeSuccess
+		}
+	}
+	if event.Message == "" {
+		event.Message = messages[event.Type]
+	}
+	event.Product = product
+	event.Version = e.Version
+
+	localLog := e.log.WithFields(logrus.Fields{"id": event.ID, "type": event.Type})
+
+	payload, err := event.Format(e.Format)
+	if err != nil {
+		emitterMetrics.storeErrors.Inc()
+		localLog.WithFields(logrus.Fields{"err": err}).Error("Cannot encode security event")
+		return
+	}
+
+	err = e.entityManager.Persist(&entities.SecurityEvent{
+		EventID:       event.ID,
+		Type:          event.Type,
+		Format:        e.Format,
+		Payload:       payload,
+		CreatedAt:     event.Time,
+		NextAttemptAt: event.Time,
+	})
+	if err != nil {
+		emitterMetrics.storeErrors.Inc()
+		// Log the payload so the event can be recovered from logs
+		localLog.WithFields(logrus.Fields{"err": err, "event": payload}).Error("Cannot store security event")
+		return
+	}
+
+	emitterMetrics.emitted.Inc()
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start delivers stored events in a goroutine: right after an event is
+// emitted and every interval (to retry failed deliveries)
+func (e *Emitter) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-e.wake:
+			}
+
+			_, err := e.Deliver(context.Background())
+			if err != nil {
+				e.log.WithFields(logrus.Fields{"err": err}).Warn("Error delivering security events")
+			}
+		}
+	}()
+}
+
+// Deliver sends events waiting for delivery in order they were emitted. It
+// stops at the first failed event and returns the number of delivered events.
+func (e *Emitter) Deliver(ctx context.Context) (delivered int, err error) {
+	for {
+		var events []*entities.SecurityEvent
+		events, err = e.repository.GetSecurityEventsToDeliver(ctx, e.now(), deliveryBatchSize)
+		if err != nil {
+			return
+		}
+
+		for _, event := range events {
+			sendErr := e.send(event)
+			now := e.now()
+
+			if sendErr != nil {
+				emitterMetrics.failures.Inc()
+				message := sendErr.Error()
+				event.Attempts++
+				event.LastError = &message
+				event.NextAttemptAt = now.Add(retryDelay(event.Attempts))
+
+				err = e.entityManager.Persist(event)
+				if err != nil {
+					return
+				}
+
+				err = fmt.Errorf("Cannot deliver event %s (attempt %d): %s", event.EventID, event.Attempts, sendErr)
+				return
+			}
+
+			event.DeliveredAt = &now
+			event.LastError = nil
+
+			// The event is sent again when it cannot be marked as delivered
+			err = e.entityManager.Persist(event)
+			if err != nil {
+				return
+			}
+
+			emitterMetrics.delivered.Inc()
+			delivered++
+		}
+
+		if len(events) < deliveryBatchSize {
+			return
+		}
+	}
+}
+
+func (e *Emitter) send(event *entities.SecurityEvent) error {
+	body := []byte(event.Payload)
+
+	req, err := http.NewRequest("POST", e.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if event.Format == FormatJSON {
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+	req.Header.Set("X-Event-ID", event.EventID)
+	if e.Secret != "" {
+		req.Header.Set("X-Signature", e.mac(body))
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SIEM endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// mac returns hex encoded HMAC-SHA256 of body using Secret
+func (e *Emitter) mac(body []byte) string {
+	macer := hmac.New(sha256.New, []byte(e.Secret))
+	macer.Write(body)
+	return hex.EncodeToString(macer.Sum(nil))
+}
+
+// retryDelay returns a delay before the next delivery attempt of an event
+// after a given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitter(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+
+	now := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	var (
+		status   = http.StatusServiceUnavailable
+		received []string
+	)
+	siem := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		macer := hmac.New(sha256.New, []byte("secret"))
+		macer.Write(body)
+		assert.Equal(t, hex.EncodeToString(macer.Sum(nil)), r.Header.Get("X-Signature"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NotEmpty(t, r.Header.Get("X-Event-ID"))
+
+		if status == http.StatusOK {
+			received = append(received, string(body))
+		}
+		w.WriteHeader(status)
+	}))
+	defer siem.Close()
+
+	emitter := NewEmitter(
+		siem.URL, FormatJSON, "secret", "v1.0.0", http.DefaultClient,
+		db.NewEntityManager(driver), repository,
+		func() time.Time { return now },
+	)
+
+	emitter.Emit(Event{Type: KeyUsage, Account: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"})
+	emitter.Emit(Event{Type: AuthFailure, SourceIP: "10.0.0.1", Message: "Invalid API key"})
+
+	// SIEM endpoint is down
+	delivered, err := emitter.Deliver(context.Background())
+	assert.Equal(t, 0, delivered)
+	assert.Contains(t, err.Error(), "SIEM endpoint responded with status 503")
+
+	// Failed event is retried after a delay
+	events, err := repository.GetSecurityEventsToDeliver(context.Background(), now, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, AuthFailure, events[0].Type)
+
+	events, err = repository.GetSecurityEventsToDeliver(context.Background(), now.Add(minRetryDelay), 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, 1, events[0].Attempts)
+	assert.Equal(t, "SIEM endpoint responded with status 503", *events[0].LastError)
+
+	status = http.StatusOK
+	now = now.Add(minRetryDelay)
+	delivered, err = emitter.Deliver(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, delivered)
+	require.Len(t, received, 2)
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(received[0]), &event))
+	assert.Equal(t, KeyUsage, event.Type)
+	assert.Equal(t, OutcomeSuccess, event.Outcome)
+	assert.Equal(t, 3, event.Severity)
+	assert.Equal(t, "v1.0.0", event.Version)
+	assert.Equal(t, "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ", event.Account)
+
+	require.NoError(t, json.Unmarshal([]byte(received[1]), &event))
+	assert.Equal(t, AuthFailure, event.Type)
+	assert.Equal(t, OutcomeFailure, event.Outcome)
+	assert.Equal(t, "Invalid API key", event.Message)
+
+	events, err = repository.GetSecurityEventsToDeliver(context.Background(), now.Add(maxRetryDelay), 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	// nil Emitter drops events
+	var disabled *Emitter
+	disabled.Emit(Event{Type: KeyUsage})
+}
+
+func TestEventCEF(t *testing.T) {
+	event := Event{
+		ID:       "abc",
+		Time:     time.Unix(1519905600, 0),
+		Product:  product,
+		Version:  "v1.0|beta",
+		Type:     LimitViolation,
+		Severity: 5,
+		Outcome:  OutcomeFailure,
+		Message:  "Amount is greater than maximum amount allowed for this asset.",
+		SourceIP: "10.0.0.1",
+		Method:   "POST",
+		Path:     "/payment",
+		Details:  map[string]string{"amount": "1000"},
+	}
+
+	cef, err := event.CEF()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`CEF:0|Stellar|Bridge Server|v1.0\|beta|limit_violation|Amount is greater than maximum amount allowed for this asset.|5|`+
+			`externalId=abc rt=1519905600000 outcome=failure src=10.0.0.1 requestMethod=POST request=/payment cs1Label=details cs1={"amount":"1000"}`,
+		cef,
+	)
+
+	event.Path = "/admin/a=b\nc"
+	cef, err = event.CEF()
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(cef, `request=/admin/a\=b\nc `))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 10*time.Second, retryDelay(1))
+	assert.Equal(t, 20*time.Second, retryDelay(2))
+	assert.Equal(t, 80*time.Second, retryDelay(4))
+	assert.Equal(t, time.Hour, retryDelay(100))
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	now := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	repository := db.NewRepository(driver)
+	emitter := NewEmitter("http://siem", FormatCEF, "", "v1.0.0", nil, db.NewEntityManager(driver), repository, func() time.Time { return now })
+
+	handler := emitter.AdminMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/feature-flags" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	for _, request := range []struct{ method, path string }{
+		{"GET", "/admin/sent-transactions"},
+		{"POST", "/payment"},
+		{"POST", "/admin/cache/flush"},
+		{"POST", "/admin/feature-flags"},
+		{"DELETE", "/payments/abc"},
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(request.method, request.path, nil))
+	}
+
+	events, err := repository.GetSecurityEventsToDeliver(context.Background(), now, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	expected := []string{
+		`outcome=success src=192.0.2.1 requestMethod=POST request=/admin/cache/flush cs1Label=details cs1={"status":"200"}`,
+		`outcome=failure src=192.0.2.1 requestMethod=POST request=/admin/feature-flags cs1Label=details cs1={"status":"400"}`,
+		`outcome=success src=192.0.2.1 requestMethod=DELETE request=/payments/abc cs1Label=details cs1={"status":"200"}`,
+	}
+	for i, event := range events {
+		assert.Equal(t, FormatCEF, event.Format)
+		assert.True(t, strings.HasPrefix(event.Payload, "CEF:0|Stellar|Bridge Server|v1.0.0|admin_action|Admin action|4|"))
+		assert.True(t, strings.HasSuffix(event.Payload, expected[i]), event.Payload)
+	}
+}
@@ -0,0 +1,177 @@
+// Package audit sends security events (authentication failures, limit
+// violations, admin actions and key usage) to a SIEM endpoint in CEF or JSON
+// format. Events are stored in the database before they are sent, so they are
+// delivered even when the SIEM endpoint is down or the server is restarted.
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event types
+const (
+	// AuthFailure is a request rejected because of invalid credentials
+	AuthFailure = "auth_failure"
+	// LimitViolation is a payment rejected because of asset limits
+	LimitViolation = "limit_violation"
+	// AdminAction is a request changing state using admin endpoints
+	AdminAction = "admin_action"
+	// KeyUsage is a transaction signed with one of the gateway keys
+	KeyUsage = "key_usage"
+	// ChainMismatch is a sent transaction with a status different from the
+	// ledger state reported by Horizon
+	ChainMismatch = "chain_mismatch"
+)
+
+// Event formats
+const (
+	FormatJSON = "json"
+	FormatCEF  = "cef"
+)
+
+// Event outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+const (
+	vendor  = "Stellar"
+	product = "Bridge Server"
+)
+
+// severities are CEF severities (0-10) of event types
+var severities = map[string]int{
+	AuthFailure:    7,
+	LimitViolation: 5,
+	AdminAction:    4,
+	KeyUsage:       3,
+	ChainMismatch:  8,
+}
+
+var messages = map[string]string{
+	AuthFailure:    "Authentication failure",
+	LimitViolation: "Limit violation",
+	AdminAction:    "Admin action",
+	KeyUsage:       "Key usage",
+	ChainMismatch:  "Transaction status mismatch",
+}
+
+// Event is a single security event. Empty ID, Time, Severity, Outcome and
+// Message are filled by Emitter.Emit.
+type Event struct {
+	// ID is a random ID allowing SIEM to drop events delivered more than once
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	Product  string    `json:"product"`
+	Version  string    `json:"version"`
+	Type     string    `json:"type"`
+	Severity int       `json:"severity"`
+	Outcome  string    `json:"outcome"`
+	Message  string    `json:"message"`
+	SourceIP string    `json:"source_ip,omitempty"`
+	Method   string    `json:"method,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	// Account is a Stellar account the event concerns
+	Account string            `json:"account,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// NewRequestEvent returns an event of a given type with source IP, method and
+// path of a request
+func NewRequestEvent(eventType string, r *http.Request) Event {
+	sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		sourceIP = r.RemoteAddr
+	}
+
+	return Event{
+		Type:     eventType,
+		SourceIP: sourceIP,
+		Method:   r.Method,
+		Path:     r.URL.Path,
+	}
+}
+
+// Format returns the event encoded in a given format
+func (e Event) Format(format string) (string, error) {
+	switch format {
+	case FormatJSON:
+		payload, err := json.Marshal(e)
+		return string(payload), err
+	case FormatCEF:
+		return e.CEF()
+	default:
+		return "", fmt.Errorf("Unknown format: %s", format)
+	}
+}
+
+// CEF returns the event in ArcSight Common Event Format. Details are sent as
+// a JSON object in cs1 custom string.
+func (e Event) CEF() (string, error) {
+	extension := []string{
+		"externalId=" + escapeExtension(e.ID),
+		fmt.Sprintf("rt=%d", e.Time.UnixNano()/int64(time.Millisecond)),
+		"outcome=" + escapeExtension(e.Outcome),
+	}
+
+	fields := []struct{ key, value string }{
+		{"src", e.SourceIP},
+		{"requestMethod", e.Method},
+		{"request", e.Path},
+		{"suser", e.Account},
+	}
+	for _, field := range fields {
+		if field.value != "" {
+			extension = append(extension, field.key+"="+escapeExtension(field.value))
+		}
+	}
+
+	if len(e.Details) > 0 {
+		details, err := json.Marshal(e.Details)
+		if err != nil {
+			return "", err
+		}
+		extension = append(extension, "cs1Label=details", "cs1="+escapeExtension(string(details)))
+	}
+
+	return fmt.Sprintf(
+		"CEF:0|%s|%s|%s|%s|%s|%d|%s",
+		escapeHeader(vendor),
+		escapeHeader(e.Product),
+		escapeHeader(e.Version),
+		escapeHeader(e.Type),
+		escapeHeader(e.Message),
+		e.Severity,
+		strings.Join(extension, " "),
+	), nil
+}
+
+var (
+	headerEscaper    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	extensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r\n", `\n`, "\r", `\r`, "\n", `\n`)
+)
+
+func escapeHeader(value string) string {
+	return headerEscaper.Replace(value)
+}
+
+func escapeExtension(value string) string {
+	return extensionEscaper.Replace(value)
+}
+
+func newEventID() string {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(id)
+}
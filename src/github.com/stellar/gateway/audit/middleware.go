@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AdminMiddleware emits AdminAction event for every request changing state
+// using admin endpoints (POST and DELETE requests to /admin/* paths) and for
+// every DELETE request (ex. cancelling a held payment). Outcome of the event
+// depends on the response status.
+func (e *Emitter) AdminMiddleware() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			admin := r.Method == "DELETE" || (r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/admin/"))
+			if !admin {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			event := NewRequestEvent(AdminAction, r)
+			event.Outcome = OutcomeSuccess
+			if recorder.status >= http.StatusBadRequest {
+				event.Outcome = OutcomeFailure
+			}
+			event.Details = map[string]string{"status": strconv.Itoa(recorder.status)}
+			e.Emit(event)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// statusRecorder remembers the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,141 @@
+// Package auth authenticates clients of the bridge API with per-client API
+// keys. A request is authenticated with the key secret or with HMAC-SHA256
+// signature of the request made with the secret, so the secret is never sent.
+// Every key can have its own rate limit and list of allowed source accounts.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+)
+
+// Key is an API key of a single client
+type Key struct {
+	ID     string
+	Secret string
+	// RequireSignature rejects requests authenticated with the secret
+	// instead of HMAC signature
+	RequireSignature bool
+	// RateLimit is the max number of requests per minute, 0 means no limit
+	RateLimit int
+	// Sources are allowed source accounts, all accounts are allowed when empty
+	Sources []string
+	// Tenant of requests authenticated with the key, empty when requests
+	// are not sent on behalf of a tenant
+	Tenant string
+}
+
+// AllowsSource returns true when payments and transactions of accountID
+// can be sent using the key
+func (k *Key) AllowsSource(accountID string) bool {
+	if k == nil || len(k.Sources) == 0 {
+		return true
+	}
+	for _, source := range k.Sources {
+		if source == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// Store loads keys by ID. Key returns nil when the key does not exist.
+type Store interface {
+	Key(ctx context.Context, id string) (*Key, error)
+}
+
+// StaticStore contains keys from the config file
+type StaticStore map[string]*Key
+
+// NewStaticStore creates a new StaticStore of keys
+func NewStaticStore(keys []Key) StaticStore {
+	store := StaticStore{}
+	for i := range keys {
+		store[keys[i].ID] = &keys[i]
+	}
+	return store
+}
+
+// Key implements Store
+func (s StaticStore) Key(ctx context.Context, id string) (*Key, error) {
+	return s[id], nil
+}
+
+// RepositoryStore loads keys from APIKey table
+type RepositoryStore struct {
+	Repository db.RepositoryInterface
+}
+
+// Key implements Store
+func (s RepositoryStore) Key(ctx context.Context, id string) (*Key, error) {
+	found, err := s.Repository.GetAPIKey(ctx, id)
+	if err != nil || found == nil {
+		return nil, err
+	}
+	return keyFromEntity(found), nil
+}
+
+func keyFromEntity(e *entities.APIKey) *Key {
+	key := &Key{
+		ID:               e.KeyID,
+		Secret:           e.Secret,
+		RequireSignature: e.RequireSignature,
+		RateLimit:        e.RateLimit,
+		Tenant:           e.Tenant,
+	}
+	for _, source := range strings.Split(e.Sources, ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			key.Sources = append(key.Sources, source)
+		}
+	}
+	return key
+}
+
+type keyContextKey struct{}
+
+// FromContext returns the key that authenticated the request or nil when
+// the request was not authenticated with an API key
+func FromContext(ctx context.Context) *Key {
+	key, _ := ctx.Value(keyContextKey{}).(*Key)
+	return key
+}
+
+// WithKey returns ctx authenticated with key, used by payments sent by the
+// bridge server itself (ex. balance top-ups) so they can be approved by API
+// keys
+func WithKey(ctx context.Context, key *Key) context.Context {
+	return context.WithValue(ctx, keyContextKey{}, key)
+}
+
+// Tenant returns tenant of the key that authenticated the request or empty
+// string when the request was not authenticated with an API key
+func Tenant(ctx context.Context) string {
+	if key := FromContext(ctx); key != nil {
+		return key.Tenant
+	}
+	return ""
+}
+
+// AllowedSource returns true when the key that authenticated the request
+// allows accountID as a source account. Requests not authenticated with an
+// API key are allowed.
+func AllowedSource(ctx context.Context, accountID string) bool {
+	return FromContext(ctx).AllowsSource(accountID)
+}
+
+// Sign returns hex encoded HMAC-SHA256 of the request sent in X-Signature
+// header. The signed message is the timestamp (Unix seconds), method, request
+// URI (path and query) and body joined with new lines.
+func Sign(secret string, timestamp int64, method, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "\n" + method + "\n" + requestURI + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sourceA = "GAJ7NTRWBLH2Q3BYVQNS3M2UU2JURHDWBMQAKHG3R5YKFZ57T4QKEVGH"
+	sourceB = "GCZDK7NDYV5E4ZF2NN5JQ5FJ25RRAIQIGWRRKW6KOJ7MFGH4R2ASLIKF"
+)
+
+func TestMiddleware(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	store := NewStaticStore([]Key{
+		{ID: "wallet", Secret: "wallet-secret-123", Sources: []string{sourceA}},
+		{ID: "exchange", Secret: "exchange-secret-1", RequireSignature: true, RateLimit: 2},
+	})
+	authenticator := NewAuthenticator([]Store{store}, []string{"/payment", "/builder", "/admin/payments/*"}, DefaultMaxClockSkew)
+	authenticator.now = func() time.Time { return now }
+	var failures []string
+	authenticator.OnFailure = func(r *http.Request, message string) {
+		failures = append(failures, message)
+	}
+
+	var key *Key
+	var body string
+	handler := authenticator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = FromContext(r.Context())
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+	}))
+
+	send := func(path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		key = nil
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+	signed := func(id, secret string, timestamp time.Time, path, body string) map[string]string {
+		return map[string]string{
+			"X-API-Key":   id,
+			"X-Timestamp": strconv.FormatInt(timestamp.Unix(), 10),
+			"X-Signature": Sign(secret, timestamp.Unix(), "POST", path, []byte(body)),
+		}
+	}
+
+	// Not protected paths are not checked
+	resp := send("/create-keypair", "", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Nil(t, key)
+
+	resp = send("/payment", "amount=1", map[string]string{"X-API-Key": "wallet:wallet-secret-123"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	require.NotNil(t, key)
+	assert.Equal(t, "wallet", key.ID)
+	assert.Equal(t, "amount=1", body)
+
+	// Paths under protected prefixes are checked
+	resp = send("/admin/payments/1/approve", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	resp = send("/admin/payments/1/approve", "", map[string]string{"X-API-Key": "wallet:wallet-secret-123"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = send("/payment", "amount=1", signed("wallet", "wallet-secret-123", now, "/payment", "amount=1"))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	require.NotNil(t, key)
+	assert.Equal(t, "amount=1", body)
+
+	for _, test := range []struct {
+		headers map[string]string
+		message string
+	}{
+		{nil, "Missing API key"},
+		{map[string]string{"X-API-Key": "unknown:secret"}, "Unknown API key"},
+		{map[string]string{"X-API-Key": "wallet"}, "Missing API key secret or signature"},
+		{map[string]string{"X-API-Key": "wallet:invalid"}, "Invalid API key secret"},
+		{map[string]string{"X-API-Key": "exchange:exchange-secret-1"}, "API key requires signed requests"},
+		{signed("wallet", "invalid", now, "/payment", "amount=1"), "Invalid signature"},
+		{signed("wallet", "wallet-secret-123", now, "/builder", "amount=1"), "Invalid signature"},
+		{signed("wallet", "wallet-secret-123", now, "/payment", "amount=100"), "Invalid signature"},
+		{signed("wallet", "wallet-secret-123", now.Add(-10*time.Minute), "/payment", "amount=1"), "X-Timestamp is too far from server time"},
+		{map[string]string{"X-API-Key": "wallet", "X-Signature": "abc"}, "Missing or invalid X-Timestamp"},
+	} {
+		failures = nil
+		resp = send("/payment", "amount=1", test.headers)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code, test.message)
+		assert.Contains(t, resp.Body.String(), `"code": "unauthorized"`)
+		assert.Equal(t, []string{test.message}, failures)
+		assert.Nil(t, key)
+	}
+
+	// Rate limit of 2 requests per minute
+	for i := 0; i < 2; i++ {
+		resp = send("/builder", "{}", signed("exchange", "exchange-secret-1", now, "/builder", "{}"))
+		assert.Equal(t, http.StatusOK, resp.Code)
+	}
+	resp = send("/builder", "{}", signed("exchange", "exchange-secret-1", now, "/builder", "{}"))
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "30", resp.Header().Get("Retry-After"))
+
+	now = now.Add(30 * time.Second)
+	resp = send("/builder", "{}", signed("exchange", "exchange-secret-1", now, "/builder", "{}"))
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestStores(t *testing.T) {
+	repository := &mocks.MockRepository{}
+	repository.On("GetAPIKey", "db").Return(&entities.APIKey{
+		KeyID:     "db",
+		Secret:    "db-secret-123456",
+		RateLimit: 10,
+		Sources:   sourceA + ", " + sourceB,
+		Tenant:    "acme",
+	}, nil)
+	repository.On("GetAPIKey", "unknown").Return(nil, nil)
+	repository.On("GetAPIKey", "broken").Return(nil, errors.New("connection refused"))
+
+	authenticator := NewAuthenticator([]Store{
+		NewStaticStore([]Key{{ID: "static", Secret: "static-secret-123"}}),
+		RepositoryStore{Repository: repository},
+	}, nil, DefaultMaxClockSkew)
+
+	key, err := authenticator.key(context.Background(), "static")
+	require.NoError(t, err)
+	assert.Equal(t, "static", key.ID)
+
+	key, err = authenticator.key(context.Background(), "db")
+	require.NoError(t, err)
+	assert.Equal(t, &Key{ID: "db", Secret: "db-secret-123456", RateLimit: 10, Sources: []string{sourceA, sourceB}, Tenant: "acme"}, key)
+
+	key, err = authenticator.key(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	_, err = authenticator.key(context.Background(), "broken")
+	assert.Error(t, err)
+}
+
+func TestAllowedSource(t *testing.T) {
+	ctx := context.Background()
+	assert.True(t, AllowedSource(ctx, sourceA))
+
+	ctx = context.WithValue(ctx, keyContextKey{}, &Key{ID: "all"})
+	assert.True(t, AllowedSource(ctx, sourceB))
+
+	ctx = context.WithValue(ctx, keyContextKey{}, &Key{ID: "wallet", Sources: []string{sourceA}})
+	assert.True(t, AllowedSource(ctx, sourceA))
+	assert.False(t, AllowedSource(ctx, sourceB))
+}
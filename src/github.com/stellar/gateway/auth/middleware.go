@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/access"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// DefaultMaxClockSkew is the default max difference between X-Timestamp of a
+// signed request and the server time
+const DefaultMaxClockSkew = 5 * time.Minute
+
+var authMetrics = struct {
+	failures    *metrics.Counter
+	rateLimited *metrics.Counter
+}{
+	failures:    metrics.NewCounter("bridge_auth_failures_total", "Number of requests rejected because of a missing or invalid API key or signature."),
+	rateLimited: metrics.NewCounter("bridge_auth_rate_limited_total", "Number of requests rejected because of the rate limit of the API key."),
+}
+
+// Authenticator authenticates requests to protected paths. Requests must
+// have `X-API-Key` header with:
+//
+//   - `<key id>:<secret>`, or
+//   - `<key id>` and `X-Timestamp` (Unix seconds) and `X-Signature` headers,
+//     see Sign.
+//
+// The key of an authenticated request is added to the request context, see
+// FromContext.
+type Authenticator struct {
+	// Stores are searched for keys in order
+	Stores []Store
+	// Paths are protected paths, other requests are not checked
+	Paths map[string]bool
+	// Prefixes protect all paths starting with them
+	Prefixes     []string
+	MaxClockSkew time.Duration
+	// OnFailure is called (when not nil) for every rejected request
+	OnFailure func(r *http.Request, message string)
+
+	limiter *access.Limiter
+	log     *logrus.Entry
+	now     func() time.Time
+}
+
+// NewAuthenticator creates a new Authenticator of requests to paths. Paths
+// ending with `/*` (ex. `/admin/payments/*`) protect all paths under them.
+func NewAuthenticator(stores []Store, paths []string, maxClockSkew time.Duration) *Authenticator {
+	a := &Authenticator{
+		Stores:       stores,
+		Paths:        map[string]bool{},
+		MaxClockSkew: maxClockSkew,
+		limiter:      access.NewLimiter(),
+		log:          logrus.WithFields(logrus.Fields{"service": "Authenticator"}),
+		now:          clock.Now,
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "/*") {
+			a.Prefixes = append(a.Prefixes, strings.TrimSuffix(path, "*"))
+			continue
+		}
+		a.Paths[path] = true
+	}
+	return a
+}
+
+// protects returns true when requests to path must be authenticated
+func (a *Authenticator) protects(path string) bool {
+	if a.Paths[path] {
+		return true
+	}
+	for _, prefix := range a.Prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware rejects requests to protected paths that are not authenticated
+// or exceed the rate limit of the key
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if !a.protects(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id, secret, hasSecret := parseAPIKey(r.Header.Get("X-API-Key"))
+		if id == "" {
+			a.reject(w, r, "Missing API key")
+			return
+		}
+
+		key, err := a.key(r.Context(), id)
+		if err != nil {
+			a.log.WithFields(logrus.Fields{"err": err}).Error("Error loading API key")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+		if key == nil {
+			a.reject(w, r, "Unknown API key")
+			return
+		}
+
+		if signature := r.Header.Get("X-Signature"); signature != "" {
+			if message := a.verifySignature(r, key, signature); message != "" {
+				a.reject(w, r, message)
+				return
+			}
+		} else if !hasSecret {
+			a.reject(w, r, "Missing API key secret or signature")
+			return
+		} else if key.RequireSignature {
+			a.reject(w, r, "API key requires signed requests")
+			return
+		} else if subtle.ConstantTimeCompare([]byte(secret), []byte(key.Secret)) != 1 {
+			a.reject(w, r, "Invalid API key secret")
+			return
+		}
+
+		if key.RateLimit > 0 {
+			allowed, retryAfter := a.limiter.Allow(key.ID, key.RateLimit, a.now())
+			if !allowed {
+				authMetrics.rateLimited.Inc()
+				a.log.WithFields(logrus.Fields{"key": key.ID}).Warn("Rate limit of API key exceeded")
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				server.Write(w, protocols.RateLimitExceededError)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithKey(r.Context(), key)))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// parseAPIKey splits X-API-Key header into key ID and secret
+func parseAPIKey(header string) (id, secret string, hasSecret bool) {
+	parts := strings.SplitN(header, ":", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1], true
+	}
+	return parts[0], "", false
+}
+
+func (a *Authenticator) key(ctx context.Context, id string) (*Key, error) {
+	for _, store := range a.Stores {
+		key, err := store.Key(ctx, id)
+		if err != nil || key != nil {
+			return key, err
+		}
+	}
+	return nil, nil
+}
+
+// verifySignature returns a reason of rejection or an empty string when the
+// signature is valid. The body is read and replaced, so next handlers can
+// read it again.
+func (a *Authenticator) verifySignature(r *http.Request, key *Key, signature string) string {
+	timestamp, err := strconv.ParseInt(r.Header.Get("X-Timestamp"), 10, 64)
+	if err != nil {
+		return "Missing or invalid X-Timestamp"
+	}
+	if skew := a.now().Sub(time.Unix(timestamp, 0)); skew > a.MaxClockSkew || skew < -a.MaxClockSkew {
+		return "X-Timestamp is too far from server time"
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, err = ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return "Error reading request body"
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	expected := Sign(key.Secret, timestamp, r.Method, r.RequestURI, body)
+	if subtle.ConstantTimeCompare([]byte(strings.ToLower(signature)), []byte(expected)) != 1 {
+		return "Invalid signature"
+	}
+	return ""
+}
+
+func (a *Authenticator) reject(w http.ResponseWriter, r *http.Request, message string) {
+	authMetrics.failures.Inc()
+	id, _, _ := parseAPIKey(r.Header.Get("X-API-Key"))
+	a.log.WithFields(logrus.Fields{"path": r.URL.Path, "key": id}).Warn(message)
+	if a.OnFailure != nil {
+		a.OnFailure(r, message)
+	}
+	server.Write(w, protocols.UnauthorizedError)
+}
@@ -0,0 +1,78 @@
+package balances
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// EmailNotifier sends alerts by email using an SMTP server
+type EmailNotifier struct {
+	// Address of the SMTP server, ex. "smtp.example.com:587"
+	Address string
+	// Username and Password authenticate using PLAIN auth when Username is
+	// set. Auth requires TLS (STARTTLS) unless the server is on localhost.
+	Username string
+	Password string
+	From     string
+	To       []string
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates a new EmailNotifier
+func NewEmailNotifier(address, username, password, from string, to []string) *EmailNotifier {
+	return &EmailNotifier{
+		Address:  address,
+		Username: username,
+		Password: password,
+		From:     from,
+		To:       to,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Notify sends an email with the balance
+func (n *EmailNotifier) Notify(balance Balance) error {
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, err := net.SplitHostPort(n.Address)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+	return n.sendMail(n.Address, auth, n.From, n.To, n.message(balance))
+}
+
+// message returns the email with the balance in RFC 5322 format
+func (n *EmailNotifier) message(balance Balance) []byte {
+	asset := "XLM"
+	if balance.AssetCode != "" {
+		asset = balance.AssetCode + " (" + balance.AssetIssuer + ")"
+	}
+
+	subject := "Balance of " + balance.AccountID + " is " + balance.Status
+	if !balance.Alerting() {
+		subject = "Balance of " + balance.AccountID + " recovered"
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&message, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&message, "Account: %s\r\n", balance.AccountID)
+	fmt.Fprintf(&message, "Asset: %s\r\n", asset)
+	fmt.Fprintf(&message, "Status: %s\r\n", balance.Status)
+	fmt.Fprintf(&message, "Balance: %s\r\n", balance.Balance)
+	fmt.Fprintf(&message, "Reserve: %s\r\n", balance.Reserve)
+	fmt.Fprintf(&message, "Available: %s\r\n", balance.Available)
+	fmt.Fprintf(&message, "Min: %s\r\n", balance.Min)
+	if balance.AssetCode != "" {
+		fmt.Fprintf(&message, "Limit: %s\r\n", balance.Limit)
+	}
+	fmt.Fprintf(&message, "Checked at: %s\r\n", balance.CheckedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+	return message.Bytes()
+}
@@ -0,0 +1,320 @@
+// Package balances periodically checks balances of configured accounts
+// against min thresholds, so low balances are noticed before payments start
+// failing with op_underfunded. Native balances are checked after subtracting
+// the reserve of the account, trustlines also against their limits (a full
+// trustline cannot receive payments).
+package balances
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/webhooks"
+	"github.com/stellar/go/support/errors"
+)
+
+// DefaultBaseReserve is the base reserve of the public network in stroops
+const DefaultBaseReserve = amounts.One / 2
+
+// Statuses of balances
+const (
+	// StatusOK means the balance is above its thresholds
+	StatusOK = "ok"
+	// StatusLow means the available balance is below the min
+	StatusLow = "low"
+	// StatusNearLimit means the trustline can receive less than the min
+	// limit room before it's full
+	StatusNearLimit = "near_limit"
+	// StatusNoTrustline means the account does not trust the asset or the
+	// issuer has not authorized it
+	StatusNoTrustline = "no_trustline"
+	// StatusNoAccount means the account does not exist
+	StatusNoAccount = "no_account"
+	// StatusUnknown means the balance has not been checked yet because
+	// Horizon is unavailable or background requests are throttled
+	StatusUnknown = "unknown"
+)
+
+var alertingGauge = metrics.NewGauge("bridge_balances_alerting", "Number of monitored balances below their thresholds.")
+
+// AccountLoader loads current state of accounts, implemented by
+// horizon.Horizon
+type AccountLoader interface {
+	LoadAccountFresh(accountID string) (horizon.AccountResponse, error)
+}
+
+// Publisher sends events to webhooks, implemented by webhooks.Dispatcher
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, data interface{})
+}
+
+// Notifier sends alerts outside of the bridge server, ex. EmailNotifier
+type Notifier interface {
+	Notify(balance Balance) error
+}
+
+// ToppingUp sends top-up payments of amount to the account of threshold from
+// its FundingSeed, implemented by handlers.RequestHandler
+type ToppingUp interface {
+	TopUp(ctx context.Context, threshold Threshold, amount amounts.Amount) error
+}
+
+// Threshold is a monitored balance of an account. Empty AssetCode is the
+// native asset.
+type Threshold struct {
+	AccountID   string
+	AssetCode   string
+	AssetIssuer string
+	// Min is the min available balance in stroops
+	Min int64
+	// MinLimitRoom is the min amount in stroops the trustline can still
+	// receive, not checked when 0
+	MinLimitRoom int64
+	// TopUpTo is the available balance in stroops restored by a top-up
+	// payment from FundingSeed when the balance is low, no top-ups when 0
+	TopUpTo     int64
+	FundingSeed string
+}
+
+// Balance is a result of a check of a Threshold
+type Balance struct {
+	AccountID   string         `json:"account_id"`
+	AssetCode   string         `json:"asset_code,omitempty"`
+	AssetIssuer string         `json:"asset_issuer,omitempty"`
+	Balance     amounts.Amount `json:"balance"`
+	// Reserve is the min balance of the account (native asset only)
+	Reserve amounts.Amount `json:"reserve"`
+	// Available is Balance minus Reserve
+	Available amounts.Amount `json:"available"`
+	Min       amounts.Amount `json:"min"`
+	// Limit of the trustline, 0 for native asset
+	Limit     amounts.Amount `json:"limit"`
+	Status    string         `json:"status"`
+	CheckedAt time.Time      `json:"checked_at"`
+}
+
+// Alerting returns true when the balance is below its thresholds
+func (b Balance) Alerting() bool {
+	return b.Status != StatusOK && b.Status != StatusUnknown
+}
+
+// Monitor checks balances of accounts and alerts when they fall below
+// thresholds. Alerts are sent when status of a balance changes, not on every
+// check, so a balance_recovered event follows every balance_low event.
+type Monitor struct {
+	Accounts   AccountLoader
+	Thresholds []Threshold
+	// BaseReserve in stroops used to compute reserves of accounts
+	BaseReserve int64
+	// Webhooks receives balance_low and balance_recovered events, can be nil
+	Webhooks Publisher
+	// Notifier can be nil
+	Notifier Notifier
+	// TopUps sends top-up payments of thresholds with TopUpTo, can be nil
+	TopUps ToppingUp
+	now    func() time.Time
+	log    *logrus.Entry
+
+	mutex    sync.RWMutex
+	balances []Balance
+}
+
+// NewMonitor creates a new Monitor. DefaultBaseReserve is used when
+// baseReserve is 0.
+func NewMonitor(accounts AccountLoader, thresholds []Threshold, baseReserve int64, now func() time.Time) *Monitor {
+	if baseReserve == 0 {
+		baseReserve = DefaultBaseReserve
+	}
+	return &Monitor{
+		Accounts:    accounts,
+		Thresholds:  thresholds,
+		BaseReserve: baseReserve,
+		now:         now,
+		log:         logrus.WithFields(logrus.Fields{"service": "BalanceMonitor"}),
+	}
+}
+
+// Balances returns results of the last check in order of thresholds, nil
+// before the first check or when the monitor is nil
+func (m *Monitor) Balances() []Balance {
+	if m == nil {
+		return nil
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.balances
+}
+
+// Run checks all thresholds, sends alerts of balances that changed status
+// and saves results returned by Balances. Balances of accounts that cannot be
+// loaded because Horizon is unavailable keep results of the previous check.
+func (m *Monitor) Run(ctx context.Context) []Balance {
+	previous := map[Threshold]Balance{}
+	for i, balance := range m.Balances() {
+		previous[m.Thresholds[i]] = balance
+	}
+
+	// nil values are accounts that do not exist
+	accounts := map[string]*horizon.AccountResponse{}
+	unavailable := map[string]bool{}
+	for _, threshold := range m.Thresholds {
+		id := threshold.AccountID
+		if _, ok := accounts[id]; ok || unavailable[id] {
+			continue
+		}
+
+		response, err := m.Accounts.LoadAccountFresh(id)
+		switch {
+		case err == nil:
+			accounts[id] = &response
+		case errors.Cause(err) == horizon.ErrUnavailable, errors.Cause(err) == horizon.ErrThrottled:
+			m.log.WithFields(logrus.Fields{"err": err, "account_id": id}).Error("Error loading account")
+			unavailable[id] = true
+		default:
+			accounts[id] = nil
+		}
+	}
+
+	result := make([]Balance, len(m.Thresholds))
+	var alerting float64
+	for i, threshold := range m.Thresholds {
+		last, checked := previous[threshold]
+		if unavailable[threshold.AccountID] {
+			if !checked {
+				last = m.unknown(threshold)
+			}
+			result[i] = last
+		} else {
+			result[i] = m.check(threshold, accounts[threshold.AccountID])
+		}
+
+		balance := result[i]
+		if balance.Alerting() {
+			alerting++
+		}
+
+		lastStatus := StatusOK
+		if checked && last.Status != StatusUnknown {
+			lastStatus = last.Status
+		}
+		if balance.Status != StatusUnknown && balance.Status != lastStatus {
+			m.alert(ctx, balance)
+			if balance.Status == StatusLow {
+				m.topUp(ctx, threshold, balance)
+			}
+		}
+	}
+
+	alertingGauge.Set(alerting)
+
+	m.mutex.Lock()
+	m.balances = result
+	m.mutex.Unlock()
+	return result
+}
+
+// unknown returns the balance of threshold that has not been checked yet
+func (m *Monitor) unknown(threshold Threshold) Balance {
+	return Balance{
+		AccountID:   threshold.AccountID,
+		AssetCode:   threshold.AssetCode,
+		AssetIssuer: threshold.AssetIssuer,
+		Min:         amounts.Amount(threshold.Min),
+		Status:      StatusUnknown,
+	}
+}
+
+// check returns the balance of threshold in account, nil when the account
+// does not exist
+func (m *Monitor) check(threshold Threshold, account *horizon.AccountResponse) Balance {
+	balance := m.unknown(threshold)
+	balance.Status = StatusOK
+	balance.CheckedAt = m.now()
+
+	if account == nil {
+		balance.Status = StatusNoAccount
+		return balance
+	}
+
+	line := account.Balance(threshold.AssetCode, threshold.AssetIssuer)
+	if line == nil || !line.Authorized() {
+		balance.Status = StatusNoTrustline
+		return balance
+	}
+
+	// Horizon returns valid amounts
+	value, _ := amounts.Parse(line.Balance)
+	balance.Balance = amounts.Amount(value)
+	if threshold.AssetCode == "" {
+		balance.Reserve = amounts.Amount((2 + account.SubentryCount) * m.BaseReserve)
+	} else {
+		limit, _ := amounts.Parse(line.Limit)
+		balance.Limit = amounts.Amount(limit)
+	}
+	balance.Available = balance.Balance - balance.Reserve
+
+	switch {
+	case int64(balance.Available) < threshold.Min:
+		balance.Status = StatusLow
+	case threshold.MinLimitRoom > 0 && int64(balance.Limit-balance.Balance) < threshold.MinLimitRoom:
+		balance.Status = StatusNearLimit
+	}
+	return balance
+}
+
+// alert logs a change of status of balance and sends it to webhooks and the
+// notifier
+func (m *Monitor) alert(ctx context.Context, balance Balance) {
+	fields := logrus.Fields{
+		"account_id":   balance.AccountID,
+		"asset_code":   balance.AssetCode,
+		"asset_issuer": balance.AssetIssuer,
+		"available":    balance.Available.String(),
+		"status":       balance.Status,
+	}
+	if balance.Alerting() {
+		m.log.WithFields(fields).Warn("Balance below threshold")
+	} else {
+		m.log.WithFields(fields).Info("Balance recovered")
+	}
+
+	if m.Webhooks != nil {
+		eventType := webhooks.EventBalanceRecovered
+		if balance.Alerting() {
+			eventType = webhooks.EventBalanceLow
+		}
+		m.Webhooks.Publish(ctx, eventType, balance)
+	}
+
+	if m.Notifier != nil {
+		if err := m.Notifier.Notify(balance); err != nil {
+			m.log.WithFields(logrus.Fields{"err": err}).Error("Error sending balance alert")
+		}
+	}
+}
+
+// topUp sends a payment restoring the available balance to TopUpTo. It's
+// sent once when the balance becomes low, so a top-up waiting for approval is
+// not sent again on the next check.
+func (m *Monitor) topUp(ctx context.Context, threshold Threshold, balance Balance) {
+	if m.TopUps == nil || threshold.TopUpTo == 0 {
+		return
+	}
+
+	amount := amounts.Amount(threshold.TopUpTo) - balance.Available
+	if err := m.TopUps.TopUp(ctx, threshold, amount); err != nil {
+		m.log.WithFields(logrus.Fields{
+			"err":          err,
+			"account_id":   balance.AccountID,
+			"asset_code":   balance.AssetCode,
+			"asset_issuer": balance.AssetIssuer,
+			"amount":       amount.String(),
+		}).Error("Error sending balance top-up")
+	}
+}
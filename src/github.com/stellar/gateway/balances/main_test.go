@@ -0,0 +1,182 @@
+package balances
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccount = "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5"
+	testIssuer  = "GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3"
+)
+
+type publisherMock struct {
+	events []string
+}
+
+func (p *publisherMock) Publish(ctx context.Context, eventType string, data interface{}) {
+	p.events = append(p.events, eventType+":"+data.(Balance).Status)
+}
+
+func account(native, usd, usdLimit string) horizon.AccountResponse {
+	return horizon.AccountResponse{
+		AccountID:     testAccount,
+		SubentryCount: 2,
+		Balances: []horizon.AccountBalance{
+			{AssetType: "native", Balance: native},
+			{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: testIssuer, Balance: usd, Limit: usdLimit},
+		},
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	mockHorizon := new(mocks.MockHorizon)
+	now := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	monitor := NewMonitor(mockHorizon, []Threshold{
+		{AccountID: testAccount, Min: 10 * amounts.One},
+		{AccountID: testAccount, AssetCode: "USD", AssetIssuer: testIssuer, Min: 100 * amounts.One, MinLimitRoom: 1000 * amounts.One},
+		{AccountID: testAccount, AssetCode: "EUR", AssetIssuer: testIssuer, Min: amounts.One},
+	}, 0, func() time.Time { return now })
+	publisher := &publisherMock{}
+	monitor.Webhooks = publisher
+
+	assert.Nil(t, monitor.Balances())
+
+	// Reserve is (2 + 2) * 0.5 = 2 XLM
+	mockHorizon.On("LoadAccountFresh", testAccount).Return(account("12.5", "500", "10000"), nil).Once()
+	result := monitor.Run(context.Background())
+	require.Len(t, result, 3)
+	assert.Equal(t, StatusOK, result[0].Status)
+	assert.Equal(t, "10.5000000", result[0].Available.String())
+	assert.Equal(t, "2.0000000", result[0].Reserve.String())
+	assert.Equal(t, StatusOK, result[1].Status)
+	assert.Equal(t, "10000.0000000", result[1].Limit.String())
+	assert.Equal(t, StatusNoTrustline, result[2].Status)
+	assert.Equal(t, now, result[2].CheckedAt)
+	assert.Equal(t, []string{"balance_low:no_trustline"}, publisher.events)
+	assert.Equal(t, float64(1), alertingGauge.Value())
+	assert.Equal(t, result, monitor.Balances())
+
+	// Alerts are sent when status changes
+	publisher.events = nil
+	mockHorizon.On("LoadAccountFresh", testAccount).Return(account("11.5", "9500", "10000"), nil).Once()
+	result = monitor.Run(context.Background())
+	assert.Equal(t, StatusLow, result[0].Status)
+	assert.Equal(t, StatusNearLimit, result[1].Status)
+	assert.Equal(t, []string{"balance_low:low", "balance_low:near_limit"}, publisher.events)
+
+	// Results of the previous check are kept when Horizon is unavailable
+	publisher.events = nil
+	mockHorizon.On("LoadAccountFresh", testAccount).Return(horizon.AccountResponse{}, horizon.ErrUnavailable).Once()
+	assert.Equal(t, result, monitor.Run(context.Background()))
+	assert.Empty(t, publisher.events)
+
+	mockHorizon.On("LoadAccountFresh", testAccount).Return(account("12.5", "500", "10000"), nil).Once()
+	result = monitor.Run(context.Background())
+	assert.Equal(t, []string{"balance_recovered:ok", "balance_recovered:ok"}, publisher.events)
+
+	publisher.events = nil
+	mockHorizon.On("LoadAccountFresh", testAccount).Return(horizon.AccountResponse{}, errors.New("Not found")).Once()
+	result = monitor.Run(context.Background())
+	assert.Equal(t, StatusNoAccount, result[0].Status)
+	assert.Equal(t, []string{"balance_low:no_account", "balance_low:no_account", "balance_low:no_account"}, publisher.events)
+	assert.Equal(t, float64(3), alertingGauge.Value())
+
+	mockHorizon.AssertExpectations(t)
+}
+
+func TestMonitorUnavailable(t *testing.T) {
+	mockHorizon := new(mocks.MockHorizon)
+	monitor := NewMonitor(mockHorizon, []Threshold{{AccountID: testAccount, Min: amounts.One}}, 0, time.Now)
+	publisher := &publisherMock{}
+	monitor.Webhooks = publisher
+
+	mockHorizon.On("LoadAccountFresh", testAccount).Return(horizon.AccountResponse{}, horizon.ErrUnavailable).Once()
+	result := monitor.Run(context.Background())
+	assert.Equal(t, StatusUnknown, result[0].Status)
+	assert.False(t, result[0].Alerting())
+	assert.Empty(t, publisher.events)
+
+	// Balances that were never checked do not recover
+	mockHorizon.On("LoadAccountFresh", testAccount).Return(account("12.5", "0", "0"), nil).Once()
+	result = monitor.Run(context.Background())
+	assert.Equal(t, StatusOK, result[0].Status)
+	assert.Empty(t, publisher.events)
+}
+
+type topUpsMock struct {
+	amounts []amounts.Amount
+}
+
+func (m *topUpsMock) TopUp(ctx context.Context, threshold Threshold, amount amounts.Amount) error {
+	m.amounts = append(m.amounts, amount)
+	return nil
+}
+
+func TestMonitorTopUp(t *testing.T) {
+	mockHorizon := new(mocks.MockHorizon)
+	monitor := NewMonitor(mockHorizon, []Threshold{
+		{AccountID: testAccount, Min: 10 * amounts.One, TopUpTo: 50 * amounts.One},
+		{AccountID: testAccount, AssetCode: "USD", AssetIssuer: testIssuer, Min: 100 * amounts.One},
+	}, 0, time.Now)
+	topUps := &topUpsMock{}
+	monitor.TopUps = topUps
+
+	// Available balance is 8 XLM (10 minus 2 XLM reserve), USD has no top-ups
+	mockHorizon.On("LoadAccountFresh", testAccount).Return(account("10", "50", "10000"), nil).Twice()
+	monitor.Run(context.Background())
+	assert.Equal(t, []amounts.Amount{42 * amounts.One}, topUps.amounts)
+
+	// Top-ups are sent once while the balance is low
+	monitor.Run(context.Background())
+	assert.Len(t, topUps.amounts, 1)
+	mockHorizon.AssertExpectations(t)
+}
+
+func TestEmailNotifier(t *testing.T) {
+	notifier := NewEmailNotifier("smtp.example.com:587", "alerts", "secret", "bridge@example.com", []string{"ops@example.com", "finance@example.com"})
+
+	var sent struct {
+		addr string
+		auth smtp.Auth
+		to   []string
+		msg  string
+	}
+	notifier.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sent.addr, sent.auth, sent.to, sent.msg = addr, a, to, string(msg)
+		return nil
+	}
+
+	err := notifier.Notify(Balance{
+		AccountID:   testAccount,
+		AssetCode:   "USD",
+		AssetIssuer: testIssuer,
+		Balance:     50 * amounts.One,
+		Available:   50 * amounts.One,
+		Min:         100 * amounts.One,
+		Status:      StatusLow,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", sent.addr)
+	assert.NotNil(t, sent.auth)
+	assert.Equal(t, []string{"ops@example.com", "finance@example.com"}, sent.to)
+	assert.Contains(t, sent.msg, "To: ops@example.com, finance@example.com\r\n")
+	assert.Contains(t, sent.msg, "Subject: Balance of "+testAccount+" is low\r\n")
+	assert.Contains(t, sent.msg, "Available: 50.0000000\r\n")
+	assert.Contains(t, sent.msg, "Asset: USD ("+testIssuer+")\r\n")
+
+	notifier.Username = ""
+	err = notifier.Notify(Balance{AccountID: testAccount, Status: StatusOK})
+	require.NoError(t, err)
+	assert.Nil(t, sent.auth)
+	assert.Contains(t, sent.msg, "recovered")
+}
@@ -1,73 +1,297 @@
 package bridge
 
 import (
+	"context"
+	"crypto/x509"
+	"database/sql"
 	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/elazarl/go-bindata-assetfs"
-	"github.com/facebookgo/inject"
+	"github.com/stellar/gateway/access"
+	"github.com/stellar/gateway/audit"
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/balances"
 	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/bridge/grpcserver"
 	"github.com/stellar/gateway/bridge/gui"
 	"github.com/stellar/gateway/bridge/handlers"
+	"github.com/stellar/gateway/cache"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/congestion"
 	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/db/drivers/mysql"
 	"github.com/stellar/gateway/db/drivers/postgres"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/expected"
+	"github.com/stellar/gateway/export"
+	"github.com/stellar/gateway/external"
+	"github.com/stellar/gateway/features"
+	federationserver "github.com/stellar/gateway/federation"
+	"github.com/stellar/gateway/health"
 	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/incident"
+	"github.com/stellar/gateway/leader"
 	"github.com/stellar/gateway/listener"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/openapi"
+	"github.com/stellar/gateway/profiles"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/queue"
+	"github.com/stellar/gateway/rates"
+	"github.com/stellar/gateway/reconciliation"
+	"github.com/stellar/gateway/redis"
+	"github.com/stellar/gateway/references"
+	"github.com/stellar/gateway/reports"
+	"github.com/stellar/gateway/risk"
+	"github.com/stellar/gateway/sep24"
 	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/signers"
+	"github.com/stellar/gateway/snapshots"
+	"github.com/stellar/gateway/sorobanrpc"
+	"github.com/stellar/gateway/stellarcore"
+	"github.com/stellar/gateway/stream"
 	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/gateway/tracing"
+	"github.com/stellar/gateway/webhooks"
 	"github.com/stellar/go/clients/federation"
-	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/keypair"
 	"github.com/zenazn/goji/graceful"
 	"github.com/zenazn/goji/web"
 	"github.com/zenazn/goji/web/middleware"
 )
 
+const (
+	// settlementInterval is how often held payments are checked for the end of settlement window
+	settlementInterval = 10 * time.Second
+	// reconciliationInterval is how often sent transactions are checked for cross-region conflicts
+	reconciliationInterval = time.Minute
+	// horizonHealthCheckInterval is how often Horizon endpoints are checked
+	horizonHealthCheckInterval = 10 * time.Second
+	// poolMetricsInterval is how often DB connection pool metrics are updated
+	poolMetricsInterval = 10 * time.Second
+	// incidentWatchInterval is how often the payments stream is checked for stalls
+	incidentWatchInterval = 30 * time.Second
+	// velocityPruneInterval is how often payments outside of velocity windows are removed
+	velocityPruneInterval = time.Minute
+	// incidentSnapshotSize is the max number of rows of a snapshot in diagnostic bundles
+	incidentSnapshotSize = 100
+)
+
+// MigrationsComponent is a name of bridge server DB migrations
+const MigrationsComponent = "gateway"
+
 // App is the application object
 type App struct {
 	config         config.Config
 	requestHandler handlers.RequestHandler
-}
+	// federationHandler is nil when federation server is disabled
+	federationHandler http.Handler
+	health            *health.Checker
+	// statuses is nil when gRPC is disabled or there is no database
+	statuses *grpcserver.StatusHub
+	// stream is nil when streams of received payments are disabled
+	stream  *stream.Handler
+	version string
+	// LoadConfig reads the config file again when the server receives SIGHUP
+	// or POST /admin/reload request. Config can't be reloaded when nil.
+	LoadConfig func() (config.Config, error)
 
-// NewApp constructs an new App instance from the provided config.
-func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version string) (app *App, err error) {
-	var g inject.Graph
+	reloadMutex sync.Mutex
+	live        *config.Live
+	rateLimiter *access.RateLimiter
+	// redisClient is nil when Redis is not configured
+	redisClient *redis.Client
+	// listening is true when PaymentListener has been started
+	listening bool
+	// networkDrivers are DB drivers of additional networks, closed on
+	// shutdown
+	networkDrivers map[string]db.Driver
+	// drainDeadline is set when the server starts stopping
+	drainDeadline time.Time
+}
 
-	var driver db.Driver
+// NewDriver returns a DB driver connected to the database or nil when
+// database is not configured
+func NewDriver(config config.Config) (driver db.Driver, err error) {
+	url := config.Database.URL
 	switch config.Database.Type {
 	case "mysql":
 		driver = &mysql.Driver{}
 	case "postgres":
-		driver = &postgres.Driver{}
+		driver = &postgres.Driver{NotifyChannel: config.Database.NotifyChannel}
+	case "sqlite":
+		driver = &sqlite.Driver{}
+	case "memory":
+		driver = &sqlite.Driver{}
+		url = sqlite.MemoryURL
 	case "":
 		// Allow to start gateway server with a single endpoint: /payment
-		break
+		return nil, nil
 	default:
 		return nil, fmt.Errorf("%s database has no driver", config.Database.Type)
 	}
 
-	var entityManager db.EntityManagerInterface
-	var repository db.Repository
+	err = driver.Init(url)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot connect to a DB: %s", err)
+	}
 
-	if driver != nil {
-		err = driver.Init(config.Database.URL)
+	db.ConfigurePool(driver.DB().DB, config.DatabasePoolOptions())
+
+	if config.Database.Secondary.Type != "" {
+		var secondary db.Driver
+		secondary, err = newSecondaryDriver(config.Database.Secondary)
 		if err != nil {
-			err = fmt.Errorf("Cannot connect to a DB: %s", err)
-			return
+			return nil, err
 		}
+		db.ConfigurePool(secondary.DB().DB, config.DatabasePoolOptions())
+		driver = db.NewDualWriteDriver(driver, secondary, config.Database.Secondary.VerifyReads)
+	}
+	return
+}
+
+// newNetworkDrivers returns DB drivers connected to Postgres schemas of
+// networks with database_schema param by network name
+func newNetworkDrivers(config config.Config) (map[string]db.Driver, error) {
+	drivers := map[string]db.Driver{}
+	for _, network := range config.Networks {
+		if network.DatabaseSchema == "" {
+			continue
+		}
+
+		driver := &postgres.Driver{Schema: network.DatabaseSchema}
+		err := driver.Init(config.Database.URL)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot connect to a DB of %s network: %s", network.Name, err)
+		}
+		db.ConfigurePool(driver.DB().DB, config.DatabasePoolOptions())
+		drivers[network.Name] = driver
+	}
+	return drivers, nil
+}
+
+// newNetworks creates dependencies of additional networks. Transactions of
+// networks without a driver are stored in the main database.
+func newNetworks(
+	config config.Config,
+	drivers map[string]db.Driver,
+	repository db.RepositoryInterface,
+	entityManager db.EntityManagerInterface,
+	keys *signers.Keyring,
+	statuses *grpcserver.StatusHub,
+) (map[string]*handlers.Network, error) {
+	networks := map[string]*handlers.Network{}
+	for _, network := range config.Networks {
+		horizonOptions := config.HorizonClient.Options()
+		horizonOptions.NetworkPassphrase = network.NetworkPassphrase
+		h := horizon.NewWithOptions(network.Horizon, horizonOptions)
+		h.StartHealthChecks(horizonHealthCheckInterval)
+
+		n := &handlers.Network{
+			Config:        config.ForNetwork(network),
+			Horizon:       &h,
+			Repository:    repository,
+			EntityManager: entityManager,
+		}
+		if driver, ok := drivers[network.Name]; ok {
+			n.Repository = db.NewRepository(driver)
+			n.EntityManager = db.NewEntityManager(driver)
+		}
+
+		ts := submitter.NewTransactionSubmitter(&h, n.EntityManager, network.NetworkPassphrase, clock.Now)
+		if keys != nil {
+			// The signing service of the main network signs transactions
+			// with its network passphrase only
+			ts.Signer = &submitter.KeyringSigner{Keys: keys}
+		}
+		ts.Region = config.Region.Name
+		ts.TimeBounds = config.Submission.TimeBoundsDuration()
+		if statuses != nil {
+			ts.StatusWatcher = statuses.Watcher(network.Name)
+		}
+		for _, seed := range []string{network.Accounts.AuthorizingSeed, network.Accounts.BaseSeed} {
+			if seed == "" {
+				continue
+			}
+			err := ts.InitAccount(seed)
+			if err != nil {
+				return nil, fmt.Errorf("Cannot initialize account of %s network: %s", network.Name, err)
+			}
+		}
+		n.TransactionSubmitter = &ts
+
+		log.Print("Payments can be sent to ", network.Name, " network")
+		networks[network.Name] = n
+	}
+	return networks, nil
+}
+
+// newSecondaryDriver returns a driver connected to the secondary database
+// receiving copies of all writes
+func newSecondaryDriver(config db.SecondaryConfig) (driver db.Driver, err error) {
+	switch config.Type {
+	case "mysql":
+		driver = &mysql.Driver{}
+	case "postgres":
+		driver = &postgres.Driver{}
+	case "sqlite":
+		driver = &sqlite.Driver{}
+	default:
+		return nil, fmt.Errorf("%s database has no driver", config.Type)
+	}
+
+	err = driver.Init(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot connect to a secondary DB: %s", err)
+	}
+	return
+}
+
+// NewApp constructs an new App instance from the provided config.
+func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version string) (app *App, err error) {
+	driver, err := NewDriver(config)
+	if err != nil {
+		return
+	}
 
+	networkDrivers, err := newNetworkDrivers(config)
+	if err != nil {
+		return
+	}
+
+	var entityManager db.EntityManagerInterface
+	var repository db.RepositoryInterface
+
+	if driver != nil {
 		entityManager = db.NewEntityManager(driver)
 		repository = db.NewRepository(driver)
 	}
 
+	if config.Database.Type == "memory" && !migrateFlag {
+		// In-memory database is empty on every start
+		_, err = driver.MigrateUp(MigrationsComponent)
+		if err != nil {
+			err = fmt.Errorf("Cannot migrate in-memory DB: %s", err)
+			return
+		}
+		log.Warn("Using in-memory database, data will be lost on exit")
+	}
+
 	if migrateFlag {
 		if driver == nil {
 			log.Fatal("No database driver.")
@@ -75,12 +299,20 @@ func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version st
 		}
 
 		var migrationsApplied int
-		migrationsApplied, err = driver.MigrateUp("gateway")
+		migrationsApplied, err = driver.MigrateUp(MigrationsComponent)
 		if err != nil {
 			return
 		}
 
 		log.Info("Applied migrations: ", migrationsApplied)
+
+		for name, networkDriver := range networkDrivers {
+			migrationsApplied, err = networkDriver.MigrateUp(MigrationsComponent)
+			if err != nil {
+				return
+			}
+			log.Info("Applied migrations of ", name, " network: ", migrationsApplied)
+		}
 		os.Exit(0)
 		return
 	}
@@ -91,10 +323,153 @@ func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version st
 		return
 	}
 
-	h := horizon.New(config.Horizon)
+	if driver != nil {
+		err = db.CheckSchema(driver, MigrationsComponent)
+		if err != nil {
+			return
+		}
+
+		db.MonitorPool(driver.DB().DB, poolMetricsInterval)
+	}
+
+	for _, networkDriver := range networkDrivers {
+		err = db.CheckSchema(networkDriver, MigrationsComponent)
+		if err != nil {
+			return
+		}
+	}
+
+	horizonOptions := config.HorizonClient.Options()
+	horizonOptions.NetworkPassphrase = config.NetworkPassphrase
+	h := horizon.NewWithOptions(config.Horizon, horizonOptions)
+	h.StartHealthChecks(horizonHealthCheckInterval)
+	// backgroundHorizon sends non-critical requests which are throttled near
+	// Horizon rate limit
+	backgroundHorizon := h.Background()
+
+	var congestionMonitor *congestion.Monitor
+	if config.Congestion.Enabled() {
+		log.Print("Monitoring network congestion every ", config.Congestion.Interval)
+		congestionMonitor = congestion.NewMonitor(backgroundHorizon, config.Congestion.Options())
+		congestionMonitor.Start(config.Congestion.IntervalDuration())
+		h.Congestion = congestionMonitor
+		backgroundHorizon.Congestion = congestionMonitor
+	}
+
+	var driftMonitor *clock.DriftMonitor
+	if config.Clock.Enabled() {
+		log.Print("Checking clock drift every ", config.Clock.CheckInterval)
+		driftMonitor = clock.NewDriftMonitor(backgroundHorizon, clock.Default, config.Clock.MaxDriftOrDefault())
+		driftMonitor.Start(config.Clock.CheckIntervalDuration())
+	}
+
+	// submissionHorizon is used to submit transactions
+	var submissionHorizon horizon.HorizonInterface = &h
+	if config.Submission.Backend == "stellar-core" {
+		// Watcher finds submitted transactions and loads sequence numbers
+		var watcher interface {
+			stellarcore.Watcher
+			stellarcore.Accounts
+		}
+		if config.Submission.StellarCore.SorobanRPCURL != "" {
+			watcher = stellarcore.NewRPCWatcher(sorobanrpc.New(
+				config.Submission.StellarCore.SorobanRPCURL,
+				&http.Client{Timeout: 10 * time.Second},
+			))
+		} else {
+			watcher, err = stellarcore.NewDatabaseWatcher(config.Submission.StellarCore.DatabaseURL)
+			if err != nil {
+				err = fmt.Errorf("Cannot connect to stellar-core DB: %s", err)
+				return
+			}
+		}
+
+		log.Print("Transactions will be submitted to stellar-core, account details are still loaded from Horizon")
+		submissionHorizon = &stellarcore.Horizon{
+			HorizonInterface: &h,
+			Core: stellarcore.NewClient(
+				config.Submission.StellarCore.URL,
+				&http.Client{Timeout: 10 * time.Second},
+				watcher,
+				config.NetworkPassphrase,
+				config.Submission.StellarCore.PollIntervalDuration(),
+				config.Submission.StellarCore.TimeoutDuration(),
+			),
+			Accounts: watcher,
+		}
+	}
+
+	var auditEmitter *audit.Emitter
+	if config.Audit.Enabled() {
+		log.Print("Sending security events to ", config.Audit.URL)
+		auditEmitter = audit.NewEmitter(
+			config.Audit.URL,
+			config.Audit.FormatOrDefault(),
+			config.Audit.Secret,
+			version,
+			&http.Client{Timeout: 10 * time.Second},
+			entityManager,
+			repository,
+			clock.Now,
+		)
+		auditEmitter.Start(config.Audit.RetryIntervalDuration())
+	}
+
+	if config.Tracing.Enabled() {
+		log.Print("Sending traces to ", config.Tracing.Endpoint)
+		exporter := tracing.NewOTLPExporter(
+			config.Tracing.Endpoint,
+			config.Tracing.ServiceNameOrDefault(),
+			config.Tracing.Headers,
+			&http.Client{Timeout: 10 * time.Second},
+		)
+		exporter.Start()
+		tracing.SetDefault(tracing.NewTracer(exporter, config.Tracing.SampleRatioOrDefault()))
+	}
+
+	var incidents *incident.Recorder
+	if config.Incidents.Enabled() {
+		incidents, err = newIncidentRecorder(config, repository, &http.Client{Timeout: time.Minute})
+		if err != nil {
+			return
+		}
+	}
+
+	// Daily aggregates are maintained whenever there is a database, so
+	// reports include payments since the aggregates were created
+	var aggregator *reports.Aggregator
+	if repository != nil {
+		aggregator = reports.NewAggregator(repository)
+	}
+
+	var recorder *rates.Recorder
+	if config.Rates.Provider != "" {
+		recorder = newRatesRecorder(config, repository, entityManager)
+	}
+
+	var dispatcher *webhooks.Dispatcher
+	if config.Webhooks.Enabled {
+		dispatcher = webhooks.NewDispatcher(repository, config.OutboundTLS.Client(http.Client{Timeout: config.Webhooks.TimeoutDuration()}), config.CloudEvents.Source)
+		log.Print("Events will be sent to webhooks registered using /admin/webhooks")
+	}
+
+	var matcher *expected.Matcher
+	if config.ExpectedPayments.Enabled {
+		matcher = expected.NewMatcher(repository, entityManager)
+	}
+
+	var sep24Tracker *sep24.Tracker
+	if config.SEP24.Enabled {
+		sep24Tracker = sep24.NewTracker(repository, entityManager, dispatcher)
+	}
+
+	var referenceStore *references.Store
+	if config.MemoReferences.Enabled() {
+		referenceStore = references.NewStore(config.MemoReferences.KeyBytes(), config.MemoReferences.AllowsPlaintext(), repository, entityManager)
+	}
 
 	log.Print("Creating and initializing TransactionSubmitter")
-	ts := submitter.NewTransactionSubmitter(&h, entityManager, config.NetworkPassphrase, time.Now)
+	ts := submitter.NewTransactionSubmitter(submissionHorizon, entityManager, config.NetworkPassphrase, clock.Now)
 	if err != nil {
 		return
 	}
@@ -120,26 +495,227 @@ func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version st
 		}
 	}
 
+	if config.Accounts.FunderSeed != "" {
+		log.Print("Initializing Funder account")
+		err = ts.InitAccount(config.Accounts.FunderSeed)
+		if err != nil {
+			return
+		}
+	}
+
+	if config.TxStatusEvents.Transport != "" {
+		ts.StatusEvents, err = queue.NewPublisher(
+			config.TxStatusEvents.Transport,
+			config.TxStatusEvents.SQS,
+			config.TxStatusEvents.PubSub,
+			&http.Client{Timeout: 10 * time.Second},
+		)
+		if err != nil {
+			return
+		}
+		ts.StatusEventAttributes = config.TxStatusEvents.Attributes
+		ts.StatusEventsFormat = config.EventFormatter(config.TxStatusEvents.Format, config.TxStatusEvents.SchemaVersion)
+	}
+
+	ts.Region = config.Region.Name
+	ts.Audit = auditEmitter
+	ts.Incidents = incidents
+	ts.Aggregates = aggregator
+	ts.Rates = recorder
+	ts.Webhooks = dispatcher
+
+	var redisClient *redis.Client
+	if config.Redis.Enabled() {
+		redisClient, err = redis.NewClient(config.Redis.URL, config.Redis.PoolSizeOrDefault(), config.Redis.TimeoutDuration())
+		if err != nil {
+			return
+		}
+		log.Print("Sharing idempotency keys, sequence numbers and rate limits in Redis")
+		ts.Sequences = redis.NewSequences(redisClient, config.Redis.KeyPrefixOrDefault())
+	}
+
+	// Statuses of sent transactions are streamed to gRPC clients, they can be
+	// queried only when there is a database
+	var statusHub *grpcserver.StatusHub
+	if config.GRPC.Enabled && repository != nil {
+		statusHub = grpcserver.NewStatusHub()
+		ts.StatusWatcher = statusHub.Watcher("")
+	}
+
+	if congestionMonitor != nil {
+		ts.Fees = congestionMonitor
+	}
+
+	ts.TimeBounds = config.Submission.TimeBoundsDuration()
+	if driftMonitor != nil {
+		ts.Drift = driftMonitor
+	} else if ts.TimeBounds > 0 {
+		log.Warning("Transactions have time bounds but clock.check_interval param is not set, clock drift will not be detected")
+	}
+
+	if config.Signer.URL != "" {
+		log.Print("Transactions of accounts configured by public key will be signed by ", config.Signer.URL)
+		signerClient := &http.Client{Timeout: 10 * time.Second}
+		signerTLS := config.Signer.TLSPolicy(config.OutboundTLS)
+		if signerTLS.Enabled() || config.Signer.CAFile != "" {
+			var rootCAs *x509.CertPool
+			if config.Signer.CAFile != "" {
+				rootCAs, err = external.LoadCertPool(config.Signer.CAFile)
+				if err != nil {
+					err = fmt.Errorf("Cannot load signer.ca_file: %s", err)
+					return
+				}
+			}
+			signerClient.Transport = signerTLS.Transport(rootCAs)
+		}
+		ts.Signer = submitter.NewRemoteSigner(
+			config.Signer.URL,
+			config.Signer.Secret,
+			config.NetworkPassphrase,
+			signerClient,
+		)
+	}
+
+	var keys *signers.Keyring
+	if len(config.Signers) > 0 {
+		keys, err = signers.Load(config.Signers, &http.Client{Timeout: 10 * time.Second})
+		if err != nil {
+			return
+		}
+		for _, account := range keys.Accounts() {
+			log.Print("Transactions of ", account, " will be signed by ", keys.BackendName(account), " signer")
+		}
+		// Signing backends take precedence over the signing service
+		ts.Signer = &submitter.KeyringSigner{Keys: keys, Next: ts.Signer}
+	}
+
 	log.Print("TransactionSubmitter created")
 
+	if config.ChainReconciliation.Enabled() {
+		log.Print("Comparing sent transactions with Horizon every ", config.ChainReconciliation.Interval)
+		chainReconciler := reconciliation.NewChainReconciler(
+			repository,
+			entityManager,
+			&h,
+			config.ChainReconciliation.LookbackDuration(),
+			config.ChainReconciliation.MinAgeDuration(),
+		)
+		if ts.StatusEvents != nil || ts.StatusWatcher != nil {
+			chainReconciler.StatusEvents = &ts
+		}
+		chainReconciler.Audit = auditEmitter
+
+		interval := config.ChainReconciliation.IntervalDuration()
+		go func() {
+			for range time.Tick(interval) {
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				chainReconciler.Run(ctx)
+				cancel()
+			}
+		}()
+	}
+
 	log.Print("Creating and starting PaymentListener")
 
+	// Received payments are streamed to subscribers of /stream/payments
+	// next to receive callbacks
+	var streamPublisher *stream.Publisher
+	var streamHandler *stream.Handler
+	if config.Stream.Enabled {
+		streamPublisher = stream.NewPublisher(entityManager, clock.Now)
+		streamHandler = stream.NewHandler(
+			streamPublisher,
+			repository,
+			config.Stream.MaxSubscribersOrDefault(),
+			config.Stream.PollIntervalDuration(),
+			config.Stream.HeartbeatDuration(),
+		)
+	}
+
+	var elector *leader.Elector
+	if config.LeaderElection.Enabled() {
+		elector, err = newElector(config, repository)
+		if err != nil {
+			return
+		}
+	}
+
+	live := config.NewLive()
 	var paymentListener listener.PaymentListener
+	listening := false
 
 	if config.Accounts.ReceivingAccountID == "" {
 		log.Warning("No accounts.receiving_account_id param. Skipping...")
-	} else if config.Callbacks.Receive == "" {
+	} else if len(config.Callbacks.Receive) == 0 && (config.Callbacks.Transport == "" || config.Callbacks.Transport == "http") && !config.Stream.Enabled && !config.Webhooks.Enabled {
 		log.Warning("No callbacks.receive param. Skipping...")
 	} else {
-		paymentListener, err = listener.NewPaymentListener(&config, entityManager, &h, repository, time.Now)
+		paymentListener, err = listener.NewPaymentListener(&config, entityManager, &h, repository, clock.Now)
+		if err != nil {
+			return
+		}
+		paymentListener.Aggregates = aggregator
+		paymentListener.Rates = recorder
+		paymentListener.Expected = matcher
+		paymentListener.Live = live
+		paymentListener.Events = streamPublisher
+		paymentListener.Webhooks = dispatcher
+		paymentListener.References = referenceStore
+		paymentListener.SEP24 = sep24Tracker
+		paymentListener.DeadLetters = listener.NewDeadLetters(repository, entityManager, clock.Now)
+		if elector != nil {
+			paymentListener.Leadership = elector
+		}
+
+		var backend listener.ListenerBackend
+		backend, err = newListenerBackend(config)
 		if err != nil {
 			return
 		}
+		if backend != nil {
+			log.Print("Payments will be read from " + config.Listener.Backend)
+			paymentListener.Backend = backend
+		}
+
 		err = paymentListener.Listen()
 		if err != nil {
 			return
 		}
 
+		// Payments returned to the base account are streamed separately, a
+		// separate backend keeps the received payments stream tracker intact
+		if config.MemoRequirement != "" && config.Accounts.BaseSeed != "" {
+			baseAccountID := keypair.MustParse(config.Accounts.BaseSeed).Address()
+			if baseAccountID != config.Accounts.ReceivingAccountID {
+				var returns listener.ListenerBackend
+				returns, err = newListenerBackend(config)
+				if err != nil {
+					return
+				}
+				if returns == nil {
+					returnsHorizon := horizon.NewWithOptions(config.Horizon, horizonOptions)
+					returns = &returnsHorizon
+				}
+				paymentListener.StreamReturnedPayments(returns, baseAccountID)
+			}
+		}
+
+		// Horizon does not stream transfers to contracts, they are read from
+		// contract events
+		if config.Listener.SorobanRPCURL != "" {
+			log.Print("Contract transfers will be read from soroban-rpc")
+			paymentListener.StreamContractTransfers(sorobanrpc.NewTransferStream(
+				config.Listener.SorobanRPCURL,
+				config.NetworkPassphrase,
+				config.Horizon[0],
+				config.Listener.PollIntervalDuration(),
+			))
+		}
+
+		if tracker, ok := paymentListener.Backend.(listener.StreamTracker); ok {
+			incidents.WatchStream(tracker.StreamUpdatedAt, config.Listener.StaleAfterDuration(), incidentWatchInterval)
+		}
+
+		listening = true
 		log.Print("PaymentListener created")
 	}
 
@@ -148,63 +724,723 @@ func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version st
 		return
 	}
 
-	requestHandler := handlers.RequestHandler{}
-
-	httpClientWithTimeout := http.Client{
+	httpClientWithTimeout := config.OutboundTLS.Client(http.Client{
 		Timeout: 10 * time.Second,
-	}
+	})
 
-	stellartomlClient := stellartoml.Client{
-		HTTP: &httpClientWithTimeout,
+	stellarTomlOptions := external.StellarTomlOptions{
+		RequireHTTPS: config.StellarToml.RequireHTTPS,
+		MaxSize:      int64(config.StellarToml.MaxSize),
+		MaxRedirects: config.StellarToml.MaxRedirects,
+		Timeout:      httpClientWithTimeout.Timeout,
+		TLSPolicy:    config.OutboundTLS,
 	}
+	if config.StellarToml.CAFile != "" {
+		stellarTomlOptions.RootCAs, err = external.LoadCertPool(config.StellarToml.CAFile)
+		if err != nil {
+			err = fmt.Errorf("Cannot load stellar_toml.ca_file: %s", err)
+			return
+		}
+	}
+	stellartomlClient := external.NewStellarTomlClient(stellarTomlOptions)
 
 	federationClient := federation.Client{
-		HTTP:        &httpClientWithTimeout,
-		StellarTOML: &stellartomlClient,
-	}
-
-	err = g.Provide(
-		&inject.Object{Value: &requestHandler},
-		&inject.Object{Value: &config},
-		&inject.Object{Value: &stellartomlClient},
-		&inject.Object{Value: &federationClient},
-		&inject.Object{Value: &h},
-		&inject.Object{Value: &repository},
-		&inject.Object{Value: driver},
-		&inject.Object{Value: &ts},
-		&inject.Object{Value: &paymentListener},
-		&inject.Object{Value: &httpClientWithTimeout},
+		HTTP:        httpClientWithTimeout,
+		StellarTOML: stellartomlClient,
+	}
+
+	var snapshotRecorder *snapshots.Recorder
+	if config.Snapshots.Enabled() {
+		snapshotRecorder = snapshots.NewRecorder(config.Snapshots.MaxEntries)
+		stellartomlClient.HTTP = snapshotRecorder.Client(snapshots.KindStellarToml, stellartomlClient.HTTP)
+		federationClient.HTTP = snapshotRecorder.Client(snapshots.KindFederation, httpClientWithTimeout)
+	}
+
+	var stellarTomlResolver external.StellarTomlClientInterface = stellartomlClient
+	var federationResolver federation.ClientInterface = &federationClient
+
+	if config.Cache.Enabled() {
+		cachedStellarToml := cache.NewStellarTomlResolver(stellartomlClient, cache.NewLRU(config.Cache.SizeOrDefault(), config.Cache.TTLDuration(), config.Cache.NegativeTTLDuration()))
+		federationClient.StellarTOML = cachedStellarToml
+		stellarTomlResolver = cachedStellarToml
+		federationResolver = cache.NewFederationResolver(&federationClient, cache.NewLRU(config.Cache.SizeOrDefault(), config.Cache.TTLDuration(), config.Cache.NegativeTTLDuration()))
+	}
+
+	var transactionSubmitter submitter.TransactionSubmitterInterface = &ts
+	if config.Submission.Concurrency > 0 {
+		for _, seed := range config.Submission.ChannelSeeds {
+			err = ts.InitAccount(seed)
+			if err != nil {
+				return
+			}
+		}
+
+		log.WithFields(log.Fields{
+			"concurrency": config.Submission.Concurrency,
+			"channels":    len(config.Submission.ChannelSeeds),
+		}).Print("Starting submission workers")
+		pool := submitter.NewPool(&ts, config.Submission.Concurrency, config.Submission.QueueDepthOrDefault(), config.Submission.ChannelSeeds)
+		pool.Start()
+		transactionSubmitter = pool
+	}
+
+	if elector != nil {
+		transactionSubmitter = &submitter.LeaderOnly{Submitter: transactionSubmitter, Leadership: elector}
+	}
+
+	requestHandler := handlers.NewRequestHandler(
+		&config,
+		httpClientWithTimeout,
+		submissionHorizon,
+		driver,
+		repository,
+		entityManager,
+		stellarTomlResolver,
+		federationResolver,
+		transactionSubmitter,
+		&paymentListener,
 	)
 
+	requestHandler.Networks, err = newNetworks(config, networkDrivers, repository, entityManager, keys, statusHub)
 	if err != nil {
-		log.Fatal("Injector: ", err)
+		return
+	}
+
+	requestHandler.Features, err = features.New(config.Features, repository)
+	if err != nil {
+		return
+	}
+	requestHandler.Audit = auditEmitter
+	requestHandler.Snapshots = snapshotRecorder
+	requestHandler.Live = live
+	requestHandler.Expected = matcher
+	requestHandler.Fees = config.Fees.Schedule()
+	requestHandler.Webhooks = dispatcher
+	requestHandler.References = referenceStore
+	requestHandler.SEP24 = sep24Tracker
+	requestHandler.Leader = elector
+	if redisClient != nil {
+		requestHandler.Idempotency = redis.NewIdempotency(redisClient, config.Redis.KeyPrefixOrDefault(), config.Redis.IdempotencyTTLDuration(), clock.Now)
+	}
+
+	if config.SorobanRPCURL != "" {
+		requestHandler.SorobanRPC = sorobanrpc.New(config.SorobanRPCURL, httpClientWithTimeout)
+	}
+
+	if config.BalanceMonitor.Enabled() {
+		requestHandler.Balances = newBalanceMonitor(config, backgroundHorizon, dispatcher)
+	}
+
+	if config.Cache.AccountTTL != "" {
+		requestHandler.DestinationAccounts = cache.NewAccountResolver(submissionHorizon, cache.NewLRU(config.Cache.SizeOrDefault(), config.Cache.AccountTTLDuration(), 0))
+	}
+
+	requestHandler.Profiles, err = profiles.Load(config.Profiles.Directory)
+	if err != nil {
+		return
+	}
+
+	if config.Risk.Enabled() {
+		requestHandler.Risk, requestHandler.Velocity, err = newRiskChain(config, repository)
+		if err != nil {
+			return
+		}
 	}
 
-	if err := g.Populate(); err != nil {
-		log.Fatal("Injector: ", err)
+	if driver != nil {
+		requestHandler.Exporter, err = newExporter(config, backgroundHorizon, httpClientWithTimeout, repository)
+		if err != nil {
+			return
+		}
 	}
 
 	app = &App{
 		config:         config,
 		requestHandler: requestHandler,
+		health:         newHealthChecker(config, driver, redisClient, &paymentListener, httpClientWithTimeout),
+		statuses:       statusHub,
+		stream:         streamHandler,
+		version:        version,
+		live:           live,
+		listening:      listening,
+		networkDrivers: networkDrivers,
+		redisClient:    redisClient,
+	}
+
+	if config.Federation.Enabled() {
+		log.Print("Starting federation server for ", config.Federation.Domain)
+		var federationDB *sql.DB
+		var dialect string
+		if driver != nil {
+			federationDB = driver.DB().DB
+			dialect = driver.DB().DriverName()
+		}
+		app.federationHandler = federationserver.NewHandler(config.Federation, federationDB, dialect)
+		go federationserver.CheckStellarToml(stellarTomlResolver, config.Federation.Domain)
 	}
 	return
 }
 
-// Serve starts the server
+// newHealthChecker creates a Checker of dependencies used by /readyz:
+// database, Redis, Horizon, payments stream and callbacks.receive endpoints
+func newHealthChecker(config config.Config, driver db.Driver, redisClient *redis.Client, paymentListener *listener.PaymentListener, client *http.Client) *health.Checker {
+	checker := &health.Checker{}
+	if driver != nil {
+		checker.Add("database", health.Database(driver.DB().DB))
+	}
+	if redisClient != nil {
+		checker.Add("redis", redisClient.Ping)
+	}
+	checker.Add("horizon", health.Reachable(http.DefaultClient, config.Horizon...))
+
+	// Backend is nil when the listener has not been started
+	if tracker, ok := paymentListener.Backend.(listener.StreamTracker); ok {
+		checker.Add("listener", health.Fresh(tracker.StreamUpdatedAt, config.Listener.StaleAfterDuration()))
+
+		if config.Callbacks.Transport == "" || config.Callbacks.Transport == listener.CallbackTransportHTTP {
+			checker.Add("callbacks.receive", health.Reachable(client, config.Callbacks.Receive...))
+		}
+	}
+	return checker
+}
+
+// newListenerBackend creates the backend of `listener.backend` config param,
+// it returns nil for Horizon
+func newListenerBackend(config config.Config) (listener.ListenerBackend, error) {
+	switch config.Listener.Backend {
+	case "stellar-core-database":
+		stream, err := stellarcore.NewDatabaseStream(
+			config.Listener.DatabaseURL,
+			config.Listener.PollIntervalDuration(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot connect to stellar-core DB: %s", err)
+		}
+		return stream, nil
+	case "history-archive":
+		return stellarcore.NewArchiveStream(
+			config.Listener.HistoryArchiveURL,
+			&http.Client{Timeout: time.Minute},
+			config.NetworkPassphrase,
+			config.Listener.PollIntervalDuration(),
+		), nil
+	}
+	return nil, nil
+}
+
+// newIncidentRecorder creates a Recorder storing diagnostic bundles in the
+// directory and/or S3 bucket of `incidents` config group. Recent log entries
+// are buffered from now on.
+func newIncidentRecorder(config config.Config, repository db.RepositoryInterface, client *http.Client) (*incident.Recorder, error) {
+	var targets []export.Target
+	if config.Incidents.Directory != "" {
+		targets = append(targets, export.DirectoryTarget{Path: config.Incidents.Directory})
+	}
+	if config.Incidents.S3.Bucket != "" {
+		target, err := export.NewS3Target(config.Incidents.S3.Bucket, config.Incidents.S3.Region, config.Incidents.S3.Prefix, client)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	logs := incident.NewLogBuffer(config.Incidents.LogLinesOrDefault())
+	log.AddHook(logs)
+
+	recorder := incident.NewRecorder(
+		targets,
+		config.Incidents.SubmissionFailuresOrDefault(),
+		config.Incidents.CooldownDuration(),
+		logs,
+		config,
+	)
+
+	if repository != nil {
+		recorder.AddSource("sending_transactions", func(ctx context.Context) (interface{}, error) {
+			filter := db.SentTransactionsFilter{Status: string(entities.SentTransactionStatusSending)}
+			return repository.GetSentTransactions(ctx, filter, 1, incidentSnapshotSize)
+		})
+		recorder.AddSource("sent_transactions", func(ctx context.Context) (interface{}, error) {
+			return repository.GetSentTransactions(ctx, db.SentTransactionsFilter{}, 1, incidentSnapshotSize)
+		})
+		recorder.AddSource("received_payments", func(ctx context.Context) (interface{}, error) {
+			return repository.GetReceivedPayments(ctx, db.ReceivedPaymentsFilter{}, 1, incidentSnapshotSize)
+		})
+		recorder.AddSource("held_payments", func(ctx context.Context) (interface{}, error) {
+			return repository.GetHeldPaymentsToRelease(ctx, clock.Now())
+		})
+		recorder.AddSource("security_events", func(ctx context.Context) (interface{}, error) {
+			return repository.GetSecurityEventsToDeliver(ctx, clock.Now(), incidentSnapshotSize)
+		})
+	}
+
+	log.Print("Capturing diagnostic bundles of incidents")
+	return recorder, nil
+}
+
+// newRiskChain creates a chain of risk hooks: the velocity checker (when
+// velocity rules are set) followed by HTTP and plugin hooks in config order
+func newRiskChain(config config.Config, repository db.RepositoryInterface) (*risk.Chain, *risk.VelocityChecker, error) {
+	chain := risk.NewChain(config.Risk.FailOpen)
+
+	var velocity *risk.VelocityChecker
+	if len(config.Risk.Velocity) > 0 {
+		velocity = risk.NewVelocityChecker(config.Risk.VelocityRules(), repository)
+		chain.Add("velocity", velocity)
+	}
+
+	client := config.OutboundTLS.Client(http.Client{Timeout: config.Risk.TimeoutDuration()})
+	for _, hookConfig := range config.Risk.Hooks {
+		var hook risk.Hook
+		if hookConfig.Plugin != "" {
+			var err error
+			hook, err = risk.LoadPlugin(hookConfig.Plugin)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Cannot load risk hook plugin %s: %s", hookConfig.Plugin, err)
+			}
+		} else {
+			hook = risk.NewHTTPHook(hookConfig.URL, hookConfig.Secret, client)
+		}
+		chain.Add(hookConfig.NameOrDefault(), hook)
+	}
+
+	log.Print("Payments will be checked by ", chain.Len(), " risk hooks")
+	return chain, velocity, nil
+}
+
+// newRatesRecorder creates a Recorder of fiat values using the configured
+// rates provider
+func newRatesRecorder(config config.Config, repository db.RepositoryInterface, entityManager db.EntityManagerInterface) *rates.Recorder {
+	var provider rates.Provider
+	if config.Rates.Provider == "http" {
+		client := config.OutboundTLS.Client(http.Client{Timeout: config.Rates.TimeoutDuration()})
+		provider = rates.NewHTTPProvider(config.Rates.URL, client, config.Rates.CacheTTLOrDefault())
+	} else {
+		fixed := make(rates.Fixed, len(config.Rates.Fixed))
+		for i, rate := range config.Rates.Fixed {
+			fixed[i] = rates.FixedRate{
+				AssetCode:   rate.AssetCode,
+				AssetIssuer: rate.AssetIssuer,
+				Currency:    rate.Currency,
+				Rate:        rate.Rate,
+			}
+		}
+		provider = fixed
+	}
+
+	log.Print("Fiat values of payments will be recorded in ", config.Rates.BaseCurrency)
+	return rates.NewRecorder(provider, repository, entityManager, config.Rates.BaseCurrency, config.Rates.TenantCurrencies())
+}
+
+// newBalanceMonitor creates a Monitor of balances sending alerts to webhooks
+// (when enabled) and by email (when configured)
+func newBalanceMonitor(config config.Config, h horizon.HorizonInterface, dispatcher *webhooks.Dispatcher) *balances.Monitor {
+	monitor := balances.NewMonitor(h, config.BalanceMonitor.Thresholds(), config.BalanceMonitor.BaseReserveStroops(), clock.Now)
+	if dispatcher != nil {
+		monitor.Webhooks = dispatcher
+	}
+
+	email := config.BalanceMonitor.Email
+	if email.SMTPAddress != "" {
+		monitor.Notifier = balances.NewEmailNotifier(email.SMTPAddress, email.Username, email.Password, email.From, email.To)
+	}
+
+	log.Print("Monitoring ", len(monitor.Thresholds), " balances every ", config.BalanceMonitor.IntervalDuration())
+	return monitor
+}
+
+// newElector creates an Elector of this replica using the backend of
+// leader_election config group
+func newElector(config config.Config, repository db.RepositoryInterface) (*leader.Elector, error) {
+	election := config.LeaderElection
+	id := election.ID
+	if id == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("Cannot get hostname, set leader_election.id param: %s", err)
+		}
+		id = hostname
+	}
+
+	var backend leader.Backend
+	switch election.Backend {
+	case "database":
+		backend = leader.NewDatabase(election.NameOrDefault(), repository)
+	case "etcd":
+		client := config.OutboundTLS.Client(http.Client{Timeout: election.RenewIntervalDuration()})
+		backend = leader.NewEtcd(client, election.EtcdEndpoints, election.NameOrDefault())
+	}
+
+	log.WithFields(log.Fields{
+		"backend":        election.Backend,
+		"id":             id,
+		"lease_duration": election.LeaseDurationDuration().String(),
+	}).Print("Payment listener and submissions will run in the elected leader")
+	return leader.NewElector(backend, id, election.LeaseDurationDuration(), election.RenewIntervalDuration(), clock.Now), nil
+}
+
+// newExporter creates an Exporter of statements of the receiving account (or
+// base account when not set) and starts daily export when it is configured
+func newExporter(config config.Config, h horizon.HorizonInterface, client *http.Client, repository db.RepositoryInterface) (*export.Exporter, error) {
+	account := config.Accounts.ReceivingAccountID
+	if account == "" && config.Accounts.BaseSeed != "" {
+		account = keypair.MustParse(config.Accounts.BaseSeed).Address()
+	}
+
+	var targets []export.Target
+	if config.Export.Directory != "" {
+		targets = append(targets, export.DirectoryTarget{Path: config.Export.Directory})
+	}
+	if config.Export.S3.Bucket != "" {
+		target, err := export.NewS3Target(config.Export.S3.Bucket, config.Export.S3.Region, config.Export.S3.Prefix, client)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	exporter := export.NewExporter(account, config.Export.FormatsOrDefault(), targets, repository, h, clock.Now)
+	if config.Export.Enabled() {
+		log.Print("Exporting daily statements in ", strings.Join(exporter.Formats, ", "), " formats")
+		exporter.Start()
+	}
+	return exporter, nil
+}
+
+// NewAppWithRequestHandler constructs an App serving the given RequestHandler.
+// Programs embedding the bridge server can build the RequestHandler using
+// handlers.NewRequestHandler with their own implementations of its dependencies.
+func NewAppWithRequestHandler(config config.Config, requestHandler handlers.RequestHandler) *App {
+	return &App{
+		config:         config,
+		requestHandler: requestHandler,
+	}
+}
+
+// Serve starts the server. Operational endpoints are served by a separate
+// listener when admin.address is set.
 func (a *App) Serve() {
 	portString := fmt.Sprintf(":%d", *a.config.Port)
 	flag.Set("bind", portString)
 
-	bridge := web.New()
-
-	bridge.Abandon(middleware.Logger)
-	bridge.Use(server.StripTrailingSlashMiddleware())
-	bridge.Use(server.HeadersMiddleware())
+	bridge := a.newMux()
+	if a.config.Access.IPFilterEnabled() {
+		bridge.Use(a.newIPFilter().Middleware)
+	}
+	var authenticator *auth.Authenticator
+	if a.config.Auth.Enabled() {
+		authenticator = a.newAuthenticator()
+		// Must be used before APIKeyMiddleware which parses request bodies
+		bridge.Use(authenticator.Middleware)
+	}
 	if a.config.APIKey != "" {
-		bridge.Use(server.APIKeyMiddleware(a.config.APIKey))
+		bridge.Use(server.APIKeyMiddleware(a.config.APIKey, func(r *http.Request) {
+			event := audit.NewRequestEvent(audit.AuthFailure, r)
+			event.Message = "Invalid API key"
+			a.requestHandler.Audit.Emit(event)
+		}))
+	}
+	// Must be used after Authenticator which adds API keys to contexts.
+	// Always used, so rate limits can be added by reloading the config.
+	a.rateLimiter = a.newRateLimiter()
+	bridge.Use(a.rateLimiter.Middleware)
+	if a.requestHandler.Audit != nil {
+		bridge.Use(a.requestHandler.Audit.AdminMiddleware())
+	}
+
+	admin := bridge
+	if a.config.Admin.Enabled() {
+		admin = a.newMux()
+		admin.Use(server.BasicAuthMiddleware(a.config.Admin.Username, a.config.Admin.Password, func(r *http.Request) {
+			event := audit.NewRequestEvent(audit.AuthFailure, r)
+			event.Message = "Invalid admin credentials"
+			a.requestHandler.Audit.Emit(event)
+		}))
+		if authenticator != nil && a.config.Approval.Enabled {
+			// Approvals are authenticated with API keys
+			admin.Use(authenticator.Middleware)
+		}
+		if a.requestHandler.Audit != nil {
+			admin.Use(a.requestHandler.Audit.AdminMiddleware())
+		}
+	}
+
+	if a.LoadConfig != nil {
+		a.requestHandler.Reload = a.Reload
+		go a.reloadOnSignal()
+	}
+
+	a.addRoutes(bridge)
+	a.addAdminRoutes(admin)
+
+	if a.config.HoldsPayments() {
+		if a.config.Settlement.HandoffFile != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), settlementInterval)
+			ingested, err := a.requestHandler.IngestHandoff(ctx)
+			cancel()
+			if err != nil {
+				log.WithFields(log.Fields{"err": err, "file": a.config.Settlement.HandoffFile}).Error("Error ingesting handoff file")
+			} else if ingested > 0 {
+				log.WithFields(log.Fields{"ingested": ingested}).Info("Held payments of the previous server ingested")
+			}
+		}
+
+		// Held payments with settlement window ended are released after
+		// the server stops accepting requests
+		graceful.PostHook(a.requestHandler.DrainHeldPayments)
+
+		go func() {
+			for range time.Tick(settlementInterval) {
+				ctx, cancel := context.WithTimeout(context.Background(), settlementInterval)
+				a.requestHandler.ReleaseHeldPayments(ctx)
+				cancel()
+			}
+		}()
+	}
+
+	// On SIGTERM the server stops accepting requests, waits for requests in
+	// progress (closing connections after shutdown.drain_timeout) and then
+	// drains work in progress
+	graceful.AddSignal(os.Interrupt, syscall.SIGTERM)
+	graceful.Timeout(a.config.Shutdown.DrainTimeoutDuration())
+	graceful.PreHook(a.startDrain)
+	graceful.PostHook(a.shutdown)
+
+	if a.requestHandler.Leader != nil {
+		go a.requestHandler.Leader.Run(context.Background())
+	}
+
+	if a.requestHandler.Velocity != nil {
+		go func() {
+			for range time.Tick(velocityPruneInterval) {
+				ctx, cancel := context.WithTimeout(context.Background(), velocityPruneInterval)
+				a.requestHandler.PruneVelocity(ctx)
+				cancel()
+			}
+		}()
+	}
+
+	if a.requestHandler.Balances != nil {
+		// Top-ups are sent like /payment requests
+		a.requestHandler.Balances.TopUps = &a.requestHandler
+		interval := a.config.BalanceMonitor.IntervalDuration()
+		go func() {
+			// Balances are checked on start, so /admin/balances is available
+			// before the first interval ends (unless the leader has not been
+			// elected yet)
+			tick := time.Tick(interval)
+			for {
+				// Top-ups are submitted and alerts sent by the leader only
+				if a.requestHandler.Leader.IsLeader() {
+					ctx, cancel := context.WithTimeout(context.Background(), interval)
+					a.requestHandler.Balances.Run(ctx)
+					cancel()
+				}
+				<-tick
+			}
+		}()
+	}
+
+	if a.config.Region.Enabled() {
+		reconciler := reconciliation.NewReconciler(a.requestHandler.Repository)
+		go func() {
+			for range time.Tick(reconciliationInterval) {
+				ctx, cancel := context.WithTimeout(context.Background(), reconciliationInterval)
+				reconciler.Run(ctx)
+				cancel()
+			}
+		}()
+	}
+
+	if a.config.Admin.Enabled() {
+		log.Println("Starting admin server on", a.config.Admin.Address)
+		go func() {
+			err := a.config.HTTP.Admin.NewServer("bridge_admin", a.config.Admin.Address, admin).ListenAndServe()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	api := a.config.HTTP.API.NewServer("bridge_api", portString, bridge)
+	if a.config.GRPC.Enabled {
+		// gRPC requires HTTP/2, it's unencrypted (h2c) when TLS is disabled
+		api.Protocols = new(http.Protocols)
+		api.Protocols.SetHTTP1(true)
+		api.Protocols.SetHTTP2(true)
+		api.Protocols.SetUnencryptedHTTP2(true)
+	}
+	err := a.config.TLS.Serve(api)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Serve returns when the server is stopped by a signal, wait for
+	// shutdown hooks
+	graceful.Wait()
+}
+
+// Reload loads the config again using LoadConfig and applies params that can
+// be changed without a restart (see config.Reloadable). The payments listener
+// cursor and transactions being submitted are not affected. Returns names of
+// applied params and of changed params that require a restart.
+func (a *App) Reload() (reloaded, ignored []string, err error) {
+	a.reloadMutex.Lock()
+	defer a.reloadMutex.Unlock()
+
+	if a.LoadConfig == nil {
+		return nil, nil, errors.New("Config loader is not set")
+	}
+
+	next, err := a.LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := next.Reloadable()
+	httpCallbacks := a.config.Callbacks.Transport == "" || a.config.Callbacks.Transport == listener.CallbackTransportHTTP
+	if a.listening && httpCallbacks && len(params.Receive) == 0 {
+		return nil, nil, errors.New("callbacks.receive param cannot be removed while payments are received")
+	}
+
+	reloaded, ignored = a.config.Changes(next)
+	a.live.Set(params)
+	log.SetLevel(params.LogLevelOrDefault())
+	if a.rateLimiter != nil {
+		a.rateLimiter.SetRules(next.Access.Rules())
+	}
+	if a.listening {
+		a.requestHandler.PaymentListener.SetReceiveURLs(params.Receive)
+	}
+	a.config = a.config.WithReloadable(params)
+
+	log.WithFields(log.Fields{"reloaded": reloaded, "ignored": ignored}).Info("Config reloaded")
+	if len(ignored) > 0 {
+		log.WithFields(log.Fields{"ignored": ignored}).Warn("Changed params require a restart of the server")
+	}
+	return reloaded, ignored, nil
+}
+
+// reloadOnSignal reloads the config every time the process receives SIGHUP
+func (a *App) reloadOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		_, _, err := a.Reload()
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error reloading config, current config is still used")
+		}
+	}
+}
+
+// newAuthenticator creates an Authenticator of /payment, /builder,
+// /create-keypair and /create-account requests using keys from the config and APIKey table
+func (a *App) newAuthenticator() *auth.Authenticator {
+	keys := make([]auth.Key, 0, len(a.config.Auth.Keys))
+	for _, key := range a.config.Auth.Keys {
+		keys = append(keys, auth.Key{
+			ID:               key.ID,
+			Secret:           key.Secret,
+			RequireSignature: key.RequireSignature,
+			RateLimit:        key.RateLimit,
+			Sources:          key.Sources,
+			Tenant:           key.Tenant,
+		})
+	}
+
+	stores := []auth.Store{auth.NewStaticStore(keys)}
+	if a.config.Auth.Database {
+		stores = append(stores, auth.RepositoryStore{Repository: a.requestHandler.Repository})
+	}
+
+	paths := []string{"/payment", "/builder", "/create-keypair", "/create-account"}
+	for _, method := range grpcserver.Methods {
+		paths = append(paths, grpcserver.PathPrefix+method)
+	}
+	if a.config.Stream.Enabled {
+		paths = append(paths, "/stream/payments")
+	}
+	if a.config.Approval.Enabled {
+		paths = append(paths, "/admin/payments/*")
+	}
+	if a.config.ExpectedPayments.Enabled {
+		paths = append(paths, "/expected-payments", "/expected-payments/*")
+	}
+	if a.config.SEP24.Enabled {
+		paths = append(paths, "/sep24/transactions", "/sep24/transactions/*", "/sep24/transaction")
+	}
+	if a.config.MemoReferences.Enabled() {
+		paths = append(paths, "/memo-references")
+	}
+	if a.config.Database.Type != "" {
+		// Refunds send payments like /payment, offline transactions are
+		// built like /builder and quotes are used by /payment
+		paths = append(paths, "/payments/*", "/offline-transactions", "/offline-transactions/*", "/quote")
+	}
+
+	authenticator := auth.NewAuthenticator(stores, paths, a.config.Auth.MaxClockSkewDuration())
+	authenticator.OnFailure = func(r *http.Request, message string) {
+		event := audit.NewRequestEvent(audit.AuthFailure, r)
+		event.Message = message
+		a.requestHandler.Audit.Emit(event)
+	}
+	return authenticator
+}
+
+// newIPFilter creates an IPFilter of access.allow and access.deny networks
+func (a *App) newIPFilter() *access.IPFilter {
+	allow, deny, trustedProxies := a.config.Access.Networks()
+	filter := access.NewIPFilter(allow, deny, trustedProxies)
+	filter.OnDenied = func(r *http.Request, ip net.IP) {
+		event := audit.NewRequestEvent(audit.AuthFailure, r)
+		event.SourceIP = ip.String()
+		event.Message = "IP address not allowed"
+		a.requestHandler.Audit.Emit(event)
+	}
+	return filter
+}
+
+// newRateLimiter creates a RateLimiter of access.rate_limits
+func (a *App) newRateLimiter() *access.RateLimiter {
+	_, _, trustedProxies := a.config.Access.Networks()
+	limiter := access.NewRateLimiter(a.config.Access.Rules(), trustedProxies)
+	limiter.APIKey = func(r *http.Request) string {
+		if key := auth.FromContext(r.Context()); key != nil {
+			return key.ID
+		}
+		return ""
+	}
+	if a.redisClient != nil {
+		limiter.Shared = redis.NewLimiter(a.redisClient, a.config.Redis.KeyPrefixOrDefault())
+		a.health.Add("redis.rate_limits", limiter.Check)
 	}
+	return limiter
+}
+
+// newMux creates a mux with middlewares shared by the API and admin
+// listeners and health endpoints
+func (a *App) newMux() *web.Mux {
+	mux := web.New()
 
+	mux.Abandon(middleware.Logger)
+	// Must be used first, so responses of all middlewares are wrapped
+	mux.Use(server.EnvelopeMiddleware(func(r *http.Request) bool {
+		return a.requestHandler.Features.EnabledForRequest(features.ResponseEnvelope, r)
+	}))
+	mux.Use(server.StripTrailingSlashMiddleware())
+	mux.Use(server.HeadersMiddleware())
+	mux.Use(server.BodyLimitMiddleware(protocols.MaxRequestBodySize))
+	mux.Use(server.JSONFormMiddleware())
+	if a.config.Tracing.Enabled() {
+		mux.Use(tracing.Middleware)
+	}
+
+	mux.Get("/healthz", a.health.Healthz)
+	mux.Get("/readyz", a.health.Readyz)
+	return mux
+}
+
+// addRoutes adds endpoints of the payment API
+func (a *App) addRoutes(bridge *web.Mux) {
 	if a.config.Accounts.AuthorizingSeed != "" {
 		bridge.Post("/authorize", a.requestHandler.Authorize)
 	} else {
@@ -212,15 +1448,131 @@ func (a *App) Serve() {
 	}
 
 	bridge.Post("/create-keypair", a.requestHandler.CreateKeypair)
+	if a.config.Accounts.FunderSeed != "" {
+		bridge.Post("/create-account", a.requestHandler.CreateAccount)
+	}
 	bridge.Post("/builder", a.requestHandler.Builder)
 	bridge.Post("/payment", a.requestHandler.Payment)
 	bridge.Get("/payment", a.requestHandler.Payment)
-	bridge.Post("/reprocess", a.requestHandler.Reprocess)
+	bridge.Get("/federation/resolve", a.requestHandler.FederationResolve)
+	bridge.Get("/openapi.json", openapi.Handler(a.openAPIInfo(), a.openAPIRoutes()))
+
+	if a.config.HoldsPayments() {
+		bridge.Delete("/payments/:id", a.requestHandler.CancelPayment)
+	}
 
-	bridge.Get("/admin/received-payments", a.requestHandler.AdminReceivedPayments)
-	bridge.Get("/admin/received-payments/:id", a.requestHandler.AdminReceivedPayment)
-	bridge.Get("/admin/received-payments/memo/:memo_id", a.requestHandler.AdminReceivedPaymentMemo)
-	bridge.Get("/admin/sent-transactions", a.requestHandler.AdminSentTransactions)
+	if a.config.Database.Type != "" {
+		bridge.Post("/payments/:id/refund", a.requestHandler.RefundPayment)
+		bridge.Get("/payments/:id/refund", a.requestHandler.Refund)
+		bridge.Post("/offline-transactions", a.requestHandler.CreateOfflineTransaction)
+		bridge.Get("/offline-transactions/:id", a.requestHandler.OfflineTransaction)
+		bridge.Post("/offline-transactions/:id/submit", a.requestHandler.SubmitOfflineTransaction)
+		bridge.Post("/quote", a.requestHandler.Quote)
+	}
+
+	if a.config.ExpectedPayments.Enabled {
+		bridge.Post("/expected-payments", a.requestHandler.CreateExpectedPayment)
+		bridge.Get("/expected-payments/:id", a.requestHandler.ExpectedPayment)
+		bridge.Delete("/expected-payments/:id", a.requestHandler.CancelExpectedPayment)
+	}
+
+	if a.config.SEP24.Enabled {
+		bridge.Post("/sep24/transactions", a.requestHandler.CreateSEP24Transaction)
+		bridge.Get("/sep24/transactions", a.requestHandler.SEP24Transactions)
+		bridge.Post("/sep24/transactions/:id", a.requestHandler.UpdateSEP24Transaction)
+		bridge.Get("/sep24/transaction", a.requestHandler.SEP24Transaction)
+	}
+
+	if a.config.MemoReferences.Enabled() {
+		bridge.Post("/memo-references", a.requestHandler.MemoReference)
+	}
+
+	if a.federationHandler != nil {
+		bridge.Get("/federation", a.federationHandler)
+	}
+
+	if a.stream != nil {
+		bridge.Get("/stream/payments", a.stream)
+		// Open streams would block the shutdown
+		graceful.PreHook(a.stream.Close)
+	}
+
+	if a.config.GRPC.Enabled {
+		grpc := grpcserver.NewServer(http.HandlerFunc(a.requestHandler.Payment), http.HandlerFunc(a.requestHandler.Builder))
+		if a.statuses != nil {
+			grpc.Transactions = &a.requestHandler
+			grpc.Statuses = a.statuses
+		}
+		bridge.Post(grpcserver.PathPrefix+"*", grpc)
+	}
+}
+
+// addAdminRoutes adds operational endpoints and admin GUI
+func (a *App) addAdminRoutes(admin *web.Mux) {
+	admin.Post("/reprocess", a.requestHandler.Reprocess)
+	admin.Get("/metrics", metrics.Handler)
+
+	admin.Get("/admin/received-payments", a.requestHandler.AdminReceivedPayments)
+	admin.Get("/admin/received-payments/:id", a.requestHandler.AdminReceivedPayment)
+	admin.Get("/admin/received-payments/memo/:memo_id", a.requestHandler.AdminReceivedPaymentMemo)
+	admin.Get("/admin/sent-transactions", a.requestHandler.AdminSentTransactions)
+	admin.Post("/admin/compliance-repair", a.requestHandler.ComplianceRepair)
+	admin.Post("/admin/cache/flush", a.requestHandler.AdminCacheFlush)
+	admin.Get("/admin/feature-flags", a.requestHandler.AdminFeatureFlags)
+	admin.Get("/admin/destination-profiles", a.requestHandler.AdminDestinationProfiles)
+	admin.Post("/admin/reload", a.requestHandler.AdminReload)
+
+	if a.requestHandler.Exporter != nil {
+		admin.Get("/admin/export", a.requestHandler.AdminExport)
+	}
+
+	if a.config.Database.Type != "" {
+		admin.Get("/admin/memo-required-destinations", a.requestHandler.AdminMemoRequiredDestinations)
+		admin.Post("/admin/memo-required-destinations", a.requestHandler.AdminAddMemoRequiredDestination)
+		admin.Delete("/admin/memo-required-destinations/:account_id", a.requestHandler.AdminRemoveMemoRequiredDestination)
+		admin.Get("/admin/destinations", a.requestHandler.AdminNamedDestinations)
+		admin.Post("/admin/destinations", a.requestHandler.AdminSetNamedDestination)
+		admin.Get("/admin/destinations/:name", a.requestHandler.AdminNamedDestination)
+		admin.Delete("/admin/destinations/:name", a.requestHandler.AdminRemoveNamedDestination)
+		admin.Post("/admin/feature-flags", a.requestHandler.AdminSetFeatureFlag)
+		admin.Get("/admin/reports/daily", a.requestHandler.AdminDailyReport)
+		admin.Get("/admin/reports/totals", a.requestHandler.AdminTotalsReport)
+		admin.Get("/admin/federation-snapshots", a.requestHandler.AdminFederationSnapshots)
+	}
+
+	if a.config.Webhooks.Enabled {
+		admin.Get("/admin/webhooks", a.requestHandler.AdminWebhooks)
+		admin.Post("/admin/webhooks", a.requestHandler.AdminCreateWebhook)
+		admin.Get("/admin/webhooks/:id", a.requestHandler.AdminWebhook)
+		admin.Post("/admin/webhooks/:id", a.requestHandler.AdminUpdateWebhook)
+		admin.Delete("/admin/webhooks/:id", a.requestHandler.AdminRemoveWebhook)
+	}
+
+	if a.config.Approval.Enabled {
+		admin.Post("/admin/payments/:id/approve", a.requestHandler.ApprovePayment)
+	}
+
+	if a.listening {
+		admin.Get("/admin/callbacks/failed", a.requestHandler.AdminFailedCallbacks)
+		admin.Get("/admin/callbacks/failed/:id", a.requestHandler.AdminFailedCallback)
+		admin.Post("/admin/callbacks/failed/:id/retry", a.requestHandler.AdminRetryFailedCallback)
+		admin.Post("/admin/callbacks/failed/:id/discard", a.requestHandler.AdminDiscardFailedCallback)
+	}
+
+	if a.config.ExpectedPayments.Enabled {
+		admin.Get("/admin/unmatched-payments", a.requestHandler.AdminUnmatchedPayments)
+		admin.Post("/admin/unmatched-payments/:operation_id/match", a.requestHandler.AdminMatchPayment)
+	}
+
+	if a.config.Region.Enabled() {
+		admin.Get("/admin/region-conflicts", a.requestHandler.AdminRegionConflicts)
+	}
+
+	if a.requestHandler.Balances != nil {
+		admin.Get("/admin/balances", a.requestHandler.AdminBalances)
+	}
+
+	admin.Get("/admin/leadership", a.requestHandler.AdminLeadership)
 
 	if a.config.Develop {
 		// Create a proxy server to localhost:3000 where GUI development server lives.
@@ -228,7 +1580,7 @@ func (a *App) Serve() {
 		if err != nil {
 			panic(err)
 		}
-		bridge.Get("/*", httputil.NewSingleHostReverseProxy(staticAdminURL))
+		admin.Get("/*", httputil.NewSingleHostReverseProxy(staticAdminURL))
 	} else {
 		// Load go-bindata files
 		fileServerHandler := http.FileServer(
@@ -237,14 +1589,9 @@ func (a *App) Serve() {
 				AssetDir:  gui.AssetDir,
 				AssetInfo: gui.AssetInfo,
 			})
-		bridge.Get("/admin", func(w http.ResponseWriter, r *http.Request) {
+		admin.Get("/admin", func(w http.ResponseWriter, r *http.Request) {
 			http.Redirect(w, r, "/admin/", http.StatusPermanentRedirect)
 		})
-		bridge.Get("/admin/*", http.StripPrefix("/admin/", fileServerHandler))
-	}
-
-	err := graceful.ListenAndServe(portString, bridge)
-	if err != nil {
-		log.Fatal(err)
+		admin.Get("/admin/*", http.StripPrefix("/admin/", fileServerHandler))
 	}
 }
@@ -1,172 +1,3772 @@
 package config
 
 import (
+	"crypto/tls"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/access"
+	"github.com/stellar/gateway/balances"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/cloudevents"
+	"github.com/stellar/gateway/congestion"
+	"github.com/stellar/gateway/crypto"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/fees"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/risk"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/signers"
+	"github.com/stellar/gateway/sorobanrpc"
+	"github.com/stellar/gateway/stage"
+	"github.com/stellar/gateway/tlspolicy"
 	"github.com/stellar/go/keypair"
+	"math/big"
+	"net"
 	"net/url"
 	"regexp"
+	"strings"
+	"time"
 )
 
+// notifyChannelRegexp matches unquoted Postgres identifiers, so clients can
+// use the channel in LISTEN statement
+var notifyChannelRegexp = regexp.MustCompile("^[a-z_][a-z0-9_]{0,62}$")
+
+var networkNameRegexp = regexp.MustCompile("^[a-zA-Z0-9_-]{1,32}$")
+
 // Config contains config params of the bridge server
 type Config struct {
 	Port              *int
-	Horizon           string
+	Horizon           []string      // primary server first, next ones are used when previous are unavailable
+	HorizonClient     HorizonClient `mapstructure:"horizon_client"`
 	Compliance        string
 	LogFormat         string `mapstructure:"log_format"`
-	MACKey            string `mapstructure:"mac_key"`
-	APIKey            string `mapstructure:"api_key"`
+	LogLevel          string `mapstructure:"log_level"` // debug, info (default), warn or error
+	MACKey            string `mapstructure:"mac_key" secret:""`
+	APIKey            string `mapstructure:"api_key" secret:""`
 	NetworkPassphrase string `mapstructure:"network_passphrase"`
 	Develop           bool
 	Assets            []Asset
 	Database          struct {
 		Type string
-		URL  string
+		URL  string `secret:""`
+		// NotifyChannel is a Postgres channel of NOTIFY events sent when a
+		// received payment or sent transaction is written
+		NotifyChannel string `mapstructure:"notify_channel"`
+		// Connection pool params, database/sql defaults are used when not set
+		MaxOpenConns    int    `mapstructure:"max_open_conns"`
+		MaxIdleConns    int    `mapstructure:"max_idle_conns"`
+		ConnMaxLifetime string `mapstructure:"conn_max_lifetime"`
+		// Secondary database receives copies of all writes during migration
+		// to another database
+		Secondary db.SecondaryConfig
 	}
 	Accounts
 	Callbacks
+	// RestrictAssets rejects payments of assets not listed in Assets
+	RestrictAssets bool           `mapstructure:"restrict_assets"`
+	TxStatusEvents TxStatusEvents `mapstructure:"tx_status_events"`
+	CloudEvents    CloudEvents    `mapstructure:"cloudevents"`
+	Settlement     Settlement
+	Region         Region
+	Submission     Submission
+	Listener       Listener
+	Federation     Federation
+	Signer         Signer
+	// Signers select HSM or KMS signing backends of accounts configured by
+	// public key
+	Signers     []signers.Config
+	Cache       Cache
+	StellarToml StellarToml `mapstructure:"stellar_toml"`
+	// Snapshots records federation and stellar.toml responses used by sent
+	// transactions
+	Snapshots Snapshots
+	// OutboundTLS is applied to connections to compliance server, federation
+	// servers, stellar.toml and callbacks
+	OutboundTLS tlspolicy.Config `mapstructure:"outbound_tls"`
+	// TLS is applied to the bridge API server, clients can be required to
+	// present a client certificate
+	TLS        tlspolicy.ServerConfig
+	Congestion Congestion
+	// Clock compares the local clock with ledger close times
+	Clock  Clock
+	Audit  Audit
+	Export Export
+	// ChainReconciliation compares statuses of sent transactions with Horizon
+	ChainReconciliation ChainReconciliation `mapstructure:"chain_reconciliation"`
+	Tracing             Tracing
+	// Admin moves operational endpoints to a separate listener
+	Admin Admin
+	// Auth authenticates clients of /payment, /builder, /create-keypair and
+	// /create-account with per-client API keys
+	Auth Auth
+	// HTTP contains timeouts and worker pools of the listeners
+	HTTP HTTP
+	// Timeouts limit stages of handling payments
+	Timeouts Timeouts
+	// Shutdown limits draining work in progress when the server is stopped
+	Shutdown Shutdown
+	// LeaderElection runs the payment listener and submission workers in a
+	// single replica when several bridge servers share a database
+	LeaderElection LeaderElection `mapstructure:"leader_election"`
+	// Redis shares idempotency keys, sequence numbers and rate limits of
+	// bridge servers
+	Redis Redis
+	// Access restricts IP addresses of clients and limits requests to
+	// endpoints
+	Access Access
+	// Incidents captures diagnostic bundles of submission failures and
+	// listener stalls
+	Incidents Incidents
+	// Profiles checks payments against destination profiles of exchanges
+	Profiles Profiles
+	Features []Feature
+	// MemoRequirement controls payments without memo sent to destinations
+	// known to require one: "warn" logs a warning, "require" rejects the
+	// payment. Disabled when empty.
+	MemoRequirement string `mapstructure:"memo_requirement"`
+	// AllowSelfPayments allows payments sent to the source account, some
+	// issuers use them intentionally. Rejected with self_payment error when
+	// false.
+	AllowSelfPayments bool `mapstructure:"allow_self_payments"`
+	// Networks are additional Stellar networks (ex. testnet next to pubnet)
+	// selected with `network` param of /payment and /builder
+	Networks []Network
+	// SorobanRPCURL of soroban-rpc server simulating invoke_host_function
+	// operations of /builder on the main network
+	SorobanRPCURL string `mapstructure:"soroban_rpc_url"`
+	// GRPC serves the gRPC API (proto/bridge.proto) on the API listener
+	GRPC GRPC `mapstructure:"grpc"`
+	// Stream serves received payments to subscribers of /stream/payments
+	Stream Stream
+	// CreateAccount contains starting balances of accounts created using
+	// /create-account
+	CreateAccount CreateAccount `mapstructure:"create_account"`
+	// Approval requires payments to be approved by another API key before
+	// they are submitted
+	Approval Approval
+	// Risk checks payments with velocity rules and pre-submission hooks
+	Risk Risk
+	// ExpectedPayments matches received payments with expected payments
+	// registered using /expected-payments
+	ExpectedPayments ExpectedPayments `mapstructure:"expected_payments"`
+	// Quotes contains expiry of quotes of cross-asset payments returned by
+	// /quote
+	Quotes Quotes
+	// Rates records fiat-equivalent values of received and sent payments
+	Rates Rates
+	// Fees are charged for payments sent using /payment
+	Fees Fees
+	// Webhooks enables delivery of events to webhooks registered using
+	// /admin/webhooks
+	Webhooks Webhooks
+	// MemoReferences derives memos of payments from internal references
+	MemoReferences MemoReferences `mapstructure:"memo_references"`
+	// BalanceMonitor alerts when balances of accounts fall below thresholds
+	BalanceMonitor BalanceMonitor `mapstructure:"balance_monitor"`
+	// SEP24 stores transactions of SEP-24 interactive flows and matches
+	// received payments with withdrawals
+	SEP24 SEP24 `mapstructure:"sep24"`
 }
 
 // Asset represents credit asset
 type Asset struct {
 	Code   string
 	Issuer string
+	// MinAmount, MaxAmount and Step (ex. "0.01") limit amounts of payments
+	// sent in this asset. There are no limits when empty.
+	MinAmount string `mapstructure:"min_amount"`
+	MaxAmount string `mapstructure:"max_amount"`
+	Step      string
+	// RecheckAmount is an amount above which source and destination accounts
+	// are reloaded and checked again right before a payment is signed
+	RecheckAmount string `mapstructure:"recheck_amount"`
+	// DailyMaxAmount and DestinationDailyMaxAmount limit the sum of amounts
+	// of payments sent in this asset in a day (UTC), to all destinations and
+	// to a single destination. Sums are tracked in the database.
+	DailyMaxAmount            string `mapstructure:"daily_max_amount"`
+	DestinationDailyMaxAmount string `mapstructure:"destination_daily_max_amount"`
+	// AutoApproveAmount is an amount up to which payments of this asset are
+	// sent without approval when approval.enabled is set
+	AutoApproveAmount string `mapstructure:"auto_approve_amount"`
+}
+
+// HasDailyLimits returns true when sums of payments of the asset are limited
+func (a Asset) HasDailyLimits() bool {
+	return a.DailyMaxAmount != "" || a.DestinationDailyMaxAmount != ""
+}
+
+func (a Asset) validateLimits() error {
+	limits := []struct {
+		name  string
+		value string
+	}{
+		{"min_amount", a.MinAmount},
+		{"max_amount", a.MaxAmount},
+		{"step", a.Step},
+		{"recheck_amount", a.RecheckAmount},
+		{"daily_max_amount", a.DailyMaxAmount},
+		{"destination_daily_max_amount", a.DestinationDailyMaxAmount},
+		{"auto_approve_amount", a.AutoApproveAmount},
+	}
+
+	for _, limit := range limits {
+		if limit.value == "" {
+			continue
+		}
+
+		value, err := amounts.Parse(limit.value)
+		if err != nil || value <= 0 {
+			return errors.New("Invalid " + limit.name + " param for " + a.Code)
+		}
+	}
+
+	if a.MinAmount != "" && a.MaxAmount != "" && amounts.MustParse(a.MinAmount) > amounts.MustParse(a.MaxAmount) {
+		return errors.New("min_amount is greater than max_amount for " + a.Code)
+	}
+
+	return nil
 }
 
-// Accounts contains values of `accounts` config group
+// Accounts contains values of `accounts` config group. AuthorizingSeed,
+// BaseSeed and FunderSeed can be public keys when Signer or a Signers entry
+// of the account is configured.
 type Accounts struct {
-	AuthorizingSeed    string `mapstructure:"authorizing_seed"`
-	BaseSeed           string `mapstructure:"base_seed"`
+	AuthorizingSeed string `mapstructure:"authorizing_seed" secret:""`
+	BaseSeed        string `mapstructure:"base_seed" secret:""`
+	// FunderSeed funds accounts created using /create-account
+	FunderSeed         string `mapstructure:"funder_seed" secret:""`
 	IssuingAccountID   string `mapstructure:"issuing_account_id"`
 	ReceivingAccountID string `mapstructure:"receiving_account_id"`
 }
 
+// Network is an additional Stellar network served by the bridge server.
+// Payments are sent from its accounts and submitted to its Horizon servers.
+// Payments are received, held (settlement) and sent with compliance on the
+// main network only.
+type Network struct {
+	Name              string
+	Horizon           []string
+	NetworkPassphrase string `mapstructure:"network_passphrase"`
+	// Accounts of the network, receiving_account_id is not used
+	Accounts Accounts
+	// DatabaseSchema is a Postgres schema of tables of the network (ex.
+	// "testnet"), so sent transactions of networks are not mixed. Tables of
+	// the main network are used when empty.
+	DatabaseSchema string `mapstructure:"database_schema"`
+}
+
+func (n Network) validate(databaseType string, signers map[string]bool) error {
+	if !networkNameRegexp.MatchString(n.Name) {
+		return errors.New("Invalid networks.name param: " + n.Name)
+	}
+
+	if len(n.Horizon) == 0 {
+		return errors.New("horizon param is required for " + n.Name + " network")
+	}
+
+	for _, horizonURL := range n.Horizon {
+		if validateHTTPURL(horizonURL) != nil {
+			return errors.New("Cannot parse horizon param of " + n.Name + " network: " + horizonURL)
+		}
+	}
+
+	if n.NetworkPassphrase == "" {
+		return errors.New("network_passphrase param is required for " + n.Name + " network")
+	}
+
+	for _, param := range []struct{ name, seed string }{
+		{"accounts.authorizing_seed", n.Accounts.AuthorizingSeed},
+		{"accounts.base_seed", n.Accounts.BaseSeed},
+		{"accounts.issuing_account_id", n.Accounts.IssuingAccountID},
+	} {
+		if param.seed == "" {
+			continue
+		}
+		if _, err := keypair.Parse(param.seed); err != nil {
+			return errors.New(param.name + " of " + n.Name + " network is invalid")
+		}
+		if param.name != "accounts.issuing_account_id" && IsPublicKeyOnly(param.seed) && !signers[param.seed] {
+			return errors.New("signers entry is required when " + param.name + " of " + n.Name + " network is a public key")
+		}
+	}
+
+	if n.DatabaseSchema != "" {
+		if databaseType != "postgres" {
+			return errors.New("database_schema param of " + n.Name + " network requires postgres database")
+		}
+		if !notifyChannelRegexp.MatchString(n.DatabaseSchema) {
+			return errors.New("Invalid database_schema param of " + n.Name + " network")
+		}
+	}
+	return nil
+}
+
+// Network returns the network by name, false when it's not configured
+func (c *Config) Network(name string) (Network, bool) {
+	for _, network := range c.Networks {
+		if network.Name == name {
+			return network, true
+		}
+	}
+	return Network{}, false
+}
+
+// ForNetwork returns a copy of c with Horizon servers, network passphrase and
+// accounts of network. Compliance, settlement, approval and risk hooks are
+// disabled.
+func (c Config) ForNetwork(network Network) *Config {
+	c.Horizon = network.Horizon
+	c.NetworkPassphrase = network.NetworkPassphrase
+	c.Accounts = network.Accounts
+	c.Accounts.ReceivingAccountID = ""
+	c.Compliance = ""
+	c.Settlement = Settlement{}
+	c.Approval = Approval{}
+	c.Risk = Risk{}
+	c.Networks = nil
+	return &c
+}
+
+// Signer contains values of `signer` config group. External signing service
+// signs transactions of accounts configured by public key only, see
+// protocols/signer.
+type Signer struct {
+	URL string
+	// Secret used to authenticate requests to the signing service
+	Secret string `secret:""`
+	// CertificateFile and PrivateKeyFile are PEM files of a client
+	// certificate presented to the signing service (mutual TLS). They
+	// replace the outbound_tls client certificate.
+	CertificateFile string `mapstructure:"certificate_file"`
+	PrivateKeyFile  string `mapstructure:"private_key_file"`
+	// CAFile is a PEM file of CA certificates verifying the signing service
+	// certificate (system roots when empty)
+	CAFile string `mapstructure:"ca_file"`
+}
+
+// TLSPolicy returns outbound TLS policy with the client certificate of the
+// signing service
+func (s Signer) TLSPolicy(outbound tlspolicy.Config) tlspolicy.Config {
+	if s.CertificateFile != "" {
+		outbound.CertificateFile = s.CertificateFile
+		outbound.PrivateKeyFile = s.PrivateKeyFile
+	}
+	return outbound
+}
+
+// IsPublicKeyOnly returns true when seed is a public key so transactions must
+// be signed by the external signing service or a signing backend
+func IsPublicKeyOnly(seed string) bool {
+	kp, err := keypair.Parse(seed)
+	if err != nil {
+		return false
+	}
+	_, full := kp.(*keypair.Full)
+	return !full
+}
+
 // Callbacks contains values of `callbacks` config group
 type Callbacks struct {
-	Receive string
-	Error   string
+	// Receive and Error contain callback URLs, primary endpoint first. Next
+	// URLs are used when previous ones are unavailable.
+	Receive  []string
+	Error    []string
+	Failover CallbackFailover
+	// Transport used to deliver receive callbacks: http (default), kafka, rabbitmq, nats, sqs or pubsub
+	Transport string
+	Kafka     KafkaTransport
+	RabbitMQ  RabbitMQTransport
+	NATS      NATSTransport
+	SQS       SQS
+	PubSub    PubSub
+	// Message attributes attached to sqs and pubsub messages
+	Attributes []string
+	// Format of messages of message bus transports: json (default) or
+	// cloudevents
+	Format string
+	// SchemaVersion of callback data in cloudevents format (default latest)
+	SchemaVersion string `mapstructure:"schema_version"`
 }
 
-// Validate validates config and returns error if any of config values is incorrect
-func (c *Config) Validate() (err error) {
-	if c.Port == nil {
-		err = errors.New("port param is required")
-		return
+// CallbackFailover contains values of `callbacks.failover` config group
+type CallbackFailover struct {
+	// Threshold is the number of consecutive failures after which an endpoint
+	// is considered unhealthy
+	Threshold int
+	// RecoveryInterval is the time after which an unhealthy endpoint is tried
+	// first again
+	RecoveryInterval string `mapstructure:"recovery_interval"`
+}
+
+// RecoveryIntervalDuration returns RecoveryInterval duration or 0 when it's
+// not set (default interval is used)
+func (c CallbackFailover) RecoveryIntervalDuration() time.Duration {
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.RecoveryInterval)
+	return duration
+}
+
+func (c CallbackFailover) validate() error {
+	if c.Threshold < 0 {
+		return errors.New("callbacks.failover.threshold param must be positive")
 	}
 
-	if c.Horizon == "" {
-		err = errors.New("horizon param is required")
-		return
+	if c.RecoveryInterval != "" {
+		duration, err := time.ParseDuration(c.RecoveryInterval)
+		if err != nil || duration <= 0 {
+			return errors.New("Invalid callbacks.failover.recovery_interval param")
+		}
 	}
 
-	_, err = url.Parse(c.Horizon)
-	if err != nil {
-		err = errors.New("Cannot parse horizon param")
-		return
+	return nil
+}
+
+// KafkaTransport contains values of `callbacks.kafka` config group
+type KafkaTransport struct {
+	RestProxyURL string `mapstructure:"rest_proxy_url"`
+	Topic        string
+}
+
+// RabbitMQTransport contains values of `callbacks.rabbitmq` config group
+type RabbitMQTransport struct {
+	// URL of AMQP broker, amqp:// or amqps:// for TLS connections
+	URL string
+	// Username, Password and VHost override values of URL when set
+	Username   string
+	Password   string `secret:""`
+	VHost      string
+	Exchange   string
+	RoutingKey string `mapstructure:"routing_key"`
+}
+
+// NATSTransport contains values of `callbacks.nats` config group
+type NATSTransport struct {
+	// URL of NATS server, nats:// or tls:// to require TLS connections
+	URL string
+	// Subject bound to a JetStream stream
+	Subject string
+}
+
+// SQS contains values of `sqs` config groups
+type SQS struct {
+	QueueURL string `mapstructure:"queue_url"`
+	// Region is taken from QueueURL when empty
+	Region string
+}
+
+// PubSub contains values of `pubsub` config groups
+type PubSub struct {
+	Project     string
+	Topic       string
+	AccessToken string `mapstructure:"access_token" secret:""`
+	// Endpoint can be used to connect to Pub/Sub emulator
+	Endpoint string
+}
+
+// TxStatusEvents contains values of `tx_status_events` config group
+type TxStatusEvents struct {
+	// Transport used to publish events: sqs or pubsub. Events are not published when empty.
+	Transport  string
+	SQS        SQS
+	PubSub     PubSub
+	Attributes []string
+	// Format of events: json (default) or cloudevents
+	Format string
+	// SchemaVersion of event data in cloudevents format (default latest)
+	SchemaVersion string `mapstructure:"schema_version"`
+}
+
+// CloudEvents contains values of `cloudevents` config group
+type CloudEvents struct {
+	// Source of all events in cloudevents format (default "stellar-bridge")
+	Source string
+}
+
+// EventFormatter returns a Formatter of events emitted in format using
+// schemaVersion, nil when format is not cloudevents
+func (c Config) EventFormatter(format, schemaVersion string) *cloudevents.Formatter {
+	if format != cloudevents.FormatCloudEvents {
+		return nil
 	}
+	return cloudevents.NewFormatter(c.CloudEvents.Source, schemaVersion)
+}
 
-	if c.NetworkPassphrase == "" {
-		err = errors.New("network_passphrase param is required")
-		return
+func validateEventFormat(group, format, schemaVersion string) error {
+	if !cloudevents.IsValidFormat(format) {
+		return fmt.Errorf("%s.format param must be json or cloudevents", group)
 	}
 
-	for _, asset := range c.Assets {
-		if asset.Issuer == "" {
-			if asset.Code != "XLM" {
-				err = errors.New("Issuer param is required for " + asset.Code)
-				return
-			}
+	if schemaVersion != "" {
+		if version, ok := cloudevents.Negotiate(schemaVersion); !ok || version != schemaVersion {
+			return fmt.Errorf("Unsupported %s.schema_version param, supported versions: %s", group, strings.Join(cloudevents.SchemaVersions, ", "))
 		}
+	}
+	return nil
+}
 
-		if asset.Issuer != "" {
-			_, err = keypair.Parse(asset.Issuer)
-			if err != nil {
-				err = errors.New("Issuing account is invalid for " + asset.Code)
-				return
-			}
+// MessageAttributes are the names of attributes that can be attached to queue messages
+var MessageAttributes = []string{"asset_code", "asset_issuer", "account"}
+
+// Settlement contains values of `settlement` config group
+type Settlement struct {
+	// Delay of payments sent by tenants not listed in Tenants, ex. "15m".
+	// Payments are submitted immediately when empty.
+	Delay   string
+	Tenants []SettlementTenant
+	// MaxAttempts limits submissions of a released payment failed with a
+	// server error before it fails (default 10)
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// DrainTimeout is a hard deadline of releasing held payments with
+	// settlement window ended when the server is stopped (default "30s")
+	DrainTimeout string `mapstructure:"drain_timeout"`
+	// HandoffFile is a path of a manifest of payments still held when the
+	// server is stopped. It's ingested by the next server on start.
+	HandoffFile string `mapstructure:"handoff_file"`
+	// HandoffKey is a base64 encoded Curve25519 private key encrypting
+	// secret seeds of held payments stored in the database and written to
+	// the manifest. Payments from accounts other than accounts.base_seed
+	// cannot be held when empty.
+	HandoffKey string `mapstructure:"handoff_key" secret:""`
+}
+
+// SettlementTenant overrides settlement delay for payments sent by a given tenant
+type SettlementTenant struct {
+	Tenant string
+	Delay  string
+}
+
+// DelayFor returns settlement delay for payments sent by tenant
+func (s Settlement) DelayFor(tenant string) time.Duration {
+	delay := s.Delay
+	for _, t := range s.Tenants {
+		if tenant != "" && t.Tenant == tenant {
+			delay = t.Delay
 		}
+	}
 
-		matched, err := regexp.MatchString("^[a-zA-Z0-9]{1,12}$", asset.Code)
-		if err != nil {
-			return err
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(delay)
+	return duration
+}
+
+// MaxAttemptsOrDefault returns MaxAttempts or 10 when not set
+func (s Settlement) MaxAttemptsOrDefault() int {
+	if s.MaxAttempts == 0 {
+		return 10
+	}
+	return s.MaxAttempts
+}
+
+// DrainTimeoutDuration returns parsed DrainTimeout or 30 seconds when not set
+func (s Settlement) DrainTimeoutDuration() time.Duration {
+	// Value is checked in Validate
+	if s.DrainTimeout == "" {
+		return 30 * time.Second
+	}
+	duration, _ := time.ParseDuration(s.DrainTimeout)
+	return duration
+}
+
+// Enabled returns true if payments from any account can be held
+func (s Settlement) Enabled() bool {
+	if s.Delay != "" {
+		return true
+	}
+
+	for _, tenant := range s.Tenants {
+		if tenant.Delay != "" {
+			return true
 		}
+	}
+	return false
+}
 
-		if !matched {
-			err = errors.New("Invalid asset code: " + asset.Code)
-			return err
+// HorizonClient contains values of `horizon_client` config group. Empty values
+// are replaced with horizon.DefaultOptions.
+type HorizonClient struct {
+	// Timeout of a single request, ex. "10s"
+	Timeout string
+	// SubmitTimeout is a timeout of a request submitting a transaction, ex. "60s"
+	SubmitTimeout string `mapstructure:"submit_timeout"`
+	MaxIdleConns  int    `mapstructure:"max_idle_conns"`
+	// Retries is the number of retries of failed GET requests
+	Retries    *int
+	RetryDelay string `mapstructure:"retry_delay"`
+	// BreakerThreshold is the number of consecutive failures after which
+	// requests fail fast. Set to 0 to disable circuit breaker.
+	BreakerThreshold *int   `mapstructure:"breaker_threshold"`
+	BreakerCooldown  string `mapstructure:"breaker_cooldown"`
+	// RateLimitReserve is a percentage of Horizon rate limit reserved for
+	// submissions and destination checks. Set to 0 to disable throttling.
+	RateLimitReserve *int `mapstructure:"rate_limit_reserve"`
+}
+
+// Options returns horizon.Options
+func (c HorizonClient) Options() horizon.Options {
+	options := horizon.DefaultOptions
+
+	// Values are checked in Validate
+	if c.Timeout != "" {
+		options.Timeout, _ = time.ParseDuration(c.Timeout)
+	}
+	if c.SubmitTimeout != "" {
+		options.SubmitTimeout, _ = time.ParseDuration(c.SubmitTimeout)
+	}
+	if c.MaxIdleConns != 0 {
+		options.MaxIdleConns = c.MaxIdleConns
+	}
+	if c.Retries != nil {
+		options.Retries = *c.Retries
+	}
+	if c.RetryDelay != "" {
+		options.RetryDelay, _ = time.ParseDuration(c.RetryDelay)
+	}
+	if c.BreakerThreshold != nil {
+		options.BreakerThreshold = *c.BreakerThreshold
+	}
+	if c.BreakerCooldown != "" {
+		options.BreakerCooldown, _ = time.ParseDuration(c.BreakerCooldown)
+	}
+	if c.RateLimitReserve != nil {
+		options.RateLimitReserve = *c.RateLimitReserve
+	}
+	return options
+}
+
+func (c HorizonClient) validate() error {
+	durations := []struct {
+		name  string
+		value string
+	}{
+		{"horizon_client.timeout", c.Timeout},
+		{"horizon_client.submit_timeout", c.SubmitTimeout},
+		{"horizon_client.retry_delay", c.RetryDelay},
+		{"horizon_client.breaker_cooldown", c.BreakerCooldown},
+	}
+
+	for _, duration := range durations {
+		if duration.value == "" {
+			continue
+		}
+
+		value, err := time.ParseDuration(duration.value)
+		if err != nil || value <= 0 {
+			return errors.New("Cannot parse " + duration.name + " param")
 		}
 	}
 
-	var dbURL *url.URL
-	dbURL, err = url.Parse(c.Database.URL)
-	if err != nil {
-		err = errors.New("Cannot parse database.url param")
-		return
+	if c.MaxIdleConns < 0 {
+		return errors.New("horizon_client.max_idle_conns param must be positive")
 	}
 
-	switch c.Database.Type {
-	case "mysql":
-		// Add `parseTime=true` param to mysql url
-		query := dbURL.Query()
-		query.Set("parseTime", "true")
-		dbURL.RawQuery = query.Encode()
-		c.Database.URL = dbURL.String()
-	case "postgres":
-		break
-	case "":
-		// Allow to start gateway server with a single endpoint: /payment
+	if c.Retries != nil && *c.Retries < 0 {
+		return errors.New("horizon_client.retries param must be positive")
+	}
+
+	if c.BreakerThreshold != nil && *c.BreakerThreshold < 0 {
+		return errors.New("horizon_client.breaker_threshold param must be positive")
+	}
+
+	if c.RateLimitReserve != nil && (*c.RateLimitReserve < 0 || *c.RateLimitReserve >= 100) {
+		return errors.New("horizon_client.rate_limit_reserve param must be between 0 and 99")
+	}
+
+	return nil
+}
+
+// Submission contains values of `submission` config group
+type Submission struct {
+	// Backend used to submit transactions: horizon (default) or stellar-core
+	Backend     string
+	StellarCore StellarCore `mapstructure:"stellar_core"`
+	// TimeBounds is how long transactions built by the bridge server are
+	// valid, ex. "5m". Transactions have no time bounds when empty. Time
+	// bounds are not applied while clock drift is detected, see Clock.
+	TimeBounds string `mapstructure:"time_bounds"`
+	// Concurrency is the number of workers submitting transactions
+	// concurrently. Transactions are submitted in HTTP handlers when 0.
+	Concurrency int
+	// QueueDepth is the number of transactions waiting for a worker, more
+	// transactions are rejected with 503 error. 100 when 0.
+	QueueDepth int `mapstructure:"queue_depth"`
+	// ChannelSeeds are seeds of channel accounts used as sources of
+	// transactions submitted by workers, so transactions of one account can
+	// be submitted concurrently
+	ChannelSeeds []string `mapstructure:"channel_seeds"`
+	Preflight    Preflight
+}
+
+// QueueDepthOrDefault returns QueueDepth or 100 when it's not set
+func (s Submission) QueueDepthOrDefault() int {
+	if s.QueueDepth == 0 {
+		return 100
+	}
+	return s.QueueDepth
+}
+
+// Preflight contains values of `submission.preflight` config group
+type Preflight struct {
+	// HighValue enables preflight of payments above recheck_amount of their
+	// asset: operations are simulated using current account state right
+	// before the transaction is signed and predicted failures are returned
+	// instead of submitting it
+	HighValue bool `mapstructure:"high_value"`
+	// BaseReserve of the network used to compute min balances of accounts,
+	// "0.5" when empty
+	BaseReserve string `mapstructure:"base_reserve"`
+}
+
+// BaseReserveStroops returns BaseReserve in stroops or 0 when it's empty
+func (p Preflight) BaseReserveStroops() int64 {
+	// Values are checked in Validate
+	stroops, _ := amounts.Parse(p.BaseReserve)
+	return stroops
+}
+
+// TimeBoundsDuration returns TimeBounds duration or 0 when it's empty
+func (s Submission) TimeBoundsDuration() time.Duration {
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(s.TimeBounds)
+	return duration
+}
+
+// StellarCore contains values of `submission.stellar_core` config group
+type StellarCore struct {
+	// URL of stellar-core HTTP port
+	URL string
+	// DatabaseURL of stellar-core postgres database used to check if submitted
+	// transactions have been included in a ledger and to load sequence numbers
+	DatabaseURL string `mapstructure:"database_url" secret:""`
+	// SorobanRPCURL of soroban-rpc server used instead of DatabaseURL with
+	// stellar-core versions that don't populate txhistory and accounts tables
+	SorobanRPCURL string `mapstructure:"soroban_rpc_url"`
+	PollInterval  string `mapstructure:"poll_interval"`
+	Timeout       string
+}
+
+// PollIntervalDuration returns PollInterval or 1 second when it's empty
+func (c StellarCore) PollIntervalDuration() time.Duration {
+	// Values are checked in Validate
+	if c.PollInterval == "" {
+		return time.Second
+	}
+	duration, _ := time.ParseDuration(c.PollInterval)
+	return duration
+}
+
+// TimeoutDuration returns Timeout or 1 minute when it's empty
+func (c StellarCore) TimeoutDuration() time.Duration {
+	// Values are checked in Validate
+	if c.Timeout == "" {
+		return time.Minute
+	}
+	duration, _ := time.ParseDuration(c.Timeout)
+	return duration
+}
+
+func (s Submission) validate() error {
+	if s.TimeBounds != "" {
+		if value, err := time.ParseDuration(s.TimeBounds); err != nil || value <= 0 {
+			return errors.New("Cannot parse submission.time_bounds param")
+		}
+	}
+
+	if s.Preflight.BaseReserve != "" {
+		if value, err := amounts.Parse(s.Preflight.BaseReserve); err != nil || value == 0 {
+			return errors.New("Cannot parse submission.preflight.base_reserve param")
+		}
+	}
+
+	if s.Concurrency < 0 {
+		return errors.New("submission.concurrency param cannot be negative")
+	}
+
+	if s.QueueDepth < 0 {
+		return errors.New("submission.queue_depth param cannot be negative")
+	}
+
+	if len(s.ChannelSeeds) > 0 && s.Concurrency == 0 {
+		return errors.New("submission.channel_seeds param requires submission.concurrency")
+	}
+
+	for _, seed := range s.ChannelSeeds {
+		if _, err := keypair.Parse(seed); err != nil || !strings.HasPrefix(seed, "S") {
+			return errors.New("Invalid submission.channel_seeds param")
+		}
+	}
+
+	switch s.Backend {
+	case "", "horizon":
+		return nil
+	case "stellar-core":
 		break
 	default:
-		err = errors.New("Invalid database.type param")
-		return
+		return errors.New("Invalid submission.backend param")
 	}
 
-	if c.Accounts.AuthorizingSeed != "" {
-		_, err = keypair.Parse(c.Accounts.AuthorizingSeed)
-		if err != nil {
-			err = errors.New("accounts.authorizing_seed is invalid")
-			return
+	if s.StellarCore.URL == "" {
+		return errors.New("submission.stellar_core.url param is required")
+	}
+
+	_, err := url.Parse(s.StellarCore.URL)
+	if err != nil {
+		return errors.New("Cannot parse submission.stellar_core.url param")
+	}
+
+	switch {
+	case s.StellarCore.DatabaseURL == "" && s.StellarCore.SorobanRPCURL == "":
+		return errors.New("submission.stellar_core.database_url or submission.stellar_core.soroban_rpc_url param is required")
+	case s.StellarCore.DatabaseURL != "" && s.StellarCore.SorobanRPCURL != "":
+		return errors.New("submission.stellar_core.database_url and submission.stellar_core.soroban_rpc_url params cannot be both set")
+	case s.StellarCore.SorobanRPCURL != "" && validateHTTPURL(s.StellarCore.SorobanRPCURL) != nil:
+		return errors.New("Cannot parse submission.stellar_core.soroban_rpc_url param")
+	}
+
+	for name, value := range map[string]string{
+		"poll_interval": s.StellarCore.PollInterval,
+		"timeout":       s.StellarCore.Timeout,
+	} {
+		if value == "" {
+			continue
+		}
+
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return errors.New("Cannot parse submission.stellar_core." + name + " param")
 		}
 	}
 
-	if c.Accounts.BaseSeed != "" {
-		_, err = keypair.Parse(c.Accounts.BaseSeed)
-		if err != nil {
-			err = errors.New("accounts.base_seed is invalid")
-			return
+	return nil
+}
+
+// Listener contains values of `listener` config group
+type Listener struct {
+	// Backend used to stream received payments: horizon (default),
+	// stellar-core-database or history-archive
+	Backend string
+	// DatabaseURL of stellar-core postgres database read by
+	// stellar-core-database backend
+	DatabaseURL string `mapstructure:"database_url" secret:""`
+	// HistoryArchiveURL of the history archive read by history-archive
+	// backend
+	HistoryArchiveURL string `mapstructure:"history_archive_url"`
+	PollInterval      string `mapstructure:"poll_interval"`
+	// StaleAfter is the time after which /readyz reports payments stream
+	// that has not been up to date, ex. "2m"
+	StaleAfter string `mapstructure:"stale_after"`
+	// SorobanRPCURL of soroban-rpc server contract transfers are streamed
+	// from, they are not streamed when it's empty
+	SorobanRPCURL string `mapstructure:"soroban_rpc_url"`
+	// Contracts are contract (C...) addresses whose received SAC transfers
+	// are sent to receive callbacks, like transfers to the receiving account
+	Contracts []string
+	// LiquidityPoolIDs enables loading IDs of liquidity pools path payments
+	// were routed through, it costs an additional backend request per path
+	// payment
+	LiquidityPoolIDs bool `mapstructure:"liquidity_pool_ids"`
+}
+
+// PollIntervalDuration returns PollInterval or 1 second when it's empty
+func (c Listener) PollIntervalDuration() time.Duration {
+	// Values are checked in Validate
+	if c.PollInterval == "" {
+		return time.Second
+	}
+	duration, _ := time.ParseDuration(c.PollInterval)
+	return duration
+}
+
+// StaleAfterDuration returns StaleAfter or 2 minutes when it's empty
+func (c Listener) StaleAfterDuration() time.Duration {
+	// Values are checked in Validate
+	if c.StaleAfter == "" {
+		return 2 * time.Minute
+	}
+	duration, _ := time.ParseDuration(c.StaleAfter)
+	return duration
+}
+
+func (c Listener) validate() error {
+	if c.StaleAfter != "" {
+		duration, err := time.ParseDuration(c.StaleAfter)
+		if err != nil || duration <= 0 {
+			return errors.New("Cannot parse listener.stale_after param")
 		}
 	}
 
-	if c.Accounts.IssuingAccountID != "" {
-		_, err = keypair.Parse(c.Accounts.IssuingAccountID)
-		if err != nil {
-			err = errors.New("accounts.issuing_account_id is invalid")
-			return
+	if c.PollInterval != "" {
+		duration, err := time.ParseDuration(c.PollInterval)
+		if err != nil || duration <= 0 {
+			return errors.New("Cannot parse listener.poll_interval param")
 		}
 	}
 
-	if c.Accounts.ReceivingAccountID != "" {
-		_, err = keypair.Parse(c.Accounts.ReceivingAccountID)
-		if err != nil {
-			err = errors.New("accounts.receiving_account_id is invalid")
-			return
+	if c.SorobanRPCURL != "" {
+		u, err := url.Parse(c.SorobanRPCURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("Cannot parse listener.soroban_rpc_url param")
 		}
 	}
 
-	if c.Callbacks.Receive != "" {
-		_, err = url.Parse(c.Callbacks.Receive)
-		if err != nil {
-			err = errors.New("Cannot parse callbacks.receive param")
-			return
+	if len(c.Contracts) > 0 && c.SorobanRPCURL == "" {
+		return errors.New("listener.soroban_rpc_url param is required to receive transfers to listener.contracts")
+	}
+
+	// The receiving account is streamed with contracts
+	if len(c.Contracts) > sorobanrpc.MaxAddresses-1 {
+		return fmt.Errorf("listener.contracts param can contain at most %d contracts", sorobanrpc.MaxAddresses-1)
+	}
+
+	for _, contract := range c.Contracts {
+		if !sorobanrpc.IsContractAddress(contract) {
+			return fmt.Errorf("Invalid contract address %s in listener.contracts param", contract)
 		}
 	}
 
-	if c.Callbacks.Error != "" {
-		_, err = url.Parse(c.Callbacks.Error)
-		if err != nil {
-			err = errors.New("Cannot parse callbacks.error param")
-			return
+	switch c.Backend {
+	case "", "horizon":
+		return nil
+	case "stellar-core-database":
+		if c.DatabaseURL == "" {
+			return errors.New("listener.database_url param is required")
 		}
+	case "history-archive":
+		u, err := url.Parse(c.HistoryArchiveURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("listener.history_archive_url param is required and must be an http(s) URL")
+		}
+	default:
+		return errors.New("Invalid listener.backend param")
 	}
 
-	return
+	return nil
+}
+
+// Federation contains values of `federation` config group. When Domain is
+// set the bridge serves federation requests at /federation.
+type Federation struct {
+	// Domain of stellar addresses (name*domain) resolved by this server
+	Domain string
+	// Query is a SQL query run against bridge database to resolve a name.
+	// It takes name and domain params (`?` placeholders) and must return
+	// `id`, `memo_type` and `memo` columns.
+	Query string
+	// ReverseQuery is a SQL query resolving account ID to `name` and `domain`
+	ReverseQuery string `mapstructure:"reverse_query"`
+	// Records is a static list of addresses used when Query is empty
+	Records []FederationRecord
+}
+
+// FederationRecord is a single static federation record
+type FederationRecord struct {
+	Name      string
+	AccountID string `mapstructure:"account_id"`
+	MemoType  string `mapstructure:"memo_type"`
+	Memo      string
+}
+
+// Enabled returns true when federation server should be started
+func (f Federation) Enabled() bool {
+	return f.Domain != ""
+}
+
+func (f Federation) validate(databaseType string) error {
+	if !f.Enabled() {
+		return nil
+	}
+
+	if f.Query == "" && len(f.Records) == 0 {
+		return errors.New("federation.query or federation.records param is required")
+	}
+
+	if f.Query != "" && databaseType == "" {
+		return errors.New("database is required when federation.query is set")
+	}
+
+	for _, record := range f.Records {
+		if record.Name == "" {
+			return errors.New("federation.records.name param is required")
+		}
+
+		_, err := keypair.Parse(record.AccountID)
+		if err != nil {
+			return errors.New("federation.records.account_id is invalid for " + record.Name)
+		}
+
+		switch record.MemoType {
+		case "", "text", "id", "hash":
+			break
+		default:
+			return errors.New("Invalid federation.records.memo_type param for " + record.Name)
+		}
+	}
+
+	return nil
+}
+
+// Cache contains values of `cache` config group. When TTL is set stellar.toml
+// files and federation responses are cached. When AccountTTL is set existing
+// destination accounts are cached.
+type Cache struct {
+	// Size is a max number of cached responses of each kind (default 1000)
+	Size int
+	TTL  string
+	// NegativeTTL is used for failed lookups. Errors are not cached when empty.
+	NegativeTTL string `mapstructure:"negative_ttl"`
+	// AccountTTL is used for destination accounts loaded from Horizon. It
+	// should be short, cached accounts are dropped when a payment fails.
+	AccountTTL string `mapstructure:"account_ttl"`
+}
+
+// Enabled returns true when responses should be cached
+func (c Cache) Enabled() bool {
+	return c.TTL != ""
+}
+
+// SizeOrDefault returns Size or 1000 when it's not set
+func (c Cache) SizeOrDefault() int {
+	if c.Size == 0 {
+		return 1000
+	}
+	return c.Size
+}
+
+// TTLDuration returns TTL duration
+func (c Cache) TTLDuration() time.Duration {
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.TTL)
+	return duration
+}
+
+// AccountTTLDuration returns AccountTTL duration or 0 when destination
+// accounts are not cached
+func (c Cache) AccountTTLDuration() time.Duration {
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.AccountTTL)
+	return duration
+}
+
+// NegativeTTLDuration returns NegativeTTL duration or 0 when it's empty
+func (c Cache) NegativeTTLDuration() time.Duration {
+	// Values are checked in Validate
+	if c.NegativeTTL == "" {
+		return 0
+	}
+	duration, _ := time.ParseDuration(c.NegativeTTL)
+	return duration
+}
+
+// Congestion contains values of `congestion` config group. Network
+// congestion detection is disabled when Interval is empty.
+type Congestion struct {
+	// Interval is how often fee stats are loaded from Horizon, ex. "30s"
+	Interval string
+	// CapacityThreshold is ledger capacity usage (0-1) above which the network
+	// is congested (default 0.8)
+	CapacityThreshold float64 `mapstructure:"capacity_threshold"`
+	// FeePercentile of accepted fees used when the network is not congested.
+	// Base fee is used when empty.
+	FeePercentile int `mapstructure:"fee_percentile"`
+	// CongestedFeePercentile of accepted fees used during congestion (default 90)
+	CongestedFeePercentile int `mapstructure:"congested_fee_percentile"`
+	// MaxFee is a max fee per operation in stroops. No limit when empty.
+	MaxFee uint32 `mapstructure:"max_fee"`
+	// RetryDelayMultiplier stretches Horizon retry delays during congestion (default 4)
+	RetryDelayMultiplier int `mapstructure:"retry_delay_multiplier"`
+}
+
+// Enabled returns true when network congestion should be monitored
+func (c Congestion) Enabled() bool {
+	return c.Interval != ""
+}
+
+// IntervalDuration returns Interval duration
+func (c Congestion) IntervalDuration() time.Duration {
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.Interval)
+	return duration
+}
+
+// Options returns congestion.Options. Empty values are replaced with
+// congestion.DefaultOptions.
+func (c Congestion) Options() congestion.Options {
+	options := congestion.DefaultOptions
+	if c.CapacityThreshold != 0 {
+		options.CapacityThreshold = c.CapacityThreshold
+	}
+	options.FeePercentile = c.FeePercentile
+	if c.CongestedFeePercentile != 0 {
+		options.CongestedFeePercentile = c.CongestedFeePercentile
+	}
+	options.MaxFee = c.MaxFee
+	if c.RetryDelayMultiplier != 0 {
+		options.RetryDelayMultiplier = c.RetryDelayMultiplier
+	}
+	return options
+}
+
+func (c Congestion) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	if value, err := time.ParseDuration(c.Interval); err != nil || value <= 0 {
+		return errors.New("Cannot parse congestion.interval param")
+	}
+
+	if c.CapacityThreshold < 0 || c.CapacityThreshold > 1 {
+		return errors.New("congestion.capacity_threshold param must be between 0 and 1")
+	}
+
+	if c.FeePercentile != 0 && !horizon.IsValidFeePercentile(c.FeePercentile) {
+		return errors.New("Invalid congestion.fee_percentile param")
+	}
+
+	if c.CongestedFeePercentile != 0 && !horizon.IsValidFeePercentile(c.CongestedFeePercentile) {
+		return errors.New("Invalid congestion.congested_fee_percentile param")
+	}
+
+	if c.RetryDelayMultiplier < 0 {
+		return errors.New("congestion.retry_delay_multiplier param must be positive")
+	}
+
+	return nil
+}
+
+// Clock contains values of `clock` config group. Clock drift detection is
+// disabled when CheckInterval is empty.
+type Clock struct {
+	// CheckInterval is how often the local clock is compared with the close
+	// time of the last ledger ingested by Horizon, ex. "1m"
+	CheckInterval string `mapstructure:"check_interval"`
+	// MaxDrift is the max difference between the clocks, larger drift stops
+	// applying time bounds to transactions (default 30s)
+	MaxDrift string `mapstructure:"max_drift"`
+}
+
+// Enabled returns true when clock drift should be monitored
+func (c Clock) Enabled() bool {
+	return c.CheckInterval != ""
+}
+
+// CheckIntervalDuration returns CheckInterval duration
+func (c Clock) CheckIntervalDuration() time.Duration {
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.CheckInterval)
+	return duration
+}
+
+// MaxDriftOrDefault returns MaxDrift or clock.DefaultMaxDrift when it's empty
+func (c Clock) MaxDriftOrDefault() time.Duration {
+	if c.MaxDrift == "" {
+		return clock.DefaultMaxDrift
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.MaxDrift)
+	return duration
+}
+
+func (c Clock) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	if value, err := time.ParseDuration(c.CheckInterval); err != nil || value <= 0 {
+		return errors.New("Cannot parse clock.check_interval param")
+	}
+
+	if c.MaxDrift != "" {
+		if value, err := time.ParseDuration(c.MaxDrift); err != nil || value <= 0 {
+			return errors.New("Cannot parse clock.max_drift param")
+		}
+	}
+
+	return nil
+}
+
+// ChainReconciliation contains values of `chain_reconciliation` config
+// group. Sent transactions are not compared with Horizon when Interval is
+// empty.
+type ChainReconciliation struct {
+	// Interval is how often sent transactions are checked, ex. "1m"
+	Interval string
+	// Lookback is the max age of checked transactions (default 24h)
+	Lookback string
+	// MinAge is the min age of checked transactions, so transactions still
+	// being submitted are skipped (default 10m)
+	MinAge string `mapstructure:"min_age"`
+}
+
+// Enabled returns true when sent transactions should be compared with Horizon
+func (c ChainReconciliation) Enabled() bool {
+	return c.Interval != ""
+}
+
+// IntervalDuration returns Interval duration
+func (c ChainReconciliation) IntervalDuration() time.Duration {
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.Interval)
+	return duration
+}
+
+// LookbackDuration returns Lookback duration or 24 hours when it's not set
+func (c ChainReconciliation) LookbackDuration() time.Duration {
+	if c.Lookback == "" {
+		return 24 * time.Hour
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.Lookback)
+	return duration
+}
+
+// MinAgeDuration returns MinAge duration or 10 minutes when it's not set
+func (c ChainReconciliation) MinAgeDuration() time.Duration {
+	if c.MinAge == "" {
+		return 10 * time.Minute
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.MinAge)
+	return duration
+}
+
+func (c ChainReconciliation) validate(databaseType string) error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	for _, duration := range []struct{ name, value string }{
+		{"chain_reconciliation.interval", c.Interval},
+		{"chain_reconciliation.lookback", c.Lookback},
+		{"chain_reconciliation.min_age", c.MinAge},
+	} {
+		if duration.value == "" {
+			continue
+		}
+		value, err := time.ParseDuration(duration.value)
+		if err != nil || value <= 0 {
+			return errors.New("Cannot parse " + duration.name + " param")
+		}
+	}
+
+	if c.MinAgeDuration() >= c.LookbackDuration() {
+		return errors.New("chain_reconciliation.min_age param must be less than chain_reconciliation.lookback")
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when chain_reconciliation is enabled")
+	}
+
+	return nil
+}
+
+// Tracing contains values of `tracing` config group. Spans are not recorded
+// when Endpoint is empty.
+type Tracing struct {
+	// Endpoint is a base URL of OTLP/HTTP collector, ex. "http://localhost:4318"
+	Endpoint string
+	// ServiceName is sent as `service.name` resource attribute (default
+	// "bridge")
+	ServiceName string `mapstructure:"service_name"`
+	// SampleRatio is the fraction of recorded traces, between 0 and 1
+	// (default 1)
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+	// Headers are added to requests to the collector
+	Headers map[string]string
+}
+
+// Enabled returns true when spans should be recorded
+func (c Tracing) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+// ServiceNameOrDefault returns ServiceName or "bridge" when it's not set
+func (c Tracing) ServiceNameOrDefault() string {
+	if c.ServiceName == "" {
+		return "bridge"
+	}
+	return c.ServiceName
+}
+
+// SampleRatioOrDefault returns SampleRatio or 1 when it's not set
+func (c Tracing) SampleRatioOrDefault() float64 {
+	if c.SampleRatio == 0 {
+		return 1
+	}
+	return c.SampleRatio
+}
+
+func (c Tracing) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return errors.New("Invalid tracing.endpoint param")
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.New("tracing.sample_ratio param must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// Admin contains values of `admin` config group. When Address is set
+// operational endpoints (/admin/*, /reprocess and /metrics) are served only by
+// a separate listener protected by HTTP basic auth, so the payment API can be
+// exposed without them.
+type Admin struct {
+	// Address of the admin listener, ex. "127.0.0.1:8007"
+	Address  string
+	Username string
+	Password string `secret:""`
+}
+
+// Enabled returns true when operational endpoints are served by a separate
+// listener
+func (c Admin) Enabled() bool {
+	return c.Address != ""
+}
+
+func (c Admin) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	_, port, err := net.SplitHostPort(c.Address)
+	if err != nil || port == "" {
+		return errors.New("Invalid admin.address param, it must be host:port")
+	}
+
+	if c.Username == "" || c.Password == "" {
+		return errors.New("admin.username and admin.password params are required")
+	}
+
+	return nil
+}
+
+// Auth contains values of `auth` config group. Requests to /payment,
+// /builder, /create-keypair and /create-account must be authenticated with one
+// of the keys when Keys are set or Database is true.
+type Auth struct {
+	Keys []APIKey
+	// Database enables keys stored in APIKey table (in addition to Keys)
+	Database bool
+	// MaxClockSkew is the max difference between the timestamp of a signed
+	// request and the server time (default "5m")
+	MaxClockSkew string `mapstructure:"max_clock_skew"`
+}
+
+// APIKey contains values of `auth.keys` config group
+type APIKey struct {
+	ID     string
+	Secret string `secret:""`
+	// RequireSignature rejects requests authenticated with the secret
+	// instead of HMAC signature
+	RequireSignature bool `mapstructure:"require_signature"`
+	// RateLimit is the max number of requests per minute, no limit when 0
+	RateLimit int `mapstructure:"rate_limit"`
+	// Sources are allowed source accounts, all accounts when empty
+	Sources []string
+	// Tenant of requests authenticated with the key, see features.Tenant
+	Tenant string
+}
+
+// Enabled returns true when requests must be authenticated with API keys
+func (c Auth) Enabled() bool {
+	return len(c.Keys) > 0 || c.Database
+}
+
+// MaxClockSkewDuration returns MaxClockSkew duration or 5 minutes when it's
+// not set
+func (c Auth) MaxClockSkewDuration() time.Duration {
+	if c.MaxClockSkew == "" {
+		return 5 * time.Minute
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.MaxClockSkew)
+	return duration
+}
+
+func (c Auth) validate(databaseType string) error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	ids := map[string]bool{}
+	for _, key := range c.Keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return errors.New("Invalid auth.keys.id param: " + key.ID)
+		}
+		if ids[key.ID] {
+			return errors.New("Duplicate auth.keys.id param: " + key.ID)
+		}
+		ids[key.ID] = true
+
+		if len(key.Secret) < 15 {
+			return errors.New("auth.keys.secret of " + key.ID + " key have to be at least 15 chars long")
+		}
+		if key.RateLimit < 0 {
+			return errors.New("auth.keys.rate_limit param cannot be negative")
+		}
+		if len(key.Tenant) > 64 {
+			return errors.New("auth.keys.tenant param cannot be longer than 64 chars")
+		}
+		for _, source := range key.Sources {
+			if _, err := keypair.Parse(source); err != nil {
+				return errors.New("Invalid auth.keys.sources param: " + source)
+			}
+		}
+	}
+
+	if c.MaxClockSkew != "" {
+		if value, err := time.ParseDuration(c.MaxClockSkew); err != nil || value <= 0 {
+			return errors.New("Cannot parse auth.max_clock_skew param")
+		}
+	}
+
+	if c.Database && databaseType == "" {
+		return errors.New("database is required when auth.database is set")
+	}
+
+	return nil
+}
+
+// GRPC config. gRPC calls are served over HTTP/2 by the API listener: with
+// TLS when `tls` is enabled, unencrypted (h2c) otherwise.
+type GRPC struct {
+	Enabled bool
+}
+
+// Stream contains values of `stream` config group. Receive callbacks are
+// recorded in the database and pushed to subscribers of /stream/payments
+// (SSE or WebSocket).
+type Stream struct {
+	Enabled bool
+	// MaxSubscribers is the max number of open streams (default 100)
+	MaxSubscribers int `mapstructure:"max_subscribers"`
+	// PollInterval is the interval of checking the database for events
+	// recorded by other servers (default "5s")
+	PollInterval string `mapstructure:"poll_interval"`
+	// Heartbeat is the interval of keep-alive messages sent to idle streams
+	// (default "15s")
+	Heartbeat string
+}
+
+// MaxSubscribersOrDefault returns MaxSubscribers or 100 when it's not set
+func (c Stream) MaxSubscribersOrDefault() int {
+	if c.MaxSubscribers == 0 {
+		return 100
+	}
+	return c.MaxSubscribers
+}
+
+// PollIntervalDuration returns PollInterval duration or 5 seconds when it's
+// not set
+func (c Stream) PollIntervalDuration() time.Duration {
+	if c.PollInterval == "" {
+		return 5 * time.Second
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.PollInterval)
+	return duration
+}
+
+// HeartbeatDuration returns Heartbeat duration or 15 seconds when it's not
+// set
+func (c Stream) HeartbeatDuration() time.Duration {
+	if c.Heartbeat == "" {
+		return 15 * time.Second
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.Heartbeat)
+	return duration
+}
+
+// Approval contains values of `approval` config group
+type Approval struct {
+	// Enabled holds payments until they are approved using
+	// /admin/payments/{id}/approve with an API key other than the key that
+	// sent the payment. Payments up to auto_approve_amount of their asset are
+	// sent without approval.
+	Enabled bool
+}
+
+func (a Approval) validate(databaseType string, authEnabled bool) error {
+	if !a.Enabled {
+		return nil
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when approval.enabled is set")
+	}
+
+	if !authEnabled {
+		return errors.New("auth.keys param is required when approval.enabled is set")
+	}
+
+	return nil
+}
+
+// ExpectedPayments contains values of `expected_payments` config group
+type ExpectedPayments struct {
+	// Enabled matches received payments with expected payments by asset and
+	// memo. Matches are sent in receive callbacks, payments that don't match
+	// are listed by /admin/unmatched-payments.
+	Enabled bool
+}
+
+func (e ExpectedPayments) validate(databaseType string) error {
+	if e.Enabled && databaseType == "" {
+		return errors.New("database is required when expected_payments.enabled is set")
+	}
+	return nil
+}
+
+// SEP24 contains values of `sep24` config group
+type SEP24 struct {
+	// Enabled serves /sep24/* endpoints and matches payments received by
+	// accounts.receiving_account_id with SEP-24 withdrawals by asset and memo
+	Enabled bool
+}
+
+func (s SEP24) validate(databaseType, receivingAccountID string) error {
+	if !s.Enabled {
+		return nil
+	}
+	if databaseType == "" {
+		return errors.New("database is required when sep24.enabled is set")
+	}
+	if receivingAccountID == "" {
+		return errors.New("accounts.receiving_account_id is required when sep24.enabled is set")
+	}
+	return nil
+}
+
+// Quotes contains values of `quotes` config group
+type Quotes struct {
+	// Expiry is the time a quote can be used by /payment (default "30s")
+	Expiry string
+}
+
+// ExpiryOrDefault returns Expiry or 30 seconds when it's not set
+func (q Quotes) ExpiryOrDefault() time.Duration {
+	if q.Expiry == "" {
+		return 30 * time.Second
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(q.Expiry)
+	return duration
+}
+
+func (q Quotes) validate() error {
+	if q.Expiry != "" {
+		if value, err := time.ParseDuration(q.Expiry); err != nil || value <= 0 {
+			return errors.New("Cannot parse quotes.expiry param")
+		}
+	}
+	return nil
+}
+
+// Rates contains values of `rates` config group: exchange rates provider and
+// base currencies fiat values of payments are recorded in
+type Rates struct {
+	// Provider is "fixed" or "http", fiat values are not recorded when empty
+	Provider string
+	// BaseCurrency is the currency of fiat values of received payments and
+	// payments sent by tenants not in Tenants
+	BaseCurrency string `mapstructure:"base_currency"`
+	Tenants      []TenantCurrency
+	// Fixed is the table of rates of "fixed" provider
+	Fixed []FixedRate
+	// URL of the rates API of "http" provider
+	URL string
+	// Timeout of requests to the rates API (default "5s")
+	Timeout string
+	// CacheTTL is how long rates of the API are reused (default "1m")
+	CacheTTL string `mapstructure:"cache_ttl"`
+}
+
+// TenantCurrency is a base currency of a tenant
+type TenantCurrency struct {
+	Tenant       string
+	BaseCurrency string `mapstructure:"base_currency"`
+}
+
+// FixedRate is a price of one unit of an asset (XLM for lumens) in a
+// currency. Rates without AssetIssuer match all issuers of AssetCode.
+type FixedRate struct {
+	AssetCode   string `mapstructure:"asset_code"`
+	AssetIssuer string `mapstructure:"asset_issuer"`
+	Currency    string
+	Rate        string
+}
+
+// TimeoutDuration returns Timeout or 5 seconds when it's not set
+func (r Rates) TimeoutDuration() time.Duration {
+	if r.Timeout == "" {
+		return 5 * time.Second
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(r.Timeout)
+	return duration
+}
+
+// CacheTTLOrDefault returns CacheTTL or 1 minute when it's not set
+func (r Rates) CacheTTLOrDefault() time.Duration {
+	if r.CacheTTL == "" {
+		return time.Minute
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(r.CacheTTL)
+	return duration
+}
+
+// TenantCurrencies returns base currencies by tenant
+func (r Rates) TenantCurrencies() map[string]string {
+	currencies := map[string]string{}
+	for _, tenant := range r.Tenants {
+		currencies[tenant.Tenant] = tenant.BaseCurrency
+	}
+	return currencies
+}
+
+func (r Rates) validate(databaseType string) error {
+	switch r.Provider {
+	case "":
+		return nil
+	case "fixed":
+		for _, rate := range r.Fixed {
+			if rate.AssetCode == "" || rate.Currency == "" {
+				return errors.New("rates.fixed.asset_code and currency params are required")
+			}
+			if rate.AssetIssuer != "" {
+				if _, err := keypair.Parse(rate.AssetIssuer); err != nil {
+					return errors.New("Invalid rates.fixed.asset_issuer param")
+				}
+			}
+			// Same as rates.ParseRate
+			if value, ok := new(big.Rat).SetString(rate.Rate); !ok || strings.ContainsAny(rate.Rate, "/eE") || value.Sign() <= 0 {
+				return errors.New("Invalid rates.fixed.rate param")
+			}
+		}
+	case "http":
+		if r.URL == "" {
+			return errors.New("rates.url param is required when rates.provider is http")
+		}
+		if _, err := url.Parse(r.URL); err != nil {
+			return errors.New("Cannot parse rates.url param")
+		}
+	default:
+		return errors.New("rates.provider param must be fixed or http")
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when rates.provider is set")
+	}
+
+	if r.BaseCurrency == "" {
+		return errors.New("rates.base_currency param is required when rates.provider is set")
+	}
+
+	for _, tenant := range r.Tenants {
+		if tenant.Tenant == "" || tenant.BaseCurrency == "" {
+			return errors.New("rates.tenants.tenant and base_currency params are required")
+		}
+	}
+
+	if r.Timeout != "" {
+		if value, err := time.ParseDuration(r.Timeout); err != nil || value <= 0 {
+			return errors.New("Cannot parse rates.timeout param")
+		}
+	}
+
+	if r.CacheTTL != "" {
+		if value, err := time.ParseDuration(r.CacheTTL); err != nil || value < 0 {
+			return errors.New("Cannot parse rates.cache_ttl param")
+		}
+	}
+
+	return nil
+}
+
+// Fees contains values of `fees` config group: fee rules of payments sent
+// using /payment
+type Fees struct {
+	// Account receives fees in a second payment operation of the transaction
+	Account string
+	Rules   []FeeRule
+}
+
+// FeeRule is a flat and percentage fee of payments of an asset (XLM for
+// lumens), optionally limited to an issuer and a tenant
+type FeeRule struct {
+	AssetCode   string `mapstructure:"asset_code"`
+	AssetIssuer string `mapstructure:"asset_issuer"`
+	Tenant      string
+	Flat        string
+	// Percentage of the amount, ex. "0.5" for 0.5%
+	Percentage string
+	// Mode is "add" (default) or "deduct"
+	Mode string
+}
+
+// Schedule returns the fee schedule, nil when there are no rules
+func (f Fees) Schedule() *fees.Schedule {
+	if len(f.Rules) == 0 {
+		return nil
+	}
+
+	schedule := &fees.Schedule{Account: f.Account, Rules: make([]fees.Rule, len(f.Rules))}
+	for i, rule := range f.Rules {
+		// Values are checked in Validate
+		schedule.Rules[i] = fees.Rule{
+			AssetCode:   rule.AssetCode,
+			AssetIssuer: rule.AssetIssuer,
+			Tenant:      rule.Tenant,
+			Mode:        fees.ModeAdd,
+		}
+		if rule.Flat != "" {
+			schedule.Rules[i].Flat = amounts.MustParse(rule.Flat)
+		}
+		if rule.Percentage != "" {
+			schedule.Rules[i].Percentage, _ = new(big.Rat).SetString(rule.Percentage)
+		}
+		if rule.Mode != "" {
+			schedule.Rules[i].Mode = rule.Mode
+		}
+	}
+	return schedule
+}
+
+func (f Fees) validate() error {
+	if f.Account != "" {
+		if _, err := keypair.Parse(f.Account); err != nil || !strings.HasPrefix(f.Account, "G") {
+			return errors.New("Invalid fees.account param")
+		}
+	}
+
+	for _, rule := range f.Rules {
+		if rule.AssetCode == "" {
+			return errors.New("fees.rules.asset_code param is required")
+		}
+
+		if rule.AssetIssuer != "" {
+			if _, err := keypair.Parse(rule.AssetIssuer); err != nil {
+				return errors.New("Invalid fees.rules.asset_issuer param")
+			}
+		}
+
+		if rule.Flat != "" {
+			if value, err := amounts.Parse(rule.Flat); err != nil || value < 0 {
+				return errors.New("Invalid fees.rules.flat param")
+			}
+		}
+
+		if rule.Percentage != "" {
+			value, ok := new(big.Rat).SetString(rule.Percentage)
+			if !ok || strings.ContainsAny(rule.Percentage, "/eE") || value.Sign() < 0 || value.Cmp(big.NewRat(100, 1)) >= 0 {
+				return errors.New("Invalid fees.rules.percentage param")
+			}
+		}
+
+		if rule.Flat == "" && rule.Percentage == "" {
+			return errors.New("fees.rules.flat or percentage param is required")
+		}
+
+		switch rule.Mode {
+		case "", fees.ModeAdd:
+			if f.Account == "" {
+				return errors.New("fees.account param is required when fees are added to payments")
+			}
+		case fees.ModeDeduct:
+		default:
+			return errors.New("fees.rules.mode param must be add or deduct")
+		}
+	}
+
+	return nil
+}
+
+// Webhooks contains values of `webhooks` config group. Defaults are used by
+// webhooks registered without their own retry policy.
+type Webhooks struct {
+	Enabled bool
+	// Timeout of requests to webhooks (default "10s")
+	Timeout string
+	// MaxAttempts is the default number of delivery attempts (default 5)
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryInterval is the default time before the first retry, doubled
+	// after every failed attempt (default "30s")
+	RetryInterval string `mapstructure:"retry_interval"`
+}
+
+// TimeoutDuration returns Timeout or 10 seconds when it's not set
+func (w Webhooks) TimeoutDuration() time.Duration {
+	if w.Timeout == "" {
+		return 10 * time.Second
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(w.Timeout)
+	return duration
+}
+
+// MaxAttemptsOrDefault returns MaxAttempts or 5 when it's not set
+func (w Webhooks) MaxAttemptsOrDefault() int {
+	if w.MaxAttempts == 0 {
+		return 5
+	}
+	return w.MaxAttempts
+}
+
+// RetryIntervalOrDefault returns RetryInterval or 30 seconds when it's not set
+func (w Webhooks) RetryIntervalOrDefault() time.Duration {
+	if w.RetryInterval == "" {
+		return 30 * time.Second
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(w.RetryInterval)
+	return duration
+}
+
+func (w Webhooks) validate(databaseType string) error {
+	if !w.Enabled {
+		return nil
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when webhooks.enabled is set")
+	}
+
+	if w.Timeout != "" {
+		if value, err := time.ParseDuration(w.Timeout); err != nil || value <= 0 {
+			return errors.New("Cannot parse webhooks.timeout param")
+		}
+	}
+
+	if w.MaxAttempts < 0 {
+		return errors.New("webhooks.max_attempts param cannot be negative")
+	}
+
+	if w.RetryInterval != "" {
+		if value, err := time.ParseDuration(w.RetryInterval); err != nil || value < 0 {
+			return errors.New("Cannot parse webhooks.retry_interval param")
+		}
+	}
+
+	return nil
+}
+
+// MemoReferences contains values of `memo_references` config group
+type MemoReferences struct {
+	// Key is a base64 encoded key of at least 32 bytes used to derive memos
+	// of references, memo references are disabled when empty
+	Key string `mapstructure:"key" secret:""`
+	// PlaintextMemos is "allow" (default) to keep sending and receiving
+	// references in text and ID memos while flows are migrated, or "deny"
+	PlaintextMemos string `mapstructure:"plaintext_memos"`
+}
+
+// Enabled returns true when memo references are enabled
+func (m MemoReferences) Enabled() bool {
+	return m.Key != ""
+}
+
+// KeyBytes returns decoded Key
+func (m MemoReferences) KeyBytes() []byte {
+	// Values are checked in Validate
+	key, _ := base64.StdEncoding.DecodeString(m.Key)
+	return key
+}
+
+// AllowsPlaintext returns true when references can be sent in text and ID
+// memos
+func (m MemoReferences) AllowsPlaintext() bool {
+	return m.PlaintextMemos != "deny"
+}
+
+func (m MemoReferences) validate(databaseType string) error {
+	if !m.Enabled() {
+		return nil
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when memo_references.key is set")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(m.Key)
+	if err != nil || len(key) < 32 {
+		return errors.New("memo_references.key param must be a base64 encoded key of at least 32 bytes")
+	}
+
+	switch m.PlaintextMemos {
+	case "", "allow", "deny":
+	default:
+		return errors.New("memo_references.plaintext_memos param must be allow or deny")
+	}
+
+	return nil
+}
+
+// BalanceMonitor contains values of `balance_monitor` config group
+type BalanceMonitor struct {
+	// Interval between checks (default "5m")
+	Interval string
+	// BaseReserve of the network used to compute reserves of accounts
+	// (default "0.5", the base reserve of the public network)
+	BaseReserve string `mapstructure:"base_reserve"`
+	Accounts    []MonitoredAccount
+	Email       AlertEmail
+}
+
+// MonitoredAccount contains values of `balance_monitor.accounts` config group
+type MonitoredAccount struct {
+	AccountID string `mapstructure:"account_id"`
+	Assets    []MonitoredAsset
+	// FundingSeed is a secret seed of the account sending top-up payments of
+	// assets with TopUpTo
+	FundingSeed string `mapstructure:"funding_seed" secret:""`
+}
+
+// MonitoredAsset contains values of `balance_monitor.accounts.assets` config
+// group. Empty Code is the native asset.
+type MonitoredAsset struct {
+	Code   string
+	Issuer string
+	// Min is the min balance, the reserve of the account is not included in
+	// balances of the native asset
+	Min string
+	// MinLimitRoom is the min amount the trustline can still receive before
+	// it's full, not checked when empty
+	MinLimitRoom string `mapstructure:"min_limit_room"`
+	// TopUpTo is the available balance restored by a top-up payment from
+	// the funding account when the balance is below Min, no top-ups when
+	// empty
+	TopUpTo string `mapstructure:"top_up_to"`
+}
+
+// AlertEmail contains values of `balance_monitor.email` config group. Alerts
+// are not sent by email when SMTPAddress is empty.
+type AlertEmail struct {
+	// SMTPAddress is host:port of the SMTP server, ex. "smtp.example.com:587"
+	SMTPAddress string `mapstructure:"smtp_address"`
+	Username    string
+	Password    string `secret:""`
+	From        string
+	To          []string
+}
+
+// Enabled returns true when any accounts are monitored
+func (b BalanceMonitor) Enabled() bool {
+	return len(b.Accounts) > 0
+}
+
+// IntervalDuration returns Interval or 5 minutes when it's not set
+func (b BalanceMonitor) IntervalDuration() time.Duration {
+	if b.Interval == "" {
+		return 5 * time.Minute
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(b.Interval)
+	return duration
+}
+
+// BaseReserveStroops returns BaseReserve in stroops or 0 when it's empty
+func (b BalanceMonitor) BaseReserveStroops() int64 {
+	// Values are checked in Validate
+	stroops, _ := amounts.Parse(b.BaseReserve)
+	return stroops
+}
+
+// Thresholds returns monitored balances. Values are checked in Validate.
+func (b BalanceMonitor) Thresholds() []balances.Threshold {
+	var thresholds []balances.Threshold
+	for _, account := range b.Accounts {
+		for _, asset := range account.Assets {
+			min, _ := amounts.Parse(asset.Min)
+			var room, topUpTo int64
+			if asset.MinLimitRoom != "" {
+				room, _ = amounts.Parse(asset.MinLimitRoom)
+			}
+			if asset.TopUpTo != "" {
+				topUpTo, _ = amounts.Parse(asset.TopUpTo)
+			}
+			thresholds = append(thresholds, balances.Threshold{
+				AccountID:    account.AccountID,
+				AssetCode:    asset.Code,
+				AssetIssuer:  asset.Issuer,
+				Min:          min,
+				MinLimitRoom: room,
+				TopUpTo:      topUpTo,
+				FundingSeed:  account.FundingSeed,
+			})
+		}
+	}
+	return thresholds
+}
+
+func (b BalanceMonitor) validate(databaseType string) error {
+	if !b.Enabled() {
+		return nil
+	}
+
+	if b.Interval != "" {
+		if value, err := time.ParseDuration(b.Interval); err != nil || value <= 0 {
+			return errors.New("Cannot parse balance_monitor.interval param")
+		}
+	}
+
+	if b.BaseReserve != "" {
+		if value, err := amounts.Parse(b.BaseReserve); err != nil || value == 0 {
+			return errors.New("Cannot parse balance_monitor.base_reserve param")
+		}
+	}
+
+	for _, account := range b.Accounts {
+		if _, err := keypair.Parse(account.AccountID); err != nil || !strings.HasPrefix(account.AccountID, "G") {
+			return errors.New("Invalid balance_monitor.accounts.account_id param")
+		}
+
+		if len(account.Assets) == 0 {
+			return errors.New("balance_monitor.accounts.assets param is required for " + account.AccountID)
+		}
+
+		if account.FundingSeed != "" {
+			if _, err := keypair.Parse(account.FundingSeed); err != nil || IsPublicKeyOnly(account.FundingSeed) {
+				return errors.New("Invalid balance_monitor.accounts.funding_seed param")
+			}
+		}
+
+		for _, asset := range account.Assets {
+			if (asset.Code == "") != (asset.Issuer == "") {
+				return errors.New("balance_monitor.accounts.assets require both code and issuer params or none for native asset")
+			}
+
+			min, err := amounts.Parse(asset.Min)
+			if err != nil {
+				return errors.New("Cannot parse balance_monitor.accounts.assets.min param")
+			}
+
+			if asset.TopUpTo != "" {
+				if databaseType == "" {
+					return errors.New("database is required when balance_monitor.accounts.assets.top_up_to is set")
+				}
+				if account.FundingSeed == "" {
+					return errors.New("balance_monitor.accounts.funding_seed param is required when balance_monitor.accounts.assets.top_up_to is set")
+				}
+				if value, err := amounts.Parse(asset.TopUpTo); err != nil || value <= min {
+					return errors.New("balance_monitor.accounts.assets.top_up_to param must be an amount greater than min")
+				}
+			}
+
+			if asset.MinLimitRoom != "" {
+				if asset.Code == "" {
+					return errors.New("balance_monitor.accounts.assets.min_limit_room param cannot be set for native asset")
+				}
+				if _, err := amounts.Parse(asset.MinLimitRoom); err != nil {
+					return errors.New("Cannot parse balance_monitor.accounts.assets.min_limit_room param")
+				}
+			}
+		}
+	}
+
+	if b.Email.SMTPAddress != "" {
+		if _, _, err := net.SplitHostPort(b.Email.SMTPAddress); err != nil {
+			return errors.New("balance_monitor.email.smtp_address param must be host:port")
+		}
+		if b.Email.From == "" || len(b.Email.To) == 0 {
+			return errors.New("balance_monitor.email.from and balance_monitor.email.to params are required when balance_monitor.email.smtp_address is set")
+		}
+	}
+
+	return nil
+}
+
+// HoldsPayments returns true when payments can be held by settlement delay,
+// until they are approved or by risk hooks
+func (c *Config) HoldsPayments() bool {
+	return c.Settlement.Enabled() || c.Approval.Enabled || c.Risk.Enabled()
+}
+
+// Risk contains values of `risk` config group: pre-submission hooks of
+// payments sent using /payment
+type Risk struct {
+	// Velocity rules are checked before Hooks
+	Velocity []VelocityRule
+	Hooks    []RiskHook
+	// HoldDuration is the time payments held by a hook wait before they are
+	// submitted when the hook does not return hold_for (default "1h")
+	HoldDuration string `mapstructure:"hold_duration"`
+	// Timeout of requests to HTTP hooks (default "5s")
+	Timeout string
+	// FailOpen sends payments when a hook fails, they are rejected otherwise
+	FailOpen bool `mapstructure:"fail_open"`
+}
+
+// RiskHook is an HTTP hook (URL) or a Go plugin (Plugin)
+type RiskHook struct {
+	// Name is used in logs and errors, URL or Plugin when empty
+	Name string
+	URL  string
+	// Secret authenticates requests to the HTTP hook
+	Secret string `secret:""`
+	// Plugin is a path to a Go plugin exporting risk.Hook
+	Plugin string
+}
+
+// NameOrDefault returns Name or URL or Plugin when it's not set
+func (h RiskHook) NameOrDefault() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	if h.URL != "" {
+		return h.URL
+	}
+	return h.Plugin
+}
+
+// VelocityRule limits payments of a source account or to a destination in a
+// sliding window
+type VelocityRule struct {
+	// Scope is "source" or "destination"
+	Scope  string
+	Window string
+	// MaxCount and MaxAmount are limits of the number of payments and the sum
+	// of amounts in the window, MaxAmount requires AssetCode
+	MaxCount    int64  `mapstructure:"max_count"`
+	MaxAmount   string `mapstructure:"max_amount"`
+	AssetCode   string `mapstructure:"asset_code"`
+	AssetIssuer string `mapstructure:"asset_issuer"`
+	// Action is "deny" (default) or "hold"
+	Action string
+}
+
+// Enabled returns true when payments are checked by velocity rules or hooks
+func (r Risk) Enabled() bool {
+	return len(r.Velocity) > 0 || len(r.Hooks) > 0
+}
+
+// HoldDurationOrDefault returns HoldDuration or 1 hour when it's not set
+func (r Risk) HoldDurationOrDefault() time.Duration {
+	if r.HoldDuration == "" {
+		return time.Hour
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(r.HoldDuration)
+	return duration
+}
+
+// TimeoutDuration returns Timeout or 5 seconds when it's not set
+func (r Risk) TimeoutDuration() time.Duration {
+	if r.Timeout == "" {
+		return 5 * time.Second
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(r.Timeout)
+	return duration
+}
+
+// VelocityRules returns velocity rules of risk.VelocityChecker
+func (r Risk) VelocityRules() []risk.VelocityRule {
+	rules := make([]risk.VelocityRule, len(r.Velocity))
+	for i, rule := range r.Velocity {
+		// Values are checked in Validate
+		window, _ := time.ParseDuration(rule.Window)
+		rules[i] = risk.VelocityRule{
+			Scope:       rule.Scope,
+			Window:      window,
+			MaxCount:    rule.MaxCount,
+			AssetCode:   rule.AssetCode,
+			AssetIssuer: rule.AssetIssuer,
+			Decision:    risk.DecisionDeny,
+		}
+		if rule.MaxAmount != "" {
+			rules[i].MaxAmount = amounts.MustParse(rule.MaxAmount)
+		}
+		if rule.Action == "hold" {
+			rules[i].Decision = risk.DecisionHold
+		}
+	}
+	return rules
+}
+
+func (r Risk) validate(databaseType string) error {
+	if !r.Enabled() {
+		return nil
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when risk.velocity or risk.hooks is set")
+	}
+
+	for _, rule := range r.Velocity {
+		if rule.Scope != risk.ScopeSource && rule.Scope != risk.ScopeDestination {
+			return errors.New("risk.velocity.scope param must be source or destination")
+		}
+
+		if window, err := time.ParseDuration(rule.Window); err != nil || window <= 0 {
+			return errors.New("Cannot parse risk.velocity.window param")
+		}
+
+		if rule.MaxCount < 0 {
+			return errors.New("risk.velocity.max_count param cannot be negative")
+		}
+
+		if rule.MaxAmount != "" {
+			if value, err := amounts.Parse(rule.MaxAmount); err != nil || value <= 0 {
+				return errors.New("Invalid risk.velocity.max_amount param")
+			}
+			if rule.AssetCode == "" {
+				return errors.New("risk.velocity.asset_code param is required when max_amount is set")
+			}
+		}
+
+		if rule.MaxCount == 0 && rule.MaxAmount == "" {
+			return errors.New("risk.velocity.max_count or max_amount param is required")
+		}
+
+		if rule.AssetIssuer != "" {
+			if _, err := keypair.Parse(rule.AssetIssuer); err != nil {
+				return errors.New("Invalid risk.velocity.asset_issuer param")
+			}
+		}
+
+		if rule.Action != "" && rule.Action != "deny" && rule.Action != "hold" {
+			return errors.New("risk.velocity.action param must be deny or hold")
+		}
+	}
+
+	for _, hook := range r.Hooks {
+		if (hook.URL == "") == (hook.Plugin == "") {
+			return errors.New("risk.hooks requires exactly one of url and plugin params")
+		}
+
+		if hook.URL != "" {
+			if _, err := url.Parse(hook.URL); err != nil {
+				return errors.New("Cannot parse risk.hooks.url param")
+			}
+		}
+	}
+
+	if r.HoldDuration != "" {
+		if value, err := time.ParseDuration(r.HoldDuration); err != nil || value <= 0 {
+			return errors.New("Cannot parse risk.hold_duration param")
+		}
+	}
+
+	if r.Timeout != "" {
+		if value, err := time.ParseDuration(r.Timeout); err != nil || value <= 0 {
+			return errors.New("Cannot parse risk.timeout param")
+		}
+	}
+
+	return nil
+}
+
+// CreateAccount contains values of `create_account` config group
+type CreateAccount struct {
+	// StartingBalance is the XLM balance of created accounts (default "2")
+	StartingBalance string `mapstructure:"starting_balance"`
+	// MaxStartingBalance is the max balance requested in `starting_balance`
+	// param, StartingBalance when not set
+	MaxStartingBalance string `mapstructure:"max_starting_balance"`
+}
+
+// StartingBalanceOrDefault returns StartingBalance or 2 XLM when it's not set
+func (c CreateAccount) StartingBalanceOrDefault() string {
+	if c.StartingBalance == "" {
+		return "2"
+	}
+	return c.StartingBalance
+}
+
+// MaxStartingBalanceOrDefault returns MaxStartingBalance or the starting
+// balance when it's not set
+func (c CreateAccount) MaxStartingBalanceOrDefault() string {
+	if c.MaxStartingBalance == "" {
+		return c.StartingBalanceOrDefault()
+	}
+	return c.MaxStartingBalance
+}
+
+func (c CreateAccount) validate() error {
+	for _, param := range []struct{ name, value string }{
+		{"starting_balance", c.StartingBalance},
+		{"max_starting_balance", c.MaxStartingBalance},
+	} {
+		if param.value == "" {
+			continue
+		}
+
+		value, err := amounts.Parse(param.value)
+		if err != nil || value <= 0 {
+			return errors.New("Invalid create_account." + param.name + " param")
+		}
+	}
+
+	if amounts.MustParse(c.StartingBalanceOrDefault()) > amounts.MustParse(c.MaxStartingBalanceOrDefault()) {
+		return errors.New("create_account.starting_balance is greater than create_account.max_starting_balance")
+	}
+
+	return nil
+}
+
+// validate checks the group. Subscribers must be authenticated, so api_key or
+// auth is required.
+func (c Stream) validate(databaseType string, authenticated bool) error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when stream.enabled is set")
+	}
+
+	if !authenticated {
+		return errors.New("api_key or auth.keys param is required when stream.enabled is set")
+	}
+
+	if c.MaxSubscribers < 0 {
+		return errors.New("stream.max_subscribers param cannot be negative")
+	}
+
+	if c.PollInterval != "" {
+		if value, err := time.ParseDuration(c.PollInterval); err != nil || value <= 0 {
+			return errors.New("Cannot parse stream.poll_interval param")
+		}
+	}
+
+	if c.Heartbeat != "" {
+		if value, err := time.ParseDuration(c.Heartbeat); err != nil || value <= 0 {
+			return errors.New("Cannot parse stream.heartbeat param")
+		}
+	}
+
+	return nil
+}
+
+// HTTP contains values of `http` config group: timeouts and worker pools of
+// the listeners
+type HTTP struct {
+	// API limits the payment API listener
+	API server.Limits
+	// Admin limits the admin listener, used only when Admin is enabled
+	Admin server.Limits
+}
+
+func (c HTTP) validate() error {
+	err := c.API.Validate("http.api")
+	if err != nil {
+		return err
+	}
+	return c.Admin.Validate("http.admin")
+}
+
+// Timeouts contains values of `timeouts` config group: timeouts of stages of
+// handling a payment, ex. "5s". Stages are not limited when empty, but they
+// are still abandoned when the client disconnects or http.api.request_timeout
+// passes.
+type Timeouts struct {
+	// Federation limits resolving the destination, including stellar.toml
+	Federation string
+	// Horizon limits loading accounts before the transaction is submitted
+	Horizon string
+	// Compliance limits sending the payment to the compliance server
+	Compliance string
+}
+
+// Stage returns the timeout of a given stage or 0 when it's not limited
+func (c Timeouts) Stage(name string) time.Duration {
+	var value string
+	switch name {
+	case stage.Federation:
+		value = c.Federation
+	case stage.Horizon:
+		value = c.Horizon
+	case stage.Compliance:
+		value = c.Compliance
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(value)
+	return duration
+}
+
+func (c Timeouts) validate() error {
+	durations := []struct {
+		name  string
+		value string
+	}{
+		{"timeouts.federation", c.Federation},
+		{"timeouts.horizon", c.Horizon},
+		{"timeouts.compliance", c.Compliance},
+	}
+
+	for _, duration := range durations {
+		if duration.value == "" {
+			continue
+		}
+
+		value, err := time.ParseDuration(duration.value)
+		if err != nil || value <= 0 {
+			return errors.New("Cannot parse " + duration.name + " param")
+		}
+	}
+
+	return nil
+}
+
+// Shutdown contains values of `shutdown` config group
+type Shutdown struct {
+	// DrainTimeout is a hard deadline of finishing requests and work in
+	// progress after the server receives SIGTERM, ex. "30s"
+	DrainTimeout string `mapstructure:"drain_timeout"`
+}
+
+// DrainTimeoutDuration returns parsed DrainTimeout or 30 seconds when not set
+func (c Shutdown) DrainTimeoutDuration() time.Duration {
+	// Value is checked in Validate
+	if c.DrainTimeout == "" {
+		return 30 * time.Second
+	}
+	duration, _ := time.ParseDuration(c.DrainTimeout)
+	return duration
+}
+
+func (c Shutdown) validate() error {
+	if c.DrainTimeout == "" {
+		return nil
+	}
+
+	if value, err := time.ParseDuration(c.DrainTimeout); err != nil || value <= 0 {
+		return errors.New("Cannot parse shutdown.drain_timeout param")
+	}
+	return nil
+}
+
+// Leader election backends
+const (
+	// LeaderElectionDatabase keeps the lease in LeaderLease table of the
+	// bridge database
+	LeaderElectionDatabase = "database"
+	// LeaderElectionEtcd keeps the lease in etcd
+	LeaderElectionEtcd = "etcd"
+)
+
+// LeaderElection contains values of `leader_election` config group. Replicas
+// compete for a lease renewed every RenewInterval, the holder is the leader
+// until it fails to renew it for LeaseDuration.
+type LeaderElection struct {
+	// Backend is "database" or "etcd", leader election is disabled when empty
+	Backend string
+	// ID identifies this replica in the lease, hostname is used when empty
+	ID string
+	// Name of the lease, replicas of different deployments sharing a
+	// database or etcd cluster must use different names. "bridge" by default.
+	Name string
+	// LeaseDuration is the time after which a lease not renewed can be
+	// acquired by another replica, ex. "15s"
+	LeaseDuration string `mapstructure:"lease_duration"`
+	// RenewInterval is the interval of renewing the lease (or trying to
+	// acquire it by followers), ex. "5s"
+	RenewInterval string `mapstructure:"renew_interval"`
+	// EtcdEndpoints are URLs of etcd v3 HTTP gateway, ex.
+	// "http://etcd:2379"
+	EtcdEndpoints []string `mapstructure:"etcd_endpoints"`
+}
+
+// Enabled returns true if leader election is configured
+func (c LeaderElection) Enabled() bool {
+	return c.Backend != ""
+}
+
+// NameOrDefault returns Name or "bridge" when not set
+func (c LeaderElection) NameOrDefault() string {
+	if c.Name == "" {
+		return "bridge"
+	}
+	return c.Name
+}
+
+// LeaseDurationDuration returns parsed LeaseDuration or 15 seconds when not
+// set
+func (c LeaderElection) LeaseDurationDuration() time.Duration {
+	// Value is checked in Validate
+	if c.LeaseDuration == "" {
+		return 15 * time.Second
+	}
+	duration, _ := time.ParseDuration(c.LeaseDuration)
+	return duration
+}
+
+// RenewIntervalDuration returns parsed RenewInterval or a third of the lease
+// duration when not set
+func (c LeaderElection) RenewIntervalDuration() time.Duration {
+	// Value is checked in Validate
+	if c.RenewInterval == "" {
+		return c.LeaseDurationDuration() / 3
+	}
+	duration, _ := time.ParseDuration(c.RenewInterval)
+	return duration
+}
+
+func (c LeaderElection) validate() error {
+	switch c.Backend {
+	case "":
+		return nil
+	case LeaderElectionDatabase:
+	case LeaderElectionEtcd:
+		if len(c.EtcdEndpoints) == 0 {
+			return errors.New("leader_election.etcd_endpoints param is required")
+		}
+		for _, endpoint := range c.EtcdEndpoints {
+			if _, err := url.ParseRequestURI(endpoint); err != nil {
+				return errors.New("Cannot parse leader_election.etcd_endpoints param")
+			}
+		}
+	default:
+		return errors.New("Invalid leader_election.backend param")
+	}
+
+	durations := []struct {
+		name  string
+		value string
+	}{
+		{"lease_duration", c.LeaseDuration},
+		{"renew_interval", c.RenewInterval},
+	}
+	for _, duration := range durations {
+		if duration.value == "" {
+			continue
+		}
+		if value, err := time.ParseDuration(duration.value); err != nil || value <= 0 {
+			return fmt.Errorf("Cannot parse leader_election.%s param", duration.name)
+		}
+	}
+
+	if c.LeaseDurationDuration() < time.Second {
+		return errors.New("leader_election.lease_duration param must be at least 1s")
+	}
+
+	if c.RenewIntervalDuration() >= c.LeaseDurationDuration() {
+		return errors.New("leader_election.renew_interval param must be shorter than lease_duration")
+	}
+	return nil
+}
+
+// Redis contains values of `redis` config group
+type Redis struct {
+	// URL of Redis server: redis://[:password@]host[:port][/db] or
+	// rediss:// for TLS. Redis is not used when empty.
+	URL string
+	// KeyPrefix is prepended to all keys, "bridge:" by default
+	KeyPrefix string `mapstructure:"key_prefix"`
+	// PoolSize is the max number of idle connections, 10 by default
+	PoolSize int `mapstructure:"pool_size"`
+	// Timeout limits every command, ex. "1s"
+	Timeout string
+	// IdempotencyTTL is the time payment IDs are kept, ex. "24h"
+	IdempotencyTTL string `mapstructure:"idempotency_ttl"`
+}
+
+// Enabled returns true if Redis is configured
+func (c Redis) Enabled() bool {
+	return c.URL != ""
+}
+
+// KeyPrefixOrDefault returns KeyPrefix or "bridge:" when not set
+func (c Redis) KeyPrefixOrDefault() string {
+	if c.KeyPrefix == "" {
+		return "bridge:"
+	}
+	return c.KeyPrefix
+}
+
+// PoolSizeOrDefault returns PoolSize or 10 when not set
+func (c Redis) PoolSizeOrDefault() int {
+	if c.PoolSize == 0 {
+		return 10
+	}
+	return c.PoolSize
+}
+
+// TimeoutDuration returns parsed Timeout or 1 second when not set
+func (c Redis) TimeoutDuration() time.Duration {
+	// Value is checked in Validate
+	if c.Timeout == "" {
+		return time.Second
+	}
+	duration, _ := time.ParseDuration(c.Timeout)
+	return duration
+}
+
+// IdempotencyTTLDuration returns parsed IdempotencyTTL or 24 hours when not
+// set
+func (c Redis) IdempotencyTTLDuration() time.Duration {
+	// Value is checked in Validate
+	if c.IdempotencyTTL == "" {
+		return 24 * time.Hour
+	}
+	duration, _ := time.ParseDuration(c.IdempotencyTTL)
+	return duration
+}
+
+func (c Redis) validate() error {
+	if c.URL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+		return errors.New("Cannot parse redis.url param")
+	}
+
+	if c.PoolSize < 0 {
+		return errors.New("redis.pool_size param must be positive")
+	}
+
+	durations := []struct {
+		name  string
+		value string
+	}{
+		{"timeout", c.Timeout},
+		{"idempotency_ttl", c.IdempotencyTTL},
+	}
+	for _, duration := range durations {
+		if duration.value == "" {
+			continue
+		}
+		if value, err := time.ParseDuration(duration.value); err != nil || value <= 0 {
+			return fmt.Errorf("Cannot parse redis.%s param", duration.name)
+		}
+	}
+	return nil
+}
+
+// Access contains values of `access` config group. Networks are in CIDR
+// notation (ex. "10.0.0.0/8") or single IP addresses.
+type Access struct {
+	// Allow contains networks allowed to send requests, all are allowed when
+	// empty
+	Allow []string
+	// Deny contains networks not allowed to send requests
+	Deny []string
+	// TrustedProxies contains networks of proxies whose X-Forwarded-For
+	// header is used to find client IP addresses
+	TrustedProxies []string    `mapstructure:"trusted_proxies"`
+	RateLimits     []RateLimit `mapstructure:"rate_limits"`
+}
+
+// RateLimit contains values of `access.rate_limits` config group
+type RateLimit struct {
+	Path string
+	// RateLimit is the max number of requests per minute
+	RateLimit int `mapstructure:"rate_limit"`
+	// Key is "ip" (default) or "api_key"
+	Key string
+}
+
+// IPFilterEnabled returns true when IP addresses of clients are checked
+func (c Access) IPFilterEnabled() bool {
+	return len(c.Allow) > 0 || len(c.Deny) > 0
+}
+
+// Networks returns parsed Allow, Deny and TrustedProxies networks
+func (c Access) Networks() (allow, deny, trustedProxies access.Networks) {
+	// Values are checked in Validate
+	allow, _ = access.ParseNetworks(c.Allow)
+	deny, _ = access.ParseNetworks(c.Deny)
+	trustedProxies, _ = access.ParseNetworks(c.TrustedProxies)
+	return
+}
+
+// Rules returns RateLimits as access.Rule
+func (c Access) Rules() []access.Rule {
+	rules := make([]access.Rule, 0, len(c.RateLimits))
+	for _, limit := range c.RateLimits {
+		key := limit.Key
+		if key == "" {
+			key = access.KeyIP
+		}
+		rules = append(rules, access.Rule{Path: limit.Path, RateLimit: limit.RateLimit, Key: key})
+	}
+	return rules
+}
+
+func (c Access) validate() error {
+	for name, values := range map[string][]string{
+		"allow":           c.Allow,
+		"deny":            c.Deny,
+		"trusted_proxies": c.TrustedProxies,
+	} {
+		if _, err := access.ParseNetworks(values); err != nil {
+			return errors.New("Invalid access." + name + " param: " + err.Error())
+		}
+	}
+
+	for _, limit := range c.RateLimits {
+		if !strings.HasPrefix(limit.Path, "/") {
+			return errors.New("Invalid access.rate_limits.path param: " + limit.Path)
+		}
+		if limit.RateLimit <= 0 {
+			return errors.New("access.rate_limits.rate_limit param must be positive")
+		}
+		switch limit.Key {
+		case "", access.KeyIP, access.KeyAPIKey:
+		default:
+			return errors.New("Invalid access.rate_limits.key param: " + limit.Key)
+		}
+	}
+
+	return nil
+}
+
+// Incidents contains values of `incidents` config group. Diagnostic bundles
+// are captured when Directory and/or S3 bucket is set.
+type Incidents struct {
+	Directory string
+	S3        ExportS3
+	// SubmissionFailures is the number of consecutive failed transaction
+	// submissions that starts capture (default 5)
+	SubmissionFailures int `mapstructure:"submission_failures"`
+	// Cooldown is the min time between bundles of the same trigger (default "15m")
+	Cooldown string
+	// LogLines is the number of recent log entries in a bundle (default 1000)
+	LogLines int `mapstructure:"log_lines"`
+}
+
+// Enabled returns true when diagnostic bundles should be captured
+func (c Incidents) Enabled() bool {
+	return c.Directory != "" || c.S3.Bucket != ""
+}
+
+// SubmissionFailuresOrDefault returns SubmissionFailures or 5 when it's not set
+func (c Incidents) SubmissionFailuresOrDefault() int {
+	if c.SubmissionFailures == 0 {
+		return 5
+	}
+	return c.SubmissionFailures
+}
+
+// CooldownDuration returns Cooldown duration or 15 minutes when it's not set
+func (c Incidents) CooldownDuration() time.Duration {
+	if c.Cooldown == "" {
+		return 15 * time.Minute
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(c.Cooldown)
+	return duration
+}
+
+// LogLinesOrDefault returns LogLines or 1000 when it's not set
+func (c Incidents) LogLinesOrDefault() int {
+	if c.LogLines == 0 {
+		return 1000
+	}
+	return c.LogLines
+}
+
+// Profiles contains values of `profiles` config group. Payments to
+// destinations matching a built-in or local profile (or assigned a profile in
+// memo required destinations) are rejected when they don't meet it.
+type Profiles struct {
+	// Check enables checking payments against profiles
+	Check bool
+	// Directory contains local *.json profile files overriding built-in
+	// profiles. `bridge profiles update` writes downloaded profiles there.
+	Directory string
+	// URL is the default source of `bridge profiles update`
+	URL string
+}
+
+func (c Profiles) validate() error {
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("Cannot parse profiles.url param")
+		}
+	}
+	return nil
+}
+
+func (c Incidents) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	if c.S3.Bucket != "" && c.S3.Region == "" {
+		return errors.New("incidents.s3.region param is required when incidents.s3.bucket is set")
+	}
+
+	if c.SubmissionFailures < 0 {
+		return errors.New("incidents.submission_failures param cannot be negative")
+	}
+
+	if c.Cooldown != "" {
+		if value, err := time.ParseDuration(c.Cooldown); err != nil || value < 0 {
+			return errors.New("Cannot parse incidents.cooldown param")
+		}
+	}
+
+	if c.LogLines < 0 {
+		return errors.New("incidents.log_lines param cannot be negative")
+	}
+
+	return nil
+}
+
+// Audit contains values of `audit` config group. Security events
+// (authentication failures, limit violations, admin actions and key usage)
+// are sent to a SIEM endpoint when URL is set.
+type Audit struct {
+	URL string
+	// Format of events: "json" (default) or "cef"
+	Format string
+	// Secret used to authenticate requests to the SIEM endpoint. Requests are
+	// not signed when empty.
+	Secret string `secret:""`
+	// RetryInterval is how often undelivered events are sent again (default "30s")
+	RetryInterval string `mapstructure:"retry_interval"`
+}
+
+// Enabled returns true when security events should be sent
+func (a Audit) Enabled() bool {
+	return a.URL != ""
+}
+
+// FormatOrDefault returns Format or "json" when it's not set
+func (a Audit) FormatOrDefault() string {
+	if a.Format == "" {
+		return "json"
+	}
+	return a.Format
+}
+
+// RetryIntervalDuration returns RetryInterval duration or 30 seconds when
+// it's not set
+func (a Audit) RetryIntervalDuration() time.Duration {
+	if a.RetryInterval == "" {
+		return 30 * time.Second
+	}
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(a.RetryInterval)
+	return duration
+}
+
+func (a Audit) validate(databaseType string) error {
+	if !a.Enabled() {
+		return nil
+	}
+
+	if _, err := url.Parse(a.URL); err != nil {
+		return errors.New("Cannot parse audit.url param")
+	}
+
+	switch a.Format {
+	case "", "json", "cef":
+		break
+	default:
+		return errors.New("Invalid audit.format param")
+	}
+
+	if a.RetryInterval != "" {
+		if value, err := time.ParseDuration(a.RetryInterval); err != nil || value <= 0 {
+			return errors.New("Cannot parse audit.retry_interval param")
+		}
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when audit.url is set")
+	}
+
+	return nil
+}
+
+// Export contains values of `export` config group. Statements of received
+// and sent payments of the previous day are stored every day in Directory
+// and/or S3 bucket when any of them is set.
+type Export struct {
+	// Formats of stored statements: "csv" and/or "camt053" (default both)
+	Formats   []string
+	Directory string
+	S3        ExportS3
+}
+
+// ExportS3 contains values of `export.s3` config group
+type ExportS3 struct {
+	Bucket string
+	Region string
+	// Prefix is prepended to names of statement files, ex. "statements/"
+	Prefix string
+}
+
+// Enabled returns true when statements should be exported every day
+func (e Export) Enabled() bool {
+	return e.Directory != "" || e.S3.Bucket != ""
+}
+
+// FormatsOrDefault returns Formats or all formats when it's not set
+func (e Export) FormatsOrDefault() []string {
+	if len(e.Formats) == 0 {
+		return []string{"csv", "camt053"}
+	}
+	return e.Formats
+}
+
+func (e Export) validate(databaseType string) error {
+	if !e.Enabled() {
+		return nil
+	}
+
+	for _, format := range e.Formats {
+		if format != "csv" && format != "camt053" {
+			return errors.New("Invalid export.formats param: " + format)
+		}
+	}
+
+	if e.S3.Bucket != "" && e.S3.Region == "" {
+		return errors.New("export.s3.region param is required when export.s3.bucket is set")
+	}
+
+	if databaseType == "" {
+		return errors.New("database is required when export is enabled")
+	}
+
+	return nil
+}
+
+func (c Cache) validate() error {
+	if c.Size < 0 {
+		return errors.New("cache.size param must be positive")
+	}
+
+	if c.TTL != "" {
+		if _, err := time.ParseDuration(c.TTL); err != nil {
+			return errors.New("Cannot parse cache.ttl param")
+		}
+	}
+
+	if c.NegativeTTL != "" {
+		if !c.Enabled() {
+			return errors.New("cache.ttl param is required when cache.negative_ttl is set")
+		}
+
+		if _, err := time.ParseDuration(c.NegativeTTL); err != nil {
+			return errors.New("Cannot parse cache.negative_ttl param")
+		}
+	}
+
+	if c.AccountTTL != "" {
+		if value, err := time.ParseDuration(c.AccountTTL); err != nil || value <= 0 {
+			return errors.New("Cannot parse cache.account_ttl param")
+		}
+	}
+
+	return nil
+}
+
+// StellarToml contains values of `stellar_toml` config group used when
+// loading stellar.toml files of destination domains
+type StellarToml struct {
+	// RequireHTTPS rejects redirects to plain HTTP URLs
+	RequireHTTPS bool `mapstructure:"require_https"`
+	// CAFile is a PEM file with certificates used to verify certificate
+	// chains. System roots are used when empty.
+	CAFile string `mapstructure:"ca_file"`
+	// MaxSize of stellar.toml file in bytes (default 5KB)
+	MaxSize      int  `mapstructure:"max_size"`
+	MaxRedirects *int `mapstructure:"max_redirects"`
+}
+
+func (c StellarToml) validate() error {
+	if c.MaxSize < 0 {
+		return errors.New("stellar_toml.max_size param must be positive")
+	}
+
+	if c.MaxRedirects != nil && *c.MaxRedirects < 0 {
+		return errors.New("stellar_toml.max_redirects param must be positive")
+	}
+
+	return nil
+}
+
+// Snapshots contains values of `snapshots` config group
+type Snapshots struct {
+	// Federation records federation and stellar.toml responses used to
+	// resolve destinations, see snapshots package
+	Federation bool
+	// MaxEntries is a max number of responses kept in memory until they are
+	// used by a payment (default 10000)
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// Enabled returns true when federation snapshots are recorded
+func (c Snapshots) Enabled() bool {
+	return c.Federation
+}
+
+func (c Snapshots) validate(databaseType string) error {
+	if c.MaxEntries < 0 {
+		return errors.New("snapshots.max_entries param must be positive")
+	}
+
+	if c.Enabled() && databaseType == "" {
+		return errors.New("database is required when snapshots.federation is set")
+	}
+
+	return nil
+}
+
+// Feature contains values of a single `features` config entry. Flags can be
+// overridden per tenant using /admin/feature-flags endpoint.
+type Feature struct {
+	Name string
+	// Enabled turns the feature on for all tenants
+	Enabled bool
+	// Tenants is a list of tenants the feature is enabled for when Enabled is false
+	Tenants []string
+}
+
+// Region contains values of `region` config group. It's used when two bridge
+// clusters in different regions share (replicated) database and submit
+// transactions for disjoint sets of source accounts.
+type Region struct {
+	Name string
+	// Accounts is a list of source accounts this region submits transactions for.
+	// Payments from other accounts are rejected. All accounts are allowed when empty.
+	Accounts []string
+}
+
+// Enabled returns true if region is configured
+func (r Region) Enabled() bool {
+	return r.Name != ""
+}
+
+// Owns returns true if this region submits transactions for accountID
+func (r Region) Owns(accountID string) bool {
+	if len(r.Accounts) == 0 {
+		return true
+	}
+
+	for _, account := range r.Accounts {
+		if account == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// DatabasePoolOptions returns connection pool options of the database
+func (c Config) DatabasePoolOptions() db.PoolOptions {
+	// Values are checked in Validate
+	lifetime, _ := time.ParseDuration(c.Database.ConnMaxLifetime)
+	return db.PoolOptions{
+		MaxOpenConns:    c.Database.MaxOpenConns,
+		MaxIdleConns:    c.Database.MaxIdleConns,
+		ConnMaxLifetime: lifetime,
+	}
+}
+
+// Validate validates config and returns error if any of config values is incorrect
+func (c *Config) Validate() (err error) {
+	if c.Port == nil {
+		err = errors.New("port param is required")
+		return
+	}
+
+	if len(c.Horizon) == 0 {
+		err = errors.New("horizon param is required")
+		return
+	}
+
+	for _, horizonURL := range c.Horizon {
+		err = validateHTTPURL(horizonURL)
+		if err != nil {
+			err = errors.New("Cannot parse horizon param: " + horizonURL)
+			return
+		}
+	}
+
+	err = c.HorizonClient.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Submission.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Listener.validate()
+	if err != nil {
+		return
+	}
+
+	if c.NetworkPassphrase == "" {
+		err = errors.New("network_passphrase param is required")
+		return
+	}
+
+	assets := map[string]bool{}
+	for _, asset := range c.Assets {
+		if assets[asset.Code+":"+asset.Issuer] {
+			err = errors.New("Duplicate assets entry of " + asset.Code)
+			return
+		}
+		assets[asset.Code+":"+asset.Issuer] = true
+
+		if asset.Issuer == "" {
+			if asset.Code != "XLM" {
+				err = errors.New("Issuer param is required for " + asset.Code)
+				return
+			}
+		}
+
+		if asset.Issuer != "" {
+			_, err = keypair.Parse(asset.Issuer)
+			if err != nil {
+				err = errors.New("Issuing account is invalid for " + asset.Code)
+				return
+			}
+		}
+
+		matched, err := regexp.MatchString("^[a-zA-Z0-9]{1,12}$", asset.Code)
+		if err != nil {
+			return err
+		}
+
+		if !matched {
+			err = errors.New("Invalid asset code: " + asset.Code)
+			return err
+		}
+
+		err = asset.validateLimits()
+		if err != nil {
+			return err
+		}
+
+		if asset.HasDailyLimits() && c.Database.Type == "" {
+			return errors.New("database is required when daily limits of " + asset.Code + " are set")
+		}
+	}
+
+	var dbURL *url.URL
+	dbURL, err = url.Parse(c.Database.URL)
+	if err != nil {
+		err = errors.New("Cannot parse database.url param")
+		return
+	}
+
+	switch c.Database.Type {
+	case "mysql":
+		// Add `parseTime=true` param to mysql url
+		query := dbURL.Query()
+		query.Set("parseTime", "true")
+		dbURL.RawQuery = query.Encode()
+		c.Database.URL = dbURL.String()
+	case "postgres":
+		break
+	case "sqlite":
+		if c.Database.URL == "" {
+			err = errors.New("database.url param is required for sqlite database")
+			return
+		}
+	case "memory":
+		// In-memory SQLite database, url is not used
+		break
+	case "":
+		// Allow to start gateway server with a single endpoint: /payment
+		break
+	default:
+		err = errors.New("Invalid database.type param")
+		return
+	}
+
+	if c.Database.NotifyChannel != "" {
+		if c.Database.Type != "postgres" {
+			err = errors.New("database.notify_channel param requires postgres database")
+			return
+		}
+
+		if !notifyChannelRegexp.MatchString(c.Database.NotifyChannel) {
+			err = errors.New("Invalid database.notify_channel param")
+			return
+		}
+	}
+
+	if c.Database.MaxOpenConns < 0 {
+		err = errors.New("Invalid database.max_open_conns param")
+		return
+	}
+
+	if c.Database.MaxIdleConns < 0 {
+		err = errors.New("Invalid database.max_idle_conns param")
+		return
+	}
+
+	if c.Database.ConnMaxLifetime != "" {
+		var lifetime time.Duration
+		lifetime, err = time.ParseDuration(c.Database.ConnMaxLifetime)
+		if err != nil || lifetime <= 0 {
+			err = errors.New("Invalid database.conn_max_lifetime param")
+			return
+		}
+	}
+
+	if c.DatabasePoolOptions() != (db.PoolOptions{}) && (c.Database.Type == "sqlite" || c.Database.Type == "memory") {
+		// SQLite driver uses a single connection
+		err = errors.New("database connection pool params are not supported by sqlite database")
+		return
+	}
+
+	err = c.Database.Secondary.Validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	if c.Accounts.AuthorizingSeed != "" {
+		_, err = keypair.Parse(c.Accounts.AuthorizingSeed)
+		if err != nil {
+			err = errors.New("accounts.authorizing_seed is invalid")
+			return
+		}
+	}
+
+	if c.Accounts.BaseSeed != "" {
+		_, err = keypair.Parse(c.Accounts.BaseSeed)
+		if err != nil {
+			err = errors.New("accounts.base_seed is invalid")
+			return
+		}
+	}
+
+	if c.Accounts.FunderSeed != "" {
+		_, err = keypair.Parse(c.Accounts.FunderSeed)
+		if err != nil {
+			err = errors.New("accounts.funder_seed is invalid")
+			return
+		}
+	}
+
+	if c.Signer.URL != "" {
+		_, err = url.Parse(c.Signer.URL)
+		if err != nil {
+			err = errors.New("Cannot parse signer.url param")
+			return
+		}
+
+		if c.Signer.Secret == "" {
+			err = errors.New("signer.secret param is required")
+			return
+		}
+
+		if (c.Signer.CertificateFile == "") != (c.Signer.PrivateKeyFile == "") {
+			err = errors.New("signer.certificate_file and signer.private_key_file params must be set together")
+			return
+		}
+
+		if c.Signer.CertificateFile != "" {
+			_, err = tls.LoadX509KeyPair(c.Signer.CertificateFile, c.Signer.PrivateKeyFile)
+			if err != nil {
+				err = errors.New("Cannot load signer client certificate: " + err.Error())
+				return
+			}
+		}
+	}
+
+	accounts := map[string]bool{}
+	for _, signer := range c.Signers {
+		err = signer.Validate("signers")
+		if err != nil {
+			return
+		}
+		if accounts[signer.Account] {
+			err = errors.New("Duplicate signers entry of " + signer.Account)
+			return
+		}
+		accounts[signer.Account] = true
+	}
+
+	for _, param := range []struct{ name, seed string }{
+		{"accounts.authorizing_seed", c.Accounts.AuthorizingSeed},
+		{"accounts.base_seed", c.Accounts.BaseSeed},
+		{"accounts.funder_seed", c.Accounts.FunderSeed},
+	} {
+		if IsPublicKeyOnly(param.seed) && c.Signer.URL == "" && !accounts[param.seed] {
+			err = errors.New("signer.url param or a signers entry is required when " + param.name + " is a public key")
+			return
+		}
+	}
+
+	networks := map[string]bool{}
+	for _, network := range c.Networks {
+		err = network.validate(c.Database.Type, accounts)
+		if err != nil {
+			return
+		}
+		if networks[network.Name] {
+			err = errors.New("Duplicate networks entry of " + network.Name)
+			return
+		}
+		networks[network.Name] = true
+	}
+
+	if c.Accounts.IssuingAccountID != "" {
+		_, err = keypair.Parse(c.Accounts.IssuingAccountID)
+		if err != nil {
+			err = errors.New("accounts.issuing_account_id is invalid")
+			return
+		}
+	}
+
+	if c.Accounts.ReceivingAccountID != "" {
+		_, err = keypair.Parse(c.Accounts.ReceivingAccountID)
+		if err != nil {
+			err = errors.New("accounts.receiving_account_id is invalid")
+			return
+		}
+	}
+
+	if c.Settlement.Delay != "" {
+		_, err = time.ParseDuration(c.Settlement.Delay)
+		if err != nil {
+			err = errors.New("Cannot parse settlement.delay param")
+			return
+		}
+	}
+
+	for _, tenant := range c.Settlement.Tenants {
+		if tenant.Tenant == "" {
+			err = errors.New("settlement.tenants.tenant param is required")
+			return
+		}
+
+		_, err = time.ParseDuration(tenant.Delay)
+		if err != nil && tenant.Delay != "" {
+			err = errors.New("Cannot parse settlement.tenants.delay param for " + tenant.Tenant)
+			return
+		}
+		err = nil
+	}
+
+	if c.Settlement.MaxAttempts < 0 {
+		err = errors.New("settlement.max_attempts param must be positive")
+		return
+	}
+
+	if c.Settlement.Enabled() {
+		if c.Database.Type == "" {
+			err = errors.New("database is required when settlement delay is set")
+			return
+		}
+
+		if c.APIKey == "" {
+			err = errors.New("api_key is required when settlement delay is set")
+			return
+		}
+	}
+
+	if c.Settlement.DrainTimeout != "" {
+		timeout, parseErr := time.ParseDuration(c.Settlement.DrainTimeout)
+		if parseErr != nil || timeout <= 0 {
+			err = errors.New("Cannot parse settlement.drain_timeout param")
+			return
+		}
+	}
+
+	if c.Settlement.HandoffKey != "" {
+		_, err = crypto.EncryptionPublicKey(c.Settlement.HandoffKey)
+		if err != nil {
+			err = errors.New("settlement.handoff_key is invalid")
+			return
+		}
+	}
+
+	if len(c.Region.Accounts) > 0 && c.Region.Name == "" {
+		err = errors.New("region.name param is required when region.accounts is set")
+		return
+	}
+
+	for _, account := range c.Region.Accounts {
+		_, err = keypair.Parse(account)
+		if err != nil {
+			err = errors.New("region.accounts contains invalid account ID: " + account)
+			return
+		}
+	}
+
+	if c.Region.Enabled() && c.Database.Type == "" {
+		err = errors.New("database is required when region is set")
+		return
+	}
+
+	err = c.Federation.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	features := map[string]bool{}
+	for _, feature := range c.Features {
+		if feature.Name == "" {
+			err = errors.New("features.name param is required")
+			return
+		}
+
+		if features[feature.Name] {
+			err = errors.New("Duplicate features entry for " + feature.Name)
+			return
+		}
+		features[feature.Name] = true
+	}
+
+	err = c.StellarToml.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Snapshots.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.Cache.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Congestion.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Clock.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.TLS.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.OutboundTLS.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Audit.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.Export.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.ChainReconciliation.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.Tracing.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Admin.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Auth.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.Access.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.HTTP.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Timeouts.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Shutdown.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.LeaderElection.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Redis.validate()
+	if err != nil {
+		return
+	}
+
+	if c.LeaderElection.Backend == LeaderElectionDatabase && (c.Database.Type == "" || c.Database.Type == "memory") {
+		err = errors.New("database shared by replicas is required when leader_election.backend is database")
+		return
+	}
+
+	err = c.Incidents.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Profiles.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Stream.validate(c.Database.Type, c.APIKey != "" || c.Auth.Enabled())
+	if err != nil {
+		return
+	}
+
+	err = c.CreateAccount.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Approval.validate(c.Database.Type, c.Auth.Enabled())
+	if err != nil {
+		return
+	}
+
+	err = c.Risk.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.ExpectedPayments.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.SEP24.validate(c.Database.Type, c.Accounts.ReceivingAccountID)
+	if err != nil {
+		return
+	}
+
+	err = c.Quotes.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Rates.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.Fees.validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Webhooks.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.MemoReferences.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	err = c.BalanceMonitor.validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	if c.LogLevel != "" {
+		_, err = logrus.ParseLevel(c.LogLevel)
+		if err != nil {
+			err = errors.New("Invalid log_level param")
+			return
+		}
+	}
+
+	if c.SorobanRPCURL != "" && validateHTTPURL(c.SorobanRPCURL) != nil {
+		err = errors.New("Cannot parse soroban_rpc_url param")
+		return
+	}
+
+	switch c.MemoRequirement {
+	case "":
+		break
+	case "warn", "require":
+		if c.Database.Type == "" {
+			err = errors.New("database is required when memo_requirement is set")
+			return
+		}
+	default:
+		err = errors.New("Invalid memo_requirement param")
+		return
+	}
+
+	for _, receiveURL := range c.Callbacks.Receive {
+		err = validateHTTPURL(receiveURL)
+		if err != nil {
+			err = errors.New("Cannot parse callbacks.receive param: " + receiveURL)
+			return
+		}
+	}
+
+	err = c.Callbacks.Failover.validate()
+	if err != nil {
+		return
+	}
+
+	switch c.Callbacks.Transport {
+	case "", "http":
+		break
+	case "kafka":
+		if c.Callbacks.Kafka.RestProxyURL == "" || c.Callbacks.Kafka.Topic == "" {
+			err = errors.New("callbacks.kafka.rest_proxy_url and callbacks.kafka.topic params are required")
+			return
+		}
+
+		_, err = url.Parse(c.Callbacks.Kafka.RestProxyURL)
+		if err != nil {
+			err = errors.New("Cannot parse callbacks.kafka.rest_proxy_url param")
+			return
+		}
+
+		// Records of REST Proxy v2 API have no headers to send the MAC in
+		if c.MACKey != "" {
+			err = errors.New("mac_key param cannot be used with kafka callbacks transport")
+			return
+		}
+	case "rabbitmq":
+		if c.Callbacks.RabbitMQ.URL == "" || c.Callbacks.RabbitMQ.Exchange == "" {
+			err = errors.New("callbacks.rabbitmq.url and callbacks.rabbitmq.exchange params are required")
+			return
+		}
+
+		var u *url.URL
+		u, err = url.Parse(c.Callbacks.RabbitMQ.URL)
+		if err != nil || (u.Scheme != "amqp" && u.Scheme != "amqps") {
+			err = errors.New("callbacks.rabbitmq.url param must be an amqp:// or amqps:// URL")
+			return
+		}
+	case "nats":
+		if c.Callbacks.NATS.URL == "" || c.Callbacks.NATS.Subject == "" {
+			err = errors.New("callbacks.nats.url and callbacks.nats.subject params are required")
+			return
+		}
+
+		var u *url.URL
+		u, err = url.Parse(c.Callbacks.NATS.URL)
+		if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") {
+			err = errors.New("callbacks.nats.url param must be a nats:// or tls:// URL")
+			return
+		}
+	case "sqs", "pubsub":
+		err = validateQueue("callbacks", c.Callbacks.Transport, c.Callbacks.SQS, c.Callbacks.PubSub, c.Callbacks.Attributes)
+		if err != nil {
+			return
+		}
+	default:
+		err = errors.New("Invalid callbacks.transport param")
+		return
+	}
+
+	err = validateEventFormat("callbacks", c.Callbacks.Format, c.Callbacks.SchemaVersion)
+	if err != nil {
+		return
+	}
+
+	if c.Callbacks.Format == cloudevents.FormatCloudEvents && (c.Callbacks.Transport == "" || c.Callbacks.Transport == "http") {
+		err = errors.New("callbacks.format param can be cloudevents only with message bus transports")
+		return
+	}
+
+	switch c.TxStatusEvents.Transport {
+	case "":
+		break
+	case "sqs", "pubsub":
+		err = validateQueue("tx_status_events", c.TxStatusEvents.Transport, c.TxStatusEvents.SQS, c.TxStatusEvents.PubSub, c.TxStatusEvents.Attributes)
+		if err != nil {
+			return
+		}
+	default:
+		err = errors.New("Invalid tx_status_events.transport param")
+		return
+	}
+
+	err = validateEventFormat("tx_status_events", c.TxStatusEvents.Format, c.TxStatusEvents.SchemaVersion)
+	if err != nil {
+		return
+	}
+
+	for _, errorURL := range c.Callbacks.Error {
+		err = validateHTTPURL(errorURL)
+		if err != nil {
+			err = errors.New("Cannot parse callbacks.error param: " + errorURL)
+			return
+		}
+	}
+
+	return
+}
+
+func validateQueue(group, transport string, sqs SQS, pubsub PubSub, attributes []string) error {
+	switch transport {
+	case "sqs":
+		if sqs.QueueURL == "" {
+			return errors.New(group + ".sqs.queue_url param is required")
+		}
+
+		_, err := url.Parse(sqs.QueueURL)
+		if err != nil {
+			return errors.New("Cannot parse " + group + ".sqs.queue_url param")
+		}
+	case "pubsub":
+		if pubsub.Project == "" || pubsub.Topic == "" {
+			return errors.New(group + ".pubsub.project and " + group + ".pubsub.topic params are required")
+		}
+	}
+
+	for _, attribute := range attributes {
+		valid := false
+		for _, name := range MessageAttributes {
+			if attribute == name {
+				valid = true
+			}
+		}
+
+		if !valid {
+			return errors.New("Invalid " + group + ".attributes value: " + attribute)
+		}
+	}
+
+	return nil
+}
+
+// validateHTTPURL checks if value is an absolute http or https URL. url.Parse
+// alone accepts almost any string, ex. a URL without a scheme.
+func validateHTTPURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("Not an http or https URL")
+	}
+	return nil
 }
@@ -0,0 +1,462 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/risk"
+	"github.com/stellar/gateway/stage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNetworks(t *testing.T) {
+	port := 8000
+	newConfig := func(networks ...Network) *Config {
+		return &Config{
+			Port:              &port,
+			Horizon:           []string{"https://horizon.stellar.org"},
+			NetworkPassphrase: "Public Global Stellar Network ; September 2015",
+			Networks:          networks,
+		}
+	}
+	testnet := Network{
+		Name:              "testnet",
+		Horizon:           []string{"https://horizon-testnet.stellar.org"},
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Accounts: Accounts{
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+	}
+
+	assert.NoError(t, newConfig(testnet).Validate())
+
+	invalid := map[string]func(n *Network){
+		"Invalid networks.name param: ":                                       func(n *Network) { n.Name = "" },
+		"horizon param is required for testnet network":                       func(n *Network) { n.Horizon = nil },
+		"Cannot parse horizon param of testnet network: horizon-testnet":      func(n *Network) { n.Horizon = []string{"horizon-testnet"} },
+		"network_passphrase param is required for testnet network":            func(n *Network) { n.NetworkPassphrase = "" },
+		"accounts.base_seed of testnet network is invalid":                    func(n *Network) { n.Accounts.BaseSeed = "SB" },
+		"database_schema param of testnet network requires postgres database": func(n *Network) { n.DatabaseSchema = "testnet" },
+		"signers entry is required when accounts.base_seed of testnet network is a public key": func(n *Network) {
+			n.Accounts.BaseSeed = "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"
+		},
+	}
+	for message, change := range invalid {
+		network := testnet
+		change(&network)
+		assert.EqualError(t, newConfig(network).Validate(), message)
+	}
+
+	assert.EqualError(t, newConfig(testnet, testnet).Validate(), "Duplicate networks entry of testnet")
+
+	c := newConfig(testnet)
+	c.Database.Type = "postgres"
+	c.Networks[0].DatabaseSchema = "testnet; drop"
+	assert.EqualError(t, c.Validate(), "Invalid database_schema param of testnet network")
+}
+
+func TestForNetwork(t *testing.T) {
+	c := Config{
+		Horizon:           []string{"https://horizon.stellar.org"},
+		NetworkPassphrase: "Public Global Stellar Network ; September 2015",
+		Compliance:        "http://compliance",
+		Accounts:          Accounts{ReceivingAccountID: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"},
+		Settlement:        Settlement{Delay: "15m"},
+	}
+	c.Networks = []Network{{
+		Name:              "testnet",
+		Horizon:           []string{"https://horizon-testnet.stellar.org"},
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Accounts:          Accounts{ReceivingAccountID: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"},
+	}}
+
+	network, ok := c.Network("testnet")
+	assert.True(t, ok)
+	_, ok = c.Network("futurenet")
+	assert.False(t, ok)
+
+	forNetwork := c.ForNetwork(network)
+	assert.Equal(t, network.Horizon, forNetwork.Horizon)
+	assert.Equal(t, network.NetworkPassphrase, forNetwork.NetworkPassphrase)
+	assert.Empty(t, forNetwork.Accounts.ReceivingAccountID)
+	assert.Empty(t, forNetwork.Compliance)
+	assert.False(t, forNetwork.Settlement.Enabled())
+	assert.Empty(t, forNetwork.Networks)
+	assert.Equal(t, "http://compliance", c.Compliance, "config is not changed")
+}
+
+func TestValidateStream(t *testing.T) {
+	stream := Stream{Enabled: true, MaxSubscribers: 10, PollInterval: "1s", Heartbeat: "30s"}
+	assert.NoError(t, stream.validate("sqlite3", true))
+	assert.NoError(t, Stream{}.validate("", false))
+
+	assert.EqualError(t, stream.validate("", true), "database is required when stream.enabled is set")
+	assert.EqualError(t, stream.validate("sqlite3", false), "api_key or auth.keys param is required when stream.enabled is set")
+
+	invalid := map[string]func(s *Stream){
+		"stream.max_subscribers param cannot be negative": func(s *Stream) { s.MaxSubscribers = -1 },
+		"Cannot parse stream.poll_interval param":         func(s *Stream) { s.PollInterval = "0s" },
+		"Cannot parse stream.heartbeat param":             func(s *Stream) { s.Heartbeat = "often" },
+	}
+	for message, change := range invalid {
+		s := stream
+		change(&s)
+		assert.EqualError(t, s.validate("sqlite3", true), message)
+	}
+
+	assert.Equal(t, 100, Stream{}.MaxSubscribersOrDefault())
+	assert.Equal(t, 15*time.Second, Stream{}.HeartbeatDuration())
+	assert.Equal(t, time.Second, stream.PollIntervalDuration())
+}
+
+func TestAssetLimits(t *testing.T) {
+	asset := Asset{Code: "USD", MaxAmount: "100", DailyMaxAmount: "1000", DestinationDailyMaxAmount: "500"}
+	assert.NoError(t, asset.validateLimits())
+	assert.True(t, asset.HasDailyLimits())
+	assert.False(t, Asset{Code: "USD", MaxAmount: "100"}.HasDailyLimits())
+
+	asset.DestinationDailyMaxAmount = "0"
+	assert.EqualError(t, asset.validateLimits(), "Invalid destination_daily_max_amount param for USD")
+}
+
+func TestSettlementDelayFor(t *testing.T) {
+	settlement := Settlement{Delay: "15m", Tenants: []SettlementTenant{{Tenant: "acme", Delay: "1h"}, {Tenant: "instant"}}}
+	assert.True(t, settlement.Enabled())
+	assert.Equal(t, 15*time.Minute, settlement.DelayFor(""))
+	assert.Equal(t, 15*time.Minute, settlement.DelayFor("other"))
+	assert.Equal(t, time.Hour, settlement.DelayFor("acme"))
+	assert.Equal(t, time.Duration(0), settlement.DelayFor("instant"))
+	assert.Equal(t, 10, settlement.MaxAttemptsOrDefault())
+
+	assert.True(t, Settlement{Tenants: []SettlementTenant{{Tenant: "acme", Delay: "1h"}}}.Enabled())
+	assert.False(t, Settlement{Tenants: []SettlementTenant{{Tenant: "acme"}}}.Enabled())
+}
+
+func TestValidateCreateAccount(t *testing.T) {
+	assert.NoError(t, CreateAccount{}.validate())
+	assert.NoError(t, CreateAccount{StartingBalance: "5", MaxStartingBalance: "10"}.validate())
+	assert.Equal(t, "2", CreateAccount{}.MaxStartingBalanceOrDefault())
+
+	assert.EqualError(t, CreateAccount{StartingBalance: "0"}.validate(), "Invalid create_account.starting_balance param")
+	assert.EqualError(t, CreateAccount{MaxStartingBalance: "1"}.validate(), "create_account.starting_balance is greater than create_account.max_starting_balance")
+}
+
+func TestValidateApproval(t *testing.T) {
+	assert.NoError(t, Approval{}.validate("", false))
+	assert.NoError(t, Approval{Enabled: true}.validate("sqlite3", true))
+
+	assert.EqualError(t, Approval{Enabled: true}.validate("", true), "database is required when approval.enabled is set")
+	assert.EqualError(t, Approval{Enabled: true}.validate("sqlite3", false), "auth.keys param is required when approval.enabled is set")
+	assert.EqualError(t, Asset{Code: "USD", AutoApproveAmount: "-1"}.validateLimits(), "Invalid auto_approve_amount param for USD")
+}
+
+func TestValidateQuotes(t *testing.T) {
+	assert.NoError(t, Quotes{}.validate())
+	assert.Equal(t, 30*time.Second, Quotes{}.ExpiryOrDefault())
+	assert.NoError(t, Quotes{Expiry: "1m"}.validate())
+	assert.Equal(t, time.Minute, Quotes{Expiry: "1m"}.ExpiryOrDefault())
+	assert.EqualError(t, Quotes{Expiry: "0s"}.validate(), "Cannot parse quotes.expiry param")
+	assert.EqualError(t, Quotes{Expiry: "soon"}.validate(), "Cannot parse quotes.expiry param")
+}
+
+func TestValidateRates(t *testing.T) {
+	assert.NoError(t, Rates{}.validate(""))
+	fixed := Rates{
+		Provider:     "fixed",
+		BaseCurrency: "EUR",
+		Fixed:        []FixedRate{{AssetCode: "XLM", Currency: "EUR", Rate: "0.1"}},
+		Tenants:      []TenantCurrency{{Tenant: "acme", BaseCurrency: "USD"}},
+	}
+	assert.NoError(t, fixed.validate("sqlite3"))
+	assert.Equal(t, map[string]string{"acme": "USD"}, fixed.TenantCurrencies())
+	assert.EqualError(t, fixed.validate(""), "database is required when rates.provider is set")
+	assert.NoError(t, Rates{Provider: "http", URL: "https://rates.example.com", BaseCurrency: "EUR"}.validate("sqlite3"))
+	assert.Equal(t, time.Minute, Rates{}.CacheTTLOrDefault())
+	assert.Equal(t, 5*time.Second, Rates{}.TimeoutDuration())
+
+	invalid := []struct {
+		rates Rates
+		err   string
+	}{
+		{Rates{Provider: "ecb", BaseCurrency: "EUR"}, "rates.provider param must be fixed or http"},
+		{Rates{Provider: "http", BaseCurrency: "EUR"}, "rates.url param is required when rates.provider is http"},
+		{Rates{Provider: "fixed"}, "rates.base_currency param is required when rates.provider is set"},
+		{Rates{Provider: "fixed", BaseCurrency: "EUR", Fixed: []FixedRate{{AssetCode: "XLM", Currency: "EUR", Rate: "1/3"}}}, "Invalid rates.fixed.rate param"},
+		{Rates{Provider: "fixed", BaseCurrency: "EUR", Fixed: []FixedRate{{AssetCode: "XLM", Rate: "1"}}}, "rates.fixed.asset_code and currency params are required"},
+		{Rates{Provider: "fixed", BaseCurrency: "EUR", Tenants: []TenantCurrency{{Tenant: "acme"}}}, "rates.tenants.tenant and base_currency params are required"},
+		{Rates{Provider: "http", URL: "https://rates.example.com", BaseCurrency: "EUR", CacheTTL: "forever"}, "Cannot parse rates.cache_ttl param"},
+	}
+	for _, test := range invalid {
+		assert.EqualError(t, test.rates.validate("sqlite3"), test.err)
+	}
+}
+
+func TestValidateFees(t *testing.T) {
+	account := "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"
+	assert.NoError(t, Fees{}.validate())
+	assert.Nil(t, Fees{}.Schedule())
+
+	valid := Fees{Account: account, Rules: []FeeRule{
+		{AssetCode: "USD", Flat: "1", Percentage: "0.5"},
+		{AssetCode: "XLM", Tenant: "acme", Percentage: "1", Mode: "deduct"},
+	}}
+	assert.NoError(t, valid.validate())
+	schedule := valid.Schedule()
+	require.Len(t, schedule.Rules, 2)
+	assert.Equal(t, account, schedule.Account)
+	assert.Equal(t, int64(10000000), schedule.Rules[0].Flat)
+	assert.Equal(t, "1/2", schedule.Rules[0].Percentage.String())
+	assert.Equal(t, "add", schedule.Rules[0].Mode)
+	assert.Equal(t, "deduct", schedule.Rules[1].Mode)
+
+	// Deducted fees don't require a fee account
+	assert.NoError(t, Fees{Rules: []FeeRule{{AssetCode: "USD", Flat: "1", Mode: "deduct"}}}.validate())
+
+	invalid := []struct {
+		fees Fees
+		err  string
+	}{
+		{Fees{Account: "account"}, "Invalid fees.account param"},
+		{Fees{Rules: []FeeRule{{AssetCode: "USD", Flat: "1"}}}, "fees.account param is required when fees are added to payments"},
+		{Fees{Account: account, Rules: []FeeRule{{Flat: "1"}}}, "fees.rules.asset_code param is required"},
+		{Fees{Account: account, Rules: []FeeRule{{AssetCode: "USD"}}}, "fees.rules.flat or percentage param is required"},
+		{Fees{Account: account, Rules: []FeeRule{{AssetCode: "USD", Flat: "-1"}}}, "Invalid fees.rules.flat param"},
+		{Fees{Account: account, Rules: []FeeRule{{AssetCode: "USD", Percentage: "100"}}}, "Invalid fees.rules.percentage param"},
+		{Fees{Account: account, Rules: []FeeRule{{AssetCode: "USD", Flat: "1", Mode: "split"}}}, "fees.rules.mode param must be add or deduct"},
+	}
+	for _, test := range invalid {
+		assert.EqualError(t, test.fees.validate(), test.err)
+	}
+}
+
+func TestValidateWebhooks(t *testing.T) {
+	assert.NoError(t, Webhooks{}.validate(""))
+	assert.EqualError(t, Webhooks{Enabled: true}.validate(""), "database is required when webhooks.enabled is set")
+	assert.NoError(t, Webhooks{Enabled: true}.validate("sqlite3"))
+	assert.EqualError(t, Webhooks{Enabled: true, Timeout: "0s"}.validate("sqlite3"), "Cannot parse webhooks.timeout param")
+	assert.EqualError(t, Webhooks{Enabled: true, MaxAttempts: -1}.validate("sqlite3"), "webhooks.max_attempts param cannot be negative")
+	assert.EqualError(t, Webhooks{Enabled: true, RetryInterval: "soon"}.validate("sqlite3"), "Cannot parse webhooks.retry_interval param")
+
+	assert.Equal(t, 5, Webhooks{}.MaxAttemptsOrDefault())
+	assert.Equal(t, 30*time.Second, Webhooks{}.RetryIntervalOrDefault())
+	assert.Equal(t, time.Minute, Webhooks{RetryInterval: "1m"}.RetryIntervalOrDefault())
+}
+
+func TestValidateMemoReferences(t *testing.T) {
+	key := "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	assert.NoError(t, MemoReferences{}.validate(""))
+	assert.EqualError(t, MemoReferences{Key: key}.validate(""), "database is required when memo_references.key is set")
+	assert.NoError(t, MemoReferences{Key: key, PlaintextMemos: "deny"}.validate("sqlite3"))
+	assert.Error(t, MemoReferences{Key: "c2hvcnQ="}.validate("sqlite3"))
+	assert.EqualError(t, MemoReferences{Key: key, PlaintextMemos: "ignore"}.validate("sqlite3"), "memo_references.plaintext_memos param must be allow or deny")
+
+	assert.True(t, MemoReferences{Key: key}.AllowsPlaintext())
+	assert.False(t, MemoReferences{Key: key, PlaintextMemos: "deny"}.AllowsPlaintext())
+	assert.Equal(t, []byte("0123456789abcdef0123456789abcdef"), MemoReferences{Key: key}.KeyBytes())
+}
+
+func TestValidateBalanceMonitor(t *testing.T) {
+	account := "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5"
+	issuer := "GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3"
+	monitor := BalanceMonitor{Accounts: []MonitoredAccount{{
+		AccountID: account,
+		Assets: []MonitoredAsset{
+			{Min: "100"},
+			{Code: "USD", Issuer: issuer, Min: "1000", MinLimitRoom: "5000"},
+		},
+	}}}
+	assert.NoError(t, BalanceMonitor{}.validate(""))
+	assert.NoError(t, monitor.validate(""))
+
+	assert.Equal(t, 5*time.Minute, monitor.IntervalDuration())
+	thresholds := monitor.Thresholds()
+	require.Len(t, thresholds, 2)
+	assert.Equal(t, int64(100*amounts.One), thresholds[0].Min)
+	assert.Equal(t, "USD", thresholds[1].AssetCode)
+	assert.Equal(t, int64(5000*amounts.One), thresholds[1].MinLimitRoom)
+
+	invalid := monitor
+	invalid.Interval = "often"
+	assert.EqualError(t, invalid.validate(""), "Cannot parse balance_monitor.interval param")
+
+	invalid = BalanceMonitor{Accounts: []MonitoredAccount{{AccountID: account, Assets: []MonitoredAsset{{Min: "100", MinLimitRoom: "1"}}}}}
+	assert.EqualError(t, invalid.validate(""), "balance_monitor.accounts.assets.min_limit_room param cannot be set for native asset")
+
+	invalid = BalanceMonitor{Accounts: []MonitoredAccount{{AccountID: account, Assets: []MonitoredAsset{{Code: "USD", Min: "100"}}}}}
+	assert.Error(t, invalid.validate(""))
+
+	invalid = BalanceMonitor{Accounts: []MonitoredAccount{{AccountID: "SABC", Assets: []MonitoredAsset{{Min: "1"}}}}}
+	assert.EqualError(t, invalid.validate(""), "Invalid balance_monitor.accounts.account_id param")
+
+	seed := "SABY7FRMMJWPBTKQQ2ZN43AUJQ3Z2ZAK36VYSG2SPE2ABNQXA66H5E5G"
+	topUp := BalanceMonitor{Accounts: []MonitoredAccount{{AccountID: account, FundingSeed: seed, Assets: []MonitoredAsset{{Min: "100", TopUpTo: "500"}}}}}
+	assert.NoError(t, topUp.validate("sqlite3"))
+	assert.EqualError(t, topUp.validate(""), "database is required when balance_monitor.accounts.assets.top_up_to is set")
+	assert.Equal(t, int64(500*amounts.One), topUp.Thresholds()[0].TopUpTo)
+	assert.Equal(t, seed, topUp.Thresholds()[0].FundingSeed)
+
+	topUp.Accounts[0].Assets[0].TopUpTo = "50"
+	assert.EqualError(t, topUp.validate("sqlite3"), "balance_monitor.accounts.assets.top_up_to param must be an amount greater than min")
+	topUp.Accounts[0].Assets[0].TopUpTo = "500"
+	topUp.Accounts[0].FundingSeed = account
+	assert.EqualError(t, topUp.validate("sqlite3"), "Invalid balance_monitor.accounts.funding_seed param")
+	topUp.Accounts[0].FundingSeed = ""
+	assert.EqualError(t, topUp.validate("sqlite3"), "balance_monitor.accounts.funding_seed param is required when balance_monitor.accounts.assets.top_up_to is set")
+
+	invalid = monitor
+	invalid.Email = AlertEmail{SMTPAddress: "smtp.example.com:587"}
+	assert.EqualError(t, invalid.validate(""), "balance_monitor.email.from and balance_monitor.email.to params are required when balance_monitor.email.smtp_address is set")
+	invalid.Email.SMTPAddress = "smtp.example.com"
+	assert.EqualError(t, invalid.validate(""), "balance_monitor.email.smtp_address param must be host:port")
+}
+
+func TestEventFormat(t *testing.T) {
+	assert.NoError(t, validateEventFormat("callbacks", "", ""))
+	assert.NoError(t, validateEventFormat("callbacks", "cloudevents", "1"))
+	assert.EqualError(t, validateEventFormat("callbacks", "xml", ""), "callbacks.format param must be json or cloudevents")
+	assert.EqualError(t, validateEventFormat("tx_status_events", "cloudevents", "7"), "Unsupported tx_status_events.schema_version param, supported versions: 1")
+
+	c := Config{CloudEvents: CloudEvents{Source: "bridge-1"}}
+	assert.Nil(t, c.EventFormatter("json", ""))
+	formatter := c.EventFormatter("cloudevents", "")
+	require.NotNil(t, formatter)
+	assert.Equal(t, "bridge-1", formatter.Source)
+	assert.Equal(t, "1", formatter.SchemaVersion)
+}
+
+func TestValidateExpectedPayments(t *testing.T) {
+	assert.NoError(t, ExpectedPayments{}.validate(""))
+	assert.NoError(t, ExpectedPayments{Enabled: true}.validate("sqlite3"))
+	assert.EqualError(t, ExpectedPayments{Enabled: true}.validate(""), "database is required when expected_payments.enabled is set")
+}
+
+func TestValidateSEP24(t *testing.T) {
+	assert.NoError(t, SEP24{}.validate("", ""))
+	assert.NoError(t, SEP24{Enabled: true}.validate("sqlite3", "GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3"))
+	assert.EqualError(t, SEP24{Enabled: true}.validate("", "GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3"), "database is required when sep24.enabled is set")
+	assert.EqualError(t, SEP24{Enabled: true}.validate("sqlite3", ""), "accounts.receiving_account_id is required when sep24.enabled is set")
+}
+
+func TestValidateRisk(t *testing.T) {
+	rule := VelocityRule{Scope: "source", Window: "1h", MaxCount: 10}
+	assert.NoError(t, Risk{}.validate(""))
+	assert.NoError(t, Risk{Velocity: []VelocityRule{rule}, Hooks: []RiskHook{{URL: "https://risk.example.com/check"}}}.validate("sqlite3"))
+	assert.EqualError(t, Risk{Velocity: []VelocityRule{rule}}.validate(""), "database is required when risk.velocity or risk.hooks is set")
+
+	invalid := []struct {
+		rule VelocityRule
+		err  string
+	}{
+		{VelocityRule{Scope: "tenant", Window: "1h", MaxCount: 10}, "risk.velocity.scope param must be source or destination"},
+		{VelocityRule{Scope: "source", Window: "1 day", MaxCount: 10}, "Cannot parse risk.velocity.window param"},
+		{VelocityRule{Scope: "source", Window: "1h"}, "risk.velocity.max_count or max_amount param is required"},
+		{VelocityRule{Scope: "source", Window: "1h", MaxAmount: "100"}, "risk.velocity.asset_code param is required when max_amount is set"},
+		{VelocityRule{Scope: "source", Window: "1h", MaxAmount: "-1", AssetCode: "USD"}, "Invalid risk.velocity.max_amount param"},
+		{VelocityRule{Scope: "source", Window: "1h", MaxCount: 10, AssetCode: "USD", AssetIssuer: "issuer"}, "Invalid risk.velocity.asset_issuer param"},
+		{VelocityRule{Scope: "source", Window: "1h", MaxCount: 10, Action: "block"}, "risk.velocity.action param must be deny or hold"},
+	}
+	for _, test := range invalid {
+		assert.EqualError(t, Risk{Velocity: []VelocityRule{test.rule}}.validate("sqlite3"), test.err)
+	}
+
+	assert.EqualError(t, Risk{Hooks: []RiskHook{{}}}.validate("sqlite3"), "risk.hooks requires exactly one of url and plugin params")
+	assert.EqualError(t, Risk{Hooks: []RiskHook{{URL: "https://risk.example.com", Plugin: "hook.so"}}}.validate("sqlite3"), "risk.hooks requires exactly one of url and plugin params")
+	assert.EqualError(t, Risk{Hooks: []RiskHook{{Plugin: "hook.so"}}, HoldDuration: "soon"}.validate("sqlite3"), "Cannot parse risk.hold_duration param")
+
+	rules := Risk{Velocity: []VelocityRule{{Scope: "destination", Window: "24h", MaxAmount: "1000", AssetCode: "USD", Action: "hold"}}}.VelocityRules()
+	assert.Equal(t, []risk.VelocityRule{{Scope: "destination", Window: 24 * time.Hour, MaxAmount: 1000 * 10000000, AssetCode: "USD", Decision: risk.DecisionHold}}, rules)
+}
+
+func TestValidateSubmissionWorkers(t *testing.T) {
+	assert.NoError(t, Submission{}.validate())
+	assert.NoError(t, Submission{Concurrency: 4, ChannelSeeds: []string{"SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK"}}.validate())
+	assert.Equal(t, 100, Submission{}.QueueDepthOrDefault())
+
+	assert.EqualError(t, Submission{Concurrency: -1}.validate(), "submission.concurrency param cannot be negative")
+	assert.EqualError(t, Submission{ChannelSeeds: []string{"SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK"}}.validate(), "submission.channel_seeds param requires submission.concurrency")
+	assert.EqualError(t, Submission{Concurrency: 4, ChannelSeeds: []string{"GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"}}.validate(), "Invalid submission.channel_seeds param")
+}
+
+func TestValidateSubmissionStellarCore(t *testing.T) {
+	core := StellarCore{URL: "http://localhost:11626", DatabaseURL: "postgres://localhost/core"}
+	assert.NoError(t, Submission{Backend: "stellar-core", StellarCore: core}.validate())
+
+	// Sequence numbers and results of transactions are loaded from the
+	// database or soroban-rpc
+	core.DatabaseURL = ""
+	assert.EqualError(t, Submission{Backend: "stellar-core", StellarCore: core}.validate(), "submission.stellar_core.database_url or submission.stellar_core.soroban_rpc_url param is required")
+
+	core.SorobanRPCURL = "http://localhost:8000"
+	assert.NoError(t, Submission{Backend: "stellar-core", StellarCore: core}.validate())
+
+	core.DatabaseURL = "postgres://localhost/core"
+	assert.EqualError(t, Submission{Backend: "stellar-core", StellarCore: core}.validate(), "submission.stellar_core.database_url and submission.stellar_core.soroban_rpc_url params cannot be both set")
+
+	core = StellarCore{URL: "http://localhost:11626", SorobanRPCURL: "localhost:8000"}
+	assert.EqualError(t, Submission{Backend: "stellar-core", StellarCore: core}.validate(), "Cannot parse submission.stellar_core.soroban_rpc_url param")
+}
+
+func TestValidateTimeouts(t *testing.T) {
+	assert.NoError(t, Timeouts{}.validate())
+	assert.NoError(t, Timeouts{Federation: "5s", Horizon: "3s"}.validate())
+	assert.EqualError(t, Timeouts{Compliance: "soon"}.validate(), "Cannot parse timeouts.compliance param")
+
+	timeouts := Timeouts{Federation: "5s"}
+	assert.Equal(t, 5*time.Second, timeouts.Stage(stage.Federation))
+	assert.Equal(t, time.Duration(0), timeouts.Stage(stage.Horizon))
+}
+
+func TestValidateShutdown(t *testing.T) {
+	assert.NoError(t, Shutdown{}.validate())
+	assert.Equal(t, 30*time.Second, Shutdown{}.DrainTimeoutDuration())
+	assert.Equal(t, time.Minute, Shutdown{DrainTimeout: "1m"}.DrainTimeoutDuration())
+	assert.EqualError(t, Shutdown{DrainTimeout: "0s"}.validate(), "Cannot parse shutdown.drain_timeout param")
+}
+
+func TestValidateLeaderElection(t *testing.T) {
+	assert.NoError(t, LeaderElection{}.validate())
+	assert.NoError(t, LeaderElection{Backend: LeaderElectionDatabase}.validate())
+	assert.Equal(t, 15*time.Second, LeaderElection{}.LeaseDurationDuration())
+	assert.Equal(t, 5*time.Second, LeaderElection{}.RenewIntervalDuration())
+	assert.Equal(t, "bridge", LeaderElection{}.NameOrDefault())
+
+	assert.EqualError(t, LeaderElection{Backend: "zookeeper"}.validate(), "Invalid leader_election.backend param")
+	assert.EqualError(t, LeaderElection{Backend: LeaderElectionEtcd}.validate(), "leader_election.etcd_endpoints param is required")
+	assert.EqualError(t, LeaderElection{Backend: LeaderElectionEtcd, EtcdEndpoints: []string{"etcd"}}.validate(), "Cannot parse leader_election.etcd_endpoints param")
+	assert.EqualError(t, LeaderElection{Backend: LeaderElectionDatabase, LeaseDuration: "15"}.validate(), "Cannot parse leader_election.lease_duration param")
+	assert.EqualError(t, LeaderElection{Backend: LeaderElectionDatabase, LeaseDuration: "10s", RenewInterval: "10s"}.validate(), "leader_election.renew_interval param must be shorter than lease_duration")
+}
+
+func TestValidateRedis(t *testing.T) {
+	assert.NoError(t, Redis{}.validate())
+	assert.NoError(t, Redis{URL: "rediss://:secret@redis:6380/1"}.validate())
+	assert.Equal(t, "bridge:", Redis{}.KeyPrefixOrDefault())
+	assert.Equal(t, 10, Redis{}.PoolSizeOrDefault())
+	assert.Equal(t, time.Second, Redis{}.TimeoutDuration())
+	assert.Equal(t, 24*time.Hour, Redis{}.IdempotencyTTLDuration())
+
+	assert.EqualError(t, Redis{URL: "redis-server:6379"}.validate(), "Cannot parse redis.url param")
+	assert.EqualError(t, Redis{URL: "redis://redis", PoolSize: -1}.validate(), "redis.pool_size param must be positive")
+	assert.EqualError(t, Redis{URL: "redis://redis", IdempotencyTTL: "1d"}.validate(), "Cannot parse redis.idempotency_ttl param")
+}
+
+func TestValidateListener(t *testing.T) {
+	assert.NoError(t, Listener{}.validate())
+	assert.NoError(t, Listener{Backend: "stellar-core-database", DatabaseURL: "postgres://localhost/core"}.validate())
+
+	// Only the database of a stellar-core node can be read
+	assert.EqualError(t, Listener{Backend: "stellar-core", DatabaseURL: "postgres://localhost/core"}.validate(), "Invalid listener.backend param")
+	assert.EqualError(t, Listener{Backend: "stellar-core-database"}.validate(), "listener.database_url param is required")
+
+	assert.NoError(t, Listener{Backend: "history-archive", HistoryArchiveURL: "https://history.stellar.org/prd/core-testnet/core_testnet_001"}.validate())
+	assert.EqualError(t, Listener{Backend: "history-archive"}.validate(), "listener.history_archive_url param is required and must be an http(s) URL")
+	assert.EqualError(t, Listener{Backend: "history-archive", HistoryArchiveURL: "s3://history"}.validate(), "listener.history_archive_url param is required and must be an http(s) URL")
+
+	contract := "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	assert.NoError(t, Listener{SorobanRPCURL: "https://soroban-testnet.stellar.org", Contracts: []string{contract}}.validate())
+	assert.EqualError(t, Listener{SorobanRPCURL: "soroban-testnet.stellar.org"}.validate(), "Cannot parse listener.soroban_rpc_url param")
+	assert.EqualError(t, Listener{Contracts: []string{contract}}.validate(), "listener.soroban_rpc_url param is required to receive transfers to listener.contracts")
+	assert.EqualError(t, Listener{SorobanRPCURL: "https://soroban-testnet.stellar.org", Contracts: []string{"GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2"}}.validate(), "Invalid contract address GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2 in listener.contracts param")
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reloadable contains config params that can be changed without restarting
+// the server (SIGHUP or POST /admin/reload)
+type Reloadable struct {
+	Assets          []Asset
+	Receive         []string
+	RateLimits      []RateLimit
+	LogLevel        string
+	MemoRequirement string
+	ProfilesCheck   bool
+}
+
+// Reloadable returns params of c that can be reloaded
+func (c Config) Reloadable() Reloadable {
+	return Reloadable{
+		Assets:          c.Assets,
+		Receive:         c.Callbacks.Receive,
+		RateLimits:      c.Access.RateLimits,
+		LogLevel:        c.LogLevel,
+		MemoRequirement: c.MemoRequirement,
+		ProfilesCheck:   c.Profiles.Check,
+	}
+}
+
+// LogLevelOrDefault returns parsed LogLevel or info level when not set
+func (r Reloadable) LogLevelOrDefault() logrus.Level {
+	// Value is checked in Validate
+	level, err := logrus.ParseLevel(r.LogLevel)
+	if err != nil {
+		return logrus.InfoLevel
+	}
+	return level
+}
+
+// Changes compares c with next config. Reloaded contains names of changed
+// params that can be reloaded, ignored contains names of other changed params
+// (or groups) which require a restart.
+func (c Config) Changes(next Config) (reloaded, ignored []string) {
+	current, updated := c.Reloadable(), next.Reloadable()
+	for _, param := range []struct {
+		name          string
+		current, next interface{}
+	}{
+		{"assets", current.Assets, updated.Assets},
+		{"callbacks.receive", current.Receive, updated.Receive},
+		{"access.rate_limits", current.RateLimits, updated.RateLimits},
+		{"log_level", current.LogLevel, updated.LogLevel},
+		{"memo_requirement", current.MemoRequirement, updated.MemoRequirement},
+		{"profiles.check", current.ProfilesCheck, updated.ProfilesCheck},
+	} {
+		if !reflect.DeepEqual(param.current, param.next) {
+			reloaded = append(reloaded, param.name)
+		}
+	}
+
+	before, after := reflect.ValueOf(c.withoutReloadable()), reflect.ValueOf(next.withoutReloadable())
+	for i := 0; i < before.NumField(); i++ {
+		if reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+			continue
+		}
+		field := before.Type().Field(i)
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		ignored = append(ignored, name)
+	}
+	return
+}
+
+// WithReloadable returns a copy of c with reloadable params replaced
+func (c Config) WithReloadable(params Reloadable) Config {
+	c.Assets = params.Assets
+	c.Callbacks.Receive = params.Receive
+	c.Access.RateLimits = params.RateLimits
+	c.LogLevel = params.LogLevel
+	c.MemoRequirement = params.MemoRequirement
+	c.Profiles.Check = params.ProfilesCheck
+	return c
+}
+
+func (c Config) withoutReloadable() Config {
+	return c.WithReloadable(Reloadable{})
+}
+
+// Live holds Reloadable params of a running server. It's safe for concurrent
+// use.
+type Live struct {
+	mutex   sync.RWMutex
+	current Reloadable
+}
+
+// NewLive creates Live with Reloadable params of c
+func (c Config) NewLive() *Live {
+	return &Live{current: c.Reloadable()}
+}
+
+// Get returns current params
+func (l *Live) Get() Reloadable {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.current
+}
+
+// Set replaces current params
+func (l *Live) Set(params Reloadable) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.current = params
+}
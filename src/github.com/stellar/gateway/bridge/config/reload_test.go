@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanges(t *testing.T) {
+	port := 8001
+	current := Config{
+		Port:              &port,
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Assets:            []Asset{{Code: "USD", Issuer: "GAB"}},
+	}
+	current.Callbacks.Receive = []string{"http://receive"}
+	current.Access.Allow = []string{"10.0.0.0/8"}
+
+	reloaded, ignored := current.Changes(current)
+	assert.Empty(t, reloaded)
+	assert.Empty(t, ignored)
+
+	nextPort := 8002
+	next := current
+	next.Port = &nextPort
+	next.Assets = []Asset{{Code: "USD", Issuer: "GAB", MaxAmount: "100"}}
+	next.Callbacks.Receive = []string{"http://receive", "http://backup"}
+	next.Callbacks.Transport = "kafka"
+	next.Access.RateLimits = []RateLimit{{Path: "/payment", RateLimit: 10}}
+	next.LogLevel = "debug"
+	next.MemoRequirement = "warn"
+	next.Profiles.Check = true
+
+	reloaded, ignored = current.Changes(next)
+	assert.Equal(t, []string{"assets", "callbacks.receive", "access.rate_limits", "log_level", "memo_requirement", "profiles.check"}, reloaded)
+	assert.Equal(t, []string{"port", "callbacks"}, ignored)
+
+	applied := current.WithReloadable(next.Reloadable())
+	assert.Equal(t, next.Assets, applied.Assets)
+	assert.Equal(t, next.Callbacks.Receive, applied.Callbacks.Receive)
+	assert.Equal(t, "", applied.Callbacks.Transport)
+	assert.Equal(t, port, *applied.Port)
+	assert.Equal(t, []string{"http://receive"}, current.Callbacks.Receive, "current config is not changed")
+}
+
+func TestLive(t *testing.T) {
+	config := Config{Assets: []Asset{{Code: "XLM"}}}
+	live := config.NewLive()
+	assert.Equal(t, config.Assets, live.Get().Assets)
+	assert.Equal(t, logrus.InfoLevel, live.Get().LogLevelOrDefault())
+
+	live.Set(Reloadable{LogLevel: "warn"})
+	assert.Empty(t, live.Get().Assets)
+	assert.Equal(t, logrus.WarnLevel, live.Get().LogLevelOrDefault())
+}
@@ -0,0 +1,325 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/proto"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+)
+
+// recorder keeps the response of a handler
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(data)
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// call sends a request of the gRPC call r to handler. The request has the
+// context (API key, tenant) and headers of r.
+func call(r *http.Request, handler http.Handler, path, contentType string, body []byte) *recorder {
+	request := r.Clone(r.Context())
+	request.URL = &url.URL{Path: path}
+	request.RequestURI = path
+	request.Proto, request.ProtoMajor, request.ProtoMinor = "HTTP/1.1", 1, 1
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Del("Content-Length")
+	request.ContentLength = int64(len(body))
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.Form, request.PostForm, request.MultipartForm = nil, nil, nil
+
+	response := &recorder{header: http.Header{}}
+	handler.ServeHTTP(response, request)
+	if response.status == 0 {
+		response.status = http.StatusOK
+	}
+	return response
+}
+
+// decodeResponse decodes a successful response to result or returns the
+// status of an error response
+func decodeResponse(response *recorder, result interface{}) *Status {
+	var errorResponse protocols.ErrorResponse
+	err := json.Unmarshal(response.body.Bytes(), &errorResponse)
+	if err == nil && errorResponse.Code != "" {
+		// Pending compliance payments are error responses with 202 status
+		errorResponse.Status = response.status
+		return errorStatus(&errorResponse)
+	}
+	if response.status != http.StatusOK && response.status != http.StatusAccepted {
+		// Ex. plain text responses of middlewares
+		return &Status{Code: codeFromHTTP(response.status), Message: strings.TrimSpace(response.body.String())}
+	}
+
+	err = json.Unmarshal(response.body.Bytes(), result)
+	if err != nil {
+		return &Status{Code: Internal, Message: "Cannot decode response"}
+	}
+	return nil
+}
+
+// payment sends request to /payment handler
+func (s *Server) payment(r *http.Request, in *proto.PaymentRequest) (proto.Message, *Status) {
+	request := &bridge.PaymentRequest{
+		ID:              in.ID,
+		Source:          in.Source,
+		Sender:          in.Sender,
+		Destination:     in.Destination,
+		DestinationName: in.DestinationName,
+		MemoType:        in.MemoType,
+		Memo:            in.Memo,
+		Amount:          in.Amount,
+		AssetCode:       in.AssetCode,
+		AssetIssuer:     in.AssetIssuer,
+		SendMax:         in.SendMax,
+		SendAssetCode:   in.SendAssetCode,
+		SendAssetIssuer: in.SendAssetIssuer,
+		UseCompliance:   in.UseCompliance,
+		ExtraMemo:       in.ExtraMemo,
+		SenderID:        in.SenderID,
+		SenderInfo:      in.SenderInfo,
+		ReceiverInfo:    in.ReceiverInfo,
+		Route:           in.Route,
+		Note:            in.Note,
+		PrivateNote:     in.PrivateNote,
+		DryRun:          in.DryRun,
+		Network:         in.Network,
+		OrderingKey:     in.OrderingKey,
+		QuoteID:         in.QuoteID,
+	}
+	for _, asset := range in.Path {
+		request.Path = append(request.Path, protocols.Asset{Code: asset.Code, Issuer: asset.Issuer})
+	}
+	if in.ForwardDestination != nil {
+		request.ForwardDestination = &protocols.ForwardDestination{
+			Domain: in.ForwardDestination.Domain,
+			Fields: url.Values{},
+		}
+		for key, value := range in.ForwardDestination.Fields {
+			request.ForwardDestination.Fields.Set(key, value)
+		}
+	}
+
+	body := request.ToValues().Encode()
+	response := call(r, s.Payment, "/payment", "application/x-www-form-urlencoded", []byte(body))
+
+	switch {
+	case in.DryRun:
+		var dryRun bridge.PaymentDryRunResponse
+		if status := decodeResponse(response, &dryRun); status != nil {
+			return nil, status
+		}
+		out := &proto.PaymentResponse{Status: proto.PaymentStatusDryRun, Valid: dryRun.Valid}
+		for _, failure := range dryRun.Failures {
+			out.Failures = append(out.Failures, &proto.PreflightFailure{
+				Operation: int32(failure.Operation),
+				Code:      failure.Code,
+				Message:   failure.Message,
+			})
+		}
+		return out, nil
+	case response.status == http.StatusAccepted:
+		var held bridge.HeldPaymentResponse
+		if status := decodeResponse(response, &held); status != nil {
+			return nil, status
+		}
+		return &proto.PaymentResponse{
+			Status:   proto.PaymentStatusHeld,
+			ID:       held.ID,
+			SettleAt: held.SettleAt.Format(time.RFC3339),
+		}, nil
+	default:
+		var submitted horizon.SubmitTransactionResponse
+		if status := decodeResponse(response, &submitted); status != nil {
+			return nil, status
+		}
+		out := &proto.PaymentResponse{
+			Status:           proto.PaymentStatusSuccess,
+			Hash:             submitted.Hash,
+			SendAmount:       submitted.SendAmount,
+			InferredMemoType: submitted.InferredMemoType,
+		}
+		if submitted.Ledger != nil {
+			out.Ledger = *submitted.Ledger
+		}
+		if submitted.ResultXdr != nil {
+			out.ResultXdr = *submitted.ResultXdr
+		}
+		return out, nil
+	}
+}
+
+// builder sends request to /builder handler
+func (s *Server) builder(r *http.Request, in *proto.BuilderRequest) (proto.Message, *Status) {
+	type operation struct {
+		Type string          `json:"type"`
+		Body json.RawMessage `json:"body"`
+	}
+	request := struct {
+		Source         string      `json:"source"`
+		SequenceNumber string      `json:"sequence_number"`
+		Operations     []operation `json:"operations"`
+		Signers        []string    `json:"signers"`
+		Network        string      `json:"network,omitempty"`
+	}{
+		Source:         in.Source,
+		SequenceNumber: in.SequenceNumber,
+		Operations:     []operation{},
+		Signers:        in.Signers,
+		Network:        in.Network,
+	}
+	for i, op := range in.Operations {
+		if !json.Valid([]byte(op.BodyJSON)) {
+			return nil, errorStatus(protocols.NewInvalidParameterError("operations", op.BodyJSON, "body_json of operation "+strconv.Itoa(i)+" is not a valid JSON."))
+		}
+		request.Operations = append(request.Operations, operation{Type: op.Type, Body: json.RawMessage(op.BodyJSON)})
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, &Status{Code: Internal, Message: "Cannot encode request"}
+	}
+	response := call(r, s.Builder, "/builder", "application/json", body)
+
+	var built bridge.BuilderResponse
+	if status := decodeResponse(response, &built); status != nil {
+		return nil, status
+	}
+	return &proto.BuilderResponse{TransactionEnvelope: built.TransactionEnvelope}, nil
+}
+
+// validateStatusRequest checks if a transaction is selected
+func validateStatusRequest(in *proto.TransactionStatusRequest) *Status {
+	if in.PaymentID == "" && in.TransactionID == "" {
+		return errorStatus(protocols.NewMissingParameter("payment_id"))
+	}
+	return nil
+}
+
+// getTransactionStatus returns the current status of a sent transaction
+func (s *Server) getTransactionStatus(ctx context.Context, in *proto.TransactionStatusRequest) (proto.Message, *Status) {
+	if s.Transactions == nil {
+		return nil, &Status{Code: Unimplemented, Message: "Transaction statuses are not available without a database"}
+	}
+	if status := validateStatusRequest(in); status != nil {
+		return nil, status
+	}
+
+	sentTransaction, status := s.sentTransaction(ctx, in)
+	if status != nil {
+		return nil, status
+	}
+	if sentTransaction == nil {
+		return nil, &Status{Code: NotFound, Message: "Transaction not found"}
+	}
+	return transactionStatus(sentTransaction), nil
+}
+
+// watchTransactionStatus streams statuses of a sent transaction until it
+// succeeds or fails. Waits for the transaction when it has not been sent yet.
+func (s *Server) watchTransactionStatus(ctx context.Context, w http.ResponseWriter, in *proto.TransactionStatusRequest) *Status {
+	if s.Transactions == nil || s.Statuses == nil {
+		return &Status{Code: Unimplemented, Message: "Transaction statuses are not available without a database"}
+	}
+	if status := validateStatusRequest(in); status != nil {
+		return status
+	}
+
+	// Subscribed before loading the current status, so no change is missed
+	updates, unsubscribe := s.Statuses.Subscribe(in.Network, in.PaymentID, in.TransactionID)
+	defer unsubscribe()
+
+	sentTransaction, status := s.sentTransaction(ctx, in)
+	if status != nil {
+		return status
+	}
+
+	var last *proto.TransactionStatus
+	for {
+		if sentTransaction != nil {
+			next := transactionStatus(sentTransaction)
+			if last == nil || next.TransactionID != last.TransactionID || next.Status != last.Status {
+				if status := writeMessage(w, next); status != nil {
+					return status
+				}
+				last = next
+			}
+			if sentTransaction.Status != entities.SentTransactionStatusSending {
+				return nil
+			}
+		}
+
+		select {
+		case sentTransaction = <-updates:
+		case <-ctx.Done():
+			return contextStatus(ctx)
+		}
+	}
+}
+
+func (s *Server) sentTransaction(ctx context.Context, in *proto.TransactionStatusRequest) (*entities.SentTransaction, *Status) {
+	sentTransaction, err := s.Transactions.SentTransaction(ctx, in.Network, in.PaymentID, in.TransactionID)
+	if errorResponse, ok := err.(*protocols.ErrorResponse); ok {
+		return nil, errorStatus(errorResponse)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, contextStatus(ctx)
+		}
+		s.log.WithFields(logrus.Fields{"err": err}).Error("Error loading sent transaction")
+		return nil, &Status{Code: Internal, Message: "Error loading sent transaction"}
+	}
+	return sentTransaction, nil
+}
+
+// transactionStatus converts a sent transaction to TransactionStatus message
+func transactionStatus(sentTransaction *entities.SentTransaction) *proto.TransactionStatus {
+	status := &proto.TransactionStatus{
+		TransactionID: sentTransaction.TransactionID,
+		Status:        string(sentTransaction.Status),
+		Source:        sentTransaction.Source,
+		SubmittedAt:   sentTransaction.SubmittedAt.Format(time.RFC3339),
+		Region:        sentTransaction.Region,
+	}
+	if sentTransaction.PaymentID != nil {
+		status.PaymentID = *sentTransaction.PaymentID
+	}
+	if sentTransaction.Ledger != nil {
+		status.Ledger = *sentTransaction.Ledger
+	}
+	if sentTransaction.SucceededAt != nil {
+		status.SucceededAt = sentTransaction.SucceededAt.Format(time.RFC3339)
+	}
+	if sentTransaction.ResultXdr != nil {
+		status.ResultXdr = *sentTransaction.ResultXdr
+	}
+	return status
+}
@@ -0,0 +1,314 @@
+// Package grpcserver serves the gRPC API of the bridge server defined in
+// proto/bridge.proto. Payment and Builder calls are handled by the same
+// handlers as /payment and /builder endpoints, so they behave the same way
+// (auth, rate limits, features, networks). Statuses of sent transactions can
+// be queried or streamed until the transaction succeeds or fails.
+//
+// gRPC requests are served by the API server over HTTP/2: with TLS or
+// unencrypted (h2c). Messages must not be compressed.
+package grpcserver
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/proto"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// PathPrefix is the prefix of paths of Bridge service methods
+const PathPrefix = "/" + proto.Service + "/"
+
+// Methods are the names of Bridge service methods
+var Methods = []string{"Payment", "Builder", "GetTransactionStatus", "WatchTransactionStatus"}
+
+// MaxMessageSize is the max size of a request message, like the default
+// limit of gRPC servers
+const MaxMessageSize = 4 << 20
+
+// Code is a gRPC status code
+type Code int
+
+// Status codes returned by the server
+const (
+	OK                 Code = 0
+	Canceled           Code = 1
+	InvalidArgument    Code = 3
+	DeadlineExceeded   Code = 4
+	NotFound           Code = 5
+	PermissionDenied   Code = 7
+	ResourceExhausted  Code = 8
+	FailedPrecondition Code = 9
+	Unimplemented      Code = 12
+	Internal           Code = 13
+	Unavailable        Code = 14
+	Unauthenticated    Code = 16
+)
+
+// Status is the result of a call sent in trailers. ErrorCode is the code of
+// the bridge error response (ex. payment_underfunded) sent in
+// `bridge-error-code` trailer.
+type Status struct {
+	Code      Code
+	Message   string
+	ErrorCode string
+}
+
+// Error implements error
+func (s *Status) Error() string {
+	return fmt.Sprintf("grpc status %d: %s", s.Code, s.Message)
+}
+
+// codeFromHTTP returns the status code of an HTTP response status
+func codeFromHTTP(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return InvalidArgument
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return FailedPrecondition
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return ResourceExhausted
+	case http.StatusAccepted, http.StatusServiceUnavailable:
+		// 202 is returned for pending compliance payments
+		return Unavailable
+	default:
+		return Internal
+	}
+}
+
+// errorStatus returns the status of an error response
+func errorStatus(errorResponse *protocols.ErrorResponse) *Status {
+	message := errorResponse.Message
+	if errorResponse.MoreInfo != "" {
+		message += " " + errorResponse.MoreInfo
+	}
+	return &Status{Code: codeFromHTTP(errorResponse.Status), Message: message, ErrorCode: errorResponse.Code}
+}
+
+// TransactionSource loads sent transactions, see
+// handlers.RequestHandler.SentTransaction
+type TransactionSource interface {
+	SentTransaction(ctx context.Context, network, paymentID, transactionID string) (*entities.SentTransaction, error)
+}
+
+// Server handles gRPC requests to Bridge service. Payment and Builder are
+// handlers of /payment and /builder endpoints. Transaction status methods are
+// unimplemented when Transactions or Statuses is nil (no database).
+type Server struct {
+	Payment      http.Handler
+	Builder      http.Handler
+	Transactions TransactionSource
+	Statuses     *StatusHub
+	log          *logrus.Entry
+}
+
+// NewServer creates a new Server of payment and builder handlers
+func NewServer(payment, builder http.Handler) *Server {
+	return &Server{
+		Payment: payment,
+		Builder: builder,
+		log:     logrus.WithFields(logrus.Fields{"service": "gRPC"}),
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !server.IsGRPC(r) {
+		http.Error(w, "Unsupported Media Type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.ProtoMajor != 2 {
+		http.Error(w, "gRPC requires HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+
+	ctx := r.Context()
+	if value := r.Header.Get("Grpc-Timeout"); value != "" {
+		timeout, ok := parseTimeout(value)
+		if !ok {
+			s.finish(w, r, &Status{Code: InvalidArgument, Message: "Invalid grpc-timeout header"})
+			return
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+
+	var status *Status
+	switch strings.TrimPrefix(r.URL.Path, PathPrefix) {
+	case "Payment":
+		request := &proto.PaymentRequest{}
+		status = readMessage(r.Body, request)
+		if status == nil {
+			status = s.unary(w, func() (proto.Message, *Status) { return s.payment(r, request) })
+		}
+	case "Builder":
+		request := &proto.BuilderRequest{}
+		status = readMessage(r.Body, request)
+		if status == nil {
+			status = s.unary(w, func() (proto.Message, *Status) { return s.builder(r, request) })
+		}
+	case "GetTransactionStatus":
+		request := &proto.TransactionStatusRequest{}
+		status = readMessage(r.Body, request)
+		if status == nil {
+			status = s.unary(w, func() (proto.Message, *Status) { return s.getTransactionStatus(ctx, request) })
+		}
+	case "WatchTransactionStatus":
+		request := &proto.TransactionStatusRequest{}
+		status = readMessage(r.Body, request)
+		if status == nil {
+			status = s.watchTransactionStatus(ctx, w, request)
+		}
+	default:
+		status = &Status{Code: Unimplemented, Message: "Unknown method " + r.URL.Path}
+	}
+
+	if status == nil {
+		status = &Status{Code: OK}
+	}
+	s.finish(w, r, status)
+}
+
+// unary writes the response of call
+func (s *Server) unary(w http.ResponseWriter, call func() (proto.Message, *Status)) *Status {
+	response, status := call()
+	if status != nil {
+		return status
+	}
+	return writeMessage(w, response)
+}
+
+// finish writes status in trailers
+func (s *Server) finish(w http.ResponseWriter, r *http.Request, status *Status) {
+	if status.Code != OK {
+		s.log.WithFields(logrus.Fields{
+			"path":       r.URL.Path,
+			"code":       status.Code,
+			"message":    status.Message,
+			"error_code": status.ErrorCode,
+		}).Warn("gRPC call failed")
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(int(status.Code)))
+	if status.Message != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", encodeMessage(status.Message))
+	}
+	if status.ErrorCode != "" {
+		w.Header().Set(http.TrailerPrefix+"Bridge-Error-Code", status.ErrorCode)
+	}
+}
+
+// readMessage reads a single length-prefixed message from body
+func readMessage(body io.Reader, message proto.Message) *Status {
+	var header [5]byte
+	_, err := io.ReadFull(body, header[:])
+	if err != nil {
+		return &Status{Code: InvalidArgument, Message: "Cannot read request message"}
+	}
+	if header[0] != 0 {
+		return &Status{Code: Unimplemented, Message: "Compressed messages are not supported"}
+	}
+
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > MaxMessageSize {
+		return &Status{Code: ResourceExhausted, Message: "Request message is too large"}
+	}
+
+	data := make([]byte, length)
+	_, err = io.ReadFull(body, data)
+	if err != nil {
+		return &Status{Code: InvalidArgument, Message: "Cannot read request message"}
+	}
+
+	err = message.Unmarshal(data)
+	if err != nil {
+		return &Status{Code: InvalidArgument, Message: "Cannot decode request message: " + err.Error()}
+	}
+	return nil
+}
+
+// writeMessage writes a length-prefixed message and flushes it, so streamed
+// messages are sent right away
+func writeMessage(w http.ResponseWriter, message proto.Message) *Status {
+	data := message.Marshal()
+	frame := make([]byte, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(data)))
+	copy(frame[5:], data)
+
+	_, err := w.Write(frame)
+	if err != nil {
+		return &Status{Code: Unavailable, Message: "Cannot write response message"}
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// parseTimeout parses grpc-timeout header: up to 8 digits and a unit
+func parseTimeout(value string) (time.Duration, bool) {
+	if len(value) < 2 || len(value) > 9 {
+		return 0, false
+	}
+
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, false
+	}
+
+	amount, err := strconv.ParseUint(value[:len(value)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(amount) * unit, true
+}
+
+// encodeMessage percent-encodes grpc-message trailer
+func encodeMessage(message string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		} else {
+			encoded.WriteByte(c)
+		}
+	}
+	return encoded.String()
+}
+
+// contextStatus returns the status of a call stopped by ctx
+func contextStatus(ctx context.Context) *Status {
+	if ctx.Err() == context.DeadlineExceeded {
+		return &Status{Code: DeadlineExceeded, Message: "Deadline exceeded"}
+	}
+	return &Status{Code: Canceled, Message: "Call cancelled"}
+}
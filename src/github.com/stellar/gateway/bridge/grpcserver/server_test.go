@@ -0,0 +1,253 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/proto"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTransactions map[string]*entities.SentTransaction
+
+func (t testTransactions) SentTransaction(ctx context.Context, network, paymentID, transactionID string) (*entities.SentTransaction, error) {
+	return t[network+"/"+paymentID+transactionID], nil
+}
+
+// newTestServer starts s on an unencrypted HTTP/2 server and returns it
+// with a client
+func newTestServer(s http.Handler) (*httptest.Server, *http.Client) {
+	ts := httptest.NewUnstartedServer(s)
+	ts.Config.Protocols = new(http.Protocols)
+	ts.Config.Protocols.SetUnencryptedHTTP2(true)
+	ts.Start()
+
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	return ts, &http.Client{Transport: transport}
+}
+
+func invoke(t *testing.T, client *http.Client, url, method string, request proto.Message, headers map[string]string) *http.Response {
+	data := request.Marshal()
+	frame := make([]byte, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(data)))
+	copy(frame[5:], data)
+
+	r, err := http.NewRequest("POST", url+PathPrefix+method, bytes.NewReader(frame))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/grpc")
+	r.Header.Set("Te", "trailers")
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+
+	response, err := client.Do(r)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	return response
+}
+
+func readFrame(t *testing.T, body io.Reader, message proto.Message) bool {
+	var header [5]byte
+	_, err := io.ReadFull(body, header[:])
+	if err == io.EOF {
+		return false
+	}
+	require.NoError(t, err)
+
+	data := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	_, err = io.ReadFull(body, data)
+	require.NoError(t, err)
+	require.NoError(t, message.Unmarshal(data))
+	return true
+}
+
+// finish reads the rest of the body and returns trailers
+func finish(t *testing.T, response *http.Response) http.Header {
+	rest, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Empty(t, rest)
+	response.Body.Close()
+	return response.Trailer
+}
+
+func TestServerPayment(t *testing.T) {
+	s := NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request bridge.PaymentRequest
+		require.NoError(t, request.FromRequest(r))
+		assert.Equal(t, "tenant", r.Header.Get("X-Tenant"))
+
+		ledger := uint64(123)
+		switch request.Amount {
+		case "20":
+			assert.Equal(t, "bob*stellar.org", request.Destination)
+			assert.Equal(t, "USD", request.Path[0].Code)
+			assert.Equal(t, "BOPBPHMM", request.ForwardDestination.Fields.Get("swift"))
+			server.Write(w, &horizon.SubmitTransactionResponse{Hash: "abc", Ledger: &ledger})
+		case "30":
+			server.Write(w, &bridge.HeldPaymentResponse{ID: request.ID, Status: "held", SettleAt: time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)})
+		case "40":
+			assert.True(t, request.DryRun)
+			server.Write(w, &bridge.PaymentDryRunResponse{Failures: []bridge.PreflightFailure{{Operation: -1, Code: "transaction_bad_seq"}}})
+		default:
+			server.Write(w, bridge.PaymentUnderfunded)
+		}
+	}), nil)
+	ts, client := newTestServer(s)
+	defer ts.Close()
+
+	headers := map[string]string{"X-Tenant": "tenant"}
+	request := &proto.PaymentRequest{
+		Destination:        "bob*stellar.org",
+		Amount:             "20",
+		Path:               []*proto.Asset{{Code: "USD", Issuer: "GA"}},
+		ForwardDestination: &proto.ForwardDestination{Domain: "stellar.org", Fields: map[string]string{"swift": "BOPBPHMM"}},
+	}
+	response := invoke(t, client, ts.URL, "Payment", request, headers)
+	var payment proto.PaymentResponse
+	require.True(t, readFrame(t, response.Body, &payment))
+	assert.Equal(t, proto.PaymentResponse{Status: proto.PaymentStatusSuccess, Hash: "abc", Ledger: 123}, payment)
+	assert.Equal(t, "0", finish(t, response).Get("Grpc-Status"))
+
+	response = invoke(t, client, ts.URL, "Payment", &proto.PaymentRequest{ID: "payment-1", Destination: "bob*stellar.org", Amount: "30"}, headers)
+	payment = proto.PaymentResponse{}
+	require.True(t, readFrame(t, response.Body, &payment))
+	assert.Equal(t, proto.PaymentResponse{Status: proto.PaymentStatusHeld, ID: "payment-1", SettleAt: "2026-10-15T12:00:00Z"}, payment)
+	assert.Equal(t, "0", finish(t, response).Get("Grpc-Status"))
+
+	response = invoke(t, client, ts.URL, "Payment", &proto.PaymentRequest{Destination: "bob*stellar.org", Amount: "40", DryRun: true}, headers)
+	payment = proto.PaymentResponse{}
+	require.True(t, readFrame(t, response.Body, &payment))
+	assert.Equal(t, proto.PaymentStatusDryRun, payment.Status)
+	assert.False(t, payment.Valid)
+	require.Len(t, payment.Failures, 1)
+	assert.Equal(t, int32(-1), payment.Failures[0].Operation)
+	finish(t, response)
+
+	response = invoke(t, client, ts.URL, "Payment", &proto.PaymentRequest{Destination: "bob*stellar.org", Amount: "50"}, headers)
+	trailers := finish(t, response)
+	assert.Equal(t, "3", trailers.Get("Grpc-Status"))
+	assert.Equal(t, "Not enough funds to send this transaction.", trailers.Get("Grpc-Message"))
+	assert.Equal(t, "payment_underfunded", trailers.Get("Bridge-Error-Code"))
+}
+
+func TestServerBuilder(t *testing.T) {
+	s := NewServer(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request bridge.BuilderRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		require.NoError(t, request.Process())
+		assert.Equal(t, "GA", request.Source)
+		assert.Equal(t, []string{"SA"}, request.Signers)
+		require.Len(t, request.Operations, 1)
+		assert.Equal(t, bridge.OperationTypeInflation, request.Operations[0].Type)
+		server.Write(w, &bridge.BuilderResponse{TransactionEnvelope: "AAAA"})
+	}))
+	ts, client := newTestServer(s)
+	defer ts.Close()
+
+	request := &proto.BuilderRequest{
+		Source:     "GA",
+		Operations: []*proto.Operation{{Type: "inflation", BodyJSON: "{}"}},
+		Signers:    []string{"SA"},
+	}
+	response := invoke(t, client, ts.URL, "Builder", request, nil)
+	var built proto.BuilderResponse
+	require.True(t, readFrame(t, response.Body, &built))
+	assert.Equal(t, "AAAA", built.TransactionEnvelope)
+	assert.Equal(t, "0", finish(t, response).Get("Grpc-Status"))
+
+	request.Operations[0].BodyJSON = "{"
+	response = invoke(t, client, ts.URL, "Builder", request, nil)
+	trailers := finish(t, response)
+	assert.Equal(t, "3", trailers.Get("Grpc-Status"))
+	assert.Equal(t, "invalid_parameter", trailers.Get("Bridge-Error-Code"))
+
+	response = invoke(t, client, ts.URL, "Unknown", request, nil)
+	assert.Equal(t, "12", finish(t, response).Get("Grpc-Status"))
+}
+
+func TestServerTransactionStatus(t *testing.T) {
+	paymentID := "payment-1"
+	sending := &entities.SentTransaction{PaymentID: &paymentID, TransactionID: "abc", Status: entities.SentTransactionStatusSending, SubmittedAt: time.Now()}
+
+	s := NewServer(nil, nil)
+	ts, client := newTestServer(s)
+	defer ts.Close()
+
+	response := invoke(t, client, ts.URL, "GetTransactionStatus", &proto.TransactionStatusRequest{PaymentID: paymentID}, nil)
+	assert.Equal(t, "12", finish(t, response).Get("Grpc-Status"), "no database")
+
+	s.Transactions = testTransactions{"/payment-1": sending}
+	s.Statuses = NewStatusHub()
+
+	response = invoke(t, client, ts.URL, "GetTransactionStatus", &proto.TransactionStatusRequest{PaymentID: paymentID}, nil)
+	var status proto.TransactionStatus
+	require.True(t, readFrame(t, response.Body, &status))
+	assert.Equal(t, "sending", status.Status)
+	assert.Equal(t, "abc", status.TransactionID)
+	assert.Equal(t, "0", finish(t, response).Get("Grpc-Status"))
+
+	response = invoke(t, client, ts.URL, "GetTransactionStatus", &proto.TransactionStatusRequest{TransactionID: "def"}, nil)
+	assert.Equal(t, "5", finish(t, response).Get("Grpc-Status"))
+
+	response = invoke(t, client, ts.URL, "GetTransactionStatus", &proto.TransactionStatusRequest{}, nil)
+	assert.Equal(t, "3", finish(t, response).Get("Grpc-Status"))
+
+	t.Run("watch", func(t *testing.T) {
+		response := invoke(t, client, ts.URL, "WatchTransactionStatus", &proto.TransactionStatusRequest{PaymentID: paymentID}, nil)
+		var status proto.TransactionStatus
+		require.True(t, readFrame(t, response.Body, &status))
+		assert.Equal(t, "sending", status.Status)
+
+		// Other networks and payments are not streamed
+		ledger := uint64(123)
+		otherID := "payment-2"
+		s.Statuses.Watcher("testnet").TransactionStatus(&entities.SentTransaction{PaymentID: &paymentID, Status: entities.SentTransactionStatusFailure})
+		s.Statuses.Watcher("").TransactionStatus(&entities.SentTransaction{PaymentID: &otherID, Status: entities.SentTransactionStatusFailure})
+		s.Statuses.Watcher("").TransactionStatus(&entities.SentTransaction{PaymentID: &paymentID, TransactionID: "abc", Status: entities.SentTransactionStatusSuccess, Ledger: &ledger})
+
+		require.True(t, readFrame(t, response.Body, &status))
+		assert.Equal(t, "success", status.Status)
+		assert.Equal(t, uint64(123), status.Ledger)
+		assert.False(t, readFrame(t, response.Body, &status))
+		assert.Equal(t, "0", finish(t, response).Get("Grpc-Status"))
+	})
+
+	t.Run("watch until deadline", func(t *testing.T) {
+		response := invoke(t, client, ts.URL, "WatchTransactionStatus", &proto.TransactionStatusRequest{TransactionID: "def"}, map[string]string{"Grpc-Timeout": "50m"})
+		assert.Equal(t, "4", finish(t, response).Get("Grpc-Status"))
+	})
+}
+
+func TestServerRejectsHTTP1(t *testing.T) {
+	ts := httptest.NewServer(NewServer(nil, nil))
+	defer ts.Close()
+
+	response, err := http.Post(ts.URL+PathPrefix+"Payment", "application/grpc", bytes.NewReader([]byte{0, 0, 0, 0, 0}))
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusHTTPVersionNotSupported, response.StatusCode)
+}
+
+func TestEncodeMessage(t *testing.T) {
+	assert.Equal(t, "100%25 sure%0A%C5%BC", encodeMessage("100% sure\nż"))
+
+	timeout, ok := parseTimeout("50m")
+	assert.True(t, ok)
+	assert.Equal(t, 50*time.Millisecond, timeout)
+	_, ok = parseTimeout("123456789S")
+	assert.False(t, ok)
+}
@@ -0,0 +1,101 @@
+package grpcserver
+
+import (
+	"sync"
+
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/submitter"
+)
+
+// StatusHub sends statuses of sent transactions to WatchTransactionStatus
+// calls. Statuses are received from transaction submitters of every network,
+// see Watcher.
+type StatusHub struct {
+	mutex         sync.Mutex
+	subscriptions map[*subscription]bool
+}
+
+type subscription struct {
+	network       string
+	paymentID     string
+	transactionID string
+	// updates holds the latest status only, older statuses are dropped when
+	// the watcher is slow
+	updates chan *entities.SentTransaction
+}
+
+// NewStatusHub creates a new StatusHub
+func NewStatusHub() *StatusHub {
+	return &StatusHub{subscriptions: map[*subscription]bool{}}
+}
+
+// Watcher returns a submitter.StatusWatcher of the transaction submitter of
+// the named network (main network when empty)
+func (h *StatusHub) Watcher(network string) submitter.StatusWatcher {
+	return networkWatcher{hub: h, network: network}
+}
+
+type networkWatcher struct {
+	hub     *StatusHub
+	network string
+}
+
+// TransactionStatus implements submitter.StatusWatcher
+func (w networkWatcher) TransactionStatus(sentTransaction *entities.SentTransaction) {
+	w.hub.publish(w.network, sentTransaction)
+}
+
+// Subscribe returns a channel of statuses of the transaction sent to network
+// by payment ID or, when paymentID is empty, by transaction hash.
+// unsubscribe must be called when statuses are no longer read.
+func (h *StatusHub) Subscribe(network, paymentID, transactionID string) (updates <-chan *entities.SentTransaction, unsubscribe func()) {
+	s := &subscription{
+		network:       network,
+		paymentID:     paymentID,
+		transactionID: transactionID,
+		updates:       make(chan *entities.SentTransaction, 1),
+	}
+
+	h.mutex.Lock()
+	h.subscriptions[s] = true
+	h.mutex.Unlock()
+
+	return s.updates, func() {
+		h.mutex.Lock()
+		delete(h.subscriptions, s)
+		h.mutex.Unlock()
+	}
+}
+
+func (s *subscription) matches(network string, sentTransaction *entities.SentTransaction) bool {
+	if s.network != network {
+		return false
+	}
+	if s.paymentID != "" {
+		return sentTransaction.PaymentID != nil && *sentTransaction.PaymentID == s.paymentID
+	}
+	return sentTransaction.TransactionID == s.transactionID
+}
+
+func (h *StatusHub) publish(network string, sentTransaction *entities.SentTransaction) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for s := range h.subscriptions {
+		if !s.matches(network, sentTransaction) {
+			continue
+		}
+
+		// Never blocks: statuses are sent under the mutex, so the channel
+		// has room after dropping the previous status
+		select {
+		case s.updates <- sentTransaction:
+		default:
+			select {
+			case <-s.updates:
+			default:
+			}
+			s.updates <- sentTransaction
+		}
+	}
+}
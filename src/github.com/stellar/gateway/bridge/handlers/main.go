@@ -1,31 +1,135 @@
 package handlers
 
 import (
+	"github.com/stellar/gateway/audit"
+	"github.com/stellar/gateway/balances"
 	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/cache"
 	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/expected"
+	"github.com/stellar/gateway/export"
 	"github.com/stellar/gateway/external"
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/fees"
 	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/leader"
 	"github.com/stellar/gateway/listener"
 	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/profiles"
+	"github.com/stellar/gateway/redis"
+	"github.com/stellar/gateway/references"
+	"github.com/stellar/gateway/risk"
+	"github.com/stellar/gateway/sep24"
+	"github.com/stellar/gateway/snapshots"
+	"github.com/stellar/gateway/sorobanrpc"
 	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/gateway/webhooks"
 	"github.com/stellar/go/clients/federation"
 )
 
 // RequestHandler implements bridge server request handlers
 type RequestHandler struct {
-	Config               *config.Config                          `inject:""`
-	Client               net.HTTPClientInterface                 `inject:""`
-	Horizon              horizon.HorizonInterface                `inject:""`
-	Driver               db.Driver                               `inject:""`
-	Repository           db.RepositoryInterface                  `inject:""`
-	StellarTomlResolver  external.StellarTomlClientInterface     `inject:""`
-	FederationResolver   federation.ClientInterface              `inject:""`
-	TransactionSubmitter submitter.TransactionSubmitterInterface `inject:""`
-	PaymentListener      *listener.PaymentListener               `inject:""`
+	Config               *config.Config
+	Client               net.HTTPClientInterface
+	Horizon              horizon.HorizonInterface
+	Driver               db.Driver
+	Repository           db.RepositoryInterface
+	EntityManager        db.EntityManagerInterface
+	StellarTomlResolver  external.StellarTomlClientInterface
+	FederationResolver   federation.ClientInterface
+	TransactionSubmitter submitter.TransactionSubmitterInterface
+	PaymentListener      *listener.PaymentListener
+	// Features is nil when feature flags are not used (defaults are used)
+	Features *features.Flags
+	// Audit is nil when security events are not sent
+	Audit *audit.Emitter
+	// Exporter is nil when the bridge runs without a database
+	Exporter *export.Exporter
+	// Profiles is nil when destination profiles are not loaded
+	Profiles *profiles.Registry
+	// Snapshots is nil when federation snapshots are not recorded
+	Snapshots *snapshots.Recorder
+	// Risk is nil when payments are not checked by risk hooks. Programs
+	// embedding the bridge server can add their own hooks.
+	Risk *risk.Chain
+	// Velocity is nil when there are no velocity rules. It must be added to
+	// Risk, sent payments are recorded for it.
+	Velocity *risk.VelocityChecker
+	// Live holds config params changed by reloads, Config is used when nil
+	Live *config.Live
+	// Reload reloads the config, /admin/reload is not available when nil
+	Reload func() (reloaded, ignored []string, err error)
+	// Networks are additional networks by name
+	Networks map[string]*Network
+	// Expected is nil when expected payments are disabled
+	Expected *expected.Matcher
+	// DestinationAccounts is nil when destination accounts are not cached
+	DestinationAccounts *cache.AccountResolver
+	// Fees is nil when no fees are charged for payments
+	Fees *fees.Schedule
+	// Webhooks is nil when webhooks are disabled
+	Webhooks *webhooks.Dispatcher
+	// SEP24 is nil when SEP-24 transactions are disabled
+	SEP24 *sep24.Tracker
+	// References is nil when memo references are disabled
+	References *references.Store
+	// Balances is nil when balances are not monitored
+	Balances *balances.Monitor
+	// Leader is nil when leader election is disabled
+	Leader *leader.Elector
+	// Idempotency is nil when Redis is not configured, payment IDs are
+	// checked in the database then
+	Idempotency *redis.Idempotency
+	// SorobanRPC is nil when soroban_rpc_url is not set, invoke_host_function
+	// operations of /builder must contain soroban_data then
+	SorobanRPC *sorobanrpc.Client
+
+	heldSeeds *heldSeeds
+}
+
+// NewRequestHandler creates a new RequestHandler using the given dependencies.
+// Every dependency (except config and payment listener) is an interface so
+// programs embedding the bridge server can provide their own implementations.
+// driver, repository and entityManager can be nil when the bridge runs without
+// a database.
+func NewRequestHandler(
+	config *config.Config,
+	client net.HTTPClientInterface,
+	horizon horizon.HorizonInterface,
+	driver db.Driver,
+	repository db.RepositoryInterface,
+	entityManager db.EntityManagerInterface,
+	stellarTomlResolver external.StellarTomlClientInterface,
+	federationResolver federation.ClientInterface,
+	transactionSubmitter submitter.TransactionSubmitterInterface,
+	paymentListener *listener.PaymentListener,
+) RequestHandler {
+	return RequestHandler{
+		Config:               config,
+		Client:               client,
+		Horizon:              horizon,
+		Driver:               driver,
+		Repository:           repository,
+		EntityManager:        entityManager,
+		StellarTomlResolver:  stellarTomlResolver,
+		FederationResolver:   federationResolver,
+		TransactionSubmitter: transactionSubmitter,
+		PaymentListener:      paymentListener,
+		Live:                 config.NewLive(),
+		heldSeeds:            newHeldSeeds(),
+	}
+}
+
+// reloadable returns current values of config params that can be reloaded
+func (rh *RequestHandler) reloadable() config.Reloadable {
+	if rh.Live == nil {
+		return rh.Config.Reloadable()
+	}
+	return rh.Live.Get()
 }
 
 func (rh *RequestHandler) isAssetAllowed(code string, issuer string) bool {
-	for _, asset := range rh.Config.Assets {
+	for _, asset := range rh.reloadable().Assets {
 		if asset.Code == code && asset.Issuer == issuer {
 			return true
 		}
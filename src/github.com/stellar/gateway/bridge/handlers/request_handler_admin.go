@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/db/entities"
 	"github.com/stellar/gateway/horizon"
 	"github.com/stellar/gateway/protocols"
@@ -49,8 +53,21 @@ func (rh *RequestHandler) AdminReceivedPayment(c web.C, w http.ResponseWriter, r
 	}
 
 	var authData *compliance.AuthData
+	var repair *entities.ComplianceRepair
 	if paymentResponse.Memo.Type == "hash" && rh.Config.Compliance != "" {
-		authData, err = rh.getComplianceData(paymentResponse.Memo.Value)
+		repair, err = rh.Repository.GetComplianceRepairByOperationID(r.Context(), payment.OperationID)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error getting ComplianceRepair")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+
+		complianceMemo := paymentResponse.Memo.Value
+		if repair != nil {
+			complianceMemo = repair.ComplianceMemo
+		}
+
+		authData, err = rh.getComplianceData(complianceMemo)
 		if err != nil {
 			log.WithFields(log.Fields{"err": err}).Error("Error loading compliance data")
 			server.Write(w, protocols.InternalServerError)
@@ -59,13 +76,13 @@ func (rh *RequestHandler) AdminReceivedPayment(c web.C, w http.ResponseWriter, r
 	}
 
 	response := struct {
-		Payment   *entities.ReceivedPayment `json:"payment"`
-		Operation horizon.PaymentResponse   `json:"operation"`
-		AuthData  *compliance.AuthData      `json:"auth_data"`
-	}{payment, paymentResponse, authData}
+		Payment   *entities.ReceivedPayment  `json:"payment"`
+		Operation horizon.PaymentResponse    `json:"operation"`
+		AuthData  *compliance.AuthData       `json:"auth_data"`
+		Repair    *entities.ComplianceRepair `json:"compliance_repair,omitempty"`
+	}{payment, paymentResponse, authData, repair}
 
-	encoder := json.NewEncoder(w)
-	err = encoder.Encode(response)
+	err = server.WriteJSON(w, response)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err, "payments": payment}).Error("Error encoding ReceivedPayment")
 		server.Write(w, protocols.InternalServerError)
@@ -75,7 +92,7 @@ func (rh *RequestHandler) AdminReceivedPayment(c web.C, w http.ResponseWriter, r
 
 // AdminReceivedPaymentMemo implements /admin/received-payments/{memo_id} endpoint
 func (rh *RequestHandler) AdminReceivedPaymentMemo(c web.C, w http.ResponseWriter, r *http.Request) {
-	total, err := rh.Repository.GetReceivedPaymentTotalByMemoID(c.URLParams["memo_id"])
+	total, err := rh.Repository.GetReceivedPaymentTotalByMemoID(r.Context(), c.URLParams["memo_id"])
 
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error loading ReceivedPayments")
@@ -87,15 +104,11 @@ func (rh *RequestHandler) AdminReceivedPaymentMemo(c web.C, w http.ResponseWrite
 		TotalReceived string `json:"total_received"`
 	}
 
-	response, err := json.Marshal(MemoData{total})
-
+	err = server.WriteJSON(w, MemoData{total})
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error marshalling total_received")
 		server.Write(w, protocols.InternalServerError)
 	}
-
-	w.Write(response)
-
 }
 
 func (rh *RequestHandler) getComplianceData(memo string) (*compliance.AuthData, error) {
@@ -140,18 +153,45 @@ func (rh *RequestHandler) getComplianceData(memo string) (*compliance.AuthData,
 
 // AdminReceivedPayments implements /admin/received-payments endpoint
 func (rh *RequestHandler) AdminReceivedPayments(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	limit := 10
+	query, errorResponse := parseAdminListQuery(r)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	filter := db.ReceivedPaymentsFilter{
+		From:   query.from,
+		To:     query.to,
+		Status: r.URL.Query().Get("status"),
+		MemoID: r.URL.Query().Get("memo_id"),
+	}
 
-	payments, err := rh.Repository.GetReceivedPayments(page, limit)
+	payments, err := rh.Repository.GetReceivedPayments(r.Context(), filter, query.page, query.limit)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error loading ReceivedPayments")
 		server.Write(w, protocols.InternalServerError)
 		return
 	}
 
-	encoder := json.NewEncoder(w)
-	err = encoder.Encode(payments)
+	if query.csv {
+		records := [][]string{{"id", "operation_id", "transaction_id", "processed_at", "status", "memo_id", "amount", "paging_token"}}
+		for _, payment := range payments {
+			records = append(records, []string{
+				formatInt64(payment.ID),
+				payment.OperationID,
+				payment.TransactionID,
+				payment.ProcessedAt.UTC().Format(time.RFC3339),
+				payment.Status,
+				payment.MemoID,
+				payment.TransactionValue,
+				payment.PagingToken,
+			})
+		}
+		writeCSV(w, "received-payments.csv", records)
+		return
+	}
+
+	err = server.WriteJSON(w, payments)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err, "payments": payments}).Error("Error encoding ReceivedPayments")
 		server.Write(w, protocols.InternalServerError)
@@ -159,23 +199,164 @@ func (rh *RequestHandler) AdminReceivedPayments(w http.ResponseWriter, r *http.R
 	}
 }
 
-// AdminReceivedPayments implements /admin/sent-transactions endpoint
+// AdminSentTransactions implements /admin/sent-transactions endpoint
 func (rh *RequestHandler) AdminSentTransactions(w http.ResponseWriter, r *http.Request) {
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	limit := 10
+	query, errorResponse := parseAdminListQuery(r)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	filter := db.SentTransactionsFilter{
+		From:   query.from,
+		To:     query.to,
+		Status: r.URL.Query().Get("status"),
+		Source: r.URL.Query().Get("account"),
+	}
+
+	for name := range r.URL.Query() {
+		if strings.HasPrefix(name, "metadata[") && strings.HasSuffix(name, "]") {
+			if filter.Metadata == nil {
+				filter.Metadata = map[string]string{}
+			}
+			filter.Metadata[name[len("metadata["):len(name)-1]] = r.URL.Query().Get(name)
+		}
+	}
 
-	transactions, err := rh.Repository.GetSentTransactions(page, limit)
+	if filter.Source != "" && !protocols.IsValidAccountID(filter.Source) {
+		server.Write(w, protocols.NewInvalidParameterError("account", filter.Source, "Account ID must start with `G` and contain 56 alphanum characters."))
+		return
+	}
+
+	transactions, err := rh.Repository.GetSentTransactions(r.Context(), filter, query.page, query.limit)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error loading SentTransactions")
 		server.Write(w, protocols.InternalServerError)
 		return
 	}
 
-	encoder := json.NewEncoder(w)
-	err = encoder.Encode(transactions)
+	if query.csv {
+		records := [][]string{{"id", "payment_id", "region", "transaction_id", "status", "source", "submitted_at", "succeeded_at", "ledger", "bad_seq_resolution", "original_id"}}
+		for _, transaction := range transactions {
+			record := []string{
+				formatInt64(transaction.ID),
+				formatString(transaction.PaymentID),
+				transaction.Region,
+				transaction.TransactionID,
+				string(transaction.Status),
+				transaction.Source,
+				transaction.SubmittedAt.UTC().Format(time.RFC3339),
+				"",
+				"",
+				formatString(transaction.BadSeqResolution),
+				formatInt64(transaction.OriginalID),
+			}
+			if transaction.SucceededAt != nil {
+				record[7] = transaction.SucceededAt.UTC().Format(time.RFC3339)
+			}
+			if transaction.Ledger != nil {
+				record[8] = strconv.FormatUint(*transaction.Ledger, 10)
+			}
+			records = append(records, record)
+		}
+		writeCSV(w, "sent-transactions.csv", records)
+		return
+	}
+
+	err = server.WriteJSON(w, transactions)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err, "transactions": transactions}).Error("Error encoding SentTransactions")
 		server.Write(w, protocols.InternalServerError)
 		return
 	}
 }
+
+const (
+	adminListDefaultLimit = 10
+	adminListMaxLimit     = 1000
+)
+
+// adminListQuery contains query params common to admin list endpoints
+type adminListQuery struct {
+	from  *time.Time
+	to    *time.Time
+	page  int
+	limit int
+	csv   bool
+}
+
+func parseAdminListQuery(r *http.Request) (query adminListQuery, errorResponse *protocols.ErrorResponse) {
+	values := r.URL.Query()
+
+	query.page = 1
+	if page := values.Get("page"); page != "" {
+		var err error
+		query.page, err = strconv.Atoi(page)
+		if err != nil || query.page < 1 {
+			errorResponse = protocols.NewInvalidParameterError("page", page, "Page must be a positive integer.")
+			return
+		}
+	}
+
+	query.limit = adminListDefaultLimit
+	if limit := values.Get("limit"); limit != "" {
+		var err error
+		query.limit, err = strconv.Atoi(limit)
+		if err != nil || query.limit < 1 || query.limit > adminListMaxLimit {
+			errorResponse = protocols.NewInvalidParameterError("limit", limit, "Limit must be an integer between 1 and "+strconv.Itoa(adminListMaxLimit)+".")
+			return
+		}
+	}
+
+	for _, param := range []struct {
+		name  string
+		value **time.Time
+	}{{"from", &query.from}, {"to", &query.to}} {
+		value := values.Get(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			errorResponse = protocols.NewInvalidParameterError(param.name, value, "Time must be in RFC 3339 format, ex. 2018-03-01T12:00:00Z.")
+			return
+		}
+		t = t.UTC()
+		*param.value = &t
+	}
+
+	switch format := values.Get("format"); format {
+	case "", "json":
+	case "csv":
+		query.csv = true
+	default:
+		errorResponse = protocols.NewInvalidParameterError("format", format, "Format must be `json` or `csv`.")
+	}
+	return
+}
+
+// writeCSV writes records as a CSV file download
+func writeCSV(w http.ResponseWriter, filename string, records [][]string) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	writer := csv.NewWriter(w)
+	err := writer.WriteAll(records)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error writing CSV")
+	}
+}
+
+func formatInt64(value *int64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatInt(*value, 10)
+}
+
+func formatString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerAdminLists(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+	requestHandler := RequestHandler{Repository: mockRepository}
+
+	receivedServer := httptest.NewServer(http.HandlerFunc(requestHandler.AdminReceivedPayments))
+	defer receivedServer.Close()
+	sentServer := httptest.NewServer(http.HandlerFunc(requestHandler.AdminSentTransactions))
+	defer sentServer.Close()
+
+	get := func(server *httptest.Server, query string) (*http.Response, string) {
+		resp, err := http.Get(server.URL + "?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	from := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	id := int64(7)
+
+	mockRepository.On(
+		"GetReceivedPayments",
+		db.ReceivedPaymentsFilter{From: &from, To: &to, Status: "Success"},
+		2, 50,
+	).Return([]*entities.ReceivedPayment{{
+		ID:               &id,
+		OperationID:      "123",
+		TransactionID:    "abc",
+		ProcessedAt:      from,
+		Status:           "Success",
+		MemoID:           "1",
+		TransactionValue: "10.0000000",
+		PagingToken:      "123",
+	}}, nil).Twice()
+
+	resp, body := get(receivedServer, "from=2018-03-01T00:00:00Z&to=2018-04-01T02:00:00%2B02:00&status=Success&page=2&limit=50&format=csv")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	assert.Equal(
+		t,
+		"id,operation_id,transaction_id,processed_at,status,memo_id,amount,paging_token\n"+
+			"7,123,abc,2018-03-01T00:00:00Z,Success,1,10.0000000,123\n",
+		body,
+	)
+
+	resp, body = get(receivedServer, "from=2018-03-01T00:00:00Z&to=2018-04-01T00:00:00Z&status=Success&page=2&limit=50")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `"operation_id":"123"`)
+
+	ledger := uint64(100)
+	mockRepository.On(
+		"GetSentTransactions",
+		db.SentTransactionsFilter{Status: "success", Source: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"},
+		1, 10,
+	).Return([]*entities.SentTransaction{{
+		ID:            &id,
+		TransactionID: "abc",
+		Status:        entities.SentTransactionStatusSuccess,
+		Source:        "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ",
+		SubmittedAt:   from,
+		SucceededAt:   &from,
+		Ledger:        &ledger,
+	}}, nil).Once()
+
+	resp, body = get(sentServer, "status=success&account=GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ&format=csv")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(
+		t,
+		"id,payment_id,region,transaction_id,status,source,submitted_at,succeeded_at,ledger,bad_seq_resolution,original_id\n"+
+			"7,,,abc,success,GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ,2018-03-01T00:00:00Z,2018-03-01T00:00:00Z,100,,\n",
+		body,
+	)
+	mockRepository.AssertExpectations(t)
+
+	for _, invalid := range []struct {
+		server *httptest.Server
+		query  string
+		name   string
+	}{
+		{receivedServer, "page=0", "page"},
+		{receivedServer, "limit=1001", "limit"},
+		{receivedServer, "from=2018-03-01", "from"},
+		{receivedServer, "format=xml", "format"},
+		{sentServer, "account=GABC", "account"},
+	} {
+		resp, body = get(invalid.server, invalid.query)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, invalid.query)
+		response := test.StringToJSONMap(body)
+		assert.Equal(t, "invalid_parameter", response["code"], invalid.query)
+		assert.Equal(t, invalid.name, response["data"].(map[string]interface{})["name"], invalid.query)
+	}
+}
@@ -20,7 +20,7 @@ func (rh *RequestHandler) Authorize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = request.Validate(rh.Config.Assets, rh.Config.Accounts.IssuingAccountID)
+	err = request.Validate(rh.reloadable().Assets, rh.Config.Accounts.IssuingAccountID)
 	if err != nil {
 		errorResponse := err.(*protocols.ErrorResponse)
 		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
@@ -35,6 +35,7 @@ func (rh *RequestHandler) Authorize(w http.ResponseWriter, r *http.Request) {
 	)
 
 	submitResponse, err := rh.TransactionSubmitter.SubmitTransaction(
+		r.Context(),
 		nil,
 		rh.Config.Accounts.AuthorizingSeed,
 		operationMutator,
@@ -43,7 +44,7 @@ func (rh *RequestHandler) Authorize(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error submitting transaction")
-		server.Write(w, protocols.InternalServerError)
+		server.Write(w, bridge.ErrorFromHorizonError(err))
 		return
 	}
 
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/balances"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// AdminBalances implements /admin/balances endpoint. It returns results of
+// the last check of monitored balances, in order of balance_monitor config.
+func (rh *RequestHandler) AdminBalances(w http.ResponseWriter, r *http.Request) {
+	result := rh.Balances.Balances()
+	if result == nil {
+		result = []balances.Balance{}
+	}
+
+	err := server.WriteJSON(w, result)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding balances")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+}
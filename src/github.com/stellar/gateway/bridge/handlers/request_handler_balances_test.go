@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/balances"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminBalances(t *testing.T) {
+	account := "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5"
+	mockHorizon := new(mocks.MockHorizon)
+	rh := NewRequestHandler(&config.Config{}, nil, mockHorizon, nil, nil, nil, nil, nil, nil, nil)
+	rh.Balances = balances.NewMonitor(mockHorizon, []balances.Threshold{{AccountID: account, Min: 100 * amounts.One}}, 0, func() time.Time {
+		return time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	})
+
+	w := httptest.NewRecorder()
+	rh.AdminBalances(w, httptest.NewRequest("GET", "/admin/balances", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+
+	mockHorizon.On("LoadAccountFresh", account).Return(horizon.AccountResponse{
+		AccountID: account,
+		Balances:  []horizon.AccountBalance{{AssetType: "native", Balance: "50"}},
+	}, nil).Once()
+	rh.Balances.Run(context.Background())
+
+	w = httptest.NewRecorder()
+	rh.AdminBalances(w, httptest.NewRequest("GET", "/admin/balances", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{
+		"account_id": "`+account+`",
+		"balance": "50.0000000",
+		"reserve": "1.0000000",
+		"available": "49.0000000",
+		"min": "100.0000000",
+		"limit": "0.0000000",
+		"status": "low",
+		"checked_at": "2018-01-02T03:04:05Z"
+	}]`, w.Body.String())
+	mockHorizon.AssertExpectations(t)
+}
@@ -1,22 +1,27 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/horizon"
 	"github.com/stellar/gateway/protocols"
 	"github.com/stellar/gateway/protocols/bridge"
 	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/stellarxdr"
 	b "github.com/stellar/go/build"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
 )
 
 // Builder implements /builder endpoint
 func (rh *RequestHandler) Builder(w http.ResponseWriter, r *http.Request) {
 	var request bridge.BuilderRequest
-	var sequenceNumber uint64
 
 	decoder := json.NewDecoder(r.Body)
 	err := decoder.Decode(&request)
@@ -42,29 +47,63 @@ func (rh *RequestHandler) Builder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if request.SequenceNumber == "" {
-		accountResponse, err := rh.Horizon.LoadAccount(request.Source)
-		if err != nil {
-			log.WithFields(log.Fields{"err": err}).Error("Error when loading account")
-			server.Write(w, protocols.InternalServerError)
+	rh, errorResponse := rh.networkHandler(request.Network)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	for _, source := range request.SourceAccounts() {
+		if !auth.AllowedSource(r.Context(), source) {
+			log.WithFields(log.Fields{"source": source}).Warn("Source account is not allowed for the API key")
+			server.Write(w, protocols.SourceNotAllowedError)
 			return
 		}
-		sequenceNumber, err = strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
-		if err == nil {
-			// increment sequence number when none is provided
-			sequenceNumber = sequenceNumber + 1
+	}
+
+	// Soroban transactions are built as ENVELOPE_TYPE_TX envelopes
+	if invoke, ok := request.InvokeHostFunction(); ok {
+		txeB64, errorResponse := rh.buildSorobanTransaction(r.Context(), request, invoke)
+		if errorResponse != nil {
+			server.Write(w, errorResponse)
+			return
 		}
-	} else {
-		sequenceNumber, err = strconv.ParseUint(request.SequenceNumber, 10, 64)
+
+		server.Write(w, &bridge.BuilderResponse{TransactionEnvelope: txeB64})
+		return
 	}
 
-	if err != nil {
-		errorResponse := protocols.NewInvalidParameterError("sequence_number", request.SequenceNumber, "Sequence number must be a number")
-		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+	tx, errorResponse := rh.buildTransaction(request)
+	if errorResponse != nil {
 		server.Write(w, errorResponse)
 		return
 	}
 
+	txe, err := tx.Sign(request.Signers...)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "request": request}).Error("Error signing transaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	txeB64, err := txe.Base64()
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "request": request}).Error("Error encoding transaction envelope")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	server.Write(w, &bridge.BuilderResponse{TransactionEnvelope: txeB64})
+}
+
+// buildTransaction builds a transaction of a processed and validated request.
+func (rh *RequestHandler) buildTransaction(request bridge.BuilderRequest) (*b.TransactionBuilder, *protocols.ErrorResponse) {
+	sequenceNumber, errorResponse := rh.sequenceNumber(request)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+
 	mutators := []b.TransactionMutator{
 		b.SourceAccount{request.Source},
 		b.Sequence{sequenceNumber},
@@ -76,26 +115,144 @@ func (rh *RequestHandler) Builder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tx, err := b.Transaction(mutators...)
-
 	if err != nil {
 		log.WithFields(log.Fields{"err": err, "request": request}).Error("TransactionBuilder returned error")
-		server.Write(w, protocols.InternalServerError)
-		return
+		return nil, protocols.InternalServerError
 	}
 
-	txe, err := tx.Sign(request.Signers...)
+	return tx, nil
+}
+
+// buildSorobanTransaction builds and signs a transaction with the
+// invoke_host_function operation of a request and returns its base64 encoded
+// envelope. Footprint, resource fee and authorization entries are simulated
+// with soroban-rpc when soroban_data is not sent.
+func (rh *RequestHandler) buildSorobanTransaction(ctx context.Context, request bridge.BuilderRequest, invoke bridge.InvokeHostFunctionOperationBody) (string, *protocols.ErrorResponse) {
+	if invoke.SorobanData == "" && rh.SorobanRPC == nil {
+		errorResponse := protocols.NewInvalidParameterError("soroban_data", "", "Soroban data is required when soroban_rpc_url is not set.")
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		return "", errorResponse
+	}
+
+	sequenceNumber, errorResponse := rh.sequenceNumber(request)
+	if errorResponse != nil {
+		return "", errorResponse
+	}
+
+	tx, err := invoke.BuildSorobanTransaction(request.Source, sequenceNumber, nil)
 	if err != nil {
-		log.WithFields(log.Fields{"err": err, "request": request}).Error("Error signing transaction")
-		server.Write(w, protocols.InternalServerError)
-		return
+		log.WithFields(log.Fields{"err": err, "request": request}).Error("Error building Soroban transaction")
+		return "", protocols.InternalServerError
 	}
 
-	txeB64, err := txe.Base64()
+	if invoke.SorobanData == "" {
+		invoke, errorResponse = rh.simulateTransaction(ctx, invoke, tx)
+		if errorResponse != nil {
+			return "", errorResponse
+		}
+
+		tx, err = invoke.BuildSorobanTransaction(request.Source, sequenceNumber, nil)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error building simulated Soroban transaction")
+			return "", protocols.InternalServerError
+		}
+	}
+
+	hash, err := tx.Hash(rh.Config.NetworkPassphrase)
 	if err != nil {
-		log.WithFields(log.Fields{"err": err, "request": request}).Error("Error encoding transaction envelope")
-		server.Write(w, protocols.InternalServerError)
-		return
+		log.WithFields(log.Fields{"err": err}).Error("Error hashing Soroban transaction")
+		return "", protocols.InternalServerError
 	}
 
-	server.Write(w, &bridge.BuilderResponse{TransactionEnvelope: txeB64})
+	envelope := stellarxdr.TransactionV1Envelope{Tx: tx}
+	for _, signer := range request.Signers {
+		// Signers are validated in Validate()
+		kp := keypair.MustParse(signer)
+		signature, err := kp.SignDecorated(hash[:])
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error signing Soroban transaction")
+			return "", protocols.InternalServerError
+		}
+		envelope.Signatures = append(envelope.Signatures, signature)
+	}
+
+	txeB64, err := xdr.MarshalBase64(stellarxdr.TransactionEnvelope{Type: stellarxdr.EnvelopeTypeTx, V1: &envelope})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding transaction envelope")
+		return "", protocols.InternalServerError
+	}
+
+	return txeB64, nil
+}
+
+// simulateTransaction simulates tx with soroban-rpc and returns the operation
+// with the simulated SorobanTransactionData and, when none were sent,
+// authorization entries.
+func (rh *RequestHandler) simulateTransaction(ctx context.Context, invoke bridge.InvokeHostFunctionOperationBody, tx stellarxdr.Transaction) (bridge.InvokeHostFunctionOperationBody, *protocols.ErrorResponse) {
+	txeB64, err := xdr.MarshalBase64(stellarxdr.TransactionEnvelope{
+		Type: stellarxdr.EnvelopeTypeTx,
+		V1:   &stellarxdr.TransactionV1Envelope{Tx: tx},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding transaction envelope")
+		return invoke, protocols.InternalServerError
+	}
+
+	simulation, err := rh.SorobanRPC.SimulateTransaction(ctx, txeB64)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error simulating transaction")
+		return invoke, bridge.SorobanRPCUnavailable
+	}
+
+	if simulation.Error != "" || simulation.RestorePreamble != nil {
+		message := simulation.Error
+		if message == "" {
+			message = "Archived ledger entries must be restored first."
+		}
+		log.WithFields(log.Fields{"error": message}).Warn("Transaction simulation failed")
+		return invoke, &protocols.ErrorResponse{
+			Status:  bridge.SimulationFailed.Status,
+			Code:    bridge.SimulationFailed.Code,
+			Message: bridge.SimulationFailed.Message,
+			Data:    map[string]interface{}{"error": message},
+		}
+	}
+
+	// Simulated authorization entries are used when none were sent
+	if len(invoke.Auth) == 0 && len(simulation.Results) > 0 {
+		invoke.Auth = simulation.Results[0].Auth
+	}
+	invoke.SorobanData = simulation.TransactionData
+	return invoke, nil
+}
+
+// sequenceNumber returns the sequence number of a transaction built from a
+// request. The next sequence number of the source account is loaded from
+// Horizon when it's not sent.
+func (rh *RequestHandler) sequenceNumber(request bridge.BuilderRequest) (uint64, *protocols.ErrorResponse) {
+	var sequenceNumber uint64
+	var err error
+	if request.SequenceNumber == "" {
+		var accountResponse horizon.AccountResponse
+		accountResponse, err = rh.Horizon.LoadAccount(request.Source)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error when loading account")
+			return 0, bridge.ErrorFromHorizonError(err)
+		}
+		sequenceNumber, err = strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
+		if err == nil {
+			// increment sequence number when none is provided
+			sequenceNumber = sequenceNumber + 1
+		}
+	} else {
+		sequenceNumber, err = strconv.ParseUint(request.SequenceNumber, 10, 64)
+	}
+
+	if err != nil {
+		errorResponse := protocols.NewInvalidParameterError("sequence_number", request.SequenceNumber, "Sequence number must be a number")
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		return 0, errorResponse
+	}
+
+	return sequenceNumber, nil
 }
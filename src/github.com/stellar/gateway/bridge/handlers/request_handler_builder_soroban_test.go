@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/sorobanrpc"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/gateway/test"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerBuilderInvokeHostFunction(t *testing.T) {
+	c := &config.Config{NetworkPassphrase: "Test SDF Network ; September 2015"}
+	mockHorizon := new(mocks.MockHorizon)
+	requestHandler := RequestHandler{Config: c, Horizon: mockHorizon}
+
+	builderServer := httptest.NewServer(http.HandlerFunc(requestHandler.Builder))
+	defer builderServer.Close()
+
+	contractCode := xdr.Hash{1}
+	transactionData, err := xdr.MarshalBase64(stellarxdr.SorobanTransactionData{
+		Resources: stellarxdr.SorobanResources{
+			Footprint: stellarxdr.LedgerFootprint{
+				ReadOnly: []stellarxdr.LedgerKey{{
+					Type:         stellarxdr.LedgerEntryTypeContractCode,
+					ContractCode: &stellarxdr.LedgerKeyContractCode{Hash: contractCode},
+				}},
+			},
+			Instructions: 100000,
+		},
+		ResourceFee: 25000,
+	})
+	require.NoError(t, err)
+
+	var simulated []string
+	simulation := sorobanrpc.SimulateTransactionResponse{TransactionData: transactionData}
+	sorobanServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Method string `json:"method"`
+			Params struct {
+				Transaction string `json:"transaction"`
+			} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "simulateTransaction", body.Method)
+		simulated = append(simulated, body.Params.Transaction)
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": simulation})
+	}))
+	defer sorobanServer.Close()
+
+	signer := keypair.MustParse("SABY7FRMMJWPBTKQQ2ZN43AUJQ3Z2ZAK36VYSG2SPE2ABNQXA66H5E5G")
+	data := test.StringToJSONMap(`{
+		"source": "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5",
+		"operations": [{
+			"type": "invoke_host_function",
+			"body": {
+				"contract_id": "CA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQGAXE",
+				"function": "increment",
+				"args": [{"type": "u32", "value": "5"}]
+			}
+		}],
+		"signers": ["SABY7FRMMJWPBTKQQ2ZN43AUJQ3Z2ZAK36VYSG2SPE2ABNQXA66H5E5G"]
+	}`)
+	mockHorizon.On("LoadAccount", "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5").
+		Return(horizon.AccountResponse{SequenceNumber: "123"}, nil)
+
+	// Soroban data is required without soroban-rpc
+	statusCode, response := net.JSONGetResponse(builderServer, data)
+	assert.Equal(t, 400, statusCode)
+	assert.Contains(t, string(response), "soroban_data")
+
+	requestHandler.SorobanRPC = sorobanrpc.New(sorobanServer.URL, http.DefaultClient)
+	builderServer.Config.Handler = http.HandlerFunc(requestHandler.Builder)
+
+	statusCode, response = net.JSONGetResponse(builderServer, data)
+	require.Equal(t, 200, statusCode, string(response))
+	require.Len(t, simulated, 1)
+
+	var builderResponse struct {
+		TransactionEnvelope string `json:"transaction_envelope"`
+	}
+	require.NoError(t, json.Unmarshal(response, &builderResponse))
+	var envelope stellarxdr.TransactionEnvelope
+	require.NoError(t, stellarxdr.SafeUnmarshalBase64(builderResponse.TransactionEnvelope, &envelope))
+	require.Equal(t, stellarxdr.EnvelopeTypeTx, envelope.Type)
+
+	// Fee includes the simulated resource fee
+	tx := envelope.V1.Tx
+	assert.Equal(t, xdr.Uint32(25100), tx.Fee)
+	assert.Equal(t, xdr.SequenceNumber(124), tx.SeqNum)
+	require.NotNil(t, tx.Ext.SorobanData)
+	assert.Equal(t, contractCode, tx.Ext.SorobanData.Resources.Footprint.ReadOnly[0].ContractCode.Hash)
+
+	// The simulated transaction has no Soroban data and signatures
+	var simulatedEnvelope stellarxdr.TransactionEnvelope
+	require.NoError(t, stellarxdr.SafeUnmarshalBase64(simulated[0], &simulatedEnvelope))
+	assert.Equal(t, int32(0), simulatedEnvelope.V1.Tx.Ext.V)
+	assert.Empty(t, simulatedEnvelope.V1.Signatures)
+
+	// Transaction is signed on the network
+	hash, err := envelope.Hash(c.NetworkPassphrase)
+	require.NoError(t, err)
+	require.Len(t, envelope.V1.Signatures, 1)
+	assert.NoError(t, signer.Verify(hash[:], envelope.V1.Signatures[0].Signature))
+
+	// Failed simulations are returned
+	simulation = sorobanrpc.SimulateTransactionResponse{Error: "HostError: Error(Contract, #1)"}
+	statusCode, response = net.JSONGetResponse(builderServer, data)
+	assert.Equal(t, 400, statusCode)
+	assert.Contains(t, string(response), "simulation_failed")
+	assert.Contains(t, string(response), "HostError")
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/cache"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+)
+
+// AdminCacheFlush implements /admin/cache/flush endpoint. It drops cached
+// stellar.toml files, federation responses and destination accounts.
+func (rh *RequestHandler) AdminCacheFlush(w http.ResponseWriter, r *http.Request) {
+	response := bridge.CacheFlushResponse{Status: "ok", Flushed: []string{}}
+	resolvers := []struct {
+		name     string
+		resolver interface{}
+	}{
+		{"stellar_toml", rh.StellarTomlResolver},
+		{"federation", rh.FederationResolver},
+	}
+	for _, r := range resolvers {
+		if flusher, ok := r.resolver.(cache.Flusher); ok {
+			flusher.Flush()
+			response.Flushed = append(response.Flushed, r.name)
+		}
+	}
+	if rh.DestinationAccounts != nil {
+		rh.DestinationAccounts.Flush()
+		response.Flushed = append(response.Flushed, "destination_accounts")
+	}
+
+	log.WithFields(log.Fields{"caches": response.Flushed}).Info("Cache flushed")
+	server.Write(w, &response)
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/cache"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerAdminCacheFlush(t *testing.T) {
+	requestHandler := RequestHandler{
+		StellarTomlResolver: cache.NewStellarTomlResolver(&mocks.MockStellartomlResolver{}, cache.NewLRU(10, time.Minute, 0)),
+		FederationResolver:  &mocks.MockFederationResolver{},
+	}
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.AdminCacheFlush))
+	defer testServer.Close()
+
+	resp, err := http.Post(testServer.URL, "", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// Federation responses are not cached
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"status": "ok", "flushed": ["stellar_toml"]}`, string(body))
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+)
+
+// ComplianceRepair implements /admin/compliance-repair endpoint. It associates
+// a received payment with the compliance attachment stored under a different
+// memo (after manual verification), records it in ComplianceRepair table and
+// sends the payment to receive callback again.
+func (rh *RequestHandler) ComplianceRepair(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.ComplianceRepairRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if rh.Config.Compliance == "" {
+		server.Write(w, bridge.ComplianceRepairNotAvailable)
+		return
+	}
+
+	_, err = strconv.ParseInt(request.OperationID, 10, 64)
+	if err != nil {
+		server.Write(w, protocols.NewInvalidParameterError("operation_id", request.OperationID, "Operation ID must be a number."))
+		return
+	}
+
+	existingPayment, err := rh.Repository.GetReceivedPaymentByOperationID(r.Context(), request.OperationID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting ReceivedPayment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if existingPayment == nil {
+		server.Write(w, bridge.ComplianceRepairPaymentNotFound)
+		return
+	}
+
+	authData, err := rh.getComplianceData(request.Memo)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading compliance data")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if authData == nil {
+		server.Write(w, bridge.ComplianceRepairAttachmentNotFound)
+		return
+	}
+
+	operation, err := rh.Horizon.LoadOperation(request.OperationID)
+	if err != nil {
+		server.Write(w, &bridge.ReprocessResponse{Status: "error", Message: err.Error()})
+		return
+	}
+
+	err = rh.Horizon.LoadMemo(&operation)
+	if err != nil {
+		server.Write(w, &bridge.ReprocessResponse{Status: "error", Message: err.Error()})
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"operation_id":    request.OperationID,
+		"original_memo":   operation.Memo.Value,
+		"compliance_memo": request.Memo,
+		"verified_by":     request.VerifiedBy,
+	}).Info("Repairing compliance data of received payment")
+
+	repairErr := rh.PaymentListener.RepairPayment(operation, request.Memo)
+
+	repair := &entities.ComplianceRepair{
+		OperationID:    request.OperationID,
+		OriginalMemo:   operation.Memo.Value,
+		ComplianceMemo: request.Memo,
+		VerifiedBy:     request.VerifiedBy,
+		Reason:         request.Reason,
+		RepairedAt:     clock.Now(),
+		Status:         "Success",
+	}
+
+	if repairErr != nil {
+		repair.Status = repairErr.Error()
+	}
+
+	err = rh.EntityManager.Persist(repair)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting ComplianceRepair")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if repairErr != nil {
+		server.Write(w, &bridge.ReprocessResponse{Status: "error", Message: repairErr.Error()})
+		return
+	}
+
+	server.Write(w, &bridge.ReprocessResponse{Status: "ok"})
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/listener"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	callback "github.com/stellar/gateway/protocols/compliance"
+	"github.com/stellar/gateway/test"
+	"github.com/stellar/go/protocols/compliance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerComplianceRepair(t *testing.T) {
+	originalMemo := "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	repairedMemo := "a28b7dc1d6bd7f5aa1d1b07a1c43bacd2ab13004c0a5a3e0f674c87d10b4e4b5"
+
+	attachmentString, _ := json.Marshal(compliance.Attachment{
+		Transaction: compliance.Transaction{Route: "jed*stellar.org"},
+	})
+	authString, _ := json.Marshal(compliance.AuthData{AttachmentJSON: string(attachmentString)})
+	receiveResponse, _ := json.Marshal(callback.ReceiveResponse{Data: string(authString)})
+
+	var callbackRequest url.Values
+	externalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/receive":
+			if r.PostForm.Get("memo") != repairedMemo {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(receiveResponse)
+		case "/callback":
+			callbackRequest = r.PostForm
+		}
+	}))
+	defer externalServer.Close()
+
+	c := &config.Config{
+		Compliance: externalServer.URL,
+		Callbacks: config.Callbacks{
+			Receive: []string{externalServer.URL + "/callback"},
+		},
+		Accounts: config.Accounts{
+			ReceivingAccountID: "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
+		},
+		Assets: []config.Asset{
+			{Code: "USD", Issuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"},
+		},
+	}
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+	mockEntityManager := new(mocks.MockEntityManager)
+
+	paymentListener, err := listener.NewPaymentListener(c, mockEntityManager, mockHorizon, mockRepository, mocks.Now)
+	require.NoError(t, err)
+
+	requestHandler := NewRequestHandler(c, http.DefaultClient, mockHorizon, nil, mockRepository, mockEntityManager, nil, nil, nil, &paymentListener)
+
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.ComplianceRepair))
+	defer testServer.Close()
+
+	operation := horizon.PaymentResponse{
+		ID:          "1",
+		Type:        "payment",
+		From:        "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+		To:          "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
+		PagingToken: "2",
+		Amount:      "200",
+		AssetCode:   "USD",
+		AssetIssuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+	}
+
+	params := url.Values{
+		"operation_id": {"1"},
+		"memo":         {repairedMemo},
+		"verified_by":  {"jed"},
+		"reason":       {"Sender used wrong memo hash"},
+	}
+
+	Convey("Given compliance repair request", t, func() {
+		Convey("When payment has not been received it should return error", func() {
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+
+			statusCode, response := net.GetResponse(testServer, params)
+			assert.Equal(t, http.StatusNotFound, statusCode)
+			assert.Equal(t, "compliance_repair_payment_not_found", test.StringToJSONMap(string(response))["code"])
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("When compliance server has no attachment it should return error", func() {
+			existingPayment := &entities.ReceivedPayment{OperationID: "1", Status: "Error response from compliance server"}
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(existingPayment, nil).Once()
+
+			wrongParams := url.Values{
+				"operation_id": {"1"},
+				"memo":         {originalMemo},
+				"verified_by":  {"jed"},
+			}
+
+			statusCode, response := net.GetResponse(testServer, wrongParams)
+			assert.Equal(t, http.StatusNotFound, statusCode)
+			assert.Equal(t, "compliance_repair_attachment_not_found", test.StringToJSONMap(string(response))["code"])
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("When attachment exists it should re-fire receive callback and save the repair", func() {
+			existingPayment := &entities.ReceivedPayment{OperationID: "1", Status: "Error response from compliance server"}
+			existingPayment.SetExists()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(existingPayment, nil).Twice()
+			mockHorizon.On("LoadOperation", "1").Return(operation, nil).Once()
+			// Memo loaded by the handler is not loaded again by the listener
+			mockHorizon.On("LoadMemo", mock.AnythingOfType("*horizon.PaymentResponse")).Return(nil).Once().Run(func(args mock.Arguments) {
+				payment := args.Get(0).(*horizon.PaymentResponse)
+				payment.Memo.Type = "hash"
+				payment.Memo.Value = originalMemo
+			})
+			mockEntityManager.On("Persist", existingPayment).Return(nil).Twice()
+			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ComplianceRepair")).Return(nil).Once().Run(func(args mock.Arguments) {
+				repair := args.Get(0).(*entities.ComplianceRepair)
+				assert.Equal(t, "1", repair.OperationID)
+				assert.Equal(t, originalMemo, repair.OriginalMemo)
+				assert.Equal(t, repairedMemo, repair.ComplianceMemo)
+				assert.Equal(t, "jed", repair.VerifiedBy)
+				assert.Equal(t, "Sender used wrong memo hash", repair.Reason)
+				assert.Equal(t, "Success", repair.Status)
+			})
+
+			statusCode, response := net.GetResponse(testServer, params)
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Equal(t, "ok", test.StringToJSONMap(string(response))["status"])
+			assert.Equal(t, "Success", existingPayment.Status)
+			assert.Equal(t, originalMemo, callbackRequest.Get("memo"))
+			assert.Equal(t, "jed*stellar.org", callbackRequest.Get("route"))
+			assert.Equal(t, string(authString), callbackRequest.Get("data"))
+			mockHorizon.AssertExpectations(t)
+			mockRepository.AssertExpectations(t)
+			mockEntityManager.AssertExpectations(t)
+		})
+	})
+}
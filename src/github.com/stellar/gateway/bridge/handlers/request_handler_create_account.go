@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go/keypair"
+)
+
+// CreateAccount implements /create-account endpoint. The account is created
+// by accounts.funder_seed, options of generated key pairs are set in the same
+// transaction signed by the new account.
+func (rh *RequestHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	var request bridge.CreateAccountRequest
+
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&request)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error decoding request")
+		server.Write(w, protocols.NewInvalidParameterError("", "", "Request body is not a valid JSON"))
+		return
+	}
+
+	err = request.Validate(rh.Config.CreateAccount.MaxStartingBalanceOrDefault())
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	funder, err := keypair.Parse(rh.Config.Accounts.FunderSeed)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error parsing accounts.funder_seed")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if !auth.AllowedSource(r.Context(), funder.Address()) {
+		log.WithFields(log.Fields{"source": funder.Address()}).Warn("Source account is not allowed for the API key")
+		server.Write(w, protocols.SourceNotAllowedError)
+		return
+	}
+
+	response := bridge.CreateAccountResponse{PublicKey: request.PublicKey}
+	ctx := r.Context()
+
+	if request.PublicKey == "" {
+		kp, err := keypair.Random()
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error generating random keypair")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+
+		response.PublicKey = kp.Address()
+		response.PrivateKey = kp.Seed()
+		ctx = submitter.WithCosigners(ctx, kp)
+	}
+
+	startingBalance := request.StartingBalance
+	if startingBalance == "" {
+		startingBalance = rh.Config.CreateAccount.StartingBalanceOrDefault()
+	}
+
+	submitResponse, err := rh.TransactionSubmitter.SubmitTransaction(
+		ctx,
+		nil,
+		rh.Config.Accounts.FunderSeed,
+		request.ToTransactionMutator(response.PublicKey, startingBalance),
+		nil,
+	)
+
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error submitting transaction")
+		server.Write(w, bridge.ErrorFromHorizonError(err))
+		return
+	}
+
+	errorResponse := bridge.ErrorFromHorizonResponse(submitResponse)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	response.Transaction = submitResponse
+	server.Write(w, &response)
+}
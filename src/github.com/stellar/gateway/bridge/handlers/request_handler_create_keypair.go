@@ -1,10 +1,10 @@
 package handlers
 
 import (
-	"encoding/json"
-	log "github.com/sirupsen/logrus"
 	"net/http"
 
+	log "github.com/sirupsen/logrus"
+
 	"github.com/stellar/gateway/protocols"
 	"github.com/stellar/gateway/server"
 	"github.com/stellar/go/keypair"
@@ -22,13 +22,12 @@ func (rh *RequestHandler) CreateKeypair(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error generating random keypair")
 		server.Write(w, protocols.InternalServerError)
+		return
 	}
 
-	response, err := json.Marshal(KeyPair{kp.Address(), kp.Seed()})
+	err = server.WriteJSON(w, KeyPair{kp.Address(), kp.Seed()})
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error marshalling random keypair")
 		server.Write(w, protocols.InternalServerError)
 	}
-
-	w.Write(response)
 }
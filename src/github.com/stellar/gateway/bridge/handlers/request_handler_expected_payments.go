@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/expected"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// ExpectedPaymentResponse is an expected payment with payments matched with it
+type ExpectedPaymentResponse struct {
+	*entities.ExpectedPayment
+	Payments []*entities.PaymentMatch `json:"payments"`
+}
+
+// CreateExpectedPayment implements POST /expected-payments endpoint. Received
+// payments of the asset and memo are matched with the expected payment until
+// the amount is received.
+func (rh *RequestHandler) CreateExpectedPayment(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.ExpectedPaymentRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	existing, err := rh.Repository.GetExpectedPayment(r.Context(), request.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting ExpectedPayment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if existing != nil {
+		server.Write(w, bridge.ExpectedPaymentExists)
+		return
+	}
+
+	// Checked in Validate
+	amount, _ := amounts.ParseAmount(request.Amount)
+	now := clock.Now()
+	expectedPayment := &entities.ExpectedPayment{
+		ExpectedID:  request.ID,
+		Amount:      amount,
+		AssetCode:   request.AssetCode,
+		AssetIssuer: request.AssetIssuer,
+		MemoType:    request.MemoType,
+		Memo:        request.Memo,
+		Status:      entities.ExpectedPaymentStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err = rh.EntityManager.Persist(expectedPayment)
+	if err != nil {
+		// Unique index of IDs fails when created by a concurrent request
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting ExpectedPayment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"id":     expectedPayment.ExpectedID,
+		"amount": expectedPayment.Amount.String(),
+		"memo":   expectedPayment.Memo,
+	}).Info("Expected payment created")
+
+	rh.writeExpectedPayment(w, ExpectedPaymentResponse{expectedPayment, []*entities.PaymentMatch{}})
+}
+
+// ExpectedPayment implements GET /expected-payments/{id} endpoint. It returns
+// the expected payment and payments matched with it.
+func (rh *RequestHandler) ExpectedPayment(c web.C, w http.ResponseWriter, r *http.Request) {
+	expectedPayment, errorResponse := rh.getExpectedPayment(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	matches, err := rh.Repository.GetPaymentMatches(r.Context(), expectedPayment.ExpectedID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting PaymentMatches")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if matches == nil {
+		matches = []*entities.PaymentMatch{}
+	}
+
+	rh.writeExpectedPayment(w, ExpectedPaymentResponse{expectedPayment, matches})
+}
+
+// CancelExpectedPayment implements DELETE /expected-payments/{id} endpoint.
+// Received payments are not matched with cancelled expected payments.
+func (rh *RequestHandler) CancelExpectedPayment(c web.C, w http.ResponseWriter, r *http.Request) {
+	expectedPayment, errorResponse := rh.getExpectedPayment(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if !expectedPayment.Open() {
+		server.Write(w, bridge.ExpectedPaymentNotOpen)
+		return
+	}
+
+	expectedPayment.Status = entities.ExpectedPaymentStatusCancelled
+	expectedPayment.UpdatedAt = clock.Now()
+	err := rh.EntityManager.Persist(expectedPayment)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting ExpectedPayment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{"id": expectedPayment.ExpectedID}).Info("Expected payment cancelled")
+	rh.writeExpectedPayment(w, expectedPayment)
+}
+
+// AdminUnmatchedPayments implements GET /admin/unmatched-payments endpoint. It
+// returns received payments that did not match any expected payment, oldest
+// first.
+func (rh *RequestHandler) AdminUnmatchedPayments(w http.ResponseWriter, r *http.Request) {
+	limit := adminListDefaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > adminListMaxLimit {
+			server.Write(w, protocols.NewInvalidParameterError("limit", value, "Limit must be an integer between 1 and "+strconv.Itoa(adminListMaxLimit)+"."))
+			return
+		}
+	}
+
+	matches, err := rh.Repository.GetUnmatchedPayments(r.Context(), limit)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting unmatched payments")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if matches == nil {
+		matches = []*entities.PaymentMatch{}
+	}
+
+	rh.writeExpectedPayment(w, matches)
+}
+
+// AdminMatchPayment implements POST /admin/unmatched-payments/{operation_id}/match
+// endpoint. It matches an unmatched payment with the expected payment sent in
+// expected_payment_id param after a review.
+func (rh *RequestHandler) AdminMatchPayment(c web.C, w http.ResponseWriter, r *http.Request) {
+	expectedID := r.PostFormValue("expected_payment_id")
+	if expectedID == "" {
+		server.Write(w, protocols.NewMissingParameter("expected_payment_id"))
+		return
+	}
+
+	match, err := rh.Repository.GetPaymentMatch(r.Context(), c.URLParams["operation_id"])
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting PaymentMatch")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if match == nil {
+		server.Write(w, bridge.PaymentMatchNotFound)
+		return
+	}
+
+	expectedPayment, errorResponse := rh.getExpectedPayment(r, expectedID)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	err = rh.Expected.Assign(r.Context(), match, expectedPayment, clock.Now())
+	switch err {
+	case nil:
+	case expected.ErrAlreadyMatched:
+		server.Write(w, bridge.PaymentAlreadyMatched)
+		return
+	case expected.ErrNotOpen:
+		server.Write(w, bridge.ExpectedPaymentNotOpen)
+		return
+	default:
+		log.WithFields(log.Fields{"err": err}).Error("Error matching payment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	rh.writeExpectedPayment(w, match)
+}
+
+func (rh *RequestHandler) getExpectedPayment(r *http.Request, id string) (*entities.ExpectedPayment, *protocols.ErrorResponse) {
+	expectedPayment, err := rh.Repository.GetExpectedPayment(r.Context(), id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting ExpectedPayment")
+		return nil, protocols.InternalServerError
+	}
+
+	if expectedPayment == nil {
+		return nil, bridge.ExpectedPaymentNotFound
+	}
+	return expectedPayment, nil
+}
+
+func (rh *RequestHandler) writeExpectedPayment(w http.ResponseWriter, value interface{}) {
+	err := server.WriteJSON(w, value)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding ExpectedPayment")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
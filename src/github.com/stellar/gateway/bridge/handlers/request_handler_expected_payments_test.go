@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/expected"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestExpectedPayments(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	rh := NewRequestHandler(&config.Config{}, nil, nil, driver, repository, entityManager, nil, nil, nil, nil)
+	rh.Expected = expected.NewMatcher(repository, entityManager)
+
+	create := func(values url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/expected-payments", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		rh.CreateExpectedPayment(w, r)
+		return w
+	}
+	get := func(id string) ExpectedPaymentResponse {
+		w := httptest.NewRecorder()
+		rh.ExpectedPayment(web.C{URLParams: map[string]string{"id": id}}, w, httptest.NewRequest("GET", "/expected-payments/"+id, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response ExpectedPaymentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	values := url.Values{
+		"id":         {"invoice-1"},
+		"amount":     {"25"},
+		"asset_code": {"XLM"},
+		"memo_type":  {"hash"},
+		"memo":       {"ab5c8f9e7a3d1c2b4e6f8a0c2e4a6c8e0a2c4e6a8c0e2a4c6e8a0c2e4a6c8e0a"},
+	}
+	w := create(values)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"amount":"25.0000000"`)
+
+	w = create(values)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	values.Set("id", "invoice-2")
+	values.Set("memo", "123")
+	w = create(values)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	values.Set("memo_type", "id")
+	values.Set("memo", "0123")
+	values.Set("amount", "0")
+	w = create(values)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	values.Set("amount", "10")
+	w = create(values)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Hash memos are stored like memos of received payments
+	response := get("invoice-1")
+	assert.Equal(t, "q1yPnno9HCtOb4oMLkpsjgosTmqMDipMbooMLkpsjgo=", response.Memo)
+	assert.Equal(t, entities.ExpectedPaymentStatusPending, response.Status)
+	assert.Empty(t, response.Payments)
+
+	payment := horizon.PaymentResponse{ID: "1", Type: "payment", AssetType: "native", Amount: "10"}
+	payment.Memo.Type = "hash"
+	payment.Memo.Value = response.Memo
+	match, err := rh.Expected.Match(context.Background(), payment, response.CreatedAt)
+	require.NoError(t, err)
+	assert.Equal(t, "invoice-1", match.ExpectedID)
+
+	response = get("invoice-1")
+	assert.Equal(t, entities.ExpectedPaymentStatusPartiallyPaid, response.Status)
+	assert.Equal(t, "10.0000000", response.ReceivedAmount.String())
+	require.Len(t, response.Payments, 1)
+	assert.Equal(t, "1", response.Payments[0].OperationID)
+
+	payment.ID = "2"
+	payment.Memo.Type = "id"
+	payment.Memo.Value = "124"
+	_, err = rh.Expected.Match(context.Background(), payment, response.CreatedAt)
+	require.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	rh.AdminUnmatchedPayments(w, httptest.NewRequest("GET", "/admin/unmatched-payments", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var unmatched []entities.PaymentMatch
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &unmatched))
+	require.Len(t, unmatched, 1)
+	assert.Equal(t, "2", unmatched[0].OperationID)
+
+	match = func() *entities.PaymentMatch {
+		r := httptest.NewRequest("POST", "/admin/unmatched-payments/2/match", strings.NewReader("expected_payment_id=invoice-2"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		rh.AdminMatchPayment(web.C{URLParams: map[string]string{"operation_id": "2"}}, w, r)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var match entities.PaymentMatch
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &match))
+		return &match
+	}()
+	assert.Equal(t, entities.ExpectedPaymentStatusMatched, match.Status)
+	assert.Equal(t, entities.ExpectedPaymentStatusMatched, get("invoice-2").Status)
+
+	// Paid expected payments cannot be cancelled
+	w = httptest.NewRecorder()
+	rh.CancelExpectedPayment(web.C{URLParams: map[string]string{"id": "invoice-2"}}, w, httptest.NewRequest("DELETE", "/expected-payments/invoice-2", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	rh.CancelExpectedPayment(web.C{URLParams: map[string]string{"id": "invoice-1"}}, w, httptest.NewRequest("DELETE", "/expected-payments/invoice-1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, entities.ExpectedPaymentStatusCancelled, get("invoice-1").Status)
+
+	w = httptest.NewRecorder()
+	rh.ExpectedPayment(web.C{URLParams: map[string]string{"id": "unknown"}}, w, httptest.NewRequest("GET", "/expected-payments/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/export"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// AdminExport implements /admin/export endpoint. It returns the statement of
+// received and sent payments of a given day.
+func (rh *RequestHandler) AdminExport(w http.ResponseWriter, r *http.Request) {
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		server.Write(w, protocols.NewMissingParameter("date"))
+		return
+	}
+
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		server.Write(w, protocols.NewInvalidParameterError("date", date, "Date must be in YYYY-MM-DD format."))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if format != export.FormatCSV && format != export.FormatCAMT053 {
+		server.Write(w, protocols.NewInvalidParameterError("format", format, "Format must be `csv` or `camt053`."))
+		return
+	}
+
+	statement, err := rh.Exporter.Statement(r.Context(), day)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "date": date}).Error("Error loading statement")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+export.FileName(statement, format)+`"`)
+
+	err = export.Write(w, statement, format)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "date": date}).Error("Error writing statement")
+	}
+}
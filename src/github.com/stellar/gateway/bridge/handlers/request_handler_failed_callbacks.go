@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// FailedCallbackResponse is a failed receive callback with its payload
+type FailedCallbackResponse struct {
+	*entities.FailedCallback
+	Payload map[string]string `json:"payload"`
+}
+
+func newFailedCallbackResponse(failedCallback *entities.FailedCallback) FailedCallbackResponse {
+	response := FailedCallbackResponse{failedCallback, map[string]string{}}
+	// Payload is always encoded by the listener
+	values, _ := failedCallback.Values()
+	for key := range values {
+		response.Payload[key] = values.Get(key)
+	}
+	return response
+}
+
+// AdminFailedCallbacks implements GET /admin/callbacks/failed endpoint. It
+// returns receive callbacks that failed on all callback endpoints, oldest
+// first. Delivered and discarded callbacks are returned using status param.
+func (rh *RequestHandler) AdminFailedCallbacks(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = entities.FailedCallbackStatusFailed
+	case entities.FailedCallbackStatusFailed, entities.FailedCallbackStatusDelivered, entities.FailedCallbackStatusDiscarded:
+	default:
+		server.Write(w, protocols.NewInvalidParameterError("status", status, "Status must be one of: failed, delivered, discarded."))
+		return
+	}
+
+	limit := adminListDefaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > adminListMaxLimit {
+			server.Write(w, protocols.NewInvalidParameterError("limit", value, "Limit must be an integer between 1 and "+strconv.Itoa(adminListMaxLimit)+"."))
+			return
+		}
+	}
+
+	failedCallbacks, err := rh.Repository.GetFailedCallbacks(r.Context(), status, limit)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting failed callbacks")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	response := make([]FailedCallbackResponse, 0, len(failedCallbacks))
+	for _, failedCallback := range failedCallbacks {
+		response = append(response, newFailedCallbackResponse(failedCallback))
+	}
+
+	rh.writeFailedCallback(w, response)
+}
+
+// AdminFailedCallback implements GET /admin/callbacks/failed/{id} endpoint. It
+// returns the failed callback of a received payment with operation ID id
+// including the full payload and the last error.
+func (rh *RequestHandler) AdminFailedCallback(c web.C, w http.ResponseWriter, r *http.Request) {
+	failedCallback, errorResponse := rh.getFailedCallback(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	rh.writeFailedCallback(w, newFailedCallbackResponse(failedCallback))
+}
+
+// AdminRetryFailedCallback implements POST /admin/callbacks/failed/{id}/retry
+// endpoint. It sends the failed callback again right away using the current
+// callback endpoints.
+func (rh *RequestHandler) AdminRetryFailedCallback(c web.C, w http.ResponseWriter, r *http.Request) {
+	failedCallback, errorResponse := rh.getFailedCallback(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if failedCallback.Status != entities.FailedCallbackStatusFailed {
+		server.Write(w, bridge.FailedCallbackNotFailed)
+		return
+	}
+
+	err := rh.PaymentListener.RetryCallback(failedCallback)
+	if err != nil {
+		// The error is stored in last_error of the returned callback
+		log.WithFields(log.Fields{"err": err, "operation_id": failedCallback.OperationID}).Error("Error retrying failed callback")
+	} else {
+		log.WithFields(log.Fields{"operation_id": failedCallback.OperationID}).Info("Failed callback delivered")
+	}
+
+	rh.writeFailedCallback(w, newFailedCallbackResponse(failedCallback))
+}
+
+// AdminDiscardFailedCallback implements POST /admin/callbacks/failed/{id}/discard
+// endpoint. Discarded callbacks are not sent again, they are kept for audit.
+func (rh *RequestHandler) AdminDiscardFailedCallback(c web.C, w http.ResponseWriter, r *http.Request) {
+	failedCallback, errorResponse := rh.getFailedCallback(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if failedCallback.Status != entities.FailedCallbackStatusFailed {
+		server.Write(w, bridge.FailedCallbackNotFailed)
+		return
+	}
+
+	failedCallback.Status = entities.FailedCallbackStatusDiscarded
+	failedCallback.UpdatedAt = clock.Now()
+	err := rh.EntityManager.Persist(failedCallback)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting FailedCallback")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{"operation_id": failedCallback.OperationID}).Info("Failed callback discarded")
+	rh.writeFailedCallback(w, newFailedCallbackResponse(failedCallback))
+}
+
+func (rh *RequestHandler) getFailedCallback(r *http.Request, operationID string) (*entities.FailedCallback, *protocols.ErrorResponse) {
+	failedCallback, err := rh.Repository.GetFailedCallback(r.Context(), operationID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting FailedCallback")
+		return nil, protocols.InternalServerError
+	}
+
+	if failedCallback == nil {
+		return nil, bridge.FailedCallbackNotFound
+	}
+	return failedCallback, nil
+}
+
+func (rh *RequestHandler) writeFailedCallback(w http.ResponseWriter, value interface{}) {
+	err := server.WriteJSON(w, value)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding FailedCallback")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestAdminFailedCallbacks(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	rh := NewRequestHandler(&config.Config{}, nil, nil, driver, repository, entityManager, nil, nil, nil, nil)
+
+	now := time.Now()
+	require.NoError(t, entityManager.Persist(&entities.FailedCallback{
+		OperationID: "100",
+		Payload:     "amount=20.0000000&id=100&memo=1",
+		LastError:   "Error response from receive callback",
+		Attempts:    1,
+		Status:      entities.FailedCallbackStatusFailed,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}))
+
+	list := func(query string) (int, []FailedCallbackResponse) {
+		w := httptest.NewRecorder()
+		rh.AdminFailedCallbacks(w, httptest.NewRequest("GET", "/admin/callbacks/failed"+query, nil))
+		var response []FailedCallbackResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w.Code, response
+	}
+
+	code, failedCallbacks := list("")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, failedCallbacks, 1)
+	assert.Equal(t, "100", failedCallbacks[0].OperationID)
+	assert.Equal(t, map[string]string{"id": "100", "amount": "20.0000000", "memo": "1"}, failedCallbacks[0].Payload)
+	assert.Equal(t, "Error response from receive callback", failedCallbacks[0].LastError)
+
+	code, _ = list("?status=unknown")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	discard := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		rh.AdminDiscardFailedCallback(web.C{URLParams: map[string]string{"id": id}}, w, httptest.NewRequest("POST", "/admin/callbacks/failed/"+id+"/discard", nil))
+		return w
+	}
+
+	w := discard("100")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"status":"discarded"`)
+
+	w = discard("100")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = discard("101")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	code, failedCallbacks = list("")
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, failedCallbacks)
+
+	code, failedCallbacks = list("?status=discarded")
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, failedCallbacks, 1)
+
+	w = httptest.NewRecorder()
+	rh.AdminFailedCallback(web.C{URLParams: map[string]string{"id": "100"}}, w, httptest.NewRequest("GET", "/admin/callbacks/failed/100", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"payload":{"amount":"20.0000000","id":"100","memo":"1"}`)
+}
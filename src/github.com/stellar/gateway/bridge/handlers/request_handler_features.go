@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+)
+
+// AdminFeatureFlags implements GET /admin/feature-flags endpoint. It returns
+// known features with values from the config file and database overrides.
+func (rh *RequestHandler) AdminFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	statuses, err := rh.Features.List(r.Context())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading feature flags")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	err = server.WriteJSON(w, statuses)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding feature flags")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+}
+
+// AdminSetFeatureFlag implements POST /admin/feature-flags endpoint. It
+// overrides a feature flag for a tenant (or all tenants when tenant is empty).
+func (rh *RequestHandler) AdminSetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.FeatureFlagRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if !features.IsKnown(request.Name) {
+		server.Write(w, protocols.NewInvalidParameterError("name", request.Name, "Unknown feature."))
+		return
+	}
+
+	flag, err := rh.Repository.GetFeatureFlag(r.Context(), request.Name, request.Tenant)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting FeatureFlag")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if flag == nil {
+		flag = &entities.FeatureFlag{Name: request.Name, Tenant: request.Tenant}
+	}
+
+	flag.Enabled = request.Enabled
+	flag.UpdatedBy = request.UpdatedBy
+	flag.UpdatedAt = clock.Now()
+
+	err = rh.EntityManager.Persist(flag)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting FeatureFlag")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+	rh.Features.Invalidate(flag.Name)
+
+	log.WithFields(log.Fields{
+		"feature":    flag.Name,
+		"tenant":     flag.Tenant,
+		"enabled":    flag.Enabled,
+		"updated_by": flag.UpdatedBy,
+	}).Info("Feature flag updated")
+
+	err = server.WriteJSON(w, flag)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding FeatureFlag")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+}
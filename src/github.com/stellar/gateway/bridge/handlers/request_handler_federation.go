@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/cache"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/go/protocols/federation"
+)
+
+// FederationResolve implements /federation/resolve endpoint. It resolves a
+// Stellar address using the same resolver (cache and stellar.toml policies)
+// as /payment endpoint.
+func (rh *RequestHandler) FederationResolve(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.FederationResolveRequest{}
+	request.FromRequest(r)
+
+	err := request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	var response *federation.NameResponse
+	source := bridge.FederationSourceRemote
+	if resolver, ok := rh.FederationResolver.(cache.CachedNameResolver); ok {
+		var cached bool
+		response, cached, err = resolver.LookupByAddressCached(request.Address)
+		if cached {
+			source = bridge.FederationSourceCache
+		}
+	} else {
+		response, err = rh.FederationResolver.LookupByAddress(request.Address)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"address": request.Address, "source": source, "err": err}).Print("Cannot resolve address")
+		server.Write(w, bridge.ErrorFromResolveError(err))
+		return
+	}
+
+	if !protocols.IsValidAccountID(response.AccountID) {
+		log.WithFields(log.Fields{"address": request.Address, "AccountId": response.AccountID}).Print("Invalid AccountId returned by federation server")
+		server.Write(w, bridge.PaymentCannotResolveDestination)
+		return
+	}
+
+	server.Write(w, &bridge.FederationResolveResponse{
+		Address:   request.Address,
+		AccountID: response.AccountID,
+		MemoType:  response.MemoType,
+		Memo:      response.Memo.String(),
+		Source:    source,
+	})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/cache"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/test"
+	"github.com/stellar/go/protocols/federation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerFederationResolve(t *testing.T) {
+	mockFederationResolver := new(mocks.MockFederationResolver)
+	resolver := cache.NewFederationResolver(mockFederationResolver, cache.NewLRU(10, time.Minute, 0))
+	requestHandler := RequestHandler{FederationResolver: resolver}
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.FederationResolve))
+	defer testServer.Close()
+
+	get := func(address string) (int, map[string]interface{}) {
+		resp, err := http.Get(testServer.URL + "?address=" + url.QueryEscape(address))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, test.StringToJSONMap(string(body))
+	}
+
+	mockFederationResolver.On("LookupByAddress", "bob*stellar.org").Return(
+		&federation.NameResponse{
+			AccountID: "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS",
+			MemoType:  "id",
+			Memo:      federation.Memo{Value: "123"},
+		},
+		nil,
+	).Once()
+
+	statusCode, response := get("bob*stellar.org")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, map[string]interface{}{
+		"address":    "bob*stellar.org",
+		"account_id": "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS",
+		"memo_type":  "id",
+		"memo":       "123",
+		"source":     "remote",
+	}, response)
+
+	// Second request is served from cache
+	statusCode, response = get("bob*stellar.org")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "cache", response["source"])
+	mockFederationResolver.AssertExpectations(t)
+
+	mockFederationResolver.On("LookupByAddress", "alice*stellar.org").Return(
+		(*federation.NameResponse)(nil),
+		errors.New("not found"),
+	).Once()
+
+	statusCode, response = get("alice*stellar.org")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "cannot_resolve_destination", response["code"])
+
+	statusCode, response = get("GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "invalid_parameter", response["code"])
+
+	statusCode, response = get("")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, "missing_parameter", response["code"])
+}
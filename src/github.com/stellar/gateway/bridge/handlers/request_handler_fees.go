@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"math/big"
+
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/fees"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	b "github.com/stellar/go/build"
+)
+
+// paymentFee returns the fee of a payment or nil when no fee is charged. Fees
+// of path payments are computed from the amount and asset received by the
+// destination, the sent amount is not known until the transaction is applied.
+func (rh *RequestHandler) paymentFee(request *bridge.PaymentRequest) (*horizon.PaymentFee, *protocols.ErrorResponse) {
+	if rh.Fees == nil {
+		return nil, nil
+	}
+
+	assetCode, assetIssuer := request.AssetCode, request.AssetIssuer
+	if assetCode == "" || assetIssuer == "" {
+		assetCode, assetIssuer = "XLM", ""
+	}
+
+	// Amount is checked in Validate
+	amount, _ := amounts.Parse(request.Amount)
+	fee, err := rh.Fees.Fee(features.Tenant(request.HTTPRequest), assetCode, assetIssuer, amount)
+	if err == fees.ErrFeeExceedsAmount {
+		return nil, bridge.PaymentFeeExceedsAmount
+	} else if err != nil {
+		return nil, protocols.NewInvalidParameterError("amount", request.Amount, "Amount with fee is too large.")
+	}
+
+	return fee, nil
+}
+
+// operations adds all its operations to a transaction
+type operations []b.TransactionMutator
+
+// MutateTransaction implements build.TransactionMutator
+func (ops operations) MutateTransaction(tx *b.TransactionBuilder) error {
+	for _, op := range ops {
+		err := op.MutateTransaction(tx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withFee returns the payment operation followed by a payment of the fee to
+// the fee collection account, so the payment and its fee are atomic. The fee
+// of a path payment is paid by a path payment from the send asset through the
+// same path.
+func withFee(operation b.TransactionMutator, request *bridge.PaymentRequest, fee *horizon.PaymentFee) b.TransactionMutator {
+	mutators := []interface{}{b.Destination{fee.Account}}
+	if fee.AssetIssuer == "" {
+		mutators = append(mutators, b.NativeAmount{fee.Fee})
+	} else {
+		mutators = append(mutators, b.CreditAmount{fee.AssetCode, fee.AssetIssuer, fee.Fee})
+	}
+
+	if request.SendMax != "" {
+		mutators = append(mutators, pathPayWith(request, sendMaxShare(request, fee.Fee, true)))
+	}
+	return operations{operation, b.Payment(mutators...)}
+}
+
+// pathPayWith returns the send asset and path of a path payment with sendMax
+func pathPayWith(request *bridge.PaymentRequest, sendMax string) b.PayWithPath {
+	var sendAsset b.Asset
+	if request.SendAssetCode == "" && request.SendAssetIssuer == "" {
+		sendAsset = b.NativeAsset()
+	} else {
+		sendAsset = b.CreditAsset(request.SendAssetCode, request.SendAssetIssuer)
+	}
+
+	payWith := b.PayWith(sendAsset, sendMax)
+	for _, asset := range request.Path {
+		payWith = payWith.Through(asset.ToBaseAsset())
+	}
+	return payWith
+}
+
+// sendMaxShare returns the share of send_max of a path payment delivering
+// amount of the request amount, so the payment and its fee are sent at most
+// at the price limited by send_max. Fee shares are rounded up, payment shares
+// down.
+func sendMaxShare(request *bridge.PaymentRequest, amount string, roundUp bool) string {
+	// Values are checked in Validate
+	sendMax, _ := amounts.Parse(request.SendMax)
+	total, _ := amounts.Parse(request.Amount)
+	part, _ := amounts.Parse(amount)
+	if part == total {
+		return request.SendMax
+	}
+
+	share := new(big.Int).Mul(big.NewInt(sendMax), big.NewInt(part))
+	if roundUp {
+		share.Add(share, big.NewInt(total-1))
+	}
+	share.Quo(share, big.NewInt(total))
+	return amounts.String(share.Int64())
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentFees(t *testing.T) {
+	issuer := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	feeAccount := "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"
+	c := &config.Config{
+		Accounts: config.Accounts{
+			// GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		Fees: config.Fees{
+			Account: feeAccount,
+			Rules: []config.FeeRule{
+				{AssetCode: "USD", Tenant: "acme", Flat: "1", Mode: "deduct"},
+				{AssetCode: "USD", Flat: "1", Percentage: "0.5"},
+			},
+		},
+	}
+	mockSubmitter := new(mocks.MockTransactionSubmitter)
+	rh := NewRequestHandler(c, nil, nil, nil, nil, nil, nil, nil, mockSubmitter, nil)
+	rh.Fees = c.Fees.Schedule()
+
+	var operations []xdr.Operation
+	ledger := uint64(100)
+	mockSubmitter.On("SubmitTransaction", (*string)(nil), c.Accounts.BaseSeed, mock.Anything, nil).Run(func(args mock.Arguments) {
+		builder := &b.TransactionBuilder{TX: &xdr.Transaction{}}
+		require.NoError(t, args.Get(2).(b.TransactionMutator).MutateTransaction(builder))
+		operations = builder.TX.Operations
+	}).Return(horizon.SubmitTransactionResponse{Hash: "tx", Ledger: &ledger}, nil)
+
+	var extra url.Values
+	send := func(amount, tenant string) *httptest.ResponseRecorder {
+		values := url.Values{
+			"destination":  {"GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"},
+			"amount":       {amount},
+			"asset_code":   {"USD"},
+			"asset_issuer": {issuer},
+		}
+		for key, value := range extra {
+			values[key] = value
+		}
+		r := httptest.NewRequest("POST", "/payment", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if tenant != "" {
+			r = r.WithContext(auth.WithKey(r.Context(), &auth.Key{ID: tenant + "-key", Tenant: tenant}))
+		}
+		w := httptest.NewRecorder()
+		rh.Payment(w, r)
+		return w
+	}
+
+	// Fee is paid to the fee account in a second operation
+	w := send("100", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response horizon.SubmitTransactionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Fee)
+	assert.Equal(t, "101.5000000", response.Fee.GrossAmount)
+	assert.Equal(t, "1.5000000", response.Fee.Fee)
+	assert.Equal(t, "100.0000000", response.Fee.NetAmount)
+
+	require.Len(t, operations, 2)
+	assert.Equal(t, xdr.Int64(1000000000), operations[0].Body.MustPaymentOp().Amount)
+	feePayment := operations[1].Body.MustPaymentOp()
+	assert.Equal(t, feeAccount, feePayment.Destination.Address())
+	assert.Equal(t, xdr.Int64(15000000), feePayment.Amount)
+
+	// Fee is deducted from the amount
+	w = send("100", "acme")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, operations, 2)
+	assert.Equal(t, xdr.Int64(990000000), operations[0].Body.MustPaymentOp().Amount)
+	assert.Equal(t, xdr.Int64(10000000), operations[1].Body.MustPaymentOp().Amount)
+
+	w = send("1", "acme")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "fee_exceeds_amount")
+
+	// Fee of a path payment is paid from the send asset with a share of
+	// send_max
+	extra = url.Values{"send_max": {"203"}, "send_asset_code": {"EUR"}, "send_asset_issuer": {issuer}}
+	w = send("100", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, operations, 2)
+	payment := operations[0].Body.MustPathPaymentOp()
+	assert.Equal(t, xdr.Int64(1000000000), payment.DestAmount)
+	assert.Equal(t, xdr.Int64(2030000000), payment.SendMax)
+	feePathPayment := operations[1].Body.MustPathPaymentOp()
+	assert.Equal(t, feeAccount, feePathPayment.Destination.Address())
+	assert.Equal(t, xdr.Int64(15000000), feePathPayment.DestAmount)
+	assert.Equal(t, xdr.Int64(30450000), feePathPayment.SendMax)
+	var sendAssetType xdr.AssetType
+	var sendAssetCode, sendAssetIssuer string
+	require.NoError(t, feePathPayment.SendAsset.Extract(&sendAssetType, &sendAssetCode, &sendAssetIssuer))
+	assert.Equal(t, "EUR", sendAssetCode)
+
+	// send_max is split between the net amount and the deducted fee
+	w = send("100", "acme")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, operations, 2)
+	assert.Equal(t, xdr.Int64(990000000), operations[0].Body.MustPathPaymentOp().DestAmount)
+	assert.Equal(t, xdr.Int64(2009700000), operations[0].Body.MustPathPaymentOp().SendMax)
+	assert.Equal(t, xdr.Int64(20300000), operations[1].Body.MustPathPaymentOp().SendMax)
+
+	// Compliance server builds the transaction, the fee can't be added
+	c.Compliance = "http://compliance"
+	extra = url.Values{"use_compliance": {"true"}, "sender": {"alice*stellar.org"}}
+	w = send("100", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "fee_not_supported")
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// AdminLeadership implements /admin/leadership endpoint. It returns the
+// leadership status of this replica and ID of the current leader.
+func (rh *RequestHandler) AdminLeadership(w http.ResponseWriter, r *http.Request) {
+	err := server.WriteJSON(w, rh.Leader.Status())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding leadership status")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+}
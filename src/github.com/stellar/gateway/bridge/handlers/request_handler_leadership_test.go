@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/leader"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminLeadership(t *testing.T) {
+	rh := NewRequestHandler(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	w := httptest.NewRecorder()
+	rh.AdminLeadership(w, httptest.NewRequest("GET", "/admin/leadership", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"enabled": false, "leader": true}`, w.Body.String())
+
+	now := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockRepository := new(mocks.MockRepository)
+	mockRepository.On("AcquireLeaderLease", "bridge", "bridge-1", now, now.Add(15*time.Second)).
+		Return(&entities.LeaderLease{Holder: "bridge-0"}, nil)
+	rh.Leader = leader.NewElector(leader.NewDatabase("bridge", mockRepository), "bridge-1", 15*time.Second, time.Hour, func() time.Time { return now })
+	// Run returns after the first renewal when ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rh.Leader.Run(ctx)
+
+	w = httptest.NewRecorder()
+	rh.AdminLeadership(w, httptest.NewRequest("GET", "/admin/leadership", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"enabled": true, "id": "bridge-1", "leader": false, "holder": "bridge-0"}`, w.Body.String())
+	mockRepository.AssertExpectations(t)
+}
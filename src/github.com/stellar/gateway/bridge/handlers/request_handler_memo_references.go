@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/references"
+	"github.com/stellar/gateway/server"
+)
+
+// MemoReferenceResponse is a memo derived from a reference
+type MemoReferenceResponse struct {
+	Reference string `json:"reference"`
+	MemoType  string `json:"memo_type"`
+	Memo      string `json:"memo"`
+}
+
+// MemoReference implements POST /memo-references endpoint. It returns the
+// hash memo of a reference and stores it, so payments received with the memo
+// (ex. deposits of a customer) are sent to the receive callback with the
+// reference.
+func (rh *RequestHandler) MemoReference(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.MemoReferenceRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	memo, err := rh.References.Register(r.Context(), request.Reference)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error registering memo reference")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	err = server.WriteJSON(w, MemoReferenceResponse{
+		Reference: request.Reference,
+		MemoType:  references.MemoType,
+		Memo:      memo,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding MemoReference")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+// applyReference sets the memo of the payment to the hash memo derived from
+// its reference
+func (rh *RequestHandler) applyReference(ctx context.Context, request *bridge.PaymentRequest) *protocols.ErrorResponse {
+	if rh.References == nil {
+		return protocols.NewInvalidParameterError("reference", request.Reference, "Memo references are not enabled.")
+	}
+
+	if request.Memo != "" || request.MemoType != "" {
+		return protocols.NewInvalidParameterError("reference", request.Reference, "reference cannot be sent with memo or memo_type.")
+	}
+
+	// Compliance payments use the hash of the attachment as memo
+	if request.ExtraMemo != "" || request.PrivateNote != "" || request.UseCompliance {
+		return protocols.NewInvalidParameterError("reference", request.Reference, "reference cannot be sent with compliance payments.")
+	}
+
+	memo, err := rh.References.Register(ctx, request.Reference)
+	if err == references.ErrInvalidReference {
+		return protocols.NewInvalidParameterError("reference", request.Reference, err.Error())
+	} else if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error registering memo reference")
+		return protocols.InternalServerError
+	}
+
+	request.MemoType = references.MemoType
+	request.Memo = memo
+	return nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/references"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoReferences(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	rh := NewRequestHandler(&config.Config{}, nil, nil, driver, repository, entityManager, nil, nil, nil, nil)
+	rh.References = references.NewStore([]byte("0123456789abcdef0123456789abcdef"), true, repository, entityManager)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/memo-references", strings.NewReader(url.Values{"reference": {"invoice-1"}}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rh.MemoReference(w, r)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response MemoReferenceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "invoice-1", response.Reference)
+	assert.Equal(t, "hash", response.MemoType)
+	assert.Equal(t, rh.References.Derive("invoice-1"), response.Memo)
+
+	reference, err := rh.References.Resolve(context.Background(), "hash", response.Memo)
+	require.NoError(t, err)
+	assert.Equal(t, "invoice-1", reference)
+
+	payment := func(values url.Values) (*bridge.PaymentRequest, *protocols.ErrorResponse) {
+		request := &bridge.PaymentRequest{}
+		r := httptest.NewRequest("POST", "/payment", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		require.NoError(t, request.FromRequest(r))
+		return request, rh.applyReference(context.Background(), request)
+	}
+
+	request, errorResponse := payment(url.Values{"reference": {"invoice-2"}})
+	require.Nil(t, errorResponse)
+	assert.Equal(t, "hash", request.MemoType)
+	assert.Equal(t, rh.References.Derive("invoice-2"), request.Memo)
+
+	_, errorResponse = payment(url.Values{"reference": {"invoice-2"}, "memo_type": {"text"}, "memo": {"invoice-2"}})
+	require.NotNil(t, errorResponse)
+	assert.Equal(t, "reference", errorResponse.Data["name"])
+
+	_, errorResponse = payment(url.Values{"reference": {"invoice-2"}, "use_compliance": {"true"}})
+	require.NotNil(t, errorResponse)
+
+	rh.References = nil
+	_, errorResponse = payment(url.Values{"reference": {"invoice-2"}})
+	require.NotNil(t, errorResponse)
+}
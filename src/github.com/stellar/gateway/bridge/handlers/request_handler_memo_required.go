@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// AdminMemoRequiredDestinations implements GET /admin/memo-required-destinations
+// endpoint. It returns destinations learned from returned payments and
+// destinations added manually.
+func (rh *RequestHandler) AdminMemoRequiredDestinations(w http.ResponseWriter, r *http.Request) {
+	destinations, err := rh.Repository.GetMemoRequiredDestinations(r.Context())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading MemoRequiredDestinations")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	rh.writeMemoRequiredDestination(w, destinations)
+}
+
+// AdminAddMemoRequiredDestination implements POST /admin/memo-required-destinations
+// endpoint. Existing destinations are marked as added manually.
+func (rh *RequestHandler) AdminAddMemoRequiredDestination(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.MemoRequiredDestinationRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if request.Profile != "" && rh.Profiles.Get(request.Profile) == nil {
+		errorResponse := protocols.NewInvalidParameterError("profile", request.Profile, "Destination profile not found.")
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	destination, err := rh.Repository.GetMemoRequiredDestination(r.Context(), request.AccountID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting MemoRequiredDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if destination == nil {
+		destination = &entities.MemoRequiredDestination{
+			AccountID: request.AccountID,
+			CreatedAt: clock.Now(),
+		}
+	}
+
+	destination.Source = entities.MemoRequiredSourceManual
+	destination.Reason = request.Reason
+	destination.Profile = nil
+	if request.Profile != "" {
+		destination.Profile = &request.Profile
+	}
+
+	err = rh.EntityManager.Persist(destination)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting MemoRequiredDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	rh.writeMemoRequiredDestination(w, destination)
+}
+
+// AdminRemoveMemoRequiredDestination implements
+// DELETE /admin/memo-required-destinations/{account_id} endpoint
+func (rh *RequestHandler) AdminRemoveMemoRequiredDestination(c web.C, w http.ResponseWriter, r *http.Request) {
+	destination, err := rh.Repository.GetMemoRequiredDestination(r.Context(), c.URLParams["account_id"])
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting MemoRequiredDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if destination == nil {
+		server.Write(w, bridge.MemoRequiredDestinationNotFound)
+		return
+	}
+
+	err = rh.EntityManager.Delete(destination)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error deleting MemoRequiredDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	rh.writeMemoRequiredDestination(w, destination)
+}
+
+func (rh *RequestHandler) writeMemoRequiredDestination(w http.ResponseWriter, value interface{}) {
+	err := server.WriteJSON(w, value)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding MemoRequiredDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// AdminNamedDestinations implements GET /admin/destinations endpoint. It
+// returns all entries of the address book.
+func (rh *RequestHandler) AdminNamedDestinations(w http.ResponseWriter, r *http.Request) {
+	destinations, err := rh.Repository.GetNamedDestinations(r.Context())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading NamedDestinations")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	rh.writeNamedDestination(w, destinations)
+}
+
+// AdminNamedDestination implements GET /admin/destinations/{name} endpoint
+func (rh *RequestHandler) AdminNamedDestination(c web.C, w http.ResponseWriter, r *http.Request) {
+	destination, err := rh.Repository.GetNamedDestination(r.Context(), c.URLParams["name"])
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting NamedDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if destination == nil {
+		server.Write(w, bridge.NamedDestinationNotFound)
+		return
+	}
+
+	rh.writeNamedDestination(w, destination)
+}
+
+// AdminSetNamedDestination implements POST /admin/destinations endpoint. It
+// adds a destination to the address book or replaces the destination with the
+// same name.
+func (rh *RequestHandler) AdminSetNamedDestination(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.NamedDestinationRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	destination, err := rh.Repository.GetNamedDestination(r.Context(), request.Name)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting NamedDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	now := clock.Now()
+	if destination == nil {
+		destination = &entities.NamedDestination{Name: request.Name, CreatedAt: now}
+	}
+
+	destination.Destination = request.Destination
+	destination.MemoType = request.MemoType
+	destination.Memo = request.Memo
+	destination.AssetCode = request.AssetCode
+	destination.AssetIssuer = request.AssetIssuer
+	destination.UpdatedAt = now
+
+	err = rh.EntityManager.Persist(destination)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting NamedDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"name":        destination.Name,
+		"destination": destination.Destination,
+	}).Info("Named destination updated")
+
+	rh.writeNamedDestination(w, destination)
+}
+
+// AdminRemoveNamedDestination implements DELETE /admin/destinations/{name}
+// endpoint
+func (rh *RequestHandler) AdminRemoveNamedDestination(c web.C, w http.ResponseWriter, r *http.Request) {
+	destination, err := rh.Repository.GetNamedDestination(r.Context(), c.URLParams["name"])
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting NamedDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if destination == nil {
+		server.Write(w, bridge.NamedDestinationNotFound)
+		return
+	}
+
+	err = rh.EntityManager.Delete(destination)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error deleting NamedDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{"name": destination.Name}).Info("Named destination removed")
+	rh.writeNamedDestination(w, destination)
+}
+
+// resolveNamedDestination sets destination of a payment sent to
+// destination_name. Memo and asset of the address book entry are used when
+// they are not sent in the request. Names are resolved before federation, in
+// the address book of the main network.
+func (rh *RequestHandler) resolveNamedDestination(ctx context.Context, request *bridge.PaymentRequest) *protocols.ErrorResponse {
+	if request.Destination != "" || request.ForwardDestination != nil {
+		return protocols.NewInvalidParameterError("destination_name", request.DestinationName, "destination_name cannot be sent with destination or forward_destination.")
+	}
+
+	if rh.Repository == nil {
+		return protocols.NewInvalidParameterError("destination_name", request.DestinationName, "Named destinations require a database.")
+	}
+
+	destination, err := rh.Repository.GetNamedDestination(ctx, request.DestinationName)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting NamedDestination")
+		return protocols.InternalServerError
+	}
+
+	if destination == nil {
+		return protocols.NewInvalidParameterError("destination_name", request.DestinationName, "Destination name not found in the address book.")
+	}
+
+	request.Destination = destination.Destination
+	if request.MemoType == "" && request.Memo == "" {
+		request.MemoType = destination.MemoType
+		request.Memo = destination.Memo
+	}
+	if request.AssetCode == "" && request.AssetIssuer == "" {
+		request.AssetCode = destination.AssetCode
+		request.AssetIssuer = destination.AssetIssuer
+	}
+
+	log.WithFields(log.Fields{
+		"destination_name": destination.Name,
+		"destination":      destination.Destination,
+	}).Info("Resolved named destination")
+	return nil
+}
+
+func (rh *RequestHandler) writeNamedDestination(w http.ResponseWriter, value interface{}) {
+	err := server.WriteJSON(w, value)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding NamedDestination")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+}
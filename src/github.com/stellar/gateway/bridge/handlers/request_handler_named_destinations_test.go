@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestNamedDestinations(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	rh := NewRequestHandler(&config.Config{}, nil, nil, driver, db.NewRepository(driver), db.NewEntityManager(driver), nil, nil, nil, nil)
+
+	set := func(values url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/admin/destinations", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		rh.AdminSetNamedDestination(w, r)
+		return w
+	}
+
+	w := set(url.Values{
+		"name":         {"acme-settlement"},
+		"destination":  {"acme*stellar.org"},
+		"memo_type":    {"id"},
+		"memo":         {"123"},
+		"asset_code":   {"USD"},
+		"asset_issuer": {"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = set(url.Values{"name": {"Acme"}, "destination": {"acme*stellar.org"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Destinations with the same name are replaced
+	w = set(url.Values{"name": {"bob"}, "destination": {"bob*stellar.org"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = set(url.Values{"name": {"bob"}, "destination": {"GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3"}})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	rh.AdminNamedDestinations(w, httptest.NewRequest("GET", "/admin/destinations", nil))
+	var destinations []entities.NamedDestination
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &destinations))
+	require.Len(t, destinations, 2)
+	assert.Equal(t, "acme-settlement", destinations[0].Name)
+	assert.Equal(t, "GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3", destinations[1].Destination)
+
+	ctx := context.Background()
+	request := &bridge.PaymentRequest{DestinationName: "acme-settlement", Amount: "10"}
+	require.Nil(t, rh.resolveNamedDestination(ctx, request))
+	assert.Equal(t, "acme*stellar.org", request.Destination)
+	assert.Equal(t, "id", request.MemoType)
+	assert.Equal(t, "USD", request.AssetCode)
+
+	// Memo and asset of the request are not replaced
+	request = &bridge.PaymentRequest{DestinationName: "acme-settlement", MemoType: "text", Memo: "invoice", AssetCode: "EUR", AssetIssuer: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"}
+	require.Nil(t, rh.resolveNamedDestination(ctx, request))
+	assert.Equal(t, "invoice", request.Memo)
+	assert.Equal(t, "EUR", request.AssetCode)
+
+	errorResponse := rh.resolveNamedDestination(ctx, &bridge.PaymentRequest{DestinationName: "unknown"})
+	require.NotNil(t, errorResponse)
+	assert.Equal(t, "destination_name", errorResponse.Data["name"])
+
+	errorResponse = rh.resolveNamedDestination(ctx, &bridge.PaymentRequest{DestinationName: "bob", Destination: "bob*stellar.org"})
+	require.NotNil(t, errorResponse)
+	assert.Equal(t, "destination_name", errorResponse.Data["name"])
+
+	c := web.C{URLParams: map[string]string{"name": "bob"}}
+	w = httptest.NewRecorder()
+	rh.AdminRemoveNamedDestination(c, w, httptest.NewRequest("DELETE", "/admin/destinations/bob", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	rh.AdminNamedDestination(c, w, httptest.NewRequest("GET", "/admin/destinations/bob", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
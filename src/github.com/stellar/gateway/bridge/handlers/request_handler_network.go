@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go/support/errors"
+)
+
+// Network holds dependencies of an additional network (`networks` config
+// param) selected with `network` param of /payment and /builder
+type Network struct {
+	// Config is the bridge config with Horizon servers, network passphrase
+	// and accounts of the network, see config.Config.ForNetwork
+	Config               *config.Config
+	Horizon              horizon.HorizonInterface
+	Repository           db.RepositoryInterface
+	EntityManager        db.EntityManagerInterface
+	TransactionSubmitter submitter.TransactionSubmitterInterface
+}
+
+// networkHandler returns a copy of rh using dependencies of the named
+// network, rh when name is empty (main network)
+func (rh *RequestHandler) networkHandler(name string) (*RequestHandler, *protocols.ErrorResponse) {
+	if name == "" {
+		return rh, nil
+	}
+
+	network, ok := rh.Networks[name]
+	if !ok {
+		return nil, protocols.NewInvalidParameterError("network", name, "Network is not configured.")
+	}
+
+	handler := *rh
+	handler.Config = network.Config
+	handler.Horizon = network.Horizon
+	handler.Repository = network.Repository
+	handler.EntityManager = network.EntityManager
+	handler.TransactionSubmitter = network.TransactionSubmitter
+	handler.Networks = nil
+	// Risk hooks are applied on the main network only
+	handler.Risk = nil
+	handler.Velocity = nil
+	// Cached destination accounts are loaded from the main network
+	handler.DestinationAccounts = nil
+	// Fees are charged on the main network only
+	handler.Fees = nil
+	// soroban_rpc_url is a server of the main network
+	handler.SorobanRPC = nil
+	return &handler, nil
+}
+
+// SentTransaction returns the transaction sent to the named network (main
+// network when empty) by payment ID or, when paymentID is empty, by
+// transaction hash. Returns nil when it's not found.
+func (rh *RequestHandler) SentTransaction(ctx context.Context, network, paymentID, transactionID string) (*entities.SentTransaction, error) {
+	rh, errorResponse := rh.networkHandler(network)
+	if errorResponse != nil {
+		return nil, errorResponse
+	}
+	if rh.Repository == nil {
+		return nil, errors.New("Bridge server runs without a database")
+	}
+
+	if paymentID != "" {
+		return rh.Repository.GetSentTransactionByPaymentID(ctx, paymentID)
+	}
+	return rh.Repository.GetSentTransactionByTransactionID(ctx, transactionID)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/test"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerNetworks(t *testing.T) {
+	c := &config.Config{NetworkPassphrase: "Public Global Stellar Network ; September 2015"}
+	testnet := config.Network{
+		Name:              "testnet",
+		Horizon:           []string{"https://horizon-testnet.stellar.org"},
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+	}
+
+	mockHorizon := new(mocks.MockHorizon)
+	mockNetworkHorizon := new(mocks.MockHorizon)
+	requestHandler := RequestHandler{
+		Config:               c,
+		Horizon:              mockHorizon,
+		TransactionSubmitter: new(mocks.MockTransactionSubmitter),
+		Networks: map[string]*Network{
+			"testnet": {
+				Config:               c.ForNetwork(testnet),
+				Horizon:              mockNetworkHorizon,
+				TransactionSubmitter: new(mocks.MockTransactionSubmitter),
+			},
+		},
+	}
+
+	builderServer := httptest.NewServer(http.HandlerFunc(requestHandler.Builder))
+	defer builderServer.Close()
+	paymentServer := httptest.NewServer(http.HandlerFunc(requestHandler.Payment))
+	defer paymentServer.Close()
+
+	signer := keypair.MustParse("SABY7FRMMJWPBTKQQ2ZN43AUJQ3Z2ZAK36VYSG2SPE2ABNQXA66H5E5G")
+	builderRequest := func(network string) map[string]interface{} {
+		data := test.StringToJSONMap(`{
+			"source": "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5",
+			"operations": [{
+				"type": "create_account",
+				"body": {
+					"destination": "GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3",
+					"starting_balance": "50"
+				}
+			}],
+			"signers": ["SABY7FRMMJWPBTKQQ2ZN43AUJQ3Z2ZAK36VYSG2SPE2ABNQXA66H5E5G"]
+		}`)
+		data["network"] = network
+		return data
+	}
+
+	t.Run("builder uses Horizon and passphrase of the network", func(t *testing.T) {
+		mockNetworkHorizon.On("LoadAccount", "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5").
+			Return(horizon.AccountResponse{SequenceNumber: "123"}, nil).Once()
+
+		statusCode, response := net.JSONGetResponse(builderServer, builderRequest("testnet"))
+		require.Equal(t, http.StatusOK, statusCode, string(response))
+		mockNetworkHorizon.AssertExpectations(t)
+		mockHorizon.AssertNotCalled(t, "LoadAccount", "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5")
+
+		var builderResponse struct {
+			TransactionEnvelope string `json:"transaction_envelope"`
+		}
+		require.NoError(t, json.Unmarshal(response, &builderResponse))
+		var envelope xdr.TransactionEnvelope
+		require.NoError(t, xdr.SafeUnmarshalBase64(builderResponse.TransactionEnvelope, &envelope))
+
+		hash, err := network.HashTransaction(&envelope.Tx, testnet.NetworkPassphrase)
+		require.NoError(t, err)
+		require.Len(t, envelope.Signatures, 1)
+		assert.NoError(t, signer.Verify(hash[:], envelope.Signatures[0].Signature))
+	})
+
+	t.Run("unknown network", func(t *testing.T) {
+		statusCode, response := net.JSONGetResponse(builderServer, builderRequest("futurenet"))
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, string(response), "Network is not configured.")
+
+		statusCode, response = net.GetResponse(paymentServer, url.Values{
+			"destination": {"GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3"},
+			"amount":      {"20"},
+			"network":     {"futurenet"},
+		})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, string(response), "Network is not configured.")
+	})
+
+	t.Run("compliance is not available on additional networks", func(t *testing.T) {
+		statusCode, response := net.GetResponse(paymentServer, url.Values{
+			"destination":    {"GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3"},
+			"amount":         {"20"},
+			"use_compliance": {"true"},
+			"network":        {"testnet"},
+		})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, string(response), "Compliance is available on the main network only.")
+	})
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/zenazn/goji/web"
+)
+
+// CreateOfflineTransaction implements POST /offline-transactions endpoint. It
+// builds a transaction like /builder and stores the unsigned envelope with
+// its required signers, so it can be signed outside of the bridge server (ex.
+// on an air-gapped machine) and submitted later.
+func (rh *RequestHandler) CreateOfflineTransaction(w http.ResponseWriter, r *http.Request) {
+	var request bridge.OfflineTransactionRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error decoding request")
+		server.Write(w, protocols.NewInvalidParameterError("", "", "Request body is not a valid JSON"))
+		return
+	}
+
+	err = request.Process()
+	if err == nil {
+		err = request.Validate()
+	}
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	for _, source := range request.SourceAccounts() {
+		if !auth.AllowedSource(r.Context(), source) {
+			log.WithFields(log.Fields{"source": source}).Warn("Source account is not allowed for the API key")
+			server.Write(w, protocols.SourceNotAllowedError)
+			return
+		}
+	}
+
+	existing, err := rh.Repository.GetOfflineTransaction(r.Context(), request.ID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting OfflineTransaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if existing != nil {
+		server.Write(w, bridge.OfflineTransactionExists)
+		return
+	}
+
+	networkHandler, errorResponse := rh.networkHandler(request.Network)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	tx, errorResponse := networkHandler.buildTransaction(request.BuilderRequest)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	transactionID, err := tx.HashHex()
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error calculating transaction hash")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	envelopeXdr, err := xdr.MarshalBase64(xdr.TransactionEnvelope{Tx: *tx.TX})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding transaction envelope")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	now := clock.Now()
+	offlineTransaction := &entities.OfflineTransaction{
+		OfflineID:       request.ID,
+		TransactionID:   transactionID,
+		Network:         request.Network,
+		Source:          request.Source,
+		EnvelopeXdr:     envelopeXdr,
+		RequiredSigners: strings.Join(request.ExpectedSigners(), ","),
+		Status:          entities.OfflineTransactionStatusPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	// Offline transactions of all networks are stored in the main database
+	err = rh.EntityManager.Persist(offlineTransaction)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting OfflineTransaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{"id": request.ID, "tx_id": transactionID}).Info("Offline transaction created")
+	rh.writeOfflineTransaction(w, offlineTransaction)
+}
+
+// OfflineTransaction implements GET /offline-transactions/{id} endpoint
+func (rh *RequestHandler) OfflineTransaction(c web.C, w http.ResponseWriter, r *http.Request) {
+	offlineTransaction, errorResponse := rh.loadOfflineTransaction(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	rh.writeOfflineTransaction(w, offlineTransaction)
+}
+
+// SubmitOfflineTransaction implements POST /offline-transactions/{id}/submit
+// endpoint. It accepts the envelope of an offline transaction signed outside
+// of the bridge server, checks that it contains the built transaction and
+// signatures of all required signers and submits it to the network.
+func (rh *RequestHandler) SubmitOfflineTransaction(c web.C, w http.ResponseWriter, r *http.Request) {
+	var request bridge.OfflineTransactionSubmitRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error decoding request")
+		server.Write(w, protocols.NewInvalidParameterError("", "", "Request body is not a valid JSON"))
+		return
+	}
+
+	var envelope xdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(request.EnvelopeXdr, &envelope)
+	if err != nil {
+		server.Write(w, protocols.NewInvalidParameterError("envelope_xdr", request.EnvelopeXdr, "Envelope is not a valid base64 encoded transaction envelope."))
+		return
+	}
+
+	offlineTransaction, errorResponse := rh.loadOfflineTransaction(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if offlineTransaction.Status == entities.OfflineTransactionStatusSubmitted {
+		server.Write(w, bridge.OfflineTransactionSubmitted)
+		return
+	}
+
+	if !auth.AllowedSource(r.Context(), offlineTransaction.Source) {
+		log.WithFields(log.Fields{"source": offlineTransaction.Source}).Warn("Source account is not allowed for the API key")
+		server.Write(w, protocols.SourceNotAllowedError)
+		return
+	}
+
+	networkHandler, errorResponse := rh.networkHandler(offlineTransaction.Network)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	hash, err := submitter.TransactionHash(&envelope.Tx, networkHandler.Config.NetworkPassphrase)
+	if err != nil || hex.EncodeToString(hash[:]) != offlineTransaction.TransactionID {
+		server.Write(w, bridge.OfflineTransactionHashMismatch)
+		return
+	}
+
+	for _, signer := range offlineTransaction.Signers() {
+		if !signedBy(envelope.Signatures, signer, hash[:]) {
+			server.Write(w, bridge.NewOfflineTransactionMissingSignature(signer))
+			return
+		}
+	}
+
+	response, err := networkHandler.Horizon.SubmitTransaction(request.EnvelopeXdr)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error submitting offline transaction")
+		server.Write(w, bridge.ErrorFromHorizonError(err))
+		return
+	}
+
+	now := clock.Now()
+	offlineTransaction.UpdatedAt = now
+	if response.Ledger != nil {
+		offlineTransaction.Status = entities.OfflineTransactionStatusSubmitted
+		offlineTransaction.LastError = ""
+		offlineTransaction.SubmittedAt = &now
+	} else {
+		offlineTransaction.Status = entities.OfflineTransactionStatusFailed
+		offlineTransaction.LastError = response.ErrorCode()
+	}
+
+	err = rh.EntityManager.Persist(offlineTransaction)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "status": offlineTransaction.Status}).Error("Error persisting OfflineTransaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"id":     offlineTransaction.OfflineID,
+		"tx_id":  offlineTransaction.TransactionID,
+		"status": offlineTransaction.Status,
+	}).Info("Offline transaction submitted")
+	rh.handleSubmitterResponse(w, response)
+}
+
+func (rh *RequestHandler) loadOfflineTransaction(r *http.Request, id string) (*entities.OfflineTransaction, *protocols.ErrorResponse) {
+	offlineTransaction, err := rh.Repository.GetOfflineTransaction(r.Context(), id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting OfflineTransaction")
+		return nil, protocols.InternalServerError
+	}
+
+	if offlineTransaction == nil {
+		return nil, bridge.OfflineTransactionNotFound
+	}
+
+	return offlineTransaction, nil
+}
+
+// OfflineTransactionResponse is an offline transaction with its required
+// signers
+type OfflineTransactionResponse struct {
+	*entities.OfflineTransaction
+	RequiredSigners []string `json:"required_signers"`
+}
+
+func (rh *RequestHandler) writeOfflineTransaction(w http.ResponseWriter, offlineTransaction *entities.OfflineTransaction) {
+	err := server.WriteJSON(w, OfflineTransactionResponse{offlineTransaction, offlineTransaction.Signers()})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding OfflineTransaction")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+// signedBy returns true when signatures contain a valid signature of hash by
+// the account
+func signedBy(signatures []xdr.DecoratedSignature, account string, hash []byte) bool {
+	kp, err := keypair.Parse(account)
+	if err != nil {
+		return false
+	}
+
+	hint := kp.Hint()
+	for _, signature := range signatures {
+		if !bytes.Equal(signature.Hint[:], hint[:]) {
+			continue
+		}
+		if kp.Verify(hash, signature.Signature) == nil {
+			return true
+		}
+	}
+	return false
+}
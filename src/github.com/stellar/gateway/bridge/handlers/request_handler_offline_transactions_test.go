@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestOfflineTransactions(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	passphrase := "Test SDF Network ; September 2015"
+	c := &config.Config{NetworkPassphrase: passphrase}
+	mockHorizon := new(mocks.MockHorizon)
+	rh := NewRequestHandler(c, nil, mockHorizon, driver, db.NewRepository(driver), db.NewEntityManager(driver), nil, nil, nil, nil)
+
+	source, err := keypair.Random()
+	require.NoError(t, err)
+	mockHorizon.On("LoadAccount", source.Address()).Return(horizon.AccountResponse{SequenceNumber: "100"}, nil)
+
+	body := `{
+		"id": "cold-1",
+		"source": "` + source.Address() + `",
+		"operations": [{"type": "payment", "body": {"destination": "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632", "amount": "10", "asset": {}}}]
+	}`
+	create := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		rh.CreateOfflineTransaction(w, httptest.NewRequest("POST", "/offline-transactions", strings.NewReader(body)))
+		return w
+	}
+
+	w := create(body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var created OfflineTransactionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "cold-1", created.OfflineID)
+	assert.Equal(t, entities.OfflineTransactionStatusPending, created.Status)
+	assert.Equal(t, []string{source.Address()}, created.RequiredSigners)
+
+	var envelope xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(created.EnvelopeXdr, &envelope))
+	assert.Empty(t, envelope.Signatures)
+	assert.Equal(t, xdr.SequenceNumber(101), envelope.Tx.SeqNum)
+
+	w = create(body)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = create(`{"id": "cold-2", "source": "` + source.Address() + `", "signers": ["` + source.Seed() + `"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	rh.OfflineTransaction(web.C{URLParams: map[string]string{"id": "cold-1"}}, w, httptest.NewRequest("GET", "/offline-transactions/cold-1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), created.TransactionID)
+
+	submit := func(envelope xdr.TransactionEnvelope) *httptest.ResponseRecorder {
+		envelopeXdr, err := xdr.MarshalBase64(envelope)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		rh.SubmitOfflineTransaction(web.C{URLParams: map[string]string{"id": "cold-1"}}, w, httptest.NewRequest("POST", "/offline-transactions/cold-1/submit", strings.NewReader(`{"envelope_xdr": "`+envelopeXdr+`"}`)))
+		return w
+	}
+	sign := func(envelope xdr.TransactionEnvelope, kp *keypair.Full) xdr.TransactionEnvelope {
+		hash, err := submitter.TransactionHash(&envelope.Tx, passphrase)
+		require.NoError(t, err)
+		signature, err := kp.SignDecorated(hash[:])
+		require.NoError(t, err)
+		envelope.Signatures = append(envelope.Signatures, signature)
+		return envelope
+	}
+
+	// Not signed by the source account
+	other, err := keypair.Random()
+	require.NoError(t, err)
+	w = submit(sign(envelope, other))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "offline_transaction_missing_signature")
+
+	// Transaction changed before it was signed
+	changed := envelope
+	changed.Tx.SeqNum++
+	w = submit(sign(changed, source))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "offline_transaction_hash_mismatch")
+
+	ledger := uint64(1000)
+	mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Return(horizon.SubmitTransactionResponse{Hash: created.TransactionID, Ledger: &ledger}, nil).Once()
+	w = submit(sign(envelope, source))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), created.TransactionID)
+
+	offlineTransaction, err := rh.Repository.GetOfflineTransaction(context.Background(), "cold-1")
+	require.NoError(t, err)
+	assert.Equal(t, entities.OfflineTransactionStatusSubmitted, offlineTransaction.Status)
+	assert.NotNil(t, offlineTransaction.SubmittedAt)
+
+	w = submit(sign(envelope, source))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockHorizon.AssertExpectations(t)
+}
@@ -1,25 +1,38 @@
 package handlers
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	log "github.com/sirupsen/logrus"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/features"
 	"github.com/stellar/gateway/horizon"
 	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
 	"github.com/stellar/gateway/protocols/bridge"
 	callback "github.com/stellar/gateway/protocols/compliance"
+	"github.com/stellar/gateway/redis"
 	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/snapshots"
+	"github.com/stellar/gateway/stage"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/gateway/tracing"
+	"github.com/stellar/gateway/webhooks"
 	"github.com/stellar/go/address"
-	"github.com/stellar/go/amount"
 	b "github.com/stellar/go/build"
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/protocols/compliance"
 	"github.com/stellar/go/protocols/federation"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
 
@@ -33,6 +46,48 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if request.DestinationName != "" {
+		errorResponse := rh.resolveNamedDestination(r.Context(), request)
+		if errorResponse != nil {
+			log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+			server.Write(w, errorResponse)
+			return
+		}
+	}
+
+	if request.QuoteID != "" {
+		errorResponse := rh.applyQuote(r.Context(), request)
+		if errorResponse != nil {
+			log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+			server.Write(w, errorResponse)
+			return
+		}
+	}
+
+	if request.MemoType == "" && request.Memo != "" && rh.Features.EnabledForRequest(features.InferMemoType, r) {
+		memoType, ok := bridge.InferMemoType(request.Memo)
+		if !ok {
+			server.Write(w, protocols.NewInvalidParameterError("memo", request.Memo, "Memo is too long to be sent as text."))
+			return
+		}
+
+		log.WithFields(log.Fields{"memo": request.Memo, "memo_type": memoType}).Info("Inferred memo type")
+		request.MemoType = memoType
+		request.InferredMemoType = memoType
+	}
+
+	if request.Reference != "" {
+		errorResponse := rh.applyReference(r.Context(), request)
+		if errorResponse != nil {
+			log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+			server.Write(w, errorResponse)
+			return
+		}
+	} else if (request.MemoType == "text" || request.MemoType == "id") && !rh.References.AllowsPlaintext() {
+		server.Write(w, protocols.NewInvalidParameterError("memo", request.Memo, "Text and ID memos are not allowed, send reference instead."))
+		return
+	}
+
 	err = request.Validate()
 	if err != nil {
 		errorResponse := err.(*protocols.ErrorResponse)
@@ -41,10 +96,108 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if request.Network != "" && rh.Config.Approval.Enabled && rh.requiresApproval(request) {
+		errorResponse := protocols.NewInvalidParameterError("network", request.Network, "Payments requiring approval can be sent on the main network only.")
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	rh, errorResponse := rh.networkHandler(request.Network)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if request.Network != "" && (request.ExtraMemo != "" || request.PrivateNote != "" || request.UseCompliance) {
+		errorResponse := protocols.NewInvalidParameterError("network", request.Network, "Compliance is available on the main network only.")
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	if rh.Config.RestrictAssets {
+		err = request.ValidateAsset(rh.reloadable().Assets)
+		if err != nil {
+			errorResponse := err.(*protocols.ErrorResponse)
+			log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+			rh.auditLimitViolation(r, request, errorResponse)
+			server.Write(w, errorResponse)
+			return
+		}
+	}
+
+	rh.payment(w, request, true)
+}
+
+// payment sends a payment. When hold is true and settlement delay is set for the
+// source account, the payment requires approval or a risk hook holds it, the
+// payment is held instead. Amount limits of the asset are checked when hold is
+// true, held payments have been checked before they were held.
+func (rh *RequestHandler) payment(w http.ResponseWriter, request *bridge.PaymentRequest, hold bool) {
 	var paymentID *string
 
-	if request.ID != "" {
-		sentTransaction, err := rh.Repository.GetSentTransactionByPaymentID(request.ID)
+	if hold {
+		errorResponse := rh.checkAmountLimits(request)
+		if errorResponse != nil {
+			server.Write(w, errorResponse)
+			return
+		}
+	}
+
+	if request.OrderingKey != "" {
+		ctx := submitter.WithOrderingKey(request.HTTPRequest.Context(), request.OrderingKey)
+		request.HTTPRequest = request.HTTPRequest.WithContext(ctx)
+	}
+
+	if len(request.Metadata) > 0 {
+		ctx := submitter.WithMetadata(request.HTTPRequest.Context(), request.Metadata)
+		request.HTTPRequest = request.HTTPRequest.WithContext(ctx)
+	}
+
+	if hold && !request.DryRun && request.ID != "" && rh.Config.HoldsPayments() {
+		heldPayment, err := rh.Repository.GetHeldPaymentByPaymentID(request.HTTPRequest.Context(), request.ID)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error getting held payment")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+
+		if heldPayment != nil {
+			switch heldPayment.Status {
+			case entities.HeldPaymentStatusHeld, entities.HeldPaymentStatusPending:
+				server.Write(w, newHeldPaymentResponse(heldPayment))
+				return
+			case entities.HeldPaymentStatusCancelled:
+				server.Write(w, bridge.PaymentCancelled)
+				return
+			}
+		}
+	}
+
+	if request.ID != "" && !request.DryRun {
+		claim, err := rh.Idempotency.Claim(request.HTTPRequest.Context(), request.ID)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Warn("Error claiming payment ID in Redis, checking database")
+			claim = redis.ClaimSeen
+		}
+
+		if claim == redis.ClaimInProgress {
+			server.Write(w, bridge.PaymentInProgress)
+			return
+		}
+		if err == nil {
+			defer rh.finishPaymentID(request.ID)
+		}
+		if claim == redis.ClaimNew {
+			// Not used within redis.idempotency_ttl, no need to check the database
+			paymentID = &request.ID
+		}
+	}
+
+	if request.ID != "" && !request.DryRun && paymentID == nil {
+		sentTransaction, err := rh.Repository.GetSentTransactionByPaymentID(request.HTTPRequest.Context(), request.ID)
 		if err != nil {
 			log.WithFields(log.Fields{"err": err}).Error("Error getting sent transaction")
 			server.Write(w, protocols.InternalServerError)
@@ -58,7 +211,7 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 			submitResponse, err := rh.Horizon.SubmitTransaction(sentTransaction.EnvelopeXdr)
 			if err != nil {
 				log.WithFields(log.Fields{"error": err}).Error("Error submitting transaction")
-				server.Write(w, protocols.InternalServerError)
+				server.Write(w, bridge.ErrorFromHorizonError(err))
 				return
 			}
 
@@ -71,42 +224,146 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		request.Source = rh.Config.Accounts.BaseSeed
 	}
 
+	if request.Source != "" {
+		sourceKeypair, err := keypair.Parse(request.Source)
+		if err == nil && !auth.AllowedSource(request.HTTPRequest.Context(), sourceKeypair.Address()) {
+			log.WithFields(log.Fields{"source": sourceKeypair.Address()}).Warn("Source account is not allowed for the API key")
+			server.Write(w, protocols.SourceNotAllowedError)
+			return
+		}
+	}
+
+	if request.Source != "" && rh.Config.Region.Enabled() {
+		sourceKeypair, err := keypair.Parse(request.Source)
+		if err == nil && !rh.Config.Region.Owns(sourceKeypair.Address()) {
+			log.WithFields(log.Fields{
+				"source": sourceKeypair.Address(),
+				"region": rh.Config.Region.Name,
+			}).Warn("Source account is not owned by this region")
+			server.Write(w, bridge.PaymentWrongRegion)
+			return
+		}
+	}
+
+	if hold && !request.DryRun && request.Source != "" && rh.Risk.Len() > 0 {
+		sourceKeypair, _ := keypair.Parse(request.Source)
+		if rh.checkRisk(w, request, sourceKeypair.Address()) {
+			return
+		}
+	}
+
+	if hold && !request.DryRun && request.Source != "" && rh.Config.Approval.Enabled && rh.requiresApproval(request) {
+		sourceKeypair, _ := keypair.Parse(request.Source)
+		rh.holdPayment(w, request, sourceKeypair.Address(), entities.HeldPaymentStatusPending, 0)
+		return
+	}
+
+	if hold && !request.DryRun && request.Source != "" && rh.Config.Settlement.Enabled() {
+		sourceKeypair, _ := keypair.Parse(request.Source)
+		delay := rh.Config.Settlement.DelayFor(features.Tenant(request.HTTPRequest))
+		if delay > 0 {
+			rh.holdPayment(w, request, sourceKeypair.Address(), entities.HeldPaymentStatusHeld, delay)
+			return
+		}
+	}
+
 	// Will use compliance if compliance server is connected and:
 	// * User passed extra memo OR
-	// * User explicitly wants to use compliance protocol
-	if rh.Config.Compliance != "" &&
-		(request.ExtraMemo != "" || (request.ExtraMemo == "" && request.UseCompliance)) {
+	// * User passed private note OR
+	// * User explicitly wants to use compliance protocol OR
+	// * strict_compliance feature is enabled and destination is a federation address
+	useCompliance := rh.Config.Compliance != "" &&
+		(request.ExtraMemo != "" || request.PrivateNote != "" || request.UseCompliance)
+	if !useCompliance && rh.requiresCompliance(request) {
+		if rh.Config.Compliance == "" || request.Sender == "" {
+			log.WithFields(log.Fields{"destination": request.Destination}).Warn("Rejecting payment to federation address without compliance")
+			server.Write(w, bridge.PaymentComplianceRequired)
+			return
+		}
+		useCompliance = true
+	}
+
+	if useCompliance {
+		if request.DryRun {
+			server.Write(w, protocols.NewInvalidParameterError("dry_run", "true", "Dry run is not supported for payments sent using compliance protocol."))
+			return
+		}
+		if request.MetadataEncoding != "" {
+			server.Write(w, protocols.NewInvalidParameterError("metadata_encoding", request.MetadataEncoding, "Metadata encoding is not supported for payments sent using compliance protocol."))
+			return
+		}
+		// The transaction is built by the compliance server and authorized
+		// by the receiver, a fee operation can't be added to it
+		if fee, errorResponse := rh.paymentFee(request); fee != nil || errorResponse != nil {
+			server.Write(w, bridge.PaymentFeeNotSupported)
+			return
+		}
 		rh.complianceProtocolPayment(w, request, paymentID)
 	} else {
 		rh.standardPayment(w, request, paymentID)
 	}
 }
 
+// requiresCompliance returns true when strict_compliance feature is enabled
+// for the tenant and the payment is sent to a federation address
+func (rh *RequestHandler) requiresCompliance(request *bridge.PaymentRequest) bool {
+	if request.ForwardDestination != nil {
+		return false
+	}
+	if _, _, err := address.Split(request.Destination); err != nil {
+		return false
+	}
+	return rh.Features.EnabledForRequest(features.StrictCompliance, request.HTTPRequest)
+}
+
+// finishPaymentID finishes a request with payment id claimed in payment
+func (rh *RequestHandler) finishPaymentID(id string) {
+	if rh.Idempotency == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rh.Config.Redis.TimeoutDuration())
+	defer cancel()
+	err := rh.Idempotency.Finish(ctx, id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "id": id}).Warn("Error finishing payment ID in Redis")
+	}
+}
+
 func (rh *RequestHandler) complianceProtocolPayment(w http.ResponseWriter, request *bridge.PaymentRequest, paymentID *string) {
 	// Compliance server part
 	sendRequest := request.ToComplianceSendRequest()
 
-	resp, err := rh.Client.PostForm(
-		rh.Config.Compliance+"/send",
-		sendRequest.ToValues(),
-	)
-	if err != nil {
-		log.WithFields(log.Fields{"err": err}).Error("Error sending request to compliance server")
-		server.Write(w, protocols.InternalServerError)
-		return
-	}
+	var statusCode int
+	var body []byte
+	err := rh.runStage(request, stage.Compliance, func(ctx context.Context) error {
+		_, span := tracing.StartKind(ctx, "compliance.send", tracing.SpanKindClient)
+		resp, err := rh.Client.PostForm(
+			rh.Config.Compliance+"/send",
+			sendRequest.ToValues(),
+		)
+		span.SetError(err)
+		span.Finish()
+		if err != nil {
+			return err
+		}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Error("Error reading compliance server response")
+		defer resp.Body.Close()
+		statusCode = resp.StatusCode
+		body, err = ioutil.ReadAll(resp.Body)
+		return err
+	})
+	if stage.Interrupted(err) {
+		server.Write(w, stageErrorResponse(stage.Compliance, err))
+		return
+	} else if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error sending request to compliance server")
 		server.Write(w, protocols.InternalServerError)
 		return
 	}
 
-	if resp.StatusCode != 200 {
+	if statusCode != 200 {
 		log.WithFields(log.Fields{
-			"status": resp.StatusCode,
+			"status": statusCode,
 			"body":   string(body),
 		}).Error("Error response from compliance server")
 		server.Write(w, protocols.InternalServerError)
@@ -124,6 +381,16 @@ func (rh *RequestHandler) complianceProtocolPayment(w http.ResponseWriter, reque
 	if callbackSendResponse.AuthResponse.InfoStatus == compliance.AuthStatusPending ||
 		callbackSendResponse.AuthResponse.TxStatus == compliance.AuthStatusPending {
 		log.WithFields(log.Fields{"response": callbackSendResponse}).Info("Compliance response pending")
+		rh.Webhooks.Publish(request.HTTPRequest.Context(), webhooks.EventCompliancePending, map[string]interface{}{
+			"id":           paymentID,
+			"source":       sendRequest.Source,
+			"sender":       request.Sender,
+			"destination":  request.Destination,
+			"amount":       request.Amount,
+			"asset_code":   request.AssetCode,
+			"asset_issuer": request.AssetIssuer,
+			"pending":      callbackSendResponse.AuthResponse.Pending,
+		})
 		server.Write(w, bridge.NewPaymentPendingError(callbackSendResponse.AuthResponse.Pending))
 		return
 	}
@@ -143,14 +410,59 @@ func (rh *RequestHandler) complianceProtocolPayment(w http.ResponseWriter, reque
 		return
 	}
 
-	submitResponse, err := rh.TransactionSubmitter.SignAndSubmitRawTransaction(paymentID, request.Source, &tx)
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("Error submitting transaction")
-		server.Write(w, protocols.InternalServerError)
+	if recheck, ok := newAccountsRecheck(request.Source, &tx); ok {
+		errorResponse := rh.checkSelfPayment(request.Source, recheck.destination)
+		if errorResponse != nil {
+			server.Write(w, errorResponse)
+			return
+		}
+	}
+
+	if request.RequiresRecheck(rh.reloadable().Assets) {
+		if recheck, ok := newAccountsRecheck(request.Source, &tx); ok {
+			errorResponse := rh.checkStage(request, stage.Horizon, func() *protocols.ErrorResponse {
+				return rh.recheckAccounts(recheck)
+			})
+			if errorResponse != nil {
+				server.Write(w, errorResponse)
+				return
+			}
+		}
+	}
+
+	if rh.requiresPreflight(request) {
+		errorResponse := rh.checkStage(request, stage.Horizon, func() *protocols.ErrorResponse {
+			return rh.preflightPayment(&tx)
+		})
+		if errorResponse != nil {
+			server.Write(w, errorResponse)
+			return
+		}
+	}
+
+	ctx, errorResponse := submissionContext(request)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
 		return
 	}
 
-	rh.handleSubmitterResponse(w, submitResponse)
+	submit := func(ctx context.Context) (horizon.SubmitTransactionResponse, error) {
+		return rh.TransactionSubmitter.SignAndSubmitRawTransaction(ctx, paymentID, request.Source, &tx)
+	}
+	rh.submitPayment(ctx, w, request, paymentID, submit, func(submitResponse *horizon.SubmitTransactionResponse, err error) {
+		if err != nil {
+			return
+		}
+
+		// Memo of the transaction is the hash of the attachment, the inferred
+		// type is returned like for payments sent without compliance
+		submitResponse.InferredMemoType = request.InferredMemoType
+
+		if bridge.ErrorFromHorizonResponse(*submitResponse) == nil {
+			rh.recordPaymentLimits(ctx, request)
+			rh.recordPaymentVelocity(request)
+		}
+	})
 }
 
 func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge.PaymentRequest, paymentID *string) {
@@ -162,54 +474,94 @@ func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge
 		if err != nil {
 			destinationObject.AccountID = request.Destination
 		} else {
-			destinationObject, err = rh.FederationResolver.LookupByAddress(request.Destination)
-			if err != nil {
+			// Resolution loads stellar.toml of the destination domain and
+			// queries its federation server
+			err = rh.runStage(request, stage.Federation, func(ctx context.Context) error {
+				_, span := tracing.StartKind(ctx, "federation.resolve", tracing.SpanKindClient)
+				span.SetAttribute("destination", request.Destination)
+				response, err := rh.FederationResolver.LookupByAddress(request.Destination)
+				span.SetError(err)
+				span.Finish()
+				destinationObject = response
+				return err
+			})
+			if stage.Interrupted(err) {
+				server.Write(w, stageErrorResponse(stage.Federation, err))
+				return
+			} else if err != nil {
 				log.WithFields(log.Fields{"destination": request.Destination, "err": err}).Print("Cannot resolve address")
-				server.Write(w, bridge.PaymentCannotResolveDestination)
+				server.Write(w, bridge.ErrorFromResolveError(err))
 				return
 			}
 		}
 	} else {
-		destinationObject, err = rh.FederationResolver.ForwardRequest(request.ForwardDestination.Domain, request.ForwardDestination.Fields)
-		if err != nil {
+		err = rh.runStage(request, stage.Federation, func(ctx context.Context) error {
+			_, span := tracing.StartKind(ctx, "federation.forward", tracing.SpanKindClient)
+			span.SetAttribute("domain", request.ForwardDestination.Domain)
+			response, err := rh.FederationResolver.ForwardRequest(request.ForwardDestination.Domain, request.ForwardDestination.Query())
+			span.SetError(err)
+			span.Finish()
+			destinationObject = response
+			return err
+		})
+		if stage.Interrupted(err) {
+			server.Write(w, stageErrorResponse(stage.Federation, err))
+			return
+		} else if err != nil {
 			log.WithFields(log.Fields{"destination": request.Destination, "err": err}).Print("Cannot resolve address")
-			server.Write(w, bridge.PaymentCannotResolveDestination)
+			server.Write(w, bridge.ErrorFromResolveError(err))
 			return
 		}
 	}
 
+	var usedSnapshots []*snapshots.Snapshot
+	if rh.Snapshots != nil {
+		usedSnapshots = rh.Snapshots.Destination(request.Destination, request.ForwardDestination)
+	}
+
 	if !protocols.IsValidAccountID(destinationObject.AccountID) {
 		log.WithFields(log.Fields{"AccountId": destinationObject.AccountID}).Print("Invalid AccountId in destination")
 		server.Write(w, protocols.NewInvalidParameterError("destination", request.Destination, "Destination public key must start with `G`."))
 		return
 	}
 
-	var payWithMutator *b.PayWithPath
+	errorResponse := rh.checkSelfPayment(request.Source, destinationObject.AccountID)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
 
-	if request.SendMax != "" {
-		// Path payment
-		var sendAsset b.Asset
-		if request.SendAssetCode == "" && request.SendAssetIssuer == "" {
-			sendAsset = b.NativeAsset()
-		} else {
-			sendAsset = b.CreditAsset(request.SendAssetCode, request.SendAssetIssuer)
-		}
+	fee, errorResponse := rh.paymentFee(request)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
 
-		payWith := b.PayWith(sendAsset, request.SendMax)
+	// Destination receives the net amount when the fee is deducted
+	amount := request.Amount
+	if fee != nil {
+		log.WithFields(log.Fields{"fee": fee.Fee, "mode": fee.Mode, "asset_code": fee.AssetCode}).Info("Charging payment fee")
+		amount = fee.NetAmount
+		ctx := submitter.WithPaymentFee(request.HTTPRequest.Context(), fee)
+		request.HTTPRequest = request.HTTPRequest.WithContext(ctx)
+	}
 
-		for _, asset := range request.Path {
-			payWith = payWith.Through(asset.ToBaseAsset())
-		}
+	var payWithMutator *b.PayWithPath
 
+	if request.SendMax != "" {
+		// Path payment, send_max of the net amount when the fee is deducted
+		payWith := pathPayWith(request, sendMaxShare(request, amount, false))
 		payWithMutator = &payWith
 	}
 
 	var operationBuilder interface{}
+	createAccount := false
 
 	if request.AssetCode != "" && request.AssetIssuer != "" {
 		mutators := []interface{}{
 			b.Destination{destinationObject.AccountID},
-			b.CreditAmount{request.AssetCode, request.AssetIssuer, request.Amount},
+			b.CreditAmount{request.AssetCode, request.AssetIssuer, amount},
 		}
 
 		if payWithMutator != nil {
@@ -220,7 +572,7 @@ func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge
 	} else {
 		mutators := []interface{}{
 			b.Destination{destinationObject.AccountID},
-			b.NativeAmount{request.Amount},
+			b.NativeAmount{amount},
 		}
 
 		if payWithMutator != nil {
@@ -228,15 +580,38 @@ func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge
 		}
 
 		// Check if destination account exist
-		_, err = rh.Horizon.LoadAccount(destinationObject.AccountID)
-		if err != nil {
+		err = rh.runStage(request, stage.Horizon, func(context.Context) error {
+			_, err := rh.loadDestination(destinationObject.AccountID)
+			return err
+		})
+		if stage.Interrupted(err) {
+			server.Write(w, stageErrorResponse(stage.Horizon, err))
+			return
+		} else if errors.Cause(err) == horizon.ErrUnavailable {
+			log.WithFields(log.Fields{"error": err}).Error("Error loading account")
+			server.Write(w, bridge.HorizonUnavailable)
+			return
+		} else if err != nil && !rh.Features.EnabledForRequest(features.AutoCreateAccount, request.HTTPRequest) {
+			log.WithFields(log.Fields{"destination": destinationObject.AccountID}).Warn("Destination does not exist and auto_create_account is disabled")
+			server.Write(w, bridge.PaymentNoDestination)
+			return
+		} else if err != nil {
 			log.WithFields(log.Fields{"error": err}).Error("Error loading account")
 			operationBuilder = b.CreateAccount(mutators...)
+			createAccount = true
 		} else {
 			operationBuilder = b.Payment(mutators...)
 		}
 	}
 
+	if fee != nil && fee.Account != "" {
+		operationBuilder = withFee(operationBuilder.(b.TransactionMutator), request, fee)
+	}
+
+	if request.MetadataEncoding == bridge.MetadataEncodingManageData {
+		operationBuilder = withMetadata(operationBuilder.(b.TransactionMutator), request.Metadata)
+	}
+
 	memoType := request.MemoType
 	memo := request.Memo
 
@@ -251,6 +626,57 @@ func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge
 		memo = destinationObject.Memo.Value
 	}
 
+	if request.MetadataEncoding == bridge.MetadataEncodingMemo {
+		if memoType != "" {
+			log.Print("Metadata cannot be encoded in memo because federation returned memo fields.")
+			server.Write(w, bridge.PaymentCannotUseMemo)
+			return
+		}
+
+		// Size is checked in Validate
+		memoType = "text"
+		memo = bridge.MetadataMemo(request.Metadata)
+	}
+
+	if rh.reloadable().ProfilesCheck {
+		profile, err := rh.destinationProfile(request.HTTPRequest.Context(), destinationObject.AccountID, request.Destination)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error getting destination profile")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+
+		if profile != nil {
+			err = profile.Check(memoType, request.AssetCode, request.AssetIssuer, request.Amount)
+			if err != nil {
+				errorResponse := bridge.NewPaymentProfileViolationError(profile.Name, err)
+				log.WithFields(errorResponse.LogData).Warn("Payment does not meet requirements of destination profile")
+				server.Write(w, errorResponse)
+				return
+			}
+		}
+	}
+
+	memoRequirement := rh.reloadable().MemoRequirement
+	if memoType == "" && memoRequirement != "" {
+		destination, err := rh.Repository.GetMemoRequiredDestination(request.HTTPRequest.Context(), destinationObject.AccountID)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error getting MemoRequiredDestination")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+
+		if destination != nil {
+			fields := log.Fields{"destination": destinationObject.AccountID, "source": destination.Source}
+			if memoRequirement == "require" {
+				log.WithFields(fields).Warn("Rejecting payment without memo to destination requiring memo")
+				server.Write(w, bridge.PaymentMemoRequired)
+				return
+			}
+			log.WithFields(fields).Warn("Sending payment without memo to destination requiring memo")
+		}
+	}
+
 	var memoMutator interface{}
 	switch {
 	case memoType == "":
@@ -264,6 +690,12 @@ func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge
 		}
 		memoMutator = b.MemoID{id}
 	case memoType == "text":
+		// Memo returned by federation server is not checked in Validate
+		if len(memo) > bridge.MaxTextMemoLength {
+			log.WithFields(log.Fields{"memo": memo}).Print("Text memo too long")
+			server.Write(w, protocols.NewInvalidParameterError("memo", memo, "Memo.text can be at most "+strconv.Itoa(bridge.MaxTextMemoLength)+" bytes long."))
+			return
+		}
 		memoMutator = b.MemoText{memo}
 	case memoType == "hash":
 		memoBytes, err := hex.DecodeString(memo)
@@ -276,20 +708,103 @@ func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge
 		copy(b32[:], memoBytes[0:32])
 		hash := xdr.Hash(b32)
 		memoMutator = b.MemoHash{hash}
+	case memoType == "return":
+		// Hash of the transaction refunded by the payment
+		memoBytes, err := hex.DecodeString(memo)
+		if err != nil || len(memoBytes) != 32 {
+			log.WithFields(log.Fields{"memo": memo}).Print("Cannot decode return memo value")
+			server.Write(w, protocols.NewInvalidParameterError("memo", request.Memo, "Memo.return must be 32 bytes and hex encoded."))
+			return
+		}
+		var b32 [32]byte
+		copy(b32[:], memoBytes[0:32])
+		memoMutator = b.MemoReturn{xdr.Hash(b32)}
 	default:
 		log.Print("Not supported memo type: ", memoType)
 		server.Write(w, protocols.NewInvalidParameterError("memo", request.Memo, "Memo type not supported"))
 		return
 	}
 
-	submitResponse, err := rh.TransactionSubmitter.SubmitTransaction(paymentID, request.Source, operationBuilder, memoMutator)
-	if err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("Error submitting transaction")
-		server.Write(w, protocols.InternalServerError)
+	if request.RequiresRecheck(rh.reloadable().Assets) {
+		recheck := accountsRecheck{
+			source:          request.Source,
+			destination:     destinationObject.AccountID,
+			sendAssetCode:   request.AssetCode,
+			sendAssetIssuer: request.AssetIssuer,
+			assetCode:       request.AssetCode,
+			assetIssuer:     request.AssetIssuer,
+			createAccount:   createAccount,
+		}
+		if request.SendMax != "" {
+			recheck.sendAssetCode, recheck.sendAssetIssuer = request.SendAssetCode, request.SendAssetIssuer
+		}
+
+		errorResponse := rh.checkStage(request, stage.Horizon, func() *protocols.ErrorResponse {
+			return rh.recheckAccounts(recheck)
+		})
+		if errorResponse != nil {
+			server.Write(w, errorResponse)
+			return
+		}
+	}
+
+	var submit func(ctx context.Context) (horizon.SubmitTransactionResponse, error)
+	if request.DryRun || rh.requiresPreflight(request) {
+		// Transaction is built first, so it can be simulated before it is
+		// signed
+		var tx *xdr.Transaction
+		tx, err = rh.TransactionSubmitter.BuildTransaction(request.HTTPRequest.Context(), request.Source, operationBuilder, memoMutator)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err}).Error("Error building transaction")
+			server.Write(w, bridge.ErrorFromHorizonError(err))
+			return
+		}
+
+		if request.DryRun {
+			rh.writeDryRun(w, tx)
+			return
+		}
+
+		errorResponse := rh.checkStage(request, stage.Horizon, func() *protocols.ErrorResponse {
+			return rh.preflightPayment(tx)
+		})
+		if errorResponse != nil {
+			server.Write(w, errorResponse)
+			return
+		}
+
+		submit = func(ctx context.Context) (horizon.SubmitTransactionResponse, error) {
+			return rh.TransactionSubmitter.SignAndSubmitRawTransaction(ctx, paymentID, request.Source, tx)
+		}
+	} else {
+		submit = func(ctx context.Context) (horizon.SubmitTransactionResponse, error) {
+			return rh.TransactionSubmitter.SubmitTransaction(ctx, paymentID, request.Source, operationBuilder, memoMutator)
+		}
+	}
+
+	ctx, errorResponse := submissionContext(request)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
 		return
 	}
 
-	rh.handleSubmitterResponse(w, submitResponse)
+	rh.submitPayment(ctx, w, request, paymentID, submit, func(submitResponse *horizon.SubmitTransactionResponse, err error) {
+		if err != nil {
+			rh.invalidateDestination(destinationObject.AccountID)
+			return
+		}
+
+		submitResponse.InferredMemoType = request.InferredMemoType
+		submitResponse.Fee = fee
+		rh.persistSnapshots(usedSnapshots, submitResponse.Hash, paymentID)
+
+		if bridge.ErrorFromHorizonResponse(*submitResponse) == nil {
+			rh.recordPaymentLimits(ctx, request)
+			rh.recordPaymentVelocity(request)
+		} else {
+			rh.invalidateDestination(destinationObject.AccountID)
+		}
+	})
 }
 
 func (rh *RequestHandler) handleSubmitterResponse(w http.ResponseWriter, response horizon.SubmitTransactionResponse) {
@@ -311,10 +826,60 @@ func (rh *RequestHandler) handleSubmitterResponse(w http.ResponseWriter, respons
 			operationResult := (*transactionResult.Result.Results)[0]
 			if operationResult.Tr.PathPaymentResult != nil {
 				sendAmount := operationResult.Tr.PathPaymentResult.SendAmount()
-				response.SendAmount = amount.String(sendAmount)
+				response.SendAmount = amounts.String(int64(sendAmount))
 			}
 		}
 	}
 
 	server.Write(w, &response)
 }
+
+// loadDestination loads destination account using DestinationAccounts cache
+// when it's enabled
+func (rh *RequestHandler) loadDestination(accountID string) (horizon.AccountResponse, error) {
+	if rh.DestinationAccounts == nil {
+		return rh.Horizon.LoadAccount(accountID)
+	}
+	return rh.DestinationAccounts.LoadAccount(accountID)
+}
+
+// invalidateDestination drops a cached destination account after a payment to
+// it failed, the account could be merged or its trustline removed
+func (rh *RequestHandler) invalidateDestination(accountID string) {
+	if rh.DestinationAccounts != nil {
+		rh.DestinationAccounts.Invalidate(accountID)
+	}
+}
+
+// checkSelfPayment returns PaymentSelfPayment error when destination is the
+// source account and allow_self_payments is not set
+func (rh *RequestHandler) checkSelfPayment(source, destination string) *protocols.ErrorResponse {
+	if rh.Config.AllowSelfPayments {
+		return nil
+	}
+
+	sourceKeypair, err := keypair.Parse(source)
+	if err != nil || sourceKeypair.Address() != destination {
+		return nil
+	}
+
+	log.WithFields(log.Fields{"source": destination}).Warn("Self payment rejected")
+	return bridge.PaymentSelfPayment
+}
+
+// withMetadata returns the payment operation followed by manage data
+// operations setting metadata as data entries of the source account, in the
+// order of keys
+func withMetadata(operation b.TransactionMutator, metadata map[string]string) b.TransactionMutator {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ops := operations{operation}
+	for _, key := range keys {
+		ops = append(ops, b.SetData(key, []byte(metadata[key])))
+	}
+	return ops
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// requiresApproval returns true when the payment is above auto_approve_amount
+// of its asset. Payments of assets without auto_approve_amount always require
+// approval.
+func (rh *RequestHandler) requiresApproval(request *bridge.PaymentRequest) bool {
+	asset, ok := request.AssetConfig(rh.reloadable().Assets)
+	if !ok || asset.AutoApproveAmount == "" {
+		return true
+	}
+
+	// Amounts are checked in Validate
+	return amounts.MustParse(request.Amount) > amounts.MustParse(asset.AutoApproveAmount)
+}
+
+// ApprovePayment implements POST /admin/payments/{id}/approve endpoint. The
+// payment must be approved with an API key other than the key that sent it.
+// Approved payments are held until settlement window of the source account
+// ends and submitted by ReleaseHeldPayments.
+func (rh *RequestHandler) ApprovePayment(c web.C, w http.ResponseWriter, r *http.Request) {
+	key := auth.FromContext(r.Context())
+	if key == nil {
+		server.Write(w, protocols.UnauthorizedError)
+		return
+	}
+
+	heldPayment, err := rh.Repository.GetHeldPaymentByPaymentID(r.Context(), c.URLParams["id"])
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting held payment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if heldPayment == nil {
+		server.Write(w, bridge.PaymentNotFound)
+		return
+	}
+
+	if heldPayment.Status != entities.HeldPaymentStatusPending {
+		server.Write(w, bridge.PaymentNotPending)
+		return
+	}
+
+	if heldPayment.CreatedBy == nil || *heldPayment.CreatedBy == key.ID {
+		log.WithFields(log.Fields{"id": heldPayment.PaymentID, "key": key.ID}).Warn("Payment approved with the API key that sent it")
+		server.Write(w, bridge.PaymentApprovalSameKey)
+		return
+	}
+
+	now := clock.Now()
+	settleAt := now.Add(rh.Config.Settlement.DelayFor(heldPaymentTenant(heldPayment)))
+	approved, err := rh.Repository.ApproveHeldPayment(r.Context(), heldPayment, key.ID, now, settleAt)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error approving held payment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if !approved {
+		// Approved or cancelled in the meantime
+		server.Write(w, bridge.PaymentNotPending)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"id":          heldPayment.PaymentID,
+		"created_by":  *heldPayment.CreatedBy,
+		"approved_by": key.ID,
+		"settle_at":   settleAt,
+	}).Info("Payment approved")
+	server.Write(w, newHeldPaymentResponse(heldPayment))
+}
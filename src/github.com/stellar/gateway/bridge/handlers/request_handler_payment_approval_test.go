@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestPaymentApproval(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	clock.Default = clock.Func(func() time.Time { return now })
+	defer func() { clock.Default = clock.System }()
+
+	c := &config.Config{
+		Assets: []config.Asset{
+			{Code: "XLM", AutoApproveAmount: "100"},
+			{Code: "EUR", Issuer: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+		},
+		Approval:   config.Approval{Enabled: true},
+		Settlement: config.Settlement{Delay: "15m"},
+	}
+	rh := NewRequestHandler(c, nil, nil, driver, db.NewRepository(driver), db.NewEntityManager(driver), nil, nil, nil, nil)
+
+	assert.False(t, rh.requiresApproval(&bridge.PaymentRequest{Amount: "100"}))
+	assert.True(t, rh.requiresApproval(&bridge.PaymentRequest{Amount: "100.0000001"}))
+	assert.True(t, rh.requiresApproval(&bridge.PaymentRequest{
+		Amount:      "1",
+		AssetCode:   "EUR",
+		AssetIssuer: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632",
+	}))
+
+	maker := "maker"
+	require.NoError(t, rh.EntityManager.Persist(&entities.HeldPayment{
+		PaymentID:     "payment-1",
+		SourceAccount: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ",
+		Request:       "amount=500&destination=bob%2Astellar.org",
+		Status:        entities.HeldPaymentStatusPending,
+		CreatedAt:     now,
+		SettleAt:      now,
+		CreatedBy:     &maker,
+	}))
+
+	authenticator := auth.NewAuthenticator(
+		[]auth.Store{auth.NewStaticStore([]auth.Key{
+			{ID: "maker", Secret: "maker-secret"},
+			{ID: "checker", Secret: "checker-secret"},
+		})},
+		[]string{"/admin/payments/*"},
+		time.Minute,
+	)
+	mux := web.New()
+	mux.Use(authenticator.Middleware)
+	mux.Post("/admin/payments/:id/approve", rh.ApprovePayment)
+
+	approve := func(id, apiKey string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/admin/payments/"+id+"/approve", nil)
+		if apiKey != "" {
+			r.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, approve("payment-1", "").Code)
+	assert.Equal(t, http.StatusNotFound, approve("payment-2", "checker:checker-secret").Code)
+
+	// Payments cannot be approved by the key that sent them
+	w := approve("payment-1", "maker:maker-secret")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "approval_same_key")
+
+	w = approve("payment-1", "checker:checker-secret")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"held"`)
+
+	heldPayment, err := rh.Repository.GetHeldPaymentByPaymentID(context.Background(), "payment-1")
+	require.NoError(t, err)
+	assert.Equal(t, entities.HeldPaymentStatusHeld, heldPayment.Status)
+	require.NotNil(t, heldPayment.ApprovedBy)
+	assert.Equal(t, "checker", *heldPayment.ApprovedBy)
+	assert.True(t, heldPayment.SettleAt.Equal(now.Add(15*time.Minute)))
+
+	w = approve("payment-1", "checker:checker-secret")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "payment_not_pending")
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/submitter"
+)
+
+// submitPayment submits the transaction of the payment using submit and calls
+// finish with the result before the response is written. When
+// async_submission feature is enabled for the tenant of a request with
+// payment ID, PaymentAcceptedResponse is written as soon as the transaction is
+// saved and finish is called when the submission finishes in the background.
+// Errors returned before the transaction is saved are written like in
+// synchronous mode.
+func (rh *RequestHandler) submitPayment(
+	ctx context.Context,
+	w http.ResponseWriter,
+	request *bridge.PaymentRequest,
+	paymentID *string,
+	submit func(ctx context.Context) (horizon.SubmitTransactionResponse, error),
+	finish func(response *horizon.SubmitTransactionResponse, err error),
+) {
+	if paymentID == nil || request.Synchronous || !rh.Features.EnabledForRequest(features.AsyncSubmission, request.HTTPRequest) {
+		response, err := submit(ctx)
+		finish(&response, err)
+		rh.writeSubmitResult(w, response, err)
+		return
+	}
+
+	type result struct {
+		response horizon.SubmitTransactionResponse
+		err      error
+	}
+
+	accepted := make(chan *entities.SentTransaction, 1)
+	done := make(chan result, 1)
+	ctx = submitter.WithAccepted(ctx, func(sentTransaction *entities.SentTransaction) {
+		accepted <- sentTransaction
+	})
+
+	go func() {
+		response, err := submit(ctx)
+		finish(&response, err)
+		done <- result{response, err}
+	}()
+
+	select {
+	case sentTransaction := <-accepted:
+		log.WithFields(log.Fields{"id": *paymentID, "hash": sentTransaction.TransactionID}).Info("Transaction accepted, submitting in the background")
+		server.Write(w, &bridge.PaymentAcceptedResponse{
+			ID:     *paymentID,
+			Status: "submitting",
+			Hash:   sentTransaction.TransactionID,
+		})
+	case r := <-done:
+		rh.writeSubmitResult(w, r.response, r.err)
+	}
+}
+
+func (rh *RequestHandler) writeSubmitResult(w http.ResponseWriter, response horizon.SubmitTransactionResponse, err error) {
+	if err != nil {
+		log.WithFields(log.Fields{"error": err}).Error("Error submitting transaction")
+		server.Write(w, bridge.ErrorFromHorizonError(err))
+		return
+	}
+
+	rh.handleSubmitterResponse(w, response)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/audit"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/reports"
+)
+
+// checkAmountLimits checks the amount of a payment against min_amount,
+// max_amount and step, then daily limits of its asset. Violations are logged
+// and audited.
+func (rh *RequestHandler) checkAmountLimits(request *bridge.PaymentRequest) *protocols.ErrorResponse {
+	err := request.ValidateAmount(amountLimits(rh.reloadable().Assets))
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		rh.auditLimitViolation(request.HTTPRequest, request, errorResponse)
+		return errorResponse
+	}
+
+	errorResponse := rh.checkPaymentLimits(request.HTTPRequest.Context(), request)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		if errorResponse.Code == bridge.PaymentLimitExceeded.Code {
+			rh.auditLimitViolation(request.HTTPRequest, request, errorResponse)
+		}
+		return errorResponse
+	}
+
+	return nil
+}
+
+// amountLimits returns amount limits of assets
+func amountLimits(assets []config.Asset) []bridge.AmountLimits {
+	limits := make([]bridge.AmountLimits, 0, len(assets))
+	for _, asset := range assets {
+		if asset.MinAmount == "" && asset.MaxAmount == "" && asset.Step == "" {
+			continue
+		}
+
+		limits = append(limits, bridge.AmountLimits{
+			AssetCode:   asset.Code,
+			AssetIssuer: asset.Issuer,
+			MinAmount:   asset.MinAmount,
+			MaxAmount:   asset.MaxAmount,
+			Step:        asset.Step,
+		})
+	}
+	return limits
+}
+
+// checkPaymentLimits returns PaymentLimitExceeded error when the payment
+// would exceed daily_max_amount or destination_daily_max_amount of its asset.
+// Destinations are compared as sent in the request, so a federated address
+// and its account ID have separate limits.
+func (rh *RequestHandler) checkPaymentLimits(ctx context.Context, request *bridge.PaymentRequest) *protocols.ErrorResponse {
+	asset, ok := request.AssetConfig(rh.reloadable().Assets)
+	if !ok || !asset.HasDailyLimits() {
+		return nil
+	}
+
+	// Amount is checked in Validate
+	amount := amounts.MustParse(request.Amount)
+	day := clock.Now().UTC().Format(reports.DayFormat)
+
+	limits := []struct {
+		name        string
+		maxAmount   string
+		destination string
+	}{
+		{"daily_max_amount", asset.DailyMaxAmount, ""},
+		{"destination_daily_max_amount", asset.DestinationDailyMaxAmount, request.Destination},
+	}
+
+	for _, limit := range limits {
+		if limit.maxAmount == "" {
+			continue
+		}
+
+		used, err := rh.Repository.GetPaymentLimitUsage(ctx, day, asset.Code, asset.Issuer, limit.destination)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error getting PaymentLimitUsage")
+			return protocols.InternalServerError
+		}
+
+		maxAmount := amounts.MustParse(limit.maxAmount)
+		total, err := amounts.Add(used, amount)
+		if err == nil && total <= maxAmount {
+			continue
+		}
+
+		remaining := maxAmount - used
+		if remaining < 0 {
+			// Limit lowered during the day
+			remaining = 0
+		}
+		return bridge.NewPaymentLimitExceededError(limit.name, limit.maxAmount, amounts.String(remaining), asset)
+	}
+
+	return nil
+}
+
+// recordPaymentLimits adds the amount of a sent payment to sums checked by
+// checkPaymentLimits. Sums are recorded for assets with any daily limit, so
+// both limits apply from the first payment of the day when one is added.
+func (rh *RequestHandler) recordPaymentLimits(ctx context.Context, request *bridge.PaymentRequest) {
+	asset, ok := request.AssetConfig(rh.reloadable().Assets)
+	if !ok || !asset.HasDailyLimits() {
+		return
+	}
+
+	day := clock.Now().UTC().Format(reports.DayFormat)
+	for _, destination := range []string{"", request.Destination} {
+		err := rh.Repository.AddToPaymentLimitUsage(ctx, &entities.PaymentLimitUsage{
+			Day:         day,
+			AssetCode:   asset.Code,
+			AssetIssuer: asset.Issuer,
+			Destination: destination,
+			Amount:      amounts.MustParse(request.Amount),
+		})
+		if err != nil {
+			// Payment has been sent already
+			log.WithFields(log.Fields{"err": err, "destination": destination}).Error("Error recording PaymentLimitUsage")
+		}
+	}
+}
+
+// auditLimitViolation sends a security event of a payment rejected because
+// of limits of its asset
+func (rh *RequestHandler) auditLimitViolation(r *http.Request, request *bridge.PaymentRequest, errorResponse *protocols.ErrorResponse) {
+	event := audit.NewRequestEvent(audit.LimitViolation, r)
+	event.Message = errorResponse.Message
+	event.Details = map[string]string{
+		"code":         errorResponse.Code,
+		"amount":       request.Amount,
+		"asset_code":   request.AssetCode,
+		"asset_issuer": request.AssetIssuer,
+		"destination":  request.Destination,
+	}
+	rh.Audit.Emit(event)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentLimits(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC)
+	clock.Default = clock.Func(func() time.Time { return now })
+	defer func() { clock.Default = clock.System }()
+
+	c := &config.Config{
+		Assets: []config.Asset{
+			{Code: "XLM", DailyMaxAmount: "100", DestinationDailyMaxAmount: "60"},
+			{Code: "EUR", Issuer: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+		},
+	}
+	rh := NewRequestHandler(c, nil, nil, driver, db.NewRepository(driver), db.NewEntityManager(driver), nil, nil, nil, nil)
+
+	ctx := context.Background()
+	send := func(destination, amount string) *protocols.ErrorResponse {
+		request := &bridge.PaymentRequest{Destination: destination, Amount: amount}
+		errorResponse := rh.checkPaymentLimits(ctx, request)
+		if errorResponse == nil {
+			rh.recordPaymentLimits(ctx, request)
+		}
+		return errorResponse
+	}
+
+	assert.Nil(t, send("alice*stellar.org", "50"))
+	assert.Nil(t, send("alice*stellar.org", "10"))
+
+	errorResponse := send("alice*stellar.org", "0.0000001")
+	require.NotNil(t, errorResponse)
+	assert.Equal(t, "payment_limit_exceeded", errorResponse.Code)
+	assert.Equal(t, "destination_daily_max_amount", errorResponse.Data["limit"])
+	assert.Equal(t, "0.0000000", errorResponse.Data["remaining"])
+
+	assert.Nil(t, send("bob*stellar.org", "40"))
+
+	errorResponse = send("carol*stellar.org", "1")
+	require.NotNil(t, errorResponse)
+	assert.Equal(t, "daily_max_amount", errorResponse.Data["limit"])
+	assert.Equal(t, "100", errorResponse.Data["max_amount"])
+
+	// Assets without daily limits are not tracked
+	request := &bridge.PaymentRequest{
+		Destination: "alice*stellar.org",
+		Amount:      "1000",
+		AssetCode:   "EUR",
+		AssetIssuer: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632",
+	}
+	assert.Nil(t, rh.checkPaymentLimits(ctx, request))
+
+	// Limits are reset at midnight UTC
+	now = now.Add(time.Hour)
+	assert.Nil(t, send("alice*stellar.org", "60"))
+
+	used, err := rh.Repository.GetPaymentLimitUsage(ctx, "2026-10-15", "XLM", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000000000), used)
+}
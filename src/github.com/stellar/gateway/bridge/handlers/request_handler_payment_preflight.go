@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/preflight"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/go/xdr"
+)
+
+var preflightRejections = metrics.NewCounter("bridge_payment_preflight_rejections_total", "Number of high-value payments not sent because preflight predicted a failure.")
+
+// requiresPreflight returns true when the transaction of a payment must be
+// simulated before it is signed
+func (rh *RequestHandler) requiresPreflight(request *bridge.PaymentRequest) bool {
+	return rh.Config.Submission.Preflight.HighValue && request.RequiresRecheck(rh.reloadable().Assets)
+}
+
+// preflight returns predicted failures of tx
+func (rh *RequestHandler) preflight(tx *xdr.Transaction) ([]preflight.Failure, *protocols.ErrorResponse) {
+	checker := preflight.NewChecker(rh.Horizon, rh.Config.Submission.Preflight.BaseReserveStroops())
+	failures, err := checker.Check(tx)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error running transaction preflight")
+		return nil, bridge.ErrorFromHorizonError(err)
+	}
+	return failures, nil
+}
+
+// preflightPayment returns the error response of the first predicted failure
+// of tx or nil when the transaction is expected to succeed
+func (rh *RequestHandler) preflightPayment(tx *xdr.Transaction) *protocols.ErrorResponse {
+	failures, errorResponse := rh.preflight(tx)
+	if errorResponse != nil {
+		return errorResponse
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	preflightRejections.Inc()
+	log.WithFields(log.Fields{
+		"source":    tx.SourceAccount.Address(),
+		"operation": failures[0].Operation,
+		"code":      failures[0].Error.Code,
+	}).Warn("Preflight predicted transaction failure, payment not sent")
+	return failures[0].Error
+}
+
+// writeDryRun writes predicted failures of tx
+func (rh *RequestHandler) writeDryRun(w http.ResponseWriter, tx *xdr.Transaction) {
+	failures, errorResponse := rh.preflight(tx)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	response := &bridge.PaymentDryRunResponse{Valid: len(failures) == 0}
+	for _, failure := range failures {
+		response.Failures = append(response.Failures, bridge.PreflightFailure{
+			Operation: failure.Operation,
+			Code:      failure.Error.Code,
+			Message:   failure.Error.Message,
+		})
+	}
+	server.Write(w, response)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/test"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerPaymentPreflight(t *testing.T) {
+	const (
+		source      = "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"
+		destination = "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"
+	)
+
+	c := &config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Accounts: config.Accounts{
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		Assets:     []config.Asset{{Code: "XLM", RecheckAmount: "1000"}},
+		Submission: config.Submission{Preflight: config.Preflight{HighValue: true}},
+	}
+
+	sourceAccount := horizon.AccountResponse{
+		AccountID:  source,
+		Thresholds: horizon.AccountThreshold{MedThreshold: 1},
+		Signers:    []horizon.AccountSigner{{Key: source, Weight: 1}},
+		Balances:   []horizon.AccountBalance{{AssetType: "native", Balance: "2000"}},
+	}
+	destinationAccount := horizon.AccountResponse{
+		AccountID: destination,
+		Balances:  []horizon.AccountBalance{{AssetType: "native", Balance: "100"}},
+	}
+
+	newTransaction := func(amount string) *xdr.Transaction {
+		tx, err := b.Transaction(
+			b.SourceAccount{source},
+			b.TestNetwork,
+			b.Payment(b.Destination{destination}, b.NativeAmount{amount}),
+		)
+		require.NoError(t, err)
+		return tx.TX
+	}
+
+	newServer := func(mockHorizon *mocks.MockHorizon, mockTransactionSubmitter *mocks.MockTransactionSubmitter) *httptest.Server {
+		requestHandler := NewRequestHandler(c, nil, mockHorizon, nil, nil, nil, nil, nil, mockTransactionSubmitter, nil)
+		return httptest.NewServer(http.HandlerFunc(requestHandler.Payment))
+	}
+
+	t.Run("dry run returns predicted failures", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		mockHorizon.On("LoadAccount", destination).Return(destinationAccount, nil).Once()
+		mockHorizon.On("LoadAccountFresh", source).Return(sourceAccount, nil)
+		mockHorizon.On("LoadAccountFresh", destination).Return(destinationAccount, nil)
+		mockTransactionSubmitter.On("BuildTransaction", c.Accounts.BaseSeed, mock.Anything, nil).
+			Return(newTransaction("1999"), nil).Once()
+
+		statusCode, response := net.GetResponse(server, url.Values{"destination": {destination}, "amount": {"1999"}, "dry_run": {"true"}})
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(t, `{
+			"valid": false,
+			"failures": [{"operation": 0, "code": "payment_underfunded", "message": "Not enough funds to send this transaction."}]
+		}`, string(response))
+		mockTransactionSubmitter.AssertNotCalled(t, "SignAndSubmitRawTransaction", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("dry run of valid payment", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		mockHorizon.On("LoadAccount", destination).Return(destinationAccount, nil).Once()
+		mockHorizon.On("LoadAccountFresh", source).Return(sourceAccount, nil)
+		mockHorizon.On("LoadAccountFresh", destination).Return(destinationAccount, nil)
+		mockTransactionSubmitter.On("BuildTransaction", c.Accounts.BaseSeed, mock.Anything, nil).
+			Return(newTransaction("10"), nil).Once()
+
+		statusCode, response := net.GetResponse(server, url.Values{"destination": {destination}, "amount": {"10"}, "dry_run": {"true"}})
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.JSONEq(t, `{"valid": true}`, string(response))
+		mockTransactionSubmitter.AssertExpectations(t)
+	})
+
+	t.Run("high-value payment is not sent when preflight predicts failure", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		mockHorizon.On("LoadAccount", destination).Return(destinationAccount, nil).Once()
+		mockHorizon.On("LoadAccountFresh", source).Return(sourceAccount, nil)
+		mockHorizon.On("LoadAccountFresh", destination).Return(destinationAccount, nil)
+		mockTransactionSubmitter.On("BuildTransaction", c.Accounts.BaseSeed, mock.Anything, nil).
+			Return(newTransaction("1999"), nil).Once()
+
+		statusCode, response := net.GetResponse(server, url.Values{"destination": {destination}, "amount": {"1999"}})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "payment_underfunded", test.StringToJSONMap(string(response))["code"])
+		mockTransactionSubmitter.AssertNotCalled(t, "SignAndSubmitRawTransaction", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("high-value payment is sent when preflight succeeds", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		mockHorizon.On("LoadAccount", destination).Return(destinationAccount, nil).Once()
+		mockHorizon.On("LoadAccountFresh", source).Return(sourceAccount, nil)
+		mockHorizon.On("LoadAccountFresh", destination).Return(destinationAccount, nil)
+		mockTransactionSubmitter.On("BuildTransaction", c.Accounts.BaseSeed, mock.Anything, nil).
+			Return(newTransaction("1500"), nil).Once()
+		mockTransactionSubmitter.On("SignAndSubmitRawTransaction", (*string)(nil), c.Accounts.BaseSeed, mock.Anything).
+			Return(horizon.SubmitTransactionResponse{}, nil).Once()
+
+		statusCode, _ := net.GetResponse(server, url.Values{"destination": {destination}, "amount": {"1500"}})
+		assert.Equal(t, http.StatusOK, statusCode)
+		mockTransactionSubmitter.AssertExpectations(t)
+	})
+}
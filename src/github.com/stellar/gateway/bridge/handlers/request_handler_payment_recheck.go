@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+var recheckRejections = metrics.NewCounter("bridge_payment_recheck_rejections_total", "Number of high-value payments rejected because account state changed since validation.")
+
+// accountsRecheck contains accounts and assets of a high-value payment that
+// are checked again right before the transaction is signed
+type accountsRecheck struct {
+	// source is a seed or public key of the source account
+	source      string
+	destination string
+	// sendAssetCode and sendAssetIssuer differ from assetCode and assetIssuer
+	// in path payments only. Empty code is native asset.
+	sendAssetCode   string
+	sendAssetIssuer string
+	assetCode       string
+	assetIssuer     string
+	// createAccount is true when the payment creates destination account
+	createAccount bool
+}
+
+// newAccountsRecheck returns accountsRecheck of the first payment operation
+// of a transaction built by compliance server
+func newAccountsRecheck(source string, tx *xdr.Transaction) (recheck accountsRecheck, ok bool) {
+	recheck.source = source
+
+	for _, operation := range tx.Operations {
+		switch operation.Body.Type {
+		case xdr.OperationTypeCreateAccount:
+			recheck.destination = operation.Body.CreateAccountOp.Destination.Address()
+			recheck.createAccount = true
+			return recheck, true
+		case xdr.OperationTypePayment:
+			op := operation.Body.PaymentOp
+			recheck.destination = op.Destination.Address()
+			op.Asset.Extract(new(string), &recheck.assetCode, &recheck.assetIssuer)
+			recheck.sendAssetCode, recheck.sendAssetIssuer = recheck.assetCode, recheck.assetIssuer
+			return recheck, true
+		case xdr.OperationTypePathPayment:
+			op := operation.Body.PathPaymentOp
+			recheck.destination = op.Destination.Address()
+			op.DestAsset.Extract(new(string), &recheck.assetCode, &recheck.assetIssuer)
+			op.SendAsset.Extract(new(string), &recheck.sendAssetCode, &recheck.sendAssetIssuer)
+			return recheck, true
+		}
+	}
+
+	return recheck, false
+}
+
+// recheckAccounts loads source and destination accounts bypassing caches and
+// runs checks of signers and trustlines again, so a payment is not signed
+// when account state changed after the request was validated (ex. signing key
+// was removed or trustline authorization revoked). Returns nil when the
+// payment can be signed.
+func (rh *RequestHandler) recheckAccounts(recheck accountsRecheck) *protocols.ErrorResponse {
+	sourceKeypair, err := keypair.Parse(recheck.source)
+	if err != nil {
+		return protocols.NewInvalidParameterError("source", "", "Source parameter must start with `S`.")
+	}
+	sourceAddress := sourceKeypair.Address()
+
+	fields := log.Fields{"source": sourceAddress, "destination": recheck.destination}
+	log.WithFields(fields).Info("Rechecking accounts of high-value payment")
+
+	source, errorResponse := rh.loadAccountFresh(sourceAddress, bridge.PaymentSourceNotExist)
+	if errorResponse != nil {
+		return rh.rejectRecheck(fields, errorResponse)
+	}
+
+	// Signing key is unknown when transactions are signed by external service
+	if !config.IsPublicKeyOnly(recheck.source) {
+		weight := source.SignerWeight(sourceAddress)
+		if weight == 0 || weight < source.Thresholds.MedThreshold {
+			return rh.rejectRecheck(fields, bridge.PaymentSourceSignerChanged)
+		}
+	}
+
+	if recheck.sendAssetCode != "" && recheck.sendAssetIssuer != sourceAddress {
+		balance := source.Balance(recheck.sendAssetCode, recheck.sendAssetIssuer)
+		if balance == nil {
+			return rh.rejectRecheck(fields, bridge.PaymentSrcNoTrust)
+		}
+		if !balance.Authorized() {
+			return rh.rejectRecheck(fields, bridge.PaymentSrcNotAuthorized)
+		}
+	}
+
+	if recheck.createAccount {
+		return nil
+	}
+
+	destination, errorResponse := rh.loadAccountFresh(recheck.destination, bridge.PaymentNoDestination)
+	if errorResponse != nil {
+		return rh.rejectRecheck(fields, errorResponse)
+	}
+
+	if recheck.assetCode != "" && recheck.assetIssuer != recheck.destination {
+		balance := destination.Balance(recheck.assetCode, recheck.assetIssuer)
+		if balance == nil {
+			return rh.rejectRecheck(fields, bridge.PaymentNoTrust)
+		}
+		if !balance.Authorized() {
+			return rh.rejectRecheck(fields, bridge.PaymentNotAuthorized)
+		}
+	}
+
+	return nil
+}
+
+// loadAccountFresh returns notFound error response when account cannot be
+// loaded and Horizon is available
+func (rh *RequestHandler) loadAccountFresh(accountID string, notFound *protocols.ErrorResponse) (*horizon.AccountResponse, *protocols.ErrorResponse) {
+	account, err := rh.Horizon.LoadAccountFresh(accountID)
+	if errors.Cause(err) == horizon.ErrUnavailable {
+		return nil, bridge.HorizonUnavailable
+	} else if err != nil {
+		log.WithFields(log.Fields{"account": accountID, "err": err}).Warn("Error loading account")
+		return nil, notFound
+	}
+	return &account, nil
+}
+
+func (rh *RequestHandler) rejectRecheck(fields log.Fields, errorResponse *protocols.ErrorResponse) *protocols.ErrorResponse {
+	if errorResponse != bridge.HorizonUnavailable {
+		recheckRejections.Inc()
+	}
+	log.WithFields(fields).WithFields(log.Fields{"code": errorResponse.Code}).Warn("Account state changed since validation, payment not sent")
+	return errorResponse
+}
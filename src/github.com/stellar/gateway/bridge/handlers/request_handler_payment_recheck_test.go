@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestHandlerPaymentRecheck(t *testing.T) {
+	const (
+		source      = "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"
+		destination = "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"
+		issuer      = "GC7DVHGMSQYAPYXQU652VVHEMZ2OZN4VH44T67QILDHDMBOACMZHQWLW"
+	)
+
+	c := &config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Accounts: config.Accounts{
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		Assets: []config.Asset{
+			{Code: "XLM", RecheckAmount: "1000"},
+			{Code: "USD", Issuer: issuer, RecheckAmount: "1000"},
+		},
+	}
+
+	sourceAccount := horizon.AccountResponse{
+		AccountID:  source,
+		Thresholds: horizon.AccountThreshold{MedThreshold: 1},
+		Signers:    []horizon.AccountSigner{{Key: source, Weight: 1}},
+	}
+
+	notAuthorized := false
+	destinationAccount := horizon.AccountResponse{
+		AccountID: destination,
+		Balances: []horizon.AccountBalance{
+			{AssetType: "native", Balance: "100"},
+			{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: issuer, IsAuthorized: &notAuthorized},
+		},
+	}
+
+	newServer := func(mockHorizon *mocks.MockHorizon, mockTransactionSubmitter *mocks.MockTransactionSubmitter) *httptest.Server {
+		requestHandler := NewRequestHandler(c, nil, mockHorizon, nil, nil, nil, nil, nil, mockTransactionSubmitter, nil)
+		return httptest.NewServer(http.HandlerFunc(requestHandler.Payment))
+	}
+
+	t.Run("payment below recheck_amount is not rechecked", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		mockHorizon.On("LoadAccount", destination).Return(destinationAccount, nil).Once()
+		mockTransactionSubmitter.On("SubmitTransaction", (*string)(nil), c.Accounts.BaseSeed, mock.Anything, nil).
+			Return(horizon.SubmitTransactionResponse{}, nil).Once()
+
+		statusCode, _ := net.GetResponse(server, url.Values{"destination": {destination}, "amount": {"1000"}})
+		assert.Equal(t, http.StatusOK, statusCode)
+		mockHorizon.AssertExpectations(t)
+		mockTransactionSubmitter.AssertExpectations(t)
+	})
+
+	t.Run("high-value payment is sent when accounts have not changed", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		mockHorizon.On("LoadAccount", destination).Return(destinationAccount, nil).Once()
+		mockHorizon.On("LoadAccountFresh", source).Return(sourceAccount, nil).Once()
+		mockHorizon.On("LoadAccountFresh", destination).Return(destinationAccount, nil).Once()
+		mockTransactionSubmitter.On("SubmitTransaction", (*string)(nil), c.Accounts.BaseSeed, mock.Anything, nil).
+			Return(horizon.SubmitTransactionResponse{}, nil).Once()
+
+		statusCode, _ := net.GetResponse(server, url.Values{"destination": {destination}, "amount": {"1000.5"}})
+		assert.Equal(t, http.StatusOK, statusCode)
+		mockHorizon.AssertExpectations(t)
+		mockTransactionSubmitter.AssertExpectations(t)
+	})
+
+	t.Run("high-value payment is rejected when signing key has been removed", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		changed := sourceAccount
+		changed.Signers = []horizon.AccountSigner{{Key: source, Weight: 0}, {Key: issuer, Weight: 1}}
+
+		mockHorizon.On("LoadAccount", destination).Return(destinationAccount, nil).Once()
+		mockHorizon.On("LoadAccountFresh", source).Return(changed, nil).Once()
+
+		statusCode, response := net.GetResponse(server, url.Values{"destination": {destination}, "amount": {"5000"}})
+		assert.Equal(t, http.StatusConflict, statusCode)
+		assert.Equal(t, "source_signer_changed", test.StringToJSONMap(string(response))["code"])
+		mockHorizon.AssertExpectations(t)
+		mockTransactionSubmitter.AssertNotCalled(t, "SubmitTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("high-value payment is rejected when destination trustline is not authorized", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		mockHorizon.On("LoadAccountFresh", source).Return(sourceAccount, nil).Once()
+		mockHorizon.On("LoadAccountFresh", destination).Return(destinationAccount, nil).Once()
+
+		statusCode, response := net.GetResponse(server, url.Values{
+			"destination":  {destination},
+			"amount":       {"5000"},
+			"asset_code":   {"USD"},
+			"asset_issuer": {issuer},
+			// Path payment sending native asset
+			"send_max": {"5000"},
+		})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "payment_not_authorized", test.StringToJSONMap(string(response))["code"])
+		mockHorizon.AssertExpectations(t)
+	})
+}
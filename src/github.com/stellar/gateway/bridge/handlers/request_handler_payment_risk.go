@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/access"
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/risk"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/go/keypair"
+)
+
+// checkRisk runs risk hooks of a payment sent from sourceAccount. It returns
+// true when the payment has been denied or held and the response has been
+// written. Held payments wait for approval when approval is enabled.
+func (rh *RequestHandler) checkRisk(w http.ResponseWriter, request *bridge.PaymentRequest, sourceAccount string) bool {
+	r := request.HTTPRequest
+	result, err := rh.Risk.Check(r.Context(), rh.riskPayment(request, sourceAccount))
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "id": request.ID}).Error("Error checking payment risk")
+		server.Write(w, bridge.PaymentRiskCheckFailed)
+		return true
+	}
+
+	switch result.Decision {
+	case risk.DecisionDeny:
+		errorResponse := bridge.NewPaymentRiskDeniedError(result.Hook, result.Reason)
+		log.WithFields(errorResponse.LogData).Warn("Payment denied by risk hook")
+		rh.auditLimitViolation(r, request, errorResponse)
+		server.Write(w, errorResponse)
+		return true
+	case risk.DecisionHold:
+		log.WithFields(log.Fields{"id": request.ID, "hook": result.Hook, "reason": result.Reason}).Info("Payment held by risk hook")
+		if rh.Config.Approval.Enabled {
+			rh.holdPayment(w, request, sourceAccount, entities.HeldPaymentStatusPending, 0)
+			return true
+		}
+
+		delay := result.HoldDuration()
+		if delay == 0 {
+			delay = rh.Config.Risk.HoldDurationOrDefault()
+		}
+		rh.holdPayment(w, request, sourceAccount, entities.HeldPaymentStatusHeld, delay)
+		return true
+	}
+
+	return false
+}
+
+// riskPayment returns the context of a payment passed to risk hooks
+func (rh *RequestHandler) riskPayment(request *bridge.PaymentRequest, sourceAccount string) risk.Payment {
+	r := request.HTTPRequest
+	payment := risk.Payment{
+		ID:              request.ID,
+		Source:          sourceAccount,
+		Destination:     request.Destination,
+		DestinationName: request.DestinationName,
+		Amount:          request.Amount,
+		AssetCode:       request.AssetCode,
+		AssetIssuer:     request.AssetIssuer,
+		SendMax:         request.SendMax,
+		SendAssetCode:   request.SendAssetCode,
+		SendAssetIssuer: request.SendAssetIssuer,
+		MemoType:        request.MemoType,
+		Memo:            request.Memo,
+		Compliance:      rh.Config.Compliance != "" && (request.ExtraMemo != "" || request.PrivateNote != "" || request.UseCompliance),
+		Tenant:          features.Tenant(r),
+		Timestamp:       clock.Now().Unix(),
+	}
+	if payment.AssetCode == "" {
+		payment.AssetCode = "XLM"
+	}
+	if key := auth.FromContext(r.Context()); key != nil {
+		payment.APIKey = key.ID
+	}
+
+	_, _, trustedProxies := rh.Config.Access.Networks()
+	if ip := access.ClientIP(r, trustedProxies); ip != nil {
+		payment.ClientIP = ip.String()
+	}
+	return payment
+}
+
+// recordPaymentVelocity records a sent payment counted by velocity rules
+func (rh *RequestHandler) recordPaymentVelocity(request *bridge.PaymentRequest) {
+	if rh.Velocity == nil {
+		return
+	}
+
+	sourceKeypair, err := keypair.Parse(request.Source)
+	if err != nil {
+		return
+	}
+
+	velocity := &entities.PaymentVelocity{
+		Source:      sourceKeypair.Address(),
+		Destination: request.Destination,
+		AssetCode:   request.AssetCode,
+		AssetIssuer: request.AssetIssuer,
+		// Amount is checked in Validate
+		Amount:    amounts.MustParse(request.Amount),
+		CreatedAt: clock.Now(),
+	}
+	if velocity.AssetCode == "" {
+		velocity.AssetCode = "XLM"
+	}
+
+	err = rh.EntityManager.Persist(velocity)
+	if err != nil {
+		// Payment has been sent already
+		log.WithFields(log.Fields{"err": err}).Error("Error recording PaymentVelocity")
+	}
+}
+
+// PruneVelocity removes payments outside of all windows of velocity rules
+func (rh *RequestHandler) PruneVelocity(ctx context.Context) {
+	if rh.Velocity == nil {
+		return
+	}
+
+	removed, err := rh.Velocity.Prune(ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error pruning PaymentVelocity")
+		return
+	}
+
+	if removed > 0 {
+		log.WithFields(log.Fields{"removed": removed}).Debug("Pruned PaymentVelocity")
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/risk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentRisk(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	clock.Default = clock.Func(func() time.Time { return now })
+	defer func() { clock.Default = clock.System }()
+
+	c := &config.Config{
+		Accounts: config.Accounts{
+			// GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		Risk: config.Risk{
+			Velocity: []config.VelocityRule{{Scope: "source", Window: "1h", MaxCount: 2}},
+		},
+	}
+	rh := NewRequestHandler(c, nil, nil, driver, db.NewRepository(driver), db.NewEntityManager(driver), nil, nil, nil, nil)
+
+	var payments []risk.Payment
+	decision := risk.DecisionDeny
+	rh.Velocity = risk.NewVelocityChecker(c.Risk.VelocityRules(), rh.Repository)
+	rh.Risk = risk.NewChain(false)
+	rh.Risk.Add("velocity", rh.Velocity)
+	rh.Risk.Add("fraud", risk.HookFunc(func(ctx context.Context, payment risk.Payment) (risk.Result, error) {
+		payments = append(payments, payment)
+		return risk.Result{Decision: decision, Reason: "Destination on watchlist.", HoldFor: 1800}, nil
+	}))
+
+	send := func(id string) *httptest.ResponseRecorder {
+		values := url.Values{
+			"id":          {id},
+			"destination": {"GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"},
+			"amount":      {"20"},
+			"memo_type":   {"id"},
+			"memo":        {"42"},
+		}
+		r := httptest.NewRequest("POST", "/payment", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		rh.Payment(w, r)
+		return w
+	}
+
+	w := send("payment-1")
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "risk_denied")
+	assert.Contains(t, w.Body.String(), "Destination on watchlist.")
+	require.Len(t, payments, 1)
+	assert.Equal(t, risk.Payment{
+		ID:          "payment-1",
+		Source:      "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ",
+		Destination: "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS",
+		Amount:      "20.0000000",
+		AssetCode:   "XLM",
+		MemoType:    "id",
+		Memo:        "42",
+		ClientIP:    "192.0.2.1",
+		Timestamp:   now.Unix(),
+	}, payments[0])
+
+	decision = risk.DecisionHold
+	w = send("payment-2")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	heldPayment, err := rh.Repository.GetHeldPaymentByPaymentID(context.Background(), "payment-2")
+	require.NoError(t, err)
+	require.NotNil(t, heldPayment)
+	assert.Equal(t, entities.HeldPaymentStatusHeld, heldPayment.Status)
+	assert.True(t, heldPayment.SettleAt.Equal(now.Add(30*time.Minute)))
+
+	// Sent payments are counted by velocity rules
+	request := &bridge.PaymentRequest{Source: c.Accounts.BaseSeed, Destination: "bob*stellar.org", Amount: "1"}
+	rh.recordPaymentVelocity(request)
+	rh.recordPaymentVelocity(request)
+
+	w = send("payment-3")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "More than 2 payments from GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ in 1h0m0s.")
+	// Hooks after a deny are not called
+	assert.Len(t, payments, 2)
+}
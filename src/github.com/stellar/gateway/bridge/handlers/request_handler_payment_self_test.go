@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestHandlerPaymentSelfPayment(t *testing.T) {
+	const source = "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"
+
+	c := &config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Accounts: config.Accounts{
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+	}
+
+	newServer := func(mockHorizon *mocks.MockHorizon, mockTransactionSubmitter *mocks.MockTransactionSubmitter) *httptest.Server {
+		requestHandler := NewRequestHandler(c, nil, mockHorizon, nil, nil, nil, nil, nil, mockTransactionSubmitter, nil)
+		return httptest.NewServer(http.HandlerFunc(requestHandler.Payment))
+	}
+
+	t.Run("self payment is rejected", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		statusCode, response := net.GetResponse(server, url.Values{"destination": {source}, "amount": {"10"}})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, string(response), "self_payment")
+		mockHorizon.AssertExpectations(t)
+		mockTransactionSubmitter.AssertExpectations(t)
+	})
+
+	t.Run("self payment is sent when allowed", func(t *testing.T) {
+		c.AllowSelfPayments = true
+		defer func() { c.AllowSelfPayments = false }()
+
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		mockHorizon.On("LoadAccount", source).Return(horizon.AccountResponse{AccountID: source}, nil).Once()
+		mockTransactionSubmitter.On("SubmitTransaction", (*string)(nil), c.Accounts.BaseSeed, mock.Anything, nil).
+			Return(horizon.SubmitTransactionResponse{}, nil).Once()
+
+		statusCode, _ := net.GetResponse(server, url.Values{"destination": {source}, "amount": {"10"}})
+		assert.Equal(t, http.StatusOK, statusCode)
+		mockHorizon.AssertExpectations(t)
+		mockTransactionSubmitter.AssertExpectations(t)
+	})
+
+	t.Run("zero amount is rejected", func(t *testing.T) {
+		mockHorizon := new(mocks.MockHorizon)
+		mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+		server := newServer(mockHorizon, mockTransactionSubmitter)
+		defer server.Close()
+
+		statusCode, response := net.GetResponse(server, url.Values{"destination": {source}, "amount": {"0.0"}})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, string(response), "amount_zero")
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentStrictCompliance(t *testing.T) {
+	c := &config.Config{
+		Accounts: config.Accounts{
+			// GAHA6GRCLCCN7XPHRL5VB6DEAADDEBXBHUCTEB4PCPDHAKZV5JTMOVZ4
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+	}
+	mockSubmitter := new(mocks.MockTransactionSubmitter)
+	rh := NewRequestHandler(c, nil, nil, nil, nil, nil, nil, nil, mockSubmitter, nil)
+
+	flags, err := features.New([]config.Feature{
+		{Name: features.StrictCompliance, Tenants: []string{"acme"}},
+	}, nil)
+	require.NoError(t, err)
+	rh.Features = flags
+
+	send := func(tenant string, header http.Header) *httptest.ResponseRecorder {
+		values := url.Values{
+			"destination": {"bob*example.com"},
+			"amount":      {"20"},
+		}
+		r := httptest.NewRequest("POST", "/payment", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for name, value := range header {
+			r.Header[name] = value
+		}
+		r = r.WithContext(auth.WithKey(r.Context(), &auth.Key{ID: tenant + "-key", Tenant: tenant}))
+		w := httptest.NewRecorder()
+		rh.Payment(w, r)
+		return w
+	}
+
+	// Compliance server is not configured so payment cannot be sent
+	w := send("acme", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "compliance_required")
+
+	// Tenant cannot be changed with a header
+	w = send("acme", http.Header{"X-Tenant-Id": {"other"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "compliance_required")
+
+	mockSubmitter.AssertNotCalled(t, "SubmitTransaction")
+}
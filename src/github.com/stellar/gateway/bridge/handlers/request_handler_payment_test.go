@@ -614,6 +614,54 @@ func TestRequestHandlerPayment(t *testing.T) {
 			})
 		})
 
+		Convey("When amount is not within asset limits", func() {
+			c.Assets = []config.Asset{
+				{Code: "USD", Issuer: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632", MinAmount: "1", MaxAmount: "1000", Step: "0.01"},
+			}
+
+			Reset(func() {
+				c.Assets = nil
+			})
+
+			params := url.Values{
+				"source":       {"SDRAS7XIQNX25UDCCX725R4EYGBFYGJE4HJ2A3DFCWJIHMRSMS7CXX42"},
+				"destination":  {"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+				"asset_code":   {"USD"},
+				"asset_issuer": {"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+			}
+
+			Convey("amount is below minimum", func() {
+				params.Set("amount", "0.5")
+				statusCode, response := net.GetResponse(testServer, params)
+				responseString := strings.TrimSpace(string(response))
+				assert.Equal(t, 400, statusCode)
+				expected := test.StringToJSONMap(`{
+  "code": "amount_below_minimum",
+  "message": "Amount is lower than minimum amount allowed for this asset.",
+  "data": {
+    "min_amount": "1",
+    "max_amount": "1000",
+    "step": "0.01"
+  }
+}`)
+				assert.Equal(t, expected, test.StringToJSONMap(responseString))
+			})
+
+			Convey("amount is above maximum", func() {
+				params.Set("amount", "1000.01")
+				statusCode, response := net.GetResponse(testServer, params)
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, "amount_above_maximum", test.StringToJSONMap(string(response))["code"])
+			})
+
+			Convey("amount is not a multiple of step", func() {
+				params.Set("amount", "20.005")
+				statusCode, response := net.GetResponse(testServer, params)
+				assert.Equal(t, 400, statusCode)
+				assert.Equal(t, "amount_invalid_step", test.StringToJSONMap(string(response))["code"])
+			})
+		})
+
 		Convey("When params are valid - base account (no source param)", func() {
 			validParams := url.Values{
 				"destination":  {"GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
@@ -1186,7 +1234,9 @@ func TestRequestHandlerPayment(t *testing.T) {
 					assert.Equal(t, []string{"GAW77Z6GPWXSODJOMF5L5BMX6VMYGEJRKUNBC2CZ725JTQZORK74HQQD"}, values["source"])
 					values.Del("source")
 					params.Del("source")
-					assert.Equal(t, values.Encode(), params.Encode())
+					// Amount is normalized to 7 decimal places
+					params.Set("amount", "20.0000000")
+					assert.Equal(t, params.Encode(), values.Encode())
 				}).Once()
 
 				statusCode, response := net.GetResponse(testServer, params)
@@ -1214,7 +1264,9 @@ func TestRequestHandlerPayment(t *testing.T) {
 					assert.Equal(t, []string{"GAW77Z6GPWXSODJOMF5L5BMX6VMYGEJRKUNBC2CZ725JTQZORK74HQQD"}, values["source"])
 					values.Del("source")
 					params.Del("source")
-					assert.Equal(t, values.Encode(), params.Encode())
+					// Amount is normalized to 7 decimal places
+					params.Set("amount", "20.0000000")
+					assert.Equal(t, params.Encode(), values.Encode())
 				}).Once()
 
 				statusCode, response := net.GetResponse(testServer, params)
@@ -1242,7 +1294,9 @@ func TestRequestHandlerPayment(t *testing.T) {
 					assert.Equal(t, []string{"GAW77Z6GPWXSODJOMF5L5BMX6VMYGEJRKUNBC2CZ725JTQZORK74HQQD"}, values["source"])
 					values.Del("source")
 					params.Del("source")
-					assert.Equal(t, values.Encode(), params.Encode())
+					// Amount is normalized to 7 decimal places
+					params.Set("amount", "20.0000000")
+					assert.Equal(t, params.Encode(), values.Encode())
 				}).Once()
 
 				statusCode, response := net.GetResponse(testServer, params)
@@ -1327,7 +1381,9 @@ func TestRequestHandlerPayment(t *testing.T) {
 					assert.Equal(t, []string{"GAW77Z6GPWXSODJOMF5L5BMX6VMYGEJRKUNBC2CZ725JTQZORK74HQQD"}, values["source"])
 					values.Del("source")
 					params.Del("source")
-					assert.Equal(t, values.Encode(), params.Encode())
+					// Amount is normalized to 7 decimal places
+					params.Set("amount", "20.0000000")
+					assert.Equal(t, params.Encode(), values.Encode())
 				}).Once()
 
 				var ledger uint64
@@ -1467,7 +1523,9 @@ func TestRequestHandlerPayment(t *testing.T) {
 					assert.Equal(t, []string{"GAW77Z6GPWXSODJOMF5L5BMX6VMYGEJRKUNBC2CZ725JTQZORK74HQQD"}, values["source"])
 					values.Del("source")
 					params.Del("source")
-					assert.Equal(t, values.Encode(), params.Encode())
+					// Amount is normalized to 7 decimal places
+					params.Set("amount", "20.0000000")
+					assert.Equal(t, params.Encode(), values.Encode())
 				}).Once()
 
 				var ledger uint64
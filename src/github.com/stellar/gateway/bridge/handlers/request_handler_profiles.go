@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/profiles"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// AdminDestinationProfiles implements GET /admin/destination-profiles
+// endpoint. It returns built-in and local destination profiles.
+func (rh *RequestHandler) AdminDestinationProfiles(w http.ResponseWriter, r *http.Request) {
+	err := server.WriteJSON(w, rh.Profiles.All())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding destination profiles")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+}
+
+// destinationProfile returns a profile of the destination or nil. Profiles
+// matching the account or federation domain are used first, then a profile
+// assigned to the account in memo required destinations.
+func (rh *RequestHandler) destinationProfile(ctx context.Context, accountID, address string) (*profiles.Profile, error) {
+	if profile := rh.Profiles.Match(accountID, address); profile != nil {
+		return profile, nil
+	}
+
+	if rh.Repository == nil {
+		return nil, nil
+	}
+
+	destination, err := rh.Repository.GetMemoRequiredDestination(ctx, accountID)
+	if err != nil || destination == nil || destination.Profile == nil {
+		return nil, err
+	}
+	return rh.Profiles.Get(*destination.Profile), nil
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+)
+
+// QuoteResponse is a quote with its path and price
+type QuoteResponse struct {
+	*entities.Quote
+	Path []protocols.Asset `json:"path"`
+	// Price is the send amount per unit of the received amount
+	Price string `json:"price"`
+}
+
+// Quote implements POST /quote endpoint. It finds paths between the assets
+// using Horizon path finding and stores the best one as a quote, so
+// /payment sent with quote_id uses its path and send amount until it
+// expires.
+func (rh *RequestHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.QuoteRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	networkHandler, errorResponse := rh.networkHandler(request.Network)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	paths, err := networkHandler.Horizon.FindPaths(horizon.PathsRequest{
+		StrictSend:        request.StrictSend(),
+		SourceAsset:       horizon.NewPathAsset(request.SendAssetCode, request.SendAssetIssuer),
+		SourceAmount:      request.SendAmount,
+		DestinationAsset:  horizon.NewPathAsset(request.AssetCode, request.AssetIssuer),
+		DestinationAmount: request.Amount,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error finding paths")
+		server.Write(w, bridge.ErrorFromHorizonError(err))
+		return
+	}
+
+	path := bestPath(paths, request.StrictSend())
+	if path == nil {
+		server.Write(w, bridge.QuoteNoPath)
+		return
+	}
+
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error generating quote ID")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	now := clock.Now()
+	quote := &entities.Quote{
+		QuoteID:         hex.EncodeToString(id),
+		Network:         request.Network,
+		Type:            entities.QuoteTypeStrictReceive,
+		SendAssetCode:   request.SendAssetCode,
+		SendAssetIssuer: request.SendAssetIssuer,
+		SendAmount:      amounts.String(amounts.MustParse(path.SourceAmount)),
+		AssetCode:       request.AssetCode,
+		AssetIssuer:     request.AssetIssuer,
+		Amount:          amounts.String(amounts.MustParse(path.DestinationAmount)),
+		ExpiresAt:       now.Add(rh.Config.Quotes.ExpiryOrDefault()),
+		CreatedAt:       now,
+	}
+	if request.StrictSend() {
+		quote.Type = entities.QuoteTypeStrictSend
+	}
+
+	var assets []protocols.Asset
+	for _, asset := range path.Path {
+		assets = append(assets, protocols.Asset{Code: asset.AssetCode, Issuer: asset.AssetIssuer})
+	}
+	quote.SetAssets(assets)
+
+	// Quotes of all networks are stored in the main database
+	err = rh.EntityManager.Persist(quote)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting Quote")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"quote_id":    quote.QuoteID,
+		"send_amount": quote.SendAmount,
+		"amount":      quote.Amount,
+	}).Info("Quote created")
+
+	err = server.WriteJSON(w, newQuoteResponse(quote))
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding Quote")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+func newQuoteResponse(quote *entities.Quote) QuoteResponse {
+	// Amounts are validated when the quote is created
+	send, _ := amounts.Parse(quote.SendAmount)
+	received, _ := amounts.Parse(quote.Amount)
+	return QuoteResponse{
+		Quote: quote,
+		Path:  quote.Assets(),
+		Price: new(big.Rat).SetFrac64(send, received).FloatString(7),
+	}
+}
+
+// bestPath returns the path sending the smallest amount (strict receive) or
+// receiving the largest amount (strict send), nil when there are no valid
+// paths
+func bestPath(paths []horizon.PathResponse, strictSend bool) (best *horizon.PathResponse) {
+	var bestAmount int64
+	for i := range paths {
+		source, err := amounts.Parse(paths[i].SourceAmount)
+		if err != nil || source <= 0 {
+			continue
+		}
+		destination, err := amounts.Parse(paths[i].DestinationAmount)
+		if err != nil || destination <= 0 {
+			continue
+		}
+
+		if strictSend {
+			if best == nil || destination > bestAmount {
+				best, bestAmount = &paths[i], destination
+			}
+		} else if best == nil || source < bestAmount {
+			best, bestAmount = &paths[i], source
+		}
+	}
+	return
+}
+
+// applyQuote sets send_max, send asset and path of the payment to the ones of
+// the quote. The asset and amount are set to the quoted ones when they are
+// empty, they must match the quote otherwise.
+func (rh *RequestHandler) applyQuote(ctx context.Context, request *bridge.PaymentRequest) *protocols.ErrorResponse {
+	if request.SendMax != "" || request.SendAssetCode != "" || request.SendAssetIssuer != "" || len(request.Path) > 0 {
+		return protocols.NewInvalidParameterError("quote_id", request.QuoteID, "quote_id cannot be sent with send_max, send_asset_code, send_asset_issuer or path.")
+	}
+
+	if rh.Repository == nil {
+		return protocols.NewInvalidParameterError("quote_id", request.QuoteID, "Quotes require a database.")
+	}
+
+	quote, err := rh.Repository.GetQuote(ctx, request.QuoteID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting Quote")
+		return protocols.InternalServerError
+	}
+
+	if quote == nil {
+		return bridge.QuoteNotFound
+	}
+
+	if !clock.Now().Before(quote.ExpiresAt) {
+		return bridge.QuoteExpired
+	}
+
+	if quote.Network != request.Network {
+		return protocols.NewInvalidParameterError("network", request.Network, "Quote is for a different network.")
+	}
+
+	if request.AssetCode == "" && request.AssetIssuer == "" {
+		request.AssetCode, request.AssetIssuer = quote.AssetCode, quote.AssetIssuer
+	} else if request.AssetCode != quote.AssetCode || request.AssetIssuer != quote.AssetIssuer {
+		return protocols.NewInvalidParameterError("asset_code", request.AssetCode, "Asset is different than the asset of the quote.")
+	}
+
+	if request.Amount == "" {
+		request.Amount = quote.Amount
+	} else if amount, err := amounts.Parse(request.Amount); err == nil && amount != amounts.MustParse(quote.Amount) {
+		return protocols.NewInvalidParameterError("amount", request.Amount, "Amount is different than the amount of the quote.")
+	}
+
+	request.SendMax = quote.SendAmount
+	request.SendAssetCode = quote.SendAssetCode
+	request.SendAssetIssuer = quote.SendAssetIssuer
+	request.Path = quote.Assets()
+
+	log.WithFields(log.Fields{"quote_id": quote.QuoteID, "send_max": quote.SendAmount}).Info("Applied quote")
+	return nil
+}
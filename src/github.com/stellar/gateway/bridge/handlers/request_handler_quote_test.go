@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	mockHorizon := new(mocks.MockHorizon)
+	entityManager := db.NewEntityManager(driver)
+	rh := NewRequestHandler(&config.Config{}, nil, mockHorizon, driver, db.NewRepository(driver), entityManager, nil, nil, nil, nil)
+
+	issuer := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	pathAsset := horizon.PathAsset{AssetType: "credit_alphanum4", AssetCode: "BTC", AssetIssuer: issuer}
+	mockHorizon.On("FindPaths", horizon.PathsRequest{
+		SourceAsset:       horizon.PathAsset{AssetType: "native"},
+		DestinationAsset:  horizon.PathAsset{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: issuer},
+		DestinationAmount: "100.0000000",
+	}).Return([]horizon.PathResponse{
+		{SourceAmount: "450.0000000", DestinationAmount: "100.0000000"},
+		{SourceAmount: "400.0000000", DestinationAmount: "100.0000000", Path: []horizon.PathAsset{pathAsset}},
+	}, nil).Once()
+
+	quote := func(values url.Values) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/quote", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rh.Quote(w, r)
+		return w
+	}
+
+	w := quote(url.Values{"asset_code": {"USD"}, "asset_issuer": {issuer}, "amount": {"100"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response QuoteResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, entities.QuoteTypeStrictReceive, response.Type)
+	assert.Equal(t, "400.0000000", response.SendAmount)
+	assert.Equal(t, "100.0000000", response.Amount)
+	assert.Equal(t, "4.0000000", response.Price)
+	assert.Equal(t, []protocols.Asset{{Code: "BTC", Issuer: issuer}}, response.Path)
+	mockHorizon.AssertExpectations(t)
+
+	w = quote(url.Values{"asset_code": {"USD"}, "asset_issuer": {issuer}, "amount": {"100"}, "send_amount": {"400"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	payment := func(values url.Values) (*bridge.PaymentRequest, *protocols.ErrorResponse) {
+		request := &bridge.PaymentRequest{}
+		r := httptest.NewRequest("POST", "/payment", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		require.NoError(t, request.FromRequest(r))
+		return request, rh.applyQuote(context.Background(), request)
+	}
+
+	request, errorResponse := payment(url.Values{"quote_id": {response.QuoteID}, "destination": {"GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2"}})
+	require.Nil(t, errorResponse)
+	assert.Equal(t, "100.0000000", request.Amount)
+	assert.Equal(t, "USD", request.AssetCode)
+	assert.Equal(t, "400.0000000", request.SendMax)
+	assert.Equal(t, "", request.SendAssetCode)
+	assert.Equal(t, []protocols.Asset{{Code: "BTC", Issuer: issuer}}, request.Path)
+
+	_, errorResponse = payment(url.Values{"quote_id": {response.QuoteID}, "amount": {"101"}})
+	require.NotNil(t, errorResponse)
+	assert.Equal(t, "amount", errorResponse.Data["name"])
+
+	_, errorResponse = payment(url.Values{"quote_id": {response.QuoteID}, "send_max": {"500"}})
+	require.NotNil(t, errorResponse)
+	assert.Equal(t, "quote_id", errorResponse.Data["name"])
+
+	_, errorResponse = payment(url.Values{"quote_id": {"unknown"}})
+	assert.Equal(t, bridge.QuoteNotFound, errorResponse)
+
+	now := time.Now()
+	expired := &entities.Quote{
+		QuoteID:    "expired",
+		Type:       entities.QuoteTypeStrictSend,
+		SendAmount: "10.0000000",
+		Amount:     "2.0000000",
+		ExpiresAt:  now.Add(-time.Second),
+		CreatedAt:  now.Add(-time.Minute),
+	}
+	expired.SetAssets(nil)
+	require.NoError(t, entityManager.Persist(expired))
+	_, errorResponse = payment(url.Values{"quote_id": {"expired"}})
+	assert.Equal(t, bridge.QuoteExpired, errorResponse)
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// refundPaymentIDPrefix is a prefix of IDs of refund payments, the operation ID
+// of the refunded payment follows it
+const refundPaymentIDPrefix = "refund-"
+
+// RefundResponse is a refund with the response of the refund payment
+type RefundResponse struct {
+	*entities.Refund
+	// Payment is a response of /payment sending the refund (ex. a held
+	// payment), empty when the refund is loaded
+	Payment json.RawMessage `json:"payment,omitempty"`
+}
+
+// RefundPayment implements POST /payments/{id}/refund endpoint. It sends the
+// amount of a received payment with operation ID id back to the sender like
+// /payment and links the refund with the received payment in Refund table.
+// A failed refund can be sent again, it's sent with the same payment ID.
+func (rh *RequestHandler) RefundPayment(c web.C, w http.ResponseWriter, r *http.Request) {
+	request := &bridge.RefundRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	operationID := c.URLParams["id"]
+	_, err = strconv.ParseInt(operationID, 10, 64)
+	if err != nil {
+		server.Write(w, protocols.NewInvalidParameterError("id", operationID, "Operation ID must be a number."))
+		return
+	}
+
+	receivedPayment, err := rh.Repository.GetReceivedPaymentByOperationID(r.Context(), operationID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting ReceivedPayment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if receivedPayment == nil {
+		server.Write(w, bridge.RefundPaymentNotFound)
+		return
+	}
+
+	refund, err := rh.Repository.GetRefund(r.Context(), operationID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting Refund")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if refund != nil && refund.Status == entities.RefundStatusSent {
+		server.Write(w, bridge.RefundAlreadySent)
+		return
+	}
+
+	operation, err := rh.Horizon.LoadOperation(operationID)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading refunded operation")
+		server.Write(w, bridge.ErrorFromHorizonError(err))
+		return
+	}
+
+	switch operation.Type {
+	case "payment", "path_payment", "path_payment_strict_receive", "path_payment_strict_send":
+	case "account_merge":
+		operation.AssetType = "native"
+		operation.From = operation.Account
+		err = rh.Horizon.LoadAccountMergeAmount(&operation)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error loading account_merge amount")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+	default:
+		server.Write(w, bridge.RefundNotSupported)
+		return
+	}
+
+	amount, err := amounts.ParseAmount(operation.Amount)
+	if err != nil {
+		log.WithFields(log.Fields{"amount": operation.Amount}).Error("Invalid amount of refunded operation")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if request.Amount != "" {
+		// Checked in Validate
+		requested, _ := amounts.ParseAmount(request.Amount)
+		if requested > amount {
+			server.Write(w, bridge.RefundAmountExceeded)
+			return
+		}
+		amount = requested
+	}
+
+	now := clock.Now()
+	if refund == nil {
+		refund = &entities.Refund{
+			OperationID: operationID,
+			PaymentID:   refundPaymentIDPrefix + operationID,
+			CreatedAt:   now,
+		}
+	}
+
+	refund.TransactionID = operation.TransactionID
+	refund.Destination = operation.From
+	refund.Amount = amount.String()
+	refund.AssetCode, refund.AssetIssuer = "", ""
+	if operation.AssetType != "native" {
+		refund.AssetCode, refund.AssetIssuer = operation.AssetCode, operation.AssetIssuer
+	}
+	// Return memo contains the hash of the refunded transaction
+	refund.MemoType, refund.Memo = "return", operation.TransactionID
+	if request.MemoType != "" {
+		refund.MemoType, refund.Memo = request.MemoType, request.Memo
+	}
+	refund.Reason = request.Reason
+	refund.UpdatedAt = now
+
+	response, errorResponse := rh.sendRefund(r, refund, request.Source)
+	if errorResponse != nil {
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	switch {
+	case response.status == http.StatusOK:
+		var submitResponse horizon.SubmitTransactionResponse
+		err = json.Unmarshal(response.body.Bytes(), &submitResponse)
+		if err == nil && submitResponse.Hash != "" {
+			refund.RefundTransactionID = &submitResponse.Hash
+		}
+		refund.Status = entities.RefundStatusSent
+	case response.status == http.StatusAccepted:
+		refund.Status = entities.RefundStatusHeld
+	default:
+		refund.Status = entities.RefundStatusFailed
+	}
+
+	err = rh.EntityManager.Persist(refund)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "operation_id": operationID, "status": refund.Status}).Error("Error persisting Refund")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"operation_id": operationID,
+		"payment_id":   refund.PaymentID,
+		"amount":       refund.Amount,
+		"status":       refund.Status,
+	}).Info("Received payment refunded")
+
+	if refund.Status == entities.RefundStatusFailed {
+		// Error of /payment is returned as is
+		errorResponse = &protocols.ErrorResponse{}
+		err = json.Unmarshal(response.body.Bytes(), errorResponse)
+		if err != nil || errorResponse.Code == "" {
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+		errorResponse.Status = response.status
+		server.Write(w, errorResponse)
+		return
+	}
+
+	rh.writeRefund(w, RefundResponse{refund, response.body.Bytes()})
+}
+
+// sendRefund sends the refund payment using /payment handler, so refunds are
+// held, approved and checked by risk hooks like other payments
+func (rh *RequestHandler) sendRefund(r *http.Request, refund *entities.Refund, source string) (*responseRecorder, *protocols.ErrorResponse) {
+	values := url.Values{
+		"id":           {refund.PaymentID},
+		"destination":  {refund.Destination},
+		"amount":       {refund.Amount},
+		"asset_code":   {refund.AssetCode},
+		"asset_issuer": {refund.AssetIssuer},
+		"memo_type":    {refund.MemoType},
+		"memo":         {refund.Memo},
+	}
+	if source != "" {
+		values.Set("source", source)
+	}
+
+	// Context contains the API key (and tenant) of the request
+	httpRequest, _ := http.NewRequestWithContext(r.Context(), "POST", "/payment", strings.NewReader(values.Encode()))
+	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	request := &bridge.PaymentRequest{}
+	err := request.FromRequest(httpRequest)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Cannot decode refund payment request")
+		return nil, protocols.InternalServerError
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return nil, err.(*protocols.ErrorResponse)
+	}
+
+	response := &responseRecorder{status: http.StatusOK, header: make(http.Header)}
+	rh.payment(response, request, true)
+	return response, nil
+}
+
+// Refund implements GET /payments/{id}/refund endpoint. It returns the refund
+// of a received payment with operation ID id. Status of a held refund is
+// updated when the refund payment has been released.
+func (rh *RequestHandler) Refund(c web.C, w http.ResponseWriter, r *http.Request) {
+	refund, err := rh.Repository.GetRefund(r.Context(), c.URLParams["id"])
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting Refund")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if refund == nil {
+		server.Write(w, bridge.RefundNotFound)
+		return
+	}
+
+	if refund.Status == entities.RefundStatusHeld {
+		err = rh.updateHeldRefund(r, refund)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error updating held Refund")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+	}
+
+	rh.writeRefund(w, RefundResponse{Refund: refund})
+}
+
+// updateHeldRefund sets status of a held refund from its held payment
+func (rh *RequestHandler) updateHeldRefund(r *http.Request, refund *entities.Refund) error {
+	heldPayment, err := rh.Repository.GetHeldPaymentByPaymentID(r.Context(), refund.PaymentID)
+	if err != nil || heldPayment == nil {
+		return err
+	}
+
+	switch heldPayment.Status {
+	case entities.HeldPaymentStatusReleased:
+		refund.Status = entities.RefundStatusSent
+		sentTransaction, err := rh.Repository.GetSentTransactionByPaymentID(r.Context(), refund.PaymentID)
+		if err != nil {
+			return err
+		}
+		if sentTransaction != nil {
+			refund.RefundTransactionID = &sentTransaction.TransactionID
+		}
+	case entities.HeldPaymentStatusCancelled, entities.HeldPaymentStatusFailed:
+		refund.Status = entities.RefundStatusFailed
+	default:
+		return nil
+	}
+
+	refund.UpdatedAt = clock.Now()
+	return rh.EntityManager.Persist(refund)
+}
+
+func (rh *RequestHandler) writeRefund(w http.ResponseWriter, response RefundResponse) {
+	err := server.WriteJSON(w, response)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding Refund")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
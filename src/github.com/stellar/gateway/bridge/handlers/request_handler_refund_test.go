@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestRefundPayment(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	c := &config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Accounts: config.Accounts{
+			// GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		// Refunds are held, so they are not submitted in the test
+		Settlement: config.Settlement{Delay: "15m"},
+	}
+	mockHorizon := new(mocks.MockHorizon)
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	rh := NewRequestHandler(c, nil, mockHorizon, driver, repository, entityManager, nil, nil, nil, nil)
+
+	txHash := "ab5c8f9e7a3d1c2b4e6f8a0c2e4a6c8e0a2c4e6a8c0e2a4c6e8a0c2e4a6c8e0a"
+	require.NoError(t, entityManager.Persist(&entities.ReceivedPayment{
+		OperationID:   "100",
+		ProcessedAt:   time.Now(),
+		Status:        "Success",
+		TransactionID: txHash,
+	}))
+	mockHorizon.On("LoadOperation", "100").Return(horizon.PaymentResponse{
+		ID:            "100",
+		Type:          "payment",
+		From:          "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS",
+		Amount:        "20",
+		AssetType:     "credit_alphanum4",
+		AssetCode:     "USD",
+		AssetIssuer:   "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632",
+		TransactionID: txHash,
+	}, nil)
+
+	refund := func(id string, values url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/payments/"+id+"/refund", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		rh.RefundPayment(web.C{URLParams: map[string]string{"id": id}}, w, r)
+		return w
+	}
+
+	w := refund("101", url.Values{})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = refund("100", url.Values{"amount": {"20.1"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "refund_amount_exceeded")
+
+	w = refund("100", url.Values{"memo": {"refund"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = refund("100", url.Values{"amount": {"5"}, "reason": {"duplicate"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response RefundResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "refund-100", response.PaymentID)
+	assert.Equal(t, "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS", response.Destination)
+	assert.Equal(t, "5.0000000", response.Amount)
+	assert.Equal(t, "USD", response.AssetCode)
+	assert.Equal(t, "return", response.MemoType)
+	assert.Equal(t, txHash, response.Memo)
+	assert.Equal(t, entities.RefundStatusHeld, response.Status)
+	assert.Contains(t, string(response.Payment), `"status":"held"`)
+
+	heldPayment, err := repository.GetHeldPaymentByPaymentID(context.Background(), "refund-100")
+	require.NoError(t, err)
+	require.NotNil(t, heldPayment)
+
+	get := func() RefundResponse {
+		w := httptest.NewRecorder()
+		rh.Refund(web.C{URLParams: map[string]string{"id": "100"}}, w, httptest.NewRequest("GET", "/payments/100/refund", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response RefundResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	assert.Equal(t, entities.RefundStatusHeld, get().Status)
+
+	// Status is updated when the held refund is released
+	heldPayment.Status = entities.HeldPaymentStatusReleased
+	require.NoError(t, entityManager.Persist(heldPayment))
+	require.NoError(t, entityManager.Persist(&entities.SentTransaction{
+		PaymentID:     &heldPayment.PaymentID,
+		TransactionID: "refund-tx",
+		Status:        entities.SentTransactionStatusSuccess,
+		Source:        "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ",
+		SubmittedAt:   time.Now(),
+		EnvelopeXdr:   "AAAA",
+	}))
+	response = get()
+	assert.Equal(t, entities.RefundStatusSent, response.Status)
+	require.NotNil(t, response.RefundTransactionID)
+	assert.Equal(t, "refund-tx", *response.RefundTransactionID)
+
+	w = refund("100", url.Values{})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	rh.Refund(web.C{URLParams: map[string]string{"id": "101"}}, w, httptest.NewRequest("GET", "/payments/101/refund", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/reconciliation"
+	"github.com/stellar/gateway/server"
+)
+
+// AdminRegionConflicts implements /admin/region-conflicts endpoint
+func (rh *RequestHandler) AdminRegionConflicts(w http.ResponseWriter, r *http.Request) {
+	transactions, err := rh.Repository.GetSentTransactionConflicts(r.Context())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting sent transaction conflicts")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	err = server.WriteJSON(w, reconciliation.Group(transactions))
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding region conflicts")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestHandlerRegion(t *testing.T) {
+	c := &config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Accounts: config.Accounts{
+			// GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		Region: config.Region{
+			Name:     "eu",
+			Accounts: []string{"GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"},
+		},
+	}
+	mockRepository := new(mocks.MockRepository)
+
+	requestHandler := NewRequestHandler(c, nil, nil, nil, mockRepository, nil, nil, nil, nil, nil)
+
+	paymentServer := httptest.NewServer(http.HandlerFunc(requestHandler.Payment))
+	defer paymentServer.Close()
+
+	conflictsServer := httptest.NewServer(http.HandlerFunc(requestHandler.AdminRegionConflicts))
+	defer conflictsServer.Close()
+
+	Convey("Given region with accounts", t, func() {
+		Convey("When source account is owned by another region it should return error", func() {
+			mockRepository.On("GetSentTransactionByPaymentID", "payment-1").Return(nil, nil).Once()
+
+			statusCode, response := net.GetResponse(paymentServer, url.Values{
+				"id":          {"payment-1"},
+				"destination": {"GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"},
+				"amount":      {"20.0"},
+			})
+			assert.Equal(t, http.StatusMisdirectedRequest, statusCode)
+			assert.Equal(t, "wrong_region", test.StringToJSONMap(string(response))["code"])
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("It should list conflicts", func() {
+			paymentID := "payment-1"
+			mockRepository.On("GetSentTransactionConflicts").Return([]*entities.SentTransaction{
+				{PaymentID: &paymentID, Region: "eu", Status: entities.SentTransactionStatusSuccess},
+				{PaymentID: &paymentID, Region: "us", Status: entities.SentTransactionStatusFailure},
+			}, nil).Once()
+
+			statusCode, response := net.GetResponse(conflictsServer, url.Values{})
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Contains(t, string(response), `"resolution":"resolved"`)
+			assert.Contains(t, string(response), `"regions":["eu","us"]`)
+			mockRepository.AssertExpectations(t)
+		})
+	})
+}
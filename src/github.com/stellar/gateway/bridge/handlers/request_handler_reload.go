@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+)
+
+// AdminReload implements POST /admin/reload endpoint. It reloads the config
+// file like SIGHUP does and returns names of applied and ignored params.
+func (rh *RequestHandler) AdminReload(w http.ResponseWriter, r *http.Request) {
+	if rh.Reload == nil {
+		server.Write(w, bridge.ConfigReloadNotAvailable)
+		return
+	}
+
+	reloaded, ignored, err := rh.Reload()
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error reloading config")
+		server.Write(w, bridge.NewConfigReloadFailed(err.Error()))
+		return
+	}
+
+	server.Write(w, &bridge.ReloadResponse{Status: "ok", Reloaded: reloaded, Ignored: ignored})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerAdminReload(t *testing.T) {
+	requestHandler := RequestHandler{}
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.AdminReload))
+	defer testServer.Close()
+
+	post := func() (*http.Response, string) {
+		resp, err := http.Post(testServer.URL, "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	resp, body := post()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, body, "config_reload_not_available")
+
+	requestHandler.Reload = func() ([]string, []string, error) {
+		return nil, nil, errors.New("Invalid log_level param")
+	}
+	resp, body = post()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, body, "config_reload_failed")
+	assert.Contains(t, body, "Invalid log_level param")
+
+	requestHandler.Reload = func() ([]string, []string, error) {
+		return []string{"assets"}, []string{"port"}, nil
+	}
+	resp, body = post()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"status": "ok", "reloaded": ["assets"], "ignored": ["port"]}`, body)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/reports"
+	"github.com/stellar/gateway/server"
+)
+
+// reportMaxDays is the max number of days in a report
+const reportMaxDays = 366
+
+// AdminDailyReport implements GET /admin/reports/daily endpoint. It returns
+// the number and the sum of payments of every day, tenant, direction and
+// asset loaded from daily aggregates.
+func (rh *RequestHandler) AdminDailyReport(w http.ResponseWriter, r *http.Request) {
+	rh.writeReport(w, r, "daily", reports.Daily)
+}
+
+// AdminTotalsReport implements GET /admin/reports/totals endpoint. It returns
+// the number and the sum of payments of every tenant, direction and asset in
+// the range of days.
+func (rh *RequestHandler) AdminTotalsReport(w http.ResponseWriter, r *http.Request) {
+	rh.writeReport(w, r, "totals", reports.Totals)
+}
+
+func (rh *RequestHandler) writeReport(
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	build func(context.Context, db.RepositoryInterface, db.DailyAggregatesFilter) (*reports.Report, error),
+) {
+	filter, csv, errorResponse := parseReportQuery(r)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	report, err := build(r.Context(), rh.Repository, filter)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading report")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if csv {
+		records := [][]string{{"day", "tenant", "direction", "asset_code", "asset_issuer", "count", "amount"}}
+		for _, row := range report.Rows {
+			records = append(records, []string{row.Day, row.Tenant, row.Direction, row.AssetCode, row.AssetIssuer, strconv.FormatInt(row.Count, 10), row.Amount})
+		}
+		writeCSV(w, "report-"+name+"-"+filter.From+"-"+filter.To+".csv", records)
+		return
+	}
+
+	err = server.WriteJSON(w, report)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding report")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+// parseReportQuery parses query params of report endpoints: required `from`
+// and `to` days (inclusive), optional `tenant`, `direction`, `asset_code` and
+// `format`
+func parseReportQuery(r *http.Request) (filter db.DailyAggregatesFilter, csv bool, errorResponse *protocols.ErrorResponse) {
+	values := r.URL.Query()
+	filter = db.DailyAggregatesFilter{
+		From:      values.Get("from"),
+		To:        values.Get("to"),
+		Tenant:    values.Get("tenant"),
+		Direction: values.Get("direction"),
+		AssetCode: values.Get("asset_code"),
+	}
+
+	var days [2]time.Time
+	for i, param := range []struct{ name, value string }{{"from", filter.From}, {"to", filter.To}} {
+		if param.value == "" {
+			errorResponse = protocols.NewMissingParameter(param.name)
+			return
+		}
+		var err error
+		days[i], err = time.Parse(reports.DayFormat, param.value)
+		if err != nil {
+			errorResponse = protocols.NewInvalidParameterError(param.name, param.value, "Day must be in YYYY-MM-DD format, ex. 2018-03-01.")
+			return
+		}
+	}
+
+	if days[1].Before(days[0]) || days[1].Sub(days[0]) >= reportMaxDays*24*time.Hour {
+		errorResponse = protocols.NewInvalidParameterError("to", filter.To, "Range must contain between 1 and "+strconv.Itoa(reportMaxDays)+" days.")
+		return
+	}
+
+	switch filter.Direction {
+	case "", entities.DailyAggregateReceived, entities.DailyAggregateSent:
+	default:
+		errorResponse = protocols.NewInvalidParameterError("direction", filter.Direction, "Direction must be `received` or `sent`.")
+		return
+	}
+
+	switch format := values.Get("format"); format {
+	case "", "json":
+	case "csv":
+		csv = true
+	default:
+		errorResponse = protocols.NewInvalidParameterError("format", format, "Format must be `json` or `csv`.")
+	}
+	return
+}
@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/sep24"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// SEP24TransactionResponse is a response of /sep24/transaction endpoint
+type SEP24TransactionResponse struct {
+	Transaction sep24.Transaction `json:"transaction"`
+}
+
+// SEP24TransactionsResponse is a response of GET /sep24/transactions endpoint
+type SEP24TransactionsResponse struct {
+	Transactions []sep24.Transaction `json:"transactions"`
+}
+
+// CreateSEP24Transaction implements POST /sep24/transactions endpoint. The
+// anchor creates a transaction when the user starts its interactive flow.
+// Withdrawals are sent to accounts.receiving_account_id with a generated id
+// memo unless a memo is given.
+func (rh *RequestHandler) CreateSEP24Transaction(w http.ResponseWriter, r *http.Request) {
+	request := &bridge.SEP24TransactionRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	transaction := &entities.SEP24Transaction{
+		TransactionID: request.ID,
+		Kind:          request.Kind,
+		Status:        request.Status,
+		Account:       request.Account,
+		AssetCode:     request.AssetCode,
+		AssetIssuer:   request.AssetIssuer,
+		MemoType:      request.MemoType,
+		Memo:          request.Memo,
+		MoreInfoURL:   request.MoreInfoURL,
+		Message:       request.Message,
+		StartedAt:     clock.Now(),
+	}
+	if transaction.Status == "" {
+		transaction.Status = entities.SEP24StatusIncomplete
+	}
+	if request.AmountIn != "" {
+		// Checked in Validate
+		transaction.AmountIn, _ = amounts.ParseAmount(request.AmountIn)
+	}
+
+	if transaction.TransactionID == "" {
+		transaction.TransactionID, err = newSEP24TransactionID()
+	} else {
+		var existing *entities.SEP24Transaction
+		existing, err = rh.Repository.GetSEP24Transaction(r.Context(), transaction.TransactionID)
+		if err == nil && existing != nil {
+			server.Write(w, bridge.SEP24TransactionExists)
+			return
+		}
+	}
+	if err == nil && transaction.Kind == entities.SEP24KindWithdrawal {
+		transaction.AnchorAccount = rh.Config.Accounts.ReceivingAccountID
+		if transaction.MemoType == "" {
+			transaction.MemoType = "id"
+			transaction.Memo, err = newWithdrawalMemo()
+		}
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error creating SEP-24 transaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	// Unique index of IDs fails when created by a concurrent request
+	err = rh.SEP24.Save(r.Context(), transaction, "", transaction.StartedAt)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error saving SEP-24 transaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	rh.writeSEP24(w, SEP24TransactionResponse{sep24.NewTransaction(transaction)})
+}
+
+// UpdateSEP24Transaction implements POST /sep24/transactions/{id} endpoint.
+// The anchor updates the status and details of a transaction while it's
+// processed. Status changes are sent to webhooks.
+func (rh *RequestHandler) UpdateSEP24Transaction(c web.C, w http.ResponseWriter, r *http.Request) {
+	request := &bridge.SEP24UpdateRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		server.Write(w, protocols.InvalidParameterError)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		server.Write(w, errorResponse)
+		return
+	}
+
+	transaction, err := rh.Repository.GetSEP24Transaction(r.Context(), c.URLParams["id"])
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting SEP-24 transaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if transaction == nil {
+		server.Write(w, bridge.SEP24TransactionNotFound)
+		return
+	}
+
+	previousStatus := transaction.Status
+	if request.Status != "" {
+		transaction.Status = request.Status
+	}
+	// Amounts are checked in Validate
+	if request.AmountIn != "" {
+		transaction.AmountIn, _ = amounts.ParseAmount(request.AmountIn)
+	}
+	if request.AmountOut != "" {
+		transaction.AmountOut, _ = amounts.ParseAmount(request.AmountOut)
+	}
+	if request.AmountFee != "" {
+		transaction.AmountFee, _ = amounts.ParseAmount(request.AmountFee)
+	}
+	if request.StellarTransactionID != "" {
+		transaction.StellarTransactionID = request.StellarTransactionID
+	}
+	if request.ExternalTransactionID != "" {
+		transaction.ExternalTransactionID = request.ExternalTransactionID
+	}
+	if request.MoreInfoURL != "" {
+		transaction.MoreInfoURL = request.MoreInfoURL
+	}
+	if request.Message != "" {
+		transaction.Message = request.Message
+	}
+
+	err = rh.SEP24.Save(r.Context(), transaction, previousStatus, clock.Now())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error saving SEP-24 transaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	rh.writeSEP24(w, SEP24TransactionResponse{sep24.NewTransaction(transaction)})
+}
+
+// SEP24Transaction implements GET /sep24/transaction endpoint. The
+// transaction is found by one of id, stellar_transaction_id or
+// external_transaction_id params.
+func (rh *RequestHandler) SEP24Transaction(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var transaction *entities.SEP24Transaction
+	var err error
+	switch {
+	case query.Get("id") != "":
+		transaction, err = rh.Repository.GetSEP24Transaction(r.Context(), query.Get("id"))
+	case query.Get("stellar_transaction_id") != "":
+		transaction, err = rh.Repository.GetSEP24TransactionByStellarID(r.Context(), query.Get("stellar_transaction_id"))
+	case query.Get("external_transaction_id") != "":
+		transaction, err = rh.Repository.GetSEP24TransactionByExternalID(r.Context(), query.Get("external_transaction_id"))
+	default:
+		server.Write(w, protocols.NewMissingParameter("id"))
+		return
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting SEP-24 transaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if transaction == nil {
+		server.Write(w, bridge.SEP24TransactionNotFound)
+		return
+	}
+
+	rh.writeSEP24(w, SEP24TransactionResponse{sep24.NewTransaction(transaction)})
+}
+
+// SEP24Transactions implements GET /sep24/transactions endpoint. It returns
+// transactions filtered by account, asset_code, kind and no_older_than
+// params, the most recently started first. Older transactions are returned
+// with paging_id param set to the ID of the last returned transaction.
+func (rh *RequestHandler) SEP24Transactions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := db.SEP24Filter{
+		Account:   query.Get("account"),
+		AssetCode: query.Get("asset_code"),
+		Kind:      query.Get("kind"),
+	}
+
+	if value := query.Get("no_older_than"); value != "" {
+		noOlderThan, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			server.Write(w, protocols.NewInvalidParameterError("no_older_than", value, "Time must be in RFC 3339 format."))
+			return
+		}
+		filter.NoOlderThan = &noOlderThan
+	}
+
+	limit := adminListDefaultLimit
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > adminListMaxLimit {
+			server.Write(w, protocols.NewInvalidParameterError("limit", value, "Limit must be an integer between 1 and "+strconv.Itoa(adminListMaxLimit)+"."))
+			return
+		}
+	}
+
+	if value := query.Get("paging_id"); value != "" {
+		last, err := rh.Repository.GetSEP24Transaction(r.Context(), value)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error getting SEP-24 transaction")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+		if last == nil {
+			server.Write(w, protocols.NewInvalidParameterError("paging_id", value, "Transaction not found."))
+			return
+		}
+		filter.BeforeID = *last.ID
+	}
+
+	transactions, err := rh.Repository.GetSEP24Transactions(r.Context(), filter, limit)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting SEP-24 transactions")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	response := SEP24TransactionsResponse{Transactions: []sep24.Transaction{}}
+	for _, transaction := range transactions {
+		response.Transactions = append(response.Transactions, sep24.NewTransaction(transaction))
+	}
+	rh.writeSEP24(w, response)
+}
+
+func (rh *RequestHandler) writeSEP24(w http.ResponseWriter, value interface{}) {
+	err := server.WriteJSON(w, value)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding SEP-24 transaction")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+// newSEP24TransactionID returns a random UUID
+func newSEP24TransactionID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+	// Version 4, variant 1
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
+
+// newWithdrawalMemo returns a random id memo identifying payment of a
+// withdrawal
+func newWithdrawalMemo() (string, error) {
+	memo := make([]byte, 8)
+	_, err := rand.Read(memo)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(binary.BigEndian.Uint64(memo), 10), nil
+}
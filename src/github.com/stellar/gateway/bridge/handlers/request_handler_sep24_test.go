@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/sep24"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestSEP24Transactions(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	c := &config.Config{}
+	c.Accounts.ReceivingAccountID = "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"
+	rh := NewRequestHandler(c, nil, nil, driver, repository, entityManager, nil, nil, nil, nil)
+	rh.SEP24 = sep24.NewTracker(repository, entityManager, nil)
+
+	account := "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"
+	post := func(handler func(w http.ResponseWriter, r *http.Request), values url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/sep24/transactions", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+	create := func(values url.Values) *httptest.ResponseRecorder {
+		return post(rh.CreateSEP24Transaction, values)
+	}
+	update := func(id string, values url.Values) *httptest.ResponseRecorder {
+		return post(func(w http.ResponseWriter, r *http.Request) {
+			rh.UpdateSEP24Transaction(web.C{URLParams: map[string]string{"id": id}}, w, r)
+		}, values)
+	}
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		rh.SEP24Transaction(w, httptest.NewRequest("GET", "/sep24/transaction?"+query, nil))
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) sep24.Transaction {
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response SEP24TransactionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Transaction
+	}
+
+	values := url.Values{
+		"id":         {"withdrawal-1"},
+		"kind":       {"withdrawal"},
+		"account":    {account},
+		"asset_code": {"XLM"},
+		"status":     {"pending_user_transfer_start"},
+		"amount_in":  {"10"},
+	}
+	withdrawal := decode(create(values))
+	assert.Equal(t, entities.SEP24StatusPendingUserTransferStart, withdrawal.Status)
+	assert.Equal(t, "stellar:native", withdrawal.AmountInAsset)
+	assert.Equal(t, c.Accounts.ReceivingAccountID, withdrawal.WithdrawAnchorAccount)
+	assert.Equal(t, "id", withdrawal.WithdrawMemoType)
+	assert.NotEmpty(t, withdrawal.WithdrawMemo)
+
+	w := create(values)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	values.Set("id", "")
+	values.Set("kind", "transfer")
+	w = create(values)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	values = url.Values{
+		"kind":       {"deposit"},
+		"account":    {account},
+		"asset_code": {"XLM"},
+	}
+	deposit := decode(create(values))
+	assert.Len(t, deposit.ID, 36)
+	assert.Equal(t, entities.SEP24StatusIncomplete, deposit.Status)
+	assert.Equal(t, account, deposit.To)
+	assert.Empty(t, deposit.DepositMemo)
+
+	w = update(deposit.ID, url.Values{"status": {"unknown"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = update("unknown", url.Values{"status": {"completed"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	hash := strings.Repeat("ab", 32)
+	deposit = decode(update(deposit.ID, url.Values{
+		"status":                  {"completed"},
+		"amount_in":               {"10"},
+		"amount_out":              {"9.5"},
+		"amount_fee":              {"0.5"},
+		"stellar_transaction_id":  {hash},
+		"external_transaction_id": {"bank-1"},
+	}))
+	assert.Equal(t, entities.SEP24StatusCompleted, deposit.Status)
+	assert.Equal(t, "9.5000000", deposit.AmountOut)
+	assert.NotNil(t, deposit.CompletedAt)
+
+	assert.Equal(t, deposit.ID, decode(get("stellar_transaction_id="+hash)).ID)
+	assert.Equal(t, deposit.ID, decode(get("external_transaction_id=bank-1")).ID)
+	assert.Equal(t, "withdrawal-1", decode(get("id=withdrawal-1")).ID)
+	assert.Equal(t, http.StatusNotFound, get("id=unknown").Code)
+	assert.Equal(t, http.StatusBadRequest, get("").Code)
+
+	list := func(query string) []sep24.Transaction {
+		w := httptest.NewRecorder()
+		rh.SEP24Transactions(w, httptest.NewRequest("GET", "/sep24/transactions?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response SEP24TransactionsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Transactions
+	}
+
+	transactions := list("account=" + account)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, deposit.ID, transactions[0].ID)
+	assert.Equal(t, "withdrawal-1", transactions[1].ID)
+
+	transactions = list("account=" + account + "&kind=withdrawal")
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "withdrawal-1", transactions[0].ID)
+
+	transactions = list("limit=1&paging_id=" + deposit.ID)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "withdrawal-1", transactions[0].ID)
+
+	assert.Empty(t, list("no_older_than=2100-01-01T00:00:00Z"))
+
+	w = httptest.NewRecorder()
+	rh.SEP24Transactions(w, httptest.NewRequest("GET", "/sep24/transactions?limit=0", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/crypto"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+	"github.com/zenazn/goji/web"
+)
+
+// heldTenantField is a field of held payment request storing the tenant
+const heldTenantField = "tenant"
+
+// heldSeeds caches secret seeds of held payments sent from accounts other
+// than accounts.base_seed. Seeds are persisted in the database encrypted to
+// settlement.handoff_key, so such payments are released after the server is
+// restarted during settlement window.
+type heldSeeds struct {
+	sync.Mutex
+	seeds map[string]string // payment ID => seed
+}
+
+func newHeldSeeds() *heldSeeds {
+	return &heldSeeds{seeds: make(map[string]string)}
+}
+
+func (s *heldSeeds) set(paymentID, seed string) bool {
+	if s == nil {
+		return false
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.seeds[paymentID] = seed
+	return true
+}
+
+func (s *heldSeeds) get(paymentID string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.Lock()
+	defer s.Unlock()
+	seed, ok := s.seeds[paymentID]
+	return seed, ok
+}
+
+func (s *heldSeeds) pop(paymentID string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.Lock()
+	defer s.Unlock()
+	seed, ok := s.seeds[paymentID]
+	delete(s.seeds, paymentID)
+	return seed, ok
+}
+
+// heldPaymentTenant returns the tenant that sent a held payment
+func heldPaymentTenant(heldPayment *entities.HeldPayment) string {
+	values, _ := url.ParseQuery(heldPayment.Request)
+	return values.Get(heldTenantField)
+}
+
+func newHeldPaymentResponse(heldPayment *entities.HeldPayment) *bridge.HeldPaymentResponse {
+	return &bridge.HeldPaymentResponse{
+		ID:       heldPayment.PaymentID,
+		Status:   heldPayment.Status,
+		SettleAt: heldPayment.SettleAt,
+	}
+}
+
+// holdPayment persists a payment with status held until delay passes or
+// pending until it's approved
+func (rh *RequestHandler) holdPayment(w http.ResponseWriter, request *bridge.PaymentRequest, sourceAccount, status string, delay time.Duration) {
+	if request.ID == "" {
+		// Payment ID is required to cancel a payment
+		id := make([]byte, 16)
+		_, err := rand.Read(id)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error generating payment ID")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+		request.ID = hex.EncodeToString(id)
+	}
+
+	values := request.ToValues()
+	values.Del("source")
+	// Tenant is needed to evaluate feature flags and settlement delay when
+	// the payment is released or approved
+	if tenant := features.Tenant(request.HTTPRequest); tenant != "" {
+		values.Set(heldTenantField, tenant)
+	}
+
+	now := clock.Now()
+	heldPayment := &entities.HeldPayment{
+		PaymentID:     request.ID,
+		SourceAccount: sourceAccount,
+		Request:       values.Encode(),
+		Status:        status,
+		CreatedAt:     now,
+		SettleAt:      now.Add(delay),
+	}
+	if key := auth.FromContext(request.HTTPRequest.Context()); key != nil {
+		heldPayment.CreatedBy = &key.ID
+	}
+
+	if request.Source != rh.Config.Accounts.BaseSeed {
+		if rh.Config.Settlement.HandoffKey == "" {
+			log.WithFields(log.Fields{"id": request.ID}).Error("settlement.handoff_key not set, cannot persist source seed of held payment")
+			server.Write(w, bridge.PaymentCannotHold)
+			return
+		}
+
+		// Value is checked in Validate
+		publicKey, _ := crypto.EncryptionPublicKey(rh.Config.Settlement.HandoffKey)
+		encryptedSeed, err := crypto.Encrypt(publicKey, []byte(request.Source))
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error encrypting seed of held payment")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+		heldPayment.EncryptedSeed = &encryptedSeed
+		rh.heldSeeds.set(request.ID, request.Source)
+	}
+
+	err := rh.EntityManager.Persist(heldPayment)
+	if err != nil {
+		rh.heldSeeds.pop(request.ID)
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting held payment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if status == entities.HeldPaymentStatusPending {
+		log.WithFields(log.Fields{"id": request.ID}).Info("Payment pending approval")
+	} else {
+		log.WithFields(log.Fields{"id": request.ID, "settle_at": heldPayment.SettleAt}).Info("Payment held")
+	}
+	server.Write(w, newHeldPaymentResponse(heldPayment))
+}
+
+// CancelPayment implements DELETE /payments/{id} endpoint
+func (rh *RequestHandler) CancelPayment(c web.C, w http.ResponseWriter, r *http.Request) {
+	heldPayment, err := rh.Repository.GetHeldPaymentByPaymentID(r.Context(), c.URLParams["id"])
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting held payment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if heldPayment == nil {
+		server.Write(w, bridge.PaymentNotFound)
+		return
+	}
+
+	if heldPayment.Status == entities.HeldPaymentStatusCancelled {
+		server.Write(w, newHeldPaymentResponse(heldPayment))
+		return
+	}
+
+	cancelled, err := rh.Repository.UpdateHeldPaymentStatus(r.Context(), heldPayment, entities.HeldPaymentStatusCancelled, clock.Now())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error cancelling held payment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if !cancelled {
+		server.Write(w, bridge.PaymentCannotCancel)
+		return
+	}
+
+	rh.heldSeeds.pop(heldPayment.PaymentID)
+	log.WithFields(log.Fields{"id": heldPayment.PaymentID}).Info("Held payment cancelled")
+	server.Write(w, newHeldPaymentResponse(heldPayment))
+}
+
+// ReleaseHeldPayments submits held payments with settlement window ended, the
+// longest overdue first, until ctx is done. Payments are releasing while they
+// are submitted and released after a successful submission. Payments failed
+// with a server error are held again and retried on the next call, up to
+// settlement.max_attempts times. Payments are released by the leader only
+// when leader election is enabled.
+func (rh *RequestHandler) ReleaseHeldPayments(ctx context.Context) {
+	if !rh.Leader.IsLeader() {
+		return
+	}
+
+	now := clock.Now()
+	heldPayments, err := rh.Repository.GetHeldPaymentsToRelease(ctx, now)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting held payments")
+		return
+	}
+
+	for i, heldPayment := range heldPayments {
+		if ctx.Err() != nil {
+			log.WithFields(log.Fields{"remaining": len(heldPayments) - i}).Warn("Releasing held payments interrupted")
+			return
+		}
+
+		// Payments still releasing were interrupted by a restart, the payment
+		// ID makes submitting them again safe
+		if heldPayment.Status != entities.HeldPaymentStatusReleasing {
+			releasing, err := rh.Repository.UpdateHeldPaymentStatus(ctx, heldPayment, entities.HeldPaymentStatusReleasing, now)
+			if err != nil {
+				log.WithFields(log.Fields{"err": err, "id": heldPayment.PaymentID}).Error("Error releasing held payment")
+				continue
+			}
+
+			if !releasing {
+				// Cancelled in the meantime
+				continue
+			}
+		}
+
+		rh.releaseHeldPayment(ctx, heldPayment)
+	}
+}
+
+func (rh *RequestHandler) releaseHeldPayment(ctx context.Context, heldPayment *entities.HeldPayment) {
+	logger := log.WithFields(log.Fields{"id": heldPayment.PaymentID})
+
+	source, err := rh.heldPaymentSource(heldPayment)
+	if err != nil {
+		logger.WithFields(log.Fields{"err": err}).Error("Source secret seed of held payment not available")
+		rh.finishHeldPayment(heldPayment, entities.HeldPaymentStatusFailed, "Source secret seed not available.")
+		return
+	}
+
+	values, err := url.ParseQuery(heldPayment.Request)
+	if err != nil {
+		logger.WithFields(log.Fields{"err": err}).Error("Cannot parse held payment request")
+		rh.finishHeldPayment(heldPayment, entities.HeldPaymentStatusFailed, "Cannot parse held payment request")
+		return
+	}
+	values.Set("source", source)
+
+	ctx = features.WithTenant(ctx, values.Get(heldTenantField))
+	httpRequest, _ := http.NewRequestWithContext(ctx, "POST", "/payment", strings.NewReader(values.Encode()))
+	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	request := &bridge.PaymentRequest{}
+	err = request.FromRequest(httpRequest)
+	if err != nil {
+		logger.WithFields(log.Fields{"err": err}).Error("Cannot decode held payment request")
+		rh.finishHeldPayment(heldPayment, entities.HeldPaymentStatusFailed, "Cannot decode held payment request")
+		return
+	}
+
+	// Result of the submission is saved in the held payment
+	request.Synchronous = true
+	response := &responseRecorder{status: http.StatusOK, header: make(http.Header)}
+	rh.payment(response, request, false)
+
+	switch {
+	case response.status == http.StatusOK:
+		logger.Info("Held payment released")
+		rh.finishHeldPayment(heldPayment, entities.HeldPaymentStatusReleased, response.body.String())
+	case response.status >= http.StatusInternalServerError:
+		heldPayment.Attempts++
+		if heldPayment.Attempts >= rh.Config.Settlement.MaxAttemptsOrDefault() {
+			logger.WithFields(log.Fields{"status": response.status, "attempts": heldPayment.Attempts}).Error("Error releasing held payment, attempts exhausted")
+			rh.finishHeldPayment(heldPayment, entities.HeldPaymentStatusFailed, response.body.String())
+			return
+		}
+		logger.WithFields(log.Fields{"status": response.status, "attempts": heldPayment.Attempts}).Error("Error releasing held payment, will retry")
+		rh.finishHeldPayment(heldPayment, entities.HeldPaymentStatusHeld, response.body.String())
+	default:
+		logger.WithFields(log.Fields{"status": response.status}).Error("Held payment failed")
+		rh.finishHeldPayment(heldPayment, entities.HeldPaymentStatusFailed, response.body.String())
+	}
+}
+
+// heldPaymentSource returns the source secret seed of a held payment, cached
+// or decrypted from the database
+func (rh *RequestHandler) heldPaymentSource(heldPayment *entities.HeldPayment) (string, error) {
+	if rh.Config.Accounts.BaseSeed != "" && keypair.MustParse(rh.Config.Accounts.BaseSeed).Address() == heldPayment.SourceAccount {
+		return rh.Config.Accounts.BaseSeed, nil
+	}
+
+	seed, ok := rh.heldSeeds.get(heldPayment.PaymentID)
+	if ok {
+		return seed, nil
+	}
+
+	if heldPayment.EncryptedSeed == nil {
+		return "", errors.New("Seed has not been persisted")
+	}
+	if rh.Config.Settlement.HandoffKey == "" {
+		return "", errors.New("settlement.handoff_key not set")
+	}
+
+	decrypted, err := crypto.Decrypt(rh.Config.Settlement.HandoffKey, *heldPayment.EncryptedSeed)
+	if err != nil {
+		return "", errors.Wrap(err, "Cannot decrypt seed")
+	}
+
+	kp, err := keypair.Parse(string(decrypted))
+	if err != nil || kp.Address() != heldPayment.SourceAccount {
+		return "", errors.New("Seed does not match the source account")
+	}
+	return string(decrypted), nil
+}
+
+// finishHeldPayment persists the status of a held payment. Cached and
+// encrypted seeds are removed unless the payment is held again.
+func (rh *RequestHandler) finishHeldPayment(heldPayment *entities.HeldPayment, status, result string) {
+	heldPayment.Status = status
+	heldPayment.Result = &result
+	if status != entities.HeldPaymentStatusHeld {
+		heldPayment.EncryptedSeed = nil
+		rh.heldSeeds.pop(heldPayment.PaymentID)
+	}
+	err := rh.EntityManager.Persist(heldPayment)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "id": heldPayment.PaymentID}).Error("Error persisting held payment")
+	}
+}
+
+// responseRecorder records the response of a released payment
+type responseRecorder struct {
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/crypto"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// HandoffVersion is the version of HandoffManifest format
+const HandoffVersion = 1
+
+// handoffTimeout limits DB queries of the handoff
+const handoffTimeout = 10 * time.Second
+
+// HandoffIngestedSuffix is added to the name of a handoff file after it has
+// been ingested, so it's not ingested again
+const HandoffIngestedSuffix = ".ingested"
+
+// HandoffManifest lists payments still held when the server has been stopped.
+// It's written to settlement.handoff_file and ingested by the next server
+// started with the same file, which releases the payments when their
+// settlement window ends.
+type HandoffManifest struct {
+	Version   int       `json:"version"`
+	Region    string    `json:"region,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// DeadlineExceeded is true when settlement.drain_timeout passed before
+	// all payments with settlement window ended were released
+	DeadlineExceeded bool             `json:"deadline_exceeded"`
+	Payments         []HandoffPayment `json:"payments"`
+}
+
+// HandoffPayment is a payment still held after draining
+type HandoffPayment struct {
+	PaymentID     string    `json:"payment_id"`
+	SourceAccount string    `json:"source_account"`
+	SettleAt      time.Time `json:"settle_at"`
+	// Due is true when settlement window has ended, the payment has not been
+	// released before the drain deadline or failed with a server error
+	Due bool `json:"due"`
+	// EncryptedSeed is the source secret seed encrypted to
+	// settlement.handoff_key. Empty for payments sent from accounts.base_seed,
+	// when the seed is not available or handoff_key is not set.
+	EncryptedSeed string `json:"encrypted_seed,omitempty"`
+}
+
+// DrainHeldPayments releases held payments with settlement window ended,
+// the longest overdue first, until settlement.drain_timeout passes. Then
+// payments still held are written to settlement.handoff_file when it's set.
+// Called when the server is stopped, after it stopped accepting requests.
+func (rh *RequestHandler) DrainHeldPayments() {
+	ctx, cancel := context.WithTimeout(context.Background(), rh.Config.Settlement.DrainTimeoutDuration())
+	rh.ReleaseHeldPayments(ctx)
+	deadlineExceeded := ctx.Err() != nil
+	cancel()
+
+	ctx, cancel = context.WithTimeout(context.Background(), handoffTimeout)
+	defer cancel()
+	manifest, err := rh.handoffManifest(ctx, deadlineExceeded)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error listing payments still held")
+		return
+	}
+
+	logger := log.WithFields(log.Fields{"held": len(manifest.Payments), "deadline_exceeded": deadlineExceeded})
+	if rh.Config.Settlement.HandoffFile == "" {
+		logger.Info("Held payments drained")
+		return
+	}
+
+	err = writeHandoff(rh.Config.Settlement.HandoffFile, manifest)
+	if err != nil {
+		logger.WithFields(log.Fields{"err": err}).Error("Error writing handoff file")
+		return
+	}
+	logger.WithFields(log.Fields{"file": rh.Config.Settlement.HandoffFile}).Info("Held payments drained, handoff file written")
+}
+
+func (rh *RequestHandler) handoffManifest(ctx context.Context, deadlineExceeded bool) (*HandoffManifest, error) {
+	heldPayments, err := rh.Repository.GetHeldPayments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var publicKey string
+	if rh.Config.Settlement.HandoffKey != "" {
+		// Value is checked in Validate
+		publicKey, _ = crypto.EncryptionPublicKey(rh.Config.Settlement.HandoffKey)
+	}
+
+	now := clock.Now()
+	manifest := &HandoffManifest{
+		Version:          HandoffVersion,
+		Region:           rh.Config.Region.Name,
+		CreatedAt:        now,
+		DeadlineExceeded: deadlineExceeded,
+		Payments:         []HandoffPayment{},
+	}
+	for _, heldPayment := range heldPayments {
+		payment := HandoffPayment{
+			PaymentID:     heldPayment.PaymentID,
+			SourceAccount: heldPayment.SourceAccount,
+			SettleAt:      heldPayment.SettleAt,
+			Due:           !heldPayment.SettleAt.After(now),
+		}
+
+		seed, ok := rh.heldSeeds.get(heldPayment.PaymentID)
+		switch {
+		case ok && publicKey != "":
+			payment.EncryptedSeed, err = crypto.Encrypt(publicKey, []byte(seed))
+			if err != nil {
+				return nil, errors.Wrap(err, "Error encrypting seed of "+heldPayment.PaymentID)
+			}
+		case ok:
+			log.WithFields(log.Fields{"id": heldPayment.PaymentID}).Warn("settlement.handoff_key not set, held payment will fail after restart")
+		}
+		manifest.Payments = append(manifest.Payments, payment)
+	}
+	return manifest, nil
+}
+
+// writeHandoff writes manifest to a temporary file renamed to path, so the
+// next server never reads a partially written file
+func writeHandoff(path string, manifest *HandoffManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Close()
+	} else {
+		file.Close()
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// IngestHandoff loads secret seeds of payments listed in
+// settlement.handoff_file by the previous server, so they can be released.
+// Payments that are no longer held are skipped. The file is renamed with
+// HandoffIngestedSuffix. Returns the number of ingested seeds, 0 when there
+// is no file.
+func (rh *RequestHandler) IngestHandoff(ctx context.Context) (int, error) {
+	path := rh.Config.Settlement.HandoffFile
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var manifest HandoffManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return 0, errors.Wrap(err, "Cannot decode handoff file")
+	}
+	if manifest.Version != HandoffVersion {
+		return 0, errors.Errorf("Unsupported handoff file version %d", manifest.Version)
+	}
+
+	ingested := 0
+	for _, payment := range manifest.Payments {
+		logger := log.WithFields(log.Fields{"id": payment.PaymentID})
+		if payment.EncryptedSeed == "" {
+			continue
+		}
+
+		if rh.Config.Settlement.HandoffKey == "" {
+			logger.Error("Cannot decrypt seed of held payment, settlement.handoff_key not set")
+			continue
+		}
+
+		heldPayment, err := rh.Repository.GetHeldPaymentByPaymentID(ctx, payment.PaymentID)
+		if err != nil {
+			return ingested, err
+		}
+		if heldPayment == nil || heldPayment.Status != entities.HeldPaymentStatusHeld {
+			// Released or cancelled in the meantime
+			continue
+		}
+
+		seed, err := crypto.Decrypt(rh.Config.Settlement.HandoffKey, payment.EncryptedSeed)
+		if err != nil {
+			logger.WithFields(log.Fields{"err": err}).Error("Cannot decrypt seed of held payment")
+			continue
+		}
+
+		kp, err := keypair.Parse(string(seed))
+		if err != nil || kp.Address() != heldPayment.SourceAccount {
+			logger.Error("Seed of held payment does not match its source account")
+			continue
+		}
+
+		rh.heldSeeds.set(payment.PaymentID, string(seed))
+		ingested++
+	}
+
+	return ingested, os.Rename(path, path+HandoffIngestedSuffix)
+}
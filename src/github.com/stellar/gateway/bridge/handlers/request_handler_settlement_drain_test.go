@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerDrainHeldPayments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "handoff")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &config.Config{
+		Accounts: config.Accounts{
+			// GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		Region: config.Region{Name: "eu"},
+		Settlement: config.Settlement{
+			Delay:        "15m",
+			DrainTimeout: "1s",
+			HandoffFile:  filepath.Join(dir, "handoff.json"),
+			HandoffKey:   "d2VsY29tZSB0byB0aGUgYnJpZGdlIHNlcnZlciAhISE=",
+		},
+	}
+
+	source, err := keypair.Random()
+	require.NoError(t, err)
+
+	heldPayments := []*entities.HeldPayment{
+		{PaymentID: "payment-1", SourceAccount: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ", Status: entities.HeldPaymentStatusHeld, SettleAt: time.Now().Add(-time.Minute)},
+		{PaymentID: "payment-2", SourceAccount: source.Address(), Status: entities.HeldPaymentStatusHeld, SettleAt: time.Now().Add(time.Hour)},
+	}
+
+	mockRepository := new(mocks.MockRepository)
+	requestHandler := NewRequestHandler(c, nil, nil, nil, mockRepository, nil, nil, nil, nil, nil)
+	requestHandler.heldSeeds.set("payment-2", source.Seed())
+
+	// payment-1 failed with a server error and is still held
+	mockRepository.On("GetHeldPaymentsToRelease", mock.Anything).Return([]*entities.HeldPayment{}, nil).Once()
+	mockRepository.On("GetHeldPayments").Return(heldPayments, nil).Once()
+	requestHandler.DrainHeldPayments()
+	mockRepository.AssertExpectations(t)
+
+	data, err := ioutil.ReadFile(c.Settlement.HandoffFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), source.Seed())
+
+	var manifest HandoffManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, HandoffVersion, manifest.Version)
+	assert.Equal(t, "eu", manifest.Region)
+	assert.False(t, manifest.DeadlineExceeded)
+	require.Len(t, manifest.Payments, 2)
+	assert.Equal(t, "payment-1", manifest.Payments[0].PaymentID)
+	assert.True(t, manifest.Payments[0].Due)
+	assert.Empty(t, manifest.Payments[0].EncryptedSeed, "base seed is not written")
+	assert.Equal(t, "payment-2", manifest.Payments[1].PaymentID)
+	assert.False(t, manifest.Payments[1].Due)
+	assert.NotEmpty(t, manifest.Payments[1].EncryptedSeed)
+
+	t.Run("next server ingests the handoff file", func(t *testing.T) {
+		mockRepository := new(mocks.MockRepository)
+		next := NewRequestHandler(c, nil, nil, nil, mockRepository, nil, nil, nil, nil, nil)
+
+		mockRepository.On("GetHeldPaymentByPaymentID", "payment-2").Return(heldPayments[1], nil).Once()
+		ingested, err := next.IngestHandoff(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, ingested)
+		mockRepository.AssertExpectations(t)
+
+		seed, ok := next.heldSeeds.get("payment-2")
+		assert.True(t, ok)
+		assert.Equal(t, source.Seed(), seed)
+
+		_, err = os.Stat(c.Settlement.HandoffFile)
+		assert.True(t, os.IsNotExist(err), "file is not ingested twice")
+		_, err = os.Stat(c.Settlement.HandoffFile + HandoffIngestedSuffix)
+		assert.NoError(t, err)
+
+		ingested, err = next.IngestHandoff(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, ingested)
+	})
+
+	t.Run("releasing stops at the deadline", func(t *testing.T) {
+		mockRepository := new(mocks.MockRepository)
+		requestHandler := NewRequestHandler(c, nil, nil, nil, mockRepository, nil, nil, nil, nil, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		mockRepository.On("GetHeldPaymentsToRelease", mock.Anything).Return(heldPayments[:1], nil).Once()
+		requestHandler.ReleaseHeldPayments(ctx)
+		// UpdateHeldPaymentStatus is not called
+		mockRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/test"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zenazn/goji/web"
+)
+
+func TestRequestHandlerSettlement(t *testing.T) {
+	c := &config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Accounts: config.Accounts{
+			// GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		Settlement: config.Settlement{
+			Delay:       "15m",
+			MaxAttempts: 2,
+			HandoffKey:  "d2VsY29tZSB0byB0aGUgYnJpZGdlIHNlcnZlciAhISE=",
+		},
+	}
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+
+	requestHandler := NewRequestHandler(c, nil, mockHorizon, nil, mockRepository, mockEntityManager, nil, nil, mockTransactionSubmitter, nil)
+
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.Payment))
+	defer testServer.Close()
+
+	mux := web.New()
+	mux.Delete("/payments/:id", requestHandler.CancelPayment)
+	cancelServer := httptest.NewServer(mux)
+	defer cancelServer.Close()
+
+	cancel := func(id string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest("DELETE", cancelServer.URL+"/payments/"+id, nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp.StatusCode, test.StringToJSONMap(string(body))
+	}
+
+	params := url.Values{
+		"id":          {"payment-1"},
+		"destination": {"GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"},
+		"amount":      {"20.0"},
+	}
+
+	Convey("Given payment from account with settlement delay", t, func() {
+		Convey("When payment is new it should be held", func() {
+			mockRepository.On("GetHeldPaymentByPaymentID", "payment-1").Return(nil, nil).Once()
+			mockRepository.On("GetSentTransactionByPaymentID", "payment-1").Return(nil, nil).Once()
+			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.HeldPayment")).Return(nil).Once().Run(func(args mock.Arguments) {
+				heldPayment := args.Get(0).(*entities.HeldPayment)
+				assert.Equal(t, "payment-1", heldPayment.PaymentID)
+				assert.Equal(t, "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ", heldPayment.SourceAccount)
+				assert.Equal(t, entities.HeldPaymentStatusHeld, heldPayment.Status)
+				assert.Equal(t, 15*time.Minute, heldPayment.SettleAt.Sub(heldPayment.CreatedAt))
+				assert.NotContains(t, heldPayment.Request, "source")
+			})
+
+			statusCode, response := net.GetResponse(testServer, params)
+			assert.Equal(t, http.StatusAccepted, statusCode)
+			responseJSON := test.StringToJSONMap(string(response))
+			assert.Equal(t, "payment-1", responseJSON["id"])
+			assert.Equal(t, "held", responseJSON["status"])
+			mockRepository.AssertExpectations(t)
+			mockEntityManager.AssertExpectations(t)
+		})
+
+		Convey("When payment is already held it should return held status", func() {
+			mockRepository.On("GetHeldPaymentByPaymentID", "payment-1").Return(&entities.HeldPayment{
+				PaymentID: "payment-1",
+				Status:    entities.HeldPaymentStatusHeld,
+			}, nil).Once()
+
+			statusCode, _ := net.GetResponse(testServer, params)
+			assert.Equal(t, http.StatusAccepted, statusCode)
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("When payment has been cancelled it should return error", func() {
+			mockRepository.On("GetHeldPaymentByPaymentID", "payment-1").Return(&entities.HeldPayment{
+				PaymentID: "payment-1",
+				Status:    entities.HeldPaymentStatusCancelled,
+			}, nil).Once()
+
+			statusCode, response := net.GetResponse(testServer, params)
+			assert.Equal(t, http.StatusConflict, statusCode)
+			assert.Equal(t, "payment_cancelled", test.StringToJSONMap(string(response))["code"])
+			mockRepository.AssertExpectations(t)
+		})
+	})
+
+	Convey("Given cancel request", t, func() {
+		heldPayment := &entities.HeldPayment{PaymentID: "payment-1", Status: entities.HeldPaymentStatusHeld}
+
+		Convey("When payment is held it should be cancelled", func() {
+			mockRepository.On("GetHeldPaymentByPaymentID", "payment-1").Return(heldPayment, nil).Once()
+			mockRepository.On(
+				"UpdateHeldPaymentStatus",
+				heldPayment,
+				entities.HeldPaymentStatusCancelled,
+				mock.AnythingOfType("time.Time"),
+			).Return(true, nil).Once().Run(func(args mock.Arguments) {
+				heldPayment.Status = entities.HeldPaymentStatusCancelled
+			})
+
+			statusCode, response := cancel("payment-1")
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Equal(t, "cancelled", response["status"])
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("When settlement window has ended it should return error", func() {
+			mockRepository.On("GetHeldPaymentByPaymentID", "payment-1").Return(heldPayment, nil).Once()
+			mockRepository.On(
+				"UpdateHeldPaymentStatus",
+				heldPayment,
+				entities.HeldPaymentStatusCancelled,
+				mock.AnythingOfType("time.Time"),
+			).Return(false, nil).Once()
+
+			statusCode, response := cancel("payment-1")
+			assert.Equal(t, http.StatusConflict, statusCode)
+			assert.Equal(t, "cannot_cancel", response["code"])
+			mockRepository.AssertExpectations(t)
+		})
+
+		Convey("When payment does not exist it should return error", func() {
+			mockRepository.On("GetHeldPaymentByPaymentID", "payment-2").Return(nil, nil).Once()
+
+			statusCode, _ := cancel("payment-2")
+			assert.Equal(t, http.StatusNotFound, statusCode)
+			mockRepository.AssertExpectations(t)
+		})
+	})
+
+	Convey("Given held payments with settlement window ended", t, func() {
+		heldPayment := &entities.HeldPayment{
+			PaymentID:     "payment-1",
+			SourceAccount: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ",
+			Request:       "amount=20.0&destination=GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS&id=payment-1",
+			Status:        entities.HeldPaymentStatusHeld,
+		}
+
+		mockRepository.On("GetHeldPaymentsToRelease", mock.AnythingOfType("time.Time")).Return([]*entities.HeldPayment{heldPayment}, nil).Once()
+		mockRepository.On(
+			"UpdateHeldPaymentStatus",
+			heldPayment,
+			entities.HeldPaymentStatusReleasing,
+			mock.AnythingOfType("time.Time"),
+		).Return(true, nil).Once().Run(func(args mock.Arguments) {
+			heldPayment.Status = entities.HeldPaymentStatusReleasing
+		})
+		mockRepository.On("GetSentTransactionByPaymentID", "payment-1").Return(nil, nil).Once()
+		mockHorizon.On("LoadAccount", "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS").Return(horizon.AccountResponse{}, nil).Once()
+
+		ledger := uint64(1988728)
+		mockTransactionSubmitter.On(
+			"SubmitTransaction",
+			mock.AnythingOfType("*string"),
+			"SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+			mock.AnythingOfType("build.PaymentBuilder"),
+			nil,
+		).Return(horizon.SubmitTransactionResponse{Ledger: &ledger}, nil).Once()
+
+		mockEntityManager.On("Persist", heldPayment).Return(nil).Once()
+
+		Convey("it should submit them", func() {
+			requestHandler.ReleaseHeldPayments(context.Background())
+			assert.Equal(t, entities.HeldPaymentStatusReleased, heldPayment.Status)
+			mockRepository.AssertExpectations(t)
+			mockTransactionSubmitter.AssertExpectations(t)
+			mockEntityManager.AssertExpectations(t)
+		})
+	})
+
+	Convey("Given payment from an account other than base seed", t, func() {
+		source := "SABLR5HOI2IUOYB27TR4TO7HWDJIGSRJTT4UUTXXZOFVVPGQKJ5ME43J"
+		sourceAccount := keypair.MustParse(source).Address()
+		otherParams := url.Values{
+			"id":          {"payment-2"},
+			"source":      {source},
+			"destination": {"GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"},
+			"amount":      {"20.0"},
+		}
+
+		var heldPayment *entities.HeldPayment
+		mockRepository.On("GetHeldPaymentByPaymentID", "payment-2").Return(nil, nil).Once()
+		mockRepository.On("GetSentTransactionByPaymentID", "payment-2").Return(nil, nil).Once()
+		mockEntityManager.On("Persist", mock.AnythingOfType("*entities.HeldPayment")).Return(nil).Once().Run(func(args mock.Arguments) {
+			heldPayment = args.Get(0).(*entities.HeldPayment)
+		})
+
+		statusCode, _ := net.GetResponse(testServer, otherParams)
+		assert.Equal(t, http.StatusAccepted, statusCode)
+		mockRepository.AssertExpectations(t)
+
+		Convey("it should persist the encrypted seed", func() {
+			So(heldPayment.EncryptedSeed, ShouldNotBeNil)
+			assert.NotContains(t, *heldPayment.EncryptedSeed, source)
+
+			Convey("and release the payment after restart", func() {
+				restarted := NewRequestHandler(c, nil, mockHorizon, nil, mockRepository, mockEntityManager, nil, nil, mockTransactionSubmitter, nil)
+				heldPayment.Status = entities.HeldPaymentStatusReleasing
+
+				mockRepository.On("GetHeldPaymentsToRelease", mock.AnythingOfType("time.Time")).Return([]*entities.HeldPayment{heldPayment}, nil).Once()
+				mockRepository.On("GetSentTransactionByPaymentID", "payment-2").Return(nil, nil).Once()
+				mockHorizon.On("LoadAccount", "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS").Return(horizon.AccountResponse{}, nil).Once()
+				ledger := uint64(1988728)
+				mockTransactionSubmitter.On(
+					"SubmitTransaction",
+					mock.AnythingOfType("*string"),
+					source,
+					mock.AnythingOfType("build.PaymentBuilder"),
+					nil,
+				).Return(horizon.SubmitTransactionResponse{Ledger: &ledger}, nil).Once()
+				mockEntityManager.On("Persist", heldPayment).Return(nil).Once()
+
+				restarted.ReleaseHeldPayments(context.Background())
+				assert.Equal(t, entities.HeldPaymentStatusReleased, heldPayment.Status)
+				assert.Nil(t, heldPayment.EncryptedSeed)
+				assert.Equal(t, sourceAccount, heldPayment.SourceAccount)
+				mockRepository.AssertExpectations(t)
+				mockTransactionSubmitter.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+
+			Convey("and fail the payment after max attempts", func() {
+				heldPayment.SettleAt = time.Now().Add(-time.Minute)
+				for attempt := 1; attempt <= 2; attempt++ {
+					mockRepository.On("GetHeldPaymentsToRelease", mock.AnythingOfType("time.Time")).Return([]*entities.HeldPayment{heldPayment}, nil).Once()
+					mockRepository.On(
+						"UpdateHeldPaymentStatus",
+						heldPayment,
+						entities.HeldPaymentStatusReleasing,
+						mock.AnythingOfType("time.Time"),
+					).Return(true, nil).Once()
+					mockRepository.On("GetSentTransactionByPaymentID", "payment-2").Return(nil, nil).Once()
+					mockHorizon.On("LoadAccount", "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS").Return(horizon.AccountResponse{}, nil).Once()
+					mockTransactionSubmitter.On(
+						"SubmitTransaction",
+						mock.AnythingOfType("*string"),
+						source,
+						mock.AnythingOfType("build.PaymentBuilder"),
+						nil,
+					).Return(horizon.SubmitTransactionResponse{}, errors.New("horizon down")).Once()
+					mockEntityManager.On("Persist", heldPayment).Return(nil).Once()
+
+					requestHandler.ReleaseHeldPayments(context.Background())
+					assert.Equal(t, attempt, heldPayment.Attempts)
+				}
+
+				assert.Equal(t, entities.HeldPaymentStatusFailed, heldPayment.Status)
+				assert.Nil(t, heldPayment.EncryptedSeed)
+				mockRepository.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+			})
+		})
+	})
+
+	Convey("Given payment from an account other than base seed without handoff key", t, func() {
+		noKey := *c
+		noKey.Settlement.HandoffKey = ""
+		noKeyHandler := NewRequestHandler(&noKey, nil, mockHorizon, nil, mockRepository, mockEntityManager, nil, nil, mockTransactionSubmitter, nil)
+		noKeyServer := httptest.NewServer(http.HandlerFunc(noKeyHandler.Payment))
+		defer noKeyServer.Close()
+
+		mockRepository.On("GetHeldPaymentByPaymentID", "payment-3").Return(nil, nil).Once()
+		mockRepository.On("GetSentTransactionByPaymentID", "payment-3").Return(nil, nil).Once()
+
+		statusCode, response := net.GetResponse(noKeyServer, url.Values{
+			"id":          {"payment-3"},
+			"source":      {"SABLR5HOI2IUOYB27TR4TO7HWDJIGSRJTT4UUTXXZOFVVPGQKJ5ME43J"},
+			"destination": {"GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"},
+			"amount":      {"20.0"},
+		})
+		assert.Equal(t, http.StatusInternalServerError, statusCode)
+		assert.Equal(t, "cannot_hold", test.StringToJSONMap(string(response))["code"])
+		mockRepository.AssertExpectations(t)
+	})
+}
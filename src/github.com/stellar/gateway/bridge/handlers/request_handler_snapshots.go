@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/snapshots"
+)
+
+// federationSnapshot is an element of /admin/federation-snapshots response
+type federationSnapshot struct {
+	TransactionID string                  `json:"transaction_id"`
+	PaymentID     *string                 `json:"payment_id"`
+	Kind          string                  `json:"kind"`
+	URL           string                  `json:"url"`
+	FinalURL      string                  `json:"final_url"`
+	StatusCode    int                     `json:"status_code"`
+	Body          string                  `json:"body"`
+	Truncated     bool                    `json:"truncated"`
+	TLSVersion    string                  `json:"tls_version"`
+	CipherSuite   string                  `json:"cipher_suite"`
+	Certificates  []snapshots.Certificate `json:"certificates"`
+	FetchedAt     time.Time               `json:"fetched_at"`
+	RecordedAt    time.Time               `json:"recorded_at"`
+}
+
+// AdminFederationSnapshots implements GET /admin/federation-snapshots
+// endpoint. It returns federation and stellar.toml responses used to resolve
+// the destination of a transaction (`transaction_id` param) or a payment
+// (`id` param).
+func (rh *RequestHandler) AdminFederationSnapshots(w http.ResponseWriter, r *http.Request) {
+	filter := db.FederationSnapshotsFilter{
+		TransactionID: r.URL.Query().Get("transaction_id"),
+		PaymentID:     r.URL.Query().Get("id"),
+	}
+	if filter.TransactionID == "" && filter.PaymentID == "" {
+		server.Write(w, protocols.NewMissingParameter("transaction_id"))
+		return
+	}
+
+	found, err := rh.Repository.GetFederationSnapshots(r.Context(), filter)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading FederationSnapshots")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	response := []federationSnapshot{}
+	for _, snapshot := range found {
+		var certificates []snapshots.Certificate
+		err = json.Unmarshal([]byte(snapshot.Certificates), &certificates)
+		if err != nil {
+			log.WithFields(log.Fields{"id": *snapshot.ID, "err": err}).Error("Error decoding FederationSnapshot certificates")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+
+		response = append(response, federationSnapshot{
+			TransactionID: snapshot.TransactionID,
+			PaymentID:     snapshot.PaymentID,
+			Kind:          snapshot.Kind,
+			URL:           snapshot.URL,
+			FinalURL:      snapshot.FinalURL,
+			StatusCode:    snapshot.StatusCode,
+			Body:          snapshot.Body,
+			Truncated:     snapshot.Truncated,
+			TLSVersion:    snapshot.TLSVersion,
+			CipherSuite:   snapshot.CipherSuite,
+			Certificates:  certificates,
+			FetchedAt:     snapshot.FetchedAt,
+			RecordedAt:    snapshot.RecordedAt,
+		})
+	}
+
+	err = server.WriteJSON(w, response)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding FederationSnapshots")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+// persistSnapshots persists snapshots used by a sent transaction. Errors are
+// logged only, the transaction has already been sent.
+func (rh *RequestHandler) persistSnapshots(used []*snapshots.Snapshot, transactionID string, paymentID *string) {
+	if transactionID == "" {
+		return
+	}
+
+	now := clock.Now()
+	for _, snapshot := range used {
+		entity, err := snapshot.Entity(transactionID, paymentID, now)
+		if err == nil {
+			err = rh.EntityManager.Persist(entity)
+		}
+		if err != nil {
+			log.WithFields(log.Fields{"transaction_id": transactionID, "url": snapshot.URL, "err": err}).Error("Error persisting FederationSnapshot")
+		}
+	}
+}
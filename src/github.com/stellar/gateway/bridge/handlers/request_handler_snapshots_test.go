@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/snapshots"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestHandlerAdminFederationSnapshots(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+	requestHandler := RequestHandler{Repository: mockRepository}
+
+	testServer := httptest.NewServer(http.HandlerFunc(requestHandler.AdminFederationSnapshots))
+	defer testServer.Close()
+
+	get := func(query string) (*http.Response, string) {
+		resp, err := http.Get(testServer.URL + "?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	resp, body := get("")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, body, "missing_parameter")
+
+	id := int64(1)
+	paymentID := "payment-1"
+	fetchedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	mockRepository.On("GetFederationSnapshots", db.FederationSnapshotsFilter{PaymentID: paymentID}).Return([]*entities.FederationSnapshot{{
+		ID:            &id,
+		TransactionID: "abc",
+		PaymentID:     &paymentID,
+		Kind:          snapshots.KindFederation,
+		URL:           "https://example.com/federation?q=bob%2Aexample.com&type=name",
+		FinalURL:      "https://example.com/federation?q=bob%2Aexample.com&type=name",
+		StatusCode:    200,
+		Body:          `{"account_id":"GAB"}`,
+		TLSVersion:    "TLS 1.3",
+		CipherSuite:   "TLS_AES_128_GCM_SHA256",
+		Certificates:  `[{"subject":"CN=example.com","sha256":"ab12"}]`,
+		FetchedAt:     fetchedAt,
+		RecordedAt:    fetchedAt.Add(time.Minute),
+	}}, nil).Once()
+
+	resp, body = get("id=" + paymentID)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response []federationSnapshot
+	require.NoError(t, json.Unmarshal([]byte(body), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "abc", response[0].TransactionID)
+	assert.Equal(t, `{"account_id":"GAB"}`, response[0].Body)
+	assert.Equal(t, "TLS 1.3", response[0].TLSVersion)
+	require.Len(t, response[0].Certificates, 1)
+	assert.Equal(t, "CN=example.com", response[0].Certificates[0].Subject)
+	assert.Equal(t, "ab12", response[0].Certificates[0].SHA256)
+	assert.True(t, fetchedAt.Equal(response[0].FetchedAt))
+	mockRepository.AssertExpectations(t)
+}
+
+func TestRequestHandlerPersistSnapshots(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	requestHandler := RequestHandler{EntityManager: mockEntityManager}
+
+	paymentID := "payment-1"
+	used := []*snapshots.Snapshot{
+		{Kind: snapshots.KindStellarToml, URL: "https://example.com/.well-known/stellar.toml"},
+		{Kind: snapshots.KindFederation, URL: "https://example.com/federation?q=bob%2Aexample.com&type=name"},
+	}
+
+	mockEntityManager.On("Persist", mock.AnythingOfType("*entities.FederationSnapshot")).Return(nil).Twice().Run(func(args mock.Arguments) {
+		snapshot := args.Get(0).(*entities.FederationSnapshot)
+		assert.Equal(t, "abc", snapshot.TransactionID)
+		assert.Equal(t, paymentID, *snapshot.PaymentID)
+		assert.Equal(t, "null", snapshot.Certificates)
+	})
+
+	requestHandler.persistSnapshots(used, "abc", &paymentID)
+	// Not sent transactions are skipped
+	requestHandler.persistSnapshots(used, "", &paymentID)
+	mockEntityManager.AssertExpectations(t)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/stage"
+)
+
+// runStage runs fn in a given stage of handling request. It is limited by
+// the request context and the stage timeout from `timeouts` config group.
+func (rh *RequestHandler) runStage(request *bridge.PaymentRequest, name string, fn func(ctx context.Context) error) error {
+	return stage.Run(request.HTTPRequest.Context(), name, rh.Config.Timeouts.Stage(name), fn)
+}
+
+// checkStage runs check returning an error response in a given stage of
+// handling request
+func (rh *RequestHandler) checkStage(request *bridge.PaymentRequest, name string, check func() *protocols.ErrorResponse) *protocols.ErrorResponse {
+	var errorResponse *protocols.ErrorResponse
+	err := rh.runStage(request, name, func(context.Context) error {
+		errorResponse = check()
+		return nil
+	})
+	if err != nil {
+		return stageErrorResponse(name, err)
+	}
+	return errorResponse
+}
+
+// stageErrorResponse returns a response to a request whose stage timed out
+// or was cancelled
+func stageErrorResponse(name string, err error) *protocols.ErrorResponse {
+	if err == stage.ErrTimeout {
+		log.WithFields(log.Fields{"stage": name}).Warn("Request stage timed out")
+		return protocols.TimeoutError
+	}
+
+	log.WithFields(log.Fields{"stage": name, "err": err}).Info("Request cancelled")
+	return protocols.ServiceUnavailableError
+}
+
+// submissionContext returns a context of submitting the transaction of
+// request. Transactions of cancelled requests are not submitted but started
+// submissions are not cancelled when the client disconnects: the transaction
+// could be already sent and its result must be saved.
+func submissionContext(request *bridge.PaymentRequest) (context.Context, *protocols.ErrorResponse) {
+	ctx := request.HTTPRequest.Context()
+	if err := ctx.Err(); err != nil {
+		log.WithFields(log.Fields{"err": err}).Info("Request cancelled before submitting transaction")
+		return nil, protocols.ServiceUnavailableError
+	}
+	return context.WithoutCancel(ctx), nil
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/mocks"
+	fproto "github.com/stellar/go/protocols/federation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentStages(t *testing.T) {
+	c := &config.Config{
+		Accounts: config.Accounts{
+			// GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ
+			BaseSeed: "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		},
+		Timeouts: config.Timeouts{Federation: "10ms"},
+	}
+	mockFederationResolver := new(mocks.MockFederationResolver)
+	mockTransactionSubmitter := new(mocks.MockTransactionSubmitter)
+	rh := NewRequestHandler(c, nil, new(mocks.MockHorizon), nil, nil, nil, nil, mockFederationResolver, mockTransactionSubmitter, nil)
+
+	newRequest := func(destination string) *http.Request {
+		values := url.Values{"destination": {destination}, "amount": {"20"}}
+		r := httptest.NewRequest("POST", "/payment", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return r
+	}
+
+	// Slow federation server
+	block := make(chan time.Time)
+	defer close(block)
+	mockFederationResolver.On("LookupByAddress", "bob*example.com").
+		Return(&fproto.NameResponse{AccountID: "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"}, nil).
+		WaitUntil(block)
+
+	w := httptest.NewRecorder()
+	rh.Payment(w, newRequest("bob*example.com"))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), "timeout")
+
+	// Transactions of cancelled requests are not submitted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = httptest.NewRecorder()
+	rh.Payment(w, newRequest("GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS").WithContext(ctx))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	mockTransactionSubmitter.AssertNotCalled(t, "SubmitTransaction")
+}
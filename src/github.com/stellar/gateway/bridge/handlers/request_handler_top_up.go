@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/balances"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+)
+
+const (
+	// topUpPaymentIDPrefix is a prefix of IDs of top-up payments
+	topUpPaymentIDPrefix = "top-up-"
+	// TopUpKeyID is the API key ID top-up payments are created by, so they
+	// can be approved by any API key
+	TopUpKeyID = "balance_monitor"
+	// TopUpMetadataType is the value of `type` metadata of top-up payments
+	TopUpMetadataType = "top_up"
+)
+
+// TopUp sends a payment of amount from the funding account of threshold to
+// its account using /payment handler, so top-ups are held, approved and
+// checked by risk hooks like other payments. Top-ups are saved with `type`
+// metadata set to top_up.
+func (rh *RequestHandler) TopUp(ctx context.Context, threshold balances.Threshold, amount amounts.Amount) error {
+	code := threshold.AssetCode
+	if code == "" {
+		code = "XLM"
+	}
+	id := topUpPaymentIDPrefix + threshold.AccountID + "-" + code + "-" + strconv.FormatInt(clock.Now().Unix(), 10)
+
+	values := url.Values{
+		"id":             {id},
+		"source":         {threshold.FundingSeed},
+		"destination":    {threshold.AccountID},
+		"amount":         {amount.String()},
+		"asset_code":     {threshold.AssetCode},
+		"asset_issuer":   {threshold.AssetIssuer},
+		"metadata[type]": {TopUpMetadataType},
+	}
+
+	ctx = auth.WithKey(ctx, &auth.Key{ID: TopUpKeyID})
+	httpRequest, _ := http.NewRequestWithContext(ctx, "POST", "/payment", strings.NewReader(values.Encode()))
+	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	request := &bridge.PaymentRequest{}
+	err := request.FromRequest(httpRequest)
+	if err != nil {
+		return err
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return err
+	}
+
+	response := &responseRecorder{status: http.StatusOK, header: make(http.Header)}
+	rh.payment(response, request, true)
+
+	fields := log.Fields{
+		"id":           id,
+		"account_id":   threshold.AccountID,
+		"asset_code":   threshold.AssetCode,
+		"asset_issuer": threshold.AssetIssuer,
+		"amount":       amount.String(),
+	}
+	switch response.status {
+	case http.StatusOK:
+		log.WithFields(fields).Info("Balance topped up")
+	case http.StatusAccepted:
+		log.WithFields(fields).Info("Balance top-up held")
+	default:
+		errorResponse := &protocols.ErrorResponse{}
+		if json.Unmarshal(response.body.Bytes(), errorResponse) != nil || errorResponse.Code == "" {
+			return errors.New("Top-up payment failed with status " + strconv.Itoa(response.status))
+		}
+		return errors.New("Top-up payment failed: " + errorResponse.Code)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/balances"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopUp(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	clock.Default = clock.Func(func() time.Time { return now })
+	defer func() { clock.Default = clock.System }()
+
+	c := &config.Config{
+		Assets:     []config.Asset{{Code: "XLM"}},
+		Approval:   config.Approval{Enabled: true},
+		Settlement: config.Settlement{HandoffKey: "d2VsY29tZSB0byB0aGUgYnJpZGdlIHNlcnZlciAhISE="},
+	}
+	rh := NewRequestHandler(c, nil, nil, driver, db.NewRepository(driver), db.NewEntityManager(driver), nil, nil, nil, nil)
+
+	// Funding account is GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ
+	threshold := balances.Threshold{
+		AccountID:   "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5",
+		Min:         100 * amounts.One,
+		TopUpTo:     500 * amounts.One,
+		FundingSeed: "SABY7FRMMJWPBTKQQ2ZN43AUJQ3Z2ZAK36VYSG2SPE2ABNQXA66H5E5G",
+	}
+	require.NoError(t, rh.TopUp(context.Background(), threshold, 450*amounts.One))
+
+	// Top-ups wait for approval by an API key
+	id := "top-up-GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5-XLM-1792065600"
+	heldPayment, err := rh.Repository.GetHeldPaymentByPaymentID(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, heldPayment)
+	assert.Equal(t, entities.HeldPaymentStatusPending, heldPayment.Status)
+	require.NotNil(t, heldPayment.CreatedBy)
+	assert.Equal(t, TopUpKeyID, *heldPayment.CreatedBy)
+
+	request, err := url.ParseQuery(heldPayment.Request)
+	require.NoError(t, err)
+	assert.Equal(t, "450.0000000", request.Get("amount"))
+	assert.Equal(t, threshold.AccountID, request.Get("destination"))
+	assert.Equal(t, TopUpMetadataType, request.Get("metadata[type]"))
+	assert.Empty(t, request.Get("source"))
+	// Funding seed is persisted encrypted
+	assert.NotNil(t, heldPayment.EncryptedSeed)
+
+	// Amount limits of the asset apply to top-ups
+	c.Assets[0].MaxAmount = "100"
+	assert.EqualError(t, rh.TopUp(context.Background(), threshold, 450*amounts.One), "Top-up payment failed: amount_above_maximum")
+	c.Assets[0].MaxAmount = ""
+
+	threshold.FundingSeed = "invalid"
+	assert.Error(t, rh.TopUp(context.Background(), threshold, 450*amounts.One))
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/cloudevents"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// WebhookResponse is a webhook with its event types. Secret is returned only
+// when the webhook is created.
+type WebhookResponse struct {
+	*entities.Webhook
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// AdminWebhooks implements GET /admin/webhooks endpoint. It returns all
+// registered webhooks.
+func (rh *RequestHandler) AdminWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := rh.Repository.GetWebhooks(r.Context())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading Webhooks")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	response := []WebhookResponse{}
+	for _, webhook := range webhooks {
+		response = append(response, WebhookResponse{Webhook: webhook, Events: webhook.EventTypes()})
+	}
+	rh.writeWebhook(w, response)
+}
+
+// AdminWebhook implements GET /admin/webhooks/{id} endpoint
+func (rh *RequestHandler) AdminWebhook(c web.C, w http.ResponseWriter, r *http.Request) {
+	webhook, errorResponse := rh.loadWebhook(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	rh.writeWebhook(w, WebhookResponse{Webhook: webhook, Events: webhook.EventTypes()})
+}
+
+// AdminCreateWebhook implements POST /admin/webhooks endpoint. It registers
+// a webhook receiving events of the selected types in the newest schema
+// version it accepts. A secret is generated when it's not sent, it's
+// returned only in this response.
+func (rh *RequestHandler) AdminCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	request, errorResponse := rh.webhookRequest(r)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error generating webhook ID")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if request.Secret == "" {
+		secret := make([]byte, 32)
+		_, err = rand.Read(secret)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error generating webhook secret")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+		request.Secret = hex.EncodeToString(secret)
+	}
+
+	now := clock.Now()
+	webhook := &entities.Webhook{
+		WebhookID:     hex.EncodeToString(id),
+		MaxAttempts:   rh.Config.Webhooks.MaxAttemptsOrDefault(),
+		RetryInterval: int(rh.Config.Webhooks.RetryIntervalOrDefault() / time.Second),
+		Enabled:       true,
+		Format:        cloudevents.FormatJSON,
+		CreatedAt:     now,
+	}
+	applyWebhookRequest(webhook, request, now)
+
+	errorResponse = rh.persistWebhook(webhook)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	log.WithFields(log.Fields{"webhook_id": webhook.WebhookID, "url": webhook.URL}).Info("Webhook created")
+	rh.writeWebhook(w, WebhookResponse{Webhook: webhook, Events: webhook.EventTypes(), Secret: webhook.Secret})
+}
+
+// AdminUpdateWebhook implements POST /admin/webhooks/{id} endpoint. It
+// replaces URL and events of the webhook. Secret and retry policy are
+// changed only when they are sent.
+func (rh *RequestHandler) AdminUpdateWebhook(c web.C, w http.ResponseWriter, r *http.Request) {
+	request, errorResponse := rh.webhookRequest(r)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	webhook, errorResponse := rh.loadWebhook(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	applyWebhookRequest(webhook, request, clock.Now())
+
+	errorResponse = rh.persistWebhook(webhook)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	log.WithFields(log.Fields{"webhook_id": webhook.WebhookID, "url": webhook.URL}).Info("Webhook updated")
+	rh.writeWebhook(w, WebhookResponse{Webhook: webhook, Events: webhook.EventTypes()})
+}
+
+// AdminRemoveWebhook implements DELETE /admin/webhooks/{id} endpoint
+func (rh *RequestHandler) AdminRemoveWebhook(c web.C, w http.ResponseWriter, r *http.Request) {
+	webhook, errorResponse := rh.loadWebhook(r, c.URLParams["id"])
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	err := rh.EntityManager.Delete(webhook)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error deleting Webhook")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	log.WithFields(log.Fields{"webhook_id": webhook.WebhookID}).Info("Webhook removed")
+	rh.writeWebhook(w, WebhookResponse{Webhook: webhook, Events: webhook.EventTypes()})
+}
+
+func (rh *RequestHandler) webhookRequest(r *http.Request) (*bridge.WebhookRequest, *protocols.ErrorResponse) {
+	request := &bridge.WebhookRequest{}
+	err := request.FromRequest(r)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, protocols.InvalidParameterError
+	}
+
+	err = request.Validate()
+	if err != nil {
+		errorResponse := err.(*protocols.ErrorResponse)
+		log.WithFields(errorResponse.LogData).Error(errorResponse.Error())
+		return nil, errorResponse
+	}
+
+	return request, nil
+}
+
+// applyWebhookRequest sets fields of the webhook sent in the request, values
+// are checked by Validate
+func applyWebhookRequest(webhook *entities.Webhook, request *bridge.WebhookRequest, now time.Time) {
+	webhook.URL = request.URL
+	webhook.Events = strings.Join(request.EventTypes(), ",")
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+	if request.MaxAttempts != "" {
+		webhook.MaxAttempts, _ = strconv.Atoi(request.MaxAttempts)
+	}
+	if request.RetryInterval != "" {
+		webhook.RetryInterval, _ = strconv.Atoi(request.RetryInterval)
+	}
+	if request.Enabled != "" {
+		webhook.Enabled, _ = strconv.ParseBool(request.Enabled)
+	}
+	if request.Format != "" {
+		webhook.Format = request.Format
+	}
+	if request.SchemaVersion != "" || webhook.SchemaVersion == "" {
+		webhook.SchemaVersion, _ = cloudevents.Negotiate(request.SchemaVersion)
+	}
+	webhook.UpdatedAt = now
+}
+
+func (rh *RequestHandler) loadWebhook(r *http.Request, id string) (*entities.Webhook, *protocols.ErrorResponse) {
+	webhook, err := rh.Repository.GetWebhook(r.Context(), id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting Webhook")
+		return nil, protocols.InternalServerError
+	}
+
+	if webhook == nil {
+		return nil, bridge.WebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+func (rh *RequestHandler) persistWebhook(webhook *entities.Webhook) *protocols.ErrorResponse {
+	err := rh.EntityManager.Persist(webhook)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error persisting Webhook")
+		return protocols.InternalServerError
+	}
+	return nil
+}
+
+func (rh *RequestHandler) writeWebhook(w http.ResponseWriter, value interface{}) {
+	err := server.WriteJSON(w, value)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding Webhook")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
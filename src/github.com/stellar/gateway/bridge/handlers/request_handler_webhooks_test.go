@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestAdminWebhooks(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	c := &config.Config{Webhooks: config.Webhooks{Enabled: true, MaxAttempts: 3}}
+	rh := NewRequestHandler(c, nil, nil, driver, db.NewRepository(driver), db.NewEntityManager(driver), nil, nil, nil, nil)
+
+	post := func(id string, values url.Values) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/admin/webhooks", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if id == "" {
+			rh.AdminCreateWebhook(w, r)
+		} else {
+			rh.AdminUpdateWebhook(web.C{URLParams: map[string]string{"id": id}}, w, r)
+		}
+		return w
+	}
+
+	w := post("", url.Values{"url": {"https://example.com/hook"}, "events": {"payment_received, payment_sent"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var created WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Len(t, created.WebhookID, 32)
+	assert.Equal(t, []string{"payment_received", "payment_sent"}, created.Events)
+	assert.Len(t, created.Secret, 64)
+	assert.Equal(t, 3, created.MaxAttempts)
+	assert.Equal(t, 30, created.RetryInterval)
+	assert.True(t, created.Enabled)
+	assert.Equal(t, "json", created.Format)
+	assert.Equal(t, "1", created.SchemaVersion)
+
+	w = post("", url.Values{"url": {"https://example.com/hook"}, "events": {"payment"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = post("", url.Values{"url": {"example.com"}, "events": {"payment_sent"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = post("", url.Values{"url": {"https://example.com/hook"}, "events": {"payment_sent"}, "schema_version": {"7"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "schema_version")
+
+	w = post(created.WebhookID, url.Values{"url": {"https://example.com/hook2"}, "events": {"submission_failed"}, "max_attempts": {"1"}, "enabled": {"false"}, "format": {"cloudevents"}, "schema_version": {"1,2"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), created.Secret)
+
+	webhook, err := rh.Repository.GetWebhook(context.Background(), created.WebhookID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook2", webhook.URL)
+	assert.Equal(t, "submission_failed", webhook.Events)
+	assert.Equal(t, created.Secret, webhook.Secret)
+	assert.Equal(t, 1, webhook.MaxAttempts)
+	assert.False(t, webhook.Enabled)
+	assert.Equal(t, "cloudevents", webhook.Format)
+	assert.Equal(t, "1", webhook.SchemaVersion)
+
+	w = httptest.NewRecorder()
+	rh.AdminWebhooks(w, httptest.NewRequest("GET", "/admin/webhooks", nil))
+	assert.Contains(t, w.Body.String(), created.WebhookID)
+	assert.NotContains(t, w.Body.String(), created.Secret)
+
+	params := web.C{URLParams: map[string]string{"id": created.WebhookID}}
+	w = httptest.NewRecorder()
+	rh.AdminRemoveWebhook(params, w, httptest.NewRequest("DELETE", "/admin/webhooks/"+created.WebhookID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	rh.AdminWebhook(params, w, httptest.NewRequest("GET", "/admin/webhooks/"+created.WebhookID, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
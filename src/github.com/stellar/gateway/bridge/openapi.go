@@ -0,0 +1,229 @@
+package bridge
+
+import (
+	"github.com/stellar/gateway/bridge/handlers"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/health"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/openapi"
+	protocolsbridge "github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/gateway/stream"
+)
+
+// openAPIInfo returns info of the OpenAPI document of the API
+func (a *App) openAPIInfo() openapi.Info {
+	version := a.version
+	if version == "" {
+		version = "N/A"
+	}
+	return openapi.Info{Title: "Bridge server", Version: version}
+}
+
+// openAPIRoutes returns endpoints added by addRoutes described in the
+// OpenAPI document served at /openapi.json. Update it with addRoutes.
+func (a *App) openAPIRoutes() []openapi.Route {
+	routes := []openapi.Route{
+		{
+			Method:    "POST",
+			Path:      "/create-keypair",
+			Summary:   "Generates a random key pair",
+			Responses: map[int][]interface{}{200: {handlers.KeyPair{}}},
+		},
+		{
+			Method:    "POST",
+			Path:      "/builder",
+			Summary:   "Builds and signs a transaction",
+			Body:      protocolsbridge.BuilderRequest{},
+			Responses: map[int][]interface{}{200: {protocolsbridge.BuilderResponse{}}},
+		},
+		{
+			Method:  "POST",
+			Path:    "/payment",
+			Summary: "Sends a payment",
+			Form:    protocolsbridge.PaymentRequest{},
+			Responses: map[int][]interface{}{
+				// Dry run response is returned for `dry_run=true` requests
+				200: {horizon.SubmitTransactionResponse{}, protocolsbridge.PaymentDryRunResponse{}},
+				202: {protocolsbridge.HeldPaymentResponse{}},
+			},
+		},
+		{
+			Method:  "GET",
+			Path:    "/federation/resolve",
+			Summary: "Resolves a Stellar address",
+			Query: []openapi.Parameter{
+				{Name: "address", Description: "Stellar address, ex. bob*stellar.org", Required: true},
+			},
+			Responses: map[int][]interface{}{200: {protocolsbridge.FederationResolveResponse{}}},
+		},
+		{
+			Method:    "GET",
+			Path:      "/healthz",
+			Summary:   "Liveness check",
+			Responses: map[int][]interface{}{200: {health.Response{}}},
+		},
+		{
+			Method:    "GET",
+			Path:      "/readyz",
+			Summary:   "Readiness check of dependencies",
+			Responses: map[int][]interface{}{200: {health.Response{}}},
+		},
+	}
+
+	if a.config.Accounts.AuthorizingSeed != "" {
+		routes = append(routes, openapi.Route{
+			Method:    "POST",
+			Path:      "/authorize",
+			Summary:   "Authorizes an account to hold an asset",
+			Form:      protocolsbridge.AuthorizeRequest{},
+			Responses: map[int][]interface{}{200: {horizon.SubmitTransactionResponse{}}},
+		})
+	}
+
+	if a.config.Accounts.FunderSeed != "" {
+		routes = append(routes, openapi.Route{
+			Method:    "POST",
+			Path:      "/create-account",
+			Summary:   "Creates and funds an account",
+			Body:      protocolsbridge.CreateAccountRequest{},
+			Responses: map[int][]interface{}{200: {protocolsbridge.CreateAccountResponse{}}},
+		})
+	}
+
+	if a.config.HoldsPayments() {
+		routes = append(routes, openapi.Route{
+			Method:    "DELETE",
+			Path:      "/payments/{id}",
+			Summary:   "Cancels a held payment",
+			Responses: map[int][]interface{}{200: {protocolsbridge.HeldPaymentResponse{}}},
+		})
+	}
+
+	if a.config.Database.Type != "" {
+		routes = append(routes,
+			openapi.Route{
+				Method:    "POST",
+				Path:      "/payments/{id}/refund",
+				Summary:   "Refunds a received payment to its sender",
+				Form:      protocolsbridge.RefundRequest{},
+				Responses: map[int][]interface{}{200: {handlers.RefundResponse{}}},
+			},
+			openapi.Route{
+				Method:    "GET",
+				Path:      "/payments/{id}/refund",
+				Summary:   "Returns the refund of a received payment",
+				Responses: map[int][]interface{}{200: {handlers.RefundResponse{}}},
+			},
+			openapi.Route{
+				Method:    "POST",
+				Path:      "/offline-transactions",
+				Summary:   "Builds and stores an unsigned transaction signed offline",
+				Body:      protocolsbridge.OfflineTransactionRequest{},
+				Responses: map[int][]interface{}{200: {handlers.OfflineTransactionResponse{}}},
+			},
+			openapi.Route{
+				Method:    "GET",
+				Path:      "/offline-transactions/{id}",
+				Summary:   "Returns an offline transaction",
+				Responses: map[int][]interface{}{200: {handlers.OfflineTransactionResponse{}}},
+			},
+			openapi.Route{
+				Method:    "POST",
+				Path:      "/offline-transactions/{id}/submit",
+				Summary:   "Submits a signed offline transaction",
+				Body:      protocolsbridge.OfflineTransactionSubmitRequest{},
+				Responses: map[int][]interface{}{200: {horizon.SubmitTransactionResponse{}}},
+			},
+			openapi.Route{
+				Method:    "POST",
+				Path:      "/quote",
+				Summary:   "Quotes a cross-asset payment using Horizon path finding",
+				Form:      protocolsbridge.QuoteRequest{},
+				Responses: map[int][]interface{}{200: {handlers.QuoteResponse{}}},
+			},
+		)
+	}
+
+	if a.config.ExpectedPayments.Enabled {
+		routes = append(routes,
+			openapi.Route{
+				Method:    "POST",
+				Path:      "/expected-payments",
+				Summary:   "Registers an expected incoming payment",
+				Form:      protocolsbridge.ExpectedPaymentRequest{},
+				Responses: map[int][]interface{}{200: {handlers.ExpectedPaymentResponse{}}},
+			},
+			openapi.Route{
+				Method:    "GET",
+				Path:      "/expected-payments/{id}",
+				Summary:   "Returns an expected payment and payments matched with it",
+				Responses: map[int][]interface{}{200: {handlers.ExpectedPaymentResponse{}}},
+			},
+			openapi.Route{
+				Method:    "DELETE",
+				Path:      "/expected-payments/{id}",
+				Summary:   "Cancels an expected payment",
+				Responses: map[int][]interface{}{200: {entities.ExpectedPayment{}}},
+			},
+		)
+	}
+
+	if a.config.SEP24.Enabled {
+		routes = append(routes,
+			openapi.Route{
+				Method:    "POST",
+				Path:      "/sep24/transactions",
+				Summary:   "Creates a SEP-24 deposit or withdrawal",
+				Form:      protocolsbridge.SEP24TransactionRequest{},
+				Responses: map[int][]interface{}{200: {handlers.SEP24TransactionResponse{}}},
+			},
+			openapi.Route{
+				Method:  "GET",
+				Path:    "/sep24/transactions",
+				Summary: "Returns SEP-24 transactions, the most recently started first",
+				Query: []openapi.Parameter{
+					{Name: "account", Description: "Stellar account of the user"},
+					{Name: "asset_code", Description: "Code of the asset"},
+					{Name: "kind", Description: "`deposit` or `withdrawal`"},
+					{Name: "no_older_than", Description: "Returns transactions started at or after this time (RFC 3339)"},
+					{Name: "limit", Description: "Maximum number of transactions, 10 by default"},
+					{Name: "paging_id", Description: "Returns transactions started before the transaction with this ID"},
+				},
+				Responses: map[int][]interface{}{200: {handlers.SEP24TransactionsResponse{}}},
+			},
+			openapi.Route{
+				Method:    "POST",
+				Path:      "/sep24/transactions/{id}",
+				Summary:   "Updates status and details of a SEP-24 transaction",
+				Form:      protocolsbridge.SEP24UpdateRequest{},
+				Responses: map[int][]interface{}{200: {handlers.SEP24TransactionResponse{}}},
+			},
+			openapi.Route{
+				Method:  "GET",
+				Path:    "/sep24/transaction",
+				Summary: "Returns a SEP-24 transaction",
+				Query: []openapi.Parameter{
+					{Name: "id", Description: "ID of the transaction"},
+					{Name: "stellar_transaction_id", Description: "Hash of the Stellar transaction of the transfer"},
+					{Name: "external_transaction_id", Description: "ID of the transfer outside of Stellar"},
+				},
+				Responses: map[int][]interface{}{200: {handlers.SEP24TransactionResponse{}}},
+			},
+		)
+	}
+
+	if a.config.Stream.Enabled {
+		routes = append(routes, openapi.Route{
+			Method:  "GET",
+			Path:    "/stream/payments",
+			Summary: "Streams received payments over SSE (`Accept: text/event-stream`) or WebSocket",
+			Query: []openapi.Parameter{
+				{Name: "cursor", Description: "ID of the last received event, `now` (default) or `0` to replay all events"},
+			},
+			// WebSocket messages, SSE events contain the payment only
+			Responses: map[int][]interface{}{200: {stream.Message{}}},
+		})
+	}
+
+	return routes
+}
@@ -0,0 +1,91 @@
+package bridge
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/submitter"
+)
+
+// startDrain is called when the server receives SIGTERM (or SIGINT), before
+// it stops accepting requests. Requests in progress and the work drained by
+// shutdown must finish before shutdown.drain_timeout passes.
+func (a *App) startDrain() {
+	timeout := a.config.Shutdown.DrainTimeoutDuration()
+	a.drainDeadline = time.Now().Add(timeout)
+	log.WithFields(log.Fields{"drain_timeout": timeout.String()}).Info("Stopping server, draining requests in progress")
+}
+
+// shutdown finishes work in progress after the server stopped accepting
+// requests and requests in progress finished: transactions waiting in the
+// submission queue are submitted, the payment being processed by the listener
+// is saved (with the cursor of the stream), the leader lease is released,
+// webhooks and security events are delivered and DB pools are closed.
+func (a *App) shutdown() {
+	ctx, cancel := context.WithDeadline(context.Background(), a.drainDeadline)
+	defer cancel()
+
+	rh := &a.requestHandler
+	transactionSubmitter := rh.TransactionSubmitter
+	if leaderOnly, ok := transactionSubmitter.(*submitter.LeaderOnly); ok {
+		transactionSubmitter = leaderOnly.Submitter
+	}
+	if pool, ok := transactionSubmitter.(*submitter.Pool); ok {
+		err := waitContext(ctx, pool.Stop)
+		if err != nil {
+			log.Error("Drain timeout passed before queued transactions were submitted")
+		}
+	}
+
+	if a.listening {
+		err := rh.PaymentListener.Shutdown(ctx)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error stopping payment listener")
+		}
+	}
+
+	// Other replicas take over after the payment being processed is saved
+	// and queued transactions are submitted
+	err := rh.Leader.Resign(ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Warn("Error releasing leader lease")
+	}
+
+	err = waitContext(ctx, rh.Webhooks.Wait)
+	if err != nil {
+		log.Warn("Drain timeout passed before webhooks were delivered")
+	}
+
+	if rh.Audit != nil {
+		_, err = rh.Audit.Deliver(ctx)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Warn("Error delivering security events")
+		}
+	}
+
+	if rh.Driver != nil {
+		rh.Driver.DB().Close()
+	}
+	for _, driver := range a.networkDrivers {
+		driver.DB().Close()
+	}
+	log.Info("Server stopped")
+}
+
+// waitContext calls wait and returns nil when it returns before ctx is done,
+// ctx error otherwise
+func waitContext(ctx context.Context, wait func()) error {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
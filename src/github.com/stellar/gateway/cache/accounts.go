@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"github.com/stellar/gateway/horizon"
+)
+
+// AccountLoader loads accounts from Horizon
+type AccountLoader interface {
+	LoadAccount(accountID string) (horizon.AccountResponse, error)
+}
+
+// AccountResolver caches destination accounts by account ID, so repeated
+// payments to the same destinations don't load them from Horizon every time.
+// Only existing accounts are cached: a missing account can be created at any
+// time. Cached accounts must not be used for sequence numbers or signers.
+type AccountResolver struct {
+	Loader AccountLoader
+	cache  *LRU
+}
+
+// NewAccountResolver creates a new AccountResolver
+func NewAccountResolver(loader AccountLoader, cache *LRU) *AccountResolver {
+	return &AccountResolver{Loader: loader, cache: cache}
+}
+
+// LoadAccount returns a cached account or loads it using Loader
+func (r *AccountResolver) LoadAccount(accountID string) (horizon.AccountResponse, error) {
+	if value, _, ok := r.cache.Get(accountID); ok {
+		return value.(horizon.AccountResponse), nil
+	}
+
+	account, err := r.Loader.LoadAccount(accountID)
+	if err == nil {
+		r.cache.Add(accountID, account, nil)
+	}
+	return account, err
+}
+
+// Invalidate drops a cached account, ex. when a payment to it failed because
+// the account was merged or its trustline removed
+func (r *AccountResolver) Invalidate(accountID string) {
+	r.cache.Remove(accountID)
+}
+
+// Flush implements Flusher
+func (r *AccountResolver) Flush() {
+	r.cache.Flush()
+}
+
+var _ AccountLoader = &AccountResolver{}
+var _ Flusher = &AccountResolver{}
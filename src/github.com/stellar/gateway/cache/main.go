@@ -0,0 +1,126 @@
+// Package cache contains an LRU cache with expiring entries and caching
+// wrappers of stellar.toml and federation clients, so repeated payments to the
+// same domain don't fetch stellar.toml and query federation server every time.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+)
+
+// LRU is a thread-safe least-recently-used cache. Successful results expire
+// after TTL, errors (negative results) after NegativeTTL. Errors are not
+// cached when NegativeTTL is 0.
+type LRU struct {
+	Size        int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type entry struct {
+	key       string
+	value     interface{}
+	err       error
+	expiresAt time.Time
+}
+
+// NewLRU creates a new LRU
+func NewLRU(size int, ttl, negativeTTL time.Duration) *LRU {
+	return &LRU{
+		Size:        size,
+		TTL:         ttl,
+		NegativeTTL: negativeTTL,
+		entries:     map[string]*list.Element{},
+		order:       list.New(),
+		now:         clock.Now,
+	}
+}
+
+// Get returns a cached value and error. ok is false when key is not found or
+// has expired.
+func (c *LRU) Get(key string) (value interface{}, err error, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		return nil, nil, false
+	}
+
+	e := element.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(element)
+		return nil, nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return e.value, e.err, true
+}
+
+// Add caches a value or an error under a given key. The least recently used
+// entry is evicted when the cache is full.
+func (c *LRU) Add(key string, value interface{}, err error) {
+	ttl := c.TTL
+	if err != nil {
+		ttl = c.NegativeTTL
+	}
+
+	if ttl <= 0 || c.Size <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e := &entry{key: key, value: value, err: err, expiresAt: c.now().Add(ttl)}
+
+	if element, found := c.entries[key]; found {
+		element.Value = e
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(e)
+
+	for c.order.Len() > c.Size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Remove removes an entry
+func (c *LRU) Remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, found := c.entries[key]; found {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of cached entries (including expired ones)
+func (c *LRU) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// Flush removes all entries
+func (c *LRU) Flush() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+func (c *LRU) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry).key)
+}
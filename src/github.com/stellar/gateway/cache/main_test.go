@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := NewLRU(2, time.Minute, time.Second)
+	c.now = func() time.Time { return now }
+
+	c.Add("a", 1, nil)
+	c.Add("b", 2, nil)
+
+	value, err, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	// "b" is the least recently used
+	c.Add("c", 3, nil)
+	_, _, ok = c.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Len())
+
+	c.Add("d", nil, errors.New("not found"))
+	_, err, ok = c.Get("d")
+	assert.True(t, ok)
+	assert.EqualError(t, err, "not found")
+
+	now = now.Add(2 * time.Second)
+	_, _, ok = c.Get("d")
+	assert.False(t, ok)
+	_, _, ok = c.Get("c")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, _, ok = c.Get("c")
+	assert.False(t, ok)
+
+	c.Add("e", 5, nil)
+	c.Flush()
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRUWithoutNegativeTTL(t *testing.T) {
+	c := NewLRU(10, time.Minute, 0)
+	c.Add("a", nil, errors.New("not found"))
+	_, _, ok := c.Get("a")
+	assert.False(t, ok)
+}
+
+func TestStellarTomlResolver(t *testing.T) {
+	client := &stellartoml.MockClient{}
+	client.On("GetStellarToml", "example.com").
+		Return(&stellartoml.Response{FederationServer: "https://example.com/federation"}, nil).Once()
+	client.On("GetStellarToml", "missing.com").
+		Return((*stellartoml.Response)(nil), errors.New("http request failed with non-200 status code")).Once()
+
+	resolver := NewStellarTomlResolver(client, NewLRU(10, time.Minute, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		response, err := resolver.GetStellarTomlByAddress("alice*example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/federation", response.FederationServer)
+
+		_, err = resolver.GetStellarToml("missing.com")
+		assert.Error(t, err)
+	}
+	client.AssertExpectations(t)
+
+	resolver.Flush()
+	client.On("GetStellarToml", "example.com").Return(&stellartoml.Response{}, nil).Once()
+	response, err := resolver.GetStellarToml("example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "", response.FederationServer)
+	client.AssertExpectations(t)
+	client.AssertNumberOfCalls(t, "GetStellarToml", 3)
+}
+
+type accountLoader struct {
+	calls map[string]int
+}
+
+func (l *accountLoader) LoadAccount(accountID string) (horizon.AccountResponse, error) {
+	l.calls[accountID]++
+	if accountID == "missing" {
+		return horizon.AccountResponse{}, errors.New("StatusCode indicates error")
+	}
+	return horizon.AccountResponse{AccountID: accountID}, nil
+}
+
+func TestAccountResolver(t *testing.T) {
+	loader := &accountLoader{calls: map[string]int{}}
+	resolver := NewAccountResolver(loader, NewLRU(10, time.Minute, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		account, err := resolver.LoadAccount("GABC")
+		assert.NoError(t, err)
+		assert.Equal(t, "GABC", account.AccountID)
+
+		// Missing accounts are not cached even when negative TTL is set
+		_, err = resolver.LoadAccount("missing")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 1, loader.calls["GABC"])
+	assert.Equal(t, 2, loader.calls["missing"])
+
+	resolver.Invalidate("GABC")
+	_, err := resolver.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, loader.calls["GABC"])
+
+	resolver.Flush()
+	_, err = resolver.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, loader.calls["GABC"])
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"net/url"
+
+	"github.com/stellar/gateway/external"
+	"github.com/stellar/go/address"
+	"github.com/stellar/go/clients/federation"
+	"github.com/stellar/go/clients/stellartoml"
+	proto "github.com/stellar/go/protocols/federation"
+	"github.com/stellar/go/support/errors"
+)
+
+// Flusher is implemented by resolvers that can drop their cached responses
+type Flusher interface {
+	Flush()
+}
+
+// CachedNameResolver is implemented by resolvers reporting whether a federation
+// response has been served from cache
+type CachedNameResolver interface {
+	LookupByAddressCached(addy string) (response *proto.NameResponse, cached bool, err error)
+}
+
+// StellarTomlResolver caches stellar.toml files by domain
+type StellarTomlResolver struct {
+	Client external.StellarTomlClientInterface
+	cache  *LRU
+}
+
+// NewStellarTomlResolver creates a new StellarTomlResolver
+func NewStellarTomlResolver(client external.StellarTomlClientInterface, cache *LRU) *StellarTomlResolver {
+	return &StellarTomlResolver{Client: client, cache: cache}
+}
+
+// GetStellarToml returns stellar.toml file for a given domain
+func (r *StellarTomlResolver) GetStellarToml(domain string) (*stellartoml.Response, error) {
+	if value, err, ok := r.cache.Get(domain); ok {
+		response, _ := value.(*stellartoml.Response)
+		return response, err
+	}
+
+	response, err := r.Client.GetStellarToml(domain)
+	r.cache.Add(domain, response, err)
+	return response, err
+}
+
+// GetStellarTomlByAddress returns stellar.toml file of a domain of a given address
+func (r *StellarTomlResolver) GetStellarTomlByAddress(addy string) (*stellartoml.Response, error) {
+	_, domain, err := address.Split(addy)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse address failed")
+	}
+
+	return r.GetStellarToml(domain)
+}
+
+// Flush implements Flusher
+func (r *StellarTomlResolver) Flush() {
+	r.cache.Flush()
+}
+
+// FederationResolver caches federation responses by address and account ID.
+// Forwarded requests are not cached.
+type FederationResolver struct {
+	Client federation.ClientInterface
+	cache  *LRU
+}
+
+// NewFederationResolver creates a new FederationResolver
+func NewFederationResolver(client federation.ClientInterface, cache *LRU) *FederationResolver {
+	return &FederationResolver{Client: client, cache: cache}
+}
+
+// LookupByAddress returns account ID and memo of a given stellar address
+func (r *FederationResolver) LookupByAddress(addy string) (*proto.NameResponse, error) {
+	response, _, err := r.LookupByAddressCached(addy)
+	return response, err
+}
+
+// LookupByAddressCached implements CachedNameResolver
+func (r *FederationResolver) LookupByAddressCached(addy string) (*proto.NameResponse, bool, error) {
+	key := "name:" + addy
+	if value, err, ok := r.cache.Get(key); ok {
+		response, _ := value.(*proto.NameResponse)
+		return response, true, err
+	}
+
+	response, err := r.Client.LookupByAddress(addy)
+	r.cache.Add(key, response, err)
+	return response, false, err
+}
+
+// LookupByAccountID returns stellar address of a given account ID
+func (r *FederationResolver) LookupByAccountID(aid string) (*proto.IDResponse, error) {
+	key := "id:" + aid
+	if value, err, ok := r.cache.Get(key); ok {
+		response, _ := value.(*proto.IDResponse)
+		return response, err
+	}
+
+	response, err := r.Client.LookupByAccountID(aid)
+	r.cache.Add(key, response, err)
+	return response, err
+}
+
+// ForwardRequest sends a forward request to federation server of a given domain
+func (r *FederationResolver) ForwardRequest(domain string, fields url.Values) (*proto.NameResponse, error) {
+	return r.Client.ForwardRequest(domain, fields)
+}
+
+// Flush implements Flusher
+func (r *FederationResolver) Flush() {
+	r.cache.Flush()
+}
+
+var _ external.StellarTomlClientInterface = &StellarTomlResolver{}
+var _ federation.StellarTOML = &StellarTomlResolver{}
+var _ federation.ClientInterface = &FederationResolver{}
+var _ Flusher = &StellarTomlResolver{}
+var _ Flusher = &FederationResolver{}
+var _ CachedNameResolver = &FederationResolver{}
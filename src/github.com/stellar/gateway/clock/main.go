@@ -0,0 +1,166 @@
+// Package clock centralizes timestamping. Timestamps saved in the database,
+// sent in callbacks and used in transactions are taken from Default, so it can
+// be replaced in a single place. Deadlines and durations of requests are still
+// measured with time.Now.
+//
+// DriftMonitor compares the system clock with close times of ledgers ingested
+// by Horizon. Time bounds of transactions are computed from the local clock,
+// so a skewed host builds transactions that are already expired (or not valid
+// yet) when they reach the network. Tight time bounds are not applied while
+// drift is detected.
+package clock
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/metrics"
+)
+
+// Clock returns the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// Func adapts a function to Clock
+type Func func() time.Time
+
+// Now implements Clock
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// System is the system clock
+var System Clock = Func(time.Now)
+
+// Default is the clock used by Now
+var Default = System
+
+// Now returns the current time of Default clock. Components accepting a
+// `now func() time.Time` are given this function by the apps.
+func Now() time.Time {
+	return Default.Now()
+}
+
+// LedgerLoader loads the close time of the last ledger, implemented by
+// horizon.Horizon
+type LedgerLoader interface {
+	LoadLatestLedgerClosedAt() (time.Time, error)
+}
+
+// DefaultMaxDrift is the default max difference between the local clock and
+// the close time of the last ledger. Ledgers close every 5-6 seconds, so it
+// must be much larger than that.
+const DefaultMaxDrift = 30 * time.Second
+
+var driftMetrics = struct {
+	drift   *metrics.Gauge
+	drifted *metrics.Gauge
+}{
+	drift:   metrics.NewGauge("bridge_clock_drift_seconds", "Difference between the local clock and the close time of the last ledger in seconds."),
+	drifted: metrics.NewGauge("bridge_clock_drifted", "1 when the local clock drifted more than the max drift, 0 otherwise."),
+}
+
+// DriftState is the clock drift observed by DriftMonitor
+type DriftState struct {
+	// Drift is the local time minus the close time of the last ledger. It is
+	// negative when the local clock is behind the network.
+	Drift          time.Duration
+	Drifted        bool
+	LedgerClosedAt time.Time
+	UpdatedAt      time.Time
+}
+
+// DriftMonitor periodically compares the clock with the close time of the
+// last ledger. Methods of a nil *DriftMonitor return values used when drift
+// detection is disabled.
+type DriftMonitor struct {
+	loader   LedgerLoader
+	clock    Clock
+	maxDrift time.Duration
+	mutex    sync.RWMutex
+	state    DriftState
+	log      *logrus.Entry
+}
+
+// NewDriftMonitor creates a new DriftMonitor of clock. Call Start to compare
+// clocks periodically.
+func NewDriftMonitor(loader LedgerLoader, clock Clock, maxDrift time.Duration) *DriftMonitor {
+	return &DriftMonitor{
+		loader:   loader,
+		clock:    clock,
+		maxDrift: maxDrift,
+		log:      logrus.WithFields(logrus.Fields{"service": "DriftMonitor"}),
+	}
+}
+
+// Start compares clocks every interval
+func (m *DriftMonitor) Start(interval time.Duration) {
+	go func() {
+		m.Update()
+		for range time.Tick(interval) {
+			m.Update()
+		}
+	}()
+}
+
+// Update loads the last ledger and updates the state. The previous state is
+// kept when the ledger cannot be loaded.
+func (m *DriftMonitor) Update() error {
+	closedAt, err := m.loader.LoadLatestLedgerClosedAt()
+	if err != nil {
+		m.log.WithFields(logrus.Fields{"err": err}).Error("Error loading the last ledger")
+		return err
+	}
+
+	now := m.clock.Now()
+	drift := now.Sub(closedAt)
+	state := DriftState{
+		Drift:          drift,
+		Drifted:        math.Abs(float64(drift)) > float64(m.maxDrift),
+		LedgerClosedAt: closedAt,
+		UpdatedAt:      now,
+	}
+
+	m.mutex.Lock()
+	previous := m.state
+	m.state = state
+	m.mutex.Unlock()
+
+	fields := logrus.Fields{
+		"drift":            drift.String(),
+		"ledger_closed_at": closedAt,
+	}
+	if state.Drifted && !previous.Drifted {
+		m.log.WithFields(fields).Warn("Clock drift detected, time bounds will not be applied")
+	} else if !state.Drifted && previous.Drifted {
+		m.log.WithFields(fields).Info("Clock drift resolved")
+	}
+
+	if state.Drifted {
+		driftMetrics.drifted.Set(1)
+	} else {
+		driftMetrics.drifted.Set(0)
+	}
+	driftMetrics.drift.Set(drift.Seconds())
+	return nil
+}
+
+// State returns the last observed drift
+func (m *DriftMonitor) State() DriftState {
+	if m == nil {
+		return DriftState{}
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.state
+}
+
+// Drifted returns true when the clock drifted more than the max drift during
+// the last check
+func (m *DriftMonitor) Drifted() bool {
+	return m.State().Drifted
+}
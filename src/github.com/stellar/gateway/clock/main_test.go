@@ -0,0 +1,66 @@
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLoader struct {
+	closedAt time.Time
+	err      error
+}
+
+func (l *testLoader) LoadLatestLedgerClosedAt() (time.Time, error) {
+	return l.closedAt, l.err
+}
+
+func TestDriftMonitor(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	loader := &testLoader{closedAt: now.Add(-4 * time.Second)}
+	monitor := NewDriftMonitor(loader, Func(func() time.Time { return now }), DefaultMaxDrift)
+
+	// Not checked yet
+	assert.False(t, monitor.Drifted())
+
+	require.NoError(t, monitor.Update())
+	assert.False(t, monitor.Drifted())
+	assert.Equal(t, 4*time.Second, monitor.State().Drift)
+
+	// Local clock behind the network
+	loader.closedAt = now.Add(2 * time.Minute)
+	require.NoError(t, monitor.Update())
+	assert.True(t, monitor.Drifted())
+	assert.Equal(t, -2*time.Minute, monitor.State().Drift)
+
+	// Previous state is kept when the ledger cannot be loaded
+	loader.err = errors.New("connection refused")
+	assert.Error(t, monitor.Update())
+	assert.True(t, monitor.Drifted())
+
+	// Local clock ahead of the network
+	loader.err = nil
+	loader.closedAt = now.Add(-time.Minute)
+	require.NoError(t, monitor.Update())
+	assert.True(t, monitor.Drifted())
+
+	loader.closedAt = now.Add(-10 * time.Second)
+	require.NoError(t, monitor.Update())
+	assert.False(t, monitor.Drifted())
+	assert.Equal(t, loader.closedAt, monitor.State().LedgerClosedAt)
+	assert.Equal(t, now, monitor.State().UpdatedAt)
+
+	var disabled *DriftMonitor
+	assert.False(t, disabled.Drifted())
+}
+
+func TestNow(t *testing.T) {
+	fixed := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	Default = Func(func() time.Time { return fixed })
+	defer func() { Default = System }()
+
+	assert.Equal(t, fixed, Now())
+}
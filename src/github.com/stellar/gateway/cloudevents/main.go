@@ -0,0 +1,140 @@
+// Package cloudevents formats events emitted by the bridge server (webhooks,
+// receive callbacks and transaction status events published to message
+// buses) as CloudEvents 1.0 in structured JSON mode. Data of every event
+// follows a schema version negotiated per subscription, so consumers can
+// upgrade independently of the bridge server.
+package cloudevents
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+)
+
+const (
+	// SpecVersion is the version of CloudEvents specification
+	SpecVersion = "1.0"
+	// ContentType is the media type of events in structured JSON mode
+	ContentType = "application/cloudevents+json"
+	// TypePrefix is prepended to event types of the bridge server, ex.
+	// org.stellar.bridge.payment_received
+	TypePrefix = "org.stellar.bridge."
+	// DefaultSource is the source of events when `cloudevents.source` is not
+	// configured
+	DefaultSource = "stellar-bridge"
+)
+
+// Formats of emitted events
+const (
+	// FormatJSON is the JSON format used before CloudEvents support
+	FormatJSON = "json"
+	// FormatCloudEvents is CloudEvents 1.0 structured JSON format
+	FormatCloudEvents = "cloudevents"
+)
+
+// SchemaVersions are versions of data schemas of events, oldest first. A new
+// version is added when data of any event changes in a backwards incompatible
+// way, older versions are kept so existing consumers are not affected.
+var SchemaVersions = []string{"1"}
+
+// LatestSchemaVersion returns the newest schema version
+func LatestSchemaVersion() string {
+	return SchemaVersions[len(SchemaVersions)-1]
+}
+
+// IsValidFormat returns true when format is a known format, empty format is
+// FormatJSON
+func IsValidFormat(format string) bool {
+	return format == "" || format == FormatJSON || format == FormatCloudEvents
+}
+
+// Negotiate returns the newest schema version of a comma separated list of
+// versions accepted by a consumer, the latest version when accepted is empty.
+// ok is false when none of the accepted versions is supported.
+func Negotiate(accepted string) (version string, ok bool) {
+	if strings.TrimSpace(accepted) == "" {
+		return LatestSchemaVersion(), true
+	}
+
+	best := -1
+	for _, value := range strings.Split(accepted, ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "v")
+		for i, supported := range SchemaVersions {
+			if value == supported && i > best {
+				best = i
+			}
+		}
+	}
+
+	if best < 0 {
+		return "", false
+	}
+	return SchemaVersions[best], true
+}
+
+// Event is a CloudEvent in structured JSON mode. SchemaVersion is an
+// extension attribute with the version of the data schema.
+type Event struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	SchemaVersion   string      `json:"schemaversion"`
+	Data            interface{} `json:"data"`
+}
+
+// Formatter formats events with Source and SchemaVersion. A nil *Formatter
+// keeps the JSON format, so it can be used when CloudEvents are not
+// configured.
+type Formatter struct {
+	Source        string
+	SchemaVersion string
+}
+
+// NewFormatter creates a Formatter of CloudEvents with source using
+// schemaVersion, the default source and latest version when they are empty
+func NewFormatter(source, schemaVersion string) *Formatter {
+	if source == "" {
+		source = DefaultSource
+	}
+	if schemaVersion == "" {
+		schemaVersion = LatestSchemaVersion()
+	}
+	return &Formatter{Source: source, SchemaVersion: schemaVersion}
+}
+
+// Event returns a CloudEvent of eventType with data. id must be unique for
+// every distinct event of Source, deliveries of the same event reuse it.
+func (f *Formatter) Event(eventType, id string, data interface{}) Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          f.Source,
+		Type:            TypePrefix + eventType,
+		Time:            clock.Now(),
+		DataContentType: "application/json",
+		SchemaVersion:   f.SchemaVersion,
+		Data:            data,
+	}
+}
+
+// Marshal encodes data as a CloudEvent of eventType or returns JSON encoded
+// data when f is nil
+func (f *Formatter) Marshal(eventType, id string, data interface{}) ([]byte, error) {
+	if f == nil {
+		return json.Marshal(data)
+	}
+	return json.Marshal(f.Event(eventType, id, data))
+}
+
+// ContentType returns ContentType or application/json when f is nil
+func (f *Formatter) ContentType() string {
+	if f == nil {
+		return "application/json"
+	}
+	return ContentType
+}
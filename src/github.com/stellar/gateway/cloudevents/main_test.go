@@ -0,0 +1,48 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	version, ok := Negotiate("")
+	assert.True(t, ok)
+	assert.Equal(t, LatestSchemaVersion(), version)
+
+	version, ok = Negotiate("v1, 7")
+	assert.True(t, ok)
+	assert.Equal(t, "1", version)
+
+	_, ok = Negotiate("0,7")
+	assert.False(t, ok)
+}
+
+func TestFormatter(t *testing.T) {
+	data := map[string]string{"id": "1"}
+
+	var formatter *Formatter
+	body, err := formatter.Marshal("payment_received", "1", data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "1"}`, string(body))
+	assert.Equal(t, "application/json", formatter.ContentType())
+
+	formatter = NewFormatter("", "")
+	body, err = formatter.Marshal("payment_received", "1", data)
+	require.NoError(t, err)
+
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, "1.0", event["specversion"])
+	assert.Equal(t, "1", event["id"])
+	assert.Equal(t, DefaultSource, event["source"])
+	assert.Equal(t, "org.stellar.bridge.payment_received", event["type"])
+	assert.Equal(t, "application/json", event["datacontenttype"])
+	assert.Equal(t, LatestSchemaVersion(), event["schemaversion"])
+	assert.Equal(t, map[string]interface{}{"id": "1"}, event["data"])
+	assert.NotEmpty(t, event["time"])
+	assert.Equal(t, ContentType, formatter.ContentType())
+}
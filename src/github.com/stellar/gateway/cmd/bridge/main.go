@@ -2,17 +2,26 @@ package main
 
 import (
 	log "github.com/sirupsen/logrus"
+	"os"
 	"runtime"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"github.com/stellar/gateway/bridge"
 	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/configcheck"
+	"github.com/stellar/gateway/configsource"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/migratecmd"
+	"github.com/stellar/gateway/profiles/profilescmd"
+	"github.com/stellar/gateway/secrets"
 )
 
+const defaultConfigFile = "bridge.cfg"
+
 var app *bridge.App
 var rootCmd *cobra.Command
 var migrateFlag bool
+var checkConfigFlag bool
 var configFile string
 var versionFlag bool
 var version = "N/A"
@@ -30,38 +39,125 @@ func init() {
 		Run:   run,
 	}
 
-	rootCmd.Flags().BoolVarP(&migrateFlag, "migrate-db", "", false, "migrate DB to the newest schema version")
-	rootCmd.Flags().StringVarP(&configFile, "config", "c", "bridge.cfg", "path to config file")
+	rootCmd.Flags().BoolVarP(&migrateFlag, "migrate-db", "", false, "migrate DB to the newest schema version (same as `migrate up`)")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", defaultConfigFile, "path to config file (TOML or YAML)")
+	rootCmd.Flags().BoolVarP(&checkConfigFlag, "check-config", "", false, "check config file and exit (non-zero status when invalid)")
 	rootCmd.Flags().BoolVarP(&versionFlag, "version", "v", false, "displays bridge server version")
+
+	rootCmd.AddCommand(migratecmd.NewCommand(bridge.MigrationsComponent, func() (db.Driver, error) {
+		return bridge.NewDriver(loadConfig())
+	}))
+	rootCmd.AddCommand(profilescmd.NewCommand(func() (string, string) {
+		config := loadConfig()
+		return config.Profiles.Directory, config.Profiles.URL
+	}))
 }
 
-func run(cmd *cobra.Command, args []string) {
-	viper.SetConfigFile(configFile)
-	viper.SetConfigType("toml")
-	err := viper.ReadInConfig()
+func loadConfig() (config config.Config) {
+	config, err := readConfig()
 	if err != nil {
-		log.Fatal("Error reading "+configFile+" file: ", err)
+		log.Fatal(err.Error())
+		return
+	}
+
+	if config.LogFormat == "json" {
+		log.SetFormatter(&log.JSONFormatter{})
 	}
+	log.SetLevel(config.Reloadable().LogLevelOrDefault())
+	return
+}
 
-	var config config.Config
-	err = viper.Unmarshal(&config)
+// configSource returns the source of config params: configFile and
+// BRIDGE_* environment variables. When the default config file does not
+// exist params are read from environment variables only.
+func configSource() configsource.Source {
+	return configsource.Source{
+		Path:      configFile,
+		Optional:  configFile == defaultConfigFile,
+		EnvPrefix: "BRIDGE",
+	}
+}
 
-	err = config.Validate()
+// readConfig reads and validates the config. A new viper instance is
+// used every time, so values set by a previous read are not kept when the
+// config is reloaded.
+func readConfig() (config config.Config, err error) {
+	v, err := configSource().Read(&config)
 	if err != nil {
-		log.Fatal(err.Error())
 		return
 	}
 
-	if config.LogFormat == "json" {
-		log.SetFormatter(&log.JSONFormatter{})
+	// horizon can be a single URL or a list of URLs
+	if horizonURL, ok := v.Get("horizon").(string); ok {
+		v.Set("horizon", []string{horizonURL})
+	}
+
+	// horizon of networks can be a single URL too. TOML arrays of tables are
+	// []map[string]interface{}, YAML sequences are []interface{}.
+	var networks []map[string]interface{}
+	switch value := v.Get("networks").(type) {
+	case []map[string]interface{}:
+		networks = value
+	case []interface{}:
+		for _, network := range value {
+			if network, ok := network.(map[string]interface{}); ok {
+				networks = append(networks, network)
+			}
+		}
+	}
+	for _, network := range networks {
+		if horizonURL, ok := network["horizon"].(string); ok {
+			network["horizon"] = []string{horizonURL}
+		}
+	}
+
+	// callback URLs can be a single URL or a list of URLs. The group is set
+	// as a whole, viper doesn't merge nested keys.
+	if callbacks, ok := v.Get("callbacks").(map[string]interface{}); ok {
+		for _, key := range []string{"receive", "error"} {
+			if callbackURL, ok := callbacks[key].(string); ok {
+				callbacks[key] = []string{callbackURL}
+			}
+		}
+		v.Set("callbacks", callbacks)
+	}
+
+	// Secret params can be loaded from env variables, files and Vault
+	err = secrets.NewLoader("BRIDGE").Load(v, &config)
+	if err != nil {
+		return
+	}
+
+	err = v.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	err = config.Validate()
+	return
+}
+
+func run(cmd *cobra.Command, args []string) {
+	if checkConfigFlag {
+		path := configFile
+		if !configSource().FileExists() {
+			// Config read from environment variables only
+			path = ""
+		}
+		os.Exit(configcheck.Run(path, config.Config{}, func() error {
+			_, err := readConfig()
+			return err
+		}, os.Stdout))
 	}
 
-	app, err = bridge.NewApp(config, migrateFlag, versionFlag, version)
+	var err error
+	app, err = bridge.NewApp(loadConfig(), migrateFlag, versionFlag, version)
 
 	if err != nil {
 		log.Fatal(err.Error())
 		return
 	}
+	app.LoadConfig = readConfig
 
 	app.Serve()
 }
@@ -1,19 +1,27 @@
 package main
 
 import (
+	"os"
 	"runtime"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"github.com/stellar/gateway/compliance"
 	"github.com/stellar/gateway/compliance/config"
+	"github.com/stellar/gateway/configcheck"
+	"github.com/stellar/gateway/configsource"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/migratecmd"
+	"github.com/stellar/gateway/secrets"
 )
 
+const defaultConfigFile = "compliance.cfg"
+
 var app *compliance.App
 var rootCmd *cobra.Command
 var migrateFlag bool
+var checkConfigFlag bool
 var configFile string
 var versionFlag bool
 var version = "N/A"
@@ -31,33 +39,77 @@ func init() {
 		Run:   run,
 	}
 
-	rootCmd.Flags().BoolVarP(&migrateFlag, "migrate-db", "", false, "migrate DB to the newest schema version")
-	rootCmd.Flags().StringVarP(&configFile, "config", "c", "compliance.cfg", "path to config file")
+	rootCmd.Flags().BoolVarP(&migrateFlag, "migrate-db", "", false, "migrate DB to the newest schema version (same as `migrate up`)")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", defaultConfigFile, "path to config file (TOML or YAML)")
+	rootCmd.Flags().BoolVarP(&checkConfigFlag, "check-config", "", false, "check config file and exit (non-zero status when invalid)")
 	rootCmd.Flags().BoolVarP(&versionFlag, "version", "v", false, "displays compliance server version")
+
+	rootCmd.AddCommand(migratecmd.NewCommand(compliance.MigrationsComponent, func() (db.Driver, error) {
+		return compliance.NewDriver(loadConfig())
+	}))
 }
 
-func run(cmd *cobra.Command, args []string) {
-	viper.SetConfigFile(configFile)
-	viper.SetConfigType("toml")
-	err := viper.ReadInConfig()
+func loadConfig() (config config.Config) {
+	config, err := readConfig()
 	if err != nil {
-		log.Fatal("Error reading "+configFile+" file: ", err)
+		log.Fatal(err.Error())
+		return
+	}
+
+	if config.LogFormat == "json" {
+		log.SetFormatter(&log.JSONFormatter{})
 	}
+	return
+}
 
-	var config config.Config
-	err = viper.Unmarshal(&config)
+// configSource returns the source of config params: configFile and
+// COMPLIANCE_* environment variables. When the default config file does not
+// exist params are read from environment variables only.
+func configSource() configsource.Source {
+	return configsource.Source{
+		Path:      configFile,
+		Optional:  configFile == defaultConfigFile,
+		EnvPrefix: "COMPLIANCE",
+	}
+}
 
-	err = config.Validate()
+// readConfig reads and validates the config
+func readConfig() (config config.Config, err error) {
+	v, err := configSource().Read(&config)
 	if err != nil {
-		log.Fatal(err.Error())
 		return
 	}
 
-	if config.LogFormat == "json" {
-		log.SetFormatter(&log.JSONFormatter{})
+	// Secret params can be loaded from env variables, files and Vault
+	err = secrets.NewLoader("COMPLIANCE").Load(v, &config)
+	if err != nil {
+		return
+	}
+
+	err = v.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	err = config.Validate()
+	return
+}
+
+func run(cmd *cobra.Command, args []string) {
+	if checkConfigFlag {
+		path := configFile
+		if !configSource().FileExists() {
+			// Config read from environment variables only
+			path = ""
+		}
+		os.Exit(configcheck.Run(path, config.Config{}, func() error {
+			_, err := readConfig()
+			return err
+		}, os.Stdout))
 	}
 
-	app, err = compliance.NewApp(config, migrateFlag, versionFlag, version)
+	var err error
+	app, err = compliance.NewApp(loadConfig(), migrateFlag, versionFlag, version)
 
 	if err != nil {
 		log.Fatal(err.Error())
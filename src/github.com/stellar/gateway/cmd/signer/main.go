@@ -0,0 +1,151 @@
+// signer is the reference signing service of the bridge server remote
+// signer protocol (see protocols/signer). It signs transactions of accounts
+// configured by public key in the bridge server with keys of `signers`
+// backends.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stellar/gateway/secrets"
+	"github.com/stellar/gateway/signers"
+	"github.com/stellar/gateway/tlspolicy"
+)
+
+// Config contains config params of the signing service
+type Config struct {
+	Port              int
+	NetworkPassphrase string `mapstructure:"network_passphrase"`
+	// Secret shared with the bridge server (bridge signer.secret param)
+	Secret string `secret:""`
+	// MaxClockSkew is the max age of a sign request (default 30s)
+	MaxClockSkew string `mapstructure:"max_clock_skew"`
+	LogFormat    string `mapstructure:"log_format"`
+	Signers      []signers.Config
+	TLS          tlspolicy.ServerConfig
+}
+
+// Validate validates config params
+func (c Config) Validate() error {
+	if c.Port == 0 {
+		return errors.New("port param is required")
+	}
+
+	if c.NetworkPassphrase == "" {
+		return errors.New("network_passphrase param is required")
+	}
+
+	if c.Secret == "" {
+		return errors.New("secret param is required")
+	}
+
+	if c.MaxClockSkew != "" {
+		skew, err := time.ParseDuration(c.MaxClockSkew)
+		if err != nil || skew <= 0 {
+			return errors.New("Cannot parse max_clock_skew param")
+		}
+	}
+
+	if len(c.Signers) == 0 {
+		return errors.New("At least one signers entry is required")
+	}
+
+	accounts := map[string]bool{}
+	for _, signer := range c.Signers {
+		err := signer.Validate("signers")
+		if err != nil {
+			return err
+		}
+		if accounts[signer.Account] {
+			return errors.New("Duplicate signers entry of " + signer.Account)
+		}
+		accounts[signer.Account] = true
+	}
+
+	return c.TLS.Validate()
+}
+
+var rootCmd *cobra.Command
+var configFile string
+
+func main() {
+	runtime.GOMAXPROCS(runtime.NumCPU())
+	rootCmd.Execute()
+}
+
+func init() {
+	rootCmd = &cobra.Command{
+		Use:   "signer",
+		Short: "stellar bridge server signing service",
+		Long:  `reference signing service of stellar bridge server accounts configured by public key`,
+		Run:   run,
+	}
+
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "signer.cfg", "path to config file")
+}
+
+func loadConfig() (config Config) {
+	viper.SetConfigFile(configFile)
+	viper.SetConfigType("toml")
+	err := viper.ReadInConfig()
+	if err != nil {
+		log.Fatal("Error reading "+configFile+" file: ", err)
+	}
+
+	// Secret params can be loaded from env variables, files and Vault
+	err = secrets.NewLoader("SIGNER").Load(secrets.GlobalViper, &config)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = viper.Unmarshal(&config)
+
+	err = config.Validate()
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	if config.LogFormat == "json" {
+		log.SetFormatter(&log.JSONFormatter{})
+	}
+	return
+}
+
+func run(cmd *cobra.Command, args []string) {
+	config := loadConfig()
+
+	keys, err := signers.Load(config.Signers, &http.Client{Timeout: 10 * time.Second})
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
+	service := signers.NewService(keys, config.Secret, config.NetworkPassphrase)
+	if config.MaxClockSkew != "" {
+		service.MaxClockSkew, _ = time.ParseDuration(config.MaxClockSkew)
+	}
+
+	for _, account := range keys.Accounts() {
+		log.Print("Signing transactions of ", account, " with ", keys.BackendName(account), " signer")
+	}
+
+	if config.TLS.ClientAuth != tlspolicy.ClientAuthRequire {
+		log.Warning("tls.client_auth is not `require`, requests are authenticated with the shared secret only")
+	}
+
+	portString := fmt.Sprintf(":%d", config.Port)
+	log.Println("Starting signing service on", portString)
+	err = config.TLS.ListenAndServe(portString, service)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -9,41 +9,101 @@ import (
 	"github.com/goji/httpauth"
 	log "github.com/sirupsen/logrus"
 
-	"github.com/facebookgo/inject"
+	"github.com/stellar/gateway/compliance/auditlog"
 	"github.com/stellar/gateway/compliance/config"
 	"github.com/stellar/gateway/compliance/handlers"
+	"github.com/stellar/gateway/compliance/outbound"
 	"github.com/stellar/gateway/crypto"
 	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/db/drivers/mysql"
 	"github.com/stellar/gateway/db/drivers/postgres"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/egress"
+	"github.com/stellar/gateway/health"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/sanctions"
 	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/signers"
+	"github.com/stellar/gateway/snapshots"
 	"github.com/stellar/go/clients/federation"
 	"github.com/stellar/go/clients/stellartoml"
-	"github.com/zenazn/goji/graceful"
 	"github.com/zenazn/goji/web"
 )
 
+// MigrationsComponent is a name of compliance server DB migrations
+const MigrationsComponent = "compliance"
+
+// poolMetricsInterval is how often DB connection pool metrics are updated
+const poolMetricsInterval = 10 * time.Second
+
 // App is the application object
 type App struct {
 	config         config.Config
 	requestHandler handlers.RequestHandler
+	health         *health.Checker
 }
 
-// NewApp constructs an new App instance from the provided config.
-func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version string) (app *App, err error) {
-	var g inject.Graph
-
-	var driver db.Driver
+// NewDriver returns a DB driver connected to the database
+func NewDriver(config config.Config) (driver db.Driver, err error) {
+	url := config.Database.URL
 	switch config.Database.Type {
 	case "mysql":
 		driver = &mysql.Driver{}
 	case "postgres":
 		driver = &postgres.Driver{}
+	case "sqlite":
+		driver = &sqlite.Driver{}
+	case "memory":
+		driver = &sqlite.Driver{}
+		url = sqlite.MemoryURL
 	default:
 		return nil, fmt.Errorf("%s database has no driver", config.Database.Type)
 	}
 
-	err = driver.Init(config.Database.URL)
+	err = driver.Init(url)
+	if err != nil {
+		return nil, err
+	}
+
+	db.ConfigurePool(driver.DB().DB, config.DatabasePoolOptions())
+
+	if config.Database.Secondary.Type != "" {
+		var secondary db.Driver
+		secondary, err = newSecondaryDriver(config.Database.Secondary)
+		if err != nil {
+			return nil, err
+		}
+		db.ConfigurePool(secondary.DB().DB, config.DatabasePoolOptions())
+		driver = db.NewDualWriteDriver(driver, secondary, config.Database.Secondary.VerifyReads)
+	}
+	return
+}
+
+// newSecondaryDriver returns a driver connected to the secondary database
+// receiving copies of all writes
+func newSecondaryDriver(config db.SecondaryConfig) (driver db.Driver, err error) {
+	switch config.Type {
+	case "mysql":
+		driver = &mysql.Driver{}
+	case "postgres":
+		driver = &postgres.Driver{}
+	case "sqlite":
+		driver = &sqlite.Driver{}
+	default:
+		return nil, fmt.Errorf("%s database has no driver", config.Type)
+	}
+
+	err = driver.Init(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot connect to a secondary DB: %s", err)
+	}
+	return
+}
+
+// NewApp constructs an new App instance from the provided config.
+func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version string) (app *App, err error) {
+	driver, err := NewDriver(config)
 	if err != nil {
 		return
 	}
@@ -51,9 +111,19 @@ func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version st
 	entityManager := db.NewEntityManager(driver)
 	repository := db.NewRepository(driver)
 
+	if config.Database.Type == "memory" && !migrateFlag {
+		// In-memory database is empty on every start
+		_, err = driver.MigrateUp(MigrationsComponent)
+		if err != nil {
+			err = fmt.Errorf("Cannot migrate in-memory DB: %s", err)
+			return
+		}
+		log.Warn("Using in-memory database, data will be lost on exit")
+	}
+
 	if migrateFlag {
 		var migrationsApplied int
-		migrationsApplied, err = driver.MigrateUp("compliance")
+		migrationsApplied, err = driver.MigrateUp(MigrationsComponent)
 		if err != nil {
 			return
 		}
@@ -69,71 +139,154 @@ func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version st
 		return
 	}
 
-	requestHandler := handlers.RequestHandler{}
-
-	httpClientWithTimeout := http.Client{
-		Timeout: 10 * time.Second,
+	err = db.CheckSchema(driver, MigrationsComponent)
+	if err != nil {
+		return
 	}
 
+	db.MonitorPool(driver.DB().DB, poolMetricsInterval)
+
+	httpClientWithTimeout := outboundClient(config, egress.CallCallbacks)
+	stellartomlHTTPClient := outboundClient(config, egress.CallStellarToml)
+	federationHTTPClient := outboundClient(config, egress.CallFederation)
+
 	stellartomlClient := stellartoml.Client{
-		HTTP: &httpClientWithTimeout,
+		HTTP: stellartomlHTTPClient,
 	}
 
 	federationClient := federation.Client{
-		HTTP:        &httpClientWithTimeout,
+		HTTP:        federationHTTPClient,
 		StellarTOML: &stellartomlClient,
 	}
 
-	err = g.Provide(
-		&inject.Object{Value: &requestHandler},
-		&inject.Object{Value: &config},
-		&inject.Object{Value: &entityManager},
-		&inject.Object{Value: &repository},
-		&inject.Object{Value: &crypto.SignerVerifier{}},
-		&inject.Object{Value: &stellartomlClient},
-		&inject.Object{Value: &federationClient},
-		&inject.Object{Value: &httpClientWithTimeout},
-		&inject.Object{Value: &handlers.NonceGenerator{}},
-	)
+	var snapshotRecorder *snapshots.Recorder
+	if config.Snapshots.Enabled() {
+		snapshotRecorder = snapshots.NewRecorder(config.Snapshots.MaxEntries)
+		stellartomlClient.HTTP = snapshotRecorder.Client(snapshots.KindStellarToml, stellartomlHTTPClient)
+		federationClient.HTTP = snapshotRecorder.Client(snapshots.KindFederation, federationHTTPClient)
+	}
 
+	keys, err := signers.Load(config.Signers, &http.Client{Timeout: 10 * time.Second})
 	if err != nil {
-		log.Fatal("Injector: ", err)
+		return
+	}
+	for _, account := range keys.Accounts() {
+		log.Print("Messages of ", account, " will be signed by ", keys.BackendName(account), " signer")
+	}
+
+	if config.Keys.EncryptionKey != "" {
+		// Checked in Validate
+		encryptionKey, _ := crypto.EncryptionPublicKey(config.Keys.EncryptionKey)
+		log.Print("Private notes will be decrypted, ENCRYPTION_KEY in stellar.toml must be ", encryptionKey)
+	}
+
+	requestHandler := handlers.NewRequestHandler(
+		&config,
+		httpClientWithTimeout,
+		&entityManager,
+		&repository,
+		&crypto.SignerVerifier{Keys: keys},
+		&stellartomlClient,
+		&federationClient,
+		&handlers.NonceGenerator{},
+	)
+	requestHandler.AuthClient = outboundClient(config, egress.CallAuth)
+	requestHandler.Snapshots = snapshotRecorder
+	requestHandler.AuditLog = auditlog.NewLog(&repository, &entityManager)
+
+	if config.Sanctions.Enabled() {
+		requestHandler.Sanctions, err = newSanctionsScreener(config, httpClientWithTimeout)
+		if err != nil {
+			return
+		}
 	}
 
-	if err := g.Populate(); err != nil {
-		log.Fatal("Injector: ", err)
+	if config.OutboundQueue.PerDomain > 0 || config.OutboundQueue.Workers > 0 {
+		// Values are checked in Validate
+		timeout, _ := time.ParseDuration(config.OutboundQueue.Timeout)
+		requestHandler.OutboundQueue = outbound.NewQueue(
+			config.OutboundQueue.PerDomain,
+			config.OutboundQueue.Workers,
+			timeout,
+		)
 	}
 
 	app = &App{
 		config:         config,
 		requestHandler: requestHandler,
+		health:         &health.Checker{},
 	}
+	app.health.Add("database", health.Database(driver.DB().DB))
 	return
 }
 
+// outboundClient returns http.Client of outbound requests of callType, using
+// egress config when it's set
+func outboundClient(config config.Config, callType string) *http.Client {
+	timeout := 10 * time.Second
+	if config.Egress.Enabled() {
+		return config.Egress.Client(callType, config.OutboundTLS, timeout)
+	}
+	return config.OutboundTLS.Client(http.Client{Timeout: timeout})
+}
+
+// newSanctionsScreener returns a Screener of a local list and a REST API,
+// results of the API are cached
+func newSanctionsScreener(config config.Config, client *http.Client) (sanctions.Screener, error) {
+	var screeners sanctions.Screeners
+
+	if config.Sanctions.List != "" {
+		list, err := sanctions.LoadList(config.Sanctions.List)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot load sanctions list: %s", err)
+		}
+		log.Print("Loaded ", list.Len(), " entries of sanctions list")
+		screeners = append(screeners, list)
+	}
+
+	if config.Sanctions.REST.URL != "" {
+		// Checked in Validate
+		var rest sanctions.Screener
+		rest, _ = sanctions.NewRESTScreener(config.Sanctions.RESTConfig(), client)
+		if ttl := config.Sanctions.CacheTTLDuration(); ttl > 0 {
+			rest = sanctions.NewCache(rest, config.Sanctions.CacheSizeOrDefault(), ttl)
+		}
+		screeners = append(screeners, rest)
+	}
+
+	log.Print("Senders and receivers will be screened against sanctions lists")
+	return screeners, nil
+}
+
+// NewAppWithRequestHandler constructs an App serving the given RequestHandler.
+// Programs embedding the compliance server can build the RequestHandler using
+// handlers.NewRequestHandler with their own implementations of its dependencies.
+func NewAppWithRequestHandler(config config.Config, requestHandler handlers.RequestHandler) *App {
+	return &App{
+		config:         config,
+		requestHandler: requestHandler,
+	}
+}
+
 // Serve starts the server
 func (a *App) Serve() {
 	// External endpoints
 	external := web.New()
 	external.Use(server.StripTrailingSlashMiddleware())
 	external.Use(server.HeadersMiddleware())
+	external.Use(server.BodyLimitMiddleware(protocols.MaxRequestBodySize))
 	external.Post("/", a.requestHandler.HandlerAuth)
 	external.Get("/tx_status", httpauth.SimpleBasicAuth(a.config.TxStatusAuth.Username, a.config.TxStatusAuth.Password)(http.HandlerFunc(a.requestHandler.HandlerTxStatus)))
+	if a.config.AttachmentAuth.Username != "" {
+		attachments := web.New()
+		attachments.Use(httpauth.SimpleBasicAuth(a.config.AttachmentAuth.Username, a.config.AttachmentAuth.Password))
+		attachments.Get("/compliance/attachment/:memo_hash", a.requestHandler.HandlerAttachment)
+		external.Get("/compliance/attachment/:memo_hash", attachments)
+	}
 	externalPortString := fmt.Sprintf(":%d", *a.config.ExternalPort)
 	log.Println("Starting external server on", externalPortString)
 	go func() {
-		var err error
-		if a.config.TLS.CertificateFile != "" && a.config.TLS.PrivateKeyFile != "" {
-			err = graceful.ListenAndServeTLS(
-				externalPortString,
-				a.config.TLS.CertificateFile,
-				a.config.TLS.PrivateKeyFile,
-				external,
-			)
-		} else {
-			err = graceful.ListenAndServe(externalPortString, external)
-		}
-
+		err := a.config.TLS.Serve(a.config.HTTP.External.NewServer("compliance_external", externalPortString, external))
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -143,13 +296,20 @@ func (a *App) Serve() {
 	internal := web.New()
 	internal.Use(server.StripTrailingSlashMiddleware())
 	internal.Use(server.HeadersMiddleware())
+	internal.Use(server.BodyLimitMiddleware(protocols.MaxRequestBodySize))
 	internal.Post("/send", a.requestHandler.HandlerSend)
 	internal.Post("/receive", a.requestHandler.HandlerReceive)
 	internal.Post("/allow_access", a.requestHandler.HandlerAllowAccess)
 	internal.Post("/remove_access", a.requestHandler.HandlerRemoveAccess)
+	internal.Get("/compliance/attachment/:memo_hash", a.requestHandler.HandlerAttachment)
+	internal.Get("/compliance/audit", a.requestHandler.HandlerAudit)
+	internal.Get("/compliance/audit/verify", a.requestHandler.HandlerAuditVerify)
+	internal.Get("/metrics", metrics.Handler)
+	internal.Get("/healthz", a.health.Healthz)
+	internal.Get("/readyz", a.health.Readyz)
 	internalPortString := fmt.Sprintf(":%d", *a.config.InternalPort)
 	log.Println("Starting internal server on", internalPortString)
-	err := graceful.ListenAndServe(internalPortString, internal)
+	err := a.config.HTTP.Internal.NewServer("compliance_internal", internalPortString, internal).ListenAndServe()
 	if err != nil {
 		log.Fatal(err)
 	}
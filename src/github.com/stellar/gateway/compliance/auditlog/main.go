@@ -0,0 +1,219 @@
+// Package auditlog records compliance decisions in an append-only audit log.
+//
+// Every entry contains the hash of the previous entry and its own hash is
+// computed from its fields and the previous hash, so the entries form a chain.
+// Modifying, removing or reordering an entry changes hashes and breaks the
+// chain which is detected by Log.Verify. Sequence numbers are unique in the
+// database so two compliance servers sharing a database cannot fork the chain.
+package auditlog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/go/protocols/compliance"
+)
+
+// Types of entries
+const (
+	// TypeAuthReceived is an auth request received from another organization
+	TypeAuthReceived = "auth_received"
+	// TypeAuthSent is an auth request sent to another organization by /send
+	TypeAuthSent = "auth_sent"
+	// TypeSendRejected is a /send request rejected before sending an auth
+	// request (ex. by sanctions screening of the receiver)
+	TypeSendRejected = "send_rejected"
+	// TypeOverride is a change of allowed FIs and users by an operator
+	TypeOverride = "override"
+)
+
+// Decisions of overrides
+const (
+	DecisionAllow  = "allow"
+	DecisionRemove = "remove"
+)
+
+// GenesisHash is the previous hash of the first entry
+var GenesisHash = strings.Repeat("0", 64)
+
+// maxAttempts is the number of attempts to append an entry when the sequence
+// number has been taken by another server
+const maxAttempts = 3
+
+// verifyBatchSize is the number of entries loaded at once by Verify
+const verifyBatchSize = 1000
+
+// Log appends entries to the audit log
+type Log struct {
+	Repository    db.RepositoryInterface
+	EntityManager db.EntityManagerInterface
+	mutex         sync.Mutex
+}
+
+// NewLog creates a new Log
+func NewLog(repository db.RepositoryInterface, entityManager db.EntityManagerInterface) *Log {
+	return &Log{
+		Repository:    repository,
+		EntityManager: entityManager,
+	}
+}
+
+// Record appends a new entry to the audit log. data is encoded to JSON and
+// stored with the entry.
+func (l *Log) Record(ctx context.Context, entryType, transactionID, counterparty, decision string, data interface{}) (*entities.ComplianceAuditEntry, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		last, err := l.Repository.GetLastComplianceAuditEntry(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &entities.ComplianceAuditEntry{
+			Sequence:      1,
+			Type:          entryType,
+			TransactionID: transactionID,
+			Counterparty:  counterparty,
+			Decision:      decision,
+			Data:          string(encoded),
+			PrevHash:      GenesisHash,
+			CreatedAt:     clock.Now().UTC().Truncate(time.Second),
+		}
+		if last != nil {
+			entry.Sequence = last.Sequence + 1
+			entry.PrevHash = last.Hash
+		}
+
+		entry.Hash, err = Hash(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		err = l.EntityManager.Persist(entry)
+		if err == nil {
+			return entry, nil
+		}
+		if attempt == maxAttempts {
+			return nil, err
+		}
+	}
+}
+
+// Hash returns a hex encoded SHA-256 hash of the entry fields and the hash of
+// the previous entry
+func Hash(entry *entities.ComplianceAuditEntry) (string, error) {
+	encoded, err := json.Marshal(struct {
+		Sequence      int64  `json:"sequence"`
+		Type          string `json:"type"`
+		TransactionID string `json:"transaction_id"`
+		Counterparty  string `json:"counterparty"`
+		Decision      string `json:"decision"`
+		Data          string `json:"data"`
+		CreatedAt     string `json:"created_at"`
+		PrevHash      string `json:"prev_hash"`
+	}{
+		Sequence:      entry.Sequence,
+		Type:          entry.Type,
+		TransactionID: entry.TransactionID,
+		Counterparty:  entry.Counterparty,
+		Decision:      entry.Decision,
+		Data:          entry.Data,
+		CreatedAt:     entry.CreatedAt.UTC().Format(time.RFC3339),
+		PrevHash:      entry.PrevHash,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Verification is a result of Log.Verify
+type Verification struct {
+	Valid bool `json:"valid"`
+	// Entries is the number of verified entries
+	Entries int64 `json:"entries"`
+	// LastHash is the hash of the last entry, it can be stored outside of the
+	// database to detect truncation of the log
+	LastHash string `json:"last_hash"`
+	// BrokenSequence is the sequence number of the first invalid entry
+	BrokenSequence int64  `json:"broken_sequence,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Verify checks hashes and sequence numbers of all entries
+func (l *Log) Verify(ctx context.Context) (Verification, error) {
+	verification := Verification{Valid: true, LastHash: GenesisHash}
+	var sequence int64
+
+	for {
+		entries, err := l.Repository.GetComplianceAuditEntries(ctx, db.ComplianceAuditFilter{AfterSequence: sequence}, verifyBatchSize)
+		if err != nil {
+			return Verification{}, err
+		}
+
+		for _, entry := range entries {
+			var problem string
+			hash, err := Hash(entry)
+			if err != nil {
+				return Verification{}, err
+			}
+
+			switch {
+			case entry.Sequence != sequence+1:
+				problem = fmt.Sprintf("Expected sequence %d", sequence+1)
+			case entry.PrevHash != verification.LastHash:
+				problem = "Previous hash does not match"
+			case entry.Hash != hash:
+				problem = "Hash does not match"
+			}
+
+			if problem != "" {
+				verification.Valid = false
+				verification.BrokenSequence = entry.Sequence
+				verification.Error = problem
+				return verification, nil
+			}
+
+			sequence = entry.Sequence
+			verification.Entries++
+			verification.LastHash = entry.Hash
+		}
+
+		if len(entries) < verifyBatchSize {
+			return verification, nil
+		}
+	}
+}
+
+// Decision returns a decision of an auth request: ok when both statuses are
+// ok, otherwise denied, error or pending in this order
+func Decision(txStatus, infoStatus compliance.AuthStatus) string {
+	statuses := []compliance.AuthStatus{txStatus, infoStatus}
+	for _, status := range []compliance.AuthStatus{
+		compliance.AuthStatusDenied,
+		compliance.AuthStatusError,
+		compliance.AuthStatusPending,
+	} {
+		if statuses[0] == status || statuses[1] == status {
+			return string(status)
+		}
+	}
+	return string(compliance.AuthStatusOk)
+}
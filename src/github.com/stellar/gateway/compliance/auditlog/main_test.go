@@ -0,0 +1,74 @@
+package auditlog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/go/protocols/compliance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("compliance")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	clock.Default = clock.Func(func() time.Time { return now })
+	defer func() { clock.Default = clock.System }()
+
+	ctx := context.Background()
+	auditLog := NewLog(db.NewRepository(driver), db.NewEntityManager(driver))
+
+	verification, err := auditLog.Verify(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Verification{Valid: true, LastHash: GenesisHash}, verification)
+
+	first, err := auditLog.Record(ctx, TypeAuthReceived, "tx1", "stellar.org", "ok", map[string]string{"sender": "alice*stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first.Sequence)
+	assert.Equal(t, GenesisHash, first.PrevHash)
+	assert.Equal(t, `{"sender":"alice*stellar.org"}`, first.Data)
+	assert.Len(t, first.Hash, 64)
+
+	second, err := auditLog.Record(ctx, TypeOverride, "", "stellar.org", DecisionAllow, nil)
+	require.NoError(t, err)
+	_, err = auditLog.Record(ctx, TypeAuthSent, "tx2", "example.com", "denied", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), second.Sequence)
+	assert.Equal(t, first.Hash, second.PrevHash)
+
+	verification, err = auditLog.Verify(ctx)
+	require.NoError(t, err)
+	assert.True(t, verification.Valid)
+	assert.Equal(t, int64(3), verification.Entries)
+
+	// Changing a decision breaks the chain
+	_, err = driver.DB().Exec("UPDATE ComplianceAudit SET decision = 'ok' WHERE sequence = 3")
+	require.NoError(t, err)
+	verification, err = auditLog.Verify(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Verification{Valid: false, Entries: 2, LastHash: second.Hash, BrokenSequence: 3, Error: "Hash does not match"}, verification)
+
+	// So does removing an entry
+	_, err = driver.DB().Exec("DELETE FROM ComplianceAudit WHERE sequence = 2")
+	require.NoError(t, err)
+	verification, err = auditLog.Verify(ctx)
+	require.NoError(t, err)
+	assert.False(t, verification.Valid)
+	assert.Equal(t, int64(3), verification.BrokenSequence)
+	assert.Equal(t, "Expected sequence 2", verification.Error)
+}
+
+func TestDecision(t *testing.T) {
+	assert.Equal(t, "ok", Decision(compliance.AuthStatusOk, compliance.AuthStatusOk))
+	assert.Equal(t, "denied", Decision(compliance.AuthStatusPending, compliance.AuthStatusDenied))
+	assert.Equal(t, "error", Decision(compliance.AuthStatusError, compliance.AuthStatusPending))
+	assert.Equal(t, "pending", Decision(compliance.AuthStatusOk, compliance.AuthStatusPending))
+}
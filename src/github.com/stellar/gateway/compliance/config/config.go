@@ -3,7 +3,15 @@ package config
 import (
 	"errors"
 	"net/url"
+	"time"
 
+	"github.com/stellar/gateway/crypto"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/egress"
+	"github.com/stellar/gateway/sanctions"
+	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/signers"
+	"github.com/stellar/gateway/tlspolicy"
 	"github.com/stellar/go/keypair"
 )
 
@@ -16,23 +24,189 @@ type Config struct {
 	NetworkPassphrase string `mapstructure:"network_passphrase"`
 	Database          struct {
 		Type string
-		URL  string
+		URL  string `secret:""`
+		// Connection pool params, database/sql defaults are used when not set
+		MaxOpenConns    int    `mapstructure:"max_open_conns"`
+		MaxIdleConns    int    `mapstructure:"max_idle_conns"`
+		ConnMaxLifetime string `mapstructure:"conn_max_lifetime"`
+		// Secondary database receives copies of all writes during migration
+		// to another database
+		Secondary db.SecondaryConfig
 	}
 	Keys
+	// Signers select HSM or KMS signing backend of keys.signing_seed when it
+	// is a public key
+	Signers []signers.Config
 	Callbacks
-	TLS struct {
-		CertificateFile string `mapstructure:"certificate_file"`
-		PrivateKeyFile  string `mapstructure:"private_key_file"`
-	}
+	// TLS is applied to the external server, other compliance servers can be
+	// required to present a client certificate
+	TLS          tlspolicy.ServerConfig
 	TxStatusAuth struct {
 		Username string `mapstructure:"username"`
-		Password string `mapstructure:"password"`
+		Password string `mapstructure:"password" secret:""`
 	} `mapstructure:"tx_status_auth"`
+	// AttachmentAuth protects /compliance/attachment endpoint on the external
+	// port, the endpoint is available on the internal port only when empty
+	AttachmentAuth struct {
+		Username string `mapstructure:"username"`
+		Password string `mapstructure:"password" secret:""`
+	} `mapstructure:"attachment_auth"`
+	OutboundQueue OutboundQueue `mapstructure:"outbound_queue"`
+	// OutboundTLS is applied to connections to other compliance servers,
+	// federation servers, stellar.toml and callbacks
+	OutboundTLS tlspolicy.Config `mapstructure:"outbound_tls"`
+	// Egress configures proxies, trust stores and timeouts of outbound
+	// auth, federation, stellar.toml and callback requests
+	Egress egress.Config
+	HTTP   HTTP
+	// Snapshots records federation and stellar.toml responses used by
+	// transactions built in /send
+	Snapshots Snapshots
+	// Sanctions screens senders of received payments and receivers of sent
+	// payments, in addition to callbacks.sanctions
+	Sanctions Sanctions
+}
+
+// Sanctions contains values of `sanctions` config group
+type Sanctions struct {
+	// List is a path to a local list of sanctioned names, account IDs and
+	// Stellar addresses, see sanctions.ReadList
+	List string
+	REST SanctionsREST
+	// CacheTTL is how long results of REST API are cached (default "1h"),
+	// "0s" disables the cache
+	CacheTTL  string `mapstructure:"cache_ttl"`
+	CacheSize int    `mapstructure:"cache_size"`
+	// Pending is the time in seconds returned in `pending` field of auth
+	// responses when a sender needs a review (default 600)
+	Pending int
+}
+
+// SanctionsREST contains values of `sanctions.rest` config group, see
+// sanctions.RESTConfig
+type SanctionsREST struct {
+	URL string
+	// Secret is returned by `secret` template function, ex. an API key
+	Secret     string `secret:""`
+	Method     string
+	Headers    map[string]string
+	Body       string
+	MatchField string `mapstructure:"match_field"`
+}
+
+// Enabled returns true when senders and receivers are screened
+func (c Sanctions) Enabled() bool {
+	return c.List != "" || c.REST.URL != ""
+}
+
+// CacheTTLDuration returns CacheTTL or 1 hour when it's not set
+func (c Sanctions) CacheTTLDuration() time.Duration {
+	if c.CacheTTL == "" {
+		return time.Hour
+	}
+	// Values are checked in Validate
+	ttl, _ := time.ParseDuration(c.CacheTTL)
+	return ttl
+}
+
+// CacheSizeOrDefault returns CacheSize or 10000 when it's not set
+func (c Sanctions) CacheSizeOrDefault() int {
+	if c.CacheSize == 0 {
+		return 10000
+	}
+	return c.CacheSize
 }
 
-// Keys contains values of `keys` config group
+// PendingOrDefault returns Pending or 600 when it's not set
+func (c Sanctions) PendingOrDefault() int {
+	if c.Pending == 0 {
+		return 600
+	}
+	return c.Pending
+}
+
+// RESTConfig returns the config of sanctions.RESTScreener
+func (c Sanctions) RESTConfig() sanctions.RESTConfig {
+	return sanctions.RESTConfig{
+		URL:        c.REST.URL,
+		Secret:     c.REST.Secret,
+		Method:     c.REST.Method,
+		Headers:    c.REST.Headers,
+		Body:       c.REST.Body,
+		MatchField: c.REST.MatchField,
+	}
+}
+
+func (c Sanctions) validate() error {
+	if c.REST.URL != "" {
+		if _, err := url.Parse(c.REST.URL); err != nil {
+			return errors.New("Cannot parse sanctions.rest.url param")
+		}
+		if _, err := sanctions.NewRESTScreener(c.RESTConfig(), nil); err != nil {
+			return errors.New("Invalid sanctions.rest param: " + err.Error())
+		}
+	}
+
+	if c.CacheTTL != "" {
+		if ttl, err := time.ParseDuration(c.CacheTTL); err != nil || ttl < 0 {
+			return errors.New("Cannot parse sanctions.cache_ttl param")
+		}
+	}
+
+	if c.CacheSize < 0 {
+		return errors.New("sanctions.cache_size param must be positive")
+	}
+
+	if c.Pending < 0 {
+		return errors.New("sanctions.pending param must be positive")
+	}
+	return nil
+}
+
+// Snapshots contains values of `snapshots` config group
+type Snapshots struct {
+	// Federation records federation and stellar.toml responses used to
+	// resolve destinations, see snapshots package
+	Federation bool
+	// MaxEntries is a max number of responses kept in memory until they are
+	// used by a payment (default 10000)
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// Enabled returns true when federation snapshots are recorded
+func (c Snapshots) Enabled() bool {
+	return c.Federation
+}
+
+// HTTP contains values of `http` config group: timeouts and worker pools of
+// the listeners
+type HTTP struct {
+	// External limits the public listener receiving auth requests from other
+	// organizations
+	External server.Limits
+	// Internal limits the listener used by the bridge server
+	Internal server.Limits
+}
+
+// OutboundQueue contains values of `outbound_queue` config group. It limits
+// concurrent requests sent to auth servers of other organizations.
+type OutboundQueue struct {
+	// PerDomain is the max number of concurrent requests to a single domain
+	PerDomain int `mapstructure:"per_domain"`
+	// Workers is the max number of concurrent requests to all domains
+	Workers int
+	// Timeout is the max time a request waits in the queue, ex. "30s"
+	Timeout string
+}
+
+// Keys contains values of `keys` config group. SigningSeed can be a public key
+// when a Signers entry of the account is configured.
 type Keys struct {
-	SigningSeed string `mapstructure:"signing_seed"`
+	SigningSeed string `mapstructure:"signing_seed" secret:""`
+	// EncryptionKey is a base64 encoded Curve25519 private key decrypting
+	// private notes. Its public key is published as ENCRYPTION_KEY in
+	// stellar.toml.
+	EncryptionKey string `mapstructure:"encryption_key" secret:""`
 }
 
 // Callbacks contains values of `callbacks` config group
@@ -43,6 +217,17 @@ type Callbacks struct {
 	TxStatus  string `mapstructure:"tx_status"`
 }
 
+// DatabasePoolOptions returns connection pool options of the database
+func (c Config) DatabasePoolOptions() db.PoolOptions {
+	// Values are checked in Validate
+	lifetime, _ := time.ParseDuration(c.Database.ConnMaxLifetime)
+	return db.PoolOptions{
+		MaxOpenConns:    c.Database.MaxOpenConns,
+		MaxIdleConns:    c.Database.MaxIdleConns,
+		ConnMaxLifetime: lifetime,
+	}
+}
+
 // Validate validates config and returns error if any of config values is incorrect
 func (c *Config) Validate() (err error) {
 	if c.ExternalPort == nil {
@@ -66,11 +251,34 @@ func (c *Config) Validate() (err error) {
 	}
 
 	if c.Keys.SigningSeed != "" {
-		_, err = keypair.Parse(c.Keys.SigningSeed)
+		var kp keypair.KP
+		kp, err = keypair.Parse(c.Keys.SigningSeed)
 		if err != nil {
 			err = errors.New("keys.signing_seed is invalid")
 			return
 		}
+
+		hasSigner := false
+		for _, signer := range c.Signers {
+			err = signer.Validate("signers")
+			if err != nil {
+				return
+			}
+			hasSigner = hasSigner || signer.Account == kp.Address()
+		}
+
+		if _, full := kp.(*keypair.Full); !full && !hasSigner {
+			err = errors.New("signers entry is required when keys.signing_seed is a public key")
+			return
+		}
+	}
+
+	if c.Keys.EncryptionKey != "" {
+		_, err = crypto.EncryptionPublicKey(c.Keys.EncryptionKey)
+		if err != nil {
+			err = errors.New("keys.encryption_key is invalid")
+			return
+		}
 	}
 
 	var dbURL *url.URL
@@ -89,11 +297,73 @@ func (c *Config) Validate() (err error) {
 		c.Database.URL = dbURL.String()
 	case "postgres":
 		break
+	case "sqlite":
+		if c.Database.URL == "" {
+			err = errors.New("database.url param is required for sqlite database")
+			return
+		}
+	case "memory":
+		// In-memory SQLite database, url is not used
+		break
 	default:
 		err = errors.New("Invalid database.type param")
 		return
 	}
 
+	if c.Database.MaxOpenConns < 0 {
+		err = errors.New("Invalid database.max_open_conns param")
+		return
+	}
+
+	if c.Database.MaxIdleConns < 0 {
+		err = errors.New("Invalid database.max_idle_conns param")
+		return
+	}
+
+	if c.Database.ConnMaxLifetime != "" {
+		var lifetime time.Duration
+		lifetime, err = time.ParseDuration(c.Database.ConnMaxLifetime)
+		if err != nil || lifetime <= 0 {
+			err = errors.New("Invalid database.conn_max_lifetime param")
+			return
+		}
+	}
+
+	if c.DatabasePoolOptions() != (db.PoolOptions{}) && (c.Database.Type == "sqlite" || c.Database.Type == "memory") {
+		// SQLite driver uses a single connection
+		err = errors.New("database connection pool params are not supported by sqlite database")
+		return
+	}
+
+	err = c.Database.Secondary.Validate(c.Database.Type)
+	if err != nil {
+		return
+	}
+
+	if c.OutboundQueue.PerDomain < 0 || c.OutboundQueue.Workers < 0 {
+		err = errors.New("outbound_queue.per_domain and outbound_queue.workers params must be positive")
+		return
+	}
+
+	if c.OutboundQueue.Timeout != "" {
+		var timeout time.Duration
+		timeout, err = time.ParseDuration(c.OutboundQueue.Timeout)
+		if err != nil || timeout <= 0 {
+			err = errors.New("Cannot parse outbound_queue.timeout param")
+			return
+		}
+	}
+
+	if c.AttachmentAuth.Username != "" && c.AttachmentAuth.Password == "" {
+		err = errors.New("attachment_auth.password param is required")
+		return
+	}
+
+	if c.Snapshots.MaxEntries < 0 {
+		err = errors.New("snapshots.max_entries param must be positive")
+		return
+	}
+
 	if c.Callbacks.Sanctions != "" {
 		_, err = url.Parse(c.Callbacks.Sanctions)
 		if err != nil {
@@ -102,6 +372,11 @@ func (c *Config) Validate() (err error) {
 		}
 	}
 
+	err = c.Sanctions.validate()
+	if err != nil {
+		return
+	}
+
 	if c.Callbacks.TxStatus != "" {
 		_, err = url.Parse(c.Callbacks.TxStatus)
 		if err != nil {
@@ -126,5 +401,26 @@ func (c *Config) Validate() (err error) {
 		}
 	}
 
+	err = c.TLS.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.OutboundTLS.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Egress.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.HTTP.External.Validate("http.external")
+	if err != nil {
+		return
+	}
+
+	err = c.HTTP.Internal.Validate("http.internal")
 	return
 }
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// recordAudit appends an entry to the audit log when it's enabled. Handlers
+// fail when a decision cannot be recorded, every decision must be in the log.
+func (rh *RequestHandler) recordAudit(r *http.Request, entryType, transactionID, counterparty, decision string, data interface{}) error {
+	if rh.AuditLog == nil {
+		return nil
+	}
+
+	_, err := rh.AuditLog.Record(r.Context(), entryType, transactionID, counterparty, decision, data)
+	if err != nil {
+		log.WithFields(log.Fields{"type": entryType, "transaction_id": transactionID, "err": err}).Error("Error recording audit log entry")
+	}
+	return err
+}
+
+// senderDomain returns the domain of a Stellar address or an empty string
+func senderDomain(address string) string {
+	tokens := strings.Split(address, "*")
+	if len(tokens) != 2 {
+		return ""
+	}
+	return tokens[1]
+}
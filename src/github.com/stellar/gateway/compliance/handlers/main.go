@@ -4,38 +4,88 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/stellar/gateway/compliance/auditlog"
 	"github.com/stellar/gateway/compliance/config"
+	"github.com/stellar/gateway/compliance/outbound"
 	"github.com/stellar/gateway/crypto"
 	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/external"
 	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/sanctions"
+	"github.com/stellar/gateway/snapshots"
 	"github.com/stellar/go/clients/federation"
 )
 
 // RequestHandler implements compliance server request handlers
 type RequestHandler struct {
-	Config                  *config.Config                      `inject:""`
-	Client                  net.HTTPClientInterface             `inject:""`
-	EntityManager           db.EntityManagerInterface           `inject:""`
-	Repository              db.RepositoryInterface              `inject:""`
-	SignatureSignerVerifier crypto.SignerVerifierInterface      `inject:""`
-	StellarTomlResolver     external.StellarTomlClientInterface `inject:""`
-	FederationResolver      federation.ClientInterface          `inject:""`
-	NonceGenerator          NonceGeneratorInterface             `inject:""`
+	Config *config.Config
+	Client net.HTTPClientInterface
+	// AuthClient sends requests to auth servers of other compliance servers,
+	// Client is used when nil
+	AuthClient              net.HTTPClientInterface
+	EntityManager           db.EntityManagerInterface
+	Repository              db.RepositoryInterface
+	SignatureSignerVerifier crypto.SignerVerifierInterface
+	StellarTomlResolver     external.StellarTomlClientInterface
+	FederationResolver      federation.ClientInterface
+	NonceGenerator          NonceGeneratorInterface
+	// OutboundQueue limits concurrent requests to auth servers, requests are
+	// not limited when nil
+	OutboundQueue *outbound.Queue
+	// Snapshots records federation and stellar.toml responses, responses
+	// used by sent transactions are not recorded when nil
+	Snapshots *snapshots.Recorder
+	// Sanctions screens senders in auth requests and receivers in /send,
+	// subjects are not screened when nil. Programs embedding the compliance
+	// server can set their own Screener.
+	Sanctions sanctions.Screener
+	// AuditLog records compliance decisions, decisions are not recorded
+	// when nil
+	AuditLog *auditlog.Log
 }
 
+// NewRequestHandler creates a new RequestHandler using the given dependencies.
+// Every dependency is an interface so programs embedding the compliance server
+// can provide their own implementations.
+func NewRequestHandler(
+	config *config.Config,
+	client net.HTTPClientInterface,
+	entityManager db.EntityManagerInterface,
+	repository db.RepositoryInterface,
+	signerVerifier crypto.SignerVerifierInterface,
+	stellarTomlResolver external.StellarTomlClientInterface,
+	federationResolver federation.ClientInterface,
+	nonceGenerator NonceGeneratorInterface,
+) RequestHandler {
+	return RequestHandler{
+		Config:                  config,
+		Client:                  client,
+		EntityManager:           entityManager,
+		Repository:              repository,
+		SignatureSignerVerifier: signerVerifier,
+		StellarTomlResolver:     stellarTomlResolver,
+		FederationResolver:      federationResolver,
+		NonceGenerator:          nonceGenerator,
+	}
+}
+
+// NonceGeneratorInterface helps mocking NonceGenerator
 type NonceGeneratorInterface interface {
 	Generate() string
 }
 
+// NonceGenerator generates nonces based on the current time
 type NonceGenerator struct{}
 
+// Generate returns a new nonce
 func (n *NonceGenerator) Generate() string {
 	return strconv.FormatInt(time.Now().UnixNano(), 10)
 }
 
+// TestNonceGenerator always returns the same nonce. Used in tests.
 type TestNonceGenerator struct{}
 
+// Generate returns "nonce"
 func (n *TestNonceGenerator) Generate() string {
 	return "nonce"
 }
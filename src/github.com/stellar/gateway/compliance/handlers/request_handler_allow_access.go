@@ -3,8 +3,9 @@ package handlers
 import (
 	log "github.com/sirupsen/logrus"
 	"net/http"
-	"time"
 
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/compliance/auditlog"
 	"github.com/stellar/gateway/db/entities"
 	"github.com/stellar/gateway/protocols"
 	"github.com/stellar/gateway/server"
@@ -28,7 +29,7 @@ func (rh *RequestHandler) HandlerAllowAccess(c web.C, w http.ResponseWriter, r *
 			FiDomain:    domain,
 			FiPublicKey: publicKey,
 			UserID:      userID,
-			AllowedAt:   time.Now(),
+			AllowedAt:   clock.Now(),
 		}
 		err = rh.EntityManager.Persist(entity)
 	} else {
@@ -36,7 +37,7 @@ func (rh *RequestHandler) HandlerAllowAccess(c web.C, w http.ResponseWriter, r *
 			Name:      name,
 			Domain:    domain,
 			PublicKey: publicKey,
-			AllowedAt: time.Now(),
+			AllowedAt: clock.Now(),
 		}
 		err = rh.EntityManager.Persist(entity)
 	}
@@ -47,5 +48,15 @@ func (rh *RequestHandler) HandlerAllowAccess(c web.C, w http.ResponseWriter, r *
 		return
 	}
 
+	err = rh.recordAudit(r, auditlog.TypeOverride, "", domain, auditlog.DecisionAllow, map[string]string{
+		"name":       name,
+		"public_key": publicKey,
+		"user_id":    userID,
+	})
+	if err != nil {
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 }
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/protocols"
+	callback "github.com/stellar/gateway/protocols/compliance"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+// HandlerAttachment implements /compliance/attachment/:memo_hash endpoint. It
+// returns an attachment sent or received by this server, memo_hash is hex or
+// base64 encoded (like memo param of /receive).
+func (rh *RequestHandler) HandlerAttachment(c web.C, w http.ResponseWriter, r *http.Request) {
+	memoHash, ok := parseMemoHash(c.URLParams["memo_hash"])
+	if !ok {
+		server.Write(w, protocols.NewInvalidParameterError("memo_hash", c.URLParams["memo_hash"], "Memo hash must be a hex or base64 encoded 32 bytes hash."))
+		return
+	}
+
+	attachment, err := rh.Repository.GetAttachmentByMemoHash(r.Context(), memoHash)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting Attachment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if attachment == nil {
+		server.Write(w, callback.AttachmentNotFound)
+		return
+	}
+
+	err = server.WriteJSON(w, attachment)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding Attachment")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+// parseMemoHash returns a hex encoded memo hash of a hex, base64 or URL-safe
+// base64 encoded value
+func parseMemoHash(value string) (string, bool) {
+	hash, err := hex.DecodeString(value)
+	if err != nil || len(hash) != 32 {
+		hash, err = base64.StdEncoding.DecodeString(value)
+	}
+	if err != nil || len(hash) != 32 {
+		hash, err = base64.URLEncoding.DecodeString(value)
+	}
+	if err != nil || len(hash) != 32 {
+		return "", false
+	}
+	return hex.EncodeToString(hash), true
+}
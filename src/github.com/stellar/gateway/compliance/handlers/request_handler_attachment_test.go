@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/compliance/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestRequestHandlerAttachment(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("compliance")
+	require.NoError(t, err)
+
+	entityManager := db.NewEntityManager(driver)
+	requestHandler := NewRequestHandler(&config.Config{}, nil, entityManager, db.NewRepository(driver), nil, nil, nil, &TestNonceGenerator{})
+
+	attachmentJSON := `{"nonce":"nonce","transaction":{"sender_info":{"first_name":"Alice"},"route":"bob","note":"","extra":""},"operations":[]}`
+	hash := sha256.Sum256([]byte(attachmentJSON))
+	require.NoError(t, entityManager.Persist(&entities.Attachment{
+		MemoHash:      hex.EncodeToString(hash[:]),
+		Direction:     entities.AttachmentDirectionSent,
+		TransactionID: "tx1",
+		Counterparty:  "stellar.org",
+		Attachment:    attachmentJSON,
+		CreatedAt:     time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC),
+	}))
+
+	get := func(memoHash string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c := web.C{URLParams: map[string]string{"memo_hash": memoHash}}
+		requestHandler.HandlerAttachment(c, w, httptest.NewRequest("GET", "/compliance/attachment/"+memoHash, nil))
+		return w
+	}
+
+	for _, memoHash := range []string{
+		hex.EncodeToString(hash[:]),
+		base64.StdEncoding.EncodeToString(hash[:]),
+		base64.URLEncoding.EncodeToString(hash[:]),
+	} {
+		w := get(memoHash)
+		require.Equal(t, http.StatusOK, w.Code, memoHash)
+
+		var attachment entities.Attachment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &attachment))
+		assert.Equal(t, entities.AttachmentDirectionSent, attachment.Direction)
+		assert.Equal(t, "tx1", attachment.TransactionID)
+		// Attachment is returned exactly as it was hashed
+		assert.Equal(t, attachmentJSON, attachment.Attachment)
+	}
+
+	w := get(hex.EncodeToString(make([]byte, 32)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "attachment_not_found")
+
+	w = get("abc")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+	"github.com/zenazn/goji/web"
+)
+
+const (
+	auditDefaultLimit = 100
+	auditMaxLimit     = 1000
+)
+
+// HandlerAudit implements /compliance/audit endpoint. Entries are returned in
+// order of sequence numbers, `after` param pages through the log.
+func (rh *RequestHandler) HandlerAudit(c web.C, w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	filter := db.ComplianceAuditFilter{
+		Type:          values.Get("type"),
+		TransactionID: values.Get("transaction_id"),
+		Counterparty:  values.Get("counterparty"),
+		Decision:      values.Get("decision"),
+	}
+
+	if after := values.Get("after"); after != "" {
+		var err error
+		filter.AfterSequence, err = strconv.ParseInt(after, 10, 64)
+		if err != nil || filter.AfterSequence < 0 {
+			server.Write(w, protocols.NewInvalidParameterError("after", after, "After must be a sequence number."))
+			return
+		}
+	}
+
+	limit := auditDefaultLimit
+	if value := values.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > auditMaxLimit {
+			server.Write(w, protocols.NewInvalidParameterError("limit", value, "Limit must be an integer between 1 and "+strconv.Itoa(auditMaxLimit)+"."))
+			return
+		}
+	}
+
+	for _, param := range []struct {
+		name  string
+		value **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := values.Get(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			server.Write(w, protocols.NewInvalidParameterError(param.name, value, "Time must be in RFC 3339 format, ex. 2018-03-01T12:00:00Z."))
+			return
+		}
+		t = t.UTC()
+		*param.value = &t
+	}
+
+	format := values.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		server.Write(w, protocols.NewInvalidParameterError("format", format, "Format must be `json` or `csv`."))
+		return
+	}
+
+	auditEntries, err := rh.Repository.GetComplianceAuditEntries(r.Context(), filter, limit)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error getting ComplianceAuditEntries")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if format == "csv" {
+		writeAuditCSV(w, auditEntries)
+		return
+	}
+
+	err = server.WriteJSON(w, struct {
+		Entries []*entities.ComplianceAuditEntry `json:"entries"`
+	}{auditEntries})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding ComplianceAuditEntries")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+// HandlerAuditVerify implements /compliance/audit/verify endpoint
+func (rh *RequestHandler) HandlerAuditVerify(c web.C, w http.ResponseWriter, r *http.Request) {
+	if rh.AuditLog == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	verification, err := rh.AuditLog.Verify(r.Context())
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error verifying audit log")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if !verification.Valid {
+		log.WithFields(log.Fields{"sequence": verification.BrokenSequence, "error": verification.Error}).Error("Audit log chain is broken")
+	}
+
+	err = server.WriteJSON(w, verification)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding audit log verification")
+		server.Write(w, protocols.InternalServerError)
+	}
+}
+
+// writeAuditCSV writes entries as a CSV file download
+func writeAuditCSV(w http.ResponseWriter, auditEntries []*entities.ComplianceAuditEntry) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="compliance-audit.csv"`)
+
+	records := [][]string{{"sequence", "type", "transaction_id", "counterparty", "decision", "data", "created_at", "prev_hash", "hash"}}
+	for _, entry := range auditEntries {
+		records = append(records, []string{
+			strconv.FormatInt(entry.Sequence, 10),
+			entry.Type,
+			entry.TransactionID,
+			entry.Counterparty,
+			entry.Decision,
+			entry.Data,
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			entry.PrevHash,
+			entry.Hash,
+		})
+	}
+
+	writer := csv.NewWriter(w)
+	err := writer.WriteAll(records)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error writing CSV")
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/compliance/auditlog"
+	"github.com/stellar/gateway/compliance/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestRequestHandlerAudit(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("compliance")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	clock.Default = clock.Func(func() time.Time { return now })
+	defer func() { clock.Default = clock.System }()
+
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	requestHandler := NewRequestHandler(&config.Config{}, nil, entityManager, repository, nil, nil, nil, &TestNonceGenerator{})
+	requestHandler.AuditLog = auditlog.NewLog(repository, entityManager)
+
+	// Overrides are recorded
+	params := url.Values{"name": {"Stellar"}, "domain": {"stellar.org"}, "public_key": {"GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"}}
+	r := httptest.NewRequest("POST", "/allow_access", strings.NewReader(params.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	requestHandler.HandlerAllowAccess(web.C{}, w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	now = now.Add(time.Hour)
+	_, err = requestHandler.AuditLog.Record(context.Background(), auditlog.TypeAuthReceived, "tx1", "stellar.org", "denied", nil)
+	require.NoError(t, err)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		requestHandler.HandlerAudit(web.C{}, w, httptest.NewRequest("GET", "/compliance/audit?"+query, nil))
+		return w
+	}
+
+	var response struct {
+		Entries []struct {
+			Sequence int64  `json:"sequence"`
+			Type     string `json:"type"`
+			Decision string `json:"decision"`
+			Data     string `json:"data"`
+		} `json:"entries"`
+	}
+
+	w = get("")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Entries, 2)
+	assert.Equal(t, auditlog.TypeOverride, response.Entries[0].Type)
+	assert.Equal(t, auditlog.DecisionAllow, response.Entries[0].Decision)
+	assert.Equal(t, `{"name":"Stellar","public_key":"GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE","user_id":""}`, response.Entries[0].Data)
+
+	w = get("decision=denied&from=2026-10-15T12:30:00Z")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Entries, 1)
+	assert.Equal(t, int64(2), response.Entries[0].Sequence)
+
+	w = get("after=2")
+	assert.JSONEq(t, `{"entries":[]}`, w.Body.String())
+
+	w = get("format=csv&type=auth_received")
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "sequence,type,transaction_id,counterparty,decision,data,created_at,prev_hash,hash", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "2,auth_received,tx1,stellar.org,denied,null,2026-10-15T13:00:00Z,"))
+
+	w = get("limit=1001")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	requestHandler.HandlerAuditVerify(web.C{}, w, httptest.NewRequest("GET", "/compliance/audit/verify", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"valid":true,"entries":2`)
+}
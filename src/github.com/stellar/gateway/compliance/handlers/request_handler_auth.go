@@ -10,15 +10,18 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/compliance/auditlog"
 
 	"github.com/stellar/gateway/db/entities"
 	"github.com/stellar/gateway/protocols"
 	callback "github.com/stellar/gateway/protocols/compliance"
+	"github.com/stellar/gateway/sanctions"
 	"github.com/stellar/gateway/server"
 	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/gateway/tlspolicy"
 	baseAmount "github.com/stellar/go/amount"
 	"github.com/stellar/go/protocols/compliance"
 	"github.com/stellar/go/xdr"
@@ -32,7 +35,11 @@ func (rh *RequestHandler) HandlerAuth(c web.C, w http.ResponseWriter, r *http.Re
 		Signature: r.PostFormValue("sig"),
 	}
 
-	log.WithFields(log.Fields{"data": authreq.DataJSON, "sig": authreq.Signature}).Info("HandlerAuth")
+	fields := log.Fields{"data": authreq.DataJSON, "sig": authreq.Signature}
+	if certificate := tlspolicy.ClientCertificate(r); certificate != nil {
+		fields["client_certificate"] = certificate.Subject.String()
+	}
+	log.WithFields(fields).Info("HandlerAuth")
 
 	err := authreq.Validate()
 	if err != nil {
@@ -143,9 +150,40 @@ func (rh *RequestHandler) HandlerAuth(c web.C, w http.ResponseWriter, r *http.Re
 	}
 
 	response := compliance.AuthResponse{}
+	// callbacks and screening are recorded in the audit log
+	callbacks := map[string]int{}
+	var screening *sanctions.Result
+
+	// Sanctions screening
+	if rh.Sanctions != nil {
+		subject := sanctions.Subject{
+			Role:      sanctions.RoleSender,
+			Address:   authData.Sender,
+			AccountID: tx.SourceAccount.Address(),
+			Info:      attachment.Transaction.SenderInfo,
+		}
+		result, err := rh.Sanctions.Screen(r.Context(), subject)
+		if err != nil {
+			log.WithFields(log.Fields{"sender": authData.Sender, "err": err}).Error("Error screening sender")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+		rh.recordScreening(hex.EncodeToString(transactionHash[:]), subject, result)
+		screening = &result
+
+		switch result.Status {
+		case sanctions.StatusMatch:
+			response.TxStatus = compliance.AuthStatusDenied
+		case sanctions.StatusReview:
+			response.TxStatus = compliance.AuthStatusPending
+			response.Pending = rh.Config.Sanctions.PendingOrDefault()
+		}
+	}
 
 	// Sanctions check
-	if rh.Config.Callbacks.Sanctions == "" {
+	if response.TxStatus != "" {
+		log.WithFields(log.Fields{"sender": authData.Sender, "tx_status": response.TxStatus}).Warn("Sender matched sanctions screening")
+	} else if rh.Config.Callbacks.Sanctions == "" {
 		response.TxStatus = compliance.AuthStatusOk
 	} else {
 		senderInfo, err := json.Marshal(attachment.Transaction.SenderInfo)
@@ -175,6 +213,7 @@ func (rh *RequestHandler) HandlerAuth(c web.C, w http.ResponseWriter, r *http.Re
 			server.Write(w, protocols.InternalServerError)
 			return
 		}
+		callbacks["sanctions"] = resp.StatusCode
 
 		switch resp.StatusCode {
 		case http.StatusOK: // AuthStatusOk
@@ -230,7 +269,7 @@ func (rh *RequestHandler) HandlerAuth(c web.C, w http.ResponseWriter, r *http.Re
 				return
 			}
 
-			allowedFi, err := rh.Repository.GetAllowedFiByDomain(tokens[1])
+			allowedFi, err := rh.Repository.GetAllowedFiByDomain(r.Context(), tokens[1])
 			if err != nil {
 				log.WithFields(log.Fields{"err": err}).Error("Error getting AllowedFi from DB")
 				server.Write(w, protocols.InternalServerError)
@@ -239,7 +278,7 @@ func (rh *RequestHandler) HandlerAuth(c web.C, w http.ResponseWriter, r *http.Re
 
 			if allowedFi == nil {
 				// FI not found check AllowedUser
-				allowedUser, err := rh.Repository.GetAllowedUserByDomainAndUserID(tokens[1], tokens[0])
+				allowedUser, err := rh.Repository.GetAllowedUserByDomainAndUserID(r.Context(), tokens[1], tokens[0])
 				if err != nil {
 					log.WithFields(log.Fields{"err": err}).Error("Error getting AllowedUser from DB")
 					server.Write(w, protocols.InternalServerError)
@@ -300,6 +339,7 @@ func (rh *RequestHandler) HandlerAuth(c web.C, w http.ResponseWriter, r *http.Re
 				server.Write(w, protocols.InternalServerError)
 				return
 			}
+			callbacks["ask_user"] = resp.StatusCode
 
 			switch resp.StatusCode {
 			case http.StatusOK: // AuthStatusOk
@@ -383,13 +423,35 @@ func (rh *RequestHandler) HandlerAuth(c web.C, w http.ResponseWriter, r *http.Re
 		response.InfoStatus = compliance.AuthStatusOk
 	}
 
+	err = rh.recordAudit(
+		r,
+		auditlog.TypeAuthReceived,
+		hex.EncodeToString(transactionHash[:]),
+		senderDomain(authData.Sender),
+		auditlog.Decision(response.TxStatus, response.InfoStatus),
+		map[string]interface{}{
+			"sender":              authData.Sender,
+			"need_info":           authData.NeedInfo,
+			"tx_status":           response.TxStatus,
+			"info_status":         response.InfoStatus,
+			"pending":             response.Pending,
+			"error":               response.Error,
+			"callbacks":           callbacks,
+			"sanctions_screening": screening,
+		},
+	)
+	if err != nil {
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
 	if response.TxStatus == compliance.AuthStatusOk && response.InfoStatus == compliance.AuthStatusOk {
 		w.WriteHeader(http.StatusOK)
 		authorizedTransaction := &entities.AuthorizedTransaction{
 			TransactionID:  hex.EncodeToString(transactionHash[:]),
 			Memo:           base64.StdEncoding.EncodeToString(memoBytes[:]),
 			TransactionXdr: authData.Tx,
-			AuthorizedAt:   time.Now(),
+			AuthorizedAt:   clock.Now(),
 			Data:           authreq.DataJSON,
 		}
 		err = rh.EntityManager.Persist(authorizedTransaction)
@@ -398,6 +460,20 @@ func (rh *RequestHandler) HandlerAuth(c web.C, w http.ResponseWriter, r *http.Re
 			server.Write(w, protocols.InternalServerError)
 			return
 		}
+
+		err = rh.EntityManager.Persist(&entities.Attachment{
+			MemoHash:      hex.EncodeToString(memoBytes[:]),
+			Direction:     entities.AttachmentDirectionReceived,
+			TransactionID: authorizedTransaction.TransactionID,
+			Counterparty:  senderDomain(authData.Sender),
+			Attachment:    authData.AttachmentJSON,
+			CreatedAt:     authorizedTransaction.AuthorizedAt,
+		})
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Warn("Error persisting Attachment")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
 	} else if response.TxStatus == compliance.AuthStatusDenied || response.InfoStatus == compliance.AuthStatusDenied {
 		w.WriteHeader(http.StatusForbidden)
 	} else if response.TxStatus == compliance.AuthStatusError || response.InfoStatus == compliance.AuthStatusError {
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"crypto/sha256"
-	"github.com/facebookgo/inject"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stellar/gateway/compliance/config"
 	"github.com/stellar/gateway/db/entities"
@@ -44,29 +43,16 @@ func TestRequestHandlerAuth(t *testing.T) {
 	mockFederationResolver := new(mocks.MockFederationResolver)
 	mockSignerVerifier := new(mocks.MockSignerVerifier)
 	mockStellartomlResolver := new(mocks.MockStellartomlResolver)
-	requestHandler := RequestHandler{}
-
-	// Inject mocks
-	var g inject.Graph
-
-	err := g.Provide(
-		&inject.Object{Value: &requestHandler},
-		&inject.Object{Value: c},
-		&inject.Object{Value: mockHTTPClient},
-		&inject.Object{Value: mockEntityManager},
-		&inject.Object{Value: mockRepository},
-		&inject.Object{Value: mockFederationResolver},
-		&inject.Object{Value: mockSignerVerifier},
-		&inject.Object{Value: mockStellartomlResolver},
-		&inject.Object{Value: &TestNonceGenerator{}},
+	requestHandler := NewRequestHandler(
+		c,
+		mockHTTPClient,
+		mockEntityManager,
+		mockRepository,
+		mockSignerVerifier,
+		mockStellartomlResolver,
+		mockFederationResolver,
+		&TestNonceGenerator{},
 	)
-	if err != nil {
-		panic(err)
-	}
-
-	if err := g.Populate(); err != nil {
-		panic(err)
-	}
 
 	httpHandle := func(w http.ResponseWriter, r *http.Request) {
 		requestHandler.HandlerAuth(web.C{}, w, r)
@@ -291,6 +277,11 @@ func TestRequestHandlerAuth(t *testing.T) {
 					assert.Equal(t, authorizedTransaction.Data, value.Data)
 				}).Return(nil).Once()
 
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.Attachment"),
+				).Return(nil).Once()
+
 				statusCode, response := net.GetResponse(testServer, params)
 				responseString := strings.TrimSpace(string(response))
 				assert.Equal(t, 200, statusCode)
@@ -468,6 +459,11 @@ func TestRequestHandlerAuth(t *testing.T) {
 					assert.Equal(t, authorizedTransaction.Data, value.Data)
 				}).Return(nil).Once()
 
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.Attachment"),
+				).Return(nil).Once()
+
 				statusCode, response := net.GetResponse(testServer, params)
 				responseString := strings.TrimSpace(string(response))
 				assert.Equal(t, 200, statusCode)
@@ -672,6 +668,11 @@ func TestRequestHandlerAuth(t *testing.T) {
 					assert.Equal(t, authorizedTransaction.Data, value.Data)
 				}).Return(nil).Once()
 
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.Attachment"),
+				).Return(nil).Once()
+
 				statusCode, response := net.GetResponse(testServer, params)
 				responseString := strings.TrimSpace(string(response))
 				assert.Equal(t, 200, statusCode)
@@ -723,6 +724,11 @@ func TestRequestHandlerAuth(t *testing.T) {
 						assert.Equal(t, authorizedTransaction.Data, value.Data)
 					}).Return(nil).Once()
 
+					mockEntityManager.On(
+						"Persist",
+						mock.AnythingOfType("*entities.Attachment"),
+					).Return(nil).Once()
+
 					statusCode, response := net.GetResponse(testServer, params)
 					responseString := strings.TrimSpace(string(response))
 					assert.Equal(t, 200, statusCode)
@@ -780,6 +786,11 @@ func TestRequestHandlerAuth(t *testing.T) {
 						assert.Equal(t, authorizedTransaction.Data, value.Data)
 					}).Return(nil).Once()
 
+					mockEntityManager.On(
+						"Persist",
+						mock.AnythingOfType("*entities.Attachment"),
+					).Return(nil).Once()
+
 					statusCode, response := net.GetResponse(testServer, params)
 					responseString := strings.TrimSpace(string(response))
 					assert.Equal(t, 200, statusCode)
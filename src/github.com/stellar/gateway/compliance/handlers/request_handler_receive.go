@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"encoding/json"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 
+	"github.com/stellar/gateway/crypto"
 	"github.com/stellar/gateway/protocols"
 	callback "github.com/stellar/gateway/protocols/compliance"
 	"github.com/stellar/gateway/server"
+	"github.com/stellar/go/protocols/compliance"
 	"github.com/zenazn/goji/web"
 )
 
@@ -28,7 +31,7 @@ func (rh *RequestHandler) HandlerReceive(c web.C, w http.ResponseWriter, r *http
 		return
 	}
 
-	authorizedTransaction, err := rh.Repository.GetAuthorizedTransactionByMemo(request.Memo)
+	authorizedTransaction, err := rh.Repository.GetAuthorizedTransactionByMemo(r.Context(), request.Memo)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error getting authorizedTransaction")
 		server.Write(w, protocols.InternalServerError)
@@ -41,6 +44,43 @@ func (rh *RequestHandler) HandlerReceive(c web.C, w http.ResponseWriter, r *http
 		return
 	}
 
-	response := callback.ReceiveResponse{Data: authorizedTransaction.Data}
+	response := callback.ReceiveResponse{
+		Data:        authorizedTransaction.Data,
+		PrivateNote: rh.privateNote(authorizedTransaction.Data),
+	}
 	server.Write(w, &response)
 }
+
+// privateNote returns the decrypted private note of the attachment in
+// authDataJSON or an empty string when there is no note. Notes that cannot be
+// decrypted are logged and skipped, the payment has been received already.
+func (rh *RequestHandler) privateNote(authDataJSON string) string {
+	var authData compliance.AuthData
+	err := json.Unmarshal([]byte(authDataJSON), &authData)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Warn("Error unmarshalling authData")
+		return ""
+	}
+
+	attachment, err := callback.ParseAttachment(authData.AttachmentJSON)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Warn("Error unmarshalling attachment")
+		return ""
+	}
+
+	if attachment.Transaction.EncryptedNote == "" {
+		return ""
+	}
+
+	if rh.Config.Keys.EncryptionKey == "" {
+		log.Error("Cannot decrypt private note, keys.encryption_key param is not set")
+		return ""
+	}
+
+	note, err := crypto.Decrypt(rh.Config.Keys.EncryptionKey, attachment.Transaction.EncryptedNote)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Cannot decrypt private note")
+		return ""
+	}
+	return string(note)
+}
@@ -1,20 +1,23 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
 
-	"github.com/facebookgo/inject"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stellar/gateway/compliance/config"
+	"github.com/stellar/gateway/crypto"
 	"github.com/stellar/gateway/db/entities"
 	"github.com/stellar/gateway/mocks"
 	"github.com/stellar/gateway/net"
 	callback "github.com/stellar/gateway/protocols/compliance"
+	"github.com/stellar/go/protocols/compliance"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/zenazn/goji/web"
 )
 
@@ -33,29 +36,16 @@ func TestRequestHandlerReceive(t *testing.T) {
 	mockFederationResolver := new(mocks.MockFederationResolver)
 	mockSignerVerifier := new(mocks.MockSignerVerifier)
 	mockStellartomlResolver := new(mocks.MockStellartomlResolver)
-	requestHandler := RequestHandler{}
-
-	// Inject mocks
-	var g inject.Graph
-
-	err := g.Provide(
-		&inject.Object{Value: &requestHandler},
-		&inject.Object{Value: c},
-		&inject.Object{Value: mockHTTPClient},
-		&inject.Object{Value: mockEntityManager},
-		&inject.Object{Value: mockRepository},
-		&inject.Object{Value: mockFederationResolver},
-		&inject.Object{Value: mockSignerVerifier},
-		&inject.Object{Value: mockStellartomlResolver},
-		&inject.Object{Value: &TestNonceGenerator{}},
+	requestHandler := NewRequestHandler(
+		c,
+		mockHTTPClient,
+		mockEntityManager,
+		mockRepository,
+		mockSignerVerifier,
+		mockStellartomlResolver,
+		mockFederationResolver,
+		&TestNonceGenerator{},
 	)
-	if err != nil {
-		panic(err)
-	}
-
-	if err := g.Populate(); err != nil {
-		panic(err)
-	}
 
 	httpHandle := func(w http.ResponseWriter, r *http.Request) {
 		requestHandler.HandlerReceive(web.C{}, w, r)
@@ -98,3 +88,60 @@ func TestRequestHandlerReceive(t *testing.T) {
 		})
 	})
 }
+
+func TestRequestHandlerReceivePrivateNote(t *testing.T) {
+	c := &config.Config{
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+		Keys: config.Keys{
+			SigningSeed:   "SDWTLFPALQSP225BSMX7HPZ7ZEAYSUYNDLJ5QI3YGVBNRUIIELWH3XUV",
+			EncryptionKey: "d2VsY29tZSB0byB0aGUgYnJpZGdlIHNlcnZlciAhISE=",
+		},
+	}
+
+	mockRepository := new(mocks.MockRepository)
+	requestHandler := NewRequestHandler(
+		c,
+		new(mocks.MockHTTPClient),
+		new(mocks.MockEntityManager),
+		mockRepository,
+		new(mocks.MockSignerVerifier),
+		new(mocks.MockStellartomlResolver),
+		new(mocks.MockFederationResolver),
+		&TestNonceGenerator{},
+	)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestHandler.HandlerReceive(web.C{}, w, r)
+	}))
+	defer testServer.Close()
+
+	encryptionKey, err := crypto.EncryptionPublicKey(c.Keys.EncryptionKey)
+	require.NoError(t, err)
+	attachment := callback.Attachment{}
+	attachment.Transaction.EncryptedNote, err = crypto.Encrypt(encryptionKey, []byte("Invoice 2026-1015"))
+	require.NoError(t, err)
+	attachmentJSON, err := attachment.Marshal()
+	require.NoError(t, err)
+	data, err := json.Marshal(compliance.AuthData{Sender: "alice*stellar.org", AttachmentJSON: string(attachmentJSON)})
+	require.NoError(t, err)
+
+	memo := "0c2d5a3c47bd4bc8cbcbbd29fb8ee8c1ac1b7c0e7d2f1a49d0a1c8f0ba0e5d11"
+	mockRepository.On("GetAuthorizedTransactionByMemo", memo).Return(
+		&entities.AuthorizedTransaction{Memo: memo, Data: string(data)},
+		nil,
+	)
+
+	statusCode, response := net.GetResponse(testServer, url.Values{"memo": {memo}})
+	assert.Equal(t, 200, statusCode)
+
+	var receiveResponse callback.ReceiveResponse
+	require.NoError(t, json.Unmarshal(response, &receiveResponse))
+	assert.Equal(t, string(data), receiveResponse.Data)
+	assert.Equal(t, "Invoice 2026-1015", receiveResponse.PrivateNote)
+
+	// Notes are not returned when the key does not match
+	c.Keys.EncryptionKey = "b3RoZXIga2V5IG9mIHRoZSByZWNlaXZpbmcgYnJpZGdl"
+	statusCode, response = net.GetResponse(testServer, url.Values{"memo": {memo}})
+	assert.Equal(t, 200, statusCode)
+	assert.NotContains(t, string(response), "private_note")
+}
@@ -4,6 +4,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"net/http"
 
+	"github.com/stellar/gateway/compliance/auditlog"
 	"github.com/stellar/gateway/protocols"
 	"github.com/stellar/gateway/server"
 	"github.com/zenazn/goji/web"
@@ -19,7 +20,7 @@ func (rh *RequestHandler) HandlerRemoveAccess(c web.C, w http.ResponseWriter, r
 	var entityManagerErr error
 
 	if userID != "" {
-		allowedUser, err := rh.Repository.GetAllowedUserByDomainAndUserID(domain, userID)
+		allowedUser, err := rh.Repository.GetAllowedUserByDomainAndUserID(r.Context(), domain, userID)
 		if err != nil {
 			log.WithFields(log.Fields{"err": err}).Warn("Error getting allowed user")
 			server.Write(w, protocols.InternalServerError)
@@ -34,7 +35,7 @@ func (rh *RequestHandler) HandlerRemoveAccess(c web.C, w http.ResponseWriter, r
 
 		entityManagerErr = rh.EntityManager.Delete(allowedUser)
 	} else {
-		allowedFi, err := rh.Repository.GetAllowedFiByDomain(domain)
+		allowedFi, err := rh.Repository.GetAllowedFiByDomain(r.Context(), domain)
 		if err != nil {
 			log.WithFields(log.Fields{"err": err}).Warn("Error getting allowed FI")
 			server.Write(w, protocols.InternalServerError)
@@ -56,5 +57,13 @@ func (rh *RequestHandler) HandlerRemoveAccess(c web.C, w http.ResponseWriter, r
 		return
 	}
 
+	err := rh.recordAudit(r, auditlog.TypeOverride, "", domain, auditlog.DecisionRemove, map[string]string{
+		"user_id": userID,
+	})
+	if err != nil {
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 }
@@ -1,14 +1,22 @@
 package handlers
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/compliance/auditlog"
+	"github.com/stellar/gateway/compliance/outbound"
+	"github.com/stellar/gateway/crypto"
+	"github.com/stellar/gateway/db/entities"
 	"github.com/stellar/gateway/protocols"
 	callback "github.com/stellar/gateway/protocols/compliance"
+	"github.com/stellar/gateway/sanctions"
 	"github.com/stellar/gateway/server"
+	"github.com/stellar/gateway/snapshots"
 	"github.com/stellar/gateway/submitter"
 	"github.com/stellar/go/address"
 	b "github.com/stellar/go/build"
@@ -60,7 +68,7 @@ func (rh *RequestHandler) HandlerSend(c web.C, w http.ResponseWriter, r *http.Re
 			return
 		}
 	} else {
-		destinationObject, err = rh.FederationResolver.ForwardRequest(request.ForwardDestination.Domain, request.ForwardDestination.Fields)
+		destinationObject, err = rh.FederationResolver.ForwardRequest(request.ForwardDestination.Domain, request.ForwardDestination.Query())
 		if err != nil {
 			log.WithFields(log.Fields{
 				"destination": request.Destination,
@@ -73,6 +81,50 @@ func (rh *RequestHandler) HandlerSend(c web.C, w http.ResponseWriter, r *http.Re
 		domain = request.ForwardDestination.Domain
 	}
 
+	var usedSnapshots []*snapshots.Snapshot
+	if rh.Snapshots != nil {
+		usedSnapshots = rh.Snapshots.Destination(request.Destination, request.ForwardDestination)
+	}
+
+	var screening *sanctions.Result
+	// Checked in Validate
+	receiverInfo, _ := callback.ParseInfo("receiver_info", request.ReceiverInfo)
+	receiver := sanctions.Subject{
+		Role:      sanctions.RoleReceiver,
+		Address:   request.Destination,
+		AccountID: destinationObject.AccountID,
+		Info:      receiverInfo,
+	}
+	if rh.Sanctions != nil {
+		result, err := rh.Sanctions.Screen(r.Context(), receiver)
+		if err != nil {
+			log.WithFields(log.Fields{"destination": request.Destination, "err": err}).Error("Error screening receiver")
+			server.Write(w, protocols.InternalServerError)
+			return
+		}
+
+		if result.Status != sanctions.StatusClear {
+			rh.recordScreening("", receiver, result)
+			log.WithFields(log.Fields{"destination": request.Destination, "status": result.Status, "provider": result.Provider}).Warn("Receiver matched sanctions screening")
+			err = rh.recordAudit(r, auditlog.TypeSendRejected, "", domain, result.Status, map[string]interface{}{
+				"sender":              request.Sender,
+				"destination":         request.Destination,
+				"sanctions_screening": result,
+			})
+			if err != nil {
+				server.Write(w, protocols.InternalServerError)
+				return
+			}
+			if result.Status == sanctions.StatusMatch {
+				server.Write(w, callback.DestinationSanctioned)
+			} else {
+				server.Write(w, callback.DestinationSanctionsReview)
+			}
+			return
+		}
+		screening = &result
+	}
+
 	stellarToml, err := rh.StellarTomlResolver.GetStellarToml(domain)
 	if err != nil {
 		log.WithFields(log.Fields{
@@ -143,7 +195,7 @@ func (rh *RequestHandler) HandlerSend(c web.C, w http.ResponseWriter, r *http.Re
 	senderInfo := make(map[string]string)
 
 	if rh.Config.Callbacks.FetchInfo != "" {
-		fetchInfoRequest := callback.FetchInfoRequest{Address: request.Sender}
+		fetchInfoRequest := callback.FetchInfoRequest{Address: request.Sender, SenderID: request.SenderID}
 		resp, err := rh.Client.PostForm(
 			rh.Config.Callbacks.FetchInfo,
 			fetchInfoRequest.ToValues(),
@@ -189,15 +241,40 @@ func (rh *RequestHandler) HandlerSend(c web.C, w http.ResponseWriter, r *http.Re
 		}
 	}
 
-	attachment := &compliance.Attachment{
+	// Checked in Validate
+	senderInfoOverrides, _ := callback.ParseInfo("sender_info", request.SenderInfo)
+	senderInfo = callback.MergeInfo(senderInfo, senderInfoOverrides)
+
+	route := destinationObject.Memo.Value
+	if request.Route != "" {
+		route = request.Route
+	}
+
+	attachment := &callback.Attachment{
 		Nonce: rh.NonceGenerator.Generate(),
-		Transaction: compliance.Transaction{
-			SenderInfo: senderInfo,
-			Route:      compliance.Route(destinationObject.Memo.Value),
-			Extra:      request.ExtraMemo,
+		Transaction: callback.Transaction{
+			Transaction: compliance.Transaction{
+				SenderInfo: senderInfo,
+				Route:      compliance.Route(route),
+				Note:       request.Note,
+				Extra:      request.ExtraMemo,
+			},
 		},
 	}
 
+	if request.PrivateNote != "" {
+		attachment.Transaction.EncryptedNote, err = crypto.Encrypt(stellarToml.EncryptionKey, []byte(request.PrivateNote))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"domain":         domain,
+				"encryption_key": stellarToml.EncryptionKey,
+				"err":            err,
+			}).Warn("Cannot encrypt private note")
+			server.Write(w, callback.EncryptionKeyNotDefined)
+			return
+		}
+	}
+
 	attachmentJSON, err := attachment.Marshal()
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Error("Error marshalling attachment")
@@ -250,11 +327,15 @@ func (rh *RequestHandler) HandlerSend(c web.C, w http.ResponseWriter, r *http.Re
 		DataJSON:  string(data),
 		Signature: sig,
 	}
-	resp, err := rh.Client.PostForm(
-		stellarToml.AuthServer,
-		authRequest.ToURLValues(),
-	)
-	if err != nil {
+	resp, err := rh.postAuthRequest(domain, stellarToml.AuthServer, authRequest)
+	if err == outbound.ErrTimeout {
+		log.WithFields(log.Fields{
+			"auth_server": stellarToml.AuthServer,
+			"domain":      domain,
+		}).Warn("Timeout waiting in outbound queue")
+		server.Write(w, callback.AuthServerBusy)
+		return
+	} else if err != nil {
 		log.WithFields(log.Fields{
 			"auth_server": stellarToml.AuthServer,
 			"err":         err,
@@ -291,9 +372,105 @@ func (rh *RequestHandler) HandlerSend(c web.C, w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	transactionHash, err := submitter.TransactionHash(transaction, rh.Config.NetworkPassphrase)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error hashing transaction")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	sentAttachment := &entities.SentAttachment{
+		TransactionID: hex.EncodeToString(transactionHash[:]),
+		Memo:          hex.EncodeToString(attachmentHashBytes[:]),
+		Sender:        request.Sender,
+		SenderID:      request.SenderID,
+		ReceiverInfo:  request.ReceiverInfo,
+		Attachment:    string(attachmentJSON),
+		SentAt:        clock.Now(),
+	}
+	err = rh.EntityManager.Persist(sentAttachment)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Warn("Error persisting SentAttachment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	err = rh.EntityManager.Persist(&entities.Attachment{
+		MemoHash:      sentAttachment.Memo,
+		Direction:     entities.AttachmentDirectionSent,
+		TransactionID: sentAttachment.TransactionID,
+		Counterparty:  domain,
+		Attachment:    sentAttachment.Attachment,
+		CreatedAt:     sentAttachment.SentAt,
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Warn("Error persisting Attachment")
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	if screening != nil {
+		rh.recordScreening(sentAttachment.TransactionID, receiver, *screening)
+	}
+
+	err = rh.recordAudit(
+		r,
+		auditlog.TypeAuthSent,
+		sentAttachment.TransactionID,
+		domain,
+		auditlog.Decision(authResponse.TxStatus, authResponse.InfoStatus),
+		map[string]interface{}{
+			"sender":              request.Sender,
+			"destination":         request.Destination,
+			"auth_server":         stellarToml.AuthServer,
+			"status":              resp.StatusCode,
+			"tx_status":           authResponse.TxStatus,
+			"info_status":         authResponse.InfoStatus,
+			"pending":             authResponse.Pending,
+			"error":               authResponse.Error,
+			"sanctions_screening": screening,
+		},
+	)
+	if err != nil {
+		server.Write(w, protocols.InternalServerError)
+		return
+	}
+
+	now := clock.Now()
+	for _, snapshot := range usedSnapshots {
+		entity, err := snapshot.Entity(sentAttachment.TransactionID, nil, now)
+		if err == nil {
+			err = rh.EntityManager.Persist(entity)
+		}
+		if err != nil {
+			log.WithFields(log.Fields{"transaction_id": sentAttachment.TransactionID, "url": snapshot.URL, "err": err}).Error("Error persisting FederationSnapshot")
+		}
+	}
+
 	response := callback.SendResponse{
 		AuthResponse:   authResponse,
 		TransactionXdr: txBase64,
 	}
 	server.Write(w, &response)
 }
+
+// postAuthRequest sends authRequest to auth server of domain, waiting in
+// OutboundQueue when it's set
+func (rh *RequestHandler) postAuthRequest(domain, authServer string, authRequest compliance.AuthRequest) (resp *http.Response, err error) {
+	client := rh.AuthClient
+	if client == nil {
+		client = rh.Client
+	}
+
+	if rh.OutboundQueue == nil {
+		return client.PostForm(authServer, authRequest.ToURLValues())
+	}
+
+	queueErr := rh.OutboundQueue.Do(domain, func() {
+		resp, err = client.PostForm(authServer, authRequest.ToURLValues())
+	})
+	if queueErr != nil {
+		return nil, queueErr
+	}
+	return
+}
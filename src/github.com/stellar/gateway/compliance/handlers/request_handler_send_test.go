@@ -7,7 +7,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/facebookgo/inject"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stellar/gateway/compliance/config"
 	"github.com/stellar/gateway/mocks"
@@ -19,6 +18,7 @@ import (
 	"github.com/stellar/go/protocols/federation"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/zenazn/goji/web"
 )
@@ -41,29 +41,16 @@ func TestRequestHandlerSend(t *testing.T) {
 	mockFederationResolver := new(mocks.MockFederationResolver)
 	mockSignerVerifier := new(mocks.MockSignerVerifier)
 	mockStellartomlResolver := new(mocks.MockStellartomlResolver)
-	requestHandler := RequestHandler{}
-
-	// Inject mocks
-	var g inject.Graph
-
-	err := g.Provide(
-		&inject.Object{Value: &requestHandler},
-		&inject.Object{Value: c},
-		&inject.Object{Value: mockHTTPClient},
-		&inject.Object{Value: mockEntityManager},
-		&inject.Object{Value: mockRepository},
-		&inject.Object{Value: mockFederationResolver},
-		&inject.Object{Value: mockSignerVerifier},
-		&inject.Object{Value: mockStellartomlResolver},
-		&inject.Object{Value: &TestNonceGenerator{}},
+	requestHandler := NewRequestHandler(
+		c,
+		mockHTTPClient,
+		mockEntityManager,
+		mockRepository,
+		mockSignerVerifier,
+		mockStellartomlResolver,
+		mockFederationResolver,
+		&TestNonceGenerator{},
 	)
-	if err != nil {
-		panic(err)
-	}
-
-	if err := g.Populate(); err != nil {
-		panic(err)
-	}
 
 	httpHandle := func(w http.ResponseWriter, r *http.Request) {
 		requestHandler.HandlerSend(web.C{}, w, r)
@@ -219,6 +206,16 @@ func TestRequestHandlerSend(t *testing.T) {
 					[]byte(authRequest.DataJSON),
 				).Return(authRequest.Signature, nil).Once()
 
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.SentAttachment"),
+				).Return(nil).Once()
+
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.Attachment"),
+				).Return(nil).Once()
+
 				statusCode, response := net.GetResponse(testServer, params)
 				responseString := strings.TrimSpace(string(response))
 				assert.Equal(t, 200, statusCode)
@@ -343,6 +340,16 @@ func TestRequestHandlerSend(t *testing.T) {
 					[]byte(authRequest.DataJSON),
 				).Return(authRequest.Signature, nil).Once()
 
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.SentAttachment"),
+				).Return(nil).Once()
+
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.Attachment"),
+				).Return(nil).Once()
+
 				statusCode, response := net.GetResponse(testServer, params)
 				responseString := strings.TrimSpace(string(response))
 				assert.Equal(t, 200, statusCode)
@@ -460,6 +467,16 @@ func TestRequestHandlerSend(t *testing.T) {
 					[]byte(authRequest.DataJSON),
 				).Return(authRequest.Signature, nil).Once()
 
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.SentAttachment"),
+				).Return(nil).Once()
+
+				mockEntityManager.On(
+					"Persist",
+					mock.AnythingOfType("*entities.Attachment"),
+				).Return(nil).Once()
+
 				statusCode, response := net.GetResponse(testServer, params)
 				responseString := strings.TrimSpace(string(response))
 				assert.Equal(t, 200, statusCode)
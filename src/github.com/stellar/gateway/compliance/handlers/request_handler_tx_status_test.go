@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/facebookgo/inject"
 	"github.com/goji/httpauth"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stellar/gateway/compliance/config"
@@ -31,29 +30,16 @@ func TestRequestHandlerTxStatus(t *testing.T) {
 	mockFederationResolver := new(mocks.MockFederationResolver)
 	mockSignerVerifier := new(mocks.MockSignerVerifier)
 	mockStellartomlResolver := new(mocks.MockStellartomlResolver)
-	requestHandler := RequestHandler{}
-
-	// Inject mocks
-	var g inject.Graph
-
-	err := g.Provide(
-		&inject.Object{Value: &requestHandler},
-		&inject.Object{Value: c},
-		&inject.Object{Value: mockHTTPClient},
-		&inject.Object{Value: mockEntityManager},
-		&inject.Object{Value: mockRepository},
-		&inject.Object{Value: mockFederationResolver},
-		&inject.Object{Value: mockSignerVerifier},
-		&inject.Object{Value: mockStellartomlResolver},
-		&inject.Object{Value: &TestNonceGenerator{}},
+	requestHandler := NewRequestHandler(
+		c,
+		mockHTTPClient,
+		mockEntityManager,
+		mockRepository,
+		mockSignerVerifier,
+		mockStellartomlResolver,
+		mockFederationResolver,
+		&TestNonceGenerator{},
 	)
-	if err != nil {
-		panic(err)
-	}
-
-	if err := g.Populate(); err != nil {
-		panic(err)
-	}
 
 	httpHandle := func(w http.ResponseWriter, r *http.Request) {
 		requestHandler.HandlerTxStatus(w, r)
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/sanctions"
+)
+
+// recordScreening persists a result of sanctions screening in the compliance
+// audit trail. Errors are logged only, the result has been used already.
+func (rh *RequestHandler) recordScreening(transactionID string, subject sanctions.Subject, result sanctions.Result) {
+	entity, err := result.Entity(transactionID, subject, clock.Now())
+	if err == nil {
+		err = rh.EntityManager.Persist(entity)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"transaction_id": transactionID, "address": subject.Address, "err": err}).Error("Error persisting SanctionsScreening")
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/compliance/config"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/sanctions"
+	"github.com/stellar/go/protocols/federation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zenazn/goji/web"
+)
+
+func TestRequestHandlerSendSanctions(t *testing.T) {
+	c := &config.Config{NetworkPassphrase: "Test SDF Network ; September 2015"}
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockFederationResolver := new(mocks.MockFederationResolver)
+	mockStellartomlResolver := new(mocks.MockStellartomlResolver)
+	requestHandler := NewRequestHandler(c, nil, mockEntityManager, nil, nil, mockStellartomlResolver, mockFederationResolver, &TestNonceGenerator{})
+
+	list, err := sanctions.ReadList(strings.NewReader("Doe, John\n"))
+	require.NoError(t, err)
+	requestHandler.Sanctions = sanctions.Screeners{list}
+
+	mockFederationResolver.On("LookupByAddress", "bob*stellar.org").Return(&federation.NameResponse{
+		AccountID: "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE",
+	}, nil).Once()
+	mockEntityManager.On("Persist", mock.MatchedBy(func(screening *entities.SanctionsScreening) bool {
+		return screening.TransactionID == "" &&
+			screening.Role == sanctions.RoleReceiver &&
+			screening.AccountID == "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE" &&
+			screening.Status == sanctions.StatusMatch &&
+			screening.Matches == `["Doe, John"]`
+	})).Return(nil).Once()
+
+	params := url.Values{
+		"source":        {"GAW77Z6GPWXSODJOMF5L5BMX6VMYGEJRKUNBC2CZ725JTQZORK74HQQD"},
+		"sender":        {"alice*stellar.org"},
+		"destination":   {"bob*stellar.org"},
+		"amount":        {"20"},
+		"asset_code":    {"USD"},
+		"asset_issuer":  {"GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"},
+		"receiver_info": {`{"first_name": "John", "last_name": "Doe"}`},
+	}
+	r := httptest.NewRequest("POST", "/send", strings.NewReader(params.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	requestHandler.HandlerSend(web.C{}, w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "destination_sanctioned")
+	mockFederationResolver.AssertExpectations(t)
+	mockEntityManager.AssertExpectations(t)
+	// Auth request is not sent
+	mockStellartomlResolver.AssertNotCalled(t, "GetStellarToml", mock.Anything)
+}
@@ -0,0 +1,227 @@
+// Package outbound limits concurrency of requests sent by the compliance
+// server to auth servers of other organizations.
+package outbound
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/metrics"
+)
+
+// ErrTimeout is returned by Acquire when request could not be started in time
+var ErrTimeout = errors.New("Timeout waiting for a free outbound request slot")
+
+var queueMetrics = struct {
+	running  *metrics.Gauge
+	waiting  *metrics.Gauge
+	timeouts *metrics.Counter
+}{
+	running:  metrics.NewGauge("compliance_outbound_requests_running", "Number of requests being sent to auth servers."),
+	waiting:  metrics.NewGauge("compliance_outbound_requests_waiting", "Number of requests waiting for a free slot."),
+	timeouts: metrics.NewCounter("compliance_outbound_requests_timeouts_total", "Number of requests that timed out waiting for a free slot."),
+}
+
+// Queue limits the number of concurrent requests sent to a single domain
+// (PerDomain) and to all domains (Workers). When all workers are busy, free
+// workers are given to waiting domains in round-robin order so a domain with
+// many queued requests does not delay requests to other domains.
+type Queue struct {
+	// PerDomain is the max number of concurrent requests to a single domain, 0 means no limit
+	PerDomain int
+	// Workers is the max number of concurrent requests to all domains, 0 means no limit
+	Workers int
+	// Timeout is the max time a request waits for a free slot, 0 means no limit
+	Timeout time.Duration
+
+	mutex   sync.Mutex
+	running int
+	waiting int
+	domains map[string]*domain
+	// ring contains domains with waiting requests
+	ring []string
+	next int
+}
+
+type domain struct {
+	running int
+	waiting []chan struct{}
+}
+
+// NewQueue creates a new Queue
+func NewQueue(perDomain, workers int, timeout time.Duration) *Queue {
+	return &Queue{
+		PerDomain: perDomain,
+		Workers:   workers,
+		Timeout:   timeout,
+		domains:   make(map[string]*domain),
+	}
+}
+
+// Do sends a request to a domain using request function when there is a
+// free slot for it
+func (q *Queue) Do(domainName string, request func()) error {
+	err := q.Acquire(domainName)
+	if err != nil {
+		return err
+	}
+	defer q.Release(domainName)
+
+	request()
+	return nil
+}
+
+// Acquire waits until a request to a domain can be sent. Release must be
+// called when the request is finished.
+func (q *Queue) Acquire(domainName string) error {
+	q.mutex.Lock()
+	d := q.domain(domainName)
+
+	if len(d.waiting) == 0 && q.canRun(d) {
+		q.start(d)
+		q.mutex.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	if len(d.waiting) == 0 {
+		q.ring = append(q.ring, domainName)
+	}
+	d.waiting = append(d.waiting, ready)
+	q.waiting++
+	q.updateMetrics()
+	q.mutex.Unlock()
+
+	if q.Timeout == 0 {
+		<-ready
+		return nil
+	}
+
+	timer := time.NewTimer(q.Timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return nil
+	case <-timer.C:
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, waiting := range d.waiting {
+		if waiting == ready {
+			d.waiting = append(d.waiting[:i], d.waiting[i+1:]...)
+			q.waiting--
+			if len(d.waiting) == 0 {
+				q.removeFromRing(domainName)
+				if d.running == 0 {
+					delete(q.domains, domainName)
+				}
+			}
+			q.updateMetrics()
+			queueMetrics.timeouts.Inc()
+			return ErrTimeout
+		}
+	}
+
+	// Slot has been given right after the timeout
+	return nil
+}
+
+// Release frees a slot taken by Acquire
+func (q *Queue) Release(domainName string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	d := q.domain(domainName)
+	d.running--
+	q.running--
+	q.dispatch()
+
+	if d.running == 0 && len(d.waiting) == 0 {
+		delete(q.domains, domainName)
+	}
+	q.updateMetrics()
+}
+
+func (q *Queue) domain(domainName string) *domain {
+	if q.domains == nil {
+		q.domains = make(map[string]*domain)
+	}
+
+	d, ok := q.domains[domainName]
+	if !ok {
+		d = &domain{}
+		q.domains[domainName] = d
+	}
+	return d
+}
+
+func (q *Queue) canRun(d *domain) bool {
+	return (q.PerDomain == 0 || d.running < q.PerDomain) &&
+		(q.Workers == 0 || q.running < q.Workers)
+}
+
+func (q *Queue) start(d *domain) {
+	d.running++
+	q.running++
+	q.updateMetrics()
+}
+
+// dispatch starts waiting requests while there are free workers, taking one
+// request from every domain in turn
+func (q *Queue) dispatch() {
+	for len(q.ring) > 0 && (q.Workers == 0 || q.running < q.Workers) {
+		started := false
+
+		for i := 0; i < len(q.ring); i++ {
+			if q.next >= len(q.ring) {
+				q.next = 0
+			}
+
+			domainName := q.ring[q.next]
+			d := q.domains[domainName]
+			if !q.canRun(d) {
+				q.next++
+				continue
+			}
+
+			ready := d.waiting[0]
+			d.waiting = d.waiting[1:]
+			q.waiting--
+			q.start(d)
+			close(ready)
+			started = true
+
+			if len(d.waiting) == 0 {
+				q.removeFromRing(domainName)
+			} else {
+				q.next++
+			}
+			break
+		}
+
+		if !started {
+			return
+		}
+	}
+}
+
+func (q *Queue) removeFromRing(domainName string) {
+	for i, name := range q.ring {
+		if name == domainName {
+			q.ring = append(q.ring[:i], q.ring[i+1:]...)
+			if q.next > i {
+				q.next--
+			}
+			return
+		}
+	}
+}
+
+func (q *Queue) updateMetrics() {
+	queueMetrics.running.Set(float64(q.running))
+	queueMetrics.waiting.Set(float64(q.waiting))
+}
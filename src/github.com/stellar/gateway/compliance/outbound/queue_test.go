@@ -0,0 +1,90 @@
+package outbound
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enqueue starts Acquire in a goroutine and waits until it's queued. Domain
+// name is sent to started when request starts.
+func enqueue(t *testing.T, q *Queue, domainName string, started chan string) {
+	q.mutex.Lock()
+	waiting := q.waiting
+	q.mutex.Unlock()
+
+	go func() {
+		if q.Acquire(domainName) == nil {
+			started <- domainName
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		q.mutex.Lock()
+		queued := q.waiting > waiting
+		q.mutex.Unlock()
+		if queued {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("request has not been queued")
+}
+
+func TestQueuePerDomainLimit(t *testing.T) {
+	q := NewQueue(2, 0, 0)
+	started := make(chan string, 10)
+
+	require.NoError(t, q.Acquire("a.com"))
+	require.NoError(t, q.Acquire("a.com"))
+	// Other domains are not limited by a.com requests
+	require.NoError(t, q.Acquire("b.com"))
+
+	enqueue(t, q, "a.com", started)
+	assert.Len(t, started, 0)
+
+	q.Release("b.com")
+	assert.Len(t, started, 0)
+
+	q.Release("a.com")
+	assert.Equal(t, "a.com", <-started)
+}
+
+func TestQueueFairness(t *testing.T) {
+	q := NewQueue(0, 1, 0)
+	started := make(chan string, 10)
+
+	require.NoError(t, q.Acquire("slow.com"))
+	enqueue(t, q, "slow.com", started)
+	enqueue(t, q, "slow.com", started)
+	enqueue(t, q, "slow.com", started)
+	enqueue(t, q, "fast.com", started)
+
+	var order []string
+	current := "slow.com"
+	for i := 0; i < 4; i++ {
+		q.Release(current)
+		current = <-started
+		order = append(order, current)
+	}
+	q.Release(current)
+
+	assert.Equal(t, []string{"slow.com", "fast.com", "slow.com", "slow.com"}, order)
+	assert.Empty(t, q.domains)
+	assert.Empty(t, q.ring)
+}
+
+func TestQueueTimeout(t *testing.T) {
+	q := NewQueue(1, 0, 10*time.Millisecond)
+
+	require.NoError(t, q.Acquire("a.com"))
+	assert.Equal(t, ErrTimeout, q.Acquire("a.com"))
+	assert.Equal(t, 0, q.waiting)
+	assert.Empty(t, q.ring)
+
+	q.Release("a.com")
+	assert.NoError(t, q.Do("a.com", func() {}))
+	assert.Empty(t, q.domains)
+}
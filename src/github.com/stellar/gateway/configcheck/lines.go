@@ -0,0 +1,181 @@
+package configcheck
+
+import (
+	"strconv"
+	"strings"
+)
+
+// location is a line of a key (or a table header) in a config file
+type location struct {
+	// path is a lowercase path of the key, ex. "signers[1].tls.ca_file"
+	path string
+	line int
+	// value is a string value of the key, empty for other values
+	value string
+}
+
+// locate returns locations of keys and table headers in data. It doesn't
+// validate the file, it's called after the file has been decoded.
+func locate(data []byte) []location {
+	var result []location
+	// arrays contains numbers of elements of arrays of tables
+	arrays := map[string]int{}
+	table := ""
+	multiline := ""
+	brackets := 0
+
+	for i, line := range strings.Split(string(data), "\n") {
+		number := i + 1
+
+		if multiline != "" {
+			if strings.Contains(line, multiline) {
+				multiline = ""
+			}
+			continue
+		}
+
+		if brackets > 0 {
+			brackets += bracketBalance(line)
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line[0] == '#':
+			continue
+		case strings.HasPrefix(line, "[["):
+			name := strings.Join(splitKey(headerName(line, "[[", "]]")), ".")
+			arrays[name]++
+			for array := range arrays {
+				if strings.HasPrefix(array, name+".") {
+					delete(arrays, array)
+				}
+			}
+			table = tablePath(name, arrays)
+			result = append(result, location{path: table, line: number})
+			continue
+		case line[0] == '[':
+			table = tablePath(strings.Join(splitKey(headerName(line, "[", "]")), "."), arrays)
+			result = append(result, location{path: table, line: number})
+			continue
+		}
+
+		equals := indexOutsideQuotes(line, '=')
+		if equals < 0 {
+			continue
+		}
+
+		path := strings.Join(splitKey(line[:equals]), ".")
+		if table != "" {
+			path = table + "." + path
+		}
+
+		value := strings.TrimSpace(line[equals+1:])
+		loc := location{path: path, line: number}
+		switch {
+		case strings.HasPrefix(value, `"""`) || strings.HasPrefix(value, "'''"):
+			if !strings.Contains(value[3:], value[:3]) {
+				multiline = value[:3]
+			}
+		case strings.HasPrefix(value, "["):
+			brackets = bracketBalance(value)
+		case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'"):
+			if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+				loc.value = value[1 : end+1]
+			}
+		}
+		result = append(result, loc)
+	}
+	return result
+}
+
+// tablePath adds current indexes of arrays of tables to name, ex.
+// "signers.tls" is "signers[1].tls" when in the second signers entry
+func tablePath(name string, arrays map[string]int) string {
+	var path []string
+	segments := strings.Split(name, ".")
+	for i, segment := range segments {
+		if count, ok := arrays[strings.Join(segments[:i+1], ".")]; ok {
+			segment += "[" + strconv.Itoa(count-1) + "]"
+		}
+		path = append(path, segment)
+	}
+	return strings.Join(path, ".")
+}
+
+func headerName(line, open, close string) string {
+	line = strings.TrimPrefix(line, open)
+	if end := strings.Index(line, close); end >= 0 {
+		line = line[:end]
+	}
+	return line
+}
+
+// splitKey splits a (dotted, quoted) key to lowercase segments
+func splitKey(key string) []string {
+	var result []string
+	for {
+		dot := indexOutsideQuotes(key, '.')
+		if dot < 0 {
+			break
+		}
+		result = append(result, unquote(key[:dot]))
+		key = key[dot+1:]
+	}
+	return append(result, unquote(key))
+}
+
+func unquote(segment string) string {
+	segment = strings.TrimSpace(segment)
+	if len(segment) >= 2 && (segment[0] == '"' || segment[0] == '\'') {
+		segment = segment[1 : len(segment)-1]
+	}
+	return strings.ToLower(segment)
+}
+
+func indexOutsideQuotes(s string, c byte) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == '\\' && quote == '"' {
+				i++
+			} else if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '#':
+			return -1
+		case s[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+// bracketBalance returns the number of opened minus the number of closed
+// brackets in line, skipping strings and comments
+func bracketBalance(line string) int {
+	balance := 0
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch {
+		case quote != 0:
+			if line[i] == '\\' && quote == '"' {
+				i++
+			} else if line[i] == quote {
+				quote = 0
+			}
+		case line[i] == '"' || line[i] == '\'':
+			quote = line[i]
+		case line[i] == '#':
+			return balance
+		case line[i] == '[':
+			balance++
+		case line[i] == ']':
+			balance--
+		}
+	}
+	return balance
+}
@@ -0,0 +1,376 @@
+// Package configcheck checks config files of the servers (`--check-config`
+// flag). It reports TOML and YAML syntax errors, params unknown to the
+// config struct (typos are otherwise silently ignored) and the error of the
+// server config validation, with line numbers where they can be found (TOML
+// files only).
+package configcheck
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stellar/gateway/configsource"
+	"github.com/stellar/gateway/secrets"
+	"gopkg.in/yaml.v2"
+)
+
+// Problem is an error found in a config file
+type Problem struct {
+	// Line is 0 when the problem cannot be located in the file
+	Line    int
+	Message string
+}
+
+// Format returns p as "file:line: message"
+func (p Problem) Format(file string) string {
+	if p.Line == 0 {
+		return file + ": " + p.Message
+	}
+	return file + ":" + strconv.Itoa(p.Line) + ": " + p.Message
+}
+
+// Run checks the config file at path, writes problems (or a confirmation the
+// file is valid) to out and returns the exit code of the command. When path
+// is empty (config read from environment variables only) only validate is
+// called.
+func Run(path string, config interface{}, validate func() error, out io.Writer) int {
+	if path == "" {
+		if err := validate(); err != nil {
+			fmt.Fprintln(out, "environment:", err)
+			return 1
+		}
+		fmt.Fprintln(out, "Config from environment variables is valid")
+		return 0
+	}
+
+	problems, err := File(path, config, validate)
+	if err != nil {
+		fmt.Fprintln(out, "Error reading "+path+" file:", err)
+		return 1
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintln(out, problem.Format(path))
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	fmt.Fprintln(out, path+" is valid")
+	return 0
+}
+
+// File is Check (or CheckYAML for .yml and .yaml files) of the file at path
+func File(path string, config interface{}, validate func() error) ([]Problem, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if configsource.Type(path) == "yaml" {
+		return CheckYAML(data, config, validate), nil
+	}
+	return Check(data, config, validate), nil
+}
+
+// Check checks config file data. Params unknown to config (a config struct or
+// a pointer to it) are reported. When data can be decoded validate is called,
+// it should load the config the same way the server does.
+func Check(data []byte, config interface{}, validate func() error) []Problem {
+	var decoded map[string]interface{}
+	_, err := toml.Decode(string(data), &decoded)
+	if err != nil {
+		return []Problem{syntaxProblem(err)}
+	}
+
+	c := checker{locations: locate(data)}
+	return c.check(decoded, config, validate)
+}
+
+// CheckYAML is Check of YAML config file data. Problems are not located.
+func CheckYAML(data []byte, config interface{}, validate func() error) []Problem {
+	var decoded map[string]interface{}
+	err := yaml.Unmarshal(data, &decoded)
+	if err != nil {
+		return []Problem{yamlSyntaxProblem(err)}
+	}
+
+	for key, value := range decoded {
+		decoded[key], _ = configsource.StringKeys(value)
+	}
+
+	c := checker{}
+	return c.check(decoded, config, validate)
+}
+
+func (c *checker) check(decoded map[string]interface{}, config interface{}, validate func() error) []Problem {
+	c.unknownParams(decoded, reflect.TypeOf(config), "")
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		return c.problems[i].Line < c.problems[j].Line
+	})
+
+	if validate != nil {
+		if err := validate(); err != nil {
+			c.problems = append(c.problems, Problem{Line: c.locateMessage(err.Error()), Message: err.Error()})
+		}
+	}
+	return c.problems
+}
+
+var syntaxErrorRegexp = regexp.MustCompile(`^Near line (\d+)(?: \(last key parsed '[^']*'\))?: `)
+
+func syntaxProblem(err error) Problem {
+	message := err.Error()
+	match := syntaxErrorRegexp.FindStringSubmatch(message)
+	if match == nil {
+		return Problem{Message: message}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return Problem{Line: line, Message: "Syntax error: " + message[len(match[0]):]}
+}
+
+var yamlSyntaxErrorRegexp = regexp.MustCompile(`^yaml: line (\d+): `)
+
+func yamlSyntaxProblem(err error) Problem {
+	message := err.Error()
+	match := yamlSyntaxErrorRegexp.FindStringSubmatch(message)
+	if match == nil {
+		return Problem{Message: "Syntax error: " + strings.TrimPrefix(message, "yaml: ")}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return Problem{Line: line, Message: "Syntax error: " + message[len(match[0]):]}
+}
+
+type checker struct {
+	locations []location
+	problems  []Problem
+}
+
+func (c *checker) unknownParams(value interface{}, t reflect.Type, path string) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := map[string]reflect.StructField{}
+		structFields(t, fields)
+
+		keys := make([]string, 0, len(table))
+		for key := range table {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			name := strings.ToLower(key)
+			keyPath := name
+			if path != "" {
+				keyPath = path + "." + name
+			}
+
+			field, ok := fields[name]
+			if ok {
+				c.unknownParams(table[key], field.Type, keyPath)
+				continue
+			}
+
+			secret, ok := fields[strings.TrimSuffix(name, secrets.FileSuffix)]
+			if ok && strings.HasSuffix(name, secrets.FileSuffix) && isSecret(secret) {
+				continue
+			}
+
+			message := "Unknown param " + keyPath
+			if suggestion := closest(name, fields); suggestion != "" {
+				message += ", did you mean " + suggestion + "?"
+			}
+			c.problems = append(c.problems, Problem{Line: c.line(keyPath), Message: message})
+		}
+	case reflect.Slice, reflect.Array:
+		switch elements := value.(type) {
+		case []map[string]interface{}:
+			for i, element := range elements {
+				c.unknownParams(element, t.Elem(), path+"["+strconv.Itoa(i)+"]")
+			}
+		case []interface{}:
+			for i, element := range elements {
+				c.unknownParams(element, t.Elem(), path+"["+strconv.Itoa(i)+"]")
+			}
+		}
+	}
+}
+
+// line returns the line of path or of its closest parent found in the file
+func (c *checker) line(path string) int {
+	for path != "" {
+		for _, location := range c.locations {
+			if location.path == path {
+				return location.line
+			}
+		}
+		end := strings.LastIndexAny(path, ".[")
+		if end < 0 {
+			break
+		}
+		path = path[:end]
+	}
+	return 0
+}
+
+var indexRegexp = regexp.MustCompile(`\[\d+\]`)
+
+// paramWords follow param names in validation errors, ex. "port param is
+// required"
+var paramWords = map[string]bool{"param": true, "params": true, "entry": true}
+
+// locateMessage returns the line of the first param mentioned in message of
+// a validation error. When the param is an element of an array of tables,
+// the element with a string value mentioned in message is preferred. When
+// the param is not in the file (ex. a required param), the line of its
+// table is returned.
+func (c *checker) locateMessage(message string) int {
+	words := strings.Fields(message)
+	mentioned := map[string]bool{}
+	for i, word := range words {
+		words[i] = strings.Trim(word, "`'\",:;()?")
+		mentioned[words[i]] = true
+	}
+
+	for i, word := range words {
+		param := strings.ToLower(strings.TrimSuffix(word, "."))
+		followed := i+1 < len(words) && paramWords[strings.ToLower(words[i+1])]
+		if param == "" || !(followed || strings.ContainsAny(param, "._")) {
+			continue
+		}
+
+		var matches []location
+		for _, location := range c.locations {
+			path := indexRegexp.ReplaceAllString(location.path, "")
+			if path == param || strings.HasSuffix(path, "."+param) {
+				matches = append(matches, location)
+			}
+		}
+
+		if len(matches) > 0 {
+			for j := len(matches) - 1; j >= 0; j-- {
+				if c.mentionsElement(matches[j].path, mentioned) {
+					return matches[j].line
+				}
+			}
+			return matches[0].line
+		}
+
+		if strings.Contains(param, ".") {
+			if line := c.line(param[:strings.LastIndex(param, ".")]); line != 0 {
+				return line
+			}
+		}
+	}
+	return 0
+}
+
+// mentionsElement returns true when a string value of the array element
+// containing path is mentioned
+func (c *checker) mentionsElement(path string, mentioned map[string]bool) bool {
+	end := strings.LastIndex(path, "]")
+	if end < 0 {
+		return false
+	}
+	element := path[:end+1]
+	for _, location := range c.locations {
+		if location.value != "" && strings.HasPrefix(location.path, element+".") && mentioned[location.value] {
+			return true
+		}
+	}
+	return false
+}
+
+// structFields adds fields of t by names used in config files, fields of
+// squashed embedded structs are added as fields of t
+func structFields(t reflect.Type, fields map[string]reflect.StructField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		parts := strings.Split(field.Tag.Get("mapstructure"), ",")
+		squash := false
+		for _, option := range parts[1:] {
+			squash = squash || option == "squash"
+		}
+
+		switch {
+		case squash:
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			structFields(fieldType, fields)
+		case parts[0] != "":
+			fields[strings.ToLower(parts[0])] = field
+		default:
+			fields[strings.ToLower(field.Name)] = field
+		}
+	}
+}
+
+func isSecret(field reflect.StructField) bool {
+	_, ok := field.Tag.Lookup("secret")
+	return ok
+}
+
+// closest returns the name of fields closest to name, empty when none is
+// similar enough to be a typo
+func closest(name string, fields map[string]reflect.StructField) string {
+	result, best := "", 3
+	for field := range fields {
+		distance := levenshtein(name, field)
+		if distance < best || (distance == best && field < result) {
+			result, best = field, distance
+		}
+	}
+	return result
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package configcheck
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAsset struct {
+	Code   string
+	Issuer string
+}
+
+type testTLS struct {
+	CAFile string `mapstructure:"ca_file"`
+}
+
+type testSigner struct {
+	Seed string `secret:""`
+	TLS  testTLS
+}
+
+// Common is exported, fields of unexported embedded structs are not set
+type Common struct {
+	LogFormat string `mapstructure:"log_format"`
+}
+
+type testConfig struct {
+	Common   `mapstructure:",squash"`
+	Port     int
+	Database struct {
+		Type string
+		URL  string `secret:""`
+	}
+	Assets  []testAsset
+	Signers []testSigner
+	Labels  map[string]string
+}
+
+const testFile = `port = 8000
+log_format = "json"
+
+[database]
+type = "postgres"
+url_file = "/run/secrets/database_url"
+tpye = "mysql"
+
+[[assets]]
+code = "USD"
+issuer = "GA"
+
+[[assets]]
+code = "EUR"
+isuer = "GB"
+
+[[signers]]
+seed = "SA"
+
+[[signers]]
+seed = """
+SB
+"""
+  [signers.tls]
+  ca_file = "ca.pem"
+  ca = "ca.pem"
+
+[labels]
+anything = "goes"
+`
+
+func TestCheck(t *testing.T) {
+	problems := Check([]byte(testFile), testConfig{}, nil)
+	assert.Equal(t, []Problem{
+		{Line: 7, Message: "Unknown param database.tpye, did you mean type?"},
+		{Line: 15, Message: "Unknown param assets[1].isuer, did you mean issuer?"},
+		{Line: 26, Message: "Unknown param signers[1].tls.ca"},
+	}, problems)
+
+	t.Run("syntax error", func(t *testing.T) {
+		problems := Check([]byte("port = 8000\n[database\ntype = \"postgres\"\n"), testConfig{}, func() error {
+			t.Fatal("validate called")
+			return nil
+		})
+		require.Len(t, problems, 1)
+		assert.Equal(t, 2, problems[0].Line)
+		assert.Contains(t, problems[0].Message, "Syntax error")
+	})
+}
+
+func TestCheckLocateValidationErrors(t *testing.T) {
+	for message, line := range map[string]int{
+		"port param is required":                   1,
+		"Invalid database.type param":              5,
+		"database.password param is required":      4,
+		"Issuer param is required for USD":         11,
+		"Duplicate assets entry of EUR":            13,
+		"signers.tls.ca_file param cannot be read": 25,
+		"Something unrelated":                      0,
+	} {
+		problems := Check([]byte(testFile), testConfig{}, func() error {
+			return errors.New(message)
+		})
+		require.Len(t, problems, 4, message)
+		assert.Equal(t, Problem{Line: line, Message: message}, problems[3])
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configcheck")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "server.cfg")
+	require.NoError(t, ioutil.WriteFile(path, []byte("port = 8000\nprot = 8001\n"), 0600))
+
+	var out bytes.Buffer
+	assert.Equal(t, 1, Run(path, &testConfig{}, nil, &out))
+	assert.Equal(t, path+":2: Unknown param prot, did you mean port?\n", out.String())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("port = 8000\n"), 0600))
+	out.Reset()
+	assert.Equal(t, 0, Run(path, &testConfig{}, func() error { return nil }, &out))
+	assert.Equal(t, path+" is valid\n", out.String())
+
+	out.Reset()
+	assert.Equal(t, 1, Run(filepath.Join(dir, "missing.cfg"), &testConfig{}, nil, &out))
+	assert.Contains(t, out.String(), "Error reading")
+}
+
+func TestCheckYAML(t *testing.T) {
+	problems := CheckYAML([]byte(`port: 8000
+database:
+  tpye: postgres
+signers:
+  - seed: SA
+    tls:
+      ca: ca.pem
+`), testConfig{}, func() error { return errors.New("port param is required") })
+	assert.Equal(t, []Problem{
+		{Message: "Unknown param database.tpye, did you mean type?"},
+		{Message: "Unknown param signers[0].tls.ca"},
+		{Message: "port param is required"},
+	}, problems)
+
+	problems = CheckYAML([]byte("port: 8000\ndatabase: [\n"), testConfig{}, nil)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Message, "Syntax error")
+
+	var out bytes.Buffer
+	assert.Equal(t, 1, Run("", &testConfig{}, func() error { return errors.New("port param is required") }, &out))
+	assert.Equal(t, "environment: port param is required\n", out.String())
+}
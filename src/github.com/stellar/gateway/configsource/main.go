@@ -0,0 +1,290 @@
+// Package configsource reads config params of the servers from a config file
+// (TOML or YAML) and environment variables. Params are taken from (in order
+// of precedence):
+//
+//   - environment variables, ex. BRIDGE_PORT or BRIDGE_DATABASE_TYPE,
+//   - the config file,
+//   - defaults of the server.
+//
+// Names of environment variables are built from the prefix of the server and
+// the path of the param, elements of arrays of tables are numbered from 0,
+// ex. BRIDGE_ASSETS_0_CODE. Lists of values are separated by commas, ex.
+// BRIDGE_HORIZON="https://a.example.com,https://b.example.com". Secret params
+// can also be read from files (ex. BRIDGE_DATABASE_URL_FILE) and Vault, see
+// package secrets.
+package configsource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/stellar/gateway/secrets"
+	"github.com/stellar/go/support/errors"
+)
+
+// Source describes where config params are read from
+type Source struct {
+	// Path of the config file. Files with .yml or .yaml extension are YAML,
+	// other files are TOML.
+	Path string
+	// Optional allows starting without the config file, params are read from
+	// environment variables only
+	Optional bool
+	// EnvPrefix is the prefix of environment variables, ex. "BRIDGE"
+	EnvPrefix string
+	lookupEnv func(key string) (string, bool)
+}
+
+// Type returns the type of the config file at path: "yaml" or "toml"
+func Type(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		return "yaml"
+	default:
+		return "toml"
+	}
+}
+
+// FileExists returns true when the config file exists. Returns false when
+// params are read from environment variables only.
+func (s Source) FileExists() bool {
+	_, err := os.Stat(s.Path)
+	return err == nil || !s.Optional || !os.IsNotExist(err)
+}
+
+// Read returns a new viper instance with params of the config file
+// overridden by environment variables. config is a pointer to the config
+// struct of the server, names of environment variables are built from its
+// fields.
+func (s Source) Read(config interface{}) (*viper.Viper, error) {
+	v := viper.New()
+	if s.FileExists() {
+		v.SetConfigFile(s.Path)
+		v.SetConfigType(Type(s.Path))
+		err := v.ReadInConfig()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error reading "+s.Path+" file")
+		}
+	}
+
+	settings := v.AllSettings()
+	// YAML tables are decoded as map[interface{}]interface{}
+	for key, value := range settings {
+		if normalized, changed := StringKeys(value); changed {
+			settings[key] = normalized
+			v.Set(key, normalized)
+		}
+	}
+
+	// Groups are set as a whole, viper doesn't merge nested keys
+	for key := range s.apply(reflect.TypeOf(config), settings, s.EnvPrefix) {
+		v.Set(key, settings[key])
+	}
+	return v, nil
+}
+
+// apply sets params of node (of type t) found in environment variables
+// starting with envName. Returns keys of node that have been changed.
+func (s Source) apply(t reflect.Type, node map[string]interface{}, envName string) map[string]bool {
+	changed := map[string]bool{}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return changed
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		name, squash := mapstructureName(field)
+		if squash {
+			for key := range s.apply(fieldType, node, envName) {
+				changed[key] = true
+			}
+			continue
+		}
+
+		key := lookupKey(node, name)
+		fieldEnvName := envName + "_" + strings.ToUpper(name)
+
+		switch {
+		case fieldType.Kind() == reflect.Struct:
+			child, ok := node[key].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+			}
+			if len(s.apply(fieldType, child, fieldEnvName)) > 0 {
+				node[key] = child
+				changed[key] = true
+			}
+		case fieldType.Kind() == reflect.Slice && isStruct(fieldType.Elem()):
+			if list, ok := s.applyList(fieldType.Elem(), node[key], fieldEnvName); ok {
+				node[key] = list
+				changed[key] = true
+			}
+		case fieldType.Kind() == reflect.Map || fieldType.Kind() == reflect.Interface:
+			// Not supported
+		default:
+			value, ok := s.getenv(fieldEnvName)
+			if ok {
+				if fieldType.Kind() == reflect.Slice {
+					node[key] = splitList(value)
+				} else {
+					node[key] = value
+				}
+				changed[key] = true
+			}
+
+			// Secret params can be read from files
+			if _, secret := field.Tag.Lookup("secret"); secret {
+				value, ok := s.getenv(fieldEnvName + strings.ToUpper(secrets.FileSuffix))
+				if ok {
+					node[lookupKey(node, name+secrets.FileSuffix)] = value
+					changed[key] = true
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// applyList applies environment variables to elements of an array of tables.
+// Elements are added while variables of the next index are found.
+func (s Source) applyList(elementType reflect.Type, value interface{}, envName string) ([]interface{}, bool) {
+	list := toList(value)
+	changed := false
+	for i := 0; ; i++ {
+		element := map[string]interface{}{}
+		if i < len(list) {
+			if existing, ok := list[i].(map[string]interface{}); ok {
+				element = existing
+			}
+		}
+
+		elementChanged := len(s.apply(elementType, element, envName+"_"+strconv.Itoa(i))) > 0
+		if i >= len(list) {
+			if !elementChanged {
+				break
+			}
+			list = append(list, element)
+		} else {
+			list[i] = element
+		}
+		changed = changed || elementChanged
+	}
+	return list, changed
+}
+
+func (s Source) getenv(name string) (string, bool) {
+	if s.lookupEnv != nil {
+		return s.lookupEnv(name)
+	}
+	return os.LookupEnv(name)
+}
+
+// splitList splits a comma separated list of values
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// StringKeys converts maps with interface{} keys (decoded from YAML) to maps
+// with string keys. Returns true when value has been changed.
+func StringKeys(value interface{}) (interface{}, bool) {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[strings.ToLower(fmt.Sprint(key))], _ = StringKeys(item)
+		}
+		return result, true
+	case map[string]interface{}:
+		changed := false
+		for key, item := range typed {
+			if normalized, itemChanged := StringKeys(item); itemChanged {
+				typed[key] = normalized
+				changed = true
+			}
+		}
+		return typed, changed
+	case []interface{}:
+		changed := false
+		for i, item := range typed {
+			if normalized, itemChanged := StringKeys(item); itemChanged {
+				typed[i] = normalized
+				changed = true
+			}
+		}
+		return typed, changed
+	default:
+		return value, false
+	}
+}
+
+// mapstructureName returns the name of a field in config files and true
+// when the fields of an embedded struct are squashed
+func mapstructureName(field reflect.StructField) (string, bool) {
+	parts := strings.Split(field.Tag.Get("mapstructure"), ",")
+	for _, option := range parts[1:] {
+		if option == "squash" {
+			return "", true
+		}
+	}
+	if parts[0] != "" {
+		return parts[0], false
+	}
+	return strings.ToLower(field.Name), false
+}
+
+func isStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// lookupKey returns the key of node matching name case-insensitively or name
+// when there is no such key
+func lookupKey(node map[string]interface{}, name string) string {
+	for key := range node {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
+
+func toList(value interface{}) []interface{} {
+	switch typed := value.(type) {
+	case []interface{}:
+		return typed
+	case []map[string]interface{}:
+		list := make([]interface{}, len(typed))
+		for i, element := range typed {
+			list[i] = element
+		}
+		return list
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package configsource
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSigner struct {
+	Name string
+	Seed string `secret:""`
+}
+
+type Common struct {
+	LogFormat string `mapstructure:"log_format"`
+}
+
+type testConfig struct {
+	Common   `mapstructure:",squash"`
+	Port     *int
+	Horizon  []string
+	Database struct {
+		Type string
+		URL  string `secret:""`
+	}
+	Signers []testSigner
+	Labels  map[string]string
+}
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func TestSourceRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configsource")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	toml := filepath.Join(dir, "server.cfg")
+	require.NoError(t, ioutil.WriteFile(toml, []byte(`port = 8000
+log_format = "json"
+
+[database]
+type = "postgres"
+url = "postgres://localhost/file"
+
+[[signers]]
+name = "first"
+seed = "SA"
+`), 0600))
+
+	yaml := filepath.Join(dir, "server.yml")
+	require.NoError(t, ioutil.WriteFile(yaml, []byte(`port: 8000
+log_format: json
+database:
+  type: postgres
+  url: postgres://localhost/file
+signers:
+  - name: first
+    seed: SA
+`), 0600))
+
+	for _, path := range []string{toml, yaml} {
+		t.Run(Type(path), func(t *testing.T) {
+			source := Source{Path: path, EnvPrefix: "SERVER", lookupEnv: env(map[string]string{
+				"SERVER_PORT":            "8001",
+				"SERVER_HORIZON":         "https://a.example.com, https://b.example.com",
+				"SERVER_DATABASE_URL":    "postgres://localhost/env",
+				"SERVER_SIGNERS_1_NAME":  "second",
+				"SERVER_SIGNERS_1_SEED":  "SB",
+				"SERVER_LABELS_ANYTHING": "ignored",
+			})}
+
+			v, err := source.Read(&testConfig{})
+			require.NoError(t, err)
+
+			var config testConfig
+			require.NoError(t, v.Unmarshal(&config))
+			require.NotNil(t, config.Port)
+			assert.Equal(t, 8001, *config.Port, "env overrides file")
+			assert.Equal(t, "json", config.LogFormat)
+			assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, config.Horizon)
+			assert.Equal(t, "postgres", config.Database.Type, "other params of the group are kept")
+			assert.Equal(t, "postgres://localhost/env", config.Database.URL)
+			assert.Equal(t, []testSigner{{Name: "first", Seed: "SA"}, {Name: "second", Seed: "SB"}}, config.Signers)
+			assert.Empty(t, config.Labels)
+		})
+	}
+
+	t.Run("environment only", func(t *testing.T) {
+		source := Source{Path: filepath.Join(dir, "missing.cfg"), Optional: true, EnvPrefix: "SERVER", lookupEnv: env(map[string]string{
+			"SERVER_LOG_FORMAT":        "json",
+			"SERVER_DATABASE_TYPE":     "postgres",
+			"SERVER_DATABASE_URL_FILE": "/run/secrets/database_url",
+		})}
+		assert.False(t, source.FileExists())
+
+		v, err := source.Read(&testConfig{})
+		require.NoError(t, err)
+
+		var config testConfig
+		require.NoError(t, v.Unmarshal(&config))
+		assert.Nil(t, config.Port)
+		assert.Equal(t, "json", config.LogFormat)
+		assert.Equal(t, "postgres", config.Database.Type)
+		assert.Equal(t, "/run/secrets/database_url", v.GetStringMap("database")["url_file"])
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		source := Source{Path: filepath.Join(dir, "missing.cfg"), EnvPrefix: "SERVER", lookupEnv: env(nil)}
+		assert.True(t, source.FileExists())
+
+		_, err := source.Read(&testConfig{})
+		assert.Error(t, err)
+	})
+}
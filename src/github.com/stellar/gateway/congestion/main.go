@@ -0,0 +1,226 @@
+// Package congestion detects Stellar network congestion using fee stats
+// returned by Horizon. When ledgers are close to full, transactions are sent
+// with a higher fee percentile, retries are delayed longer and responses
+// contain a congestion notice, so payments are not rejected during network
+// spikes.
+package congestion
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+)
+
+// FeeStatsLoader loads fee stats, implemented by horizon.Horizon
+type FeeStatsLoader interface {
+	LoadFeeStats() (horizon.FeeStatsResponse, error)
+}
+
+// Options configures Monitor
+type Options struct {
+	// CapacityThreshold is ledger capacity usage (0-1) above which the network
+	// is considered congested
+	CapacityThreshold float64
+	// FeePercentile of accepted fees used when the network is not congested.
+	// Base fee is used when 0.
+	FeePercentile int
+	// CongestedFeePercentile of accepted fees used during congestion
+	CongestedFeePercentile int
+	// MaxFee is a max fee per operation in stroops. There is no limit when 0.
+	MaxFee uint32
+	// RetryDelayMultiplier stretches retry delays during congestion
+	RetryDelayMultiplier int
+}
+
+// DefaultOptions contains default values of Options
+var DefaultOptions = Options{
+	CapacityThreshold:      0.8,
+	CongestedFeePercentile: 90,
+	RetryDelayMultiplier:   4,
+}
+
+var monitorMetrics = struct {
+	congested     *metrics.Gauge
+	capacityUsage *metrics.Gauge
+	fee           *metrics.Gauge
+}{
+	congested:     metrics.NewGauge("bridge_network_congested", "1 when the Stellar network is congested, 0 otherwise."),
+	capacityUsage: metrics.NewGauge("bridge_network_ledger_capacity_usage", "Ledger capacity usage (0-1) of the last ledger."),
+	fee:           metrics.NewGauge("bridge_network_fee_per_operation", "Fee per operation (in stroops) used in submitted transactions."),
+}
+
+// State is the network state observed by Monitor
+type State struct {
+	Congested           bool    `json:"congested"`
+	LedgerCapacityUsage float64 `json:"ledger_capacity_usage"`
+	BaseFee             uint32  `json:"base_fee"`
+	// Fee is a fee per operation used in submitted transactions
+	Fee       uint32    `json:"fee"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Monitor periodically loads fee stats and decides on fees and retry delays.
+// Methods of a nil *Monitor return values used when congestion detection is
+// disabled.
+type Monitor struct {
+	loader  FeeStatsLoader
+	options Options
+	mutex   sync.RWMutex
+	state   State
+	log     *logrus.Entry
+	now     func() time.Time
+}
+
+// NewMonitor creates a new Monitor. Call Start to load fee stats periodically.
+func NewMonitor(loader FeeStatsLoader, options Options) *Monitor {
+	return &Monitor{
+		loader:  loader,
+		options: options,
+		log:     logrus.WithFields(logrus.Fields{"service": "CongestionMonitor"}),
+		now:     clock.Now,
+	}
+}
+
+// Start loads fee stats every interval
+func (m *Monitor) Start(interval time.Duration) {
+	go func() {
+		m.Update()
+		for range time.Tick(interval) {
+			m.Update()
+		}
+	}()
+}
+
+// Update loads fee stats and updates the state. The previous state is kept
+// when fee stats cannot be loaded.
+func (m *Monitor) Update() error {
+	stats, err := m.loader.LoadFeeStats()
+	if err != nil {
+		m.log.WithFields(logrus.Fields{"err": err}).Error("Error loading fee stats")
+		return err
+	}
+
+	state, err := m.evaluate(stats)
+	if err != nil {
+		m.log.WithFields(logrus.Fields{"err": err}).Error("Invalid fee stats")
+		return err
+	}
+
+	m.mutex.Lock()
+	previous := m.state
+	m.state = state
+	m.mutex.Unlock()
+
+	fields := logrus.Fields{
+		"ledger_capacity_usage": state.LedgerCapacityUsage,
+		"fee":                   state.Fee,
+	}
+	if state.Congested && !previous.Congested {
+		m.log.WithFields(fields).Warn("Network congestion detected")
+	} else if !state.Congested && previous.Congested {
+		m.log.WithFields(fields).Info("Network congestion ended")
+	}
+
+	if state.Congested {
+		monitorMetrics.congested.Set(1)
+	} else {
+		monitorMetrics.congested.Set(0)
+	}
+	monitorMetrics.capacityUsage.Set(state.LedgerCapacityUsage)
+	monitorMetrics.fee.Set(float64(state.Fee))
+	return nil
+}
+
+func (m *Monitor) evaluate(stats horizon.FeeStatsResponse) (state State, err error) {
+	state.LedgerCapacityUsage, err = stats.CapacityUsage()
+	if err != nil {
+		return
+	}
+
+	state.BaseFee, err = stats.BaseFee()
+	if err != nil {
+		return
+	}
+
+	state.Congested = state.LedgerCapacityUsage >= m.options.CapacityThreshold
+
+	percentile := m.options.FeePercentile
+	if state.Congested {
+		percentile = m.options.CongestedFeePercentile
+	}
+
+	state.Fee = state.BaseFee
+	if percentile != 0 {
+		var accepted uint32
+		accepted, err = stats.AcceptedFee(percentile)
+		if err != nil {
+			return
+		}
+		if accepted > state.Fee {
+			state.Fee = accepted
+		}
+	}
+
+	// Transactions with fee lower than base fee are rejected so MaxFee
+	// never lowers the fee below it
+	if m.options.MaxFee != 0 && state.Fee > m.options.MaxFee {
+		state.Fee = m.options.MaxFee
+		if state.Fee < state.BaseFee {
+			state.Fee = state.BaseFee
+		}
+	}
+
+	state.UpdatedAt = m.now()
+	return
+}
+
+// State returns the last observed network state
+func (m *Monitor) State() State {
+	if m == nil {
+		return State{}
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.state
+}
+
+// Congested returns true when the network is congested
+func (m *Monitor) Congested() bool {
+	return m.State().Congested
+}
+
+// Fee returns fee per operation that should be used in transactions or 0
+// when fee stats have not been loaded yet
+func (m *Monitor) Fee() uint32 {
+	return m.State().Fee
+}
+
+// Notice returns a notice added to responses of transactions submitted
+// during congestion or nil when the network is not congested
+func (m *Monitor) Notice() *horizon.CongestionNotice {
+	state := m.State()
+	if !state.Congested {
+		return nil
+	}
+
+	return &horizon.CongestionNotice{
+		Message:             fmt.Sprintf("Stellar network is congested (ledger capacity usage %.0f%%). Transaction has been sent with a higher fee and may take longer to be included in a ledger.", state.LedgerCapacityUsage*100),
+		LedgerCapacityUsage: state.LedgerCapacityUsage,
+		FeePerOperation:     state.Fee,
+	}
+}
+
+// StretchRetryDelay returns delay multiplied by Options.RetryDelayMultiplier
+// during congestion
+func (m *Monitor) StretchRetryDelay(delay time.Duration) time.Duration {
+	if m == nil || m.options.RetryDelayMultiplier <= 1 || !m.Congested() {
+		return delay
+	}
+	return delay * time.Duration(m.options.RetryDelayMultiplier)
+}
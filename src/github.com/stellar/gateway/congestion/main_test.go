@@ -0,0 +1,105 @@
+package congestion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLoader struct {
+	stats horizon.FeeStatsResponse
+	err   error
+}
+
+func (l *testLoader) LoadFeeStats() (horizon.FeeStatsResponse, error) {
+	return l.stats, l.err
+}
+
+func feeStats(capacityUsage string) horizon.FeeStatsResponse {
+	return horizon.FeeStatsResponse{
+		LastLedgerBaseFee:   "100",
+		LedgerCapacityUsage: capacityUsage,
+		P10AcceptedFee:      "100",
+		P50AcceptedFee:      "150",
+		P90AcceptedFee:      "2000",
+		P99AcceptedFee:      "10000",
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	loader := &testLoader{stats: feeStats("0.35")}
+	options := DefaultOptions
+	options.FeePercentile = 50
+	monitor := NewMonitor(loader, options)
+
+	// Fee stats not loaded yet
+	assert.Equal(t, uint32(0), monitor.Fee())
+	assert.Nil(t, monitor.Notice())
+
+	require.NoError(t, monitor.Update())
+	assert.False(t, monitor.Congested())
+	assert.Equal(t, uint32(150), monitor.Fee())
+	assert.Nil(t, monitor.Notice())
+	assert.Equal(t, time.Second, monitor.StretchRetryDelay(time.Second))
+
+	loader.stats = feeStats("0.97")
+	require.NoError(t, monitor.Update())
+	assert.True(t, monitor.Congested())
+	assert.Equal(t, uint32(2000), monitor.Fee())
+	assert.Equal(t, 4*time.Second, monitor.StretchRetryDelay(time.Second))
+
+	notice := monitor.Notice()
+	require.NotNil(t, notice)
+	assert.Equal(t, 0.97, notice.LedgerCapacityUsage)
+	assert.Equal(t, uint32(2000), notice.FeePerOperation)
+
+	// Previous state is kept when fee stats cannot be loaded
+	loader.err = errors.New("timeout")
+	assert.Error(t, monitor.Update())
+	assert.True(t, monitor.Congested())
+
+	loader.err = nil
+	loader.stats.LedgerCapacityUsage = "invalid"
+	assert.Error(t, monitor.Update())
+	assert.Equal(t, uint32(2000), monitor.Fee())
+}
+
+func TestMonitorMaxFee(t *testing.T) {
+	loader := &testLoader{stats: feeStats("0.9")}
+	options := DefaultOptions
+	options.CongestedFeePercentile = 99
+	options.MaxFee = 5000
+	monitor := NewMonitor(loader, options)
+
+	require.NoError(t, monitor.Update())
+	assert.Equal(t, uint32(5000), monitor.Fee())
+
+	// Fee is never lower than base fee
+	loader.stats.LastLedgerBaseFee = "6000"
+	require.NoError(t, monitor.Update())
+	assert.Equal(t, uint32(6000), monitor.Fee())
+}
+
+func TestMonitorBaseFee(t *testing.T) {
+	monitor := NewMonitor(&testLoader{stats: feeStats("0.1")}, DefaultOptions)
+	require.NoError(t, monitor.Update())
+	assert.Equal(t, uint32(100), monitor.Fee())
+	assert.Equal(t, State{
+		LedgerCapacityUsage: 0.1,
+		BaseFee:             100,
+		Fee:                 100,
+		UpdatedAt:           monitor.State().UpdatedAt,
+	}, monitor.State())
+}
+
+func TestNilMonitor(t *testing.T) {
+	var monitor *Monitor
+	assert.False(t, monitor.Congested())
+	assert.Equal(t, uint32(0), monitor.Fee())
+	assert.Nil(t, monitor.Notice())
+	assert.Equal(t, time.Second, monitor.StretchRetryDelay(time.Second))
+}
@@ -1,72 +1,96 @@
 package crypto
 
-// import (
-// 	"crypto/aes"
-// 	"crypto/cipher"
-// 	"crypto/rand"
-// 	"encoding/base64"
-// 	"io"
-
-// 	"github.com/stellar/go-stellar-base/strkey"
-// )
-
-// func Encrypt(key string, message []byte) (cipherBase64, nonceBase64 string, err error) {
-// 	keyBytes, err := strkey.Decode(strkey.VersionByteAccountID, key)
-// 	if err != nil {
-// 		return
-// 	}
-
-// 	block, err := aes.NewCipher(keyBytes)
-// 	if err != nil {
-// 		return
-// 	}
-
-// 	aesgcm, err := cipher.NewGCM(block)
-// 	if err != nil {
-// 		return
-// 	}
-
-// 	nonceBytes := make([]byte, aesgcm.NonceSize())
-// 	if _, err = io.ReadFull(rand.Reader, nonceBytes); err != nil {
-// 		return
-// 	}
-// 	nonceBase64 = base64.StdEncoding.EncodeToString(nonceBytes)
-
-// 	cipherBytes := aesgcm.Seal(nil, nonceBytes, message, nil)
-// 	cipherBase64 = base64.StdEncoding.EncodeToString(cipherBytes)
-// 	return
-// }
-
-// func Decrypt(key, cipherBase64, nonceBase64 string) (string, error) {
-// 	keyBytes, err := strkey.Decode(strkey.VersionByteAccountID, key)
-// 	if err != nil {
-// 		return "", nil
-// 	}
-
-// 	cipherBytes, err := base64.StdEncoding.DecodeString(cipherBase64)
-// 	if err != nil {
-// 		return "", err
-// 	}
-
-// 	nonceBytes, err := base64.StdEncoding.DecodeString(nonceBase64)
-// 	if err != nil {
-// 		return "", err
-// 	}
-
-// 	block, err := aes.NewCipher(keyBytes)
-// 	if err != nil {
-// 		return "", err
-// 	}
-
-// 	aesgcm, err := cipher.NewGCM(block)
-// 	if err != nil {
-// 		return "", err
-// 	}
-
-// 	plaintext, err := aesgcm.Open(nil, nonceBytes, cipherBytes, nil)
-// 	if err != nil {
-// 		return "", err
-// 	}
-
-// 	return string(plaintext), nil
-// }
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	encryptionKeySize = 32
+	nonceSize         = 24
+)
+
+// ErrDecryption is returned when a message cannot be decrypted using the
+// private key: it was encrypted to other key or has been modified
+var ErrDecryption = errors.New("Cannot decrypt message")
+
+// EncryptionPublicKey returns a base64 encoded Curve25519 public key of
+// privateKey. The public key is published as ENCRYPTION_KEY in stellar.toml.
+func EncryptionPublicKey(privateKey string) (string, error) {
+	private, err := decodeEncryptionKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	var public [encryptionKeySize]byte
+	curve25519.ScalarBaseMult(&public, private)
+	return base64.StdEncoding.EncodeToString(public[:]), nil
+}
+
+// Encrypt encrypts message to publicKey using NaCl box with a new ephemeral
+// key pair, so only the owner of the private key can decrypt it. Returns
+// base64 encoded ephemeral public key, nonce and the box.
+func Encrypt(publicKey string, message []byte) (string, error) {
+	public, err := decodeEncryptionKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	ephemeralPublic, ephemeralPrivate, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	var nonce [nonceSize]byte
+	if _, err = io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return "", err
+	}
+
+	sealed := append(ephemeralPublic[:], nonce[:]...)
+	sealed = box.Seal(sealed, message, &nonce, public, ephemeralPrivate)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a message encrypted by Encrypt using privateKey
+func Decrypt(privateKey, encrypted string) ([]byte, error) {
+	private, err := decodeEncryptionKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < encryptionKeySize+nonceSize+box.Overhead {
+		return nil, ErrDecryption
+	}
+
+	var ephemeralPublic [encryptionKeySize]byte
+	var nonce [nonceSize]byte
+	copy(ephemeralPublic[:], sealed[:encryptionKeySize])
+	copy(nonce[:], sealed[encryptionKeySize:encryptionKeySize+nonceSize])
+
+	message, ok := box.Open(nil, sealed[encryptionKeySize+nonceSize:], &nonce, &ephemeralPublic, private)
+	if !ok {
+		return nil, ErrDecryption
+	}
+	return message, nil
+}
+
+func decodeEncryptionKey(key string) (*[encryptionKeySize]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(decoded) != encryptionKeySize {
+		return nil, errors.New("Encryption key must be 32 bytes encoded in base64")
+	}
+
+	var result [encryptionKeySize]byte
+	copy(result[:], decoded)
+	return &result, nil
+}
@@ -0,0 +1,40 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const encryptionPrivateKey = "d2VsY29tZSB0byB0aGUgYnJpZGdlIHNlcnZlciAhISE="
+
+func TestEncrypt(t *testing.T) {
+	publicKey, err := EncryptionPublicKey(encryptionPrivateKey)
+	require.NoError(t, err)
+
+	encrypted, err := Encrypt(publicKey, []byte("Invoice 2026-1015"))
+	require.NoError(t, err)
+
+	message, err := Decrypt(encryptionPrivateKey, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "Invoice 2026-1015", string(message))
+
+	// Every message is encrypted with a new ephemeral key and nonce
+	other, err := Encrypt(publicKey, []byte("Invoice 2026-1015"))
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, other)
+
+	// Modified messages are rejected
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	require.NoError(t, err)
+	sealed[len(sealed)-1] ^= 1
+	_, err = Decrypt(encryptionPrivateKey, base64.StdEncoding.EncodeToString(sealed))
+	assert.Equal(t, ErrDecryption, err)
+
+	_, err = Decrypt("bm90IGEga2V5", encrypted)
+	assert.EqualError(t, err, "Encryption key must be 32 bytes encoded in base64")
+	_, err = Decrypt(encryptionPrivateKey, "c2hvcnQ=")
+	assert.Equal(t, ErrDecryption, err)
+}
@@ -3,6 +3,7 @@ package crypto
 import (
 	"encoding/base64"
 
+	"github.com/stellar/gateway/signers"
 	"github.com/stellar/go/keypair"
 )
 
@@ -13,7 +14,11 @@ type SignerVerifierInterface interface {
 }
 
 // SignerVerifier implements methods to Sign and Verify signatures
-type SignerVerifier struct{}
+type SignerVerifier struct {
+	// Keys signs messages when secretSeed is a public key of an account with
+	// HSM or KMS signing backend
+	Keys *signers.Keyring
+}
 
 // Sign signs message using secretSeed. Returns base64-encoded signature.
 func (s *SignerVerifier) Sign(secretSeed string, message []byte) (string, error) {
@@ -22,7 +27,12 @@ func (s *SignerVerifier) Sign(secretSeed string, message []byte) (string, error)
 		return "", err
 	}
 
-	signature, err := kp.Sign(message)
+	var signature []byte
+	if _, full := kp.(*keypair.Full); !full && s.Keys.Has(kp.Address()) {
+		signature, err = s.Keys.Sign(kp.Address(), message)
+	} else {
+		signature, err = kp.Sign(message)
+	}
 	if err != nil {
 		return "", err
 	}
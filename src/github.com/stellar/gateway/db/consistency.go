@@ -0,0 +1,271 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// compareBatchSize is the number of rows of a table loaded at once by
+// CompareDatabases
+const compareBatchSize = 1000
+
+// ComponentTables contains tables of every component (migrations set)
+var ComponentTables = map[string][]string{
+	"gateway": {
+		"ReceivedPayment",
+		"SentTransaction",
+		"HeldPayment",
+		"ComplianceRepair",
+		"MemoRequiredDestination",
+		"FeatureFlag",
+		"SecurityEvent",
+		"APIKey",
+		"DailyAggregate",
+		"FederationSnapshot",
+		"ReceivedPaymentEvent",
+		"PaymentLimitUsage",
+		"NamedDestination",
+		"PaymentVelocity",
+		"ExpectedPayment",
+		"PaymentMatch",
+		"Refund",
+		"FailedCallback",
+		"OfflineTransaction",
+		"Quote",
+		"FiatValue",
+		"Webhook",
+		"MemoReference",
+		"SEP24Transaction",
+		"SentTransactionConflict",
+	},
+	"compliance": {
+		"AuthorizedTransaction",
+		"AllowedFI",
+		"AllowedUser",
+		"SentAttachment",
+		"FederationSnapshot",
+		"SanctionsScreening",
+		"ComplianceAudit",
+		"Attachment",
+	},
+}
+
+// ConsistencyReport is a result of CompareDatabases
+type ConsistencyReport struct {
+	Tables []TableReport
+}
+
+// TableReport compares rows of a table in primary and secondary databases
+type TableReport struct {
+	Table         string
+	PrimaryRows   int
+	SecondaryRows int
+	// Missing are IDs of rows that exist in the primary database only
+	Missing []int64
+	// Extra are IDs of rows that exist in the secondary database only
+	Extra []int64
+	// Different are IDs of rows with different values
+	Different []int64
+}
+
+// Consistent returns true when all tables contain the same rows
+func (r ConsistencyReport) Consistent() bool {
+	for _, table := range r.Tables {
+		if !table.Consistent() {
+			return false
+		}
+	}
+	return true
+}
+
+// Consistent returns true when both databases contain the same rows
+func (r TableReport) Consistent() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Different) == 0
+}
+
+// CompareDatabases compares all rows of tables in primary and secondary
+// databases by ID. Values are compared after normalization, so differences of
+// drivers (ex. time zones, bool and timestamp precision) are not reported.
+func CompareDatabases(ctx context.Context, primary, secondary *sqlx.DB, tables []string) (report ConsistencyReport, err error) {
+	for _, table := range tables {
+		var tableReport TableReport
+		tableReport, err = compareTable(ctx, primary, secondary, table)
+		if err != nil {
+			return
+		}
+		report.Tables = append(report.Tables, tableReport)
+	}
+	return
+}
+
+func compareTable(ctx context.Context, primary, secondary *sqlx.DB, table string) (report TableReport, err error) {
+	report.Table = table
+	var primaryRows, secondaryRows map[int64]string
+	var lastID int64
+
+	for {
+		primaryRows, err = loadRows(ctx, primary, table, lastID)
+		if err != nil {
+			return report, fmt.Errorf("Error loading %s rows from primary database: %s", table, err)
+		}
+		secondaryRows, err = loadRowsUntil(ctx, secondary, table, lastID, primaryRows)
+		if err != nil {
+			return report, fmt.Errorf("Error loading %s rows from secondary database: %s", table, err)
+		}
+
+		report.PrimaryRows += len(primaryRows)
+		report.SecondaryRows += len(secondaryRows)
+
+		for _, id := range sortedIDs(primaryRows) {
+			row, ok := secondaryRows[id]
+			if !ok {
+				report.Missing = append(report.Missing, id)
+			} else if row != primaryRows[id] {
+				report.Different = append(report.Different, id)
+			}
+			lastID = id
+		}
+		for _, id := range sortedIDs(secondaryRows) {
+			if _, ok := primaryRows[id]; !ok {
+				report.Extra = append(report.Extra, id)
+			}
+		}
+
+		if len(primaryRows) < compareBatchSize {
+			break
+		}
+	}
+	return
+}
+
+// loadRows loads the next batch of rows with ID greater than afterID
+func loadRows(ctx context.Context, database *sqlx.DB, table string, afterID int64) (map[int64]string, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id > ? ORDER BY id LIMIT %d", table, compareBatchSize)
+	return queryRows(ctx, database, database.Rebind(query), afterID)
+}
+
+// loadRowsUntil loads rows of the secondary database matching a batch of
+// primary rows: with ID greater than afterID and not greater than the last ID
+// of the batch (no limit when the batch is the last one)
+func loadRowsUntil(ctx context.Context, database *sqlx.DB, table string, afterID int64, batch map[int64]string) (map[int64]string, error) {
+	if len(batch) < compareBatchSize {
+		query := fmt.Sprintf("SELECT * FROM %s WHERE id > ?", table)
+		return queryRows(ctx, database, database.Rebind(query), afterID)
+	}
+
+	ids := sortedIDs(batch)
+	query := fmt.Sprintf("SELECT * FROM %s WHERE id > ? AND id <= ?", table)
+	return queryRows(ctx, database, database.Rebind(query), afterID, ids[len(ids)-1])
+}
+
+func queryRows(ctx context.Context, database *sqlx.DB, query string, args ...interface{}) (map[int64]string, error) {
+	sqlRows, err := database.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer sqlRows.Close()
+	rows := &sqlx.Rows{Rows: sqlRows, Mapper: database.Mapper}
+
+	result := map[int64]string{}
+	for rows.Next() {
+		row := map[string]interface{}{}
+		err = rows.MapScan(row)
+		if err != nil {
+			return nil, err
+		}
+
+		var id int64
+		columns := make([]string, 0, len(row))
+		for column, value := range row {
+			column = strings.ToLower(column)
+			if column == "id" {
+				id, err = strconv.ParseInt(normalize(value), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid id: %v", value)
+				}
+			}
+			columns = append(columns, column+"="+normalize(value))
+		}
+		sort.Strings(columns)
+		result[id] = strings.Join(columns, "\n")
+	}
+	return result, rows.Err()
+}
+
+func sortedIDs(rows map[int64]string) []int64 {
+	ids := make([]int64, 0, len(rows))
+	for id := range rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// normalize returns a value loaded from a database in a form that does not
+// depend on the database driver
+func normalize(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(value)
+	case time.Time:
+		return value.UTC().Truncate(time.Second).Format(time.RFC3339)
+	case bool:
+		if value {
+			return "1"
+		}
+		return "0"
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// fingerprint returns normalized values of `db` fields of entities loaded by
+// Repository or Driver, so the same rows loaded from different databases have
+// equal fingerprints. v is a pointer to a struct or a slice.
+func fingerprint(v interface{}) string {
+	var fields []string
+	var walk func(value reflect.Value)
+	walk = func(value reflect.Value) {
+		switch value.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if value.IsNil() {
+				fields = append(fields, "NULL")
+				return
+			}
+			walk(value.Elem())
+		case reflect.Slice:
+			if value.Type().Elem().Kind() == reflect.Uint8 {
+				fields = append(fields, string(value.Bytes()))
+				return
+			}
+			for i := 0; i < value.Len(); i++ {
+				walk(value.Index(i))
+			}
+		case reflect.Struct:
+			if t, ok := value.Interface().(time.Time); ok {
+				fields = append(fields, normalize(t))
+				return
+			}
+			for i := 0; i < value.NumField(); i++ {
+				if value.Type().Field(i).Tag.Get("db") != "" {
+					walk(value.Field(i))
+				}
+			}
+		default:
+			fields = append(fields, normalize(value.Interface()))
+		}
+	}
+	walk(reflect.ValueOf(v))
+	return strings.Join(fields, "\n")
+}
@@ -10,6 +10,8 @@ type Driver interface {
 	Init(url string) (err error)
 	DB() *sqlx.DB
 	MigrateUp(component string) (migrationsApplied int, err error)
+	MigrateDown(component string, max int) (migrationsApplied int, err error)
+	MigrationStatus(component string) (status MigrationStatus, err error)
 
 	Insert(object entities.Entity) (id int64, err error)
 	Update(object entities.Entity) (err error)
@@ -3,7 +3,44 @@
 // migrations_gateway/01_init.sql
 // migrations_gateway/02_payment_id.sql
 // migrations_gateway/03_transaction_id.sql
+// migrations_gateway/04_held_payment.sql
+// migrations_gateway/05_compliance_repair.sql
+// migrations_gateway/06_sent_transaction_region.sql
+// migrations_gateway/07_bad_seq_resolution.sql
+// migrations_gateway/08_memo_required_destination.sql
+// migrations_gateway/09_feature_flag.sql
+// migrations_gateway/10_security_event.sql
+// migrations_gateway/11_api_key.sql
+// migrations_gateway/12_destination_profile.sql
+// migrations_gateway/13_daily_aggregate.sql
+// migrations_gateway/14_federation_snapshot.sql
+// migrations_gateway/15_received_payment_event.sql
+// migrations_gateway/16_payment_limit_usage.sql
+// migrations_gateway/17_named_destination.sql
+// migrations_gateway/18_held_payment_approval.sql
+// migrations_gateway/19_payment_velocity.sql
+// migrations_gateway/20_expected_payment.sql
+// migrations_gateway/21_refund.sql
+// migrations_gateway/22_failed_callback.sql
+// migrations_gateway/23_offline_transaction.sql
+// migrations_gateway/24_quote.sql
+// migrations_gateway/25_fiat_value.sql
+// migrations_gateway/26_webhook.sql
+// migrations_gateway/27_webhook_format.sql
+// migrations_gateway/28_memo_reference.sql
+// migrations_gateway/29_sent_transaction_metadata.sql
+// migrations_gateway/30_leader_lease.sql
+// migrations_gateway/31_sep24_transaction.sql
+// migrations_gateway/32_held_payment_seed.sql
+// migrations_gateway/33_sent_transaction_conflict.sql
+// migrations_gateway/34_sent_transaction_original_id.sql
+// migrations_gateway/35_api_key_tenant.sql
 // migrations_compliance/01_init.sql
+// migrations_compliance/02_sent_attachment.sql
+// migrations_compliance/03_federation_snapshot.sql
+// migrations_compliance/04_sanctions_screening.sql
+// migrations_compliance/05_compliance_audit.sql
+// migrations_compliance/06_attachment.sql
 // DO NOT EDIT!
 
 package mysql
@@ -71,7 +108,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _migrations_gateway01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x93\xc1\xaf\x9a\x40\x10\xc6\xef\xfc\x15\x73\x84\xb4\x26\x6a\xaa\x69\x62\x3c\xa0\x6c\x5b\x52\x44\x8b\xcb\xc1\x13\xac\x30\xa5\x9b\xca\x2e\x59\x06\x6b\xff\xfb\x06\x9b\xd6\xc7\x9a\xe7\x7b\xef\xc8\xcc\x6f\x86\xfd\xbe\x2f\x33\x1a\xc1\xbb\x5a\x56\x46\x10\x42\xda\x38\xeb\x84\xf9\x9c\x01\xf7\x57\x11\x83\x3c\xc1\x02\xe5\x19\xcb\x9d\xf8\x5d\xa3\xa2\x1c\x5c\x07\x20\x97\x65\x0e\x52\x91\x3b\x99\x78\x10\x6f\x39\xc4\x69\x14\x81\x9f\xf2\x6d\x16\xc6\xeb\x84\x6d\x58\xcc\xdf\xf7\x9c\x6e\xd0\x08\x92\x5a\x65\xfd\xc4\x59\x98\xe2\x87\x30\xee\x74\x36\xbb\x8d\x5d\xb9\xc6\xe8\x02\xdb\x16\xcb\x4c\x50\x0e\xa5\x20\x24\x59\xa3\xc5\x88\x4a\xaa\x2a\x23\xfd\x13\xd5\xa3\x5d\x2d\x09\xea\xda\x07\xc4\x2e\x09\x37\x7e\x72\x80\xaf\xec\x00\x6e\x2f\xc5\xeb\xab\x69\x1c\x7e\x4b\xd9\xb5\x68\x3d\xdb\x1d\x7e\x7b\x8e\x07\x2c\xfe\x1c\xc6\x6c\x19\x2a\xa5\x83\x15\x04\xec\x93\x9f\x46\x1c\xd6\x5f\xfc\x64\xcf\xf8\xb2\xa3\xef\x1f\x17\x8e\x65\xe4\x1e\x15\x71\x23\x54\x2b\x8a\x7e\xd3\x1b\x8d\xa4\xdb\xe4\xc0\xca\xf9\x87\x17\xd4\x4f\xc6\x36\xa0\x3b\x53\xe0\x0d\x98\xcd\x6d\xa0\x3b\xd6\x92\xe8\x61\x16\x6d\x57\x14\x88\xa5\xcd\xfc\x33\xe2\x3f\x77\xc2\xb2\x42\x93\xc3\x51\x56\xbd\xca\xe9\xd8\xbb\x67\x50\x9d\xf1\xa4\x1b\xcc\x2e\xa5\xc9\x81\xf0\x42\xc3\x7f\x19\x6c\xbb\x13\xfd\xed\x0e\x32\xb5\x37\xdd\xe7\xfa\xda\xa4\x9e\x5e\x40\xa0\x7f\x29\x27\x48\xb6\xbb\xe7\x2e\x60\x31\xe8\xda\xb1\x2e\x9c\x3f\x01\x00\x00\xff\xff\x83\xe1\xb3\xac\x4f\x03\x00\x00")
+var _migrations_gateway01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x93\x41\xcf\x9a\x40\x10\x86\xef\xfc\x8a\x39\x42\x5a\x13\x35\xd5\x34\x31\x1e\x50\xb6\x2d\x29\xa2\xc5\xe5\xe0\x09\x56\x98\xd2\x4d\x65\x97\x2c\x83\xb5\xff\xbe\xc1\xc6\x5a\xd6\xd4\x7e\xdf\x71\x77\x9e\x99\x9d\x79\xdf\x9d\xd1\x08\xde\xd4\xb2\x32\x82\x10\xd2\xc6\x59\x27\xcc\xe7\x0c\xb8\xbf\x8a\x18\xe4\x09\x16\x28\xcf\x58\xee\xc4\xcf\x1a\x15\xe5\xe0\x3a\x00\xb9\x2c\x73\x90\x8a\xdc\xc9\xc4\x83\x78\xcb\x21\x4e\xa3\x08\xfc\x94\x6f\xb3\x30\x5e\x27\x6c\xc3\x62\xfe\xb6\xe7\x74\x83\x46\x90\xd4\x2a\xeb\x33\xce\xc2\x14\xdf\x84\x71\xa7\xb3\xd9\x3d\xed\xca\x35\x46\x17\xd8\xb6\x58\x66\x82\x72\x28\x05\x21\xc9\x1a\x2d\x46\x54\x52\x55\x19\xe9\xef\xa8\x9e\xd5\x6a\x49\x50\xd7\x3e\x21\x76\x49\xb8\xf1\x93\x03\x7c\x66\x07\x70\xfb\x51\xbc\xbe\x87\x34\x0e\xbf\xa4\xec\x7a\x69\xb5\xed\x0e\xcf\x9e\xe3\x01\x8b\x3f\x86\x31\x5b\x86\x4a\xe9\x60\x05\x01\xfb\xe0\xa7\x11\x87\xf5\x27\x3f\xd9\x33\xbe\xec\xe8\xeb\xfb\x85\x63\x09\xb9\x47\x45\xdc\x08\xd5\x8a\xa2\xaf\xf4\x4a\x21\xe9\x9e\x39\x90\x72\xfe\xee\x3f\xd3\x4f\xc6\x36\xa0\x3b\x53\xe0\x1d\x98\xcd\x6d\xa0\x3b\xd6\x92\xe8\xa9\x17\x6d\x57\x14\x88\xa5\xcd\xdc\x84\xf8\xc3\x9d\xb0\xac\xd0\xe4\x70\x94\x55\xff\x5d\xa6\x63\xef\x91\x41\x75\xc6\x93\x6e\x30\xbb\x94\x26\x07\xc2\x0b\x0d\xdf\x32\xd8\x76\x27\xfa\x1d\xbd\x35\x7d\xf5\xd4\xae\xf4\xe8\xeb\x4b\x9d\xfa\x7b\x03\x02\xfd\x43\x39\x41\xb2\xdd\xfd\x6b\x03\x16\x83\xa8\x6d\xeb\xc2\xf9\x35\x00\x83\xe1\xb3\xac\x4f\x03\x00\x00")
 
 func migrations_gateway01_initSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -86,12 +123,12 @@ func migrations_gateway01_initSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations_gateway/01_init.sql", size: 847, mode: os.FileMode(420), modTime: time.Unix(1461004393, 0)}
+	info := bindataFileInfo{name: "migrations_gateway/01_init.sql", size: 847, mode: os.FileMode(436), modTime: time.Unix(1523031737, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations_gateway02_payment_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x4b\x50\x70\x74\x71\x51\x48\x28\x48\xac\xcc\x4d\xcd\x2b\x89\xcf\x4c\x49\x50\x08\x73\x0c\x72\xf6\x70\x0c\xd2\x30\x32\x35\xd5\x54\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x81\x70\x1c\xdd\x40\xa6\x25\x64\xa6\x24\xe8\x80\xf5\x86\xfa\x79\x06\x86\xba\x2a\x68\x20\x9b\xa1\xa9\x60\xcd\xc5\x85\xec\x0a\x97\xfc\xf2\x3c\x02\xee\x70\x09\xf2\x0f\x40\x71\x88\x35\x17\x20\x00\x00\xff\xff\x8e\xff\xcd\xef\xc8\x00\x00\x00")
+var _migrations_gateway02_payment_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x4b\x50\x70\x74\x71\x51\x48\x28\x48\xac\xcc\x4d\xcd\x2b\x89\xcf\x4c\x49\x50\x08\x73\x0c\x72\xf6\x70\x0c\xd2\x30\x32\x35\xd5\x54\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x81\x70\x1c\xdd\x40\xa6\x25\x64\xa6\x24\xe8\x80\xf5\x86\xfa\x79\x06\x86\xba\x2a\x68\x20\x9b\xa1\xa9\x60\xcd\xc5\x85\xec\x0a\x97\xfc\xf2\x3c\x02\xee\x70\x09\xf2\x0f\x40\x71\x88\x35\x17\x60\x00\x8e\xff\xcd\xef\xc8\x00\x00\x00")
 
 func migrations_gateway02_payment_idSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -106,12 +143,12 @@ func migrations_gateway02_payment_idSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations_gateway/02_payment_id.sql", size: 200, mode: os.FileMode(420), modTime: time.Unix(1516147186, 0)}
+	info := bindataFileInfo{name: "migrations_gateway/02_payment_id.sql", size: 200, mode: os.FileMode(436), modTime: time.Unix(1523031737, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations_gateway03_transaction_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4a\x4d\x4e\xcd\x2c\x4b\x4d\x09\x48\xac\xcc\x4d\xcd\x2b\x49\x50\x70\x74\x71\x51\x48\x28\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x8b\xcf\x4c\x49\x50\x08\x73\x0c\x72\xf6\x70\x0c\xd2\x30\x33\xd1\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\xf7\xd3\x77\x54\xb7\xe6\xe2\x42\x36\xdd\x25\xbf\x3c\x8f\x80\xf9\x2e\x41\xfe\x01\x18\x16\x58\x73\x01\x02\x00\x00\xff\xff\xa5\x9e\xfe\x52\xa4\x00\x00\x00")
+var _migrations_gateway03_transaction_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4a\x4d\x4e\xcd\x2c\x4b\x4d\x09\x48\xac\xcc\x4d\xcd\x2b\x49\x50\x70\x74\x71\x51\x48\x28\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x8b\xcf\x4c\x49\x50\x08\x73\x0c\x72\xf6\x70\x0c\xd2\x30\x33\xd1\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\xf7\xd3\x77\x54\xb7\xe6\xe2\x42\x36\xdd\x25\xbf\x3c\x8f\x80\xf9\x2e\x41\xfe\x01\x18\x16\x58\x73\x01\x06\x00\xa5\x9e\xfe\x52\xa4\x00\x00\x00")
 
 func migrations_gateway03_transaction_idSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -126,12 +163,652 @@ func migrations_gateway03_transaction_idSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations_gateway/03_transaction_id.sql", size: 164, mode: os.FileMode(420), modTime: time.Unix(1516147358, 0)}
+	info := bindataFileInfo{name: "migrations_gateway/03_transaction_id.sql", size: 164, mode: os.FileMode(436), modTime: time.Unix(1523031737, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations_compliance01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x94\x4d\x73\xaa\x30\x14\x86\xf7\xfc\x8a\xb3\xc4\xb9\xba\xf0\xce\xd5\xb9\x33\x8e\x0b\x94\xd8\x32\x45\xb4\x34\x2c\x5c\x85\x54\x42\xcd\x54\x12\x27\x86\x6a\xfb\xeb\x3b\xd0\x96\x2f\xbf\xea\xb4\x3b\x38\x3c\x27\xbc\xe7\x49\x26\x9d\x0e\xfc\x49\xf8\x93\xa2\x9a\x41\xb0\x31\xc6\x3e\xb2\x30\x02\x6c\x8d\x5c\x04\xa1\x95\xea\x95\x54\xfc\x8d\x45\x58\x51\xb1\xa5\x4b\xcd\xa5\x08\xc1\x34\x00\x42\x1e\x85\xc0\x85\x36\xbb\xdd\x16\x78\x33\x0c\x5e\xe0\xba\x60\x05\x78\x46\x1c\x6f\xec\xa3\x29\xf2\x70\x3b\xe3\x74\xd9\x49\xb2\x9e\xe5\x8a\x2a\xb3\xff\xaf\x6c\xca\xa9\x84\x25\x32\x84\x17\xaa\x8e\x7f\xae\x2e\xb2\x8f\x54\x08\x9a\xed\x75\x1d\xa1\x45\x56\x42\x75\x08\x11\xd5\x4c\xf3\x84\xd5\xa1\x88\x6a\x7a\xa4\x79\xee\x3b\x53\xcb\x5f\xc0\x1d\x5a\x80\x99\x4d\xd6\x32\x5a\x80\xbc\x1b\xc7\x43\x43\x47\x08\x69\x8f\xc0\x46\x13\x2b\x70\x31\x8c\x6f\x2d\xff\x01\xe1\x61\xaa\xe3\xff\x03\xa3\xe9\x6b\xbd\x96\x3b\x16\x4d\x9c\x2b\x1d\x09\x9a\xb0\x72\xfa\xbf\xbd\x5e\x63\xfc\x48\x26\x94\x8b\x73\xc4\x26\x7d\x5c\xf3\x25\x79\x66\xaf\x9f\x86\x7b\xfd\x06\x41\x3f\xb2\x9d\x96\x73\x28\x21\xab\x06\x9e\x73\x1f\xa0\xbc\x58\xc4\x30\xbf\x9e\x0e\x88\x6a\x0c\xb3\xfa\xf6\x33\xa1\xc1\x96\xa9\x2b\x95\xc6\x9c\x5c\xb2\x1a\x73\x72\x59\x6c\xcc\xc9\x65\xb7\xe9\x96\xa9\xfc\x70\x9f\x5e\xe7\x17\xf4\xd7\xa2\x90\xe2\x9f\x66\x23\x63\xbb\xcc\xf3\x6d\xeb\xd5\x5b\xc0\x96\x3b\x61\xd8\xfe\x6c\x7e\xfe\x16\x18\xd4\x99\xe2\xe4\x1f\xad\xe7\x1b\x38\x30\xde\x03\x00\x00\xff\xff\xb0\xd9\x8a\xda\x6d\x04\x00\x00")
+var _migrations_gateway04_held_paymentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x91\xcf\x6e\xb3\x30\x10\xc4\xef\x7e\x8a\x3d\x82\xbe\x44\xfa\x52\x29\x55\xa5\x28\x07\x12\xdc\x06\x95\x38\x29\x85\x43\x4e\x60\x99\x6d\x8b\x44\x4c\x6a\xd6\xfd\xf3\xf6\x15\x44\x29\xd4\xaa\x7a\xf4\xfa\x37\xa3\xd9\xd9\xe9\x14\xfe\x1d\xab\x67\x23\x09\x21\x3b\xb1\x75\xc2\x83\x94\x43\x1a\xac\x62\x0e\xc5\x06\xeb\x72\x2f\x3f\x8f\xa8\xa9\x00\x8f\x01\x14\x55\x59\x40\xa5\xc9\x9b\xcd\x7c\x10\xbb\x14\x44\x16\xc7\x10\x64\xe9\x2e\x8f\xc4\x3a\xe1\x5b\x2e\xd2\x49\xc7\x9d\xce\xaa\xbc\xe3\xdf\xa4\x51\x2f\xd2\x78\x57\xf3\xf9\x20\xea\xa9\xb6\xb1\x46\x61\x2e\x95\x6a\xac\xa6\x81\x9c\x5f\x3b\xa0\xc1\x57\x8b\x2d\x15\x40\xf8\x41\x8e\x07\x49\xb2\xed\xa0\x9d\xfd\x77\xb4\xca\xa0\x24\x2c\x73\x49\x05\x94\x92\x90\xaa\x23\x3a\x16\x48\x54\xe3\x1f\x80\x92\x5a\x61\x5d\xbb\x26\x21\xbf\x0d\xb2\x78\xc4\x19\x6c\x6d\x7d\x49\xe9\xfe\xee\x93\x68\x1b\x24\x07\xb8\xe7\x07\xf0\xba\x22\xfd\x6e\x9a\x89\xe8\x21\xe3\xfd\xf0\x47\x69\xde\xf8\xd5\x93\x3d\x72\xde\x36\x1f\x25\xf6\x2e\x0d\x4c\xc6\x8b\xf8\xcc\x07\x2e\xee\x22\xc1\x97\x91\xd6\x4d\xb8\xfa\x8e\xb3\xde\x04\xc9\x23\x4f\x97\x96\x9e\x6e\x16\x8c\x8d\xef\x1f\x36\xef\x9a\x85\xc9\x6e\xff\xdb\xfd\x17\xec\x6b\x00\x2e\xb7\x30\x30\x2b\x02\x00\x00")
+
+func migrations_gateway04_held_paymentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway04_held_paymentSql,
+		"migrations_gateway/04_held_payment.sql",
+	)
+}
+
+func migrations_gateway04_held_paymentSql() (*asset, error) {
+	bytes, err := migrations_gateway04_held_paymentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/04_held_payment.sql", size: 555, mode: os.FileMode(420), modTime: time.Unix(1792005019, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway05_compliance_repairSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\xc1\x6a\xf3\x30\x10\x84\xef\x7a\x8a\x3d\xda\xfc\x7f\x0e\x29\x04\x0a\x21\x07\xc5\x56\x5b\x53\x47\x0e\xaa\x7c\xc8\xc9\x52\xed\x4d\x2a\x88\x25\x23\x2b\x69\xfb\xf6\x45\x14\xda\x26\x50\xf7\x38\xe8\x1b\x31\x3b\x33\x9b\xc1\xbf\xde\x1c\xbc\x0e\x08\xf5\x40\x32\xc1\xa8\x64\x20\xe9\xba\x64\xa0\x32\xd7\x0f\x47\xa3\x6d\x8b\x02\x07\x6d\xbc\x82\x84\x00\x28\xd3\x29\x30\x36\x24\xf3\x79\x0a\xbc\x92\xc0\xeb\xb2\x04\x5a\xcb\xaa\x29\x78\x26\xd8\x86\x71\xf9\x3f\x72\x6e\x40\xaf\x83\x71\xb6\x89\x8e\xb3\xf6\xed\x8b\xf6\xc9\xcd\x62\xf1\x6d\xfb\xe4\xbc\x39\x18\xab\x8f\x4d\x8f\xbd\x9b\x02\xdb\xaf\x3c\x7f\xa2\x67\xf4\x66\x6f\xb0\x6b\x9e\xdf\xa7\x30\x8f\x7a\x74\x56\x41\xc0\xb7\x70\xfd\x12\x2f\xc6\xae\xd1\x41\x41\xa7\x03\x06\xd3\xe3\x25\x32\x06\x1d\x4e\xe3\xc4\xf7\x5b\x51\x6c\xa8\xd8\xc1\x23\xdb\x41\x12\x6b\x4b\x63\xb6\xa8\xae\xba\x49\x2e\x75\x4a\x52\x60\xfc\xbe\xe0\x6c\x55\x58\xeb\xf2\x35\xe4\xec\x8e\xd6\xa5\x84\xec\x81\x8a\x27\x26\x57\xa7\xb0\xbf\x5d\x12\xf2\x73\xbd\xdc\xbd\x5a\x92\x8b\x6a\xfb\xeb\x7a\x4b\xf2\x31\x00\x9b\x29\x0d\x04\xee\x01\x00\x00")
+
+func migrations_gateway05_compliance_repairSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway05_compliance_repairSql,
+		"migrations_gateway/05_compliance_repair.sql",
+	)
+}
+
+func migrations_gateway05_compliance_repairSql() (*asset, error) {
+	bytes, err := migrations_gateway05_compliance_repairSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/05_compliance_repair.sql", size: 494, mode: os.FileMode(420), modTime: time.Unix(1792005241, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway06_sent_transaction_regionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x8f\x31\x8b\x83\x30\x1c\x47\xf7\x7c\x8a\xff\xa6\x72\xba\x1d\xb7\x38\xe5\x2e\x91\x13\x42\x6c\xd3\xa4\x74\x33\xa1\x0d\x92\xc1\x28\x69\xa0\xf4\xdb\x97\x0a\x82\xb6\x43\xdb\x31\x84\xdf\x7b\xef\x5f\x14\xf0\xd5\xbb\x2e\x98\x68\x41\x8d\x08\x33\x49\x05\x48\xfc\xcb\x28\xe8\x9d\xf5\x51\x06\xe3\xcf\xe6\x18\xdd\xe0\x35\x60\x42\x40\x07\xdb\x4d\x8f\x3d\x16\x7f\xff\x58\xa4\x3f\xdf\x19\xf0\x46\x02\x57\x8c\x01\xa1\x15\x56\x4c\x42\x92\x00\xae\xee\x28\x3d\x9a\x6b\x6f\x7d\x6c\xdd\x49\x97\x2f\xf0\x44\x34\x1b\xa8\x39\xa1\x87\xd5\x2c\x9f\xbc\x8a\xd7\x5b\x45\x67\x7d\xbb\xf8\x87\x74\x6e\xca\x57\xbb\xac\x44\x68\x79\x1e\x19\x2e\xfe\x83\x82\x67\xd1\x2a\x24\x7d\x50\xbd\x01\xd6\xc1\x76\x6e\xf0\xba\x44\xb7\x01\x00\xf7\x3d\x23\x4d\x76\x01\x00\x00")
+
+func migrations_gateway06_sent_transaction_regionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway06_sent_transaction_regionSql,
+		"migrations_gateway/06_sent_transaction_region.sql",
+	)
+}
+
+func migrations_gateway06_sent_transaction_regionSql() (*asset, error) {
+	bytes, err := migrations_gateway06_sent_transaction_regionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/06_sent_transaction_region.sql", size: 374, mode: os.FileMode(420), modTime: time.Unix(1792005626, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway07_bad_seq_resolutionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x4b\x50\x70\x74\x71\x51\x48\x48\x4a\x4c\x89\x2f\x4e\x2d\x8c\x2f\x4a\x2d\xce\xcf\x29\x85\x48\x84\x39\x06\x39\x7b\x38\x06\x69\x18\x9a\x69\x2a\xf8\x85\xfa\xf8\x28\xb8\xb8\xba\x39\x86\xfa\x84\x40\x38\x8e\x6e\x20\x43\x13\x8a\x52\x8b\x4b\x73\x4a\xe2\x2b\x52\x8a\x12\xac\xb9\xb8\x90\x2d\x76\xc9\x2f\xcf\x23\x60\xb5\x4b\x90\x7f\x00\x56\xbb\xad\xb9\x00\x03\x00\xa4\x8d\x17\x08\xc3\x00\x00\x00")
+
+func migrations_gateway07_bad_seq_resolutionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway07_bad_seq_resolutionSql,
+		"migrations_gateway/07_bad_seq_resolution.sql",
+	)
+}
+
+func migrations_gateway07_bad_seq_resolutionSql() (*asset, error) {
+	bytes, err := migrations_gateway07_bad_seq_resolutionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/07_bad_seq_resolution.sql", size: 195, mode: os.FileMode(420), modTime: time.Unix(1792006117, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway08_memo_required_destinationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x90\x31\x4f\xf3\x30\x10\x86\x77\xff\x8a\x1b\x13\x7d\x5f\x87\x4a\x50\x21\x55\x1d\xdc\xc6\x40\x44\xea\x16\x63\x0f\x9d\x1a\xcb\x39\xc0\x43\x6d\x70\x2e\xc0\xcf\x47\xce\x40\x1b\x16\x46\xdb\xcf\xf9\xb9\xf7\x9d\xcd\xe0\xdf\xc9\xbf\x24\x4b\x08\xe6\x8d\x6d\x94\xe0\x5a\x80\xe6\xeb\x46\x40\xbb\xc5\x53\x54\xf8\x3e\xf8\x84\x5d\x85\x3d\xf9\x60\xc9\xc7\xd0\x42\xc1\x00\x5a\xdf\xb5\xe0\x03\x15\xf3\x79\x09\x72\xa7\x41\x9a\xa6\x01\x6e\xf4\xee\x58\xcb\x8d\x12\x5b\x21\xf5\xff\xcc\x59\xe7\xe2\x10\xe8\x98\xf9\x0f\x9b\xdc\xab\x4d\xc5\xf5\xe2\x3c\x33\x42\x7d\x1c\x92\xc3\x33\x30\xff\x0d\x24\xb4\x7d\x56\x13\x7e\xd1\xf4\x85\x92\x0d\xbd\x75\x79\xb3\xd1\x31\x0a\x16\x57\xe5\x48\x40\x25\x6e\xb9\x69\x2e\x70\x97\xd0\x12\x76\x47\x4b\x2d\x74\x96\x90\xfc\x09\x27\x1f\xee\x55\xbd\xe5\xea\x00\x0f\xe2\x00\x45\x4e\x59\xe6\x39\x23\xeb\x47\x23\xc6\xcb\x49\xa2\xe2\xf2\x54\xb2\x12\x84\xbc\xab\xa5\x58\xd5\x21\xc4\x6a\xfd\xa3\xdf\xdc\x73\xf5\x24\xf4\x6a\xa0\xe7\x9b\x25\x63\x97\xb5\x57\xf1\x33\xb0\x4a\xed\xf6\x7f\xd5\xbe\x64\xdf\x03\x00\x6a\x26\xd0\xac\xae\x01\x00\x00")
+
+func migrations_gateway08_memo_required_destinationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway08_memo_required_destinationSql,
+		"migrations_gateway/08_memo_required_destination.sql",
+	)
+}
+
+func migrations_gateway08_memo_required_destinationSql() (*asset, error) {
+	bytes, err := migrations_gateway08_memo_required_destinationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/08_memo_required_destination.sql", size: 430, mode: os.FileMode(420), modTime: time.Unix(1792029806, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway09_feature_flagSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\xd0\x41\x4f\xc2\x30\x14\x07\xf0\x7b\x3f\xc5\x3b\x6e\x11\x0e\x18\x31\x26\x84\x43\x61\x6f\xba\x38\x3a\xac\xed\x81\xd3\xfa\x70\x15\x97\xb0\x42\x96\x37\x0d\xdf\xde\x6c\x89\xa2\x46\x3d\xb6\xfd\xb5\x7d\xff\xff\x78\x0c\x17\x4d\xbd\x6b\x89\x3d\xd8\xa3\x58\x6a\x94\x06\xc1\xc8\x45\x8e\xe0\x52\x4f\xdc\xb5\x3e\xdd\xd3\xce\x41\x24\x00\x5c\x5d\x39\xa8\x03\x47\x93\x49\x0c\xaa\x30\xa0\x6c\x9e\x83\xb4\xa6\x28\x33\xb5\xd4\xb8\x42\x65\x46\xbd\x0b\xd4\x78\x07\xaf\xd4\x3e\xbd\x50\x1b\x5d\x5f\x9d\xf5\x70\xcc\x3e\x50\xe0\x7f\x80\x0f\xb4\xdd\xfb\xca\x01\xd7\xe1\x34\x7c\xf8\x03\x74\xc7\x8a\xd8\x57\xe5\xf6\x74\x7e\xe5\x72\x3a\xfd\x43\x11\x3b\xe8\x3d\xd7\x8d\xff\x26\xd6\x3a\x5b\x49\xbd\x81\x7b\xdc\x40\xd4\xa7\x8b\xfb\x7b\x56\x65\x0f\x16\x87\xcd\x21\x49\xf9\x31\x6f\x34\x2c\xdd\xe8\x33\x41\x2c\x62\x40\x75\x9b\x29\x9c\x67\x21\x1c\x92\x05\x24\x98\x4a\x9b\x1b\x58\xde\x49\xfd\x88\x66\xde\xf1\xf3\xcd\x4c\x88\xaf\x3d\x27\x87\xb7\x20\x12\x5d\xac\x7f\xeb\x79\x26\xde\x07\x00\x4e\x59\x03\xa6\x93\x01\x00\x00")
+
+func migrations_gateway09_feature_flagSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway09_feature_flagSql,
+		"migrations_gateway/09_feature_flag.sql",
+	)
+}
+
+func migrations_gateway09_feature_flagSql() (*asset, error) {
+	bytes, err := migrations_gateway09_feature_flagSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/09_feature_flag.sql", size: 403, mode: os.FileMode(420), modTime: time.Unix(1792030079, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway10_security_eventSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x92\xc1\x6e\xf2\x30\x10\x84\xef\x7e\x8a\x3d\x26\xfa\x41\xfa\x69\xab\x0a\x09\x71\x08\xc4\x6d\xa3\x86\x80\x82\x73\xe0\x14\x5b\x64\x69\x2d\x11\x27\x32\x0b\x85\xb7\xaf\x8c\x28\x69\x02\xea\xd1\x9e\x6f\x76\xc6\xd6\xf6\xfb\xf0\xaf\xd4\x1f\x56\x11\x42\x56\xb3\x69\xca\x03\xc1\x41\x04\x93\x98\x83\x5c\xe2\x7a\x6f\x35\x9d\xf8\x01\x0d\x49\xf0\x18\x80\xd4\x85\x04\x6d\xc8\x1b\x0c\x7c\x48\xe6\x02\x92\x2c\x8e\x21\xc8\xc4\x3c\x8f\x92\x69\xca\x67\x3c\x11\x3d\xc7\xa1\xf3\xe4\x8e\x3e\x28\xbb\xfe\x54\xd6\x7b\x7e\x6a\x1c\x67\x84\x4e\x35\x36\xf2\xe3\x43\x47\xde\x54\xb6\x54\xd4\x00\xc3\x8e\x5e\xab\xd3\xb6\x52\x85\x04\xc2\x23\xb5\xa5\xb5\x45\x45\x58\xe4\xce\x5e\x28\x42\xd2\x25\xb6\x09\x45\x84\x65\x4d\xbb\x3b\x8f\x09\xf9\x4b\x90\xc5\x02\xfe\x9f\x53\x0c\x1e\x29\xbf\xd0\x7f\xcc\x2b\x70\xab\x0f\x68\xbb\x99\x3f\xb3\xae\xdc\x56\xed\x28\x47\x6b\x2b\x7b\xe9\xdd\x25\x16\x69\x34\x0b\xd2\x15\xbc\xf3\x15\x78\xee\xb7\x7d\x77\xeb\x4e\xad\x8c\xfc\xa6\x97\xd7\xd2\x65\xef\xb6\xba\xcf\x7c\xe0\xc9\x6b\x94\xf0\x71\x64\x4c\x15\x4e\xae\xf5\xa6\x6f\x41\xba\xe4\x62\xbc\xa7\xcd\x70\xc4\xd8\xef\xa5\x08\xab\x2f\xc3\xc2\x74\xbe\xb8\xbf\x14\x23\xf6\x3d\x00\xd8\x74\x27\x69\x42\x02\x00\x00")
+
+func migrations_gateway10_security_eventSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway10_security_eventSql,
+		"migrations_gateway/10_security_event.sql",
+	)
+}
+
+func migrations_gateway10_security_eventSql() (*asset, error) {
+	bytes, err := migrations_gateway10_security_eventSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/10_security_event.sql", size: 578, mode: os.FileMode(420), modTime: time.Unix(1792031588, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway11_api_keySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xd1\x41\x4f\xc2\x30\x14\x07\xf0\x7b\x3f\xc5\x3b\x6e\x51\x12\x31\x62\x4c\x08\x87\xc2\xaa\x2e\x8c\x82\xb5\x3d\x70\x5a\x9b\xf1\xc4\x46\x29\xda\xbd\xa9\x7c\x7b\xb3\x19\x41\x88\xb7\xa6\xf9\xbd\xf6\xe5\xff\xef\xf5\xe0\x6c\xe3\xd7\xd1\x11\x82\x79\x63\x13\x25\xb8\x16\xa0\xf9\xb8\x10\x60\xf9\x22\x9f\xe2\xce\x42\xc2\x00\xac\x5f\x59\xf0\x81\x92\x7e\x3f\x05\x39\xd7\x20\x4d\x51\x00\x37\x7a\x5e\xe6\x72\xa2\xc4\x4c\x48\x7d\xde\xba\x17\xdc\x95\xad\xfd\x70\xb1\x7a\x76\x31\xb9\xbe\x3a\xf8\x0e\xd4\x58\x45\xa4\x03\xb8\x1c\x0c\x4e\x44\xc4\xf7\xc6\x47\x2c\x6b\xbf\x0e\x8e\x9a\x88\x16\xc8\x87\x5d\xf7\xfb\x81\x42\x26\x6e\xb9\x29\x34\x5c\xfc\x0c\x39\xc2\xf2\xd5\x6f\x3c\xfd\xb3\xe7\x31\xad\xb7\x4d\xac\xb0\xb6\x40\xf8\x45\x7b\xd4\xbd\x52\x45\x74\x84\xab\xd2\x91\x85\x95\x23\x24\xbf\xc1\x23\xb1\x50\xf9\x8c\xab\x25\x4c\xc5\x12\x92\x36\x94\xb4\x9d\x33\x32\x7f\x30\xa2\xbb\xdc\x07\x90\xfc\x9e\x52\x96\x82\x90\x77\xb9\x14\xa3\x3c\x84\x6d\x36\xde\x6f\x3e\xb9\xe7\xea\x51\xe8\x51\x43\x4f\x37\x43\xc6\xfe\x96\x91\x6d\x3f\x03\xcb\xd4\x7c\x71\x52\xc6\x90\x7d\x0f\x00\x4b\x3b\x3c\x40\xb3\x01\x00\x00")
+
+func migrations_gateway11_api_keySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway11_api_keySql,
+		"migrations_gateway/11_api_key.sql",
+	)
+}
+
+func migrations_gateway11_api_keySql() (*asset, error) {
+	bytes, err := migrations_gateway11_api_keySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/11_api_key.sql", size: 435, mode: os.FileMode(420), modTime: time.Unix(1792034520, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway12_destination_profileSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xf0\x4d\xcd\xcd\x0f\x4a\x2d\x2c\xcd\x2c\x4a\x4d\x71\x49\x2d\x2e\xc9\xcc\x4b\x2c\xc9\xcc\xcf\x4b\x50\x70\x74\x71\x51\x48\x28\x28\xca\x4f\xcb\xcc\x49\x4d\x50\x08\x73\x0c\x72\xf6\x70\x0c\xd2\x30\x33\xd1\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\xf0\x0b\xf5\xf1\xb1\xe6\xe2\x42\x36\xdd\x25\xbf\x3c\x8f\x48\xf3\x5d\x82\xfc\x03\x10\x16\x58\x73\x01\x06\x00\xc1\xbb\xbe\x72\xa5\x00\x00\x00")
+
+func migrations_gateway12_destination_profileSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway12_destination_profileSql,
+		"migrations_gateway/12_destination_profile.sql",
+	)
+}
+
+func migrations_gateway12_destination_profileSql() (*asset, error) {
+	bytes, err := migrations_gateway12_destination_profileSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/12_destination_profile.sql", size: 165, mode: os.FileMode(420), modTime: time.Unix(1792034791, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway13_daily_aggregateSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xd2\x41\x4f\xc2\x30\x14\x07\xf0\x7b\x3f\xc5\xbb\xb1\xc5\x91\x00\x51\x62\x42\x38\x14\x56\x75\x71\x74\x58\xdb\x03\x27\x5a\xb7\x3a\x9b\x48\x67\xba\x4e\xc3\xb7\x37\xdb\x81\x4d\x82\xc6\x6b\xfb\xeb\xeb\x7b\xff\xbc\xf1\x18\xae\x0e\xa6\x74\xca\x6b\x10\x1f\x68\xcd\x08\xe6\x04\x38\x5e\xa5\x04\x64\xac\xcc\xfb\x11\x97\xa5\xd3\xa5\xf2\x5a\x42\x80\x00\xa4\x29\x24\x18\xeb\x83\xe9\x34\x04\x9a\x71\xa0\x22\x4d\x01\x0b\x9e\xed\x13\xba\x66\x64\x43\x28\x8f\x5a\x57\xa8\xa3\x84\x4f\xe5\xf2\x37\xe5\x82\xe9\xa4\xc7\xdd\xad\xd7\x56\x59\xdf\x83\xf9\xf5\xa0\x5a\x4c\xee\xb0\x48\x39\x8c\x46\x9d\x2d\x8c\xd3\xb9\x37\x95\xfd\xa3\x9e\xaa\x6b\xed\xf7\x79\x55\xe8\x01\x9a\x5d\x44\xa6\xae\x1b\xed\x7a\x76\x33\xef\xd9\xf9\xd7\x79\xd5\xb4\x5d\xbe\x98\xb2\x1d\x79\x36\xb9\x20\x27\x1d\x54\x87\x7f\xca\x2d\x4b\x36\x98\xed\xe0\x91\xec\x20\x68\xc3\x0c\xdb\xf7\x82\x26\x4f\x82\x74\x87\x52\x0d\x02\xef\x52\x8c\x4e\x71\x45\xc3\x30\xa2\x1f\x53\x47\x67\xe3\x85\x28\x04\x42\xef\x13\x4a\x96\x89\xb5\x55\xbc\x3a\xb5\xb1\x7e\xc0\xec\x99\xf0\x65\xe3\x5f\x6f\x17\x08\x0d\x37\x20\xae\xbe\x2c\x8a\x59\xb6\xfd\x65\x03\x16\xe8\x7b\x00\x85\xaa\x6a\xcd\x30\x02\x00\x00")
+
+func migrations_gateway13_daily_aggregateSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway13_daily_aggregateSql,
+		"migrations_gateway/13_daily_aggregate.sql",
+	)
+}
+
+func migrations_gateway13_daily_aggregateSql() (*asset, error) {
+	bytes, err := migrations_gateway13_daily_aggregateSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/13_daily_aggregate.sql", size: 560, mode: os.FileMode(420), modTime: time.Unix(1792035022, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway14_federation_snapshotSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\xc1\x6e\x82\x40\x10\x86\xef\x3c\xc5\xdc\x84\xb4\x26\xb5\xa9\xa6\x89\xf1\x80\xb2\xb6\xa4\x8a\x06\xe1\xe0\x09\xb6\xb0\x94\x4d\x65\x21\xbb\x83\xad\x6f\x5f\xa0\xa9\xa2\x54\x8f\x33\xf3\xfd\x93\xf9\x67\xa6\xdf\x87\xbb\x8c\x7f\x48\x8a\x0c\xfc\x42\x9b\xb9\xc4\xf4\x08\x78\xe6\x74\x41\x20\x9c\xb3\x98\x55\x15\x9e\x8b\x8d\xa0\x85\x4a\x73\x0c\x41\xd7\x00\x42\x1e\x87\xc0\x05\xea\x83\x81\x01\xce\xca\x03\xc7\x5f\x2c\xc0\xf4\xbd\x55\x60\x3b\x55\x8b\x25\x71\xbc\xfb\x9a\x43\x49\x85\xa2\x51\xdd\x21\xa8\x35\x51\x4a\xa5\x3e\x7a\x3a\x89\x1a\xaa\xa0\x87\x8c\x09\x6c\x88\x3d\x95\x0d\xf4\x38\x1c\x1a\x60\x91\xb9\xe9\x2f\x5a\xe4\x27\x17\x2d\x66\x30\xba\x68\x54\xca\x5d\x08\xc8\xbe\xf1\x3c\x9d\x70\x41\x77\xc1\x95\xa2\x42\x8a\xa5\x0a\xa2\x3c\x66\x5d\x4f\x0d\xf1\x9e\xc7\x87\x10\x32\x16\xf3\x32\xeb\xea\x51\x96\x22\xaa\xb6\x57\xcd\x85\x5c\x1c\x9a\x0e\xad\xa5\xfc\x59\x78\xf8\x85\x77\x2a\xd8\x33\xa9\xaa\x7d\xfc\x6f\xe3\xc8\xf7\x7a\x8d\x20\xe2\x45\xca\x64\xa0\x4a\x8e\xec\xa4\x68\x6f\xb0\xa3\x60\x12\x79\xc2\xeb\x91\xd4\xf5\xa9\x13\x86\x51\xca\xe2\x80\x56\x17\x8d\x2b\x14\x79\xc6\xce\x09\xc9\xa2\x5c\xc6\xb7\x90\xb5\x6b\x2f\x4d\x77\x0b\x6f\x64\x0b\x7a\xfd\x12\x46\x9d\xad\xa3\xce\xdd\xf5\xcb\xcc\x09\x6d\x1f\x5f\x6f\x47\x86\x66\x00\x71\x5e\x6c\x87\x4c\x6c\x21\x72\x6b\x7a\x74\x3a\x7b\x35\xdd\x0d\xf1\x26\x25\x26\xcf\x63\x4d\xeb\xb7\x5e\xd8\xca\xbf\x84\x66\xb9\xab\xf5\x8d\x17\x1e\x6b\x3f\x0d\xd9\xee\x40\xf5\x02\x00\x00")
+
+func migrations_gateway14_federation_snapshotSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway14_federation_snapshotSql,
+		"migrations_gateway/14_federation_snapshot.sql",
+	)
+}
+
+func migrations_gateway14_federation_snapshotSql() (*asset, error) {
+	bytes, err := migrations_gateway14_federation_snapshotSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/14_federation_snapshot.sql", size: 757, mode: os.FileMode(420), modTime: time.Unix(1792037395, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway15_received_payment_eventSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x50\xcb\x6a\xc3\x30\x10\xbc\xeb\x2b\xf6\x28\xd3\x1a\x4a\x20\x50\x08\x39\x28\xf1\x36\x35\x75\x64\xa3\xca\x87\x9c\x6c\xd5\x56\x52\x41\x2d\x19\xa3\xba\xcd\xdf\x57\xea\xa5\xf1\xa5\x97\x65\x1f\x33\xb3\xc3\xa4\x29\xdc\x0d\xe6\x32\x29\xaf\xa1\x1e\xc9\x5e\x20\x93\x08\x92\xed\x0a\x84\x56\xe8\x4e\x9b\x59\xf7\x95\xba\x0e\xda\x7a\x9c\x43\x69\x81\x12\x80\xd6\xf4\x2d\xbc\x99\x8b\xb1\x9e\xae\x1e\x12\xe0\xa5\x04\x5e\x17\x05\xb0\x5a\x96\x4d\xce\x83\xce\x11\xb9\xbc\x8f\x50\x37\xea\x20\x6f\x9c\x6d\x22\x69\x56\x53\xf7\xae\x26\xba\x5a\xaf\xff\x68\xbf\xb8\x51\x5d\x3f\x9c\x0a\x10\xaf\xbf\xfd\xf2\xd4\x4d\x3a\x18\xec\x1b\x15\xde\xf7\xa1\xf3\x66\xd0\x0b\x44\x25\xf2\x23\x13\x27\x78\xc1\x13\xd0\x68\x2e\x89\xdb\x38\x2d\xb8\xf4\x76\x4a\x48\x02\xc8\x0f\x39\xc7\x6d\x6e\xad\xcb\x76\x90\xe1\x13\xab\x0b\x09\xfb\x67\x26\x5e\x51\x6e\x3f\xfd\xf9\x71\x43\x48\x7a\x93\x51\xe6\xbe\x2c\xc9\x44\x59\xfd\x9b\xd1\x86\xfc\x00\x69\xff\x8d\x18\x58\x01\x00\x00")
+
+func migrations_gateway15_received_payment_eventSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway15_received_payment_eventSql,
+		"migrations_gateway/15_received_payment_event.sql",
+	)
+}
+
+func migrations_gateway15_received_payment_eventSql() (*asset, error) {
+	bytes, err := migrations_gateway15_received_payment_eventSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/15_received_payment_event.sql", size: 344, mode: os.FileMode(420), modTime: time.Unix(1792041118, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway16_payment_limit_usageSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x91\x41\x4f\x83\x40\x10\x85\xef\xfb\x2b\xe6\x56\x88\x90\x00\x09\xc6\xa4\xe9\x81\x96\x55\x89\x74\x41\xdc\x3d\xf4\xd4\x5d\xcb\x8a\x7b\x60\x31\xb0\x68\xfa\xef\x15\x6a\x84\x9a\xf6\x3a\xf3\xbd\x79\xf3\x66\x5c\x17\x6e\x6a\x55\xb5\xc2\x48\x60\x1f\x68\x53\xe0\x88\x62\xa0\xd1\x3a\xc5\xc0\x73\x71\xac\xa5\x36\xa9\xaa\x95\x61\x9d\xa8\x24\x07\x0b\x01\x70\x55\x72\x50\xda\x58\xbe\x6f\x03\xc9\x28\x10\x96\xa6\x10\x31\x9a\xed\x13\xf2\x33\x61\x8b\x09\x75\x06\xae\x14\x47\x0e\x9f\xa2\x3d\xbc\x8b\xd6\xf2\xbd\x09\x1e\xbb\xa2\xeb\xa4\xd9\x1f\x9a\x52\xce\xa0\xe0\x22\xa4\xba\xae\x97\xed\x84\x85\xb7\x33\xe3\x18\xdf\x47\x2c\xa5\xb0\x58\x9c\x4c\x65\x67\x94\x16\x46\x35\x7a\x12\x04\x61\x78\x5d\x21\xea\xa6\xd7\x86\xc3\xab\xaa\x86\x54\x81\x77\x01\xf5\x06\x32\x2f\x92\x6d\x54\xec\xe0\x09\xef\xc0\x1a\xae\x60\x0f\x55\x46\x92\x67\x86\xc7\x22\xef\x7f\xaf\x34\x46\x77\xce\x32\x3a\xff\xc2\x38\xe7\xab\xda\xc8\x06\x4c\x1e\x12\x82\x57\x89\xd6\x4d\xbc\xfe\xb3\xde\x3c\x46\xc5\x0b\xa6\xab\xde\xbc\xdd\x2d\x11\x72\x67\x1f\x8b\x9b\x2f\x8d\xe2\x22\xcb\xaf\x7f\x6c\x89\xbe\x01\xf4\xa4\x7f\x63\xe3\x01\x00\x00")
+
+func migrations_gateway16_payment_limit_usageSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway16_payment_limit_usageSql,
+		"migrations_gateway/16_payment_limit_usage.sql",
+	)
+}
+
+func migrations_gateway16_payment_limit_usageSql() (*asset, error) {
+	bytes, err := migrations_gateway16_payment_limit_usageSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/16_payment_limit_usage.sql", size: 483, mode: os.FileMode(420), modTime: time.Unix(1792041624, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway17_named_destinationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x91\xc1\x4e\xc3\x30\x0c\x86\xef\x79\x0a\xdf\xd6\x0a\x7a\xe8\x44\x27\xa4\x69\x87\x6c\x0d\x50\xd1\x65\xa3\x24\x87\x9d\x9a\xa8\x09\x90\x43\xd3\xaa\x4d\x41\xbc\x3d\xed\x80\xad\x4c\xda\x38\xd9\x72\x3e\xff\x76\x7e\x07\x01\x5c\x95\xe6\xb5\x91\x4e\x03\xaf\xd1\x2a\x23\x98\x11\x60\x78\x99\x12\x10\x54\x96\x5a\xc5\xba\x75\xc6\x4a\x67\x2a\x2b\xc0\x43\x00\xc2\x28\x01\xc6\x3a\x2f\x0c\x7d\xa0\x1b\x06\x94\xa7\x29\x60\xce\x36\x79\x42\x7b\x81\x35\xa1\xec\x7a\xe0\x6c\xdf\x2e\xe0\x5d\x36\xc5\x9b\x6c\xbc\xd9\xcd\x91\xde\x3f\xab\xb1\xf0\x2f\x35\x8d\xa2\x13\xac\xd4\x65\x95\xbb\xcf\x7a\x24\x15\xce\x46\x83\x63\x72\x87\x79\xca\x60\x32\x39\xe0\x67\xe4\x4e\x51\xd9\xb6\xda\xe5\x45\xa5\xc6\xd2\xd3\xff\x78\xd3\xb6\x9d\x6e\x8e\x1d\xd1\x85\x65\x8a\x46\xf7\xc6\xaa\x5c\x3a\x01\xaa\xcf\x9c\x29\xf5\xdf\xdf\x75\xb5\xba\x4c\x6c\xb3\x64\x8d\xb3\x1d\x3c\x92\x1d\x78\x83\xf5\xfe\x50\xe5\x34\x79\xe2\x64\x5f\xfc\xb1\xd9\xfb\x8e\x3e\xf2\x81\xd0\xfb\x84\x92\x45\x62\x6d\x15\x2f\x0f\x2b\xad\x1e\x70\xf6\x4c\xd8\xa2\x73\x2f\xb7\x73\x84\x82\xd1\xe1\xe3\xea\xc3\xa2\x38\xdb\x6c\xcf\x1e\x7e\x8e\xbe\x00\xc4\x49\x2c\xf9\x29\x02\x00\x00")
+
+func migrations_gateway17_named_destinationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway17_named_destinationSql,
+		"migrations_gateway/17_named_destination.sql",
+	)
+}
+
+func migrations_gateway17_named_destinationSql() (*asset, error) {
+	bytes, err := migrations_gateway17_named_destinationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/17_named_destination.sql", size: 553, mode: os.FileMode(420), modTime: time.Unix(1792041959, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway18_held_payment_approvalSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\xf0\x48\xcd\x49\x09\x48\xac\xcc\x4d\xcd\x2b\x49\x50\x70\x74\x71\x51\x48\x48\x2e\x4a\x05\x2a\x4c\x89\x4f\xaa\x4c\x50\x28\x4b\x2c\x4a\xce\x48\x2c\xd2\x30\x33\xd1\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\xf0\x0b\xf5\xf1\xb1\x26\x64\x48\x62\x41\x41\x51\x7e\x19\xd5\x4c\x49\x04\x8a\xa4\x00\x5d\x55\x92\x99\x9b\x8a\x66\x04\x97\x2e\x92\xef\x5c\xf2\xcb\xf3\xf0\x18\xea\x12\xe4\x1f\x80\xe2\x41\x6b\x82\x8a\x91\x3d\x42\x82\x6a\xa0\x83\xad\xb9\x00\x02\x48\x45\x4a\x78\x01\x00\x00")
+
+func migrations_gateway18_held_payment_approvalSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway18_held_payment_approvalSql,
+		"migrations_gateway/18_held_payment_approval.sql",
+	)
+}
+
+func migrations_gateway18_held_payment_approvalSql() (*asset, error) {
+	bytes, err := migrations_gateway18_held_payment_approvalSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/18_held_payment_approval.sql", size: 376, mode: os.FileMode(420), modTime: time.Unix(1792042129, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway19_payment_velocitySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x91\x51\x4f\x83\x30\x14\x85\xdf\xfb\x2b\xee\xdb\x4a\x1c\x89\x90\x60\x4c\x96\x3d\x74\xa3\x2a\x91\xc1\x82\xc5\x64\x4f\x50\xa1\xce\x26\xd2\x1a\x28\x9a\xfd\x7b\x61\xc6\x01\xcb\xf6\x76\x6f\xf3\x9d\xd3\x7b\xee\xb5\x6d\xb8\xa9\xe4\xbe\xe6\x46\x40\xfa\x85\xd6\x09\x25\x8c\x02\x23\xab\x90\x42\xbe\xe5\x87\x4a\x28\xf3\x2a\x3e\x75\x21\xcd\x21\x07\x8c\x00\x72\x59\xe6\x20\x95\xc1\x8e\x63\x41\x14\x33\x88\xd2\x30\x04\x92\xb2\x38\x0b\xa2\x4e\xbf\xa1\x11\x9b\xf7\x5c\xa3\xdb\xba\x10\x39\x7c\xf3\xba\xf8\xe0\x35\xf6\xee\x06\xfe\x08\x94\xa2\x31\x52\x71\x23\xb5\x1a\x28\xd7\xf3\xce\x30\xde\x34\xc2\x64\x85\x2e\x47\x5e\x8e\x7b\x11\x92\x4d\xd3\x8a\xfa\xf2\x97\xe0\xd3\x07\x92\x86\x0c\x66\xb3\x3f\x45\xa5\x5b\x65\x72\x78\x93\xfb\x3e\x8d\x7b\x7b\xe6\x58\xd4\xa2\x5b\x4a\x99\xf1\x8e\x29\xbb\xca\xc8\x4a\x4c\x88\x6d\x12\x6c\x48\xb2\x83\x67\xba\x03\xdc\x6f\xc5\xea\x5f\xfb\xee\x14\x1d\xff\x57\xf3\x89\xdf\x00\x4e\x56\x80\x27\xed\x35\xc9\x78\x2e\x3c\x41\x90\x05\x34\x7a\x0c\x22\xba\x0c\x94\xd2\xfe\xea\x94\x78\xfd\x44\x92\x17\xca\x96\xad\x79\xbf\x5f\x20\x64\x8f\x6e\xee\xeb\x1f\x85\xfc\x24\xde\x5e\xbb\xf9\x02\xfd\x02\x63\xdb\x32\x3b\x23\x02\x00\x00")
+
+func migrations_gateway19_payment_velocitySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway19_payment_velocitySql,
+		"migrations_gateway/19_payment_velocity.sql",
+	)
+}
+
+func migrations_gateway19_payment_velocitySql() (*asset, error) {
+	bytes, err := migrations_gateway19_payment_velocitySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/19_payment_velocity.sql", size: 547, mode: os.FileMode(420), modTime: time.Unix(1792042397, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway20_expected_paymentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x94\x41\x6f\x82\x30\x18\x86\xef\xfc\x8a\xde\x06\x99\x26\x6a\xa6\x59\x62\x3c\xa0\x74\x1b\x19\xa2\x63\x70\xf0\x04\x5d\xa9\xda\x03\x94\x40\x71\xf3\xdf\x8f\xa2\x13\xa8\xc4\xcd\x2c\x4b\x76\xa3\xed\xfb\x7e\xf9\xde\x7e\x0f\xed\x76\xc1\x6d\x44\x37\x29\xe2\x04\x78\x89\x32\x73\xa0\xee\x42\xe0\xea\x53\x0b\x82\x00\x7e\x24\x04\x73\x12\x2e\xd1\x3e\x22\x31\x0f\x80\xaa\x00\x10\xd0\x30\x00\x34\xe6\x6a\xbf\xaf\x01\x7b\xe1\x02\xdb\xb3\x2c\xa0\x7b\xee\xc2\x37\xed\xc2\x3f\x87\xb6\xdb\x11\x3a\x72\x74\xfb\xc2\xb0\x43\x29\xde\xa2\x54\x1d\xdd\x55\xa6\x52\x85\x22\x96\x8b\xd2\x6f\x74\x23\x8a\x0e\x7a\xf2\x79\x96\x11\xee\x63\x16\x92\xaa\x48\x7f\xd0\x2a\xa2\x59\x96\x93\xb4\x92\x0d\x47\xb5\x06\x0d\xf8\xa0\x7b\x96\x0b\x6e\x6e\x4a\x47\x44\x22\xe6\xf3\x7d\x52\xab\x3a\x92\x8a\x0a\xc9\x85\xc6\x33\x8e\x78\x9e\xd5\x9a\x92\xfd\x29\xc1\x84\xee\x8a\xfc\x17\x22\x9e\xda\xea\x95\x16\x9c\x12\x24\x6e\x0c\x15\xea\xb0\xf8\xe2\x34\x22\xcd\xa2\x79\x12\x5e\x56\x2c\x1d\x73\xae\x3b\x2b\xf0\x0c\x57\x40\x15\xb3\xd2\xc4\xae\x67\x9b\x2f\x1e\x2c\x37\x9b\x73\x51\x1b\xcb\x52\x5b\x8a\x0e\xe1\xd5\xda\x3d\x75\x8e\x9b\x9d\x53\x74\x4d\xd1\x00\xb4\x1f\x4d\x1b\x4e\xcc\x38\x66\xc6\xf4\x94\x66\xf6\xa4\x3b\xaf\xd0\x9d\xe4\x7c\x7d\x3f\x56\x24\xaa\x8e\x34\xcd\x11\xc7\xdb\x2b\x91\x62\x09\x29\x48\xa5\x2c\x6e\x30\x35\x18\x0e\xa5\xab\xe7\x29\x8a\x33\x84\xcf\x94\x67\x43\x5c\xa7\x2c\xf2\x11\xc6\x87\x01\xb5\x81\xf3\x7f\x21\x6d\x53\xb7\x47\x95\x95\xdf\xfe\x99\xb2\xe1\x2f\x58\x8f\xc4\xfc\x7f\x47\x72\x13\x07\xb5\xb9\xae\x58\xfe\x11\xf0\x5f\x11\xd5\xab\xe9\xee\xd6\x9e\x50\x83\xbd\xc7\x8a\xe1\x2c\x96\xad\xb0\x8f\x1b\x47\xf2\xeb\x3a\x56\x3e\x01\x4a\x75\xcc\x08\x8d\x05\x00\x00")
+
+func migrations_gateway20_expected_paymentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway20_expected_paymentSql,
+		"migrations_gateway/20_expected_payment.sql",
+	)
+}
+
+func migrations_gateway20_expected_paymentSql() (*asset, error) {
+	bytes, err := migrations_gateway20_expected_paymentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/20_expected_payment.sql", size: 1421, mode: os.FileMode(420), modTime: time.Unix(1792043311, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway21_refundSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x92\x4f\x6f\x82\x30\x18\xc6\xef\xfd\x14\xef\x4d\xc8\xe6\x41\x33\xcd\x12\xe3\x01\xa5\xdb\xc8\xb0\x2a\x2b\x07\x4f\xd8\x40\xdd\x7a\xa0\x25\x6d\xd9\xe2\xb7\x1f\x90\x4c\xb1\x33\x7a\x7b\xdb\xfc\x9e\xe7\xfd\x3b\x1c\xc2\x43\x29\x3e\x35\xb3\x1c\xd2\x0a\x2d\x13\x1c\x50\x0c\x34\x58\xc4\x18\xf6\x09\x3f\xd4\xb2\xd8\x83\x87\x00\xf6\xa2\x09\x84\xb4\xde\x68\xe4\x03\x59\x53\x20\x69\x1c\x43\x90\xd2\x75\x16\x91\x46\xb6\xc2\x84\x3e\xb6\x9c\xaa\x78\xe3\x26\x94\xcc\x5a\xc5\x37\xd3\xf9\x17\xd3\xde\x78\x32\x39\xcb\x3a\xce\x6a\x26\x0d\xcb\xff\x91\xd3\x27\x07\xac\xd8\xb1\xe4\xd2\xde\xb1\x2b\xb8\xb1\x42\x76\x89\xcf\xd8\x64\xea\x50\xac\x54\xb5\xb4\xb7\x7c\x98\x31\xdc\x66\xb9\x2a\xf8\x99\x1a\x8d\x7b\x2d\x87\xf8\x25\x48\x63\x0a\x83\x41\x8f\x17\xc6\xd4\x5c\x5f\x4f\xec\x2a\x4a\x5e\xaa\xcc\x1e\xab\x5e\x82\x3b\xf4\xf5\xe1\xb8\xa4\xe6\xcc\xf4\x9b\xbf\xe8\xcd\x85\x8d\x65\xb6\x36\xbd\x16\xdd\x49\xe9\x6e\xf7\xd9\xad\x2d\xfd\x59\x9e\x34\x79\x53\x82\xe5\x45\xc6\x9a\x09\x17\x4d\x64\x45\xc9\x2f\x5d\xeb\xaa\xb8\x4d\x6c\x92\x68\x15\x24\x3b\x78\xc7\x3b\xf0\xda\x9b\xf3\xdb\xdf\x94\x44\xdb\x14\x77\x9f\xce\x7d\x79\x97\x6f\x1f\xf9\x80\xc9\x6b\x44\xf0\x3c\x92\x52\x85\x8b\x53\x91\xcb\xb7\x20\xf9\xc0\x74\x5e\xdb\xc3\xf3\x0c\xa1\x61\xef\xee\x43\xf5\x23\x51\x98\xac\x37\xce\xdd\xcf\xd0\x2f\x28\x6f\x3e\xab\x1e\x03\x00\x00")
+
+func migrations_gateway21_refundSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway21_refundSql,
+		"migrations_gateway/21_refund.sql",
+	)
+}
+
+func migrations_gateway21_refundSql() (*asset, error) {
+	bytes, err := migrations_gateway21_refundSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/21_refund.sql", size: 798, mode: os.FileMode(420), modTime: time.Unix(1792043595, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway22_failed_callbackSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x91\x4d\x6f\x83\x30\x0c\x86\xef\xf9\x15\x3e\x06\x6d\x1c\x98\xd4\x69\x52\xd5\x03\x85\x74\x43\xa3\xa1\xa3\xc9\xa1\x27\xc8\x20\xdb\xd0\xf8\x52\x30\xfb\xf8\xf7\x03\xa4\xaa\xa3\xfb\xb8\xd9\x7e\x9f\xd7\x71\x6c\xdb\x86\x8b\xaa\x78\x36\x0a\x35\xc8\x96\x78\x31\x73\x05\x03\xe1\xae\x43\x06\xe9\x46\x15\xa5\xce\x3d\x55\x96\x8f\x2a\x7b\x4d\x81\x12\x80\xb4\xc8\x53\x28\x6a\xa4\x8e\x63\x01\x8f\x04\x70\x19\x86\xe0\x4a\x11\x25\x01\x1f\xec\x5b\xc6\xc5\xe5\xc8\x35\xad\x1e\xba\x16\x4d\x9d\x8c\x8e\x37\x65\xb2\x17\x65\xe8\xd5\x62\x71\xb2\x4d\x5c\xab\x3e\xcb\x46\x0d\x08\xea\x0f\x9c\x4b\xa5\xea\x30\xd1\xc6\x34\xe6\x37\x55\x21\xea\xaa\xc5\xee\xe7\x38\x93\xdc\xa1\xc2\xbe\x3b\xbd\xec\x5c\x9f\x01\x99\xd1\xc3\xaf\xf3\x44\x61\x0a\xf9\x10\x61\x51\xe9\x39\xd1\xb7\xf9\xff\xc4\x2e\x0e\xb6\x6e\x7c\x80\x7b\x76\x00\x3a\x6e\xc6\x1a\xab\x92\x07\x0f\x92\x4d\xc5\xb3\x2d\xd0\x79\x3e\xd1\x13\x76\x1c\x96\x1e\x23\x8b\x58\xc0\xf8\x6d\xc0\xd9\x2a\xa8\xeb\xc6\x5f\x83\xcf\x36\xae\x0c\x05\x78\x77\x6e\xbc\x67\x62\xd5\xe3\xd3\xcd\x92\x10\xfb\xdb\x01\xfd\xe6\xbd\x26\x7e\x1c\xed\xfe\x38\xe0\x92\x7c\x01\x12\x51\x20\xae\xef\x01\x00\x00")
+
+func migrations_gateway22_failed_callbackSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway22_failed_callbackSql,
+		"migrations_gateway/22_failed_callback.sql",
+	)
+}
+
+func migrations_gateway22_failed_callbackSql() (*asset, error) {
+	bytes, err := migrations_gateway22_failed_callbackSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/22_failed_callback.sql", size: 495, mode: os.FileMode(420), modTime: time.Unix(1792043806, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway23_offline_transactionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x92\x4d\x4f\xc2\x40\x10\x86\xef\xfb\x2b\xe6\xd8\x46\x39\x60\x84\x98\x10\x0f\x85\xae\xda\x58\xb6\x58\xb7\x07\x4e\xed\xda\x2e\xb8\x11\x76\x71\x76\x0b\xfc\x7c\x29\x9a\x94\xa2\xc1\xdb\x7c\x3c\xf3\xce\x64\x66\x7a\x3d\xb8\x5a\xab\x25\x0a\x27\x21\xdb\x90\x49\x4a\x03\x4e\x81\x07\xe3\x98\x42\x91\x2c\x16\x2b\xa5\x25\x47\xa1\xad\x28\x9d\x32\xba\x00\x8f\x00\x14\xaa\x2a\x40\x69\xe7\xf5\xfb\x3e\xb0\x84\x03\xcb\xe2\x18\x82\x8c\x27\x79\xc4\x0e\x12\x53\xca\xf8\x75\xc3\x99\x6f\x81\xbc\xe1\xb7\x02\xcb\x77\x81\xde\xcd\x60\xd0\x16\x1d\x29\xd7\xea\x77\xc8\xe1\xed\x19\xa8\xa5\xdb\x19\xfc\xb8\xa4\x65\x4d\x8d\xa5\x6c\x89\xc1\xf0\x0c\x90\x7a\x2b\x57\x66\x23\xf3\x7d\x85\x05\x38\xb9\x77\xdd\x3c\xca\xcf\x5a\xa1\xac\x72\xab\x96\x5a\xa2\xfd\x8b\xb1\x4e\xb8\xda\xb6\x4d\xfa\xe7\x4d\x56\xc2\xba\x5c\x22\x1a\xbc\x34\x6b\x89\xf2\xb0\xf6\x2a\x17\xae\x80\xea\x60\x39\xb5\x96\x5d\xa2\xde\x54\xff\x10\xb6\x7e\x5b\x2b\xf7\x9b\xf9\xc9\xcf\xd2\x68\x1a\xa4\x73\x78\xa6\x73\xf0\x9a\xb3\xf9\x4d\x34\x63\xd1\x4b\x46\x8f\xc1\xce\x89\xbc\x53\xcf\x27\x3e\x50\xf6\x18\x31\x7a\x1f\x69\x6d\xc2\x31\x84\xf4\x21\xc8\x62\x0e\x93\xa7\x20\x7d\xa5\xfc\xbe\x76\x8b\xbb\x11\x21\xbd\x93\x17\x0a\xcd\x4e\x93\x30\x4d\x66\x17\x5e\x68\x44\xbe\x00\x1c\x3c\xc7\xc3\x75\x02\x00\x00")
+
+func migrations_gateway23_offline_transactionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway23_offline_transactionSql,
+		"migrations_gateway/23_offline_transaction.sql",
+	)
+}
+
+func migrations_gateway23_offline_transactionSql() (*asset, error) {
+	bytes, err := migrations_gateway23_offline_transactionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/23_offline_transaction.sql", size: 629, mode: os.FileMode(420), modTime: time.Unix(1792044252, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway24_quoteSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x92\x31\x6f\x83\x30\x10\x85\x77\xff\x8a\x1b\x41\x2d\x03\x69\x89\x2a\x45\x19\x48\x70\x5b\x54\x62\x12\x62\x86\x4c\x60\x81\xdb\xa0\x0a\x43\x8d\x69\xd2\x7f\x5f\xc8\x62\x40\x8d\x9a\xed\xf4\xee\x7b\xf7\x86\x77\x96\x05\x77\x65\xf1\x21\x99\xe2\x10\xd7\x68\x1d\x61\x97\x62\xa0\xee\x2a\xc0\x90\xee\xda\x4a\xf1\x14\x0c\x04\x90\x16\x79\x0a\x85\x50\x86\x6d\x9b\x40\x42\x0a\x24\x0e\x02\x70\x63\x1a\x26\x3e\xe9\x5c\x1b\x4c\xe8\x7d\xcf\x7d\xf5\x9e\xa4\xa7\xbf\x99\xcc\x8e\x4c\x1a\xf3\x47\xed\xb8\x20\x82\xab\x53\x25\x3f\x35\x31\x73\x9c\x09\xa2\x7e\x6a\xae\xf7\xf6\x7c\xb2\x6e\xb8\xc8\x13\xd6\x34\x5c\x25\x59\x95\x0f\xc9\xd9\x75\xb2\x68\x9a\x96\x4b\xcd\x3a\x7f\x5f\x2d\xab\x56\x28\x4d\x3d\x4c\x2f\xde\x14\x7b\x63\xe2\xbf\x61\x35\x53\xc7\x14\x14\x3f\xab\xb1\xce\xcf\x75\x21\x79\x93\xb0\xce\x9c\x77\xdd\xa9\xa2\xe4\x63\x22\x93\xbc\xd3\xf3\xeb\xc4\x36\xf2\x37\x6e\x74\x80\x37\x7c\x00\xa3\xaf\xd7\xec\xd5\x98\xf8\xbb\x18\x5f\xc4\x41\x95\x86\x9e\x4d\x64\x02\x26\x2f\x3e\xc1\x4b\x5f\x88\xca\x5b\x81\x87\x9f\xdd\x38\xa0\xb0\x7e\x75\xa3\x3d\xa6\xcb\x56\xbd\x3f\x2d\x10\xb2\x06\x9f\xe5\x55\x27\x81\xbc\x28\xdc\x8e\x3f\x6b\x81\x7e\x01\x10\xb8\x37\xf2\x7f\x02\x00\x00")
+
+func migrations_gateway24_quoteSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway24_quoteSql,
+		"migrations_gateway/24_quote.sql",
+	)
+}
+
+func migrations_gateway24_quoteSql() (*asset, error) {
+	bytes, err := migrations_gateway24_quoteSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/24_quote.sql", size: 639, mode: os.FileMode(420), modTime: time.Unix(1792044769, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway25_fiat_valueSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\x4d\x4f\x83\x40\x10\x86\xef\xfb\x2b\xe6\x08\xb1\x1c\xa8\xd2\x98\x34\x3d\x6c\xcb\x56\x89\x74\xa9\xb8\x98\xf4\x04\x9b\x65\xd1\x4d\x64\x31\xcb\xa2\xf1\xdf\x0b\xbd\x80\xd4\xf4\x36\x99\x79\xe6\x23\xef\x3b\x9e\x07\x37\xb5\x7a\x33\xdc\x4a\xc8\x3e\xd1\x2e\x25\x98\x11\x60\x78\x1b\x13\x28\xf6\x8a\xdb\x57\xfe\xd1\xc9\x02\x1c\x04\x50\xa8\xb2\x00\xa5\xad\xe3\xfb\x2e\xd0\x84\x01\xcd\xe2\x18\x70\xc6\x92\x3c\xa2\x7d\xe7\x81\x50\xb6\x18\xb8\x52\x19\x29\xac\x6a\x74\x01\x5f\xdc\x88\x77\x6e\x1c\x7f\x35\xb6\x9c\x19\x23\x2b\x69\xa4\x16\x72\x64\x96\x41\x30\x83\xac\xd4\x5c\xdb\x6b\x04\x6f\x5b\x69\x73\xd1\x94\x93\x39\xfe\xf2\x5f\x48\xb5\x6d\x27\xcd\x88\x05\xf3\x93\x78\xdd\x74\xd3\x6d\xb7\xf3\x39\xa2\x33\xc3\xc9\x3f\x57\x56\x0d\x42\x8e\xe5\xd5\xdd\xac\x5c\xf5\x8a\xe6\xf3\x3d\x17\x94\x30\xb2\x1f\x53\xe6\xbc\x87\xca\x3e\xb2\xaa\x96\x7f\x88\x63\x1a\x1d\x70\x7a\x82\x27\x72\x02\x67\xb0\xc5\x1d\xb2\x19\x8d\x9e\x33\x72\x4e\x8e\x16\xe4\x13\xa1\x9d\x89\x33\x8b\xa9\x05\x2e\x72\x81\xd0\x87\x88\x92\x4d\xa4\x75\x13\x6e\x21\x24\x7b\x9c\xc5\x0c\x76\x8f\x38\x7d\x21\x6c\xd3\xd9\xea\x7e\x8d\x90\x37\x79\x97\xb0\xf9\xd6\x28\x4c\x93\xe3\xe5\xbb\xac\xd1\x2f\x76\x19\xf9\xe8\x58\x02\x00\x00")
+
+func migrations_gateway25_fiat_valueSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway25_fiat_valueSql,
+		"migrations_gateway/25_fiat_value.sql",
+	)
+}
+
+func migrations_gateway25_fiat_valueSql() (*asset, error) {
+	bytes, err := migrations_gateway25_fiat_valueSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/25_fiat_value.sql", size: 600, mode: os.FileMode(420), modTime: time.Unix(1792045086, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway26_webhookSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x91\x51\x6f\x82\x30\x14\x85\xdf\xfb\x2b\xee\x9b\x90\x8d\x64\xba\x99\x98\x18\x1f\x50\xba\x8d\x0c\x8b\x63\x6d\x16\x9f\xa0\x4a\x37\xc9\xa0\x90\x5a\x71\xfe\x7b\x0b\x4b\x26\x23\x71\x6f\xed\x3d\xdf\xbd\xed\x39\xd7\x71\xe0\xa6\xc8\x3e\x15\xd7\x02\x58\x85\x16\x11\x76\x29\x06\xea\xce\x03\x0c\xc9\xbb\xd8\xec\xca\xf2\x2b\x01\x0b\x01\x24\x59\x9a\x40\x26\xb5\x35\x1c\xda\x40\x42\x0a\x84\x05\x01\xb8\x8c\x86\xb1\x4f\x4c\xdf\x12\x13\x7a\xdb\x70\xc7\x9f\xae\xb8\xe1\x6b\xae\xb6\x3b\xae\xac\xfb\xd1\xa5\xa7\x85\x0e\x2a\xbf\xa8\xa3\xbb\x87\x49\x4f\x17\xb5\x90\x7a\xdf\x41\xc6\xe3\x1e\xb1\x17\x5b\x25\xf4\x15\x02\x3c\xfc\xe8\xb2\x80\xc2\x60\xd0\xc2\x05\xff\x8e\xb9\xd6\xa2\xa8\x9a\xa1\x7d\x17\x2d\x62\x86\xa9\x53\x6c\x24\xa1\x6a\x9e\x5f\x81\x84\xe4\x9b\x5c\x18\x63\x3a\x93\xa7\x96\xe8\x01\xe6\x4f\x26\xca\xd4\x3c\x96\x40\x6a\x4e\x3a\x2b\x44\xcf\x79\x95\xfe\x4f\xac\x22\x7f\xe9\x46\x6b\x78\xc1\x6b\xb0\x9a\xd4\xed\xa6\xca\x88\xff\xca\x70\x5b\xfc\x93\xb0\xd5\xbd\xd9\xc8\x06\x4c\x9e\x7c\x82\x67\xbe\x94\xa5\x37\xff\xcd\x61\xf1\xec\x46\x6f\x98\xce\x0e\xfa\x63\x32\x45\xc8\xe9\xac\xdd\x2b\x8f\x12\x79\x51\xb8\xea\xaf\x7d\x8a\xce\x3a\xd2\x9a\x90\x1e\x02\x00\x00")
+
+func migrations_gateway26_webhookSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway26_webhookSql,
+		"migrations_gateway/26_webhook.sql",
+	)
+}
+
+func migrations_gateway26_webhookSql() (*asset, error) {
+	bytes, err := migrations_gateway26_webhookSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/26_webhook.sql", size: 542, mode: os.FileMode(420), modTime: time.Unix(1792045671, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway27_webhook_formatSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x8e\xb1\x0e\x82\x30\x14\x45\xf7\x7e\xc5\xdb\xd0\x48\x07\x16\x17\xa6\x9a\x96\xa9\x01\x43\xda\x38\xda\x82\xc5\xa2\xb6\x35\x85\xe0\xef\x1b\x23\x21\xc6\xc4\x38\xde\xe1\x9e\x73\x30\x86\x8d\xeb\xcf\x51\x8f\x06\xe4\x1d\x11\x2e\x58\x0d\x82\xec\x38\x03\x75\x30\x8d\x0d\xe1\xaa\x80\x50\x0a\xaa\x0b\xd1\xe9\x51\xc1\xa4\x63\x6b\x75\x5c\x65\xdb\x35\x94\x95\x80\x52\x72\x0e\x94\x15\x44\x72\x01\xc9\x65\x08\x3e\x01\x52\xbc\x30\xca\x78\xdd\xdc\xcc\x49\xa5\x6f\xc2\xd0\x5a\xe3\xf4\x71\x32\x71\xe8\x83\xff\x47\xca\x16\xcc\x6c\xce\x11\xc2\x1f\xb5\x34\x3c\xfc\x8f\x5e\x5a\x57\xfb\xe5\x96\xce\xf3\xcb\x9e\xa3\x27\x3b\x1a\xa7\x7a\xfb\x00\x00\x00")
+
+func migrations_gateway27_webhook_formatSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway27_webhook_formatSql,
+		"migrations_gateway/27_webhook_format.sql",
+	)
+}
+
+func migrations_gateway27_webhook_formatSql() (*asset, error) {
+	bytes, err := migrations_gateway27_webhook_formatSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/27_webhook_format.sql", size: 251, mode: os.FileMode(420), modTime: time.Unix(1792046020, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway28_memo_referenceSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x6d\x90\xc1\x4e\xc3\x30\x10\x44\xef\xfe\x8a\x39\x26\x82\x1c\x8a\x28\x42\xaa\x7a\x70\x9b\x05\x2c\x12\xa7\x18\xfb\xd0\x53\x1d\x25\x2e\xe4\x10\x07\x59\x06\x7e\x9f\x24\x20\x15\x2a\x4e\xbb\x9a\x7d\xb3\x1a\x4d\x96\xe1\xa2\xef\x5e\x42\x1d\x1d\xcc\x1b\xdb\x2a\xe2\x9a\xa0\xf9\xa6\x20\xd8\xd2\xf5\x83\x72\x47\x17\x9c\x6f\x9c\x45\xc2\x00\xdb\xb5\x16\x9d\x8f\xc9\x62\x91\x42\x56\x1a\xd2\x14\x05\xb8\xd1\xd5\x41\xc8\xd1\x5d\x92\xd4\x97\x13\xd7\x8f\x5e\x8b\xe6\xb5\x0e\xc9\xcd\xf5\x09\x9d\x6f\xe1\xf4\xf3\xa3\x0e\x33\x73\xb5\x5c\x9e\x41\x4d\x70\x63\xa8\xf6\x50\x47\x8b\x76\xdc\x62\xd7\xbb\x3f\xc4\x4e\x89\x92\xab\x3d\x1e\x69\x8f\x64\xca\x95\x4e\xaa\x91\xe2\xc9\xd0\x2c\xfe\x64\x48\xbe\x67\xca\x52\x90\xbc\x17\x92\xd6\xc2\xfb\x21\xdf\x20\xa7\x3b\x6e\x0a\x8d\xed\x03\x57\xcf\xa4\xd7\xef\xf1\x78\xbb\x62\x2c\xfb\x55\x49\x3e\x7c\x7a\x96\xab\x6a\xf7\x7f\x25\x2b\xf6\x05\x2c\x13\xb1\x28\x40\x01\x00\x00")
+
+func migrations_gateway28_memo_referenceSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway28_memo_referenceSql,
+		"migrations_gateway/28_memo_reference.sql",
+	)
+}
+
+func migrations_gateway28_memo_referenceSql() (*asset, error) {
+	bytes, err := migrations_gateway28_memo_referenceSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/28_memo_reference.sql", size: 320, mode: os.FileMode(420), modTime: time.Unix(1792046436, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway29_sent_transaction_metadataSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\xcd\x31\x0e\xc2\x30\x0c\x40\xd1\x3d\xa7\xf0\x8e\x72\x82\x4e\x41\x4e\xa7\x08\x50\x49\xe6\xda\x50\x0b\x55\xa2\x09\x24\x46\x70\x7c\x04\x2c\xdd\x18\xff\xf2\x9f\xb5\xb0\x59\xe6\x4b\x65\x15\x48\x37\xe3\x42\xf4\x03\x44\xb7\x0d\x1e\xe8\x28\x59\x63\xe5\xdc\xf8\xac\x73\xc9\x04\x0e\x11\x68\x11\xe5\x89\x95\x09\x54\x5e\x0a\xbb\x14\x02\xa0\xef\x5d\x0a\xf1\x17\xae\xff\x3c\xe8\xc4\xd3\xd8\xe4\x3e\x56\x69\xe5\xfa\xf8\x0e\x3a\x63\xec\xca\xc3\xf2\xcc\x7f\x44\x1c\xf6\x87\x15\xd9\x99\x37\xa5\xd8\x26\x52\xb0\x00\x00\x00")
+
+func migrations_gateway29_sent_transaction_metadataSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway29_sent_transaction_metadataSql,
+		"migrations_gateway/29_sent_transaction_metadata.sql",
+	)
+}
+
+func migrations_gateway29_sent_transaction_metadataSql() (*asset, error) {
+	bytes, err := migrations_gateway29_sent_transaction_metadataSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/29_sent_transaction_metadata.sql", size: 176, mode: os.FileMode(420), modTime: time.Unix(1792046656, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway30_leader_leaseSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x90\x41\x4f\x02\x31\x10\x85\xef\xfd\x15\x73\xec\x46\xf7\x00\x11\x63\x42\x38\x14\xb6\x6a\x63\xe9\x62\x6d\x0f\x9c\x68\xc3\x56\x69\xe2\x76\xb1\x14\xf5\xe7\xdb\x45\x12\xd4\xc8\x65\x26\x79\xf3\xbd\xc9\xcb\x2b\x4b\xb8\x68\xfd\x4b\xb4\xc9\x81\xde\xa2\x99\xa4\x44\x51\x50\x64\xca\x29\x18\xee\x6c\xe3\x62\x9e\x3b\x67\x00\x23\x00\xe3\x1b\x03\x3e\x24\x3c\x18\x14\x20\x6a\x05\x42\x73\x0e\x44\xab\x7a\xc5\x44\xf6\xce\xa9\x50\x97\x3d\x17\x6c\x9b\x2d\xef\x36\xae\x37\x36\xe2\xeb\xab\x13\x7d\x38\x6f\xba\xd7\xfc\xf8\x04\x0c\x47\xa3\x3f\x84\x5d\xbf\xed\x7d\x74\xcd\xca\x26\x03\x4d\x4e\x97\x7c\xeb\x7e\x23\xee\x73\x9b\x89\xdd\x79\x62\x21\xd9\x9c\xc8\x25\x3c\xd0\x25\xe0\x3e\x7a\xd1\xab\x5a\xb0\x47\x4d\x0f\xe2\x31\x26\xfe\xde\x05\x2a\x80\x8a\x3b\x26\xe8\x84\x85\xd0\x55\x53\xa8\xe8\x2d\xd1\x5c\xc1\xec\x9e\xc8\x27\xaa\x26\xfb\xf4\x7c\x33\x46\xa8\xfc\xd1\x59\xd5\x7d\x04\x54\xc9\x7a\xf1\x5f\x67\x63\xf4\x05\x93\xfe\x15\xac\x5f\x01\x00\x00")
+
+func migrations_gateway30_leader_leaseSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway30_leader_leaseSql,
+		"migrations_gateway/30_leader_lease.sql",
+	)
+}
+
+func migrations_gateway30_leader_leaseSql() (*asset, error) {
+	bytes, err := migrations_gateway30_leader_leaseSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/30_leader_lease.sql", size: 351, mode: os.FileMode(420), modTime: time.Unix(1792048227, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway31_sep24_transactionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\x94\xcb\x6e\xc2\x30\x10\x45\xf7\xf9\x0a\xef\x30\x2a\x48\x90\x02\xaa\x84\x58\x04\x70\xdb\xa8\x10\x68\x48\x16\xac\x8c\x1b\x0c\x58\x4d\x6c\x64\x3b\x7d\xfc\x7d\x13\x28\x24\x04\x42\xe9\xce\x9a\x39\xd7\x63\xdd\x99\x71\xbd\x0e\xee\x22\xb6\x96\x44\x53\xe0\x6f\x8d\x81\x8b\x2c\x0f\x01\xcf\xea\x8f\x10\x58\xcc\xd0\xd4\x6c\x79\x92\x70\x45\x02\xcd\x04\x5f\x00\x68\x00\xb0\x60\xcb\x05\x60\x5c\xc3\x66\xb3\x0a\x9c\x89\x07\x1c\x7f\x34\x02\x96\xef\x4d\xb0\xed\x24\x17\x8c\x91\xe3\xd5\x52\x4e\x67\x4a\x9c\x6a\x3e\x88\x0c\x36\x44\xc2\x4e\x2b\xd3\xed\xc0\x77\xc6\x73\xe9\x66\xa3\x90\x56\x9a\xe8\x58\x65\xc0\xbd\x59\x00\x48\x10\x88\x98\xeb\x8c\x68\x77\x8a\x84\x52\x54\xe3\x40\x2c\x69\xae\x8e\x79\x11\x62\x4a\xc5\x54\x5e\xbe\x0b\x0c\xd1\xa3\xe5\x8f\x3c\x50\xa9\xec\x15\x51\x5a\x17\xb3\xc4\x99\x37\xb6\x4e\x3d\x31\x1b\x17\xe8\x46\x1e\x16\xb1\xfe\x07\xbd\xa2\xf4\x26\x9a\x07\x1b\x21\xf1\x55\x23\x8a\x8f\x8f\x68\x24\xb0\xfe\xde\xe6\x2c\xf9\x83\xbe\xdc\xc2\x33\x52\x48\x9a\x58\xb2\x12\x38\x96\x61\x26\x31\xdb\xed\x72\x8d\xd2\x34\x0c\x89\xc4\xb7\x8c\x4c\x51\x4b\xbf\x34\x95\x9c\x84\xa5\xe2\xab\x95\x23\xaa\x14\x59\xd3\x9b\xdf\x49\xa4\xa6\x4b\x4c\x12\x8f\x97\xc9\xce\x68\x16\xd1\xd3\x21\x8a\xb7\x69\xfc\x1a\x11\x88\x68\x1b\xd2\x33\xe6\x50\xe9\xc0\x4d\x5d\x7b\x6c\xb9\x73\xf0\x82\xe6\x00\xa6\x3b\x57\x4d\xa3\xbe\x63\xbf\xfa\x68\x17\x3c\xdb\x2f\x58\x8c\xec\x14\x3b\x74\xdf\x3d\x98\xeb\x79\xed\x37\x58\x3b\xee\x57\x46\x1f\xc7\x08\x1e\x8f\xb5\x93\x1d\xca\xd0\xb2\xc6\xc1\xb2\x4c\x26\x2d\xed\x1b\x2c\x4d\x55\x8d\x2a\x40\xce\x93\xed\xa0\x9e\xcd\xb9\x18\xf6\x8f\xae\x0d\x9e\x2d\x77\x86\xbc\x5e\xac\x57\x0f\x5d\xc3\xa8\xe7\xbe\xb5\xa1\xf8\xe4\xc6\xd0\x9d\x4c\x4b\xbf\xb5\xae\xf1\x03\x45\xcd\x16\x56\x07\x05\x00\x00")
+
+func migrations_gateway31_sep24_transactionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway31_sep24_transactionSql,
+		"migrations_gateway/31_sep24_transaction.sql",
+	)
+}
+
+func migrations_gateway31_sep24_transactionSql() (*asset, error) {
+	bytes, err := migrations_gateway31_sep24_transactionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/31_sep24_transaction.sql", size: 1287, mode: os.FileMode(420), modTime: time.Unix(1792049628, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway32_held_payment_seedSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\xce\xb1\x0a\xc2\x30\x14\x46\xe1\x3d\x4f\xf1\xef\x12\x70\xcf\x94\x92\x88\x43\x68\x4b\x49\x66\x13\xcc\x45\x0a\x36\x86\xf6\x82\xf6\xed\x1d\x84\x22\x08\xf6\x01\xce\xc7\x91\x12\x87\x69\xbc\xcd\x89\x09\xa1\x0a\xed\xbc\x1d\xe0\x75\xe3\x2c\xe2\x99\xee\xb9\x4f\xeb\x44\x85\x23\xb4\x31\x88\x54\xae\xf3\x5a\x99\xf2\x65\x21\xca\x11\x4c\x2f\x86\xb1\x27\x1d\x9c\x47\x1b\x9c\x53\x7b\x42\x62\xa6\xa9\xf2\x12\x31\x16\x46\xdb\x7d\xb2\xcd\x38\x2a\x21\xe4\xd7\x92\x79\x3c\xcb\x1f\xd2\x0c\x5d\xff\x73\xa5\x76\x83\x6d\x42\x89\x37\x2d\x7d\xc4\x1b\x00\x01\x00\x00")
+
+func migrations_gateway32_held_payment_seedSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway32_held_payment_seedSql,
+		"migrations_gateway/32_held_payment_seed.sql",
+	)
+}
+
+func migrations_gateway32_held_payment_seedSql() (*asset, error) {
+	bytes, err := migrations_gateway32_held_payment_seedSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/32_held_payment_seed.sql", size: 256, mode: os.FileMode(420), modTime: time.Unix(1792051914, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway33_sent_transaction_conflictSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\x56\x4d\x73\xda\x30\x10\xbd\xfb\x57\xec\x2d\x78\x2a\x3a\x25\xd3\x64\x3a\x93\x72\x70\xb0\xd2\x78\x4a\x0c\xb5\xcd\x34\x39\x81\xb0\x05\x78\x06\x2c\x62\xcb\x24\xf9\xf7\x5d\xf1\x21\xdb\x02\x92\xe9\x31\x27\xa4\xf5\xdb\xd5\x7b\xbb\x4f\x1a\xda\x6d\xf8\xb2\x4a\xe7\x39\x93\x1c\x46\x6b\xab\x17\x50\x27\xa2\x10\x39\xb7\x7d\x0a\x93\x90\x67\x32\xca\x59\x56\xb0\x58\xa6\x22\xeb\x89\x6c\xb6\x4c\x63\x39\x81\x96\x05\x30\x49\x93\x09\xa4\x99\x6c\x75\x3a\x36\xf8\x83\x08\xfc\x51\xbf\x4f\xd4\x87\x35\x7b\x5b\x61\xe6\x58\x01\x36\x2c\x8f\x17\x2c\x6f\x5d\x5e\x5d\x19\xa8\x9c\xcf\xb1\x66\x85\xb8\xfe\x5e\x01\xc0\xa5\x77\xce\xa8\x1f\xc1\xc5\xc5\x16\x2b\x2b\x12\x8d\xaa\xf5\x9c\x2d\xb0\x90\x4c\x96\x45\x05\xe8\x7c\x33\x01\xa2\xcc\x63\x5e\x01\xae\xae\x4d\x40\x39\x5d\xa5\x52\xf2\x64\xcc\x50\x68\x82\x7d\x91\xe9\x8a\x9b\x98\x38\xe6\x3c\x31\x31\x07\xd2\x1a\xb7\xe4\xc9\x9c\xe7\x13\x98\xa6\x73\xd5\xa8\x4b\x24\x73\x84\xe1\xd9\x86\x2f\xc5\x9a\x8f\x5f\x13\x44\x4a\xfe\x2a\xcd\x36\x15\xe5\x52\xee\xbe\x36\x9a\x79\x54\x69\xca\x92\x71\xc1\x9f\xc7\x98\x21\x96\xa5\x6c\x34\xb7\xa3\x64\xd6\x1b\xab\xb3\x56\x5c\x32\x54\xc0\x0e\x67\x9f\x02\x0d\x03\xef\xc1\x09\x9e\xe0\x37\x7d\x82\x96\x9a\xbb\xad\xa2\x6a\xd7\x98\x75\xab\xbe\xb3\x2d\x1b\xa8\xff\xcb\xf3\x69\xd7\xcb\x32\xe1\xde\xea\xaa\xbd\x7b\x27\x08\x69\xd4\x2d\xe5\xec\xc7\x8d\x65\xb5\xdb\x30\xdc\xa5\x81\xe7\x42\x5a\x40\x99\xa5\xcf\x25\x07\x16\xe7\xa2\x28\x60\x67\x93\x02\xd8\x9c\xa5\x19\x81\x9a\x13\x0a\x10\x33\x10\x72\xc1\xf3\x03\x48\xd5\x7a\x49\xe5\x02\x30\x08\x05\xc3\x91\xac\xab\xca\x2c\xe7\xb0\x12\x1b\x9e\x80\x14\x70\xc6\xd9\x96\xe7\x87\x34\x88\xc0\xf3\xa3\xc1\x7b\xf6\x57\x3d\x20\x0d\xf1\x44\x1b\x9a\x1c\xd9\x95\x68\x5f\x12\x6d\x40\x62\x38\x8d\x18\xae\x22\xda\x3d\xc4\xf0\x08\x69\x78\x82\x9c\x9c\x3b\xa9\xcd\xd5\xc6\x51\x85\xb4\x4f\x7b\x11\xc8\xaf\x3b\xe2\xf8\xdb\xa0\x8e\x7b\x4d\x1e\xd7\x47\xf4\x31\xa6\x05\xa8\xf5\x41\x82\x5a\x37\x45\x6c\x23\x0d\x19\x18\xd1\x42\x70\x6d\x48\xd9\x9e\x5c\x13\x83\xfb\x93\x72\x30\xae\x05\xa1\x9e\xbb\x60\xf0\x70\x34\x1f\x74\x30\x7e\xfa\x7b\x4f\x03\x0a\xf4\xd1\x0b\xa3\x10\x5a\x7b\xe1\x9d\x73\x19\x62\x8f\x17\x8d\x8e\x40\xd7\x68\x11\x38\xbe\xab\x30\x6a\xf9\x73\xdf\x46\x1b\xbd\xeb\x62\x79\x7c\x2e\xcf\x14\xdf\x95\xde\x26\x79\xbe\xe6\xb2\xdd\x9f\xcc\xd0\x06\x53\xa5\x9d\x7e\x44\x83\x33\x0f\xf1\x04\xdc\x60\x30\xc4\xa2\x2e\x7d\x3c\xf8\x6e\xdc\x98\xa8\xe3\xba\x30\xf2\xbd\x3f\x23\x6a\xdc\xca\xdd\x85\xd3\x4f\xbe\x2b\x5e\xb2\xff\x38\xea\xdc\x19\x27\x38\xe0\xb9\xd5\x85\x30\x18\xbc\x77\xcb\x3e\xef\xed\xfa\x4c\xb4\x3f\x30\xa0\xb2\xb6\x1a\xfb\x07\xff\x03\x6e\xac\x7f\xd8\x0a\x15\x6c\x3f\x08\x00\x00")
+
+func migrations_gateway33_sent_transaction_conflictSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway33_sent_transaction_conflictSql,
+		"migrations_gateway/33_sent_transaction_conflict.sql",
+	)
+}
+
+func migrations_gateway33_sent_transaction_conflictSql() (*asset, error) {
+	bytes, err := migrations_gateway33_sent_transaction_conflictSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/33_sent_transaction_conflict.sql", size: 2111, mode: os.FileMode(420), modTime: time.Unix(1792052278, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway34_sent_transaction_original_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x4b\x50\x70\x74\x71\x51\x48\xc8\x2f\xca\x4c\xcf\xcc\x4b\xcc\x89\xcf\x4c\x49\x50\xc8\xcc\x2b\xd1\x30\x34\xd4\x54\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x81\x70\x1c\xdd\x40\x26\x25\x14\x24\x56\xe6\x02\x8d\x01\x29\xb6\xc6\x6b\xba\x73\x7e\x5e\x5a\x4e\x66\x72\x09\xe5\xb6\x70\xe9\x22\xf9\xc9\x25\xbf\x3c\x8f\x80\xaf\x5c\x82\xfc\x03\x50\x2d\x24\xd6\xa5\xd8\x74\x02\x00\x8b\x7e\x4e\xb7\x51\x01\x00\x00")
+
+func migrations_gateway34_sent_transaction_original_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway34_sent_transaction_original_idSql,
+		"migrations_gateway/34_sent_transaction_original_id.sql",
+	)
+}
+
+func migrations_gateway34_sent_transaction_original_idSql() (*asset, error) {
+	bytes, err := migrations_gateway34_sent_transaction_original_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/34_sent_transaction_original_id.sql", size: 337, mode: os.FileMode(420), modTime: time.Unix(1792052419, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway35_api_key_tenantSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x70\x0c\xf0\xf4\x4e\xad\x4c\x50\x70\x74\x71\x51\x48\x28\x49\xcd\x4b\xcc\x2b\x49\x50\x28\x4b\x2c\x4a\xce\x48\x2c\xd2\x30\x33\xd1\x54\xf0\xf3\x0f\x51\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\x57\x57\x70\x74\x03\x99\x90\x50\x9c\x5f\x5a\x94\x9c\x5a\x9c\x60\xcd\xc5\xa5\x8b\x64\x85\x4b\x7e\x79\x1e\x76\x4b\x5c\x82\xfc\x03\xe0\xb6\x58\x73\x01\x00\x47\xf7\xb4\x57\x98\x00\x00\x00")
+
+func migrations_gateway35_api_key_tenantSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway35_api_key_tenantSql,
+		"migrations_gateway/35_api_key_tenant.sql",
+	)
+}
+
+func migrations_gateway35_api_key_tenantSql() (*asset, error) {
+	bytes, err := migrations_gateway35_api_key_tenantSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/35_api_key_tenant.sql", size: 152, mode: os.FileMode(420), modTime: time.Unix(1792053129, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x94\x4d\x6f\xda\x30\x18\xc7\xef\xf9\x14\xcf\x31\x68\x70\x60\x1a\x68\x12\xe2\x10\x88\xd9\xa2\x85\xc0\x32\xfb\xc0\xc9\xf6\x88\x33\xac\x11\x07\x39\x4e\xa1\xfd\xf4\x55\x68\x9b\x37\xde\x8a\xda\x5b\xe4\xfc\xff\xf6\xef\xf9\xc9\x72\xaf\x07\x5f\x12\xf9\x4f\x73\x23\x80\xec\xac\x69\x88\x1c\x8c\x00\x3b\x13\x1f\x01\x73\x72\xb3\x49\xb5\x7c\x12\x11\xd6\x5c\x65\x7c\x6d\x64\xaa\x18\xd8\x16\x00\x93\x11\x03\xa9\x8c\xdd\xef\x77\x20\x58\x60\x08\x88\xef\x83\x43\xf0\x82\x7a\xc1\x34\x44\x73\x14\xe0\x6e\x91\x33\x55\x93\x16\x9d\xf5\x86\x6b\x7b\xf8\xad\x2a\x1d\x53\x89\x48\x52\x06\x0f\x5c\x9f\xff\x5d\xdf\xe4\x10\x69\x06\x46\x1c\x4c\x33\xc2\x4b\x56\xca\x0d\x83\x88\x1b\x61\x64\x22\x9a\xa1\x88\x1b\x7e\xa6\xbc\x0c\xbd\xb9\x13\xae\xe0\x17\x5a\x81\x5d\x4c\xd6\xb1\x3a\x80\x82\x1f\x5e\x80\xc6\x9e\x52\xa9\x3b\x01\x17\xcd\x1c\xe2\x63\x98\xfe\x74\xc2\x3f\x08\x8f\x73\x13\x7f\x1f\x59\x6d\x5f\xdb\x6d\xba\x17\xd1\xcc\xbb\xd3\x91\xe2\x89\xa8\xa6\xff\x3a\x18\xb4\xc6\x8f\xd2\x84\x4b\x75\x2d\xb1\xcb\xff\x6e\xe5\x9a\xfe\x17\x8f\xaf\x86\x07\xc3\x56\x82\xbf\xb0\x5d\x96\x73\x2a\xa1\x58\x25\x81\xf7\x9b\xa0\xa3\x99\x12\xc3\x7e\xfb\x3a\x49\xd4\x31\xec\x3a\xd4\xc7\x84\x92\x4c\xe8\x3b\x95\xc6\x92\xde\xb2\x1a\x4b\x7a\x5b\x6c\x2c\xe9\x6d\xb7\x79\x26\xf4\xf1\x72\x5f\xde\xe7\x13\xf4\x37\x50\x68\x79\xa6\xdd\x62\xec\x56\x3c\xef\xb6\x5e\x7f\x05\xdc\x74\xaf\x2c\x37\x5c\x2c\xaf\xbf\x02\xa3\x66\xa6\xbc\xf9\x67\xd7\x49\x26\x34\x1b\x59\xcf\x03\x00\xb0\xd9\x8a\xda\x6d\x04\x00\x00")
 
 func migrations_compliance01_initSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -146,7 +823,107 @@ func migrations_compliance01_initSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations_compliance/01_init.sql", size: 1133, mode: os.FileMode(420), modTime: time.Unix(1461004393, 0)}
+	info := bindataFileInfo{name: "migrations_compliance/01_init.sql", size: 1133, mode: os.FileMode(436), modTime: time.Unix(1523031737, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance02_sent_attachmentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\x31\x6f\xfa\x30\x10\xc5\x77\x7f\x8a\x1b\x13\xfd\xff\x0c\x54\xa5\xaa\x84\x18\x0c\x71\xdb\xa8\xc1\x20\xe3\x0c\x4c\xb1\x95\x1c\xc5\x43\x2e\x95\x73\xa5\xfd\xf8\x55\x58\x80\x02\xa3\xfd\x7e\x4f\x77\xf7\xde\x68\x04\xff\xda\xf0\x11\x3d\x23\x94\x9f\x62\x61\x94\xb4\x0a\xac\x9c\x17\x0a\xdc\x06\x89\x25\xb3\xaf\xf7\x2d\x12\x3b\x48\x04\x80\x0b\x8d\x83\x40\x9c\x8c\xc7\x29\xe8\x95\x05\x5d\x16\x05\xc8\xd2\xae\xaa\x5c\x2f\x8c\x5a\x2a\x6d\xff\x0f\x1c\x47\x4f\xbd\xaf\x39\x74\x54\x0d\x9e\x7a\xef\x63\xf2\xf4\x78\x32\x1d\xa9\x16\xdb\xce\xc1\xc1\xc7\xdb\x72\x8f\xd4\x60\x3c\x01\x0f\x93\xc9\x4d\xe2\x38\xe1\x3e\x14\xb1\xc6\x70\xc0\x58\x05\xda\x75\x0e\x18\x7f\xf8\x12\xf0\x67\x57\x5e\xab\x3d\x12\x57\x9e\x1d\x34\x9e\x91\x43\x8b\x17\xf2\xda\xe4\x4b\x69\xb6\xf0\xae\xb6\x90\x0c\xf1\xa4\xc3\x62\xc3\xeb\x2a\x83\xe4\xef\x4f\x2a\x52\x50\xfa\x35\xd7\x6a\x96\x13\x75\xd9\x1c\x32\xf5\x22\xcb\xc2\xc2\xe2\x4d\x9a\x8d\xb2\xb3\x2f\xde\x3d\x4f\x85\x38\xef\x29\xeb\xbe\x49\x64\x66\xb5\xbe\xd3\xd3\x54\xfc\x0e\x00\x08\x83\xb8\x29\xd6\x01\x00\x00")
+
+func migrations_compliance02_sent_attachmentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance02_sent_attachmentSql,
+		"migrations_compliance/02_sent_attachment.sql",
+	)
+}
+
+func migrations_compliance02_sent_attachmentSql() (*asset, error) {
+	bytes, err := migrations_compliance02_sent_attachmentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/02_sent_attachment.sql", size: 470, mode: os.FileMode(420), modTime: time.Unix(1792030515, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance03_federation_snapshotSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\xc1\x6e\x82\x40\x10\x86\xef\x3c\xc5\xdc\x84\xb4\x26\xb5\xa9\xa6\x89\xf1\x80\xb2\xb6\xa4\x8a\x06\xe1\xe0\x09\xb6\xb0\x94\x4d\x65\x21\xbb\x83\xad\x6f\x5f\xa0\xa9\xa2\x54\x8f\x33\xf3\xfd\x93\xf9\x67\xa6\xdf\x87\xbb\x8c\x7f\x48\x8a\x0c\xfc\x42\x9b\xb9\xc4\xf4\x08\x78\xe6\x74\x41\x20\x9c\xb3\x98\x55\x15\x9e\x8b\x8d\xa0\x85\x4a\x73\x0c\x41\xd7\x00\x42\x1e\x87\xc0\x05\xea\x83\x81\x01\xce\xca\x03\xc7\x5f\x2c\xc0\xf4\xbd\x55\x60\x3b\x55\x8b\x25\x71\xbc\xfb\x9a\x43\x49\x85\xa2\x51\xdd\x21\xa8\x35\x51\x4a\xa5\x3e\x7a\x3a\x89\x1a\xaa\xa0\x87\x8c\x09\x6c\x88\x3d\x95\x0d\xf4\x38\x1c\x1a\x60\x91\xb9\xe9\x2f\x5a\xe4\x27\x17\x2d\x66\x30\xba\x68\x54\xca\x5d\x08\xc8\xbe\xf1\x3c\x9d\x70\x41\x77\xc1\x95\xa2\x42\x8a\xa5\x0a\xa2\x3c\x66\x5d\x4f\x0d\xf1\x9e\xc7\x87\x10\x32\x16\xf3\x32\xeb\xea\x51\x96\x22\xaa\xb6\x57\xcd\x85\x5c\x1c\x9a\x0e\xad\xa5\xfc\x59\x78\xf8\x85\x77\x2a\xd8\x33\xa9\xaa\x7d\xfc\x6f\xe3\xc8\xf7\x7a\x8d\x20\xe2\x45\xca\x64\xa0\x4a\x8e\xec\xa4\x68\x6f\xb0\xa3\x60\x12\x79\xc2\xeb\x91\xd4\xf5\xa9\x13\x86\x51\xca\xe2\x80\x56\x17\x8d\x2b\x14\x79\xc6\xce\x09\xc9\xa2\x5c\xc6\xb7\x90\xb5\x6b\x2f\x4d\x77\x0b\x6f\x64\x0b\x7a\xfd\x12\x46\x9d\xad\xa3\xce\xdd\xf5\xcb\xcc\x09\x6d\x1f\x5f\x6f\x47\x86\x66\x00\x71\x5e\x6c\x87\x4c\x6c\x21\x72\x6b\x7a\x74\x3a\x7b\x35\xdd\x0d\xf1\x26\x25\x26\xcf\x63\x4d\xeb\xb7\x5e\xd8\xca\xbf\x84\x66\xb9\xab\xf5\x8d\x17\x1e\x6b\x3f\x0d\xd9\xee\x40\xf5\x02\x00\x00")
+
+func migrations_compliance03_federation_snapshotSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance03_federation_snapshotSql,
+		"migrations_compliance/03_federation_snapshot.sql",
+	)
+}
+
+func migrations_compliance03_federation_snapshotSql() (*asset, error) {
+	bytes, err := migrations_compliance03_federation_snapshotSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/03_federation_snapshot.sql", size: 757, mode: os.FileMode(420), modTime: time.Unix(1792037395, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance04_sanctions_screeningSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\xcb\x6e\xc2\x30\x10\x45\xf7\xfe\x8a\xd9\x91\xa8\x45\x2a\x55\x41\x95\x10\x8b\x40\xdc\x36\x6a\x08\x28\x38\x0b\x56\xc4\xb2\x5d\xb0\x44\x1c\xe4\x4c\x68\xfb\xf7\x4d\x82\x44\x78\x14\x76\xf6\xcc\xb9\xf3\xd0\x9d\x6e\x17\x1e\x32\xbd\xb6\x1c\x15\x24\x3b\x32\x89\xa9\xc7\x28\x30\x6f\x1c\x52\x48\x17\xdc\x08\xd4\xb9\x29\x16\xc2\x2a\x65\xb4\x59\xa7\xe0\x10\x80\x54\xcb\x14\xb4\x41\xa7\xd7\x73\x21\x9a\x31\x88\x92\x30\x04\x2f\x61\xb3\x55\x10\x55\x25\xa6\x34\x62\x8f\x35\x87\x96\x9b\x82\x37\x35\x56\xb5\x66\xcf\xad\xd8\x70\xeb\x0c\x5e\x4e\x74\x3e\x7d\xf3\x92\x90\x41\xa7\xd3\x68\x6c\xbe\x55\x2d\xd9\x1b\xb4\x64\x93\xe6\x52\x5a\x55\x14\x2d\xf1\xdc\xef\x5f\x22\x42\xe4\xa5\xc1\xb3\x8e\xfd\xc1\xed\x8e\x86\x67\xea\x46\xbd\x4b\x74\x67\xf3\xbd\x96\xca\xde\x6b\x5f\x20\xc7\xb2\xb8\xb3\x42\xc6\x51\x6c\x54\x45\xa0\xfa\xc1\xf3\x94\x54\xc8\xf5\xb6\x4a\x65\x4a\xea\x32\xbb\x06\x04\xaf\xa4\xd5\x5e\xa8\xcd\x6f\xe3\xc0\x3f\xb3\x3e\x1d\xc6\x68\x3c\x53\x72\xc5\x31\x05\x59\xf9\x8b\x3a\x53\x67\xc5\xe6\x71\x30\xf5\xe2\x25\x7c\xd2\x25\x38\xb5\xa7\x6e\x1d\xad\x7f\x57\xc6\x39\x97\x91\x16\x3d\x1a\xe2\x1c\x9f\x2e\x71\x81\x46\xef\x41\x44\x47\x81\x31\xb9\x3f\x3e\x8e\x36\xf9\xf0\xe2\x05\x65\xa3\x12\xbf\x5e\x87\x84\x74\x4f\xae\xcf\xcf\xbf\x0d\xf1\xe3\xd9\xfc\xce\xf5\x0d\xc9\x1f\x63\xa4\x2b\x0b\xb0\x02\x00\x00")
+
+func migrations_compliance04_sanctions_screeningSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance04_sanctions_screeningSql,
+		"migrations_compliance/04_sanctions_screening.sql",
+	)
+}
+
+func migrations_compliance04_sanctions_screeningSql() (*asset, error) {
+	bytes, err := migrations_compliance04_sanctions_screeningSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/04_sanctions_screening.sql", size: 688, mode: os.FileMode(420), modTime: time.Unix(1792042765, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance05_compliance_auditSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\xc1\x4f\x83\x30\x18\xc5\xef\xfd\x2b\xbe\xdb\x20\xba\xc3\xa6\x5b\x4c\x96\x1d\xba\x51\x95\xc8\x60\x22\x1c\x76\xa2\x15\xea\xd6\x44\x0a\x96\x32\xdd\x7f\x6f\x21\x71\x20\x66\xde\xda\xef\xfd\x5e\xde\xd7\x97\x8e\xc7\x70\x95\x8b\xbd\x62\x9a\x43\x5c\xa2\x75\x48\x70\x44\x20\xc2\x2b\x8f\x00\x5d\x17\x79\xf9\x2e\x98\x4c\x39\xae\x33\xa1\x29\x58\x08\x80\x8a\x8c\x82\x90\xda\x9a\x4c\x6c\xf0\x83\x08\xfc\xd8\xf3\x00\xc7\x51\x90\xb8\xbe\xf1\x6f\x88\x1f\x5d\x37\x5c\xc5\x3f\x6a\x6e\xbc\x14\x5e\xc5\xde\x18\xce\x70\xab\xea\x53\x69\x94\x23\x53\xe9\x81\x29\xeb\x66\x6a\x0f\x64\xc5\x64\xc5\x52\x2d\x0a\x99\x34\x81\x3f\xe0\xfc\xb6\x17\xea\x90\x7b\x1c\x7b\x11\x8c\x46\xad\x27\x2d\x6a\xa9\xb9\x2a\x99\xd2\xa7\xce\x31\x9d\xcd\x2e\x5b\x32\x9e\x8a\xca\x64\x74\xf8\x64\x3e\xd8\x24\x63\x9a\x51\xc8\x79\x26\xea\x5c\xf3\xaf\xc1\x33\x4a\xc5\x8f\xc9\x81\x55\x07\x0a\x7f\xf6\x6b\x81\xff\xb4\x54\x71\xd3\x7b\x96\x30\x53\xad\x89\xe1\x5a\xe4\xfc\x17\xb1\x0d\xdd\x0d\x0e\x77\xf0\x44\x76\x60\x35\xc5\xdb\xcd\x34\xf6\xdd\xe7\x98\xb4\xc3\x5e\xc9\x56\x77\x6e\xa9\x56\x1e\xd6\x68\x0d\x27\x1d\xda\x5f\xc6\xea\xdf\x6c\x64\x03\xf1\x1f\x5c\x9f\x2c\x5d\x29\x0b\x67\x75\x2e\x71\xfd\x88\xc3\x17\x12\x2d\x6b\xfd\x76\xb7\x40\x68\xdc\xfb\x4b\x4e\xf1\x29\x91\x13\x06\xdb\x4b\x7f\x69\x81\xbe\x01\x79\x21\x04\xf1\x7b\x02\x00\x00")
+
+func migrations_compliance05_compliance_auditSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance05_compliance_auditSql,
+		"migrations_compliance/05_compliance_audit.sql",
+	)
+}
+
+func migrations_compliance05_compliance_auditSql() (*asset, error) {
+	bytes, err := migrations_compliance05_compliance_auditSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/05_compliance_audit.sql", size: 635, mode: os.FileMode(420), modTime: time.Unix(1792042944, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance06_attachmentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x6d\xd1\xbd\x4e\xc3\x30\x10\x00\xe0\xdd\x4f\x71\x5b\x13\x41\x86\x22\x8a\x2a\x55\x1d\xdc\xc6\x40\x44\xea\x96\x60\x0f\x9d\x12\x2b\x31\xc4\x83\x9d\xca\xbd\xf0\xf3\xf6\x24\x91\x68\x43\x61\xb3\x4f\xdf\x9d\xcf\x77\x51\x04\x57\xd6\xbc\x79\x85\x1a\xe4\x81\xac\x33\x46\x05\x03\x41\x57\x29\x83\x82\x22\xaa\xb2\xb6\xda\x61\x01\x01\x01\x28\x4c\x55\x80\x71\x18\x4c\xa7\x21\xf0\xad\x00\x2e\xd3\x14\xa8\x14\xdb\x3c\xe1\x5d\xea\x86\x71\x71\xdd\x3b\xab\x6d\x93\xd7\xea\x58\x17\x50\xd6\xca\x07\x77\xb7\x67\x3f\x80\xca\x78\x5d\xa2\x69\x5c\x01\xef\xca\x0f\x66\x7e\x41\xd0\x2b\x77\x54\x03\xca\xfb\x77\x7f\xdc\x9f\x5a\x65\xd3\x3a\xd4\xfe\xa0\x3c\x7e\x9d\xd9\xcd\x6c\x36\xea\x31\x66\xf7\x54\xa6\x02\x26\x93\x21\x45\x8d\x3e\x66\x75\x65\x5a\x8b\xfa\x13\x2f\xca\x7a\xdd\x0d\xa5\xca\x55\x67\xaa\xee\x84\xc6\xea\x5f\x62\x97\x25\x1b\x9a\xed\xe1\x89\xed\x21\xe8\x47\x13\xf6\x51\xc9\x93\x67\xc9\x86\xe0\x78\x0c\xc1\xe8\x12\x92\x10\x18\x7f\x48\x38\x5b\x26\xce\x35\xf1\xea\xd4\xde\xfa\x91\x66\x2f\x4c\x2c\x5b\x7c\x9d\x2f\x08\x89\x46\xcb\x89\x9b\x0f\x47\xe2\x6c\xbb\xfb\x67\x39\x0b\xf2\x0d\xfe\x8f\x9f\x12\xc7\x01\x00\x00")
+
+func migrations_compliance06_attachmentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance06_attachmentSql,
+		"migrations_compliance/06_attachment.sql",
+	)
+}
+
+func migrations_compliance06_attachmentSql() (*asset, error) {
+	bytes, err := migrations_compliance06_attachmentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/06_attachment.sql", size: 455, mode: os.FileMode(420), modTime: time.Unix(1792043136, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -203,21 +980,60 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"migrations_gateway/01_init.sql": migrations_gateway01_initSql,
-	"migrations_gateway/02_payment_id.sql": migrations_gateway02_payment_idSql,
-	"migrations_gateway/03_transaction_id.sql": migrations_gateway03_transaction_idSql,
-	"migrations_compliance/01_init.sql": migrations_compliance01_initSql,
+	"migrations_gateway/01_init.sql":                      migrations_gateway01_initSql,
+	"migrations_gateway/02_payment_id.sql":                migrations_gateway02_payment_idSql,
+	"migrations_gateway/03_transaction_id.sql":            migrations_gateway03_transaction_idSql,
+	"migrations_gateway/04_held_payment.sql":              migrations_gateway04_held_paymentSql,
+	"migrations_gateway/05_compliance_repair.sql":         migrations_gateway05_compliance_repairSql,
+	"migrations_gateway/06_sent_transaction_region.sql":   migrations_gateway06_sent_transaction_regionSql,
+	"migrations_gateway/07_bad_seq_resolution.sql":        migrations_gateway07_bad_seq_resolutionSql,
+	"migrations_gateway/08_memo_required_destination.sql": migrations_gateway08_memo_required_destinationSql,
+	"migrations_gateway/09_feature_flag.sql":              migrations_gateway09_feature_flagSql,
+	"migrations_gateway/10_security_event.sql":            migrations_gateway10_security_eventSql,
+	"migrations_gateway/11_api_key.sql":                   migrations_gateway11_api_keySql,
+	"migrations_gateway/12_destination_profile.sql":       migrations_gateway12_destination_profileSql,
+	"migrations_gateway/13_daily_aggregate.sql":           migrations_gateway13_daily_aggregateSql,
+	"migrations_gateway/14_federation_snapshot.sql":       migrations_gateway14_federation_snapshotSql,
+	"migrations_gateway/15_received_payment_event.sql": migrations_gateway15_received_payment_eventSql,
+	"migrations_gateway/16_payment_limit_usage.sql": migrations_gateway16_payment_limit_usageSql,
+	"migrations_gateway/17_named_destination.sql": migrations_gateway17_named_destinationSql,
+	"migrations_gateway/18_held_payment_approval.sql": migrations_gateway18_held_payment_approvalSql,
+	"migrations_gateway/19_payment_velocity.sql": migrations_gateway19_payment_velocitySql,
+	"migrations_gateway/20_expected_payment.sql": migrations_gateway20_expected_paymentSql,
+	"migrations_gateway/21_refund.sql": migrations_gateway21_refundSql,
+	"migrations_gateway/22_failed_callback.sql": migrations_gateway22_failed_callbackSql,
+	"migrations_gateway/23_offline_transaction.sql": migrations_gateway23_offline_transactionSql,
+	"migrations_gateway/24_quote.sql": migrations_gateway24_quoteSql,
+	"migrations_gateway/25_fiat_value.sql": migrations_gateway25_fiat_valueSql,
+	"migrations_gateway/26_webhook.sql": migrations_gateway26_webhookSql,
+	"migrations_gateway/27_webhook_format.sql": migrations_gateway27_webhook_formatSql,
+	"migrations_gateway/28_memo_reference.sql": migrations_gateway28_memo_referenceSql,
+	"migrations_gateway/29_sent_transaction_metadata.sql": migrations_gateway29_sent_transaction_metadataSql,
+	"migrations_gateway/30_leader_lease.sql": migrations_gateway30_leader_leaseSql,
+	"migrations_gateway/31_sep24_transaction.sql": migrations_gateway31_sep24_transactionSql,
+	"migrations_gateway/32_held_payment_seed.sql": migrations_gateway32_held_payment_seedSql,
+	"migrations_gateway/33_sent_transaction_conflict.sql": migrations_gateway33_sent_transaction_conflictSql,
+	"migrations_gateway/34_sent_transaction_original_id.sql": migrations_gateway34_sent_transaction_original_idSql,
+	"migrations_gateway/35_api_key_tenant.sql": migrations_gateway35_api_key_tenantSql,
+	"migrations_compliance/01_init.sql":                   migrations_compliance01_initSql,
+	"migrations_compliance/02_sent_attachment.sql":        migrations_compliance02_sent_attachmentSql,
+	"migrations_compliance/03_federation_snapshot.sql":    migrations_compliance03_federation_snapshotSql,
+	"migrations_compliance/04_sanctions_screening.sql": migrations_compliance04_sanctions_screeningSql,
+	"migrations_compliance/05_compliance_audit.sql": migrations_compliance05_compliance_auditSql,
+	"migrations_compliance/06_attachment.sql": migrations_compliance06_attachmentSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"}
 // AssetDir("data/img") would return []string{"a.png", "b.png"}
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error
@@ -248,14 +1064,52 @@ type bintree struct {
 	Func     func() (*asset, error)
 	Children map[string]*bintree
 }
+
 var _bintree = &bintree{nil, map[string]*bintree{
 	"migrations_compliance": &bintree{nil, map[string]*bintree{
-		"01_init.sql": &bintree{migrations_compliance01_initSql, map[string]*bintree{}},
+		"01_init.sql":                &bintree{migrations_compliance01_initSql, map[string]*bintree{}},
+		"02_sent_attachment.sql":     &bintree{migrations_compliance02_sent_attachmentSql, map[string]*bintree{}},
+		"03_federation_snapshot.sql": &bintree{migrations_compliance03_federation_snapshotSql, map[string]*bintree{}},
+		"04_sanctions_screening.sql": &bintree{migrations_compliance04_sanctions_screeningSql, map[string]*bintree{}},
+		"05_compliance_audit.sql": &bintree{migrations_compliance05_compliance_auditSql, map[string]*bintree{}},
+		"06_attachment.sql": &bintree{migrations_compliance06_attachmentSql, map[string]*bintree{}},
 	}},
 	"migrations_gateway": &bintree{nil, map[string]*bintree{
-		"01_init.sql": &bintree{migrations_gateway01_initSql, map[string]*bintree{}},
-		"02_payment_id.sql": &bintree{migrations_gateway02_payment_idSql, map[string]*bintree{}},
-		"03_transaction_id.sql": &bintree{migrations_gateway03_transaction_idSql, map[string]*bintree{}},
+		"01_init.sql":                      &bintree{migrations_gateway01_initSql, map[string]*bintree{}},
+		"02_payment_id.sql":                &bintree{migrations_gateway02_payment_idSql, map[string]*bintree{}},
+		"03_transaction_id.sql":            &bintree{migrations_gateway03_transaction_idSql, map[string]*bintree{}},
+		"04_held_payment.sql":              &bintree{migrations_gateway04_held_paymentSql, map[string]*bintree{}},
+		"05_compliance_repair.sql":         &bintree{migrations_gateway05_compliance_repairSql, map[string]*bintree{}},
+		"06_sent_transaction_region.sql":   &bintree{migrations_gateway06_sent_transaction_regionSql, map[string]*bintree{}},
+		"07_bad_seq_resolution.sql":        &bintree{migrations_gateway07_bad_seq_resolutionSql, map[string]*bintree{}},
+		"08_memo_required_destination.sql": &bintree{migrations_gateway08_memo_required_destinationSql, map[string]*bintree{}},
+		"09_feature_flag.sql":              &bintree{migrations_gateway09_feature_flagSql, map[string]*bintree{}},
+		"10_security_event.sql":            &bintree{migrations_gateway10_security_eventSql, map[string]*bintree{}},
+		"11_api_key.sql":                   &bintree{migrations_gateway11_api_keySql, map[string]*bintree{}},
+		"12_destination_profile.sql":       &bintree{migrations_gateway12_destination_profileSql, map[string]*bintree{}},
+		"13_daily_aggregate.sql":           &bintree{migrations_gateway13_daily_aggregateSql, map[string]*bintree{}},
+		"14_federation_snapshot.sql":       &bintree{migrations_gateway14_federation_snapshotSql, map[string]*bintree{}},
+		"15_received_payment_event.sql": &bintree{migrations_gateway15_received_payment_eventSql, map[string]*bintree{}},
+		"16_payment_limit_usage.sql": &bintree{migrations_gateway16_payment_limit_usageSql, map[string]*bintree{}},
+		"17_named_destination.sql": &bintree{migrations_gateway17_named_destinationSql, map[string]*bintree{}},
+		"18_held_payment_approval.sql": &bintree{migrations_gateway18_held_payment_approvalSql, map[string]*bintree{}},
+		"19_payment_velocity.sql": &bintree{migrations_gateway19_payment_velocitySql, map[string]*bintree{}},
+		"20_expected_payment.sql": &bintree{migrations_gateway20_expected_paymentSql, map[string]*bintree{}},
+		"21_refund.sql": &bintree{migrations_gateway21_refundSql, map[string]*bintree{}},
+		"22_failed_callback.sql": &bintree{migrations_gateway22_failed_callbackSql, map[string]*bintree{}},
+		"23_offline_transaction.sql": &bintree{migrations_gateway23_offline_transactionSql, map[string]*bintree{}},
+		"24_quote.sql": &bintree{migrations_gateway24_quoteSql, map[string]*bintree{}},
+		"25_fiat_value.sql": &bintree{migrations_gateway25_fiat_valueSql, map[string]*bintree{}},
+		"26_webhook.sql": &bintree{migrations_gateway26_webhookSql, map[string]*bintree{}},
+		"27_webhook_format.sql": &bintree{migrations_gateway27_webhook_formatSql, map[string]*bintree{}},
+		"28_memo_reference.sql": &bintree{migrations_gateway28_memo_referenceSql, map[string]*bintree{}},
+		"29_sent_transaction_metadata.sql": &bintree{migrations_gateway29_sent_transaction_metadataSql, map[string]*bintree{}},
+		"30_leader_lease.sql": &bintree{migrations_gateway30_leader_leaseSql, map[string]*bintree{}},
+		"31_sep24_transaction.sql": &bintree{migrations_gateway31_sep24_transactionSql, map[string]*bintree{}},
+		"32_held_payment_seed.sql": &bintree{migrations_gateway32_held_payment_seedSql, map[string]*bintree{}},
+		"33_sent_transaction_conflict.sql": &bintree{migrations_gateway33_sent_transaction_conflictSql, map[string]*bintree{}},
+		"34_sent_transaction_original_id.sql": &bintree{migrations_gateway34_sent_transaction_original_idSql, map[string]*bintree{}},
+		"35_api_key_tenant.sql": &bintree{migrations_gateway35_api_key_tenantSql, map[string]*bintree{}},
 	}},
 }}
 
@@ -305,4 +1159,3 @@ func _filePath(dir, name string) string {
 	cannonicalName := strings.Replace(name, "\\", "/", -1)
 	return filepath.Join(append([]string{dir}, strings.Split(cannonicalName, "/")...)...)
 }
-
@@ -11,6 +11,7 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/db/entities"
 )
 
@@ -38,6 +39,18 @@ func (d *Driver) MigrateUp(component string) (migrationsApplied int, err error)
 	return
 }
 
+// MigrateDown rolls back max migrations (all when 0)
+func (d *Driver) MigrateDown(component string, max int) (migrationsApplied int, err error) {
+	source := d.getAssetMigrationSource(component)
+	migrationsApplied, err = migrate.ExecMax(d.database.DB, "mysql", source, migrate.Down, max)
+	return
+}
+
+// MigrationStatus returns applied and pending migrations
+func (d *Driver) MigrationStatus(component string) (db.MigrationStatus, error) {
+	return db.GetMigrationStatus(d.database.DB, "mysql", d.getAssetMigrationSource(component))
+}
+
 // Insert inserts the entity to a DB
 func (d *Driver) Insert(object entities.Entity) (id int64, err error) {
 	value, tableName, err := getTypeData(object)
@@ -74,6 +87,58 @@ func (d *Driver) Insert(object entities.Entity) (id int64, err error) {
 		result, err = d.database.NamedExec(query, object)
 	case *entities.ReceivedPayment:
 		result, err = d.database.NamedExec(query, object)
+	case *entities.HeldPayment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ComplianceRepair:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SEP24Transaction:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.MemoReference:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.Webhook:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.FiatValue:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.Quote:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.OfflineTransaction:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.FailedCallback:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.Refund:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.PaymentMatch:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ExpectedPayment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.Attachment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ComplianceAuditEntry:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SanctionsScreening:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.PaymentVelocity:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.NamedDestination:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.PaymentLimitUsage:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ReceivedPaymentEvent:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.FederationSnapshot:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.DailyAggregate:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.APIKey:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SecurityEvent:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SentAttachment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.FeatureFlag:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.MemoRequiredDestination:
+		result, err = d.database.NamedExec(query, object)
 	}
 
 	if err != nil {
@@ -132,6 +197,58 @@ func (d *Driver) Update(object entities.Entity) (err error) {
 		_, err = d.database.NamedExec(query, object)
 	case *entities.ReceivedPayment:
 		_, err = d.database.NamedExec(query, object)
+	case *entities.HeldPayment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ComplianceRepair:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SEP24Transaction:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.MemoReference:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.Webhook:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.FiatValue:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.Quote:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.OfflineTransaction:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.FailedCallback:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.Refund:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.PaymentMatch:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ExpectedPayment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.Attachment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ComplianceAuditEntry:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SanctionsScreening:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.PaymentVelocity:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.NamedDestination:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.PaymentLimitUsage:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ReceivedPaymentEvent:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.FederationSnapshot:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.DailyAggregate:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.APIKey:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SecurityEvent:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SentAttachment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.FeatureFlag:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.MemoRequiredDestination:
+		_, err = d.database.NamedExec(query, object)
 	}
 
 	return
@@ -211,6 +328,13 @@ func (d *Driver) GetMany(slice interface{}, where, order, offset, limit *string,
 			tmp[i].SetExists()
 		}
 		slice = &tmp
+	case *[]*entities.HeldPayment:
+		err = d.database.Select(slice, query.String(), params...)
+		tmp := *slice
+		for i := range tmp {
+			tmp[i].SetExists()
+		}
+		slice = &tmp
 	}
 
 	if err != nil && err.Error() == "sql: no rows in result set" {
@@ -241,6 +365,86 @@ func getTypeData(object interface{}) (typeValue reflect.Type, tableName string,
 		tableName = "SentTransaction"
 	case *[]*entities.ReceivedPayment:
 		tableName = "ReceivedPayment"
+	case *entities.HeldPayment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "HeldPayment"
+	case *[]*entities.HeldPayment:
+		tableName = "HeldPayment"
+	case *entities.ComplianceRepair:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ComplianceRepair"
+	case *entities.SEP24Transaction:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SEP24Transaction"
+	case *entities.MemoReference:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "MemoReference"
+	case *entities.Webhook:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Webhook"
+	case *entities.FiatValue:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FiatValue"
+	case *entities.Quote:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Quote"
+	case *entities.OfflineTransaction:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "OfflineTransaction"
+	case *entities.FailedCallback:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FailedCallback"
+	case *entities.Refund:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Refund"
+	case *entities.PaymentMatch:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentMatch"
+	case *entities.ExpectedPayment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ExpectedPayment"
+	case *entities.Attachment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Attachment"
+	case *entities.ComplianceAuditEntry:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ComplianceAudit"
+	case *entities.SanctionsScreening:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SanctionsScreening"
+	case *entities.PaymentVelocity:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentVelocity"
+	case *entities.NamedDestination:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "NamedDestination"
+	case *entities.PaymentLimitUsage:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentLimitUsage"
+	case *entities.ReceivedPaymentEvent:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ReceivedPaymentEvent"
+	case *entities.FederationSnapshot:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FederationSnapshot"
+	case *entities.DailyAggregate:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "DailyAggregate"
+	case *entities.APIKey:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "APIKey"
+	case *entities.SecurityEvent:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SecurityEvent"
+	case *entities.SentAttachment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SentAttachment"
+	case *entities.FeatureFlag:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FeatureFlag"
+	case *entities.MemoRequiredDestination:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "MemoRequiredDestination"
 	default:
 		return typeValue, tableName, fmt.Errorf("Unknown entity type: %T", object)
 	}
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE `SentAttachment` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `transaction_id` char(64) NOT NULL,
+  `memo` varchar(64) NOT NULL,
+  `sender` varchar(255) NOT NULL,
+  `sender_id` varchar(255) NOT NULL,
+  `receiver_info` text NOT NULL,
+  `attachment` text NOT NULL,
+  `sent_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `transaction_id` (`transaction_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `SentAttachment`;
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE `SanctionsScreening` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `transaction_id` varchar(64) NOT NULL DEFAULT '',
+  `role` varchar(16) NOT NULL,
+  `address` varchar(255) NOT NULL,
+  `account_id` varchar(56) NOT NULL DEFAULT '',
+  `name` varchar(255) NOT NULL DEFAULT '',
+  `provider` varchar(255) NOT NULL,
+  `status` varchar(16) NOT NULL,
+  `matches` text NOT NULL,
+  `details` mediumtext NOT NULL,
+  `cached` tinyint(1) NOT NULL DEFAULT 0,
+  `screened_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `transaction_id` (`transaction_id`),
+  KEY `address` (`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `SanctionsScreening`;
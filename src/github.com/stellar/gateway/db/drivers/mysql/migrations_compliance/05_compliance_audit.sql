@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE `ComplianceAudit` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `sequence` bigint NOT NULL,
+  `type` varchar(32) NOT NULL,
+  `transaction_id` varchar(64) NOT NULL DEFAULT '',
+  `counterparty` varchar(255) NOT NULL DEFAULT '',
+  `decision` varchar(16) NOT NULL,
+  `data` mediumtext NOT NULL,
+  `prev_hash` char(64) NOT NULL,
+  `hash` char(64) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `sequence` (`sequence`),
+  KEY `transaction_id` (`transaction_id`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `ComplianceAudit`;
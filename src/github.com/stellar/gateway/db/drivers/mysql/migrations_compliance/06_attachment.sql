@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE `Attachment` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `memo_hash` char(64) NOT NULL,
+  `direction` varchar(8) NOT NULL,
+  `transaction_id` varchar(64) NOT NULL,
+  `counterparty` varchar(255) NOT NULL DEFAULT '',
+  `attachment` mediumtext NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `memo_hash` (`memo_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `Attachment`;
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE `HeldPayment` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `payment_id` varchar(255) NOT NULL,
+  `source_account` varchar(56) NOT NULL,
+  `request` text NOT NULL,
+  `status` varchar(10) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `settle_at` datetime NOT NULL,
+  `cancelled_at` datetime DEFAULT NULL,
+  `result` text DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `payment_id` (`payment_id`),
+  KEY `status_settle_at` (`status`, `settle_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `HeldPayment`;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE `ComplianceRepair` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `operation_id` varchar(255) NOT NULL,
+  `original_memo` varchar(255) NOT NULL,
+  `compliance_memo` varchar(255) NOT NULL,
+  `verified_by` varchar(255) NOT NULL,
+  `reason` text NOT NULL,
+  `repaired_at` datetime NOT NULL,
+  `status` varchar(255) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `operation_id` (`operation_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `ComplianceRepair`;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `SentTransaction` ADD `region` VARCHAR(64) NOT NULL DEFAULT '' AFTER `payment_id`;
+ALTER TABLE `SentTransaction` DROP INDEX `payment_id`, ADD UNIQUE `region_payment_id` (`region`, `payment_id`);
+
+-- +migrate Down
+ALTER TABLE `SentTransaction` DROP INDEX `region_payment_id`, ADD UNIQUE (`payment_id`);
+ALTER TABLE `SentTransaction` DROP `region`;
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE `MemoRequiredDestination` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `account_id` varchar(56) NOT NULL,
+  `source` varchar(16) NOT NULL,
+  `reason` text NOT NULL,
+  `transaction_id` char(64) NULL DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `account_id` (`account_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `MemoRequiredDestination`;
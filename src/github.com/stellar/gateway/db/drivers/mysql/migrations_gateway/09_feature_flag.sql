@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE `FeatureFlag` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(64) NOT NULL,
+  `tenant` varchar(64) NOT NULL,
+  `enabled` tinyint(1) NOT NULL,
+  `updated_by` varchar(255) NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name_tenant` (`name`, `tenant`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `FeatureFlag`;
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE `SecurityEvent` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `event_id` varchar(64) NOT NULL,
+  `type` varchar(32) NOT NULL,
+  `format` varchar(8) NOT NULL,
+  `payload` text NOT NULL,
+  `created_at` datetime NOT NULL,
+  `attempts` int(11) NOT NULL DEFAULT 0,
+  `next_attempt_at` datetime NOT NULL,
+  `delivered_at` datetime DEFAULT NULL,
+  `last_error` text DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `delivered_at_next_attempt_at` (`delivered_at`, `next_attempt_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `SecurityEvent`;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE `APIKey` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `key_id` varchar(64) NOT NULL,
+  `secret` varchar(255) NOT NULL,
+  `require_signature` tinyint(1) NOT NULL DEFAULT 0,
+  `rate_limit` int(11) NOT NULL DEFAULT 0,
+  `sources` text NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `key_id` (`key_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `APIKey`;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE `MemoRequiredDestination` ADD `profile` VARCHAR(64) DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE `MemoRequiredDestination` DROP `profile`;
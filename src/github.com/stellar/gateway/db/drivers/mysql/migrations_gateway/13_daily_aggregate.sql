@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE `DailyAggregate` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `day` varchar(10) NOT NULL,
+  `tenant` varchar(64) NOT NULL DEFAULT '',
+  `direction` varchar(10) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  `count` bigint(20) NOT NULL DEFAULT 0,
+  `amount` bigint(20) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `aggregate` (`day`, `tenant`, `direction`, `asset_code`, `asset_issuer`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `DailyAggregate`;
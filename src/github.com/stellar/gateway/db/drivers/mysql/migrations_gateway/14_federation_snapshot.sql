@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE `FederationSnapshot` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `transaction_id` char(64) NOT NULL,
+  `payment_id` varchar(255) DEFAULT NULL,
+  `kind` varchar(16) NOT NULL,
+  `url` text NOT NULL,
+  `final_url` text NOT NULL,
+  `status_code` int(11) NOT NULL,
+  `body` mediumtext NOT NULL,
+  `truncated` tinyint(1) NOT NULL DEFAULT 0,
+  `tls_version` varchar(16) NOT NULL DEFAULT '',
+  `cipher_suite` varchar(64) NOT NULL DEFAULT '',
+  `certificates` mediumtext NOT NULL,
+  `fetched_at` datetime NOT NULL,
+  `recorded_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `transaction_id` (`transaction_id`),
+  KEY `payment_id` (`payment_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `FederationSnapshot`;
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE `ReceivedPaymentEvent` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `operation_id` varchar(255) NOT NULL,
+  `payload` text NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `ReceivedPaymentEvent`;
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE `PaymentLimitUsage` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `day` varchar(10) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  `destination` varchar(255) NOT NULL DEFAULT '',
+  `amount` bigint(20) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `usage` (`day`, `asset_code`, `asset_issuer`, `destination`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `PaymentLimitUsage`;
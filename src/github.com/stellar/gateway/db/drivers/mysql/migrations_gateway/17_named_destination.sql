@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE `NamedDestination` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(64) NOT NULL,
+  `destination` varchar(255) NOT NULL,
+  `memo_type` varchar(16) NOT NULL DEFAULT '',
+  `memo` varchar(255) NOT NULL DEFAULT '',
+  `asset_code` varchar(12) NOT NULL DEFAULT '',
+  `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `NamedDestination`;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE `HeldPayment` ADD `created_by` varchar(64) DEFAULT NULL;
+ALTER TABLE `HeldPayment` ADD `approved_by` varchar(64) DEFAULT NULL;
+ALTER TABLE `HeldPayment` ADD `approved_at` datetime DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE `HeldPayment` DROP `created_by`;
+ALTER TABLE `HeldPayment` DROP `approved_by`;
+ALTER TABLE `HeldPayment` DROP `approved_at`;
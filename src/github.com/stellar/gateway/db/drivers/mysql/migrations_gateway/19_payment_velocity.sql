@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE `PaymentVelocity` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `source` varchar(56) NOT NULL,
+  `destination` varchar(255) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  `amount` bigint(20) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `source` (`source`, `created_at`),
+  KEY `destination` (`destination`, `created_at`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `PaymentVelocity`;
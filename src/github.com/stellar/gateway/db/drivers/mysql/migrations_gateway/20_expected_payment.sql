@@ -0,0 +1,41 @@
+-- +migrate Up
+CREATE TABLE `ExpectedPayment` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `expected_id` varchar(64) NOT NULL,
+  `amount` bigint(20) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  `memo_type` varchar(6) NOT NULL,
+  `memo` varchar(64) NOT NULL,
+  `status` varchar(16) NOT NULL,
+  `received_amount` bigint(20) NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `expected_id` (`expected_id`),
+  KEY `memo` (`memo_type`, `memo`, `status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `PaymentMatch` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `operation_id` varchar(255) NOT NULL,
+  `transaction_id` varchar(64) NOT NULL,
+  `from_account` varchar(56) NOT NULL,
+  `amount` bigint(20) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  `memo_type` varchar(6) NOT NULL DEFAULT '',
+  `memo` varchar(64) NOT NULL DEFAULT '',
+  `expected_id` varchar(64) NOT NULL DEFAULT '',
+  `status` varchar(16) NOT NULL,
+  `received_amount` bigint(20) NOT NULL DEFAULT 0,
+  `matched_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `operation_id` (`operation_id`),
+  KEY `expected_id` (`expected_id`),
+  KEY `status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `PaymentMatch`;
+DROP TABLE `ExpectedPayment`;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE `Refund` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `operation_id` varchar(255) NOT NULL,
+  `transaction_id` varchar(64) NOT NULL,
+  `payment_id` varchar(255) NOT NULL,
+  `destination` varchar(56) NOT NULL,
+  `amount` varchar(255) NOT NULL,
+  `asset_code` varchar(12) NOT NULL DEFAULT '',
+  `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  `memo_type` varchar(6) NOT NULL DEFAULT '',
+  `memo` varchar(64) NOT NULL DEFAULT '',
+  `reason` varchar(255) NOT NULL DEFAULT '',
+  `status` varchar(16) NOT NULL,
+  `refund_transaction_id` varchar(64) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `operation_id` (`operation_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `Refund`;
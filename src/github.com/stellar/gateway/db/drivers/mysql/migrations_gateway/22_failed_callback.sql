@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE `FailedCallback` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `operation_id` varchar(255) NOT NULL,
+  `payload` text NOT NULL,
+  `last_error` text NOT NULL,
+  `attempts` int(11) NOT NULL,
+  `status` varchar(16) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `operation_id` (`operation_id`),
+  KEY `status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `FailedCallback`;
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE `Quote` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `quote_id` varchar(64) NOT NULL,
+  `network` varchar(255) NOT NULL,
+  `type` varchar(16) NOT NULL,
+  `send_asset_code` varchar(12) NOT NULL,
+  `send_asset_issuer` varchar(56) NOT NULL,
+  `send_amount` varchar(32) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `asset_issuer` varchar(56) NOT NULL,
+  `amount` varchar(32) NOT NULL,
+  `path` text NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `quote_id` (`quote_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `Quote`;
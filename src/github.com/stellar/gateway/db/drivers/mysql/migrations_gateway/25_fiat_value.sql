@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE `FiatValue` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `direction` varchar(16) NOT NULL,
+  `reference` varchar(255) NOT NULL,
+  `tenant` varchar(255) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `asset_issuer` varchar(56) NOT NULL,
+  `amount` varchar(32) NOT NULL,
+  `currency` varchar(12) NOT NULL,
+  `rate` varchar(64) NOT NULL,
+  `fiat_amount` varchar(64) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `direction_reference` (`direction`, `reference`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `FiatValue`;
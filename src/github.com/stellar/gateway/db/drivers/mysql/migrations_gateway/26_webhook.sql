@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE `Webhook` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `webhook_id` varchar(32) NOT NULL,
+  `url` varchar(2048) NOT NULL,
+  `events` varchar(255) NOT NULL,
+  `secret` varchar(255) NOT NULL DEFAULT '',
+  `max_attempts` int(11) NOT NULL,
+  `retry_interval` int(11) NOT NULL,
+  `enabled` tinyint(1) NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `webhook_id` (`webhook_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `Webhook`;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE `Webhook` ADD `format` varchar(16) NOT NULL DEFAULT 'json' AFTER `enabled`, ADD `schema_version` varchar(16) NOT NULL DEFAULT '1' AFTER `format`;
+
+-- +migrate Down
+ALTER TABLE `Webhook` DROP `format`, DROP `schema_version`;
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE `MemoReference` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `memo` char(64) NOT NULL,
+  `reference` varchar(255) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `memo` (`memo`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `MemoReference`;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE `SentTransaction` ADD `metadata` text NULL DEFAULT NULL AFTER `bad_seq_resolution`;
+
+-- +migrate Down
+ALTER TABLE `SentTransaction` DROP `metadata`;
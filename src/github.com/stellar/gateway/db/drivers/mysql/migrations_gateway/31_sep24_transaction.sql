@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE `SEP24Transaction` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `transaction_id` varchar(64) NOT NULL,
+  `kind` varchar(10) NOT NULL,
+  `status` varchar(32) NOT NULL,
+  `account` varchar(56) NOT NULL,
+  `asset_code` varchar(12) NOT NULL,
+  `asset_issuer` varchar(56) NOT NULL DEFAULT '',
+  `amount_in` bigint(20) NOT NULL DEFAULT 0,
+  `amount_out` bigint(20) NOT NULL DEFAULT 0,
+  `amount_fee` bigint(20) NOT NULL DEFAULT 0,
+  `anchor_account` varchar(56) NOT NULL DEFAULT '',
+  `memo_type` varchar(6) NOT NULL DEFAULT '',
+  `memo` varchar(64) NOT NULL DEFAULT '',
+  `more_info_url` varchar(255) NOT NULL DEFAULT '',
+  `stellar_transaction_id` varchar(64) NOT NULL DEFAULT '',
+  `external_transaction_id` varchar(255) NOT NULL DEFAULT '',
+  `message` varchar(255) NOT NULL DEFAULT '',
+  `started_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  `completed_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `transaction_id` (`transaction_id`),
+  KEY `memo` (`memo_type`, `memo`, `status`),
+  KEY `account` (`account`, `asset_code`),
+  KEY `stellar_transaction_id` (`stellar_transaction_id`),
+  KEY `external_transaction_id` (`external_transaction_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+DROP TABLE `SEP24Transaction`;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `HeldPayment` ADD `encrypted_seed` text DEFAULT NULL;
+ALTER TABLE `HeldPayment` ADD `attempts` int NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE `HeldPayment` DROP `encrypted_seed`;
+ALTER TABLE `HeldPayment` DROP `attempts`;
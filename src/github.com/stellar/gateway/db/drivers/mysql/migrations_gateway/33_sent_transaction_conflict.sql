@@ -0,0 +1,37 @@
+-- +migrate Up
+CREATE TABLE `SentTransactionConflict` (
+  `id` int(11) NOT NULL,
+  `payment_id` varchar(255) NOT NULL,
+  `region` varchar(64) NOT NULL DEFAULT '',
+  `transaction_id` varchar(64) NOT NULL,
+  `status` varchar(10) NOT NULL,
+  `source` varchar(56) NOT NULL,
+  `submitted_at` datetime NOT NULL,
+  `succeeded_at` datetime DEFAULT NULL,
+  `ledger` bigint(20) DEFAULT NULL,
+  `envelope_xdr` text NOT NULL,
+  `result_xdr` varchar(255) DEFAULT NULL,
+  `bad_seq_resolution` varchar(16) NULL DEFAULT NULL,
+  `metadata` text NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `payment_id` (`payment_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- Payment ID is unique across regions again, transactions of other regions
+-- with the same payment ID are moved to SentTransactionConflict
+INSERT INTO `SentTransactionConflict` (`id`, `payment_id`, `region`, `transaction_id`, `status`, `source`, `submitted_at`, `succeeded_at`, `ledger`, `envelope_xdr`, `result_xdr`, `bad_seq_resolution`, `metadata`)
+  SELECT t.`id`, t.`payment_id`, t.`region`, t.`transaction_id`, t.`status`, t.`source`, t.`submitted_at`, t.`succeeded_at`, t.`ledger`, t.`envelope_xdr`, t.`result_xdr`, t.`bad_seq_resolution`, t.`metadata`
+  FROM `SentTransaction` t
+  WHERE EXISTS (SELECT 1 FROM `SentTransaction` o WHERE o.`payment_id` = t.`payment_id` AND o.`id` < t.`id`);
+
+DELETE FROM `SentTransaction` WHERE `id` IN (SELECT `id` FROM `SentTransactionConflict`);
+
+ALTER TABLE `SentTransaction` DROP INDEX `region_payment_id`, ADD UNIQUE (`payment_id`);
+
+-- +migrate Down
+ALTER TABLE `SentTransaction` DROP INDEX `payment_id`, ADD UNIQUE `region_payment_id` (`region`, `payment_id`);
+
+INSERT INTO `SentTransaction` (`id`, `payment_id`, `region`, `transaction_id`, `status`, `source`, `submitted_at`, `succeeded_at`, `ledger`, `envelope_xdr`, `result_xdr`, `bad_seq_resolution`, `metadata`)
+  SELECT `id`, `payment_id`, `region`, `transaction_id`, `status`, `source`, `submitted_at`, `succeeded_at`, `ledger`, `envelope_xdr`, `result_xdr`, `bad_seq_resolution`, `metadata` FROM `SentTransactionConflict`;
+
+DROP TABLE `SentTransactionConflict`;
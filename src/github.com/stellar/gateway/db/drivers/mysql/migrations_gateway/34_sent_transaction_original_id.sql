@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `SentTransaction` ADD `original_id` int(11) NULL DEFAULT NULL AFTER `payment_id`;
+ALTER TABLE `SentTransactionConflict` ADD `original_id` int(11) NULL DEFAULT NULL AFTER `payment_id`;
+
+-- +migrate Down
+ALTER TABLE `SentTransaction` DROP `original_id`;
+ALTER TABLE `SentTransactionConflict` DROP `original_id`;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE `APIKey` ADD `tenant` varchar(64) NOT NULL DEFAULT '' AFTER `sources`;
+
+-- +migrate Down
+ALTER TABLE `APIKey` DROP `tenant`;
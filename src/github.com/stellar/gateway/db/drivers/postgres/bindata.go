@@ -3,7 +3,44 @@
 // migrations_gateway/01_init.sql
 // migrations_gateway/02_payment_id.sql
 // migrations_gateway/03_transaction_id.sql
+// migrations_gateway/04_held_payment.sql
+// migrations_gateway/05_compliance_repair.sql
+// migrations_gateway/06_sent_transaction_region.sql
+// migrations_gateway/07_bad_seq_resolution.sql
+// migrations_gateway/08_memo_required_destination.sql
+// migrations_gateway/09_feature_flag.sql
+// migrations_gateway/10_security_event.sql
+// migrations_gateway/11_api_key.sql
+// migrations_gateway/12_destination_profile.sql
+// migrations_gateway/13_daily_aggregate.sql
+// migrations_gateway/14_federation_snapshot.sql
+// migrations_gateway/15_received_payment_event.sql
+// migrations_gateway/16_payment_limit_usage.sql
+// migrations_gateway/17_named_destination.sql
+// migrations_gateway/18_held_payment_approval.sql
+// migrations_gateway/19_payment_velocity.sql
+// migrations_gateway/20_expected_payment.sql
+// migrations_gateway/21_refund.sql
+// migrations_gateway/22_failed_callback.sql
+// migrations_gateway/23_offline_transaction.sql
+// migrations_gateway/24_quote.sql
+// migrations_gateway/25_fiat_value.sql
+// migrations_gateway/26_webhook.sql
+// migrations_gateway/27_webhook_format.sql
+// migrations_gateway/28_memo_reference.sql
+// migrations_gateway/29_sent_transaction_metadata.sql
+// migrations_gateway/30_leader_lease.sql
+// migrations_gateway/31_sep24_transaction.sql
+// migrations_gateway/32_held_payment_seed.sql
+// migrations_gateway/33_sent_transaction_conflict.sql
+// migrations_gateway/34_sent_transaction_original_id.sql
+// migrations_gateway/35_api_key_tenant.sql
 // migrations_compliance/01_init.sql
+// migrations_compliance/02_sent_attachment.sql
+// migrations_compliance/03_federation_snapshot.sql
+// migrations_compliance/04_sanctions_screening.sql
+// migrations_compliance/05_compliance_audit.sql
+// migrations_compliance/06_attachment.sql
 // DO NOT EDIT!
 
 package postgres
@@ -71,34 +108,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _migrations_gateway01_initSql = []byte{
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x7c, 0x92,
-	0x41, 0x4f, 0xc3, 0x30, 0x0c, 0x85, 0xef, 0xf9, 0x15, 0x3e, 0x6e, 0x82,
-	0x49, 0x80, 0xd8, 0x2e, 0x3b, 0x15, 0x16, 0xa4, 0x89, 0xb2, 0x8d, 0xd2,
-	0x1e, 0x76, 0xaa, 0xb2, 0xc4, 0x2a, 0x11, 0x6d, 0x52, 0x25, 0xee, 0x18,
-	0xff, 0x9e, 0x0c, 0x09, 0xd6, 0x86, 0xc1, 0x31, 0xfa, 0x5e, 0x6c, 0x3f,
-	0x3f, 0x4f, 0x26, 0x70, 0xd1, 0xe8, 0xca, 0x09, 0x42, 0x28, 0x5a, 0x76,
-	0x9f, 0xf1, 0x24, 0xe7, 0x90, 0x27, 0x77, 0x29, 0x87, 0x0c, 0x25, 0xea,
-	0x3d, 0xaa, 0x8d, 0xf8, 0x68, 0xd0, 0x10, 0x8c, 0x18, 0x80, 0x56, 0xb0,
-	0xd3, 0x95, 0x47, 0xa7, 0x45, 0x7d, 0x19, 0xde, 0xb6, 0xc5, 0xf0, 0x57,
-	0x5b, 0x53, 0x06, 0xb2, 0x17, 0x4e, 0xbe, 0x0a, 0x37, 0xba, 0x99, 0x4e,
-	0xc7, 0x50, 0xac, 0x96, 0xcf, 0x05, 0x87, 0xd5, 0x3a, 0x87, 0x55, 0x91,
-	0xa6, 0x47, 0x71, 0xeb, 0xac, 0x44, 0xef, 0x51, 0x95, 0x82, 0x80, 0x74,
-	0x83, 0x9e, 0x44, 0xd3, 0x0e, 0x25, 0xa2, 0xd2, 0xa6, 0x2a, 0xc9, 0xbe,
-	0xa1, 0x19, 0xd6, 0xeb, 0xab, 0xc2, 0x3f, 0xea, 0xfc, 0xdf, 0x7c, 0x93,
-	0x2d, 0x9f, 0x92, 0x6c, 0x0b, 0x8f, 0x7c, 0x0b, 0x23, 0xad, 0xc6, 0x6c,
-	0x3c, 0x67, 0x43, 0x6f, 0x2f, 0xc1, 0x50, 0xee, 0x84, 0xf1, 0x42, 0x1e,
-	0xa7, 0xff, 0xf6, 0x76, 0x32, 0x46, 0x27, 0xd8, 0xb7, 0x36, 0xbb, 0xed,
-	0x75, 0x82, 0xdf, 0xa3, 0x5c, 0x5f, 0x45, 0x93, 0xda, 0xce, 0x49, 0xfc,
-	0xc1, 0xd3, 0x59, 0x84, 0xbb, 0x5d, 0xa3, 0x89, 0xfe, 0xdb, 0x88, 0xef,
-	0xa4, 0x44, 0x54, 0xb1, 0x64, 0xc1, 0x1f, 0x92, 0x22, 0x3d, 0xc9, 0x6a,
-	0x54, 0x15, 0xba, 0x63, 0x38, 0x3a, 0x44, 0x15, 0x53, 0x34, 0x7b, 0xac,
-	0x43, 0x56, 0xe5, 0x41, 0x39, 0x20, 0x3c, 0xd0, 0xa0, 0x85, 0x43, 0xdf,
-	0xd5, 0xf4, 0xc5, 0x06, 0x2b, 0x8d, 0xab, 0x9c, 0x5d, 0x6b, 0xff, 0x82,
-	0x16, 0xf6, 0xdd, 0xb0, 0x45, 0xb6, 0xde, 0x9c, 0xbf, 0xa0, 0x79, 0x9f,
-	0x45, 0x09, 0xcc, 0xd9, 0x67, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4d, 0x61,
-	0x55, 0x6b, 0x8b, 0x02, 0x00, 0x00,
-}
+var _migrations_gateway01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x92\xcf\x4f\xfa\x40\x10\xc5\xef\xfb\x57\xcc\x11\xf2\xfd\x92\xa8\x11\x2e\x9c\xaa\xac\x09\xb1\x02\xd6\xf6\xc0\xa9\x59\x76\x27\x75\x62\xbb\xdb\xec\x4e\x11\xff\x7b\x03\x09\xf6\x07\xe8\xf9\xf3\x32\xf3\xde\xbc\x99\x4c\xe0\x5f\x45\x85\x57\x8c\x90\xd5\xe2\x31\x91\x51\x2a\x21\x8d\x1e\x62\x09\x09\x6a\xa4\x3d\x9a\x8d\xfa\xaa\xd0\x32\x8c\x04\x00\x19\xd8\x51\x11\xd0\x93\x2a\xff\x0b\x00\x57\xa3\x57\x4c\xce\xe6\x64\x60\xaf\xbc\x7e\x57\x7e\x74\x37\x9d\x8e\x21\x5b\x2d\x5f\x33\x09\xab\x75\x0a\xab\x2c\x8e\x8f\xe2\xda\x3b\x8d\x21\xa0\xc9\x15\x03\x53\x85\x81\x55\x55\xf7\x25\xaa\x20\x5b\xe4\xec\x3e\xd0\xf6\xe7\x75\x55\x81\x15\x37\xe1\x77\xbe\x49\x96\x2f\x51\xb2\x85\x67\xb9\x85\x11\x99\xb1\x18\xcf\x45\x3f\xdb\x1b\x5a\x4e\xbd\xb2\x41\xe9\xa3\xfb\x73\xb6\x36\x18\xb7\xb0\x1b\x6d\x76\xdf\xd9\x04\x97\x56\x6e\x6f\xfa\x4e\x82\x6b\xbc\xc6\x1f\x3c\x9d\x0d\x70\xb3\xab\x88\xf9\xaf\x8b\x84\x46\x6b\x44\x33\x94\x2c\xe4\x53\x94\xc5\xad\xac\x44\x53\xa0\x3f\x96\x43\x96\x2f\x28\xda\x3d\x96\xae\xc6\xfc\x60\x3c\x30\x1e\xb8\xb7\xc2\x63\x68\x4a\x3e\xb1\xb3\xd1\x53\x85\xc3\x29\x57\xcf\xda\xfd\xa0\x85\xfb\xb4\x62\x91\xac\x37\xd7\x3f\x68\xde\x65\x83\x06\xe6\xe2\x7b\x00\x4d\x61\x55\x6b\x8b\x02\x00\x00")
 
 func migrations_gateway01_initSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -113,27 +123,12 @@ func migrations_gateway01_initSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations_gateway/01_init.sql", size: 651, mode: os.FileMode(420), modTime: time.Unix(1476130062, 0)}
+	info := bindataFileInfo{name: "migrations_gateway/01_init.sql", size: 651, mode: os.FileMode(436), modTime: time.Unix(1523031737, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations_gateway02_payment_idSql = []byte{
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xd2, 0xd5,
-	0x55, 0xd0, 0xce, 0xcd, 0x4c, 0x2f, 0x4a, 0x2c, 0x49, 0x55, 0x08, 0x2d,
-	0xe0, 0x72, 0xf4, 0x09, 0x71, 0x0d, 0x52, 0x08, 0x71, 0x74, 0xf2, 0x71,
-	0x55, 0x08, 0x4e, 0xcd, 0x2b, 0x09, 0x29, 0x4a, 0xcc, 0x2b, 0x4e, 0x4c,
-	0x2e, 0xc9, 0xcc, 0xcf, 0x53, 0x70, 0x74, 0x71, 0x51, 0x28, 0x48, 0xac,
-	0xcc, 0x05, 0x0a, 0xc7, 0x67, 0xa6, 0x28, 0x84, 0x39, 0x06, 0x39, 0x7b,
-	0x38, 0x06, 0x69, 0x18, 0x99, 0x9a, 0x6a, 0x2a, 0xf8, 0x85, 0xfa, 0xf8,
-	0x28, 0xb8, 0xb8, 0xba, 0x39, 0x86, 0xfa, 0x84, 0x80, 0x39, 0xd6, 0x04,
-	0x8d, 0x72, 0xf6, 0xf7, 0x0b, 0x0e, 0x09, 0x72, 0xf4, 0xf4, 0x0b, 0x41,
-	0x32, 0x35, 0xbe, 0x34, 0x2f, 0xb3, 0xb0, 0x14, 0xe8, 0x14, 0x3f, 0xcf,
-	0xc0, 0x50, 0x57, 0x05, 0x0d, 0x84, 0x8c, 0xa6, 0x35, 0x17, 0x17, 0xb2,
-	0x63, 0x5d, 0xf2, 0xcb, 0xf3, 0xf0, 0xda, 0xe1, 0x12, 0xe4, 0x1f, 0x80,
-	0x64, 0xb2, 0x35, 0x17, 0x20, 0x00, 0x00, 0xff, 0xff, 0xd0, 0xf6, 0xe1,
-	0x10, 0xeb, 0x00, 0x00, 0x00,
-}
+var _migrations_gateway02_payment_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\x31\x0b\xc2\x30\x10\x05\xe0\x3d\xbf\xe2\xc6\x16\xe9\x22\x74\xea\x74\x36\x11\x0b\x21\xd5\xf4\xe2\x5a\x82\x06\xc9\xd0\x6b\xad\x29\xe2\xbf\x17\x5c\xec\xa4\xe3\xe3\xc1\xf7\x5e\x51\xc0\x66\x88\xb7\xd9\xa7\x00\x6e\x12\xa8\x49\x59\x20\xdc\x69\x05\x5d\xe0\x44\xb3\xe7\x87\xbf\xa4\x38\x32\xa0\x94\x30\xf9\xd7\x10\x38\xf5\xf1\x0a\x67\xb4\xf5\x01\x6d\xb6\x2d\xcb\x1c\x8c\xd3\x1a\xa4\xda\xa3\xd3\xf4\x09\xd5\x5f\xaa\x6e\x4d\x47\x16\x1b\x43\x2b\xb5\x5f\x38\xde\x97\x00\xce\x34\x27\xa7\x20\xfb\x36\x79\x25\xc4\xfa\xac\x1c\x9f\xfc\x73\x43\xda\xf6\xb8\x92\x2b\xf1\x1e\x00\xd0\xf6\xe1\x10\xeb\x00\x00\x00")
 
 func migrations_gateway02_payment_idSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -148,26 +143,12 @@ func migrations_gateway02_payment_idSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations_gateway/02_payment_id.sql", size: 235, mode: os.FileMode(420), modTime: time.Unix(1516147186, 0)}
+	info := bindataFileInfo{name: "migrations_gateway/02_payment_id.sql", size: 235, mode: os.FileMode(436), modTime: time.Unix(1523031737, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations_gateway03_transaction_idSql = []byte{
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xd2, 0xd5,
-	0x55, 0xd0, 0xce, 0xcd, 0x4c, 0x2f, 0x4a, 0x2c, 0x49, 0x55, 0x08, 0x2d,
-	0xe0, 0x72, 0xf4, 0x09, 0x71, 0x0d, 0x52, 0x08, 0x71, 0x74, 0xf2, 0x71,
-	0x55, 0x08, 0x4a, 0x4d, 0x4e, 0xcd, 0x2c, 0x4b, 0x4d, 0x09, 0x48, 0xac,
-	0xcc, 0x4d, 0xcd, 0x2b, 0x51, 0x70, 0x74, 0x71, 0x51, 0x28, 0x29, 0x4a,
-	0xcc, 0x2b, 0x4e, 0x4c, 0x2e, 0xc9, 0xcc, 0xcf, 0x8b, 0xcf, 0x4c, 0x51,
-	0x08, 0x73, 0x0c, 0x72, 0xf6, 0x70, 0x0c, 0xd2, 0x30, 0x33, 0xd1, 0x54,
-	0x70, 0x71, 0x75, 0x73, 0x0c, 0xf5, 0x09, 0x51, 0x50, 0xf7, 0xd3, 0x77,
-	0x54, 0xb7, 0x26, 0x68, 0x52, 0x6e, 0x6a, 0x6e, 0x3e, 0x85, 0x46, 0x20,
-	0x3b, 0xa6, 0x2c, 0x31, 0xa7, 0x34, 0x15, 0x9f, 0x61, 0x5c, 0xc8, 0x3e,
-	0x75, 0xc9, 0x2f, 0xcf, 0xc3, 0x6b, 0xbc, 0x4b, 0x90, 0x7f, 0x00, 0x9a,
-	0x67, 0xad, 0xb9, 0x00, 0x01, 0x00, 0x00, 0xff, 0xff, 0xca, 0xba, 0x27,
-	0xa4, 0x2c, 0x01, 0x00, 0x00,
-}
+var _migrations_gateway03_transaction_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x08\x4a\x4d\x4e\xcd\x2c\x4b\x4d\x09\x48\xac\xcc\x4d\xcd\x2b\x51\x70\x74\x71\x51\x28\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x8b\xcf\x4c\x51\x08\x73\x0c\x72\xf6\x70\x0c\xd2\x30\x33\xd1\x54\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\xf7\xd3\x77\x54\xb7\x26\x68\x52\x6e\x6a\x6e\x3e\x85\x46\x20\x3b\xa6\x2c\x31\xa7\x34\x15\x9f\x61\x5c\xc8\x3e\x75\xc9\x2f\xcf\xc3\x6b\xbc\x4b\x90\x7f\x00\x9a\x67\xad\xb9\x00\x03\x00\xca\xba\x27\xa4\x2c\x01\x00\x00")
 
 func migrations_gateway03_transaction_idSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -182,43 +163,653 @@ func migrations_gateway03_transaction_idSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations_gateway/03_transaction_id.sql", size: 156, mode: os.FileMode(420), modTime: time.Unix(1516147543, 0)}
+	info := bindataFileInfo{name: "migrations_gateway/03_transaction_id.sql", size: 300, mode: os.FileMode(436), modTime: time.Unix(1523031737, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway04_held_paymentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\x4f\x4b\xc3\x40\x10\xc5\xef\xfb\x29\xe6\xd8\x60\x0b\x2a\xd4\x4b\x4f\xd1\xac\x18\x8c\x49\x0c\x09\xd8\x53\x18\x77\x87\x76\x61\xf3\xc7\xdd\x89\x7f\xbe\xbd\x94\xd8\xc6\x54\x3c\xbf\xdf\x1b\xde\xbc\xb7\x5a\xc1\x45\x63\x76\x0e\x99\xa0\xea\xc5\x5d\x21\xc3\x52\x42\x19\xde\x26\x12\x1e\xc8\xea\x1c\xbf\x1a\x6a\x19\x16\x02\xc0\x68\x78\x35\x3b\x4f\xce\xa0\x5d\x0a\x80\x7e\xd4\x6a\xa3\xe1\x1d\x9d\xda\xa3\x5b\x5c\xaf\xd7\x01\x54\x69\xfc\x5c\x49\x48\xb3\x12\xd2\x2a\x49\x0e\xa8\xef\x06\xa7\xa8\x46\xa5\xba\xa1\xe5\x13\xbe\xbe\x09\x66\x98\xa3\xb7\x81\x3c\x03\xd3\x27\xcf\xfd\x8c\x3c\xf8\x93\xef\xea\x72\xee\x53\x8e\x90\x49\xd7\xc8\xc0\xa6\x21\xcf\xd8\xf4\x73\x3f\x31\x5b\xfa\x5f\x57\xd8\x2a\xb2\xf6\xfc\x44\x24\xef\xc3\x2a\x99\x30\x47\x7e\xb0\x3f\xf1\xce\xb5\xbc\x88\x9f\xc2\x62\x0b\x8f\x72\x0b\x0b\xa3\x03\x11\x6c\xc4\xb1\xcf\x38\x8d\xe4\x0b\xec\xc9\xea\xfa\x58\xda\xf8\x51\x3d\x05\xcb\xd2\x79\xe1\x23\xb0\x9c\xa2\x1f\xee\xfd\x9e\x2b\xea\x3e\x5a\x11\x15\x59\xfe\x77\xae\x8d\xf8\x1e\x00\x10\x46\x0c\x5d\xd8\x01\x00\x00")
+
+func migrations_gateway04_held_paymentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway04_held_paymentSql,
+		"migrations_gateway/04_held_payment.sql",
+	)
+}
+
+func migrations_gateway04_held_paymentSql() (*asset, error) {
+	bytes, err := migrations_gateway04_held_paymentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/04_held_payment.sql", size: 472, mode: os.FileMode(420), modTime: time.Unix(1792005019, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway05_compliance_repairSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\xbd\x6a\xeb\x40\x10\x85\xfb\x7d\x8a\x29\x25\xee\x75\x13\x70\xe5\x4a\xb1\x54\x98\x28\x92\x11\x32\xc4\xd5\x32\xd6\x4e\x94\x01\xed\x0f\xb3\x1b\x27\x79\xfb\x60\x43\x82\x1c\x22\xd2\x0e\x1f\x67\x38\xdf\x59\xad\xe0\x9f\xe5\x51\x30\x11\x1c\x82\xda\x76\x55\xd1\x57\xd0\x17\xf7\x75\x05\x5b\x6f\xc3\xc4\xe8\x06\xea\x28\x20\x0b\x64\x0a\x80\x0d\x9c\x78\x8c\x24\x8c\xd3\x7f\x05\xe0\x03\x09\x26\xf6\x4e\xb3\x81\x33\xca\xf0\x82\x92\xdd\xad\xd7\x39\x34\x6d\x0f\xcd\xa1\xae\xaf\x94\xf0\xc8\x0e\x27\x6d\xc9\xfa\x65\x6c\xf8\xfe\xf8\x07\x78\x26\xe1\x67\x26\xa3\x4f\x1f\xcb\x90\x10\x46\xef\x20\xd1\x7b\xfa\x71\xbf\xb4\x21\xa3\x31\x41\x62\x4b\x31\xa1\x0d\x37\x44\x4c\x98\x5e\xe3\x72\xf2\xbe\xdb\x3d\x16\xdd\x11\x1e\xaa\x23\x64\x6c\x72\x95\x6f\xd4\x97\xbb\x5d\x53\x56\x4f\xf3\x26\x72\x95\xa7\x6f\x44\xb5\xcd\x2f\x76\xe7\xc4\x25\x70\xbe\x4d\xe9\xdf\x9c\x2a\xbb\x76\xbf\xb0\xcd\x46\x7d\x0e\x00\x3f\xc3\x23\x78\xca\x01\x00\x00")
+
+func migrations_gateway05_compliance_repairSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway05_compliance_repairSql,
+		"migrations_gateway/05_compliance_repair.sql",
+	)
+}
+
+func migrations_gateway05_compliance_repairSql() (*asset, error) {
+	bytes, err := migrations_gateway05_compliance_repairSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/05_compliance_repair.sql", size: 458, mode: os.FileMode(420), modTime: time.Unix(1792005241, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway06_sent_transaction_regionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x90\xb1\xaa\x83\x30\x14\x86\xf7\x3c\xc5\xd9\x54\xee\x75\xbb\xdc\xc5\x29\x35\x29\x15\x42\x6c\x63\xd2\x55\x42\x1b\x24\x83\xd1\xa6\x91\xd2\xb7\x2f\xd6\xa1\x42\x51\x70\x0b\xe4\xfc\xdf\xff\x9d\x93\xa6\xf0\xd3\xda\xc6\xeb\x60\x40\xf5\x08\x33\x49\x05\x48\xbc\x63\x14\x2a\xe3\x82\xf4\xda\xdd\xf5\x25\xd8\xce\x01\x26\x04\xbc\x69\xc6\xe7\x19\x8b\xfc\x80\x45\xfc\xff\x97\x00\x2f\x25\x70\xc5\x18\x10\xba\xc7\x8a\x49\x88\xa2\x6c\x95\x43\x44\x79\x84\xbc\xe4\x95\x14\xb8\xe0\x12\x7a\xfd\x6c\x8d\x0b\xb5\xbd\xd6\x83\xb3\xb7\xc1\xac\xc7\x47\x8d\x59\x7a\x32\xaa\xbf\x20\xa0\x78\x71\x52\x14\xe2\x69\xe0\x77\x56\x93\x64\x08\xcd\xf7\x26\xdd\xc3\x6d\x32\x5e\xea\xdc\x24\xbe\x6c\xfc\xf9\x49\xd6\x89\xef\x4b\x7a\xd3\xd8\xce\x65\xe8\x35\x00\x9e\x39\x8d\xd9\xca\x01\x00\x00")
+
+func migrations_gateway06_sent_transaction_regionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway06_sent_transaction_regionSql,
+		"migrations_gateway/06_sent_transaction_region.sql",
+	)
+}
+
+func migrations_gateway06_sent_transaction_regionSql() (*asset, error) {
+	bytes, err := migrations_gateway06_sent_transaction_regionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/06_sent_transaction_region.sql", size: 458, mode: os.FileMode(420), modTime: time.Unix(1792005626, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway07_bad_seq_resolutionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd2\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x53\x70\x74\x71\x51\x48\x4a\x4c\x89\x2f\x4e\x2d\x8c\x2f\x4a\x2d\xce\xcf\x29\x05\x0b\x87\x39\x06\x39\x7b\x38\x06\x69\x18\x9a\x69\x2a\xf8\x85\xfa\xf8\x28\xb8\xb8\xba\x39\x86\xfa\x84\x80\x39\xd6\x5c\x5c\xc8\x36\xb8\xe4\x97\xe7\xe1\xb5\xc3\x25\xc8\x3f\x00\x8b\x25\xd6\x5c\x80\x01\x00\x1c\xd2\x94\x31\xa8\x00\x00\x00")
+
+func migrations_gateway07_bad_seq_resolutionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway07_bad_seq_resolutionSql,
+		"migrations_gateway/07_bad_seq_resolution.sql",
+	)
+}
+
+func migrations_gateway07_bad_seq_resolutionSql() (*asset, error) {
+	bytes, err := migrations_gateway07_bad_seq_resolutionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/07_bad_seq_resolution.sql", size: 168, mode: os.FileMode(420), modTime: time.Unix(1792006117, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway08_memo_required_destinationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x90\xc1\x4e\xc3\x30\x10\x44\xef\xfe\x8a\x3d\x26\x82\x1e\x90\xa0\x97\x9e\x02\x31\x52\x44\x9a\x94\x28\x91\xe8\xc9\x5a\xec\x55\xb1\x84\xed\x62\x6f\x80\xcf\x47\xa9\x80\xb4\x48\xf4\xba\x33\x3b\xa3\x37\x8b\x05\x5c\x38\xbb\x8b\xc8\x04\xc3\x5e\xdc\x75\xb2\xe8\x25\xf4\xc5\x6d\x2d\x61\x4d\x2e\x74\xf4\x36\xda\x48\xa6\xa4\xc4\xd6\x23\xdb\xe0\x21\x13\x00\xd6\xc0\xb3\xdd\x25\x8a\x16\x5f\x2f\x05\x00\x6a\x1d\x46\xcf\xca\x1a\x78\xc7\xa8\x5f\x30\x66\x37\xcb\x1c\x9a\xb6\x87\x66\xa8\xeb\xc9\x92\xc2\x18\x35\xfd\xca\x57\x7f\xe4\x48\x98\x82\x07\xa6\x4f\x3e\xb9\x73\x44\x9f\x50\x4f\xcd\x53\xfa\xe1\x77\x79\x9d\x1f\x74\x28\xe5\x7d\x31\xd4\xb3\x59\x47\x42\x26\xa3\x90\x81\xad\xa3\xc4\xe8\xf6\x27\x69\x9b\xae\x5a\x17\xdd\x16\x1e\xe4\x16\x32\x6b\x72\x91\xaf\xc4\x0f\xf5\xd0\x54\x8f\x83\x84\xaa\x29\xe5\x13\x38\x72\x41\xc5\x6f\x7a\x65\x66\x7c\x75\x84\xda\x36\xff\x8f\x34\xdb\xa6\x8a\xe3\x9d\xcb\xf0\xe1\x45\xd9\xb5\x9b\xf3\x3b\xaf\xc4\xd7\x00\x18\x55\x53\x12\x9d\x01\x00\x00")
+
+func migrations_gateway08_memo_required_destinationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway08_memo_required_destinationSql,
+		"migrations_gateway/08_memo_required_destination.sql",
+	)
+}
+
+func migrations_gateway08_memo_required_destinationSql() (*asset, error) {
+	bytes, err := migrations_gateway08_memo_required_destinationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/08_memo_required_destination.sql", size: 413, mode: os.FileMode(420), modTime: time.Unix(1792029806, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway09_feature_flagSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x90\x41\x4f\x83\x30\x18\x86\xef\xfd\x15\xef\x11\xe2\x76\x31\xce\xcb\x4e\x28\x5d\x42\xc4\x32\x09\x24\xee\x44\x3e\xec\x37\x6c\x52\x0a\xe9\x3a\x8d\xff\xde\x10\xd1\x48\x74\xe7\xe7\x6d\xf3\x3d\xcf\x7a\x8d\xab\xde\x74\x9e\x02\xa3\x1e\xc5\x7d\x29\x93\x4a\xa2\x4a\xee\x72\x89\x1d\x53\x38\x7b\xde\x59\xea\x10\x09\xc0\x68\xb4\xa6\x3b\xb1\x37\x64\x57\x02\x70\xd4\x33\xde\xc8\xbf\xbc\x92\x8f\x6e\x6f\x62\xa8\xa2\x82\xaa\xf3\x7c\x82\x81\x1d\xb9\x70\x11\xb3\xa3\xd6\xb2\x46\x3b\x0c\x96\xc9\x2d\xd8\x79\xd4\x14\x58\x37\xed\xc7\xcf\xf3\xeb\xcd\x26\xfe\x77\x43\x01\xc1\xf4\x7c\x0a\xd4\x8f\x8b\xc1\xbe\xcc\x1e\x93\xf2\x80\x07\x79\x40\x64\x74\x2c\xe2\xad\xf8\xd6\xab\x55\xf6\x54\x4b\x64\x2a\x95\xcf\x38\x7e\x59\x36\x47\x4b\x5d\x33\x29\x35\xf3\xe9\x85\x5a\x16\x98\xd8\x6a\xf6\x9a\x3e\xfb\x9d\x2e\x1d\xde\x9d\x48\xcb\x62\xff\x37\xdd\x56\x7c\x0e\x00\x34\x6d\xed\x9a\x64\x01\x00\x00")
+
+func migrations_gateway09_feature_flagSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway09_feature_flagSql,
+		"migrations_gateway/09_feature_flag.sql",
+	)
+}
+
+func migrations_gateway09_feature_flagSql() (*asset, error) {
+	bytes, err := migrations_gateway09_feature_flagSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/09_feature_flag.sql", size: 356, mode: os.FileMode(420), modTime: time.Unix(1792030079, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway10_security_eventSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\x4f\x4b\xc3\x40\x10\xc5\xef\xfb\x29\xe6\xd8\x62\x0b\xa2\x22\x42\x4f\xd1\xac\x50\x8c\x69\x89\x29\xd8\xd3\x32\x66\xc7\xb8\x90\x7f\x4c\xc6\xd8\x7c\x7b\x09\x6d\x42\x12\xf4\xfc\xfb\xb1\x6f\xf6\xbd\xf5\x1a\xae\x72\x97\x32\x0a\xc1\xa1\x52\x4f\x91\xf6\x62\x0d\xb1\xf7\x18\x68\x78\xa3\xe4\x9b\x9d\xb4\xba\xa1\x42\x60\xa1\x00\x9c\x85\x0f\x97\xd6\xc4\x0e\xb3\x95\x02\xa0\x8e\x18\x67\xa1\x41\x4e\xbe\x90\x17\xf7\x77\x4b\x08\x77\x31\x84\x87\x20\xe8\x04\x69\x2b\x1a\xe0\xed\xcd\x14\x7e\x96\x9c\xa3\x0c\xf8\x61\x4a\x2b\x6c\xb3\x12\x2d\x08\x9d\x64\x02\x12\x26\x14\xb2\x06\x05\xc4\xe5\x54\x0b\xe6\xd5\x44\x40\x11\xca\x2b\xa9\xc1\x15\x42\x29\xf1\x00\xc1\xd7\xcf\xde\x21\x88\xe1\xba\x0b\x28\xe8\x24\xe6\xe2\xfe\xff\x98\xa5\xcc\x35\xc4\xf3\xbc\xfe\xa5\x5e\xcb\xb0\x16\x43\xcc\x25\x9f\x0f\x9e\xf3\x7d\xb4\x7d\xf5\xa2\x23\xbc\xe8\x23\x2c\x9c\x5d\xaa\xe5\x46\xf5\x6d\x6f\x43\x5f\xbf\x43\x7d\x69\xdb\x9c\x4b\x1d\xe7\x9a\xf9\xa9\xbb\x70\x3e\xce\x58\x5f\xcd\xbf\xd6\x65\x8d\x87\xf6\xcb\x9f\x42\xf9\xd1\x6e\xff\xd7\xd0\x1b\xf5\x3b\x00\x87\x4e\x04\x79\x14\x02\x00\x00")
+
+func migrations_gateway10_security_eventSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway10_security_eventSql,
+		"migrations_gateway/10_security_event.sql",
+	)
+}
+
+func migrations_gateway10_security_eventSql() (*asset, error) {
+	bytes, err := migrations_gateway10_security_eventSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/10_security_event.sql", size: 532, mode: os.FileMode(420), modTime: time.Unix(1792031588, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway11_api_keySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x90\x4d\x4f\x83\x40\x14\x45\xf7\xf3\x2b\xee\xb2\x8d\x36\x31\xc6\xba\xe9\x0a\x65\x4c\x48\x91\x56\x02\x89\x5d\x91\x57\x78\xe2\x8b\x7c\xd4\x99\x57\xb5\xff\xde\xe0\x07\x91\xb8\xbd\xe7\xdc\xcd\x59\x2c\x70\xd6\x4a\xed\x48\x19\xf9\xc1\xdc\xa6\x36\xc8\x2c\xb2\xe0\x26\xb6\x08\xb6\xd1\x9a\x4f\x98\x19\x40\x2a\xec\xa5\xf6\xec\x84\x9a\x73\x03\xbc\xf0\xa9\x90\x0a\x6f\xe4\xca\x67\x72\xb3\xeb\xab\x39\x92\x4d\x86\x24\x8f\xe3\x01\x7b\x2e\x1d\xeb\x88\x2f\x97\xcb\x29\x77\xfc\x7a\x14\xc7\x85\x97\xba\x23\x3d\x3a\xc6\xbe\xef\x1b\xa6\x6e\xb4\x10\xda\xbb\x20\x8f\x33\x3c\x51\xe3\xf9\xeb\x43\xca\x45\x23\xad\x28\xa4\x53\xae\xd9\xfd\x97\x2f\x06\xd1\xf7\x47\x57\xb2\x87\xf2\x87\x8e\xca\x00\x4a\xc7\xa4\x5c\x15\xa4\x50\x69\xd9\x2b\xb5\x87\x89\xb0\x4d\xa3\xfb\x20\xdd\x61\x6d\x77\x98\x49\x35\x37\xf3\x95\xf9\x4d\x92\x27\xd1\x43\x6e\x11\x25\xa1\x7d\x04\x1d\xa4\x18\x12\xfc\x64\xd8\x24\x63\xab\xef\x65\xf8\xfd\x2d\x1b\xf6\xef\x9d\x09\xd3\xcd\x76\x52\x76\x65\x3e\x07\x00\x02\x78\x22\x8b\x7e\x01\x00\x00")
+
+func migrations_gateway11_api_keySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway11_api_keySql,
+		"migrations_gateway/11_api_key.sql",
+	)
+}
+
+func migrations_gateway11_api_keySql() (*asset, error) {
+	bytes, err := migrations_gateway11_api_keySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/11_api_key.sql", size: 382, mode: os.FileMode(420), modTime: time.Unix(1792034520, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway12_destination_profileSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xcc\x31\xae\xc2\x30\x0c\x00\xd0\x3d\xa7\xf0\xf8\xbf\x50\x37\xc4\xd2\x29\xe0\x30\xb9\x2d\x8a\x92\x03\x44\xe0\x82\xa5\x36\x09\x26\xc0\xf5\x59\x19\xb9\xc0\xeb\x3a\xd8\xac\x72\xd5\xd4\x18\x62\x35\x96\x82\xf3\x10\xec\x9e\x1c\x0c\xbc\x16\xcf\xf7\xa7\x28\x5f\x90\x1f\x4d\x72\x6a\x52\x32\x58\x44\xa8\x5a\x66\x59\x18\x5e\x49\xcf\xb7\xa4\x7f\xbb\xed\x3f\xa0\x3b\xda\x48\x01\xc6\x48\xd4\x1b\xf3\x2d\x63\x79\xe7\x9f\x6c\xf4\xd3\x09\x0e\x13\xc5\x61\x84\xaa\x65\x96\x85\x7b\xf3\x19\x00\x97\xc3\x6e\x82\xa4\x00\x00\x00")
+
+func migrations_gateway12_destination_profileSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway12_destination_profileSql,
+		"migrations_gateway/12_destination_profile.sql",
+	)
+}
+
+func migrations_gateway12_destination_profileSql() (*asset, error) {
+	bytes, err := migrations_gateway12_destination_profileSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/12_destination_profile.sql", size: 164, mode: os.FileMode(420), modTime: time.Unix(1792034791, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway13_daily_aggregateSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\x51\x4b\xc3\x30\x10\xc7\xdf\xf3\x29\xee\x6d\x2b\x76\x30\x45\xf7\xb2\xa7\x6a\x23\x14\x6b\x3a\x4b\x03\xee\x69\x9c\x4d\x88\x07\x5b\x2a\x49\xa6\xf4\xdb\x4b\x74\x5d\x85\x6d\xf8\x76\xf0\xff\x71\xc7\xff\x77\xb3\x19\x5c\xed\xc8\x38\x0c\x1a\xe4\x07\x7b\xa8\x79\xd6\x70\x68\xb2\xfb\x92\x43\x8e\xb4\xed\x33\x63\x9c\x36\x31\x9e\x32\x00\x52\xf0\x46\xc6\x6b\x47\xb8\x4d\x19\x80\xc2\x1e\x3e\xd1\xb5\xef\xe8\xa6\xd7\xf3\x04\x44\xd5\x80\x90\x65\x19\xb3\xa0\x2d\xda\x70\x8c\x17\xb7\x63\x0c\x39\x7f\xcc\x64\xd9\xc0\x64\x12\x49\x45\x4e\xb7\x81\x3a\x7b\x71\x17\x7a\xaf\xc3\xa6\xed\x94\x1e\x91\x9b\x73\x08\x79\xbf\xd7\xee\x08\xdd\x2d\x2e\x1e\x6d\xbb\xbd\x0d\xb1\x0d\xd9\x70\xca\xcc\x7f\x56\xee\xfe\x67\x56\x75\xf1\x9c\xd5\x6b\x78\xe2\x6b\x98\x92\x4a\x58\xb2\x64\x83\x46\x29\x8a\x17\xc9\xa1\x10\x39\x7f\x05\x15\x6d\x6e\x70\xd0\x39\x4e\x50\x89\x13\xd5\x0a\xfb\xf4\x20\x30\x1d\xf5\xa4\x87\x92\xd1\xc3\x30\xff\x16\x8e\x47\xff\xbe\x32\xef\xbe\x2c\xcb\xeb\x6a\x75\xf6\x95\x4b\xf6\x3d\x00\x52\xcf\x16\xe1\xf7\x01\x00\x00")
+
+func migrations_gateway13_daily_aggregateSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway13_daily_aggregateSql,
+		"migrations_gateway/13_daily_aggregate.sql",
+	)
+}
+
+func migrations_gateway13_daily_aggregateSql() (*asset, error) {
+	bytes, err := migrations_gateway13_daily_aggregateSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/13_daily_aggregate.sql", size: 503, mode: os.FileMode(420), modTime: time.Unix(1792035022, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _migrations_compliance01_initSql = []byte{
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x94, 0x93,
-	0xc1, 0x6e, 0xf2, 0x30, 0x10, 0x84, 0xef, 0x7e, 0x8a, 0x3d, 0x12, 0xfd,
-	0x70, 0xf9, 0x55, 0xb8, 0x70, 0x4a, 0x8b, 0x2b, 0x45, 0xa5, 0x81, 0x46,
-	0x89, 0x54, 0x4e, 0xd6, 0x82, 0x5d, 0xb0, 0x1a, 0xc7, 0xc8, 0x38, 0x05,
-	0xfa, 0xf4, 0x75, 0x4a, 0x21, 0x71, 0x21, 0xa0, 0x4a, 0xb9, 0xc4, 0x3b,
-	0xbb, 0x3b, 0xf3, 0x25, 0xee, 0xf5, 0xe0, 0x9f, 0x92, 0x4b, 0x83, 0x56,
-	0x40, 0xb6, 0x26, 0x0f, 0x09, 0x0d, 0x53, 0x0a, 0x69, 0x78, 0x3f, 0xa6,
-	0x10, 0x96, 0x76, 0xa5, 0x8d, 0xfc, 0x14, 0x3c, 0x35, 0x58, 0x6c, 0x70,
-	0x61, 0xa5, 0x2e, 0xa0, 0x43, 0x00, 0x24, 0x87, 0xb9, 0x5c, 0x6e, 0x84,
-	0x91, 0x98, 0x77, 0xdd, 0xbb, 0xad, 0xeb, 0xcc, 0xd5, 0x3e, 0xd0, 0x2c,
-	0x56, 0x68, 0x3a, 0x83, 0xbb, 0x00, 0xe2, 0x49, 0x0a, 0x71, 0x36, 0x1e,
-	0x57, 0x32, 0x25, 0x94, 0x6e, 0x2d, 0x36, 0x67, 0xec, 0xb8, 0x01, 0x2b,
-	0x76, 0xd6, 0x13, 0xe0, 0xc9, 0x0e, 0x43, 0x0b, 0x56, 0x2a, 0xb1, 0xb1,
-	0xa8, 0xd6, 0x9e, 0x86, 0xa3, 0xc5, 0xf3, 0x4e, 0xf7, 0x4c, 0x93, 0xe8,
-	0x39, 0x4c, 0x66, 0xf0, 0x44, 0x67, 0xd0, 0x91, 0x3c, 0x20, 0xc1, 0x90,
-	0xfc, 0x4a, 0x9b, 0xe7, 0x7a, 0x2b, 0xf8, 0x63, 0x74, 0x31, 0x61, 0x81,
-	0x4a, 0x9c, 0xac, 0xff, 0xef, 0xf7, 0x7d, 0xef, 0x5c, 0x2b, 0x94, 0x45,
-	0x7b, 0x7d, 0x5d, 0xce, 0x73, 0xb9, 0x60, 0xef, 0x62, 0x0f, 0xdf, 0x82,
-	0xfe, 0xc0, 0xaf, 0xe3, 0x61, 0x77, 0x7b, 0xae, 0x33, 0xfb, 0x24, 0x80,
-	0x3a, 0x40, 0x16, 0x47, 0x2f, 0x19, 0x85, 0x28, 0x1e, 0xd1, 0x57, 0xc0,
-	0x37, 0xc9, 0xe6, 0x7b, 0xf6, 0x63, 0x69, 0x12, 0x37, 0x83, 0x1d, 0x0e,
-	0x5d, 0xf4, 0x2b, 0x8d, 0x0d, 0xaf, 0x7e, 0x73, 0x5d, 0x68, 0x63, 0x97,
-	0x39, 0x5e, 0x17, 0xe9, 0xb9, 0xc9, 0xd7, 0x01, 0x3a, 0xc1, 0x2d, 0x86,
-	0x4e, 0x72, 0x0b, 0x63, 0xe9, 0x36, 0x36, 0xff, 0xbf, 0xb3, 0x19, 0x7f,
-	0xe7, 0x1c, 0xb4, 0x51, 0x2e, 0x2b, 0x56, 0x9e, 0x27, 0x76, 0x5c, 0x5f,
-	0x63, 0x3b, 0x00, 0xf1, 0x54, 0xdd, 0xa3, 0xcb, 0x6a, 0x32, 0x69, 0x5e,
-	0xbf, 0x91, 0xde, 0x16, 0x64, 0x94, 0x4c, 0xa6, 0xd7, 0xae, 0xdf, 0xd0,
-	0x53, 0x1c, 0x3f, 0xce, 0xa5, 0xd3, 0x6a, 0xf7, 0x90, 0x7c, 0x05, 0x00,
-	0x00, 0xff, 0xff, 0x02, 0xc5, 0x23, 0x8a, 0xe0, 0x03, 0x00, 0x00,
+var _migrations_gateway14_federation_snapshotSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x92\x4f\x53\xc2\x30\x10\xc5\xef\xfd\x14\x7b\x93\x8e\x72\xd0\x11\x2e\x9c\xaa\x2d\x33\x8c\xb5\x30\x15\x66\xe4\x94\x09\xe9\x42\x77\x6c\x93\x4e\x92\xa2\x7c\x7b\x53\x46\xfe\x14\x8b\x1e\x93\xf7\xdb\xcd\xdb\xcd\xeb\xf7\xe1\xb6\xa4\x8d\xe6\x16\x61\x51\x79\xcf\x69\x14\xcc\x23\x98\x07\x4f\x71\x04\x63\xcc\xd0\x09\xa4\xe4\x9b\xe4\x95\xc9\x95\x85\x9e\x07\x40\x19\xac\x68\x63\x50\x13\x2f\xee\xdc\xd9\x6a\x2e\x0d\x17\x0d\xc7\x9c\x26\x72\xae\x7b\xc3\x47\x1f\x92\xe9\x1c\x92\x45\x1c\x37\x4c\xc5\x77\x25\x4a\xdb\xe8\x5b\xae\xf7\xc8\xc3\x60\xe0\x43\x18\x8d\x83\x45\x7c\xe2\x3e\x48\x9e\x88\xfb\x61\xbb\x49\xad\x0b\xb0\xf8\x65\x5b\x97\x6b\x92\xbc\x60\x9d\x92\xb1\xdc\xd6\x86\x09\x95\x21\x90\xb4\xb8\x41\xdd\xd2\x57\x2a\xdb\xfd\xae\xb2\xba\x96\xc2\xad\xc3\x4d\xa9\x54\x81\x5c\x1e\xd5\xa3\xdb\x35\x2f\x0c\xee\xd9\xc2\xb0\x2d\x6a\xe3\x46\xef\x74\x7d\xac\xb8\xb9\x69\x70\x41\x55\x8e\x9a\x99\x9a\xdc\xb6\x0f\xfc\xf9\xaa\x2e\x79\xd4\x96\xd6\xd4\xb8\x31\x1d\x93\xa3\x15\x39\x66\x8c\x5b\xb0\x54\xa2\x9b\xb6\xac\x5a\x80\x46\xa1\x74\xf6\x17\x31\x4b\x27\xaf\x41\xba\x84\x97\x68\x09\x3d\xca\x7c\xcf\x1f\x79\x87\x0c\x4c\x92\x30\x7a\x77\x8f\x1c\x32\xc0\xcc\x4f\x08\xd8\xc5\x87\x4f\x93\xce\xa4\xb4\x29\xd7\xf8\xdf\xbe\x67\x21\xb9\xd2\xf3\x44\x34\x46\xfb\x67\xd9\x0d\xd5\xa7\xf4\xc2\x74\x3a\xbb\x9a\xdd\x91\xf7\x0d\xfe\x32\x19\x6c\xec\x02\x00\x00")
+
+func migrations_gateway14_federation_snapshotSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway14_federation_snapshotSql,
+		"migrations_gateway/14_federation_snapshot.sql",
+	)
 }
 
+func migrations_gateway14_federation_snapshotSql() (*asset, error) {
+	bytes, err := migrations_gateway14_federation_snapshotSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/14_federation_snapshot.sql", size: 748, mode: os.FileMode(420), modTime: time.Unix(1792037395, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway15_received_payment_eventSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x90\x31\x0b\xc2\x30\x10\x85\xf7\xfc\x8a\x1b\x5b\xb4\x8b\xe0\xd4\xa9\xda\x0c\x62\x6d\x4b\xa8\xa0\x53\x38\x9b\x43\x03\xb6\x0d\x69\xa8\xfa\xef\x4d\x51\x51\x07\x5d\x0e\x8e\xf7\x78\xf7\xdd\x8b\x22\x98\x34\xfa\x68\xd1\x11\x6c\x0d\x5b\x0a\x9e\x54\x1c\xaa\x64\x91\x71\x10\x54\x93\x1e\x48\x95\x78\x6b\xa8\x75\x7c\xf0\x03\x02\x06\xa0\x15\x1c\xf4\xb1\x27\xab\xf1\x3c\xf5\x7b\x67\xc8\x07\xe8\xae\x95\x5e\x19\xd0\xd6\x27\xb4\xc1\x6c\x3e\x0f\x21\x2f\x2a\xc8\xb7\x59\x36\xba\x0c\xde\xce\x1d\x2a\x70\x74\x75\x5f\x42\x6d\xc9\x9f\x57\x12\x1d\x38\xdd\x50\xef\xb0\x31\x5f\x86\x52\xac\x36\x89\xd8\xc3\x9a\xef\x21\xd0\x2a\x64\x61\xcc\x5e\xa8\xab\x3c\xe5\x3b\xb0\x4f\x54\x69\x1e\xac\x92\x46\x58\xf9\x91\x5c\xe4\x3f\xfe\x79\x7b\xc6\xd4\xe8\xa3\x8f\xb4\xbb\xb4\x2c\x15\x45\xf9\xa7\x8f\x98\xdd\x01\x32\xaa\xa3\x38\x42\x01\x00\x00")
+
+func migrations_gateway15_received_payment_eventSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway15_received_payment_eventSql,
+		"migrations_gateway/15_received_payment_event.sql",
+	)
+}
+
+func migrations_gateway15_received_payment_eventSql() (*asset, error) {
+	bytes, err := migrations_gateway15_received_payment_eventSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/15_received_payment_event.sql", size: 322, mode: os.FileMode(420), modTime: time.Unix(1792041118, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway16_payment_limit_usageSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x91\xc1\x6a\xc3\x30\x0c\x40\xef\xfe\x0a\xdd\x9a\xb0\x04\xba\x42\x76\xe9\x29\x5b\x3c\x08\xf3\x9c\x2c\xc4\xd0\x9e\x82\xd7\x98\x4c\xd0\x38\xc5\x76\x36\xfa\xf7\x8b\x57\xd6\x66\xac\xbd\x48\x08\x3d\x90\xf4\x14\xc7\x70\xd7\x63\x67\xa4\x53\x20\x0e\xe4\xa9\xa2\x69\x4d\xa1\x4e\x1f\x19\x85\x52\x1e\x7b\xa5\x1d\xc3\x1e\x9d\xb0\xb2\x53\x10\x10\x00\x6c\xe1\x1d\x3b\xab\x0c\xca\x7d\x34\xd5\xad\x3c\xc2\xa7\x34\xbb\x0f\x69\x82\xfb\x65\x08\xbc\xa8\x81\x0b\xc6\x7c\x4f\x5a\xab\x5c\xb3\x1b\x5a\x75\x41\x56\xd7\x10\xb4\x76\x54\xe6\x0c\x25\x0f\x17\x08\x32\xfa\x9c\x0a\x56\xc3\x62\xf1\x33\x4e\x59\x87\x5a\x3a\x1c\xf4\x19\x5f\x25\xc9\x4d\x5e\xf6\xc3\xa8\x9d\x5f\x19\xa7\xf4\x0f\x5a\x7a\xa6\xac\xf2\xd7\xb4\xda\xc2\x0b\xdd\x42\x80\x6d\x48\xc2\x35\xf9\x55\x21\x78\xfe\x26\x28\xe4\x3c\xa3\x1b\x38\x9c\x8c\x34\x7b\xaf\xa4\x19\xbd\x93\x53\x84\x82\x5f\xd3\x35\xb9\x89\x66\x12\xa2\x3f\xd7\x46\xf3\x5b\xfc\xc4\x78\xf6\x8b\x6c\xf8\xd2\x24\xab\x8a\xf2\xd6\x2f\xd6\xe4\x1b\x55\x8d\x1f\xca\xbb\x01\x00\x00")
+
+func migrations_gateway16_payment_limit_usageSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway16_payment_limit_usageSql,
+		"migrations_gateway/16_payment_limit_usage.sql",
+	)
+}
+
+func migrations_gateway16_payment_limit_usageSql() (*asset, error) {
+	bytes, err := migrations_gateway16_payment_limit_usageSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/16_payment_limit_usage.sql", size: 443, mode: os.FileMode(420), modTime: time.Unix(1792041624, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway17_named_destinationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x91\x41\x4b\xc3\x30\x14\xc7\xef\xf9\x14\xef\xb6\x16\xed\xc1\x61\x77\xd9\xa9\x9a\x08\xc5\x9a\xce\xd2\x80\x3b\x85\xd8\x84\xf9\xc0\xb4\x25\xc9\x14\xbf\xbd\xed\xa0\xae\x93\x4d\x8f\xe1\xfd\xde\x9f\xbc\xdf\x3f\x49\xe0\xca\xe2\xce\xa9\x60\x40\xf4\xe4\xbe\x62\x59\xcd\xa0\xce\xee\x0a\x06\x5c\x59\xa3\xa9\xf1\x01\x5b\x15\xb0\x6b\x21\x22\x00\xa8\xe1\x15\x77\xde\x38\x54\xef\xd7\xc3\xbb\x1d\x20\xf8\x50\xae\x79\x53\x2e\x5a\xdd\xc6\xc0\xcb\x1a\xb8\x28\x8a\x71\xa8\x67\xcb\x13\xb3\x4c\xd3\x53\xc8\x1a\xdb\xc9\xf0\xd5\x1f\x63\x6e\x56\x47\x02\x28\x7b\xc8\x44\x51\xc3\x62\x31\xc1\xe7\xa3\x7e\x81\xca\x7b\x13\x64\xd3\xe9\x59\xec\xf2\x1f\x1a\xbd\xdf\x1b\xf7\xc3\xa7\x97\xbf\xd1\x38\x33\x18\xd3\x52\x05\x08\x68\x87\x2b\x95\xed\x4f\x8e\xda\xf7\xfa\x6f\x60\x53\xe5\x4f\x59\xb5\x85\x47\xb6\x85\x08\x75\x4c\xe2\x35\x99\xf4\x0b\x9e\x3f\x0b\x06\x39\xa7\xec\xe5\x20\x58\xcb\x99\x49\x79\x50\x5e\xf2\x33\xfd\x8c\x93\x31\x27\x99\xb5\x4a\xbb\xcf\x96\xd0\xaa\xdc\x5c\x68\x75\x4d\xbe\x01\x63\x4f\xef\xcd\x04\x02\x00\x00")
+
+func migrations_gateway17_named_destinationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway17_named_destinationSql,
+		"migrations_gateway/17_named_destination.sql",
+	)
+}
+
+func migrations_gateway17_named_destinationSql() (*asset, error) {
+	bytes, err := migrations_gateway17_named_destinationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/17_named_destination.sql", size: 516, mode: os.FileMode(420), modTime: time.Unix(1792041959, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway18_held_payment_approvalSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x8f\xb1\x0a\xc2\x30\x14\x00\xf7\x7c\xc5\x1b\x15\xc9\x26\x2e\x99\xa2\x89\x38\x3c\xdb\x52\x92\x59\x9e\x6d\xb0\x05\xd3\x86\x18\x2a\xfd\x7b\x1d\x83\xa0\x28\xb8\xdf\x1d\x1c\xe7\xb0\xf2\xfd\x25\x52\x72\x60\x03\x93\x68\x74\x0d\x46\x6e\x51\xc3\xc1\x5d\xdb\x8a\x66\xef\x86\x04\x52\x29\x68\xa2\x7b\x52\xed\xe9\x3c\xc3\x44\xb1\xe9\x28\x2e\x36\xeb\x25\x28\xbd\x97\x16\x0d\x14\x16\x51\x7c\x0c\x50\x08\x71\x9c\xfe\x52\xa0\x04\xa9\xf7\xee\x96\xc8\x87\x17\x9f\xf1\x6c\x49\x8d\xf7\xe1\x6d\x51\xd5\x65\x05\xbb\x12\xed\xb1\xc8\xe6\xc4\x57\x7c\xf6\xf2\xa3\x40\x49\xb0\x07\xab\x45\x42\xed\x76\x01\x00\x00")
+
+func migrations_gateway18_held_payment_approvalSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway18_held_payment_approvalSql,
+		"migrations_gateway/18_held_payment_approval.sql",
+	)
+}
+
+func migrations_gateway18_held_payment_approvalSql() (*asset, error) {
+	bytes, err := migrations_gateway18_held_payment_approvalSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/18_held_payment_approval.sql", size: 374, mode: os.FileMode(420), modTime: time.Unix(1792042129, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway19_payment_velocitySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\x92\x51\x4b\xc3\x30\x10\xc7\xdf\xf3\x29\xee\x6d\x2d\xae\x0f\x0e\xea\xcb\x9e\xaa\x8d\x30\xac\x6d\x29\xad\xb8\xa7\x12\xd3\x30\x0f\x96\xa4\x24\xe9\x64\xdf\xde\xce\x4d\x4d\x59\x11\x7c\x0a\xe1\xfe\xfc\xee\xee\xc7\x45\x11\xdc\x48\xdc\x19\xe6\x04\x34\x3d\x79\xa8\x68\x52\x53\xa8\x93\xfb\x8c\x42\xc9\x8e\x52\x28\xf7\x22\xf6\x9a\xa3\x3b\x42\x40\x00\xb0\x83\x37\xdc\x59\x61\x90\xed\x97\xe3\xdf\xea\xc1\x70\x01\x07\x66\xf8\x3b\x33\x41\x7c\x17\x42\x5e\xd4\x90\x37\x59\x76\x2a\x77\xc2\x3a\x54\xcc\xa1\x56\x3f\x99\x55\x1c\x4f\x43\xcc\x5a\xe1\x5a\xae\xbb\x5f\xce\xed\x6a\x2e\x82\xd6\x0e\xc2\xcc\x36\x83\x94\x3e\x26\x4d\x56\xc3\x62\xf1\x95\x97\x7a\x50\xee\x34\x2a\x8e\x8f\x4f\xe2\x46\x8c\xbb\x76\x2d\x73\xe0\x50\x8e\xe3\x31\xd9\x4f\x02\x65\xb5\x79\x4e\xaa\x2d\x3c\xd1\x2d\x04\xd8\x85\x24\x5c\x93\x6f\x2f\x9b\x3c\xa5\xaf\xd0\x9f\xbd\xb4\x87\x8b\x98\xf6\x22\xa1\xc8\xaf\x95\x9d\x4b\x4b\xaf\xed\x88\xfb\x9b\xe6\x3b\x9b\x43\x7a\xf5\x7f\x71\xbd\xcd\xe7\xb0\x13\x12\x89\xbc\xbb\x48\xf5\x87\x22\x69\x55\x94\xf3\x77\xb1\x26\x9f\x85\xb4\x79\xe6\x45\x02\x00\x00")
+
+func migrations_gateway19_payment_velocitySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway19_payment_velocitySql,
+		"migrations_gateway/19_payment_velocity.sql",
+	)
+}
+
+func migrations_gateway19_payment_velocitySql() (*asset, error) {
+	bytes, err := migrations_gateway19_payment_velocitySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/19_payment_velocity.sql", size: 581, mode: os.FileMode(420), modTime: time.Unix(1792042397, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway20_expected_paymentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x94\xc1\x6f\x82\x30\x14\xc6\xef\xfc\x15\xbd\x29\x99\x26\x9b\x99\x5e\x3c\xb1\xd1\x25\x66\x88\x8e\x40\x32\x4f\xa4\x2b\x9d\x36\x59\x81\xb4\xc5\xcd\xff\x7e\xc5\x31\xa0\xb5\xba\x8b\x87\x9d\x48\xf3\xbe\xf7\xe5\xf5\x7d\xbf\x32\x1e\x83\x1b\x46\xb7\x1c\x49\x02\x92\xd2\x79\x8c\xa0\x17\x43\x10\x7b\x0f\x01\x04\xf0\xab\x24\x58\x92\x6c\x8d\x0e\x8c\xe4\x12\x0c\x1d\x00\x68\x06\xde\xe8\x56\x10\x4e\xd1\xc7\x48\x9d\x49\xa3\x49\x55\x61\x8f\x38\xde\x21\x3e\x9c\xdd\xbb\x20\x5c\xc5\x20\x4c\x82\xa0\xd6\x20\x56\x54\xaa\x5d\xf5\x51\xf5\xd1\x2a\x42\x10\x99\xe2\x22\x23\x6d\xf3\xdd\xc4\xb5\x48\xa8\x10\x15\xe1\xad\x68\x3a\xeb\x44\xc0\x87\x4f\x5e\x12\xc4\x60\x30\xa8\xf5\x8c\xb0\x22\x95\x87\xb2\x73\x9c\xe9\x86\xb5\xe0\xec\xa8\x42\x22\x59\x89\x6e\x18\xa3\x97\x13\x4c\xe8\x5e\xdd\xd6\x7e\xa5\x76\x94\xdb\x5a\x8c\x39\x41\xf5\x66\x90\x04\x92\x32\xa2\xac\x59\xa9\xb9\x55\x65\x76\x59\xb0\x8e\x16\x4b\x2f\xda\x80\x67\xb8\x01\x43\x9a\xb9\x8e\x3b\x77\x7e\x23\x4a\xc2\xc5\x4b\x02\xc1\x22\xf4\xe1\x6b\x97\x42\xf9\x13\x55\xda\x8f\x65\x15\x9e\x26\xd9\xab\x2b\xcf\xc6\xf2\x8c\xd7\x71\x61\x36\x93\x76\xd5\xa3\xe3\x52\x47\xcd\xf6\x7a\x43\xfe\x70\xd4\x34\x2c\x91\xc4\x3b\x2b\x44\x45\x49\x14\x80\xb4\xc8\xfb\x14\x4d\xa6\x53\x7d\xf9\x92\xa3\x5c\x20\x6c\xea\xcc\x08\xdf\x79\xc1\x52\x84\xf1\x31\x20\x1b\x30\xff\x0b\x49\x8b\xd6\x7a\x35\x43\xf7\xd7\xbb\x33\xe4\xd7\xe4\x9a\xd5\x39\x5e\x05\xdb\x96\xb0\xda\x31\xd5\x28\x50\xbc\xe9\xd8\xf4\xab\x26\xb2\xba\x8f\xc1\xbe\x6e\x73\x01\x7c\xdd\xa5\xd9\xd8\x89\x41\xc7\xf8\xb8\xf7\xeb\xf4\x8b\xcf\xdc\xf1\xa3\xd5\xda\x82\xfc\xbc\x5f\x30\x1e\xd1\xdc\xf9\x06\xb0\x44\x58\x78\x81\x05\x00\x00")
+
+func migrations_gateway20_expected_paymentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway20_expected_paymentSql,
+		"migrations_gateway/20_expected_payment.sql",
+	)
+}
+
+func migrations_gateway20_expected_paymentSql() (*asset, error) {
+	bytes, err := migrations_gateway20_expected_paymentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/20_expected_payment.sql", size: 1409, mode: os.FileMode(420), modTime: time.Unix(1792043311, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway21_refundSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\x31\x6f\x83\x30\x10\x85\x77\x7e\xc5\x6d\x01\xb5\x0c\x8d\x4a\x96\x4c\xb4\xb8\x12\x2a\x85\x14\x81\x94\x4c\xe8\x8a\xdd\xd4\x52\x6d\x90\x6d\x5a\xe5\xdf\x97\x44\x02\x62\x94\x32\xda\xfe\xee\x9d\xdf\xdd\xf3\x7d\xb8\x13\xfc\xa8\xd0\x30\x28\x5b\xe7\x39\x27\x61\x41\xa0\x08\x9f\x12\x02\x39\xfb\xec\x24\x05\xd7\x01\xe0\x14\x3e\xf8\x51\x33\xc5\xf1\xfb\xbe\x3f\x37\x2d\xeb\x4b\x78\x23\xab\xfe\xe5\x07\x55\xfd\x85\xca\x5d\x07\x81\x07\x69\x56\x40\x5a\x26\xc9\x99\x32\x0a\xa5\xc6\x7a\xce\x6d\x1e\x6d\xac\xc5\x93\x60\xd2\x2c\x4a\x51\xa6\x0d\x97\x97\x96\x23\x14\x6c\x6c\x06\x45\xd3\x49\xf3\xbf\x06\x6a\xcd\x4c\x55\x37\x94\x8d\xcc\xc3\x7a\x42\x20\x22\x2f\x61\x99\x14\xb0\x5a\x4d\x34\xd7\xba\x63\xea\x66\xcb\x19\x2f\x98\x68\x2a\x73\x6a\x27\xf1\x65\xf6\xe6\x38\x66\x9c\x62\xa8\xaf\x0c\x5b\x8e\x66\xa8\x36\x68\x3a\x3d\x19\x9b\xcd\x46\x5d\x76\x59\x2d\x6c\x64\x90\x1b\x2a\xea\xbe\xb9\x61\xb4\x42\x03\x86\x8b\x7e\xfc\x28\x5a\x4b\xb2\x6b\xe9\x32\xb0\xcb\xe3\xb7\x30\x3f\xc0\x2b\x39\x80\xcb\xa9\xe7\x78\x5b\x67\x48\x58\x99\xc6\xef\x25\x81\x38\x8d\xc8\x7e\xf8\x9c\x15\xaa\x2c\x1d\xf3\x77\x7d\x7f\x96\xf0\xaf\x32\x1b\x35\xbf\xd2\x89\xf2\x6c\x67\x65\x76\xeb\xfc\x01\xd6\xd4\xff\x80\xd8\x02\x00\x00")
+
+func migrations_gateway21_refundSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway21_refundSql,
+		"migrations_gateway/21_refund.sql",
+	)
+}
+
+func migrations_gateway21_refundSql() (*asset, error) {
+	bytes, err := migrations_gateway21_refundSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/21_refund.sql", size: 728, mode: os.FileMode(420), modTime: time.Unix(1792043595, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway22_failed_callbackSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x91\x4f\x4f\x84\x30\x10\xc5\xef\xfd\x14\x73\x84\x28\x07\x4d\xd6\xcb\x9e\x70\xa9\x09\x11\x61\x25\x90\xb8\x27\x32\x4b\x2b\x36\x16\x68\xca\xac\x7f\xbe\xbd\x5d\x45\xb3\xcd\x12\x8f\x93\xdf\x9b\x37\x33\x6f\xa2\x08\x2e\x7a\xd5\x59\x24\x09\xb5\x61\x9b\x92\xc7\x15\x87\x2a\xbe\xcd\x38\xdc\xa1\xd2\x52\x6c\x50\xeb\x3d\xb6\xaf\x10\x30\x00\x25\x60\xaf\xba\x49\x5a\x85\xfa\xd2\xd5\xa3\x91\xae\x55\x8d\x43\xe3\xc8\x1b\xda\xf6\x05\x6d\x70\xbd\x5a\x85\x90\x17\x15\xe4\x75\x96\x1d\x55\x06\x3f\xf5\x88\x02\x48\x7e\x90\x07\x34\x4e\xd4\x48\x6b\x47\x7b\xce\x90\x48\xf6\x86\x26\x50\x03\xc9\x4e\x5a\x0f\x4e\x84\x74\x98\xfe\x26\x5e\xdd\xf8\x03\x5b\x2b\xdd\x41\xa2\x41\x02\x52\xbd\x74\xea\xde\x78\x82\x83\x11\xff\x0b\xb6\x65\xfa\x10\x97\x3b\xb8\xe7\x3b\x08\x94\x08\x59\xb8\x66\xbf\xe9\xd4\x79\xfa\x58\x73\x48\xf3\x84\x3f\xc1\xf3\x77\x48\x4d\x3b\xa7\xd4\x78\x89\x14\xf9\x59\x88\xa7\xdc\x79\xce\x96\xcb\x5e\xf3\x95\x0b\x2e\x3f\xe4\xb8\x53\x74\xf2\xc0\x64\x7c\x1f\x58\x52\x16\xdb\xc5\x07\xae\xd9\x17\x34\xe3\x47\xf4\xed\x01\x00\x00")
+
+func migrations_gateway22_failed_callbackSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway22_failed_callbackSql,
+		"migrations_gateway/22_failed_callback.sql",
+	)
+}
+
+func migrations_gateway22_failed_callbackSql() (*asset, error) {
+	bytes, err := migrations_gateway22_failed_callbackSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/22_failed_callback.sql", size: 493, mode: os.FileMode(420), modTime: time.Unix(1792043806, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway23_offline_transactionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\xc1\x4e\x83\x40\x10\x86\xef\x3c\xc5\x1c\x21\xda\x83\x46\x7a\xe9\x09\x65\x0f\x44\x84\x4a\x20\xb1\x27\xb2\x85\x29\x6e\x84\x5d\x9c\x5d\xda\x3e\xbe\x34\x2a\x85\x56\x3c\xee\xfc\x5f\xbe\x6c\xe6\x9f\xc5\x02\x6e\x1a\x51\x11\x37\x08\x59\x6b\x3d\x25\xcc\x4b\x19\xa4\xde\x63\xc8\x20\xde\xed\x6a\x21\x31\x25\x2e\x35\x2f\x8c\x50\x12\x6c\x0b\x40\x94\xb0\x15\x95\x46\x12\xbc\xbe\xed\xdf\xea\x1b\xcb\xfb\xf9\x9e\x53\xf1\xce\xc9\xbe\x77\x5d\x07\xa2\x38\x85\x28\x0b\xc3\x13\x63\xce\x8e\x31\xb7\x7c\x98\x62\x12\xcd\x41\xd1\xc7\xbc\x47\xab\x8e\x0a\x1c\x72\x77\x39\x8d\x51\xee\xb1\x56\x2d\xe6\xc7\x92\xc0\xe0\xd1\x4c\x52\xc2\xcf\x4e\x10\x96\xb9\x16\x95\x44\xd2\xd7\x84\x36\xdc\x74\x7a\xd0\xdf\x5d\xe8\x6b\xae\x4d\x8e\x44\x8a\xe6\x7f\x58\x10\xf6\xbb\x2c\x73\x6e\xc0\x88\x06\x7b\x63\xd3\x4e\x80\xae\x2d\xff\x07\x74\xb7\x6d\x84\xb9\x46\x7e\xe2\x75\x12\xbc\x78\xc9\x06\x9e\xd9\x06\x6c\x51\x3a\x96\xb3\xb2\x7e\x7b\xcb\xa2\xe0\x35\x63\x10\x44\x3e\x7b\x1b\x7a\x19\xef\x7e\xd4\x55\x1c\xfd\x59\xf0\x99\x38\x79\x17\xa3\xf3\xf0\xd5\x41\x5a\x7e\x12\xaf\x67\xcf\x63\x65\x7d\x01\x14\x7e\xdc\xc2\x4f\x02\x00\x00")
+
+func migrations_gateway23_offline_transactionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway23_offline_transactionSql,
+		"migrations_gateway/23_offline_transaction.sql",
+	)
+}
+
+func migrations_gateway23_offline_transactionSql() (*asset, error) {
+	bytes, err := migrations_gateway23_offline_transactionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/23_offline_transaction.sql", size: 591, mode: os.FileMode(420), modTime: time.Unix(1792044252, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway24_quoteSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x92\x4f\x4f\x83\x40\x10\xc5\xef\xfb\x29\xe6\x08\x51\x0e\xad\xd2\x4b\x4f\x58\xf6\x40\x44\x68\x09\x24\xf6\x44\x56\x98\xb4\x1b\xe5\x8f\xbb\x83\xad\xdf\x5e\x48\x64\x03\x8d\x8d\x3d\xce\x7b\xbf\x99\x77\x78\xe3\x38\x70\x57\xc9\x83\x12\x84\x90\xb5\x6c\x93\x70\x2f\xe5\x90\x7a\x4f\x21\x87\x5d\xd7\xf4\xaa\xc5\x00\x64\x09\x6f\xf2\xa0\x51\x49\xf1\x71\xdf\xcf\x9f\x83\x93\xf7\xea\x97\x50\xc5\x51\x28\x6b\xf5\x68\x43\x14\xa7\x10\x65\x61\x38\x00\x35\xd2\xa9\x51\xef\xc6\x5f\xba\xee\x1c\xa0\xef\x16\x8d\xbb\x58\xcd\x4d\x8d\x75\x99\x0b\xad\x91\xf2\xa2\x29\x27\xdc\xf2\x2a\x27\xb5\xee\x50\x19\xd2\xfd\xf3\x62\xd5\x74\x35\x19\xe6\xe1\xe2\xda\x0d\x81\x37\x65\xfd\x13\xd3\x0a\x3a\x02\xe1\x99\x66\x2a\x9e\x5b\xa9\x50\xe7\x82\x80\x64\x85\x9a\x44\xd5\xce\x80\x42\x61\xdf\x51\x79\x1d\xd8\x26\xc1\x8b\x97\xec\xe1\x99\xef\xc1\x92\xa5\xcd\xec\x35\x1b\xfb\xcc\xa2\x60\x97\x71\x08\x22\x9f\xbf\xfe\x96\x67\x2a\x8c\xa3\xb1\xe8\x51\x1a\x16\x9d\xc9\x5f\xf8\xcd\xa9\x66\x7e\x12\x6f\xa7\x7f\xb1\x66\x3f\xb0\x76\x44\x14\x3b\x02\x00\x00")
+
+func migrations_gateway24_quoteSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway24_quoteSql,
+		"migrations_gateway/24_quote.sql",
+	)
+}
+
+func migrations_gateway24_quoteSql() (*asset, error) {
+	bytes, err := migrations_gateway24_quoteSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/24_quote.sql", size: 571, mode: os.FileMode(420), modTime: time.Unix(1792044769, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway25_fiat_valueSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x91\x4d\x6b\x83\x40\x10\x86\xef\xfb\x2b\xe6\xa8\x34\x1e\x92\xd6\x5c\x72\xb2\x75\x0b\x52\xab\xa9\x68\x68\x4e\x32\x5d\x27\xe9\x42\xd4\xb0\xbb\xa6\xf4\xdf\x57\x03\xf1\x8b\xe6\x38\xcc\xc3\xfb\x0e\xf3\x38\x0e\x3c\x94\xf2\xa8\xd0\x10\x64\x67\xf6\x92\x70\x2f\xe5\x90\x7a\xcf\x21\x87\x57\x89\x66\x87\xa7\x86\xc0\x62\x00\xb2\x80\x2f\x79\xd4\xa4\x24\x9e\x16\xed\x5c\x48\x45\xc2\xc8\xba\x82\x0b\x2a\xf1\x8d\xca\x5a\xae\x6d\x88\xe2\x14\xa2\x2c\x0c\x3b\x42\xd1\x81\x14\x55\x82\x7a\x62\xe5\xba\x53\xc4\x50\x85\x95\xb9\xbf\x47\xad\xc9\xe4\xa2\x2e\x86\x8c\xe5\xea\x3f\x44\x6a\xdd\x90\xea\x21\x77\x76\x0a\x96\x75\x33\xea\x79\x9c\x65\x88\x46\x75\x87\xfe\xde\x2d\xb9\xfe\xe7\xb6\x5c\x3f\x4d\x97\x87\xf6\x4f\xf9\xac\x61\xce\x08\x45\x6d\x44\x91\xa3\x01\x23\x4b\xd2\x06\xcb\xf3\x04\xd8\x26\xc1\xbb\x97\xec\xe1\x8d\xef\xc1\x92\x85\xcd\xec\x0d\xbb\xe9\xc8\xa2\xe0\x23\xe3\x10\x44\x3e\xff\xbc\xb6\x5d\x3a\x2b\x79\x6f\x20\x1f\x3e\x1d\x47\x63\x6d\x3d\xb1\x18\x64\x74\xb9\xce\xc8\xba\x5f\xff\x54\xcc\x4f\xe2\xed\xdc\xfa\x86\xfd\x01\xbc\xe8\xb8\xa5\x1d\x02\x00\x00")
+
+func migrations_gateway25_fiat_valueSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway25_fiat_valueSql,
+		"migrations_gateway/25_fiat_value.sql",
+	)
+}
+
+func migrations_gateway25_fiat_valueSql() (*asset, error) {
+	bytes, err := migrations_gateway25_fiat_valueSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/25_fiat_value.sql", size: 541, mode: os.FileMode(420), modTime: time.Unix(1792045086, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway26_webhookSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x91\x51\x6b\x83\x30\x14\x85\xdf\xf3\x2b\xee\x5b\x95\x4d\x28\xdd\x0a\x83\x3e\xb9\x9a\x81\xcc\x69\x27\x86\xad\x4f\x12\xeb\xc5\x86\x69\x22\x31\xb3\xdb\xbf\x5f\x64\xac\x36\x50\xf6\x98\xfb\x1d\xce\xcd\x3d\x27\x08\xe0\xa6\x13\x8d\xe6\x06\x81\xf5\x64\x9b\xd3\xb0\xa0\x50\x84\x8f\x09\x85\x37\xac\x8e\x4a\x7d\x80\x47\x00\x44\x0d\x95\x68\x06\xd4\x82\xb7\xb7\xf6\x7d\xfa\x65\xa5\x9d\x8f\x5c\x1f\x8e\x5c\x7b\x77\x2b\x1f\xd2\xac\x80\x94\x25\xc9\x24\xf9\xd4\xed\x99\xad\x96\xf7\x0f\x2e\xc5\x11\xa5\x19\x66\xc1\x7a\xed\xf2\x01\x0f\x1a\xcd\x75\x0e\x11\x7d\x0a\x59\x52\xc0\x62\x31\x49\x3b\xfe\x55\x72\x63\xb0\xeb\xad\xa1\x90\x06\x1b\xd4\x8e\x97\x35\xd2\xdf\xe5\x44\xf4\xc8\xdb\xab\x12\x94\xbc\x6a\xd1\x1e\xa9\x54\x8b\x5c\x3a\xcc\x7e\xc4\xc6\x53\xdb\x1d\x60\x44\x87\x83\xe1\x5d\xef\x5e\xda\xd7\xff\x0b\x76\x79\xfc\x12\xe6\x7b\x78\xa6\x7b\xf0\x44\xed\x13\x7f\x43\xfe\xb2\x66\x69\xfc\xca\x28\xc4\x69\x44\xdf\xcf\xb1\x5e\xc4\x9b\xa5\x73\x11\xf3\x78\x32\x08\x2e\xba\x8b\xd4\x49\x92\x28\xcf\x76\x6e\x77\x1b\xf2\x03\x2e\xde\x5e\x5c\xe1\x01\x00\x00")
+
+func migrations_gateway26_webhookSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway26_webhookSql,
+		"migrations_gateway/26_webhook.sql",
+	)
+}
+
+func migrations_gateway26_webhookSql() (*asset, error) {
+	bytes, err := migrations_gateway26_webhookSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/26_webhook.sql", size: 481, mode: os.FileMode(420), modTime: time.Unix(1792045671, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway27_webhook_formatSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x08\x4f\x4d\xca\xc8\xcf\xcf\x56\x70\x74\x71\x51\x48\xcb\x2f\xca\x4d\x2c\x51\x28\x4b\x2c\x4a\xce\x48\x2c\xd2\x30\x34\xd3\x54\xf0\xf3\x0f\x51\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\xcf\x2a\xce\xcf\x53\xb7\xc6\x69\x46\x71\x72\x46\x6a\x6e\x62\x7c\x59\x6a\x51\x71\x66\x7e\x1e\x01\xb3\x0c\x81\x06\x71\xe9\x22\x39\xce\x25\xbf\x3c\x0f\xab\xd1\x2e\x41\xfe\x01\x50\xf7\x59\xe3\x56\x80\x6a\xb9\x35\x17\x00\x69\xf5\x06\xb4\xf8\x00\x00\x00")
+
+func migrations_gateway27_webhook_formatSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway27_webhook_formatSql,
+		"migrations_gateway/27_webhook_format.sql",
+	)
+}
+
+func migrations_gateway27_webhook_formatSql() (*asset, error) {
+	bytes, err := migrations_gateway27_webhook_formatSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/27_webhook_format.sql", size: 248, mode: os.FileMode(420), modTime: time.Unix(1792046020, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway28_memo_referenceSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x6d\x8f\xc1\x0e\x82\x30\x0c\x86\xef\x7b\x8a\x1e\x21\xca\xc5\x88\x17\x4e\x28\x3b\x10\x61\xe0\xc2\x12\x3d\x91\x09\x15\x97\x38\x20\x83\xe8\xeb\x0b\x24\x12\x31\x1e\xdb\x7e\x6d\xbf\xdf\x71\x60\xa5\x55\x65\x64\x8f\x20\x5a\x72\xe0\xd4\xcf\x28\x64\xfe\x3e\xa2\x10\xa3\x6e\x38\xde\xd0\x60\x5d\x20\x58\x04\x40\x95\x70\x55\x55\x87\x46\xc9\xc7\x7a\xa8\xf5\x40\x40\x71\x97\xc6\xda\x6d\x6d\x60\x49\x06\x4c\x44\xd1\x38\x31\xf3\xde\x53\x9a\x89\xd8\xb8\xee\x12\x29\x0c\x0e\x5f\xcb\x5c\xf6\xd0\x2b\x8d\x5d\x2f\x75\xbb\x00\x52\x1e\xc6\x3e\xbf\xc0\x91\x5e\xc0\x52\xa5\x4d\x6c\x8f\x7c\x0c\x05\x0b\x4f\x82\x42\xc8\x02\x7a\x9e\x34\xf2\xf9\x63\x3e\x59\x25\xec\xd7\x7f\x6c\x8f\x17\x9c\xaf\xc8\x41\xf3\xaa\x49\xc0\x93\xf4\x5f\x64\x8f\xbc\x01\xfb\x9f\xa8\x49\x1e\x01\x00\x00")
+
+func migrations_gateway28_memo_referenceSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway28_memo_referenceSql,
+		"migrations_gateway/28_memo_reference.sql",
+	)
+}
+
+func migrations_gateway28_memo_referenceSql() (*asset, error) {
+	bytes, err := migrations_gateway28_memo_referenceSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/28_memo_reference.sql", size: 286, mode: os.FileMode(420), modTime: time.Unix(1792046436, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway29_sent_transaction_metadataSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x53\x70\x74\x71\x51\xc8\x4d\x2d\x49\x4c\x49\x2c\x49\x54\x28\x49\xad\x28\x51\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x01\x73\xac\xb9\xb8\x74\x91\x0c\x74\xc9\x2f\xcf\xc3\x6b\xa4\x4b\x90\x7f\x00\xdc\x4c\x6b\x2e\x00\x03\xd1\x37\x92\x8d\x00\x00\x00")
+
+func migrations_gateway29_sent_transaction_metadataSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway29_sent_transaction_metadataSql,
+		"migrations_gateway/29_sent_transaction_metadata.sql",
+	)
+}
+
+func migrations_gateway29_sent_transaction_metadataSql() (*asset, error) {
+	bytes, err := migrations_gateway29_sent_transaction_metadataSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/29_sent_transaction_metadata.sql", size: 141, mode: os.FileMode(420), modTime: time.Unix(1792046655, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway30_leader_leaseSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x90\x4b\x0b\x82\x50\x10\x85\xf7\xf7\x57\xcc\x52\x29\x37\x91\x6d\x5c\x59\xde\x85\x64\x57\x13\x85\x5a\xc9\xa4\x83\x5e\xf0\xd5\xd5\x1e\x3f\x3f\x15\x8a\x24\xda\x0c\xcc\x9c\x73\x60\xbe\x63\x18\xb0\xa8\x64\xae\xb0\x27\x88\x5b\xb6\x0b\xb9\x1d\x71\x88\xec\xad\xc7\xc1\x23\xcc\x48\x0d\xb3\x23\xd0\x18\x80\xcc\xe0\x22\xf3\x8e\x94\xc4\x72\x39\xec\x35\x56\x04\x77\x54\x69\x81\x4a\xdb\xac\x75\x10\x7e\x04\x22\xf6\xbc\x51\x2c\x9a\x72\x08\x7f\xe4\x95\x69\xce\x75\x4c\xaf\x37\xa9\x28\x4b\xb0\x87\x5e\x56\xd4\xf5\x58\xb5\x33\x07\x3d\xdb\xc1\xd0\xfd\x37\x04\xa1\x7b\xb0\xc3\x33\xec\xf9\x19\x34\x99\xe9\x4c\xb7\xd8\x9b\x20\x16\xee\x31\xe6\xe0\x0a\x87\x9f\xa0\x9c\x40\x92\x72\x24\x49\xa6\xaf\x7d\x31\xa7\x1b\x8f\x63\xda\xf8\xaa\xc3\x69\x1e\x35\x73\x42\x3f\xf8\xad\xc3\x62\x2f\xd6\x97\x20\x55\x38\x01\x00\x00")
+
+func migrations_gateway30_leader_leaseSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway30_leader_leaseSql,
+		"migrations_gateway/30_leader_lease.sql",
+	)
+}
+
+func migrations_gateway30_leader_leaseSql() (*asset, error) {
+	bytes, err := migrations_gateway30_leader_leaseSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/30_leader_lease.sql", size: 312, mode: os.FileMode(420), modTime: time.Unix(1792048227, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway31_sep24_transactionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x94\x4f\x6f\x82\x40\x10\xc5\xef\x7c\x8a\xb9\xa9\x29\x26\x96\xaa\x17\x4f\xb6\xd0\xc4\xd4\xa2\xa5\x90\xd4\x13\xd9\xc2\xa8\x9b\x02\x4b\x76\x97\xfe\xf9\xf6\x45\x83\x20\xb8\x88\xc7\xcd\xfb\xcd\x9b\x9d\xec\x9b\x1d\x0e\xe1\x2e\xa6\x3b\x4e\x24\x82\x97\x6a\x4f\x8e\x35\x77\x2d\x70\xe7\x8f\x4b\x0b\xde\xad\xb5\x31\x76\x39\x49\x04\x09\x24\x65\x09\xf4\x35\x00\x1a\xc2\x27\xdd\x09\xe4\x94\x44\x7a\x7e\x96\x95\xee\xe7\xda\x37\xe1\xc1\x9e\xf0\xfe\x74\x3c\x00\x7b\xe5\x82\xed\x2d\x97\x07\xec\x8b\x26\x95\x78\x3f\xaa\x8b\x42\x12\x99\x89\x52\x7e\x30\xea\x32\x09\x02\x96\x25\xb2\xd4\x27\xd3\x86\x2e\x04\x4a\x3f\x60\x21\x56\x1d\x0c\x15\x42\x85\xc8\x90\x2b\x7d\xc0\xb4\x9e\xe7\xde\xd2\x85\x5e\xef\xc8\xc7\x87\x8e\x3e\x4d\x0e\xc3\xd2\xbc\xf7\x05\x37\x3a\xc3\x58\x26\x6f\xe2\xb6\x88\x1d\x5c\x12\xec\x19\xf7\xaf\x0d\xdc\xb8\x68\x8c\x31\xf3\xe5\x5f\x5a\x8d\x7e\x9d\x55\x3e\x50\x93\x63\x1c\xf3\xd1\xb7\xcc\xcf\x78\x54\x16\x18\x93\x49\x6b\x85\x90\x18\x45\x84\xfb\x37\x84\xa1\x51\x89\xbf\x12\x79\x42\xa2\xb6\xd2\x6b\x5d\x63\x14\x82\xec\xf0\xc6\x1b\x12\x2e\x31\xf4\x89\x04\x49\xf3\x42\x49\xe2\xb4\x96\x90\x2c\x0d\xc9\x55\x20\x60\x71\x1a\xe1\x25\x52\xc8\x6b\x67\xf1\x3a\x77\x36\xf0\x62\x6d\xa0\x4f\xc3\x81\x36\x98\x69\xa7\x75\xf2\xec\xc5\x9b\x67\xc1\xc2\x36\xad\x0f\x10\x98\x1a\xe3\xda\xb8\x8d\xd1\x57\xb6\x62\xf3\xea\x4c\xee\x5d\x58\xb7\x79\x1e\xdf\x5a\xe9\x54\x26\x46\x3f\x06\x42\x2f\xb6\xaf\xdb\xf2\x94\x4a\xa5\x6b\x21\xea\x67\xcb\xd8\xed\xd8\x12\x1b\x65\x03\x35\xdb\xdd\xa3\x2d\x60\xca\x26\x2d\xf0\xe1\x29\x87\x67\x1f\xa5\xc9\x7e\x12\xcd\x74\x56\xeb\x96\x8f\x72\xa6\xfd\x03\xf8\xce\xa8\xd4\x57\x05\x00\x00")
+
+func migrations_gateway31_sep24_transactionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway31_sep24_transactionSql,
+		"migrations_gateway/31_sep24_transaction.sql",
+	)
+}
+
+func migrations_gateway31_sep24_transactionSql() (*asset, error) {
+	bytes, err := migrations_gateway31_sep24_transactionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/31_sep24_transaction.sql", size: 1367, mode: os.FileMode(420), modTime: time.Unix(1792049628, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway32_held_payment_seedSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\xce\xc1\x0a\x02\x21\x10\x80\xe1\xbb\x4f\x31\xf7\x10\xba\x7b\xb2\x34\x3a\x4c\xba\x2c\x7a\x0e\xc9\x61\x59\x68\x4d\xdc\x81\xda\xb7\xef\x10\x45\x04\x45\x0f\xf0\xfd\xfc\x52\xc2\x6a\x1a\x87\x96\x98\x20\x56\xa1\x31\xd8\x1e\x82\xde\xa0\x85\x3d\x9d\x73\x97\x96\x89\x0a\x83\x36\x06\xa8\x9c\xda\x52\x99\xf2\x71\x26\xca\xc0\x74\x63\x30\x76\xa7\x23\x06\x70\x11\x51\xfd\xd4\x89\x99\xa6\xca\x33\x8c\x85\x69\xa0\x06\xce\x3f\xd8\xab\xb1\x56\x42\xc8\xb7\x1d\x73\xb9\x96\xaf\x49\xd3\xfb\x0e\xb6\x1e\xe3\xc1\x7d\x8c\xa9\xbf\xcc\x73\x47\x89\x3b\x8a\xe6\xe3\x0a\x02\x01\x00\x00")
+
+func migrations_gateway32_held_payment_seedSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway32_held_payment_seedSql,
+		"migrations_gateway/32_held_payment_seed.sql",
+	)
+}
+
+func migrations_gateway32_held_payment_seedSql() (*asset, error) {
+	bytes, err := migrations_gateway32_held_payment_seedSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/32_held_payment_seed.sql", size: 258, mode: os.FileMode(420), modTime: time.Unix(1792051914, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway33_sent_transaction_conflictSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x55\x4d\x73\x9b\x30\x14\xbc\xfb\x57\xbc\x5b\xec\xa9\xcc\xd4\x9d\x3a\x17\xb7\x07\x6a\x94\x29\x53\x07\xbb\x80\xa7\xc9\x89\x91\x41\xb1\x35\x63\x90\x03\x8f\x7c\xfc\xfb\x4a\x31\x60\x41\xb0\x67\x3a\xbd\xe4\x26\xd8\xd5\xbe\xd5\x7b\x2b\x18\x8f\xe1\x53\x2a\xb6\x39\x43\x0e\xeb\xc3\x60\xee\x53\x3b\xa4\x10\xda\x3f\x16\x14\x02\x9e\x61\x98\xb3\xac\x60\x31\x0a\x99\xcd\x65\xf6\xb0\x17\x31\xc2\x70\x00\x20\x12\x10\x19\xf2\x2d\xcf\xc1\x5b\x86\xe0\xad\x17\x0b\xa2\x5e\x1f\xd8\x6b\xaa\x76\x45\x0a\x7e\x62\x79\xbc\x63\xf9\xf0\xcb\x74\x3a\x6a\x71\x72\xbe\x55\x6a\x0d\x7e\xfd\xf5\x04\x83\x43\x6f\xec\xf5\x22\x84\xab\x2b\xcd\xc4\x53\x71\x53\xd1\xdc\xa1\x69\x05\x32\x2c\x8b\x06\x9e\x7c\xee\xc0\xb2\xcc\x63\xde\xc0\xd3\xeb\x0e\x5c\x6e\x52\x81\xc8\x93\x88\x21\xa0\x48\xb9\x92\x4b\x0f\x1d\x4a\x1c\x73\x9e\x74\x29\xb5\xd9\x9a\xb6\xe7\x89\xee\xc7\x46\x6c\x55\x6b\xde\xa1\x3c\x7b\xe2\x7b\x79\xe0\xd1\x4b\x92\x03\xf2\x17\xec\x34\xa5\x28\xf7\xf8\x86\xb5\x1a\xd7\x55\xd9\xb0\x24\x2a\xf8\x63\xa4\xf8\x72\x5f\xa2\xd9\xc8\x89\x3e\x98\xd9\xc4\x7a\x4f\xca\x91\x25\x0c\x59\x55\xb5\x8f\xb2\xf2\xdd\x5b\xdb\xbf\x87\x5f\xf4\x1e\x86\x22\x19\x0d\x46\xb3\x41\x1d\x06\xd7\x73\xe8\x1d\x14\x7a\xac\xe6\x40\xe2\x2a\x0e\x91\x31\xf3\xa5\x77\x3e\x34\x27\x9a\xd6\x1e\x8f\x61\x75\x7c\x01\xae\x03\xa2\x80\x32\x13\x8f\x25\x07\x16\xe7\xb2\x28\xaa\x8c\x14\xc0\xb6\x4c\x64\xc4\x0c\x42\x01\xf2\x01\x24\xee\x54\xa3\x2b\x92\xd6\x7a\x16\xb8\x03\xf5\x12\x0a\x96\xf2\x3a\x85\x5a\x99\xe5\x1c\x52\xf9\xc4\x13\x40\x79\xce\xdb\xc0\xf5\x02\xea\x87\xea\xa0\xe1\xf2\xbc\x7f\x91\x10\x23\xde\xa4\xaa\x4e\x3a\x21\x25\x55\x1a\x49\x15\x3b\xd2\xca\x17\x69\x45\x89\x54\x89\x21\xad\x6c\x10\x23\x0b\xa4\x67\xde\xa4\x99\xe7\x48\x0d\x2e\xa0\x0b\x3a\x0f\x01\x2d\x5d\x1a\x2d\xd3\x20\x5a\x8d\x45\xab\x6b\x12\xad\xda\xa6\x5a\x55\x46\xd5\xaa\x65\x55\x3f\x9b\x66\xd1\xaa\xed\xa2\xd5\x36\xac\x2b\x9d\x2c\xa3\xd5\x67\x1a\xad\xda\xb6\x72\x7d\xe3\x2f\x6f\xbb\x9d\x06\x54\xc0\x9f\x9f\xd4\xa7\x40\xef\xdc\x20\x0c\x60\x58\x1d\x6e\xd2\xcf\x97\x15\x5b\x1a\xa7\x86\xef\xad\x26\x80\xed\x39\x0a\x57\x8b\x6f\x6f\x2d\xd2\xc9\x73\x94\xa8\x4a\x75\xaf\xe4\x51\x50\xd1\x5d\xaf\xa9\xae\x9e\xfa\xb8\x75\x30\xb4\xa4\xbd\x08\xa9\xdf\xff\xd1\x04\xc7\x5f\xae\x60\xbe\xf4\x82\xd0\xb7\x55\xc0\xaa\xd8\x18\x97\x26\x3a\x26\x7f\x76\x51\xc5\x76\x1c\x53\xe4\xdd\x6e\x58\x7b\xee\xef\x35\xed\xb9\x65\xcd\xd7\xdd\x91\xcf\xd9\x3f\x39\xfd\x3f\x8b\xe7\xce\xd9\x38\xad\xc3\xd9\x76\x7c\xe1\x2a\x7e\xf0\x2b\xf8\xf1\xcc\x5d\xcc\xad\xbe\x09\x7a\xe0\x17\x7f\xf5\xb3\xc1\x5f\x39\x02\x0c\x03\x20\x08\x00\x00")
+
+func migrations_gateway33_sent_transaction_conflictSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway33_sent_transaction_conflictSql,
+		"migrations_gateway/33_sent_transaction_conflict.sql",
+	)
+}
+
+func migrations_gateway33_sent_transaction_conflictSql() (*asset, error) {
+	bytes, err := migrations_gateway33_sent_transaction_conflictSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/33_sent_transaction_conflict.sql", size: 2080, mode: os.FileMode(420), modTime: time.Unix(1792052278, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway34_sent_transaction_original_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x08\x4e\xcd\x2b\x09\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x53\x70\x74\x71\x51\xc8\x2f\xca\x4c\xcf\xcc\x4b\xcc\x89\xcf\x4c\x51\xc8\xcc\x2b\x49\x4d\x4f\x2d\x52\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x01\x73\xac\xf1\x19\xe3\x9c\x9f\x97\x96\x93\x99\x5c\x42\x8a\x71\x5c\xba\x48\xae\x74\xc9\x2f\xcf\xc3\xeb\x4e\x97\x20\xff\x00\x64\x93\x89\x73\x0e\xa6\x2e\x00\x68\x59\xf8\xf3\x1b\x01\x00\x00")
+
+func migrations_gateway34_sent_transaction_original_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway34_sent_transaction_original_idSql,
+		"migrations_gateway/34_sent_transaction_original_id.sql",
+	)
+}
+
+func migrations_gateway34_sent_transaction_original_idSql() (*asset, error) {
+	bytes, err := migrations_gateway34_sent_transaction_original_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/34_sent_transaction_original_id.sql", size: 283, mode: os.FileMode(420), modTime: time.Unix(1792052419, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway35_api_key_tenantSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x70\x0c\xf0\xf4\x4e\xad\x54\x70\x74\x71\x51\x28\x49\xcd\x4b\xcc\x2b\x51\x28\x4b\x2c\x4a\xce\x48\x2c\xd2\x30\x33\xd1\x54\xf0\xf3\x0f\x51\xf0\x0b\xf5\xf1\x51\x70\x71\x75\x73\x0c\xf5\x09\x51\x50\x57\xb7\xe6\xe2\xd2\x45\x32\xce\x25\xbf\x3c\x0f\x9b\x81\x2e\x41\xfe\x01\x50\x13\xad\xb9\x00\x6b\xbf\xd6\x0c\x80\x00\x00\x00")
+
+func migrations_gateway35_api_key_tenantSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway35_api_key_tenantSql,
+		"migrations_gateway/35_api_key_tenant.sql",
+	)
+}
+
+func migrations_gateway35_api_key_tenantSql() (*asset, error) {
+	bytes, err := migrations_gateway35_api_key_tenantSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/35_api_key_tenant.sql", size: 128, mode: os.FileMode(420), modTime: time.Unix(1792053129, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x93\xcf\x6e\xb2\x40\x14\xc5\xf7\xf3\x14\x77\x29\xf9\x74\xf3\xa5\xba\x61\x45\x2b\x4d\x48\x2d\x5a\x02\x49\x5d\x4d\xae\xce\xa0\x37\x65\xc0\x0c\x43\xd5\x3e\x7d\x63\xfd\x03\x53\x05\xd3\xed\x9c\x73\xef\x9c\xf3\x83\x19\x0c\xe0\x9f\xa2\x95\x46\x23\x21\xd9\xb0\xa7\xc8\xf7\x62\x1f\x62\xef\x71\xe2\x83\x57\x99\x75\xa1\xe9\x4b\x8a\x58\x63\x5e\xe2\xd2\x50\x91\x43\x8f\x01\x90\x80\x05\xad\x4a\xa9\x09\xb3\x3e\x03\x30\xb5\xce\x49\xc0\x27\xea\xe5\x1a\x75\x6f\xf4\xe0\x40\x38\x8d\x21\x4c\x26\x93\x83\x4d\x49\x55\xb4\x8a\xcd\x1d\x3b\xa1\xc1\xc8\x9d\xb1\x0c\x78\x89\xc3\xd1\x80\x21\x25\x4b\x83\x6a\x63\x79\x04\x1a\xbc\x9e\x64\x00\xb3\x28\x78\xf5\xa2\x39\xbc\xf8\x73\xe8\x91\x70\x98\xe3\xb2\x5f\x6d\xb3\xac\xd8\x4a\xf1\x1c\xdc\x6c\x98\xa3\x92\x97\xe8\xff\x87\x43\x3b\xbb\x28\x14\x52\xde\xae\x6f\xaa\x45\x46\x4b\xfe\x21\xf7\xf0\x63\x18\x8e\x6c\x1d\x8f\x77\xb7\xf7\xba\x8a\xcf\x1c\xa8\x0b\x24\x61\xf0\x96\xf8\x10\x84\x63\xff\x1d\x30\x25\xbe\xd8\xf3\x53\xa4\x69\xd8\x2c\x76\x3c\x74\xdc\xae\xc1\x46\x56\x7b\xb8\x16\xda\xd8\x25\xa5\xd4\x37\xe9\xa5\xc4\xbb\x01\xa6\xc4\xef\x31\x4c\x89\xdf\xc3\x58\x95\x52\x37\xff\xbf\xab\x1d\x7f\xe7\xec\xb4\x51\xae\x0e\xac\xac\x4c\xfc\x7c\x7d\x8d\xed\x08\xc4\x72\xf5\xe1\x64\x3b\x6c\x66\xcd\xe7\x37\x2e\xb6\x39\x1b\x47\xd3\x59\xd7\xf3\x73\x2d\xc7\xf9\xe3\xdc\x3a\x4d\x4a\xa9\x5d\xf6\x3d\x00\x02\xc5\x23\x8a\xe0\x03\x00\x00")
+
 func migrations_compliance01_initSqlBytes() ([]byte, error) {
 	return bindataRead(
 		_migrations_compliance01_initSql,
@@ -232,7 +823,107 @@ func migrations_compliance01_initSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "migrations_compliance/01_init.sql", size: 992, mode: os.FileMode(420), modTime: time.Unix(1476985200, 0)}
+	info := bindataFileInfo{name: "migrations_compliance/01_init.sql", size: 992, mode: os.FileMode(436), modTime: time.Unix(1523031737, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance02_sent_attachmentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\x41\x4f\x83\x40\x10\x85\xef\xfb\x2b\xde\x11\xa2\xbd\x18\xeb\xa5\x27\x14\x0e\x8d\x08\x0d\xd2\xc4\x9e\xc8\x08\x63\x3b\x89\xbb\x34\xbb\x93\xea\xcf\x37\x1c\xd0\x22\xed\xf9\xfb\xf6\x6d\xe6\xbd\xc5\x02\x37\x56\xf6\x9e\x94\xb1\x3d\x9a\xa7\x2a\x4b\xea\x0c\x75\xf2\x98\x67\x78\x65\xa7\x89\x2a\xb5\x07\xcb\x4e\x11\x19\x40\x3a\xbc\xcb\x3e\xb0\x17\xfa\xbc\x35\x80\x7a\x72\x81\x5a\x95\xde\x35\xd2\xe1\x44\xbe\x3d\x90\x8f\x1e\xee\x63\x14\x65\x8d\x62\x9b\xe7\x83\x66\xd9\xf6\x57\x61\x60\xd7\xb1\xff\xc5\x77\xcb\xe5\x25\x7e\x1e\x3f\x53\x3c\xb7\x2c\xa7\x41\x72\x1f\x3d\x94\xbf\x75\x82\xe9\xef\x88\x19\x0b\xec\xb4\x21\x85\x8a\xe5\xa0\x64\x8f\x13\xba\xa9\xd6\x2f\x49\xb5\xc3\x73\xb6\x43\x24\x5d\x6c\xe2\x95\x19\x4b\x5a\x17\x69\xf6\x36\xbe\x1f\x3f\x68\xfe\x35\x52\x16\xb3\x1a\xa7\xc6\x10\x78\x3e\x42\xda\x7f\x39\x93\x56\xe5\xe6\xe2\x08\x2b\xf3\x33\x00\x59\xcb\x3a\xdb\xb1\x01\x00\x00")
+
+func migrations_compliance02_sent_attachmentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance02_sent_attachmentSql,
+		"migrations_compliance/02_sent_attachment.sql",
+	)
+}
+
+func migrations_compliance02_sent_attachmentSql() (*asset, error) {
+	bytes, err := migrations_compliance02_sent_attachmentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/02_sent_attachment.sql", size: 433, mode: os.FileMode(420), modTime: time.Unix(1792030515, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance03_federation_snapshotSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x92\x4f\x53\xc2\x30\x10\xc5\xef\xfd\x14\x7b\x93\x8e\x72\xd0\x11\x2e\x9c\xaa\x2d\x33\x8c\xb5\x30\x15\x66\xe4\x94\x09\xe9\x42\x77\x6c\x93\x4e\x92\xa2\x7c\x7b\x53\x46\xfe\x14\x8b\x1e\x93\xf7\xdb\xcd\xdb\xcd\xeb\xf7\xe1\xb6\xa4\x8d\xe6\x16\x61\x51\x79\xcf\x69\x14\xcc\x23\x98\x07\x4f\x71\x04\x63\xcc\xd0\x09\xa4\xe4\x9b\xe4\x95\xc9\x95\x85\x9e\x07\x40\x19\xac\x68\x63\x50\x13\x2f\xee\xdc\xd9\x6a\x2e\x0d\x17\x0d\xc7\x9c\x26\x72\xae\x7b\xc3\x47\x1f\x92\xe9\x1c\x92\x45\x1c\x37\x4c\xc5\x77\x25\x4a\xdb\xe8\x5b\xae\xf7\xc8\xc3\x60\xe0\x43\x18\x8d\x83\x45\x7c\xe2\x3e\x48\x9e\x88\xfb\x61\xbb\x49\xad\x0b\xb0\xf8\x65\x5b\x97\x6b\x92\xbc\x60\x9d\x92\xb1\xdc\xd6\x86\x09\x95\x21\x90\xb4\xb8\x41\xdd\xd2\x57\x2a\xdb\xfd\xae\xb2\xba\x96\xc2\xad\xc3\x4d\xa9\x54\x81\x5c\x1e\xd5\xa3\xdb\x35\x2f\x0c\xee\xd9\xc2\xb0\x2d\x6a\xe3\x46\xef\x74\x7d\xac\xb8\xb9\x69\x70\x41\x55\x8e\x9a\x99\x9a\xdc\xb6\x0f\xfc\xf9\xaa\x2e\x79\xd4\x96\xd6\xd4\xb8\x31\x1d\x93\xa3\x15\x39\x66\x8c\x5b\xb0\x54\xa2\x9b\xb6\xac\x5a\x80\x46\xa1\x74\xf6\x17\x31\x4b\x27\xaf\x41\xba\x84\x97\x68\x09\x3d\xca\x7c\xcf\x1f\x79\x87\x0c\x4c\x92\x30\x7a\x77\x8f\x1c\x32\xc0\xcc\x4f\x08\xd8\xc5\x87\x4f\x93\xce\xa4\xb4\x29\xd7\xf8\xdf\xbe\x67\x21\xb9\xd2\xf3\x44\x34\x46\xfb\x67\xd9\x0d\xd5\xa7\xf4\xc2\x74\x3a\xbb\x9a\xdd\x91\xf7\x0d\xfe\x32\x19\x6c\xec\x02\x00\x00")
+
+func migrations_compliance03_federation_snapshotSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance03_federation_snapshotSql,
+		"migrations_compliance/03_federation_snapshot.sql",
+	)
+}
+
+func migrations_compliance03_federation_snapshotSql() (*asset, error) {
+	bytes, err := migrations_compliance03_federation_snapshotSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/03_federation_snapshot.sql", size: 748, mode: os.FileMode(420), modTime: time.Unix(1792037395, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance04_sanctions_screeningSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x52\xc1\x4e\x83\x40\x10\xbd\xf3\x15\x73\x2b\x8d\x72\xd0\x58\x2e\x3d\xa1\x60\xd2\x88\xd0\x50\x9a\xd8\x13\x19\x77\x47\xba\x09\x2c\xcd\xee\xb6\xfa\xf9\x2e\x44\x6a\x8b\x10\x8f\x93\xf7\xe6\xbd\x97\x79\xe3\x79\x70\x53\x8b\x52\xa1\x21\xd8\x1e\x9c\xa7\x2c\x0a\xf2\x08\xf2\xe0\x31\x8e\x60\x83\x92\x19\xd1\x48\xbd\x61\x8a\x48\x0a\x59\x82\xeb\x00\x08\x0e\xef\xa2\xd4\xa4\x04\x56\xb7\x76\x36\x0a\xa5\xc6\x8e\x59\x58\xec\x84\x8a\xed\x51\xb9\xfe\xc3\x1c\x92\x34\x87\x64\x1b\xc7\x10\x46\xcf\xc1\x36\xce\x61\x36\x6b\x37\x54\x53\xd1\x99\x77\xe7\xff\xf2\x5a\x10\x39\x57\xa4\xf5\x19\xbf\x5f\x2c\x06\x04\xc6\x9a\xa3\x34\x97\x5e\x0b\x7f\xd2\x4b\x62\x4d\xe3\x5a\x03\xe2\x41\x35\x27\xc1\x49\x4d\x1b\x6b\x83\xe6\xa8\x27\x83\xd7\x68\xd8\x9e\x34\x18\xfa\x32\x57\x00\x27\x83\xa2\x1a\x01\x18\xda\x05\x7b\xcd\xc6\xde\x03\xe5\xdf\x60\x1f\x58\x69\xea\x9c\xbb\x02\x88\x17\x68\xc0\x88\x9a\x6c\x92\xfa\x70\x25\xb5\xce\x56\xaf\x41\xb6\x83\x97\x68\x07\xae\xe0\x73\x67\xbe\x74\xfa\x36\x57\x49\x18\xbd\x81\xee\xdb\x2c\x74\x5f\x67\x31\xa8\x2e\x4d\x46\x3b\xbf\x66\x59\xe1\x7f\x75\xfb\x0e\x27\x04\x7f\xe0\x36\xa2\x77\xf1\x7f\x61\xf3\x29\x9d\x30\x4b\xd7\x93\xff\xb7\x74\xbe\x01\x2c\x5f\x26\x2f\xb0\x02\x00\x00")
+
+func migrations_compliance04_sanctions_screeningSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance04_sanctions_screeningSql,
+		"migrations_compliance/04_sanctions_screening.sql",
+	)
+}
+
+func migrations_compliance04_sanctions_screeningSql() (*asset, error) {
+	bytes, err := migrations_compliance04_sanctions_screeningSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/04_sanctions_screening.sql", size: 688, mode: os.FileMode(420), modTime: time.Unix(1792042765, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance05_compliance_auditSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x92\xcd\x6e\x83\x30\x10\x84\xef\x7e\x8a\xbd\x05\xd4\x72\x68\xda\xe4\x92\x13\x0d\xae\x84\x4a\x21\x45\x20\x35\x27\xb4\xb5\xad\x60\x29\xfc\xd4\x98\xb4\x79\xfb\x1a\xa9\x24\x21\x01\xf5\x68\xcf\xec\xa7\xdd\xd9\x75\x1c\xb8\x2b\xe4\x4e\xa1\x16\x90\xd6\x64\x1d\x53\x37\xa1\x90\xb8\xcf\x01\x85\x75\x55\xd4\x7b\x89\x25\x13\x6e\xcb\xa5\x06\x8b\x00\x48\x0e\x9f\x72\xd7\x08\x25\x71\x7f\x6f\xde\x8d\xf8\x6a\x85\x71\x74\xbf\xb2\xd4\x10\x46\x09\x84\x69\x10\x74\x9a\x3e\xd6\x02\x0e\xa8\x58\x8e\xca\x7a\x9c\xdb\x43\x51\x61\xd9\x20\xd3\xb2\x2a\x33\x03\xed\x6d\xcb\xa7\xb3\x0d\x3c\xfa\xe2\xa6\x41\x02\xb3\x59\x57\xc1\xaa\xb6\xd4\x42\xd5\xa8\xf4\xf1\xe4\x9f\x2f\x16\x93\x05\x5c\x30\xd9\x18\xfe\xc9\xfc\xb0\x1c\xf6\xc0\x51\x23\x68\xf1\x33\x6c\xbb\x56\xe2\x90\xe5\xd8\xe4\x70\xd3\x51\x27\x4f\x2b\x4c\x09\x13\x23\xcf\x50\x83\x96\x85\x68\x34\x16\xf5\xc0\xb0\x89\xfd\x37\x37\xde\xc2\x2b\xdd\x82\x25\xb9\x4d\xec\x15\xe9\x23\x4f\x43\xff\x3d\xa5\xe0\x87\x1e\xfd\x30\xa3\xf6\xc9\x67\xd8\x45\x9f\x9d\x62\x8e\xc2\xdb\xb5\xf4\xa2\xa1\xfd\xc1\x26\x28\x57\x99\x8f\xb1\x86\x96\x7f\x89\x17\x23\x8f\xd1\xce\x72\x37\xa9\x73\x71\x6b\x5e\xf5\x5d\x12\x2f\x8e\x36\xe3\xb7\xb6\x22\xbf\xa1\x9a\xe4\xcb\x99\x02\x00\x00")
+
+func migrations_compliance05_compliance_auditSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance05_compliance_auditSql,
+		"migrations_compliance/05_compliance_audit.sql",
+	)
+}
+
+func migrations_compliance05_compliance_auditSql() (*asset, error) {
+	bytes, err := migrations_compliance05_compliance_auditSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/05_compliance_audit.sql", size: 665, mode: os.FileMode(420), modTime: time.Unix(1792042944, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance06_attachmentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x65\x90\xcb\x6e\x83\x30\x10\x45\xf7\xfe\x8a\xd9\x05\xd4\xb2\xa9\x9a\xaa\x52\x56\x4e\x71\x25\x14\x62\x52\x84\xa5\x64\x85\xa6\x60\x05\x4b\xb5\x41\x66\xfa\xfa\xfb\x42\xa4\x84\xd0\x2c\xad\x7b\xe6\x8e\xe7\x44\x11\xdc\x59\x73\xf4\x48\x1a\x54\xc7\x5e\x72\xc1\x0b\x01\x05\x5f\xa7\x02\x38\x11\x56\x8d\xd5\x8e\x20\x60\x00\xa6\x86\x77\x73\xec\xb5\x37\xf8\x71\x3f\xbc\xad\xb6\x6d\xd9\x60\xdf\x40\xd5\xa0\x0f\x9e\x1e\x43\x90\x59\x01\x52\xa5\xe9\x18\xd7\xc6\xeb\x8a\x4c\xeb\xe0\x0b\xfd\x89\x78\x9e\x03\xe4\xd1\xf5\x78\x42\xca\xa1\xfb\x4c\xfd\xef\xa9\xda\x4f\x47\xda\x77\xe8\xe9\xf7\x02\x3d\x2c\x97\x13\x05\xb1\x78\xe5\x2a\x2d\x60\xb1\x18\x07\x70\xfa\x36\xe9\x1f\x9a\x97\x79\x3d\x5c\x5a\x97\x38\x64\xc6\xea\x9e\xd0\x76\x33\x60\x97\x27\x5b\x9e\x1f\x60\x23\x0e\x10\x98\x3a\x64\xe1\x8a\x9d\xad\x28\x99\xbc\x29\x01\x89\x8c\xc5\xfe\x6a\x4b\x39\x89\xc8\xe4\x4c\xda\x25\x18\x4b\xa2\x2b\xd3\x71\xfb\xed\x58\x9c\x67\xbb\x1b\xd3\x2b\xf6\x07\xcd\x0a\x46\x49\x92\x01\x00\x00")
+
+func migrations_compliance06_attachmentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance06_attachmentSql,
+		"migrations_compliance/06_attachment.sql",
+	)
+}
+
+func migrations_compliance06_attachmentSql() (*asset, error) {
+	bytes, err := migrations_compliance06_attachmentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/06_attachment.sql", size: 402, mode: os.FileMode(420), modTime: time.Unix(1792043136, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -289,21 +980,60 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"migrations_gateway/01_init.sql":           migrations_gateway01_initSql,
-	"migrations_gateway/02_payment_id.sql":     migrations_gateway02_payment_idSql,
-	"migrations_gateway/03_transaction_id.sql": migrations_gateway03_transaction_idSql,
-	"migrations_compliance/01_init.sql":        migrations_compliance01_initSql,
+	"migrations_gateway/01_init.sql":                      migrations_gateway01_initSql,
+	"migrations_gateway/02_payment_id.sql":                migrations_gateway02_payment_idSql,
+	"migrations_gateway/03_transaction_id.sql":            migrations_gateway03_transaction_idSql,
+	"migrations_gateway/04_held_payment.sql":              migrations_gateway04_held_paymentSql,
+	"migrations_gateway/05_compliance_repair.sql":         migrations_gateway05_compliance_repairSql,
+	"migrations_gateway/06_sent_transaction_region.sql":   migrations_gateway06_sent_transaction_regionSql,
+	"migrations_gateway/07_bad_seq_resolution.sql":        migrations_gateway07_bad_seq_resolutionSql,
+	"migrations_gateway/08_memo_required_destination.sql": migrations_gateway08_memo_required_destinationSql,
+	"migrations_gateway/09_feature_flag.sql":              migrations_gateway09_feature_flagSql,
+	"migrations_gateway/10_security_event.sql":            migrations_gateway10_security_eventSql,
+	"migrations_gateway/11_api_key.sql":                   migrations_gateway11_api_keySql,
+	"migrations_gateway/12_destination_profile.sql":       migrations_gateway12_destination_profileSql,
+	"migrations_gateway/13_daily_aggregate.sql":           migrations_gateway13_daily_aggregateSql,
+	"migrations_gateway/14_federation_snapshot.sql":       migrations_gateway14_federation_snapshotSql,
+	"migrations_gateway/15_received_payment_event.sql": migrations_gateway15_received_payment_eventSql,
+	"migrations_gateway/16_payment_limit_usage.sql": migrations_gateway16_payment_limit_usageSql,
+	"migrations_gateway/17_named_destination.sql": migrations_gateway17_named_destinationSql,
+	"migrations_gateway/18_held_payment_approval.sql": migrations_gateway18_held_payment_approvalSql,
+	"migrations_gateway/19_payment_velocity.sql": migrations_gateway19_payment_velocitySql,
+	"migrations_gateway/20_expected_payment.sql": migrations_gateway20_expected_paymentSql,
+	"migrations_gateway/21_refund.sql": migrations_gateway21_refundSql,
+	"migrations_gateway/22_failed_callback.sql": migrations_gateway22_failed_callbackSql,
+	"migrations_gateway/23_offline_transaction.sql": migrations_gateway23_offline_transactionSql,
+	"migrations_gateway/24_quote.sql": migrations_gateway24_quoteSql,
+	"migrations_gateway/25_fiat_value.sql": migrations_gateway25_fiat_valueSql,
+	"migrations_gateway/26_webhook.sql": migrations_gateway26_webhookSql,
+	"migrations_gateway/27_webhook_format.sql": migrations_gateway27_webhook_formatSql,
+	"migrations_gateway/28_memo_reference.sql": migrations_gateway28_memo_referenceSql,
+	"migrations_gateway/29_sent_transaction_metadata.sql": migrations_gateway29_sent_transaction_metadataSql,
+	"migrations_gateway/30_leader_lease.sql": migrations_gateway30_leader_leaseSql,
+	"migrations_gateway/31_sep24_transaction.sql": migrations_gateway31_sep24_transactionSql,
+	"migrations_gateway/32_held_payment_seed.sql": migrations_gateway32_held_payment_seedSql,
+	"migrations_gateway/33_sent_transaction_conflict.sql": migrations_gateway33_sent_transaction_conflictSql,
+	"migrations_gateway/34_sent_transaction_original_id.sql": migrations_gateway34_sent_transaction_original_idSql,
+	"migrations_gateway/35_api_key_tenant.sql": migrations_gateway35_api_key_tenantSql,
+	"migrations_compliance/01_init.sql":                   migrations_compliance01_initSql,
+	"migrations_compliance/02_sent_attachment.sql":        migrations_compliance02_sent_attachmentSql,
+	"migrations_compliance/03_federation_snapshot.sql":    migrations_compliance03_federation_snapshotSql,
+	"migrations_compliance/04_sanctions_screening.sql": migrations_compliance04_sanctions_screeningSql,
+	"migrations_compliance/05_compliance_audit.sql": migrations_compliance05_compliance_auditSql,
+	"migrations_compliance/06_attachment.sql": migrations_compliance06_attachmentSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"}
 // AssetDir("data/img") would return []string{"a.png", "b.png"}
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error
@@ -337,12 +1067,49 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"migrations_compliance": &bintree{nil, map[string]*bintree{
-		"01_init.sql": &bintree{migrations_compliance01_initSql, map[string]*bintree{}},
+		"01_init.sql":                &bintree{migrations_compliance01_initSql, map[string]*bintree{}},
+		"02_sent_attachment.sql":     &bintree{migrations_compliance02_sent_attachmentSql, map[string]*bintree{}},
+		"03_federation_snapshot.sql": &bintree{migrations_compliance03_federation_snapshotSql, map[string]*bintree{}},
+		"04_sanctions_screening.sql": &bintree{migrations_compliance04_sanctions_screeningSql, map[string]*bintree{}},
+		"05_compliance_audit.sql": &bintree{migrations_compliance05_compliance_auditSql, map[string]*bintree{}},
+		"06_attachment.sql": &bintree{migrations_compliance06_attachmentSql, map[string]*bintree{}},
 	}},
 	"migrations_gateway": &bintree{nil, map[string]*bintree{
-		"01_init.sql":           &bintree{migrations_gateway01_initSql, map[string]*bintree{}},
-		"02_payment_id.sql":     &bintree{migrations_gateway02_payment_idSql, map[string]*bintree{}},
-		"03_transaction_id.sql": &bintree{migrations_gateway03_transaction_idSql, map[string]*bintree{}},
+		"01_init.sql":                      &bintree{migrations_gateway01_initSql, map[string]*bintree{}},
+		"02_payment_id.sql":                &bintree{migrations_gateway02_payment_idSql, map[string]*bintree{}},
+		"03_transaction_id.sql":            &bintree{migrations_gateway03_transaction_idSql, map[string]*bintree{}},
+		"04_held_payment.sql":              &bintree{migrations_gateway04_held_paymentSql, map[string]*bintree{}},
+		"05_compliance_repair.sql":         &bintree{migrations_gateway05_compliance_repairSql, map[string]*bintree{}},
+		"06_sent_transaction_region.sql":   &bintree{migrations_gateway06_sent_transaction_regionSql, map[string]*bintree{}},
+		"07_bad_seq_resolution.sql":        &bintree{migrations_gateway07_bad_seq_resolutionSql, map[string]*bintree{}},
+		"08_memo_required_destination.sql": &bintree{migrations_gateway08_memo_required_destinationSql, map[string]*bintree{}},
+		"09_feature_flag.sql":              &bintree{migrations_gateway09_feature_flagSql, map[string]*bintree{}},
+		"10_security_event.sql":            &bintree{migrations_gateway10_security_eventSql, map[string]*bintree{}},
+		"11_api_key.sql":                   &bintree{migrations_gateway11_api_keySql, map[string]*bintree{}},
+		"12_destination_profile.sql":       &bintree{migrations_gateway12_destination_profileSql, map[string]*bintree{}},
+		"13_daily_aggregate.sql":           &bintree{migrations_gateway13_daily_aggregateSql, map[string]*bintree{}},
+		"14_federation_snapshot.sql":       &bintree{migrations_gateway14_federation_snapshotSql, map[string]*bintree{}},
+		"15_received_payment_event.sql": &bintree{migrations_gateway15_received_payment_eventSql, map[string]*bintree{}},
+		"16_payment_limit_usage.sql": &bintree{migrations_gateway16_payment_limit_usageSql, map[string]*bintree{}},
+		"17_named_destination.sql": &bintree{migrations_gateway17_named_destinationSql, map[string]*bintree{}},
+		"18_held_payment_approval.sql": &bintree{migrations_gateway18_held_payment_approvalSql, map[string]*bintree{}},
+		"19_payment_velocity.sql": &bintree{migrations_gateway19_payment_velocitySql, map[string]*bintree{}},
+		"20_expected_payment.sql": &bintree{migrations_gateway20_expected_paymentSql, map[string]*bintree{}},
+		"21_refund.sql": &bintree{migrations_gateway21_refundSql, map[string]*bintree{}},
+		"22_failed_callback.sql": &bintree{migrations_gateway22_failed_callbackSql, map[string]*bintree{}},
+		"23_offline_transaction.sql": &bintree{migrations_gateway23_offline_transactionSql, map[string]*bintree{}},
+		"24_quote.sql": &bintree{migrations_gateway24_quoteSql, map[string]*bintree{}},
+		"25_fiat_value.sql": &bintree{migrations_gateway25_fiat_valueSql, map[string]*bintree{}},
+		"26_webhook.sql": &bintree{migrations_gateway26_webhookSql, map[string]*bintree{}},
+		"27_webhook_format.sql": &bintree{migrations_gateway27_webhook_formatSql, map[string]*bintree{}},
+		"28_memo_reference.sql": &bintree{migrations_gateway28_memo_referenceSql, map[string]*bintree{}},
+		"29_sent_transaction_metadata.sql": &bintree{migrations_gateway29_sent_transaction_metadataSql, map[string]*bintree{}},
+		"30_leader_lease.sql": &bintree{migrations_gateway30_leader_leaseSql, map[string]*bintree{}},
+		"31_sep24_transaction.sql": &bintree{migrations_gateway31_sep24_transactionSql, map[string]*bintree{}},
+		"32_held_payment_seed.sql": &bintree{migrations_gateway32_held_payment_seedSql, map[string]*bintree{}},
+		"33_sent_transaction_conflict.sql": &bintree{migrations_gateway33_sent_transaction_conflictSql, map[string]*bintree{}},
+		"34_sent_transaction_original_id.sql": &bintree{migrations_gateway34_sent_transaction_original_idSql, map[string]*bintree{}},
+		"35_api_key_tenant.sql": &bintree{migrations_gateway35_api_key_tenantSql, map[string]*bintree{}},
 	}},
 }}
 
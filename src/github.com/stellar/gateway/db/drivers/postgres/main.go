@@ -3,6 +3,7 @@ package postgres
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 	// To load pq driver
 	_ "github.com/lib/pq"
 	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/db/entities"
 )
 
@@ -17,26 +19,75 @@ import (
 
 // Driver implements Driver interface using Postgres connection
 type Driver struct {
+	// NotifyChannel is a channel of NOTIFY events sent when a received
+	// payment or sent transaction is written. Disabled when empty.
+	NotifyChannel string
+	// Schema is the search_path of connections, tables are created in it by
+	// migrations. Tables of the default schema are used when empty.
+	Schema   string
 	database *sqlx.DB
 }
 
 // Init initializes DB connection
 func (d *Driver) Init(url string) (err error) {
+	if d.Schema != "" {
+		url, err = withSearchPath(url, d.Schema)
+		if err != nil {
+			return
+		}
+	}
 	d.database, err = sqlx.Connect("postgres", url)
 	return
 }
 
+// withSearchPath adds search_path run-time parameter to url (URL or
+// key=value connection string)
+func withSearchPath(connection, schema string) (string, error) {
+	if !strings.HasPrefix(connection, "postgres://") && !strings.HasPrefix(connection, "postgresql://") {
+		return connection + " search_path=" + schema, nil
+	}
+
+	parsed, err := url.Parse(connection)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set("search_path", schema)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
 func (d *Driver) DB() *sqlx.DB {
 	return d.database
 }
 
-// MigrateUp migrates DB using migrate files
+// MigrateUp migrates DB using migrate files. Schema is created when it does
+// not exist.
 func (d *Driver) MigrateUp(component string) (migrationsApplied int, err error) {
+	if d.Schema != "" {
+		_, err = d.database.Exec("CREATE SCHEMA IF NOT EXISTS " + d.Schema)
+		if err != nil {
+			return
+		}
+	}
+
 	source := d.getAssetMigrationSource(component)
 	migrationsApplied, err = migrate.Exec(d.database.DB, "postgres", source, migrate.Up)
 	return
 }
 
+// MigrateDown rolls back max migrations (all when 0)
+func (d *Driver) MigrateDown(component string, max int) (migrationsApplied int, err error) {
+	source := d.getAssetMigrationSource(component)
+	migrationsApplied, err = migrate.ExecMax(d.database.DB, "postgres", source, migrate.Down, max)
+	return
+}
+
+// MigrationStatus returns applied and pending migrations
+func (d *Driver) MigrationStatus(component string) (db.MigrationStatus, error) {
+	return db.GetMigrationStatus(d.database.DB, "postgres", d.getAssetMigrationSource(component))
+}
+
 // Insert inserts the entity to a DB
 func (d *Driver) Insert(object entities.Entity) (id int64, err error) {
 	value, tableName, err := getTypeData(object)
@@ -68,42 +119,98 @@ func (d *Driver) Insert(object entities.Entity) (id int64, err error) {
 
 	query := "INSERT INTO " + tableName + " (" + strings.Join(fieldNames, ", ") + ") VALUES (" + strings.Join(fieldValues, ", ") + ") RETURNING id;"
 
-	// TODO cache prepared statement
-	stmt, err := d.database.PrepareNamed(query)
-	if err != nil {
-		return
-	}
+	err = d.write(object, NotificationActionInsert, func(e execer) error {
+		// TODO cache prepared statement
+		stmt, err := e.PrepareNamed(query)
+		if err != nil {
+			return err
+		}
 
-	switch object := object.(type) {
-	case *entities.AuthorizedTransaction:
-		err = stmt.Get(&id, object)
-	case *entities.AllowedFi:
-		err = stmt.Get(&id, object)
-	case *entities.AllowedUser:
-		err = stmt.Get(&id, object)
-	case *entities.SentTransaction:
-		err = stmt.Get(&id, object)
-	case *entities.ReceivedPayment:
-		err = stmt.Get(&id, object)
-	}
+		switch object := object.(type) {
+		case *entities.AuthorizedTransaction:
+			err = stmt.Get(&id, object)
+		case *entities.AllowedFi:
+			err = stmt.Get(&id, object)
+		case *entities.AllowedUser:
+			err = stmt.Get(&id, object)
+		case *entities.SentTransaction:
+			err = stmt.Get(&id, object)
+		case *entities.ReceivedPayment:
+			err = stmt.Get(&id, object)
+		case *entities.HeldPayment:
+			err = stmt.Get(&id, object)
+		case *entities.ComplianceRepair:
+			err = stmt.Get(&id, object)
+		case *entities.SEP24Transaction:
+			err = stmt.Get(&id, object)
+		case *entities.MemoReference:
+			err = stmt.Get(&id, object)
+		case *entities.Webhook:
+			err = stmt.Get(&id, object)
+		case *entities.FiatValue:
+			err = stmt.Get(&id, object)
+		case *entities.Quote:
+			err = stmt.Get(&id, object)
+		case *entities.OfflineTransaction:
+			err = stmt.Get(&id, object)
+		case *entities.FailedCallback:
+			err = stmt.Get(&id, object)
+		case *entities.Refund:
+			err = stmt.Get(&id, object)
+		case *entities.PaymentMatch:
+			err = stmt.Get(&id, object)
+		case *entities.ExpectedPayment:
+			err = stmt.Get(&id, object)
+		case *entities.Attachment:
+			err = stmt.Get(&id, object)
+		case *entities.ComplianceAuditEntry:
+			err = stmt.Get(&id, object)
+		case *entities.SanctionsScreening:
+			err = stmt.Get(&id, object)
+		case *entities.PaymentVelocity:
+			err = stmt.Get(&id, object)
+		case *entities.NamedDestination:
+			err = stmt.Get(&id, object)
+		case *entities.PaymentLimitUsage:
+			err = stmt.Get(&id, object)
+		case *entities.ReceivedPaymentEvent:
+			err = stmt.Get(&id, object)
+		case *entities.FederationSnapshot:
+			err = stmt.Get(&id, object)
+		case *entities.DailyAggregate:
+			err = stmt.Get(&id, object)
+		case *entities.APIKey:
+			err = stmt.Get(&id, object)
+		case *entities.SecurityEvent:
+			err = stmt.Get(&id, object)
+		case *entities.SentAttachment:
+			err = stmt.Get(&id, object)
+		case *entities.FeatureFlag:
+			err = stmt.Get(&id, object)
+		case *entities.MemoRequiredDestination:
+			err = stmt.Get(&id, object)
+		}
 
-	if err != nil {
-		return
-	}
+		if err != nil {
+			return err
+		}
 
-	if id == 0 {
-		// Not autoincrement
-		if object.GetID() == nil {
-			return 0, fmt.Errorf("Not autoincrement but ID nil")
+		if id == 0 {
+			// Not autoincrement
+			if object.GetID() == nil {
+				return fmt.Errorf("Not autoincrement but ID nil")
+			}
+			id = *object.GetID()
 		}
-		id = *object.GetID()
-	}
 
-	if err == nil {
 		object.SetID(id)
-		object.SetExists()
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
+	object.SetExists()
 	return
 }
 
@@ -130,20 +237,74 @@ func (d *Driver) Update(object entities.Entity) (err error) {
 
 	query += strings.Join(fields, ", ") + " WHERE id = :id;"
 
-	switch object := object.(type) {
-	case *entities.AuthorizedTransaction:
-		_, err = d.database.NamedExec(query, object)
-	case *entities.AllowedFi:
-		_, err = d.database.NamedExec(query, object)
-	case *entities.AllowedUser:
-		_, err = d.database.NamedExec(query, object)
-	case *entities.SentTransaction:
-		_, err = d.database.NamedExec(query, object)
-	case *entities.ReceivedPayment:
-		_, err = d.database.NamedExec(query, object)
-	}
+	return d.write(object, NotificationActionUpdate, func(e execer) (err error) {
+		switch object := object.(type) {
+		case *entities.AuthorizedTransaction:
+			_, err = e.NamedExec(query, object)
+		case *entities.AllowedFi:
+			_, err = e.NamedExec(query, object)
+		case *entities.AllowedUser:
+			_, err = e.NamedExec(query, object)
+		case *entities.SentTransaction:
+			_, err = e.NamedExec(query, object)
+		case *entities.ReceivedPayment:
+			_, err = e.NamedExec(query, object)
+		case *entities.HeldPayment:
+			_, err = e.NamedExec(query, object)
+		case *entities.ComplianceRepair:
+			_, err = e.NamedExec(query, object)
+		case *entities.SEP24Transaction:
+			_, err = e.NamedExec(query, object)
+		case *entities.MemoReference:
+			_, err = e.NamedExec(query, object)
+		case *entities.Webhook:
+			_, err = e.NamedExec(query, object)
+		case *entities.FiatValue:
+			_, err = e.NamedExec(query, object)
+		case *entities.Quote:
+			_, err = e.NamedExec(query, object)
+		case *entities.OfflineTransaction:
+			_, err = e.NamedExec(query, object)
+		case *entities.FailedCallback:
+			_, err = e.NamedExec(query, object)
+		case *entities.Refund:
+			_, err = e.NamedExec(query, object)
+		case *entities.PaymentMatch:
+			_, err = e.NamedExec(query, object)
+		case *entities.ExpectedPayment:
+			_, err = e.NamedExec(query, object)
+		case *entities.Attachment:
+			_, err = e.NamedExec(query, object)
+		case *entities.ComplianceAuditEntry:
+			_, err = e.NamedExec(query, object)
+		case *entities.SanctionsScreening:
+			_, err = e.NamedExec(query, object)
+		case *entities.PaymentVelocity:
+			_, err = e.NamedExec(query, object)
+		case *entities.NamedDestination:
+			_, err = e.NamedExec(query, object)
+		case *entities.PaymentLimitUsage:
+			_, err = e.NamedExec(query, object)
+		case *entities.ReceivedPaymentEvent:
+			_, err = e.NamedExec(query, object)
+		case *entities.FederationSnapshot:
+			_, err = e.NamedExec(query, object)
+		case *entities.DailyAggregate:
+			_, err = e.NamedExec(query, object)
+		case *entities.APIKey:
+			_, err = e.NamedExec(query, object)
+		case *entities.SecurityEvent:
+			_, err = e.NamedExec(query, object)
+		case *entities.SentAttachment:
+			_, err = e.NamedExec(query, object)
+		case *entities.FeatureFlag:
+			_, err = e.NamedExec(query, object)
+		case *entities.MemoRequiredDestination:
+			_, err = e.NamedExec(query, object)
+		}
 
-	return
+		return
+	})
 }
 
 // Delete delets the entity from a DB
@@ -225,6 +386,13 @@ func (d *Driver) GetMany(slice interface{}, where, order, offset, limit *string,
 			tmp[i].SetExists()
 		}
 		slice = &tmp
+	case *[]*entities.HeldPayment:
+		err = d.database.Select(slice, query.String(), params...)
+		tmp := *slice
+		for i := range tmp {
+			tmp[i].SetExists()
+		}
+		slice = &tmp
 	}
 
 	if err != nil && err.Error() == "sql: no rows in result set" {
@@ -255,6 +423,86 @@ func getTypeData(object interface{}) (typeValue reflect.Type, tableName string,
 		tableName = "SentTransaction"
 	case *[]*entities.ReceivedPayment:
 		tableName = "ReceivedPayment"
+	case *entities.HeldPayment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "HeldPayment"
+	case *[]*entities.HeldPayment:
+		tableName = "HeldPayment"
+	case *entities.ComplianceRepair:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ComplianceRepair"
+	case *entities.SEP24Transaction:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SEP24Transaction"
+	case *entities.MemoReference:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "MemoReference"
+	case *entities.Webhook:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Webhook"
+	case *entities.FiatValue:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FiatValue"
+	case *entities.Quote:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Quote"
+	case *entities.OfflineTransaction:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "OfflineTransaction"
+	case *entities.FailedCallback:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FailedCallback"
+	case *entities.Refund:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Refund"
+	case *entities.PaymentMatch:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentMatch"
+	case *entities.ExpectedPayment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ExpectedPayment"
+	case *entities.Attachment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Attachment"
+	case *entities.ComplianceAuditEntry:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ComplianceAudit"
+	case *entities.SanctionsScreening:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SanctionsScreening"
+	case *entities.PaymentVelocity:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentVelocity"
+	case *entities.NamedDestination:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "NamedDestination"
+	case *entities.PaymentLimitUsage:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentLimitUsage"
+	case *entities.ReceivedPaymentEvent:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ReceivedPaymentEvent"
+	case *entities.FederationSnapshot:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FederationSnapshot"
+	case *entities.DailyAggregate:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "DailyAggregate"
+	case *entities.APIKey:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "APIKey"
+	case *entities.SecurityEvent:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SecurityEvent"
+	case *entities.SentAttachment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SentAttachment"
+	case *entities.FeatureFlag:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FeatureFlag"
+	case *entities.MemoRequiredDestination:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "MemoRequiredDestination"
 	default:
 		return typeValue, tableName, fmt.Errorf("Unknown entity type: %T", object)
 	}
@@ -0,0 +1,18 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSearchPath(t *testing.T) {
+	url, err := withSearchPath("postgres://localhost/bridge?sslmode=disable", "testnet")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://localhost/bridge?search_path=testnet&sslmode=disable", url)
+
+	url, err = withSearchPath("host=localhost dbname=bridge", "testnet")
+	require.NoError(t, err)
+	assert.Equal(t, "host=localhost dbname=bridge search_path=testnet", url)
+}
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE SentAttachment (
+  id bigserial,
+  transaction_id varchar(64) NOT NULL,
+  memo varchar(64) NOT NULL,
+  sender varchar(255) NOT NULL,
+  sender_id varchar(255) NOT NULL,
+  receiver_info text NOT NULL,
+  attachment text NOT NULL,
+  sent_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX sent_attachment_transaction_id ON SentAttachment (transaction_id);
+
+-- +migrate Down
+DROP TABLE SentAttachment;
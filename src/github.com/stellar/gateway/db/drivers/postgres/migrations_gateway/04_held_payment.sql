@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE HeldPayment (
+  id bigserial,
+  payment_id varchar(255) UNIQUE NOT NULL,
+  source_account varchar(56) NOT NULL,
+  request text NOT NULL,
+  status varchar(10) NOT NULL,
+  created_at timestamp NOT NULL,
+  settle_at timestamp NOT NULL,
+  cancelled_at timestamp DEFAULT NULL,
+  result text DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX held_payment_status_settle_at ON HeldPayment (status, settle_at);
+
+-- +migrate Down
+DROP TABLE HeldPayment;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE ComplianceRepair (
+  id bigserial,
+  operation_id varchar(255) NOT NULL,
+  original_memo varchar(255) NOT NULL,
+  compliance_memo varchar(255) NOT NULL,
+  verified_by varchar(255) NOT NULL,
+  reason text NOT NULL,
+  repaired_at timestamp NOT NULL,
+  status varchar(255) NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX compliance_repair_operation_id ON ComplianceRepair (operation_id);
+
+-- +migrate Down
+DROP TABLE ComplianceRepair;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE SentTransaction ADD region VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE SentTransaction DROP CONSTRAINT payment_id_unique;
+ALTER TABLE SentTransaction ADD CONSTRAINT region_payment_id_unique UNIQUE (region, payment_id);
+
+-- +migrate Down
+ALTER TABLE SentTransaction DROP CONSTRAINT region_payment_id_unique;
+ALTER TABLE SentTransaction ADD CONSTRAINT payment_id_unique UNIQUE (payment_id);
+ALTER TABLE SentTransaction DROP region;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE SentTransaction ADD bad_seq_resolution VARCHAR(16) NULL DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE SentTransaction DROP bad_seq_resolution;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE MemoRequiredDestination (
+  id bigserial,
+  account_id varchar(56) NOT NULL,
+  source varchar(16) NOT NULL,
+  reason text NOT NULL,
+  transaction_id char(64) NULL DEFAULT NULL,
+  created_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX memo_required_destination_account_id ON MemoRequiredDestination (account_id);
+
+-- +migrate Down
+DROP TABLE MemoRequiredDestination;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE FeatureFlag (
+  id bigserial,
+  name varchar(64) NOT NULL,
+  tenant varchar(64) NOT NULL,
+  enabled boolean NOT NULL,
+  updated_by varchar(255) NOT NULL,
+  updated_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX feature_flag_name_tenant ON FeatureFlag (name, tenant);
+
+-- +migrate Down
+DROP TABLE FeatureFlag;
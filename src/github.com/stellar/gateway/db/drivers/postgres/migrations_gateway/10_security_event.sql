@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE SecurityEvent (
+  id bigserial,
+  event_id varchar(64) NOT NULL,
+  type varchar(32) NOT NULL,
+  format varchar(8) NOT NULL,
+  payload text NOT NULL,
+  created_at timestamp NOT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  next_attempt_at timestamp NOT NULL,
+  delivered_at timestamp DEFAULT NULL,
+  last_error text DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX security_event_delivered_at_next_attempt_at ON SecurityEvent (delivered_at, next_attempt_at);
+
+-- +migrate Down
+DROP TABLE SecurityEvent;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE MemoRequiredDestination ADD profile varchar(64) DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE MemoRequiredDestination DROP COLUMN profile;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE DailyAggregate (
+  id bigserial,
+  day varchar(10) NOT NULL,
+  tenant varchar(64) NOT NULL DEFAULT '',
+  direction varchar(10) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  count bigint NOT NULL DEFAULT 0,
+  amount bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX daily_aggregate_aggregate ON DailyAggregate (day, tenant, direction, asset_code, asset_issuer);
+
+-- +migrate Down
+DROP TABLE DailyAggregate;
@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE ReceivedPaymentEvent (
+  id bigserial,
+  operation_id varchar(255) NOT NULL,
+  payload text NOT NULL,
+  created_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX received_payment_event_created_at ON ReceivedPaymentEvent (created_at);
+
+-- +migrate Down
+DROP TABLE ReceivedPaymentEvent;
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE NamedDestination (
+  id bigserial,
+  name varchar(64) NOT NULL,
+  destination varchar(255) NOT NULL,
+  memo_type varchar(16) NOT NULL DEFAULT '',
+  memo varchar(255) NOT NULL DEFAULT '',
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  created_at timestamp NOT NULL,
+  updated_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX named_destination_name ON NamedDestination (name);
+
+-- +migrate Down
+DROP TABLE NamedDestination;
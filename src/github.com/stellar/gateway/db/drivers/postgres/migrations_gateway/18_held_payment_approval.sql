@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE HeldPayment ADD created_by varchar(64) DEFAULT NULL;
+ALTER TABLE HeldPayment ADD approved_by varchar(64) DEFAULT NULL;
+ALTER TABLE HeldPayment ADD approved_at timestamp DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE HeldPayment DROP COLUMN created_by;
+ALTER TABLE HeldPayment DROP COLUMN approved_by;
+ALTER TABLE HeldPayment DROP COLUMN approved_at;
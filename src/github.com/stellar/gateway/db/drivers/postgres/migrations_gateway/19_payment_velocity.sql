@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE PaymentVelocity (
+  id bigserial,
+  source varchar(56) NOT NULL,
+  destination varchar(255) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  amount bigint NOT NULL,
+  created_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX payment_velocity_source ON PaymentVelocity (source, created_at);
+CREATE INDEX payment_velocity_destination ON PaymentVelocity (destination, created_at);
+CREATE INDEX payment_velocity_created_at ON PaymentVelocity (created_at);
+
+-- +migrate Down
+DROP TABLE PaymentVelocity;
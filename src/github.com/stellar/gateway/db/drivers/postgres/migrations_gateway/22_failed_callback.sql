@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE FailedCallback (
+  id bigserial,
+  operation_id varchar(255) NOT NULL,
+  payload text NOT NULL,
+  last_error text NOT NULL,
+  attempts integer NOT NULL,
+  status varchar(16) NOT NULL,
+  created_at timestamp NOT NULL,
+  updated_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX failed_callback_operation_id ON FailedCallback (operation_id);
+CREATE INDEX failed_callback_status ON FailedCallback (status);
+
+-- +migrate Down
+DROP TABLE FailedCallback;
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE Quote (
+  id bigserial,
+  quote_id varchar(64) NOT NULL,
+  network varchar(255) NOT NULL,
+  type varchar(16) NOT NULL,
+  send_asset_code varchar(12) NOT NULL,
+  send_asset_issuer varchar(56) NOT NULL,
+  send_amount varchar(32) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(56) NOT NULL,
+  amount varchar(32) NOT NULL,
+  path text NOT NULL,
+  expires_at timestamp NOT NULL,
+  created_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX quote_quote_id ON Quote (quote_id);
+
+-- +migrate Down
+DROP TABLE Quote;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE FiatValue (
+  id bigserial,
+  direction varchar(16) NOT NULL,
+  reference varchar(255) NOT NULL,
+  tenant varchar(255) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(56) NOT NULL,
+  amount varchar(32) NOT NULL,
+  currency varchar(12) NOT NULL,
+  rate varchar(64) NOT NULL,
+  fiat_amount varchar(64) NOT NULL,
+  created_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX fiatvalue_direction_reference ON FiatValue (direction, reference);
+
+-- +migrate Down
+DROP TABLE FiatValue;
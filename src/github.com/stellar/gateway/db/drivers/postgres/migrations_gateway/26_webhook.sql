@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE Webhook (
+  id bigserial,
+  webhook_id varchar(32) NOT NULL,
+  url varchar(2048) NOT NULL,
+  events varchar(255) NOT NULL,
+  secret varchar(255) NOT NULL DEFAULT '',
+  max_attempts integer NOT NULL,
+  retry_interval integer NOT NULL,
+  enabled boolean NOT NULL,
+  created_at timestamp NOT NULL,
+  updated_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX webhook_webhook_id ON Webhook (webhook_id);
+
+-- +migrate Down
+DROP TABLE Webhook;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE Webhook ADD format varchar(16) NOT NULL DEFAULT 'json';
+ALTER TABLE Webhook ADD schema_version varchar(16) NOT NULL DEFAULT '1';
+
+-- +migrate Down
+ALTER TABLE Webhook DROP format;
+ALTER TABLE Webhook DROP schema_version;
@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE MemoReference (
+  id bigserial,
+  memo char(64) NOT NULL,
+  reference varchar(255) NOT NULL,
+  created_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX memo_reference_memo ON MemoReference (memo);
+
+-- +migrate Down
+DROP TABLE MemoReference;
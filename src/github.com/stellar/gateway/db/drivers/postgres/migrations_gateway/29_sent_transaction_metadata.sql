@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE SentTransaction ADD metadata text NULL DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE SentTransaction DROP metadata;
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE LeaderLease (
+  id bigserial,
+  name varchar(64) NOT NULL,
+  holder varchar(255) NOT NULL,
+  acquired_at timestamp NOT NULL,
+  expires_at timestamp NOT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX leader_lease_name ON LeaderLease (name);
+
+-- +migrate Down
+DROP TABLE LeaderLease;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE HeldPayment ADD encrypted_seed text DEFAULT NULL;
+ALTER TABLE HeldPayment ADD attempts integer NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE HeldPayment DROP COLUMN encrypted_seed;
+ALTER TABLE HeldPayment DROP COLUMN attempts;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE SentTransaction ADD original_id integer NULL DEFAULT NULL;
+ALTER TABLE SentTransactionConflict ADD original_id integer NULL DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE SentTransaction DROP original_id;
+ALTER TABLE SentTransactionConflict DROP original_id;
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/gateway/db/entities"
+)
+
+// NotificationVersion is a version of the notification payload schema. It is
+// increased when fields are removed or their meaning changes. New fields can
+// be added without changing the version.
+const NotificationVersion = 1
+
+// Notification actions
+const (
+	NotificationActionInsert = "insert"
+	NotificationActionUpdate = "update"
+)
+
+// Notification types
+const (
+	NotificationTypeReceivedPayment = "received_payment"
+	NotificationTypeSentTransaction = "sent_transaction"
+)
+
+// Notification is a JSON payload of NOTIFY event sent on Driver.NotifyChannel
+// when a received payment or sent transaction is written. Payloads are
+// limited to 8000 bytes by Postgres so XDR fields are not included, clients
+// should load the row using ID when they need them.
+type Notification struct {
+	Version         int                          `json:"version"`
+	Type            string                       `json:"type"`
+	Action          string                       `json:"action"`
+	ID              int64                        `json:"id"`
+	ReceivedPayment *ReceivedPaymentNotification `json:"received_payment,omitempty"`
+	SentTransaction *SentTransactionNotification `json:"sent_transaction,omitempty"`
+}
+
+// ReceivedPaymentNotification contains fields of entities.ReceivedPayment
+type ReceivedPaymentNotification struct {
+	OperationID   string    `json:"operation_id"`
+	TransactionID string    `json:"transaction_id"`
+	PagingToken   string    `json:"paging_token"`
+	Status        string    `json:"status"`
+	ProcessedAt   time.Time `json:"processed_at"`
+}
+
+// SentTransactionNotification contains fields of entities.SentTransaction
+type SentTransactionNotification struct {
+	PaymentID     *string    `json:"payment_id"`
+	Region        string     `json:"region"`
+	TransactionID string     `json:"transaction_id"`
+	Status        string     `json:"status"`
+	Source        string     `json:"source"`
+	SubmittedAt   time.Time  `json:"submitted_at"`
+	SucceededAt   *time.Time `json:"succeeded_at"`
+	Ledger        *uint64    `json:"ledger"`
+}
+
+// execer is implemented by *sqlx.DB and *sqlx.Tx
+type execer interface {
+	PrepareNamed(query string) (*sqlx.NamedStmt, error)
+	NamedExec(query string, arg interface{}) (sql.Result, error)
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// NewNotification returns a notification of a given action on the object.
+// ok is false when notifications are not sent for this type of entity.
+func NewNotification(object entities.Entity, action string) (notification Notification, ok bool) {
+	notification = Notification{Version: NotificationVersion, Action: action}
+	if id := object.GetID(); id != nil {
+		notification.ID = *id
+	}
+
+	switch object := object.(type) {
+	case *entities.ReceivedPayment:
+		notification.Type = NotificationTypeReceivedPayment
+		notification.ReceivedPayment = &ReceivedPaymentNotification{
+			OperationID:   object.OperationID,
+			TransactionID: object.TransactionID,
+			PagingToken:   object.PagingToken,
+			Status:        object.Status,
+			ProcessedAt:   object.ProcessedAt,
+		}
+	case *entities.SentTransaction:
+		notification.Type = NotificationTypeSentTransaction
+		notification.SentTransaction = &SentTransactionNotification{
+			PaymentID:     object.PaymentID,
+			Region:        object.Region,
+			TransactionID: object.TransactionID,
+			Status:        string(object.Status),
+			Source:        object.Source,
+			SubmittedAt:   object.SubmittedAt,
+			SucceededAt:   object.SucceededAt,
+			Ledger:        object.Ledger,
+		}
+	default:
+		return notification, false
+	}
+
+	return notification, true
+}
+
+// notifies returns true if writes of the object are notified
+func (d *Driver) notifies(object entities.Entity) bool {
+	if d.NotifyChannel == "" {
+		return false
+	}
+	_, ok := NewNotification(object, "")
+	return ok
+}
+
+// write runs fn writing the object. When the object is notified, fn runs in
+// a transaction together with pg_notify so the notification is delivered
+// only when the row is committed.
+func (d *Driver) write(object entities.Entity, action string, fn func(e execer) error) (err error) {
+	if !d.notifies(object) {
+		return fn(d.database)
+	}
+
+	tx, err := d.database.Beginx()
+	if err != nil {
+		return
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	notification, _ := NewNotification(object, action)
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	_, err = tx.Exec("SELECT pg_notify($1, $2);", d.NotifyChannel, string(payload))
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotification(t *testing.T) {
+	id := int64(12)
+	processedAt := time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC)
+	payment := &entities.ReceivedPayment{
+		ID:               &id,
+		OperationID:      "1234",
+		ProcessedAt:      processedAt,
+		PagingToken:      "1234",
+		Status:           "Success",
+		TransactionID:    "abcd",
+		TransactionValue: "memo",
+	}
+
+	notification, ok := NewNotification(payment, NotificationActionInsert)
+	require.True(t, ok)
+	payload, err := json.Marshal(notification)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"type": "received_payment",
+		"action": "insert",
+		"id": 12,
+		"received_payment": {
+			"operation_id": "1234",
+			"transaction_id": "abcd",
+			"paging_token": "1234",
+			"status": "Success",
+			"processed_at": "2018-03-01T10:00:00Z"
+		}
+	}`, string(payload))
+
+	paymentID := "payment-1"
+	ledger := uint64(100)
+	transaction := &entities.SentTransaction{
+		ID:            &id,
+		PaymentID:     &paymentID,
+		TransactionID: "abcd",
+		Status:        entities.SentTransactionStatusSuccess,
+		Source:        "GABC",
+		SubmittedAt:   processedAt,
+		SucceededAt:   &processedAt,
+		Ledger:        &ledger,
+		EnvelopeXdr:   "AAAA",
+	}
+
+	notification, ok = NewNotification(transaction, NotificationActionUpdate)
+	require.True(t, ok)
+	payload, err = json.Marshal(notification)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"type": "sent_transaction",
+		"action": "update",
+		"id": 12,
+		"sent_transaction": {
+			"payment_id": "payment-1",
+			"region": "",
+			"transaction_id": "abcd",
+			"status": "success",
+			"source": "GABC",
+			"submitted_at": "2018-03-01T10:00:00Z",
+			"succeeded_at": "2018-03-01T10:00:00Z",
+			"ledger": 100
+		}
+	}`, string(payload))
+
+	_, ok = NewNotification(&entities.HeldPayment{}, NotificationActionInsert)
+	assert.False(t, ok)
+}
+
+func TestDriverNotifies(t *testing.T) {
+	driver := &Driver{}
+	assert.False(t, driver.notifies(&entities.ReceivedPayment{}))
+
+	driver.NotifyChannel = "bridge_events"
+	assert.True(t, driver.notifies(&entities.ReceivedPayment{}))
+	assert.True(t, driver.notifies(&entities.SentTransaction{}))
+	assert.False(t, driver.notifies(&entities.FeatureFlag{}))
+}
@@ -0,0 +1,954 @@
+// Code generated by go-bindata.
+// sources:
+// migrations_gateway/01_init.sql
+// migrations_gateway/02_security_event.sql
+// migrations_gateway/03_api_key.sql
+// migrations_gateway/04_destination_profile.sql
+// migrations_gateway/05_daily_aggregate.sql
+// migrations_gateway/06_federation_snapshot.sql
+// migrations_gateway/07_received_payment_event.sql
+// migrations_gateway/08_payment_limit_usage.sql
+// migrations_gateway/09_named_destination.sql
+// migrations_gateway/10_held_payment_approval.sql
+// migrations_gateway/11_payment_velocity.sql
+// migrations_gateway/12_expected_payment.sql
+// migrations_gateway/13_refund.sql
+// migrations_gateway/14_failed_callback.sql
+// migrations_gateway/15_offline_transaction.sql
+// migrations_gateway/16_quote.sql
+// migrations_gateway/17_fiat_value.sql
+// migrations_gateway/18_webhook.sql
+// migrations_gateway/19_webhook_format.sql
+// migrations_gateway/20_memo_reference.sql
+// migrations_gateway/21_sent_transaction_metadata.sql
+// migrations_gateway/22_leader_lease.sql
+// migrations_gateway/23_sep24_transaction.sql
+// migrations_gateway/24_held_payment_seed.sql
+// migrations_gateway/25_sent_transaction_conflict.sql
+// migrations_gateway/26_sent_transaction_original_id.sql
+// migrations_gateway/27_api_key_tenant.sql
+// migrations_compliance/01_init.sql
+// migrations_compliance/02_federation_snapshot.sql
+// migrations_compliance/03_sanctions_screening.sql
+// migrations_compliance/04_compliance_audit.sql
+// migrations_compliance/05_attachment.sql
+// DO NOT EDIT!
+
+package sqlite
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func bindataRead(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("Read %q: %v", name, err)
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, gz)
+	clErr := gz.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("Read %q: %v", name, err)
+	}
+	if clErr != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type asset struct {
+	bytes []byte
+	info  os.FileInfo
+}
+
+type bindataFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (fi bindataFileInfo) Name() string {
+	return fi.name
+}
+func (fi bindataFileInfo) Size() int64 {
+	return fi.size
+}
+func (fi bindataFileInfo) Mode() os.FileMode {
+	return fi.mode
+}
+func (fi bindataFileInfo) ModTime() time.Time {
+	return fi.modTime
+}
+func (fi bindataFileInfo) IsDir() bool {
+	return false
+}
+func (fi bindataFileInfo) Sys() interface{} {
+	return nil
+}
+
+var _migrations_gateway01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x96\x41\x73\xda\x30\x10\x85\xef\xfe\x15\xba\x25\x4c\xc9\xb4\xe9\x04\x2e\x39\xd1\xe0\x4c\x99\x12\x93\xba\x30\xd3\x9c\x34\x8b\xb5\x71\x34\x95\x25\x47\x5e\xd3\xe4\xdf\x77\x14\x43\x2c\xdb\x18\x4a\xa7\x57\xf6\x21\xbf\xfd\x9e\x76\xed\x8b\x0b\xf6\x21\x93\xa9\x05\x42\xb6\xca\x83\x9b\x38\x9c\x2c\x43\xb6\x9c\x7c\x99\x87\x2c\xc6\x04\xe5\x06\xc5\x3d\xbc\x66\xa8\x89\x9d\x07\x8c\x49\xc1\xa4\x26\x4c\xd1\xb2\xfb\x78\x76\x37\x89\x1f\xd8\xb7\xf0\x81\x4d\x56\xcb\xc5\x2c\xba\x89\xc3\xbb\x30\x5a\x0e\x03\xc6\x4c\x8e\x16\x48\x1a\xcd\xa5\x60\x1b\xb0\xc9\x13\xd8\xf3\xcf\xa3\xd1\x80\x45\x8b\x25\x8b\x56\xf3\x39\x5b\x45\xb3\xef\xab\x70\x18\x30\x96\x5b\x93\x60\x51\xa0\xe0\x40\x4c\x00\x21\xc9\x0c\xdf\x85\xee\xb8\x1c\x52\xa9\x53\x4e\xe6\x17\xea\xfd\xc7\x39\x55\x41\x40\x65\xd1\x5f\x27\x0b\xba\x80\xa4\x6d\x6b\x7c\x35\x60\xd3\xf0\x76\xb2\x9a\x2f\xd9\x59\xf4\x71\x72\xe6\xb4\x19\x66\xe6\xa8\xc8\x3f\x70\x03\xaa\xc4\x7e\x79\x30\xb8\x0e\x9a\x78\x7f\xa0\xa6\x65\x7d\xc0\x49\x78\xf3\x2a\x12\xdf\x60\x05\xd7\x81\xdd\x3d\x77\xd7\xb6\xc5\x54\x9a\x1a\xdb\xf8\xaa\xa6\x52\x7b\xec\xf4\x23\xc5\xde\x7f\xec\xe1\x7c\xf9\xa9\x55\x36\xa5\x4d\x6a\x14\xa3\x71\xab\x5c\xae\x33\x49\x74\x20\xed\xa2\x4c\x12\x44\xd1\x52\xb4\xdb\x52\x28\x1c\xa7\xb5\x4c\xa5\xa6\x4e\xd3\xa8\x37\xa8\x4c\x8e\xfc\x45\x58\x46\xf8\x42\x8d\x27\x58\x2c\x4a\x45\x6f\xb5\x9d\xcd\x37\x7e\xed\x53\xd6\x20\x78\x81\xcf\xdc\x62\x61\x54\x49\x3e\xc6\xcb\x71\x0f\xee\xea\x5e\xb3\xf3\x0a\xfb\xd0\xcb\x6a\xd0\xbd\x04\x5f\x51\xfd\xd3\x7c\xf5\x5e\x80\xee\x74\x55\x71\x70\x48\x12\x53\x6a\xea\x8d\xc5\xe2\x73\x89\x05\x75\x59\x1d\x49\x3b\xb1\x08\x07\xc3\x44\x22\x85\xbd\xe5\x04\x74\x82\x4a\x1d\xc9\xba\xca\xab\xf2\xe6\xd7\x7c\xa0\xb3\x68\x1a\xfe\x64\x4f\xa8\x04\xdf\xd1\xa9\xac\xf3\xda\xc2\x22\x6a\x12\xaf\x04\xc3\xda\x64\x27\xa0\x1b\x93\xe5\x4a\x3a\x93\x31\xe6\x20\xed\x7f\xde\x82\x8e\x90\xb1\xee\x0a\x83\xe2\x6e\xe7\xf4\xcb\x92\x77\x27\x47\x84\x1b\xb4\xf2\x51\xa2\xe0\xeb\xd7\x7e\x91\x45\x28\x8c\xde\x37\x19\xae\xcb\x43\x79\x36\xaf\x43\xe3\xe0\x6e\x1c\x9e\xe9\xea\x64\xde\x60\xb2\x88\xf6\x00\xf6\x15\x9d\x3c\xee\x30\x33\x31\x3e\x97\xce\xe3\x14\x0b\x92\x1a\x4e\xde\x9e\xdb\x51\xf0\x43\x19\x8d\x0f\xee\xb0\xcb\xf1\xdf\xd1\x6b\x2d\xd0\x7a\x7b\xee\x5b\x14\x87\x26\xc7\x27\xb9\x5d\x28\x15\x50\x17\x3d\xb7\x5b\x00\x5c\xd4\x04\x76\x03\xbe\xc5\xda\xcb\xa9\x96\x75\xd8\xde\x22\x50\x69\xf1\x56\x41\x7a\x12\x4f\x0d\x19\xf6\xbe\x2c\x08\x35\x68\xea\x2d\xa3\x86\xb5\x42\xc1\xd6\xc6\x28\x04\xdd\xa8\x95\xb9\xbb\x81\x87\xef\xf1\x4e\x73\x12\xc4\xc7\xaa\x51\xfe\xa8\x20\xe5\xce\x3d\xdf\xba\x5c\x44\x4d\x08\xae\x36\xdc\xb6\xe0\x70\xf9\x9f\x4b\x53\xf3\x5b\x07\xd3\x78\x71\xbf\xff\x73\xe9\xda\xaf\xb5\xde\xf5\x8d\x9a\xb7\x90\x1a\xbf\xb7\x07\xa3\x51\xec\x89\xb7\xa1\xf1\x3a\xb9\x0e\xfe\x0c\x00\xbd\x67\x1f\x57\xe9\x09\x00\x00")
+
+func migrations_gateway01_initSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway01_initSql,
+		"migrations_gateway/01_init.sql",
+	)
+}
+
+func migrations_gateway01_initSql() (*asset, error) {
+	bytes, err := migrations_gateway01_initSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/01_init.sql", size: 2537, mode: os.FileMode(420), modTime: time.Unix(1792030942, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway02_security_eventSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\xcf\x6a\xf3\x30\x10\xc4\xef\x7a\x8a\x3d\x26\x7c\x09\x7c\xb4\xa5\x14\x72\x72\x63\x15\x42\x1d\x39\xb8\x32\x34\x27\x21\xa2\x6d\x2a\xf0\x3f\xd6\x5b\x37\x7e\xfb\x62\x12\x1b\xdb\xb4\xe7\xdf\x68\x46\x3b\xb3\x5e\xc3\xbf\xdc\x9f\xc9\x32\x42\x5a\x89\x6d\x22\x03\x2d\x41\x07\xcf\x91\x84\x37\x3c\x7d\x91\xe7\x56\x36\x58\x30\x2c\x04\x80\x77\xe0\x0b\xc6\x33\x12\x1c\x92\xdd\x3e\x48\x8e\xf0\x2a\x8f\x10\xa4\x3a\xde\xa9\x6d\x22\xf7\x52\xe9\x95\x00\xc0\xee\x85\xf1\x0e\x1a\x4b\xa7\x4f\x4b\x8b\xc7\x87\x25\xa8\x58\x83\x4a\xa3\xa8\x13\x70\x5b\xe1\x00\xef\xef\xa6\xf0\xa3\xa4\xdc\xf2\x80\x9f\xa6\xb4\xb2\x6d\x56\x5a\x07\x8c\x17\x9e\x80\x13\xa1\x65\x74\xc6\x32\x38\xcb\xc8\x3e\xc7\x09\xb7\xcc\x98\x57\x5c\x0f\x27\xf4\x10\x42\xf9\x12\xa4\x91\x86\xff\x9d\x7f\x81\x17\x36\x37\xed\x9f\x5e\x0e\x33\xdf\x20\xcd\xd2\x7a\x9f\x5e\x95\xd9\x9a\x0d\x12\x95\x74\xfd\xed\x98\x8b\xe5\x46\xf4\x75\xef\x54\x28\xdf\xa1\xbe\xd5\x6d\xae\xed\x8d\x23\xcc\xfc\x53\xb1\x9a\xaf\x33\x96\xaf\xe6\x47\x74\x59\xe3\xa5\xc3\xf2\xbb\x10\x61\x12\x1f\x7e\x5b\x7a\x23\x7e\x06\x00\xb2\x56\x6b\x65\x15\x02\x00\x00")
+
+func migrations_gateway02_security_eventSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway02_security_eventSql,
+		"migrations_gateway/02_security_event.sql",
+	)
+}
+
+func migrations_gateway02_security_eventSql() (*asset, error) {
+	bytes, err := migrations_gateway02_security_eventSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/02_security_event.sql", size: 533, mode: os.FileMode(420), modTime: time.Unix(1792031588, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway03_api_keySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x90\xcb\x4e\xf3\x30\x14\x84\xf7\x7e\x8a\x59\xb6\xfa\xff\x4a\x08\x51\x36\x5d\x99\xc6\x48\x51\x53\xa7\x44\xb6\x44\x57\x91\x49\x0e\xc1\x22\x97\xe2\x9c\x00\x7d\x7b\x14\x2e\x11\x11\x5b\x7f\x33\xf2\x99\x6f\xb5\xc2\xbf\xc6\x57\xc1\x31\xc1\x9e\xc4\x36\x53\xd2\x28\x18\x79\x93\x28\xc8\x43\xbc\xa3\x33\x16\x02\xf0\x25\x7c\xcb\x54\x51\xc0\x21\x8b\xf7\x32\x3b\x62\xa7\x8e\x90\xd6\xa4\xb1\xde\x66\x6a\xaf\xb4\xf9\x2f\x80\x67\x3a\xe7\xbe\xc4\xab\x0b\xc5\x93\x0b\x8b\xeb\xab\x25\x74\x6a\xa0\x6d\x92\x8c\xb8\xa7\x22\x10\x4f\xf8\x72\xbd\x9e\xf3\x40\x2f\x83\x0f\x94\xf7\xbe\x6a\x1d\x0f\x81\xf0\xd0\x75\x35\xb9\x76\x4a\x21\x52\xb7\xd2\x26\x06\x8f\xae\xee\xe9\xb3\xe3\x98\xf2\xda\x37\x9e\xa7\x13\xff\x84\x2f\xc6\x60\xdf\x0d\xa1\xa0\x1e\x4c\xef\x3c\xfb\xb5\x08\xe4\x98\xca\xdc\x31\x4a\xc7\xc4\xbe\xa1\x89\x8b\xe5\x46\xfc\x58\xb1\x3a\xbe\xb3\x0a\xb1\x8e\xd4\x3d\xdc\xc9\xe7\xe3\xda\xef\xc5\xa9\x9e\x74\x7d\xbd\x8c\xbd\xdf\x72\xa3\xee\xad\x15\x51\x96\x1e\x66\x72\x37\xe2\x63\x00\xda\x21\x82\x58\x81\x01\x00\x00")
+
+func migrations_gateway03_api_keySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway03_api_keySql,
+		"migrations_gateway/03_api_key.sql",
+	)
+}
+
+func migrations_gateway03_api_keySql() (*asset, error) {
+	bytes, err := migrations_gateway03_api_keySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/03_api_key.sql", size: 385, mode: os.FileMode(420), modTime: time.Unix(1792034520, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway04_destination_profileSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\xc1\x6e\xdb\x30\x10\x44\xef\xfc\x8a\x39\xda\xa8\x72\x28\xd0\xe6\xa2\x93\x6a\x31\x80\x50\x89\x4a\x68\x09\x68\x4e\x02\x4b\x6e\x63\x02\x16\xe9\x52\xab\xa6\x9f\x5f\x38\x71\x6d\x27\x80\x8d\xa2\xd7\xe5\x60\x76\xde\x70\x6f\x6e\xf0\x61\xf4\x4f\xc9\x30\xa1\xdf\x89\xa2\xee\xa4\x46\x57\x7c\xa9\x25\x1a\x1a\xa3\xa6\x9f\xb3\x4f\xe4\x4a\x9a\xd8\x07\xc3\x3e\x06\x14\x65\x89\x5d\x8a\x3f\xfc\x96\xf0\xcb\x24\xbb\x31\x69\x71\xfb\x69\x89\x52\xde\x15\x7d\xdd\x41\xf5\x75\x9d\x0b\x71\xee\x5c\xc6\xe7\xb0\x1f\xac\x1f\x6a\xcf\x04\x6b\x42\x88\x0c\x97\xe2\x0e\x36\x6e\xe7\x31\x4c\x19\x78\x43\x60\xf3\x7d\x4b\xf0\x13\x6c\xdc\x79\x72\x78\xf6\xbc\x89\x33\x1f\xd7\xbd\x8a\xc5\x4a\xcb\xa2\x93\xd7\x63\x0e\x71\xeb\xb0\x10\x80\x77\xf0\x81\xe9\x89\x12\xee\x75\xd5\x14\xfa\x11\x5f\xe5\x23\x8a\xbe\x6b\x2b\xb5\xd2\xb2\x91\xaa\xcb\x04\x60\xac\x8d\x73\xe0\xc1\xbb\x23\xd5\xe7\xdb\x25\x54\xfb\x4a\xb4\x97\x4c\x71\x4e\xf6\x04\xfd\xf1\xdd\x73\x22\x33\xc5\x00\xa6\xdf\xfc\x66\xce\xc9\x84\xc9\xd8\x97\x54\xde\xe1\x58\xd8\xde\xf6\x4d\x6b\xfb\x1d\x36\x91\x61\x72\x83\x61\x38\xc3\xc4\x7e\xa4\xa3\x99\x58\xe6\x42\x54\x6a\x2d\x75\x87\x4a\x75\xed\x75\x76\xef\xb2\x33\xa8\xec\x90\x3e\x3b\xc4\xcc\xde\xc5\xca\xce\x36\x2f\x05\xb0\x96\xb5\x5c\x75\xf8\x7f\x13\xdc\xe9\xb6\xb9\x94\x30\x17\xa2\xd4\xed\xfd\xf5\x2f\xcc\xff\xe5\x1c\x5f\x58\xb5\x54\x45\x23\x71\xb9\x91\xfc\xef\xcd\xf4\xaa\x7a\xe8\x25\x2a\x55\xca\x6f\x18\x69\x8c\x43\x3a\xa8\x07\x77\x66\x7a\x42\x46\xab\x2e\x99\x62\x71\x92\x2d\x73\xf1\x67\x00\x9c\x7c\x12\xdc\x4b\x03\x00\x00")
+
+func migrations_gateway04_destination_profileSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway04_destination_profileSql,
+		"migrations_gateway/04_destination_profile.sql",
+	)
+}
+
+func migrations_gateway04_destination_profileSql() (*asset, error) {
+	bytes, err := migrations_gateway04_destination_profileSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/04_destination_profile.sql", size: 843, mode: os.FileMode(420), modTime: time.Unix(1792034802, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway05_daily_aggregateSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x91\x51\x6b\xf2\x30\x14\x86\xef\xf3\x2b\xde\x3b\x95\xaf\x82\xdf\xd8\xbc\xf1\x2a\xb3\x19\x94\xd5\xd4\x95\x04\xe6\x95\x64\x4d\xc8\x02\x33\x1d\x69\xdc\xf0\xdf\x8f\x6c\xd6\x0e\xd4\xdd\x1d\x78\x1f\xce\xe1\x7d\xce\x74\x8a\x7f\x3b\x67\x83\x8a\x06\xf2\x9d\x2c\x6b\x46\x05\x83\xa0\xf7\x25\x43\xae\xdc\xdb\x81\x5a\x1b\x8c\x4d\xf1\x98\x00\x4e\xc3\xf9\x68\xac\x09\x58\xd7\xc5\x8a\xd6\x1b\x3c\xb2\x0d\xa8\x14\x55\xc1\x97\x35\x5b\x31\x2e\x32\x02\x68\x75\xc0\x87\x0a\xcd\xab\x0a\xe3\xff\xb3\x09\x78\x25\xc0\x65\x59\xa6\x2c\x1a\xaf\x7c\x3c\xc5\xf3\xdb\x21\x46\xce\x1e\xa8\x2c\x05\x46\xa3\x44\x6a\x17\x4c\x13\x5d\xeb\xaf\xee\x52\x5d\x67\xe2\xb6\x69\xb5\x19\x90\x9b\x4b\x88\xeb\xba\xbd\x09\x27\xe8\x6e\x7e\xf5\x68\xd3\xee\x7d\xc4\x8b\xb3\xce\xc7\x73\x66\xf6\xbd\x72\xf7\x37\x43\x26\x0b\xd2\xab\x94\xbc\x78\x92\x0c\x05\xcf\xd9\x33\x74\x32\xba\x55\xbd\xd2\x61\x42\xc5\xcf\x74\x6b\x75\xc8\x8e\xb2\xb2\x41\x45\x76\x2c\x94\x3a\xf7\xf3\x4f\xb9\x74\xf4\xf7\x3b\xf3\xf6\xd3\x93\xbc\xae\xd6\x17\xdf\xb9\x20\x5f\x03\x00\x5b\x21\xa9\x48\xfb\x01\x00\x00")
+
+func migrations_gateway05_daily_aggregateSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway05_daily_aggregateSql,
+		"migrations_gateway/05_daily_aggregate.sql",
+	)
+}
+
+func migrations_gateway05_daily_aggregateSql() (*asset, error) {
+	bytes, err := migrations_gateway05_daily_aggregateSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/05_daily_aggregate.sql", size: 507, mode: os.FileMode(420), modTime: time.Unix(1792035022, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway06_federation_snapshotSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x92\x41\x6f\x82\x40\x10\x85\xef\xfc\x8a\xb9\xa9\x69\x3d\xb4\xa9\x5e\x3c\xd1\xb2\x26\xa6\x08\x86\x42\x52\x4f\x9b\x2d\x8c\xb2\x29\xec\x92\xdd\xc5\xd6\x7f\xdf\xc5\x54\x04\x8b\xe9\x75\xdf\xf7\x5e\x66\x66\xdf\x74\x0a\x77\x25\xdf\x2b\x66\x10\x92\xca\x79\x89\x88\x1b\x13\x88\xdd\x67\x9f\xc0\x12\x33\xb4\x02\x97\xe2\x4d\xb0\x4a\xe7\xd2\xc0\xd8\x01\xe0\x19\x70\x61\x70\x8f\x0a\x36\xd1\x6a\xed\x46\x5b\x78\x25\x5b\x70\x93\x38\x5c\x05\x36\x60\x4d\x82\xf8\xde\x72\x46\x31\xa1\x59\xda\xf8\xa9\xf5\xa4\x39\x53\xe3\xf9\xd3\x04\x82\x30\x86\x20\xf1\xfd\x86\xa9\xd8\xb1\x44\x61\x1a\xfd\xc0\xd4\x09\x79\x9c\xcd\x26\xe0\x91\xa5\x9b\xf8\x17\xee\x93\x8b\x0b\xf1\x30\xef\x87\xd4\xaa\x00\x83\xdf\xa6\xf7\xb8\xe3\x82\x15\x74\x50\xd2\x86\x99\x5a\xd3\x54\x66\xd8\x6e\xd2\xd5\x3f\x64\x76\xfc\xeb\x32\xaa\x16\xa9\x3d\x53\x66\x75\x59\x20\x13\xad\xda\x4e\xbb\x63\x85\xc6\x13\x5b\x68\x7a\x40\xa5\xed\xea\x83\x53\xb7\x8e\xd1\xa8\xc1\x53\x5e\xe5\xa8\xa8\xae\xb9\xfd\x85\x33\xdf\x3d\xd5\x35\x8f\xca\xf0\x1d\x6f\xa6\xd1\x03\x9b\xa3\x49\x73\xcc\x28\x33\x60\x78\x89\x76\xdb\xb2\xea\x01\x0a\x53\xa9\xb2\x5b\x84\x33\x59\x38\xe7\x1e\xac\x02\x8f\xbc\xdb\xc0\x73\x0f\xa8\xfe\x2d\x02\xbd\xfa\xdc\x30\x18\x6c\x4b\x9f\xb2\xc1\xff\xe6\x76\x0a\x71\x23\xf3\x42\x34\x83\x4e\x3b\xfd\xf5\xe4\x97\x70\xbc\x28\xdc\xdc\xec\xef\xc2\xf9\x01\x2f\xef\xa4\xd5\xf0\x02\x00\x00")
+
+func migrations_gateway06_federation_snapshotSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway06_federation_snapshotSql,
+		"migrations_gateway/06_federation_snapshot.sql",
+	)
+}
+
+func migrations_gateway06_federation_snapshotSql() (*asset, error) {
+	bytes, err := migrations_gateway06_federation_snapshotSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/06_federation_snapshot.sql", size: 752, mode: os.FileMode(420), modTime: time.Unix(1792037395, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway07_received_payment_eventSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x90\x4b\x0b\xc2\x30\x10\x84\xef\xf9\x15\x7b\xac\x68\x2f\x42\x4f\x9e\xaa\xcd\xa1\xd8\xa6\x25\xa4\xa0\xa7\x10\x9a\xa5\x06\xec\x83\x10\xaa\xfe\x7b\x53\x7c\xd5\x83\x5e\x96\x85\x99\x1d\xbe\x9d\x30\x84\x65\x6b\x1a\xab\x1c\x42\x35\x90\x1d\xa7\xb1\xa0\x20\xe2\x6d\x46\x81\x63\x8d\x66\x44\x5d\xaa\x5b\x8b\x9d\xa3\xa3\x1f\x10\x10\x00\xa3\xc1\x74\x0e\x1b\xb4\x50\xf2\x34\x8f\xf9\x11\xf6\xf4\x08\x71\x25\x8a\x94\xf9\x88\x9c\x32\xb1\xf2\xbe\x7e\x40\x1f\x6c\xfa\x4e\xfa\x8b\x51\xd9\xfa\xa4\x6c\xb0\x8e\xa2\x05\xb0\x42\x00\xab\xb2\x6c\x72\x0d\xea\x76\xee\x95\x06\x87\x57\xf7\x25\xd4\x16\x3d\x96\x96\xca\x81\xf6\x8b\x33\x2d\xbe\x75\xb2\xd8\x90\x17\x6d\xca\x12\x7a\x00\xfb\xa4\x95\xc3\x03\x57\xe2\xc4\x2b\x67\x21\x05\xfb\xf1\xd2\xc7\x33\xa5\x86\xb3\x4a\x92\xfe\xd2\x91\x84\x17\xe5\x9f\x4a\x36\xe4\x0e\xa0\x44\x6e\x71\x45\x01\x00\x00")
+
+func migrations_gateway07_received_payment_eventSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway07_received_payment_eventSql,
+		"migrations_gateway/07_received_payment_event.sql",
+	)
+}
+
+func migrations_gateway07_received_payment_eventSql() (*asset, error) {
+	bytes, err := migrations_gateway07_received_payment_eventSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/07_received_payment_event.sql", size: 325, mode: os.FileMode(420), modTime: time.Unix(1792041118, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway08_payment_limit_usageSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x91\x41\x6b\x83\x30\x18\x86\xef\xf9\x15\xef\xad\x2d\x53\xe8\x0a\xee\xd2\x53\x36\x33\x90\xd9\xe8\x24\x81\xf6\x24\x59\x0d\x2e\x30\x63\x31\x71\xa3\xff\x7e\xba\xd2\xd5\xb1\xf6\xf2\x5d\xf2\x84\xf7\x7b\x9f\x2f\x0c\x71\xd7\x98\xba\x53\x5e\x43\x1e\xc8\x53\xc1\xa8\x60\x10\xf4\x31\x65\xc8\xd5\xb1\xd1\xd6\xa7\xa6\x31\x5e\x3a\x55\x6b\xcc\x09\x60\x2a\x18\xeb\x75\xad\x3b\xe4\x45\xb2\xa1\xc5\x0e\x2f\x6c\x07\x2a\x45\x96\xf0\xe1\xff\x86\x71\x11\x0c\x5c\xa5\x8e\xf8\x54\xdd\xfe\x5d\x75\xf3\xfb\xe5\x02\x3c\x13\xe0\x32\x4d\xc7\x37\xe5\x9c\xf6\xe5\xbe\xad\xf4\x05\x59\x5d\x43\x8c\x73\xfd\x10\x74\x86\xa2\x87\x0b\x84\x98\x3d\x53\x99\x0a\xcc\x66\x3f\x71\xda\x79\x63\x95\x37\xad\xfd\xc5\x57\x51\x74\x93\x57\x4d\xdb\x5b\x8f\x37\x53\x0f\x6d\xfe\x43\x4b\xb2\x58\x93\xb3\x0e\xc9\x93\x57\xc9\x90\xf0\x98\x6d\x71\x38\x59\x29\x3f\x46\x2d\x65\x3f\x7a\x39\x4d\x64\xfc\x9a\xb2\xc1\x43\x30\x29\x1c\xfc\x69\x16\x4c\xf7\x1e\x13\xc3\xc9\x3d\xe2\xf6\xcb\x92\xb8\xc8\xf2\x5b\xf7\x58\x93\x6f\x3d\xdf\x44\xbe\xbf\x01\x00\x00")
+
+func migrations_gateway08_payment_limit_usageSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway08_payment_limit_usageSql,
+		"migrations_gateway/08_payment_limit_usage.sql",
+	)
+}
+
+func migrations_gateway08_payment_limit_usageSql() (*asset, error) {
+	bytes, err := migrations_gateway08_payment_limit_usageSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/08_payment_limit_usage.sql", size: 447, mode: os.FileMode(420), modTime: time.Unix(1792041624, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway09_named_destinationSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x91\x31\x6b\xc3\x30\x10\x85\x77\xfd\x8a\xdb\x92\xd0\x7a\x68\xa8\xb3\x64\x72\x2b\x05\x4c\x1d\x39\x35\x12\x34\x93\x10\x96\x48\x34\x58\x36\x92\xd2\xd2\x7f\x5f\x39\x60\xa2\x96\x98\x6e\x07\xef\xbb\xc7\xdd\x7b\x59\x06\x0f\x9d\x39\x39\x19\x34\xf0\x01\xbd\x36\xa4\x60\x04\x58\xf1\x52\x11\xa0\xb2\xd3\x0a\x6b\x1f\x8c\x95\xc1\xf4\x16\x96\x08\xc0\x28\x30\x36\xe8\x93\x76\x70\x68\xca\x7d\xd1\x1c\xe1\x8d\x1c\xa1\xe0\xac\x2e\x69\x5c\xdf\x13\xca\x1e\x23\x67\xe3\x32\x7c\x4a\xd7\x9e\xa5\x5b\x6e\x9e\x57\x40\x6b\x06\x94\x57\xd5\x28\xaa\xc4\x74\x62\xd6\x79\xfe\x1b\xea\x74\xd7\x8b\xf0\x3d\xdc\x6c\x9e\x36\x37\x02\x30\xd9\x15\xbc\x62\xb0\x58\x4c\xf0\x7d\xab\x3f\xa0\xf4\x5e\x07\xd1\xf6\x2a\xb1\x5d\xff\x43\x1b\xef\x2f\xf1\xdf\x89\xcf\xe7\xcf\x68\x9d\x8e\x49\x2a\x21\x03\xa8\x38\x04\x13\x43\x48\x7f\xba\x0c\x6a\x56\x47\xab\x2d\x9a\x0a\xe0\xb4\x7c\xe7\x04\x4a\x8a\xc9\xc7\x35\x4a\x25\x92\xcc\xc4\x35\xdc\x9a\xde\x69\x68\x54\x46\x9f\x2c\xe9\x15\xf7\x5f\x16\xe1\xa6\x3e\xcc\xf4\xba\x45\x3f\x3d\xe5\x6f\x5b\x06\x02\x00\x00")
+
+func migrations_gateway09_named_destinationSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway09_named_destinationSql,
+		"migrations_gateway/09_named_destination.sql",
+	)
+}
+
+func migrations_gateway09_named_destinationSql() (*asset, error) {
+	bytes, err := migrations_gateway09_named_destinationSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/09_named_destination.sql", size: 518, mode: os.FileMode(420), modTime: time.Unix(1792041959, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway10_held_payment_approvalSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xb5\x53\xcf\x4f\x83\x30\x14\xbe\xf3\x57\xbc\xe3\x16\xbb\x44\x8d\xf3\xe2\x09\x47\x8d\x44\x56\x26\x2b\x89\x9e\x48\x85\x17\xd7\xa4\xa3\x58\xca\xd4\xff\xde\xce\xc1\x06\x2e\xf3\xa4\x47\xfa\xbe\xaf\xfd\x7e\x3c\x26\x13\x38\x5b\xcb\x57\x23\x2c\x42\x5a\x79\x7e\xc4\x69\x02\xdc\xbf\x8d\x28\xdc\xa3\x2a\x16\xe2\x73\x8d\xa5\x05\x3f\x08\x20\x37\xe8\x50\x45\xf6\xf2\x09\x1b\x61\xf2\x95\x30\xa3\xeb\xab\x31\x04\xf4\xce\x4f\x23\x0e\x2c\x8d\xa2\x9b\x5f\x2f\x10\x55\x65\xf4\xe6\x4f\x6e\x10\x16\x0a\x27\xc6\xca\x35\xfe\xa0\x7b\x93\x9e\xa3\x40\xbf\x97\xdb\x83\xe5\x63\x24\xdd\x67\x2e\xca\x52\x3b\xa6\xd1\x15\xe4\x5a\x35\xeb\xb2\x26\x60\x57\x08\x56\xbc\x28\x04\x59\xbb\xd3\x4a\x62\x01\xef\xd2\xae\x74\x63\xdb\xf7\x84\xea\xd0\xde\x2c\xa1\x3e\xa7\xc7\xea\x32\xad\x0a\x18\x79\x00\xb2\x00\x59\x5a\x7c\x45\x03\x8b\x24\x9c\xfb\xc9\x33\x3c\xd0\x67\xf0\x53\x1e\x87\xcc\xb1\xe7\x94\x71\xe2\x70\x55\xcb\x73\xf8\x2e\x8a\xcb\xe9\x74\x0c\x2c\xde\x19\x81\x94\x85\x8f\x29\xdd\x42\x6b\xdd\x98\x1c\x33\x91\xe7\xba\x71\x39\x74\xf0\xe9\xf5\x01\xbd\x85\x19\x7c\x6b\xb0\xb6\x60\xf1\xc3\x0e\x06\xb5\x15\xb6\xa9\xf7\xbc\x8b\xf3\x21\xaf\xab\xb5\x1f\xe9\x80\x8e\xd6\x2a\x3c\x39\x76\x99\xe6\xa8\xd4\x2f\x9d\xec\xc4\xd5\x8d\x6a\xb5\xf5\x67\xde\xd8\x35\x16\xb2\x25\x4d\x38\x84\x8c\xc7\xc7\x99\xca\x82\xf4\xc2\x22\x3f\xd2\x20\x9d\x6d\xd2\xda\x24\x3d\x3f\xe4\xa0\x9d\x0c\x74\x92\x56\xcf\xd8\x29\x5b\xd2\x88\xce\x38\xfc\xe7\x33\x70\x97\xc4\xf3\xbe\x33\xe7\x39\x48\xe2\xc5\xf1\x1a\x9d\xdc\xfe\xef\x2c\x12\xca\xfc\xb9\x5b\xbe\x78\x48\x69\x77\x32\x64\x01\x7d\x82\x95\x9b\x64\x9d\x91\x9d\xd6\xec\xd0\x60\xcc\x06\xbf\xd4\xa8\x33\xb3\x47\xb8\x3a\xbe\x00\xab\x65\x7c\x06\x12\x04\x00\x00")
+
+func migrations_gateway10_held_payment_approvalSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway10_held_payment_approvalSql,
+		"migrations_gateway/10_held_payment_approval.sql",
+	)
+}
+
+func migrations_gateway10_held_payment_approvalSql() (*asset, error) {
+	bytes, err := migrations_gateway10_held_payment_approvalSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/10_held_payment_approval.sql", size: 1042, mode: os.FileMode(420), modTime: time.Unix(1792042129, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway11_payment_velocitySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\x92\xc1\x6a\xc3\x30\x10\x44\xef\xfa\x8a\xbd\x25\xa1\xf1\x21\x01\xf7\x92\x93\x5b\x2b\x60\xea\xc8\xc6\xc8\xa5\x39\x19\xd5\x12\xae\xa0\x96\x82\x24\xa7\xe4\xef\xab\x36\x69\x6a\x13\x51\xe8\x4d\x30\xb3\x6f\x35\xc3\x46\x11\xdc\xf5\xb2\x33\xcc\x09\xa8\x0f\xe8\xb1\xc2\x09\xc5\x40\x93\x87\x1c\x43\xc9\x4e\xbd\x50\xee\x59\xbc\xeb\x56\xba\x13\xcc\x11\x80\xe4\x20\x95\x13\x9d\x30\x50\x56\xd9\x2e\xa9\xf6\xf0\x84\xf7\x90\xd4\xb4\xc8\x88\x9f\xde\x61\x42\x97\xde\x67\xf5\x60\x5a\x01\x47\x66\xda\x37\x66\xe6\xf1\xfd\x02\x48\x41\x81\xd4\x79\xfe\x25\x73\x61\x9d\x54\xcc\x49\xad\xae\x9e\x75\x1c\x4f\x4d\xcc\x5a\xe1\x9a\x56\xf3\x5f\xce\x6a\x1d\xb2\x48\x6b\x07\xff\xa1\xd0\x32\x48\xf1\x36\xa9\x73\x0a\xb3\xd9\xb7\xbf\xd7\x83\x72\xf0\x2a\x3b\x9f\x62\x42\x6a\x8d\xf0\x1d\xf0\x86\x39\xe0\xfe\xe1\x64\x2f\xae\x3a\x5a\x6c\xd0\x4f\x35\x19\x49\xf1\x0b\x1c\xce\xd5\x34\xc7\x4b\x37\xcd\x25\x6f\x41\x6e\x5b\x3b\x4b\xcb\xd1\x06\x8f\xfb\x9b\x36\xae\x27\x84\x1c\xe9\xff\xe2\x8e\x42\x86\xb0\x13\x12\x8a\x46\xa7\x91\xea\x0f\x85\xd2\xaa\x28\xc3\xa7\xb1\x41\x9f\xe6\xc4\xb5\xf6\x48\x02\x00\x00")
+
+func migrations_gateway11_payment_velocitySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway11_payment_velocitySql,
+		"migrations_gateway/11_payment_velocity.sql",
+	)
+}
+
+func migrations_gateway11_payment_velocitySql() (*asset, error) {
+	bytes, err := migrations_gateway11_payment_velocitySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/11_payment_velocity.sql", size: 584, mode: os.FileMode(420), modTime: time.Unix(1792042397, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway12_expected_paymentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xcd\x94\xc1\x6f\x82\x30\x14\xc6\xef\xfc\x15\xef\xa6\x66\x9a\x6c\x66\x7a\xf1\xc4\x46\x97\x90\x21\x38\x03\xc9\x3c\x91\xae\x74\xda\x43\x81\x94\xe2\xe6\x7f\xbf\xa2\x0c\x68\xc5\x2d\x26\x3b\xec\x66\xf2\xbe\xf7\xe5\xf5\xfb\x7e\x38\x99\xc0\x0d\x67\x5b\x81\x25\x85\x28\xb7\x1e\xd7\xc8\x0e\x11\x84\xf6\x83\x87\x00\x7d\xe6\x94\x48\x9a\xac\xf0\x81\xd3\x54\xc2\xd0\x02\x60\x09\xb0\x54\xd2\x2d\x15\xb0\x5a\xbb\x4b\x7b\xbd\x81\x67\xb4\x01\x3b\x0a\x03\xd7\x57\xdb\x4b\xe4\x87\x63\xa5\xa3\xf5\x6e\xac\x16\xf6\x58\x90\x1d\x16\xc3\xf9\xfd\x08\xfc\x20\x04\x3f\xf2\xbc\x4a\x83\x79\x56\x2a\xdb\x37\xb6\x55\x96\xfa\xa4\x28\xa8\x8c\x49\x96\xd0\x66\xf9\x6e\x3a\xea\x91\xb0\xa2\x28\xd5\x29\xdf\xa2\xd9\xbc\x15\x81\x83\x9e\xec\xc8\x0b\x61\x30\xa8\xf4\x9c\xf2\x2c\x96\x87\xbc\x75\x9c\xeb\x86\x95\xe0\xe2\xa9\x85\xc4\xb2\x2c\xda\x63\x8c\x5d\x41\x09\x65\x7b\xf5\xda\xfe\x27\x35\xa7\xdc\x56\x62\x22\x28\xae\x92\xc1\x12\x12\xf5\x43\x32\x4e\x35\xb3\x32\x4f\x2e\xce\xad\xd1\xc2\xfa\x2e\x29\xf2\xdd\x97\x08\x81\xeb\x3b\xe8\xb5\xcd\x3b\x3f\x95\x15\x77\x0b\x08\xfc\xf3\x2e\x3b\x73\xe5\x59\x5b\x5e\xf0\x3a\x46\xd3\x67\xd2\x84\x3a\x3e\xc6\x37\xae\x73\xea\x1c\x79\x22\xa9\x5e\x58\x62\x49\x76\x57\x61\x94\xe5\x54\xa1\xc9\xb2\xb4\xcb\xd1\x74\x36\xd3\xe3\x97\x02\xa7\x05\x26\xa6\xce\x2c\xf1\x5d\x64\x3c\xc6\x84\x1c\x2b\xea\x43\xe6\x7f\x41\xd9\xa3\xed\x7d\x9a\xa1\xfb\xed\xcb\x33\xe4\x7f\x49\x36\xaf\xfa\xbd\x96\xdc\x06\xb2\x6a\x39\xd6\x0a\x57\xc8\xe9\xe4\x74\xa7\x26\xb5\xba\x8f\x81\xbf\x6e\xf3\x03\xfb\xba\x4b\x1d\xce\x99\x41\x8b\xf9\xa4\xf3\xff\xe9\x64\x1f\xa9\xe5\xac\x83\x55\x0f\xf5\x8b\xee\xc0\xf8\x8e\x16\xd6\x17\x41\x31\x03\x5c\x86\x05\x00\x00")
+
+func migrations_gateway12_expected_paymentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway12_expected_paymentSql,
+		"migrations_gateway/12_expected_payment.sql",
+	)
+}
+
+func migrations_gateway12_expected_paymentSql() (*asset, error) {
+	bytes, err := migrations_gateway12_expected_paymentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/12_expected_payment.sql", size: 1414, mode: os.FileMode(420), modTime: time.Unix(1792043311, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway13_refundSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\x31\x6f\x83\x30\x10\x85\x77\x7e\xc5\x6d\x49\xd4\x32\x34\x2a\x59\x32\xd1\xe2\x4a\xa8\xc4\xa4\x08\xa4\x64\x42\x16\xbe\xa6\x1e\x30\xc8\x3e\x5a\xe5\xdf\xd7\x89\x44\x08\x28\xc9\x66\xd9\xdf\xbd\xf3\xbd\x7b\xbe\x0f\x4f\xb5\x3a\x18\x41\x08\x45\xeb\xbd\x67\x2c\xcc\x19\xe4\xe1\x5b\xc2\x20\xc3\xef\x4e\x4b\x98\x7b\x00\x4a\x82\xd2\x84\x07\x34\xb0\xcd\xe2\x4d\x98\xed\xe1\x93\xed\x21\x2c\xf2\x34\xe6\xae\x68\xc3\x78\xfe\xec\xb8\xa6\x45\x27\xa5\x1a\x5d\xba\x8a\x5f\x61\xaa\x1f\x61\xe6\xcb\x20\x58\x00\x4f\x73\xe0\x45\x92\x9c\x28\x32\x42\x5b\x51\x4d\xb9\xd5\xeb\x18\x6b\xc5\xb1\x46\x4d\x0f\xa5\x24\x5a\x52\xfa\xdc\xf2\x02\x05\xab\x31\x23\xea\xa6\xd3\x74\x5f\x43\x58\x8b\x54\x56\x8d\xc4\x0b\xf3\xb2\x1c\x10\x88\xd8\x47\x58\x24\x39\xcc\x66\x03\xad\xac\xed\x9c\x19\xb7\x5a\x4e\xf8\x1a\xeb\xa6\xa4\x63\x3b\x88\x3f\x66\x6f\xda\x31\xe1\x0c\x0a\x7b\x35\xf0\x68\xa2\x09\x6a\x49\x50\x67\x87\xc1\x26\xde\x98\xf3\x8e\xcb\x07\x1b\xe9\xe5\xfa\x8a\xca\x35\x27\x94\xa5\x20\x90\xee\x40\xaa\xc6\x91\x62\xd7\xca\xbb\xef\xde\x62\xed\xf5\x19\x2b\x78\xfc\x55\x30\x88\x79\xc4\x76\xfd\x37\x46\xf1\x49\xf9\x25\x81\xd7\xf7\x27\x09\xff\x2a\xb5\x51\xf3\xa7\xbd\x28\x4b\xb7\xa3\xd4\xae\xbd\x7f\x97\xc3\xb9\x08\xda\x02\x00\x00")
+
+func migrations_gateway13_refundSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway13_refundSql,
+		"migrations_gateway/13_refund.sql",
+	)
+}
+
+func migrations_gateway13_refundSql() (*asset, error) {
+	bytes, err := migrations_gateway13_refundSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/13_refund.sql", size: 730, mode: os.FileMode(420), modTime: time.Unix(1792043595, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway14_failed_callbackSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x91\x4d\x4f\xc3\x30\x0c\x86\xef\xf9\x15\x3e\x6e\x82\x1e\x40\x1a\x97\x9d\xca\x9a\x49\x15\x5d\x3a\xaa\x56\x62\xa7\xc8\x34\xd9\x88\x48\x3f\x94\x7a\x7c\xfc\x7b\x32\x28\x53\xab\x75\x37\x4b\x8f\xfd\xda\xef\xeb\x20\x80\x9b\xca\x1c\x1c\x92\x86\xa2\x65\xab\x8c\x87\x39\x87\x3c\x7c\x4c\x38\xac\xd1\x58\xad\x56\x68\xed\x2b\x96\xef\x30\x63\x00\x46\x81\xa9\x49\x1f\xb4\x83\x6d\x16\x6f\xc2\x6c\x07\x4f\x7c\x07\x61\x91\xa7\xb1\xf0\xc3\x1b\x2e\xf2\x5b\xdf\xd7\xb4\xda\x4b\x9a\xa6\x96\x7e\xe2\x03\x5d\xf9\x86\x6e\x76\xbf\x58\xcc\x41\xa4\x39\x88\x22\x49\x4e\x5d\x2d\x7e\xdb\x06\x15\x90\xfe\xa2\x11\xb0\xd8\x91\xd4\xce\x35\xee\x92\x21\x91\xae\x5a\xea\xce\x87\x0c\x61\x47\x48\xc7\xee\xbc\xf1\xee\x61\xbc\xb0\x74\xda\x1b\x55\x12\x09\x94\x2f\xc8\x54\x7a\xc4\x8f\xad\xba\xca\xd9\x7c\xc9\xfe\xf3\x29\x44\xfc\x5c\x70\x88\x45\xc4\x5f\x60\xff\x1b\x93\x2c\xfb\x9c\xe4\xc8\x7b\x2a\x2e\x62\x1c\x72\xaf\xd9\x4b\x4e\x6b\xf5\x7e\x26\x54\xfe\xc8\xe9\xa6\x60\xf0\xc2\xa8\xf9\xac\x59\x94\xa5\xdb\xc9\x17\x2e\xd9\x0f\xf8\x31\x39\xfe\xef\x01\x00\x00")
+
+func migrations_gateway14_failed_callbackSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway14_failed_callbackSql,
+		"migrations_gateway/14_failed_callback.sql",
+	)
+}
+
+func migrations_gateway14_failed_callbackSql() (*asset, error) {
+	bytes, err := migrations_gateway14_failed_callbackSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/14_failed_callback.sql", size: 495, mode: os.FileMode(420), modTime: time.Unix(1792043806, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway15_offline_transactionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x92\xc1\x4e\x83\x40\x10\x86\xef\x3c\xc5\x1c\xdb\x68\x0f\x1a\xdb\x4b\x4f\x28\x7b\x20\xb6\x4b\x25\x90\xd8\x13\x59\x61\x8a\x1b\x61\xb7\xce\x0e\x6d\x1f\x5f\x1a\x23\x05\x2b\xde\x36\xf9\xbe\xf9\xb3\x99\x7f\x66\x33\xb8\xa9\x75\x49\x8a\x11\xd2\xbd\xf7\x14\x0b\x3f\x11\x90\xf8\x8f\x2b\x01\xd1\x6e\x57\x69\x83\x09\x29\xe3\x54\xce\xda\x1a\x98\x78\x00\xba\x00\x6d\x18\x4b\x24\xd8\xc4\xe1\xda\x8f\xb7\xf0\x2c\xb6\xe0\xa7\x49\x14\xca\x36\x60\x2d\x64\x72\xdb\x7a\xf6\x7b\x3c\x6b\xfd\x83\xa2\xfc\x5d\xd1\xe4\x7e\x3e\x9f\x82\x8c\x12\x90\xe9\x6a\x75\x76\xf8\x92\xdd\xf7\x16\x0f\x43\xcd\x20\x1f\x2d\x7d\x8c\xe7\x38\xdb\x50\x8e\x1d\x9f\x2f\x86\x18\xcd\x01\x2b\xbb\xc7\xec\x54\x10\x30\x9e\x78\x40\x09\x3f\x1b\x4d\x58\x64\x4e\x97\x06\xc9\x5d\x1b\x8e\x15\x37\xae\x8b\xbf\xfb\x15\x5f\x29\xc7\x19\x12\x59\x1a\xff\x61\x4e\xd8\xee\xb8\xc8\x14\x43\xd1\x3e\x58\xd7\x38\xe0\xcd\xbe\xf8\x97\xbb\xe6\xad\xd6\x7c\x65\xb4\xd4\x9b\x2e\xbd\x9f\xe2\x52\x19\xbe\xa4\x02\x42\x19\x88\xd7\xae\x80\xfe\x92\x7b\xa5\x44\xf2\xcf\x86\x2f\xc6\x39\x77\xd6\xbb\x8f\xc0\x1e\x8d\x17\xc4\xd1\x66\xf4\x3e\x96\xde\x17\xc1\x2d\x13\x85\x50\x02\x00\x00")
+
+func migrations_gateway15_offline_transactionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway15_offline_transactionSql,
+		"migrations_gateway/15_offline_transaction.sql",
+	)
+}
+
+func migrations_gateway15_offline_transactionSql() (*asset, error) {
+	bytes, err := migrations_gateway15_offline_transactionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/15_offline_transaction.sql", size: 592, mode: os.FileMode(420), modTime: time.Unix(1792044252, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway16_quoteSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x92\x4f\x4f\x83\x40\x10\xc5\xef\xfb\x29\xe6\xd8\x46\x39\x58\xa5\x97\x9e\x50\xf6\x40\xa4\x4b\x4b\xd8\xc4\x9e\xc8\x06\x26\xed\xc6\xf0\xc7\xdd\xc1\xd6\x6f\xef\x92\x08\x01\x53\x63\xaf\xef\xfd\xde\xcc\x24\x6f\x3c\x0f\xee\x2a\x7d\x34\x8a\x10\x64\xcb\x5e\x52\x1e\x64\x1c\xb2\xe0\x39\xe6\xb0\xef\x1a\xa7\x2e\x18\x80\x2e\x41\xd7\x84\x47\x34\xb0\x4b\xa3\x6d\x90\x1e\xe0\x95\x1f\x20\x90\x59\x12\x09\x97\xd9\x72\x91\xdd\x3b\xee\xa3\x4f\xe4\x8e\xfe\x54\xa6\x38\x29\xb3\x58\x3f\x2d\x41\x24\x19\x08\x19\xc7\x3d\x50\x23\x9d\x1b\xf3\x3e\xfa\x2b\xdf\x9f\x03\xf4\xd5\xe2\xe8\x3e\xac\xe7\xa6\xc5\xba\xcc\x95\xb5\x48\x79\xd1\x94\x13\x6e\xf5\x27\xa7\xad\xed\xdc\xd5\x03\xe9\x5f\x9d\x58\x35\x5d\x4d\x23\xf3\xf8\x6b\xda\x0d\x0b\x6f\xda\xf5\xcf\x9a\x56\xd1\x09\x08\x2f\x34\x53\xf1\xd2\x6a\x83\x36\x57\x04\xa5\xeb\x88\x74\x85\x33\xbf\x30\xe8\xe4\xf2\xaa\xcf\x96\x1b\x36\x34\x2a\x45\xb4\x97\x1c\x22\x11\xf2\xb7\x9f\x9a\xc6\xb2\x12\x31\x54\x3d\x48\x7d\xd0\x9b\x7c\x46\xd8\x9c\x6b\x16\xa6\xc9\x6e\xfa\x19\x1b\xf6\x0d\xd8\x65\xc6\x23\x3d\x02\x00\x00")
+
+func migrations_gateway16_quoteSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway16_quoteSql,
+		"migrations_gateway/16_quote.sql",
+	)
+}
+
+func migrations_gateway16_quoteSql() (*asset, error) {
+	bytes, err := migrations_gateway16_quoteSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/16_quote.sql", size: 573, mode: os.FileMode(420), modTime: time.Unix(1792044769, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway17_fiat_valueSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x91\xcd\x6e\xc2\x30\x10\x84\xef\x7e\x8a\x3d\x82\x4a\x0e\xd0\x86\x0b\xa7\xb4\x31\x52\xd4\xe0\xd0\x28\x41\xe5\x64\x59\xce\x02\x96\x1a\xa7\x72\x1c\xaa\xbe\x7d\x1d\xa4\xfc\x10\x95\x9b\xa5\xf9\x3c\xb3\x9a\xf1\x3c\x78\x2a\xd5\xd9\x08\x8b\x90\x7f\x93\xb7\x94\x06\x19\x85\x2c\x78\x8d\x29\x6c\x95\xb0\x07\xf1\xd5\x20\xcc\x08\x80\x2a\x40\x69\x8b\x67\x34\xb0\x4f\xa3\x5d\x90\x1e\xe1\x9d\x1e\x21\xc8\xb3\x24\x62\xee\xdf\x8e\xb2\x6c\xe1\xb8\x42\x19\x94\x56\x55\x1a\xae\xc2\xc8\x8b\x30\xb3\xe5\x7a\x0e\x2c\xc9\x80\xe5\x71\xdc\x12\x06\x4f\x68\x50\x4b\xec\x89\x95\xef\xdf\x23\x16\xb5\xd0\xf6\xb1\x2e\xea\x1a\x2d\x97\x55\x31\x78\x2c\x57\xff\x21\xaa\xae\x1b\x77\x71\x07\xf9\x93\x53\x44\x59\x35\xa3\x9c\xe7\x89\x87\x6c\x4c\x7b\xe8\xef\xc3\x90\x5b\x6f\x9d\xb8\x7e\xb9\x17\x4f\xae\x3f\x3e\x49\x98\x32\xd2\xa0\xb3\x28\xb8\xb0\x50\xb8\x87\x55\x25\xf6\x3a\x99\x6f\x48\xb7\x48\xce\xa2\x8f\x9c\x42\xc4\x42\xfa\x79\x33\xbe\xb6\xc3\xf0\xbe\x6c\x3e\x94\x9a\xb0\xf1\x72\x3d\xb1\x18\x7a\x6f\x7d\xbd\xd1\xf0\x61\xf5\xa3\x49\x98\x26\xfb\xe9\xf0\x1b\xf2\x07\xf7\xd8\x2f\x66\x20\x02\x00\x00")
+
+func migrations_gateway17_fiat_valueSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway17_fiat_valueSql,
+		"migrations_gateway/17_fiat_value.sql",
+	)
+}
+
+func migrations_gateway17_fiat_valueSql() (*asset, error) {
+	bytes, err := migrations_gateway17_fiat_valueSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/17_fiat_value.sql", size: 544, mode: os.FileMode(420), modTime: time.Unix(1792045086, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway18_webhookSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x75\x91\x51\x6b\xc2\x30\x14\x85\xdf\xf3\x2b\xee\x9b\xca\x56\x10\x37\x61\xe0\x53\x67\x33\x28\xab\xa9\x2b\x09\x9b\x4f\x25\xb5\x17\x2d\x6b\x93\x12\xb3\xba\xfd\xfb\xa5\x0c\x6d\x03\xee\x2d\xe4\x3b\xf7\xe4\xe6\x9c\x20\x80\xbb\xa6\x3a\x18\x69\x11\x44\x4b\xd6\x19\x0d\x39\x05\x1e\x3e\x27\x14\xde\xb1\x38\x6a\xfd\x09\x53\x02\x50\x95\x50\x29\x8b\x07\x34\xb0\xcd\xe2\x4d\x98\xed\xe0\x95\xee\x20\x14\x3c\x8d\x99\x9b\xda\x50\xc6\xef\x9d\xee\xfc\x37\x93\x3b\x7d\x27\xcd\xfe\x28\xcd\xf4\x61\x31\x03\x96\x72\x60\x22\x49\x7a\xc9\x97\xa9\xaf\x6c\x31\x7f\x7c\xf2\x29\x76\xa8\xec\x69\x10\x2c\x97\x3e\x3f\xe1\xde\xa0\xbd\xcd\x21\xa2\x2f\xa1\x48\x38\x4c\x26\xbd\xb4\x91\xdf\xb9\xb4\x16\x9b\xd6\x19\x5e\xb6\x1f\x7b\x39\x23\xf3\x93\xf7\xc4\x74\xb2\xbe\x29\x41\x25\x8b\x1a\x4b\x28\xb4\xae\x51\x2a\x8f\xb9\x45\x5c\x6c\xa5\x7b\x03\x4a\x77\xb0\x55\x83\xfe\x47\xdb\xf2\x5f\x4e\x66\x2b\x72\x49\x5b\xb0\xf8\x4d\x50\x88\x59\x44\x3f\xae\x01\x8e\x82\x4c\xd9\x50\xc5\x70\xdd\x1b\x04\xa3\xf6\x22\x7d\x56\x24\xca\xd2\xad\xdf\xde\x8a\xfc\x02\x6b\x91\xdd\xa8\xe3\x01\x00\x00")
+
+func migrations_gateway18_webhookSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway18_webhookSql,
+		"migrations_gateway/18_webhook.sql",
+	)
+}
+
+func migrations_gateway18_webhookSql() (*asset, error) {
+	bytes, err := migrations_gateway18_webhookSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/18_webhook.sql", size: 483, mode: os.FileMode(420), modTime: time.Unix(1792045671, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway19_webhook_formatSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x52\x51\x4f\x83\x30\x18\x7c\xe7\x57\x7c\x6f\xdb\x62\x97\xb8\xe9\x8c\xc9\x9e\x70\x74\x09\x11\xda\xd9\x95\xa8\x4f\xa4\x1b\x75\x43\x81\x2e\x05\x99\xfe\x7b\x8b\x81\x01\x66\x66\x6f\x4d\xef\x7a\xdf\xdd\xf5\x1b\x8f\xe1\x2a\x8d\x77\x5a\x14\x12\x82\x83\x65\x7b\x1c\x33\xe0\xf6\x83\x87\xe1\x59\x6e\xf6\x4a\x7d\x80\xed\x38\xb0\xa0\x5e\xe0\x13\x78\x53\x3a\x15\x05\x94\x42\x6f\xf7\x42\x0f\x27\x77\x23\x20\x94\x03\x09\x3c\x0f\x1c\xbc\xb4\x03\x8f\xc3\xe0\x3d\x57\xd9\x60\x7e\x49\x2a\xdf\xee\x65\x2a\xc2\x52\xea\x3c\x56\xd9\x05\xc9\x89\xd1\xb3\xc6\x1d\xab\x8e\x3a\x66\xd6\x82\x61\x9b\xe3\xfe\x88\x50\x25\x11\x0c\x2d\x80\x38\x82\x38\x2b\xe4\x4e\x6a\x58\x31\xd7\xb7\xd9\x2b\x3c\xe2\x57\xb0\x03\x4e\x5d\x62\x5e\xfa\x98\x70\x64\x78\xc7\xfa\x9d\xe1\x37\x1e\x6e\xa6\xad\x87\x8a\xf2\xa9\x93\x13\x36\xbd\xbe\xbd\xef\xa3\xb2\x94\x59\x91\xb7\x84\xd9\xac\x8f\xe7\x72\xab\x65\x71\x1e\x6f\x13\x0e\x2a\x6a\x2a\xbe\x42\x51\x14\x32\x3d\x18\xc1\xc6\x7d\x57\xcb\x08\xe9\xef\xb0\x42\x74\x29\x92\xb3\x14\x99\x89\x4d\x22\x23\xd8\x28\x95\x48\x91\xf5\x30\x63\xc4\x94\x17\x99\x19\x10\x99\x43\x11\xa7\xb2\x1f\xf4\x10\xfd\x8b\x5b\xa3\xb9\xe5\x92\x35\x66\x1c\x5c\xc2\x69\xaf\xef\x35\xf6\xf0\x82\x9b\xca\x51\xa7\x4e\x54\xf5\x86\xea\x7a\x50\x5d\x03\xea\x65\x44\x7f\x02\xa1\xc6\x3d\xea\x58\x45\x5d\x5b\x4b\x46\xfd\x66\xf4\xdc\x72\x18\x5d\xf5\xbf\xff\xec\xda\xfd\x7a\x64\x98\xd8\xbe\x59\x16\xda\x52\xeb\xfd\x09\x88\xfb\x14\x60\x93\xca\xc1\x2f\x27\xff\x9d\xb5\xa0\xe4\xb4\xbf\xc3\xf6\xda\xd4\xf1\x03\x19\x0a\x41\x30\x3c\x03\x00\x00")
+
+func migrations_gateway19_webhook_formatSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway19_webhook_formatSql,
+		"migrations_gateway/19_webhook_format.sql",
+	)
+}
+
+func migrations_gateway19_webhook_formatSql() (*asset, error) {
+	bytes, err := migrations_gateway19_webhook_formatSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/19_webhook_format.sql", size: 828, mode: os.FileMode(420), modTime: time.Unix(1792046020, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway20_memo_referenceSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x6d\x4f\x3d\x0f\x82\x30\x10\xdd\xfb\x2b\x6e\x84\x28\x8b\x11\x17\x26\x94\x0e\x44\x68\xb1\x69\x13\x9d\x08\x81\x13\x3b\x14\x4c\xd3\xe8\xdf\xb7\x90\x48\xd4\xb8\x5d\xde\xd7\xbd\x17\x45\xb0\x32\xba\xb7\x8d\x43\x50\x77\x72\x10\x34\x95\x14\x64\xba\x2f\x28\x94\x68\x46\x81\x57\xb4\x38\xb4\x08\x01\x01\xd0\x1d\xe8\xc1\x61\x8f\x16\x2a\x91\x97\xa9\xb8\xc0\x91\x5e\x20\x55\x92\xe7\xcc\x7b\x4b\xca\xe4\xda\xeb\x8c\x77\x42\x7b\x6b\x6c\xb0\xdb\x86\xc0\xb8\x04\xa6\x8a\x62\x62\xec\x92\xf7\x68\xec\xac\xd8\xc4\xf1\xb7\xa4\xb5\xe8\xdb\x74\x75\xe3\xa0\xf3\x87\xd3\x06\x17\x9e\x84\x09\x79\x97\x54\x2c\x3f\x29\x0a\x39\xcb\xe8\x79\xfe\x58\x2f\xe1\xf5\x5c\x80\xb3\xdf\x09\x13\x3c\x25\x44\x1f\xab\xb3\xf1\x39\x90\x4c\xf0\xea\xdf\xea\x84\xbc\x00\x1c\xba\x22\x86\x21\x01\x00\x00")
+
+func migrations_gateway20_memo_referenceSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway20_memo_referenceSql,
+		"migrations_gateway/20_memo_reference.sql",
+	)
+}
+
+func migrations_gateway20_memo_referenceSql() (*asset, error) {
+	bytes, err := migrations_gateway20_memo_referenceSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/20_memo_reference.sql", size: 289, mode: os.FileMode(420), modTime: time.Unix(1792046436, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway21_sent_transaction_metadataSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x7d\x93\x4d\x6f\x82\x40\x10\x86\xef\xfc\x8a\xb9\x55\xd3\x35\xa9\x4d\xf5\xc2\x89\xca\x9a\x90\xf2\x61\x71\x39\x78\x22\x2b\x4c\x2c\x09\x1f\x16\x06\x6b\xff\x7d\x17\xab\x66\x41\xdb\xe3\xe6\x7d\xe7\xcd\xcc\x33\xb3\x93\x09\x3c\x16\xd9\xae\x96\x84\x10\xed\x0d\xcb\x15\x3c\x04\x61\xbd\xba\x1c\xd6\x58\x92\xa8\x65\xd9\xc8\x84\xb2\xaa\x04\xcb\xb6\x61\x11\xb8\x91\xe7\x43\x81\x24\x53\x49\x12\x08\x8f\x04\x7e\xe4\xba\x60\xf3\xa5\x15\xb9\xe2\xf4\x30\x0d\x63\xa2\xe5\xda\xd5\x57\x69\x2c\x42\x6e\x09\x7e\x3f\x3a\xae\xf2\x14\x46\x06\x40\x96\x42\x56\x12\xee\xb0\x86\x55\xe8\x78\x56\xb8\x81\x37\xbe\x01\x2b\x12\x81\xe3\xab\x04\x8f\xfb\x82\x29\xdf\x5e\x7e\x17\x2a\x22\x56\xfe\x83\xac\x93\x0f\x59\x8f\x9e\x67\xb3\xf1\x6d\x27\x9d\xb9\xc6\x5d\xd7\xfe\xc5\x38\x7f\x51\xbe\x40\xf4\xbd\x0f\x0f\x9d\x93\xb4\x96\xb4\x68\xbd\xa2\xb3\x35\x24\xa9\x6d\xae\xf2\xf4\x69\x20\x57\x6d\x9d\xe0\x55\x9e\xcd\x07\x72\xbb\x2d\x32\x22\x4c\x63\x49\xa0\x20\x22\x65\x05\x0e\x1c\x49\x82\x98\x0e\x1c\xc3\xb1\x72\x4c\x3b\x4e\xdb\x6c\xa7\x90\xdd\xa8\x58\x1e\x30\xaf\xf6\x18\x1f\xd3\xfa\xbc\xa5\x40\x47\xd2\xb4\x39\x9d\xb4\x1e\xbf\x61\xca\x56\xa6\x71\x83\x9f\xb1\xf2\x57\x79\x4b\x3a\xc6\xe9\xfc\x0f\xdc\x91\xef\xbc\x47\x1c\x46\xbf\xd8\x99\xb6\xab\xb1\x31\x36\x0d\xc7\x5f\xf3\x50\x80\xe3\x8b\xe0\xee\x19\xac\xb9\xcb\x17\x42\x5d\x82\x5e\xc9\xe0\x92\xd6\x5f\x11\x3b\xef\x82\x9d\xa1\xb3\x1e\x5d\xd6\x23\xc9\xce\xc4\x58\x8f\x0d\xd3\x58\xb0\x7b\xf3\x2e\xc3\xc0\x1b\x36\x6a\x1a\x76\x18\xac\xee\xdf\xb2\xf9\xdf\x1f\x3a\x4d\x18\x72\xdf\xf2\xd4\x4f\x08\x6e\x4b\x7f\x00\x47\xb2\x08\xa4\x8e\x03\x00\x00")
+
+func migrations_gateway21_sent_transaction_metadataSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway21_sent_transaction_metadataSql,
+		"migrations_gateway/21_sent_transaction_metadata.sql",
+	)
+}
+
+func migrations_gateway21_sent_transaction_metadataSql() (*asset, error) {
+	bytes, err := migrations_gateway21_sent_transaction_metadataSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/21_sent_transaction_metadata.sql", size: 910, mode: os.FileMode(420), modTime: time.Unix(1792046656, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway22_leader_leaseSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x6d\x90\xbd\x0e\x82\x40\x10\x84\xfb\x7b\x8a\x29\x31\x4a\x63\xc4\xc6\x0a\xe5\x0a\x22\x1c\x4a\xee\x12\xa9\xc8\x05\x36\x7a\x09\xa2\x9e\xf8\xf3\xf8\x1e\x26\x1a\x8d\x36\x5b\xec\x37\xb3\xd9\x19\xdf\xc7\x70\x6f\xb6\x56\x77\x04\x75\x64\x8b\x9c\x87\x92\x43\x86\xf3\x84\x23\x21\x5d\x93\x75\xf3\x4c\xf0\x18\x60\x6a\x98\xb6\xa3\x2d\x59\xac\xf2\x38\x0d\xf3\x02\x4b\x5e\x20\x54\x32\x8b\x85\x73\xa6\x5c\xc8\x91\xd3\xb5\x7a\x4f\xb8\x6a\x5b\xed\xb4\xf5\xa6\x93\x01\x44\x26\x21\x54\x92\xf4\x70\x77\x68\xdc\xd1\x37\x1e\x07\xc1\x37\xd7\xd5\xe9\x62\x2c\xd5\xa5\xee\x50\xbb\xaf\x3a\xe3\x8e\x7d\x0a\xe8\x7e\x74\xfc\xfc\x97\xb3\xc1\x8c\xbd\x32\x28\x11\xaf\x15\x47\x2c\x22\xbe\x41\xf3\x8c\x52\x36\x7d\x96\xf2\xf9\x5f\x26\xbe\xf3\xf5\xcb\xde\xed\x7f\x14\x12\x1d\x6e\x2d\x8b\xf2\x6c\xf5\x5b\xc8\x8c\x3d\x00\x69\xfc\x04\x5c\x3a\x01\x00\x00")
+
+func migrations_gateway22_leader_leaseSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway22_leader_leaseSql,
+		"migrations_gateway/22_leader_lease.sql",
+	)
+}
+
+func migrations_gateway22_leader_leaseSql() (*asset, error) {
+	bytes, err := migrations_gateway22_leader_leaseSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/22_leader_lease.sql", size: 314, mode: os.FileMode(420), modTime: time.Unix(1792048227, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway23_sep24_transactionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x8d\x94\x4d\x73\x82\x30\x10\x86\xef\xfc\x8a\xbd\xa9\x53\x9c\xb1\x54\xbd\x78\xa2\x35\x9d\x71\xaa\x68\x2d\xcc\xd4\x13\x93\xe2\xaa\x99\x42\xc2\x24\xa1\x1f\xff\xbe\xe0\xf8\x05\x0d\xe2\x95\xf7\xd9\x77\xb3\xe4\xdd\x74\xbb\x70\x97\xb0\xad\xa4\x1a\x21\x48\xad\xa7\x25\x71\x7d\x02\xbe\xfb\x38\x25\xf0\x46\x16\x4e\xdf\x97\x94\x2b\x1a\x69\x26\x38\xb4\x2d\x00\xb6\x06\xc6\x35\x6e\x51\xc2\x62\x39\x99\xb9\xcb\x15\xbc\x90\x15\xb8\x81\x3f\x9f\x78\x79\xf9\x8c\x78\xbe\x9d\x73\xfa\x5c\x17\xe6\x35\x5f\x54\x46\x3b\x2a\xdb\xc3\x7e\x07\xbc\xb9\x0f\x5e\x30\x9d\x16\xd8\x27\xe3\x67\xf1\xbe\x57\x16\x95\xa6\x3a\x53\x27\xf9\xc1\x29\xcb\x34\x8a\x44\xc6\xf5\x49\x1f\x0c\x2b\xba\x52\xa8\xc3\x48\xac\xf1\xdc\xc1\x31\x21\x4c\xa9\x2c\x9f\xc7\xe4\x03\x63\xf2\xec\x06\x53\x1f\x5a\xad\x3d\x9f\x14\x1d\x43\xc6\xe1\x83\x6d\xf3\xff\xf0\x9f\xeb\x5d\x60\x22\xd3\x37\x71\x1b\xc4\x06\x8e\x47\x3b\x21\xc3\x6b\x03\x57\x0e\x9a\x60\x22\x42\xfd\x9b\x9e\x47\xbf\xce\x1a\x2f\xa8\xca\x09\x89\xf9\xe8\x1b\x11\x66\x32\x3e\x15\x38\x83\x41\x6d\x85\xd2\x18\xc7\x54\x86\x37\x84\xa1\x52\x89\x3f\x1a\x25\xa7\x71\x5d\xe9\xb5\xae\x09\x2a\x45\xb7\x78\xe3\x09\xa9\xd4\xb8\x0e\xa9\x86\x75\xbe\x03\x9a\x25\x58\x0a\x48\x96\x16\x9f\xeb\xf5\x48\x24\x69\x8c\x55\xe2\xd8\xa3\xa0\xac\xce\xc8\x3a\xee\x55\xe0\x4d\x5e\x03\x02\x13\x6f\x4c\xde\x41\x61\xea\xf4\x4b\xf3\x55\x66\x9d\x7b\x86\x15\x2c\x33\xb9\xf7\xc1\xba\xce\x73\x7f\xb9\x46\xa7\x53\x44\xec\x7d\x02\xec\xc3\xba\x35\x5b\x1e\x63\x68\x74\x3d\x88\xf6\xc5\xf6\x35\x3b\xd6\xe4\xc4\xd8\xc0\xcc\x36\xf7\xa8\x4b\x94\xb1\x49\x0d\x5c\x5c\x65\xf7\xe2\xc5\x1c\x8b\x6f\x6e\x8d\x97\xf3\x45\xcd\x8b\x39\xb2\xfe\x00\xa7\x07\xf2\x2e\x60\x05\x00\x00")
+
+func migrations_gateway23_sep24_transactionSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway23_sep24_transactionSql,
+		"migrations_gateway/23_sep24_transaction.sql",
+	)
+}
+
+func migrations_gateway23_sep24_transactionSql() (*asset, error) {
+	bytes, err := migrations_gateway23_sep24_transactionSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/23_sep24_transaction.sql", size: 1376, mode: os.FileMode(420), modTime: time.Unix(1792049628, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway24_held_payment_seedSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xc5\x54\x5d\x6f\x9b\x30\x14\x7d\xe7\x57\xdc\xc7\x44\x73\xa4\x6e\x5a\xfa\xd2\x27\x56\x5c\x0d\x8d\x98\x94\x80\xb4\x3e\x21\x17\xae\x1a\x4b\x7c\xcd\xbe\xb4\xcb\xbf\x9f\xd3\x10\x62\x8a\x32\x69\x4f\x7b\xc3\xf6\x39\xd6\xf9\xf0\x65\xb5\x82\x4f\xb5\x7a\xd1\x92\x10\xb2\xce\xf3\xa3\x94\x27\x90\xfa\xdf\x22\x0e\xdf\xb1\x2a\xb7\xf2\x50\x63\x43\xe0\x07\x01\x60\x53\xe8\x43\x47\x58\xe6\x06\xb1\x04\xc2\xdf\x04\x01\x7f\xf0\xb3\x28\x05\x91\x45\xd1\xdd\x5f\xd9\x92\x08\xeb\x8e\x0c\xa8\x86\xf0\x05\x35\x88\xf8\x44\x1b\xef\xb8\xb9\xf3\xbc\x95\x23\x27\x68\xdf\x9a\xe3\xc6\xee\x31\x52\x76\x59\xc8\xa6\x69\x09\x4a\xdd\x76\x50\xb4\x55\x5f\x37\x86\x01\xed\x11\x48\x3e\x57\x08\xca\xd8\xdd\x4e\x59\x61\x6f\x8a\xf6\x6d\x4f\xf0\xae\x72\x40\x7a\xf7\x09\xf7\x53\x3e\xd7\x96\xb7\x55\x09\x0b\x0f\x40\x95\xa3\xb2\x6d\x12\x6e\xfc\xe4\x09\x7e\xf0\x27\xf0\xb3\x34\x0e\x85\x65\x6f\xb8\x48\x99\xc5\x75\x03\xcf\xe2\x5f\xa5\x2e\xf6\x52\x2f\xbe\xac\xd7\xcb\x8b\x9d\x4c\x84\x8f\x19\x3f\x42\x4d\xdb\xeb\x02\x73\x59\x14\x6d\x6f\x53\x38\xc3\xd7\xb7\x17\xf4\x11\xa6\xf1\x57\x8f\x86\x4e\x81\xba\x07\x86\x24\xf5\x66\xe4\x7d\xbe\x99\xf2\x0a\x8d\xf2\xd8\x86\xb4\xa1\xd8\x0f\x52\x35\x4e\xe9\x48\x54\xe1\xd5\x63\x9b\x67\x81\x55\xf5\xe1\x02\xb7\xd0\x93\x38\xd3\x57\x34\x2f\xdb\x15\xf0\x7c\x18\x35\xde\x7e\x5d\xce\x60\xb2\xeb\x74\xfb\xfa\x0f\xb8\x6b\x7a\xbc\xa5\x7d\x21\xa1\xd8\xf1\x24\x85\x50\xa4\xf1\xbc\x47\x55\x32\xa7\x20\xf6\xa1\x01\x76\x8e\x9a\x0d\xd1\x32\x27\x43\x76\xc9\x8b\x4d\xb2\x61\x43\x06\xcc\xf1\xcb\x5c\x53\xcc\x55\xbe\xb4\x46\x76\x3c\xe2\xf7\x29\xfc\x7f\x31\xf0\x90\xc4\x1b\x37\x25\x9b\x5f\x90\xc4\xdb\xf9\x18\x5c\x9d\xdd\xf7\x5c\x13\x2e\xfc\x8d\x1d\x9e\x78\x4a\x19\x66\x2a\x14\x01\xff\x09\x7b\x7b\x92\x9f\xed\x9e\x1c\xe5\x97\x17\x18\x8b\xc9\x0f\x61\x71\xb6\x3c\x22\x6c\xb5\x7f\x00\xa6\xfe\xaa\xfc\x8b\x04\x00\x00")
+
+func migrations_gateway24_held_payment_seedSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway24_held_payment_seedSql,
+		"migrations_gateway/24_held_payment_seed.sql",
+	)
+}
+
+func migrations_gateway24_held_payment_seedSql() (*asset, error) {
+	bytes, err := migrations_gateway24_held_payment_seedSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/24_held_payment_seed.sql", size: 1163, mode: os.FileMode(420), modTime: time.Unix(1792051914, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway25_sent_transaction_conflictSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x56\x4d\x6f\x9b\x40\x10\xbd\xfb\x57\xcc\x2d\x8e\x8a\x51\x53\x35\xbd\xa4\x3d\xd0\x78\xa3\xa2\x62\x48\x30\x56\x93\x13\x5a\xc3\xc6\x59\x09\xb3\x0e\x0c\x49\xfa\xef\x3b\x6b\xf3\x1d\x9b\xaa\xaa\x2a\xb9\x52\x6e\x0b\xf3\x76\xf6\xed\xcc\x9b\xa7\x9d\x4c\xe0\xdd\x5a\xae\x32\x8e\x02\x16\x9b\xd1\xa5\xcf\xac\x80\x41\x60\x7d\x75\x18\xcc\x45\x8a\x41\xc6\xd3\x9c\x47\x28\x55\x7a\xa9\xd2\xfb\x44\x46\x08\xe3\x11\x80\x8c\x41\xa6\x28\x56\x22\x83\x6b\xdf\x9e\x59\xfe\x1d\x7c\x67\x77\x06\x45\x36\xfc\xe7\x9a\x36\x86\x84\x78\xe2\x59\xf4\xc0\xb3\xf1\x87\xf3\xf3\x53\x70\xbd\x00\xdc\x85\xe3\x68\x4c\x26\x56\x94\xb0\x8e\x7f\xfa\xd8\x84\x61\xca\xae\xac\x85\x13\xc0\xc9\x89\x46\x62\x73\x7e\x3b\x63\x7b\x87\x86\xe5\xc8\xb1\xc8\xeb\xf0\xd9\xfb\x5e\x58\x15\x59\x24\xea\xf0\xf9\xa7\x5e\xb8\x58\xae\x25\xa2\x88\x43\x8e\x10\x53\x29\x50\xae\x45\x0f\x11\x45\x42\xc4\x3d\x44\x45\xb5\x42\x25\x22\xd6\x05\x59\xca\x15\xd5\xe6\x55\x54\xa4\x4f\x22\x51\x1b\x11\xbe\xc4\x19\xa0\x78\xc1\x5e\x49\xf2\x22\xc1\x6d\xac\x53\xb6\x7e\x96\x25\x8f\xc3\x5c\x3c\x86\x84\x57\x49\x81\xed\x32\x9e\xe9\x6b\xb5\x4b\x58\xed\x59\x0b\xe4\x44\x9a\x97\xa7\xf6\x21\xa3\xd3\x8b\x51\xd5\x79\xdb\x9d\xb2\x5b\xc8\x75\x03\xdb\xa5\x8f\xca\xde\x87\xad\xee\x7a\xee\x61\x85\x34\x30\x9d\x7b\x32\x81\xeb\xdd\x0f\xb0\xa7\x20\x73\x28\x52\xf9\x58\x08\xe0\x51\xa6\xf2\xbc\x54\x43\x0e\x7c\xc5\x65\x6a\xb4\x5b\x9e\x83\xba\x07\x85\x0f\x54\xd4\x12\xa4\x73\x3d\x4b\x7c\x00\xfa\x09\x39\xa7\x26\x6c\x9a\xcc\x3c\x13\xb0\x56\x4f\x22\x06\x54\x87\xb8\x8d\x6c\x77\xce\xfc\x80\x2e\x1a\x78\x87\xf9\xcb\xd8\x68\x09\xd9\x28\x4f\x37\x7a\x72\x34\x4a\xdd\x19\xa5\xc0\x8c\x8e\x92\x8c\x8e\x6a\x8c\x52\x1d\x46\x47\x07\x46\xab\xef\xc6\x9e\xde\x1a\x75\xef\x4e\xa9\x8f\x73\xe6\xb0\xcb\x00\xd0\xd4\x47\xa3\xd9\x26\x88\x66\x4d\xd1\xec\x93\x44\xb3\xa2\x49\xab\x92\x28\xad\x3a\x54\xf5\x77\x9b\x2c\x9a\x15\x5d\x34\xbb\x84\xf5\x49\x0d\x65\x34\xf7\x91\x46\xb3\xa2\x4d\xac\xaf\x7c\x6f\xd6\xaf\x34\x20\x05\x7e\x7c\x63\x3e\x03\x76\x6b\xcf\x83\x39\x8c\xcb\xcb\x9d\xed\xc7\xab\x12\xad\x5a\xb7\x86\x2f\x9d\x22\x80\xe5\x4e\x29\x4e\x8b\xcf\xdb\x12\x95\xca\x9b\xdf\x38\x92\xcc\x2d\xe2\x69\xaa\x68\x76\x33\xb5\x01\x52\x73\x4e\x45\xa2\x21\xd5\x35\x21\x21\x21\x5f\x26\x42\x0b\x33\x52\x1b\x49\xf2\xd9\x2a\xac\x14\x69\xa3\xaf\x41\x77\x0c\x53\xf1\x3c\xe0\x8c\x60\x2d\x02\xcf\x76\x29\xc3\x8c\xb9\xc1\xa0\x4f\xf6\xc7\x13\x16\xae\x7d\xb3\x60\x6f\xbe\x79\x94\xbe\x39\x60\x27\x3b\x49\x1c\xb5\x95\x1c\x1f\xb9\x43\x86\xb1\x1b\x7f\x92\xb2\x6e\xbe\xed\xd6\x7e\x41\x7f\xf6\xe1\x2b\x2b\xd7\x2d\x9a\xfa\xde\xf5\xfe\xa1\xbd\x18\x59\x4e\xc0\xfc\x81\x89\xf6\x99\x6b\xcd\x68\xe4\xbd\xd7\x5b\xb5\xb9\xd4\x6f\xa7\xa9\x7a\x4e\x87\xfd\x41\x25\xf1\xbf\xf1\x87\x37\x63\x38\x36\x63\xd0\x90\x9d\x69\xc3\xb8\x9a\xa8\xd6\x8b\xe8\xb7\xbe\xb1\x95\xca\x9b\x6f\xfc\x11\xb9\xbd\x2e\xb0\xeb\x03\x3d\x54\x2d\x72\xeb\xff\x8d\x7c\x65\x61\x7f\xe1\x60\x5a\x48\x03\x0e\x76\x38\x6d\x73\xf6\x2f\xba\xac\x2e\x63\x21\x0e\x00\x00")
+
+func migrations_gateway25_sent_transaction_conflictSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway25_sent_transaction_conflictSql,
+		"migrations_gateway/25_sent_transaction_conflict.sql",
+	)
+}
+
+func migrations_gateway25_sent_transaction_conflictSql() (*asset, error) {
+	bytes, err := migrations_gateway25_sent_transaction_conflictSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/25_sent_transaction_conflict.sql", size: 3617, mode: os.FileMode(420), modTime: time.Unix(1792052278, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway26_sent_transaction_original_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x96\x41\x6f\x9b\x30\x14\xc7\xef\xf9\x14\xef\xd6\x44\x73\xa4\x75\x5a\x72\xd9\x89\x05\x57\x42\x23\xd0\x12\x23\xb5\x27\xe4\x80\x97\x58\x02\x3b\x33\xa6\xed\xbe\xfd\xec\x88\x24\x86\x32\x5a\x6d\x97\x4d\xea\xd1\xbc\xbf\x9f\xdf\xfb\x3d\xff\x2d\xe6\x73\xf8\x50\xf1\x9d\xa2\x9a\x41\x7a\x98\x78\x21\xc1\x09\x10\xef\x6b\x88\x61\xc3\x84\x26\x8a\x8a\x9a\xe6\x9a\x4b\x01\x9e\xef\xc3\x2a\x0e\xd3\x75\x04\x52\xf1\x1d\x17\xb4\xcc\x78\x01\x5c\x68\xb6\x63\x0a\xa2\x34\x0c\xc1\xc7\x37\x5e\x1a\x92\xe3\xe2\xcb\x58\xb6\x95\x14\xdf\x4b\x9e\xeb\x3f\xc8\x3a\x99\x3b\x35\xfb\xf2\x49\xd8\x0f\x9b\xbb\x90\x9b\x65\x4e\x85\x90\x1a\x0a\x25\x0f\x90\xcb\xb2\xa9\x44\x8d\x40\xef\x19\x68\xba\x2d\x59\x0d\x54\x19\x8d\x3c\x70\x56\xc0\x13\xd7\x7b\xd9\x68\xf7\xd4\xc9\x2a\xc1\x1e\xc1\xc3\x15\x67\xb2\x2c\x60\x3a\x01\x70\xaa\xbb\x4d\x82\xb5\x97\x3c\xc0\x37\xfc\x00\x5e\x4a\xe2\x20\x32\x19\xd6\x38\x22\xc8\xe8\x0e\xf4\x67\x65\x52\xd8\x6e\x1e\xa9\xca\xf7\x54\x4d\x3f\x2d\x16\xb3\x97\x2d\x41\x1a\x05\x77\x29\xb6\x7b\x14\xdb\x59\xd4\x27\xfd\xf2\xb3\x91\xc7\xa4\xbb\xe5\xea\xca\x2a\xb5\x53\x99\x73\x82\xbb\xc3\xca\x6a\x4d\x75\x53\x9f\xc3\xd7\x1f\x7b\x61\xd9\xa8\x9c\x9d\xc3\x8b\x65\x2f\xdc\x6c\x2b\xae\x35\x2b\x32\x6a\xa0\x1a\xde\x9a\x57\xac\xa7\xc8\x73\xc6\x8a\x9e\xc2\xed\xce\xaa\x4a\x56\x58\x5c\x5b\x4b\x5a\xbf\x88\x32\xf1\xc8\x4a\x79\x60\xd9\x73\xa1\x40\xb3\x67\xdd\x39\x41\xb1\xba\x29\xf5\x31\xd6\xc1\xd8\xcf\xb2\xa5\x45\x56\xb3\x1f\x99\xd1\x9b\xc1\x6b\x17\xe3\xf5\x72\x80\xba\xdd\x53\x31\x4d\x4d\xd1\xb4\x3d\xb5\x2f\x99\xcc\xcc\x6d\x0b\xa2\x0d\x4e\x08\x04\x11\x89\x87\xaf\x04\x2f\x90\x33\x6b\xd4\xce\x10\xf5\x26\x84\xda\x51\xa0\x96\x39\xea\xc0\x45\x1d\x90\xa8\x05\x86\x3a\x68\x90\x83\x02\x0d\xb4\x8b\xce\xed\xcc\x4c\x6b\x1b\x1c\xe2\x15\x81\x7f\xaf\x38\xb8\x49\xe2\x75\x9f\xa4\xe1\xec\x27\xf1\xed\xb0\xf3\x46\x1f\x92\xe3\x0c\x12\x1c\x79\x6b\xe3\xdb\x78\x20\xef\x98\xa7\x4f\xaf\xd0\x2b\xde\x1e\x75\x73\xe7\xaa\xbe\xbb\xf7\xff\x71\x6f\x77\xf8\xef\x2e\xfe\x7b\x17\x9f\x88\x8e\xba\xf9\x22\x7a\xc3\xef\xc1\xb8\xbb\x2f\xa9\x5a\x93\x07\x91\x8f\xef\xa1\xb6\x98\x5c\x34\xf9\x29\x9b\x63\xe2\x38\xfa\xed\x2f\xc9\xf4\x22\x33\x57\xe8\x17\xd9\x44\x4c\xd0\x22\x09\x00\x00")
+
+func migrations_gateway26_sent_transaction_original_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway26_sent_transaction_original_idSql,
+		"migrations_gateway/26_sent_transaction_original_id.sql",
+	)
+}
+
+func migrations_gateway26_sent_transaction_original_idSql() (*asset, error) {
+	bytes, err := migrations_gateway26_sent_transaction_original_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/26_sent_transaction_original_id.sql", size: 2338, mode: os.FileMode(420), modTime: time.Unix(1792052419, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_gateway27_api_key_tenantSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xad\x92\x4d\x4f\x83\x40\x10\x86\xef\xfc\x8a\xb9\xd9\x46\x9a\x18\xa3\x5e\x3c\xad\x65\x9b\x90\xc2\x52\xe9\x92\xe8\x89\xac\x30\xb6\x1b\xe9\x6e\x5d\x16\x3f\xfe\xbd\xd3\x8a\xb5\xa4\x1e\xbd\xc1\x7c\x3e\xef\xbb\x33\x99\xc0\xf9\x46\xaf\x9c\xf2\x08\xc5\x36\x60\x89\xe4\x39\x48\x76\x97\x70\x60\x8b\x78\x8e\x9f\xc0\xa2\x08\xa6\x59\x52\xa4\x02\x3c\x1a\x65\x3c\xbc\x29\x57\xad\x95\x1b\xdd\x5c\x8d\x41\x64\x12\x44\x91\x24\x10\xf1\x19\x2b\x12\x09\x67\x67\xb7\x41\x30\x39\x9a\x1a\xd9\x77\xb3\x0b\x2c\xef\x13\x4d\xbf\x95\x32\xc6\x7a\xa8\x9d\xdd\x42\x65\x9b\x6e\x63\xda\x10\xfc\x1a\xc1\xab\xa7\x06\x41\xb7\x14\xdd\x6a\xac\xe1\x5d\xfb\xb5\xed\x7c\xbf\x34\x98\xe6\x9c\x49\x3e\x40\x2b\x6d\x53\xc3\x28\x00\xd0\x35\x68\xe3\x71\x85\x0e\x16\x79\x9c\xb2\xfc\x11\xe6\xfc\x11\x58\x21\xb3\x58\x50\x63\xca\x85\x0c\xa9\xee\x85\x7a\xa8\xf6\x2f\xfe\x5d\xba\xc5\xca\xe1\xaf\xbc\xcb\xeb\xeb\x61\xde\xe1\x6b\xa7\x1d\x96\xad\x5e\x19\xe5\x3b\x87\xf0\x64\x6d\x83\xca\x9c\xba\xf0\xac\x9a\x16\xf7\x3d\x64\x41\xd9\xe8\x8d\xf6\x07\xc4\x93\xe2\x8b\xfd\x72\xdb\xb9\x0a\x5b\x92\xfb\xe1\x07\x5b\x89\x89\x66\xd4\xa5\x22\xd3\xe8\xc3\xeb\x0d\x1e\xf2\xc1\x98\xcc\x8e\xc5\x92\xe7\x12\x62\x21\xb3\x81\x31\xba\x0e\x7b\xc5\x61\x2f\x2d\x3c\x95\x10\x1e\x11\x86\x3f\x10\xe1\xd1\xd2\x31\x21\x2c\x79\xc2\xa7\x12\xfe\x67\x20\xcc\xf2\x2c\xed\x41\x89\x3e\xca\xb3\xc5\xe0\x55\x6f\xff\x38\xc2\xbd\xa0\x9c\x0b\x96\xd2\x05\x64\x87\xc2\xfe\x26\x0a\x11\xdf\x17\x9c\x0c\x88\xf8\x03\xa8\xad\x2e\x77\x90\xfd\x5b\x67\xe2\xe7\x8e\x47\xdf\x11\x72\xec\x0b\x0e\x52\x3f\xcb\xf4\x02\x00\x00")
+
+func migrations_gateway27_api_key_tenantSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_gateway27_api_key_tenantSql,
+		"migrations_gateway/27_api_key_tenant.sql",
+	)
+}
+
+func migrations_gateway27_api_key_tenantSql() (*asset, error) {
+	bytes, err := migrations_gateway27_api_key_tenantSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_gateway/27_api_key_tenant.sql", size: 756, mode: os.FileMode(420), modTime: time.Unix(1792053129, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance01_initSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x94\x4f\x73\x9b\x30\x10\xc5\xef\x7c\x8a\x3d\xc6\xd3\xe4\xd2\xa9\x73\xe1\x44\x63\x75\x86\xa9\x23\x52\x0a\x33\xcd\x49\xa3\xa0\x25\xd6\xd4\x88\x8c\x58\x62\xbb\x9f\xbe\x43\xb1\x0d\x32\x06\x8f\x7b\xc8\x55\xfb\x76\xf5\xde\x4f\x7f\xee\xee\xe0\x53\xa1\x5f\xad\x24\x84\xf4\xcd\x7b\x88\x59\x90\x30\x48\x82\xaf\x4b\x06\x41\x4d\xab\xd2\xea\x3f\xa8\x12\x2b\x4d\x25\x33\xd2\xa5\x81\x1b\x0f\x40\x2b\xd0\x86\xf0\x15\x2d\x3c\xc5\xe1\x63\x10\x3f\xc3\x77\xf6\x0c\x41\x9a\x44\x21\x7f\x88\xd9\x23\xe3\xc9\xad\x07\x40\x5d\x9f\xd0\x0a\xde\xa5\xcd\x56\xd2\xde\xdc\x7f\x99\x01\x8f\x12\xe0\xe9\x72\xd9\xc8\x0a\x2c\xca\xd1\x62\x7f\xc6\x56\x59\x20\xdc\x92\x23\x90\x47\x9b\x42\x12\x28\x49\x48\xba\x40\x47\xa2\x24\x49\xb7\xd1\x9b\xf9\xde\x49\xda\xf5\xba\xdc\xa0\xfa\x16\x5e\x95\xd0\xc8\x02\x8f\xd6\x3f\xcf\xe7\xae\x77\x55\x16\x52\x9b\xf1\xfa\x5b\xfd\xb2\xd6\x99\xf8\x8d\x3b\xf8\x27\x98\xdf\xbb\x75\xd9\x7a\x3a\x9b\xab\x9f\x20\xe5\xe1\x8f\x94\x41\xc8\x17\xec\x17\xc8\x5c\x8b\x97\x9d\xd8\xef\x1d\xf1\x7e\xb2\x76\x71\xe6\x4f\x35\xf6\x4c\xb9\xcd\x5d\x61\x0c\x5e\x5a\xa1\xbd\x0a\x5f\xae\xc5\x34\xc1\x5c\x8b\x4b\x10\x73\x2d\x2e\x71\xac\x2b\xb4\xfd\x0b\x38\x98\xf1\x7f\xa0\xeb\x06\x97\xb3\xbd\x38\xec\xd4\x91\x6b\x99\x38\xaa\x5b\xd8\xcb\x06\x1c\x7f\xa2\xa1\x80\x48\x66\xab\x02\x0d\x7d\xf8\x5b\xab\xd0\x28\xb4\xe3\x98\xda\xfa\x24\x49\x8b\x19\xea\xf7\x46\x64\xf2\xf2\xcc\x63\xed\xc2\x0d\x6a\x15\x1a\xba\x78\x02\xed\x1d\xdf\x4b\x0f\xb3\xc4\x49\xf8\x88\x0f\x48\xba\x8a\x66\x60\xff\xeb\x5b\x94\x1b\xe3\x2d\xe2\xe8\x69\xea\xeb\xf3\x1d\xc5\xe1\x5d\x9c\x5b\x6d\xce\xdc\x59\x77\xdd\xf8\xde\xdf\x01\x00\xc5\x09\x2b\x0d\x77\x05\x00\x00")
+
+func migrations_compliance01_initSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance01_initSql,
+		"migrations_compliance/01_init.sql",
+	)
+}
+
+func migrations_compliance01_initSql() (*asset, error) {
+	bytes, err := migrations_compliance01_initSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/01_init.sql", size: 1399, mode: os.FileMode(420), modTime: time.Unix(1792030942, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance02_federation_snapshotSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x92\x41\x6f\x82\x40\x10\x85\xef\xfc\x8a\xb9\xa9\x69\x3d\xb4\xa9\x5e\x3c\xd1\xb2\x26\xa6\x08\x86\x42\x52\x4f\x9b\x2d\x8c\xb2\x29\xec\x92\xdd\xc5\xd6\x7f\xdf\xc5\x54\x04\x8b\xe9\x75\xdf\xf7\x5e\x66\x66\xdf\x74\x0a\x77\x25\xdf\x2b\x66\x10\x92\xca\x79\x89\x88\x1b\x13\x88\xdd\x67\x9f\xc0\x12\x33\xb4\x02\x97\xe2\x4d\xb0\x4a\xe7\xd2\xc0\xd8\x01\xe0\x19\x70\x61\x70\x8f\x0a\x36\xd1\x6a\xed\x46\x5b\x78\x25\x5b\x70\x93\x38\x5c\x05\x36\x60\x4d\x82\xf8\xde\x72\x46\x31\xa1\x59\xda\xf8\xa9\xf5\xa4\x39\x53\xe3\xf9\xd3\x04\x82\x30\x86\x20\xf1\xfd\x86\xa9\xd8\xb1\x44\x61\x1a\xfd\xc0\xd4\x09\x79\x9c\xcd\x26\xe0\x91\xa5\x9b\xf8\x17\xee\x93\x8b\x0b\xf1\x30\xef\x87\xd4\xaa\x00\x83\xdf\xa6\xf7\xb8\xe3\x82\x15\x74\x50\xd2\x86\x99\x5a\xd3\x54\x66\xd8\x6e\xd2\xd5\x3f\x64\x76\xfc\xeb\x32\xaa\x16\xa9\x3d\x53\x66\x75\x59\x20\x13\xad\xda\x4e\xbb\x63\x85\xc6\x13\x5b\x68\x7a\x40\xa5\xed\xea\x83\x53\xb7\x8e\xd1\xa8\xc1\x53\x5e\xe5\xa8\xa8\xae\xb9\xfd\x85\x33\xdf\x3d\xd5\x35\x8f\xca\xf0\x1d\x6f\xa6\xd1\x03\x9b\xa3\x49\x73\xcc\x28\x33\x60\x78\x89\x76\xdb\xb2\xea\x01\x0a\x53\xa9\xb2\x5b\x84\x33\x59\x38\xe7\x1e\xac\x02\x8f\xbc\xdb\xc0\x73\x0f\xa8\xfe\x2d\x02\xbd\xfa\xdc\x30\x18\x6c\x4b\x9f\xb2\xc1\xff\xe6\x76\x0a\x71\x23\xf3\x42\x34\x83\x4e\x3b\xfd\xf5\xe4\x97\x70\xbc\x28\xdc\xdc\xec\xef\xc2\xf9\x01\x2f\xef\xa4\xd5\xf0\x02\x00\x00")
+
+func migrations_compliance02_federation_snapshotSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance02_federation_snapshotSql,
+		"migrations_compliance/02_federation_snapshot.sql",
+	)
+}
+
+func migrations_compliance02_federation_snapshotSql() (*asset, error) {
+	bytes, err := migrations_compliance02_federation_snapshotSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/02_federation_snapshot.sql", size: 752, mode: os.FileMode(420), modTime: time.Unix(1792037395, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance03_sanctions_screeningSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x52\x4d\x4f\xc2\x40\x10\xbd\xf7\x57\xcc\x0d\x88\x72\xd0\x08\x17\x4e\xd5\xae\x09\xb1\xb4\xa4\xb4\x09\x9c\x9a\x71\x77\x84\x4d\xe8\x96\xec\x0e\xe8\xcf\x77\x21\x16\x01\xdb\x78\x7e\x9f\xbb\x6f\x86\x43\xb8\xab\xf4\xda\x22\x13\x14\xbb\xe0\x25\x13\x61\x2e\x20\x0f\x9f\x63\x01\x0b\x34\x92\x75\x6d\xdc\x42\x5a\x22\xa3\xcd\x1a\xfa\x01\x80\x56\xa0\x0d\xd3\x9a\x2c\xcc\xb3\xe9\x2c\xcc\x56\xf0\x26\x56\x10\x16\x79\x3a\x4d\xbc\xc1\x4c\x24\xf9\xbd\xe7\xb1\x45\xe3\xf0\xe4\x50\x7a\xcd\x01\xad\xdc\xa0\xed\x8f\x9f\x06\x90\xa4\x39\x24\x45\x1c\x43\x24\x5e\xc3\x22\xce\xa1\xd7\x3b\x2a\x6c\xbd\xa5\x33\xef\x61\xfc\xcb\x3b\x82\xa8\x94\x25\xe7\xce\xf8\xe3\x68\x74\x43\x90\xb2\xde\x1b\xbe\xcc\x1a\x8d\x3b\xb3\x0c\x56\xd4\xee\x75\x43\xdc\xd9\xfa\xa0\x95\x7f\x6c\x67\xb0\x63\xe4\xbd\xeb\x2c\x5e\x21\xcb\x0d\x39\x60\xfa\xe2\x2b\x40\x11\xa3\xde\xb6\x00\x12\xbd\x40\xc1\x7b\xed\xff\x03\xcd\xdf\x62\x1f\xb8\x75\x74\x4a\x3e\x0d\x43\xaa\x44\x06\xd6\x15\xf9\x26\xd5\xee\xcc\x0f\x06\x93\xa0\x59\x74\x9a\x44\x62\x09\xae\x59\xb4\x74\xcd\xa4\xe5\xcd\x4c\x69\xd2\xba\xfb\x35\xcb\x1b\xff\xeb\xdb\xec\xd5\x61\xf8\x03\x1f\x2b\x0e\x2f\x6e\x30\xaa\x3f\x4d\x10\x65\xe9\xbc\xf3\x06\x27\xc1\x37\xf5\x4f\xfc\x31\xb4\x02\x00\x00")
+
+func migrations_compliance03_sanctions_screeningSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance03_sanctions_screeningSql,
+		"migrations_compliance/03_sanctions_screening.sql",
+	)
+}
+
+func migrations_compliance03_sanctions_screeningSql() (*asset, error) {
+	bytes, err := migrations_compliance03_sanctions_screeningSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/03_sanctions_screening.sql", size: 692, mode: os.FileMode(420), modTime: time.Unix(1792042765, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance04_compliance_auditSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x52\xcb\x6e\x83\x30\x10\xbc\xf3\x15\x7b\x4b\xa2\x96\x43\xd3\x26\x97\x9c\x68\x70\x25\x54\x62\x52\x64\xa4\xe6\x84\xb6\xc6\x02\x4b\xe5\x51\x63\xd2\xe6\xef\x6b\xa4\x42\x42\x02\xea\x79\x1e\x9e\x19\xaf\x6d\xc3\x5d\x2e\x53\x85\x5a\x40\x54\x59\xdb\x90\x38\x8c\x00\x73\x9e\x7d\x02\xdb\x32\xaf\x3e\x25\x16\x5c\x38\x4d\x22\x35\xcc\x2d\x00\x99\x80\x2c\xb4\x48\x85\x82\x7d\xe8\xed\x9c\xf0\x00\xaf\xe4\x00\x4e\xc4\x02\x8f\x1a\xf5\x8e\x50\x76\x6f\x78\xb5\xf8\x6a\x84\x51\xc2\x87\x4c\x8d\x00\x68\xc0\x80\x46\xbe\xdf\x62\xfa\x54\x09\x38\xa2\xe2\x19\xaa\xf9\xe3\x72\x31\x04\x15\x16\x35\x72\x2d\xcb\x22\x36\x8f\x75\xb4\xf5\xd3\x99\x06\x2e\x79\x71\x22\x9f\xc1\x6c\xd6\x2a\x78\xd9\x98\x44\xaa\x42\xa5\x4f\x3d\x7f\xb9\x5a\x4d\x0a\x12\xc1\x65\x6d\xfc\x7b\xf2\xc3\x7a\x98\x21\x41\x8d\xa0\xc5\xcf\x30\x76\xa5\xc4\x31\xce\xb0\xce\xe0\x26\x51\x0b\x4f\x23\x5c\x09\x33\x6f\x12\xa3\x06\x2d\x73\x51\x6b\xcc\xab\x9e\x60\x2d\x36\x56\x37\x7b\x44\xbd\xb7\x88\x80\x47\x5d\xf2\x6e\x6a\x75\xeb\xc7\xd8\xce\x1f\xf7\x93\x06\xf4\xf6\x6b\x3a\xd0\xb8\xfd\x99\x4d\xb8\x5c\xed\x3b\xe6\x35\xa4\xfc\xeb\x78\x51\x6f\xcc\xed\x0c\xb7\x4d\xed\x8b\x7b\x73\xcb\xef\xc2\x72\xc3\x60\x3f\x7e\x6f\x1b\xeb\x17\x12\x5b\x07\x27\x9d\x02\x00\x00")
+
+func migrations_compliance04_compliance_auditSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance04_compliance_auditSql,
+		"migrations_compliance/04_compliance_audit.sql",
+	)
+}
+
+func migrations_compliance04_compliance_auditSql() (*asset, error) {
+	bytes, err := migrations_compliance04_compliance_auditSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/04_compliance_audit.sql", size: 669, mode: os.FileMode(420), modTime: time.Unix(1792042944, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations_compliance05_attachmentSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x65\x90\xcb\x6a\xc3\x30\x10\x45\xf7\xfa\x8a\xd9\x25\xa1\xf5\xa6\x34\xa5\x90\x95\x5a\xab\x60\xea\xc8\xa9\x91\xa0\x59\x99\xc1\x16\xb1\x16\x92\x8d\x3c\x7d\xfd\x7d\xe5\x40\xec\xb8\x5d\xdf\x33\x77\x66\x4e\x92\xc0\x8d\xb3\xa7\x80\x64\x40\xf7\xec\xb9\x14\x5c\x09\x50\xfc\x29\x17\xc0\x89\xb0\x6e\x9d\xf1\x04\x6b\x06\x60\x1b\xb0\x9e\xcc\xc9\x04\x38\x94\xd9\x9e\x97\x47\x78\x15\x47\xe0\x5a\x15\x99\x8c\x83\x7b\x21\xd5\x6d\xe4\x9c\x71\x5d\xd5\xe2\xd0\x42\xdd\x62\x58\x3f\xdc\x6f\x40\x16\x0a\xa4\xce\xf3\x31\x6e\x6c\x30\x35\xd9\xce\xc3\x27\x86\x33\xf1\xb8\x04\x28\xa0\x1f\xf0\x8c\x54\x71\xe7\x85\xfa\xdb\x53\x77\x1f\xf1\x9a\xd0\x63\xa0\x9f\x09\xba\xdb\x6e\x67\x0a\x52\xf1\xc2\x75\xae\x60\xb5\x1a\x07\x70\x7e\x87\xcc\x37\x2d\xcb\x82\x89\x06\x9a\x0a\x63\x66\x9d\x19\x08\x5d\x3f\x01\x6c\xb3\x63\x17\x33\x5a\x66\x6f\x5a\x40\x26\x53\xf1\x7e\xd5\x58\xcd\x4f\x17\x72\x21\x6e\x0a\xc6\x92\xe4\xca\x76\xda\x7d\x79\x96\x96\xc5\xe1\x9f\xed\x1d\xfb\x05\x6d\xda\x14\x87\x96\x01\x00\x00")
+
+func migrations_compliance05_attachmentSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations_compliance05_attachmentSql,
+		"migrations_compliance/05_attachment.sql",
+	)
+}
+
+func migrations_compliance05_attachmentSql() (*asset, error) {
+	bytes, err := migrations_compliance05_attachmentSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations_compliance/05_attachment.sql", size: 406, mode: os.FileMode(420), modTime: time.Unix(1792043136, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+// Asset loads and returns the asset for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func Asset(name string) ([]byte, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[cannonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("Asset %s can't read by error: %v", name, err)
+		}
+		return a.bytes, nil
+	}
+	return nil, fmt.Errorf("Asset %s not found", name)
+}
+
+// MustAsset is like Asset but panics when Asset would return an error.
+// It simplifies safe initialization of global variables.
+func MustAsset(name string) []byte {
+	a, err := Asset(name)
+	if err != nil {
+		panic("asset: Asset(" + name + "): " + err.Error())
+	}
+
+	return a
+}
+
+// AssetInfo loads and returns the asset info for the given name.
+// It returns an error if the asset could not be found or
+// could not be loaded.
+func AssetInfo(name string) (os.FileInfo, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if f, ok := _bindata[cannonicalName]; ok {
+		a, err := f()
+		if err != nil {
+			return nil, fmt.Errorf("AssetInfo %s can't read by error: %v", name, err)
+		}
+		return a.info, nil
+	}
+	return nil, fmt.Errorf("AssetInfo %s not found", name)
+}
+
+// AssetNames returns the names of the assets.
+func AssetNames() []string {
+	names := make([]string, 0, len(_bindata))
+	for name := range _bindata {
+		names = append(names, name)
+	}
+	return names
+}
+
+// _bindata is a table, holding each asset generator, mapped to its name.
+var _bindata = map[string]func() (*asset, error){
+	"migrations_gateway/01_init.sql":                   migrations_gateway01_initSql,
+	"migrations_gateway/02_security_event.sql":         migrations_gateway02_security_eventSql,
+	"migrations_gateway/03_api_key.sql":                migrations_gateway03_api_keySql,
+	"migrations_gateway/04_destination_profile.sql":    migrations_gateway04_destination_profileSql,
+	"migrations_gateway/05_daily_aggregate.sql":        migrations_gateway05_daily_aggregateSql,
+	"migrations_gateway/06_federation_snapshot.sql":    migrations_gateway06_federation_snapshotSql,
+	"migrations_gateway/07_received_payment_event.sql": migrations_gateway07_received_payment_eventSql,
+	"migrations_gateway/08_payment_limit_usage.sql": migrations_gateway08_payment_limit_usageSql,
+	"migrations_gateway/09_named_destination.sql": migrations_gateway09_named_destinationSql,
+	"migrations_gateway/10_held_payment_approval.sql": migrations_gateway10_held_payment_approvalSql,
+	"migrations_gateway/11_payment_velocity.sql": migrations_gateway11_payment_velocitySql,
+	"migrations_gateway/12_expected_payment.sql": migrations_gateway12_expected_paymentSql,
+	"migrations_gateway/13_refund.sql": migrations_gateway13_refundSql,
+	"migrations_gateway/14_failed_callback.sql": migrations_gateway14_failed_callbackSql,
+	"migrations_gateway/15_offline_transaction.sql": migrations_gateway15_offline_transactionSql,
+	"migrations_gateway/16_quote.sql": migrations_gateway16_quoteSql,
+	"migrations_gateway/17_fiat_value.sql": migrations_gateway17_fiat_valueSql,
+	"migrations_gateway/18_webhook.sql": migrations_gateway18_webhookSql,
+	"migrations_gateway/19_webhook_format.sql": migrations_gateway19_webhook_formatSql,
+	"migrations_gateway/20_memo_reference.sql": migrations_gateway20_memo_referenceSql,
+	"migrations_gateway/21_sent_transaction_metadata.sql": migrations_gateway21_sent_transaction_metadataSql,
+	"migrations_gateway/22_leader_lease.sql": migrations_gateway22_leader_leaseSql,
+	"migrations_gateway/23_sep24_transaction.sql": migrations_gateway23_sep24_transactionSql,
+	"migrations_gateway/24_held_payment_seed.sql": migrations_gateway24_held_payment_seedSql,
+	"migrations_gateway/25_sent_transaction_conflict.sql": migrations_gateway25_sent_transaction_conflictSql,
+	"migrations_gateway/26_sent_transaction_original_id.sql": migrations_gateway26_sent_transaction_original_idSql,
+	"migrations_gateway/27_api_key_tenant.sql": migrations_gateway27_api_key_tenantSql,
+	"migrations_compliance/01_init.sql":                migrations_compliance01_initSql,
+	"migrations_compliance/02_federation_snapshot.sql": migrations_compliance02_federation_snapshotSql,
+	"migrations_compliance/03_sanctions_screening.sql": migrations_compliance03_sanctions_screeningSql,
+	"migrations_compliance/04_compliance_audit.sql": migrations_compliance04_compliance_auditSql,
+	"migrations_compliance/05_attachment.sql": migrations_compliance05_attachmentSql,
+}
+
+// AssetDir returns the file names below a certain
+// directory embedded in the file by go-bindata.
+// For example if you run go-bindata on data/... and data contains the
+// following hierarchy:
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
+// then AssetDir("data") would return []string{"foo.txt", "img"}
+// AssetDir("data/img") would return []string{"a.png", "b.png"}
+// AssetDir("foo.txt") and AssetDir("notexist") would return an error
+// AssetDir("") will return []string{"data"}.
+func AssetDir(name string) ([]string, error) {
+	node := _bintree
+	if len(name) != 0 {
+		cannonicalName := strings.Replace(name, "\\", "/", -1)
+		pathList := strings.Split(cannonicalName, "/")
+		for _, p := range pathList {
+			node = node.Children[p]
+			if node == nil {
+				return nil, fmt.Errorf("Asset %s not found", name)
+			}
+		}
+	}
+	if node.Func != nil {
+		return nil, fmt.Errorf("Asset %s not found", name)
+	}
+	rv := make([]string, 0, len(node.Children))
+	for childName := range node.Children {
+		rv = append(rv, childName)
+	}
+	return rv, nil
+}
+
+type bintree struct {
+	Func     func() (*asset, error)
+	Children map[string]*bintree
+}
+
+var _bintree = &bintree{nil, map[string]*bintree{
+	"migrations_compliance": &bintree{nil, map[string]*bintree{
+		"01_init.sql":                &bintree{migrations_compliance01_initSql, map[string]*bintree{}},
+		"02_federation_snapshot.sql": &bintree{migrations_compliance02_federation_snapshotSql, map[string]*bintree{}},
+		"03_sanctions_screening.sql": &bintree{migrations_compliance03_sanctions_screeningSql, map[string]*bintree{}},
+		"04_compliance_audit.sql": &bintree{migrations_compliance04_compliance_auditSql, map[string]*bintree{}},
+		"05_attachment.sql": &bintree{migrations_compliance05_attachmentSql, map[string]*bintree{}},
+	}},
+	"migrations_gateway": &bintree{nil, map[string]*bintree{
+		"01_init.sql":                &bintree{migrations_gateway01_initSql, map[string]*bintree{}},
+		"02_security_event.sql":      &bintree{migrations_gateway02_security_eventSql, map[string]*bintree{}},
+		"03_api_key.sql":             &bintree{migrations_gateway03_api_keySql, map[string]*bintree{}},
+		"04_destination_profile.sql": &bintree{migrations_gateway04_destination_profileSql, map[string]*bintree{}},
+		"05_daily_aggregate.sql":     &bintree{migrations_gateway05_daily_aggregateSql, map[string]*bintree{}},
+		"06_federation_snapshot.sql": &bintree{migrations_gateway06_federation_snapshotSql, map[string]*bintree{}},
+		"07_received_payment_event.sql": &bintree{migrations_gateway07_received_payment_eventSql, map[string]*bintree{}},
+		"08_payment_limit_usage.sql": &bintree{migrations_gateway08_payment_limit_usageSql, map[string]*bintree{}},
+		"09_named_destination.sql": &bintree{migrations_gateway09_named_destinationSql, map[string]*bintree{}},
+		"10_held_payment_approval.sql": &bintree{migrations_gateway10_held_payment_approvalSql, map[string]*bintree{}},
+		"11_payment_velocity.sql": &bintree{migrations_gateway11_payment_velocitySql, map[string]*bintree{}},
+		"12_expected_payment.sql": &bintree{migrations_gateway12_expected_paymentSql, map[string]*bintree{}},
+		"13_refund.sql": &bintree{migrations_gateway13_refundSql, map[string]*bintree{}},
+		"14_failed_callback.sql": &bintree{migrations_gateway14_failed_callbackSql, map[string]*bintree{}},
+		"15_offline_transaction.sql": &bintree{migrations_gateway15_offline_transactionSql, map[string]*bintree{}},
+		"16_quote.sql": &bintree{migrations_gateway16_quoteSql, map[string]*bintree{}},
+		"17_fiat_value.sql": &bintree{migrations_gateway17_fiat_valueSql, map[string]*bintree{}},
+		"18_webhook.sql": &bintree{migrations_gateway18_webhookSql, map[string]*bintree{}},
+		"19_webhook_format.sql": &bintree{migrations_gateway19_webhook_formatSql, map[string]*bintree{}},
+		"20_memo_reference.sql": &bintree{migrations_gateway20_memo_referenceSql, map[string]*bintree{}},
+		"21_sent_transaction_metadata.sql": &bintree{migrations_gateway21_sent_transaction_metadataSql, map[string]*bintree{}},
+		"22_leader_lease.sql": &bintree{migrations_gateway22_leader_leaseSql, map[string]*bintree{}},
+		"23_sep24_transaction.sql": &bintree{migrations_gateway23_sep24_transactionSql, map[string]*bintree{}},
+		"24_held_payment_seed.sql": &bintree{migrations_gateway24_held_payment_seedSql, map[string]*bintree{}},
+		"25_sent_transaction_conflict.sql": &bintree{migrations_gateway25_sent_transaction_conflictSql, map[string]*bintree{}},
+		"26_sent_transaction_original_id.sql": &bintree{migrations_gateway26_sent_transaction_original_idSql, map[string]*bintree{}},
+		"27_api_key_tenant.sql": &bintree{migrations_gateway27_api_key_tenantSql, map[string]*bintree{}},
+	}},
+}}
+
+// RestoreAsset restores an asset under the given directory
+func RestoreAsset(dir, name string) error {
+	data, err := Asset(name)
+	if err != nil {
+		return err
+	}
+	info, err := AssetInfo(name)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(_filePath(dir, filepath.Dir(name)), os.FileMode(0755))
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(_filePath(dir, name), data, info.Mode())
+	if err != nil {
+		return err
+	}
+	err = os.Chtimes(_filePath(dir, name), info.ModTime(), info.ModTime())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreAssets restores an asset under the given directory recursively
+func RestoreAssets(dir, name string) error {
+	children, err := AssetDir(name)
+	// File
+	if err != nil {
+		return RestoreAsset(dir, name)
+	}
+	// Dir
+	for _, child := range children {
+		err = RestoreAssets(dir, filepath.Join(name, child))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func _filePath(dir, name string) string {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	return filepath.Join(append([]string{dir}, strings.Split(cannonicalName, "/")...)...)
+}
@@ -0,0 +1,474 @@
+package sqlite
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	// To load sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+)
+
+//go:generate go-bindata -ignore .+\.go$ -pkg sqlite -o bindata.go ./migrations_gateway ./migrations_compliance
+
+// MemoryURL is a url of an in-memory database. Data is lost when the
+// process exits.
+const MemoryURL = ":memory:"
+
+// Driver implements Driver interface using SQLite database. url passed to Init
+// is a path to a database file or MemoryURL.
+type Driver struct {
+	database *sqlx.DB
+}
+
+// Init initializes DB connection
+func (d *Driver) Init(url string) (err error) {
+	d.database, err = sqlx.Connect("sqlite3", url)
+	if err != nil {
+		return
+	}
+
+	// SQLite allows a single writer at a time and every connection to
+	// :memory: opens a new, empty database so all queries share one
+	// connection.
+	d.database.SetMaxOpenConns(1)
+	return
+}
+
+func (d *Driver) DB() *sqlx.DB {
+	return d.database
+}
+
+// MigrateUp migrates DB using migrate files
+func (d *Driver) MigrateUp(component string) (migrationsApplied int, err error) {
+	source := d.getAssetMigrationSource(component)
+	migrationsApplied, err = migrate.Exec(d.database.DB, "sqlite3", source, migrate.Up)
+	return
+}
+
+// MigrateDown rolls back max migrations (all when 0)
+func (d *Driver) MigrateDown(component string, max int) (migrationsApplied int, err error) {
+	source := d.getAssetMigrationSource(component)
+	migrationsApplied, err = migrate.ExecMax(d.database.DB, "sqlite3", source, migrate.Down, max)
+	return
+}
+
+// MigrationStatus returns applied and pending migrations
+func (d *Driver) MigrationStatus(component string) (db.MigrationStatus, error) {
+	return db.GetMigrationStatus(d.database.DB, "sqlite3", d.getAssetMigrationSource(component))
+}
+
+// Insert inserts the entity to a DB
+func (d *Driver) Insert(object entities.Entity) (id int64, err error) {
+	value, tableName, err := getTypeData(object)
+
+	if err != nil {
+		return 0, err
+	}
+
+	fieldsCount := value.NumField()
+	var fieldNames []string
+	var fieldValues []string
+
+	for i := 0; i < fieldsCount; i++ {
+		field := value.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "" {
+			continue
+		}
+		fieldNames = append(fieldNames, tag)
+		fieldValues = append(fieldValues, ":"+tag)
+	}
+
+	query := "INSERT INTO " + tableName + " (" + strings.Join(fieldNames, ", ") + ") VALUES (" + strings.Join(fieldValues, ", ") + ");"
+
+	var result sql.Result
+	switch object := object.(type) {
+	case *entities.AuthorizedTransaction:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.AllowedFi:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.AllowedUser:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SentTransaction:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ReceivedPayment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.HeldPayment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ComplianceRepair:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SEP24Transaction:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.MemoReference:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.Webhook:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.FiatValue:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.Quote:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.OfflineTransaction:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.FailedCallback:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.Refund:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.PaymentMatch:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ExpectedPayment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.Attachment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ComplianceAuditEntry:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SanctionsScreening:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.PaymentVelocity:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.NamedDestination:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.PaymentLimitUsage:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.ReceivedPaymentEvent:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.FederationSnapshot:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.DailyAggregate:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.APIKey:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SecurityEvent:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.SentAttachment:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.FeatureFlag:
+		result, err = d.database.NamedExec(query, object)
+	case *entities.MemoRequiredDestination:
+		result, err = d.database.NamedExec(query, object)
+	}
+
+	if err != nil {
+		return
+	}
+
+	id, err = result.LastInsertId()
+
+	if id == 0 {
+		// Not autoincrement
+		if object.GetID() == nil {
+			return 0, fmt.Errorf("Not autoincrement but ID nil")
+		}
+		id = *object.GetID()
+	}
+
+	if err == nil {
+		object.SetID(id)
+		object.SetExists()
+	}
+
+	return
+}
+
+// Update updates the entity to a DB
+func (d *Driver) Update(object entities.Entity) (err error) {
+	value, tableName, err := getTypeData(object)
+
+	if err != nil {
+		return err
+	}
+
+	fieldsCount := value.NumField()
+
+	query := "UPDATE " + tableName + " SET "
+	var fields []string
+
+	for i := 0; i < fieldsCount; i++ {
+		field := value.Field(i)
+		if field.Tag.Get("db") == "id" || field.Tag.Get("db") == "" {
+			continue
+		}
+		fields = append(fields, field.Tag.Get("db")+" = :"+field.Tag.Get("db"))
+	}
+
+	query += strings.Join(fields, ", ") + " WHERE id = :id;"
+
+	switch object := object.(type) {
+	case *entities.AuthorizedTransaction:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.AllowedFi:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.AllowedUser:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SentTransaction:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ReceivedPayment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.HeldPayment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ComplianceRepair:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SEP24Transaction:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.MemoReference:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.Webhook:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.FiatValue:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.Quote:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.OfflineTransaction:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.FailedCallback:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.Refund:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.PaymentMatch:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ExpectedPayment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.Attachment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ComplianceAuditEntry:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SanctionsScreening:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.PaymentVelocity:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.NamedDestination:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.PaymentLimitUsage:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.ReceivedPaymentEvent:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.FederationSnapshot:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.DailyAggregate:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.APIKey:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SecurityEvent:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.SentAttachment:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.FeatureFlag:
+		_, err = d.database.NamedExec(query, object)
+	case *entities.MemoRequiredDestination:
+		_, err = d.database.NamedExec(query, object)
+	}
+
+	return
+}
+
+// Delete delets the entity from a DB
+func (d *Driver) Delete(object entities.Entity) (err error) {
+	_, tableName, err := getTypeData(object)
+
+	if err != nil {
+		return
+	}
+
+	query := "DELETE FROM " + tableName + " WHERE id = :id;"
+	_, err = d.database.NamedExec(query, object)
+
+	return
+}
+
+// GetOne returns a single entity based on a seach conditions
+func (d *Driver) GetOne(object entities.Entity, where string, params ...interface{}) (entities.Entity, error) {
+	_, tableName, err := getTypeData(object)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.database.Get(object, "SELECT * FROM "+tableName+" WHERE "+where+" LIMIT 1;", params...)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	object.SetExists() // Mark this entity as existing
+	return object, err
+}
+
+// GetMany returns many entities
+func (d *Driver) GetMany(slice interface{}, where, order, offset, limit *string, params ...interface{}) (err error) {
+	_, tableName, err := getTypeData(slice)
+	if err != nil {
+		return
+	}
+
+	var query bytes.Buffer
+
+	query.WriteString("SELECT * FROM " + tableName)
+
+	if where != nil {
+		query.WriteString(" WHERE " + *where)
+	}
+
+	if order != nil {
+		query.WriteString(" ORDER BY " + *order)
+	}
+
+	if limit != nil && offset != nil {
+		query.WriteString(fmt.Sprintf(" LIMIT %s OFFSET %s", *limit, *offset))
+	} else if limit != nil {
+		query.WriteString(" LIMIT " + *limit)
+	}
+
+	query.WriteString(";")
+
+	switch slice := slice.(type) {
+	case *[]*entities.ReceivedPayment:
+		err = d.database.Select(slice, query.String(), params...)
+		tmp := *slice
+		for i := range tmp {
+			tmp[i].SetExists()
+		}
+		slice = &tmp
+	case *[]*entities.SentTransaction:
+		err = d.database.Select(slice, query.String(), params...)
+		tmp := *slice
+		for i := range tmp {
+			tmp[i].SetExists()
+		}
+		slice = &tmp
+	case *[]*entities.HeldPayment:
+		err = d.database.Select(slice, query.String(), params...)
+		tmp := *slice
+		for i := range tmp {
+			tmp[i].SetExists()
+		}
+		slice = &tmp
+	}
+
+	if err != nil && err.Error() == "sql: no rows in result set" {
+		return nil
+	}
+
+	return
+}
+
+func getTypeData(object interface{}) (typeValue reflect.Type, tableName string, err error) {
+	switch object := object.(type) {
+	case *entities.AuthorizedTransaction:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "AuthorizedTransaction"
+	case *entities.AllowedFi:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "AllowedFI"
+	case *entities.AllowedUser:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "AllowedUser"
+	case *entities.SentTransaction:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SentTransaction"
+	case *entities.ReceivedPayment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ReceivedPayment"
+	case *[]*entities.SentTransaction:
+		tableName = "SentTransaction"
+	case *[]*entities.ReceivedPayment:
+		tableName = "ReceivedPayment"
+	case *entities.HeldPayment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "HeldPayment"
+	case *[]*entities.HeldPayment:
+		tableName = "HeldPayment"
+	case *entities.ComplianceRepair:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ComplianceRepair"
+	case *entities.SEP24Transaction:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SEP24Transaction"
+	case *entities.MemoReference:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "MemoReference"
+	case *entities.Webhook:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Webhook"
+	case *entities.FiatValue:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FiatValue"
+	case *entities.Quote:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Quote"
+	case *entities.OfflineTransaction:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "OfflineTransaction"
+	case *entities.FailedCallback:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FailedCallback"
+	case *entities.Refund:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Refund"
+	case *entities.PaymentMatch:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentMatch"
+	case *entities.ExpectedPayment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ExpectedPayment"
+	case *entities.Attachment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "Attachment"
+	case *entities.ComplianceAuditEntry:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ComplianceAudit"
+	case *entities.SanctionsScreening:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SanctionsScreening"
+	case *entities.PaymentVelocity:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentVelocity"
+	case *entities.NamedDestination:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "NamedDestination"
+	case *entities.PaymentLimitUsage:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "PaymentLimitUsage"
+	case *entities.ReceivedPaymentEvent:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "ReceivedPaymentEvent"
+	case *entities.FederationSnapshot:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FederationSnapshot"
+	case *entities.DailyAggregate:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "DailyAggregate"
+	case *entities.APIKey:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "APIKey"
+	case *entities.SecurityEvent:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SecurityEvent"
+	case *entities.SentAttachment:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "SentAttachment"
+	case *entities.FeatureFlag:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "FeatureFlag"
+	case *entities.MemoRequiredDestination:
+		typeValue = reflect.TypeOf(*object)
+		tableName = "MemoRequiredDestination"
+	default:
+		return typeValue, tableName, fmt.Errorf("Unknown entity type: %T", object)
+	}
+	return
+}
+
+func (d *Driver) getAssetMigrationSource(component string) (source *migrate.AssetMigrationSource) {
+	source = &migrate.AssetMigrationSource{
+		Asset:    Asset,
+		AssetDir: AssetDir,
+		Dir:      "migrations_" + component,
+	}
+	return
+}
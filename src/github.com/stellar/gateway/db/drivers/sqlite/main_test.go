@@ -0,0 +1,290 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryDriver(t *testing.T, component string) *Driver {
+	driver := &Driver{}
+	require.NoError(t, driver.Init(MemoryURL))
+
+	applied, err := driver.MigrateUp(component)
+	require.NoError(t, err)
+	assert.NotZero(t, applied)
+	return driver
+}
+
+func TestGatewayEntities(t *testing.T) {
+	driver := newMemoryDriver(t, "gateway")
+	entityManager := db.NewEntityManager(driver)
+	repository := db.NewRepository(driver)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	paymentID := "payment-1"
+	transaction := &entities.SentTransaction{
+		PaymentID:     &paymentID,
+		TransactionID: "f3b2a1",
+		Status:        entities.SentTransactionStatusSending,
+		Source:        "GAB",
+		SubmittedAt:   now,
+		EnvelopeXdr:   "AAAA",
+		Metadata:      entities.Metadata{"order_id": "12_%"},
+	}
+	require.NoError(t, entityManager.Persist(transaction))
+	require.NotNil(t, transaction.ID)
+
+	ledger := uint64(123)
+	transaction.Status = entities.SentTransactionStatusSuccess
+	transaction.SucceededAt = &now
+	transaction.Ledger = &ledger
+	require.NoError(t, entityManager.Persist(transaction))
+
+	found, err := repository.GetSentTransactionByPaymentID(ctx, paymentID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, *transaction.ID, *found.ID)
+	assert.Equal(t, entities.SentTransactionStatusSuccess, found.Status)
+	assert.Equal(t, ledger, *found.Ledger)
+	assert.True(t, now.Equal(*found.SucceededAt))
+
+	for i, operationID := range []string{"1", "2", "3"} {
+		payment := &entities.ReceivedPayment{
+			OperationID: operationID,
+			ProcessedAt: now.Add(time.Duration(i) * time.Second),
+			PagingToken: operationID,
+			Status:      "Success",
+		}
+		require.NoError(t, entityManager.Persist(payment))
+	}
+
+	payment, err := repository.GetReceivedPaymentByOperationID(ctx, "2")
+	require.NoError(t, err)
+	require.NotNil(t, payment)
+	assert.Equal(t, "2", payment.PagingToken)
+
+	payments, err := repository.GetReceivedPayments(ctx, db.ReceivedPaymentsFilter{}, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	assert.Equal(t, "1", payments[0].OperationID)
+
+	from := now.Add(time.Second)
+	payments, err = repository.GetReceivedPayments(ctx, db.ReceivedPaymentsFilter{From: &from, Status: "Success"}, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, payments, 2)
+	assert.Equal(t, "3", payments[0].OperationID)
+	assert.Equal(t, "2", payments[1].OperationID)
+
+	payments, err = repository.GetReceivedPayments(ctx, db.ReceivedPaymentsFilter{To: &from}, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	assert.Equal(t, "1", payments[0].OperationID)
+
+	transactions, err := repository.GetSentTransactions(ctx, db.SentTransactionsFilter{Status: "success", Source: "GAB"}, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+
+	transactions, err = repository.GetSentTransactions(ctx, db.SentTransactionsFilter{Source: "GCD"}, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	transactions, err = repository.GetSentTransactions(ctx, db.SentTransactionsFilter{Metadata: map[string]string{"order_id": "12_%"}}, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, entities.Metadata{"order_id": "12_%"}, transactions[0].Metadata)
+
+	// Wildcards are matched literally
+	transactions, err = repository.GetSentTransactions(ctx, db.SentTransactionsFilter{Metadata: map[string]string{"order_id": "1__%"}}, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	cursor, err := repository.GetLastCursorValue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "3", *cursor)
+
+	cursor, err = repository.GetLastContractEventCursor(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	// Contract events streamed from soroban-rpc have their own cursor
+	contractTransfer := &entities.ReceivedPayment{
+		OperationID: "429496733697",
+		ProcessedAt: now,
+		PagingToken: "0000000429496733696-0000000001",
+		Status:      "Success",
+	}
+	require.NoError(t, entityManager.Persist(contractTransfer))
+
+	cursor, err = repository.GetLastCursorValue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "3", *cursor)
+
+	cursor, err = repository.GetLastContractEventCursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "0000000429496733696-0000000001", *cursor)
+
+	flag := &entities.FeatureFlag{Name: "settlement", Tenant: "", Enabled: true, UpdatedBy: "admin", UpdatedAt: now}
+	require.NoError(t, entityManager.Persist(flag))
+
+	foundFlag, err := repository.GetFeatureFlag(ctx, "settlement", "")
+	require.NoError(t, err)
+	require.NotNil(t, foundFlag)
+	assert.True(t, foundFlag.Enabled)
+
+	require.NoError(t, entityManager.Delete(flag))
+	foundFlag, err = repository.GetFeatureFlag(ctx, "settlement", "")
+	require.NoError(t, err)
+	assert.Nil(t, foundFlag)
+
+	key := &entities.APIKey{KeyID: "wallet", Secret: "0123456789abcdef", RequireSignature: true, RateLimit: 60, Sources: "GABC,GDEF", CreatedAt: now}
+	require.NoError(t, entityManager.Persist(key))
+
+	foundKey, err := repository.GetAPIKey(ctx, "wallet")
+	require.NoError(t, err)
+	require.NotNil(t, foundKey)
+	assert.True(t, foundKey.RequireSignature)
+	assert.Equal(t, 60, foundKey.RateLimit)
+	assert.Equal(t, "GABC,GDEF", foundKey.Sources)
+
+	foundKey, err = repository.GetAPIKey(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, foundKey)
+
+	for _, amount := range []int64{10, 25} {
+		require.NoError(t, repository.AddToDailyAggregate(ctx, &entities.DailyAggregate{Day: "2026-10-15", Direction: entities.DailyAggregateSent, AssetCode: "XLM", Count: 1, Amount: amount}))
+	}
+	require.NoError(t, repository.AddToDailyAggregate(ctx, &entities.DailyAggregate{Day: "2026-10-16", Tenant: "acme", Direction: entities.DailyAggregateSent, AssetCode: "XLM", Count: 1, Amount: 5}))
+
+	aggregates, err := repository.GetDailyAggregates(ctx, db.DailyAggregatesFilter{From: "2026-10-01", To: "2026-10-31"})
+	require.NoError(t, err)
+	require.Len(t, aggregates, 2)
+	assert.Equal(t, int64(2), aggregates[0].Count)
+	assert.Equal(t, int64(35), aggregates[0].Amount)
+	assert.Equal(t, "acme", aggregates[1].Tenant)
+
+	aggregates, err = repository.GetDailyAggregates(ctx, db.DailyAggregatesFilter{From: "2026-10-16", To: "2026-10-16", Tenant: "acme"})
+	require.NoError(t, err)
+	require.Len(t, aggregates, 1)
+	assert.Equal(t, int64(5), aggregates[0].Amount)
+
+	for _, kind := range []string{"stellar_toml", "federation"} {
+		require.NoError(t, entityManager.Persist(&entities.FederationSnapshot{
+			TransactionID: "f3b2a1",
+			PaymentID:     &paymentID,
+			Kind:          kind,
+			URL:           "https://example.com/" + kind,
+			FinalURL:      "https://example.com/" + kind,
+			StatusCode:    200,
+			Body:          "body",
+			TLSVersion:    "TLS 1.3",
+			Certificates:  "[]",
+			FetchedAt:     now,
+			RecordedAt:    now,
+		}))
+	}
+
+	snapshots, err := repository.GetFederationSnapshots(ctx, db.FederationSnapshotsFilter{PaymentID: paymentID})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "stellar_toml", snapshots[0].Kind)
+	assert.Equal(t, "TLS 1.3", snapshots[0].TLSVersion)
+	assert.True(t, now.Equal(snapshots[1].FetchedAt))
+
+	snapshots, err = repository.GetFederationSnapshots(ctx, db.FederationSnapshotsFilter{TransactionID: "unknown"})
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 0)
+}
+
+func TestHeldPaymentsToRelease(t *testing.T) {
+	driver := newMemoryDriver(t, "gateway")
+	entityManager := db.NewEntityManager(driver)
+	repository := db.NewRepository(driver)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for _, id := range []string{"due", "later"} {
+		settleAt := now.Add(-time.Minute)
+		if id == "later" {
+			settleAt = now.Add(time.Hour)
+		}
+		require.NoError(t, entityManager.Persist(&entities.HeldPayment{
+			PaymentID:     id,
+			SourceAccount: "GAB",
+			Request:       "{}",
+			Status:        entities.HeldPaymentStatusHeld,
+			CreatedAt:     now,
+			SettleAt:      settleAt,
+		}))
+	}
+
+	payments, err := repository.GetHeldPaymentsToRelease(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	assert.Equal(t, "due", payments[0].PaymentID)
+
+	updated, err := repository.UpdateHeldPaymentStatus(ctx, payments[0], entities.HeldPaymentStatusCancelled, now)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = repository.UpdateHeldPaymentStatus(ctx, payments[0], entities.HeldPaymentStatusCancelled, now)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	held, err := repository.GetHeldPayments(ctx)
+	require.NoError(t, err)
+	require.Len(t, held, 1)
+	assert.Equal(t, "later", held[0].PaymentID)
+}
+
+func TestRepositoryContext(t *testing.T) {
+	driver := newMemoryDriver(t, "gateway")
+	repository := db.NewRepository(driver)
+
+	// Prepared statements are reused by next calls
+	for i := 0; i < 2; i++ {
+		cursor, err := repository.GetLastCursorValue(context.Background())
+		require.NoError(t, err)
+		assert.Nil(t, cursor)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := repository.GetLastCursorValue(ctx)
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+}
+
+func TestComplianceEntities(t *testing.T) {
+	driver := newMemoryDriver(t, "compliance")
+	entityManager := db.NewEntityManager(driver)
+	repository := db.NewRepository(driver)
+	ctx := context.Background()
+
+	fi := &entities.AllowedFi{
+		Name:      "Example",
+		Domain:    "example.com",
+		PublicKey: "GAB",
+		AllowedAt: time.Now(),
+	}
+	require.NoError(t, entityManager.Persist(fi))
+
+	found, err := repository.GetAllowedFiByDomain(ctx, "example.com")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "Example", found.Name)
+
+	// Unique index on domain
+	assert.Error(t, entityManager.Persist(&entities.AllowedFi{
+		Name:      "Duplicate",
+		Domain:    "example.com",
+		PublicKey: "GCD",
+		AllowedAt: time.Now(),
+	}))
+}
@@ -0,0 +1,50 @@
+-- +migrate Up
+CREATE TABLE AuthorizedTransaction (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  transaction_id varchar(64) NOT NULL,
+  memo varchar(64) NOT NULL,
+  transaction_xdr text NOT NULL,
+  authorized_at datetime NOT NULL,
+  data text NOT NULL
+);
+
+CREATE TABLE AllowedFI (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  name varchar(255) NOT NULL,
+  domain varchar(255) NOT NULL,
+  public_key char(56) NOT NULL,
+  allowed_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX afi_by_domain ON AllowedFI (domain);
+CREATE UNIQUE INDEX afi_by_public_key ON AllowedFI (public_key);
+
+CREATE TABLE AllowedUser (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  fi_name varchar(255) NOT NULL,
+  fi_domain varchar(255) NOT NULL,
+  fi_public_key char(56) NOT NULL,
+  user_id varchar(255) NOT NULL,
+  allowed_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX au_by_fi_public_key_user_id ON AllowedUser (fi_public_key, user_id);
+
+CREATE TABLE SentAttachment (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  transaction_id varchar(64) NOT NULL,
+  memo varchar(64) NOT NULL,
+  sender varchar(255) NOT NULL,
+  sender_id varchar(255) NOT NULL,
+  receiver_info text NOT NULL,
+  attachment text NOT NULL,
+  sent_at datetime NOT NULL
+);
+
+CREATE INDEX sent_attachment_transaction_id ON SentAttachment (transaction_id);
+
+-- +migrate Down
+DROP TABLE AuthorizedTransaction;
+DROP TABLE AllowedFI;
+DROP TABLE AllowedUser;
+DROP TABLE SentAttachment;
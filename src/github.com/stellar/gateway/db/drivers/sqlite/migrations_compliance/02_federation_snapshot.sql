@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE FederationSnapshot (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  transaction_id char(64) NOT NULL,
+  payment_id varchar(255) DEFAULT NULL,
+  kind varchar(16) NOT NULL,
+  url text NOT NULL,
+  final_url text NOT NULL,
+  status_code integer NOT NULL,
+  body text NOT NULL,
+  truncated boolean NOT NULL DEFAULT false,
+  tls_version varchar(16) NOT NULL DEFAULT '',
+  cipher_suite varchar(64) NOT NULL DEFAULT '',
+  certificates text NOT NULL,
+  fetched_at timestamp NOT NULL,
+  recorded_at timestamp NOT NULL
+);
+
+CREATE INDEX federation_snapshot_transaction_id ON FederationSnapshot (transaction_id);
+CREATE INDEX federation_snapshot_payment_id ON FederationSnapshot (payment_id);
+
+-- +migrate Down
+DROP TABLE FederationSnapshot;
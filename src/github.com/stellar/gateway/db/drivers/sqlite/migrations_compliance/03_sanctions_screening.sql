@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE SanctionsScreening (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  transaction_id varchar(64) NOT NULL DEFAULT '',
+  role varchar(16) NOT NULL,
+  address varchar(255) NOT NULL,
+  account_id varchar(56) NOT NULL DEFAULT '',
+  name varchar(255) NOT NULL DEFAULT '',
+  provider varchar(255) NOT NULL,
+  status varchar(16) NOT NULL,
+  matches text NOT NULL,
+  details text NOT NULL,
+  cached boolean NOT NULL DEFAULT false,
+  screened_at timestamp NOT NULL
+);
+
+CREATE INDEX sanctions_screening_transaction_id ON SanctionsScreening (transaction_id);
+CREATE INDEX sanctions_screening_address ON SanctionsScreening (address);
+
+-- +migrate Down
+DROP TABLE SanctionsScreening;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE ComplianceAudit (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  sequence bigint NOT NULL,
+  type varchar(32) NOT NULL,
+  transaction_id varchar(64) NOT NULL DEFAULT '',
+  counterparty varchar(255) NOT NULL DEFAULT '',
+  decision varchar(16) NOT NULL,
+  data text NOT NULL,
+  prev_hash char(64) NOT NULL,
+  hash char(64) NOT NULL,
+  created_at timestamp NOT NULL
+);
+
+CREATE UNIQUE INDEX compliance_audit_sequence ON ComplianceAudit (sequence);
+CREATE INDEX compliance_audit_transaction_id ON ComplianceAudit (transaction_id);
+CREATE INDEX compliance_audit_created_at ON ComplianceAudit (created_at);
+
+-- +migrate Down
+DROP TABLE ComplianceAudit;
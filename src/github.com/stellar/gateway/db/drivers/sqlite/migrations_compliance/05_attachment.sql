@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE Attachment (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  memo_hash char(64) NOT NULL,
+  direction varchar(8) NOT NULL,
+  transaction_id varchar(64) NOT NULL,
+  counterparty varchar(255) NOT NULL DEFAULT '',
+  attachment text NOT NULL,
+  created_at timestamp NOT NULL
+);
+
+CREATE UNIQUE INDEX attachment_memo_hash ON Attachment (memo_hash);
+
+-- +migrate Down
+DROP TABLE Attachment;
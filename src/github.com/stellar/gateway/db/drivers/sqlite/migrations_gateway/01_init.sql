@@ -0,0 +1,84 @@
+-- +migrate Up
+CREATE TABLE ReceivedPayment (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  operation_id varchar(255) NOT NULL UNIQUE,
+  processed_at datetime NOT NULL,
+  paging_token varchar(255) NOT NULL,
+  status varchar(255) NOT NULL,
+  transaction_id varchar(64) DEFAULT 'N/A',
+  memo_id varchar(64) DEFAULT 'N/A',
+  transaction_value varchar(64) DEFAULT 'N/A'
+);
+
+CREATE TABLE SentTransaction (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  payment_id varchar(255) NULL DEFAULT NULL,
+  region varchar(64) NOT NULL DEFAULT '',
+  transaction_id varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  source varchar(56) NOT NULL,
+  submitted_at datetime NOT NULL,
+  succeeded_at datetime DEFAULT NULL,
+  ledger bigint DEFAULT NULL,
+  envelope_xdr text NOT NULL,
+  result_xdr varchar(255) DEFAULT NULL,
+  bad_seq_resolution varchar(16) NULL DEFAULT NULL,
+  UNIQUE (region, payment_id)
+);
+
+CREATE TABLE HeldPayment (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  payment_id varchar(255) NOT NULL UNIQUE,
+  source_account varchar(56) NOT NULL,
+  request text NOT NULL,
+  status varchar(10) NOT NULL,
+  created_at datetime NOT NULL,
+  settle_at datetime NOT NULL,
+  cancelled_at datetime DEFAULT NULL,
+  result text DEFAULT NULL
+);
+
+CREATE INDEX held_payment_status_settle_at ON HeldPayment (status, settle_at);
+
+CREATE TABLE ComplianceRepair (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  operation_id varchar(255) NOT NULL,
+  original_memo varchar(255) NOT NULL,
+  compliance_memo varchar(255) NOT NULL,
+  verified_by varchar(255) NOT NULL,
+  reason text NOT NULL,
+  repaired_at datetime NOT NULL,
+  status varchar(255) NOT NULL
+);
+
+CREATE INDEX compliance_repair_operation_id ON ComplianceRepair (operation_id);
+
+CREATE TABLE MemoRequiredDestination (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  account_id varchar(56) NOT NULL,
+  source varchar(16) NOT NULL,
+  reason text NOT NULL,
+  transaction_id char(64) NULL DEFAULT NULL,
+  created_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX memo_required_destination_account_id ON MemoRequiredDestination (account_id);
+
+CREATE TABLE FeatureFlag (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  name varchar(64) NOT NULL,
+  tenant varchar(64) NOT NULL,
+  enabled boolean NOT NULL,
+  updated_by varchar(255) NOT NULL,
+  updated_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX feature_flag_name_tenant ON FeatureFlag (name, tenant);
+
+-- +migrate Down
+DROP TABLE ReceivedPayment;
+DROP TABLE SentTransaction;
+DROP TABLE HeldPayment;
+DROP TABLE ComplianceRepair;
+DROP TABLE MemoRequiredDestination;
+DROP TABLE FeatureFlag;
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE SecurityEvent (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  event_id varchar(64) NOT NULL,
+  type varchar(32) NOT NULL,
+  format varchar(8) NOT NULL,
+  payload text NOT NULL,
+  created_at datetime NOT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  next_attempt_at datetime NOT NULL,
+  delivered_at datetime DEFAULT NULL,
+  last_error text DEFAULT NULL
+);
+
+CREATE INDEX security_event_delivered_at_next_attempt_at ON SecurityEvent (delivered_at, next_attempt_at);
+
+-- +migrate Down
+DROP TABLE SecurityEvent;
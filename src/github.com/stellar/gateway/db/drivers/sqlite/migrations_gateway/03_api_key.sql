@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE APIKey (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  key_id varchar(64) NOT NULL,
+  secret varchar(255) NOT NULL,
+  require_signature boolean NOT NULL DEFAULT false,
+  rate_limit integer NOT NULL DEFAULT 0,
+  sources text NOT NULL,
+  created_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX api_key_key_id ON APIKey (key_id);
+
+-- +migrate Down
+DROP TABLE APIKey;
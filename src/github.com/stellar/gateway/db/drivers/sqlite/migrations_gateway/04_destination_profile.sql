@@ -0,0 +1,20 @@
+-- +migrate Up
+ALTER TABLE MemoRequiredDestination ADD profile varchar(64) DEFAULT NULL;
+
+-- +migrate Down
+-- SQLite cannot drop columns, the table is copied without profile column
+CREATE TABLE MemoRequiredDestination_old (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  account_id varchar(56) NOT NULL,
+  source varchar(16) NOT NULL,
+  reason text NOT NULL,
+  transaction_id char(64) NULL DEFAULT NULL,
+  created_at datetime NOT NULL
+);
+
+INSERT INTO MemoRequiredDestination_old (id, account_id, source, reason, transaction_id, created_at)
+  SELECT id, account_id, source, reason, transaction_id, created_at FROM MemoRequiredDestination;
+
+DROP TABLE MemoRequiredDestination;
+ALTER TABLE MemoRequiredDestination_old RENAME TO MemoRequiredDestination;
+CREATE UNIQUE INDEX memo_required_destination_account_id ON MemoRequiredDestination (account_id);
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE ReceivedPaymentEvent (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  operation_id varchar(255) NOT NULL,
+  payload text NOT NULL,
+  created_at datetime NOT NULL
+);
+
+CREATE INDEX received_payment_event_created_at ON ReceivedPaymentEvent (created_at);
+
+-- +migrate Down
+DROP TABLE ReceivedPaymentEvent;
@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE PaymentLimitUsage (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  day varchar(10) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  destination varchar(255) NOT NULL DEFAULT '',
+  amount bigint NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX payment_limit_usage_usage ON PaymentLimitUsage (day, asset_code, asset_issuer, destination);
+
+-- +migrate Down
+DROP TABLE PaymentLimitUsage;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE NamedDestination (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  name varchar(64) NOT NULL,
+  destination varchar(255) NOT NULL,
+  memo_type varchar(16) NOT NULL DEFAULT '',
+  memo varchar(255) NOT NULL DEFAULT '',
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  created_at datetime NOT NULL,
+  updated_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX named_destination_name ON NamedDestination (name);
+
+-- +migrate Down
+DROP TABLE NamedDestination;
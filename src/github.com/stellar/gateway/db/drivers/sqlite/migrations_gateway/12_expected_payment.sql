@@ -0,0 +1,41 @@
+-- +migrate Up
+CREATE TABLE ExpectedPayment (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  expected_id varchar(64) NOT NULL,
+  amount bigint NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  memo_type varchar(6) NOT NULL,
+  memo varchar(64) NOT NULL,
+  status varchar(16) NOT NULL,
+  received_amount bigint NOT NULL DEFAULT 0,
+  created_at datetime NOT NULL,
+  updated_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX expected_payment_expected_id ON ExpectedPayment (expected_id);
+CREATE INDEX expected_payment_memo ON ExpectedPayment (memo_type, memo, status);
+
+CREATE TABLE PaymentMatch (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  operation_id varchar(255) NOT NULL,
+  transaction_id varchar(64) NOT NULL,
+  from_account varchar(56) NOT NULL,
+  amount bigint NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  memo_type varchar(6) NOT NULL DEFAULT '',
+  memo varchar(64) NOT NULL DEFAULT '',
+  expected_id varchar(64) NOT NULL DEFAULT '',
+  status varchar(16) NOT NULL,
+  received_amount bigint NOT NULL DEFAULT 0,
+  matched_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX payment_match_operation_id ON PaymentMatch (operation_id);
+CREATE INDEX payment_match_expected_id ON PaymentMatch (expected_id);
+CREATE INDEX payment_match_status ON PaymentMatch (status);
+
+-- +migrate Down
+DROP TABLE PaymentMatch;
+DROP TABLE ExpectedPayment;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE Refund (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  operation_id varchar(255) NOT NULL,
+  transaction_id varchar(64) NOT NULL,
+  payment_id varchar(255) NOT NULL,
+  destination varchar(56) NOT NULL,
+  amount varchar(255) NOT NULL,
+  asset_code varchar(12) NOT NULL DEFAULT '',
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  memo_type varchar(6) NOT NULL DEFAULT '',
+  memo varchar(64) NOT NULL DEFAULT '',
+  reason varchar(255) NOT NULL DEFAULT '',
+  status varchar(16) NOT NULL,
+  refund_transaction_id varchar(64) DEFAULT NULL,
+  created_at datetime NOT NULL,
+  updated_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX refund_operation_id ON Refund (operation_id);
+
+-- +migrate Down
+DROP TABLE Refund;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE OfflineTransaction (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  offline_id varchar(255) NOT NULL,
+  transaction_id varchar(64) NOT NULL,
+  network varchar(255) NOT NULL,
+  source varchar(56) NOT NULL,
+  envelope_xdr text NOT NULL,
+  required_signers text NOT NULL,
+  status varchar(16) NOT NULL,
+  last_error varchar(255) NOT NULL,
+  created_at datetime NOT NULL,
+  updated_at datetime NOT NULL,
+  submitted_at datetime NULL
+);
+
+CREATE UNIQUE INDEX offline_transaction_offline_id ON OfflineTransaction (offline_id);
+
+-- +migrate Down
+DROP TABLE OfflineTransaction;
@@ -0,0 +1,21 @@
+-- +migrate Up
+ALTER TABLE Webhook ADD COLUMN format varchar(16) NOT NULL DEFAULT 'json';
+ALTER TABLE Webhook ADD COLUMN schema_version varchar(16) NOT NULL DEFAULT '1';
+
+-- +migrate Down
+CREATE TABLE Webhook_old (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  webhook_id varchar(32) NOT NULL,
+  url varchar(2048) NOT NULL,
+  events varchar(255) NOT NULL,
+  secret varchar(255) NOT NULL DEFAULT '',
+  max_attempts integer NOT NULL,
+  retry_interval integer NOT NULL,
+  enabled boolean NOT NULL,
+  created_at datetime NOT NULL,
+  updated_at datetime NOT NULL
+);
+INSERT INTO Webhook_old SELECT id, webhook_id, url, events, secret, max_attempts, retry_interval, enabled, created_at, updated_at FROM Webhook;
+DROP TABLE Webhook;
+ALTER TABLE Webhook_old RENAME TO Webhook;
+CREATE UNIQUE INDEX webhook_webhook_id ON Webhook (webhook_id);
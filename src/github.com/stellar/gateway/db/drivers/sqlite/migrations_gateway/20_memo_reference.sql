@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE MemoReference (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  memo char(64) NOT NULL,
+  reference varchar(255) NOT NULL,
+  created_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX memo_reference_memo ON MemoReference (memo);
+
+-- +migrate Down
+DROP TABLE MemoReference;
@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE SentTransaction ADD COLUMN metadata text NULL DEFAULT NULL;
+
+-- +migrate Down
+CREATE TABLE SentTransaction_old (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  payment_id varchar(255) NULL DEFAULT NULL,
+  region varchar(64) NOT NULL DEFAULT '',
+  transaction_id varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  source varchar(56) NOT NULL,
+  submitted_at datetime NOT NULL,
+  succeeded_at datetime DEFAULT NULL,
+  ledger bigint DEFAULT NULL,
+  envelope_xdr text NOT NULL,
+  result_xdr varchar(255) DEFAULT NULL,
+  bad_seq_resolution varchar(16) NULL DEFAULT NULL,
+  UNIQUE (region, payment_id)
+);
+INSERT INTO SentTransaction_old SELECT id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution FROM SentTransaction;
+DROP TABLE SentTransaction;
+ALTER TABLE SentTransaction_old RENAME TO SentTransaction;
@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE LeaderLease (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  name varchar(64) NOT NULL,
+  holder varchar(255) NOT NULL,
+  acquired_at datetime NOT NULL,
+  expires_at datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX leader_lease_name ON LeaderLease (name);
+
+-- +migrate Down
+DROP TABLE LeaderLease;
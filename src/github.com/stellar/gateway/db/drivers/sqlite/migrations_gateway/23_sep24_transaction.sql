@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE SEP24Transaction (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  transaction_id varchar(64) NOT NULL,
+  kind varchar(10) NOT NULL,
+  status varchar(32) NOT NULL,
+  account varchar(56) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  asset_issuer varchar(56) NOT NULL DEFAULT '',
+  amount_in bigint NOT NULL DEFAULT 0,
+  amount_out bigint NOT NULL DEFAULT 0,
+  amount_fee bigint NOT NULL DEFAULT 0,
+  anchor_account varchar(56) NOT NULL DEFAULT '',
+  memo_type varchar(6) NOT NULL DEFAULT '',
+  memo varchar(64) NOT NULL DEFAULT '',
+  more_info_url varchar(255) NOT NULL DEFAULT '',
+  stellar_transaction_id varchar(64) NOT NULL DEFAULT '',
+  external_transaction_id varchar(255) NOT NULL DEFAULT '',
+  message varchar(255) NOT NULL DEFAULT '',
+  started_at datetime NOT NULL,
+  updated_at datetime NOT NULL,
+  completed_at datetime DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX sep24_transaction_transaction_id ON SEP24Transaction (transaction_id);
+CREATE INDEX sep24_transaction_memo ON SEP24Transaction (memo_type, memo, status);
+CREATE INDEX sep24_transaction_account ON SEP24Transaction (account, asset_code);
+CREATE INDEX sep24_transaction_stellar_transaction_id ON SEP24Transaction (stellar_transaction_id);
+CREATE INDEX sep24_transaction_external_transaction_id ON SEP24Transaction (external_transaction_id);
+
+-- +migrate Down
+DROP TABLE SEP24Transaction;
@@ -0,0 +1,27 @@
+-- +migrate Up
+ALTER TABLE HeldPayment ADD encrypted_seed text DEFAULT NULL;
+ALTER TABLE HeldPayment ADD attempts integer NOT NULL DEFAULT 0;
+
+-- +migrate Down
+-- SQLite cannot drop columns, the table is copied without seed columns
+CREATE TABLE HeldPayment_old (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  payment_id varchar(255) NOT NULL UNIQUE,
+  source_account varchar(56) NOT NULL,
+  request text NOT NULL,
+  status varchar(10) NOT NULL,
+  created_at datetime NOT NULL,
+  settle_at datetime NOT NULL,
+  cancelled_at datetime DEFAULT NULL,
+  result text DEFAULT NULL,
+  created_by varchar(64) DEFAULT NULL,
+  approved_by varchar(64) DEFAULT NULL,
+  approved_at datetime DEFAULT NULL
+);
+
+INSERT INTO HeldPayment_old (id, payment_id, source_account, request, status, created_at, settle_at, cancelled_at, result, created_by, approved_by, approved_at)
+  SELECT id, payment_id, source_account, request, status, created_at, settle_at, cancelled_at, result, created_by, approved_by, approved_at FROM HeldPayment;
+
+DROP TABLE HeldPayment;
+ALTER TABLE HeldPayment_old RENAME TO HeldPayment;
+CREATE INDEX held_payment_status_settle_at ON HeldPayment (status, settle_at);
@@ -0,0 +1,76 @@
+-- +migrate Up
+CREATE TABLE SentTransactionConflict (
+  id integer PRIMARY KEY,
+  payment_id varchar(255) NOT NULL,
+  region varchar(64) NOT NULL DEFAULT '',
+  transaction_id varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  source varchar(56) NOT NULL,
+  submitted_at datetime NOT NULL,
+  succeeded_at datetime DEFAULT NULL,
+  ledger bigint DEFAULT NULL,
+  envelope_xdr text NOT NULL,
+  result_xdr varchar(255) DEFAULT NULL,
+  bad_seq_resolution varchar(16) NULL DEFAULT NULL,
+  metadata text NULL DEFAULT NULL
+);
+
+CREATE INDEX sent_transaction_conflict_payment_id ON SentTransactionConflict (payment_id);
+
+-- Payment ID is unique across regions again, transactions of other regions
+-- with the same payment ID are moved to SentTransactionConflict
+INSERT INTO SentTransactionConflict (id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata)
+  SELECT t.id, t.payment_id, t.region, t.transaction_id, t.status, t.source, t.submitted_at, t.succeeded_at, t.ledger, t.envelope_xdr, t.result_xdr, t.bad_seq_resolution, t.metadata
+  FROM SentTransaction t
+  WHERE EXISTS (SELECT 1 FROM SentTransaction o WHERE o.payment_id = t.payment_id AND o.id < t.id);
+
+-- SQLite cannot drop constraints, the table is copied with unique payment ID
+CREATE TABLE SentTransaction_new (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  payment_id varchar(255) NULL DEFAULT NULL UNIQUE,
+  region varchar(64) NOT NULL DEFAULT '',
+  transaction_id varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  source varchar(56) NOT NULL,
+  submitted_at datetime NOT NULL,
+  succeeded_at datetime DEFAULT NULL,
+  ledger bigint DEFAULT NULL,
+  envelope_xdr text NOT NULL,
+  result_xdr varchar(255) DEFAULT NULL,
+  bad_seq_resolution varchar(16) NULL DEFAULT NULL,
+  metadata text NULL DEFAULT NULL
+);
+
+INSERT INTO SentTransaction_new (id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata)
+  SELECT id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata
+  FROM SentTransaction WHERE id NOT IN (SELECT id FROM SentTransactionConflict);
+
+DROP TABLE SentTransaction;
+ALTER TABLE SentTransaction_new RENAME TO SentTransaction;
+
+-- +migrate Down
+CREATE TABLE SentTransaction_old (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  payment_id varchar(255) NULL DEFAULT NULL,
+  region varchar(64) NOT NULL DEFAULT '',
+  transaction_id varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  source varchar(56) NOT NULL,
+  submitted_at datetime NOT NULL,
+  succeeded_at datetime DEFAULT NULL,
+  ledger bigint DEFAULT NULL,
+  envelope_xdr text NOT NULL,
+  result_xdr varchar(255) DEFAULT NULL,
+  bad_seq_resolution varchar(16) NULL DEFAULT NULL,
+  metadata text NULL DEFAULT NULL,
+  UNIQUE (region, payment_id)
+);
+
+INSERT INTO SentTransaction_old (id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata)
+  SELECT id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata FROM SentTransaction
+  UNION ALL
+  SELECT id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata FROM SentTransactionConflict;
+
+DROP TABLE SentTransaction;
+ALTER TABLE SentTransaction_old RENAME TO SentTransaction;
+DROP TABLE SentTransactionConflict;
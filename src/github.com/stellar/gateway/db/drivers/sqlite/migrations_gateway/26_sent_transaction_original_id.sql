@@ -0,0 +1,50 @@
+-- +migrate Up
+ALTER TABLE SentTransaction ADD COLUMN original_id integer NULL DEFAULT NULL;
+ALTER TABLE SentTransactionConflict ADD COLUMN original_id integer NULL DEFAULT NULL;
+
+-- +migrate Down
+-- SQLite cannot drop columns, the tables are copied without original_id
+CREATE TABLE SentTransaction_old (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  payment_id varchar(255) NULL DEFAULT NULL UNIQUE,
+  region varchar(64) NOT NULL DEFAULT '',
+  transaction_id varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  source varchar(56) NOT NULL,
+  submitted_at datetime NOT NULL,
+  succeeded_at datetime DEFAULT NULL,
+  ledger bigint DEFAULT NULL,
+  envelope_xdr text NOT NULL,
+  result_xdr varchar(255) DEFAULT NULL,
+  bad_seq_resolution varchar(16) NULL DEFAULT NULL,
+  metadata text NULL DEFAULT NULL
+);
+
+INSERT INTO SentTransaction_old (id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata)
+  SELECT id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata FROM SentTransaction;
+
+DROP TABLE SentTransaction;
+ALTER TABLE SentTransaction_old RENAME TO SentTransaction;
+
+CREATE TABLE SentTransactionConflict_old (
+  id integer PRIMARY KEY,
+  payment_id varchar(255) NOT NULL,
+  region varchar(64) NOT NULL DEFAULT '',
+  transaction_id varchar(64) NOT NULL,
+  status varchar(10) NOT NULL,
+  source varchar(56) NOT NULL,
+  submitted_at datetime NOT NULL,
+  succeeded_at datetime DEFAULT NULL,
+  ledger bigint DEFAULT NULL,
+  envelope_xdr text NOT NULL,
+  result_xdr varchar(255) DEFAULT NULL,
+  bad_seq_resolution varchar(16) NULL DEFAULT NULL,
+  metadata text NULL DEFAULT NULL
+);
+
+INSERT INTO SentTransactionConflict_old (id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata)
+  SELECT id, payment_id, region, transaction_id, status, source, submitted_at, succeeded_at, ledger, envelope_xdr, result_xdr, bad_seq_resolution, metadata FROM SentTransactionConflict;
+
+DROP TABLE SentTransactionConflict;
+ALTER TABLE SentTransactionConflict_old RENAME TO SentTransactionConflict;
+CREATE INDEX sent_transaction_conflict_payment_id ON SentTransactionConflict (payment_id);
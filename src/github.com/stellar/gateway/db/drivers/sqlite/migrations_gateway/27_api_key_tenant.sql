@@ -0,0 +1,21 @@
+-- +migrate Up
+ALTER TABLE APIKey ADD COLUMN tenant varchar(64) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQLite cannot drop columns, the table is copied without tenant
+CREATE TABLE APIKey_old (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  key_id varchar(64) NOT NULL,
+  secret varchar(255) NOT NULL,
+  require_signature boolean NOT NULL DEFAULT false,
+  rate_limit integer NOT NULL DEFAULT 0,
+  sources text NOT NULL,
+  created_at datetime NOT NULL
+);
+
+INSERT INTO APIKey_old (id, key_id, secret, require_signature, rate_limit, sources, created_at)
+  SELECT id, key_id, secret, require_signature, rate_limit, sources, created_at FROM APIKey;
+
+DROP TABLE APIKey;
+ALTER TABLE APIKey_old RENAME TO APIKey;
+CREATE UNIQUE INDEX api_key_key_id ON APIKey (key_id);
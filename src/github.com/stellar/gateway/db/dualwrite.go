@@ -0,0 +1,280 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+)
+
+var (
+	dualWriteErrors  = metrics.NewCounter("db_dual_write_errors_total", "Number of writes that succeeded in the primary database and failed in the secondary database.")
+	verifyMismatches = metrics.NewCounter("db_read_verify_mismatches_total", "Number of reads whose result in the secondary database differed from the primary database.")
+)
+
+// DualWriteDriver implements Driver writing to two databases, used to migrate
+// to another database (ex. MySQL to Postgres) without a long maintenance
+// window. Primary is the source of truth: all reads are served from it and
+// a write fails only when it fails in Primary. Every successful write is then
+// repeated in Secondary (inserts keep the IDs assigned by Primary). Failed
+// secondary writes are logged and counted, CompareDatabases finds the rows
+// that differ.
+type DualWriteDriver struct {
+	Primary   Driver
+	Secondary Driver
+	// VerifyReads makes every read repeated in Secondary and compared with the
+	// result of Primary. Mismatches are logged and counted.
+	VerifyReads bool
+
+	log *logrus.Entry
+}
+
+// NewDualWriteDriver creates a new DualWriteDriver using initialized drivers
+func NewDualWriteDriver(primary, secondary Driver, verifyReads bool) *DualWriteDriver {
+	return &DualWriteDriver{
+		Primary:     primary,
+		Secondary:   secondary,
+		VerifyReads: verifyReads,
+		log:         logrus.WithFields(logrus.Fields{"service": "DualWriteDriver"}),
+	}
+}
+
+// Init returns an error, drivers are initialized before NewDualWriteDriver
+func (d *DualWriteDriver) Init(string) error {
+	return errors.New("DualWriteDriver uses initialized drivers")
+}
+
+// DB returns the primary database
+func (d *DualWriteDriver) DB() *sqlx.DB {
+	return d.Primary.DB()
+}
+
+// MigrateUp migrates both databases, returns the number of migrations applied
+// to the primary database
+func (d *DualWriteDriver) MigrateUp(component string) (int, error) {
+	applied, err := d.Primary.MigrateUp(component)
+	if err != nil {
+		return applied, err
+	}
+	_, err = d.Secondary.MigrateUp(component)
+	return applied, err
+}
+
+// MigrateDown rolls back max migrations of both databases
+func (d *DualWriteDriver) MigrateDown(component string, max int) (int, error) {
+	rolledBack, err := d.Primary.MigrateDown(component, max)
+	if err != nil {
+		return rolledBack, err
+	}
+	_, err = d.Secondary.MigrateDown(component, max)
+	return rolledBack, err
+}
+
+// MigrationStatus returns the status of the primary database with pending and
+// unknown migrations of the secondary database prefixed with `secondary:`
+func (d *DualWriteDriver) MigrationStatus(component string) (MigrationStatus, error) {
+	status, err := d.Primary.MigrationStatus(component)
+	if err != nil {
+		return status, err
+	}
+
+	secondary, err := d.Secondary.MigrationStatus(component)
+	if err != nil {
+		return status, err
+	}
+	for _, migration := range secondary.Pending {
+		status.Pending = append(status.Pending, "secondary:"+migration)
+	}
+	for _, migration := range secondary.Unknown {
+		status.Unknown = append(status.Unknown, "secondary:"+migration)
+	}
+	return status, nil
+}
+
+// Insert inserts the entity to both databases
+func (d *DualWriteDriver) Insert(object entities.Entity) (int64, error) {
+	id, err := d.Primary.Insert(object)
+	if err != nil {
+		return id, err
+	}
+	_, err = d.Secondary.Insert(object)
+	d.secondaryResult("insert", object, err)
+	return id, nil
+}
+
+// Update updates the entity in both databases
+func (d *DualWriteDriver) Update(object entities.Entity) error {
+	err := d.Primary.Update(object)
+	if err != nil {
+		return err
+	}
+	d.secondaryResult("update", object, d.Secondary.Update(object))
+	return nil
+}
+
+// Delete deletes the entity from both databases
+func (d *DualWriteDriver) Delete(object entities.Entity) error {
+	err := d.Primary.Delete(object)
+	if err != nil {
+		return err
+	}
+	d.secondaryResult("delete", object, d.Secondary.Delete(object))
+	return nil
+}
+
+// GetOne returns a single entity from the primary database
+func (d *DualWriteDriver) GetOne(object entities.Entity, where string, params ...interface{}) (entities.Entity, error) {
+	found, err := d.Primary.GetOne(object, where, params...)
+	if err != nil || !d.VerifyReads {
+		return found, err
+	}
+
+	secondary := reflect.New(reflect.TypeOf(object).Elem()).Interface().(entities.Entity)
+	secondaryFound, secondaryErr := d.Secondary.GetOne(secondary, where, params...)
+	if found == nil {
+		d.verifyResult(where, nil, secondaryFound, secondaryErr)
+	} else {
+		// found is object when a row exists
+		d.verifyResult(where, object, secondaryFound, secondaryErr)
+	}
+	return found, nil
+}
+
+// GetMany returns many entities from the primary database
+func (d *DualWriteDriver) GetMany(slice interface{}, where, order, offset, limit *string, params ...interface{}) error {
+	err := d.Primary.GetMany(slice, where, order, offset, limit, params...)
+	if err != nil || !d.VerifyReads {
+		return err
+	}
+
+	secondary := reflect.New(reflect.TypeOf(slice).Elem()).Interface()
+	secondaryErr := d.Secondary.GetMany(secondary, where, order, offset, limit, params...)
+	query := ""
+	if where != nil {
+		query = *where
+	}
+	d.verifyResult(query, slice, secondary, secondaryErr)
+	return nil
+}
+
+// execSecondary repeats a raw write query of Repository in the secondary
+// database
+func (d *DualWriteDriver) execSecondary(ctx context.Context, query string, args ...interface{}) {
+	database := d.Secondary.DB()
+	_, err := database.ExecContext(ctx, database.Rebind(query), args...)
+	d.secondaryResult("exec", query, err)
+}
+
+// verifySecondary repeats a raw read query of Repository in the secondary
+// database and compares the result with primary. dest is a pointer to
+// a struct or a slice loaded from the primary database, nil when no rows
+// were found.
+func (d *DualWriteDriver) verifySecondary(ctx context.Context, dest interface{}, query string, args ...interface{}) {
+	database := d.Secondary.DB()
+	rows, err := database.QueryContext(ctx, database.Rebind(query), args...)
+	if err != nil {
+		d.verifyResult(query, dest, nil, err)
+		return
+	}
+	defer rows.Close()
+
+	if dest == nil {
+		var secondary interface{}
+		if rows.Next() {
+			secondary = "row"
+		}
+		d.verifyResult(query, nil, secondary, rows.Err())
+		return
+	}
+
+	secondary := reflect.New(reflect.TypeOf(dest).Elem()).Interface()
+	scanner := &sqlx.Rows{Rows: rows, Mapper: database.Mapper}
+	if reflect.TypeOf(dest).Elem().Kind() == reflect.Slice {
+		err = sqlx.StructScan(scanner, secondary)
+	} else if rows.Next() {
+		err = scanner.StructScan(secondary)
+	} else {
+		secondary, err = nil, rows.Err()
+	}
+	d.verifyResult(query, dest, secondary, err)
+}
+
+func (d *DualWriteDriver) secondaryResult(operation string, object interface{}, err error) {
+	if err == nil {
+		return
+	}
+	dualWriteErrors.Inc()
+	d.log.WithFields(logrus.Fields{
+		"err":       err,
+		"operation": operation,
+		"object":    object,
+	}).Error("Error writing to secondary database")
+}
+
+func (d *DualWriteDriver) verifyResult(query string, primary, secondary interface{}, err error) {
+	if err != nil {
+		verifyMismatches.Inc()
+		d.log.WithFields(logrus.Fields{"err": err, "query": query}).Warn("Error reading from secondary database")
+		return
+	}
+
+	if (primary == nil) != (secondary == nil) || (primary != nil && fingerprint(primary) != fingerprint(secondary)) {
+		verifyMismatches.Inc()
+		d.log.WithFields(logrus.Fields{
+			"query":     query,
+			"primary":   primary,
+			"secondary": secondary,
+		}).Warn("Secondary database returned different result")
+	}
+}
+
+// SecondaryConfig contains values of `database.secondary` config group of
+// bridge and compliance servers
+type SecondaryConfig struct {
+	// Type is "mysql", "postgres" or "sqlite", DualWriteDriver is not used
+	// when empty
+	Type        string
+	URL         string `secret:""`
+	VerifyReads bool   `mapstructure:"verify_reads"`
+}
+
+// Validate returns an error when any of the params is invalid. `parseTime`
+// param is added to mysql URL.
+func (c *SecondaryConfig) Validate(primaryType string) error {
+	if c.Type == "" {
+		if c.VerifyReads {
+			return errors.New("database.secondary.verify_reads param requires database.secondary.type")
+		}
+		return nil
+	}
+
+	if primaryType == "" || primaryType == "memory" {
+		return errors.New("database.secondary requires mysql, postgres or sqlite database")
+	}
+
+	if c.URL == "" {
+		return errors.New("database.secondary.url param is required")
+	}
+
+	switch c.Type {
+	case "mysql":
+		secondaryURL, err := url.Parse(c.URL)
+		if err != nil {
+			return errors.New("Cannot parse database.secondary.url param")
+		}
+		query := secondaryURL.Query()
+		query.Set("parseTime", "true")
+		secondaryURL.RawQuery = query.Encode()
+		c.URL = secondaryURL.String()
+	case "postgres", "sqlite":
+		break
+	default:
+		return errors.New("Invalid database.secondary.type param")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"time"
+)
+
+// APIKey authenticates a client of the bridge API, see auth.Authenticator
+type APIKey struct {
+	exists bool
+	ID     *int64 `db:"id" json:"id"`
+	KeyID  string `db:"key_id" json:"key_id"`
+	Secret string `db:"secret" json:"-"`
+	// RequireSignature rejects requests authenticated with the secret
+	// instead of HMAC signature
+	RequireSignature bool `db:"require_signature" json:"require_signature"`
+	// RateLimit is the max number of requests per minute, 0 means no limit
+	RateLimit int `db:"rate_limit" json:"rate_limit"`
+	// Sources is a comma separated list of allowed source accounts, all
+	// accounts are allowed when empty
+	Sources string `db:"sources" json:"sources"`
+	// Tenant of requests authenticated with the key, see features.Tenant
+	Tenant    string    `db:"tenant" json:"tenant"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetID returns ID of the entity
+func (e *APIKey) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *APIKey) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *APIKey) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *APIKey) SetExists() {
+	e.exists = true
+}
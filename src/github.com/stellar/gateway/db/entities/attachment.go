@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"time"
+)
+
+// Directions of attachments
+const (
+	AttachmentDirectionSent     = "sent"
+	AttachmentDirectionReceived = "received"
+)
+
+// Attachment is a compliance attachment (the preimage of the memo hash of a
+// transaction) sent to or received from other organization. Attachment is
+// stored exactly as it was hashed so its SHA-256 hash equals MemoHash.
+type Attachment struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// MemoHash is a hex encoded memo hash of the transaction
+	MemoHash      string `db:"memo_hash" json:"memo_hash"`
+	Direction     string `db:"direction" json:"direction"`
+	TransactionID string `db:"transaction_id" json:"transaction_id"`
+	// Counterparty is the domain of the other organization
+	Counterparty string    `db:"counterparty" json:"counterparty"`
+	Attachment   string    `db:"attachment" json:"attachment"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetID returns ID of the entity
+func (e *Attachment) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *Attachment) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *Attachment) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *Attachment) SetExists() {
+	e.exists = true
+}
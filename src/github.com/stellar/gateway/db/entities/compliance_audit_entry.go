@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+)
+
+// ComplianceAuditEntry represents a compliance decision recorded in the
+// append-only audit log of the compliance server. Hash is computed from the
+// fields of the entry and PrevHash (the hash of the previous entry), so
+// modifying or removing an entry breaks the chain, see auditlog package.
+type ComplianceAuditEntry struct {
+	exists   bool
+	ID       *int64 `db:"id" json:"-"`
+	Sequence int64  `db:"sequence" json:"sequence"`
+	// Type is auth_received, auth_sent, send_rejected or override
+	Type          string `db:"type" json:"type"`
+	TransactionID string `db:"transaction_id" json:"transaction_id"`
+	// Counterparty is the domain of the other organization
+	Counterparty string `db:"counterparty" json:"counterparty"`
+	Decision     string `db:"decision" json:"decision"`
+	// Data is a JSON object with details of the decision
+	Data      string    `db:"data" json:"data"`
+	PrevHash  string    `db:"prev_hash" json:"prev_hash"`
+	Hash      string    `db:"hash" json:"hash"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetID returns ID of the entity
+func (e *ComplianceAuditEntry) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *ComplianceAuditEntry) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *ComplianceAuditEntry) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *ComplianceAuditEntry) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+)
+
+// ComplianceRepair represents manual re-association of a received payment
+// with a compliance attachment stored under a different memo than the memo of
+// the payment transaction
+type ComplianceRepair struct {
+	exists         bool
+	ID             *int64    `db:"id" json:"id"`
+	OperationID    string    `db:"operation_id" json:"operation_id"`
+	OriginalMemo   string    `db:"original_memo" json:"original_memo"`
+	ComplianceMemo string    `db:"compliance_memo" json:"compliance_memo"`
+	VerifiedBy     string    `db:"verified_by" json:"verified_by"`
+	Reason         string    `db:"reason" json:"reason"`
+	RepairedAt     time.Time `db:"repaired_at" json:"repaired_at"`
+	// Status of the payment after re-firing receive callback
+	Status string `db:"status" json:"status"`
+}
+
+// GetID returns ID of the entity
+func (e *ComplianceRepair) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *ComplianceRepair) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *ComplianceRepair) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *ComplianceRepair) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,52 @@
+package entities
+
+// Directions of DailyAggregate
+const (
+	// DailyAggregateReceived aggregates received payments
+	DailyAggregateReceived = "received"
+	// DailyAggregateSent aggregates payments sent in successful transactions
+	DailyAggregateSent = "sent"
+)
+
+// DailyAggregate contains the number and the sum of payments of an asset in a
+// direction made in a day (UTC) by a tenant. Rows are updated incrementally
+// when payments are received and sent, see reports.Aggregator.
+type DailyAggregate struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// Day is a date in 2006-01-02 format
+	Day string `db:"day" json:"day"`
+	// Tenant is empty when the payment was not sent with an API key with a tenant
+	// and for received payments
+	Tenant      string `db:"tenant" json:"tenant"`
+	Direction   string `db:"direction" json:"direction"`
+	AssetCode   string `db:"asset_code" json:"asset_code"`
+	AssetIssuer string `db:"asset_issuer" json:"asset_issuer"`
+	Count       int64  `db:"count" json:"count"`
+	// Amount is the sum of amounts in stroops
+	Amount int64 `db:"amount" json:"amount"`
+}
+
+// GetID returns ID of the entity
+func (e *DailyAggregate) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *DailyAggregate) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *DailyAggregate) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *DailyAggregate) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+// Statuses of expected payments
+const (
+	// ExpectedPaymentStatusPending means no payment has been received yet
+	ExpectedPaymentStatusPending = "pending"
+	// ExpectedPaymentStatusMatched means the expected amount has been received
+	ExpectedPaymentStatusMatched = "matched"
+	// ExpectedPaymentStatusPartiallyPaid means less than the expected amount
+	// has been received, next payments are matched until it's paid
+	ExpectedPaymentStatusPartiallyPaid = "partially_paid"
+	// ExpectedPaymentStatusOverpaid means more than the expected amount has
+	// been received
+	ExpectedPaymentStatusOverpaid = "overpaid"
+	// ExpectedPaymentStatusCancelled means the expected payment was cancelled
+	// and payments are not matched with it
+	ExpectedPaymentStatusCancelled = "cancelled"
+)
+
+// ExpectedPayment is an incoming transfer registered by a caller. Received
+// payments of the same asset and memo are matched with it, see PaymentMatch.
+type ExpectedPayment struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// ExpectedID is the ID given by the caller
+	ExpectedID     string         `db:"expected_id" json:"id"`
+	Amount         amounts.Amount `db:"amount" json:"amount"`
+	AssetCode      string         `db:"asset_code" json:"asset_code"`
+	AssetIssuer    string         `db:"asset_issuer" json:"asset_issuer"`
+	MemoType       string         `db:"memo_type" json:"memo_type"`
+	Memo           string         `db:"memo" json:"memo"`
+	Status         string         `db:"status" json:"status"`
+	ReceivedAmount amounts.Amount `db:"received_amount" json:"received_amount"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// Open returns true when received payments can be matched with e
+func (e *ExpectedPayment) Open() bool {
+	return e.Status == ExpectedPaymentStatusPending || e.Status == ExpectedPaymentStatusPartiallyPaid
+}
+
+// GetID returns ID of the entity
+func (e *ExpectedPayment) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *ExpectedPayment) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *ExpectedPayment) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *ExpectedPayment) SetExists() {
+	e.exists = true
+}
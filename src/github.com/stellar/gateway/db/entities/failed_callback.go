@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"net/url"
+	"time"
+)
+
+// Statuses of failed callbacks
+const (
+	// FailedCallbackStatusFailed means the callback was not delivered and
+	// needs a review
+	FailedCallbackStatusFailed = "failed"
+	// FailedCallbackStatusDelivered means the callback was delivered by a
+	// retry or a reprocessed payment
+	FailedCallbackStatusDelivered = "delivered"
+	// FailedCallbackStatusDiscarded means the callback was discarded after a
+	// review and is not sent again
+	FailedCallbackStatusDiscarded = "discarded"
+)
+
+// FailedCallback is a receive callback that could not be delivered after all
+// receive callback endpoints failed. It keeps the payload, so the callback can
+// be sent again.
+type FailedCallback struct {
+	exists      bool
+	ID          *int64 `db:"id" json:"-"`
+	OperationID string `db:"operation_id" json:"operation_id"`
+	// Payload is the form encoded callback
+	Payload   string    `db:"payload" json:"-"`
+	LastError string    `db:"last_error" json:"last_error"`
+	Attempts  int       `db:"attempts" json:"attempts"`
+	Status    string    `db:"status" json:"status"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Values returns the callback payload
+func (e *FailedCallback) Values() (url.Values, error) {
+	return url.ParseQuery(e.Payload)
+}
+
+// GetID returns ID of the entity
+func (e *FailedCallback) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *FailedCallback) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *FailedCallback) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *FailedCallback) SetExists() {
+	e.exists = true
+}
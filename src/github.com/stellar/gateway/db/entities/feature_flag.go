@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+)
+
+// FeatureFlag overrides a feature flag from the config file. Empty Tenant
+// overrides the flag for all tenants.
+type FeatureFlag struct {
+	exists    bool
+	ID        *int64    `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Tenant    string    `db:"tenant" json:"tenant"`
+	Enabled   bool      `db:"enabled" json:"enabled"`
+	UpdatedBy string    `db:"updated_by" json:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// GetID returns ID of the entity
+func (e *FeatureFlag) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *FeatureFlag) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *FeatureFlag) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *FeatureFlag) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"time"
+)
+
+// FederationSnapshot is a federation or stellar.toml response used to resolve
+// the destination of a sent transaction, recorded with the TLS certificates
+// presented by the server. See snapshots package.
+type FederationSnapshot struct {
+	exists        bool
+	ID            *int64  `db:"id"`
+	TransactionID string  `db:"transaction_id"`
+	PaymentID     *string `db:"payment_id"`
+	// Kind is stellar_toml or federation
+	Kind string `db:"kind"`
+	URL  string `db:"url"`
+	// FinalURL differs from URL when redirects were followed
+	FinalURL   string `db:"final_url"`
+	StatusCode int    `db:"status_code"`
+	Body       string `db:"body"`
+	// Truncated is true when the body exceeded snapshots.MaxBodySize
+	Truncated   bool   `db:"truncated"`
+	TLSVersion  string `db:"tls_version"`
+	CipherSuite string `db:"cipher_suite"`
+	// Certificates is a JSON encoded chain of certificates presented by the
+	// server, leaf first
+	Certificates string    `db:"certificates"`
+	FetchedAt    time.Time `db:"fetched_at"`
+	RecordedAt   time.Time `db:"recorded_at"`
+}
+
+// GetID returns ID of the entity
+func (e *FederationSnapshot) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *FederationSnapshot) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *FederationSnapshot) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *FederationSnapshot) SetExists() {
+	e.exists = true
+}
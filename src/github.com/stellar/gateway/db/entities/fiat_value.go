@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// Directions of FiatValue
+const (
+	// FiatValueReceived is a value of a received payment
+	FiatValueReceived = "received"
+	// FiatValueSent is a value of a payment sent in a successful transaction
+	FiatValueSent = "sent"
+)
+
+// FiatValue is the fiat-equivalent value of a received or sent payment at the
+// exchange rate of the time it was processed, see rates.Recorder.
+type FiatValue struct {
+	exists    bool
+	ID        *int64 `db:"id" json:"-"`
+	Direction string `db:"direction" json:"direction"`
+	// Reference is the operation ID of received payments and the transaction
+	// ID and index of the operation (txid:index) of sent payments
+	Reference string `db:"reference" json:"reference"`
+	// Tenant is empty when the payment was not sent with an API key with a tenant
+	// and for received payments
+	Tenant      string `db:"tenant" json:"tenant"`
+	AssetCode   string `db:"asset_code" json:"asset_code"`
+	AssetIssuer string `db:"asset_issuer" json:"asset_issuer"`
+	Amount      string `db:"amount" json:"amount"`
+	Currency    string `db:"currency" json:"currency"`
+	// Rate is the price of one unit of the asset in Currency
+	Rate       string    `db:"rate" json:"rate"`
+	FiatAmount string    `db:"fiat_amount" json:"fiat_amount"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetID returns ID of the entity
+func (e *FiatValue) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *FiatValue) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *FiatValue) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *FiatValue) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	// HeldPaymentStatusPending is a status indicating that payment is waiting for approval
+	HeldPaymentStatusPending = "pending"
+	// HeldPaymentStatusHeld is a status indicating that payment is waiting for the end of settlement window
+	HeldPaymentStatusHeld = "held"
+	// HeldPaymentStatusCancelled is a status indicating that payment was cancelled during settlement window
+	HeldPaymentStatusCancelled = "cancelled"
+	// HeldPaymentStatusReleasing is a status indicating that payment is being submitted after settlement window
+	HeldPaymentStatusReleasing = "releasing"
+	// HeldPaymentStatusReleased is a status indicating that payment has been submitted after settlement window
+	HeldPaymentStatusReleased = "released"
+	// HeldPaymentStatusFailed is a status indicating that payment could not be submitted after settlement window
+	HeldPaymentStatusFailed = "failed"
+)
+
+// HeldPayment represents payment held by the bridge server during settlement window
+type HeldPayment struct {
+	exists        bool
+	ID            *int64 `db:"id" json:"id"`
+	PaymentID     string `db:"payment_id" json:"payment_id"`
+	SourceAccount string `db:"source_account" json:"source_account"`
+	// URL-encoded payment request without a source secret seed
+	Request     string     `db:"request" json:"-"`
+	Status      string     `db:"status" json:"status"` // pending/held/releasing/cancelled/released/failed
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	SettleAt    time.Time  `db:"settle_at" json:"settle_at"`
+	CancelledAt *time.Time `db:"cancelled_at" json:"cancelled_at"`
+	Result      *string    `db:"result" json:"result"`
+	// CreatedBy and ApprovedBy are IDs of API keys that sent and approved a
+	// payment requiring approval
+	CreatedBy  *string    `db:"created_by" json:"created_by"`
+	ApprovedBy *string    `db:"approved_by" json:"approved_by"`
+	ApprovedAt *time.Time `db:"approved_at" json:"approved_at"`
+	// EncryptedSeed is the source secret seed encrypted to
+	// settlement.handoff_key. Nil for payments sent from accounts.base_seed
+	// and after the payment is released, failed or cancelled.
+	EncryptedSeed *string `db:"encrypted_seed" json:"-"`
+	// Attempts is the number of submissions failed with a server error
+	Attempts int `db:"attempts" json:"attempts"`
+}
+
+// GetID returns ID of the entity
+func (e *HeldPayment) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *HeldPayment) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *HeldPayment) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *HeldPayment) SetExists() {
+	e.exists = true
+}
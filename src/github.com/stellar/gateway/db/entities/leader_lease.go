@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+)
+
+// LeaderLease is a lease held by the leader of bridge server replicas. The
+// lease can be acquired by another replica when it is not renewed before
+// ExpiresAt.
+type LeaderLease struct {
+	exists bool
+	ID     *int64 `db:"id"`
+	Name   string `db:"name"`
+	// Holder is ID of the replica holding the lease
+	Holder     string    `db:"holder"`
+	AcquiredAt time.Time `db:"acquired_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+// GetID returns ID of the entity
+func (e *LeaderLease) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *LeaderLease) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *LeaderLease) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *LeaderLease) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+)
+
+// MemoReference maps a hash memo derived from an internal reference (ex. ID
+// of a customer or an invoice) back to the reference. Memos are HMACs of
+// references, so the references are not visible on the public ledger.
+type MemoReference struct {
+	exists bool
+	ID     *int64 `db:"id"`
+	// Memo is the hex encoded hash memo
+	Memo      string    `db:"memo"`
+	Reference string    `db:"reference"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// GetID returns ID of the entity
+func (e *MemoReference) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *MemoReference) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *MemoReference) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *MemoReference) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	// MemoRequiredSourceLearned is a source of destinations added after a payment without memo was returned
+	MemoRequiredSourceLearned = "learned"
+	// MemoRequiredSourceManual is a source of destinations added using admin API
+	MemoRequiredSourceManual = "manual"
+)
+
+// MemoRequiredDestination represents destination account that requires memo.
+// Destinations are learned from payments without memo returned by them.
+type MemoRequiredDestination struct {
+	exists    bool
+	ID        *int64 `db:"id" json:"id"`
+	AccountID string `db:"account_id" json:"account_id"`
+	Source    string `db:"source" json:"source"` // learned/manual
+	Reason    string `db:"reason" json:"reason"`
+	// TransactionID of the returned transaction when Source is learned
+	TransactionID *string   `db:"transaction_id" json:"transaction_id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	// Profile is a name of the destination profile payments are checked
+	// against, see profiles package
+	Profile *string `db:"profile" json:"profile"`
+}
+
+// GetID returns ID of the entity
+func (e *MemoRequiredDestination) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *MemoRequiredDestination) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *MemoRequiredDestination) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *MemoRequiredDestination) SetExists() {
+	e.exists = true
+}
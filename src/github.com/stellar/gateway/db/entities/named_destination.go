@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+)
+
+// NamedDestination is an entry of the address book mapping a friendly name
+// to a destination and defaults of payments sent to it. Empty memo and asset
+// fields are not applied.
+type NamedDestination struct {
+	exists bool
+	ID     *int64 `db:"id" json:"id"`
+	Name   string `db:"name" json:"name"`
+	// Destination is an account ID or a Stellar address (like bob*stellar.org)
+	Destination string    `db:"destination" json:"destination"`
+	MemoType    string    `db:"memo_type" json:"memo_type"`
+	Memo        string    `db:"memo" json:"memo"`
+	AssetCode   string    `db:"asset_code" json:"asset_code"`
+	AssetIssuer string    `db:"asset_issuer" json:"asset_issuer"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// GetID returns ID of the entity
+func (e *NamedDestination) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *NamedDestination) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *NamedDestination) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *NamedDestination) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// Statuses of offline transactions
+const (
+	// OfflineTransactionStatusPending means the transaction waits for the
+	// signed envelope
+	OfflineTransactionStatusPending = "pending"
+	// OfflineTransactionStatusSubmitted means the signed transaction was
+	// included in a ledger
+	OfflineTransactionStatusSubmitted = "submitted"
+	// OfflineTransactionStatusFailed means the signed transaction failed, it
+	// can be signed and submitted again
+	OfflineTransactionStatusFailed = "failed"
+)
+
+// OfflineTransaction is a transaction built by the bridge server and signed
+// outside of it (ex. on an air-gapped machine). The unsigned envelope is kept
+// until the signed one is submitted.
+type OfflineTransaction struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// OfflineID is the ID of the transaction sent by the client
+	OfflineID string `db:"offline_id" json:"id"`
+	// TransactionID is the hash of the transaction
+	TransactionID string `db:"transaction_id" json:"transaction_id"`
+	// Network is the name of an additional network the transaction is built
+	// for, the main network when empty
+	Network string `db:"network" json:"network,omitempty"`
+	Source  string `db:"source" json:"source"`
+	// EnvelopeXdr is the unsigned transaction envelope
+	EnvelopeXdr string `db:"envelope_xdr" json:"envelope_xdr"`
+	// RequiredSigners is a comma separated list of public keys that must sign
+	// the transaction
+	RequiredSigners string `db:"required_signers" json:"-"`
+	Status          string `db:"status" json:"status"`
+	// LastError is the error code of the last failed submission
+	LastError   string     `db:"last_error" json:"last_error,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	SubmittedAt *time.Time `db:"submitted_at" json:"submitted_at"`
+}
+
+// Signers returns public keys of RequiredSigners
+func (e *OfflineTransaction) Signers() []string {
+	if e.RequiredSigners == "" {
+		return nil
+	}
+	return strings.Split(e.RequiredSigners, ",")
+}
+
+// GetID returns ID of the entity
+func (e *OfflineTransaction) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *OfflineTransaction) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *OfflineTransaction) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *OfflineTransaction) SetExists() {
+	e.exists = true
+}
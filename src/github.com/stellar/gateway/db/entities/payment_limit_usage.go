@@ -0,0 +1,40 @@
+package entities
+
+// PaymentLimitUsage contains the sum of amounts of payments of an asset sent
+// in a day (UTC), checked against daily limits of the asset. Destination is
+// empty for the row of all payments of the asset.
+type PaymentLimitUsage struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// Day is a date in 2006-01-02 format
+	Day         string `db:"day" json:"day"`
+	AssetCode   string `db:"asset_code" json:"asset_code"`
+	AssetIssuer string `db:"asset_issuer" json:"asset_issuer"`
+	Destination string `db:"destination" json:"destination"`
+	// Amount is the sum of amounts in stroops
+	Amount int64 `db:"amount" json:"amount"`
+}
+
+// GetID returns ID of the entity
+func (e *PaymentLimitUsage) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *PaymentLimitUsage) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *PaymentLimitUsage) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *PaymentLimitUsage) SetExists() {
+	e.exists = true
+}
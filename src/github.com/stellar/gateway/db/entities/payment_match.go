@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+// PaymentMatchStatusUnmatched is the status of received payments that did not
+// match any expected payment, they need a review
+const PaymentMatchStatusUnmatched = "unmatched"
+
+// PaymentMatch is the result of matching a received payment with expected
+// payments. Every received payment is matched once, reprocessed payments keep
+// their match.
+type PaymentMatch struct {
+	exists        bool
+	ID            *int64         `db:"id" json:"-"`
+	OperationID   string         `db:"operation_id" json:"operation_id"`
+	TransactionID string         `db:"transaction_id" json:"transaction_id"`
+	From          string         `db:"from_account" json:"from"`
+	Amount        amounts.Amount `db:"amount" json:"amount"`
+	AssetCode     string         `db:"asset_code" json:"asset_code"`
+	AssetIssuer   string         `db:"asset_issuer" json:"asset_issuer"`
+	MemoType      string         `db:"memo_type" json:"memo_type"`
+	Memo          string         `db:"memo" json:"memo"`
+	// ExpectedID is the ID of the matched expected payment, empty when
+	// unmatched
+	ExpectedID string `db:"expected_id" json:"expected_payment_id"`
+	// Status is the status of the expected payment after the payment was
+	// matched or PaymentMatchStatusUnmatched
+	Status string `db:"status" json:"status"`
+	// ReceivedAmount is the amount received by the expected payment after
+	// the payment was matched
+	ReceivedAmount amounts.Amount `db:"received_amount" json:"received_amount"`
+	MatchedAt      time.Time      `db:"matched_at" json:"matched_at"`
+}
+
+// GetID returns ID of the entity
+func (e *PaymentMatch) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *PaymentMatch) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *PaymentMatch) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *PaymentMatch) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+)
+
+// PaymentVelocity is a payment sent by the bridge server, counted by velocity
+// checks of sliding windows. Rows older than the longest window are removed.
+type PaymentVelocity struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// Source is the source account ID
+	Source string `db:"source" json:"source"`
+	// Destination is compared as sent in the request
+	Destination string `db:"destination" json:"destination"`
+	// AssetCode is XLM (without issuer) for native payments
+	AssetCode   string `db:"asset_code" json:"asset_code"`
+	AssetIssuer string `db:"asset_issuer" json:"asset_issuer"`
+	// Amount in stroops
+	Amount    int64     `db:"amount" json:"amount"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetID returns ID of the entity
+func (e *PaymentVelocity) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *PaymentVelocity) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *PaymentVelocity) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *PaymentVelocity) SetExists() {
+	e.exists = true
+}
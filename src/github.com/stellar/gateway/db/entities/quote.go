@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+// Types of quotes
+const (
+	// QuoteTypeStrictReceive is a quote of the amount sent to deliver Amount
+	QuoteTypeStrictReceive = "strict_receive"
+	// QuoteTypeStrictSend is a quote of the amount delivered by sending
+	// SendAmount
+	QuoteTypeStrictSend = "strict_send"
+)
+
+// Quote is a priced path of a cross-asset payment found by Horizon path
+// finding. /payment sent with quote_id uses its path and SendAmount as
+// send_max until ExpiresAt.
+type Quote struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// QuoteID is the ID of the quote generated by the bridge server
+	QuoteID string `db:"quote_id" json:"id"`
+	// Network is the name of an additional network the quote is for, the
+	// main network when empty
+	Network         string `db:"network" json:"network,omitempty"`
+	Type            string `db:"type" json:"type"`
+	SendAssetCode   string `db:"send_asset_code" json:"send_asset_code"`
+	SendAssetIssuer string `db:"send_asset_issuer" json:"send_asset_issuer"`
+	SendAmount      string `db:"send_amount" json:"send_amount"`
+	AssetCode       string `db:"asset_code" json:"asset_code"`
+	AssetIssuer     string `db:"asset_issuer" json:"asset_issuer"`
+	Amount          string `db:"amount" json:"amount"`
+	// Path is a JSON array of assets between the send asset and the asset
+	Path      string    `db:"path" json:"-"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Assets returns assets of Path
+func (e *Quote) Assets() (path []protocols.Asset) {
+	// Path is always encoded by SetAssets
+	json.Unmarshal([]byte(e.Path), &path)
+	return
+}
+
+// SetAssets sets Path to the JSON array of path assets
+func (e *Quote) SetAssets(path []protocols.Asset) {
+	if path == nil {
+		path = []protocols.Asset{}
+	}
+	encoded, _ := json.Marshal(path)
+	e.Path = string(encoded)
+}
+
+// GetID returns ID of the entity
+func (e *Quote) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *Quote) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *Quote) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *Quote) SetExists() {
+	e.exists = true
+}
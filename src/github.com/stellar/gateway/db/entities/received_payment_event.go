@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+)
+
+// ReceivedPaymentEvent is a receive callback recorded for subscribers of the
+// payments stream. ID is the cursor of the event in the stream.
+type ReceivedPaymentEvent struct {
+	exists      bool
+	ID          *int64 `db:"id" json:"id"`
+	OperationID string `db:"operation_id" json:"operation_id"`
+	// Payload is the callback payload encoded as a flat JSON object
+	Payload   string    `db:"payload" json:"payload"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetID returns ID of the entity
+func (e *ReceivedPaymentEvent) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *ReceivedPaymentEvent) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *ReceivedPaymentEvent) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *ReceivedPaymentEvent) SetExists() {
+	e.exists = true
+}
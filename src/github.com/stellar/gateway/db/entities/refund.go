@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"time"
+)
+
+// Statuses of refunds
+const (
+	// RefundStatusSent means the refund payment was submitted successfully
+	RefundStatusSent = "sent"
+	// RefundStatusHeld means the refund payment is held (ex. pending
+	// approval), see HeldPayment
+	RefundStatusHeld = "held"
+	// RefundStatusFailed means the refund payment failed, it can be sent
+	// again
+	RefundStatusFailed = "failed"
+)
+
+// Refund links a received payment with the payment returning it to the sender
+type Refund struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// OperationID and TransactionID identify the refunded payment
+	OperationID   string `db:"operation_id" json:"operation_id"`
+	TransactionID string `db:"transaction_id" json:"transaction_id"`
+	// PaymentID is the ID of the refund payment sent using /payment
+	PaymentID   string `db:"payment_id" json:"payment_id"`
+	Destination string `db:"destination" json:"destination"`
+	Amount      string `db:"amount" json:"amount"`
+	AssetCode   string `db:"asset_code" json:"asset_code"`
+	AssetIssuer string `db:"asset_issuer" json:"asset_issuer"`
+	MemoType    string `db:"memo_type" json:"memo_type"`
+	Memo        string `db:"memo" json:"memo"`
+	Reason      string `db:"reason" json:"reason"`
+	Status      string `db:"status" json:"status"`
+	// RefundTransactionID is the hash of the refund transaction, nil until
+	// it's sent
+	RefundTransactionID *string   `db:"refund_transaction_id" json:"refund_transaction_id"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// GetID returns ID of the entity
+func (e *Refund) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *Refund) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *Refund) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *Refund) SetExists() {
+	e.exists = true
+}
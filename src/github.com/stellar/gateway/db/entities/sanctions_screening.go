@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+)
+
+// SanctionsScreening represents a result of screening a sender of a received
+// payment or a receiver of a sent payment against sanctions lists, see
+// sanctions package. TransactionID is empty when a sent payment was rejected
+// before its transaction was built.
+type SanctionsScreening struct {
+	exists        bool
+	ID            *int64 `db:"id"`
+	TransactionID string `db:"transaction_id"`
+	// Role is "sender" or "receiver"
+	Role      string `db:"role"`
+	Address   string `db:"address"`
+	AccountID string `db:"account_id"`
+	Name      string `db:"name"`
+	Provider  string `db:"provider"`
+	// Status is "clear", "match" or "review"
+	Status string `db:"status"`
+	// Matches is a JSON array of matched list entries
+	Matches    string    `db:"matches"`
+	Details    string    `db:"details"`
+	Cached     bool      `db:"cached"`
+	ScreenedAt time.Time `db:"screened_at"`
+}
+
+// GetID returns ID of the entity
+func (e *SanctionsScreening) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *SanctionsScreening) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *SanctionsScreening) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *SanctionsScreening) SetExists() {
+	e.exists = true
+}
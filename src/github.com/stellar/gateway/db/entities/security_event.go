@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+)
+
+// SecurityEvent represents a security event waiting for delivery (or already
+// delivered) to a SIEM endpoint, see audit.Emitter
+type SecurityEvent struct {
+	exists  bool
+	ID      *int64 `db:"id" json:"id"`
+	EventID string `db:"event_id" json:"event_id"`
+	Type    string `db:"type" json:"type"`
+	// Format of the payload: json or cef
+	Format        string     `db:"format" json:"format"`
+	Payload       string     `db:"payload" json:"payload"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	Attempts      int        `db:"attempts" json:"attempts"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt   *time.Time `db:"delivered_at" json:"delivered_at"`
+	LastError     *string    `db:"last_error" json:"last_error"`
+}
+
+// GetID returns ID of the entity
+func (e *SecurityEvent) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *SecurityEvent) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *SecurityEvent) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *SecurityEvent) SetExists() {
+	e.exists = true
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"time"
+)
+
+// SentAttachment represents compliance attachment of a transaction sent to
+// other FI together with KYC overrides given in the payment request.
+// ReceiverInfo is not a part of the attachment, it's stored for auditing only.
+type SentAttachment struct {
+	exists        bool
+	ID            *int64    `db:"id"`
+	TransactionID string    `db:"transaction_id"`
+	Memo          string    `db:"memo"`
+	Sender        string    `db:"sender"`
+	SenderID      string    `db:"sender_id"`
+	ReceiverInfo  string    `db:"receiver_info"`
+	Attachment    string    `db:"attachment"`
+	SentAt        time.Time `db:"sent_at"`
+}
+
+// GetID returns ID of the entity
+func (e *SentAttachment) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *SentAttachment) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *SentAttachment) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *SentAttachment) SetExists() {
+	e.exists = true
+}
@@ -2,8 +2,11 @@ package entities
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"time"
+
+	"github.com/stellar/gateway/clock"
 )
 
 // SentTransactionStatus type represents sent transaction status
@@ -26,6 +29,38 @@ func (status SentTransactionStatus) Value() (driver.Value, error) {
 
 var _ driver.Valuer = SentTransactionStatus("")
 
+// Metadata are key/values of a payment sent in metadata[...] params of
+// /payment, stored as a JSON object
+type Metadata map[string]string
+
+// Scan implements database/sql.Scanner interface
+func (m *Metadata) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, m)
+	case string:
+		return json.Unmarshal([]byte(value), m)
+	}
+	return errors.New("Cannot convert value to Metadata")
+}
+
+// Value implements driver.Valuer, empty metadata are stored as NULL
+func (m Metadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	value, err := json.Marshal(map[string]string(m))
+	if err != nil {
+		return nil, err
+	}
+	return driver.Value(string(value)), nil
+}
+
+var _ driver.Valuer = Metadata(nil)
+
 const (
 	// SentTransactionStatusSending is a status indicating that transaction is sending
 	SentTransactionStatusSending SentTransactionStatus = "sending"
@@ -35,11 +70,28 @@ const (
 	SentTransactionStatusFailure SentTransactionStatus = "failure"
 )
 
+// Resolutions of tx_bad_seq responses saved in SentTransaction.BadSeqResolution
+const (
+	// BadSeqResolutionLanded means the transaction was already included in a ledger
+	BadSeqResolutionLanded = "landed"
+	// BadSeqResolutionResubmitted means the sequence number was resynced and the transaction was rebuilt and submitted again
+	BadSeqResolutionResubmitted = "resubmitted"
+	// BadSeqResolutionExternal means the account sequence was advanced by someone else so the transaction was not rebuilt
+	BadSeqResolutionExternal = "external"
+	// BadSeqResolutionUnverified means the account state could not be loaded from Horizon
+	BadSeqResolutionUnverified = "unverified"
+)
+
 // SentTransaction represents transaction sent by the gateway server
 type SentTransaction struct {
-	exists        bool
-	ID            *int64                `db:"id" json:"id"`
-	PaymentID     *string               `db:"payment_id" json:"payment_id"`
+	exists    bool
+	ID        *int64  `db:"id" json:"id"`
+	PaymentID *string `db:"payment_id" json:"payment_id"`
+	// OriginalID is ID of the transaction rejected with tx_bad_seq that this
+	// transaction resubmits. Payment ID is moved to the resubmission.
+	OriginalID *int64 `db:"original_id" json:"original_id,omitempty"`
+	// Region is a name of the region that submitted the transaction
+	Region        string                `db:"region" json:"region"`
 	TransactionID string                `db:"transaction_id" json:"transaction_id"`
 	Status        SentTransactionStatus `db:"status" json:"status"` // sending/success/failure
 	Source        string                `db:"source" json:"source"`
@@ -48,6 +100,10 @@ type SentTransaction struct {
 	Ledger        *uint64               `db:"ledger" json:"ledger"`
 	EnvelopeXdr   string                `db:"envelope_xdr" json:"envelope_xdr"`
 	ResultXdr     *string               `db:"result_xdr" json:"result_xdr"`
+	// BadSeqResolution is set when Horizon responded with tx_bad_seq, see BadSeqResolution* constants
+	BadSeqResolution *string `db:"bad_seq_resolution" json:"bad_seq_resolution"`
+	// Metadata of the payment sent in the transaction
+	Metadata Metadata `db:"metadata" json:"metadata,omitempty"`
 }
 
 // GetID returns ID of the entity
@@ -78,7 +134,7 @@ func (e *SentTransaction) SetExists() {
 func (e *SentTransaction) MarkSucceeded(ledger uint64) {
 	e.Status = SentTransactionStatusSuccess
 	e.Ledger = &ledger
-	now := time.Now()
+	now := clock.Now()
 	e.SucceededAt = &now
 }
 
@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+// Kinds of SEP-24 transactions
+const (
+	// SEP24KindDeposit is a deposit: the anchor sends the asset to the user
+	SEP24KindDeposit = "deposit"
+	// SEP24KindWithdrawal is a withdrawal: the user sends the asset to the
+	// anchor
+	SEP24KindWithdrawal = "withdrawal"
+)
+
+// Statuses of SEP-24 transactions, see
+// https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0024.md
+const (
+	SEP24StatusIncomplete                  = "incomplete"
+	SEP24StatusPendingUserTransferStart    = "pending_user_transfer_start"
+	SEP24StatusPendingUserTransferComplete = "pending_user_transfer_complete"
+	SEP24StatusPendingExternal             = "pending_external"
+	SEP24StatusPendingAnchor               = "pending_anchor"
+	SEP24StatusPendingStellar              = "pending_stellar"
+	SEP24StatusPendingTrust                = "pending_trust"
+	SEP24StatusPendingUser                 = "pending_user"
+	SEP24StatusCompleted                   = "completed"
+	SEP24StatusRefunded                    = "refunded"
+	SEP24StatusExpired                     = "expired"
+	SEP24StatusError                       = "error"
+	SEP24StatusNoMarket                    = "no_market"
+	SEP24StatusTooSmall                    = "too_small"
+	SEP24StatusTooLarge                    = "too_large"
+)
+
+// SEP24Statuses are all statuses of SEP-24 transactions
+var SEP24Statuses = []string{
+	SEP24StatusIncomplete,
+	SEP24StatusPendingUserTransferStart,
+	SEP24StatusPendingUserTransferComplete,
+	SEP24StatusPendingExternal,
+	SEP24StatusPendingAnchor,
+	SEP24StatusPendingStellar,
+	SEP24StatusPendingTrust,
+	SEP24StatusPendingUser,
+	SEP24StatusCompleted,
+	SEP24StatusRefunded,
+	SEP24StatusExpired,
+	SEP24StatusError,
+	SEP24StatusNoMarket,
+	SEP24StatusTooSmall,
+	SEP24StatusTooLarge,
+}
+
+// SEP24Transaction is a deposit or withdrawal of an anchor started in its
+// SEP-24 interactive flow. Payments received with the memo of a withdrawal
+// waiting for the user's transfer are matched with it.
+type SEP24Transaction struct {
+	exists bool
+	ID     *int64 `db:"id"`
+	// TransactionID is the ID of the transaction in SEP-24 API
+	TransactionID string `db:"transaction_id"`
+	Kind          string `db:"kind"`
+	Status        string `db:"status"`
+	// Account is the Stellar account of the user: the source of withdrawals
+	// and the destination of deposits
+	Account     string         `db:"account"`
+	AssetCode   string         `db:"asset_code"`
+	AssetIssuer string         `db:"asset_issuer"`
+	AmountIn    amounts.Amount `db:"amount_in"`
+	AmountOut   amounts.Amount `db:"amount_out"`
+	AmountFee   amounts.Amount `db:"amount_fee"`
+	// AnchorAccount receives payments of withdrawals
+	AnchorAccount string `db:"anchor_account"`
+	// MemoType and Memo are the memo of the withdrawal payment or the deposit
+	// payment. Memos of hash type are base64 encoded.
+	MemoType              string     `db:"memo_type"`
+	Memo                  string     `db:"memo"`
+	MoreInfoURL           string     `db:"more_info_url"`
+	StellarTransactionID  string     `db:"stellar_transaction_id"`
+	ExternalTransactionID string     `db:"external_transaction_id"`
+	Message               string     `db:"message"`
+	StartedAt             time.Time  `db:"started_at"`
+	UpdatedAt             time.Time  `db:"updated_at"`
+	CompletedAt           *time.Time `db:"completed_at"`
+}
+
+// IsSEP24Status returns true when status is one of SEP24Statuses
+func IsSEP24Status(status string) bool {
+	for _, s := range SEP24Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Completed returns true when funds of e have been delivered or refunded
+func (e *SEP24Transaction) Completed() bool {
+	return e.Status == SEP24StatusCompleted || e.Status == SEP24StatusRefunded
+}
+
+// GetID returns ID of the entity
+func (e *SEP24Transaction) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *SEP24Transaction) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *SEP24Transaction) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *SEP24Transaction) SetExists() {
+	e.exists = true
+}
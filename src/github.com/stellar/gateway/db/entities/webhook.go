@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook is an endpoint subscribed to events of the bridge server using
+// /admin/webhooks. Events are delivered in POST requests signed with Secret
+// and retried MaxAttempts times with RetryInterval doubled after every
+// failed attempt.
+type Webhook struct {
+	exists bool
+	ID     *int64 `db:"id" json:"-"`
+	// WebhookID is the ID of the webhook generated by the bridge server
+	WebhookID string `db:"webhook_id" json:"id"`
+	URL       string `db:"url" json:"url"`
+	// Events is a comma separated list of event types the webhook is
+	// subscribed to
+	Events string `db:"events" json:"-"`
+	Secret string `db:"secret" json:"-"`
+	// MaxAttempts is the number of delivery attempts of every event
+	MaxAttempts int `db:"max_attempts" json:"max_attempts"`
+	// RetryInterval is the number of seconds before the first retry
+	RetryInterval int  `db:"retry_interval" json:"retry_interval"`
+	Enabled       bool `db:"enabled" json:"enabled"`
+	// Format of event bodies: json or cloudevents
+	Format string `db:"format" json:"format"`
+	// SchemaVersion is the version of event data negotiated when the
+	// webhook was registered
+	SchemaVersion string    `db:"schema_version" json:"schema_version"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// EventTypes returns event types of Events
+func (e *Webhook) EventTypes() []string {
+	if e.Events == "" {
+		return []string{}
+	}
+	return strings.Split(e.Events, ",")
+}
+
+// Subscribed returns true when the webhook is subscribed to eventType
+func (e *Webhook) Subscribed(eventType string) bool {
+	for _, event := range e.EventTypes() {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetID returns ID of the entity
+func (e *Webhook) GetID() *int64 {
+	if e.ID == nil {
+		return nil
+	}
+	newID := *e.ID
+	return &newID
+}
+
+// SetID sets ID of the entity
+func (e *Webhook) SetID(id int64) {
+	e.ID = &id
+}
+
+// IsNew returns true if the entity has not been persisted yet
+func (e *Webhook) IsNew() bool {
+	return !e.exists
+}
+
+// SetExists sets entity as persisted
+func (e *Webhook) SetExists() {
+	e.exists = true
+}
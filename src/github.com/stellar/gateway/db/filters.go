@@ -0,0 +1,206 @@
+package db
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ReceivedPaymentsFilter limits payments returned by GetReceivedPayments.
+// Empty fields are not used.
+type ReceivedPaymentsFilter struct {
+	// From and To limit processed_at to [From, To)
+	From   *time.Time
+	To     *time.Time
+	Status string
+	MemoID string
+}
+
+func (f ReceivedPaymentsFilter) where() (string, []interface{}) {
+	c := conditions{}
+	c.addTime("processed_at >= ?", f.From)
+	c.addTime("processed_at < ?", f.To)
+	c.addString("status = ?", f.Status)
+	c.addString("memo_id = ?", f.MemoID)
+	return c.where()
+}
+
+// SentTransactionsFilter limits transactions returned by GetSentTransactions.
+// Empty fields are not used.
+type SentTransactionsFilter struct {
+	// From and To limit submitted_at to [From, To)
+	From   *time.Time
+	To     *time.Time
+	Status string
+	// Source is a source account of the transaction
+	Source string
+	// Metadata are key/values the metadata of the transaction must contain
+	Metadata map[string]string
+}
+
+func (f SentTransactionsFilter) where() (string, []interface{}) {
+	c := conditions{}
+	c.addTime("submitted_at >= ?", f.From)
+	c.addTime("submitted_at < ?", f.To)
+	c.addString("status = ?", f.Status)
+	c.addString("source = ?", f.Source)
+	for key, value := range f.Metadata {
+		c.addMetadata(key, value)
+	}
+	return c.where()
+}
+
+// DailyAggregatesFilter limits aggregates returned by GetDailyAggregates.
+// Empty fields are not used.
+type DailyAggregatesFilter struct {
+	// From and To limit day to [From, To], days are in 2006-01-02 format
+	From      string
+	To        string
+	Tenant    string
+	Direction string
+	AssetCode string
+}
+
+func (f DailyAggregatesFilter) where() (string, []interface{}) {
+	c := conditions{}
+	c.addString("day >= ?", f.From)
+	c.addString("day <= ?", f.To)
+	c.addString("tenant = ?", f.Tenant)
+	c.addString("direction = ?", f.Direction)
+	c.addString("asset_code = ?", f.AssetCode)
+	return c.where()
+}
+
+// FederationSnapshotsFilter limits snapshots returned by
+// GetFederationSnapshots. Empty fields are not used.
+type FederationSnapshotsFilter struct {
+	TransactionID string
+	PaymentID     string
+}
+
+func (f FederationSnapshotsFilter) where() (string, []interface{}) {
+	c := conditions{}
+	c.addString("transaction_id = ?", f.TransactionID)
+	c.addString("payment_id = ?", f.PaymentID)
+	return c.where()
+}
+
+// PaymentVelocityFilter selects payments counted by GetPaymentVelocity.
+// Empty Source and Destination are not used. Payments of all assets are
+// counted when AssetCode is empty.
+type PaymentVelocityFilter struct {
+	Source      string
+	Destination string
+	AssetCode   string
+	AssetIssuer string
+	// Since limits created_at to [Since, now]
+	Since time.Time
+}
+
+func (f PaymentVelocityFilter) where() (string, []interface{}) {
+	c := conditions{}
+	c.addString("source = ?", f.Source)
+	c.addString("destination = ?", f.Destination)
+	if f.AssetCode != "" {
+		c.clauses = append(c.clauses, "asset_code = ?", "asset_issuer = ?")
+		c.params = append(c.params, f.AssetCode, f.AssetIssuer)
+	}
+	c.addTime("created_at >= ?", &f.Since)
+	return c.where()
+}
+
+// ComplianceAuditFilter limits entries returned by GetComplianceAuditEntries.
+// Empty fields are not used.
+type ComplianceAuditFilter struct {
+	// AfterSequence returns entries with greater sequence numbers
+	AfterSequence int64
+	// From and To limit created_at to [From, To)
+	From          *time.Time
+	To            *time.Time
+	Type          string
+	TransactionID string
+	Counterparty  string
+	Decision      string
+}
+
+func (f ComplianceAuditFilter) where() (string, []interface{}) {
+	c := conditions{}
+	if f.AfterSequence > 0 {
+		c.clauses = append(c.clauses, "sequence > ?")
+		c.params = append(c.params, f.AfterSequence)
+	}
+	c.addTime("created_at >= ?", f.From)
+	c.addTime("created_at < ?", f.To)
+	c.addString("type = ?", f.Type)
+	c.addString("transaction_id = ?", f.TransactionID)
+	c.addString("counterparty = ?", f.Counterparty)
+	c.addString("decision = ?", f.Decision)
+	return c.where()
+}
+
+// SEP24Filter filters SEP-24 transactions, empty fields are not filtered
+type SEP24Filter struct {
+	Account   string
+	AssetCode string
+	Kind      string
+	// NoOlderThan limits started_at to NoOlderThan or later
+	NoOlderThan *time.Time
+	// BeforeID returns transactions started before the transaction with this
+	// ID (ID of the row)
+	BeforeID int64
+}
+
+func (f SEP24Filter) where() (string, []interface{}) {
+	c := conditions{}
+	c.addString("account = ?", f.Account)
+	c.addString("asset_code = ?", f.AssetCode)
+	c.addString("kind = ?", f.Kind)
+	c.addTime("started_at >= ?", f.NoOlderThan)
+	if f.BeforeID > 0 {
+		c.clauses = append(c.clauses, "id < ?")
+		c.params = append(c.params, f.BeforeID)
+	}
+	return c.where()
+}
+
+// conditions builds a WHERE clause of a query
+type conditions struct {
+	clauses []string
+	params  []interface{}
+}
+
+func (c *conditions) addTime(clause string, value *time.Time) {
+	if value != nil {
+		c.clauses = append(c.clauses, clause)
+		c.params = append(c.params, *value)
+	}
+}
+
+func (c *conditions) addString(clause, value string) {
+	if value != "" {
+		c.clauses = append(c.clauses, clause)
+		c.params = append(c.params, value)
+	}
+}
+
+// addMetadata adds a condition matching metadata column containing key with
+// value. Metadata are stored as JSON objects, so the key/value pair is
+// matched as a JSON-encoded substring.
+func (c *conditions) addMetadata(key, value string) {
+	encodedKey, _ := json.Marshal(key)
+	encodedValue, _ := json.Marshal(value)
+	pattern := likeEscaper.Replace(string(encodedKey) + ":" + string(encodedValue))
+	c.clauses = append(c.clauses, "metadata LIKE ? ESCAPE '!'")
+	c.params = append(c.params, "%"+pattern+"%")
+}
+
+// likeEscaper escapes wildcards of LIKE patterns using ! escape character
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// where returns WHERE clause (empty when there are no conditions) and its params
+func (c *conditions) where() (string, []interface{}) {
+	if len(c.clauses) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(c.clauses, " AND "), c.params
+}
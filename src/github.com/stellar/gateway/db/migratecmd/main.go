@@ -0,0 +1,186 @@
+// Package migratecmd contains `migrate` command of bridge and compliance
+// servers used to apply, roll back and list DB migrations.
+package migratecmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/gateway/db"
+)
+
+// NewCommand returns `migrate` command with `up`, `down` and `status`
+// subcommands. open returns a driver connected to the database of a server
+// with migrations of a given component.
+func NewCommand(component string, open func() (db.Driver, error)) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "migrate",
+		Short: "manage DB schema",
+		Long:  "Applies, rolls back and lists DB migrations. Server refuses to start when DB schema does not match its version.",
+	}
+
+	command.AddCommand(&cobra.Command{
+		Use:   "up",
+		Short: "apply pending migrations",
+		Run: func(cmd *cobra.Command, args []string) {
+			driver := mustOpen(open)
+			applied, err := driver.MigrateUp(component)
+			if err != nil {
+				log.Fatal("Error applying migrations: ", err)
+			}
+			log.Info("Applied migrations: ", applied)
+		},
+	})
+
+	command.AddCommand(&cobra.Command{
+		Use:   "down [count]",
+		Short: "roll back the last count migrations (default 1)",
+		Run: func(cmd *cobra.Command, args []string) {
+			count, err := parseCount(args)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			driver := mustOpen(open)
+			rolledBack, err := driver.MigrateDown(component, count)
+			if err != nil {
+				log.Fatal("Error rolling back migrations: ", err)
+			}
+			log.Info("Rolled back migrations: ", rolledBack)
+		},
+	})
+
+	command.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "list applied and pending migrations",
+		Run: func(cmd *cobra.Command, args []string) {
+			driver := mustOpen(open)
+			status, err := driver.MigrationStatus(component)
+			if err != nil {
+				log.Fatal("Error loading migrations: ", err)
+			}
+			PrintStatus(os.Stdout, status)
+		},
+	})
+
+	command.AddCommand(&cobra.Command{
+		Use:   "verify",
+		Short: "compare rows of primary and secondary databases",
+		Long:  "Compares all rows of primary and secondary (database.secondary) databases and prints a consistency report. Exits with status 1 when databases differ.",
+		Run: func(cmd *cobra.Command, args []string) {
+			driver := mustOpen(open)
+			dualWrite, ok := driver.(*db.DualWriteDriver)
+			if !ok {
+				log.Fatal("database.secondary is not configured")
+			}
+
+			report, err := db.CompareDatabases(context.Background(), dualWrite.Primary.DB(), dualWrite.Secondary.DB(), db.ComponentTables[component])
+			if err != nil {
+				log.Fatal("Error comparing databases: ", err)
+			}
+			PrintReport(os.Stdout, report)
+			if !report.Consistent() {
+				os.Exit(1)
+			}
+		},
+	})
+
+	return command
+}
+
+// maxReportedIDs is the max number of IDs of differing rows printed by
+// PrintReport for every table
+const maxReportedIDs = 20
+
+// PrintReport writes a consistency report of primary and secondary databases
+// to w
+func PrintReport(w io.Writer, report db.ConsistencyReport) {
+	for _, table := range report.Tables {
+		fmt.Fprintf(w, "%s: %d primary rows, %d secondary rows\n", table.Table, table.PrimaryRows, table.SecondaryRows)
+
+		groups := []struct {
+			name string
+			ids  []int64
+		}{
+			{"Missing in secondary", table.Missing},
+			{"Extra in secondary", table.Extra},
+			{"Different", table.Different},
+		}
+		for _, group := range groups {
+			if len(group.ids) == 0 {
+				continue
+			}
+
+			ids := make([]string, 0, maxReportedIDs)
+			for i, id := range group.ids {
+				if i == maxReportedIDs {
+					ids = append(ids, "...")
+					break
+				}
+				ids = append(ids, strconv.FormatInt(id, 10))
+			}
+			fmt.Fprintf(w, "  %s: %d (ids: %s)\n", group.name, len(group.ids), strings.Join(ids, ", "))
+		}
+	}
+
+	if report.Consistent() {
+		fmt.Fprintln(w, "Databases are consistent")
+	} else {
+		fmt.Fprintln(w, "Databases differ")
+	}
+}
+
+// PrintStatus writes a list of migrations in status to w
+func PrintStatus(w io.Writer, status db.MigrationStatus) {
+	groups := []struct {
+		name       string
+		migrations []string
+	}{
+		{"Applied", status.Applied},
+		{"Pending", status.Pending},
+		{"Unknown (applied by a newer version)", status.Unknown},
+	}
+
+	for _, group := range groups {
+		if len(group.migrations) == 0 && group.name != "Pending" {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s migrations: %d\n", group.name, len(group.migrations))
+		for _, migration := range group.migrations {
+			fmt.Fprintf(w, "  %s\n", migration)
+		}
+	}
+}
+
+func parseCount(args []string) (int, error) {
+	switch len(args) {
+	case 0:
+		return 1, nil
+	case 1:
+		count, err := strconv.Atoi(args[0])
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("Invalid count: %s", args[0])
+		}
+		return count, nil
+	default:
+		return 0, fmt.Errorf("Too many arguments")
+	}
+}
+
+func mustOpen(open func() (db.Driver, error)) db.Driver {
+	driver, err := open()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if driver == nil {
+		log.Fatal("No database driver.")
+	}
+	return driver
+}
@@ -0,0 +1,148 @@
+package migratecmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+
+	status, err := driver.MigrationStatus("gateway")
+	require.NoError(t, err)
+	assert.Empty(t, status.Applied)
+	assert.Equal(t, []string{"01_init.sql", "02_security_event.sql", "03_api_key.sql", "04_destination_profile.sql", "05_daily_aggregate.sql", "06_federation_snapshot.sql", "07_received_payment_event.sql", "08_payment_limit_usage.sql", "09_named_destination.sql", "10_held_payment_approval.sql", "11_payment_velocity.sql", "12_expected_payment.sql", "13_refund.sql", "14_failed_callback.sql", "15_offline_transaction.sql", "16_quote.sql", "17_fiat_value.sql", "18_webhook.sql", "19_webhook_format.sql", "20_memo_reference.sql", "21_sent_transaction_metadata.sql", "22_leader_lease.sql", "23_sep24_transaction.sql", "24_held_payment_seed.sql", "25_sent_transaction_conflict.sql", "26_sent_transaction_original_id.sql", "27_api_key_tenant.sql"}, status.Pending)
+	assert.EqualError(t, db.CheckSchema(driver, "gateway"), "DB schema is out of date, pending migrations: 01_init.sql, 02_security_event.sql, 03_api_key.sql, 04_destination_profile.sql, 05_daily_aggregate.sql, 06_federation_snapshot.sql, 07_received_payment_event.sql, 08_payment_limit_usage.sql, 09_named_destination.sql, 10_held_payment_approval.sql, 11_payment_velocity.sql, 12_expected_payment.sql, 13_refund.sql, 14_failed_callback.sql, 15_offline_transaction.sql, 16_quote.sql, 17_fiat_value.sql, 18_webhook.sql, 19_webhook_format.sql, 20_memo_reference.sql, 21_sent_transaction_metadata.sql, 22_leader_lease.sql, 23_sep24_transaction.sql, 24_held_payment_seed.sql, 25_sent_transaction_conflict.sql, 26_sent_transaction_original_id.sql, 27_api_key_tenant.sql. Apply them using `migrate up` command.")
+
+	_, err = driver.MigrateUp("gateway")
+	require.NoError(t, err)
+	assert.NoError(t, db.CheckSchema(driver, "gateway"))
+
+	var out bytes.Buffer
+	status, err = driver.MigrationStatus("gateway")
+	require.NoError(t, err)
+	PrintStatus(&out, status)
+	assert.Equal(t, "Applied migrations: 27\n  01_init.sql\n  02_security_event.sql\n  03_api_key.sql\n  04_destination_profile.sql\n  05_daily_aggregate.sql\n  06_federation_snapshot.sql\n  07_received_payment_event.sql\n  08_payment_limit_usage.sql\n  09_named_destination.sql\n  10_held_payment_approval.sql\n  11_payment_velocity.sql\n  12_expected_payment.sql\n  13_refund.sql\n  14_failed_callback.sql\n  15_offline_transaction.sql\n  16_quote.sql\n  17_fiat_value.sql\n  18_webhook.sql\n  19_webhook_format.sql\n  20_memo_reference.sql\n  21_sent_transaction_metadata.sql\n  22_leader_lease.sql\n  23_sep24_transaction.sql\n  24_held_payment_seed.sql\n  25_sent_transaction_conflict.sql\n  26_sent_transaction_original_id.sql\n  27_api_key_tenant.sql\nPending migrations: 0\n", out.String())
+
+	// Migration applied by a newer version of the server
+	_, err = driver.DB().Exec("INSERT INTO gorp_migrations (id, applied_at) VALUES ('99_future.sql', CURRENT_TIMESTAMP)")
+	require.NoError(t, err)
+	assert.Contains(t, db.CheckSchema(driver, "gateway").Error(), "unknown migrations: 99_future.sql")
+
+	_, err = driver.DB().Exec("DELETE FROM gorp_migrations WHERE id = '99_future.sql'")
+	require.NoError(t, err)
+
+	rolledBack, err := driver.MigrateDown("gateway", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rolledBack)
+
+	status, err = driver.MigrationStatus("gateway")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"27_api_key_tenant.sql"}, status.Pending)
+}
+
+func TestParseCount(t *testing.T) {
+	count, err := parseCount(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = parseCount([]string{"3"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	_, err = parseCount([]string{"0"})
+	assert.Error(t, err)
+
+	_, err = parseCount([]string{"1", "2"})
+	assert.Error(t, err)
+}
+
+func TestDualWrite(t *testing.T) {
+	primary := &sqlite.Driver{}
+	require.NoError(t, primary.Init(sqlite.MemoryURL))
+	secondary := &sqlite.Driver{}
+	require.NoError(t, secondary.Init(sqlite.MemoryURL))
+
+	driver := db.NewDualWriteDriver(primary, secondary, true)
+	_, err := secondary.MigrateUp("gateway")
+	require.NoError(t, err)
+	_, err = secondary.MigrateDown("gateway", 1)
+	require.NoError(t, err)
+	assert.EqualError(t, db.CheckSchema(driver, "gateway"), "DB schema is out of date, pending migrations: 01_init.sql, 02_security_event.sql, 03_api_key.sql, 04_destination_profile.sql, 05_daily_aggregate.sql, 06_federation_snapshot.sql, 07_received_payment_event.sql, 08_payment_limit_usage.sql, 09_named_destination.sql, 10_held_payment_approval.sql, 11_payment_velocity.sql, 12_expected_payment.sql, 13_refund.sql, 14_failed_callback.sql, 15_offline_transaction.sql, 16_quote.sql, 17_fiat_value.sql, 18_webhook.sql, 19_webhook_format.sql, 20_memo_reference.sql, 21_sent_transaction_metadata.sql, 22_leader_lease.sql, 23_sep24_transaction.sql, 24_held_payment_seed.sql, 25_sent_transaction_conflict.sql, 26_sent_transaction_original_id.sql, 27_api_key_tenant.sql, secondary:27_api_key_tenant.sql. Apply them using `migrate up` command.")
+
+	_, err = driver.MigrateUp("gateway")
+	require.NoError(t, err)
+	require.NoError(t, db.CheckSchema(driver, "gateway"))
+
+	entityManager := db.NewEntityManager(driver)
+	repository := db.NewRepository(driver)
+	secondaryRepository := db.NewRepository(secondary)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// IDs assigned by primary database are kept in secondary
+	_, err = primary.DB().Exec("INSERT INTO HeldPayment (payment_id, source_account, request, status, created_at, settle_at) VALUES ('skipped', 'GAB', '', 'held', ?, ?)", now, now)
+	require.NoError(t, err)
+
+	payment := &entities.HeldPayment{
+		PaymentID:     "payment-1",
+		SourceAccount: "GAB",
+		Status:        entities.HeldPaymentStatusHeld,
+		CreatedAt:     now,
+		SettleAt:      now,
+	}
+	require.NoError(t, entityManager.Persist(payment))
+	assert.Equal(t, int64(2), *payment.ID)
+
+	// Raw writes of Repository are repeated in secondary database
+	updated, err := repository.UpdateHeldPaymentStatus(ctx, payment, entities.HeldPaymentStatusCancelled, now)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	found, err := secondaryRepository.GetHeldPaymentByPaymentID(ctx, "payment-1")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, int64(2), *found.ID)
+	assert.Equal(t, entities.HeldPaymentStatusCancelled, found.Status)
+
+	// Reads are served from primary database
+	_, err = secondary.DB().Exec("UPDATE HeldPayment SET source_account = 'GCD' WHERE id = 2")
+	require.NoError(t, err)
+	found, err = repository.GetHeldPaymentByPaymentID(ctx, "payment-1")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "GAB", found.SourceAccount)
+
+	_, err = secondary.DB().Exec("INSERT INTO FeatureFlag (name, tenant, enabled, updated_by, updated_at) VALUES ('flag', '', 1, 'admin', ?)", now)
+	require.NoError(t, err)
+
+	report, err := db.CompareDatabases(ctx, primary.DB(), secondary.DB(), db.ComponentTables["gateway"])
+	require.NoError(t, err)
+	assert.False(t, report.Consistent())
+
+	var out bytes.Buffer
+	PrintReport(&out, report)
+	assert.Contains(t, out.String(), "HeldPayment: 2 primary rows, 1 secondary rows\n  Missing in secondary: 1 (ids: 1)\n  Different: 1 (ids: 2)\n")
+	assert.Contains(t, out.String(), "FeatureFlag: 0 primary rows, 1 secondary rows\n  Extra in secondary: 1 (ids: 1)\n")
+	assert.Contains(t, out.String(), "SentTransaction: 0 primary rows, 0 secondary rows\n")
+	assert.Contains(t, out.String(), "Databases differ\n")
+
+	require.NoError(t, entityManager.Delete(payment))
+	_, err = primary.DB().Exec("DELETE FROM HeldPayment")
+	require.NoError(t, err)
+	_, err = secondary.DB().Exec("DELETE FROM FeatureFlag")
+	require.NoError(t, err)
+
+	report, err = db.CompareDatabases(ctx, primary.DB(), secondary.DB(), db.ComponentTables["gateway"])
+	require.NoError(t, err)
+	assert.True(t, report.Consistent())
+}
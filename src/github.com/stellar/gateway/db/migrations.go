@@ -0,0 +1,80 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// MigrationStatus compares migrations of a component known to this version of
+// the server with migrations applied to the database
+type MigrationStatus struct {
+	Applied []string
+	// Pending migrations have not been applied yet
+	Pending []string
+	// Unknown migrations have been applied by a newer version of the server
+	Unknown []string
+}
+
+// GetMigrationStatus returns MigrationStatus of migrations in source
+func GetMigrationStatus(database *sql.DB, dialect string, source migrate.MigrationSource) (status MigrationStatus, err error) {
+	migrations, err := source.FindMigrations()
+	if err != nil {
+		return
+	}
+
+	records, err := migrate.GetMigrationRecords(database, dialect)
+	if err != nil {
+		return
+	}
+
+	applied := map[string]bool{}
+	for _, record := range records {
+		applied[record.Id] = true
+	}
+
+	known := map[string]bool{}
+	for _, migration := range migrations {
+		known[migration.Id] = true
+		if applied[migration.Id] {
+			status.Applied = append(status.Applied, migration.Id)
+		} else {
+			status.Pending = append(status.Pending, migration.Id)
+		}
+	}
+
+	for _, record := range records {
+		if !known[record.Id] {
+			status.Unknown = append(status.Unknown, record.Id)
+		}
+	}
+
+	return
+}
+
+// CheckSchema returns error when the database schema of a component does not
+// match this version of the server
+func CheckSchema(driver Driver, component string) error {
+	status, err := driver.MigrationStatus(component)
+	if err != nil {
+		return fmt.Errorf("Cannot check DB schema version: %s", err)
+	}
+
+	if len(status.Unknown) > 0 {
+		return fmt.Errorf(
+			"DB schema is newer than this version of the server, unknown migrations: %s. Upgrade the server or roll the migrations back using `migrate down` command.",
+			strings.Join(status.Unknown, ", "),
+		)
+	}
+
+	if len(status.Pending) > 0 {
+		return fmt.Errorf(
+			"DB schema is out of date, pending migrations: %s. Apply them using `migrate up` command.",
+			strings.Join(status.Pending, ", "),
+		)
+	}
+
+	return nil
+}
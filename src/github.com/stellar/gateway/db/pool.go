@@ -0,0 +1,64 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/stellar/gateway/metrics"
+)
+
+var poolMetrics = struct {
+	open         *metrics.Gauge
+	inUse        *metrics.Gauge
+	idle         *metrics.Gauge
+	maxOpen      *metrics.Gauge
+	waitCount    *metrics.Gauge
+	waitDuration *metrics.Gauge
+}{
+	open:         metrics.NewGauge(metrics.Label("db_pool_connections", "state", "open"), "Number of DB connections by state."),
+	inUse:        metrics.NewGauge(metrics.Label("db_pool_connections", "state", "in_use"), "Number of DB connections by state."),
+	idle:         metrics.NewGauge(metrics.Label("db_pool_connections", "state", "idle"), "Number of DB connections by state."),
+	maxOpen:      metrics.NewGauge("db_pool_max_open_connections", "Maximum number of open DB connections, 0 when unlimited."),
+	waitCount:    metrics.NewGauge("db_pool_wait_count", "Total number of times a query waited for a free DB connection."),
+	waitDuration: metrics.NewGauge("db_pool_wait_duration_seconds", "Total time queries waited for a free DB connection."),
+}
+
+// PoolOptions configures the connection pool of a database. Zero values keep
+// database/sql defaults.
+type PoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// ConfigurePool applies options to the connection pool of database
+func ConfigurePool(database *sql.DB, options PoolOptions) {
+	if options.MaxOpenConns > 0 {
+		database.SetMaxOpenConns(options.MaxOpenConns)
+	}
+	if options.MaxIdleConns > 0 {
+		database.SetMaxIdleConns(options.MaxIdleConns)
+	}
+	if options.ConnMaxLifetime > 0 {
+		database.SetConnMaxLifetime(options.ConnMaxLifetime)
+	}
+}
+
+// MonitorPool updates connection pool metrics every interval in a goroutine
+func MonitorPool(database *sql.DB, interval time.Duration) {
+	updatePoolMetrics(database.Stats())
+	go func() {
+		for range time.Tick(interval) {
+			updatePoolMetrics(database.Stats())
+		}
+	}()
+}
+
+func updatePoolMetrics(stats sql.DBStats) {
+	poolMetrics.open.Set(float64(stats.OpenConnections))
+	poolMetrics.inUse.Set(float64(stats.InUse))
+	poolMetrics.idle.Set(float64(stats.Idle))
+	poolMetrics.maxOpen.Set(float64(stats.MaxOpenConnections))
+	poolMetrics.waitCount.Set(float64(stats.WaitCount))
+	poolMetrics.waitDuration.Set(stats.WaitDuration.Seconds())
+}
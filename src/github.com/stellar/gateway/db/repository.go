@@ -1,37 +1,107 @@
 package db
 
 import (
-	"fmt"
-	"strconv"
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 	"github.com/stellar/gateway/db/entities"
-	"github.com/stellar/go/support/db"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/go/support/errors"
 )
 
 // RepositoryInterface helps mocking Repository
 type RepositoryInterface interface {
-	GetLastCursorValue() (cursor *string, err error)
-	GetAuthorizedTransactionByMemo(memo string) (*entities.AuthorizedTransaction, error)
-	GetSentTransactionByPaymentID(paymentID string) (*entities.SentTransaction, error)
-	GetAllowedFiByDomain(domain string) (*entities.AllowedFi, error)
-	GetAllowedUserByDomainAndUserID(domain, userID string) (*entities.AllowedUser, error)
-	GetReceivedPaymentByOperationID(operationID int64) (*entities.ReceivedPayment, error)
-	GetReceivedPayments(page, limit int) ([]*entities.ReceivedPayment, error)
-	GetSentTransactions(page, limit int) ([]*entities.SentTransaction, error)
+	GetLastCursorValue(ctx context.Context) (cursor *string, err error)
+	GetLastContractEventCursor(ctx context.Context) (cursor *string, err error)
+	GetAuthorizedTransactionByMemo(ctx context.Context, memo string) (*entities.AuthorizedTransaction, error)
+	GetSentTransactionByPaymentID(ctx context.Context, paymentID string) (*entities.SentTransaction, error)
+	GetAllowedFiByDomain(ctx context.Context, domain string) (*entities.AllowedFi, error)
+	GetAllowedUserByDomainAndUserID(ctx context.Context, domain, userID string) (*entities.AllowedUser, error)
+	GetReceivedPaymentByOperationID(ctx context.Context, operationID string) (*entities.ReceivedPayment, error)
+	GetReceivedPayments(ctx context.Context, filter ReceivedPaymentsFilter, page, limit int) ([]*entities.ReceivedPayment, error)
+	GetReceivedPaymentTotalByMemoID(ctx context.Context, memoID string) (string, error)
+	GetSentTransactions(ctx context.Context, filter SentTransactionsFilter, page, limit int) ([]*entities.SentTransaction, error)
+	GetSentTransactionConflicts(ctx context.Context) ([]*entities.SentTransaction, error)
+	GetHeldPaymentByPaymentID(ctx context.Context, paymentID string) (*entities.HeldPayment, error)
+	GetHeldPaymentsToRelease(ctx context.Context, now time.Time) ([]*entities.HeldPayment, error)
+	GetHeldPayments(ctx context.Context) ([]*entities.HeldPayment, error)
+	UpdateHeldPaymentStatus(ctx context.Context, payment *entities.HeldPayment, status string, now time.Time) (bool, error)
+	ApproveHeldPayment(ctx context.Context, payment *entities.HeldPayment, approvedBy string, now, settleAt time.Time) (bool, error)
+	GetComplianceRepairByOperationID(ctx context.Context, operationID string) (*entities.ComplianceRepair, error)
+	GetSentTransactionByTransactionID(ctx context.Context, transactionID string) (*entities.SentTransaction, error)
+	GetMemoRequiredDestination(ctx context.Context, accountID string) (*entities.MemoRequiredDestination, error)
+	GetMemoRequiredDestinations(ctx context.Context) ([]*entities.MemoRequiredDestination, error)
+	GetFeatureFlag(ctx context.Context, name, tenant string) (*entities.FeatureFlag, error)
+	GetFeatureFlags(ctx context.Context, name string) ([]*entities.FeatureFlag, error)
+	GetSecurityEventsToDeliver(ctx context.Context, now time.Time, limit int) ([]*entities.SecurityEvent, error)
+	GetAPIKey(ctx context.Context, keyID string) (*entities.APIKey, error)
+	GetDailyAggregates(ctx context.Context, filter DailyAggregatesFilter) ([]*entities.DailyAggregate, error)
+	AddToDailyAggregate(ctx context.Context, aggregate *entities.DailyAggregate) error
+	GetFederationSnapshots(ctx context.Context, filter FederationSnapshotsFilter) ([]*entities.FederationSnapshot, error)
+	GetReceivedPaymentEvents(ctx context.Context, afterID int64, limit int) ([]*entities.ReceivedPaymentEvent, error)
+	GetLastReceivedPaymentEventID(ctx context.Context) (int64, error)
+	GetPaymentLimitUsage(ctx context.Context, day, assetCode, assetIssuer, destination string) (int64, error)
+	AddToPaymentLimitUsage(ctx context.Context, usage *entities.PaymentLimitUsage) error
+	GetNamedDestination(ctx context.Context, name string) (*entities.NamedDestination, error)
+	GetNamedDestinations(ctx context.Context) ([]*entities.NamedDestination, error)
+	GetPaymentVelocity(ctx context.Context, filter PaymentVelocityFilter) (count, amount int64, err error)
+	DeletePaymentVelocityBefore(ctx context.Context, before time.Time) (int64, error)
+	GetLastComplianceAuditEntry(ctx context.Context) (*entities.ComplianceAuditEntry, error)
+	GetComplianceAuditEntries(ctx context.Context, filter ComplianceAuditFilter, limit int) ([]*entities.ComplianceAuditEntry, error)
+	GetAttachmentByMemoHash(ctx context.Context, memoHash string) (*entities.Attachment, error)
+	GetExpectedPayment(ctx context.Context, expectedID string) (*entities.ExpectedPayment, error)
+	GetOpenExpectedPayment(ctx context.Context, assetCode, assetIssuer, memoType, memo string) (*entities.ExpectedPayment, error)
+	GetPaymentMatch(ctx context.Context, operationID string) (*entities.PaymentMatch, error)
+	GetPaymentMatches(ctx context.Context, expectedID string) ([]*entities.PaymentMatch, error)
+	GetUnmatchedPayments(ctx context.Context, limit int) ([]*entities.PaymentMatch, error)
+	GetRefund(ctx context.Context, operationID string) (*entities.Refund, error)
+	GetFailedCallback(ctx context.Context, operationID string) (*entities.FailedCallback, error)
+	GetFailedCallbacks(ctx context.Context, status string, limit int) ([]*entities.FailedCallback, error)
+	GetOfflineTransaction(ctx context.Context, offlineID string) (*entities.OfflineTransaction, error)
+	GetQuote(ctx context.Context, quoteID string) (*entities.Quote, error)
+	GetFiatValue(ctx context.Context, direction, reference string) (*entities.FiatValue, error)
+	GetWebhook(ctx context.Context, webhookID string) (*entities.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]*entities.Webhook, error)
+	GetMemoReference(ctx context.Context, memo string) (*entities.MemoReference, error)
+	AcquireLeaderLease(ctx context.Context, name, holder string, now, expiresAt time.Time) (*entities.LeaderLease, error)
+	ReleaseLeaderLease(ctx context.Context, name, holder string, now time.Time) error
+	GetSEP24Transaction(ctx context.Context, transactionID string) (*entities.SEP24Transaction, error)
+	GetSEP24TransactionByStellarID(ctx context.Context, stellarTransactionID string) (*entities.SEP24Transaction, error)
+	GetSEP24TransactionByExternalID(ctx context.Context, externalTransactionID string) (*entities.SEP24Transaction, error)
+	GetSEP24Transactions(ctx context.Context, filter SEP24Filter, limit int) ([]*entities.SEP24Transaction, error)
+	GetPendingSEP24Withdrawal(ctx context.Context, anchorAccount, assetCode, assetIssuer, memoType, memo string) (*entities.SEP24Transaction, error)
 }
 
-// Repository helps getting data from DB
+// Repository helps getting data from DB. Queries are prepared once and the
+// prepared statements are reused by all copies of the Repository. Every query
+// is cancelled when its ctx is done.
 type Repository struct {
-	driver Driver
-	repo   *db.Session
-	log    *logrus.Entry
+	driver     Driver
+	db         *sqlx.DB
+	statements *statements
+	log        *logrus.Entry
+	// dualWrite is set when driver is DualWriteDriver, raw writes are repeated
+	// in its secondary database
+	dualWrite *DualWriteDriver
+}
+
+// statements caches prepared statements by query
+type statements struct {
+	sync.Mutex
+	prepared map[string]*sql.Stmt
 }
 
 // NewRepository creates a new Repository using driver
 func NewRepository(driver Driver) (r Repository) {
 	r.driver = driver
-	r.repo = &db.Session{DB: driver.DB()}
+	r.db = driver.DB()
+	r.statements = &statements{prepared: map[string]*sql.Stmt{}}
+	r.dualWrite, _ = driver.(*DualWriteDriver)
 	r.log = logrus.WithFields(logrus.Fields{
 		"service": "Repository",
 	})
@@ -39,8 +109,8 @@ func NewRepository(driver Driver) (r Repository) {
 }
 
 // GetLastCursorValue returns last cursor value from a DB
-func (r Repository) GetLastCursorValue() (cursor *string, err error) {
-	receivedPayment, err := r.getLastReceivedPayment()
+func (r Repository) GetLastCursorValue(ctx context.Context) (cursor *string, err error) {
+	receivedPayment, err := r.getLastReceivedPayment(ctx, false)
 	if err != nil {
 		return nil, err
 	} else if receivedPayment == nil {
@@ -50,18 +120,28 @@ func (r Repository) GetLastCursorValue() (cursor *string, err error) {
 	}
 }
 
+// GetLastContractEventCursor returns the ID of the last contract event
+// processed, contract transfers are streamed from soroban-rpc separately
+func (r Repository) GetLastContractEventCursor(ctx context.Context) (cursor *string, err error) {
+	receivedPayment, err := r.getLastReceivedPayment(ctx, true)
+	if err != nil || receivedPayment == nil {
+		return nil, err
+	}
+	return &receivedPayment.PagingToken, nil
+}
+
 // GetAuthorizedTransactionByMemo returns authorized transaction searching by memo
-func (r Repository) GetAuthorizedTransactionByMemo(memo string) (*entities.AuthorizedTransaction, error) {
+func (r Repository) GetAuthorizedTransactionByMemo(ctx context.Context, memo string) (*entities.AuthorizedTransaction, error) {
 
 	var found entities.AuthorizedTransaction
 
-	err := r.repo.GetRaw(
+	err := r.getRaw(ctx,
 		&found,
 		"SELECT * FROM AuthorizedTransaction WHERE memo = ?",
 		memo,
 	)
 
-	if r.repo.NoRows(err) {
+	if noRows(err) {
 		return nil, nil
 	}
 
@@ -74,17 +154,17 @@ func (r Repository) GetAuthorizedTransactionByMemo(memo string) (*entities.Autho
 }
 
 // GetSentTransactionByPaymentID returns sent transaction searching by payment ID
-func (r Repository) GetSentTransactionByPaymentID(paymentID string) (*entities.SentTransaction, error) {
+func (r Repository) GetSentTransactionByPaymentID(ctx context.Context, paymentID string) (*entities.SentTransaction, error) {
 
 	var found entities.SentTransaction
 
-	err := r.repo.GetRaw(
+	err := r.getRaw(ctx,
 		&found,
-		"SELECT * FROM SentTransaction WHERE payment_id = ?",
+		"SELECT * FROM SentTransaction WHERE payment_id = ? ORDER BY id LIMIT 1",
 		paymentID,
 	)
 
-	if r.repo.NoRows(err) {
+	if noRows(err) {
 		return nil, nil
 	}
 
@@ -97,17 +177,17 @@ func (r Repository) GetSentTransactionByPaymentID(paymentID string) (*entities.S
 }
 
 // GetAllowedFiByDomain returns allowed FI by a domain
-func (r Repository) GetAllowedFiByDomain(domain string) (*entities.AllowedFi, error) {
+func (r Repository) GetAllowedFiByDomain(ctx context.Context, domain string) (*entities.AllowedFi, error) {
 
 	var found entities.AllowedFi
 
-	err := r.repo.GetRaw(
+	err := r.getRaw(ctx,
 		&found,
 		"SELECT * FROM AllowedFI WHERE domain = ?",
 		domain,
 	)
 
-	if r.repo.NoRows(err) {
+	if noRows(err) {
 		return nil, nil
 	}
 
@@ -120,18 +200,18 @@ func (r Repository) GetAllowedFiByDomain(domain string) (*entities.AllowedFi, er
 }
 
 // GetAllowedUserByDomainAndUserID returns allowed user by domain and userID
-func (r Repository) GetAllowedUserByDomainAndUserID(domain, userID string) (*entities.AllowedUser, error) {
+func (r Repository) GetAllowedUserByDomainAndUserID(ctx context.Context, domain, userID string) (*entities.AllowedUser, error) {
 
 	var found entities.AllowedUser
 
-	err := r.repo.GetRaw(
+	err := r.getRaw(ctx,
 		&found,
 		"SELECT * FROM AllowedUser WHERE fi_domain = ? AND user_id = ?",
 		domain,
 		userID,
 	)
 
-	if r.repo.NoRows(err) {
+	if noRows(err) {
 		return nil, nil
 	}
 
@@ -144,17 +224,17 @@ func (r Repository) GetAllowedUserByDomainAndUserID(domain, userID string) (*ent
 }
 
 // GetReceivedPaymentByOperationID returns received payment by operation_id
-func (r Repository) GetReceivedPaymentByOperationID(operationID int64) (*entities.ReceivedPayment, error) {
+func (r Repository) GetReceivedPaymentByOperationID(ctx context.Context, operationID string) (*entities.ReceivedPayment, error) {
 
 	var found entities.ReceivedPayment
 
-	err := r.repo.GetRaw(
+	err := r.getRaw(ctx,
 		&found,
 		"SELECT * FROM ReceivedPayment WHERE operation_id = ?",
 		operationID,
 	)
 
-	if r.repo.NoRows(err) {
+	if noRows(err) {
 		return nil, nil
 	}
 
@@ -166,84 +246,152 @@ func (r Repository) GetReceivedPaymentByOperationID(operationID int64) (*entitie
 	return &found, nil
 }
 
-// GetReceivedPayments returns received payments
-func (r Repository) GetReceivedPayments(page, limit int) ([]*entities.ReceivedPayment, error) {
+// GetReceivedPayments returns a page of received payments matching filter,
+// newest first
+func (r Repository) GetReceivedPayments(ctx context.Context, filter ReceivedPaymentsFilter, page, limit int) ([]*entities.ReceivedPayment, error) {
 	payments := []*entities.ReceivedPayment{}
 
 	if page == 0 {
 		page = 1
 	}
 
-	offset := (page - 1) * limit
+	where, params := filter.where()
+	params = append(params, limit, (page-1)*limit)
 
-	limitQuery := fmt.Sprintf("%d", limit)
-	offsetQuery := fmt.Sprintf("%d", offset)
-	orderQuery := "id desc"
+	err := r.selectRaw(
+		ctx,
+		&payments,
+		"SELECT * FROM ReceivedPayment"+where+" ORDER BY id DESC LIMIT ? OFFSET ?",
+		params...,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.driver.GetMany(&payments, nil, &orderQuery, &offsetQuery, &limitQuery)
-	return payments, err
+	for _, payment := range payments {
+		payment.SetExists()
+	}
+	return payments, nil
 }
 
-// GetReceivedPaymentTotalByMemoID returns received payment by memo_id
-func (r Repository) GetReceivedPaymentTotalByMemoID(MemoID string) (string, error) {
-
-	rows, err := r.repo.QueryRaw("select transaction_value from ReceivedPayment where memo_id = ?", MemoID)
+// GetReceivedPaymentTotalByMemoID returns the sum of amounts of received
+// payments with memo_id
+func (r Repository) GetReceivedPaymentTotalByMemoID(ctx context.Context, memoID string) (string, error) {
+	rows, err := r.queryRaw(ctx, "SELECT transaction_value FROM ReceivedPayment WHERE memo_id = ?", memoID)
 	if err != nil {
-		return "Database error", err
+		return "", err
 	}
-
-	var transList []string
-
 	defer rows.Close()
 
-	var total string
-
+	var total int64
 	for rows.Next() {
-		err := rows.Scan(&total)
+		var value string
+		err = rows.Scan(&value)
 		if err != nil {
-			fmt.Println(err)
+			return "", errors.Wrap(err, "Error scanning transaction_value")
 		}
-		transList = append(transList, total)
-	}
 
-	var sum float64
+		var stroops int64
+		stroops, err = amounts.Parse(value)
+		if err != nil {
+			return "", errors.Wrap(err, "Invalid transaction_value")
+		}
 
-	for _, i := range transList {
-		j, _ := strconv.ParseFloat(i, 64)
-		sum += j
+		total, err = amounts.Add(total, stroops)
+		if err != nil {
+			return "", err
+		}
 	}
 
-	fmt.Println(sum)
-	return strconv.FormatFloat(sum, 'f', 8, 64), err
-
+	err = rows.Err()
+	if err != nil {
+		return "", err
+	}
+	return amounts.String(total), nil
 }
 
-// GetSentTransactions returns received payments
-func (r Repository) GetSentTransactions(page, limit int) ([]*entities.SentTransaction, error) {
+// GetSentTransactions returns a page of sent transactions matching filter,
+// newest first
+func (r Repository) GetSentTransactions(ctx context.Context, filter SentTransactionsFilter, page, limit int) ([]*entities.SentTransaction, error) {
 	transactions := []*entities.SentTransaction{}
 
 	if page == 0 {
 		page = 1
 	}
 
-	offset := (page - 1) * limit
+	where, params := filter.where()
+	params = append(params, limit, (page-1)*limit)
 
-	limitQuery := fmt.Sprintf("%d", limit)
-	offsetQuery := fmt.Sprintf("%d", offset)
-	orderQuery := "id desc"
+	err := r.selectRaw(
+		ctx,
+		&transactions,
+		"SELECT * FROM SentTransaction"+where+" ORDER BY id DESC LIMIT ? OFFSET ?",
+		params...,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.driver.GetMany(&transactions, nil, &orderQuery, &offsetQuery, &limitQuery)
-	return transactions, err
+	for _, transaction := range transactions {
+		transaction.SetExists()
+	}
+	return transactions, nil
 }
 
-// getLastReceivedPayment returns the last received payment
-func (r Repository) getLastReceivedPayment() (*entities.ReceivedPayment, error) {
-	var receivedPayment entities.ReceivedPayment
-	// DO NOT use `processed_at` as payment can be reprocessed. Reprocessing will update `processed_at`
-	// value but not `id`.
-	err := r.repo.GetRaw(&receivedPayment, "SELECT * FROM ReceivedPayment ORDER BY id DESC LIMIT 1")
+// conflictingPaymentIDs selects payment IDs of SentTransactionConflict
+// submitted by a region other than the one in SentTransaction
+const conflictingPaymentIDs = `SELECT c.payment_id FROM SentTransactionConflict c
+	JOIN SentTransaction s ON s.payment_id = c.payment_id
+	WHERE c.region <> s.region`
+
+// GetSentTransactionConflicts returns sent transactions with payment ID that
+// has been submitted by more than one region, ordered by payment ID. Payment
+// ID is unique in SentTransaction table, transactions of other regions are
+// found in SentTransactionConflict table (filled when regional databases are
+// merged).
+func (r Repository) GetSentTransactionConflicts(ctx context.Context) ([]*entities.SentTransaction, error) {
+	transactions := []*entities.SentTransaction{}
+
+	err := r.selectRaw(ctx,
+		&transactions,
+		"SELECT * FROM SentTransaction WHERE payment_id IN ("+conflictingPaymentIDs+")",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []*entities.SentTransaction{}
+	err = r.selectRaw(ctx,
+		&conflicts,
+		"SELECT * FROM SentTransactionConflict WHERE payment_id IN ("+conflictingPaymentIDs+")",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range transactions {
+		transaction.SetExists()
+	}
+	transactions = append(transactions, conflicts...)
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return *transactions[i].PaymentID < *transactions[j].PaymentID
+	})
+	return transactions, nil
+}
 
-	if r.repo.NoRows(err) {
+// GetHeldPaymentByPaymentID returns held payment searching by payment ID
+func (r Repository) GetHeldPaymentByPaymentID(ctx context.Context, paymentID string) (*entities.HeldPayment, error) {
+
+	var found entities.HeldPayment
+
+	err := r.getRaw(ctx,
+		&found,
+		"SELECT * FROM HeldPayment WHERE payment_id = ?",
+		paymentID,
+	)
+
+	if noRows(err) {
 		return nil, nil
 	}
 
@@ -251,6 +399,1065 @@ func (r Repository) getLastReceivedPayment() (*entities.ReceivedPayment, error)
 		return nil, err
 	}
 
-	receivedPayment.SetExists()
-	return &receivedPayment, nil
+	found.SetExists()
+	return &found, nil
+}
+
+// GetHeldPaymentsToRelease returns held payments with settlement window ended
+// before now, the longest overdue first. Payments still releasing (the server
+// was stopped while submitting them) are returned too.
+func (r Repository) GetHeldPaymentsToRelease(ctx context.Context, now time.Time) ([]*entities.HeldPayment, error) {
+	payments := []*entities.HeldPayment{}
+
+	err := r.selectRaw(ctx,
+		&payments,
+		"SELECT * FROM HeldPayment WHERE status IN (?, ?) AND settle_at <= ? ORDER BY settle_at, id",
+		entities.HeldPaymentStatusHeld,
+		entities.HeldPaymentStatusReleasing,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, payment := range payments {
+		payment.SetExists()
+	}
+	return payments, nil
+}
+
+// GetHeldPayments returns all payments still held ordered by the end of
+// settlement window
+func (r Repository) GetHeldPayments(ctx context.Context) ([]*entities.HeldPayment, error) {
+	payments := []*entities.HeldPayment{}
+
+	err := r.selectRaw(ctx,
+		&payments,
+		"SELECT * FROM HeldPayment WHERE status = ? ORDER BY settle_at, id",
+		entities.HeldPaymentStatusHeld,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, payment := range payments {
+		payment.SetExists()
+	}
+	return payments, nil
+}
+
+// UpdateHeldPaymentStatus changes status of a held payment only if it is still
+// held or pending approval. Returns false if payment has been released or
+// cancelled in the meantime. Encrypted seed of a cancelled payment is removed.
+func (r Repository) UpdateHeldPaymentStatus(ctx context.Context, payment *entities.HeldPayment, status string, now time.Time) (bool, error) {
+	var cancelledAt *time.Time
+	encryptedSeed := payment.EncryptedSeed
+	if status == entities.HeldPaymentStatusCancelled {
+		cancelledAt = &now
+		encryptedSeed = nil
+	}
+
+	result, err := r.execRaw(ctx,
+		"UPDATE HeldPayment SET status = ?, cancelled_at = ?, encrypted_seed = ? WHERE id = ? AND status IN (?, ?)",
+		status,
+		cancelledAt,
+		encryptedSeed,
+		payment.ID,
+		entities.HeldPaymentStatusHeld,
+		entities.HeldPaymentStatusPending,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	payment.Status = status
+	payment.CancelledAt = cancelledAt
+	payment.EncryptedSeed = encryptedSeed
+	return true, nil
+}
+
+// ApproveHeldPayment holds a payment pending approval until settleAt. Returns
+// false if payment has been approved or cancelled in the meantime.
+func (r Repository) ApproveHeldPayment(ctx context.Context, payment *entities.HeldPayment, approvedBy string, now, settleAt time.Time) (bool, error) {
+	result, err := r.execRaw(ctx,
+		"UPDATE HeldPayment SET status = ?, approved_by = ?, approved_at = ?, settle_at = ? WHERE id = ? AND status = ?",
+		entities.HeldPaymentStatusHeld,
+		approvedBy,
+		now,
+		settleAt,
+		payment.ID,
+		entities.HeldPaymentStatusPending,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	payment.Status = entities.HeldPaymentStatusHeld
+	payment.ApprovedBy = &approvedBy
+	payment.ApprovedAt = &now
+	payment.SettleAt = settleAt
+	return true, nil
+}
+
+// GetComplianceRepairByOperationID returns the latest compliance repair of a received payment
+func (r Repository) GetComplianceRepairByOperationID(ctx context.Context, operationID string) (*entities.ComplianceRepair, error) {
+
+	var found entities.ComplianceRepair
+
+	err := r.getRaw(ctx,
+		&found,
+		"SELECT * FROM ComplianceRepair WHERE operation_id = ? ORDER BY id DESC LIMIT 1",
+		operationID,
+	)
+
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	found.SetExists()
+	return &found, nil
+}
+
+// GetSentTransactionByTransactionID returns sent transaction searching by transaction hash
+func (r Repository) GetSentTransactionByTransactionID(ctx context.Context, transactionID string) (*entities.SentTransaction, error) {
+
+	var found entities.SentTransaction
+
+	err := r.getRaw(ctx,
+		&found,
+		"SELECT * FROM SentTransaction WHERE transaction_id = ? ORDER BY id DESC LIMIT 1",
+		transactionID,
+	)
+
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	found.SetExists()
+	return &found, nil
+}
+
+// GetMemoRequiredDestination returns memo required destination searching by account ID
+func (r Repository) GetMemoRequiredDestination(ctx context.Context, accountID string) (*entities.MemoRequiredDestination, error) {
+
+	var found entities.MemoRequiredDestination
+
+	err := r.getRaw(ctx,
+		&found,
+		"SELECT * FROM MemoRequiredDestination WHERE account_id = ?",
+		accountID,
+	)
+
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	found.SetExists()
+	return &found, nil
+}
+
+// GetMemoRequiredDestinations returns all memo required destinations
+func (r Repository) GetMemoRequiredDestinations(ctx context.Context) ([]*entities.MemoRequiredDestination, error) {
+	destinations := []*entities.MemoRequiredDestination{}
+
+	err := r.selectRaw(ctx, &destinations, "SELECT * FROM MemoRequiredDestination ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, destination := range destinations {
+		destination.SetExists()
+	}
+	return destinations, nil
+}
+
+// GetFeatureFlag returns feature flag override for a given name and tenant
+func (r Repository) GetFeatureFlag(ctx context.Context, name, tenant string) (*entities.FeatureFlag, error) {
+
+	var found entities.FeatureFlag
+
+	err := r.getRaw(ctx,
+		&found,
+		"SELECT * FROM FeatureFlag WHERE name = ? AND tenant = ?",
+		name,
+		tenant,
+	)
+
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	found.SetExists()
+	return &found, nil
+}
+
+// GetFeatureFlags returns feature flag overrides. All overrides are returned
+// when name is empty.
+func (r Repository) GetFeatureFlags(ctx context.Context, name string) ([]*entities.FeatureFlag, error) {
+	flags := []*entities.FeatureFlag{}
+
+	var err error
+	if name == "" {
+		err = r.selectRaw(ctx, &flags, "SELECT * FROM FeatureFlag ORDER BY name, tenant")
+	} else {
+		err = r.selectRaw(ctx, &flags, "SELECT * FROM FeatureFlag WHERE name = ? ORDER BY tenant", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, flag := range flags {
+		flag.SetExists()
+	}
+	return flags, nil
+}
+
+// GetSecurityEventsToDeliver returns security events that have not been
+// delivered yet with next delivery attempt scheduled before now
+func (r Repository) GetSecurityEventsToDeliver(ctx context.Context, now time.Time, limit int) ([]*entities.SecurityEvent, error) {
+	events := []*entities.SecurityEvent{}
+
+	err := r.selectRaw(ctx,
+		&events,
+		"SELECT * FROM SecurityEvent WHERE delivered_at IS NULL AND next_attempt_at <= ? ORDER BY id LIMIT ?",
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		event.SetExists()
+	}
+	return events, nil
+}
+
+// GetAPIKey returns API key searching by key ID
+func (r Repository) GetAPIKey(ctx context.Context, keyID string) (*entities.APIKey, error) {
+
+	var found entities.APIKey
+
+	err := r.getRaw(ctx,
+		&found,
+		"SELECT * FROM APIKey WHERE key_id = ?",
+		keyID,
+	)
+
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	found.SetExists()
+	return &found, nil
+}
+
+// GetDailyAggregates returns daily aggregates ordered by day
+func (r Repository) GetDailyAggregates(ctx context.Context, filter DailyAggregatesFilter) ([]*entities.DailyAggregate, error) {
+	aggregates := []*entities.DailyAggregate{}
+
+	where, params := filter.where()
+	err := r.selectRaw(ctx,
+		&aggregates,
+		"SELECT * FROM DailyAggregate"+where+" ORDER BY day, tenant, direction, asset_code, asset_issuer",
+		params...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, aggregate := range aggregates {
+		aggregate.SetExists()
+	}
+	return aggregates, nil
+}
+
+// AddToDailyAggregate adds Count and Amount of aggregate to the row of its
+// day, tenant, direction and asset. The row is created when it does not
+// exist. Rows are updated in a single statement, so concurrent updates are
+// not lost.
+func (r Repository) AddToDailyAggregate(ctx context.Context, aggregate *entities.DailyAggregate) error {
+	update := func() (bool, error) {
+		result, err := r.execRaw(ctx,
+			"UPDATE DailyAggregate SET count = count + ?, amount = amount + ? WHERE day = ? AND tenant = ? AND direction = ? AND asset_code = ? AND asset_issuer = ?",
+			aggregate.Count,
+			aggregate.Amount,
+			aggregate.Day,
+			aggregate.Tenant,
+			aggregate.Direction,
+			aggregate.AssetCode,
+			aggregate.AssetIssuer,
+		)
+		if err != nil {
+			return false, err
+		}
+		affected, err := result.RowsAffected()
+		return affected > 0, err
+	}
+
+	updated, err := update()
+	if err != nil || updated {
+		return err
+	}
+
+	_, err = r.execRaw(ctx,
+		"INSERT INTO DailyAggregate (day, tenant, direction, asset_code, asset_issuer, count, amount) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		aggregate.Day,
+		aggregate.Tenant,
+		aggregate.Direction,
+		aggregate.AssetCode,
+		aggregate.AssetIssuer,
+		aggregate.Count,
+		aggregate.Amount,
+	)
+	if err == nil {
+		return nil
+	}
+
+	// The row has been inserted by a concurrent update in the meantime
+	updated, updateErr := update()
+	if updateErr != nil || !updated {
+		return errors.Wrap(err, "Error inserting DailyAggregate")
+	}
+	return nil
+}
+
+// GetFederationSnapshots returns snapshots in order of recording
+func (r Repository) GetFederationSnapshots(ctx context.Context, filter FederationSnapshotsFilter) ([]*entities.FederationSnapshot, error) {
+	snapshots := []*entities.FederationSnapshot{}
+
+	where, params := filter.where()
+	err := r.selectRaw(ctx, &snapshots, "SELECT * FROM FederationSnapshot"+where+" ORDER BY id", params...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range snapshots {
+		snapshot.SetExists()
+	}
+	return snapshots, nil
+}
+
+// GetReceivedPaymentEvents returns at most limit events with IDs greater than
+// afterID in order of recording
+func (r Repository) GetReceivedPaymentEvents(ctx context.Context, afterID int64, limit int) ([]*entities.ReceivedPaymentEvent, error) {
+	events := []*entities.ReceivedPaymentEvent{}
+
+	err := r.selectRaw(ctx, &events, "SELECT * FROM ReceivedPaymentEvent WHERE id > ? ORDER BY id LIMIT ?", afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		event.SetExists()
+	}
+	return events, nil
+}
+
+// GetLastReceivedPaymentEventID returns ID of the last recorded event or 0
+// when no events have been recorded
+func (r Repository) GetLastReceivedPaymentEventID(ctx context.Context) (int64, error) {
+	var event entities.ReceivedPaymentEvent
+	err := r.getRaw(ctx, &event, "SELECT * FROM ReceivedPaymentEvent ORDER BY id DESC LIMIT 1")
+
+	if noRows(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return *event.ID, nil
+}
+
+// GetPaymentLimitUsage returns the sum of amounts (in stroops) of payments of
+// an asset sent in a day to destination, or to all destinations when
+// destination is empty
+func (r Repository) GetPaymentLimitUsage(ctx context.Context, day, assetCode, assetIssuer, destination string) (int64, error) {
+	var usage entities.PaymentLimitUsage
+	err := r.getRaw(ctx,
+		&usage,
+		"SELECT * FROM PaymentLimitUsage WHERE day = ? AND asset_code = ? AND asset_issuer = ? AND destination = ?",
+		day,
+		assetCode,
+		assetIssuer,
+		destination,
+	)
+
+	if noRows(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return usage.Amount, nil
+}
+
+// AddToPaymentLimitUsage adds Amount of usage to the row of its day, asset
+// and destination. The row is created when it does not exist. Like
+// AddToDailyAggregate, rows are updated in a single statement, so concurrent
+// updates are not lost.
+func (r Repository) AddToPaymentLimitUsage(ctx context.Context, usage *entities.PaymentLimitUsage) error {
+	update := func() (bool, error) {
+		result, err := r.execRaw(ctx,
+			"UPDATE PaymentLimitUsage SET amount = amount + ? WHERE day = ? AND asset_code = ? AND asset_issuer = ? AND destination = ?",
+			usage.Amount,
+			usage.Day,
+			usage.AssetCode,
+			usage.AssetIssuer,
+			usage.Destination,
+		)
+		if err != nil {
+			return false, err
+		}
+		affected, err := result.RowsAffected()
+		return affected > 0, err
+	}
+
+	updated, err := update()
+	if err != nil || updated {
+		return err
+	}
+
+	_, err = r.execRaw(ctx,
+		"INSERT INTO PaymentLimitUsage (day, asset_code, asset_issuer, destination, amount) VALUES (?, ?, ?, ?, ?)",
+		usage.Day,
+		usage.AssetCode,
+		usage.AssetIssuer,
+		usage.Destination,
+		usage.Amount,
+	)
+	if err == nil {
+		return nil
+	}
+
+	// The row has been inserted by a concurrent update in the meantime
+	updated, updateErr := update()
+	if updateErr != nil || !updated {
+		return errors.Wrap(err, "Error inserting PaymentLimitUsage")
+	}
+	return nil
+}
+
+// GetPaymentVelocity returns the number of payments matching filter and the
+// sum of their amounts in stroops. The sum is meaningful for a single asset
+// only.
+func (r Repository) GetPaymentVelocity(ctx context.Context, filter PaymentVelocityFilter) (count, amount int64, err error) {
+	var velocity struct {
+		Count  int64 `db:"count"`
+		Amount int64 `db:"amount"`
+	}
+
+	where, params := filter.where()
+	err = r.getRaw(ctx, &velocity, "SELECT COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount FROM PaymentVelocity"+where, params...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return velocity.Count, velocity.Amount, nil
+}
+
+// DeletePaymentVelocityBefore removes payments created before the given time,
+// they are outside of all velocity windows. Returns the number of removed
+// rows.
+func (r Repository) DeletePaymentVelocityBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.execRaw(ctx, "DELETE FROM PaymentVelocity WHERE created_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetLastComplianceAuditEntry returns the entry with the greatest sequence
+// number or nil when the audit log is empty
+func (r Repository) GetLastComplianceAuditEntry(ctx context.Context) (*entities.ComplianceAuditEntry, error) {
+	var entry entities.ComplianceAuditEntry
+	err := r.getRaw(ctx, &entry, "SELECT * FROM ComplianceAudit ORDER BY sequence DESC LIMIT 1")
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	entry.SetExists()
+	return &entry, nil
+}
+
+// GetComplianceAuditEntries returns at most limit entries matching filter in
+// order of sequence numbers
+func (r Repository) GetComplianceAuditEntries(ctx context.Context, filter ComplianceAuditFilter, limit int) ([]*entities.ComplianceAuditEntry, error) {
+	entries := []*entities.ComplianceAuditEntry{}
+
+	where, params := filter.where()
+	params = append(params, limit)
+	err := r.selectRaw(ctx, &entries, "SELECT * FROM ComplianceAudit"+where+" ORDER BY sequence LIMIT ?", params...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		entry.SetExists()
+	}
+	return entries, nil
+}
+
+// GetAttachmentByMemoHash returns an attachment by hex encoded memo hash or
+// nil when it's not found
+func (r Repository) GetAttachmentByMemoHash(ctx context.Context, memoHash string) (*entities.Attachment, error) {
+	var attachment entities.Attachment
+	err := r.getRaw(ctx, &attachment, "SELECT * FROM Attachment WHERE memo_hash = ?", memoHash)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.SetExists()
+	return &attachment, nil
+}
+
+// GetExpectedPayment returns an expected payment by the ID given by the
+// caller or nil when it's not found
+func (r Repository) GetExpectedPayment(ctx context.Context, expectedID string) (*entities.ExpectedPayment, error) {
+	var expectedPayment entities.ExpectedPayment
+	err := r.getRaw(ctx, &expectedPayment, "SELECT * FROM ExpectedPayment WHERE expected_id = ?", expectedID)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	expectedPayment.SetExists()
+	return &expectedPayment, nil
+}
+
+// GetOpenExpectedPayment returns the oldest pending or partially paid
+// expected payment of the given asset and memo or nil when there is none
+func (r Repository) GetOpenExpectedPayment(ctx context.Context, assetCode, assetIssuer, memoType, memo string) (*entities.ExpectedPayment, error) {
+	var expectedPayment entities.ExpectedPayment
+	err := r.getRaw(
+		ctx,
+		&expectedPayment,
+		"SELECT * FROM ExpectedPayment WHERE memo_type = ? AND memo = ? AND asset_code = ? AND asset_issuer = ? AND status IN (?, ?) ORDER BY id LIMIT 1",
+		memoType, memo, assetCode, assetIssuer,
+		entities.ExpectedPaymentStatusPending, entities.ExpectedPaymentStatusPartiallyPaid,
+	)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	expectedPayment.SetExists()
+	return &expectedPayment, nil
+}
+
+// GetPaymentMatch returns the match of a received payment or nil when the
+// payment has not been matched
+func (r Repository) GetPaymentMatch(ctx context.Context, operationID string) (*entities.PaymentMatch, error) {
+	var match entities.PaymentMatch
+	err := r.getRaw(ctx, &match, "SELECT * FROM PaymentMatch WHERE operation_id = ?", operationID)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	match.SetExists()
+	return &match, nil
+}
+
+// GetPaymentMatches returns payments matched with an expected payment in
+// order they were matched
+func (r Repository) GetPaymentMatches(ctx context.Context, expectedID string) ([]*entities.PaymentMatch, error) {
+	return r.getPaymentMatches(ctx, "SELECT * FROM PaymentMatch WHERE expected_id = ? ORDER BY id", expectedID)
+}
+
+// GetUnmatchedPayments returns at most limit received payments that did not
+// match any expected payment, the oldest first
+func (r Repository) GetUnmatchedPayments(ctx context.Context, limit int) ([]*entities.PaymentMatch, error) {
+	return r.getPaymentMatches(ctx, "SELECT * FROM PaymentMatch WHERE status = ? ORDER BY id LIMIT ?", entities.PaymentMatchStatusUnmatched, limit)
+}
+
+func (r Repository) getPaymentMatches(ctx context.Context, query string, params ...interface{}) ([]*entities.PaymentMatch, error) {
+	matches := []*entities.PaymentMatch{}
+	err := r.selectRaw(ctx, &matches, query, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, match := range matches {
+		match.SetExists()
+	}
+	return matches, nil
+}
+
+// getLastReceivedPayment returns the last received payment streamed from
+// Horizon (or stellar-core) or, when contractEvents is true, from soroban-rpc.
+// Paging tokens of contract events are event IDs containing "-".
+func (r Repository) getLastReceivedPayment(ctx context.Context, contractEvents bool) (*entities.ReceivedPayment, error) {
+	var receivedPayment entities.ReceivedPayment
+	// DO NOT use `processed_at` as payment can be reprocessed. Reprocessing will update `processed_at`
+	// value but not `id`.
+	query := "SELECT * FROM ReceivedPayment WHERE paging_token NOT LIKE '%-%' ORDER BY id DESC LIMIT 1"
+	if contractEvents {
+		query = "SELECT * FROM ReceivedPayment WHERE paging_token LIKE '%-%' ORDER BY id DESC LIMIT 1"
+	}
+	err := r.getRaw(ctx, &receivedPayment, query)
+
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	receivedPayment.SetExists()
+	return &receivedPayment, nil
+}
+
+// prepare returns a prepared statement of a query
+func (r Repository) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	r.statements.Lock()
+	defer r.statements.Unlock()
+
+	if stmt, ok := r.statements.prepared[query]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := r.db.PrepareContext(ctx, r.db.Rebind(query))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error preparing query")
+	}
+
+	r.statements.prepared[query] = stmt
+	return stmt, nil
+}
+
+// getRaw loads the first row to dest, sql.ErrNoRows is returned when there are no rows
+func (r Repository) getRaw(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	err := r.getPrimary(ctx, dest, query, args...)
+	if r.verifyReads() {
+		if err == nil {
+			r.dualWrite.verifySecondary(ctx, dest, query, args...)
+		} else if err == sql.ErrNoRows {
+			r.dualWrite.verifySecondary(ctx, nil, query, args...)
+		}
+	}
+	return err
+}
+
+func (r Repository) getPrimary(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	rows, err := r.queryRaw(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	return (&sqlx.Rows{Rows: rows, Mapper: r.db.Mapper}).StructScan(dest)
+}
+
+// selectRaw loads all rows to dest slice
+func (r Repository) selectRaw(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	rows, err := r.queryRaw(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	err = sqlx.StructScan(&sqlx.Rows{Rows: rows, Mapper: r.db.Mapper}, dest)
+	if err == nil && r.verifyReads() {
+		r.dualWrite.verifySecondary(ctx, dest, query, args...)
+	}
+	return err
+}
+
+func (r Repository) execRaw(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := r.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := stmt.ExecContext(ctx, args...)
+	if err == nil && r.dualWrite != nil {
+		r.dualWrite.execSecondary(ctx, query, args...)
+	}
+	return result, err
+}
+
+func (r Repository) verifyReads() bool {
+	return r.dualWrite != nil && r.dualWrite.VerifyReads
+}
+
+func (r Repository) queryRaw(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := r.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+func noRows(err error) bool {
+	return errors.Cause(err) == sql.ErrNoRows
+}
+
+// GetNamedDestination returns named destination searching by name
+func (r Repository) GetNamedDestination(ctx context.Context, name string) (*entities.NamedDestination, error) {
+	var found entities.NamedDestination
+
+	err := r.getRaw(ctx,
+		&found,
+		"SELECT * FROM NamedDestination WHERE name = ?",
+		name,
+	)
+
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	found.SetExists()
+	return &found, nil
+}
+
+// GetNamedDestinations returns all named destinations ordered by name
+func (r Repository) GetNamedDestinations(ctx context.Context) ([]*entities.NamedDestination, error) {
+	destinations := []*entities.NamedDestination{}
+
+	err := r.selectRaw(ctx, &destinations, "SELECT * FROM NamedDestination ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, destination := range destinations {
+		destination.SetExists()
+	}
+	return destinations, nil
+}
+
+// GetRefund returns the refund of a received payment or nil when the payment
+// has not been refunded
+func (r Repository) GetRefund(ctx context.Context, operationID string) (*entities.Refund, error) {
+	var refund entities.Refund
+	err := r.getRaw(ctx, &refund, "SELECT * FROM Refund WHERE operation_id = ?", operationID)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	refund.SetExists()
+	return &refund, nil
+}
+
+// GetFailedCallback returns the failed receive callback of a received payment
+// or nil when its callback has never failed
+func (r Repository) GetFailedCallback(ctx context.Context, operationID string) (*entities.FailedCallback, error) {
+	var failedCallback entities.FailedCallback
+	err := r.getRaw(ctx, &failedCallback, "SELECT * FROM FailedCallback WHERE operation_id = ?", operationID)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	failedCallback.SetExists()
+	return &failedCallback, nil
+}
+
+// GetFailedCallbacks returns at most limit failed receive callbacks with
+// status, oldest first
+func (r Repository) GetFailedCallbacks(ctx context.Context, status string, limit int) ([]*entities.FailedCallback, error) {
+	failedCallbacks := []*entities.FailedCallback{}
+	err := r.selectRaw(ctx, &failedCallbacks, "SELECT * FROM FailedCallback WHERE status = ? ORDER BY id LIMIT ?", status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, failedCallback := range failedCallbacks {
+		failedCallback.SetExists()
+	}
+	return failedCallbacks, nil
+}
+
+// GetOfflineTransaction returns the offline transaction by the ID sent by the
+// client or nil when it does not exist
+func (r Repository) GetOfflineTransaction(ctx context.Context, offlineID string) (*entities.OfflineTransaction, error) {
+	var offlineTransaction entities.OfflineTransaction
+	err := r.getRaw(ctx, &offlineTransaction, "SELECT * FROM OfflineTransaction WHERE offline_id = ?", offlineID)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	offlineTransaction.SetExists()
+	return &offlineTransaction, nil
+}
+
+// GetQuote returns the quote by ID or nil when it does not exist
+func (r Repository) GetQuote(ctx context.Context, quoteID string) (*entities.Quote, error) {
+	var quote entities.Quote
+	err := r.getRaw(ctx, &quote, "SELECT * FROM Quote WHERE quote_id = ?", quoteID)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	quote.SetExists()
+	return &quote, nil
+}
+
+// GetFiatValue returns the fiat value of a payment by direction and reference
+// or nil when it does not exist
+func (r Repository) GetFiatValue(ctx context.Context, direction, reference string) (*entities.FiatValue, error) {
+	var value entities.FiatValue
+	err := r.getRaw(ctx, &value, "SELECT * FROM FiatValue WHERE direction = ? AND reference = ?", direction, reference)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	value.SetExists()
+	return &value, nil
+}
+
+// GetWebhook returns the webhook with webhookID or nil when it does not exist
+func (r Repository) GetWebhook(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+	var webhook entities.Webhook
+	err := r.getRaw(ctx, &webhook, "SELECT * FROM Webhook WHERE webhook_id = ?", webhookID)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.SetExists()
+	return &webhook, nil
+}
+
+// GetWebhooks returns all webhooks ordered by creation time
+func (r Repository) GetWebhooks(ctx context.Context) ([]*entities.Webhook, error) {
+	webhooks := []*entities.Webhook{}
+
+	err := r.selectRaw(ctx, &webhooks, "SELECT * FROM Webhook ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhooks {
+		webhook.SetExists()
+	}
+	return webhooks, nil
+}
+
+// GetMemoReference returns the reference of a hash memo or nil when it does
+// not exist
+func (r Repository) GetMemoReference(ctx context.Context, memo string) (*entities.MemoReference, error) {
+	var memoReference entities.MemoReference
+	err := r.getRaw(ctx, &memoReference, "SELECT * FROM MemoReference WHERE memo = ?", memo)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	memoReference.SetExists()
+	return &memoReference, nil
+}
+
+// AcquireLeaderLease acquires lease name for holder until expiresAt when it
+// does not exist or has expired, or extends it when holder already holds it.
+// Returns the lease after the call: holder is the leader only if it is the
+// holder of the returned lease.
+func (r Repository) AcquireLeaderLease(ctx context.Context, name, holder string, now, expiresAt time.Time) (*entities.LeaderLease, error) {
+	// acquired_at is set before holder: MySQL evaluates assignments in order
+	result, err := r.execRaw(ctx,
+		"UPDATE LeaderLease SET acquired_at = CASE WHEN holder = ? THEN acquired_at ELSE ? END, holder = ?, expires_at = ? WHERE name = ? AND (holder = ? OR expires_at < ?)",
+		holder,
+		now,
+		holder,
+		expiresAt,
+		name,
+		holder,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if affected == 0 {
+		// The lease does not exist yet or is held by another replica. When
+		// it exists (or another replica inserted it first) the insert fails
+		// on the unique name and the current lease is loaded below.
+		r.execRaw(ctx,
+			"INSERT INTO LeaderLease (name, holder, acquired_at, expires_at) VALUES (?, ?, ?, ?)",
+			name,
+			holder,
+			now,
+			expiresAt,
+		)
+	}
+
+	var lease entities.LeaderLease
+	err = r.getRaw(ctx, &lease, "SELECT * FROM LeaderLease WHERE name = ?", name)
+	if err != nil {
+		return nil, err
+	}
+
+	lease.SetExists()
+	return &lease, nil
+}
+
+// ReleaseLeaderLease expires lease name if it is held by holder, so other
+// replicas can acquire it without waiting for the lease duration
+func (r Repository) ReleaseLeaderLease(ctx context.Context, name, holder string, now time.Time) error {
+	_, err := r.execRaw(ctx, "UPDATE LeaderLease SET expires_at = ? WHERE name = ? AND holder = ?", now, name, holder)
+	return err
+}
+
+// GetSEP24Transaction returns a SEP-24 transaction by its ID or nil when it's
+// not found
+func (r Repository) GetSEP24Transaction(ctx context.Context, transactionID string) (*entities.SEP24Transaction, error) {
+	return r.getSEP24Transaction(ctx, "SELECT * FROM SEP24Transaction WHERE transaction_id = ?", transactionID)
+}
+
+// GetSEP24TransactionByStellarID returns a SEP-24 transaction by the hash of
+// its Stellar transaction or nil when it's not found
+func (r Repository) GetSEP24TransactionByStellarID(ctx context.Context, stellarTransactionID string) (*entities.SEP24Transaction, error) {
+	return r.getSEP24Transaction(ctx, "SELECT * FROM SEP24Transaction WHERE stellar_transaction_id = ? ORDER BY id LIMIT 1", stellarTransactionID)
+}
+
+// GetSEP24TransactionByExternalID returns a SEP-24 transaction by the ID of
+// its transfer outside of Stellar network or nil when it's not found
+func (r Repository) GetSEP24TransactionByExternalID(ctx context.Context, externalTransactionID string) (*entities.SEP24Transaction, error) {
+	return r.getSEP24Transaction(ctx, "SELECT * FROM SEP24Transaction WHERE external_transaction_id = ? ORDER BY id LIMIT 1", externalTransactionID)
+}
+
+// GetSEP24Transactions returns at most limit SEP-24 transactions matching
+// filter, the most recently started first
+func (r Repository) GetSEP24Transactions(ctx context.Context, filter SEP24Filter, limit int) ([]*entities.SEP24Transaction, error) {
+	transactions := []*entities.SEP24Transaction{}
+
+	where, params := filter.where()
+	params = append(params, limit)
+	err := r.selectRaw(ctx, &transactions, "SELECT * FROM SEP24Transaction"+where+" ORDER BY id DESC LIMIT ?", params...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range transactions {
+		transaction.SetExists()
+	}
+	return transactions, nil
+}
+
+// GetPendingSEP24Withdrawal returns the oldest withdrawal waiting for the
+// user's payment of the asset with the memo to anchorAccount or nil when there
+// is none
+func (r Repository) GetPendingSEP24Withdrawal(ctx context.Context, anchorAccount, assetCode, assetIssuer, memoType, memo string) (*entities.SEP24Transaction, error) {
+	return r.getSEP24Transaction(
+		ctx,
+		"SELECT * FROM SEP24Transaction WHERE memo_type = ? AND memo = ? AND status = ? AND kind = ? AND anchor_account = ? AND asset_code = ? AND asset_issuer = ? ORDER BY id LIMIT 1",
+		memoType, memo, entities.SEP24StatusPendingUserTransferStart, entities.SEP24KindWithdrawal,
+		anchorAccount, assetCode, assetIssuer,
+	)
+}
+
+func (r Repository) getSEP24Transaction(ctx context.Context, query string, params ...interface{}) (*entities.SEP24Transaction, error) {
+	var transaction entities.SEP24Transaction
+	err := r.getRaw(ctx, &transaction, query, params...)
+	if noRows(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.SetExists()
+	return &transaction, nil
 }
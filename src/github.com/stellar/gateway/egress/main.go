@@ -0,0 +1,276 @@
+// Package egress routes outbound HTTP requests of the compliance server
+// through HTTP(S) proxies and custom trust stores configured per destination
+// domain (corporate networks often allow egress only via a proxy), with
+// connect and read timeouts per call type.
+package egress
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stellar/gateway/tlspolicy"
+)
+
+// Direct is the value of Domain.Proxy sending requests without a proxy
+const Direct = "direct"
+
+// Types of outbound calls
+const (
+	// CallAuth are requests to auth servers of other compliance servers
+	CallAuth = "auth"
+	// CallFederation are requests to federation servers
+	CallFederation = "federation"
+	// CallStellarToml are requests of stellar.toml files
+	CallStellarToml = "stellar_toml"
+	// CallCallbacks are requests to callbacks and the sanctions API
+	CallCallbacks = "callbacks"
+)
+
+// CallTypes are all types of outbound calls
+var CallTypes = []string{CallAuth, CallFederation, CallStellarToml, CallCallbacks}
+
+// Config contains values of `egress` config group
+type Config struct {
+	// Proxy is the URL of the proxy of all requests, ex.
+	// http://proxy.example.com:3128. HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// environment variables are used when empty.
+	Proxy string
+	// CAFile is a PEM file of CA certificates trusted in addition to system
+	// roots, ex. the CA of a TLS inspecting proxy
+	CAFile  string `mapstructure:"ca_file"`
+	Domains []Domain
+	// Timeouts by call type
+	Timeouts map[string]Timeouts
+}
+
+// Domain contains egress settings of a destination domain
+type Domain struct {
+	// Domain is a host name or a wildcard matching its subdomains, ex.
+	// "*.example.com"
+	Domain string
+	// Proxy replaces Config.Proxy for the domain, "direct" sends requests
+	// without a proxy
+	Proxy string
+	// CAFile is a PEM file of CA certificates trusted for the domain in
+	// addition to system roots and Config.CAFile
+	CAFile string `mapstructure:"ca_file"`
+}
+
+// Timeouts contains timeouts of a call type
+type Timeouts struct {
+	// Connect is the max time of establishing a connection including TLS
+	// handshake, ex. "5s"
+	Connect string
+	// Read is the max time waiting for response headers after the request
+	// is sent, ex. "30s"
+	Read string
+}
+
+// Enabled returns true when any of the params is set
+func (c Config) Enabled() bool {
+	return c.Proxy != "" || c.CAFile != "" || len(c.Domains) > 0 || len(c.Timeouts) > 0
+}
+
+// Validate returns an error when any of the params is invalid
+func (c Config) Validate() error {
+	if c.Proxy != "" {
+		if err := validateProxy(c.Proxy); err != nil {
+			return errors.New("Invalid egress.proxy param")
+		}
+	}
+
+	if c.CAFile != "" {
+		if _, err := loadCAs(nil, c.CAFile); err != nil {
+			return fmt.Errorf("Cannot load egress.ca_file: %s", err)
+		}
+	}
+
+	for _, domain := range c.Domains {
+		if domain.Domain == "" {
+			return errors.New("egress.domains.domain param is required")
+		}
+
+		if domain.Proxy != "" && domain.Proxy != Direct {
+			if err := validateProxy(domain.Proxy); err != nil {
+				return fmt.Errorf("Invalid egress.domains.proxy param of %s", domain.Domain)
+			}
+		}
+
+		if domain.CAFile != "" {
+			if _, err := loadCAs(nil, domain.CAFile); err != nil {
+				return fmt.Errorf("Cannot load egress.domains.ca_file of %s: %s", domain.Domain, err)
+			}
+		}
+	}
+
+	for callType, timeouts := range c.Timeouts {
+		if !isCallType(callType) {
+			return fmt.Errorf("Unknown egress.timeouts call type: %s, must be one of: %s", callType, strings.Join(CallTypes, ", "))
+		}
+
+		for name, value := range map[string]string{"connect": timeouts.Connect, "read": timeouts.Read} {
+			if value == "" {
+				continue
+			}
+			if duration, err := time.ParseDuration(value); err != nil || duration <= 0 {
+				return fmt.Errorf("Cannot parse egress.timeouts.%s.%s param", callType, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Client returns http.Client of callType using proxies, trust stores and
+// timeouts of the config and TLS policy. timeout limits the whole request
+// unless connect and read timeouts of callType are longer. Values are checked
+// in Validate.
+func (c Config) Client(callType string, policy tlspolicy.Config, timeout time.Duration) *http.Client {
+	timeouts := c.Timeouts[callType]
+	connect, _ := time.ParseDuration(timeouts.Connect)
+	read, _ := time.ParseDuration(timeouts.Read)
+	if connect+read > timeout {
+		timeout = connect + read
+	}
+
+	// System roots are used when there are no custom CAs
+	roots, _ := loadCAs(nil, c.CAFile)
+	r := &router{
+		domains:  map[string]http.RoundTripper{},
+		fallback: policy.Wrap(c.transport(connect, read), roots),
+	}
+
+	for _, domain := range c.Domains {
+		if domain.CAFile == "" {
+			continue
+		}
+		domainRoots, _ := loadCAs(roots, domain.CAFile)
+		r.domains[strings.ToLower(domain.Domain)] = policy.Wrap(c.transport(connect, read), domainRoots)
+	}
+
+	return &http.Client{Transport: r, Timeout: timeout}
+}
+
+// transport returns a new http.Transport using the proxy of the destination
+// domain and timeouts
+func (c Config) transport(connect, read time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = c.proxy
+	if connect > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: connect, KeepAlive: 30 * time.Second}).DialContext
+		transport.TLSHandshakeTimeout = connect
+	}
+	if read > 0 {
+		transport.ResponseHeaderTimeout = read
+	}
+	return transport
+}
+
+// proxy returns the proxy URL of req, nil when it's sent directly
+func (c Config) proxy(req *http.Request) (*url.URL, error) {
+	proxy := c.Proxy
+	if domain, ok := c.domain(req.URL.Hostname()); ok && domain.Proxy != "" {
+		proxy = domain.Proxy
+	}
+
+	switch proxy {
+	case "":
+		return http.ProxyFromEnvironment(req)
+	case Direct:
+		return nil, nil
+	default:
+		return url.Parse(proxy)
+	}
+}
+
+// domain returns settings of host: exact domain first, then wildcards
+func (c Config) domain(host string) (Domain, bool) {
+	for _, pattern := range patterns(host) {
+		for _, domain := range c.Domains {
+			if strings.ToLower(domain.Domain) == pattern {
+				return domain, true
+			}
+		}
+	}
+	return Domain{}, false
+}
+
+// router sends requests using the transport of the destination domain
+type router struct {
+	domains  map[string]http.RoundTripper
+	fallback http.RoundTripper
+}
+
+func (r *router) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, pattern := range patterns(req.URL.Hostname()) {
+		if transport, ok := r.domains[pattern]; ok {
+			return transport.RoundTrip(req)
+		}
+	}
+	return r.fallback.RoundTrip(req)
+}
+
+// patterns returns host and wildcards matching it, most specific first
+func patterns(host string) []string {
+	host = strings.ToLower(host)
+	result := []string{host}
+	for i := strings.Index(host, "."); i >= 0; i = strings.Index(host, ".") {
+		host = host[i+1:]
+		result = append(result, "*."+host)
+	}
+	return result
+}
+
+func validateProxy(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("Proxy must be an http or https URL")
+	}
+	return nil
+}
+
+// loadCAs returns a copy of pool (system roots when nil) with certificates of
+// file added, nil when file is empty and pool is nil
+func loadCAs(pool *x509.CertPool, file string) (*x509.CertPool, error) {
+	if file == "" {
+		return pool, nil
+	}
+
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if pool == nil {
+		pool, err = x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+	} else {
+		pool = pool.Clone()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("No certificates found in " + file)
+	}
+	return pool, nil
+}
+
+func isCallType(callType string) bool {
+	for _, known := range CallTypes {
+		if known == callType {
+			return true
+		}
+	}
+	return false
+}
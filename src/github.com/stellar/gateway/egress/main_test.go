@@ -0,0 +1,94 @@
+package egress
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/tlspolicy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	assert.False(t, Config{}.Enabled())
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{
+		Proxy:    "http://proxy.example.com:3128",
+		Domains:  []Domain{{Domain: "*.internal.example.com", Proxy: Direct}},
+		Timeouts: map[string]Timeouts{CallAuth: {Connect: "5s", Read: "30s"}},
+	}.Validate())
+
+	assert.EqualError(t, Config{Proxy: "proxy.example.com"}.Validate(), "Invalid egress.proxy param")
+	assert.Error(t, Config{CAFile: "missing.pem"}.Validate())
+	assert.Error(t, Config{Domains: []Domain{{Proxy: Direct}}}.Validate())
+	assert.Error(t, Config{Domains: []Domain{{Domain: "example.com", Proxy: "ftp://proxy"}}}.Validate())
+	assert.Error(t, Config{Timeouts: map[string]Timeouts{"horizon": {Connect: "5s"}}}.Validate())
+	assert.EqualError(t, Config{Timeouts: map[string]Timeouts{CallAuth: {Read: "soon"}}}.Validate(), "Cannot parse egress.timeouts.auth.read param")
+}
+
+func TestProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer direct.Close()
+
+	config := Config{
+		Proxy:   proxy.URL,
+		Domains: []Domain{{Domain: "127.0.0.1", Proxy: Direct}},
+	}
+	client := config.Client(CallFederation, tlspolicy.Config{}, time.Second)
+
+	resp, err := client.Get("http://example.com/.well-known/stellar.toml")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"http://example.com/.well-known/stellar.toml"}, proxied)
+
+	resp, err = client.Get(direct.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Len(t, proxied, 1)
+}
+
+func TestCAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600))
+
+	get := func(config Config) error {
+		resp, err := config.Client(CallCallbacks, tlspolicy.Config{}, time.Second).Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// Certificate of the server is not trusted without the CA file
+	assert.Error(t, get(Config{Domains: []Domain{{Domain: "127.0.0.1", Proxy: Direct}}}))
+	assert.NoError(t, get(Config{Domains: []Domain{{Domain: "127.0.0.1", Proxy: Direct, CAFile: caFile}}}))
+	assert.NoError(t, get(Config{CAFile: caFile, Domains: []Domain{{Domain: "127.0.0.1", Proxy: Direct}}}))
+}
+
+func TestClientTimeout(t *testing.T) {
+	config := Config{Timeouts: map[string]Timeouts{CallAuth: {Connect: "5s", Read: "30s"}}}
+	assert.Equal(t, 35*time.Second, config.Client(CallAuth, tlspolicy.Config{}, 10*time.Second).Timeout)
+	assert.Equal(t, 10*time.Second, config.Client(CallFederation, tlspolicy.Config{}, 10*time.Second).Timeout)
+
+	transport := config.Client(CallAuth, tlspolicy.Config{}, 0).Transport.(*router).fallback.(*http.Transport)
+	assert.Equal(t, 5*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 30*time.Second, transport.ResponseHeaderTimeout)
+}
+
+func TestPatterns(t *testing.T) {
+	assert.Equal(t, []string{"api.example.com", "*.example.com", "*.com"}, patterns("API.example.com"))
+}
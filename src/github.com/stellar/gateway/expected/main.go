@@ -0,0 +1,226 @@
+// Package expected matches received payments with expected payments
+// registered by callers of the bridge server (ex. an invoice of 100 USD to be
+// paid with memo 12345), so receivers don't have to rebuild reconciliation.
+//
+// A received payment matches the oldest pending or partially paid expected
+// payment with the same asset and memo. The amount received by an expected
+// payment is the sum of its matched payments, so it's matched, partially
+// paid or overpaid. Payments that don't match any expected payment are
+// unmatched and wait for a review, see Matcher.Assign.
+package expected
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/go/support/errors"
+)
+
+var matcherMetrics = struct {
+	matched   *metrics.Counter
+	unmatched *metrics.Counter
+}{
+	matched:   metrics.NewCounter("bridge_expected_payments_matched_total", "Number of received payments matched with expected payments."),
+	unmatched: metrics.NewCounter("bridge_expected_payments_unmatched_total", "Number of received payments that did not match any expected payment."),
+}
+
+// ErrNotOpen is returned by Assign when the expected payment is paid or
+// cancelled
+var ErrNotOpen = errors.New("Expected payment is not pending or partially paid")
+
+// ErrAlreadyMatched is returned by Assign when the payment has been matched
+var ErrAlreadyMatched = errors.New("Payment has been matched already")
+
+// Matcher matches received payments with expected payments. A nil *Matcher
+// does nothing, so it can be used when expected payments are disabled.
+type Matcher struct {
+	repository    db.RepositoryInterface
+	entityManager db.EntityManagerInterface
+	log           *logrus.Entry
+}
+
+// NewMatcher creates a new Matcher
+func NewMatcher(repository db.RepositoryInterface, entityManager db.EntityManagerInterface) *Matcher {
+	return &Matcher{
+		repository:    repository,
+		entityManager: entityManager,
+		log:           logrus.WithFields(logrus.Fields{"service": "ExpectedPayments"}),
+	}
+}
+
+// Status returns the status of an expected payment of amount after received
+// was received
+func Status(amount, received amounts.Amount) string {
+	switch {
+	case received == 0:
+		return entities.ExpectedPaymentStatusPending
+	case received < amount:
+		return entities.ExpectedPaymentStatusPartiallyPaid
+	case received > amount:
+		return entities.ExpectedPaymentStatusOverpaid
+	}
+	return entities.ExpectedPaymentStatusMatched
+}
+
+// Match matches a received payment. Memo (and amount of account_merge
+// operations) must be loaded. A payment matched before (ex. reprocessed)
+// returns its existing match. Returns nil when m is nil.
+func (m *Matcher) Match(ctx context.Context, payment horizon.PaymentResponse, now time.Time) (*entities.PaymentMatch, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	match, err := m.repository.GetPaymentMatch(ctx, payment.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting PaymentMatch")
+	}
+
+	if match != nil {
+		if match.ExpectedID != "" {
+			// Received amount may not have been updated when processing
+			// failed
+			_, err = m.updateExpectedPayment(ctx, match.ExpectedID, now)
+		}
+		return match, err
+	}
+
+	amount, err := amounts.ParseAmount(payment.Amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid payment amount")
+	}
+
+	assetCode := payment.AssetCode
+	if payment.AssetType == "native" || payment.Type == "account_merge" {
+		assetCode = "XLM"
+	}
+
+	match = &entities.PaymentMatch{
+		OperationID:   payment.ID,
+		TransactionID: payment.TransactionID,
+		From:          payment.From,
+		Amount:        amount,
+		AssetCode:     assetCode,
+		AssetIssuer:   payment.AssetIssuer,
+		MemoType:      payment.Memo.Type,
+		Memo:          payment.Memo.Value,
+		Status:        entities.PaymentMatchStatusUnmatched,
+		MatchedAt:     now,
+	}
+
+	var expectedPayment *entities.ExpectedPayment
+	if match.MemoType != "" && match.MemoType != "none" {
+		expectedPayment, err = m.repository.GetOpenExpectedPayment(ctx, match.AssetCode, match.AssetIssuer, match.MemoType, match.Memo)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error getting ExpectedPayment")
+		}
+	}
+
+	if expectedPayment == nil {
+		err = m.entityManager.Persist(match)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error persisting PaymentMatch")
+		}
+
+		matcherMetrics.unmatched.Inc()
+		m.log.WithFields(logrus.Fields{"operation_id": payment.ID, "memo": match.Memo}).Warn("Received payment does not match any expected payment")
+		return match, nil
+	}
+
+	err = m.assign(ctx, match, expectedPayment, now)
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// Assign matches an unmatched payment with an expected payment after a review
+// (ex. the sender forgot the memo). Asset of the payment is not checked.
+func (m *Matcher) Assign(ctx context.Context, match *entities.PaymentMatch, expectedPayment *entities.ExpectedPayment, now time.Time) error {
+	if match.Status != entities.PaymentMatchStatusUnmatched {
+		return ErrAlreadyMatched
+	}
+
+	if !expectedPayment.Open() {
+		return ErrNotOpen
+	}
+
+	return m.assign(ctx, match, expectedPayment, now)
+}
+
+// assign persists match of expectedPayment first and then updates the
+// received amount. When the update fails, it's retried by reprocessing.
+func (m *Matcher) assign(ctx context.Context, match *entities.PaymentMatch, expectedPayment *entities.ExpectedPayment, now time.Time) error {
+	received, err := amounts.Add(int64(expectedPayment.ReceivedAmount), int64(match.Amount))
+	if err != nil {
+		return err
+	}
+
+	match.ExpectedID = expectedPayment.ExpectedID
+	match.ReceivedAmount = amounts.Amount(received)
+	match.Status = Status(expectedPayment.Amount, match.ReceivedAmount)
+	match.MatchedAt = now
+	err = m.entityManager.Persist(match)
+	if err != nil {
+		return errors.Wrap(err, "Error persisting PaymentMatch")
+	}
+
+	_, err = m.updateExpectedPayment(ctx, expectedPayment.ExpectedID, now)
+	if err != nil {
+		return err
+	}
+
+	matcherMetrics.matched.Inc()
+	m.log.WithFields(logrus.Fields{
+		"operation_id":        match.OperationID,
+		"expected_payment_id": match.ExpectedID,
+		"status":              match.Status,
+	}).Info("Received payment matched expected payment")
+	return nil
+}
+
+// updateExpectedPayment sets the received amount and status of an expected
+// payment from its matched payments
+func (m *Matcher) updateExpectedPayment(ctx context.Context, expectedID string, now time.Time) (*entities.ExpectedPayment, error) {
+	expectedPayment, err := m.repository.GetExpectedPayment(ctx, expectedID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting ExpectedPayment")
+	}
+
+	if expectedPayment == nil {
+		return nil, errors.New("Expected payment " + expectedID + " not found")
+	}
+
+	matches, err := m.repository.GetPaymentMatches(ctx, expectedID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting PaymentMatches")
+	}
+
+	var received int64
+	for _, match := range matches {
+		received, err = amounts.Add(received, int64(match.Amount))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if expectedPayment.ReceivedAmount == amounts.Amount(received) {
+		return expectedPayment, nil
+	}
+
+	expectedPayment.ReceivedAmount = amounts.Amount(received)
+	if expectedPayment.Status != entities.ExpectedPaymentStatusCancelled {
+		expectedPayment.Status = Status(expectedPayment.Amount, expectedPayment.ReceivedAmount)
+	}
+	expectedPayment.UpdatedAt = now
+	err = m.entityManager.Persist(expectedPayment)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error persisting ExpectedPayment")
+	}
+	return expectedPayment, nil
+}
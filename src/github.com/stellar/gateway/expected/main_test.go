@@ -0,0 +1,120 @@
+package expected
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const issuer = "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"
+
+func payment(id, amount, memo string) horizon.PaymentResponse {
+	payment := horizon.PaymentResponse{
+		ID:            id,
+		Type:          "payment",
+		From:          "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE",
+		Amount:        amount,
+		AssetType:     "credit_alphanum4",
+		AssetCode:     "USD",
+		AssetIssuer:   issuer,
+		TransactionID: "tx" + id,
+	}
+	payment.Memo.Type = "id"
+	payment.Memo.Value = memo
+	return payment
+}
+
+func TestStatus(t *testing.T) {
+	amount := amounts.Amount(100)
+	assert.Equal(t, entities.ExpectedPaymentStatusPending, Status(amount, 0))
+	assert.Equal(t, entities.ExpectedPaymentStatusPartiallyPaid, Status(amount, 99))
+	assert.Equal(t, entities.ExpectedPaymentStatusMatched, Status(amount, 100))
+	assert.Equal(t, entities.ExpectedPaymentStatusOverpaid, Status(amount, 101))
+}
+
+func TestMatcher(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	matcher := NewMatcher(repository, entityManager)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	for _, expectedPayment := range []*entities.ExpectedPayment{
+		{ExpectedID: "invoice-1", Amount: amounts.Amount(amounts.MustParse("100")), AssetCode: "USD", AssetIssuer: issuer, MemoType: "id", Memo: "1", Status: entities.ExpectedPaymentStatusPending, CreatedAt: now, UpdatedAt: now},
+		{ExpectedID: "invoice-2", Amount: amounts.Amount(amounts.MustParse("10")), AssetCode: "USD", AssetIssuer: issuer, MemoType: "id", Memo: "2", Status: entities.ExpectedPaymentStatusPending, CreatedAt: now, UpdatedAt: now},
+	} {
+		require.NoError(t, entityManager.Persist(expectedPayment))
+	}
+
+	match, err := matcher.Match(ctx, payment("1", "60", "1"), now)
+	require.NoError(t, err)
+	assert.Equal(t, "invoice-1", match.ExpectedID)
+	assert.Equal(t, entities.ExpectedPaymentStatusPartiallyPaid, match.Status)
+	assert.Equal(t, "60.0000000", match.ReceivedAmount.String())
+
+	match, err = matcher.Match(ctx, payment("2", "40", "1"), now)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ExpectedPaymentStatusMatched, match.Status)
+
+	// Paid expected payments are not matched
+	match, err = matcher.Match(ctx, payment("3", "5", "1"), now)
+	require.NoError(t, err)
+	assert.Equal(t, entities.PaymentMatchStatusUnmatched, match.Status)
+	assert.Equal(t, "", match.ExpectedID)
+
+	// Reprocessed payments are not counted twice
+	match, err = matcher.Match(ctx, payment("2", "40", "1"), now)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ExpectedPaymentStatusMatched, match.Status)
+
+	expectedPayment, err := repository.GetExpectedPayment(ctx, "invoice-1")
+	require.NoError(t, err)
+	assert.Equal(t, entities.ExpectedPaymentStatusMatched, expectedPayment.Status)
+	assert.Equal(t, "100.0000000", expectedPayment.ReceivedAmount.String())
+
+	// Asset must match
+	other := payment("4", "20", "2")
+	other.AssetCode = "EUR"
+	match, err = matcher.Match(ctx, other, now)
+	require.NoError(t, err)
+	assert.Equal(t, entities.PaymentMatchStatusUnmatched, match.Status)
+
+	unmatched, err := repository.GetUnmatchedPayments(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, unmatched, 2)
+	assert.Equal(t, "3", unmatched[0].OperationID)
+
+	// Unmatched payments are matched after a review
+	expectedPayment, err = repository.GetExpectedPayment(ctx, "invoice-2")
+	require.NoError(t, err)
+	require.NoError(t, matcher.Assign(ctx, unmatched[1], expectedPayment, now))
+	assert.Equal(t, entities.ExpectedPaymentStatusOverpaid, unmatched[1].Status)
+	assert.Equal(t, ErrAlreadyMatched, matcher.Assign(ctx, unmatched[1], expectedPayment, now))
+
+	expectedPayment, err = repository.GetExpectedPayment(ctx, "invoice-1")
+	require.NoError(t, err)
+	assert.Equal(t, ErrNotOpen, matcher.Assign(ctx, unmatched[0], expectedPayment, now))
+
+	matches, err := repository.GetPaymentMatches(ctx, "invoice-2")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "4", matches[0].OperationID)
+
+	var nilMatcher *Matcher
+	match, err = nilMatcher.Match(ctx, payment("5", "1", "1"), now)
+	assert.NoError(t, err)
+	assert.Nil(t, match)
+}
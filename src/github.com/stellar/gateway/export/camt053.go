@@ -0,0 +1,197 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// camt053Document is a subset of camt.053.001.02 BankToCustomerStatement
+// message used to describe Stellar payments
+type camt053Document struct {
+	XMLName   xml.Name         `xml:"Document"`
+	Namespace string           `xml:"xmlns,attr"`
+	Statement camt053Statement `xml:"BkToCstmrStmt"`
+}
+
+type camt053Statement struct {
+	GroupHeader struct {
+		MessageID string `xml:"MsgId"`
+		CreatedAt string `xml:"CreDtTm"`
+	} `xml:"GrpHdr"`
+	Statement struct {
+		ID        string `xml:"Id"`
+		CreatedAt string `xml:"CreDtTm"`
+		Period    struct {
+			From string `xml:"FrDtTm"`
+			To   string `xml:"ToDtTm"`
+		} `xml:"FrToDt"`
+		Account struct {
+			ID string `xml:"Id>Othr>Id"`
+		} `xml:"Acct"`
+		Summary *camt053Summary `xml:"TxsSummry,omitempty"`
+		Entries []camt053Entry  `xml:"Ntry"`
+	} `xml:"Stmt"`
+}
+
+type camt053Summary struct {
+	Total   camt053Count `xml:"TtlNtries"`
+	Credits camt053Count `xml:"TtlCdtNtries"`
+	Debits  camt053Count `xml:"TtlDbtNtries"`
+	// Sums are reported per asset because amounts of different assets
+	// cannot be added
+	PerAsset []camt053AssetSummary `xml:"TtlNtriesPerBkTxCd"`
+}
+
+type camt053Count struct {
+	Number string `xml:"NbOfNtries"`
+}
+
+type camt053AssetSummary struct {
+	Number        string `xml:"NbOfNtries"`
+	NetAmount     string `xml:"TtlNetNtryAmt"`
+	CreditDebit   string `xml:"CdtDbtInd"`
+	TransactionCd string `xml:"BkTxCd>Prtry>Cd"`
+}
+
+type camt053Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camt053Entry struct {
+	Reference     string        `xml:"NtryRef"`
+	Amount        camt053Amount `xml:"Amt"`
+	CreditDebit   string        `xml:"CdtDbtInd"`
+	Status        string        `xml:"Sts"`
+	BookingDate   string        `xml:"BookgDt>DtTm"`
+	ValueDate     string        `xml:"ValDt>DtTm"`
+	ServicerRef   string        `xml:"AcctSvcrRef"`
+	TransactionCd string        `xml:"BkTxCd>Prtry>Cd"`
+	Details       struct {
+		EndToEndID string `xml:"Refs>EndToEndId"`
+		// Debtor is set for credit entries, Creditor for debit entries
+		Debtor   string `xml:"RltdPties>Dbtr>Nm,omitempty"`
+		Creditor string `xml:"RltdPties>Cdtr>Nm,omitempty"`
+		Memo     string `xml:"RmtInf>Ustrd,omitempty"`
+		Issuer   string `xml:"AddtlTxInf,omitempty"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+// WriteCAMT053 writes statement as ISO 20022 camt.053.001.02 XML message.
+// Stellar account ID is used as the account identifier, asset code as the
+// currency and amounts keep 7 fractional digits. Asset issuer is sent in
+// additional transaction information. Opening and closing balances are not
+// reported because the history of the account before the bridge server
+// started is unknown.
+func WriteCAMT053(w io.Writer, statement *Statement) error {
+	document := camt053Document{Namespace: camt053Namespace}
+	s := &document.Statement
+
+	s.GroupHeader.MessageID = statement.ID
+	s.GroupHeader.CreatedAt = formatTime(statement.CreatedAt)
+	s.Statement.ID = statement.ID
+	s.Statement.CreatedAt = formatTime(statement.CreatedAt)
+	s.Statement.Period.From = formatTime(statement.From)
+	s.Statement.Period.To = formatTime(statement.To)
+	s.Statement.Account.ID = statement.Account
+
+	credits, debits, sums, err := statement.Totals()
+	if err != nil {
+		return err
+	}
+
+	summary := &camt053Summary{
+		Total:   camt053Count{strconv.Itoa(credits + debits)},
+		Credits: camt053Count{strconv.Itoa(credits)},
+		Debits:  camt053Count{strconv.Itoa(debits)},
+	}
+
+	assets := make([]string, 0, len(sums))
+	for asset := range sums {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+
+	for _, asset := range assets {
+		count := 0
+		for _, entry := range statement.Entries {
+			if entry.AssetCode == asset {
+				count++
+			}
+		}
+
+		sum := sums[asset]
+		indicator := "CRDT"
+		if sum < 0 {
+			indicator = "DBIT"
+			sum = -sum
+		}
+
+		summary.PerAsset = append(summary.PerAsset, camt053AssetSummary{
+			Number:        strconv.Itoa(count),
+			NetAmount:     amounts.String(sum),
+			CreditDebit:   indicator,
+			TransactionCd: asset,
+		})
+	}
+	s.Statement.Summary = summary
+
+	for _, entry := range statement.Entries {
+		e := camt053Entry{
+			Reference:     entry.Reference,
+			Amount:        camt053Amount{Currency: entry.AssetCode, Value: amounts.String(entry.Amount)},
+			CreditDebit:   "DBIT",
+			Status:        "BOOK",
+			BookingDate:   formatTime(entry.Time),
+			ValueDate:     formatTime(entry.Time),
+			ServicerRef:   entry.TransactionID,
+			TransactionCd: "payment",
+		}
+
+		if entry.Credit {
+			e.CreditDebit = "CRDT"
+			e.Details.Debtor = entry.Counterparty
+		} else {
+			e.Details.Creditor = entry.Counterparty
+		}
+		if entry.Pending {
+			e.Status = "PDNG"
+		}
+
+		e.Details.EndToEndID = entry.PaymentID
+		if e.Details.EndToEndID == "" {
+			e.Details.EndToEndID = "NOTPROVIDED"
+		}
+		if entry.AssetIssuer != "" {
+			e.Details.Issuer = "Issuer: " + entry.AssetIssuer
+		}
+		e.Details.Memo = entry.Memo
+
+		s.Statement.Entries = append(s.Statement.Entries, e)
+	}
+
+	_, err = io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(document)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+var csvHeader = []string{
+	"time", "reference", "transaction_id", "payment_id", "direction", "status",
+	"amount", "asset_code", "asset_issuer", "counterparty", "memo",
+}
+
+// WriteCSV writes statement entries as CSV, one entry per row
+func WriteCSV(w io.Writer, statement *Statement) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range statement.Entries {
+		direction := "debit"
+		if entry.Credit {
+			direction = "credit"
+		}
+
+		status := "booked"
+		if entry.Pending {
+			status = "pending"
+		}
+
+		err = writer.Write([]string{
+			entry.Time.UTC().Format(time.RFC3339),
+			entry.Reference,
+			entry.TransactionID,
+			entry.PaymentID,
+			direction,
+			status,
+			amounts.String(entry.Amount),
+			entry.AssetCode,
+			entry.AssetIssuer,
+			entry.Counterparty,
+			entry.Memo,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
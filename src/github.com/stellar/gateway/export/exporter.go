@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+)
+
+// scheduleDelay is the time after midnight (UTC) when the statement of the
+// previous day is exported, so payments processed just before midnight are
+// already saved
+const scheduleDelay = 15 * time.Minute
+
+// retryInterval is a delay between attempts of a failed scheduled export
+const retryInterval = 5 * time.Minute
+
+// exportTimeout limits the time of a single export
+const exportTimeout = 10 * time.Minute
+
+var exporterMetrics = struct {
+	stored   *metrics.Counter
+	failures *metrics.Counter
+}{
+	stored:   metrics.NewCounter("bridge_export_statements_stored_total", "Number of statement files stored by scheduled export."),
+	failures: metrics.NewCounter("bridge_export_failures_total", "Number of failed scheduled exports."),
+}
+
+// Exporter generates statements of the bridge server account
+type Exporter struct {
+	// Account is a Stellar account ID used as the statement account
+	Account string
+	// Formats of files stored by scheduled export
+	Formats []string
+	Targets []Target
+
+	repository db.RepositoryInterface
+	horizon    horizon.HorizonInterface
+	now        func() time.Time
+	log        *logrus.Entry
+}
+
+// NewExporter creates a new Exporter
+func NewExporter(
+	account string,
+	formats []string,
+	targets []Target,
+	repository db.RepositoryInterface,
+	h horizon.HorizonInterface,
+	now func() time.Time,
+) *Exporter {
+	return &Exporter{
+		Account:    account,
+		Formats:    formats,
+		Targets:    targets,
+		repository: repository,
+		horizon:    h,
+		now:        now,
+		log:        logrus.WithFields(logrus.Fields{"service": "Exporter"}),
+	}
+}
+
+// Statement returns the statement of a day (UTC) containing date
+func (e *Exporter) Statement(ctx context.Context, date time.Time) (*Statement, error) {
+	date = date.UTC()
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return loadStatement(ctx, e.repository, e.horizon, e.Account, from, e.now())
+}
+
+// Write writes statement in a given format
+func Write(w io.Writer, statement *Statement, format string) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, statement)
+	case FormatCAMT053:
+		return WriteCAMT053(w, statement)
+	default:
+		return fmt.Errorf("Unknown statement format: %s", format)
+	}
+}
+
+// ContentType returns a MIME type of files in format
+func ContentType(format string) string {
+	if format == FormatCAMT053 {
+		return "application/xml"
+	}
+	return "text/csv"
+}
+
+// ExportDay stores the statement of a day in all Formats in all Targets
+func (e *Exporter) ExportDay(ctx context.Context, date time.Time) error {
+	statement, err := e.Statement(ctx, date)
+	if err != nil {
+		return err
+	}
+
+	for _, format := range e.Formats {
+		var buf bytes.Buffer
+		err = Write(&buf, statement, format)
+		if err != nil {
+			return err
+		}
+
+		name := FileName(statement, format)
+		for _, target := range e.Targets {
+			err = target.Store(name, buf.Bytes())
+			if err != nil {
+				return err
+			}
+			exporterMetrics.stored.Inc()
+		}
+	}
+
+	e.log.WithFields(logrus.Fields{"statement": statement.ID, "entries": len(statement.Entries)}).Info("Statement exported")
+	return nil
+}
+
+// Start exports the statement of the previous day every day shortly after
+// midnight (UTC) in a goroutine. Failed exports are retried until the next
+// day starts.
+func (e *Exporter) Start() {
+	go func() {
+		for {
+			next := nextRun(e.now())
+			time.Sleep(next.Sub(e.now()))
+
+			day := next.Add(-scheduleDelay).AddDate(0, 0, -1)
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+				err := e.ExportDay(ctx, day)
+				cancel()
+				if err == nil {
+					break
+				}
+
+				exporterMetrics.failures.Inc()
+				e.log.WithFields(logrus.Fields{"err": err, "day": day.Format("2006-01-02")}).Error("Error exporting statement")
+				if e.now().Add(retryInterval).After(next.AddDate(0, 0, 1)) {
+					break
+				}
+				time.Sleep(retryInterval)
+			}
+		}
+	}()
+}
+
+// nextRun returns the time of the next scheduled export after now
+func nextRun(now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(scheduleDelay)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,219 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	account     = "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"
+	destination = "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"
+	issuer      = "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+)
+
+func paymentEnvelope(t *testing.T) string {
+	var source, dest, assetIssuer xdr.AccountId
+	require.NoError(t, source.SetAddress(account))
+	require.NoError(t, dest.SetAddress(destination))
+	require.NoError(t, assetIssuer.SetAddress(issuer))
+
+	var asset xdr.Asset
+	require.NoError(t, asset.SetCredit("USD", assetIssuer))
+
+	body, err := xdr.NewOperationBody(xdr.OperationTypePayment, xdr.PaymentOp{
+		Destination: dest,
+		Asset:       asset,
+		Amount:      xdr.Int64(125000000),
+	})
+	require.NoError(t, err)
+
+	envelope := xdr.TransactionEnvelope{
+		Tx: xdr.Transaction{
+			SourceAccount: source,
+			Operations:    []xdr.Operation{{Body: body}},
+		},
+	}
+	encoded, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	return encoded
+}
+
+func newTestExporter(t *testing.T) (*Exporter, *mocks.MockRepository, *mocks.MockHorizon) {
+	mockRepository := new(mocks.MockRepository)
+	mockHorizon := new(mocks.MockHorizon)
+	now := time.Date(2018, 3, 2, 0, 20, 0, 0, time.UTC)
+	exporter := NewExporter(account, Formats, nil, mockRepository, mockHorizon, func() time.Time { return now })
+
+	from := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	paymentID := "payment-1"
+	succeededAt := time.Date(2018, 3, 1, 14, 0, 0, 0, time.UTC)
+
+	mockRepository.On("GetReceivedPayments", db.ReceivedPaymentsFilter{From: &from, To: &to}, 1, pageSize).Return(
+		[]*entities.ReceivedPayment{{
+			OperationID:      "100",
+			TransactionID:    "tx-received",
+			ProcessedAt:      time.Date(2018, 3, 1, 10, 0, 0, 0, time.UTC),
+			TransactionValue: "10.5",
+			MemoID:           "memo, with comma",
+		}}, nil,
+	).Once()
+	mockRepository.On("GetSentTransactions", db.SentTransactionsFilter{From: &from, To: &to}, 1, pageSize).Return(
+		[]*entities.SentTransaction{
+			{
+				TransactionID: "tx-sent",
+				PaymentID:     &paymentID,
+				Status:        entities.SentTransactionStatusSuccess,
+				SubmittedAt:   time.Date(2018, 3, 1, 13, 59, 0, 0, time.UTC),
+				SucceededAt:   &succeededAt,
+				EnvelopeXdr:   paymentEnvelope(t),
+			},
+			{
+				TransactionID: "tx-failed",
+				Status:        entities.SentTransactionStatusFailure,
+				SubmittedAt:   time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC),
+				EnvelopeXdr:   paymentEnvelope(t),
+			},
+		}, nil,
+	).Once()
+	mockHorizon.On("LoadOperation", "100").Return(horizon.PaymentResponse{
+		Type:      "payment",
+		From:      destination,
+		AssetType: "native",
+		Amount:    "10.5",
+	}, nil).Once()
+
+	return exporter, mockRepository, mockHorizon
+}
+
+func TestStatement(t *testing.T) {
+	exporter, mockRepository, mockHorizon := newTestExporter(t)
+
+	statement, err := exporter.Statement(context.Background(), time.Date(2018, 3, 1, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	mockRepository.AssertExpectations(t)
+	mockHorizon.AssertExpectations(t)
+
+	assert.Equal(t, account+"-20180301", statement.ID)
+	require.Len(t, statement.Entries, 2)
+
+	received := statement.Entries[0]
+	assert.True(t, received.Credit)
+	assert.Equal(t, int64(105000000), received.Amount)
+	assert.Equal(t, "XLM", received.AssetCode)
+	assert.Equal(t, destination, received.Counterparty)
+
+	sent := statement.Entries[1]
+	assert.False(t, sent.Credit)
+	assert.False(t, sent.Pending)
+	assert.Equal(t, "tx-sent:0", sent.Reference)
+	assert.Equal(t, "payment-1", sent.PaymentID)
+	assert.Equal(t, int64(125000000), sent.Amount)
+	assert.Equal(t, "USD", sent.AssetCode)
+	assert.Equal(t, issuer, sent.AssetIssuer)
+
+	credits, debits, sums, err := statement.Totals()
+	require.NoError(t, err)
+	assert.Equal(t, 1, credits)
+	assert.Equal(t, 1, debits)
+	assert.Equal(t, map[string]int64{"XLM": 105000000, "USD": -125000000}, sums)
+
+	var csv bytes.Buffer
+	require.NoError(t, Write(&csv, statement, FormatCSV))
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, strings.Join(csvHeader, ","), lines[0])
+	assert.Equal(t, `2018-03-01T10:00:00Z,100,tx-received,,credit,booked,10.5000000,XLM,,`+destination+`,"memo, with comma"`, lines[1])
+	assert.Equal(t, `2018-03-01T14:00:00Z,tx-sent:0,tx-sent,payment-1,debit,booked,12.5000000,USD,`+issuer+`,`+destination+`,`, lines[2])
+
+	var camt bytes.Buffer
+	require.NoError(t, Write(&camt, statement, FormatCAMT053))
+	xml := camt.String()
+	assert.Contains(t, xml, `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">`)
+	assert.Contains(t, xml, `<FrDtTm>2018-03-01T00:00:00Z</FrDtTm>`)
+	assert.Contains(t, xml, `<Amt Ccy="XLM">10.5000000</Amt>`)
+	assert.Contains(t, xml, `<Amt Ccy="USD">12.5000000</Amt>`)
+	assert.Contains(t, xml, `<EndToEndId>payment-1</EndToEndId>`)
+	assert.Contains(t, xml, `<Ustrd>memo, with comma</Ustrd>`)
+	assert.Contains(t, xml, `<AddtlTxInf>Issuer: `+issuer+`</AddtlTxInf>`)
+	assert.Contains(t, xml, `<TtlNetNtryAmt>12.5000000</TtlNetNtryAmt>`)
+
+	assert.Error(t, Write(&camt, statement, "pdf"))
+	assert.Equal(t, "statement-2018-03-01.xml", FileName(statement, FormatCAMT053))
+}
+
+func TestExportDay(t *testing.T) {
+	exporter, mockRepository, _ := newTestExporter(t)
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exporter.Targets = []Target{DirectoryTarget{Path: filepath.Join(dir, "statements")}}
+
+	require.NoError(t, exporter.ExportDay(context.Background(), time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)))
+	mockRepository.AssertExpectations(t)
+
+	files, err := filepath.Glob(filepath.Join(dir, "statements", "*"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "statements", "statement-2018-03-01.csv"),
+		filepath.Join(dir, "statements", "statement-2018-03-01.xml"),
+	}, files)
+}
+
+func TestS3Target(t *testing.T) {
+	var request *http.Request
+	var body []byte
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = ioutil.ReadAll(r.Body)
+		if strings.HasSuffix(r.URL.Path, "error.csv") {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer s.Close()
+
+	creds := credentials.NewStaticCredentials("AKID", "SECRET", "")
+	target := NewS3TargetWithCredentials(s.URL+"/", "us-east-1", "statements/", creds, http.DefaultClient)
+
+	require.NoError(t, target.Store("statement-2018-03-01.csv", []byte("time\n")))
+	assert.Equal(t, "PUT", request.Method)
+	assert.Equal(t, "/statements/statement-2018-03-01.csv", request.URL.Path)
+	assert.Equal(t, "text/csv", request.Header.Get("Content-Type"))
+	assert.Contains(t, request.Header.Get("Authorization"), "Credential=AKID/")
+	assert.Contains(t, request.Header.Get("Authorization"), "/us-east-1/s3/aws4_request")
+	assert.Equal(t, "time\n", string(body))
+
+	assert.Error(t, target.Store("error.csv", []byte("time\n")))
+}
+
+func TestNextRun(t *testing.T) {
+	assert.Equal(t,
+		time.Date(2018, 3, 2, 0, 15, 0, 0, time.UTC),
+		nextRun(time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)),
+	)
+	assert.Equal(t,
+		time.Date(2018, 3, 1, 0, 15, 0, 0, time.UTC),
+		nextRun(time.Date(2018, 3, 1, 0, 10, 0, 0, time.UTC)),
+	)
+	assert.Equal(t,
+		time.Date(2018, 3, 2, 0, 15, 0, 0, time.UTC),
+		nextRun(time.Date(2018, 3, 1, 0, 15, 0, 0, time.UTC)),
+	)
+}
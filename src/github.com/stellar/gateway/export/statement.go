@@ -0,0 +1,260 @@
+// Package export generates daily statements of received payments and sent
+// transactions in formats used by accounting and treasury systems: CSV and
+// ISO 20022 CAMT.053 (bank-to-customer statement).
+package export
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// Statement formats
+const (
+	FormatCSV     = "csv"
+	FormatCAMT053 = "camt053"
+)
+
+// Formats contains all supported statement formats
+var Formats = []string{FormatCSV, FormatCAMT053}
+
+// pageSize is the number of rows loaded from DB at once
+const pageSize = 500
+
+// Entry is a single movement of funds in a Statement
+type Entry struct {
+	// Reference is unique within a statement: Horizon operation ID of received
+	// payments, transaction ID and operation index of sent transactions
+	Reference     string
+	TransactionID string
+	// PaymentID is the `id` of /payment request, empty for received payments
+	PaymentID string
+	Time      time.Time
+	// Credit is true for received payments, false for sent
+	Credit bool
+	// Pending is true when the transaction has not been included in a ledger yet
+	Pending      bool
+	Amount       int64
+	AssetCode    string
+	AssetIssuer  string
+	Counterparty string
+	Memo         string
+}
+
+// Statement contains payments received and sent by the bridge server in
+// [From, To) time range, ordered by time
+type Statement struct {
+	ID        string
+	Account   string
+	From      time.Time
+	To        time.Time
+	CreatedAt time.Time
+	Entries   []Entry
+}
+
+// Totals returns the number of credit and debit entries and their sums by
+// asset (amounts of different assets cannot be added)
+func (s *Statement) Totals() (credits, debits int, sums map[string]int64, err error) {
+	sums = map[string]int64{}
+	for _, entry := range s.Entries {
+		amount := entry.Amount
+		if entry.Credit {
+			credits++
+		} else {
+			debits++
+			amount = -amount
+		}
+
+		sums[entry.AssetCode], err = amounts.Add(sums[entry.AssetCode], amount)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// loadStatement loads entries of a day (UTC) starting at from
+func loadStatement(
+	ctx context.Context,
+	repository db.RepositoryInterface,
+	h horizon.HorizonInterface,
+	account string,
+	from time.Time,
+	now time.Time,
+) (*Statement, error) {
+	to := from.AddDate(0, 0, 1)
+	statement := &Statement{
+		ID:        account + "-" + from.Format("20060102"),
+		Account:   account,
+		From:      from,
+		To:        to,
+		CreatedAt: now,
+	}
+
+	for page := 1; ; page++ {
+		payments, err := repository.GetReceivedPayments(ctx, db.ReceivedPaymentsFilter{From: &from, To: &to}, page, pageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error loading received payments")
+		}
+
+		for _, payment := range payments {
+			entry, err := receivedEntry(payment, h)
+			if err != nil {
+				return nil, errors.Wrap(err, "Error loading received payment "+payment.OperationID)
+			}
+			statement.Entries = append(statement.Entries, entry)
+		}
+
+		if len(payments) < pageSize {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		transactions, err := repository.GetSentTransactions(ctx, db.SentTransactionsFilter{From: &from, To: &to}, page, pageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error loading sent transactions")
+		}
+
+		for _, transaction := range transactions {
+			entries, err := SentEntries(transaction)
+			if err != nil {
+				return nil, errors.Wrap(err, "Error decoding sent transaction "+transaction.TransactionID)
+			}
+			statement.Entries = append(statement.Entries, entries...)
+		}
+
+		if len(transactions) < pageSize {
+			break
+		}
+	}
+
+	sort.SliceStable(statement.Entries, func(i, j int) bool {
+		return statement.Entries[i].Time.Before(statement.Entries[j].Time)
+	})
+	return statement, nil
+}
+
+// receivedEntry returns an entry of a received payment. Asset and sender are
+// not stored in DB so they are loaded from Horizon.
+func receivedEntry(payment *entities.ReceivedPayment, h horizon.HorizonInterface) (entry Entry, err error) {
+	operation, err := h.LoadOperation(payment.OperationID)
+	if err != nil {
+		return
+	}
+
+	entry = Entry{
+		Reference:     payment.OperationID,
+		TransactionID: payment.TransactionID,
+		Time:          payment.ProcessedAt,
+		Credit:        true,
+		AssetCode:     assetCode(operation.AssetType, operation.AssetCode),
+		AssetIssuer:   operation.AssetIssuer,
+		Counterparty:  operation.From,
+		Memo:          payment.MemoID,
+	}
+	if operation.Type == "account_merge" {
+		entry.AssetCode = assetCode("native", "")
+		entry.Counterparty = operation.Account
+	}
+
+	entry.Amount, err = amounts.Parse(payment.TransactionValue)
+	return
+}
+
+// SentEntries returns entries of operations moving funds in a sent
+// transaction. Failed transactions have no entries.
+func SentEntries(transaction *entities.SentTransaction) (entries []Entry, err error) {
+	if transaction.Status == entities.SentTransactionStatusFailure {
+		return
+	}
+
+	var envelope xdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(transaction.EnvelopeXdr, &envelope)
+	if err != nil {
+		return
+	}
+
+	// Path payments send amount is known from the result only
+	var results []xdr.OperationResult
+	if transaction.ResultXdr != nil {
+		var result xdr.TransactionResult
+		if xdr.SafeUnmarshalBase64(*transaction.ResultXdr, &result) == nil {
+			results, _ = result.Result.GetResults()
+		}
+	}
+
+	for i, op := range envelope.Tx.Operations {
+		entry := Entry{
+			Reference:     transaction.TransactionID + ":" + strconv.Itoa(i),
+			TransactionID: transaction.TransactionID,
+			Time:          transaction.SubmittedAt,
+			Pending:       transaction.Status != entities.SentTransactionStatusSuccess,
+		}
+		if transaction.PaymentID != nil {
+			entry.PaymentID = *transaction.PaymentID
+		}
+		if transaction.SucceededAt != nil {
+			entry.Time = *transaction.SucceededAt
+		}
+
+		var asset xdr.Asset
+		switch op.Body.Type {
+		case xdr.OperationTypePayment:
+			body := op.Body.MustPaymentOp()
+			entry.Amount = int64(body.Amount)
+			entry.Counterparty = body.Destination.Address()
+			asset = body.Asset
+		case xdr.OperationTypePathPayment:
+			body := op.Body.MustPathPaymentOp()
+			entry.Amount = int64(body.SendMax)
+			if i < len(results) && results[i].Tr != nil && results[i].Tr.PathPaymentResult != nil {
+				entry.Amount = int64(results[i].Tr.PathPaymentResult.SendAmount())
+			}
+			entry.Counterparty = body.Destination.Address()
+			asset = body.SendAsset
+		case xdr.OperationTypeCreateAccount:
+			body := op.Body.MustCreateAccountOp()
+			entry.Amount = int64(body.StartingBalance)
+			entry.Counterparty = body.Destination.Address()
+			asset = xdr.Asset{Type: xdr.AssetTypeAssetTypeNative}
+		default:
+			continue
+		}
+
+		var assetType string
+		err = asset.Extract(&assetType, &entry.AssetCode, &entry.AssetIssuer)
+		if err != nil {
+			return
+		}
+		entry.AssetCode = assetCode(assetType, entry.AssetCode)
+
+		entries = append(entries, entry)
+	}
+	return
+}
+
+func assetCode(assetType, code string) string {
+	if assetType == "native" {
+		return "XLM"
+	}
+	return code
+}
+
+// FileName returns a name of a statement file, ex. statement-2018-03-01.xml
+func FileName(statement *Statement, format string) string {
+	extension := format
+	if format == FormatCAMT053 {
+		extension = "xml"
+	}
+	return fmt.Sprintf("statement-%s.%s", statement.From.Format("2006-01-02"), extension)
+}
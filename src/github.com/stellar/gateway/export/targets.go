@@ -0,0 +1,117 @@
+package export
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/go/support/errors"
+)
+
+// Target stores statement files
+type Target interface {
+	Store(name string, data []byte) error
+}
+
+// HTTP represents an http client that S3Target can use to make HTTP requests
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// DirectoryTarget stores statements in a local directory
+type DirectoryTarget struct {
+	Path string
+}
+
+// Store writes the file to the directory. The file is written under a
+// temporary name and renamed so readers never see a partial statement.
+func (t DirectoryTarget) Store(name string, data []byte) error {
+	err := os.MkdirAll(t.Path, 0755)
+	if err != nil {
+		return errors.Wrap(err, "Cannot create export directory")
+	}
+
+	path := filepath.Join(t.Path, name)
+	err = ioutil.WriteFile(path+".tmp", data, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Cannot write statement")
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// S3Target uploads statements to AWS S3 bucket using PUT Object API.
+// Requests are signed using AWS Signature Version 4.
+type S3Target struct {
+	Client HTTP
+	// Endpoint is an URL of the bucket, ex. https://bucket.s3.us-east-1.amazonaws.com
+	Endpoint string
+	Region   string
+	// Prefix is prepended to file names, ex. "statements/"
+	Prefix string
+	Signer *v4.Signer
+	now    func() time.Time
+}
+
+// NewS3Target creates a new S3Target. Credentials are loaded from default AWS
+// credentials chain (env variables, shared credentials file, EC2/ECS roles).
+func NewS3Target(bucket, region, prefix string, client HTTP) (*S3Target, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot create AWS session")
+	}
+
+	return NewS3TargetWithCredentials("https://"+bucket+".s3."+region+".amazonaws.com", region, prefix, sess.Config.Credentials, client), nil
+}
+
+// NewS3TargetWithCredentials creates a new S3Target using given credentials
+func NewS3TargetWithCredentials(endpoint, region, prefix string, creds *credentials.Credentials, client HTTP) *S3Target {
+	return &S3Target{
+		Client:   client,
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		Region:   region,
+		Prefix:   prefix,
+		Signer:   v4.NewSigner(creds),
+		now:      clock.Now,
+	}
+}
+
+// Store uploads the file to the bucket
+func (t *S3Target) Store(name string, data []byte) error {
+	body := bytes.NewReader(data)
+	req, err := http.NewRequest("PUT", t.Endpoint+"/"+t.Prefix+name, body)
+	if err != nil {
+		return errors.Wrap(err, "configure http request failed")
+	}
+
+	if strings.HasSuffix(name, ".xml") {
+		req.Header.Set("Content-Type", "application/xml")
+	} else if strings.HasSuffix(name, ".tar.gz") {
+		req.Header.Set("Content-Type", "application/gzip")
+	} else {
+		req.Header.Set("Content-Type", "text/csv")
+	}
+
+	_, err = t.Signer.Sign(req, body, "s3", t.Region, t.now())
+	if err != nil {
+		return errors.Wrap(err, "Cannot sign S3 request")
+	}
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error sending request to S3")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Error response from S3: %d", resp.StatusCode)
+	}
+	return nil
+}
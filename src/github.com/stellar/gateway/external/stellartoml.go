@@ -0,0 +1,184 @@
+package external
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stellar/gateway/tlspolicy"
+	"github.com/stellar/go/address"
+	"github.com/stellar/go/clients/stellartoml"
+)
+
+// Codes of StellarTomlError
+const (
+	StellarTomlInsecure           = "stellar_toml_insecure"
+	StellarTomlInvalidCertificate = "stellar_toml_invalid_certificate"
+	StellarTomlTooManyRedirects   = "stellar_toml_too_many_redirects"
+	StellarTomlTooLarge           = "stellar_toml_too_large"
+	StellarTomlNotFound           = "stellar_toml_not_found"
+	StellarTomlInvalid            = "stellar_toml_invalid"
+	StellarTomlUnreachable        = "stellar_toml_unreachable"
+)
+
+const defaultMaxRedirects = 10
+
+var (
+	errInsecureRedirect = errors.New("redirect to non-HTTPS URL")
+	errTooManyRedirects = errors.New("too many redirects")
+)
+
+// StellarTomlError is returned by StellarTomlClient. Code allows callers to
+// distinguish between failures (ex. invalid certificate and missing file).
+type StellarTomlError struct {
+	Code   string
+	Domain string
+	Err    error
+}
+
+func (e *StellarTomlError) Error() string {
+	return fmt.Sprintf("Cannot load stellar.toml of %s (%s): %s", e.Domain, e.Code, e.Err)
+}
+
+// StellarTomlOptions configures StellarTomlClient
+type StellarTomlOptions struct {
+	// RequireHTTPS rejects redirects to plain HTTP URLs. stellar.toml is
+	// always requested using HTTPS.
+	RequireHTTPS bool
+	// RootCAs verifies certificate chains. System roots are used when nil.
+	RootCAs *x509.CertPool
+	// MaxSize is a max size of stellar.toml file in bytes (default 5KB)
+	MaxSize int64
+	// MaxRedirects is a max number of redirects followed (default 10)
+	MaxRedirects *int
+	Timeout      time.Duration
+	// TLSPolicy is applied to connections, ex. min TLS version and pins
+	TLSPolicy tlspolicy.Config
+}
+
+// StellarTomlClient loads stellar.toml files from
+// https://DOMAIN/.well-known/stellar.toml (SEP-1) and returns
+// *StellarTomlError when the file cannot be loaded.
+type StellarTomlClient struct {
+	HTTP    *http.Client
+	MaxSize int64
+}
+
+// NewStellarTomlClient creates a new StellarTomlClient
+func NewStellarTomlClient(options StellarTomlOptions) *StellarTomlClient {
+	maxRedirects := defaultMaxRedirects
+	if options.MaxRedirects != nil {
+		maxRedirects = *options.MaxRedirects
+	}
+
+	maxSize := options.MaxSize
+	if maxSize == 0 {
+		maxSize = stellartoml.StellarTomlMaxSize
+	}
+
+	return &StellarTomlClient{
+		HTTP: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: options.TLSPolicy.Transport(options.RootCAs),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return errTooManyRedirects
+				}
+				if options.RequireHTTPS && req.URL.Scheme != "https" {
+					return errInsecureRedirect
+				}
+				return nil
+			},
+		},
+		MaxSize: maxSize,
+	}
+}
+
+// LoadCertPool loads PEM encoded certificates from a file
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("No certificates found in %s", file)
+	}
+	return pool, nil
+}
+
+// GetStellarToml returns stellar.toml file for a given domain
+func (c *StellarTomlClient) GetStellarToml(domain string) (*stellartoml.Response, error) {
+	resp, err := c.HTTP.Get("https://" + domain + stellartoml.WellKnownPath)
+	if err != nil {
+		return nil, &StellarTomlError{Code: requestErrorCode(err), Domain: domain, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err = fmt.Errorf("HTTP status %d", resp.StatusCode)
+		return nil, &StellarTomlError{Code: StellarTomlNotFound, Domain: domain, Err: err}
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, c.MaxSize+1))
+	if err != nil {
+		return nil, &StellarTomlError{Code: StellarTomlUnreachable, Domain: domain, Err: err}
+	}
+
+	if int64(len(body)) > c.MaxSize {
+		err = fmt.Errorf("response exceeds %d bytes limit", c.MaxSize)
+		return nil, &StellarTomlError{Code: StellarTomlTooLarge, Domain: domain, Err: err}
+	}
+
+	var response stellartoml.Response
+	_, err = toml.DecodeReader(bytes.NewReader(body), &response)
+	if err != nil {
+		return nil, &StellarTomlError{Code: StellarTomlInvalid, Domain: domain, Err: err}
+	}
+
+	return &response, nil
+}
+
+// GetStellarTomlByAddress returns stellar.toml file of a domain of a given address
+func (c *StellarTomlClient) GetStellarTomlByAddress(addy string) (*stellartoml.Response, error) {
+	_, domain, err := address.Split(addy)
+	if err != nil {
+		return nil, errors.New("Invalid stellar address: " + addy)
+	}
+
+	return c.GetStellarToml(domain)
+}
+
+// requestErrorCode returns code of an error returned by http.Client
+func requestErrorCode(err error) string {
+	for err != nil {
+		switch err.(type) {
+		case x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError, *tlspolicy.PinError:
+			return StellarTomlInvalidCertificate
+		}
+
+		switch err {
+		case errInsecureRedirect, tlspolicy.ErrInsecure:
+			return StellarTomlInsecure
+		case errTooManyRedirects:
+			return StellarTomlTooManyRedirects
+		}
+
+		wrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		err = wrapper.Unwrap()
+	}
+
+	return StellarTomlUnreachable
+}
+
+var _ StellarTomlClientInterface = &StellarTomlClient{}
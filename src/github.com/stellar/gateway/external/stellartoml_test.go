@@ -0,0 +1,92 @@
+package external
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serverCertPool(server *httptest.Server) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	return pool
+}
+
+func assertStellarTomlError(t *testing.T, err error, code string) {
+	require.Error(t, err)
+	stellarTomlErr, ok := err.(*StellarTomlError)
+	require.True(t, ok, err.Error())
+	assert.Equal(t, code, stellarTomlErr.Code, err.Error())
+}
+
+func TestStellarTomlClient(t *testing.T) {
+	var handler http.HandlerFunc
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r)
+	}))
+	defer server.Close()
+	domain := strings.TrimPrefix(server.URL, "https://")
+
+	client := NewStellarTomlClient(StellarTomlOptions{RootCAs: serverCertPool(server), MaxSize: 100})
+
+	handler = func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/.well-known/stellar.toml", r.URL.Path)
+		w.Write([]byte(`FEDERATION_SERVER="https://example.com/federation"`))
+	}
+	response, err := client.GetStellarToml(domain)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/federation", response.FederationServer)
+
+	handler = func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("#", 101)))
+	}
+	_, err = client.GetStellarToml(domain)
+	assertStellarTomlError(t, err, StellarTomlTooLarge)
+
+	handler = func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`FEDERATION_SERVER=`))
+	}
+	_, err = client.GetStellarToml(domain)
+	assertStellarTomlError(t, err, StellarTomlInvalid)
+
+	handler = http.NotFound
+	_, err = client.GetStellarToml(domain)
+	assertStellarTomlError(t, err, StellarTomlNotFound)
+
+	// System roots do not contain test server certificate
+	_, err = NewStellarTomlClient(StellarTomlOptions{}).GetStellarToml(domain)
+	assertStellarTomlError(t, err, StellarTomlInvalidCertificate)
+}
+
+func TestStellarTomlClientRedirects(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`FEDERATION_SERVER="https://example.com/federation"`))
+	}))
+	defer plain.Close()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/stellar.toml" {
+			http.Redirect(w, r, "/stellar.toml", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, plain.URL+"/stellar.toml", http.StatusFound)
+	}))
+	defer server.Close()
+	domain := strings.TrimPrefix(server.URL, "https://")
+
+	response, err := NewStellarTomlClient(StellarTomlOptions{RootCAs: serverCertPool(server)}).GetStellarToml(domain)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/federation", response.FederationServer)
+
+	_, err = NewStellarTomlClient(StellarTomlOptions{RootCAs: serverCertPool(server), RequireHTTPS: true}).GetStellarToml(domain)
+	assertStellarTomlError(t, err, StellarTomlInsecure)
+
+	maxRedirects := 1
+	_, err = NewStellarTomlClient(StellarTomlOptions{RootCAs: serverCertPool(server), MaxRedirects: &maxRedirects}).GetStellarToml(domain)
+	assertStellarTomlError(t, err, StellarTomlTooManyRedirects)
+}
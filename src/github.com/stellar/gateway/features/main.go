@@ -0,0 +1,259 @@
+// Package features contains feature flags gating risky behaviors of the
+// bridge server, so they can be rolled out to one tenant at a time. Flags are
+// evaluated per request: database overrides (set using admin API) take
+// precedence over the config file, which takes precedence over defaults.
+// Tenant of a request is the tenant of the API key that authenticated it.
+package features
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db/entities"
+)
+
+const (
+	// AutoCreateAccount sends create_account operation when the destination of
+	// a native payment does not exist
+	AutoCreateAccount = "auto_create_account"
+	// InferMemoType infers memo_type of payments sent with memo only
+	InferMemoType = "infer_memo_type"
+	// AsyncSubmission responds to /payment requests with payment ID as soon
+	// as the transaction is saved, the transaction is submitted in the
+	// background
+	AsyncSubmission = "async_submission"
+	// StrictCompliance sends payments to federation addresses using the
+	// compliance protocol only
+	StrictCompliance = "strict_compliance"
+	// ResponseEnvelope wraps responses of the API in {"result", "error"}
+	// envelope
+	ResponseEnvelope = "response_envelope"
+)
+
+// defaults contains values of known features used when a feature is not
+// configured
+var defaults = map[string]bool{
+	AutoCreateAccount: true,
+	InferMemoType:     false,
+	AsyncSubmission:   false,
+	StrictCompliance:  false,
+	ResponseEnvelope:  false,
+}
+
+// overridesTTL is the time overrides loaded from the database are reused.
+// Overrides set using the admin API of another replica are used after it.
+const overridesTTL = 10 * time.Second
+
+// Repository loads feature flag overrides
+type Repository interface {
+	GetFeatureFlags(ctx context.Context, name string) ([]*entities.FeatureFlag, error)
+}
+
+// Flags evaluates feature flags. A nil *Flags returns defaults.
+type Flags struct {
+	config     map[string]config.Feature
+	repository Repository
+	log        *logrus.Entry
+	now        func() time.Time
+
+	mutex sync.Mutex
+	// overrides are cached overrides of features by name
+	overrides map[string]cachedOverrides
+}
+
+type cachedOverrides struct {
+	flags    []*entities.FeatureFlag
+	loadedAt time.Time
+}
+
+// Status describes a feature flag returned by /admin/feature-flags endpoint
+type Status struct {
+	Name    string   `json:"name"`
+	Default bool     `json:"default"`
+	Enabled bool     `json:"enabled"`
+	Tenants []string `json:"tenants"`
+	// Overrides are loaded from the database
+	Overrides []*entities.FeatureFlag `json:"overrides"`
+}
+
+// New creates Flags using features from the config file. repository can be
+// nil when the bridge runs without a database.
+func New(features []config.Feature, repository Repository) (*Flags, error) {
+	flags := &Flags{
+		config:     map[string]config.Feature{},
+		repository: repository,
+		log:        logrus.WithFields(logrus.Fields{"service": "Features"}),
+		now:        time.Now,
+		overrides:  map[string]cachedOverrides{},
+	}
+
+	for _, feature := range features {
+		if !IsKnown(feature.Name) {
+			return nil, fmt.Errorf("Unknown feature: %s", feature.Name)
+		}
+		flags.config[feature.Name] = feature
+	}
+
+	return flags, nil
+}
+
+// IsKnown returns true if name is a known feature
+func IsKnown(name string) bool {
+	_, ok := defaults[name]
+	return ok
+}
+
+// Tenant returns tenant of the request or empty string when r is nil, see
+// TenantFromContext
+func Tenant(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	return TenantFromContext(r.Context())
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns ctx of requests sent by the bridge server itself on
+// behalf of a tenant (ex. held payments submitted after settlement window)
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns tenant added by WithTenant or tenant of the API
+// key that authenticated the request. Tenant is never read from request
+// headers, so clients cannot act on behalf of other tenants.
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok {
+		return tenant
+	}
+	return auth.Tenant(ctx)
+}
+
+// EnabledForRequest returns true if the feature is enabled for the tenant of
+// the request
+func (f *Flags) EnabledForRequest(name string, r *http.Request) bool {
+	return f.enabled(r.Context(), name, Tenant(r))
+}
+
+// Enabled returns true if the feature is enabled for a tenant
+func (f *Flags) Enabled(name, tenant string) bool {
+	return f.enabled(context.Background(), name, tenant)
+}
+
+func (f *Flags) enabled(ctx context.Context, name, tenant string) bool {
+	if f == nil {
+		return defaults[name]
+	}
+
+	if f.repository != nil {
+		if enabled, found := override(f.loadOverrides(ctx, name), tenant); found {
+			return enabled
+		}
+	}
+
+	feature, found := f.config[name]
+	if !found {
+		return defaults[name]
+	}
+
+	if feature.Enabled {
+		return true
+	}
+
+	for _, t := range feature.Tenants {
+		if tenant != "" && t == tenant {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadOverrides returns overrides of the feature loaded from the database at
+// most overridesTTL ago. Cached overrides are used when they cannot be
+// loaded, config is used when they have never been loaded.
+func (f *Flags) loadOverrides(ctx context.Context, name string) []*entities.FeatureFlag {
+	f.mutex.Lock()
+	cached, found := f.overrides[name]
+	f.mutex.Unlock()
+	if found && f.now().Sub(cached.loadedAt) < overridesTTL {
+		return cached.flags
+	}
+
+	overrides, err := f.repository.GetFeatureFlags(ctx, name)
+	if err != nil {
+		f.log.WithFields(logrus.Fields{"err": err, "feature": name}).Error("Error loading feature flag overrides")
+		return cached.flags
+	}
+
+	f.mutex.Lock()
+	f.overrides[name] = cachedOverrides{flags: overrides, loadedAt: f.now()}
+	f.mutex.Unlock()
+	return overrides
+}
+
+// Invalidate drops cached overrides of the feature after it has been
+// overridden, so the new override is used right away by this replica
+func (f *Flags) Invalidate(name string) {
+	if f == nil {
+		return
+	}
+	f.mutex.Lock()
+	delete(f.overrides, name)
+	f.mutex.Unlock()
+}
+
+// List returns statuses of all known features
+func (f *Flags) List(ctx context.Context) ([]Status, error) {
+	if f == nil {
+		f = &Flags{}
+	}
+
+	var statuses []Status
+	for name, value := range defaults {
+		status := Status{Name: name, Default: value, Enabled: value}
+		if feature, found := f.config[name]; found {
+			status.Enabled = feature.Enabled
+			status.Tenants = feature.Tenants
+		}
+
+		if f.repository != nil {
+			overrides, err := f.repository.GetFeatureFlags(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			status.Overrides = overrides
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+// override returns the value of tenant override or the override for all
+// tenants. found is false when there are no matching overrides.
+func override(overrides []*entities.FeatureFlag, tenant string) (enabled bool, found bool) {
+	for _, flag := range overrides {
+		if tenant != "" && flag.Tenant == tenant {
+			return flag.Enabled, true
+		}
+	}
+
+	for _, flag := range overrides {
+		if flag.Tenant == "" {
+			return flag.Enabled, true
+		}
+	}
+
+	return false, false
+}
@@ -0,0 +1,71 @@
+package features
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags(t *testing.T) {
+	var nilFlags *Flags
+	assert.True(t, nilFlags.Enabled(AutoCreateAccount, "a"))
+
+	_, err := New([]config.Feature{{Name: "unknown"}}, nil)
+	assert.EqualError(t, err, "Unknown feature: unknown")
+
+	flags, err := New([]config.Feature{{Name: AutoCreateAccount, Tenants: []string{"a"}}}, nil)
+	require.NoError(t, err)
+	assert.True(t, flags.Enabled(AutoCreateAccount, "a"))
+	assert.False(t, flags.Enabled(AutoCreateAccount, "b"))
+	assert.False(t, flags.Enabled(AutoCreateAccount, ""))
+
+	// Tenant is read from the API key, not from request headers
+	r, _ := http.NewRequest("POST", "/payment", nil)
+	r.Header.Set("X-Tenant-ID", "a")
+	assert.False(t, flags.EnabledForRequest(AutoCreateAccount, r))
+	r = r.WithContext(auth.WithKey(r.Context(), &auth.Key{ID: "key", Tenant: "a"}))
+	assert.True(t, flags.EnabledForRequest(AutoCreateAccount, r))
+
+	// Tenant of payments sent by the bridge server itself
+	r = r.WithContext(WithTenant(r.Context(), "b"))
+	assert.False(t, flags.EnabledForRequest(AutoCreateAccount, r))
+}
+
+func TestFlagsOverrides(t *testing.T) {
+	repository := new(mocks.MockRepository)
+	flags, err := New([]config.Feature{{Name: AutoCreateAccount, Tenants: []string{"a"}}}, repository)
+	require.NoError(t, err)
+
+	now := time.Now()
+	flags.now = func() time.Time { return now }
+
+	// Overrides are loaded once per overridesTTL
+	repository.On("GetFeatureFlags", AutoCreateAccount).Return([]*entities.FeatureFlag{
+		{Name: AutoCreateAccount, Tenant: "", Enabled: true},
+		{Name: AutoCreateAccount, Tenant: "a", Enabled: false},
+	}, nil).Once()
+
+	assert.False(t, flags.Enabled(AutoCreateAccount, "a"))
+	assert.True(t, flags.Enabled(AutoCreateAccount, "b"))
+	assert.True(t, flags.Enabled(AutoCreateAccount, ""))
+
+	// Cached overrides are used when they cannot be reloaded
+	now = now.Add(overridesTTL)
+	repository.On("GetFeatureFlags", AutoCreateAccount).Return(nil, errors.New("db error")).Once()
+	assert.False(t, flags.Enabled(AutoCreateAccount, "a"))
+
+	// Config is used when overrides have never been loaded
+	flags.Invalidate(AutoCreateAccount)
+	repository.On("GetFeatureFlags", AutoCreateAccount).Return(nil, errors.New("db error")).Once()
+	assert.True(t, flags.Enabled(AutoCreateAccount, "a"))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,130 @@
+// Package federation contains a federation server embedded in the bridge
+// server. Names are resolved using a SQL query run against bridge database or
+// a static list of records from the config file.
+package federation
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/external"
+	"github.com/stellar/go/handlers/federation"
+)
+
+// NewHandler creates a handler serving federation requests for addresses in
+// a configured domain. db is used only when federation.query is set.
+func NewHandler(c config.Federation, db *sql.DB, dialect string) http.Handler {
+	var driver federation.Driver
+
+	if c.Query != "" && c.ReverseQuery != "" {
+		reverseSQLDriver := &federation.ReverseSQLDriver{
+			SQLDriver: federation.SQLDriver{
+				DB:                db,
+				Dialect:           dialect,
+				LookupRecordQuery: c.Query,
+			},
+			LookupReverseRecordQuery: c.ReverseQuery,
+		}
+		driver = &reverseDriver{
+			domainDriver:  domainDriver{domain: c.Domain, Driver: reverseSQLDriver},
+			ReverseDriver: reverseSQLDriver,
+		}
+	} else if c.Query != "" {
+		driver = &domainDriver{
+			domain: c.Domain,
+			Driver: &federation.SQLDriver{
+				DB:                db,
+				Dialect:           dialect,
+				LookupRecordQuery: c.Query,
+			},
+		}
+	} else {
+		driver = NewStaticDriver(c.Domain, c.Records)
+	}
+
+	return &federation.Handler{Driver: driver}
+}
+
+// CheckStellarToml logs a warning when stellar.toml of the domain does not
+// publish FEDERATION_SERVER so federation requests will never reach the bridge.
+func CheckStellarToml(client external.StellarTomlClientInterface, domain string) {
+	log := logrus.WithFields(logrus.Fields{"service": "Federation", "domain": domain})
+
+	stellarToml, err := client.GetStellarToml(domain)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Warn("Cannot load stellar.toml")
+		return
+	}
+
+	if stellarToml.FederationServer == "" {
+		log.Warn("FEDERATION_SERVER is not set in stellar.toml. Set it to <bridge URL>/federation.")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"url": stellarToml.FederationServer}).Info("FEDERATION_SERVER found in stellar.toml")
+}
+
+// StaticDriver resolves addresses using a list of records
+type StaticDriver struct {
+	Domain  string
+	Records []config.FederationRecord
+}
+
+// NewStaticDriver creates a new StaticDriver
+func NewStaticDriver(domain string, records []config.FederationRecord) *StaticDriver {
+	return &StaticDriver{Domain: domain, Records: records}
+}
+
+// LookupRecord implements federation.Driver
+func (d *StaticDriver) LookupRecord(name, domain string) (*federation.Record, error) {
+	if domain != d.Domain {
+		return nil, nil
+	}
+
+	for _, record := range d.Records {
+		if record.Name == name {
+			return &federation.Record{
+				AccountID: record.AccountID,
+				MemoType:  record.MemoType,
+				Memo:      record.Memo,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// LookupReverseRecord implements federation.ReverseDriver. The first record
+// with a given account ID is returned.
+func (d *StaticDriver) LookupReverseRecord(accountID string) (*federation.ReverseRecord, error) {
+	for _, record := range d.Records {
+		if record.AccountID == accountID {
+			return &federation.ReverseRecord{Name: record.Name, Domain: d.Domain}, nil
+		}
+	}
+	return nil, nil
+}
+
+// domainDriver rejects names in domains other than the configured domain
+// before running the query
+type domainDriver struct {
+	federation.Driver
+	domain string
+}
+
+func (d *domainDriver) LookupRecord(name, domain string) (*federation.Record, error) {
+	if domain != d.domain {
+		return nil, nil
+	}
+	return d.Driver.LookupRecord(name, domain)
+}
+
+// reverseDriver is a domainDriver that also resolves account IDs
+type reverseDriver struct {
+	domainDriver
+	federation.ReverseDriver
+}
+
+var _ federation.ReverseDriver = &StaticDriver{}
+var _ federation.ReverseDriver = &reverseDriver{}
@@ -0,0 +1,58 @@
+package federation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticRecords(t *testing.T) {
+	handler := NewHandler(config.Federation{
+		Domain: "example.com",
+		Records: []config.FederationRecord{
+			{
+				Name:      "alice",
+				AccountID: "GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2",
+				MemoType:  "id",
+				Memo:      "12",
+			},
+		},
+	}, nil, "")
+
+	tests := []struct {
+		query  string
+		status int
+		body   string
+	}{
+		{
+			"type=name&q=alice*example.com",
+			http.StatusOK,
+			`{"account_id":"GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2","memo_type":"id","memo":"12"}`,
+		},
+		{
+			"type=name&q=alice*other.com",
+			http.StatusNotFound,
+			`{"code":"not_found","message":"Account not found"}`,
+		},
+		{
+			"type=name&q=bob*example.com",
+			http.StatusNotFound,
+			`{"code":"not_found","message":"Account not found"}`,
+		},
+		{
+			"type=id&q=GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2",
+			http.StatusOK,
+			`{"stellar_address":"alice*example.com"}`,
+		},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/federation?"+test.query, nil))
+		assert.Equal(t, test.status, w.Code, test.query)
+		assert.JSONEq(t, test.body, w.Body.String(), test.query)
+	}
+}
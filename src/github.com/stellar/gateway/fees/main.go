@@ -0,0 +1,105 @@
+// Package fees computes fees of outbound payments from a schedule of flat and
+// percentage fee rules per asset and tenant. A fee is either added to the
+// amount and paid to the fee collection account by a second operation of the
+// payment transaction, or deducted from the amount received by the
+// destination, so the payment and its fee are atomic.
+package fees
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+// Modes of fee rules
+const (
+	// ModeAdd adds the fee to the amount, the destination receives the amount
+	ModeAdd = "add"
+	// ModeDeduct deducts the fee from the amount, the source sends the amount
+	ModeDeduct = "deduct"
+)
+
+// ErrFeeExceedsAmount is returned when a deducted fee is not lower than the
+// amount of the payment
+var ErrFeeExceedsAmount = errors.New("Fee is not lower than the amount")
+
+// Rule is a fee of payments of an asset (XLM for lumens). Rules with an empty
+// AssetIssuer match all issuers of AssetCode, rules with an empty Tenant
+// match all tenants.
+type Rule struct {
+	AssetCode   string
+	AssetIssuer string
+	Tenant      string
+	// Flat is a fixed fee in stroops
+	Flat int64
+	// Percentage of the amount added to Flat, nil when not set
+	Percentage *big.Rat
+	Mode       string
+}
+
+func (r Rule) matches(tenant, assetCode, assetIssuer string) bool {
+	return r.AssetCode == assetCode &&
+		(r.AssetIssuer == "" || r.AssetIssuer == assetIssuer) &&
+		(r.Tenant == "" || r.Tenant == tenant)
+}
+
+// Schedule contains fee rules. A nil *Schedule charges no fees.
+type Schedule struct {
+	// Account collects fees, deducted fees stay on the source account when
+	// it's empty
+	Account string
+	Rules   []Rule
+}
+
+// Fee returns the fee of a payment of amount (in stroops) of an asset (XLM
+// for lumens) sent by a tenant or nil when no rule matches the payment. The
+// first matching rule is applied. Percentage fees are rounded down to a
+// stroop.
+func (s *Schedule) Fee(tenant, assetCode, assetIssuer string, amount int64) (*horizon.PaymentFee, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	for _, rule := range s.Rules {
+		if !rule.matches(tenant, assetCode, assetIssuer) {
+			continue
+		}
+
+		fee := rule.Flat
+		if rule.Percentage != nil {
+			percentage := new(big.Rat).Mul(big.NewRat(amount, 100), rule.Percentage)
+			fee += new(big.Int).Quo(percentage.Num(), percentage.Denom()).Int64()
+		}
+
+		if fee == 0 {
+			return nil, nil
+		}
+
+		paymentFee := &horizon.PaymentFee{
+			Mode:        rule.Mode,
+			Account:     s.Account,
+			AssetCode:   assetCode,
+			AssetIssuer: assetIssuer,
+			Fee:         amounts.String(fee),
+		}
+
+		if rule.Mode == ModeDeduct {
+			if fee >= amount {
+				return nil, ErrFeeExceedsAmount
+			}
+			paymentFee.GrossAmount = amounts.String(amount)
+			paymentFee.NetAmount = amounts.String(amount - fee)
+		} else {
+			gross, err := amounts.Add(amount, fee)
+			if err != nil {
+				return nil, err
+			}
+			paymentFee.GrossAmount = amounts.String(gross)
+			paymentFee.NetAmount = amounts.String(amount)
+		}
+		return paymentFee, nil
+	}
+	return nil, nil
+}
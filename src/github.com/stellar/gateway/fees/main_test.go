@@ -0,0 +1,68 @@
+package fees
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	issuer  = "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+	account = "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"
+)
+
+func TestSchedule(t *testing.T) {
+	schedule := &Schedule{
+		Account: account,
+		Rules: []Rule{
+			{AssetCode: "USD", Tenant: "acme", Flat: 5000000, Mode: ModeDeduct},
+			{AssetCode: "USD", AssetIssuer: issuer, Flat: 10000000, Percentage: big.NewRat(1, 2), Mode: ModeAdd},
+			{AssetCode: "XLM", Percentage: big.NewRat(1, 3), Mode: ModeAdd},
+		},
+	}
+
+	// 1 USD + 0.5% of 100 USD added to the amount
+	fee, err := schedule.Fee("", "USD", issuer, 1000000000)
+	require.NoError(t, err)
+	assert.Equal(t, &horizon.PaymentFee{
+		Mode:        ModeAdd,
+		Account:     account,
+		AssetCode:   "USD",
+		AssetIssuer: issuer,
+		GrossAmount: "101.5000000",
+		Fee:         "1.5000000",
+		NetAmount:   "100.0000000",
+	}, fee)
+
+	// Tenant rule is matched first
+	fee, err = schedule.Fee("acme", "USD", issuer, 1000000000)
+	require.NoError(t, err)
+	assert.Equal(t, ModeDeduct, fee.Mode)
+	assert.Equal(t, "100.0000000", fee.GrossAmount)
+	assert.Equal(t, "0.5000000", fee.Fee)
+	assert.Equal(t, "99.5000000", fee.NetAmount)
+
+	_, err = schedule.Fee("acme", "USD", issuer, 5000000)
+	assert.Equal(t, ErrFeeExceedsAmount, err)
+
+	// Percentage fees are rounded down
+	fee, err = schedule.Fee("", "XLM", "", 10000)
+	require.NoError(t, err)
+	assert.Equal(t, "0.0000033", fee.Fee)
+
+	// No rule or zero fee
+	fee, err = schedule.Fee("", "EUR", issuer, 1000000000)
+	require.NoError(t, err)
+	assert.Nil(t, fee)
+	fee, err = schedule.Fee("", "XLM", "", 1)
+	require.NoError(t, err)
+	assert.Nil(t, fee)
+
+	var disabled *Schedule
+	fee, err = disabled.Fee("", "USD", issuer, 1000000000)
+	require.NoError(t, err)
+	assert.Nil(t, fee)
+}
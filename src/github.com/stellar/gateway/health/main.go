@@ -0,0 +1,198 @@
+// Package health implements liveness (/healthz) and readiness (/readyz)
+// endpoints. Readiness runs checks of dependencies (database, Horizon,
+// callbacks, ...) and reports status of each of them.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/server"
+)
+
+// DefaultTimeout is a timeout of all checks of a single readiness request
+const DefaultTimeout = 5 * time.Second
+
+const (
+	// StatusOK means the dependency (or all dependencies) is available
+	StatusOK = "ok"
+	// StatusUnavailable means the dependency (or any of dependencies) is
+	// not available
+	StatusUnavailable = "unavailable"
+)
+
+// Check returns nil when a dependency is available
+type Check func(ctx context.Context) error
+
+// HTTP represents an http client that checks can use to make HTTP requests
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// Checker runs checks of dependencies
+type Checker struct {
+	// Timeout of all checks, DefaultTimeout when 0
+	Timeout time.Duration
+
+	mutex  sync.Mutex
+	checks map[string]Check
+}
+
+// Add adds a check of a dependency called name
+func (c *Checker) Add(name string, check Check) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.checks == nil {
+		c.checks = map[string]Check{}
+	}
+	c.checks[name] = check
+}
+
+// Run runs all checks concurrently
+func (c *Checker) Run(ctx context.Context) Response {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c.mutex.Lock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mutex.Unlock()
+
+	response := Response{Status: StatusOK, Checks: map[string]Result{}}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			start := time.Now()
+			err := check(ctx)
+			result := Result{Status: StatusOK, Duration: time.Since(start).String()}
+			if err != nil {
+				result.Status = StatusUnavailable
+				result.Error = err.Error()
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			response.Checks[name] = result
+			if err != nil {
+				response.Status = StatusUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return response
+}
+
+// Healthz implements /healthz endpoint. It responds when the server is
+// running, dependencies are not checked, so an outage of a dependency does
+// not make the orchestrator restart all instances.
+func (c *Checker) Healthz(w http.ResponseWriter, r *http.Request) {
+	server.Write(w, Response{Status: StatusOK})
+}
+
+// Readyz implements /readyz endpoint. It responds with
+// http.StatusServiceUnavailable when any of the checks fails.
+func (c *Checker) Readyz(w http.ResponseWriter, r *http.Request) {
+	server.Write(w, c.Run(r.Context()))
+}
+
+// Response is a response of health endpoints
+type Response struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Result is a result of a single check
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// HTTPStatus returns http.StatusOK when all checks passed
+func (r Response) HTTPStatus() int {
+	if r.Status != StatusOK {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Marshal marshals Response
+func (r Response) Marshal() []byte {
+	json, _ := json.MarshalIndent(r, "", "  ")
+	return json
+}
+
+// Database checks if the database responds to ping
+func Database(database *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return database.PingContext(ctx)
+	}
+}
+
+// Reachable checks if any of urls responds to a GET request with a status
+// lower than 500. Other responses (ex. 404 or 405 of POST-only callbacks)
+// mean the server is reachable.
+func Reachable(client HTTP, urls ...string) Check {
+	return func(ctx context.Context) error {
+		var err error
+		for _, u := range urls {
+			err = get(ctx, client, u)
+			if err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+func get(ctx context.Context, client HTTP, url string) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%s responded with %d status", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// Fresh checks if the time returned by updatedAt is not older than maxAge.
+// It's used to detect stuck streams, ex. payments stream that hasn't been
+// connected for a long time.
+func Fresh(updatedAt func() time.Time, maxAge time.Duration) Check {
+	return func(ctx context.Context) error {
+		t := updatedAt()
+		if t.IsZero() {
+			return errors.New("not started")
+		}
+		if age := time.Since(t); age > maxAge {
+			return fmt.Errorf("not updated for %s", age.Truncate(time.Second))
+		}
+		return nil
+	}
+}
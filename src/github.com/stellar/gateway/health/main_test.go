@@ -0,0 +1,90 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	checker := &Checker{}
+	checker.Add("ok", func(ctx context.Context) error { return nil })
+
+	recorder := httptest.NewRecorder()
+	checker.Readyz(recorder, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response Response
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, StatusOK, response.Status)
+	assert.Equal(t, StatusOK, response.Checks["ok"].Status)
+
+	checker.Add("failing", func(ctx context.Context) error { return errors.New("connection refused") })
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	checker.Timeout = 10 * time.Millisecond
+
+	recorder = httptest.NewRecorder()
+	checker.Readyz(recorder, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	response = Response{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, StatusUnavailable, response.Status)
+	assert.Equal(t, StatusOK, response.Checks["ok"].Status)
+	assert.Equal(t, Result{Status: StatusUnavailable, Error: "connection refused", Duration: response.Checks["failing"].Duration}, response.Checks["failing"])
+	assert.Equal(t, "context deadline exceeded", response.Checks["slow"].Error)
+
+	// Liveness does not depend on checks
+	recorder = httptest.NewRecorder()
+	checker.Healthz(recorder, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, recorder.Body.String())
+}
+
+func TestChecks(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	assert.NoError(t, Database(database)(ctx))
+	database.Close()
+	assert.Error(t, Database(database)(ctx))
+
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	status = http.StatusMethodNotAllowed
+	assert.NoError(t, Reachable(http.DefaultClient, server.URL)(ctx))
+	status = http.StatusBadGateway
+	assert.EqualError(t, Reachable(http.DefaultClient, server.URL)(ctx), server.URL+" responded with 502 status")
+
+	// Any reachable URL is enough
+	unreachable := httptest.NewServer(nil)
+	unreachable.Close()
+	status = http.StatusOK
+	assert.NoError(t, Reachable(http.DefaultClient, unreachable.URL, server.URL)(ctx))
+	assert.Error(t, Reachable(http.DefaultClient, unreachable.URL)(ctx))
+
+	updatedAt := time.Time{}
+	check := Fresh(func() time.Time { return updatedAt }, time.Minute)
+	assert.EqualError(t, check(ctx), "not started")
+	updatedAt = time.Now().Add(-30 * time.Second)
+	assert.NoError(t, check(ctx))
+	updatedAt = time.Now().Add(-90 * time.Second)
+	assert.EqualError(t, check(ctx), "not updated for 1m30s")
+}
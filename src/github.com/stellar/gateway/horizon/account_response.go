@@ -2,6 +2,67 @@ package horizon
 
 // AccountResponse contains account data returned by Horizon
 type AccountResponse struct {
-	AccountID      string `json:"id"`
-	SequenceNumber string `json:"sequence"`
+	AccountID      string           `json:"id"`
+	SequenceNumber string           `json:"sequence"`
+	SubentryCount  int64            `json:"subentry_count"`
+	Thresholds     AccountThreshold `json:"thresholds"`
+	Balances       []AccountBalance `json:"balances"`
+	Signers        []AccountSigner  `json:"signers"`
+}
+
+// AccountThreshold contains thresholds of an account
+type AccountThreshold struct {
+	LowThreshold  int `json:"low_threshold"`
+	MedThreshold  int `json:"med_threshold"`
+	HighThreshold int `json:"high_threshold"`
+}
+
+// AccountBalance is a balance of native asset or a trustline of an account
+type AccountBalance struct {
+	Balance     string `json:"balance"`
+	Limit       string `json:"limit"`
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer"`
+	// IsAuthorized is nil when Horizon does not return trustline flags
+	IsAuthorized *bool `json:"is_authorized"`
+}
+
+// Authorized returns false when trustline issuer revoked (or has not yet
+// granted) authorization to hold the asset
+func (b AccountBalance) Authorized() bool {
+	return b.IsAuthorized == nil || *b.IsAuthorized
+}
+
+// AccountSigner is a signer of an account. Older Horizon versions return the
+// signer key in PublicKey field.
+type AccountSigner struct {
+	Key       string `json:"key"`
+	PublicKey string `json:"public_key"`
+	Weight    int    `json:"weight"`
+}
+
+// Balance returns the trustline of a given asset or nil when the account does
+// not trust the asset. Empty code returns native balance.
+func (a AccountResponse) Balance(code, issuer string) *AccountBalance {
+	for i, balance := range a.Balances {
+		if code == "" && balance.AssetType == "native" {
+			return &a.Balances[i]
+		}
+		if code != "" && balance.AssetCode == code && balance.AssetIssuer == issuer {
+			return &a.Balances[i]
+		}
+	}
+	return nil
+}
+
+// SignerWeight returns weight of a given signer key, 0 when the key is not a
+// signer of the account
+func (a AccountResponse) SignerWeight(key string) int {
+	for _, signer := range a.Signers {
+		if signer.Key == key || (signer.Key == "" && signer.PublicKey == key) {
+			return signer.Weight
+		}
+	}
+	return 0
 }
@@ -0,0 +1,364 @@
+package horizon
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/go/support/errors"
+)
+
+// ErrUnavailable is returned without sending a request when the circuit
+// breaker is open after too many consecutive Horizon failures
+var ErrUnavailable = errors.New("Horizon is unavailable")
+
+// Options contains settings of HTTP client used to connect to Horizon
+type Options struct {
+	// Timeout of a single request (except submitting transactions and streaming)
+	Timeout time.Duration
+	// SubmitTimeout is a timeout of a request submitting a transaction
+	SubmitTimeout time.Duration
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections to Horizon
+	MaxIdleConns int
+	// Retries is the number of retries of failed GET requests
+	Retries int
+	// RetryDelay is a delay before the first retry, doubled for every next retry
+	RetryDelay time.Duration
+	// BreakerThreshold is the number of consecutive failures after which
+	// requests fail fast with ErrUnavailable. Circuit breaker is disabled when 0.
+	BreakerThreshold int
+	// BreakerCooldown is a time after which a single request is sent to check
+	// if Horizon is available again
+	BreakerCooldown time.Duration
+	// RateLimitReserve is a percentage of Horizon rate limit reserved for
+	// critical requests, see Horizon.Background. Disabled when 0.
+	RateLimitReserve int
+	// NetworkPassphrase is used to find transactions submitted before failover
+	NetworkPassphrase string
+}
+
+// DefaultOptions are used by New
+var DefaultOptions = Options{
+	Timeout:          10 * time.Second,
+	SubmitTimeout:    60 * time.Second,
+	MaxIdleConns:     10,
+	Retries:          2,
+	RetryDelay:       500 * time.Millisecond,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+	RateLimitReserve: 20,
+}
+
+var clientMetrics = struct {
+	retries   *metrics.Counter
+	failovers *metrics.Counter
+	throttled *metrics.Counter
+}{
+	retries:   metrics.NewCounter("bridge_horizon_request_retries_total", "Number of retried GET requests to Horizon."),
+	failovers: metrics.NewCounter("bridge_horizon_failovers_total", "Number of requests sent to the next Horizon endpoint after the previous one failed."),
+	throttled: metrics.NewCounter("bridge_horizon_throttled_requests_total", "Number of background requests to Horizon not sent because the remaining rate limit is reserved for critical requests."),
+}
+
+// endpoint is a single Horizon server with its own circuit breaker and rate
+// limit budget
+type endpoint struct {
+	url       string
+	breaker   *circuitBreaker
+	rateLimit *rateLimit
+	requests  *metrics.Counter
+}
+
+func newEndpoint(serverURL string, options Options) *endpoint {
+	return &endpoint{
+		url: strings.TrimRight(serverURL, "/"),
+		breaker: newCircuitBreaker(
+			options.BreakerThreshold,
+			options.BreakerCooldown,
+			metrics.NewGauge(metrics.Label("bridge_horizon_circuit_open", "endpoint", serverURL), "1 when requests to Horizon endpoint fail fast because of consecutive failures, 0 otherwise."),
+			metrics.NewCounter(metrics.Label("bridge_horizon_request_failures_total", "endpoint", serverURL), "Number of failed requests to Horizon endpoint (network errors and 5xx responses)."),
+		),
+		rateLimit: newRateLimit(metrics.NewGauge(metrics.Label("bridge_horizon_rate_limit_remaining", "endpoint", serverURL), "Number of requests remaining in the current rate limit window of Horizon endpoint, from X-RateLimit-Remaining header.")),
+		requests:  metrics.NewCounter(metrics.Label("bridge_horizon_requests_total", "endpoint", serverURL), "Number of requests served by Horizon endpoint."),
+	}
+}
+
+// target is a URL of a request to a given endpoint. endpoint is nil for URLs
+// pointing to unknown servers.
+type target struct {
+	endpoint *endpoint
+	url      string
+}
+
+// targets returns rawURL rewritten for every endpoint in preference order
+// when rawURL points to one of the endpoints
+func (h *Horizon) targets(rawURL string) []target {
+	for _, e := range h.endpoints {
+		if !strings.HasPrefix(rawURL, e.url) {
+			continue
+		}
+
+		path := rawURL[len(e.url):]
+		targets := make([]target, len(h.endpoints))
+		for i, e := range h.endpoints {
+			targets[i] = target{endpoint: e, url: e.url + path}
+		}
+		return targets
+	}
+
+	return []target{{url: rawURL}}
+}
+
+func newHTTPClients(options Options) (client, submitClient, streamClient *http.Client) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   options.Timeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        options.MaxIdleConns,
+		MaxIdleConnsPerHost: options.MaxIdleConns,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: options.Timeout,
+	}
+
+	client = &http.Client{Transport: transport, Timeout: options.Timeout}
+	submitClient = &http.Client{Transport: transport, Timeout: options.SubmitTimeout}
+
+	// Streams are long-lived so only connecting and waiting for headers time out
+	streamTransport := transport.Clone()
+	streamTransport.ResponseHeaderTimeout = options.Timeout
+	streamClient = &http.Client{Transport: streamTransport}
+	return
+}
+
+// get sends an idempotent GET request, failing over to the next endpoints and
+// retrying with backoff on network errors and 5xx (or 429) responses
+func (h *Horizon) get(rawURL string) (statusCode int, body []byte, err error) {
+	return h.retry(rawURL, h.client.Get)
+}
+
+// getFresh works like get but asks caches between the bridge server and
+// Horizon (ex. a CDN or a caching proxy) not to return stored responses
+func (h *Horizon) getFresh(rawURL string) (statusCode int, body []byte, err error) {
+	return h.retry(rawURL, func(url string) (*http.Response, error) {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Cache-Control", "no-cache")
+		req.Header.Set("Pragma", "no-cache")
+		return h.client.Do(req)
+	})
+}
+
+func (h *Horizon) retry(rawURL string, send func(url string) (*http.Response, error)) (statusCode int, body []byte, err error) {
+	for attempt := 0; ; attempt++ {
+		statusCode, body, err = h.failover(rawURL, send)
+
+		if err == ErrUnavailable || err == ErrThrottled || !retryable(statusCode, err) || attempt >= h.options.Retries {
+			return
+		}
+
+		clientMetrics.retries.Inc()
+		h.log.WithFields(logrus.Fields{"url": rawURL, "status": statusCode, "err": err}).Warn("Retrying request to Horizon")
+		h.sleep(h.retryDelay(attempt))
+	}
+}
+
+// retryDelay returns a delay before a given retry attempt
+func (h *Horizon) retryDelay(attempt int) time.Duration {
+	delay := h.options.RetryDelay << uint(attempt)
+	if h.Congestion != nil {
+		delay = h.Congestion.StretchRetryDelay(delay)
+	}
+	return delay
+}
+
+// failover sends a request to the first available endpoint and to the next
+// endpoints on network errors and 5xx (or 429) responses. It returns
+// ErrUnavailable when circuit breakers of all endpoints are open and
+// ErrThrottled when background requests to all available endpoints are
+// throttled.
+func (h *Horizon) failover(rawURL string, send func(url string) (*http.Response, error)) (statusCode int, body []byte, err error) {
+	sent := false
+	err = ErrUnavailable
+
+	for _, t := range h.targets(rawURL) {
+		if sent {
+			clientMetrics.failovers.Inc()
+			h.log.WithFields(logrus.Fields{"endpoint": t.endpoint.url}).Warn("Failing over to the next Horizon endpoint")
+		}
+
+		code, responseBody, requestErr := h.do(t.endpoint, func() (*http.Response, error) {
+			return send(t.url)
+		})
+		if requestErr == ErrUnavailable || requestErr == ErrThrottled {
+			if !sent && requestErr == ErrThrottled {
+				err = ErrThrottled
+			}
+			continue
+		}
+
+		sent = true
+		statusCode, body, err = code, responseBody, requestErr
+		if !retryable(statusCode, err) {
+			return
+		}
+	}
+
+	return
+}
+
+// do sends a request through the circuit breaker of e (when not nil) and
+// reads the response body. Background requests are not sent when the
+// remaining rate limit of e is reserved for critical requests.
+func (h *Horizon) do(e *endpoint, send func() (*http.Response, error)) (statusCode int, body []byte, err error) {
+	if e != nil && h.background && !e.rateLimit.allowBackground(h.options.RateLimitReserve) {
+		clientMetrics.throttled.Inc()
+		return 0, nil, ErrThrottled
+	}
+
+	if e != nil && !e.breaker.allow() {
+		return 0, nil, ErrUnavailable
+	}
+
+	resp, err := send()
+	if err == nil {
+		defer resp.Body.Close()
+		body, err = ioutil.ReadAll(resp.Body)
+	}
+
+	if err != nil {
+		if e != nil {
+			e.breaker.failure()
+		}
+		return 0, nil, err
+	}
+
+	if e != nil {
+		e.requests.Inc()
+		e.rateLimit.update(resp.Header)
+		h.log.WithFields(logrus.Fields{
+			"endpoint": e.url,
+			"url":      resp.Request.URL.String(),
+			"status":   resp.StatusCode,
+		}).Debug("Request served by Horizon")
+
+		if resp.StatusCode >= 500 {
+			e.breaker.failure()
+		} else {
+			e.breaker.success()
+		}
+	}
+
+	return resp.StatusCode, body, nil
+}
+
+// StartHealthChecks checks all Horizon endpoints every interval. Endpoints
+// responding again are used without waiting for circuit breaker cooldown so
+// requests go back to the primary endpoint as soon as it's healthy.
+func (h *Horizon) StartHealthChecks(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			h.checkHealth()
+		}
+	}()
+}
+
+func (h *Horizon) checkHealth() {
+	for _, e := range h.endpoints {
+		resp, err := h.client.Get(e.url + "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		if err != nil || resp.StatusCode >= 500 {
+			if e.breaker.closed() {
+				h.log.WithFields(logrus.Fields{"endpoint": e.url, "err": err}).Warn("Horizon endpoint health check failed")
+			}
+			e.breaker.failure()
+		} else {
+			e.breaker.success()
+		}
+	}
+}
+
+func retryable(statusCode int, err error) bool {
+	return err != nil || statusCode >= 500 || statusCode == http.StatusTooManyRequests
+}
+
+// circuitBreaker stops sending requests to Horizon after threshold
+// consecutive failures. After cooldown a single request is allowed and when
+// it succeeds the breaker is closed again.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	open      *metrics.Gauge
+	failed    *metrics.Counter
+
+	mutex    sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration, open *metrics.Gauge, failed *metrics.Counter) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: clock.Now, open: open, failed: failed}
+}
+
+func (b *circuitBreaker) allow() bool {
+	if b.threshold == 0 {
+		return true
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+
+	if !b.probing && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.probing = true
+		return true
+	}
+
+	return false
+}
+
+// closed returns true if requests are sent normally
+func (b *circuitBreaker) closed() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.threshold == 0 || b.failures < b.threshold
+}
+
+func (b *circuitBreaker) success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	b.open.Set(0)
+}
+
+func (b *circuitBreaker) failure() {
+	b.failed.Inc()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.open.Set(1)
+	}
+}
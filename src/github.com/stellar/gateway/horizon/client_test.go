@@ -0,0 +1,292 @@
+package horizon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHorizonRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"GABC","sequence":"100"}`))
+	}))
+	defer server.Close()
+
+	options := DefaultOptions
+	options.RetryDelay = time.Second
+	h := NewWithOptions([]string{server.URL}, options)
+	var sleeps []time.Duration
+	h.sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+	}
+
+	account, err := h.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, "100", account.SequenceNumber)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sleeps)
+
+	// Submitting transactions is not retried
+	requests = 0
+	_, err = h.SubmitTransaction("AAAA")
+	assert.Equal(t, 1, requests)
+}
+
+func TestHorizonCircuitBreaker(t *testing.T) {
+	requests := 0
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	options := DefaultOptions
+	options.Retries = 0
+	options.BreakerThreshold = 2
+	options.BreakerCooldown = time.Minute
+	h := NewWithOptions([]string{server.URL}, options)
+
+	now := time.Now()
+	h.endpoints[0].breaker.now = func() time.Time { return now }
+
+	_, err := h.LoadOperation("1")
+	assert.Error(t, err)
+	_, err = h.LoadOperation("1")
+	assert.Error(t, err)
+	assert.Equal(t, 2, requests)
+
+	// Circuit is open: fail fast
+	_, err = h.LoadOperation("1")
+	assert.Equal(t, ErrUnavailable, err)
+	_, err = h.SubmitTransaction("AAAA")
+	assert.Equal(t, ErrUnavailable, err)
+	assert.Equal(t, 2, requests)
+
+	// After cooldown a single request is sent
+	now = now.Add(time.Minute)
+	failing = false
+	operation, err := h.LoadOperation("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", operation.ID)
+	assert.Equal(t, 3, requests)
+
+	_, err = h.LoadOperation("1")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, requests)
+}
+
+func TestHorizonFailover(t *testing.T) {
+	var primaryRequests, secondaryRequests int
+	primaryDown := true
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests++
+		if primaryDown {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"id":"GABC","sequence":"1"}`))
+	}))
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryRequests++
+		switch r.URL.Path {
+		case "/accounts/GABC":
+			w.Write([]byte(`{"id":"GABC","sequence":"2"}`))
+		case "/transactions":
+			// tx_bad_seq: transaction has been applied after the primary response timed out
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"extras":{"envelope_xdr":"","result_xdr":"AAAAAAAAAAD////7AAAAAA=="}}`))
+		default:
+			if len(r.URL.Path) > len("/transactions/") && r.URL.Path[:len("/transactions/")] == "/transactions/" {
+				fmt.Fprintf(w, `{"hash":"%s","ledger":123}`, r.URL.Path[len("/transactions/"):])
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer secondary.Close()
+
+	options := DefaultOptions
+	options.Retries = 0
+	options.BreakerThreshold = 1
+	options.BreakerCooldown = time.Hour
+	options.NetworkPassphrase = "Test SDF Network ; September 2015"
+	h := NewWithOptions([]string{primary.URL, secondary.URL}, options)
+
+	account, err := h.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", account.SequenceNumber)
+	assert.Equal(t, 1, primaryRequests)
+	assert.Equal(t, 1, secondaryRequests)
+
+	// Primary circuit breaker is open
+	account, err = h.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, primaryRequests)
+	assert.Equal(t, "2", account.SequenceNumber)
+
+	// Health check brings primary back
+	primaryDown = false
+	h.checkHealth()
+	account, err = h.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", account.SequenceNumber)
+
+	// Submission fails over and finds transaction applied before failover
+	var source xdr.AccountId
+	source.SetAddress("GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ")
+	envelope, _ := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Tx: xdr.Transaction{SourceAccount: source, Fee: 100, SeqNum: 1},
+	})
+
+	primaryDown = true
+	response, err := h.SubmitTransaction(envelope)
+	assert.NoError(t, err)
+	if assert.NotNil(t, response.Ledger) {
+		assert.Equal(t, uint64(123), *response.Ledger)
+	}
+}
+
+func TestHorizonRateLimit(t *testing.T) {
+	requests := 0
+	remaining := "50"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", remaining)
+		w.Header().Set("X-RateLimit-Reset", "60")
+		w.Write([]byte(`{"id":"GABC","sequence":"1"}`))
+	}))
+	defer server.Close()
+
+	options := DefaultOptions
+	options.RateLimitReserve = 20
+	h := NewWithOptions([]string{server.URL}, options)
+	now := time.Now()
+	h.endpoints[0].rateLimit.now = func() time.Time { return now }
+	background := h.Background()
+
+	_, err := background.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(50), h.endpoints[0].rateLimit.remainingGauge.Value())
+
+	// 20 requests of 100 are reserved for critical requests
+	remaining = "20"
+	_, err = h.LoadAccount("GABC")
+	assert.NoError(t, err)
+	_, err = background.LoadAccount("GABC")
+	assert.Equal(t, ErrThrottled, err)
+	assert.Equal(t, 2, requests)
+
+	_, err = h.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	// Background requests are sent again after the limit is reset
+	now = now.Add(time.Minute)
+	_, err = background.LoadAccount("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, requests)
+}
+
+func TestHorizonLoadAccountFresh(t *testing.T) {
+	var cacheControl []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cacheControl = append(cacheControl, r.Header.Get("Cache-Control"))
+		w.Write([]byte(`{
+			"id": "GABC",
+			"sequence": "100",
+			"thresholds": {"low_threshold": 0, "med_threshold": 2, "high_threshold": 3},
+			"balances": [
+				{"balance": "10.0000000", "asset_type": "credit_alphanum4", "asset_code": "USD", "asset_issuer": "GISSUER", "is_authorized": false},
+				{"balance": "100.0000000", "asset_type": "native"}
+			],
+			"signers": [{"public_key": "GABC", "weight": 1}, {"key": "GSIGNER", "weight": 2}]
+		}`))
+	}))
+	defer server.Close()
+
+	h := New(server.URL)
+
+	_, err := h.LoadAccount("GABC")
+	assert.NoError(t, err)
+
+	account, err := h.LoadAccountFresh("GABC")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "no-cache"}, cacheControl)
+
+	assert.Equal(t, 2, account.Thresholds.MedThreshold)
+	assert.Equal(t, 1, account.SignerWeight("GABC"))
+	assert.Equal(t, 2, account.SignerWeight("GSIGNER"))
+	assert.Equal(t, 0, account.SignerWeight("GOTHER"))
+	assert.False(t, account.Balance("USD", "GISSUER").Authorized())
+	assert.True(t, account.Balance("", "").Authorized())
+	assert.Nil(t, account.Balance("EUR", "GISSUER"))
+}
+
+func TestHorizonLoadLiquidityPools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"_embedded": {"records": [
+			{"type": "account_debited", "amount": "10.0000000"},
+			{"type": "liquidity_pool_trade", "liquidity_pool": {"id": "dd7b1ab831c273310ddbec6f97870aa83c2fbd78ce22aded37ecbf4f3380fac7"}},
+			{"type": "trade", "amount": "5.0000000"},
+			{"type": "account_credited", "amount": "20.0000000"}
+		]}}`))
+	}))
+	defer server.Close()
+
+	h := New(server.URL)
+	payment := PaymentResponse{Type: "path_payment_strict_send"}
+	payment.Links.Effects.Href = server.URL + "/operations/1/effects"
+
+	assert.NoError(t, h.LoadLiquidityPools(&payment))
+	assert.Equal(t, []string{"dd7b1ab831c273310ddbec6f97870aa83c2fbd78ce22aded37ecbf4f3380fac7"}, payment.LiquidityPoolIDs)
+
+	payment.Type = "payment"
+	assert.Error(t, h.LoadLiquidityPools(&payment))
+}
+
+func TestHorizonFindPaths(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Write([]byte(`{"_embedded": {"records": [{"source_amount": "400.0000000", "destination_amount": "100.0000000", "path": [{"asset_type": "native"}]}]}}`))
+	}))
+	defer server.Close()
+
+	h := New(server.URL)
+	usd := NewPathAsset("USD", "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR")
+
+	paths, err := h.FindPaths(PathsRequest{SourceAsset: NewPathAsset("", ""), DestinationAsset: usd, DestinationAmount: "100.0000000"})
+	assert.NoError(t, err)
+	if assert.Len(t, paths, 1) {
+		assert.Equal(t, "400.0000000", paths[0].SourceAmount)
+		assert.Equal(t, []PathAsset{{AssetType: "native"}}, paths[0].Path)
+	}
+
+	_, err = h.FindPaths(PathsRequest{StrictSend: true, SourceAsset: usd, SourceAmount: "100.0000000", DestinationAsset: NewPathAsset("", "")})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/paths/strict-receive?destination_amount=100.0000000&destination_asset_code=USD&destination_asset_issuer=GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR&destination_asset_type=credit_alphanum4&source_assets=native",
+		"/paths/strict-send?destination_assets=native&source_amount=100.0000000&source_asset_code=USD&source_asset_issuer=GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR&source_asset_type=credit_alphanum4",
+	}, requests)
+}
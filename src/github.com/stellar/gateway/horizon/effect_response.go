@@ -11,4 +11,9 @@ type EffectsPageResponse struct {
 type EffectResponse struct {
 	Type   string `json:"type"`
 	Amount string `json:"amount"`
+
+	// liquidity_pool_trade
+	LiquidityPool struct {
+		ID string `json:"id"`
+	} `json:"liquidity_pool"`
 }
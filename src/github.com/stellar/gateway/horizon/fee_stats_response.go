@@ -0,0 +1,83 @@
+package horizon
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FeeStatsResponse contains fee stats of the last ledger returned by
+// /fee_stats endpoint
+type FeeStatsResponse struct {
+	LastLedger          string `json:"last_ledger"`
+	LastLedgerBaseFee   string `json:"last_ledger_base_fee"`
+	LedgerCapacityUsage string `json:"ledger_capacity_usage"`
+	MinAcceptedFee      string `json:"min_accepted_fee"`
+	ModeAcceptedFee     string `json:"mode_accepted_fee"`
+	P10AcceptedFee      string `json:"p10_accepted_fee"`
+	P20AcceptedFee      string `json:"p20_accepted_fee"`
+	P30AcceptedFee      string `json:"p30_accepted_fee"`
+	P40AcceptedFee      string `json:"p40_accepted_fee"`
+	P50AcceptedFee      string `json:"p50_accepted_fee"`
+	P60AcceptedFee      string `json:"p60_accepted_fee"`
+	P70AcceptedFee      string `json:"p70_accepted_fee"`
+	P80AcceptedFee      string `json:"p80_accepted_fee"`
+	P90AcceptedFee      string `json:"p90_accepted_fee"`
+	P95AcceptedFee      string `json:"p95_accepted_fee"`
+	P99AcceptedFee      string `json:"p99_accepted_fee"`
+}
+
+// IsValidFeePercentile returns true if accepted fee percentile is returned
+// by /fee_stats endpoint
+func IsValidFeePercentile(percentile int) bool {
+	switch percentile {
+	case 10, 20, 30, 40, 50, 60, 70, 80, 90, 95, 99:
+		return true
+	}
+	return false
+}
+
+// BaseFee returns base fee of the last ledger
+func (r FeeStatsResponse) BaseFee() (uint32, error) {
+	return parseFee("last_ledger_base_fee", r.LastLedgerBaseFee)
+}
+
+// CapacityUsage returns ledger capacity usage of the last ledger (0-1)
+func (r FeeStatsResponse) CapacityUsage() (float64, error) {
+	usage, err := strconv.ParseFloat(r.LedgerCapacityUsage, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid ledger_capacity_usage: %s", r.LedgerCapacityUsage)
+	}
+	return usage, nil
+}
+
+// AcceptedFee returns a given percentile of fees accepted in the last
+// ledgers, see IsValidFeePercentile
+func (r FeeStatsResponse) AcceptedFee(percentile int) (uint32, error) {
+	values := map[int]string{
+		10: r.P10AcceptedFee,
+		20: r.P20AcceptedFee,
+		30: r.P30AcceptedFee,
+		40: r.P40AcceptedFee,
+		50: r.P50AcceptedFee,
+		60: r.P60AcceptedFee,
+		70: r.P70AcceptedFee,
+		80: r.P80AcceptedFee,
+		90: r.P90AcceptedFee,
+		95: r.P95AcceptedFee,
+		99: r.P99AcceptedFee,
+	}
+
+	value, ok := values[percentile]
+	if !ok {
+		return 0, fmt.Errorf("Invalid fee percentile: %d", percentile)
+	}
+	return parseFee(fmt.Sprintf("p%d_accepted_fee", percentile), value)
+}
+
+func parseFee(name, value string) (uint32, error) {
+	fee, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s: %s", name, value)
+	}
+	return uint32(fee), nil
+}
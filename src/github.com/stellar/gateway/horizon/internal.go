@@ -37,11 +37,9 @@ func parseEvent(data []byte) (result sse.Event, err error) {
 	return
 }
 
+// splitSSE splits stream into events. Complete events buffered when the
+// stream ends are still returned.
 func splitSSE(data []byte, atEOF bool) (advance int, token []byte, err error) {
-	if atEOF {
-		return 0, nil, nil
-	}
-
 	if loc := endEvent.FindIndex(data); loc != nil {
 		return loc[1], data[0:loc[1]], nil
 	}
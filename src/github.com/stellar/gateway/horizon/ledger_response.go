@@ -0,0 +1,16 @@
+package horizon
+
+import "time"
+
+// LedgersPageResponse contains page of ledgers returned by Horizon
+type LedgersPageResponse struct {
+	Embedded struct {
+		Records []LedgerResponse
+	} `json:"_embedded"`
+}
+
+// LedgerResponse contains ledger data returned by Horizon
+type LedgerResponse struct {
+	Sequence int32     `json:"sequence"`
+	ClosedAt time.Time `json:"closed_at"`
+}
@@ -1,18 +1,17 @@
 package horizon
 
 import (
-	"bufio"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
-	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/stellar/go/network"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -23,24 +22,59 @@ type PaymentHandler func(PaymentResponse) error
 // HorizonInterface allows mocking Horizon struct object
 type HorizonInterface interface {
 	LoadAccount(accountID string) (response AccountResponse, err error)
+	LoadAccountFresh(accountID string) (response AccountResponse, err error)
 	LoadMemo(p *PaymentResponse) (err error)
 	LoadAccountMergeAmount(p *PaymentResponse) error
 	LoadOperation(operationID string) (response PaymentResponse, err error)
+	LoadTransaction(hash string) (response SubmitTransactionResponse, err error)
 	StreamPayments(accountID string, cursor *string, onPaymentHandler PaymentHandler) (err error)
 	SubmitTransaction(txeBase64 string) (response SubmitTransactionResponse, err error)
+	FindPaths(request PathsRequest) (paths []PathResponse, err error)
 }
 
 // Horizon implements methods to get (or submit) data from Horizon server
 type Horizon struct {
-	ServerURL string
-	log       *logrus.Entry
+	// ServerURL is a URL of the primary Horizon server
+	ServerURL    string
+	endpoints    []*endpoint
+	options      Options
+	client       *http.Client
+	submitClient *http.Client
+	streamClient *http.Client
+	log          *logrus.Entry
+	// sleep is used to wait between retries and stream reconnects, replaced in tests
+	sleep func(time.Duration)
+	// Congestion stretches delays between retries during network congestion
+	// when set
+	Congestion RetryDelayStretcher
+	// stream is a state of payments stream
+	stream *streamState
+	// background is true in views returned by Background
+	background bool
 }
 
-const submitTimeout = 60 * time.Second
+// RetryDelayStretcher stretches retry delays, see congestion.Monitor
+type RetryDelayStretcher interface {
+	StretchRetryDelay(delay time.Duration) time.Duration
+}
 
-// New creates a new Horizon instance
+// New creates a new Horizon instance using DefaultOptions
 func New(serverURL string) (horizon Horizon) {
-	horizon.ServerURL = serverURL
+	return NewWithOptions([]string{serverURL}, DefaultOptions)
+}
+
+// NewWithOptions creates a new Horizon instance using given HTTP client
+// options. The first of serverURLs is the primary server, requests are sent
+// to the next servers only when previous ones fail.
+func NewWithOptions(serverURLs []string, options Options) (horizon Horizon) {
+	for _, serverURL := range serverURLs {
+		horizon.endpoints = append(horizon.endpoints, newEndpoint(serverURL, options))
+	}
+	horizon.ServerURL = horizon.endpoints[0].url
+	horizon.options = options
+	horizon.client, horizon.submitClient, horizon.streamClient = newHTTPClients(options)
+	horizon.sleep = time.Sleep
+	horizon.stream = &streamState{}
 	horizon.log = logrus.WithFields(logrus.Fields{
 		"service": "Horizon",
 	})
@@ -49,21 +83,25 @@ func New(serverURL string) (horizon Horizon) {
 
 // LoadAccount loads a single account from Horizon server
 func (h *Horizon) LoadAccount(accountID string) (response AccountResponse, err error) {
+	return h.loadAccount(accountID, h.get)
+}
+
+// LoadAccountFresh loads a single account from Horizon server bypassing HTTP
+// caches so the response reflects the last closed ledger
+func (h *Horizon) LoadAccountFresh(accountID string) (response AccountResponse, err error) {
+	return h.loadAccount(accountID, h.getFresh)
+}
+
+func (h *Horizon) loadAccount(accountID string, get func(rawURL string) (int, []byte, error)) (response AccountResponse, err error) {
 	h.log.WithFields(logrus.Fields{
 		"accountID": accountID,
 	}).Info("Loading account")
-	resp, err := http.Get(h.ServerURL + "/accounts/" + accountID)
+	statusCode, body, err := get(h.ServerURL + "/accounts/" + accountID)
 	if err != nil {
 		return
 	}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-
-	if resp.StatusCode != 200 {
+	if statusCode != 200 {
 		h.log.WithFields(logrus.Fields{
 			"accountID": accountID,
 		}).Info("Account does not exist")
@@ -87,18 +125,12 @@ func (h *Horizon) LoadOperation(operationID string) (response PaymentResponse, e
 	h.log.WithFields(logrus.Fields{
 		"operationID": operationID,
 	}).Info("Loading operation")
-	resp, err := http.Get(h.ServerURL + "/operations/" + operationID)
+	statusCode, body, err := h.get(h.ServerURL + "/operations/" + operationID)
 	if err != nil {
 		return
 	}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return
-	}
-
-	if resp.StatusCode != 200 {
+	if statusCode != 200 {
 		h.log.WithFields(logrus.Fields{
 			"operationID": operationID,
 		}).Error("Operation does not exist")
@@ -117,14 +149,126 @@ func (h *Horizon) LoadOperation(operationID string) (response PaymentResponse, e
 	return
 }
 
+// ErrTransactionNotFound is returned by LoadTransaction when the transaction
+// has not been included in a ledger (or Horizon has not ingested it yet)
+var ErrTransactionNotFound = errors.New("Transaction not found")
+
+// LoadTransaction loads a single transaction from Horizon server by its hash
+func (h *Horizon) LoadTransaction(hash string) (response SubmitTransactionResponse, err error) {
+	statusCode, body, err := h.get(h.ServerURL + "/transactions/" + hash)
+	if err != nil {
+		return
+	}
+
+	if statusCode == 404 {
+		err = ErrTransactionNotFound
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("StatusCode indicates error: %s", body)
+		return
+	}
+
+	err = json.Unmarshal(body, &response)
+	return
+}
+
+// LoadFeeStats loads fee stats of the last ledgers from Horizon server
+func (h *Horizon) LoadFeeStats() (response FeeStatsResponse, err error) {
+	statusCode, body, err := h.get(h.ServerURL + "/fee_stats")
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("StatusCode indicates error: %s", body)
+		return
+	}
+
+	err = json.Unmarshal(body, &response)
+	return
+}
+
+// FindPaths finds payment paths using Horizon strict send or strict receive
+// path finding. Prices change with every ledger so responses are never
+// cached.
+func (h *Horizon) FindPaths(request PathsRequest) (paths []PathResponse, err error) {
+	params := url.Values{}
+	endpoint := "/paths/strict-receive"
+	if request.StrictSend {
+		endpoint = "/paths/strict-send"
+		request.SourceAsset.addParams(params, "source")
+		params.Set("source_amount", request.SourceAmount)
+		params.Set("destination_assets", request.DestinationAsset.String())
+	} else {
+		params.Set("source_assets", request.SourceAsset.String())
+		request.DestinationAsset.addParams(params, "destination")
+		params.Set("destination_amount", request.DestinationAmount)
+	}
+
+	statusCode, body, err := h.getFresh(h.ServerURL + endpoint + "?" + params.Encode())
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("StatusCode indicates error: %s", body)
+		return
+	}
+
+	var page PathsPageResponse
+	err = json.Unmarshal(body, &page)
+	if err != nil {
+		return
+	}
+	return page.Embedded.Records, nil
+}
+
+// LoadLatestLedger loads the last ledger ingested by Horizon server
+func (h *Horizon) LoadLatestLedger() (response LedgerResponse, err error) {
+	statusCode, body, err := h.getFresh(h.ServerURL + "/ledgers?order=desc&limit=1")
+	if err != nil {
+		return
+	}
+
+	if statusCode != 200 {
+		err = fmt.Errorf("StatusCode indicates error: %s", body)
+		return
+	}
+
+	var page LedgersPageResponse
+	err = json.Unmarshal(body, &page)
+	if err != nil {
+		return
+	}
+
+	if len(page.Embedded.Records) == 0 {
+		err = errors.New("No ledgers returned")
+		return
+	}
+	return page.Embedded.Records[0], nil
+}
+
+// LoadLatestLedgerClosedAt returns close time of the last ledger ingested by
+// Horizon server, see clock.DriftMonitor
+func (h *Horizon) LoadLatestLedgerClosedAt() (time.Time, error) {
+	ledger, err := h.LoadLatestLedger()
+	return ledger.ClosedAt, err
+}
+
 // LoadMemo loads memo for a transaction in PaymentResponse
 func (h *Horizon) LoadMemo(p *PaymentResponse) (err error) {
-	res, err := http.Get(p.Links.Transaction.Href)
+	statusCode, body, err := h.get(p.Links.Transaction.Href)
 	if err != nil {
 		return err
 	}
-	defer res.Body.Close()
-	return json.NewDecoder(res.Body).Decode(&p.Memo)
+
+	if statusCode != 200 {
+		return fmt.Errorf("StatusCode indicates error: %s", body)
+	}
+
+	return json.Unmarshal(body, &p.Memo)
 }
 
 // LoadAccountMergeAmount loads `account_merge` operation amount from it's effects
@@ -133,13 +277,17 @@ func (h *Horizon) LoadAccountMergeAmount(p *PaymentResponse) error {
 		return errors.New("Not `account_merge` operation")
 	}
 
-	res, err := http.Get(p.Links.Effects.Href)
+	statusCode, body, err := h.get(p.Links.Effects.Href)
 	if err != nil {
 		return errors.Wrap(err, "Error getting effects for operation")
 	}
-	defer res.Body.Close()
+
+	if statusCode != 200 {
+		return fmt.Errorf("StatusCode indicates error: %s", body)
+	}
+
 	var page EffectsPageResponse
-	err = json.NewDecoder(res.Body).Decode(&page)
+	err = json.Unmarshal(body, &page)
 	if err != nil {
 		return errors.Wrap(err, "Error decoding effects page")
 	}
@@ -154,88 +302,70 @@ func (h *Horizon) LoadAccountMergeAmount(p *PaymentResponse) error {
 	return errors.New("Could not find `account_credited` effect in `account_merge` operation effects")
 }
 
-// StreamPayments streams incoming payments
-func (h *Horizon) StreamPayments(accountID string, cursor *string, onPaymentHandler PaymentHandler) (err error) {
-	url := h.ServerURL + "/accounts/" + accountID + "/payments"
-	if cursor != nil {
-		url += "?cursor=" + *cursor
+// LoadLiquidityPools loads IDs of liquidity pools a path payment was routed
+// through from `liquidity_pool_trade` effects of the operation
+func (h *Horizon) LoadLiquidityPools(p *PaymentResponse) error {
+	if !p.IsPathPayment() {
+		return errors.New("Not a path payment operation")
 	}
 
-	req, _ := http.NewRequest("GET", url, nil)
+	statusCode, body, err := h.get(p.Links.Effects.Href)
 	if err != nil {
-		return
+		return errors.Wrap(err, "Error getting effects for operation")
 	}
-	req.Header.Set("Accept", "text/event-stream")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	if statusCode != 200 {
+		return fmt.Errorf("StatusCode indicates error: %s", body)
 	}
-	defer resp.Body.Close()
-	scanner := bufio.NewScanner(resp.Body)
-	scanner.Split(splitSSE)
 
-	for scanner.Scan() {
-		if len(scanner.Bytes()) == 0 {
-			continue
-		}
-
-		ev, err := parseEvent(scanner.Bytes())
-		if err != nil {
-			return err
-		}
-
-		if ev.Event != "message" {
-			continue
-		}
-
-		var payment PaymentResponse
-		data := ev.Data.(string)
-		err = json.Unmarshal([]byte(data), &payment)
-		if err != nil {
-			return err
-		}
-
-		for {
-			err = onPaymentHandler(payment)
-			if err != nil {
-				h.log.Error("Error from onPaymentHandler: ", err)
-				h.log.Info("Sleeping...")
-				time.Sleep(10 * time.Second)
-			} else {
-				break
-			}
-		}
-	}
-
-	err = scanner.Err()
-	if err == io.ErrUnexpectedEOF {
-		h.log.Info("Streaming connection closed.")
-		return nil
-	}
+	var page EffectsPageResponse
+	err = json.Unmarshal(body, &page)
 	if err != nil {
-		return err
+		return errors.Wrap(err, "Error decoding effects page")
 	}
 
+	p.LiquidityPoolIDs = nil
+	for _, effect := range page.Embedded.Records {
+		if effect.Type == "liquidity_pool_trade" && effect.LiquidityPool.ID != "" {
+			p.LiquidityPoolIDs = append(p.LiquidityPoolIDs, effect.LiquidityPool.ID)
+		}
+	}
 	return nil
 }
 
-// SubmitTransaction submits a transaction to Stellar network via Horizon server
+// SubmitTransaction submits a transaction to Stellar network via Horizon
+// server. On network errors and 5xx responses the transaction is submitted to
+// the next Horizon server. If it's rejected there with tx_bad_seq the earlier
+// submission may have succeeded, so the transaction is searched by hash.
 func (h *Horizon) SubmitTransaction(txeBase64 string) (response SubmitTransactionResponse, err error) {
 	v := url.Values{}
 	v.Set("tx", txeBase64)
 
-	client := http.Client{
-		Timeout: submitTimeout,
-	}
-	resp, err := client.PostForm(h.ServerURL+"/transactions", v)
-	if err != nil {
-		return
+	var body []byte
+	var previousFailed bool
+	err = ErrUnavailable
+
+	for _, e := range h.endpoints {
+		if previousFailed {
+			clientMetrics.failovers.Inc()
+			h.log.WithFields(logrus.Fields{"endpoint": e.url}).Warn("Submitting transaction to the next Horizon endpoint")
+		}
+
+		submitURL := e.url + "/transactions"
+		statusCode, responseBody, requestErr := h.do(e, func() (*http.Response, error) {
+			return h.submitClient.PostForm(submitURL, v)
+		})
+		if requestErr == ErrUnavailable {
+			continue
+		}
+
+		body, err = responseBody, requestErr
+		if !retryable(statusCode, err) {
+			break
+		}
+		previousFailed = true
 	}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return
 	}
@@ -248,20 +378,71 @@ func (h *Horizon) SubmitTransaction(txeBase64 string) (response SubmitTransactio
 		return
 	}
 
+	if previousFailed && IsBadSequence(response) {
+		submitted, found := h.loadSubmittedTransaction(txeBase64)
+		if found {
+			h.log.WithFields(logrus.Fields{"hash": submitted.Hash}).Info("Transaction submitted to the previous Horizon endpoint has been found")
+			response = submitted
+		}
+	}
+
 	if response.Ledger != nil {
 		h.log.WithFields(logrus.Fields{
 			"ledger": *response.Ledger,
 		}).Info("Success response from horizon")
-	} else {
+	} else if response.Extras != nil {
 		h.log.WithFields(logrus.Fields{
 			"envelope": response.Extras.EnvelopeXdr,
 			"result":   response.Extras.ResultXdr,
 		}).Info("Error response from horizon")
+	} else {
+		h.log.WithFields(logrus.Fields{
+			"body": string(body),
+		}).Info("Error response from horizon")
 	}
 
 	return
 }
 
+// loadSubmittedTransaction loads transaction from Horizon by the hash of the
+// envelope. It requires Options.NetworkPassphrase.
+func (h *Horizon) loadSubmittedTransaction(txeBase64 string) (response SubmitTransactionResponse, found bool) {
+	if h.options.NetworkPassphrase == "" {
+		return
+	}
+
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(txeBase64, &envelope)
+	if err != nil {
+		return
+	}
+
+	hash, err := network.HashTransaction(&envelope.Tx, h.options.NetworkPassphrase)
+	if err != nil {
+		return
+	}
+
+	response, err = h.LoadTransaction(hex.EncodeToString(hash[:]))
+	if err != nil || response.Ledger == nil {
+		return
+	}
+
+	return response, true
+}
+
+// IsBadSequence returns true when transaction failed with tx_bad_seq result code
+func IsBadSequence(response SubmitTransactionResponse) bool {
+	codes, err := response.ResultCodes()
+	return err == nil && codes != nil && codes.TransactionCode == TxBadSeq
+}
+
+// IsInsufficientFee returns true when transaction failed with
+// tx_insufficient_fee result code
+func IsInsufficientFee(response SubmitTransactionResponse) bool {
+	codes, err := response.ResultCodes()
+	return err == nil && codes != nil && codes.TransactionCode == TxInsufficientFee
+}
+
 func unmarshalTransactionResult(transactionResult string) (txResult xdr.TransactionResult, err error) {
 	reader := strings.NewReader(transactionResult)
 	b64r := base64.NewDecoder(base64.StdEncoding, reader)
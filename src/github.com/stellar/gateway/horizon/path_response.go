@@ -0,0 +1,72 @@
+package horizon
+
+import "net/url"
+
+// PathAsset is an asset of a path returned by Horizon path finding
+type PathAsset struct {
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer"`
+}
+
+// NewPathAsset creates a PathAsset of a credit asset or native asset when
+// code and issuer are empty
+func NewPathAsset(code, issuer string) PathAsset {
+	switch {
+	case code == "" && issuer == "":
+		return PathAsset{AssetType: "native"}
+	case len(code) <= 4:
+		return PathAsset{AssetType: "credit_alphanum4", AssetCode: code, AssetIssuer: issuer}
+	default:
+		return PathAsset{AssetType: "credit_alphanum12", AssetCode: code, AssetIssuer: issuer}
+	}
+}
+
+// String returns the asset in format of Horizon source_assets and
+// destination_assets params: native or CODE:ISSUER
+func (a PathAsset) String() string {
+	if a.AssetType == "native" {
+		return "native"
+	}
+	return a.AssetCode + ":" + a.AssetIssuer
+}
+
+func (a PathAsset) addParams(params url.Values, prefix string) {
+	params.Set(prefix+"_asset_type", a.AssetType)
+	if a.AssetType != "native" {
+		params.Set(prefix+"_asset_code", a.AssetCode)
+		params.Set(prefix+"_asset_issuer", a.AssetIssuer)
+	}
+}
+
+// PathsRequest is a request of Horizon path finding. With StrictSend paths
+// sending SourceAmount are found, otherwise paths receiving
+// DestinationAmount.
+type PathsRequest struct {
+	StrictSend        bool
+	SourceAsset       PathAsset
+	SourceAmount      string
+	DestinationAsset  PathAsset
+	DestinationAmount string
+}
+
+// PathsPageResponse contains page of paths returned by Horizon
+type PathsPageResponse struct {
+	Embedded struct {
+		Records []PathResponse
+	} `json:"_embedded"`
+}
+
+// PathResponse contains a path found by Horizon with amounts sent and
+// received
+type PathResponse struct {
+	SourceAssetType        string      `json:"source_asset_type"`
+	SourceAssetCode        string      `json:"source_asset_code"`
+	SourceAssetIssuer      string      `json:"source_asset_issuer"`
+	SourceAmount           string      `json:"source_amount"`
+	DestinationAssetType   string      `json:"destination_asset_type"`
+	DestinationAssetCode   string      `json:"destination_asset_code"`
+	DestinationAssetIssuer string      `json:"destination_asset_issuer"`
+	DestinationAmount      string      `json:"destination_amount"`
+	Path                   []PathAsset `json:"path"`
+}
@@ -1,5 +1,7 @@
 package horizon
 
+import "strconv"
+
 // PaymentResponse contains a single payment data returned by Horizon
 type PaymentResponse struct {
 	ID          string `json:"id"`
@@ -15,18 +17,27 @@ type PaymentResponse struct {
 		} `json:"effects"`
 	} `json:"_links"`
 
-	// payment/path_payment fields
+	// payment/path_payment_strict_receive/path_payment_strict_send fields
 	From        string `json:"from"`
 	To          string `json:"to"`
 	AssetType   string `json:"asset_type"`
 	AssetCode   string `json:"asset_code"`
 	AssetIssuer string `json:"asset_issuer"`
 	Amount      string `json:"amount"`
+	// ToMuxed and ToMuxedID are set when To is a muxed account (M...)
+	ToMuxed   string `json:"to_muxed"`
+	ToMuxedID string `json:"to_muxed_id"`
 
 	// account_merge
 	Account string `json:"account"`
 	Into    string `json:"into"`
 
+	// path payments, see LoadLiquidityPools
+	LiquidityPoolIDs []string `json:"-"`
+
+	// invoke_host_function, see LoadContractTransfer
+	AssetBalanceChanges []AssetBalanceChange `json:"asset_balance_changes"`
+
 	// transaction fields
 	Memo struct {
 		Type  string `json:"memo_type"`
@@ -35,3 +46,90 @@ type PaymentResponse struct {
 
 	TransactionID string `json:"transaction_hash"`
 }
+
+// AssetBalanceChange is a change of a balance by a Stellar Asset Contract
+// (SAC) in invoke_host_function operation
+type AssetBalanceChange struct {
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer"`
+	// Type is transfer, mint, burn or clawback
+	Type   string `json:"type"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+}
+
+// LoadContractTransfer sets From, To, asset and Amount of invoke_host_function
+// operation from its first SAC transfer to one of addresses (accounts or
+// contracts). It returns false when the operation is not
+// invoke_host_function or it does not transfer to addresses. Payments
+// returned by ContractTransfers contain a single transfer.
+func (p *PaymentResponse) LoadContractTransfer(addresses ...string) bool {
+	if p.Type != "invoke_host_function" {
+		return false
+	}
+
+	for _, change := range p.AssetBalanceChanges {
+		if !change.transfersTo(addresses) {
+			continue
+		}
+
+		p.From, p.To = change.From, change.To
+		p.AssetType, p.AssetCode, p.AssetIssuer = change.AssetType, change.AssetCode, change.AssetIssuer
+		p.Amount = change.Amount
+		return true
+	}
+	return false
+}
+
+// ContractTransfers returns a payment for every SAC transfer to one of
+// addresses in invoke_host_function operation. ID of a payment is
+// ContractTransferID of the operation and the index of the balance change,
+// AssetBalanceChanges contains the transfer only.
+func (p PaymentResponse) ContractTransfers(addresses ...string) (payments []PaymentResponse) {
+	if p.Type != "invoke_host_function" {
+		return nil
+	}
+
+	for i, change := range p.AssetBalanceChanges {
+		if !change.transfersTo(addresses) {
+			continue
+		}
+
+		payment := p
+		payment.ID = ContractTransferID(p.ID, int64(i))
+		payment.AssetBalanceChanges = []AssetBalanceChange{change}
+		payment.LoadContractTransfer(addresses...)
+		payments = append(payments, payment)
+	}
+	return
+}
+
+// ContractTransferID returns the ID of a payment of SAC transfer: the ID of
+// invoke_host_function operation and the index of the transfer in it, ex.
+// 12884905985-1. An operation can transfer to the receiving account many
+// times.
+func ContractTransferID(operationID string, index int64) string {
+	return operationID + "-" + strconv.FormatInt(index, 10)
+}
+
+func (c AssetBalanceChange) transfersTo(addresses []string) bool {
+	return c.Type == "transfer" && containsAddress(addresses, c.To)
+}
+
+func containsAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPathPayment returns true when the operation is a path payment. Horizon
+// before protocol 12 and stellarcore.DatabaseStream report path_payment type,
+// later Horizon versions report its strict receive or strict send variant.
+func (p PaymentResponse) IsPathPayment() bool {
+	return p.Type == "path_payment" || p.Type == "path_payment_strict_receive" || p.Type == "path_payment_strict_send"
+}
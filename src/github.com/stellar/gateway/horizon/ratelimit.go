@@ -0,0 +1,87 @@
+package horizon
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/go/support/errors"
+)
+
+// ErrThrottled is returned without sending a background request when the
+// remaining Horizon rate limit is reserved for critical requests
+var ErrThrottled = errors.New("Horizon request budget is reserved for critical requests")
+
+// Background returns a view of h sharing its endpoints, circuit breakers and
+// rate limit budgets, used for non-critical requests (ex. balance checks,
+// fee stats, clock drift checks and exports). Its requests fail fast with
+// ErrThrottled when the remaining rate limit of all endpoints is within
+// Options.RateLimitReserve, so submissions and destination checks sent by h
+// are not rejected by Horizon with 429 responses.
+func (h *Horizon) Background() *Horizon {
+	background := *h
+	background.background = true
+	return &background
+}
+
+// rateLimit is a request budget of an endpoint read from X-RateLimit-*
+// headers of Horizon responses
+type rateLimit struct {
+	now            func() time.Time
+	remainingGauge *metrics.Gauge
+
+	mutex     sync.Mutex
+	limit     int
+	remaining int
+	resetAt   time.Time
+}
+
+func newRateLimit(remainingGauge *metrics.Gauge) *rateLimit {
+	return &rateLimit{now: clock.Now, remainingGauge: remainingGauge}
+}
+
+// update reads the budget from response headers. Responses without
+// X-RateLimit-* headers (ex. Horizon with rate limiting disabled) are ignored.
+func (r *rateLimit) update(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	// Reset is the number of seconds until the limit is reset
+	reset, err := strconv.Atoi(header.Get("X-RateLimit-Reset"))
+	if err != nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.limit = limit
+	r.remaining = remaining
+	r.resetAt = r.now().Add(time.Duration(reset) * time.Second)
+	r.remainingGauge.Set(float64(remaining))
+}
+
+// allowBackground returns true if more than reservePercent of the limit
+// remains or the limit has been reset since the last response
+func (r *rateLimit) allowBackground(reservePercent int) bool {
+	if reservePercent == 0 {
+		return true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.limit == 0 || !r.now().Before(r.resetAt) {
+		return true
+	}
+
+	return r.remaining*100 > r.limit*reservePercent
+}
@@ -0,0 +1,238 @@
+package horizon
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// Problem contains fields of a problem (application/problem+json) response
+// returned by Horizon
+type Problem struct {
+	Type   string `json:"type,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Status int    `json:"status,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// TransactionResultCodes contains result codes of a failed transaction
+// (`extras.result_codes` object of transaction_failed problem)
+type TransactionResultCodes struct {
+	TransactionCode string               `json:"transaction"`
+	OperationCodes  OperationResultCodes `json:"operations,omitempty"`
+}
+
+// OperationResultCodes contains result codes of transaction operations, in
+// the same order as operations in the transaction
+type OperationResultCodes []string
+
+const (
+	// TxBadSeq is a result code of transaction with invalid sequence number
+	TxBadSeq = "tx_bad_seq"
+	// TxInsufficientFee is a result code of transaction with fee lower than
+	// required by the network
+	TxInsufficientFee = "tx_insufficient_fee"
+	// TxFailed is a result code of transaction with failed operations
+	TxFailed = "tx_failed"
+	// OpSuccess is a result code of successful operation
+	OpSuccess = "op_success"
+	// OpUnknown is returned by ResultCodes for operation results it cannot decode
+	OpUnknown = "op_unknown"
+)
+
+var transactionResultCodes = map[xdr.TransactionResultCode]string{
+	xdr.TransactionResultCodeTxSuccess:             "tx_success",
+	xdr.TransactionResultCodeTxFailed:              TxFailed,
+	xdr.TransactionResultCodeTxTooEarly:            "tx_too_early",
+	xdr.TransactionResultCodeTxTooLate:             "tx_too_late",
+	xdr.TransactionResultCodeTxMissingOperation:    "tx_missing_operation",
+	xdr.TransactionResultCodeTxBadSeq:              TxBadSeq,
+	xdr.TransactionResultCodeTxBadAuth:             "tx_bad_auth",
+	xdr.TransactionResultCodeTxInsufficientBalance: "tx_insufficient_balance",
+	xdr.TransactionResultCodeTxNoAccount:           "tx_no_source_account",
+	xdr.TransactionResultCodeTxInsufficientFee:     TxInsufficientFee,
+	xdr.TransactionResultCodeTxBadAuthExtra:        "tx_bad_auth_extra",
+	xdr.TransactionResultCodeTxInternalError:       "tx_internal_error",
+}
+
+var operationResultCodes = map[xdr.OperationResultCode]string{
+	xdr.OperationResultCodeOpBadAuth:   "op_bad_auth",
+	xdr.OperationResultCodeOpNoAccount: "op_no_source_account",
+}
+
+var createAccountResultCodes = map[xdr.CreateAccountResultCode]string{
+	xdr.CreateAccountResultCodeCreateAccountSuccess:      OpSuccess,
+	xdr.CreateAccountResultCodeCreateAccountMalformed:    "op_malformed",
+	xdr.CreateAccountResultCodeCreateAccountUnderfunded:  "op_underfunded",
+	xdr.CreateAccountResultCodeCreateAccountLowReserve:   "op_low_reserve",
+	xdr.CreateAccountResultCodeCreateAccountAlreadyExist: "op_already_exists",
+}
+
+var paymentResultCodes = map[xdr.PaymentResultCode]string{
+	xdr.PaymentResultCodePaymentSuccess:          OpSuccess,
+	xdr.PaymentResultCodePaymentMalformed:        "op_malformed",
+	xdr.PaymentResultCodePaymentUnderfunded:      "op_underfunded",
+	xdr.PaymentResultCodePaymentSrcNoTrust:       "op_src_no_trust",
+	xdr.PaymentResultCodePaymentSrcNotAuthorized: "op_src_not_authorized",
+	xdr.PaymentResultCodePaymentNoDestination:    "op_no_destination",
+	xdr.PaymentResultCodePaymentNoTrust:          "op_no_trust",
+	xdr.PaymentResultCodePaymentNotAuthorized:    "op_not_authorized",
+	xdr.PaymentResultCodePaymentLineFull:         "op_line_full",
+	xdr.PaymentResultCodePaymentNoIssuer:         "op_no_issuer",
+}
+
+var pathPaymentResultCodes = map[xdr.PathPaymentResultCode]string{
+	xdr.PathPaymentResultCodePathPaymentSuccess:          OpSuccess,
+	xdr.PathPaymentResultCodePathPaymentMalformed:        "op_malformed",
+	xdr.PathPaymentResultCodePathPaymentUnderfunded:      "op_underfunded",
+	xdr.PathPaymentResultCodePathPaymentSrcNoTrust:       "op_src_no_trust",
+	xdr.PathPaymentResultCodePathPaymentSrcNotAuthorized: "op_src_not_authorized",
+	xdr.PathPaymentResultCodePathPaymentNoDestination:    "op_no_destination",
+	xdr.PathPaymentResultCodePathPaymentNoTrust:          "op_no_trust",
+	xdr.PathPaymentResultCodePathPaymentNotAuthorized:    "op_not_authorized",
+	xdr.PathPaymentResultCodePathPaymentLineFull:         "op_line_full",
+	xdr.PathPaymentResultCodePathPaymentNoIssuer:         "op_no_issuer",
+	xdr.PathPaymentResultCodePathPaymentTooFewOffers:     "op_too_few_offers",
+	xdr.PathPaymentResultCodePathPaymentOfferCrossSelf:   "op_cross_self",
+	xdr.PathPaymentResultCodePathPaymentOverSendmax:      "op_over_source_max",
+}
+
+var allowTrustResultCodes = map[xdr.AllowTrustResultCode]string{
+	xdr.AllowTrustResultCodeAllowTrustSuccess:          OpSuccess,
+	xdr.AllowTrustResultCodeAllowTrustMalformed:        "op_malformed",
+	xdr.AllowTrustResultCodeAllowTrustNoTrustLine:      "op_no_trust",
+	xdr.AllowTrustResultCodeAllowTrustTrustNotRequired: "op_not_required",
+	xdr.AllowTrustResultCodeAllowTrustCantRevoke:       "op_cant_revoke",
+}
+
+// Stable error codes returned by ErrorCode. They are the same as codes of
+// error responses returned by bridge server.
+var transactionErrorCodes = map[string]string{
+	TxBadSeq:                  "transaction_bad_seq",
+	"tx_bad_auth":             "transaction_bad_auth",
+	"tx_insufficient_balance": "transaction_insufficient_balance",
+	"tx_no_source_account":    "transaction_no_account",
+	"tx_insufficient_fee":     "transaction_insufficient_fee",
+	"tx_bad_auth_extra":       "transaction_bad_auth_extra",
+}
+
+var paymentErrorCodes = map[string]string{
+	"op_malformed":          "payment_malformed",
+	"op_underfunded":        "payment_underfunded",
+	"op_src_no_trust":       "payment_src_no_trust",
+	"op_src_not_authorized": "payment_src_not_authorized",
+	"op_no_destination":     "payment_no_destination",
+	"op_no_trust":           "payment_no_trust",
+	"op_not_authorized":     "payment_not_authorized",
+	"op_line_full":          "payment_line_full",
+	"op_no_issuer":          "payment_no_issuer",
+	"op_too_few_offers":     "payment_too_few_offers",
+	"op_cross_self":         "payment_offer_cross_self",
+	"op_over_source_max":    "payment_over_sendmax",
+	"op_low_reserve":        "payment_low_reserve",
+	"op_already_exists":     "payment_already_exists",
+}
+
+var allowTrustErrorCodes = map[string]string{
+	"op_malformed":    "allow_trust_malformed",
+	"op_no_trust":     "allow_trust_no_trustline",
+	"op_not_required": "allow_trust_trust_not_required",
+	"op_cant_revoke":  "allow_trust_cant_revoke",
+}
+
+// ResultCodes returns result codes of a failed transaction. They are taken
+// from `extras.result_codes` or decoded from result XDR when Horizon did not
+// send them. Returns nil when transaction has not failed.
+func (response *SubmitTransactionResponse) ResultCodes() (*TransactionResultCodes, error) {
+	if response.Ledger != nil || response.Extras == nil {
+		return nil, nil
+	}
+
+	if response.Extras.ResultCodes != nil {
+		return response.Extras.ResultCodes, nil
+	}
+
+	txResult, err := unmarshalTransactionResult(response.Extras.ResultXdr)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := &TransactionResultCodes{
+		TransactionCode: transactionResultCodes[txResult.Result.Code],
+	}
+
+	if txResult.Result.Results != nil {
+		for _, operationResult := range *txResult.Result.Results {
+			codes.OperationCodes = append(codes.OperationCodes, operationResultCode(operationResult))
+		}
+	}
+
+	return codes, nil
+}
+
+// ErrorCode returns a stable error code (ex. `payment_underfunded`) of a
+// failed transaction or empty string when transaction has not failed or the
+// result code is not known.
+func (response *SubmitTransactionResponse) ErrorCode() string {
+	codes, err := response.ResultCodes()
+	if err != nil || codes == nil {
+		return ""
+	}
+
+	if codes.TransactionCode != TxFailed {
+		return transactionErrorCodes[codes.TransactionCode]
+	}
+
+	// Operation codes are not unique between operation types, result XDR is
+	// used to find the type of the failed operation.
+	var operationResults []xdr.OperationResult
+	txResult, err := unmarshalTransactionResult(response.Extras.ResultXdr)
+	if err == nil && txResult.Result.Results != nil {
+		operationResults = *txResult.Result.Results
+	}
+
+	for i, code := range codes.OperationCodes {
+		if code == OpSuccess {
+			continue
+		}
+
+		if i < len(operationResults) && operationResults[i].Tr != nil &&
+			operationResults[i].Tr.Type == xdr.OperationTypeAllowTrust {
+			return allowTrustErrorCodes[code]
+		}
+		return paymentErrorCodes[code]
+	}
+
+	return ""
+}
+
+func operationResultCode(operationResult xdr.OperationResult) string {
+	if operationResult.Code != xdr.OperationResultCodeOpInner {
+		if code, ok := operationResultCodes[operationResult.Code]; ok {
+			return code
+		}
+		return OpUnknown
+	}
+
+	tr := operationResult.Tr
+	if tr == nil {
+		return OpUnknown
+	}
+
+	var code string
+	var ok bool
+
+	switch {
+	case tr.CreateAccountResult != nil:
+		code, ok = createAccountResultCodes[tr.CreateAccountResult.Code]
+	case tr.PaymentResult != nil:
+		code, ok = paymentResultCodes[tr.PaymentResult.Code]
+	case tr.PathPaymentResult != nil:
+		code, ok = pathPaymentResultCodes[tr.PathPaymentResult.Code]
+	case tr.AllowTrustResult != nil:
+		code, ok = allowTrustResultCodes[tr.AllowTrustResult.Code]
+	}
+
+	if !ok {
+		return OpUnknown
+	}
+	return code
+}
@@ -0,0 +1,86 @@
+package horizon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultCodesFromProblem(t *testing.T) {
+	body := `{
+  "type": "https://stellar.org/horizon-errors/transaction_failed",
+  "title": "Transaction Failed",
+  "status": 400,
+  "detail": "The transaction failed when submitted to the stellar network.",
+  "extras": {
+    "envelope_xdr": "AAAA",
+    "result_xdr": "AAAAAAAAAGT/////AAAAAQAAAAAAAAAB////+wAAAAA=",
+    "result_codes": {
+      "transaction": "tx_failed",
+      "operations": ["op_underfunded"]
+    }
+  }
+}`
+
+	var response SubmitTransactionResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &response))
+	require.NotNil(t, response.Problem)
+	assert.Equal(t, "Transaction Failed", response.Title)
+	assert.Equal(t, 400, response.Status)
+
+	codes, err := response.ResultCodes()
+	require.NoError(t, err)
+	assert.Equal(t, &TransactionResultCodes{TxFailed, OperationResultCodes{"op_underfunded"}}, codes)
+	assert.Equal(t, "payment_underfunded", response.ErrorCode())
+}
+
+func TestResultCodesFromXDR(t *testing.T) {
+	response := SubmitTransactionResponse{
+		Extras: &SubmitTransactionResponseExtras{
+			ResultXdr: "AAAAAAAAAGT/////AAAAAQAAAAAAAAAB////+wAAAAA=",
+		},
+	}
+	codes, err := response.ResultCodes()
+	require.NoError(t, err)
+	assert.Equal(t, &TransactionResultCodes{TxFailed, OperationResultCodes{"op_no_destination"}}, codes)
+	assert.Equal(t, "payment_no_destination", response.ErrorCode())
+
+	response.Extras.ResultXdr = "AAAAAAAAAAD////7AAAAAA=="
+	codes, err = response.ResultCodes()
+	require.NoError(t, err)
+	assert.Equal(t, &TransactionResultCodes{TransactionCode: TxBadSeq}, codes)
+	assert.Equal(t, "transaction_bad_seq", response.ErrorCode())
+	assert.True(t, IsBadSequence(response))
+
+	// The same operation code is mapped depending on operation type
+	results := []xdr.OperationResult{{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type:             xdr.OperationTypeAllowTrust,
+			AllowTrustResult: &xdr.AllowTrustResult{Code: xdr.AllowTrustResultCodeAllowTrustNoTrustLine},
+		},
+	}}
+	response.Extras.ResultXdr, err = xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &results,
+		},
+	})
+	require.NoError(t, err)
+	codes, err = response.ResultCodes()
+	require.NoError(t, err)
+	assert.Equal(t, OperationResultCodes{"op_no_trust"}, codes.OperationCodes)
+	assert.Equal(t, "allow_trust_no_trustline", response.ErrorCode())
+
+	_, err = (&SubmitTransactionResponse{Extras: &SubmitTransactionResponseExtras{ResultXdr: "invalid"}}).ResultCodes()
+	assert.Error(t, err)
+
+	ledger := uint64(1)
+	codes, err = (&SubmitTransactionResponse{Ledger: &ledger}).ResultCodes()
+	assert.NoError(t, err)
+	assert.Nil(t, codes)
+}
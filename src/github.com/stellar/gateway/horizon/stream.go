@@ -0,0 +1,257 @@
+package horizon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	// streamMinBackoff is a delay before the first reconnect
+	streamMinBackoff = time.Second
+	// streamMaxBackoff is the maximum delay between reconnects
+	streamMaxBackoff = time.Minute
+	// handlerRetryDelay is a delay between onPaymentHandler retries
+	handlerRetryDelay = 10 * time.Second
+)
+
+var streamMetrics = struct {
+	connected     *metrics.Gauge
+	connections   *metrics.Counter
+	errors        *metrics.Counter
+	events        *metrics.Counter
+	lastEvent     *metrics.Gauge
+	reconnectWait *metrics.Gauge
+}{
+	connected:     metrics.NewGauge("bridge_horizon_stream_connected", "1 when payments stream is connected to Horizon, 0 otherwise."),
+	connections:   metrics.NewCounter("bridge_horizon_stream_connections_total", "Number of connections (including reconnects) made to Horizon payments stream."),
+	errors:        metrics.NewCounter("bridge_horizon_stream_errors_total", "Number of errors that caused payments stream to reconnect."),
+	events:        metrics.NewCounter("bridge_horizon_stream_events_total", "Number of payments received from Horizon payments stream."),
+	lastEvent:     metrics.NewGauge("bridge_horizon_stream_last_event_timestamp_seconds", "Unix time of the last payment received from Horizon payments stream."),
+	reconnectWait: metrics.NewGauge("bridge_horizon_stream_reconnect_delay_seconds", "Delay before the next reconnect to Horizon payments stream."),
+}
+
+// StreamPayments streams incoming payments. When the connection is closed or
+// fails it reconnects with jittered exponential backoff and resumes from the
+// paging token of the last processed payment. It returns only when the
+// Horizon URL is invalid.
+func (h *Horizon) StreamPayments(accountID string, cursor *string, onPaymentHandler PaymentHandler) (err error) {
+	for _, e := range h.endpoints {
+		_, err = url.Parse(e.url)
+		if err != nil {
+			return errors.Wrap(err, "Invalid Horizon URL")
+		}
+	}
+
+	var lastCursor string
+	if cursor != nil {
+		lastCursor = *cursor
+	}
+
+	backoff := streamBackoff{min: streamMinBackoff, max: streamMaxBackoff}
+
+	for {
+		var received int
+		lastCursor, received, err = h.streamPayments(accountID, lastCursor, onPaymentHandler)
+		streamMetrics.connected.Set(0)
+		h.stream.set(false)
+
+		if received > 0 {
+			backoff.reset()
+		}
+
+		if err != nil {
+			streamMetrics.errors.Inc()
+			h.log.WithFields(logrus.Fields{"err": err}).Error("Error while streaming payments")
+		} else {
+			h.log.Info("Streaming connection closed.")
+		}
+
+		delay := backoff.next()
+		streamMetrics.reconnectWait.Set(delay.Seconds())
+		h.log.WithFields(logrus.Fields{
+			"cursor": lastCursor,
+			"delay":  delay.String(),
+		}).Info("Reconnecting to payments stream")
+		h.sleep(delay)
+	}
+}
+
+// streamPayments opens a single streaming connection and sends payments to
+// onPaymentHandler until the connection is closed. It returns the paging
+// token of the last processed payment and the number of processed payments.
+func (h *Horizon) streamPayments(accountID, cursor string, onPaymentHandler PaymentHandler) (lastCursor string, received int, err error) {
+	lastCursor = cursor
+
+	e := h.streamEndpoint()
+	streamURL := e.url + "/accounts/" + accountID + "/payments"
+	if cursor != "" {
+		streamURL += "?cursor=" + url.QueryEscape(cursor)
+	}
+
+	req, err := http.NewRequest("GET", streamURL, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if cursor != "" {
+		req.Header.Set("Last-Event-ID", cursor)
+	}
+
+	resp, err := h.streamClient.Do(req)
+	if err != nil {
+		e.breaker.failure()
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode >= 500 {
+			e.breaker.failure()
+		}
+		err = fmt.Errorf("StatusCode indicates error: %d", resp.StatusCode)
+		return
+	}
+
+	e.breaker.success()
+	e.requests.Inc()
+	h.log.WithFields(logrus.Fields{"endpoint": e.url}).Info("Connected to payments stream")
+
+	streamMetrics.connections.Inc()
+	streamMetrics.connected.Set(1)
+	h.stream.set(true)
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Split(splitSSE)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		ev, err := parseEvent(scanner.Bytes())
+		if err != nil {
+			return lastCursor, received, err
+		}
+
+		if ev.Event != "message" {
+			continue
+		}
+
+		var payment PaymentResponse
+		data := ev.Data.(string)
+		err = json.Unmarshal([]byte(data), &payment)
+		if err != nil {
+			return lastCursor, received, err
+		}
+
+		streamMetrics.events.Inc()
+		streamMetrics.lastEvent.Set(float64(time.Now().Unix()))
+
+		for {
+			err = onPaymentHandler(payment)
+			if err != nil {
+				h.log.Error("Error from onPaymentHandler: ", err)
+				h.log.Info("Sleeping...")
+				h.sleep(handlerRetryDelay)
+			} else {
+				break
+			}
+		}
+
+		received++
+		if ev.Id != "" {
+			lastCursor = ev.Id
+		} else if payment.PagingToken != "" {
+			lastCursor = payment.PagingToken
+		}
+	}
+
+	err = scanner.Err()
+	if err == io.ErrUnexpectedEOF {
+		// Stream has been closed by Horizon
+		err = nil
+	}
+	return
+}
+
+// StreamUpdatedAt returns the time payments stream was last known to be up
+// to date: now when it's connected, the time of disconnection otherwise. Zero
+// time is returned when the stream has never been connected.
+func (h *Horizon) StreamUpdatedAt() time.Time {
+	return h.stream.get()
+}
+
+// streamState tracks if payments stream is connected. Methods of nil
+// streamState are no-ops.
+type streamState struct {
+	mutex     sync.Mutex
+	connected bool
+	updatedAt time.Time
+}
+
+func (s *streamState) set(connected bool) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.connected = connected
+	s.updatedAt = time.Now()
+}
+
+func (s *streamState) get() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.connected {
+		return time.Now()
+	}
+	return s.updatedAt
+}
+
+// streamEndpoint returns the first endpoint with closed circuit breaker or
+// the primary endpoint when all are failing
+func (h *Horizon) streamEndpoint() *endpoint {
+	for _, e := range h.endpoints {
+		if e.breaker.closed() {
+			return e
+		}
+	}
+	return h.endpoints[0]
+}
+
+// streamBackoff calculates exponential delays between reconnects. Each delay
+// is randomized to [d/2, d] so multiple instances don't reconnect at once.
+type streamBackoff struct {
+	min     time.Duration
+	max     time.Duration
+	attempt uint
+}
+
+func (b *streamBackoff) next() time.Duration {
+	delay := b.max
+	if b.attempt < 32 && b.min<<b.attempt < b.max {
+		delay = b.min << b.attempt
+	}
+	b.attempt++
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func (b *streamBackoff) reset() {
+	b.attempt = 0
+}
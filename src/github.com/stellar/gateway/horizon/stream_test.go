@@ -0,0 +1,87 @@
+package horizon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamPayments(t *testing.T) {
+	var requestedCursors, lastEventIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/accounts/GABC/payments", r.URL.Path)
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		requestedCursors = append(requestedCursors, r.URL.Query().Get("cursor"))
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "retry: 1000\nevent: open\ndata: \"hello\"\n\n")
+		fmt.Fprint(w, "id: 100\ndata: {\"id\":\"100\",\"paging_token\":\"100\"}\n\n")
+		fmt.Fprint(w, "id: 200\ndata: {\"id\":\"200\",\"paging_token\":\"200\"}\n\n")
+	}))
+	defer server.Close()
+
+	h := New(server.URL)
+	var sleeps []time.Duration
+	h.sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+	}
+
+	var ids []string
+	failures := 1
+	assert.True(t, h.StreamUpdatedAt().IsZero())
+	handler := func(payment PaymentResponse) error {
+		// Stream is up to date while it's connected
+		assert.WithinDuration(t, time.Now(), h.StreamUpdatedAt(), time.Second)
+		if failures > 0 {
+			failures--
+			return fmt.Errorf("handler error")
+		}
+		ids = append(ids, payment.ID)
+		return nil
+	}
+
+	cursor, received, err := h.streamPayments("GABC", "now", handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "200", cursor)
+	assert.Equal(t, 2, received)
+	assert.Equal(t, []string{"100", "200"}, ids)
+	assert.Equal(t, []time.Duration{handlerRetryDelay}, sleeps)
+
+	// Reconnect resumes from the last processed payment
+	cursor, received, err = h.streamPayments("GABC", cursor, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"now", "200"}, requestedCursors)
+	assert.Equal(t, []string{"now", "200"}, lastEventIDs)
+}
+
+func TestStreamPaymentsErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	h := New(server.URL)
+	cursor, received, err := h.streamPayments("GABC", "123", func(PaymentResponse) error { return nil })
+	assert.Error(t, err)
+	assert.Equal(t, "123", cursor)
+	assert.Equal(t, 0, received)
+}
+
+func TestStreamBackoff(t *testing.T) {
+	backoff := streamBackoff{min: time.Second, max: 10 * time.Second}
+
+	for _, expected := range []time.Duration{1, 2, 4, 8, 10, 10} {
+		delay := backoff.next()
+		assert.True(t, delay >= expected*time.Second/2, "delay %s too short", delay)
+		assert.True(t, delay <= expected*time.Second, "delay %s too long", delay)
+	}
+
+	backoff.reset()
+	assert.True(t, backoff.next() <= time.Second)
+}
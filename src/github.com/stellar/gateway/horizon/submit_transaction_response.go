@@ -11,6 +11,15 @@ type SubmitTransactionResponse struct {
 	ResultXdr  *string                          `json:"result_xdr,omitempty"`  // Only success response.
 	Ledger     *uint64                          `json:"ledger"`
 	Extras     *SubmitTransactionResponseExtras `json:"extras,omitempty"`
+	// InferredMemoType is set by bridge server when memo_type of the payment was inferred
+	InferredMemoType string `json:"inferred_memo_type,omitempty"`
+	// Congestion is set by bridge server when the transaction was submitted
+	// during network congestion
+	Congestion *CongestionNotice `json:"congestion,omitempty"`
+	// Fee is set by bridge server when a fee was charged for the payment
+	Fee *PaymentFee `json:"fee,omitempty"`
+	// Problem is set when Horizon returned an error
+	*Problem
 }
 
 // HTTPStatus implements protocols.SuccessResponse interface
@@ -24,8 +33,38 @@ func (response *SubmitTransactionResponse) Marshal() []byte {
 	return json
 }
 
+// PaymentFee is a fee charged by the bridge server for a payment, amounts are
+// in the asset of the payment
+type PaymentFee struct {
+	// Mode is "add" (fee paid on top of the amount) or "deduct" (fee netted
+	// from the amount)
+	Mode string `json:"mode"`
+	// Account receiving the fee, empty when a deducted fee stays on the
+	// source account
+	Account     string `json:"account,omitempty"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer,omitempty"`
+	// GrossAmount is the amount debited from the source account
+	GrossAmount string `json:"gross_amount"`
+	Fee         string `json:"fee"`
+	// NetAmount is the amount received by the destination
+	NetAmount string `json:"net_amount"`
+}
+
+// CongestionNotice informs clients that the transaction was submitted
+// during network congestion with a higher fee
+type CongestionNotice struct {
+	Message             string  `json:"message"`
+	LedgerCapacityUsage float64 `json:"ledger_capacity_usage"`
+	// FeePerOperation is a fee per operation (in stroops) used by bridge server
+	FeePerOperation uint32 `json:"fee_per_operation"`
+}
+
 // SubmitTransactionResponseExtras contains extra information returned by Horizon
 type SubmitTransactionResponseExtras struct {
 	EnvelopeXdr string `json:"envelope_xdr"`
 	ResultXdr   string `json:"result_xdr"`
+	// ResultCodes can be empty in responses of older Horizon servers, use
+	// SubmitTransactionResponse.ResultCodes instead
+	ResultCodes *TransactionResultCodes `json:"result_codes,omitempty"`
 }
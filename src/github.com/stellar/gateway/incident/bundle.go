@@ -0,0 +1,198 @@
+package incident
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/url"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/secrets"
+)
+
+// redactedKeys are parts of config keys (case insensitive) with values
+// removed from bundles
+var redactedKeys = []string{"seed", "secret", "password", "key", "token", "header"}
+
+const redacted = secrets.Redaction
+
+// Summary is saved as summary.json in bundles
+type Summary struct {
+	Trigger    string    `json:"trigger"`
+	Reason     string    `json:"reason"`
+	CapturedAt time.Time `json:"captured_at"`
+	Goroutines int       `json:"goroutines"`
+	// Errors are errors of snapshots that could not be loaded
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+func (r *Recorder) bundle(ctx context.Context, now time.Time, trigger, reason string) ([]byte, error) {
+	var buffer bytes.Buffer
+	gz := gzip.NewWriter(&buffer)
+	archive := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: now})
+		if err != nil {
+			return err
+		}
+		_, err = archive.Write(data)
+		return err
+	}
+
+	summary := Summary{
+		Trigger:    trigger,
+		Reason:     reason,
+		CapturedAt: now,
+		Goroutines: runtime.NumGoroutine(),
+		Errors:     map[string]string{},
+	}
+
+	r.lock.Lock()
+	sources := make(map[string]Source, len(r.sources))
+	for name, source := range r.sources {
+		sources[name] = source
+	}
+	r.lock.Unlock()
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		snapshot, err := sources[name](ctx)
+		if err != nil {
+			summary.Errors[name] = err.Error()
+			continue
+		}
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			summary.Errors[name] = err.Error()
+			continue
+		}
+		if err = add("snapshots/"+name+".json", data); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err = add("summary.json", data); err != nil {
+		return nil, err
+	}
+
+	if r.Logs != nil {
+		if err = add("logs.jsonl", r.Logs.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Config != nil {
+		data, err = Redact(r.Config)
+		if err != nil {
+			return nil, err
+		}
+		if err = add("config.json", data); err != nil {
+			return nil, err
+		}
+	}
+
+	var dump bytes.Buffer
+	if err = pprof.Lookup("goroutine").WriteTo(&dump, 2); err != nil {
+		return nil, err
+	}
+	if err = add("goroutines.txt", dump.Bytes()); err != nil {
+		return nil, err
+	}
+
+	var values bytes.Buffer
+	if _, err = metrics.DefaultRegistry.WriteTo(&values); err != nil {
+		return nil, err
+	}
+	if err = add("metrics.txt", values.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if err = archive.Close(); err != nil {
+		return nil, err
+	}
+	if err = gz.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Redact encodes config to JSON without secrets: secret params (see
+// secrets.Redacted) and values of keys containing seed, secret, password,
+// key, token or header are replaced and passwords are removed from URLs (ex.
+// database DSN)
+func Redact(config interface{}) ([]byte, error) {
+	data, err := json.Marshal(secrets.Redacted(config))
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(redact(value), "", "  ")
+}
+
+func redact(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			if isSecret(key) && v != nil && v != "" {
+				value[key] = redacted
+			} else {
+				value[key] = redact(v)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, v := range value {
+			value[i] = redact(v)
+		}
+		return value
+	case string:
+		return redactURL(value)
+	default:
+		return value
+	}
+}
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range redactedKeys {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL removes a password from URLs and key=value DSNs
+func redactURL(value string) string {
+	if strings.Contains(value, "password=") {
+		return redacted
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, ok := u.User.Password(); !ok {
+		return value
+	}
+	u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	return u.String()
+}
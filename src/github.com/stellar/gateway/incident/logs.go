@@ -0,0 +1,62 @@
+package incident
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogBuffer is a logrus hook keeping the last Size log entries in JSON
+// format (one entry per line), regardless of the formatter of the logger
+type LogBuffer struct {
+	Size int
+
+	lock      sync.Mutex
+	lines     [][]byte
+	next      int
+	formatter logrus.JSONFormatter
+}
+
+// NewLogBuffer creates a new LogBuffer. Add it with logrus.AddHook.
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{Size: size, lines: make([][]byte, 0, size)}
+}
+
+// Levels implements logrus.Hook
+func (b *LogBuffer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (b *LogBuffer) Fire(entry *logrus.Entry) error {
+	line, err := b.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.Size <= 0 {
+		return nil
+	}
+	if len(b.lines) < b.Size {
+		b.lines = append(b.lines, line)
+		return nil
+	}
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % b.Size
+	return nil
+}
+
+// Bytes returns buffered entries from the oldest one
+func (b *LogBuffer) Bytes() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var buffer bytes.Buffer
+	for i := range b.lines {
+		buffer.Write(b.lines[(b.next+i)%len(b.lines)])
+	}
+	return buffer.Bytes()
+}
@@ -0,0 +1,191 @@
+// Package incident captures diagnostic bundles when the server detects an
+// incident: repeated transaction submission failures or a stalled payment
+// listener. A bundle is a tar.gz archive with recent logs, snapshots of
+// queues, a redacted config summary, metrics and a goroutine dump. It's
+// stored in export targets (local directory, S3) and referenced in the error
+// log entry that reports the incident, so alerts built on logs point to it.
+package incident
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/export"
+	"github.com/stellar/gateway/metrics"
+)
+
+// Triggers of bundle capture
+const (
+	SubmissionFailures = "submission_failures"
+	ListenerStall      = "listener_stall"
+)
+
+const (
+	// DefaultThreshold is the default number of consecutive failures that
+	// starts capture
+	DefaultThreshold = 5
+	// DefaultCooldown is the default min time between bundles of a trigger
+	DefaultCooldown = 15 * time.Minute
+	// captureTimeout limits the time of loading snapshots and storing a bundle
+	captureTimeout = time.Minute
+)
+
+var recorderMetrics = struct {
+	errors *metrics.Counter
+}{
+	errors: metrics.NewCounter("incident_bundle_errors_total", "Number of diagnostic bundles that could not be stored."),
+}
+
+// Source returns a snapshot of a part of the server state (ex. pending
+// transactions) included in bundles. The value is encoded to JSON.
+type Source func(ctx context.Context) (interface{}, error)
+
+// Recorder counts failures and captures bundles when an incident is detected.
+// At most one bundle of a trigger is captured per Cooldown. A nil Recorder
+// ignores all calls, so it can be used when bundles are not configured.
+type Recorder struct {
+	Targets []export.Target
+	// Threshold is the number of consecutive failures of a trigger that
+	// starts capture
+	Threshold int
+	Cooldown  time.Duration
+	// Logs provides recent log entries, not included when nil
+	Logs *LogBuffer
+	// Config is encoded to JSON and redacted, see Redact
+	Config interface{}
+
+	lock         sync.Mutex
+	sources      map[string]Source
+	failures     map[string]int
+	lastCaptured map[string]time.Time
+	log          *logrus.Entry
+	now          func() time.Time
+}
+
+// NewRecorder creates a new Recorder
+func NewRecorder(targets []export.Target, threshold int, cooldown time.Duration, logs *LogBuffer, config interface{}) *Recorder {
+	return &Recorder{
+		Targets:      targets,
+		Threshold:    threshold,
+		Cooldown:     cooldown,
+		Logs:         logs,
+		Config:       config,
+		sources:      map[string]Source{},
+		failures:     map[string]int{},
+		lastCaptured: map[string]time.Time{},
+		log:          logrus.WithFields(logrus.Fields{"service": "IncidentRecorder"}),
+		now:          clock.Now,
+	}
+}
+
+// AddSource adds a snapshot saved as snapshots/<name>.json in bundles
+func (r *Recorder) AddSource(name string, source Source) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.sources[name] = source
+}
+
+// Failure records a failure of trigger. A bundle is captured in a goroutine
+// when the number of consecutive failures reaches Threshold.
+func (r *Recorder) Failure(trigger string, err error) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	r.failures[trigger]++
+	failures := r.failures[trigger]
+	r.lock.Unlock()
+
+	if failures < r.Threshold {
+		return
+	}
+	r.trigger(trigger, fmt.Sprintf("%d consecutive failures, last: %s", failures, err))
+}
+
+// Success resets the number of consecutive failures of trigger
+func (r *Recorder) Success(trigger string) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.failures[trigger] = 0
+}
+
+// WatchStream checks updatedAt every interval in a goroutine and captures
+// a ListenerStall bundle when the stream hasn't been updated for staleAfter.
+// Streams that haven't started yet are not reported.
+func (r *Recorder) WatchStream(updatedAt func() time.Time, staleAfter, interval time.Duration) {
+	if r == nil {
+		return
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			t := updatedAt()
+			if t.IsZero() {
+				continue
+			}
+			if age := r.now().Sub(t); age > staleAfter {
+				r.trigger(ListenerStall, fmt.Sprintf("payments stream not updated for %s", age.Truncate(time.Second)))
+			}
+		}
+	}()
+}
+
+// trigger starts capture of a bundle in a goroutine unless a bundle of
+// trigger has been captured within Cooldown
+func (r *Recorder) trigger(trigger, reason string) {
+	r.lock.Lock()
+	now := r.now()
+	if last, ok := r.lastCaptured[trigger]; ok && now.Sub(last) < r.Cooldown {
+		r.lock.Unlock()
+		return
+	}
+	r.lastCaptured[trigger] = now
+	r.lock.Unlock()
+
+	go r.Capture(trigger, reason)
+}
+
+// Capture builds a bundle and stores it in all targets. The incident is
+// logged with the bundle name, so it can be found from the alert.
+func (r *Recorder) Capture(trigger, reason string) (name string, err error) {
+	if r == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), captureTimeout)
+	defer cancel()
+
+	now := r.now().UTC()
+	name = fmt.Sprintf("incident-%s-%s.tar.gz", now.Format("20060102T150405Z"), trigger)
+	data, err := r.bundle(ctx, now, trigger, reason)
+	if err != nil {
+		recorderMetrics.errors.Inc()
+		r.log.WithFields(logrus.Fields{"trigger": trigger, "reason": reason, "err": err}).Error("Incident detected, cannot build diagnostic bundle")
+		return
+	}
+
+	for _, target := range r.Targets {
+		if storeErr := target.Store(name, data); storeErr != nil {
+			recorderMetrics.errors.Inc()
+			r.log.WithFields(logrus.Fields{"bundle": name, "err": storeErr}).Error("Cannot store diagnostic bundle")
+			err = storeErr
+		}
+	}
+
+	metrics.NewCounter(metrics.Label("incident_bundles_total", "trigger", trigger), "Number of diagnostic bundles captured.").Inc()
+	r.log.WithFields(logrus.Fields{"trigger": trigger, "reason": reason, "bundle": name}).Error("Incident detected, diagnostic bundle captured")
+	return
+}
@@ -0,0 +1,34 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/gateway/db"
+)
+
+// Database keeps the lease in LeaderLease table. Expiration times are
+// computed by replicas, so their clocks must be synchronized.
+type Database struct {
+	name       string
+	repository db.RepositoryInterface
+}
+
+// NewDatabase creates a Database backend of lease name
+func NewDatabase(name string, repository db.RepositoryInterface) *Database {
+	return &Database{name: name, repository: repository}
+}
+
+// Acquire acquires or renews the lease, see Backend
+func (d *Database) Acquire(ctx context.Context, id string, now time.Time, ttl time.Duration) (string, error) {
+	lease, err := d.repository.AcquireLeaderLease(ctx, d.name, id, now, now.Add(ttl))
+	if err != nil {
+		return "", err
+	}
+	return lease.Holder, nil
+}
+
+// Release expires the lease held by id, see Backend
+func (d *Database) Release(ctx context.Context, id string, now time.Time) error {
+	return d.repository.ReleaseLeaderLease(ctx, d.name, id, now)
+}
@@ -0,0 +1,249 @@
+package leader
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// Etcd keeps the lease in etcd using its v3 HTTP gateway (JSON API). The key
+// of the leader is attached to an etcd lease of the replica, so etcd removes
+// it when the leader stops renewing it and clocks of replicas do not matter.
+type Etcd struct {
+	Client *http.Client
+	// Endpoints are tried in order until one of them responds
+	Endpoints []string
+
+	key     string
+	mutex   sync.Mutex
+	leaseID string
+}
+
+type etcdKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Lease string `json:"lease"`
+}
+
+type etcdTxnResponse struct {
+	Succeeded bool `json:"succeeded"`
+	Responses []struct {
+		ResponseRange *struct {
+			Kvs []etcdKeyValue `json:"kvs"`
+		} `json:"response_range"`
+	} `json:"responses"`
+}
+
+// NewEtcd creates an Etcd backend of lease name
+func NewEtcd(client *http.Client, endpoints []string, name string) *Etcd {
+	return &Etcd{
+		Client:    client,
+		Endpoints: endpoints,
+		key:       "/stellar-bridge/leader/" + name,
+	}
+}
+
+// Acquire acquires or renews the lease, see Backend
+func (e *Etcd) Acquire(ctx context.Context, id string, now time.Time, ttl time.Duration) (string, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.leaseID != "" {
+		alive, err := e.keepAlive(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !alive {
+			e.leaseID = ""
+		}
+	}
+
+	if e.leaseID == "" {
+		err := e.grant(ctx, ttl)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Key is put with the lease of this replica when it does not exist
+	response, err := e.txn(ctx, id, map[string]interface{}{
+		"key":             e.encode(e.key),
+		"target":          "CREATE",
+		"create_revision": "0",
+	})
+	if err != nil {
+		return "", err
+	}
+	if response.Succeeded {
+		return id, nil
+	}
+
+	if len(response.Responses) == 0 || response.Responses[0].ResponseRange == nil || len(response.Responses[0].ResponseRange.Kvs) == 0 {
+		// Key expired in the meantime, it's acquired in the next renewal
+		return "", nil
+	}
+
+	kv := response.Responses[0].ResponseRange.Kvs[0]
+	holder, err := base64.StdEncoding.DecodeString(kv.Value)
+	if err != nil {
+		return "", errors.Wrap(err, "Cannot decode etcd value")
+	}
+
+	if string(holder) == id && kv.Lease != e.leaseID {
+		// Key has been put by this replica with a previous lease (ex.
+		// before it restarted), it's attached to the current one
+		response, err = e.txn(ctx, id, map[string]interface{}{
+			"key":    e.encode(e.key),
+			"target": "VALUE",
+			"value":  e.encode(id),
+		})
+		if err != nil {
+			return "", err
+		}
+		if !response.Succeeded {
+			return "", nil
+		}
+	}
+	return string(holder), nil
+}
+
+// Release revokes the lease of this replica which removes the key when it's
+// held by this replica, see Backend
+func (e *Etcd) Release(ctx context.Context, id string, now time.Time) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.leaseID == "" {
+		return nil
+	}
+
+	err := e.post(ctx, "/v3/lease/revoke", map[string]interface{}{"ID": e.leaseID}, nil)
+	if err != nil {
+		return err
+	}
+	e.leaseID = ""
+	return nil
+}
+
+func (e *Etcd) grant(ctx context.Context, ttl time.Duration) error {
+	seconds := int64((ttl + time.Second - 1) / time.Second)
+
+	var response struct {
+		ID    string `json:"ID"`
+		Error string `json:"error"`
+	}
+	err := e.post(ctx, "/v3/lease/grant", map[string]interface{}{"TTL": strconv.FormatInt(seconds, 10)}, &response)
+	if err != nil {
+		return err
+	}
+	if response.ID == "" {
+		return errors.New("etcd did not grant a lease: " + response.Error)
+	}
+	e.leaseID = response.ID
+	return nil
+}
+
+// keepAlive renews the lease of this replica, returns false when it has
+// already expired
+func (e *Etcd) keepAlive(ctx context.Context) (bool, error) {
+	var response struct {
+		Result *struct {
+			TTL string `json:"TTL"`
+		} `json:"result"`
+	}
+	err := e.post(ctx, "/v3/lease/keepalive", map[string]interface{}{"ID": e.leaseID}, &response)
+	if err != nil {
+		return false, err
+	}
+	return response.Result != nil && response.Result.TTL != "" && response.Result.TTL != "0", nil
+}
+
+// txn puts id to the key with the lease of this replica if compare
+// succeeds, loads the key otherwise
+func (e *Etcd) txn(ctx context.Context, id string, compare map[string]interface{}) (*etcdTxnResponse, error) {
+	request := map[string]interface{}{
+		"compare": []interface{}{compare},
+		"success": []interface{}{map[string]interface{}{
+			"request_put": map[string]interface{}{
+				"key":   e.encode(e.key),
+				"value": e.encode(id),
+				"lease": e.leaseID,
+			},
+		}},
+		"failure": []interface{}{map[string]interface{}{
+			"request_range": map[string]interface{}{"key": e.encode(e.key)},
+		}},
+	}
+
+	var response etcdTxnResponse
+	err := e.post(ctx, "/v3/kv/txn", request, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (e *Etcd) encode(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+// post sends request to the first endpoint that responds and decodes the
+// first JSON object of the response (keepalive responses are streamed) to
+// response
+func (e *Etcd) post(ctx context.Context, path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "Error encoding etcd request")
+	}
+
+	err = errors.New("No etcd endpoints")
+	for _, endpoint := range e.Endpoints {
+		err = e.postEndpoint(ctx, strings.TrimRight(endpoint, "/")+path, body, response)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (e *Etcd) postEndpoint(ctx context.Context, url string, body []byte, response interface{}) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "configure http request failed")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error sending request to etcd")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("Error response from etcd: %d %s", resp.StatusCode, responseBody)
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(response)
+	if err != nil {
+		return errors.Wrap(err, "Cannot unmarshal etcd response")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package leader
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// etcdGateway is a minimal etcd v3 HTTP gateway keeping a single key
+type etcdGateway struct {
+	value, lease string
+	leases       map[string]bool
+	next         int
+}
+
+func (g *etcdGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&request)
+
+	switch r.URL.Path {
+	case "/v3/lease/grant":
+		g.next++
+		id := string(rune('0' + g.next))
+		g.leases[id] = true
+		json.NewEncoder(w).Encode(map[string]string{"ID": id, "TTL": "15"})
+	case "/v3/lease/keepalive":
+		if g.leases[request["ID"].(string)] {
+			w.Write([]byte(`{"result":{"ID":"` + request["ID"].(string) + `","TTL":"15"}}`))
+		} else {
+			w.Write([]byte(`{"result":{"ID":"` + request["ID"].(string) + `"}}`))
+		}
+	case "/v3/lease/revoke":
+		id := request["ID"].(string)
+		delete(g.leases, id)
+		if g.lease == id {
+			g.value, g.lease = "", ""
+		}
+		w.Write([]byte(`{}`))
+	case "/v3/kv/txn":
+		compare := request["compare"].([]interface{})[0].(map[string]interface{})
+		succeeded := g.value == ""
+		if compare["target"] == "VALUE" {
+			succeeded = g.value == compare["value"]
+		}
+		if succeeded {
+			put := request["success"].([]interface{})[0].(map[string]interface{})["request_put"].(map[string]interface{})
+			g.value, g.lease = put["value"].(string), put["lease"].(string)
+			w.Write([]byte(`{"succeeded":true}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"responses": []interface{}{map[string]interface{}{
+				"response_range": map[string]interface{}{
+					"kvs": []interface{}{map[string]string{"value": g.value, "lease": g.lease}},
+				},
+			}},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestEtcd(t *testing.T) {
+	gateway := &etcdGateway{leases: map[string]bool{}}
+	srv := httptest.NewServer(gateway)
+	defer srv.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+	// Unreachable endpoints are skipped
+	a := NewEtcd(http.DefaultClient, []string{"http://127.0.0.1:1", srv.URL}, "bridge")
+	b := NewEtcd(http.DefaultClient, []string{srv.URL}, "bridge")
+
+	holder, err := a.Acquire(ctx, "a", now, 15*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "a", holder)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("a")), gateway.value)
+
+	holder, err = b.Acquire(ctx, "b", now, 15*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "a", holder)
+
+	holder, err = a.Acquire(ctx, "a", now, 15*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "a", holder)
+
+	// Key of an expired lease of the same replica is attached to a new lease
+	delete(gateway.leases, a.leaseID)
+	holder, err = a.Acquire(ctx, "a", now, 15*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "a", holder)
+	assert.Equal(t, a.leaseID, gateway.lease)
+
+	require.NoError(t, a.Release(ctx, "a", now))
+	holder, err = b.Acquire(ctx, "b", now, 15*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "b", holder)
+}
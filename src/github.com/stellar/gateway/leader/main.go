@@ -0,0 +1,228 @@
+// Package leader elects a single leader among bridge server replicas sharing
+// a database. Replicas compete for a lease kept in the database or in etcd,
+// the holder renews it periodically and is the leader until it fails to
+// renew it for the lease duration. Only the leader streams received payments
+// and submits transactions, so replicas do not send duplicate callbacks and
+// do not fight over sequence numbers, while all replicas serve the API.
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/metrics"
+)
+
+var (
+	leaderGauge = metrics.NewGauge("bridge_leader", "1 when this replica is the leader, 0 otherwise.")
+	transitions = metrics.NewCounter("bridge_leader_transitions_total", "Number of times this replica became the leader or stepped down.")
+	renewErrors = metrics.NewCounter("bridge_leader_renew_errors_total", "Number of failed attempts to acquire or renew the leader lease.")
+)
+
+// Backend keeps the lease of the leader
+type Backend interface {
+	// Acquire acquires the lease for id until now + ttl when it is free or
+	// has expired, or extends it when id already holds it. Returns ID of
+	// the replica holding the lease after the call.
+	Acquire(ctx context.Context, id string, now time.Time, ttl time.Duration) (string, error)
+	// Release releases the lease when it is held by id, so other replicas
+	// can acquire it without waiting for it to expire
+	Release(ctx context.Context, id string, now time.Time) error
+}
+
+// Status is the leadership status of a replica returned by
+// /admin/leadership
+type Status struct {
+	// Enabled is false when leader election is disabled, a single replica
+	// is always the leader then
+	Enabled bool   `json:"enabled"`
+	ID      string `json:"id,omitempty"`
+	Leader  bool   `json:"leader"`
+	// Holder is ID of the current leader as seen in the last renewal
+	Holder string `json:"holder,omitempty"`
+	// Since is the time this replica became the leader
+	Since *time.Time `json:"since,omitempty"`
+	// RenewedAt is the time of the last successful renewal of the lease
+	RenewedAt *time.Time `json:"renewed_at,omitempty"`
+	// Error is the error of the last renewal
+	Error string `json:"error,omitempty"`
+}
+
+// Elector acquires and renews the lease of the leader. A nil *Elector is
+// always the leader, so it can be used when leader election is disabled.
+type Elector struct {
+	backend  Backend
+	id       string
+	ttl      time.Duration
+	interval time.Duration
+	now      func() time.Time
+	log      *logrus.Entry
+
+	// round serializes renewals with Resign, so a renewal in progress
+	// can't acquire the lease again after it has been released
+	round sync.Mutex
+
+	mutex     sync.RWMutex
+	leader    bool
+	resigned  bool
+	holder    string
+	since     time.Time
+	renewedAt time.Time
+	err       error
+}
+
+// NewElector creates an Elector of replica id keeping the lease in backend.
+// The lease is valid for ttl and renewed every interval, which must be
+// shorter than ttl.
+func NewElector(backend Backend, id string, ttl, interval time.Duration, now func() time.Time) *Elector {
+	return &Elector{
+		backend:  backend,
+		id:       id,
+		ttl:      ttl,
+		interval: interval,
+		now:      now,
+		log:      logrus.WithFields(logrus.Fields{"service": "LeaderElection", "id": id}),
+	}
+}
+
+// IsLeader returns true when this replica holds the lease, always true when
+// e is nil
+func (e *Elector) IsLeader() bool {
+	if e == nil {
+		return true
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.leader
+}
+
+// Run acquires or renews the lease every interval until ctx is done. The
+// first attempt is made immediately.
+func (e *Elector) Run(ctx context.Context) {
+	for {
+		renewCtx, cancel := context.WithTimeout(ctx, e.interval)
+		e.renew(renewCtx)
+		cancel()
+
+		select {
+		case <-time.After(e.interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (e *Elector) renew(ctx context.Context) {
+	e.round.Lock()
+	defer e.round.Unlock()
+
+	if e.isResigned() {
+		return
+	}
+
+	now := e.now()
+	holder, err := e.backend.Acquire(ctx, e.id, now, e.ttl)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	wasLeader := e.leader
+	if err != nil {
+		renewErrors.Inc()
+		e.err = err
+		e.log.WithFields(logrus.Fields{"err": err}).Warn("Error renewing leader lease")
+		// Other replicas can acquire the lease when it expires, the leader
+		// steps down before the next renewal if it could happen earlier
+		if e.leader && !now.Add(e.interval).Before(e.renewedAt.Add(e.ttl)) {
+			e.leader = false
+		}
+	} else {
+		e.err = nil
+		e.holder = holder
+		e.leader = holder == e.id
+		if e.leader {
+			// The lease is valid for ttl since the renewal was started
+			e.renewedAt = now
+			if !wasLeader {
+				e.since = now
+			}
+		}
+	}
+
+	if e.leader != wasLeader {
+		e.transition()
+	}
+}
+
+// Resign releases the lease when this replica holds it and stops renewing
+// it. It's called when the server stops, after the work of the leader is
+// finished.
+func (e *Elector) Resign(ctx context.Context) error {
+	if e == nil {
+		return nil
+	}
+
+	e.round.Lock()
+	defer e.round.Unlock()
+
+	e.mutex.Lock()
+	wasLeader := e.leader
+	e.leader = false
+	e.resigned = true
+	if wasLeader {
+		e.transition()
+	}
+	e.mutex.Unlock()
+
+	if !wasLeader {
+		return nil
+	}
+	return e.backend.Release(ctx, e.id, e.now())
+}
+
+// Status returns the leadership status of this replica
+func (e *Elector) Status() Status {
+	if e == nil {
+		return Status{Leader: true}
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	status := Status{
+		Enabled: true,
+		ID:      e.id,
+		Leader:  e.leader,
+		Holder:  e.holder,
+	}
+	if e.leader {
+		since, renewedAt := e.since, e.renewedAt
+		status.Since = &since
+		status.RenewedAt = &renewedAt
+	}
+	if e.err != nil {
+		status.Error = e.err.Error()
+	}
+	return status
+}
+
+func (e *Elector) isResigned() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.resigned
+}
+
+// transition records a change of e.leader, e.mutex must be locked
+func (e *Elector) transition() {
+	transitions.Inc()
+	if e.leader {
+		leaderGauge.Set(1)
+		e.log.Info("Elected leader, starting payment listener and submissions")
+	} else {
+		leaderGauge.Set(0)
+		e.log.Warn("Stepped down as leader, stopping payment listener and submissions")
+	}
+}
@@ -0,0 +1,89 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingBackend struct {
+	Backend
+	err error
+}
+
+func (b *failingBackend) Acquire(ctx context.Context, id string, now time.Time, ttl time.Duration) (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	return b.Backend.Acquire(ctx, id, now, ttl)
+}
+
+func TestElector(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	ctx := context.Background()
+
+	backendA := &failingBackend{Backend: NewDatabase("bridge", db.NewRepository(driver))}
+	a := NewElector(backendA, "a", 15*time.Second, 5*time.Second, clock)
+	b := NewElector(NewDatabase("bridge", db.NewRepository(driver)), "b", 15*time.Second, 5*time.Second, clock)
+
+	a.renew(ctx)
+	b.renew(ctx)
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+	assert.Equal(t, "a", b.Status().Holder)
+	assert.Equal(t, now, *a.Status().Since)
+
+	// Renewed lease is not acquired by the follower
+	now = now.Add(10 * time.Second)
+	a.renew(ctx)
+	now = now.Add(10 * time.Second)
+	b.renew(ctx)
+	a.renew(ctx)
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	// Leader steps down before its lease can expire
+	backendA.err = errors.New("connection refused")
+	now = now.Add(5 * time.Second)
+	a.renew(ctx)
+	assert.True(t, a.IsLeader())
+	now = now.Add(5 * time.Second)
+	a.renew(ctx)
+	assert.False(t, a.IsLeader())
+	assert.Equal(t, "connection refused", a.Status().Error)
+
+	// Follower acquires expired lease
+	now = now.Add(6 * time.Second)
+	b.renew(ctx)
+	assert.True(t, b.IsLeader())
+	backendA.err = nil
+	a.renew(ctx)
+	assert.False(t, a.IsLeader())
+	assert.Equal(t, "b", a.Status().Holder)
+
+	// Released lease is acquired immediately
+	require.NoError(t, b.Resign(ctx))
+	assert.False(t, b.IsLeader())
+	b.renew(ctx)
+	assert.False(t, b.IsLeader())
+	now = now.Add(time.Second)
+	a.renew(ctx)
+	assert.True(t, a.IsLeader())
+
+	var disabled *Elector
+	assert.True(t, disabled.IsLeader())
+	assert.False(t, disabled.Status().Enabled)
+	assert.NoError(t, disabled.Resign(ctx))
+}
@@ -0,0 +1,154 @@
+package listener
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/metrics"
+)
+
+const (
+	// DefaultCallbackFailoverThreshold is the number of consecutive failures
+	// after which a callback endpoint is considered unhealthy
+	DefaultCallbackFailoverThreshold = 3
+	// DefaultCallbackRecoveryInterval is the time after which an unhealthy
+	// callback endpoint is tried first again
+	DefaultCallbackRecoveryInterval = 30 * time.Second
+)
+
+var callbackFailovers = metrics.NewCounter("bridge_callback_failovers_total", "Number of callbacks sent to the next callback endpoint after the previous one failed.")
+
+// CallbackEndpoints is an ordered list of callback URLs (primary first) with
+// health tracking. Callbacks are sent to the first healthy endpoint. An
+// endpoint becomes unhealthy after threshold consecutive failures and is
+// tried again in its place after recoveryInterval, so callbacks go back to
+// the primary endpoint as soon as it's available again.
+type CallbackEndpoints struct {
+	threshold        int
+	recoveryInterval time.Duration
+	now              func() time.Time
+	log              *logrus.Entry
+
+	mutex     sync.Mutex
+	endpoints []*callbackEndpoint
+}
+
+type callbackEndpoint struct {
+	url      string
+	failures int
+	failedAt time.Time
+	healthy  *metrics.Gauge
+}
+
+// NewCallbackEndpoints creates CallbackEndpoints. Defaults are used when
+// threshold or recoveryInterval is 0.
+func NewCallbackEndpoints(urls []string, threshold int, recoveryInterval time.Duration) *CallbackEndpoints {
+	if threshold == 0 {
+		threshold = DefaultCallbackFailoverThreshold
+	}
+	if recoveryInterval == 0 {
+		recoveryInterval = DefaultCallbackRecoveryInterval
+	}
+
+	c := &CallbackEndpoints{
+		threshold:        threshold,
+		recoveryInterval: recoveryInterval,
+		now:              clock.Now,
+		log:              logrus.WithFields(logrus.Fields{"service": "CallbackEndpoints"}),
+	}
+	for _, u := range urls {
+		c.endpoints = append(c.endpoints, newCallbackEndpoint(u))
+	}
+	return c
+}
+
+func newCallbackEndpoint(url string) *callbackEndpoint {
+	e := &callbackEndpoint{
+		url:     url,
+		healthy: metrics.NewGauge(metrics.Label("bridge_callback_endpoint_healthy", "endpoint", url), "1 when callback endpoint is healthy, 0 after consecutive failures."),
+	}
+	e.healthy.Set(1)
+	return e
+}
+
+// SetURLs replaces callback URLs when the config is reloaded. Endpoints that
+// are still configured keep their health.
+func (c *CallbackEndpoints) SetURLs(urls []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	endpoints := make([]*callbackEndpoint, 0, len(urls))
+	for _, u := range urls {
+		e := c.endpoint(u)
+		if e == nil {
+			e = newCallbackEndpoint(u)
+		}
+		endpoints = append(endpoints, e)
+	}
+	c.endpoints = endpoints
+}
+
+// URLs returns callback URLs in the order they should be tried: healthy
+// endpoints and endpoints due for a recovery attempt first, then the
+// remaining unhealthy ones as a last resort.
+func (c *CallbackEndpoints) URLs() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var available, unhealthy []string
+	for _, e := range c.endpoints {
+		if e.failures < c.threshold || c.now().Sub(e.failedAt) >= c.recoveryInterval {
+			available = append(available, e.url)
+		} else {
+			unhealthy = append(unhealthy, e.url)
+		}
+	}
+	return append(available, unhealthy...)
+}
+
+// Success marks the endpoint as healthy
+func (c *CallbackEndpoints) Success(url string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e := c.endpoint(url)
+	if e == nil {
+		return
+	}
+	if e.failures >= c.threshold {
+		c.log.WithField("endpoint", url).Info("Callback endpoint recovered")
+	}
+	e.failures = 0
+	e.healthy.Set(1)
+}
+
+// Failure records a failed callback. The endpoint becomes unhealthy after
+// threshold consecutive failures.
+func (c *CallbackEndpoints) Failure(url string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e := c.endpoint(url)
+	if e == nil {
+		return
+	}
+	e.failures++
+	if e.failures >= c.threshold {
+		if e.failures == c.threshold {
+			c.log.WithField("endpoint", url).Warn("Callback endpoint is unhealthy, failing over to the next endpoint")
+		}
+		e.failedAt = c.now()
+		e.healthy.Set(0)
+	}
+}
+
+func (c *CallbackEndpoints) endpoint(url string) *callbackEndpoint {
+	for _, e := range c.endpoints {
+		if e.url == url {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package listener
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/amqp"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/cloudevents"
+	"github.com/stellar/gateway/queue"
+	"github.com/stellar/gateway/tracing"
+	"github.com/stellar/gateway/webhooks"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+)
+
+// CallbackTransport delivers receive callbacks. When Send returns an error the
+// payment is considered not processed and will be retried.
+type CallbackTransport interface {
+	Send(payload url.Values) error
+}
+
+const (
+	// CallbackTransportHTTP sends callbacks as HTTP POST requests to callbacks.receive URLs
+	CallbackTransportHTTP = "http"
+	// CallbackTransportKafka publishes callbacks to a Kafka topic via Kafka REST Proxy
+	CallbackTransportKafka = "kafka"
+	// CallbackTransportRabbitMQ publishes callbacks to a RabbitMQ exchange over AMQP
+	CallbackTransportRabbitMQ = "rabbitmq"
+	// CallbackTransportNATS publishes callbacks to a NATS JetStream stream
+	CallbackTransportNATS = "nats"
+	// CallbackTransportSQS sends callbacks to AWS SQS queue
+	CallbackTransportSQS = queue.TransportSQS
+	// CallbackTransportPubSub publishes callbacks to Google Cloud Pub/Sub topic
+	CallbackTransportPubSub = queue.TransportPubSub
+)
+
+// NewCallbackTransport creates a CallbackTransport using `callbacks` config group.
+// Messages of message bus transports are CloudEvents when format is set.
+// tlsConfig is used by TLS connections of rabbitmq and nats transports (can
+// be nil).
+func NewCallbackTransport(callbacks config.Callbacks, macKey string, tlsConfig *tls.Config, client HTTP, format *cloudevents.Formatter) (CallbackTransport, error) {
+	switch callbacks.Transport {
+	case "", CallbackTransportHTTP:
+		return &HTTPCallbackTransport{
+			Client:    client,
+			Endpoints: NewCallbackEndpoints(callbacks.Receive, callbacks.Failover.Threshold, callbacks.Failover.RecoveryIntervalDuration()),
+			MACKey:    macKey,
+		}, nil
+	case CallbackTransportKafka:
+		return &KafkaCallbackTransport{
+			Client:       client,
+			RestProxyURL: callbacks.Kafka.RestProxyURL,
+			Topic:        callbacks.Kafka.Topic,
+			Format:       format,
+		}, nil
+	case CallbackTransportRabbitMQ:
+		publisher, err := amqp.NewPublisher(callbacks.RabbitMQ.URL, callbacks.RabbitMQ.Exchange, callbacks.RabbitMQ.RoutingKey, tlsConfig, amqpTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if callbacks.RabbitMQ.Username != "" {
+			publisher.SetCredentials(callbacks.RabbitMQ.Username, callbacks.RabbitMQ.Password)
+		}
+		if callbacks.RabbitMQ.VHost != "" {
+			publisher.SetVHost(callbacks.RabbitMQ.VHost)
+		}
+		return &RabbitMQCallbackTransport{Publisher: publisher, MACKey: macKey, Format: format}, nil
+	case CallbackTransportNATS:
+		transport, err := NewNATSCallbackTransport(callbacks.NATS.URL, callbacks.NATS.Subject, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		transport.MACKey = macKey
+		transport.Format = format
+		return transport, nil
+	case CallbackTransportSQS, CallbackTransportPubSub:
+		publisher, err := queue.NewPublisher(callbacks.Transport, callbacks.SQS, callbacks.PubSub, client)
+		if err != nil {
+			return nil, err
+		}
+		return &QueueCallbackTransport{Publisher: publisher, Attributes: callbacks.Attributes, MACKey: macKey, Format: format}, nil
+	default:
+		return nil, fmt.Errorf("Unknown callback transport: %s", callbacks.Transport)
+	}
+}
+
+// HTTPCallbackTransport sends receive callbacks as form-encoded HTTP POST requests.
+// Callbacks are considered delivered when 200 OK status is returned. When an
+// endpoint is unreachable or responds with 5xx status the callback is sent to
+// the next endpoint.
+type HTTPCallbackTransport struct {
+	Client    HTTP
+	Endpoints *CallbackEndpoints
+	// When set, X_PAYLOAD_MAC header is attached to every request
+	MACKey string
+}
+
+// Send sends payload to the receive callback
+func (t *HTTPCallbackTransport) Send(payload url.Values) (err error) {
+	for i, endpointURL := range t.Endpoints.URLs() {
+		if i > 0 {
+			callbackFailovers.Inc()
+		}
+
+		var retry bool
+		retry, err = t.send(endpointURL, payload)
+		if !retry {
+			return
+		}
+		t.Endpoints.Failure(endpointURL)
+	}
+
+	if err == nil {
+		err = errors.New("No receive callback URL")
+	}
+	return
+}
+
+// send sends payload to a single endpoint. retry is true when the endpoint
+// failed and the callback can be sent to the next one.
+func (t *HTTPCallbackTransport) send(endpointURL string, payload url.Values) (retry bool, err error) {
+	ctx, span := tracing.StartKind(context.Background(), "callback.deliver", tracing.SpanKindClient)
+	span.SetAttribute("endpoint", endpointURL)
+	span.SetAttribute("operation_id", payload.Get("id"))
+	defer func() {
+		span.SetError(err)
+		span.Finish()
+	}()
+
+	resp, err := postForm(ctx, t.Client, endpointURL, payload, t.MACKey)
+	if err != nil {
+		return true, errors.Wrap(err, "Error sending request to receive callback")
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return true, errors.Wrap(err, "Error reading receive callback response")
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"endpoint": endpointURL,
+			"status":   resp.StatusCode,
+			"body":     string(body),
+		}).Error("Error response from receive callback")
+
+		// 4xx responses come from a working receiver rejecting the callback
+		retry = resp.StatusCode >= http.StatusInternalServerError
+		if !retry {
+			t.Endpoints.Success(endpointURL)
+		}
+		return retry, errors.New("Error response from receive callback")
+	}
+
+	t.Endpoints.Success(endpointURL)
+	return false, nil
+}
+
+// callbackMessage encodes callback payload as a flat JSON object or as data of
+// payment_received CloudEvent when format is set. Operation ID is the ID of
+// the event, so consumers can deduplicate deliveries of the same payment. It
+// is used by message bus transports.
+func callbackMessage(payload url.Values, format *cloudevents.Formatter) ([]byte, error) {
+	message := make(map[string]string, len(payload))
+	for key := range payload {
+		message[key] = payload.Get(key)
+	}
+	return format.Marshal(webhooks.EventPaymentReceived, payload.Get("id"), message)
+}
+
+// macHeaders returns X_PAYLOAD_MAC header of message bus transports, base64
+// encoded HMAC-SHA256 of message, or nil when macKey is not set
+func macHeaders(macKey string, message []byte) (map[string]string, error) {
+	if macKey == "" {
+		return nil, nil
+	}
+
+	rawMAC, err := getMAC(macKey, message)
+	if err != nil {
+		return nil, errors.Wrap(err, "getMAC failed")
+	}
+	return map[string]string{"X_PAYLOAD_MAC": base64.StdEncoding.EncodeToString(rawMAC)}, nil
+}
+
+// postForm sends form to url. Trace context of the span in ctx is sent in
+// `traceparent` header.
+func postForm(ctx context.Context, client HTTP, url string, form url.Values, macKey string) (*http.Response, error) {
+	strbody := form.Encode()
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(strbody))
+	if err != nil {
+		return nil, errors.Wrap(err, "configure http request failed")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tracing.Inject(ctx, req.Header)
+
+	if macKey != "" {
+		rawMAC, err := getMAC(macKey, []byte(strbody))
+		if err != nil {
+			return nil, errors.Wrap(err, "getMAC failed")
+		}
+
+		encMAC := base64.StdEncoding.EncodeToString(rawMAC)
+		req.Header.Set("X_PAYLOAD_MAC", encMAC)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request errored")
+	}
+
+	return resp, nil
+}
+
+func getMAC(key string, raw []byte) ([]byte, error) {
+	rawkey, err := strkey.Decode(strkey.VersionByteSeed, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid MAC key")
+	}
+
+	macer := hmac.New(sha256.New, rawkey)
+	macer.Write(raw)
+	return macer.Sum(nil), nil
+}
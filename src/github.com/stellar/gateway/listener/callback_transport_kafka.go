@@ -0,0 +1,97 @@
+package listener
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/stellar/gateway/cloudevents"
+	"github.com/stellar/go/support/errors"
+)
+
+// KafkaCallbackTransport publishes receive callbacks to a Kafka topic using
+// Kafka REST Proxy (v2 API). Operation ID is used as a record key so all
+// deliveries of a single payment land in the same partition.
+type KafkaCallbackTransport struct {
+	Client       HTTP
+	RestProxyURL string
+	Topic        string
+	// Format makes messages CloudEvents when set
+	Format *cloudevents.Formatter
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		Partition *int    `json:"partition"`
+		Offset    *int64  `json:"offset"`
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Send publishes payload to the topic
+func (t *KafkaCallbackTransport) Send(payload url.Values) error {
+	message, err := callbackMessage(payload, t.Format)
+	if err != nil {
+		return errors.Wrap(err, "Error encoding callback message")
+	}
+
+	body, err := json.Marshal(struct {
+		Records []kafkaRecord `json:"records"`
+	}{[]kafkaRecord{{Key: payload.Get("id"), Value: message}}})
+	if err != nil {
+		return errors.Wrap(err, "Error encoding Kafka records")
+	}
+
+	endpoint := strings.TrimRight(t.RestProxyURL, "/") + "/topics/" + url.PathEscape(t.Topic)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "configure http request failed")
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error sending request to Kafka REST Proxy")
+	}
+
+	defer resp.Body.Close()
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "Error reading Kafka REST Proxy response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Error response from Kafka REST Proxy: %d %s", resp.StatusCode, responseBody)
+	}
+
+	var produceResponse kafkaProduceResponse
+	err = json.Unmarshal(responseBody, &produceResponse)
+	if err != nil {
+		return errors.Wrap(err, "Cannot unmarshal Kafka REST Proxy response")
+	}
+
+	if len(produceResponse.Offsets) != 1 {
+		return errors.New("Kafka REST Proxy did not return record offset")
+	}
+
+	offset := produceResponse.Offsets[0]
+	if offset.Error != nil {
+		return errors.New("Kafka error: " + *offset.Error)
+	}
+
+	if offset.ErrorCode != nil {
+		return errors.Errorf("Kafka error code: %d", *offset.ErrorCode)
+	}
+
+	return nil
+}
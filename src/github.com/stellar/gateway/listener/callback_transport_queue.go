@@ -0,0 +1,53 @@
+package listener
+
+import (
+	"net/url"
+
+	"github.com/stellar/gateway/cloudevents"
+	"github.com/stellar/gateway/queue"
+	"github.com/stellar/go/support/errors"
+)
+
+// QueueCallbackTransport sends receive callbacks to a cloud queue (SQS, Pub/Sub).
+// Attributes contains names of message attributes to attach: `asset_code`,
+// `asset_issuer` and `account` (account ID of the sender).
+type QueueCallbackTransport struct {
+	Publisher  queue.Publisher
+	Attributes []string
+	// When set, X_PAYLOAD_MAC attribute is attached to every message
+	MACKey string
+	// Format makes messages CloudEvents when set
+	Format *cloudevents.Formatter
+}
+
+// queueAttributePayloadFields maps message attributes to callback payload fields
+var queueAttributePayloadFields = map[string]string{
+	"asset_code":   "asset_code",
+	"asset_issuer": "asset_issuer",
+	"account":      "from",
+}
+
+// Send publishes payload to the queue
+func (t *QueueCallbackTransport) Send(payload url.Values) error {
+	message, err := callbackMessage(payload, t.Format)
+	if err != nil {
+		return errors.Wrap(err, "Error encoding callback message")
+	}
+
+	attributes, err := macHeaders(t.MACKey, message)
+	if err != nil {
+		return err
+	}
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	for _, name := range t.Attributes {
+		value := payload.Get(queueAttributePayloadFields[name])
+		// Queues do not allow empty attribute values (native asset has no issuer)
+		if value != "" {
+			attributes[name] = value
+		}
+	}
+
+	return t.Publisher.Publish(message, attributes)
+}
@@ -0,0 +1,48 @@
+package listener
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/stellar/gateway/amqp"
+	"github.com/stellar/gateway/cloudevents"
+	"github.com/stellar/go/support/errors"
+)
+
+const amqpTimeout = 10 * time.Second
+
+// RabbitMQCallbackTransport publishes receive callbacks to a RabbitMQ exchange
+// over AMQP 0-9-1. Messages are persistent and the callback is considered
+// delivered when the broker confirms the message. Publish is considered
+// failed when the message was not routed to any queue.
+type RabbitMQCallbackTransport struct {
+	Publisher *amqp.Publisher
+	// When set, X_PAYLOAD_MAC header is attached to every message
+	MACKey string
+	// Format makes messages CloudEvents when set
+	Format *cloudevents.Formatter
+}
+
+// Send publishes payload to the exchange
+func (t *RabbitMQCallbackTransport) Send(payload url.Values) error {
+	message, err := callbackMessage(payload, t.Format)
+	if err != nil {
+		return errors.Wrap(err, "Error encoding callback message")
+	}
+
+	headers, err := macHeaders(t.MACKey, message)
+	if err != nil {
+		return err
+	}
+
+	err = t.Publisher.Publish(amqp.Message{
+		MessageID:   payload.Get("id"),
+		ContentType: t.Format.ContentType(),
+		Headers:     headers,
+		Body:        message,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error publishing to RabbitMQ")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package listener
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/go/support/errors"
+)
+
+var callbackDeadLetters = metrics.NewCounter("bridge_callback_dead_letters_total", "Number of receive callbacks that failed on all callback endpoints.")
+
+// DeadLetters keeps receive callbacks that could not be delivered, so they can
+// be inspected, sent again or discarded by an admin. Methods of nil
+// DeadLetters do nothing.
+type DeadLetters struct {
+	repository    db.RepositoryInterface
+	entityManager db.EntityManagerInterface
+	now           func() time.Time
+}
+
+// NewDeadLetters creates DeadLetters stored in FailedCallback table
+func NewDeadLetters(repository db.RepositoryInterface, entityManager db.EntityManagerInterface, now func() time.Time) *DeadLetters {
+	return &DeadLetters{repository: repository, entityManager: entityManager, now: now}
+}
+
+// Record stores the result of sending payload. A failed callback is stored
+// with deliveryErr, a callback that failed before is marked as delivered when
+// deliveryErr is nil (ex. when the payment is reprocessed).
+func (d *DeadLetters) Record(ctx context.Context, payload url.Values, deliveryErr error) error {
+	if d == nil {
+		return nil
+	}
+
+	failedCallback, err := d.repository.GetFailedCallback(ctx, payload.Get("id"))
+	if err != nil {
+		return errors.Wrap(err, "Error getting FailedCallback")
+	}
+
+	if failedCallback == nil {
+		if deliveryErr == nil {
+			return nil
+		}
+		failedCallback = &entities.FailedCallback{
+			OperationID: payload.Get("id"),
+			CreatedAt:   d.now(),
+		}
+	}
+
+	return d.update(failedCallback, payload, deliveryErr)
+}
+
+func (d *DeadLetters) update(failedCallback *entities.FailedCallback, payload url.Values, deliveryErr error) error {
+	if deliveryErr == nil {
+		failedCallback.Status = entities.FailedCallbackStatusDelivered
+	} else {
+		callbackDeadLetters.Inc()
+		failedCallback.Status = entities.FailedCallbackStatusFailed
+		failedCallback.LastError = deliveryErr.Error()
+		failedCallback.Attempts++
+	}
+	failedCallback.Payload = payload.Encode()
+	failedCallback.UpdatedAt = d.now()
+	return d.entityManager.Persist(failedCallback)
+}
+
+// RetryCallback sends a failed receive callback again. When it's delivered
+// status of the received payment is changed to Success.
+func (pl *PaymentListener) RetryCallback(failedCallback *entities.FailedCallback) error {
+	if pl.DeadLetters == nil {
+		return errors.New("Failed callbacks are not stored")
+	}
+
+	payload, err := failedCallback.Values()
+	if err != nil {
+		return errors.Wrap(err, "Invalid callback payload")
+	}
+
+	deliveryErr := pl.callbackTransport().Send(payload)
+	err = pl.DeadLetters.update(failedCallback, payload, deliveryErr)
+	if err != nil {
+		return errors.Wrap(err, "Error persisting FailedCallback")
+	}
+
+	if deliveryErr != nil {
+		return deliveryErr
+	}
+
+	receivedPayment, err := pl.repository.GetReceivedPaymentByOperationID(pl.ctx, failedCallback.OperationID)
+	if err != nil || receivedPayment == nil {
+		return err
+	}
+
+	receivedPayment.Status = "Success"
+	return pl.entityManager.Persist(receivedPayment)
+}
@@ -0,0 +1,98 @@
+package listener
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetters(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	deadLetters := NewDeadLetters(repository, entityManager, mocks.Now)
+	ctx := context.Background()
+	payload := url.Values{"id": {"100"}, "amount": {"20.0000000"}, "memo": {"1"}}
+
+	// Delivered callbacks are not stored
+	require.NoError(t, deadLetters.Record(ctx, payload, nil))
+	failedCallback, err := repository.GetFailedCallback(ctx, "100")
+	require.NoError(t, err)
+	assert.Nil(t, failedCallback)
+
+	require.NoError(t, deadLetters.Record(ctx, payload, errors.New("Error response from receive callback")))
+	require.NoError(t, deadLetters.Record(ctx, payload, errors.New("No receive callback URL")))
+	failedCallback, err = repository.GetFailedCallback(ctx, "100")
+	require.NoError(t, err)
+	require.NotNil(t, failedCallback)
+	assert.Equal(t, entities.FailedCallbackStatusFailed, failedCallback.Status)
+	assert.Equal(t, "No receive callback URL", failedCallback.LastError)
+	assert.Equal(t, 2, failedCallback.Attempts)
+	values, err := failedCallback.Values()
+	require.NoError(t, err)
+	assert.Equal(t, payload, values)
+
+	require.NoError(t, entityManager.Persist(&entities.ReceivedPayment{
+		OperationID: "100",
+		ProcessedAt: mocks.PredefinedTime,
+		Status:      "Error response from receive callback",
+	}))
+
+	status := http.StatusServiceUnavailable
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		received = r.PostForm
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	pl := &PaymentListener{
+		client:           http.DefaultClient,
+		config:           &config.Config{},
+		repository:       repository,
+		entityManager:    entityManager,
+		receiveEndpoints: NewCallbackEndpoints([]string{server.URL}, 0, 0),
+		DeadLetters:      deadLetters,
+		ctx:              ctx,
+	}
+
+	assert.Error(t, pl.RetryCallback(failedCallback))
+	assert.Equal(t, entities.FailedCallbackStatusFailed, failedCallback.Status)
+	assert.Equal(t, 3, failedCallback.Attempts)
+
+	status = http.StatusOK
+	require.NoError(t, pl.RetryCallback(failedCallback))
+	assert.Equal(t, payload, received)
+
+	failedCallback, err = repository.GetFailedCallback(ctx, "100")
+	require.NoError(t, err)
+	assert.Equal(t, entities.FailedCallbackStatusDelivered, failedCallback.Status)
+	assert.Equal(t, 3, failedCallback.Attempts)
+
+	receivedPayment, err := repository.GetReceivedPaymentByOperationID(ctx, "100")
+	require.NoError(t, err)
+	assert.Equal(t, "Success", receivedPayment.Status)
+
+	failedCallbacks, err := repository.GetFailedCallbacks(ctx, entities.FailedCallbackStatusFailed, 10)
+	require.NoError(t, err)
+	assert.Empty(t, failedCallbacks)
+
+	var nilDeadLetters *DeadLetters
+	assert.NoError(t, nilDeadLetters.Record(ctx, payload, errors.New("error")))
+}
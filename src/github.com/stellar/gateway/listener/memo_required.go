@@ -0,0 +1,118 @@
+package listener
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// StreamReturnedPayments learns memo requirements from payments received by
+// accountID, ex. the base account when it's not the receiving account.
+// Destinations return payments to the account that sent them, so these
+// payments are not streamed with received payments. Payments are streamed
+// from "now" in a new goroutine and the cursor is not saved: payments returned
+// while the server is down are not learned. Only the leader learns them.
+func (pl *PaymentListener) StreamReturnedPayments(backend ListenerBackend, accountID string) {
+	onPayment := func(payment horizon.PaymentResponse) error {
+		if pl.ctx.Err() != nil || !pl.isLeader() {
+			return nil
+		}
+
+		if payment.To != accountID || payment.Type != "payment" && !payment.IsPathPayment() {
+			return nil
+		}
+
+		err := backend.LoadMemo(&payment)
+		if err != nil {
+			return errors.Wrap(err, "Unable to load transaction memo")
+		}
+
+		pl.learnMemoRequirement(payment)
+		return nil
+	}
+
+	go func() {
+		cursor := "now"
+		for pl.ctx.Err() == nil {
+			pl.log.WithFields(logrus.Fields{"accountId": accountID}).Info("Started listening for returned payments")
+			err := backend.StreamPayments(accountID, &cursor, onPayment)
+			if err != nil {
+				pl.log.Error("Error while streaming returned payments: ", err)
+				select {
+				case <-time.After(10 * time.Second):
+				case <-pl.ctx.Done():
+				}
+			}
+		}
+	}()
+}
+
+// learnMemoRequirement checks if the payment returns a transaction sent by the
+// bridge server (memo type "return" with a hash of the sent transaction). When
+// the returned transaction had no memo, the sender of the payment is added to
+// memo required destinations so future payments to it can be checked.
+func (pl *PaymentListener) learnMemoRequirement(payment horizon.PaymentResponse) {
+	if payment.Memo.Type != "return" {
+		return
+	}
+
+	log := pl.log.WithFields(logrus.Fields{"destination": payment.From})
+
+	hash, err := base64.StdEncoding.DecodeString(payment.Memo.Value)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Warn("Cannot decode return memo")
+		return
+	}
+
+	transactionID := hex.EncodeToString(hash)
+	sentTransaction, err := pl.repository.GetSentTransactionByTransactionID(pl.ctx, transactionID)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Error getting SentTransaction")
+		return
+	}
+
+	if sentTransaction == nil {
+		return
+	}
+
+	var envelope xdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(sentTransaction.EnvelopeXdr, &envelope)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Cannot decode returned transaction envelope")
+		return
+	}
+
+	if envelope.Tx.Memo.Type != xdr.MemoTypeMemoNone {
+		return
+	}
+
+	existing, err := pl.repository.GetMemoRequiredDestination(pl.ctx, payment.From)
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Error getting MemoRequiredDestination")
+		return
+	}
+
+	if existing != nil {
+		return
+	}
+
+	err = pl.entityManager.Persist(&entities.MemoRequiredDestination{
+		AccountID:     payment.From,
+		Source:        entities.MemoRequiredSourceLearned,
+		Reason:        "Payment without memo returned",
+		TransactionID: &transactionID,
+		CreatedAt:     pl.now(),
+	})
+	if err != nil {
+		log.WithFields(logrus.Fields{"err": err}).Error("Error persisting MemoRequiredDestination")
+		return
+	}
+
+	log.WithFields(logrus.Fields{"transaction_id": transactionID}).Info("Destination requires memo")
+}
@@ -0,0 +1,168 @@
+package listener
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const returningAccountID = "GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2"
+
+func sentTransaction(t *testing.T, memo xdr.Memo) *entities.SentTransaction {
+	envelope := xdr.TransactionEnvelope{Tx: xdr.Transaction{Fee: 100, SeqNum: 1, Memo: memo}}
+	require.NoError(t, envelope.Tx.SourceAccount.SetAddress("GCOGCYU77DLEVYCXDQM7F32M5PCKES6VU3Z5GURF6U6OA5LFOVTRYPOX"))
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	return &entities.SentTransaction{EnvelopeXdr: envelopeXdr}
+}
+
+func TestLearnMemoRequirement(t *testing.T) {
+	var hash [32]byte
+	hash[0] = 1
+	transactionID := hex.EncodeToString(hash[:])
+
+	payment := horizon.PaymentResponse{From: returningAccountID}
+	payment.Memo.Type = "return"
+	payment.Memo.Value = base64.StdEncoding.EncodeToString(hash[:])
+
+	t.Run("returned payment without memo", func(t *testing.T) {
+		mockRepository := new(mocks.MockRepository)
+		mockEntityManager := new(mocks.MockEntityManager)
+		pl := &PaymentListener{
+			repository:    mockRepository,
+			entityManager: mockEntityManager,
+			now:           mocks.Now,
+			log:           logrus.WithFields(logrus.Fields{}),
+		}
+
+		mockRepository.On("GetSentTransactionByTransactionID", transactionID).
+			Return(sentTransaction(t, xdr.Memo{Type: xdr.MemoTypeMemoNone}), nil).Once()
+		mockRepository.On("GetMemoRequiredDestination", returningAccountID).Return(nil, nil).Once()
+		mockEntityManager.On("Persist", mock.AnythingOfType("*entities.MemoRequiredDestination")).
+			Run(func(args mock.Arguments) {
+				destination := args.Get(0).(*entities.MemoRequiredDestination)
+				assert.Equal(t, returningAccountID, destination.AccountID)
+				assert.Equal(t, entities.MemoRequiredSourceLearned, destination.Source)
+				assert.Equal(t, transactionID, *destination.TransactionID)
+				assert.Equal(t, mocks.PredefinedTime, destination.CreatedAt)
+			}).Return(nil).Once()
+
+		pl.learnMemoRequirement(payment)
+		mockRepository.AssertExpectations(t)
+		mockEntityManager.AssertExpectations(t)
+	})
+
+	t.Run("returned payment with memo", func(t *testing.T) {
+		mockRepository := new(mocks.MockRepository)
+		mockEntityManager := new(mocks.MockEntityManager)
+		pl := &PaymentListener{
+			repository:    mockRepository,
+			entityManager: mockEntityManager,
+			now:           mocks.Now,
+			log:           logrus.WithFields(logrus.Fields{}),
+		}
+
+		id := xdr.Uint64(12)
+		mockRepository.On("GetSentTransactionByTransactionID", transactionID).
+			Return(sentTransaction(t, xdr.Memo{Type: xdr.MemoTypeMemoId, Id: &id}), nil).Once()
+
+		pl.learnMemoRequirement(payment)
+		mockRepository.AssertExpectations(t)
+		mockEntityManager.AssertNotCalled(t, "Persist", mock.Anything)
+	})
+
+	t.Run("not a return memo", func(t *testing.T) {
+		mockRepository := new(mocks.MockRepository)
+		pl := &PaymentListener{repository: mockRepository, log: logrus.WithFields(logrus.Fields{})}
+
+		textPayment := payment
+		textPayment.Memo.Type = "text"
+		pl.learnMemoRequirement(textPayment)
+		mockRepository.AssertNotCalled(t, "GetSentTransactionByTransactionID", mock.Anything)
+	})
+}
+
+func TestLearnMemoRequirementReturnedToBase(t *testing.T) {
+	const baseAccountID = "GAHA6GRCLCCN7XPHRL5VB6DEAADDEBXBHUCTEB4PCPDHAKZV5JTMOVZ4"
+
+	var hash [32]byte
+	hash[0] = 2
+	transactionID := hex.EncodeToString(hash[:])
+	setReturnMemo := func(args mock.Arguments) {
+		memo := &args.Get(0).(*horizon.PaymentResponse).Memo
+		memo.Type, memo.Value = "return", base64.StdEncoding.EncodeToString(hash[:])
+	}
+
+	newListener := func(mockRepository *mocks.MockRepository, mockEntityManager *mocks.MockEntityManager, mockHorizon *mocks.MockHorizon) PaymentListener {
+		pl, err := NewPaymentListener(
+			&config.Config{Accounts: config.Accounts{ReceivingAccountID: "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"}},
+			mockEntityManager,
+			mockHorizon,
+			mockRepository,
+			mocks.Now,
+		)
+		require.NoError(t, err)
+
+		mockRepository.On("GetSentTransactionByTransactionID", transactionID).
+			Return(sentTransaction(t, xdr.Memo{Type: xdr.MemoTypeMemoNone}), nil).Once()
+		mockRepository.On("GetMemoRequiredDestination", returningAccountID).Return(nil, nil).Once()
+		mockEntityManager.On("Persist", mock.AnythingOfType("*entities.MemoRequiredDestination")).
+			Run(func(args mock.Arguments) {
+				assert.Equal(t, returningAccountID, args.Get(0).(*entities.MemoRequiredDestination).AccountID)
+			}).Return(nil).Once()
+		return pl
+	}
+
+	t.Run("streamed with received payments", func(t *testing.T) {
+		mockRepository := new(mocks.MockRepository)
+		mockEntityManager := new(mocks.MockEntityManager)
+		mockHorizon := new(mocks.MockHorizon)
+		pl := newListener(mockRepository, mockEntityManager, mockHorizon)
+
+		// Payment returned to the base account is skipped but learned
+		payment := horizon.PaymentResponse{ID: "1", Type: "payment", From: returningAccountID, To: baseAccountID, AssetType: "native"}
+		mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+		mockHorizon.On("LoadMemo", &payment).Run(setReturnMemo).Return(nil).Once()
+		mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).Return(nil).Twice()
+
+		require.NoError(t, pl.onPayment(payment))
+		mockRepository.AssertExpectations(t)
+		mockEntityManager.AssertExpectations(t)
+	})
+
+	t.Run("streamed separately", func(t *testing.T) {
+		mockRepository := new(mocks.MockRepository)
+		mockEntityManager := new(mocks.MockEntityManager)
+		mockHorizon := new(mocks.MockHorizon)
+		pl := newListener(mockRepository, mockEntityManager, mockHorizon)
+
+		streamed := make(chan struct{})
+		mockHorizon.On("LoadMemo", mock.AnythingOfType("*horizon.PaymentResponse")).Run(setReturnMemo).Return(nil).Once()
+		mockHorizon.On("StreamPayments", baseAccountID, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				onPayment := args.Get(2).(horizon.PaymentHandler)
+				// Payments sent by the base account are not loaded
+				assert.NoError(t, onPayment(horizon.PaymentResponse{Type: "payment", From: baseAccountID, To: returningAccountID}))
+				assert.NoError(t, onPayment(horizon.PaymentResponse{Type: "payment", From: returningAccountID, To: baseAccountID}))
+				pl.Stop()
+				close(streamed)
+			}).Return(errors.New("closed")).Once()
+
+		pl.StreamReturnedPayments(mockHorizon, baseAccountID)
+		<-streamed
+		mockHorizon.AssertExpectations(t)
+		mockRepository.AssertExpectations(t)
+		mockEntityManager.AssertExpectations(t)
+	})
+}
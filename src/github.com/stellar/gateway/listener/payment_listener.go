@@ -1,26 +1,32 @@
 package listener
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"encoding/base64"
-
 	"github.com/sirupsen/logrus"
 	"github.com/stellar/gateway/bridge/config"
 	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/expected"
 	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/amounts"
 	callback "github.com/stellar/gateway/protocols/compliance"
+	"github.com/stellar/gateway/rates"
+	"github.com/stellar/gateway/references"
+	"github.com/stellar/gateway/reports"
+	"github.com/stellar/gateway/sep24"
+	"github.com/stellar/gateway/stream"
+	"github.com/stellar/gateway/webhooks"
 	"github.com/stellar/go/protocols/compliance"
-	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 )
 
@@ -33,6 +39,94 @@ type PaymentListener struct {
 	log           *logrus.Entry
 	repository    db.RepositoryInterface
 	now           func() time.Time
+	// transport is nil when receive callbacks are sent over HTTP
+	transport CallbackTransport
+	// receiveEndpoints keeps health of callbacks.receive URLs between callbacks
+	receiveEndpoints *CallbackEndpoints
+	// Backend streams received payments, Horizon by default
+	Backend ListenerBackend
+	// Aggregates receives processed payments, nil when daily aggregates are
+	// not maintained
+	Aggregates *reports.Aggregator
+	// Rates records fiat values of received payments, nil when rates are not
+	// configured
+	Rates *rates.Recorder
+	// Live holds accepted assets changed by config reloads
+	Live *config.Live
+	// Events records receive callbacks for subscribers of payment streams,
+	// nil when streams are disabled
+	Events *stream.Publisher
+	// Webhooks receives payment_received events, nil when webhooks are
+	// disabled
+	Webhooks *webhooks.Dispatcher
+	// Expected matches received payments with expected payments, nil when
+	// expected payments are disabled
+	Expected *expected.Matcher
+	// References resolves memos derived from internal references, nil when
+	// memo references are disabled
+	References *references.Store
+	// SEP24 matches received payments with SEP-24 withdrawals, nil when
+	// SEP-24 transactions are disabled
+	SEP24 *sep24.Tracker
+	// DeadLetters stores receive callbacks that could not be delivered, nil
+	// when they are only logged
+	DeadLetters *DeadLetters
+	// Leadership stops streaming payments when this replica is not the
+	// leader, nil when leader election is disabled
+	Leadership Leadership
+	// ctx is used in DB queries and cancelled by Stop
+	ctx    context.Context
+	cancel context.CancelFunc
+	// processing is locked while a streamed payment is processed
+	processing *sync.Mutex
+	// stopping is 1 after Shutdown is called, new payments are not processed
+	stopping int32
+}
+
+// errStopping is returned for payments streamed after Shutdown is called, so
+// they are processed again after restart
+var errStopping = errors.New("Payment listener is stopping")
+
+// errNotLeader is returned for payments streamed after this replica stepped
+// down as the leader, they are processed by the new leader (or again when
+// this replica is elected)
+var errNotLeader = errors.New("Payment listener is not the leader")
+
+// leaderPollInterval is the interval of checking if a follower became the
+// leader before it starts streaming
+const leaderPollInterval = time.Second
+
+// Leadership tells if this replica is the leader, see leader.Elector
+type Leadership interface {
+	IsLeader() bool
+}
+
+// ListenerBackend is a source of received payments. It's implemented by
+// horizon.Horizon, stellarcore.DatabaseStream and stellarcore.ArchiveStream.
+type ListenerBackend interface {
+	StreamPayments(accountID string, cursor *string, onPaymentHandler horizon.PaymentHandler) error
+	LoadMemo(p *horizon.PaymentResponse) error
+	LoadAccountMergeAmount(p *horizon.PaymentResponse) error
+}
+
+// StreamTracker is implemented by backends reporting the time the payments
+// stream was last known to be up to date
+type StreamTracker interface {
+	StreamUpdatedAt() time.Time
+}
+
+// LiquidityPoolLoader is implemented by backends reporting liquidity pools
+// path payments were routed through: horizon.Horizon (from effects) and
+// stellarcore streams (from operation results).
+type LiquidityPoolLoader interface {
+	LoadLiquidityPools(p *horizon.PaymentResponse) error
+}
+
+// ContractTransferSource streams SAC transfers to accounts and contracts as
+// invoke_host_function payments. It's implemented by
+// sorobanrpc.TransferStream, Horizon does not stream transfers to contracts.
+type ContractTransferSource interface {
+	StreamTransfers(addresses []string, cursor *string, onPaymentHandler horizon.PaymentHandler) error
 }
 
 // HTTP represents an http client that a payment listener can use to make HTTP
@@ -51,20 +145,49 @@ func NewPaymentListener(
 	repository db.RepositoryInterface,
 	now func() time.Time,
 ) (pl PaymentListener, err error) {
-	pl.client = &http.Client{
+	pl.client = config.OutboundTLS.Client(http.Client{
 		Timeout: callbackTimeout,
-	}
+	})
 	pl.config = config
+	pl.Live = config.NewLive()
 	pl.entityManager = entityManager
 	pl.horizon = horizon
+	pl.Backend = horizon
 	pl.repository = repository
 	pl.now = now
+	pl.ctx, pl.cancel = context.WithCancel(context.Background())
+	pl.processing = &sync.Mutex{}
+	pl.receiveEndpoints = NewCallbackEndpoints(
+		config.Callbacks.Receive,
+		config.Callbacks.Failover.Threshold,
+		config.Callbacks.Failover.RecoveryIntervalDuration(),
+	)
+
+	if config.Callbacks.Transport != "" && config.Callbacks.Transport != CallbackTransportHTTP {
+		var tlsConfig *tls.Config
+		tlsConfig, err = config.OutboundTLS.TLSConfig()
+		if err != nil {
+			return
+		}
+		pl.transport, err = NewCallbackTransport(config.Callbacks, config.MACKey, tlsConfig, pl.client, config.EventFormatter(config.Callbacks.Format, config.Callbacks.SchemaVersion))
+		if err != nil {
+			return
+		}
+	}
+
 	pl.log = logrus.WithFields(logrus.Fields{
 		"service": "PaymentListener",
 	})
 	return
 }
 
+// SetReceiveURLs replaces callbacks.receive URLs when the config is reloaded
+func (pl *PaymentListener) SetReceiveURLs(urls []string) {
+	if pl.receiveEndpoints != nil {
+		pl.receiveEndpoints.SetURLs(urls)
+	}
+}
+
 // Listen starts listening for new payments
 func (pl *PaymentListener) Listen() (err error) {
 	accountID := pl.config.Accounts.ReceivingAccountID
@@ -76,7 +199,20 @@ func (pl *PaymentListener) Listen() (err error) {
 
 	go func() {
 		for {
-			cursor, err := pl.repository.GetLastCursorValue()
+			if pl.ctx.Err() != nil {
+				pl.log.Info("Stopped listening for new payments")
+				return
+			}
+
+			if !pl.isLeader() {
+				select {
+				case <-time.After(leaderPollInterval):
+				case <-pl.ctx.Done():
+				}
+				continue
+			}
+
+			cursor, err := pl.repository.GetLastCursorValue(pl.ctx)
 			if err != nil {
 				pl.log.WithFields(logrus.Fields{"error": err}).Error("Could not load last cursor from the DB")
 				return
@@ -96,7 +232,7 @@ func (pl *PaymentListener) Listen() (err error) {
 				"cursor":    cursorValue,
 			}).Info("Started listening for new payments")
 
-			err = pl.horizon.StreamPayments(
+			err = pl.Backend.StreamPayments(
 				accountID,
 				cursor,
 				pl.onPayment,
@@ -104,7 +240,10 @@ func (pl *PaymentListener) Listen() (err error) {
 			if err != nil {
 				pl.log.Error("Error while streaming: ", err)
 				pl.log.Info("Sleeping...")
-				time.Sleep(10 * time.Second)
+				select {
+				case <-time.After(10 * time.Second):
+				case <-pl.ctx.Done():
+				}
 			}
 		}
 	}()
@@ -112,16 +251,139 @@ func (pl *PaymentListener) Listen() (err error) {
 	return
 }
 
-func (pl *PaymentListener) ReprocessPayment(payment horizon.PaymentResponse, force bool) error {
-	pl.log.WithFields(logrus.Fields{"id": payment.ID}).Info("Reprocessing a payment")
+// StreamContractTransfers starts streaming SAC transfers to the receiving
+// account and listener.contracts from source in a new goroutine. Transfers
+// are processed like payments, the cursor is the ID of the last contract
+// event processed. invoke_host_function operations streamed by the backend
+// are skipped then, so transfers to the receiving account are processed once.
+func (pl *PaymentListener) StreamContractTransfers(source ContractTransferSource) {
+	addresses := pl.receivingAddresses()
+
+	go func() {
+		for {
+			if pl.ctx.Err() != nil {
+				pl.log.Info("Stopped listening for contract transfers")
+				return
+			}
+
+			if !pl.isLeader() {
+				select {
+				case <-time.After(leaderPollInterval):
+				case <-pl.ctx.Done():
+				}
+				continue
+			}
 
-	id, err := strconv.ParseInt(payment.ID, 10, 64)
+			cursor, err := pl.repository.GetLastContractEventCursor(pl.ctx)
+			if err != nil {
+				pl.log.WithFields(logrus.Fields{"error": err}).Error("Could not load last contract event cursor from the DB")
+				return
+			}
+
+			cursorValue := "now"
+			if cursor != nil {
+				cursorValue = *cursor
+			}
+
+			pl.log.WithFields(logrus.Fields{
+				"addresses": addresses,
+				"cursor":    cursorValue,
+			}).Info("Started listening for contract transfers")
+
+			err = source.StreamTransfers(addresses, &cursorValue, pl.onContractTransfer)
+			if err != nil {
+				pl.log.Error("Error while streaming contract transfers: ", err)
+				pl.log.Info("Sleeping...")
+				select {
+				case <-time.After(10 * time.Second):
+				case <-pl.ctx.Done():
+				}
+			}
+		}
+	}()
+}
+
+// receivingAddresses returns the receiving account and contracts whose
+// received transfers are processed
+func (pl *PaymentListener) receivingAddresses() []string {
+	return append([]string{pl.config.Accounts.ReceivingAccountID}, pl.config.Listener.Contracts...)
+}
+
+// Stop stops listening for new payments. Running DB queries are cancelled
+// and payments streamed after Stop are not processed.
+func (pl *PaymentListener) Stop() {
+	pl.cancel()
+}
+
+// Shutdown stops processing new payments and waits until the payment being
+// processed is saved, so the next server resumes from its paging token. Then
+// it works like Stop. It returns ctx error when ctx is done before the
+// payment is saved.
+func (pl *PaymentListener) Shutdown(ctx context.Context) error {
+	if pl.cancel == nil {
+		return nil
+	}
+	atomic.StoreInt32(&pl.stopping, 1)
+	defer pl.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		pl.processing.Lock()
+		pl.processing.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	cursor, err := pl.repository.GetLastCursorValue(ctx)
 	if err != nil {
-		pl.log.WithFields(logrus.Fields{"err": err}).Error("Error converting ID to int64")
 		return err
 	}
+	if cursor != nil {
+		pl.log.WithFields(logrus.Fields{"cursor": *cursor}).Info("Payment listener stopped")
+	}
+	return nil
+}
+
+func (pl *PaymentListener) isLeader() bool {
+	return pl.Leadership == nil || pl.Leadership.IsLeader()
+}
 
-	existingPayment, err := pl.repository.GetReceivedPaymentByOperationID(id)
+// ReprocessPayment processes a payment again. Compliance data of a repaired
+// payment is loaded using the memo of the latest repair.
+func (pl *PaymentListener) ReprocessPayment(payment horizon.PaymentResponse, force bool) error {
+	var complianceMemo string
+	if pl.config.Compliance != "" {
+		repair, err := pl.repository.GetComplianceRepairByOperationID(pl.ctx, payment.ID)
+		if err != nil {
+			pl.log.WithFields(logrus.Fields{"err": err}).Error("Error checking if payment has been repaired")
+			return err
+		}
+
+		if repair != nil {
+			complianceMemo = repair.ComplianceMemo
+		}
+	}
+
+	return pl.reprocess(payment, force, complianceMemo)
+}
+
+// RepairPayment processes a payment again using compliance data stored under
+// complianceMemo instead of the memo of the payment transaction. It should be
+// used after manual verification when the memo hash does not match the
+// attachment (ex. because of a bug on the sender side).
+func (pl *PaymentListener) RepairPayment(payment horizon.PaymentResponse, complianceMemo string) error {
+	return pl.reprocess(payment, true, complianceMemo)
+}
+
+func (pl *PaymentListener) reprocess(payment horizon.PaymentResponse, force bool, complianceMemo string) error {
+	pl.log.WithFields(logrus.Fields{"id": payment.ID}).Info("Reprocessing a payment")
+
+	existingPayment, err := pl.repository.GetReceivedPaymentByOperationID(pl.ctx, payment.ID)
 	if err != nil {
 		pl.log.WithFields(logrus.Fields{"err": err}).Error("Error checking if receive payment exists")
 		return err
@@ -145,7 +407,7 @@ func (pl *PaymentListener) ReprocessPayment(payment horizon.PaymentResponse, for
 		return err
 	}
 
-	err = pl.process(payment)
+	err = pl.process(&payment, complianceMemo)
 
 	if err != nil {
 		pl.log.WithFields(logrus.Fields{"err": err}).Error("Payment reprocessed with errors")
@@ -158,16 +420,52 @@ func (pl *PaymentListener) ReprocessPayment(payment horizon.PaymentResponse, for
 	return pl.entityManager.Persist(existingPayment)
 }
 
-func (pl *PaymentListener) onPayment(payment horizon.PaymentResponse) (err error) {
-	pl.log.WithFields(logrus.Fields{"id": payment.ID}).Info("New received payment")
+// onPayment processes a payment streamed by the backend. Every SAC transfer
+// to the receiving addresses in invoke_host_function operation is received
+// as a separate payment.
+func (pl *PaymentListener) onPayment(payment horizon.PaymentResponse) error {
+	if payment.Type != "invoke_host_function" {
+		return pl.receive(payment, false)
+	}
 
-	id, err := strconv.ParseInt(payment.ID, 10, 64)
-	if err != nil {
-		pl.log.WithFields(logrus.Fields{"err": err}).Error("Error converting ID to int64")
+	// Transfers are received from soroban-rpc, keyed by their events
+	if pl.config.Listener.SorobanRPCURL != "" {
+		return nil
+	}
+
+	for _, transfer := range payment.ContractTransfers(pl.receivingAddresses()...) {
+		err := pl.receive(transfer, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onContractTransfer processes a contract transfer streamed with the memo
+// made from the muxed ID of its destination. Soroban transactions have no
+// memos so it's not loaded from the backend.
+func (pl *PaymentListener) onContractTransfer(payment horizon.PaymentResponse) error {
+	return pl.receive(payment, true)
+}
+
+func (pl *PaymentListener) receive(payment horizon.PaymentResponse, memoLoaded bool) (err error) {
+	pl.processing.Lock()
+	defer pl.processing.Unlock()
+
+	if atomic.LoadInt32(&pl.stopping) == 1 {
+		return errStopping
+	}
+	if !pl.isLeader() {
+		return errNotLeader
+	}
+	if err = pl.ctx.Err(); err != nil {
 		return err
 	}
 
-	existingPayment, err := pl.repository.GetReceivedPaymentByOperationID(id)
+	pl.log.WithFields(logrus.Fields{"id": payment.ID}).Info("New received payment")
+
+	existingPayment, err := pl.repository.GetReceivedPaymentByOperationID(pl.ctx, payment.ID)
 	if err != nil {
 		pl.log.WithFields(logrus.Fields{"err": err}).Error("Error checking if receive payment exists")
 		return err
@@ -178,11 +476,20 @@ func (pl *PaymentListener) onPayment(payment horizon.PaymentResponse) (err error
 		return
 	}
 
-	memoError := pl.horizon.LoadMemo(&payment)
-	if memoError != nil {
-		return errors.Wrap(memoError, "Unable to load transaction memo")
+	// Memo is stored with skipped payments too. Payments are retried by the
+	// stream when it cannot be loaded.
+	if !memoLoaded {
+		err = pl.Backend.LoadMemo(&payment)
+		if err != nil {
+			return errors.Wrap(err, "Unable to load transaction memo")
+		}
 	}
 
+	// Returned payments are learned before filtering: they are usually sent
+	// to the base account, not the receiving account
+	pl.learnMemoRequirement(payment)
+	process, status := pl.shouldProcessPayment(payment)
+
 	dbPayment := &entities.ReceivedPayment{
 		OperationID:      payment.ID,
 		TransactionID:    payment.TransactionID,
@@ -198,12 +505,11 @@ func (pl *PaymentListener) onPayment(payment horizon.PaymentResponse) (err error
 		return
 	}
 
-	process, status := pl.shouldProcessPayment(payment)
 	if !process {
 		dbPayment.Status = status
 		pl.log.Info(status)
 	} else {
-		err = pl.process(payment)
+		err = pl.process(&payment, "")
 
 		if err != nil {
 			pl.log.WithFields(logrus.Fields{"err": err}).Error("Payment processed with errors")
@@ -212,6 +518,7 @@ func (pl *PaymentListener) onPayment(payment horizon.PaymentResponse) (err error
 			pl.log.Info("Payment successfully processed")
 			dbPayment.Status = "Success"
 		}
+		pl.Aggregates.RecordReceived(pl.ctx, payment, dbPayment.ProcessedAt)
 	}
 
 	return pl.entityManager.Persist(dbPayment)
@@ -220,15 +527,26 @@ func (pl *PaymentListener) onPayment(payment horizon.PaymentResponse) (err error
 // shouldProcessPayment returns false and text status if payment should not be processed
 // (ex. asset is different than allowed assets).
 func (pl *PaymentListener) shouldProcessPayment(payment horizon.PaymentResponse) (bool, string) {
-	if payment.Type != "payment" && payment.Type != "path_payment" && payment.Type != "account_merge" {
+	if payment.Type != "payment" && !payment.IsPathPayment() && payment.Type != "account_merge" && payment.Type != "invoke_host_function" {
 		return false, "Not a payment operation"
 	}
 
+	// Pool shares can't be sent in payments, they are listed by Horizon in
+	// balances only
+	if payment.AssetType == "liquidity_pool_shares" {
+		return false, "Liquidity pool shares are not transferable"
+	}
+
 	if payment.Type == "account_merge" {
 		payment.AssetType = "native"
 	}
 
-	if payment.To != pl.config.Accounts.ReceivingAccountID && payment.Into != pl.config.Accounts.ReceivingAccountID {
+	if payment.Type == "invoke_host_function" {
+		// Transfers can be received by contracts too
+		if !payment.LoadContractTransfer(pl.receivingAddresses()...) {
+			return false, "Not a contract transfer received"
+		}
+	} else if payment.To != pl.config.Accounts.ReceivingAccountID && payment.Into != pl.config.Accounts.ReceivingAccountID {
 		return false, "Operation sent not received"
 	}
 
@@ -239,21 +557,41 @@ func (pl *PaymentListener) shouldProcessPayment(payment horizon.PaymentResponse)
 	return true, ""
 }
 
-func (pl *PaymentListener) process(payment horizon.PaymentResponse) error {
+// process sends the payment to the receive callback. It loads the memo when
+// it's not loaded yet (and account_merge amount or SAC transfer of
+// invoke_host_function) into payment.
+// Compliance data is loaded using complianceMemo when it's not empty.
+func (pl *PaymentListener) process(payment *horizon.PaymentResponse, complianceMemo string) error {
+	// Stellar Asset Contract transfers are sent to the callback like payments
+	contract := payment.LoadContractTransfer(pl.receivingAddresses()...)
+
 	if payment.Type == "account_merge" {
 		payment.AssetType = "native"
 		payment.From = payment.Account
 		payment.To = payment.Into
 
-		err := pl.horizon.LoadAccountMergeAmount(&payment)
+		err := pl.Backend.LoadAccountMergeAmount(payment)
 		if err != nil {
 			return errors.Wrap(err, "Unable to load account_merge amount")
 		}
 	}
 
-	err := pl.horizon.LoadMemo(&payment)
-	if err != nil {
-		return errors.Wrap(err, "Unable to load transaction memo")
+	// Pools are informational, the payment is sent without them when they
+	// can't be loaded
+	loader, ok := pl.Backend.(LiquidityPoolLoader)
+	if ok && pl.config.Listener.LiquidityPoolIDs && payment.IsPathPayment() {
+		err := loader.LoadLiquidityPools(payment)
+		if err != nil {
+			pl.log.WithFields(logrus.Fields{"id": payment.ID, "err": err}).Warn("Unable to load liquidity pools of path payment")
+		}
+	}
+
+	// Memo type is "none" when a loaded transaction has no memo
+	if payment.Memo.Type == "" {
+		err := pl.Backend.LoadMemo(payment)
+		if err != nil {
+			return errors.Wrap(err, "Unable to load transaction memo")
+		}
 	}
 
 	pl.log.WithFields(logrus.Fields{"memo": payment.Memo.Value, "type": payment.Memo.Type}).Info("Loaded memo")
@@ -264,7 +602,10 @@ func (pl *PaymentListener) process(payment horizon.PaymentResponse) error {
 	// Request extra_memo from compliance server
 	if pl.config.Compliance != "" && payment.Memo.Type == "hash" {
 		complianceRequestURL := pl.config.Compliance + "/receive"
-		complianceRequestBody := url.Values{"memo": {string(payment.Memo.Value)}}
+		if complianceMemo == "" {
+			complianceMemo = payment.Memo.Value
+		}
+		complianceRequestBody := url.Values{"memo": {complianceMemo}}
 
 		pl.log.WithFields(logrus.Fields{"url": complianceRequestURL, "body": complianceRequestBody}).Info("Sending request to compliance server")
 		resp, err := pl.postForm(complianceRequestURL, complianceRequestBody)
@@ -308,44 +649,106 @@ func (pl *PaymentListener) process(payment horizon.PaymentResponse) error {
 		route = payment.Memo.Value
 	}
 
-	resp, err := pl.postForm(
-		pl.config.Callbacks.Receive,
-		url.Values{
-			"id":             {payment.ID},
-			"from":           {payment.From},
-			"route":          {route},
-			"amount":         {payment.Amount},
-			"asset_code":     {payment.AssetCode},
-			"asset_issuer":   {payment.AssetIssuer},
-			"memo_type":      {payment.Memo.Type},
-			"memo":           {payment.Memo.Value},
-			"data":           {receiveResponse.Data},
-			"transaction_id": {payment.TransactionID},
-		},
-	)
+	// Sent in the format used on the ledger whatever the backend returns
+	amount, err := amounts.ParseAmount(payment.Amount)
 	if err != nil {
-		return errors.Wrap(err, "Error sending request to receive callback")
+		return errors.Wrap(err, "Invalid payment amount")
 	}
 
-	if resp.StatusCode != 200 {
-		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return errors.Wrap(err, "Error reading receive callback response")
+	values := url.Values{
+		"id":             {payment.ID},
+		"from":           {payment.From},
+		"route":          {route},
+		"amount":         {amount.String()},
+		"asset_code":     {payment.AssetCode},
+		"asset_issuer":   {payment.AssetIssuer},
+		"memo_type":      {payment.Memo.Type},
+		"memo":           {payment.Memo.Value},
+		"data":           {receiveResponse.Data},
+		"transaction_id": {payment.TransactionID},
+	}
+	if receiveResponse.PrivateNote != "" {
+		values.Set("private_note", receiveResponse.PrivateNote)
+	}
+	if contract {
+		values.Set("origin", "contract")
+		// Receiving account or one of listener.contracts
+		values.Set("to", payment.To)
+	}
+	// Payments to muxed accounts (M...) of the receiving account
+	if payment.ToMuxedID != "" {
+		values.Set("to_muxed_id", payment.ToMuxedID)
+	}
+	if len(payment.LiquidityPoolIDs) > 0 {
+		values.Set("liquidity_pool_ids", strings.Join(payment.LiquidityPoolIDs, ","))
+	}
+
+	reference, err := pl.References.Resolve(pl.ctx, payment.Memo.Type, payment.Memo.Value)
+	if err != nil {
+		return errors.Wrap(err, "Error resolving memo reference")
+	}
+	if reference != "" {
+		values.Set("reference", reference)
+	}
+
+	match, err := pl.Expected.Match(pl.ctx, *payment, pl.now())
+	if err != nil {
+		return errors.Wrap(err, "Error matching expected payment")
+	}
+	if match != nil {
+		values.Set("expected_payment_status", match.Status)
+		if match.ExpectedID != "" {
+			values.Set("expected_payment_id", match.ExpectedID)
+			values.Set("expected_payment_received", match.ReceivedAmount.String())
 		}
+	}
 
-		pl.log.WithFields(logrus.Fields{
-			"status": resp.StatusCode,
-			"body":   string(body),
-		}).Error("Error response from receive callback")
-		return errors.New("Error response from receive callback")
+	withdrawal, err := pl.SEP24.Match(pl.ctx, *payment, pl.now())
+	if err != nil {
+		return errors.Wrap(err, "Error matching SEP-24 withdrawal")
+	}
+	if withdrawal != nil {
+		values.Set("sep24_transaction_id", withdrawal.TransactionID)
+		values.Set("sep24_status", withdrawal.Status)
 	}
 
-	return nil
+	// Value of the time the payment was first processed
+	if fiat := pl.Rates.RecordReceived(pl.ctx, *payment, pl.now()); fiat != nil {
+		values.Set("fiat_amount", fiat.FiatAmount)
+		values.Set("fiat_currency", fiat.Currency)
+		values.Set("fiat_rate", fiat.Rate)
+	}
+
+	pl.Events.Publish(payment.ID, values)
+	pl.publishWebhookEvent(values)
+	if (pl.Events != nil || pl.Webhooks != nil) && pl.transport == nil && len(pl.receiveEndpoints.URLs()) == 0 {
+		// Payments are only streamed or sent to webhooks, there are no
+		// receive callbacks
+		return nil
+	}
+
+	err = pl.callbackTransport().Send(values)
+	if recordErr := pl.DeadLetters.Record(pl.ctx, values, err); recordErr != nil {
+		pl.log.WithFields(logrus.Fields{"err": recordErr}).Error("Error recording failed callback")
+	}
+	return err
+}
+
+// callbackTransport returns transport used to send receive callbacks. When no
+// message bus transport is configured callbacks are sent to callbacks.receive.
+func (pl *PaymentListener) callbackTransport() CallbackTransport {
+	if pl.transport != nil {
+		return pl.transport
+	}
+	return &HTTPCallbackTransport{
+		Client:    pl.client,
+		Endpoints: pl.receiveEndpoints,
+		MACKey:    pl.config.MACKey,
+	}
 }
 
 func (pl *PaymentListener) isAssetAllowed(asset_type string, code string, issuer string) bool {
-	for _, asset := range pl.config.Assets {
+	for _, asset := range pl.Live.Get().Assets {
 		if asset.Code == code && asset.Issuer == issuer {
 			return true
 		}
@@ -362,41 +765,19 @@ func (pl *PaymentListener) postForm(
 	url string,
 	form url.Values,
 ) (*http.Response, error) {
-
-	strbody := form.Encode()
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(strbody))
-	if err != nil {
-		return nil, errors.Wrap(err, "configure http request failed")
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	if pl.config.MACKey != "" {
-		rawMAC, err := pl.getMAC(pl.config.MACKey, []byte(strbody))
-		if err != nil {
-			return nil, errors.Wrap(err, "getMAC failed")
-		}
-
-		encMAC := base64.StdEncoding.EncodeToString(rawMAC)
-		req.Header.Set("X_PAYLOAD_MAC", encMAC)
-	}
-
-	resp, err := pl.client.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "http request errored")
-	}
-
-	return resp, nil
+	return postForm(context.Background(), pl.client, url, form, pl.config.MACKey)
 }
 
-func (pl *PaymentListener) getMAC(key string, raw []byte) ([]byte, error) {
-
-	rawkey, err := strkey.Decode(strkey.VersionByteSeed, pl.config.MACKey)
-	if err != nil {
-		return nil, errors.Wrap(err, "invalid MAC key")
+// publishWebhookEvent sends payment_received event with params of the receive
+// callback to webhooks
+func (pl *PaymentListener) publishWebhookEvent(values url.Values) {
+	if pl.Webhooks == nil {
+		return
 	}
 
-	macer := hmac.New(sha256.New, rawkey)
-	macer.Write(raw)
-	return macer.Sum(nil), nil
+	data := map[string]string{}
+	for name := range values {
+		data[name] = values.Get(name)
+	}
+	pl.Webhooks.Publish(pl.ctx, webhooks.EventPaymentReceived, data)
 }
@@ -1,6 +1,7 @@
 package listener
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -54,7 +55,7 @@ func TestPaymentListener(t *testing.T) {
 			ReceivingAccountID: "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
 		},
 		Callbacks: config.Callbacks{
-			Receive: "http://receive_callback",
+			Receive: []string{"http://receive_callback"},
 		},
 	}
 
@@ -70,6 +71,8 @@ func TestPaymentListener(t *testing.T) {
 	paymentListener.client = mockHTTPClient
 
 	Convey("PaymentListener", t, func() {
+		paymentListener.config.Compliance = ""
+
 		operation := horizon.PaymentResponse{
 			ID:          "1",
 			From:        "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
@@ -84,7 +87,7 @@ func TestPaymentListener(t *testing.T) {
 
 		Convey("When operation exists", func() {
 			operation.Type = "payment"
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(&entities.ReceivedPayment{}, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(&entities.ReceivedPayment{}, nil).Once()
 
 			Convey("it should save the status", func() {
 				err := paymentListener.onPayment(operation)
@@ -100,9 +103,18 @@ func TestPaymentListener(t *testing.T) {
 				Run(ensurePaymentStatus(t, operation, "Processing...")).Return(nil).Once()
 
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
-				Run(ensurePaymentStatus(t, operation, "Not a payment operation")).Return(nil).Once()
-
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+				Run(func(args mock.Arguments) {
+					ensurePaymentStatus(t, operation, "Not a payment operation")(args)
+					// Memo is stored with skipped payments
+					assert.Equal(t, "123", args.Get(0).(*entities.ReceivedPayment).MemoID)
+				}).Return(nil).Once()
+
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+			// LoadMemo sets the memo, so the payment does not equal operation after the call
+			mockHorizon.On("LoadMemo", mock.AnythingOfType("*horizon.PaymentResponse")).Run(func(args mock.Arguments) {
+				memo := &args.Get(0).(*horizon.PaymentResponse).Memo
+				memo.Type, memo.Value = "id", "123"
+			}).Return(nil).Once()
 
 			Convey("it should save the status", func() {
 				err := paymentListener.onPayment(operation)
@@ -122,7 +134,8 @@ func TestPaymentListener(t *testing.T) {
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
 				Run(ensurePaymentStatus(t, operation, "Operation sent not received")).Return(nil).Once()
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			Convey("it should save the status", func() {
 				err := paymentListener.onPayment(operation)
@@ -144,7 +157,8 @@ func TestPaymentListener(t *testing.T) {
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
 				Run(ensurePaymentStatus(t, operation, "Asset not allowed")).Return(nil).Once()
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			Convey("it should save the status", func() {
 				err := paymentListener.onPayment(operation)
@@ -166,7 +180,8 @@ func TestPaymentListener(t *testing.T) {
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
 				Run(ensurePaymentStatus(t, operation, "Asset not allowed")).Return(nil).Once()
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			Convey("it should save the status", func() {
 				err := paymentListener.onPayment(operation)
@@ -190,7 +205,8 @@ func TestPaymentListener(t *testing.T) {
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
 				Run(ensurePaymentStatus(t, operation, "Asset not allowed")).Return(nil).Once()
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			Convey("it should save the status", func() {
 				err := paymentListener.onPayment(operation)
@@ -211,7 +227,7 @@ func TestPaymentListener(t *testing.T) {
 			config.Assets[1].Code = "XLM"
 			config.Assets[1].Issuer = ""
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
@@ -245,21 +261,17 @@ func TestPaymentListener(t *testing.T) {
 			operation.AssetCode = "USD"
 			operation.AssetIssuer = "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
-
-			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
-				Run(ensurePaymentStatus(t, operation, "Processing...")).Return(nil).Once()
-
-			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
-				Run(ensurePaymentStatus(t, operation, "Unable to load transaction memo: Connection error")).Return(nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
 
 			mockHorizon.On("LoadMemo", &operation).Return(errors.New("Connection error")).Once()
 
-			Convey("it should save the status", func() {
+			Convey("it should return the error, so the stream retries the payment", func() {
+				calls := len(mockEntityManager.Calls)
 				err := paymentListener.onPayment(operation)
-				assert.NoError(t, err)
+				assert.EqualError(t, err, "Unable to load transaction memo: Connection error")
 				mockHorizon.AssertExpectations(t)
-				mockEntityManager.AssertExpectations(t)
+				// Payment is not saved
+				assert.Len(t, mockEntityManager.Calls, calls)
 				mockRepository.AssertExpectations(t)
 			})
 		})
@@ -272,7 +284,7 @@ func TestPaymentListener(t *testing.T) {
 			operation.Memo.Type = "text"
 			operation.Memo.Value = "testing"
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
@@ -310,7 +322,7 @@ func TestPaymentListener(t *testing.T) {
 			operation.Memo.Type = "text"
 			operation.Memo.Value = "testing"
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
@@ -331,7 +343,8 @@ func TestPaymentListener(t *testing.T) {
 				req := args.Get(0).(*http.Request)
 
 				assert.Equal(t, operation.From, req.PostFormValue("from"))
-				assert.Equal(t, operation.Amount, req.PostFormValue("amount"))
+				// Amount is sent with 7 decimal places
+				assert.Equal(t, "100.0000000", req.PostFormValue("amount"))
 				assert.Equal(t, operation.AssetCode, req.PostFormValue("asset_code"))
 				assert.Equal(t, operation.AssetIssuer, req.PostFormValue("asset_issuer"))
 				assert.Equal(t, operation.Memo.Type, req.PostFormValue("memo_type"))
@@ -363,7 +376,7 @@ func TestPaymentListener(t *testing.T) {
 			config.Assets[1].Code = "XLM"
 			config.Assets[1].Issuer = ""
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
 			mockHorizon.On("LoadAccountMergeAmount", &operation).Return(nil).Once()
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
@@ -385,7 +398,7 @@ func TestPaymentListener(t *testing.T) {
 				req := args.Get(0).(*http.Request)
 
 				assert.Equal(t, operation.Account, req.PostFormValue("from"))
-				assert.Equal(t, operation.Amount, req.PostFormValue("amount"))
+				assert.Equal(t, "100.0000000", req.PostFormValue("amount"))
 				assert.Equal(t, operation.AssetCode, req.PostFormValue("asset_code"))
 				assert.Equal(t, operation.AssetIssuer, req.PostFormValue("asset_issuer"))
 				assert.Equal(t, operation.Memo.Type, req.PostFormValue("memo_type"))
@@ -413,8 +426,11 @@ func TestPaymentListener(t *testing.T) {
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
 				Run(ensurePaymentStatus(t, operation, "Success")).Return(nil).Once()
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
-			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+			// LoadMemo sets the memo, so the payment does not equal operation after the call
+			mockHorizon.On("LoadMemo", mock.AnythingOfType("*horizon.PaymentResponse")).Run(func(args mock.Arguments) {
+				args.Get(0).(*horizon.PaymentResponse).Memo.Type = "none"
+			}).Return(nil).Once()
 
 			mockHTTPClient.On(
 				"Do",
@@ -445,7 +461,7 @@ func TestPaymentListener(t *testing.T) {
 			operation.Memo.Type = "hash"
 			operation.Memo.Value = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
@@ -527,7 +543,7 @@ func TestPaymentListener(t *testing.T) {
 				existingPayment.SetExists()
 
 				mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
-				mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(&existingPayment, nil).Once()
+				mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(&existingPayment, nil).Once()
 
 				mockEntityManager.On("Persist", &existingPayment).Return(nil).
 					Run(func(args mock.Arguments) {
@@ -560,6 +576,90 @@ func TestPaymentListener(t *testing.T) {
 				mockHorizon.AssertExpectations(t)
 				mockEntityManager.AssertExpectations(t)
 			})
+
+			Convey("it should use compliance data of repaired payment", func() {
+				paymentListener.config.Compliance = "http://compliance"
+
+				operation := horizon.PaymentResponse{
+					ID:          "1",
+					From:        "GBIHSMPXC2KJ3NJVHEYTG3KCHYEUQRT45X6AWYWXMAXZOAX4F5LFZYYQ",
+					PagingToken: "2",
+					Amount:      "200",
+					Type:        "payment",
+					To:          "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB",
+					AssetCode:   "USD",
+					AssetIssuer: "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR",
+				}
+				operation.Memo.Type = "hash"
+				operation.Memo.Value = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+				repairedMemo := "a28b7dc1d6bd7f5aa1d1b07a1c43bacd2ab13004c0a5a3e0f674c87d10b4e4b5"
+
+				var id int64 = 3
+				existingPayment := entities.ReceivedPayment{
+					ID:          &id,
+					OperationID: operation.ID,
+					ProcessedAt: mocks.PredefinedTime,
+					PagingToken: operation.PagingToken,
+					Status:      "Error response from compliance server",
+				}
+				existingPayment.SetExists()
+
+				// Memo of the payment is loaded, so it is not loaded again
+				mockRepository.On("GetComplianceRepairByOperationID", "1").Return(&entities.ComplianceRepair{
+					OperationID:    "1",
+					OriginalMemo:   operation.Memo.Value,
+					ComplianceMemo: repairedMemo,
+				}, nil).Once()
+				mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(&existingPayment, nil).Once()
+
+				mockEntityManager.On("Persist", &existingPayment).Return(nil).Twice()
+
+				attachment := compliance.Attachment{
+					Transaction: compliance.Transaction{
+						Route: "jed*stellar.org",
+					},
+				}
+				attachmentString, _ := json.Marshal(attachment)
+				auth := compliance.AuthData{AttachmentJSON: string(attachmentString)}
+				authString, _ := json.Marshal(auth)
+				responseString, _ := json.Marshal(callback.ReceiveResponse{Data: string(authString)})
+
+				mockHTTPClient.On(
+					"Do",
+					mock.MatchedBy(func(req *http.Request) bool {
+						return req.URL.String() == "http://compliance/receive"
+					}),
+				).Return(
+					net.BuildHTTPResponse(200, string(responseString)),
+					nil,
+				).Run(func(args mock.Arguments) {
+					req := args.Get(0).(*http.Request)
+					assert.Equal(t, repairedMemo, req.PostFormValue("memo"))
+				}).Once()
+
+				mockHTTPClient.On(
+					"Do",
+					mock.MatchedBy(func(req *http.Request) bool {
+						return req.URL.String() == "http://receive_callback"
+					}),
+				).Return(
+					net.BuildHTTPResponse(200, "ok"),
+					nil,
+				).Run(func(args mock.Arguments) {
+					req := args.Get(0).(*http.Request)
+					assert.Equal(t, operation.Memo.Value, req.PostFormValue("memo"))
+					assert.Equal(t, "jed*stellar.org", req.PostFormValue("route"))
+					assert.Equal(t, string(authString), req.PostFormValue("data"))
+				}).Once()
+
+				err := paymentListener.ReprocessPayment(operation, false)
+				assert.Nil(t, err)
+				assert.Equal(t, "Success", existingPayment.Status)
+				mockHorizon.AssertExpectations(t)
+				mockEntityManager.AssertExpectations(t)
+				mockRepository.AssertExpectations(t)
+			})
 		})
 
 		Convey("When transaction id is not returned (current horizon release)", func() {
@@ -570,7 +670,7 @@ func TestPaymentListener(t *testing.T) {
 			operation.Memo.Type = "text"
 			operation.Memo.Value = "testing"
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
@@ -609,7 +709,7 @@ func TestPaymentListener(t *testing.T) {
 			operation.Memo.Value = "testing"
 			operation.TransactionID = "18ce6c3320d35e683cb653a3e812ce43e8f5c24ab0d0e87668d5591c679c9755"
 
-			mockRepository.On("GetReceivedPaymentByOperationID", int64(1)).Return(nil, nil).Once()
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
 			mockHorizon.On("LoadMemo", &operation).Return(nil).Once()
 
 			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).
@@ -630,7 +730,7 @@ func TestPaymentListener(t *testing.T) {
 				req := args.Get(0).(*http.Request)
 
 				assert.Equal(t, operation.From, req.PostFormValue("from"))
-				assert.Equal(t, operation.Amount, req.PostFormValue("amount"))
+				assert.Equal(t, "100.0000000", req.PostFormValue("amount"))
 				assert.Equal(t, operation.AssetCode, req.PostFormValue("asset_code"))
 				assert.Equal(t, operation.AssetIssuer, req.PostFormValue("asset_issuer"))
 				assert.Equal(t, operation.Memo.Type, req.PostFormValue("memo_type"))
@@ -695,3 +795,345 @@ func TestPostForm_MACKey(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid MAC key")
 	}
 }
+
+func TestShouldProcessContractTransfer(t *testing.T) {
+	receivingAccountID := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+	issuer := "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+
+	cfg := &config.Config{
+		Assets: []config.Asset{{Code: "USD", Issuer: issuer}},
+	}
+	cfg.Accounts.ReceivingAccountID = receivingAccountID
+	pl, err := NewPaymentListener(cfg, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	payment := horizon.PaymentResponse{
+		Type: "invoke_host_function",
+		AssetBalanceChanges: []horizon.AssetBalanceChange{
+			{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: issuer, Type: "mint", To: receivingAccountID, Amount: "5.0000000"},
+			{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: issuer, Type: "transfer", From: returningAccountID, To: receivingAccountID, Amount: "20.0000000"},
+		},
+	}
+
+	process, status := pl.shouldProcessPayment(payment)
+	assert.True(t, process, status)
+
+	// shouldProcessPayment does not change the payment, process loads the
+	// transfer
+	assert.Empty(t, payment.From)
+	require.True(t, payment.LoadContractTransfer(receivingAccountID))
+	assert.Equal(t, returningAccountID, payment.From)
+	assert.Equal(t, "20.0000000", payment.Amount)
+	assert.Equal(t, "USD", payment.AssetCode)
+
+	payment = horizon.PaymentResponse{
+		Type: "invoke_host_function",
+		AssetBalanceChanges: []horizon.AssetBalanceChange{
+			{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: issuer, Type: "transfer", From: receivingAccountID, To: returningAccountID, Amount: "20.0000000"},
+		},
+	}
+	process, status = pl.shouldProcessPayment(payment)
+	assert.False(t, process)
+	assert.Equal(t, "Not a contract transfer received", status)
+
+	payment.AssetBalanceChanges[0].From, payment.AssetBalanceChanges[0].To = returningAccountID, receivingAccountID
+	payment.AssetBalanceChanges[0].AssetCode = "EUR"
+	process, status = pl.shouldProcessPayment(payment)
+	assert.False(t, process)
+	assert.Equal(t, "Asset not allowed", status)
+
+	// Transfers to contracts are received when they are in listener.contracts
+	contractID := "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	payment.AssetBalanceChanges[0].To = contractID
+	payment.AssetBalanceChanges[0].AssetCode = "USD"
+	process, status = pl.shouldProcessPayment(payment)
+	assert.False(t, process)
+	assert.Equal(t, "Not a contract transfer received", status)
+
+	cfg.Listener.Contracts = []string{contractID}
+	process, status = pl.shouldProcessPayment(payment)
+	assert.True(t, process, status)
+}
+
+func TestContractTransferToContract(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+	mockHTTPClient := new(mocks.MockHTTPClient)
+
+	contractID := "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	cfg := &config.Config{
+		Assets: []config.Asset{{Code: "XLM"}},
+		Callbacks: config.Callbacks{
+			Receive: []string{"http://receive_callback"},
+		},
+	}
+	cfg.Accounts.ReceivingAccountID = "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+	cfg.Listener.Contracts = []string{contractID}
+
+	pl, err := NewPaymentListener(cfg, mockEntityManager, mockHorizon, mockRepository, mocks.Now)
+	require.NoError(t, err)
+	pl.client = mockHTTPClient
+
+	payment := horizon.PaymentResponse{
+		ID:            "429496733697-1",
+		Type:          "invoke_host_function",
+		PagingToken:   "0000000429496733696-0000000001",
+		TransactionID: "abc",
+		AssetBalanceChanges: []horizon.AssetBalanceChange{
+			{AssetType: "native", Type: "transfer", From: returningAccountID, To: contractID, Amount: "0.1000000"},
+		},
+	}
+	payment.Memo.Type, payment.Memo.Value = "id", "7"
+
+	mockRepository.On("GetReceivedPaymentByOperationID", "429496733697-1").Return(nil, nil).Once()
+	mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).Return(nil).Twice()
+	mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(net.BuildHTTPResponse(200, "ok"), nil).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		assert.Equal(t, "contract", req.PostFormValue("origin"))
+		assert.Equal(t, contractID, req.PostFormValue("to"))
+		assert.Equal(t, returningAccountID, req.PostFormValue("from"))
+		assert.Equal(t, "0.1000000", req.PostFormValue("amount"))
+		assert.Equal(t, "id", req.PostFormValue("memo_type"))
+		assert.Equal(t, "7", req.PostFormValue("memo"))
+	}).Once()
+
+	// Memo made from muxed ID is not replaced by the transaction memo
+	require.NoError(t, pl.onContractTransfer(payment))
+	mockHorizon.AssertNotCalled(t, "LoadMemo", mock.Anything)
+	mockRepository.AssertExpectations(t)
+	mockEntityManager.AssertExpectations(t)
+	mockHTTPClient.AssertExpectations(t)
+}
+
+func TestContractTransfersOfOperation(t *testing.T) {
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+	mockRepository := new(mocks.MockRepository)
+	mockHTTPClient := new(mocks.MockHTTPClient)
+
+	cfg := &config.Config{
+		Assets: []config.Asset{{Code: "XLM"}},
+		Callbacks: config.Callbacks{
+			Receive: []string{"http://receive_callback"},
+		},
+	}
+	cfg.Accounts.ReceivingAccountID = "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+
+	pl, err := NewPaymentListener(cfg, mockEntityManager, mockHorizon, mockRepository, mocks.Now)
+	require.NoError(t, err)
+	pl.client = mockHTTPClient
+
+	payment := horizon.PaymentResponse{
+		ID:            "429496733697",
+		Type:          "invoke_host_function",
+		PagingToken:   "429496733697",
+		TransactionID: "abc",
+		AssetBalanceChanges: []horizon.AssetBalanceChange{
+			{AssetType: "native", Type: "transfer", From: returningAccountID, To: cfg.Accounts.ReceivingAccountID, Amount: "1.0000000"},
+			{AssetType: "native", Type: "transfer", From: cfg.Accounts.ReceivingAccountID, To: returningAccountID, Amount: "3.0000000"},
+			{AssetType: "native", Type: "transfer", From: returningAccountID, To: cfg.Accounts.ReceivingAccountID, Amount: "2.0000000"},
+		},
+	}
+	payment.Memo.Type = "none"
+
+	// Every transfer to the receiving account is a payment
+	mockRepository.On("GetReceivedPaymentByOperationID", "429496733697-0").Return(nil, nil).Once()
+	mockRepository.On("GetReceivedPaymentByOperationID", "429496733697-2").Return(nil, nil).Once()
+	mockHorizon.On("LoadMemo", mock.AnythingOfType("*horizon.PaymentResponse")).Return(nil)
+	mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).Return(nil).Times(4)
+	var amounts []string
+	mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(net.BuildHTTPResponse(200, "ok"), nil).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		amounts = append(amounts, req.PostFormValue("amount"))
+	}).Twice()
+
+	require.NoError(t, pl.onPayment(payment))
+	assert.Equal(t, []string{"1.0000000", "2.0000000"}, amounts)
+	mockRepository.AssertExpectations(t)
+	mockEntityManager.AssertExpectations(t)
+	mockHTTPClient.AssertExpectations(t)
+
+	// Transfers are received from soroban-rpc when it's configured
+	cfg.Listener.SorobanRPCURL = "https://soroban-testnet.stellar.org"
+	require.NoError(t, pl.onPayment(payment))
+	mockRepository.AssertNumberOfCalls(t, "GetReceivedPaymentByOperationID", 2)
+}
+
+func TestShouldProcessPathPayment(t *testing.T) {
+	receivingAccountID := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+
+	cfg := &config.Config{
+		Assets: []config.Asset{{Code: "XLM"}},
+	}
+	cfg.Accounts.ReceivingAccountID = receivingAccountID
+	pl, err := NewPaymentListener(cfg, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	for _, operationType := range []string{"path_payment", "path_payment_strict_receive", "path_payment_strict_send"} {
+		process, status := pl.shouldProcessPayment(horizon.PaymentResponse{Type: operationType, To: receivingAccountID, AssetType: "native"})
+		assert.True(t, process, status)
+	}
+
+	process, status := pl.shouldProcessPayment(horizon.PaymentResponse{Type: "payment", To: receivingAccountID, AssetType: "liquidity_pool_shares"})
+	assert.False(t, process)
+	assert.Equal(t, "Liquidity pool shares are not transferable", status)
+}
+
+// liquidityPoolBackend is a ListenerBackend implementing LiquidityPoolLoader
+type liquidityPoolBackend struct {
+	*mocks.MockHorizon
+	loads int
+	err   error
+}
+
+func (b *liquidityPoolBackend) LoadLiquidityPools(p *horizon.PaymentResponse) error {
+	b.loads++
+	if b.err != nil {
+		return b.err
+	}
+	p.LiquidityPoolIDs = []string{"dd7b1ab831c273310ddbec6f97870aa83c2fbd78ce22aded37ecbf4f3380fac7"}
+	return nil
+}
+
+func TestPathPaymentLiquidityPools(t *testing.T) {
+	receivingAccountID := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+
+	cfg := &config.Config{
+		Assets: []config.Asset{{Code: "XLM"}},
+		Callbacks: config.Callbacks{
+			Receive: []string{"http://receive_callback"},
+		},
+	}
+	cfg.Accounts.ReceivingAccountID = receivingAccountID
+
+	for _, test := range []struct {
+		name    string
+		enabled bool
+		err     error
+		loads   int
+		pools   string
+	}{
+		{"disabled", false, nil, 0, ""},
+		{"enabled", true, nil, 1, "dd7b1ab831c273310ddbec6f97870aa83c2fbd78ce22aded37ecbf4f3380fac7"},
+		{"effects not loaded", true, assert.AnError, 1, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			mockEntityManager := new(mocks.MockEntityManager)
+			mockRepository := new(mocks.MockRepository)
+			mockHTTPClient := new(mocks.MockHTTPClient)
+			cfg.Listener.LiquidityPoolIDs = test.enabled
+
+			pl, err := NewPaymentListener(cfg, mockEntityManager, new(mocks.MockHorizon), mockRepository, mocks.Now)
+			require.NoError(t, err)
+			pl.client = mockHTTPClient
+			backend := &liquidityPoolBackend{MockHorizon: new(mocks.MockHorizon), err: test.err}
+			backend.On("LoadMemo", mock.AnythingOfType("*horizon.PaymentResponse")).Return(nil).Once()
+			pl.Backend = backend
+
+			payment := horizon.PaymentResponse{
+				ID:          "1",
+				Type:        "path_payment_strict_send",
+				PagingToken: "1",
+				From:        returningAccountID,
+				To:          receivingAccountID,
+				AssetType:   "native",
+				Amount:      "10",
+			}
+			payment.Memo.Type = "none"
+
+			mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+			mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).Return(nil).Twice()
+			mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(net.BuildHTTPResponse(200, "ok"), nil).Run(func(args mock.Arguments) {
+				req := args.Get(0).(*http.Request)
+				assert.Equal(t, test.pools, req.PostFormValue("liquidity_pool_ids"))
+			}).Once()
+
+			// The payment is sent when pools can't be loaded
+			require.NoError(t, pl.onPayment(payment))
+			assert.Equal(t, test.loads, backend.loads)
+			mockHTTPClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPaymentToMuxedAccount(t *testing.T) {
+	receivingAccountID := "GATKP6ZQM5CSLECPMTAC5226PE367QALCPM6AFHTSULPPZMT62OOPMQB"
+
+	cfg := &config.Config{
+		Assets: []config.Asset{{Code: "XLM"}},
+		Callbacks: config.Callbacks{
+			Receive: []string{"http://receive_callback"},
+		},
+	}
+	cfg.Accounts.ReceivingAccountID = receivingAccountID
+
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockRepository := new(mocks.MockRepository)
+	mockHTTPClient := new(mocks.MockHTTPClient)
+	mockHorizon := new(mocks.MockHorizon)
+	pl, err := NewPaymentListener(cfg, mockEntityManager, mockHorizon, mockRepository, mocks.Now)
+	require.NoError(t, err)
+	pl.client = mockHTTPClient
+	mockHorizon.On("LoadMemo", mock.AnythingOfType("*horizon.PaymentResponse")).Return(nil).Once()
+
+	payment := horizon.PaymentResponse{
+		ID:          "1",
+		Type:        "payment",
+		PagingToken: "1",
+		From:        returningAccountID,
+		To:          receivingAccountID,
+		ToMuxed:     "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAAAAAAAACJUQ",
+		ToMuxedID:   "42",
+		AssetType:   "native",
+		Amount:      "10",
+	}
+	payment.Memo.Type = "none"
+
+	mockRepository.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+	mockEntityManager.On("Persist", mock.AnythingOfType("*entities.ReceivedPayment")).Return(nil).Twice()
+	mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(net.BuildHTTPResponse(200, "ok"), nil).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*http.Request)
+		assert.Equal(t, "42", req.PostFormValue("to_muxed_id"))
+	}).Once()
+
+	require.NoError(t, pl.onPayment(payment))
+	mockHTTPClient.AssertExpectations(t)
+}
+
+func TestPaymentListenerShutdown(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+	paymentListener, err := NewPaymentListener(&config.Config{}, new(mocks.MockEntityManager), new(mocks.MockHorizon), mockRepository, mocks.Now)
+	require.NoError(t, err)
+
+	// Shutdown waits for the payment being processed
+	paymentListener.processing.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, paymentListener.Shutdown(ctx))
+	paymentListener.processing.Unlock()
+
+	// Payments streamed after Shutdown are processed after restart
+	assert.Equal(t, errStopping, paymentListener.onPayment(horizon.PaymentResponse{ID: "1"}))
+	assert.Error(t, paymentListener.ctx.Err())
+
+	cursor := "2"
+	mockRepository.On("GetLastCursorValue").Return(&cursor, nil).Once()
+	assert.NoError(t, paymentListener.Shutdown(context.Background()))
+	mockRepository.AssertExpectations(t)
+}
+
+type leadership bool
+
+func (l leadership) IsLeader() bool { return bool(l) }
+
+func TestPaymentListenerFollower(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+	paymentListener, err := NewPaymentListener(&config.Config{}, new(mocks.MockEntityManager), new(mocks.MockHorizon), mockRepository, mocks.Now)
+	require.NoError(t, err)
+
+	// Payments are processed by the leader only
+	paymentListener.Leadership = leadership(false)
+	assert.Equal(t, errNotLeader, paymentListener.onPayment(horizon.PaymentResponse{ID: "1"}))
+	mockRepository.AssertNotCalled(t, "GetReceivedPaymentByOperationID", "1")
+}
@@ -0,0 +1,184 @@
+// Package metrics contains simple counters and gauges exposed in Prometheus
+// text format by /metrics endpoint.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultRegistry is a registry used by NewCounter, NewGauge and Handler
+var DefaultRegistry = NewRegistry()
+
+type metric interface {
+	kind() string
+	help() string
+	value() float64
+}
+
+// Registry contains named metrics
+type Registry struct {
+	lock    sync.RWMutex
+	metrics map[string]metric
+}
+
+// NewRegistry creates a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// Counter is a metric that only goes up
+type Counter struct {
+	description string
+	count       int64
+}
+
+// Inc increments counter by 1
+func (c *Counter) Inc() {
+	atomic.AddInt64(&c.count, 1)
+}
+
+// Add increments counter by delta
+func (c *Counter) Add(delta int64) {
+	atomic.AddInt64(&c.count, delta)
+}
+
+// Value returns current value of the counter
+func (c *Counter) Value() int64 {
+	return atomic.LoadInt64(&c.count)
+}
+
+func (c *Counter) kind() string   { return "counter" }
+func (c *Counter) help() string   { return c.description }
+func (c *Counter) value() float64 { return float64(c.Value()) }
+
+// Gauge is a metric that can be set to any value
+type Gauge struct {
+	description string
+	bits        uint64
+}
+
+// Set sets value of the gauge
+func (g *Gauge) Set(value float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(value))
+}
+
+// Value returns current value of the gauge
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+func (g *Gauge) kind() string   { return "gauge" }
+func (g *Gauge) help() string   { return g.description }
+func (g *Gauge) value() float64 { return g.Value() }
+
+// NewCounter registers a new Counter in the registry. Name can contain labels,
+// ex. `requests_total{endpoint="a"}`. It returns the existing Counter when
+// a counter with the same name is already registered.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	return r.register(name, &Counter{description: help}).(*Counter)
+}
+
+// NewGauge registers a new Gauge in the registry. Name can contain labels.
+// It returns the existing Gauge when a gauge with the same name is already
+// registered.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	return r.register(name, &Gauge{description: help}).(*Gauge)
+}
+
+// register adds m to the registry or returns already registered metric. It
+// panics when a metric of a different kind with the same name exists.
+func (r *Registry) register(name string, m metric) metric {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if existing, exists := r.metrics[name]; exists {
+		if existing.kind() != m.kind() {
+			panic("metrics: " + name + " already registered as " + existing.kind())
+		}
+		return existing
+	}
+	r.metrics[name] = m
+	return m
+}
+
+// WriteTo writes all metrics in Prometheus text format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.lock.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	r.lock.RUnlock()
+
+	// Metrics with labels are grouped with other metrics of the same family
+	sort.Slice(names, func(i, j int) bool {
+		if family(names[i]) != family(names[j]) {
+			return family(names[i]) < family(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	var written int64
+	var lastFamily string
+	for _, name := range names {
+		r.lock.RLock()
+		m := r.metrics[name]
+		r.lock.RUnlock()
+
+		var n int
+		var err error
+		if family(name) != lastFamily {
+			lastFamily = family(name)
+			n, err = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", lastFamily, m.help(), lastFamily, m.kind())
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+
+		n, err = fmt.Fprintf(w, "%s %s\n", name, strconv.FormatFloat(m.value(), 'g', -1, 64))
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// family returns metric name without labels
+func family(name string) string {
+	if i := strings.IndexByte(name, '{'); i != -1 {
+		return name[:i]
+	}
+	return name
+}
+
+// Label returns name with a single label, ex. `requests_total{endpoint="a"}`
+func Label(name, label, value string) string {
+	return name + "{" + label + "=" + strconv.Quote(value) + "}"
+}
+
+// NewCounter registers a new Counter in DefaultRegistry
+func NewCounter(name, help string) *Counter {
+	return DefaultRegistry.NewCounter(name, help)
+}
+
+// NewGauge registers a new Gauge in DefaultRegistry
+func NewGauge(name, help string) *Gauge {
+	return DefaultRegistry.NewGauge(name, help)
+}
+
+// Handler implements /metrics endpoint
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	DefaultRegistry.WriteTo(w)
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("test_events_total", "Number of events.")
+	gauge := registry.NewGauge("test_connected", "Connection status.")
+
+	counter.Inc()
+	counter.Add(2)
+	gauge.Set(1.5)
+
+	var buf bytes.Buffer
+	_, err := registry.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"# HELP test_connected Connection status.\n# TYPE test_connected gauge\ntest_connected 1.5\n"+
+			"# HELP test_events_total Number of events.\n# TYPE test_events_total counter\ntest_events_total 3\n",
+		buf.String(),
+	)
+
+	assert.Equal(t, counter, registry.NewCounter("test_events_total", "Number of events."))
+	assert.Panics(t, func() {
+		registry.NewCounter("test_connected", "Duplicate.")
+	})
+}
+
+func TestRegistryLabels(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounter(Label("test_requests_total", "endpoint", "b"), "Number of requests.").Inc()
+	registry.NewCounter(Label("test_requests_total", "endpoint", "a"), "Number of requests.").Add(2)
+	registry.NewCounter("test_requests_total_other", "Other.")
+
+	var buf bytes.Buffer
+	_, err := registry.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"# HELP test_requests_total Number of requests.\n# TYPE test_requests_total counter\n"+
+			"test_requests_total{endpoint=\"a\"} 2\ntest_requests_total{endpoint=\"b\"} 1\n"+
+			"# HELP test_requests_total_other Other.\n# TYPE test_requests_total_other counter\ntest_requests_total_other 0\n",
+		buf.String(),
+	)
+}
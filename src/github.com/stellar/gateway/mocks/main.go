@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"time"
@@ -90,12 +91,24 @@ func (m *MockHorizon) LoadAccount(accountID string) (response horizon.AccountRes
 	return a.Get(0).(horizon.AccountResponse), a.Error(1)
 }
 
+// LoadAccountFresh is a mocking a method
+func (m *MockHorizon) LoadAccountFresh(accountID string) (response horizon.AccountResponse, err error) {
+	a := m.Called(accountID)
+	return a.Get(0).(horizon.AccountResponse), a.Error(1)
+}
+
 // LoadOperation is a mocking a method
 func (m *MockHorizon) LoadOperation(operationID string) (response horizon.PaymentResponse, err error) {
 	a := m.Called(operationID)
 	return a.Get(0).(horizon.PaymentResponse), a.Error(1)
 }
 
+// LoadTransaction is a mocking a method
+func (m *MockHorizon) LoadTransaction(hash string) (response horizon.SubmitTransactionResponse, err error) {
+	a := m.Called(hash)
+	return a.Get(0).(horizon.SubmitTransactionResponse), a.Error(1)
+}
+
 // LoadMemo is a mocking a method
 func (m *MockHorizon) LoadMemo(p *horizon.PaymentResponse) (err error) {
 	a := m.Called(p)
@@ -120,19 +133,43 @@ func (m *MockHorizon) SubmitTransaction(txeBase64 string) (response horizon.Subm
 	return a.Get(0).(horizon.SubmitTransactionResponse), a.Error(1)
 }
 
-// MockRepository ...
+// FindPaths is a mocking a method
+func (m *MockHorizon) FindPaths(request horizon.PathsRequest) (paths []horizon.PathResponse, err error) {
+	a := m.Called(request)
+	return a.Get(0).([]horizon.PathResponse), a.Error(1)
+}
+
+// MockPublisher ...
+type MockPublisher struct {
+	mock.Mock
+}
+
+// Publish is a mocking a method
+func (m *MockPublisher) Publish(message []byte, attributes map[string]string) error {
+	a := m.Called(message, attributes)
+	return a.Error(0)
+}
+
+// MockRepository ... Contexts are not passed to mocked calls, so
+// expectations are set without them.
 type MockRepository struct {
 	mock.Mock
 }
 
 // GetLastCursorValue is a mocking a method
-func (m *MockRepository) GetLastCursorValue() (cursor *string, err error) {
+func (m *MockRepository) GetLastCursorValue(ctx context.Context) (cursor *string, err error) {
+	a := m.Called()
+	return a.Get(0).(*string), a.Error(1)
+}
+
+// GetLastContractEventCursor is a mocking a method
+func (m *MockRepository) GetLastContractEventCursor(ctx context.Context) (cursor *string, err error) {
 	a := m.Called()
 	return a.Get(0).(*string), a.Error(1)
 }
 
 // GetAuthorizedTransactionByMemo is a mocking a method
-func (m *MockRepository) GetAuthorizedTransactionByMemo(memo string) (*entities.AuthorizedTransaction, error) {
+func (m *MockRepository) GetAuthorizedTransactionByMemo(ctx context.Context, memo string) (*entities.AuthorizedTransaction, error) {
 	a := m.Called(memo)
 	if a.Get(0) == nil {
 		return nil, a.Error(1)
@@ -141,7 +178,7 @@ func (m *MockRepository) GetAuthorizedTransactionByMemo(memo string) (*entities.
 }
 
 // GetAllowedFiByDomain is a mocking a method
-func (m *MockRepository) GetAllowedFiByDomain(domain string) (*entities.AllowedFi, error) {
+func (m *MockRepository) GetAllowedFiByDomain(ctx context.Context, domain string) (*entities.AllowedFi, error) {
 	a := m.Called(domain)
 	if a.Get(0) == nil {
 		return nil, a.Error(1)
@@ -150,7 +187,7 @@ func (m *MockRepository) GetAllowedFiByDomain(domain string) (*entities.AllowedF
 }
 
 // GetAllowedUserByDomainAndUserID is a mocking a method
-func (m *MockRepository) GetAllowedUserByDomainAndUserID(domain, userID string) (*entities.AllowedUser, error) {
+func (m *MockRepository) GetAllowedUserByDomainAndUserID(ctx context.Context, domain, userID string) (*entities.AllowedUser, error) {
 	a := m.Called(domain, userID)
 	if a.Get(0) == nil {
 		return nil, a.Error(1)
@@ -159,7 +196,7 @@ func (m *MockRepository) GetAllowedUserByDomainAndUserID(domain, userID string)
 }
 
 // GetReceivedPaymentByOperationID is a mocking a method
-func (m *MockRepository) GetReceivedPaymentByOperationID(operationID int64) (*entities.ReceivedPayment, error) {
+func (m *MockRepository) GetReceivedPaymentByOperationID(ctx context.Context, operationID string) (*entities.ReceivedPayment, error) {
 	a := m.Called(operationID)
 	if a.Get(0) == nil {
 		return nil, a.Error(1)
@@ -167,31 +204,444 @@ func (m *MockRepository) GetReceivedPaymentByOperationID(operationID int64) (*en
 	return a.Get(0).(*entities.ReceivedPayment), a.Error(1)
 }
 
-func (m *MockRepository) GetReceivedPayments(page, limit int) ([]*entities.ReceivedPayment, error) {
-	// a := m.Called(page, limit)
-	// if a.Get(0) == nil {
-	// 	return nil, a.Error(1)
-	// }
-	// return a.Get(0).([]*entities.ReceivedPayment), a.Error(1)
-	return "Dummy", nil
+// GetReceivedPayments is a mocking a method
+func (m *MockRepository) GetReceivedPayments(ctx context.Context, filter db.ReceivedPaymentsFilter, page, limit int) ([]*entities.ReceivedPayment, error) {
+	a := m.Called(filter, page, limit)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.ReceivedPayment), a.Error(1)
+}
+
+// GetReceivedPaymentTotalByMemoID is a mocking a method
+func (m *MockRepository) GetReceivedPaymentTotalByMemoID(ctx context.Context, memoID string) (string, error) {
+	a := m.Called(memoID)
+	return a.String(0), a.Error(1)
 }
 
-func (m *MockRepository) GetSentTransactions(page, limit int) ([]*entities.SentTransaction, error) {
-	a := m.Called(page, limit)
+// GetSentTransactions is a mocking a method
+func (m *MockRepository) GetSentTransactions(ctx context.Context, filter db.SentTransactionsFilter, page, limit int) ([]*entities.SentTransaction, error) {
+	a := m.Called(filter, page, limit)
 	if a.Get(0) == nil {
 		return nil, a.Error(1)
 	}
 	return a.Get(0).([]*entities.SentTransaction), a.Error(1)
 }
 
-func (m *MockRepository) GetSentTransactionByPaymentID(paymentID string) (*entities.SentTransaction, error) {
+// GetSentTransactionByPaymentID is a mocking a method
+func (m *MockRepository) GetSentTransactionByPaymentID(ctx context.Context, paymentID string) (*entities.SentTransaction, error) {
+	a := m.Called(paymentID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.SentTransaction), a.Error(1)
+}
+
+// GetHeldPaymentByPaymentID is a mocking a method
+func (m *MockRepository) GetHeldPaymentByPaymentID(ctx context.Context, paymentID string) (*entities.HeldPayment, error) {
 	a := m.Called(paymentID)
 	if a.Get(0) == nil {
 		return nil, a.Error(1)
 	}
+	return a.Get(0).(*entities.HeldPayment), a.Error(1)
+}
+
+// GetHeldPaymentsToRelease is a mocking a method
+func (m *MockRepository) GetHeldPaymentsToRelease(ctx context.Context, now time.Time) ([]*entities.HeldPayment, error) {
+	a := m.Called(now)
+	return a.Get(0).([]*entities.HeldPayment), a.Error(1)
+}
+
+// GetHeldPayments is a mocking a method
+func (m *MockRepository) GetHeldPayments(ctx context.Context) ([]*entities.HeldPayment, error) {
+	a := m.Called()
+	return a.Get(0).([]*entities.HeldPayment), a.Error(1)
+}
+
+// UpdateHeldPaymentStatus is a mocking a method
+func (m *MockRepository) UpdateHeldPaymentStatus(ctx context.Context, payment *entities.HeldPayment, status string, now time.Time) (bool, error) {
+	a := m.Called(payment, status, now)
+	return a.Bool(0), a.Error(1)
+}
+
+// ApproveHeldPayment is a mocking a method
+func (m *MockRepository) ApproveHeldPayment(ctx context.Context, payment *entities.HeldPayment, approvedBy string, now, settleAt time.Time) (bool, error) {
+	a := m.Called(payment, approvedBy, now, settleAt)
+	return a.Bool(0), a.Error(1)
+}
+
+// GetComplianceRepairByOperationID is a mocking a method
+func (m *MockRepository) GetComplianceRepairByOperationID(ctx context.Context, operationID string) (*entities.ComplianceRepair, error) {
+	a := m.Called(operationID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.ComplianceRepair), a.Error(1)
+}
+
+// GetSentTransactionConflicts is a mocking a method
+func (m *MockRepository) GetSentTransactionConflicts(ctx context.Context) ([]*entities.SentTransaction, error) {
+	a := m.Called()
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.SentTransaction), a.Error(1)
+}
+
+// GetSentTransactionByTransactionID is a mocking a method
+func (m *MockRepository) GetSentTransactionByTransactionID(ctx context.Context, transactionID string) (*entities.SentTransaction, error) {
+	a := m.Called(transactionID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
 	return a.Get(0).(*entities.SentTransaction), a.Error(1)
 }
 
+// GetMemoRequiredDestination is a mocking a method
+func (m *MockRepository) GetMemoRequiredDestination(ctx context.Context, accountID string) (*entities.MemoRequiredDestination, error) {
+	a := m.Called(accountID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.MemoRequiredDestination), a.Error(1)
+}
+
+// GetMemoRequiredDestinations is a mocking a method
+func (m *MockRepository) GetMemoRequiredDestinations(ctx context.Context) ([]*entities.MemoRequiredDestination, error) {
+	a := m.Called()
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.MemoRequiredDestination), a.Error(1)
+}
+
+// GetFeatureFlag is a mocking a method
+func (m *MockRepository) GetFeatureFlag(ctx context.Context, name, tenant string) (*entities.FeatureFlag, error) {
+	a := m.Called(name, tenant)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.FeatureFlag), a.Error(1)
+}
+
+// GetFeatureFlags is a mocking a method
+func (m *MockRepository) GetFeatureFlags(ctx context.Context, name string) ([]*entities.FeatureFlag, error) {
+	a := m.Called(name)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.FeatureFlag), a.Error(1)
+}
+
+// GetSecurityEventsToDeliver is a mocking a method
+func (m *MockRepository) GetSecurityEventsToDeliver(ctx context.Context, now time.Time, limit int) ([]*entities.SecurityEvent, error) {
+	a := m.Called(now, limit)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.SecurityEvent), a.Error(1)
+}
+
+// GetAPIKey is a mocking a method
+func (m *MockRepository) GetAPIKey(ctx context.Context, keyID string) (*entities.APIKey, error) {
+	a := m.Called(keyID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.APIKey), a.Error(1)
+}
+
+// GetDailyAggregates is a mocking a method
+func (m *MockRepository) GetDailyAggregates(ctx context.Context, filter db.DailyAggregatesFilter) ([]*entities.DailyAggregate, error) {
+	a := m.Called(filter)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.DailyAggregate), a.Error(1)
+}
+
+// AddToDailyAggregate is a mocking a method
+func (m *MockRepository) AddToDailyAggregate(ctx context.Context, aggregate *entities.DailyAggregate) error {
+	a := m.Called(aggregate)
+	return a.Error(0)
+}
+
+// GetFederationSnapshots is a mocking a method
+func (m *MockRepository) GetFederationSnapshots(ctx context.Context, filter db.FederationSnapshotsFilter) ([]*entities.FederationSnapshot, error) {
+	a := m.Called(filter)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.FederationSnapshot), a.Error(1)
+}
+
+// GetReceivedPaymentEvents is a mocking a method
+func (m *MockRepository) GetReceivedPaymentEvents(ctx context.Context, afterID int64, limit int) ([]*entities.ReceivedPaymentEvent, error) {
+	a := m.Called(afterID, limit)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.ReceivedPaymentEvent), a.Error(1)
+}
+
+// GetLastReceivedPaymentEventID is a mocking a method
+func (m *MockRepository) GetLastReceivedPaymentEventID(ctx context.Context) (int64, error) {
+	a := m.Called()
+	return a.Get(0).(int64), a.Error(1)
+}
+
+// GetPaymentLimitUsage is a mocking a method
+func (m *MockRepository) GetPaymentLimitUsage(ctx context.Context, day, assetCode, assetIssuer, destination string) (int64, error) {
+	a := m.Called(day, assetCode, assetIssuer, destination)
+	return a.Get(0).(int64), a.Error(1)
+}
+
+// AddToPaymentLimitUsage is a mocking a method
+func (m *MockRepository) AddToPaymentLimitUsage(ctx context.Context, usage *entities.PaymentLimitUsage) error {
+	a := m.Called(usage)
+	return a.Error(0)
+}
+
+// GetNamedDestination is a mocking a method
+func (m *MockRepository) GetNamedDestination(ctx context.Context, name string) (*entities.NamedDestination, error) {
+	a := m.Called(name)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.NamedDestination), a.Error(1)
+}
+
+// GetNamedDestinations is a mocking a method
+func (m *MockRepository) GetNamedDestinations(ctx context.Context) ([]*entities.NamedDestination, error) {
+	a := m.Called()
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.NamedDestination), a.Error(1)
+}
+
+// GetPaymentVelocity is a mocking a method
+func (m *MockRepository) GetPaymentVelocity(ctx context.Context, filter db.PaymentVelocityFilter) (int64, int64, error) {
+	a := m.Called(filter)
+	return a.Get(0).(int64), a.Get(1).(int64), a.Error(2)
+}
+
+// DeletePaymentVelocityBefore is a mocking a method
+func (m *MockRepository) DeletePaymentVelocityBefore(ctx context.Context, before time.Time) (int64, error) {
+	a := m.Called(before)
+	return a.Get(0).(int64), a.Error(1)
+}
+
+// GetLastComplianceAuditEntry is a mocking a method
+func (m *MockRepository) GetLastComplianceAuditEntry(ctx context.Context) (*entities.ComplianceAuditEntry, error) {
+	a := m.Called()
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.ComplianceAuditEntry), a.Error(1)
+}
+
+// GetComplianceAuditEntries is a mocking a method
+func (m *MockRepository) GetComplianceAuditEntries(ctx context.Context, filter db.ComplianceAuditFilter, limit int) ([]*entities.ComplianceAuditEntry, error) {
+	a := m.Called(filter, limit)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.ComplianceAuditEntry), a.Error(1)
+}
+
+// GetAttachmentByMemoHash is a mocking a method
+func (m *MockRepository) GetAttachmentByMemoHash(ctx context.Context, memoHash string) (*entities.Attachment, error) {
+	a := m.Called(memoHash)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.Attachment), a.Error(1)
+}
+
+// GetExpectedPayment is a mocking a method
+func (m *MockRepository) GetExpectedPayment(ctx context.Context, expectedID string) (*entities.ExpectedPayment, error) {
+	a := m.Called(expectedID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.ExpectedPayment), a.Error(1)
+}
+
+// GetOpenExpectedPayment is a mocking a method
+func (m *MockRepository) GetOpenExpectedPayment(ctx context.Context, assetCode, assetIssuer, memoType, memo string) (*entities.ExpectedPayment, error) {
+	a := m.Called(assetCode, assetIssuer, memoType, memo)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.ExpectedPayment), a.Error(1)
+}
+
+// GetPaymentMatch is a mocking a method
+func (m *MockRepository) GetPaymentMatch(ctx context.Context, operationID string) (*entities.PaymentMatch, error) {
+	a := m.Called(operationID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.PaymentMatch), a.Error(1)
+}
+
+// GetPaymentMatches is a mocking a method
+func (m *MockRepository) GetPaymentMatches(ctx context.Context, expectedID string) ([]*entities.PaymentMatch, error) {
+	a := m.Called(expectedID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.PaymentMatch), a.Error(1)
+}
+
+// GetUnmatchedPayments is a mocking a method
+func (m *MockRepository) GetUnmatchedPayments(ctx context.Context, limit int) ([]*entities.PaymentMatch, error) {
+	a := m.Called(limit)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.PaymentMatch), a.Error(1)
+}
+
+// GetRefund is a mocking a method
+func (m *MockRepository) GetRefund(ctx context.Context, operationID string) (*entities.Refund, error) {
+	a := m.Called(operationID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.Refund), a.Error(1)
+}
+
+// GetFailedCallback is a mocking a method
+func (m *MockRepository) GetFailedCallback(ctx context.Context, operationID string) (*entities.FailedCallback, error) {
+	a := m.Called(operationID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.FailedCallback), a.Error(1)
+}
+
+// GetFailedCallbacks is a mocking a method
+func (m *MockRepository) GetFailedCallbacks(ctx context.Context, status string, limit int) ([]*entities.FailedCallback, error) {
+	a := m.Called(status, limit)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.FailedCallback), a.Error(1)
+}
+
+// GetOfflineTransaction is a mocking a method
+func (m *MockRepository) GetOfflineTransaction(ctx context.Context, offlineID string) (*entities.OfflineTransaction, error) {
+	a := m.Called(offlineID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.OfflineTransaction), a.Error(1)
+}
+
+// GetQuote is a mocking a method
+func (m *MockRepository) GetQuote(ctx context.Context, quoteID string) (*entities.Quote, error) {
+	a := m.Called(quoteID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.Quote), a.Error(1)
+}
+
+// GetFiatValue is a mocking a method
+func (m *MockRepository) GetFiatValue(ctx context.Context, direction, reference string) (*entities.FiatValue, error) {
+	a := m.Called(direction, reference)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.FiatValue), a.Error(1)
+}
+
+// GetWebhook is a mocking a method
+func (m *MockRepository) GetWebhook(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+	a := m.Called(webhookID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.Webhook), a.Error(1)
+}
+
+// GetWebhooks is a mocking a method
+func (m *MockRepository) GetWebhooks(ctx context.Context) ([]*entities.Webhook, error) {
+	a := m.Called()
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).([]*entities.Webhook), a.Error(1)
+}
+
+// GetMemoReference is a mocking a method
+func (m *MockRepository) GetMemoReference(ctx context.Context, memo string) (*entities.MemoReference, error) {
+	a := m.Called(memo)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.MemoReference), a.Error(1)
+}
+
+// AcquireLeaderLease is a mocking a method
+func (m *MockRepository) AcquireLeaderLease(ctx context.Context, name, holder string, now, expiresAt time.Time) (*entities.LeaderLease, error) {
+	a := m.Called(name, holder, now, expiresAt)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.LeaderLease), a.Error(1)
+}
+
+// ReleaseLeaderLease is a mocking a method
+func (m *MockRepository) ReleaseLeaderLease(ctx context.Context, name, holder string, now time.Time) error {
+	a := m.Called(name, holder, now)
+	return a.Error(0)
+}
+
+// GetSEP24Transaction is a mocking a method
+func (m *MockRepository) GetSEP24Transaction(ctx context.Context, transactionID string) (*entities.SEP24Transaction, error) {
+	a := m.Called(transactionID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.SEP24Transaction), a.Error(1)
+}
+
+// GetSEP24TransactionByStellarID is a mocking a method
+func (m *MockRepository) GetSEP24TransactionByStellarID(ctx context.Context, stellarTransactionID string) (*entities.SEP24Transaction, error) {
+	a := m.Called(stellarTransactionID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.SEP24Transaction), a.Error(1)
+}
+
+// GetSEP24TransactionByExternalID is a mocking a method
+func (m *MockRepository) GetSEP24TransactionByExternalID(ctx context.Context, externalTransactionID string) (*entities.SEP24Transaction, error) {
+	a := m.Called(externalTransactionID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.SEP24Transaction), a.Error(1)
+}
+
+// GetSEP24Transactions is a mocking a method
+func (m *MockRepository) GetSEP24Transactions(ctx context.Context, filter db.SEP24Filter, limit int) ([]*entities.SEP24Transaction, error) {
+	a := m.Called(filter, limit)
+	return a.Get(0).([]*entities.SEP24Transaction), a.Error(1)
+}
+
+// GetPendingSEP24Withdrawal is a mocking a method
+func (m *MockRepository) GetPendingSEP24Withdrawal(ctx context.Context, anchorAccount, assetCode, assetIssuer, memoType, memo string) (*entities.SEP24Transaction, error) {
+	a := m.Called(anchorAccount, assetCode, assetIssuer, memoType, memo)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*entities.SEP24Transaction), a.Error(1)
+}
+
 var _ db.RepositoryInterface = &MockRepository{}
 
 // MockSignerVerifier ...
@@ -235,13 +685,19 @@ type MockTransactionSubmitter struct {
 }
 
 // SubmitTransaction is a mocking a method
-func (ts *MockTransactionSubmitter) SubmitTransaction(paymentID *string, seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error) {
+func (ts *MockTransactionSubmitter) SubmitTransaction(ctx context.Context, paymentID *string, seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error) {
 	a := ts.Called(paymentID, seed, operation, memo)
 	return a.Get(0).(horizon.SubmitTransactionResponse), a.Error(1)
 }
 
+// BuildTransaction is a mocking a method
+func (ts *MockTransactionSubmitter) BuildTransaction(ctx context.Context, seed string, operation, memo interface{}) (tx *xdr.Transaction, err error) {
+	a := ts.Called(seed, operation, memo)
+	return a.Get(0).(*xdr.Transaction), a.Error(1)
+}
+
 // SignAndSubmitRawTransaction is a mocking a method
-func (ts *MockTransactionSubmitter) SignAndSubmitRawTransaction(paymentID *string, seed string, tx *xdr.Transaction) (response horizon.SubmitTransactionResponse, err error) {
+func (ts *MockTransactionSubmitter) SignAndSubmitRawTransaction(ctx context.Context, paymentID *string, seed string, tx *xdr.Transaction) (response horizon.SubmitTransactionResponse, err error) {
 	a := ts.Called(paymentID, seed, tx)
 	return a.Get(0).(horizon.SubmitTransactionResponse), a.Error(1)
 }
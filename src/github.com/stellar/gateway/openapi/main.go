@@ -0,0 +1,237 @@
+// Package openapi generates OpenAPI 3 specs of the bridge server API from
+// request and response types of its endpoints. Schemas are built using
+// reflection: `name` tags of form requests (also accepted as JSON objects, see
+// server.JSONFormMiddleware) and `json` tags of JSON requests and responses.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// Version is the version of OpenAPI specification of generated documents
+const Version = "3.0.3"
+
+// Route describes an endpoint. Path params are written in braces, ex.
+// `/payments/{id}`.
+type Route struct {
+	Method  string
+	Path    string
+	Summary string
+	// Form is a request with `name` tags (ex. bridge.PaymentRequest) sent as
+	// form or JSON body
+	Form interface{}
+	// Body is a request decoded from JSON body (ex. bridge.BuilderRequest)
+	Body  interface{}
+	Query []Parameter
+	// Responses are successful responses by HTTP status. Every route
+	// returns protocols.ErrorResponse on errors.
+	Responses map[int][]interface{}
+}
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem contains operations of a path by lower-case method
+type PathItem map[string]*Operation
+
+// Operation is a single endpoint
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path or query param. Schema is a string when nil.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is a request body by media type
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response by media type
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType contains the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components contains schemas referenced by other schemas
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a JSON schema of a value
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Generate generates a document of routes. When envelope is true responses
+// are described as server.Envelope objects.
+func Generate(info Info, routes []Route, envelope bool) *Document {
+	g := &generator{schemas: map[string]*Schema{}, types: map[string]string{}}
+	document := &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      map[string]*PathItem{},
+		Components: Components{Schemas: g.schemas},
+	}
+
+	var errorSchema *Schema
+	if envelope {
+		errorSchema = envelopeSchema(nil, g.schema(server.EnvelopeError{}))
+	} else {
+		errorSchema = g.schema(protocols.ErrorResponse{})
+	}
+
+	for _, route := range routes {
+		operation := &Operation{
+			OperationID: operationID(route.Method, route.Path),
+			Summary:     route.Summary,
+			Responses: map[string]*Response{
+				"default": {Description: "Error", Content: jsonContent(errorSchema)},
+			},
+		}
+
+		for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+			operation.Parameters = append(operation.Parameters, Parameter{Name: match[1], In: "path", Required: true})
+		}
+		for _, param := range route.Query {
+			param.In = "query"
+			operation.Parameters = append(operation.Parameters, param)
+		}
+		for i := range operation.Parameters {
+			if operation.Parameters[i].Schema == nil {
+				operation.Parameters[i].Schema = &Schema{Type: "string"}
+			}
+		}
+
+		switch {
+		case route.Form != nil:
+			schema := formSchema(route.Form)
+			operation.RequestBody = &RequestBody{
+				Required: len(schema.Required) > 0,
+				Content: map[string]MediaType{
+					"application/x-www-form-urlencoded": {Schema: schema},
+					"application/json":                  {Schema: schema},
+				},
+			}
+		case route.Body != nil:
+			operation.RequestBody = &RequestBody{Required: true, Content: jsonContent(g.schema(route.Body))}
+		}
+
+		for status, responses := range route.Responses {
+			var schemas []*Schema
+			for _, response := range responses {
+				schemas = append(schemas, g.schema(response))
+			}
+			schema := schemas[0]
+			if len(schemas) > 1 {
+				schema = &Schema{OneOf: schemas}
+			}
+			if envelope {
+				schema = envelopeSchema(schema, nil)
+			}
+			operation.Responses[strconv.Itoa(status)] = &Response{
+				Description: http.StatusText(status),
+				Content:     jsonContent(schema),
+			}
+		}
+
+		item, ok := document.Paths[route.Path]
+		if !ok {
+			item = &PathItem{}
+			document.Paths[route.Path] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = operation
+	}
+
+	return document
+}
+
+// Handler serves a document of routes. Responses are described as envelopes
+// when responses of the request are wrapped (see server.EnvelopeMiddleware).
+func Handler(info Info, routes []Route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		document, err := json.MarshalIndent(Generate(info, routes, server.Enveloped(w)), "", "  ")
+		if err != nil {
+			server.Write(w, protocols.NewInternalServerError("Error encoding OpenAPI document", map[string]interface{}{"err": err}))
+			return
+		}
+		// The document is not a response of the API, so it's never wrapped
+		w.Write(document)
+	}
+}
+
+// envelopeSchema returns a schema of server.Envelope with result or error
+func envelopeSchema(result, err *Schema) *Schema {
+	if result == nil {
+		result = &Schema{Nullable: true}
+	}
+	if err == nil {
+		err = &Schema{Nullable: true}
+	}
+	return &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"result": result, "error": err},
+		Required:   []string{"error", "result"},
+	}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// operationID returns an ID of the operation, ex. `deletePaymentsId` for
+// `DELETE /payments/{id}`
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	words := strings.FieldsFunc(path, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	for _, word := range words {
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return id
+}
@@ -0,0 +1,131 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Amount string            `name:"amount" required:""`
+	DryRun bool              `name:"dry_run"`
+	Path   []protocols.Asset `name:"path"`
+	Note   string
+
+	protocols.FormRequest
+}
+
+type testEmbedded struct {
+	Hash string `json:"hash"`
+}
+
+type testResponse struct {
+	*testEmbedded
+	ID       string          `json:"id"`
+	Ledger   *uint64         `json:"ledger"`
+	SettleAt time.Time       `json:"settle_at"`
+	Extras   *testResponse   `json:"extras,omitempty"`
+	Raw      json.RawMessage `json:"raw"`
+	Internal string          `json:"-"`
+}
+
+var testRoutes = []Route{
+	{
+		Method:    "POST",
+		Path:      "/payment",
+		Form:      testRequest{},
+		Responses: map[int][]interface{}{200: {testResponse{}}, 202: {testResponse{}, protocols.Asset{}}},
+	},
+	{
+		Method:    "DELETE",
+		Path:      "/payments/{id}",
+		Responses: map[int][]interface{}{200: {&testResponse{}}},
+	},
+}
+
+func TestGenerate(t *testing.T) {
+	document := Generate(Info{Title: "Test", Version: "1"}, testRoutes, false)
+	assert.Equal(t, Version, document.OpenAPI)
+
+	payment := (*document.Paths["/payment"])["post"]
+	require.NotNil(t, payment)
+	assert.Equal(t, "postPayment", payment.OperationID)
+
+	request := payment.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, payment.RequestBody.Content["application/x-www-form-urlencoded"].Schema, request)
+	assert.True(t, payment.RequestBody.Required)
+	assert.Equal(t, []string{"amount"}, request.Required)
+	assert.Equal(t, &Schema{Type: "boolean"}, request.Properties["dry_run"])
+	assert.Equal(t, &Schema{Type: "string"}, request.Properties["path"].Items.Properties["asset_code"])
+	assert.Len(t, request.Properties, 3)
+
+	assert.Equal(t, "#/components/schemas/testResponse", payment.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Len(t, payment.Responses["202"].Content["application/json"].Schema.OneOf, 2)
+	assert.Equal(t, "#/components/schemas/ErrorResponse", payment.Responses["default"].Content["application/json"].Schema.Ref)
+
+	response := document.Components.Schemas["testResponse"]
+	require.NotNil(t, response)
+	assert.Equal(t, []string{"extras", "hash", "id", "ledger", "raw", "settle_at"}, keys(response.Properties))
+	assert.Equal(t, &Schema{Type: "integer", Nullable: true}, response.Properties["ledger"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, response.Properties["settle_at"])
+	assert.Equal(t, "#/components/schemas/testResponse", response.Properties["extras"].Ref)
+
+	cancel := (*document.Paths["/payments/{id}"])["delete"]
+	require.NotNil(t, cancel)
+	assert.Equal(t, "deletePaymentsId", cancel.OperationID)
+	assert.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, cancel.Parameters)
+	assert.Nil(t, cancel.RequestBody)
+}
+
+func TestGenerateEnvelope(t *testing.T) {
+	document := Generate(Info{Title: "Test", Version: "1"}, testRoutes, true)
+	payment := (*document.Paths["/payment"])["post"]
+
+	result := payment.Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/testResponse", result.Properties["result"].Ref)
+	assert.True(t, result.Properties["error"].Nullable)
+
+	errorSchema := payment.Responses["default"].Content["application/json"].Schema
+	assert.True(t, errorSchema.Properties["result"].Nullable)
+	assert.Equal(t, "#/components/schemas/EnvelopeError", errorSchema.Properties["error"].Ref)
+	assert.Contains(t, document.Components.Schemas["EnvelopeError"].Properties, "extras")
+}
+
+func TestHandler(t *testing.T) {
+	handler := server.EnvelopeMiddleware(func(r *http.Request) bool {
+		return r.Header.Get("X-Envelope") != ""
+	})(Handler(Info{Title: "Test", Version: "1"}, testRoutes))
+
+	for _, envelope := range []bool{false, true} {
+		request := httptest.NewRequest("GET", "/openapi.json", nil)
+		if envelope {
+			request.Header.Set("X-Envelope", "true")
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var document Document
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &document))
+		assert.Equal(t, Version, document.OpenAPI, "document is not wrapped")
+		_, found := document.Components.Schemas["EnvelopeError"]
+		assert.Equal(t, envelope, found)
+	}
+}
+
+func keys(properties map[string]*Schema) []string {
+	var keys []string
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/url"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	rawType    = reflect.TypeOf(json.RawMessage{})
+	valuesType = reflect.TypeOf(url.Values{})
+	// textType is implemented by types encoded as JSON strings
+	textType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// generator keeps named schemas of JSON types in components
+type generator struct {
+	schemas map[string]*Schema
+	// types maps names of schemas to Go types, so types with the same name
+	// in different packages get different schemas
+	types map[string]string
+}
+
+// schema returns a reference to the schema of value type in components
+func (g *generator) schema(value interface{}) *Schema {
+	return g.typeSchema(reflect.TypeOf(value))
+}
+
+func (g *generator) typeSchema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t == timeType {
+		return primitiveSchema(t, g.typeSchema)
+	}
+	if t.Name() == "" {
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		g.addJSONProperties(schema, t)
+		return schema
+	}
+
+	name := t.Name()
+	if goType, ok := g.types[name]; ok && goType != t.PkgPath() {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, ok := g.schemas[name]; ok {
+		return ref
+	}
+
+	// Added before properties, so recursive types end
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.schemas[name] = schema
+	g.types[name] = t.PkgPath()
+	g.addJSONProperties(schema, t)
+	return ref
+}
+
+// addJSONProperties adds fields of struct t to schema like encoding/json does
+func (g *generator) addJSONProperties(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			g.addJSONProperties(schema, fieldType)
+			continue
+		}
+		if field.PkgPath != "" {
+			// Unexported
+			continue
+		}
+		if fieldType.Kind() == reflect.Interface && fieldType.NumMethod() > 0 {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = g.typeSchema(field.Type)
+		if field.Type.Kind() == reflect.Ptr && !strings.Contains(tag, "omitempty") {
+			schema.Properties[name] = nullable(schema.Properties[name])
+		}
+	}
+}
+
+// formSchema returns a schema of a form request: fields with `name` tags.
+// Fields with `required` tag are required. Nested objects are sent as
+// `path[0][asset_code]` fields in forms.
+func formSchema(value interface{}) *Schema {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("name")
+		if name == "" {
+			continue
+		}
+
+		schema.Properties[name] = formProperty(field.Type)
+		if _, required := field.Tag.Lookup("required"); required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// formProperty returns a schema of a field of a form request
+func formProperty(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t != timeType {
+		return formSchema(reflect.New(t).Interface())
+	}
+	return primitiveSchema(t, formProperty)
+}
+
+// primitiveSchema returns a schema of a type other than a named struct.
+// Elements of arrays and maps are described using element.
+func primitiveSchema(t reflect.Type, element func(t reflect.Type) *Schema) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawType:
+		return &Schema{}
+	case t == valuesType:
+		return &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}
+	case t.Implements(textType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: element(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: element(t.Elem())}
+	case reflect.Struct:
+		return &Schema{Type: "object"}
+	default:
+		// interface{}
+		return &Schema{}
+	}
+}
+
+// nullable marks schema as nullable. References can't have other properties,
+// so they're wrapped.
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{Nullable: true, OneOf: []*Schema{schema}}
+	}
+	schema.Nullable = true
+	return schema
+}
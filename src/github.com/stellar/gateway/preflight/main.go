@@ -0,0 +1,279 @@
+// Package preflight predicts failures of transactions before they are signed
+// and submitted. Operations are applied in order to the state of accounts
+// loaded from Horizon (balances, trustlines and subentries), so a transaction
+// that would fail in stellar-core is rejected before it consumes a sequence
+// number and a fee.
+//
+// Only create_account, payment and path_payment operations are simulated.
+// Other operations are assumed to succeed and do not change the simulated
+// state. The amount sent in a path payment depends on the order books, so only
+// trustlines of the send asset are checked.
+package preflight
+
+import (
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/protocols/bridge"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// DefaultBaseReserve is the base reserve of the public network in stroops
+const DefaultBaseReserve = amounts.One / 2
+
+// TransactionLevel is Failure.Operation of failures of the whole transaction
+const TransactionLevel = -1
+
+// AccountLoader loads current state of accounts, implemented by
+// horizon.Horizon
+type AccountLoader interface {
+	LoadAccountFresh(accountID string) (horizon.AccountResponse, error)
+}
+
+// Failure is a predicted failure of a transaction
+type Failure struct {
+	// Operation is the index of the failed operation or TransactionLevel
+	Operation int
+	// Error is the error response the bridge server would return when the
+	// transaction failed
+	Error *protocols.ErrorResponse
+}
+
+// Checker predicts failures of transactions
+type Checker struct {
+	Accounts AccountLoader
+	// BaseReserve in stroops used to compute min balances of accounts
+	BaseReserve int64
+}
+
+// NewChecker creates a new Checker. DefaultBaseReserve is used when
+// baseReserve is 0.
+func NewChecker(accounts AccountLoader, baseReserve int64) *Checker {
+	if baseReserve == 0 {
+		baseReserve = DefaultBaseReserve
+	}
+	return &Checker{Accounts: accounts, BaseReserve: baseReserve}
+}
+
+// Check applies operations of tx and returns predicted failures, nil when
+// the transaction is expected to succeed. All operations are checked, so
+// failures of every operation are returned. Returns an error when accounts
+// cannot be loaded because Horizon is unavailable.
+func (c *Checker) Check(tx *xdr.Transaction) ([]Failure, error) {
+	s := &state{checker: c, accounts: map[string]*account{}}
+
+	source, err := s.account(tx.SourceAccount.Address())
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return []Failure{{Operation: TransactionLevel, Error: bridge.TransactionNoAccount}}, nil
+	}
+
+	native := source.balance(asset{})
+	if native.amount-int64(tx.Fee) < c.minBalance(source.subentries) {
+		return []Failure{{Operation: TransactionLevel, Error: bridge.TransactionInsufficientBalance}}, nil
+	}
+	native.amount -= int64(tx.Fee)
+
+	var failures []Failure
+	for i, op := range tx.Operations {
+		sourceID := tx.SourceAccount.Address()
+		if op.SourceAccount != nil {
+			sourceID = op.SourceAccount.Address()
+		}
+
+		errorResponse, err := s.apply(sourceID, op.Body)
+		if err != nil {
+			return nil, err
+		}
+		if errorResponse != nil {
+			failures = append(failures, Failure{Operation: i, Error: errorResponse})
+		}
+	}
+	return failures, nil
+}
+
+func (c *Checker) minBalance(subentries int64) int64 {
+	return (2 + subentries) * c.BaseReserve
+}
+
+type asset struct {
+	code   string
+	issuer string
+}
+
+func assetOf(a xdr.Asset) (result asset) {
+	a.Extract(new(string), &result.code, &result.issuer)
+	return
+}
+
+type balance struct {
+	amount int64
+	// limit is 0 for native balances
+	limit      int64
+	authorized bool
+}
+
+type account struct {
+	id         string
+	subentries int64
+	balances   map[asset]*balance
+}
+
+// balance returns the trustline of a given asset or nil when the account
+// does not trust it. Native balance always exists.
+func (a *account) balance(of asset) *balance {
+	return a.balances[of]
+}
+
+type state struct {
+	checker  *Checker
+	accounts map[string]*account
+}
+
+// account returns simulated state of an account, nil when it does not exist
+func (s *state) account(id string) (*account, error) {
+	if result, ok := s.accounts[id]; ok {
+		return result, nil
+	}
+
+	response, err := s.checker.Accounts.LoadAccountFresh(id)
+	if errors.Cause(err) == horizon.ErrUnavailable {
+		return nil, err
+	} else if err != nil {
+		s.accounts[id] = nil
+		return nil, nil
+	}
+
+	result := &account{id: id, subentries: response.SubentryCount, balances: map[asset]*balance{}}
+	for _, b := range response.Balances {
+		var key asset
+		if b.AssetType != "native" {
+			key = asset{code: b.AssetCode, issuer: b.AssetIssuer}
+		}
+		amount, err := amounts.Parse(b.Balance)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid balance of "+id)
+		}
+		value := &balance{amount: amount, authorized: b.Authorized()}
+		if b.Limit != "" {
+			value.limit, err = amounts.Parse(b.Limit)
+			if err != nil {
+				return nil, errors.Wrap(err, "Invalid trustline limit of "+id)
+			}
+		}
+		result.balances[key] = value
+	}
+	if result.balances[asset{}] == nil {
+		result.balances[asset{}] = &balance{authorized: true}
+	}
+
+	s.accounts[id] = result
+	return result, nil
+}
+
+// apply applies an operation of sourceID and returns the error response of
+// a predicted failure. State is not changed by failed operations.
+func (s *state) apply(sourceID string, body xdr.OperationBody) (*protocols.ErrorResponse, error) {
+	source, err := s.account(sourceID)
+	if err != nil || source == nil {
+		return bridge.TransactionNoAccount, err
+	}
+
+	switch body.Type {
+	case xdr.OperationTypeCreateAccount:
+		op := body.CreateAccountOp
+		return s.createAccount(source, op.Destination.Address(), int64(op.StartingBalance))
+	case xdr.OperationTypePayment:
+		op := body.PaymentOp
+		destination, err := s.account(op.Destination.Address())
+		if err != nil || destination == nil {
+			return bridge.PaymentNoDestination, err
+		}
+		return s.payment(source, destination, assetOf(op.Asset), int64(op.Amount), assetOf(op.Asset), int64(op.Amount))
+	case xdr.OperationTypePathPayment:
+		op := body.PathPaymentOp
+		destination, err := s.account(op.Destination.Address())
+		if err != nil || destination == nil {
+			return bridge.PaymentNoDestination, err
+		}
+		// Amount sent is not known, it is not debited
+		return s.payment(source, destination, assetOf(op.SendAsset), 0, assetOf(op.DestAsset), int64(op.DestAmount))
+	}
+	return nil, nil
+}
+
+func (s *state) createAccount(source *account, destinationID string, startingBalance int64) (*protocols.ErrorResponse, error) {
+	destination, err := s.account(destinationID)
+	if err != nil {
+		return nil, err
+	}
+	if destination != nil {
+		return bridge.PaymentAlreadyExists, nil
+	}
+
+	if startingBalance < s.checker.minBalance(0) {
+		return bridge.PaymentLowReserve, nil
+	}
+
+	native := source.balance(asset{})
+	if native.amount-startingBalance < s.checker.minBalance(source.subentries) {
+		return bridge.PaymentUnderfunded, nil
+	}
+
+	native.amount -= startingBalance
+	s.accounts[destinationID] = &account{
+		id:       destinationID,
+		balances: map[asset]*balance{{}: {amount: startingBalance, authorized: true}},
+	}
+	return nil, nil
+}
+
+// payment sends sendAmount of sendAsset and receives receiveAmount of
+// receiveAsset. Trustlines of issuers are not needed.
+func (s *state) payment(source, destination *account, sendAsset asset, sendAmount int64, receiveAsset asset, receiveAmount int64) (*protocols.ErrorResponse, error) {
+	var received *balance
+	if receiveAsset.code != "" && receiveAsset.issuer != destination.id {
+		received = destination.balance(receiveAsset)
+		if received == nil {
+			return bridge.PaymentNoTrust, nil
+		}
+		if !received.authorized {
+			return bridge.PaymentNotAuthorized, nil
+		}
+		if received.amount+receiveAmount > received.limit {
+			return bridge.PaymentLineFull, nil
+		}
+	} else if receiveAsset.code == "" {
+		received = destination.balance(asset{})
+	}
+
+	var sent *balance
+	if sendAsset.code == "" {
+		sent = source.balance(asset{})
+		if sent.amount-sendAmount < s.checker.minBalance(source.subentries) {
+			return bridge.PaymentUnderfunded, nil
+		}
+	} else if sendAsset.issuer != source.id {
+		sent = source.balance(sendAsset)
+		if sent == nil {
+			return bridge.PaymentSrcNoTrust, nil
+		}
+		if !sent.authorized {
+			return bridge.PaymentSrcNotAuthorized, nil
+		}
+		if sent.amount < sendAmount {
+			return bridge.PaymentUnderfunded, nil
+		}
+	}
+
+	if sent != nil {
+		sent.amount -= sendAmount
+	}
+	if received != nil {
+		received.amount += receiveAmount
+	}
+	return nil, nil
+}
@@ -0,0 +1,210 @@
+package preflight
+
+import (
+	"testing"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/bridge"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	source      = "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"
+	destination = "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"
+	issuer      = "GC7DVHGMSQYAPYXQU652VVHEMZ2OZN4VH44T67QILDHDMBOACMZHQWLW"
+	newAccount  = "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"
+)
+
+type accounts map[string]horizon.AccountResponse
+
+func (a accounts) LoadAccountFresh(accountID string) (horizon.AccountResponse, error) {
+	if account, ok := a[accountID]; ok {
+		return account, nil
+	}
+	return horizon.AccountResponse{}, errors.New("Not found")
+}
+
+type unavailable struct{}
+
+func (unavailable) LoadAccountFresh(accountID string) (horizon.AccountResponse, error) {
+	return horizon.AccountResponse{}, errors.Wrap(horizon.ErrUnavailable, "Error loading account")
+}
+
+func newTransaction(t *testing.T, operations ...b.TransactionMutator) *xdr.Transaction {
+	mutators := append([]b.TransactionMutator{
+		b.SourceAccount{source},
+		b.Sequence{1},
+		b.TestNetwork,
+	}, operations...)
+	tx, err := b.Transaction(mutators...)
+	require.NoError(t, err)
+	return tx.TX
+}
+
+func errorsOf(failures []Failure) (result []string) {
+	for _, failure := range failures {
+		result = append(result, failure.Error.Code)
+	}
+	return
+}
+
+func TestCheck(t *testing.T) {
+	authorized, notAuthorized := true, false
+	state := accounts{
+		source: {
+			AccountID:     source,
+			SubentryCount: 1,
+			Balances: []horizon.AccountBalance{
+				{AssetType: "native", Balance: "101.5001"},
+				{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: issuer, Balance: "50", Limit: "1000", IsAuthorized: &authorized},
+				{AssetType: "credit_alphanum4", AssetCode: "EUR", AssetIssuer: issuer, Balance: "50", Limit: "1000", IsAuthorized: &notAuthorized},
+			},
+		},
+		destination: {
+			AccountID: destination,
+			Balances: []horizon.AccountBalance{
+				{AssetType: "native", Balance: "10"},
+				{AssetType: "credit_alphanum4", AssetCode: "USD", AssetIssuer: issuer, Balance: "990", Limit: "1000"},
+			},
+		},
+		issuer: {
+			AccountID: issuer,
+			Balances:  []horizon.AccountBalance{{AssetType: "native", Balance: "10"}},
+		},
+	}
+	checker := NewChecker(state, 0)
+
+	usd := func(amount string) b.CreditAmount { return b.CreditAmount{"USD", issuer, amount} }
+
+	tests := []struct {
+		name       string
+		operations []b.TransactionMutator
+		expected   []string
+	}{
+		{
+			"native payment above min balance",
+			[]b.TransactionMutator{b.Payment(b.Destination{destination}, b.NativeAmount{"100"})},
+			nil,
+		},
+		{
+			// 1.5 XLM reserve of 1 subentry and 0.0001 fee
+			"native payment below min balance",
+			[]b.TransactionMutator{b.Payment(b.Destination{destination}, b.NativeAmount{"100.0001"})},
+			[]string{"payment_underfunded"},
+		},
+		{
+			"payments are applied in order",
+			[]b.TransactionMutator{
+				b.Payment(b.Destination{destination}, b.NativeAmount{"60"}),
+				b.Payment(b.Destination{destination}, b.NativeAmount{"60"}),
+			},
+			[]string{"payment_underfunded"},
+		},
+		{
+			"credit payment",
+			[]b.TransactionMutator{b.Payment(b.Destination{destination}, usd("10"))},
+			nil,
+		},
+		{
+			"destination trustline limit",
+			[]b.TransactionMutator{b.Payment(b.Destination{destination}, usd("10.0000001"))},
+			[]string{"payment_line_full"},
+		},
+		{
+			"source credit balance",
+			[]b.TransactionMutator{b.Payment(b.Destination{issuer}, usd("50.0000001"))},
+			[]string{"payment_underfunded"},
+		},
+		{
+			"issuer receives its asset",
+			[]b.TransactionMutator{b.Payment(b.Destination{issuer}, usd("10"))},
+			nil,
+		},
+		{
+			"destination does not trust asset",
+			[]b.TransactionMutator{b.Payment(b.Destination{destination}, b.CreditAmount{"EUR", issuer, "1"})},
+			[]string{"payment_no_trust"},
+		},
+		{
+			"source not authorized",
+			[]b.TransactionMutator{b.Payment(b.Destination{issuer}, b.CreditAmount{"EUR", issuer, "1"})},
+			[]string{"payment_src_not_authorized"},
+		},
+		{
+			"source does not trust asset",
+			[]b.TransactionMutator{b.Payment(b.Destination{destination}, b.CreditAmount{"GBP", destination, "1"})},
+			[]string{"payment_src_no_trust"},
+		},
+		{
+			"destination does not exist",
+			[]b.TransactionMutator{b.Payment(b.Destination{newAccount}, b.NativeAmount{"1"})},
+			[]string{"payment_no_destination"},
+		},
+		{
+			"created account receives payment",
+			[]b.TransactionMutator{
+				b.CreateAccount(b.Destination{newAccount}, b.NativeAmount{"1"}),
+				b.Payment(b.Destination{newAccount}, b.NativeAmount{"1"}),
+			},
+			nil,
+		},
+		{
+			"create account failures",
+			[]b.TransactionMutator{
+				b.CreateAccount(b.Destination{destination}, b.NativeAmount{"1"}),
+				b.CreateAccount(b.Destination{newAccount}, b.NativeAmount{"0.9999999"}),
+				b.CreateAccount(b.Destination{newAccount}, b.NativeAmount{"100.0001"}),
+			},
+			[]string{"payment_already_exists", "payment_low_reserve", "payment_underfunded"},
+		},
+		{
+			"path payment checks destination",
+			[]b.TransactionMutator{b.Payment(b.Destination{destination}, usd("11"), b.PayWith(b.NativeAsset(), "20"))},
+			[]string{"payment_line_full"},
+		},
+		{
+			"path payment checks send asset trustline",
+			[]b.TransactionMutator{b.Payment(b.Destination{destination}, usd("1"), b.PayWith(b.CreditAsset("EUR", issuer), "20"))},
+			[]string{"payment_src_not_authorized"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			failures, err := checker.Check(newTransaction(t, test.operations...))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, errorsOf(failures))
+		})
+	}
+
+	t.Run("failures contain operation index", func(t *testing.T) {
+		failures, err := checker.Check(newTransaction(t,
+			b.Payment(b.Destination{destination}, usd("1")),
+			b.Payment(b.Destination{newAccount}, usd("1")),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, []Failure{{Operation: 1, Error: bridge.PaymentNoDestination}}, failures)
+	})
+
+	t.Run("fee below min balance", func(t *testing.T) {
+		poor := accounts{source: {AccountID: source, Balances: []horizon.AccountBalance{{AssetType: "native", Balance: "1"}}}}
+		failures, err := NewChecker(poor, 0).Check(newTransaction(t, b.Payment(b.Destination{destination}, b.NativeAmount{"1"})))
+		require.NoError(t, err)
+		assert.Equal(t, []Failure{{Operation: TransactionLevel, Error: bridge.TransactionInsufficientBalance}}, failures)
+	})
+
+	t.Run("source account does not exist", func(t *testing.T) {
+		failures, err := NewChecker(accounts{}, 0).Check(newTransaction(t, b.Payment(b.Destination{destination}, b.NativeAmount{"1"})))
+		require.NoError(t, err)
+		assert.Equal(t, []Failure{{Operation: TransactionLevel, Error: bridge.TransactionNoAccount}}, failures)
+	})
+
+	t.Run("horizon unavailable", func(t *testing.T) {
+		_, err := NewChecker(unavailable{}, 0).Check(newTransaction(t, b.Payment(b.Destination{destination}, b.NativeAmount{"1"})))
+		assert.Equal(t, horizon.ErrUnavailable, errors.Cause(err))
+	})
+}
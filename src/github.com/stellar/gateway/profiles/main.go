@@ -0,0 +1,254 @@
+// Package profiles contains destination profiles of exchanges and anchors:
+// the memo type their deposits require, accepted assets and minimum amounts.
+// Payments to a destination matching a profile are checked against it, so
+// withdrawals that would be lost or returned by the exchange are rejected
+// before they are sent.
+//
+// Built-in profiles are maintained as JSON files in data directory. They can
+// be overridden by files in a local directory, see Load.
+package profiles
+
+//go:generate go-bindata -ignore .+\.go$ -pkg profiles -o bindata.go -prefix data/ ./data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/go/support/errors"
+)
+
+// Profile describes requirements of deposits of an exchange or anchor
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Accounts are receiving accounts of the exchange
+	Accounts []string `json:"accounts,omitempty"`
+	// Domains are federation domains of the exchange, ex. "example.com"
+	// matches "user*example.com"
+	Domains []string `json:"domains,omitempty"`
+	// MemoType is the memo type deposits require: id, text or hash. Memo is
+	// not required when empty.
+	MemoType string `json:"memo_type,omitempty"`
+	// Assets are assets accepted by the exchange, all assets are accepted
+	// when empty
+	Assets []AcceptedAsset `json:"assets,omitempty"`
+	// Disabled removes a built-in profile with the same name when set in a
+	// local file
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// AcceptedAsset is an asset accepted by the exchange
+type AcceptedAsset struct {
+	Code string `json:"code"`
+	// Issuer is empty for XLM
+	Issuer string `json:"issuer,omitempty"`
+	// MinAmount is the min amount of a deposit, no minimum when empty
+	MinAmount string `json:"min_amount,omitempty"`
+}
+
+// Validate checks if the profile is correct
+func (p Profile) Validate() error {
+	if p.Name == "" {
+		return errors.New("Profile name is required")
+	}
+	if p.Disabled {
+		return nil
+	}
+
+	for _, account := range p.Accounts {
+		if !protocols.IsValidAccountID(account) {
+			return fmt.Errorf("Invalid account %s of %s profile", account, p.Name)
+		}
+	}
+
+	switch p.MemoType {
+	case "", "id", "text", "hash":
+	default:
+		return fmt.Errorf("Invalid memo_type %s of %s profile", p.MemoType, p.Name)
+	}
+
+	for _, asset := range p.Assets {
+		if asset.Code == "" {
+			return fmt.Errorf("Asset code is required in %s profile", p.Name)
+		}
+		if asset.Issuer != "" && !protocols.IsValidAccountID(asset.Issuer) {
+			return fmt.Errorf("Invalid asset issuer %s of %s profile", asset.Issuer, p.Name)
+		}
+		if asset.MinAmount != "" {
+			if _, err := amounts.Parse(asset.MinAmount); err != nil {
+				return fmt.Errorf("Invalid min_amount %s of %s profile", asset.MinAmount, p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns an error describing why a payment does not meet requirements
+// of the profile or nil when it does. Empty assetCode means XLM.
+func (p *Profile) Check(memoType, assetCode, assetIssuer, amount string) error {
+	if p.MemoType != "" && memoType != p.MemoType {
+		if memoType == "" {
+			return fmt.Errorf("%s requires %s memo", p.Name, p.MemoType)
+		}
+		return fmt.Errorf("%s requires %s memo, %s memo given", p.Name, p.MemoType, memoType)
+	}
+
+	if len(p.Assets) == 0 {
+		return nil
+	}
+
+	if assetCode == "" {
+		assetCode = "XLM"
+	}
+	for _, asset := range p.Assets {
+		if asset.Code != assetCode || asset.Issuer != assetIssuer {
+			continue
+		}
+		if asset.MinAmount == "" {
+			return nil
+		}
+		compared, err := amounts.Compare(amount, asset.MinAmount)
+		if err != nil {
+			return err
+		}
+		if compared < 0 {
+			return fmt.Errorf("%s requires at least %s %s", p.Name, asset.MinAmount, asset.Code)
+		}
+		return nil
+	}
+
+	if assetIssuer == "" {
+		return fmt.Errorf("%s does not accept %s", p.Name, assetCode)
+	}
+	return fmt.Errorf("%s does not accept %s issued by %s", p.Name, assetCode, assetIssuer)
+}
+
+// Parse parses and validates a profiles file: a JSON array of profiles
+func Parse(data []byte) ([]Profile, error) {
+	var profiles []Profile
+	err := json.Unmarshal(data, &profiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid profiles file")
+	}
+
+	for _, profile := range profiles {
+		if err = profile.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// Registry contains profiles by name and finds profiles of destinations
+type Registry struct {
+	profiles map[string]*Profile
+	accounts map[string]*Profile
+	domains  map[string]*Profile
+}
+
+// Load returns a Registry of built-in profiles and profiles from *.json
+// files in directory (not loaded when empty). Local profiles replace
+// built-in profiles with the same name; files are loaded in name order.
+func Load(directory string) (*Registry, error) {
+	var profiles []Profile
+	for _, name := range AssetNames() {
+		loaded, err := Parse(MustAsset(name))
+		if err != nil {
+			return nil, errors.Wrap(err, "Error loading built-in profiles "+name)
+		}
+		profiles = append(profiles, loaded...)
+	}
+
+	if directory != "" {
+		files, err := filepath.Glob(filepath.Join(directory, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+
+		for _, file := range files {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			loaded, err := Parse(data)
+			if err != nil {
+				return nil, errors.Wrap(err, "Error loading profiles "+file)
+			}
+			profiles = append(profiles, loaded...)
+		}
+	}
+
+	return NewRegistry(profiles), nil
+}
+
+// NewRegistry creates a Registry of profiles. Later profiles replace earlier
+// ones with the same name.
+func NewRegistry(profiles []Profile) *Registry {
+	r := &Registry{
+		profiles: map[string]*Profile{},
+		accounts: map[string]*Profile{},
+		domains:  map[string]*Profile{},
+	}
+
+	for i := range profiles {
+		if profiles[i].Disabled {
+			delete(r.profiles, profiles[i].Name)
+		} else {
+			r.profiles[profiles[i].Name] = &profiles[i]
+		}
+	}
+
+	for _, profile := range r.profiles {
+		for _, account := range profile.Accounts {
+			r.accounts[account] = profile
+		}
+		for _, domain := range profile.Domains {
+			r.domains[strings.ToLower(domain)] = profile
+		}
+	}
+	return r
+}
+
+// Get returns a profile by name or nil
+func (r *Registry) Get(name string) *Profile {
+	if r == nil {
+		return nil
+	}
+	return r.profiles[name]
+}
+
+// All returns all profiles sorted by name
+func (r *Registry) All() []*Profile {
+	if r == nil {
+		return []*Profile{}
+	}
+	profiles := make([]*Profile, 0, len(r.profiles))
+	for _, profile := range r.profiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
+
+// Match returns a profile of a destination account or a federation address
+// (`name*domain`, can be empty) or nil
+func (r *Registry) Match(accountID, address string) *Profile {
+	if r == nil {
+		return nil
+	}
+	if profile, ok := r.accounts[accountID]; ok {
+		return profile
+	}
+
+	if i := strings.LastIndex(address, "*"); i != -1 {
+		return r.domains[strings.ToLower(address[i+1:])]
+	}
+	return nil
+}
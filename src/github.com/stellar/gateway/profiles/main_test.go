@@ -0,0 +1,99 @@
+package profiles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	account = "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"
+	issuer  = "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5"
+)
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		profile Profile
+		err     string
+	}{
+		{Profile{Name: "ok", Accounts: []string{account}, MemoType: "id", Assets: []AcceptedAsset{{Code: "USD", Issuer: issuer, MinAmount: "10"}}}, ""},
+		{Profile{}, "Profile name is required"},
+		{Profile{Name: "disabled", Disabled: true, MemoType: "invalid"}, ""},
+		{Profile{Name: "a", Accounts: []string{"invalid"}}, "Invalid account invalid of a profile"},
+		{Profile{Name: "a", MemoType: "return"}, "Invalid memo_type return of a profile"},
+		{Profile{Name: "a", Assets: []AcceptedAsset{{Issuer: issuer}}}, "Asset code is required in a profile"},
+		{Profile{Name: "a", Assets: []AcceptedAsset{{Code: "USD", Issuer: "invalid"}}}, "Invalid asset issuer invalid of a profile"},
+		{Profile{Name: "a", Assets: []AcceptedAsset{{Code: "XLM", MinAmount: "abc"}}}, "Invalid min_amount abc of a profile"},
+	} {
+		err := test.profile.Validate()
+		if test.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, test.err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	profile := &Profile{
+		Name:     "exchange",
+		MemoType: "id",
+		Assets: []AcceptedAsset{
+			{Code: "XLM", MinAmount: "5"},
+			{Code: "USD", Issuer: issuer},
+		},
+	}
+
+	assert.NoError(t, profile.Check("id", "", "", "5"))
+	assert.NoError(t, profile.Check("id", "USD", issuer, "0.1"))
+	assert.EqualError(t, profile.Check("", "", "", "5"), "exchange requires id memo")
+	assert.EqualError(t, profile.Check("text", "", "", "5"), "exchange requires id memo, text memo given")
+	assert.EqualError(t, profile.Check("id", "", "", "4.9999999"), "exchange requires at least 5 XLM")
+	assert.EqualError(t, profile.Check("id", "EUR", issuer, "10"), "exchange does not accept EUR issued by "+issuer)
+	assert.EqualError(t, profile.Check("id", "USD", account, "10"), "exchange does not accept USD issued by "+account)
+
+	// No requirements
+	assert.NoError(t, (&Profile{Name: "any"}).Check("", "EUR", issuer, "1"))
+}
+
+func TestLoad(t *testing.T) {
+	registry, err := Load("")
+	require.NoError(t, err)
+	builtIn := registry.Get("example")
+	require.NotNil(t, builtIn)
+	assert.Equal(t, builtIn, registry.Match(account, "user*Example.com"))
+
+	directory, err := ioutil.TempDir("", "profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	write := func(name, data string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(directory, name), []byte(data), 0644))
+	}
+	write("a.json", `[{"name": "local", "accounts": ["`+account+`"], "memo_type": "text"}]`)
+	write("b.json", `[{"name": "example", "disabled": true}, {"name": "local", "accounts": ["`+account+`"], "memo_type": "id"}]`)
+	write("ignored.txt", `invalid`)
+
+	registry, err = Load(directory)
+	require.NoError(t, err)
+	assert.Nil(t, registry.Get("example"))
+	assert.Nil(t, registry.Match(issuer, "user*example.com"))
+	local := registry.Match(account, "")
+	require.NotNil(t, local)
+	assert.Equal(t, "id", local.MemoType)
+	assert.Len(t, registry.All(), 1)
+
+	write("c.json", `[{"name": "broken", "memo_type": "none"}]`)
+	_, err = Load(directory)
+	assert.Error(t, err)
+
+	// nil Registry is empty
+	var empty *Registry
+	assert.Nil(t, empty.Get("example"))
+	assert.Nil(t, empty.Match(account, "user*example.com"))
+	assert.Empty(t, empty.All())
+}
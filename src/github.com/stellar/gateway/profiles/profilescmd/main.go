@@ -0,0 +1,157 @@
+// Package profilescmd contains `profiles` command of bridge server used to
+// update and list destination profiles.
+package profilescmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/gateway/profiles"
+	"github.com/stellar/go/support/errors"
+)
+
+// DownloadedFile is the name of the file in profiles directory `update`
+// writes downloaded profiles to
+const DownloadedFile = "downloaded.json"
+
+// maxSize is the max size of a downloaded profiles file
+const maxSize = 10 * 1024 * 1024
+
+// NewCommand returns `profiles` command with `update` and `list`
+// subcommands. load returns profiles directory and the default URL of
+// profiles file from the config of a server.
+func NewCommand(load func() (directory, url string)) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "profiles",
+		Short: "manage destination profiles",
+		Long:  "Updates and lists destination profiles of exchanges and anchors payments are checked against.",
+	}
+
+	command.AddCommand(&cobra.Command{
+		Use:   "update [url]",
+		Short: "download profiles to profiles directory (default url: profiles.url)",
+		Run: func(cmd *cobra.Command, args []string) {
+			directory, url := load()
+			if len(args) > 0 {
+				url = args[0]
+			}
+			if directory == "" {
+				log.Fatal("profiles.directory param is required")
+			}
+			if url == "" {
+				log.Fatal("url argument or profiles.url param is required")
+			}
+
+			loaded, err := Update(&http.Client{Timeout: 30 * time.Second}, url, directory)
+			if err != nil {
+				log.Fatal("Error updating profiles: ", err)
+			}
+			log.Infof("Downloaded %d profiles to %s, restart the server to use them", len(loaded), filepath.Join(directory, DownloadedFile))
+		},
+	})
+
+	command.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "list built-in and local profiles",
+		Run: func(cmd *cobra.Command, args []string) {
+			directory, _ := load()
+			registry, err := profiles.Load(directory)
+			if err != nil {
+				log.Fatal("Error loading profiles: ", err)
+			}
+			PrintProfiles(os.Stdout, registry.All())
+		},
+	})
+
+	return command
+}
+
+// Update downloads profiles from url, validates them and replaces
+// DownloadedFile in directory. The file is not changed when profiles are
+// invalid.
+func Update(client *http.Client, url, directory string) ([]profiles.Profile, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error downloading profiles")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error downloading profiles: status code %d", resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error downloading profiles")
+	}
+	if len(data) > maxSize {
+		return nil, errors.New("Profiles file is too large")
+	}
+
+	loaded, err := profiles.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(directory, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write to a temporary file first so the server never loads a partial file
+	file, err := ioutil.TempFile(directory, ".downloaded-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Rename(file.Name(), filepath.Join(directory, DownloadedFile))
+	if err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
+
+// PrintProfiles writes a table of profiles to w
+func PrintProfiles(w io.Writer, list []*profiles.Profile) {
+	table := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tMEMO\tASSETS\tDOMAINS")
+	for _, profile := range list {
+		memoType := profile.MemoType
+		if memoType == "" {
+			memoType = "-"
+		}
+
+		assets := make([]string, 0, len(profile.Assets))
+		for _, asset := range profile.Assets {
+			value := asset.Code
+			if asset.MinAmount != "" {
+				value += " (min " + asset.MinAmount + ")"
+			}
+			assets = append(assets, value)
+		}
+		if len(assets) == 0 {
+			assets = append(assets, "any")
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", profile.Name, memoType, strings.Join(assets, ", "), strings.Join(profile.Domains, ", "))
+	}
+	table.Flush()
+}
@@ -0,0 +1,47 @@
+package profilescmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdate(t *testing.T) {
+	body := `[{"name": "downloaded", "memo_type": "id"}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	directory, err := ioutil.TempDir("", "profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	loaded, err := Update(server.Client(), server.URL, directory)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "downloaded", loaded[0].Name)
+
+	data, err := ioutil.ReadFile(filepath.Join(directory, DownloadedFile))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data))
+
+	// Invalid profiles do not replace the file
+	body = `[{"name": "broken", "memo_type": "none"}]`
+	_, err = Update(server.Client(), server.URL, directory)
+	assert.Error(t, err)
+
+	data, err = ioutil.ReadFile(filepath.Join(directory, DownloadedFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "downloaded")
+
+	files, err := ioutil.ReadDir(directory)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
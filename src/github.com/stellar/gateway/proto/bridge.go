@@ -0,0 +1,534 @@
+// Package proto contains messages of the gRPC API of the bridge server
+// defined in bridge.proto. Messages are encoded by hand (see wire.go), so the
+// server doesn't depend on a protobuf runtime; keep them in sync with
+// bridge.proto. Clients can generate their code from bridge.proto.
+package proto
+
+import (
+	"sort"
+)
+
+// Service is the full name of the Bridge service
+const Service = "stellar.bridge.v1.Bridge"
+
+// Asset message
+type Asset struct {
+	Code   string
+	Issuer string
+}
+
+// Marshal encodes Asset
+func (m *Asset) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.Code)
+	e.string(2, m.Issuer)
+	return e.buf
+}
+
+// Unmarshal decodes Asset
+func (m *Asset) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.Code = d.string()
+		case 2:
+			m.Issuer = d.string()
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// ForwardDestination message
+type ForwardDestination struct {
+	Domain string
+	Fields map[string]string
+}
+
+// Marshal encodes ForwardDestination
+func (m *ForwardDestination) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.Domain)
+	keys := make([]string, 0, len(m.Fields))
+	for key := range m.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		// Map entries are messages with key (1) and value (2) fields
+		entry := &encoder{}
+		entry.string(1, key)
+		entry.string(2, m.Fields[key])
+		e.bytes(2, entry.buf)
+	}
+	return e.buf
+}
+
+// Unmarshal decodes ForwardDestination
+func (m *ForwardDestination) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.Domain = d.string()
+		case 2:
+			var key, value string
+			entry := d.bytes()
+			if d.err != nil {
+				return true
+			}
+			d.err = unmarshal(entry, func(d *decoder, number int) bool {
+				switch number {
+				case 1:
+					key = d.string()
+				case 2:
+					value = d.string()
+				default:
+					return false
+				}
+				return true
+			})
+			if m.Fields == nil {
+				m.Fields = map[string]string{}
+			}
+			m.Fields[key] = value
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// PaymentRequest message, fields match params of /payment endpoint
+type PaymentRequest struct {
+	ID                 string
+	Source             string
+	Sender             string
+	Destination        string
+	MemoType           string
+	Memo               string
+	Amount             string
+	AssetCode          string
+	AssetIssuer        string
+	SendMax            string
+	SendAssetCode      string
+	SendAssetIssuer    string
+	Path               []*Asset
+	UseCompliance      bool
+	ExtraMemo          string
+	SenderID           string
+	SenderInfo         string
+	ReceiverInfo       string
+	Route              string
+	Note               string
+	PrivateNote        string
+	DryRun             bool
+	Network            string
+	ForwardDestination *ForwardDestination
+	DestinationName    string
+	OrderingKey        string
+	QuoteID            string
+}
+
+// Marshal encodes PaymentRequest
+func (m *PaymentRequest) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.ID)
+	e.string(2, m.Source)
+	e.string(3, m.Sender)
+	e.string(4, m.Destination)
+	e.string(5, m.MemoType)
+	e.string(6, m.Memo)
+	e.string(7, m.Amount)
+	e.string(8, m.AssetCode)
+	e.string(9, m.AssetIssuer)
+	e.string(10, m.SendMax)
+	e.string(11, m.SendAssetCode)
+	e.string(12, m.SendAssetIssuer)
+	for _, asset := range m.Path {
+		e.message(13, asset)
+	}
+	e.bool(14, m.UseCompliance)
+	e.string(15, m.ExtraMemo)
+	e.string(16, m.SenderID)
+	e.string(17, m.SenderInfo)
+	e.string(18, m.ReceiverInfo)
+	e.string(19, m.Route)
+	e.string(20, m.Note)
+	e.string(21, m.PrivateNote)
+	e.bool(22, m.DryRun)
+	e.string(23, m.Network)
+	if m.ForwardDestination != nil {
+		e.message(24, m.ForwardDestination)
+	}
+	e.string(25, m.DestinationName)
+	e.string(26, m.OrderingKey)
+	e.string(27, m.QuoteID)
+	return e.buf
+}
+
+// Unmarshal decodes PaymentRequest
+func (m *PaymentRequest) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.ID = d.string()
+		case 2:
+			m.Source = d.string()
+		case 3:
+			m.Sender = d.string()
+		case 4:
+			m.Destination = d.string()
+		case 5:
+			m.MemoType = d.string()
+		case 6:
+			m.Memo = d.string()
+		case 7:
+			m.Amount = d.string()
+		case 8:
+			m.AssetCode = d.string()
+		case 9:
+			m.AssetIssuer = d.string()
+		case 10:
+			m.SendMax = d.string()
+		case 11:
+			m.SendAssetCode = d.string()
+		case 12:
+			m.SendAssetIssuer = d.string()
+		case 13:
+			asset := &Asset{}
+			d.message(asset)
+			m.Path = append(m.Path, asset)
+		case 14:
+			m.UseCompliance = d.bool()
+		case 15:
+			m.ExtraMemo = d.string()
+		case 16:
+			m.SenderID = d.string()
+		case 17:
+			m.SenderInfo = d.string()
+		case 18:
+			m.ReceiverInfo = d.string()
+		case 19:
+			m.Route = d.string()
+		case 20:
+			m.Note = d.string()
+		case 21:
+			m.PrivateNote = d.string()
+		case 22:
+			m.DryRun = d.bool()
+		case 23:
+			m.Network = d.string()
+		case 24:
+			m.ForwardDestination = &ForwardDestination{}
+			d.message(m.ForwardDestination)
+		case 25:
+			m.DestinationName = d.string()
+		case 26:
+			m.OrderingKey = d.string()
+		case 27:
+			m.QuoteID = d.string()
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// PreflightFailure message
+type PreflightFailure struct {
+	Operation int32
+	Code      string
+	Message   string
+}
+
+// Marshal encodes PreflightFailure
+func (m *PreflightFailure) Marshal() []byte {
+	e := &encoder{}
+	e.int32(1, m.Operation)
+	e.string(2, m.Code)
+	e.string(3, m.Message)
+	return e.buf
+}
+
+// Unmarshal decodes PreflightFailure
+func (m *PreflightFailure) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.Operation = d.int32()
+		case 2:
+			m.Code = d.string()
+		case 3:
+			m.Message = d.string()
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// Statuses of PaymentResponse
+const (
+	PaymentStatusSuccess = "success"
+	PaymentStatusHeld    = "held"
+	PaymentStatusDryRun  = "dry_run"
+)
+
+// PaymentResponse message
+type PaymentResponse struct {
+	Status           string
+	Hash             string
+	Ledger           uint64
+	ResultXdr        string
+	SendAmount       string
+	InferredMemoType string
+	ID               string
+	SettleAt         string
+	Valid            bool
+	Failures         []*PreflightFailure
+}
+
+// Marshal encodes PaymentResponse
+func (m *PaymentResponse) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.Status)
+	e.string(2, m.Hash)
+	e.uint64(3, m.Ledger)
+	e.string(4, m.ResultXdr)
+	e.string(5, m.SendAmount)
+	e.string(6, m.InferredMemoType)
+	e.string(7, m.ID)
+	e.string(8, m.SettleAt)
+	e.bool(9, m.Valid)
+	for _, failure := range m.Failures {
+		e.message(10, failure)
+	}
+	return e.buf
+}
+
+// Unmarshal decodes PaymentResponse
+func (m *PaymentResponse) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.Status = d.string()
+		case 2:
+			m.Hash = d.string()
+		case 3:
+			m.Ledger = d.uint64()
+		case 4:
+			m.ResultXdr = d.string()
+		case 5:
+			m.SendAmount = d.string()
+		case 6:
+			m.InferredMemoType = d.string()
+		case 7:
+			m.ID = d.string()
+		case 8:
+			m.SettleAt = d.string()
+		case 9:
+			m.Valid = d.bool()
+		case 10:
+			failure := &PreflightFailure{}
+			d.message(failure)
+			m.Failures = append(m.Failures, failure)
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// Operation message, BodyJSON is the body of the operation as in requests
+// to /builder endpoint
+type Operation struct {
+	Type     string
+	BodyJSON string
+}
+
+// Marshal encodes Operation
+func (m *Operation) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.Type)
+	e.string(2, m.BodyJSON)
+	return e.buf
+}
+
+// Unmarshal decodes Operation
+func (m *Operation) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.Type = d.string()
+		case 2:
+			m.BodyJSON = d.string()
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// BuilderRequest message
+type BuilderRequest struct {
+	Source         string
+	SequenceNumber string
+	Operations     []*Operation
+	Signers        []string
+	Network        string
+}
+
+// Marshal encodes BuilderRequest
+func (m *BuilderRequest) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.Source)
+	e.string(2, m.SequenceNumber)
+	for _, operation := range m.Operations {
+		e.message(3, operation)
+	}
+	for _, signer := range m.Signers {
+		e.bytes(4, []byte(signer))
+	}
+	e.string(5, m.Network)
+	return e.buf
+}
+
+// Unmarshal decodes BuilderRequest
+func (m *BuilderRequest) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.Source = d.string()
+		case 2:
+			m.SequenceNumber = d.string()
+		case 3:
+			operation := &Operation{}
+			d.message(operation)
+			m.Operations = append(m.Operations, operation)
+		case 4:
+			m.Signers = append(m.Signers, d.string())
+		case 5:
+			m.Network = d.string()
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// BuilderResponse message
+type BuilderResponse struct {
+	TransactionEnvelope string
+}
+
+// Marshal encodes BuilderResponse
+func (m *BuilderResponse) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.TransactionEnvelope)
+	return e.buf
+}
+
+// Unmarshal decodes BuilderResponse
+func (m *BuilderResponse) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.TransactionEnvelope = d.string()
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// TransactionStatusRequest message, selects a transaction by PaymentID or
+// TransactionID
+type TransactionStatusRequest struct {
+	PaymentID     string
+	TransactionID string
+	Network       string
+}
+
+// Marshal encodes TransactionStatusRequest
+func (m *TransactionStatusRequest) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.PaymentID)
+	e.string(2, m.TransactionID)
+	e.string(3, m.Network)
+	return e.buf
+}
+
+// Unmarshal decodes TransactionStatusRequest
+func (m *TransactionStatusRequest) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.PaymentID = d.string()
+		case 2:
+			m.TransactionID = d.string()
+		case 3:
+			m.Network = d.string()
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// TransactionStatus message
+type TransactionStatus struct {
+	TransactionID string
+	PaymentID     string
+	Status        string
+	Source        string
+	Ledger        uint64
+	SubmittedAt   string
+	SucceededAt   string
+	ResultXdr     string
+	Region        string
+}
+
+// Marshal encodes TransactionStatus
+func (m *TransactionStatus) Marshal() []byte {
+	e := &encoder{}
+	e.string(1, m.TransactionID)
+	e.string(2, m.PaymentID)
+	e.string(3, m.Status)
+	e.string(4, m.Source)
+	e.uint64(5, m.Ledger)
+	e.string(6, m.SubmittedAt)
+	e.string(7, m.SucceededAt)
+	e.string(8, m.ResultXdr)
+	e.string(9, m.Region)
+	return e.buf
+}
+
+// Unmarshal decodes TransactionStatus
+func (m *TransactionStatus) Unmarshal(data []byte) error {
+	return unmarshal(data, func(d *decoder, number int) bool {
+		switch number {
+		case 1:
+			m.TransactionID = d.string()
+		case 2:
+			m.PaymentID = d.string()
+		case 3:
+			m.Status = d.string()
+		case 4:
+			m.Source = d.string()
+		case 5:
+			m.Ledger = d.uint64()
+		case 6:
+			m.SubmittedAt = d.string()
+		case 7:
+			m.SucceededAt = d.string()
+		case 8:
+			m.ResultXdr = d.string()
+		case 9:
+			m.Region = d.string()
+		default:
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,60 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	// Encoded like protoc generated code
+	assert.Equal(t, []byte{0x0a, 0x03, 'U', 'S', 'D'}, (&Asset{Code: "USD"}).Marshal())
+	assert.Equal(t, []byte{0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, (&PreflightFailure{Operation: -1}).Marshal())
+	assert.Empty(t, (&PaymentResponse{}).Marshal())
+
+	request := &PaymentRequest{
+		ID:          "payment-1",
+		Source:      "SBKKWO3ZVDDEHDJILGHPHCJCFD2GNUAYIUDMRAS326HLUEQ7ZFXWIGQK",
+		Destination: "bob*stellar.org",
+		Amount:      "20",
+		Path:        []*Asset{{}, {Code: "USD", Issuer: "GA"}},
+		DryRun:      true,
+		ForwardDestination: &ForwardDestination{
+			Domain: "stellar.org",
+			Fields: map[string]string{"forward_type": "bank_account", "swift": "BOPBPHMM"},
+		},
+		DestinationName: "acme-settlement",
+	}
+	var decoded PaymentRequest
+	require.NoError(t, decoded.Unmarshal(request.Marshal()))
+	assert.Equal(t, request, &decoded)
+
+	response := &PaymentResponse{
+		Status:   PaymentStatusDryRun,
+		Failures: []*PreflightFailure{{Operation: -1, Code: "transaction_bad_seq", Message: "Bad sequence."}},
+	}
+	var decodedResponse PaymentResponse
+	require.NoError(t, decodedResponse.Unmarshal(response.Marshal()))
+	assert.Equal(t, response, &decodedResponse)
+
+	t.Run("unknown fields are skipped", func(t *testing.T) {
+		e := &encoder{}
+		e.string(1, "USD")
+		e.uint64(3, 300)
+		e.string(4, "unknown")
+		e.buf = append(e.buf, 0x2d, 1, 2, 3, 4) // fixed32 field 5
+		e.string(2, "GA")
+
+		var asset Asset
+		require.NoError(t, asset.Unmarshal(e.buf))
+		assert.Equal(t, Asset{Code: "USD", Issuer: "GA"}, asset)
+	})
+
+	t.Run("invalid messages", func(t *testing.T) {
+		var asset Asset
+		assert.Error(t, asset.Unmarshal([]byte{0x0a, 0x05, 'U'}), "truncated")
+		assert.Error(t, asset.Unmarshal([]byte{0x08, 0x01}), "wrong wire type")
+		assert.Error(t, asset.Unmarshal([]byte{0x0f}), "unsupported wire type")
+	})
+}
@@ -0,0 +1,199 @@
+package proto
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// Wire types used by messages of bridge.proto
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Message is a protobuf message of bridge.proto
+type Message interface {
+	Marshal() []byte
+	Unmarshal(data []byte) error
+}
+
+// encoder appends fields to buf. Fields with default values are not written,
+// like in proto3.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) varint(value uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], value)
+	e.buf = append(e.buf, b[:n]...)
+}
+
+func (e *encoder) tag(field, wireType int) {
+	e.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (e *encoder) bytes(field int, value []byte) {
+	e.tag(field, wireBytes)
+	e.varint(uint64(len(value)))
+	e.buf = append(e.buf, value...)
+}
+
+func (e *encoder) string(field int, value string) {
+	if value != "" {
+		e.bytes(field, []byte(value))
+	}
+}
+
+func (e *encoder) bool(field int, value bool) {
+	if value {
+		e.tag(field, wireVarint)
+		e.varint(1)
+	}
+}
+
+func (e *encoder) uint64(field int, value uint64) {
+	if value != 0 {
+		e.tag(field, wireVarint)
+		e.varint(value)
+	}
+}
+
+func (e *encoder) int32(field int, value int32) {
+	if value != 0 {
+		e.tag(field, wireVarint)
+		// Negative values are sign extended to 64 bits
+		e.varint(uint64(int64(value)))
+	}
+}
+
+// message writes value even when it's empty, so elements of repeated fields
+// are not lost
+func (e *encoder) message(field int, value Message) {
+	e.bytes(field, value.Marshal())
+}
+
+// decoder reads fields of a message. The first error is kept in err and
+// stops decoding.
+type decoder struct {
+	buf      []byte
+	wireType int
+	err      error
+}
+
+// unmarshal calls field for every field of data. field returns false for
+// unknown fields, which are skipped.
+func unmarshal(data []byte, field func(d *decoder, number int) bool) error {
+	d := &decoder{buf: data}
+	for d.err == nil && len(d.buf) > 0 {
+		key := d.varint()
+		if d.err != nil {
+			break
+		}
+		number := int(key >> 3)
+		d.wireType = int(key & 7)
+		if number == 0 {
+			return errors.New("Invalid field number 0")
+		}
+		if !field(d, number) {
+			d.skip()
+		}
+	}
+	return d.err
+}
+
+func (d *decoder) varint() uint64 {
+	value, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errors.New("Invalid varint")
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return value
+}
+
+func (d *decoder) expect(wireType int) bool {
+	if d.err == nil && d.wireType != wireType {
+		d.err = errors.Errorf("Unexpected wire type %d", d.wireType)
+	}
+	return d.err == nil
+}
+
+func (d *decoder) bytes() []byte {
+	if !d.expect(wireBytes) {
+		return nil
+	}
+	length := d.varint()
+	if d.err != nil {
+		return nil
+	}
+	if length > uint64(len(d.buf)) {
+		d.err = errors.New("Unexpected end of message")
+		return nil
+	}
+	value := d.buf[:length]
+	d.buf = d.buf[length:]
+	return value
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+func (d *decoder) bool() bool {
+	if !d.expect(wireVarint) {
+		return false
+	}
+	return d.varint() != 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if !d.expect(wireVarint) {
+		return 0
+	}
+	return d.varint()
+}
+
+func (d *decoder) int32() int32 {
+	if !d.expect(wireVarint) {
+		return 0
+	}
+	value := d.varint()
+	if int64(value) < math.MinInt32 || int64(value) > math.MaxInt32 {
+		d.err = errors.New("int32 value out of range")
+		return 0
+	}
+	return int32(value)
+}
+
+func (d *decoder) message(value Message) {
+	data := d.bytes()
+	if d.err == nil {
+		d.err = value.Unmarshal(data)
+	}
+}
+
+func (d *decoder) skip() {
+	switch d.wireType {
+	case wireVarint:
+		d.varint()
+	case wireBytes:
+		d.bytes()
+	case wireFixed64, wireFixed32:
+		size := 8
+		if d.wireType == wireFixed32 {
+			size = 4
+		}
+		if len(d.buf) < size {
+			d.err = errors.New("Unexpected end of message")
+			return
+		}
+		d.buf = d.buf[size:]
+	default:
+		d.err = errors.Errorf("Unsupported wire type %d", d.wireType)
+	}
+}
@@ -0,0 +1,205 @@
+// Package amounts implements arithmetic on Stellar amounts. Amounts are
+// handled as int64 number of stroops (the unit used by stellar-core, 10^-7 of
+// an asset) so adding and comparing them never loses precision. Use String to
+// format stroops the way Horizon does.
+package amounts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// One is the number of stroops in one unit of an asset
+const One = 10000000
+
+// decimals is the number of digits in the fractional part of an amount
+const decimals = 7
+
+// ErrOverflow is returned when the result does not fit in int64 stroops
+var ErrOverflow = errors.New("amount overflows int64 stroops")
+
+// maxExponent limits exponents of amounts, greater values overflow anyway
+const maxExponent = 32
+
+// Amount is a fixed-point amount in stroops. Use it instead of strings and
+// floats when amounts are validated, compared or sent to other services, so
+// every amount has at most 7 fractional digits and the same format as on the
+// ledger.
+type Amount int64
+
+// ParseAmount converts an amount string to Amount, see Parse
+func ParseAmount(value string) (Amount, error) {
+	stroops, err := Parse(value)
+	return Amount(stroops), err
+}
+
+// String formats the amount the way Horizon does, ex. "10.5000000"
+func (a Amount) String() string {
+	return String(int64(a))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (a *Amount) UnmarshalText(text []byte) error {
+	amount, err := ParseAmount(string(text))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Parse converts an amount string (ex. "10.5") to stroops. Unlike
+// github.com/stellar/go/amount.Parse it never rounds: amounts with more than 7
+// fractional digits, negative amounts and fractions are rejected. Exponents
+// (ex. "1.5e-3", sent as JSON numbers by some clients) are accepted when the
+// amount has at most 7 fractional digits.
+func Parse(value string) (int64, error) {
+	mantissa, exponent := value, 0
+	if i := strings.IndexAny(value, "eE"); i >= 0 {
+		var err error
+		mantissa = value[:i]
+		exponent, err = strconv.Atoi(value[i+1:])
+		if err != nil || exponent > maxExponent || exponent < -maxExponent {
+			return 0, fmt.Errorf("cannot parse amount: %q", value)
+		}
+	}
+
+	whole, fraction := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		whole, fraction = mantissa[:i], mantissa[i+1:]
+	}
+
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("cannot parse amount: %q", value)
+	}
+	if exponent != 0 {
+		whole, fraction = shift(whole, fraction, exponent)
+	}
+	if len(fraction) > decimals {
+		return 0, fmt.Errorf("amount %q has more than %d fractional digits", value, decimals)
+	}
+
+	stroops := int64(0)
+	digits := whole + fraction + strings.Repeat("0", decimals-len(fraction))
+	for _, digit := range digits {
+		if stroops > (math.MaxInt64-int64(digit-'0'))/10 {
+			return 0, ErrOverflow
+		}
+		stroops = stroops*10 + int64(digit-'0')
+	}
+	return stroops, nil
+}
+
+// shift moves the decimal point of whole.fraction by exponent digits.
+// Trailing zeros of the fraction are removed.
+func shift(whole, fraction string, exponent int) (string, string) {
+	digits := whole + fraction
+	point := len(whole) + exponent
+	if point < 0 {
+		digits = strings.Repeat("0", -point) + digits
+		point = 0
+	}
+	if point > len(digits) {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+	return digits[:point], strings.TrimRight(digits[point:], "0")
+}
+
+// Normalize returns value formatted the way Horizon does, ex. "10.5000000"
+// for "10.5" and "1.05e1"
+func Normalize(value string) (string, error) {
+	stroops, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return String(stroops), nil
+}
+
+// MustParse is the panicking version of Parse. Use it for values already
+// checked with Parse.
+func MustParse(value string) int64 {
+	stroops, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return stroops
+}
+
+// String converts stroops to an amount string with 7 fractional digits
+// (ex. "10.5000000"), the format used by Horizon
+func String(stroops int64) string {
+	sign := ""
+	whole := uint64(stroops)
+	if stroops < 0 {
+		sign = "-"
+		whole = uint64(-(stroops + 1)) + 1
+	}
+
+	fraction := strconv.FormatUint(whole%One, 10)
+	return sign + strconv.FormatUint(whole/One, 10) + "." + strings.Repeat("0", decimals-len(fraction)) + fraction
+}
+
+// Add returns a+b or ErrOverflow
+func Add(a, b int64) (int64, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, ErrOverflow
+	}
+	return a + b, nil
+}
+
+// Sum parses and adds amount strings
+func Sum(values ...string) (sum int64, err error) {
+	for _, value := range values {
+		var stroops int64
+		stroops, err = Parse(value)
+		if err != nil {
+			return 0, err
+		}
+
+		sum, err = Add(sum, stroops)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return
+}
+
+// Compare parses amount strings and returns -1 when a < b, 0 when a == b and
+// 1 when a > b. "1.5" and "1.5000000" are equal.
+func Compare(a, b string) (int, error) {
+	x, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+
+	y, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
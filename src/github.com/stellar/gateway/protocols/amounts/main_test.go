@@ -0,0 +1,152 @@
+package amounts
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stellar/go/amount"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for value, expected := range map[string]int64{
+		"0":                    0,
+		"1":                    One,
+		"1.5":                  15000000,
+		"0.0000001":            1,
+		".5":                   5000000,
+		"5.":                   5 * One,
+		"100.0010000":          1000010000,
+		"922337203685.4775807": math.MaxInt64,
+		// Exponents
+		"1e3":           1000 * One,
+		"1.5E-3":        15000,
+		"1e-7":          1,
+		"0.000000010e1": 1,
+		"25e+0":         25 * One,
+	} {
+		stroops, err := Parse(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, stroops, value)
+	}
+
+	for _, value := range []string{"", ".", "-1", "+1", "1/3", "0.00000001", "1.2.3", " 1", "922337203685.4775808", "e3", "1e", "1e-8", "1e1.5", "1e999999999", "0x10"} {
+		_, err := Parse(value)
+		assert.Error(t, err, value)
+	}
+
+	_, err := Parse("922337203685.4775808")
+	assert.Equal(t, ErrOverflow, err)
+	_, err = Parse("1e12")
+	assert.Equal(t, ErrOverflow, err)
+}
+
+func TestAmount(t *testing.T) {
+	value, err := ParseAmount("1.05e1")
+	require.NoError(t, err)
+	assert.Equal(t, Amount(105000000), value)
+	assert.Equal(t, "10.5000000", value.String())
+
+	normalized, err := Normalize("0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "0.1000000", normalized)
+	_, err = Normalize("0.1.2")
+	assert.Error(t, err)
+
+	// Amounts are encoded as strings in JSON
+	var decoded struct {
+		Amount Amount `json:"amount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"2.5"}`), &decoded))
+	assert.Equal(t, Amount(25000000), decoded.Amount)
+	encoded, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":"2.5000000"}`, string(encoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"2.50000001"}`), &decoded))
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "0.0000000", String(0))
+	assert.Equal(t, "0.0000001", String(1))
+	assert.Equal(t, "1.5000000", String(15000000))
+	assert.Equal(t, "-1.5000000", String(-15000000))
+	assert.Equal(t, "922337203685.4775807", String(math.MaxInt64))
+	assert.Equal(t, "-922337203685.4775808", String(math.MinInt64))
+}
+
+func TestArithmetic(t *testing.T) {
+	// Floats would give 0.30000000000000004
+	sum, err := Sum("0.1", "0.2")
+	require.NoError(t, err)
+	assert.Equal(t, "0.3000000", String(sum))
+
+	_, err = Sum("1", "x")
+	assert.Error(t, err)
+
+	_, err = Add(math.MaxInt64, 1)
+	assert.Equal(t, ErrOverflow, err)
+	_, err = Add(math.MinInt64, -1)
+	assert.Equal(t, ErrOverflow, err)
+
+	result, err := Compare("1.5", "1.5000000")
+	require.NoError(t, err)
+	assert.Equal(t, 0, result)
+
+	result, err = Compare("1.4999999", "1.5")
+	require.NoError(t, err)
+	assert.Equal(t, -1, result)
+
+	result, err = Compare("10", "9.9999999")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	_, err = Compare("1", "0.00000001")
+	assert.Error(t, err)
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"0", "1.5", ".5", "0.0000001", "922337203685.4775807", "1e3", "1.5E-3", "-1", "0.00000001"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		stroops, err := Parse(value)
+		if err != nil {
+			return
+		}
+
+		// Accepted amounts are parsed the same way as by stellar-core clients
+		expected, err := amount.Parse(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, int64(expected), stroops, value)
+
+		formatted := String(stroops)
+		assert.Equal(t, amount.StringFromInt64(stroops), formatted)
+
+		again, err := Parse(formatted)
+		require.NoError(t, err, formatted)
+		assert.Equal(t, stroops, again)
+	})
+}
+
+func FuzzAdd(f *testing.F) {
+	f.Add(int64(1), int64(2))
+	f.Add(int64(math.MaxInt64), int64(1))
+	f.Add(int64(math.MinInt64), int64(-1))
+
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		expected := new(big.Int).Add(big.NewInt(a), big.NewInt(b))
+
+		sum, err := Add(a, b)
+		if !expected.IsInt64() {
+			assert.Equal(t, ErrOverflow, err)
+			return
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, expected.Int64(), sum)
+	})
+}
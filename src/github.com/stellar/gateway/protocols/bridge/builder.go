@@ -34,18 +34,36 @@ const (
 	OperationTypeInflation OperationType = "inflation"
 	// OperationTypeManageData represents manage_data operation
 	OperationTypeManageData OperationType = "manage_data"
+	// OperationTypeLiquidityPoolDeposit represents liquidity_pool_deposit
+	// operation
+	OperationTypeLiquidityPoolDeposit OperationType = "liquidity_pool_deposit"
+	// OperationTypeLiquidityPoolWithdraw represents liquidity_pool_withdraw
+	// operation
+	OperationTypeLiquidityPoolWithdraw OperationType = "liquidity_pool_withdraw"
+	// OperationTypeInvokeHostFunction represents invoke_host_function
+	// operation
+	OperationTypeInvokeHostFunction OperationType = "invoke_host_function"
 )
 
 // BuilderRequest represents request made to /builder endpoint of bridge server
 type BuilderRequest struct {
-	Source         string
-	SequenceNumber string `json:"sequence_number"`
-	Operations     []Operation
-	Signers        []string
+	Source         string      `json:"source"`
+	SequenceNumber string      `json:"sequence_number"`
+	Operations     []Operation `json:"operations"`
+	Signers        []string    `json:"signers"`
+	// Network is the name of an additional network the transaction is built
+	// for, the main network when empty
+	Network string `json:"network,omitempty"`
 }
 
-// Process parses operations and creates OperationBody object for each operation
+// Process parses operations and creates OperationBody object for each operation.
+// Requests with more than protocols.MaxOperations operations are rejected
+// before parsing.
 func (r BuilderRequest) Process() error {
+	if len(r.Operations) > protocols.MaxOperations {
+		return protocols.NewInvalidParameterError("operations", strconv.Itoa(len(r.Operations)), "Transaction can contain at most "+strconv.Itoa(protocols.MaxOperations)+" operations.")
+	}
+
 	var err error
 	for i, operation := range r.Operations {
 		var operationBody OperationBody
@@ -96,6 +114,18 @@ func (r BuilderRequest) Process() error {
 			var manageData ManageDataOperationBody
 			err = json.Unmarshal(operation.RawBody, &manageData)
 			operationBody = manageData
+		case OperationTypeLiquidityPoolDeposit:
+			var deposit LiquidityPoolDepositOperationBody
+			err = json.Unmarshal(operation.RawBody, &deposit)
+			operationBody = deposit
+		case OperationTypeLiquidityPoolWithdraw:
+			var withdraw LiquidityPoolWithdrawOperationBody
+			err = json.Unmarshal(operation.RawBody, &withdraw)
+			operationBody = withdraw
+		case OperationTypeInvokeHostFunction:
+			var invoke InvokeHostFunctionOperationBody
+			err = json.Unmarshal(operation.RawBody, &invoke)
+			operationBody = invoke
 		default:
 			return protocols.NewInvalidParameterError("operations["+strconv.Itoa(i)+"][type]", string(operation.Type), "Invalid operation type.")
 		}
@@ -116,6 +146,10 @@ func (r BuilderRequest) Validate() error {
 		return protocols.NewInvalidParameterError("source", r.Source, "Source parameter must start with `G`.")
 	}
 
+	if len(r.Signers) > protocols.MaxSignatures {
+		return protocols.NewInvalidParameterError("signers", strconv.Itoa(len(r.Signers)), "Transaction can contain at most "+strconv.Itoa(protocols.MaxSignatures)+" signatures.")
+	}
+
 	for i, signer := range r.Signers {
 		if !protocols.IsValidSecret(signer) {
 			return protocols.NewInvalidParameterError("signers["+strconv.Itoa(i)+"]", signer, "Signer must start with `S`.")
@@ -127,14 +161,46 @@ func (r BuilderRequest) Validate() error {
 		if err != nil {
 			return err
 		}
+
+		_, soroban := operation.Body.(InvokeHostFunctionOperationBody)
+		if soroban && len(r.Operations) > 1 {
+			return protocols.NewInvalidParameterError("operations", strconv.Itoa(len(r.Operations)), "invoke_host_function must be the only operation of a transaction.")
+		}
 	}
 
 	return nil
 }
 
+// InvokeHostFunction returns invoke_host_function operation of requests
+// building Soroban transactions. Call it after Validate.
+func (r BuilderRequest) InvokeHostFunction() (InvokeHostFunctionOperationBody, bool) {
+	if len(r.Operations) != 1 {
+		return InvokeHostFunctionOperationBody{}, false
+	}
+	body, ok := r.Operations[0].Body.(InvokeHostFunctionOperationBody)
+	return body, ok
+}
+
+// SourceAccounts returns the source account of the transaction and source
+// accounts of operations. Call it after Process.
+func (r BuilderRequest) SourceAccounts() []string {
+	accounts := []string{r.Source}
+	for _, operation := range r.Operations {
+		var body struct {
+			Source *string
+		}
+		// RawBody has been parsed in Process
+		json.Unmarshal(operation.RawBody, &body)
+		if body.Source != nil {
+			accounts = append(accounts, *body.Source)
+		}
+	}
+	return accounts
+}
+
 // Operation struct contains operation type and body
 type Operation struct {
-	Type    OperationType
+	Type    OperationType   `json:"type"`
 	RawBody json.RawMessage `json:"body"` // Delay parsing until we know operation type
 	Body    OperationBody   `json:"-"`    // Created during processing stage
 }
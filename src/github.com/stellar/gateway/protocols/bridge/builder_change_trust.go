@@ -9,12 +9,18 @@ import (
 type ChangeTrustOperationBody struct {
 	Source *string
 	Asset  protocols.Asset
+	// LiquidityPool is set to trust pool shares instead of Asset
+	LiquidityPool *LiquidityPool `json:"liquidity_pool"`
 	// nil means max limit
 	Limit *string
 }
 
 // ToTransactionMutator returns go-stellar-base TransactionMutator
 func (op ChangeTrustOperationBody) ToTransactionMutator() b.TransactionMutator {
+	if op.LiquidityPool != nil {
+		return changeTrustPoolShares(op.Source, *op.LiquidityPool, op.Limit)
+	}
+
 	mutators := []interface{}{
 		op.Asset.ToBaseAsset(),
 	}
@@ -35,7 +41,16 @@ func (op ChangeTrustOperationBody) ToTransactionMutator() b.TransactionMutator {
 
 // Validate validates if operation body is valid.
 func (op ChangeTrustOperationBody) Validate() error {
-	if !op.Asset.Validate() {
+	if op.LiquidityPool != nil {
+		if op.Asset != (protocols.Asset{}) {
+			return protocols.NewInvalidParameterError("asset", op.Asset.String(), "Asset cannot be sent with liquidity_pool.")
+		}
+
+		err := op.LiquidityPool.Validate()
+		if err != nil {
+			return err
+		}
+	} else if !op.Asset.Validate() {
 		return protocols.NewInvalidParameterError("asset", op.Asset.String(), "Asset is invalid.")
 	}
 
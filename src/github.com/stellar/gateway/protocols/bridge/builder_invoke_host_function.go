@@ -0,0 +1,301 @@
+package bridge
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/sorobanrpc"
+	"github.com/stellar/gateway/stellarxdr"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+)
+
+var symbolRegexp = regexp.MustCompile("^[a-zA-Z0-9_]{1,32}$")
+
+// InvokeHostFunctionOperationBody represents invoke_host_function operation
+// calling Function of a contract. Soroban transactions contain a single
+// operation and are built as ENVELOPE_TYPE_TX envelopes, so the operation is
+// not built by go/build (see BuilderRequest.InvokeHostFunction). Auth and
+// SorobanData are simulated with soroban-rpc when SorobanData is empty.
+type InvokeHostFunctionOperationBody struct {
+	Source     *string
+	ContractID string `json:"contract_id"`
+	Function   string
+	Args       []ContractArgument
+	// Auth are base64 encoded SorobanAuthorizationEntry values
+	Auth []string
+	// SorobanData is base64 encoded SorobanTransactionData (footprint,
+	// resources and resource fee)
+	SorobanData string `json:"soroban_data"`
+}
+
+// ContractArgument is an argument of a contract function. Type is one of
+// bool, void, u32, i32, u64, i64, u128, i128, string, symbol, bytes (hex
+// encoded), address (G... or C...) or xdr (base64 encoded SCVal).
+type ContractArgument struct {
+	Type  string
+	Value string
+}
+
+// ToTransactionMutator returns go-stellar-base TransactionMutator. The
+// vendored XDR can't encode Soroban transactions, the mutator returns an
+// error.
+func (op InvokeHostFunctionOperationBody) ToTransactionMutator() b.TransactionMutator {
+	return xdrOperation{Err: errors.New("invoke_host_function is built by BuildSorobanTransaction")}
+}
+
+// Validate validates if operation body is valid.
+func (op InvokeHostFunctionOperationBody) Validate() error {
+	if !sorobanrpc.IsContractAddress(op.ContractID) {
+		return protocols.NewInvalidParameterError("contract_id", op.ContractID, "Contract ID must be a contract address (starting with `C`).")
+	}
+
+	if !symbolRegexp.MatchString(op.Function) {
+		return protocols.NewInvalidParameterError("function", op.Function, "Function must be a symbol of at most 32 characters.")
+	}
+
+	for i, arg := range op.Args {
+		_, err := arg.ScVal()
+		if err != nil {
+			return protocols.NewInvalidParameterError("args["+strconv.Itoa(i)+"]", arg.Value, err.Error())
+		}
+	}
+
+	for i, auth := range op.Auth {
+		var entry stellarxdr.SorobanAuthorizationEntry
+		if stellarxdr.SafeUnmarshalBase64(auth, &entry) != nil {
+			return protocols.NewInvalidParameterError("auth["+strconv.Itoa(i)+"]", auth, "Auth must be a base64 encoded SorobanAuthorizationEntry.")
+		}
+	}
+
+	if op.SorobanData != "" {
+		var data stellarxdr.SorobanTransactionData
+		if stellarxdr.SafeUnmarshalBase64(op.SorobanData, &data) != nil {
+			return protocols.NewInvalidParameterError("soroban_data", op.SorobanData, "Soroban data must be a base64 encoded SorobanTransactionData.")
+		}
+	}
+
+	if op.Source != nil && !protocols.IsValidAccountID(*op.Source) {
+		return protocols.NewInvalidParameterError("source", *op.Source, "Source must be a public key (starting with `G`).")
+	}
+
+	return nil
+}
+
+// Operation returns invoke_host_function operation. Call it after Validate.
+func (op InvokeHostFunctionOperationBody) Operation() (operation stellarxdr.Operation, err error) {
+	invoke := stellarxdr.InvokeContractArgs{FunctionName: op.Function}
+	invoke.ContractAddress, err = newScAddress(op.ContractID)
+	if err != nil {
+		return
+	}
+
+	for _, arg := range op.Args {
+		var value stellarxdr.ScVal
+		value, err = arg.ScVal()
+		if err != nil {
+			return
+		}
+		invoke.Args = append(invoke.Args, value)
+	}
+
+	body := stellarxdr.InvokeHostFunctionOp{
+		HostFunction: stellarxdr.HostFunction{
+			Type:           stellarxdr.HostFunctionTypeInvokeContract,
+			InvokeContract: &invoke,
+		},
+	}
+
+	for _, auth := range op.Auth {
+		var entry stellarxdr.SorobanAuthorizationEntry
+		err = stellarxdr.SafeUnmarshalBase64(auth, &entry)
+		if err != nil {
+			return
+		}
+		body.Auth = append(body.Auth, entry)
+	}
+
+	operation.Body = stellarxdr.OperationBody{
+		Type:                 stellarxdr.OperationTypeInvokeHostFunction,
+		InvokeHostFunctionOp: &body,
+	}
+	if op.Source != nil {
+		var source stellarxdr.MuxedAccount
+		source, err = newMuxedAccount(*op.Source)
+		operation.SourceAccount = &source
+	}
+	return
+}
+
+// BuildSorobanTransaction returns a transaction of the source account with
+// the invoke_host_function operation. data is the simulated
+// SorobanTransactionData when op.SorobanData is empty, the transaction fee
+// is the default base fee plus its resource fee.
+func (op InvokeHostFunctionOperationBody) BuildSorobanTransaction(source string, sequence uint64, data *stellarxdr.SorobanTransactionData) (tx stellarxdr.Transaction, err error) {
+	operation, err := op.Operation()
+	if err != nil {
+		return
+	}
+
+	if op.SorobanData != "" {
+		data = &stellarxdr.SorobanTransactionData{}
+		err = stellarxdr.SafeUnmarshalBase64(op.SorobanData, data)
+		if err != nil {
+			return
+		}
+	}
+
+	tx.SourceAccount, err = newMuxedAccount(source)
+	if err != nil {
+		return
+	}
+	tx.SeqNum = xdr.SequenceNumber(sequence)
+	tx.Fee = xdr.Uint32(b.DefaultBaseFee)
+	tx.Memo = xdr.Memo{Type: xdr.MemoTypeMemoNone}
+	tx.Operations = []stellarxdr.Operation{operation}
+
+	if data != nil {
+		if data.ResourceFee < 0 || int64(data.ResourceFee) > math.MaxUint32-int64(tx.Fee) {
+			err = errors.New("Resource fee is out of range")
+			return
+		}
+		tx.Fee += xdr.Uint32(data.ResourceFee)
+		tx.Ext = stellarxdr.TransactionExt{V: 1, SorobanData: data}
+	}
+	return
+}
+
+// ScVal returns the SCVal of the argument
+func (arg ContractArgument) ScVal() (value stellarxdr.ScVal, err error) {
+	switch arg.Type {
+	case "bool":
+		var v bool
+		v, err = strconv.ParseBool(arg.Value)
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvBool, B: &v}
+	case "void":
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvVoid}
+	case "u32":
+		var v uint64
+		v, err = strconv.ParseUint(arg.Value, 10, 32)
+		u32 := xdr.Uint32(v)
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvU32, U32: &u32}
+	case "i32":
+		var v int64
+		v, err = strconv.ParseInt(arg.Value, 10, 32)
+		i32 := xdr.Int32(v)
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvI32, I32: &i32}
+	case "u64":
+		var v uint64
+		v, err = strconv.ParseUint(arg.Value, 10, 64)
+		u64 := xdr.Uint64(v)
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvU64, U64: &u64}
+	case "i64":
+		var v int64
+		v, err = strconv.ParseInt(arg.Value, 10, 64)
+		i64 := xdr.Int64(v)
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvI64, I64: &i64}
+	case "u128", "i128":
+		value, err = int128ScVal(arg.Type, arg.Value)
+	case "string":
+		v := arg.Value
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvString, Str: &v}
+	case "symbol":
+		if !symbolRegexp.MatchString(arg.Value) {
+			err = errors.New("Symbol can contain at most 32 letters, digits and underscores.")
+		}
+		v := arg.Value
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvSymbol, Sym: &v}
+	case "bytes":
+		var v []byte
+		v, err = hex.DecodeString(arg.Value)
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvBytes, Bytes: &v}
+	case "address":
+		var address stellarxdr.ScAddress
+		address, err = newScAddress(arg.Value)
+		value = stellarxdr.ScVal{Type: stellarxdr.ScValTypeScvAddress, Address: &address}
+	case "xdr":
+		err = stellarxdr.SafeUnmarshalBase64(arg.Value, &value)
+	default:
+		return value, errors.New("Unknown argument type: " + arg.Type)
+	}
+
+	if err != nil {
+		err = errors.New("Invalid " + arg.Type + " argument: " + err.Error())
+	}
+	return
+}
+
+var (
+	maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	maxInt128  = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	minInt128  = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+)
+
+// int128ScVal returns SCV_U128 or SCV_I128 value of a decimal integer
+func int128ScVal(typ, value string) (scVal stellarxdr.ScVal, err error) {
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return scVal, errors.New("not an integer")
+	}
+
+	min, max := big.NewInt(0), maxUint128
+	if typ == "i128" {
+		min, max = minInt128, maxInt128
+	}
+	if v.Cmp(min) < 0 || v.Cmp(max) > 0 {
+		return scVal, errors.New("out of range")
+	}
+
+	// Two's complement of negative values
+	if v.Sign() < 0 {
+		v.Add(v, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	lo := new(big.Int).And(v, new(big.Int).SetUint64(^uint64(0))).Uint64()
+	hi := new(big.Int).Rsh(v, 64).Uint64()
+
+	if typ == "i128" {
+		scVal = stellarxdr.ScVal{
+			Type: stellarxdr.ScValTypeScvI128,
+			I128: &stellarxdr.Int128Parts{Hi: xdr.Int64(hi), Lo: xdr.Uint64(lo)},
+		}
+	} else {
+		scVal = stellarxdr.ScVal{
+			Type: stellarxdr.ScValTypeScvU128,
+			U128: &stellarxdr.UInt128Parts{Hi: xdr.Uint64(hi), Lo: xdr.Uint64(lo)},
+		}
+	}
+	return
+}
+
+// newScAddress returns SCAddress of an account (G...) or a contract (C...)
+func newScAddress(address string) (scAddress stellarxdr.ScAddress, err error) {
+	if sorobanrpc.IsContractAddress(address) {
+		var id [32]byte
+		id, err = sorobanrpc.DecodeContractAddress(address)
+		contractID := xdr.Hash(id)
+		return stellarxdr.ScAddress{Type: stellarxdr.ScAddressTypeContract, ContractId: &contractID}, err
+	}
+
+	if !protocols.IsValidAccountID(address) {
+		return scAddress, errors.New("Address must be an account or a contract address.")
+	}
+
+	var accountID xdr.AccountId
+	err = accountID.SetAddress(address)
+	return stellarxdr.ScAddress{Type: stellarxdr.ScAddressTypeAccount, AccountId: &accountID}, err
+}
+
+// newMuxedAccount returns MuxedAccount of an account (G...)
+func newMuxedAccount(address string) (account stellarxdr.MuxedAccount, err error) {
+	var accountID xdr.AccountId
+	err = accountID.SetAddress(address)
+	if err != nil {
+		return
+	}
+	return stellarxdr.MuxedAccount{Type: stellarxdr.CryptoKeyTypeEd25519, Ed25519: accountID.Ed25519}, nil
+}
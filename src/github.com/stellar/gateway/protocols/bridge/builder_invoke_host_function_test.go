@@ -0,0 +1,148 @@
+package bridge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContract = "CA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQGAXE"
+
+func TestBuilderRequestInvokeHostFunction(t *testing.T) {
+	var request BuilderRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"source": "`+testSource+`",
+		"operations": [{"type": "invoke_host_function", "body": {
+			"contract_id": "`+testContract+`",
+			"function": "transfer",
+			"args": [
+				{"type": "address", "value": "`+testSource+`"},
+				{"type": "address", "value": "`+testContract+`"},
+				{"type": "i128", "value": "-2"}
+			]
+		}}]
+	}`), &request))
+	require.NoError(t, request.Process())
+	require.NoError(t, request.Validate())
+
+	invoke, ok := request.InvokeHostFunction()
+	require.True(t, ok)
+
+	tx, err := invoke.BuildSorobanTransaction(testSource, 124, nil)
+	require.NoError(t, err)
+	assert.Equal(t, xdr.Uint32(100), tx.Fee)
+	assert.Equal(t, xdr.SequenceNumber(124), tx.SeqNum)
+	assert.Equal(t, int32(0), tx.Ext.V)
+
+	contract := tx.Operations[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract
+	assert.Equal(t, stellarxdr.ScAddressTypeContract, contract.ContractAddress.Type)
+	assert.Equal(t, "transfer", contract.FunctionName)
+	require.Len(t, contract.Args, 3)
+	assert.Equal(t, stellarxdr.ScAddressTypeAccount, contract.Args[0].Address.Type)
+	assert.Equal(t, stellarxdr.ScAddressTypeContract, contract.Args[1].Address.Type)
+	assert.Equal(t, stellarxdr.Int128Parts{Hi: -1, Lo: xdr.Uint64(^uint64(1))}, *contract.Args[2].I128)
+
+	// Transaction data sent in the request is used instead of the simulated
+	// one
+	data, err := xdr.MarshalBase64(stellarxdr.SorobanTransactionData{ResourceFee: 5000})
+	require.NoError(t, err)
+	invoke.SorobanData = data
+	tx, err = invoke.BuildSorobanTransaction(testSource, 124, &stellarxdr.SorobanTransactionData{ResourceFee: 1})
+	require.NoError(t, err)
+	assert.Equal(t, xdr.Uint32(5100), tx.Fee)
+	assert.Equal(t, int32(1), tx.Ext.V)
+
+	_, err = invoke.BuildSorobanTransaction(testSource, 124, nil)
+	require.NoError(t, err)
+	invoke.SorobanData = ""
+	_, err = invoke.BuildSorobanTransaction(testSource, 124, &stellarxdr.SorobanTransactionData{ResourceFee: -1})
+	assert.Error(t, err)
+}
+
+func TestBuilderRequestInvokeHostFunctionInvalid(t *testing.T) {
+	for name, body := range map[string]string{
+		"contract_id":  `{"contract_id": "` + testSource + `", "function": "transfer"}`,
+		"function":     `{"contract_id": "` + testContract + `", "function": "transfer-from"}`,
+		"args[0]":      `{"contract_id": "` + testContract + `", "function": "transfer", "args": [{"type": "u32", "value": "-1"}]}`,
+		"auth[0]":      `{"contract_id": "` + testContract + `", "function": "transfer", "auth": ["AAAA"]}`,
+		"soroban_data": `{"contract_id": "` + testContract + `", "function": "transfer", "soroban_data": "AAAA"}`,
+	} {
+		var request BuilderRequest
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"source": "`+testSource+`",
+			"operations": [{"type": "invoke_host_function", "body": `+body+`}]
+		}`), &request))
+		require.NoError(t, request.Process(), name)
+
+		err := request.Validate()
+		require.Error(t, err, name)
+		assert.Equal(t, name, err.(*protocols.ErrorResponse).Data["name"], name)
+	}
+
+	// Soroban transactions contain a single operation
+	var request BuilderRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"source": "`+testSource+`",
+		"operations": [
+			{"type": "invoke_host_function", "body": {"contract_id": "`+testContract+`", "function": "transfer"}},
+			{"type": "inflation", "body": {}}
+		]
+	}`), &request))
+	require.NoError(t, request.Process())
+	err := request.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "operations", err.(*protocols.ErrorResponse).Data["name"])
+}
+
+func TestContractArgumentScVal(t *testing.T) {
+	for _, arg := range []ContractArgument{
+		{"bool", "true"},
+		{"void", ""},
+		{"u32", "4294967295"},
+		{"i32", "-2147483648"},
+		{"u64", "18446744073709551615"},
+		{"i64", "-9223372036854775808"},
+		{"u128", "340282366920938463463374607431768211455"},
+		{"i128", "-170141183460469231731687303715884105728"},
+		{"string", "hello world"},
+		{"symbol", "hello_world"},
+		{"bytes", "00ff"},
+		{"address", testDestination},
+		{"xdr", "AAAAAwAAAAE="},
+	} {
+		value, err := arg.ScVal()
+		require.NoError(t, err, arg.Type)
+
+		// Values are encoded and decoded
+		encoded, err := xdr.MarshalBase64(value)
+		require.NoError(t, err, arg.Type)
+		var decoded stellarxdr.ScVal
+		require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &decoded), arg.Type)
+		assert.Equal(t, value, decoded, arg.Type)
+	}
+
+	u128, err := ContractArgument{"u128", "18446744073709551617"}.ScVal()
+	require.NoError(t, err)
+	assert.Equal(t, stellarxdr.UInt128Parts{Hi: 1, Lo: 1}, *u128.U128)
+
+	for _, arg := range []ContractArgument{
+		{"bool", "yes"},
+		{"u32", "4294967296"},
+		{"i64", "9223372036854775808"},
+		{"u128", "-1"},
+		{"i128", "170141183460469231731687303715884105728"},
+		{"symbol", "hello world"},
+		{"bytes", "0g"},
+		{"address", "GABC"},
+		{"xdr", "AAAA"},
+		{"map", "{}"},
+	} {
+		_, err := arg.ScVal()
+		assert.Error(t, err, arg.Type)
+	}
+}
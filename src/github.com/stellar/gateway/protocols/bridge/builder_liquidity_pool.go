@@ -0,0 +1,257 @@
+package bridge
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/go/amount"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/price"
+	"github.com/stellar/go/xdr"
+)
+
+// LiquidityPoolDepositOperationBody represents liquidity_pool_deposit operation
+type LiquidityPoolDepositOperationBody struct {
+	Source *string
+	// LiquidityPoolID is hex encoded, like in Horizon
+	LiquidityPoolID string `json:"liquidity_pool_id"`
+	MaxAmountA      string `json:"max_amount_a"`
+	MaxAmountB      string `json:"max_amount_b"`
+	// MinPrice and MaxPrice limit the price of asset A in asset B
+	MinPrice string `json:"min_price"`
+	MaxPrice string `json:"max_price"`
+}
+
+// ToTransactionMutator returns go-stellar-base TransactionMutator
+func (op LiquidityPoolDepositOperationBody) ToTransactionMutator() b.TransactionMutator {
+	// Validated in Validate()
+	deposit := xdr.LiquidityPoolDepositOp{
+		LiquidityPoolId: mustParsePoolID(op.LiquidityPoolID),
+		MaxAmountA:      amount.MustParse(op.MaxAmountA),
+		MaxAmountB:      amount.MustParse(op.MaxAmountB),
+	}
+	deposit.MinPrice, _ = price.Parse(op.MinPrice)
+	deposit.MaxPrice, _ = price.Parse(op.MaxPrice)
+
+	return newXDROperation(op.Source, xdr.OperationTypeLiquidityPoolDeposit, deposit)
+}
+
+// Validate validates if operation body is valid.
+func (op LiquidityPoolDepositOperationBody) Validate() error {
+	if !isValidPoolID(op.LiquidityPoolID) {
+		return protocols.NewInvalidParameterError("liquidity_pool_id", op.LiquidityPoolID, "Liquidity pool ID must be a hex encoded 32 bytes hash.")
+	}
+
+	if !protocols.IsValidAmount(op.MaxAmountA) {
+		return protocols.NewInvalidParameterError("max_amount_a", op.MaxAmountA, "Not a valid amount.")
+	}
+
+	if !protocols.IsValidAmount(op.MaxAmountB) {
+		return protocols.NewInvalidParameterError("max_amount_b", op.MaxAmountB, "Not a valid amount.")
+	}
+
+	if _, err := price.Parse(op.MinPrice); err != nil {
+		return protocols.NewInvalidParameterError("min_price", op.MinPrice, "Not a valid price.")
+	}
+
+	if _, err := price.Parse(op.MaxPrice); err != nil {
+		return protocols.NewInvalidParameterError("max_price", op.MaxPrice, "Not a valid price.")
+	}
+
+	if op.Source != nil && !protocols.IsValidAccountID(*op.Source) {
+		return protocols.NewInvalidParameterError("source", *op.Source, "Source must be a public key (starting with `G`).")
+	}
+
+	return nil
+}
+
+// LiquidityPoolWithdrawOperationBody represents liquidity_pool_withdraw
+// operation
+type LiquidityPoolWithdrawOperationBody struct {
+	Source *string
+	// LiquidityPoolID is hex encoded, like in Horizon
+	LiquidityPoolID string `json:"liquidity_pool_id"`
+	// Amount of pool shares withdrawn
+	Amount string
+	// nil means 0
+	MinAmountA *string `json:"min_amount_a"`
+	MinAmountB *string `json:"min_amount_b"`
+}
+
+// ToTransactionMutator returns go-stellar-base TransactionMutator
+func (op LiquidityPoolWithdrawOperationBody) ToTransactionMutator() b.TransactionMutator {
+	// Validated in Validate()
+	withdraw := xdr.LiquidityPoolWithdrawOp{
+		LiquidityPoolId: mustParsePoolID(op.LiquidityPoolID),
+		Amount:          amount.MustParse(op.Amount),
+	}
+	if op.MinAmountA != nil {
+		withdraw.MinAmountA = amount.MustParse(*op.MinAmountA)
+	}
+	if op.MinAmountB != nil {
+		withdraw.MinAmountB = amount.MustParse(*op.MinAmountB)
+	}
+
+	return newXDROperation(op.Source, xdr.OperationTypeLiquidityPoolWithdraw, withdraw)
+}
+
+// Validate validates if operation body is valid.
+func (op LiquidityPoolWithdrawOperationBody) Validate() error {
+	if !isValidPoolID(op.LiquidityPoolID) {
+		return protocols.NewInvalidParameterError("liquidity_pool_id", op.LiquidityPoolID, "Liquidity pool ID must be a hex encoded 32 bytes hash.")
+	}
+
+	if !protocols.IsValidAmount(op.Amount) {
+		return protocols.NewInvalidParameterError("amount", op.Amount, "Not a valid amount.")
+	}
+
+	if op.MinAmountA != nil && !protocols.IsValidAmount(*op.MinAmountA) {
+		return protocols.NewInvalidParameterError("min_amount_a", *op.MinAmountA, "Not a valid amount.")
+	}
+
+	if op.MinAmountB != nil && !protocols.IsValidAmount(*op.MinAmountB) {
+		return protocols.NewInvalidParameterError("min_amount_b", *op.MinAmountB, "Not a valid amount.")
+	}
+
+	if op.Source != nil && !protocols.IsValidAccountID(*op.Source) {
+		return protocols.NewInvalidParameterError("source", *op.Source, "Source must be a public key (starting with `G`).")
+	}
+
+	return nil
+}
+
+// LiquidityPool is a constant product liquidity pool whose shares are
+// trusted by change_trust operation
+type LiquidityPool struct {
+	// Assets can be sent in any order, they are sorted like in the pool ID
+	AssetA protocols.Asset `json:"asset_a"`
+	AssetB protocols.Asset `json:"asset_b"`
+}
+
+// Validate checks if assets of the pool are valid and different
+func (p LiquidityPool) Validate() error {
+	if !p.AssetA.Validate() {
+		return protocols.NewInvalidParameterError("liquidity_pool[asset_a]", p.AssetA.String(), "Asset is invalid.")
+	}
+
+	if !p.AssetB.Validate() {
+		return protocols.NewInvalidParameterError("liquidity_pool[asset_b]", p.AssetB.String(), "Asset is invalid.")
+	}
+
+	if p.AssetA == p.AssetB {
+		return protocols.NewInvalidParameterError("liquidity_pool[asset_b]", p.AssetB.String(), "Assets of the pool must be different.")
+	}
+
+	return nil
+}
+
+// Parameters returns XDR parameters of the pool. Call it after Validate.
+func (p LiquidityPool) Parameters() (params xdr.LiquidityPoolParameters, err error) {
+	assetA, err := p.AssetA.ToBaseAsset().ToXDR()
+	if err != nil {
+		return
+	}
+	assetB, err := p.AssetB.ToBaseAsset().ToXDR()
+	if err != nil {
+		return
+	}
+
+	// Assets are ordered by type, code and issuer. Assets of the same type
+	// have encodings of the same length, so they can be compared as bytes.
+	var encodedA, encodedB bytes.Buffer
+	if _, err = xdr.Marshal(&encodedA, assetA); err != nil {
+		return
+	}
+	if _, err = xdr.Marshal(&encodedB, assetB); err != nil {
+		return
+	}
+	if bytes.Compare(encodedA.Bytes(), encodedB.Bytes()) > 0 {
+		assetA, assetB = assetB, assetA
+	}
+
+	params.Type = xdr.LiquidityPoolTypeLiquidityPoolConstantProduct
+	params.ConstantProduct = &xdr.LiquidityPoolConstantProductParameters{
+		AssetA: assetA,
+		AssetB: assetB,
+		Fee:    xdr.LiquidityPoolFeeV18,
+	}
+	return
+}
+
+// ID returns hex encoded ID of the pool, as returned by Horizon
+func (p LiquidityPool) ID() (string, error) {
+	params, err := p.Parameters()
+	if err != nil {
+		return "", err
+	}
+
+	var encoded bytes.Buffer
+	if _, err = xdr.Marshal(&encoded, params); err != nil {
+		return "", err
+	}
+	id := sha256.Sum256(encoded.Bytes())
+	return hex.EncodeToString(id[:]), nil
+}
+
+// changeTrustPoolShares returns change_trust mutator of pool shares. go/build
+// supports assets only.
+func changeTrustPoolShares(source *string, pool LiquidityPool, limit *string) b.TransactionMutator {
+	params, err := pool.Parameters()
+	if err != nil {
+		return xdrOperation{Err: err}
+	}
+
+	changeTrust := xdr.ChangeTrustOp{
+		Line:  xdr.ChangeTrustAsset{Type: xdr.AssetTypeAssetTypePoolShare, LiquidityPool: &params},
+		Limit: xdr.Int64(math.MaxInt64),
+	}
+	if limit != nil {
+		// Validated in Validate()
+		changeTrust.Limit = amount.MustParse(*limit)
+	}
+
+	return newXDROperation(source, xdr.OperationTypeChangeTrust, changeTrust)
+}
+
+// xdrOperation adds an operation built from XDR to a transaction, for
+// operations go/build has no builders for
+type xdrOperation struct {
+	O   xdr.Operation
+	Err error
+}
+
+func newXDROperation(source *string, operationType xdr.OperationType, value interface{}) xdrOperation {
+	var m xdrOperation
+	m.O.Body, m.Err = xdr.NewOperationBody(operationType, value)
+	if m.Err == nil && source != nil {
+		m.Err = b.SourceAccount{*source}.MutateOperation(&m.O)
+	}
+	return m
+}
+
+// MutateTransaction adds the operation to the transaction
+func (m xdrOperation) MutateTransaction(o *b.TransactionBuilder) error {
+	if m.Err != nil {
+		return m.Err
+	}
+
+	o.TX.Operations = append(o.TX.Operations, m.O)
+	return nil
+}
+
+func isValidPoolID(id string) bool {
+	raw, err := hex.DecodeString(id)
+	return err == nil && len(raw) == 32
+}
+
+func mustParsePoolID(id string) (poolID xdr.PoolId) {
+	raw, err := hex.DecodeString(id)
+	if err != nil || len(raw) != 32 {
+		panic("invalid liquidity pool ID: " + id)
+	}
+	copy(poolID[:], raw)
+	return
+}
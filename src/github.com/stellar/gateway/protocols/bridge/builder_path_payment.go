@@ -86,6 +86,11 @@ func (op PathPaymentOperationBody) Validate() error {
 		return protocols.NewInvalidParameterError("source", *op.Source, "Source must be a public key (starting with `G`).")
 	}
 
+	err := protocols.CheckPathLength("path", op.Path)
+	if err != nil {
+		return err
+	}
+
 	for i, asset := range op.Path {
 		if !asset.Validate() {
 			return protocols.NewInvalidParameterError("path["+strconv.Itoa(i)+"]", asset.String(), "Invalid asset.")
@@ -87,6 +87,10 @@ func (op SetOptionsOperationBody) Validate() error {
 		}
 	}
 
+	if op.HomeDomain != nil && len(*op.HomeDomain) > 32 {
+		return protocols.NewInvalidParameterError("home_domain", *op.HomeDomain, "Home domain can be at most 32 bytes long.")
+	}
+
 	if op.Source != nil && !protocols.IsValidAccountID(*op.Source) {
 		return protocols.NewInvalidParameterError("source", *op.Source, "Source must be a public key (starting with `G`).")
 	}
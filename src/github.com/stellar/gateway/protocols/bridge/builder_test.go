@@ -0,0 +1,114 @@
+package bridge
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/protocols"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderRequestLiquidityPool(t *testing.T) {
+	poolID := "dd7b1ab831c273310ddbec6f97870aa83c2fbd78ce22aded37ecbf4f3380fac7"
+
+	var request BuilderRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"source": "`+testSource+`",
+		"operations": [
+			{"type": "change_trust", "body": {"liquidity_pool": {"asset_a": {"code": "USD", "issuer": "`+testDestination+`"}, "asset_b": {}}}},
+			{"type": "liquidity_pool_deposit", "body": {"liquidity_pool_id": "`+poolID+`", "max_amount_a": "10", "max_amount_b": "20", "min_price": "0.45", "max_price": "0.55"}},
+			{"type": "liquidity_pool_withdraw", "body": {"source": "`+testDestination+`", "liquidity_pool_id": "`+poolID+`", "amount": "5", "min_amount_b": "1"}}
+		]
+	}`), &request))
+	require.NoError(t, request.Process())
+	require.NoError(t, request.Validate())
+
+	var mutators []b.TransactionMutator
+	for _, operation := range request.Operations {
+		mutators = append(mutators, operation.Body.ToTransactionMutator())
+	}
+	tx, err := b.Transaction(append([]b.TransactionMutator{b.SourceAccount{AddressOrSeed: testSource}, b.Sequence{Sequence: 1}, b.TestNetwork}, mutators...)...)
+	require.NoError(t, err)
+
+	// Operations are decoded from the encoded transaction
+	encoded, err := xdr.MarshalBase64(tx.TX)
+	require.NoError(t, err)
+	var decoded xdr.Transaction
+	require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &decoded))
+	require.Len(t, decoded.Operations, 3)
+
+	// Native asset is the first asset of the pool
+	line := decoded.Operations[0].Body.ChangeTrustOp.Line
+	assert.Equal(t, xdr.AssetTypeAssetTypePoolShare, line.Type)
+	assert.Equal(t, xdr.AssetTypeAssetTypeNative, line.LiquidityPool.ConstantProduct.AssetA.Type)
+	assert.Equal(t, xdr.AssetTypeAssetTypeCreditAlphanum4, line.LiquidityPool.ConstantProduct.AssetB.Type)
+	assert.Equal(t, xdr.Int32(30), line.LiquidityPool.ConstantProduct.Fee)
+	assert.Equal(t, xdr.Int64(math.MaxInt64), decoded.Operations[0].Body.ChangeTrustOp.Limit)
+
+	// Pool shares are valid in change_trust only
+	encodedLine, err := xdr.MarshalBase64(line)
+	require.NoError(t, err)
+	var asset xdr.Asset
+	assert.Error(t, xdr.SafeUnmarshalBase64(encodedLine, &asset))
+
+	deposit := decoded.Operations[1].Body.LiquidityPoolDepositOp
+	require.NotNil(t, deposit)
+	assert.Equal(t, xdr.OperationTypeLiquidityPoolDeposit, decoded.Operations[1].Body.Type)
+	assert.Equal(t, poolID, hex.EncodeToString(deposit.LiquidityPoolId[:]))
+	assert.Equal(t, xdr.Int64(100000000), deposit.MaxAmountA)
+	assert.Equal(t, xdr.Int64(200000000), deposit.MaxAmountB)
+	assert.Equal(t, xdr.Price{N: 9, D: 20}, deposit.MinPrice)
+	assert.Equal(t, xdr.Price{N: 11, D: 20}, deposit.MaxPrice)
+
+	withdraw := decoded.Operations[2].Body.LiquidityPoolWithdrawOp
+	require.NotNil(t, withdraw)
+	assert.Equal(t, testDestination, decoded.Operations[2].SourceAccount.Address())
+	assert.Equal(t, xdr.Int64(50000000), withdraw.Amount)
+	assert.Equal(t, xdr.Int64(0), withdraw.MinAmountA)
+	assert.Equal(t, xdr.Int64(10000000), withdraw.MinAmountB)
+}
+
+func TestLiquidityPoolID(t *testing.T) {
+	// XLM/USDC pool on the public network
+	id, err := LiquidityPool{AssetB: protocols.Asset{Code: "USDC", Issuer: "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"}}.ID()
+	require.NoError(t, err)
+	assert.Equal(t, "a468d41d8e9b8f3c7209651608b74b7db7ac9952dcae0cdf24871d1d9c7b0088", id)
+
+	usd := protocols.Asset{Code: "USD", Issuer: testDestination}
+	eur := protocols.Asset{Code: "EUR", Issuer: testDestination}
+
+	// ID does not depend on the order of assets
+	id, err = LiquidityPool{AssetA: usd, AssetB: eur}.ID()
+	require.NoError(t, err)
+	reversed, err := LiquidityPool{AssetA: eur, AssetB: usd}.ID()
+	require.NoError(t, err)
+	assert.Equal(t, id, reversed)
+	assert.Len(t, id, 64)
+
+	err = LiquidityPool{AssetA: usd, AssetB: usd}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.(*protocols.ErrorResponse).MoreInfo, "must be different")
+}
+
+func TestBuilderRequestLiquidityPoolInvalid(t *testing.T) {
+	for body, name := range map[string]string{
+		`{"type": "liquidity_pool_deposit", "body": {"liquidity_pool_id": "abc", "max_amount_a": "1", "max_amount_b": "1", "min_price": "1", "max_price": "1"}}`:            "liquidity_pool_id",
+		`{"type": "liquidity_pool_deposit", "body": {"liquidity_pool_id": "` + strings.Repeat("ab", 32) + `", "max_amount_a": "1", "max_amount_b": "1", "max_price": "1"}}`: "min_price",
+		`{"type": "liquidity_pool_withdraw", "body": {"liquidity_pool_id": "` + strings.Repeat("ab", 32) + `", "amount": "x"}}`:                                             "amount",
+		`{"type": "change_trust", "body": {"asset": {"code": "USD", "issuer": "` + testDestination + `"}, "liquidity_pool": {"asset_a": {}, "asset_b": {}}}}`:               "asset",
+	} {
+		var request BuilderRequest
+		require.NoError(t, json.Unmarshal([]byte(`{"source": "`+testSource+`", "operations": [`+body+`]}`), &request))
+		require.NoError(t, request.Process())
+
+		err := request.Validate()
+		require.Error(t, err, body)
+		assert.Equal(t, name, err.(*protocols.ErrorResponse).Data["name"], body)
+	}
+}
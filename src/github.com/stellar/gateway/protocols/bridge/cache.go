@@ -0,0 +1,26 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// CacheFlushResponse represents response returned by /admin/cache/flush
+// endpoint
+type CacheFlushResponse struct {
+	Status string `json:"status"`
+	// Flushed contains names of dropped caches: stellar_toml, federation and
+	// destination_accounts. Disabled caches are not listed.
+	Flushed []string `json:"flushed"`
+}
+
+// HTTPStatus implements server.Response
+func (r CacheFlushResponse) HTTPStatus() int {
+	return http.StatusOK
+}
+
+// Marshal marshals CacheFlushResponse
+func (r CacheFlushResponse) Marshal() []byte {
+	json, _ := json.MarshalIndent(r, "", "  ")
+	return json
+}
@@ -0,0 +1,48 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+var (
+	// ComplianceRepairNotAvailable is an error response
+	ComplianceRepairNotAvailable = &protocols.ErrorResponse{Code: "compliance_repair_not_available", Message: "Compliance server is not configured.", Status: http.StatusBadRequest}
+	// ComplianceRepairPaymentNotFound is an error response
+	ComplianceRepairPaymentNotFound = &protocols.ErrorResponse{Code: "compliance_repair_payment_not_found", Message: "Received payment with given operation ID not found.", Status: http.StatusNotFound}
+	// ComplianceRepairAttachmentNotFound is an error response
+	ComplianceRepairAttachmentNotFound = &protocols.ErrorResponse{Code: "compliance_repair_attachment_not_found", Message: "Compliance server has no attachment for given memo.", Status: http.StatusNotFound}
+)
+
+// ComplianceRepairRequest represents request made to /admin/compliance-repair endpoint of bridge server
+type ComplianceRepairRequest struct {
+	OperationID string `name:"operation_id" required:""`
+	// Memo is the memo value under which the correct attachment is stored in compliance server
+	Memo       string `name:"memo" required:""`
+	VerifiedBy string `name:"verified_by" required:""`
+	Reason     string `name:"reason"`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *ComplianceRepairRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *ComplianceRepairRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *ComplianceRepairRequest) Validate() error {
+	err := request.FormRequest.CheckRequired(request)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package bridge
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+)
+
+// MaxAccountSigners is the max number of signers of an account (Stellar
+// protocol limit)
+const MaxAccountSigners = 20
+
+// CreateAccountRequest represents request made to /create-account endpoint of
+// bridge server. A random key pair is generated when PublicKey is empty.
+type CreateAccountRequest struct {
+	PublicKey string `json:"public_key,omitempty"`
+	// StartingBalance is the XLM balance of the account, the configured
+	// starting balance when empty
+	StartingBalance string `json:"starting_balance,omitempty"`
+	// HomeDomain and Signers are set by the account in the transaction
+	// creating it, so they are available for generated key pairs only
+	HomeDomain   string             `json:"home_domain,omitempty"`
+	Signers      []SetOptionsSigner `json:"signers,omitempty"`
+	MasterWeight *uint32            `json:"master_weight,omitempty"`
+	// Sponsored makes the funder sponsor reserves of the account and its
+	// signers. The account must sign end_sponsoring_future_reserves, so it
+	// is available for generated key pairs only.
+	Sponsored bool `json:"sponsored,omitempty"`
+}
+
+// Validate validates if request fields are valid. maxStartingBalance is the
+// max starting balance allowed by the config.
+func (r CreateAccountRequest) Validate(maxStartingBalance string) error {
+	if r.PublicKey != "" {
+		if !protocols.IsValidAccountID(r.PublicKey) {
+			return protocols.NewInvalidParameterError("public_key", r.PublicKey, "Public key must start with `G`.")
+		}
+
+		if r.HomeDomain != "" || len(r.Signers) > 0 || r.MasterWeight != nil || r.Sponsored {
+			return protocols.NewInvalidParameterError("public_key", r.PublicKey, "home_domain, signers, master_weight and sponsored can be set for generated key pairs only.")
+		}
+	}
+
+	// Accounts with sponsored reserves can be created with no balance
+	zeroBalance := r.Sponsored && protocols.IsValidAmount(r.StartingBalance) && amounts.MustParse(r.StartingBalance) == 0
+
+	if r.StartingBalance != "" && !zeroBalance {
+		err := validatePositiveAmount("starting_balance", r.StartingBalance)
+		if err != nil {
+			return err
+		}
+
+		if amounts.MustParse(r.StartingBalance) > amounts.MustParse(maxStartingBalance) {
+			return protocols.NewInvalidParameterError("starting_balance", r.StartingBalance, "Starting balance can be at most "+maxStartingBalance+".")
+		}
+	}
+
+	if len(r.HomeDomain) > 32 {
+		return protocols.NewInvalidParameterError("home_domain", r.HomeDomain, "Home domain can be at most 32 bytes long.")
+	}
+
+	if len(r.Signers) > MaxAccountSigners {
+		return protocols.NewInvalidParameterError("signers", strconv.Itoa(len(r.Signers)), "Account can have at most "+strconv.Itoa(MaxAccountSigners)+" signers.")
+	}
+
+	for _, signer := range r.Signers {
+		if !protocols.IsValidAccountID(signer.PublicKey) {
+			return protocols.NewInvalidParameterError("signers", signer.PublicKey, "Signer must be a public key (starting with `G`).")
+		}
+
+		if signer.Weight == 0 || signer.Weight > 255 {
+			return protocols.NewInvalidParameterError("signers", strconv.FormatUint(uint64(signer.Weight), 10), "Signer weight must be between 1 and 255.")
+		}
+	}
+
+	if r.MasterWeight != nil && *r.MasterWeight > 255 {
+		return protocols.NewInvalidParameterError("master_weight", strconv.FormatUint(uint64(*r.MasterWeight), 10), "Master weight must be between 0 and 255.")
+	}
+
+	return nil
+}
+
+// ToTransactionMutator returns operations of a transaction of the funder
+// creating account accountID with startingBalance. Options are set in
+// set_options operations with the new account as source, a single operation
+// can add one signer. Sponsored accounts are created and set up between
+// begin_sponsoring_future_reserves and end_sponsoring_future_reserves.
+func (r CreateAccountRequest) ToTransactionMutator(accountID, startingBalance string) b.TransactionMutator {
+	var operations TransactionMutators
+	if r.Sponsored {
+		sponsored := xdr.BeginSponsoringFutureReservesOp{}
+		err := sponsored.SponsoredId.SetAddress(accountID)
+		if err != nil {
+			return xdrOperation{Err: err}
+		}
+		operations = append(operations, newXDROperation(nil, xdr.OperationTypeBeginSponsoringFutureReserves, sponsored))
+	}
+
+	operations = append(operations, b.CreateAccount(b.Destination{accountID}, b.NativeAmount{startingBalance}))
+
+	var options []interface{}
+	if r.HomeDomain != "" {
+		options = append(options, b.HomeDomain(r.HomeDomain))
+	}
+	if len(r.Signers) > 0 {
+		options = append(options, b.Signer{Address: r.Signers[0].PublicKey, Weight: r.Signers[0].Weight})
+	}
+	if len(options) > 0 {
+		operations = append(operations, b.SetOptions(append(options, b.SourceAccount{accountID})...))
+	}
+
+	for i := 1; i < len(r.Signers); i++ {
+		operations = append(operations, b.SetOptions(
+			b.SourceAccount{accountID},
+			b.Signer{Address: r.Signers[i].PublicKey, Weight: r.Signers[i].Weight},
+		))
+	}
+
+	// Master weight is changed last, signers may be required to sign
+	// further transactions
+	if r.MasterWeight != nil {
+		operations = append(operations, b.SetOptions(b.SourceAccount{accountID}, b.MasterWeight(*r.MasterWeight)))
+	}
+
+	if r.Sponsored {
+		operations = append(operations, newXDROperation(&accountID, xdr.OperationTypeEndSponsoringFutureReserves, nil))
+	}
+
+	return operations
+}
+
+// TransactionMutators applies a list of mutators, ex. operations of a single
+// transaction
+type TransactionMutators []b.TransactionMutator
+
+// MutateTransaction implements build.TransactionMutator
+func (m TransactionMutators) MutateTransaction(t *b.TransactionBuilder) error {
+	for _, mutator := range m {
+		err := mutator.MutateTransaction(t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateAccountResponse represents response returned by /create-account
+// endpoint of bridge server. PrivateKey is empty when the public key was
+// sent in the request.
+type CreateAccountResponse struct {
+	protocols.SuccessResponse
+	PublicKey   string                            `json:"public_key"`
+	PrivateKey  string                            `json:"private_key,omitempty"`
+	Transaction horizon.SubmitTransactionResponse `json:"transaction"`
+}
+
+// Marshal marshals CreateAccountResponse
+func (response *CreateAccountResponse) Marshal() []byte {
+	json, _ := json.MarshalIndent(response, "", "  ")
+	return json
+}
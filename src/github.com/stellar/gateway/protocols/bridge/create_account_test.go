@@ -0,0 +1,118 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/stellar/gateway/protocols"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAccountRequestValidate(t *testing.T) {
+	weight := uint32(0)
+	valid := CreateAccountRequest{
+		StartingBalance: "10",
+		HomeDomain:      "stellar.org",
+		Signers:         []SetOptionsSigner{{PublicKey: testDestination, Weight: 1}},
+		MasterWeight:    &weight,
+	}
+	assert.NoError(t, valid.Validate("10"))
+	assert.NoError(t, CreateAccountRequest{PublicKey: testSource}.Validate("2"))
+
+	for name, request := range map[string]CreateAccountRequest{
+		"public_key":       {PublicKey: "SBAD", StartingBalance: "1"},
+		"starting_balance": {StartingBalance: "10.0000001"},
+		"home_domain":      {HomeDomain: "a-home-domain-longer-than-32-bytes.org"},
+		"signers":          {Signers: []SetOptionsSigner{{PublicKey: testDestination, Weight: 256}}},
+	} {
+		err := request.Validate("10")
+		require.Error(t, err, name)
+		assert.Equal(t, name, err.(*protocols.ErrorResponse).Data["name"], name)
+	}
+
+	// Options can't be signed by accounts of public keys sent in the request
+	err := CreateAccountRequest{PublicKey: testSource, HomeDomain: "stellar.org"}.Validate("2")
+	require.Error(t, err)
+	assert.Equal(t, "public_key", err.(*protocols.ErrorResponse).Data["name"])
+
+	err = CreateAccountRequest{PublicKey: testSource, Sponsored: true}.Validate("2")
+	require.Error(t, err)
+	assert.Equal(t, "public_key", err.(*protocols.ErrorResponse).Data["name"])
+
+	// Only sponsored accounts can be created with no balance
+	assert.NoError(t, CreateAccountRequest{StartingBalance: "0", Sponsored: true}.Validate("2"))
+	err = CreateAccountRequest{StartingBalance: "0"}.Validate("2")
+	require.Error(t, err)
+	assert.Equal(t, "starting_balance", err.(*protocols.ErrorResponse).Data["name"])
+}
+
+func TestCreateAccountRequestToTransactionMutator(t *testing.T) {
+	weight := uint32(0)
+	request := CreateAccountRequest{
+		HomeDomain: "stellar.org",
+		Signers: []SetOptionsSigner{
+			{PublicKey: testDestination, Weight: 1},
+			{PublicKey: testSource, Weight: 2},
+		},
+		MasterWeight: &weight,
+	}
+
+	tx, err := b.Transaction(
+		b.SourceAccount{testSource},
+		b.Sequence{1},
+		b.TestNetwork,
+		request.ToTransactionMutator(testDestination, "2"),
+	)
+	require.NoError(t, err)
+
+	operations := tx.TX.Operations
+	require.Len(t, operations, 4)
+	assert.Equal(t, xdr.OperationTypeCreateAccount, operations[0].Body.Type)
+	assert.Nil(t, operations[0].SourceAccount)
+	assert.Equal(t, xdr.Int64(20000000), operations[0].Body.CreateAccountOp.StartingBalance)
+
+	for _, operation := range operations[1:] {
+		assert.Equal(t, xdr.OperationTypeSetOptions, operation.Body.Type)
+		require.NotNil(t, operation.SourceAccount)
+		assert.Equal(t, testDestination, operation.SourceAccount.Address())
+	}
+	assert.Equal(t, "stellar.org", string(*operations[1].Body.SetOptionsOp.HomeDomain))
+	assert.Equal(t, xdr.Uint32(0), *operations[3].Body.SetOptionsOp.MasterWeight)
+}
+
+func TestCreateAccountRequestToTransactionMutatorSponsored(t *testing.T) {
+	request := CreateAccountRequest{
+		Signers:   []SetOptionsSigner{{PublicKey: testSource, Weight: 1}},
+		Sponsored: true,
+	}
+
+	tx, err := b.Transaction(
+		b.SourceAccount{testSource},
+		b.Sequence{1},
+		b.TestNetwork,
+		request.ToTransactionMutator(testDestination, "0"),
+	)
+	require.NoError(t, err)
+
+	// Operations are decoded from the encoded transaction
+	encoded, err := xdr.MarshalBase64(tx.TX)
+	require.NoError(t, err)
+	var decoded xdr.Transaction
+	require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &decoded))
+
+	operations := decoded.Operations
+	require.Len(t, operations, 4)
+	assert.Equal(t, xdr.OperationTypeBeginSponsoringFutureReserves, operations[0].Body.Type)
+	assert.Nil(t, operations[0].SourceAccount)
+	assert.Equal(t, testDestination, operations[0].Body.BeginSponsoringFutureReservesOp.SponsoredId.Address())
+	assert.Equal(t, xdr.OperationTypeCreateAccount, operations[1].Body.Type)
+	assert.Equal(t, xdr.Int64(0), operations[1].Body.CreateAccountOp.StartingBalance)
+	assert.Equal(t, xdr.OperationTypeSetOptions, operations[2].Body.Type)
+
+	// Reserves are sponsored until the new account ends the sponsorship
+	assert.Equal(t, xdr.OperationTypeEndSponsoringFutureReserves, operations[3].Body.Type)
+	require.NotNil(t, operations[3].SourceAccount)
+	assert.Equal(t, testDestination, operations[3].SourceAccount.Address())
+}
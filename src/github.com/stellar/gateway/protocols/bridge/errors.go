@@ -1,13 +1,12 @@
 package bridge
 
 import (
-	"encoding/base64"
 	"net/http"
-	"strings"
 
 	"github.com/stellar/gateway/horizon"
 	"github.com/stellar/gateway/protocols"
-	"github.com/stellar/go/xdr"
+	"github.com/stellar/gateway/submitter"
+	"github.com/stellar/go/support/errors"
 )
 
 var (
@@ -23,126 +22,93 @@ var (
 	TransactionInsufficientFee = &protocols.ErrorResponse{Code: "transaction_insufficient_fee", Message: "Transaction fee is too small.", Status: http.StatusBadRequest}
 	// TransactionBadAuthExtra is an error response
 	TransactionBadAuthExtra = &protocols.ErrorResponse{Code: "transaction_bad_auth_extra", Message: "Unused signatures attached to transaction.", Status: http.StatusBadRequest}
+	// HorizonUnavailable is an error response
+	HorizonUnavailable = &protocols.ErrorResponse{Code: "horizon_unavailable", Message: "Horizon server is unavailable. Please, try again later.", Status: http.StatusServiceUnavailable}
+	// SubmissionQueueFull is an error response
+	SubmissionQueueFull = &protocols.ErrorResponse{Code: "submission_queue_full", Message: "Too many transactions are waiting for submission. Please, try again later.", Status: http.StatusServiceUnavailable}
+	// NotLeader is an error response
+	NotLeader = &protocols.ErrorResponse{Code: "not_leader", Message: "Transactions are submitted by the leader server only. Please, send the request to the leader or try again later.", Status: http.StatusServiceUnavailable}
+	// SorobanRPCUnavailable is an error response
+	SorobanRPCUnavailable = &protocols.ErrorResponse{Code: "soroban_rpc_unavailable", Message: "soroban-rpc server is unavailable. Please, try again later.", Status: http.StatusServiceUnavailable}
+	// SimulationFailed is an error response
+	SimulationFailed = &protocols.ErrorResponse{Code: "simulation_failed", Message: "Contract invocation failed in simulation.", Status: http.StatusBadRequest}
 )
 
-// ErrorFromHorizonResponse checks if horizon.SubmitTransactionResponse is an error response and creates ErrorResponse for it
-func ErrorFromHorizonResponse(response horizon.SubmitTransactionResponse) *protocols.ErrorResponse {
-	if response.Ledger == nil && response.Extras != nil {
-		var txResult xdr.TransactionResult
-		txResult, err := unmarshalTransactionResult(response.Extras.ResultXdr)
+// ErrorFromHorizonError returns HorizonUnavailable if err has been returned
+// because Horizon is unavailable, SubmissionQueueFull if the transaction has
+// been rejected by submitter.Pool, NotLeader if this replica is not the
+// leader and InternalServerError otherwise
+func ErrorFromHorizonError(err error) *protocols.ErrorResponse {
+	switch errors.Cause(err) {
+	case horizon.ErrUnavailable:
+		return HorizonUnavailable
+	case submitter.ErrQueueFull:
+		return SubmissionQueueFull
+	case submitter.ErrNotLeader:
+		return NotLeader
+	}
+	return protocols.InternalServerError
+}
+
+// errorResponses are error responses with codes returned by
+// horizon.SubmitTransactionResponse.ErrorCode
+var errorResponses = map[string]*protocols.ErrorResponse{}
 
-		if err != nil {
-			return protocols.NewInternalServerError(
-				"Error decoding xdr.TransactionResult",
-				map[string]interface{}{"err": err},
-			)
-		}
+func init() {
+	for _, errorResponse := range []*protocols.ErrorResponse{
+		TransactionBadSequence,
+		TransactionBadAuth,
+		TransactionInsufficientBalance,
+		TransactionNoAccount,
+		TransactionInsufficientFee,
+		TransactionBadAuthExtra,
+		AllowTrustMalformed,
+		AllowTrustNoTrustline,
+		AllowTrustTrustNotRequired,
+		AllowTrustCantRevoke,
+		PaymentMalformed,
+		PaymentUnderfunded,
+		PaymentSrcNoTrust,
+		PaymentSrcNotAuthorized,
+		PaymentNoDestination,
+		PaymentNoTrust,
+		PaymentNotAuthorized,
+		PaymentLineFull,
+		PaymentNoIssuer,
+		PaymentTooFewOffers,
+		PaymentOfferCrossSelf,
+		PaymentOverSendmax,
+		PaymentLowReserve,
+		PaymentAlreadyExists,
+	} {
+		errorResponses[errorResponse.Code] = errorResponse
+	}
+}
 
-		transactionResult := txResult.Result.Code
-		var operationsResult *xdr.OperationResult
-		if txResult.Result.Results != nil {
-			operationsResultsSlice := *txResult.Result.Results
-			if len(operationsResultsSlice) > 0 {
-				operationsResult = &operationsResultsSlice[0]
-			}
-		}
+// ErrorFromHorizonResponse checks if horizon.SubmitTransactionResponse is an error response and creates ErrorResponse for it
+func ErrorFromHorizonResponse(response horizon.SubmitTransactionResponse) *protocols.ErrorResponse {
+	resultCodes, err := response.ResultCodes()
+	if err != nil {
+		return protocols.NewInternalServerError(
+			"Error decoding xdr.TransactionResult",
+			map[string]interface{}{"err": err},
+		)
+	}
 
-		if transactionResult != xdr.TransactionResultCodeTxSuccess &&
-			transactionResult != xdr.TransactionResultCodeTxFailed {
-			switch transactionResult {
-			case xdr.TransactionResultCodeTxBadSeq:
-				return TransactionBadSequence
-			case xdr.TransactionResultCodeTxBadAuth:
-				return TransactionBadAuth
-			case xdr.TransactionResultCodeTxInsufficientBalance:
-				return TransactionInsufficientBalance
-			case xdr.TransactionResultCodeTxNoAccount:
-				return TransactionNoAccount
-			case xdr.TransactionResultCodeTxInsufficientFee:
-				return TransactionInsufficientFee
-			case xdr.TransactionResultCodeTxBadAuthExtra:
-				return TransactionBadAuthExtra
-			default:
-				return protocols.InternalServerError
-			}
-		} else if operationsResult != nil {
-			if operationsResult.Tr.AllowTrustResult != nil {
-				switch operationsResult.Tr.AllowTrustResult.Code {
-				case xdr.AllowTrustResultCodeAllowTrustMalformed:
-					return AllowTrustMalformed
-				case xdr.AllowTrustResultCodeAllowTrustNoTrustLine:
-					return AllowTrustNoTrustline
-				case xdr.AllowTrustResultCodeAllowTrustTrustNotRequired:
-					return AllowTrustTrustNotRequired
-				case xdr.AllowTrustResultCodeAllowTrustCantRevoke:
-					return AllowTrustCantRevoke
-				default:
-					return protocols.InternalServerError
-				}
-			} else if operationsResult.Tr.PaymentResult != nil {
-				switch operationsResult.Tr.PaymentResult.Code {
-				case xdr.PaymentResultCodePaymentMalformed:
-					return PaymentMalformed
-				case xdr.PaymentResultCodePaymentUnderfunded:
-					return PaymentUnderfunded
-				case xdr.PaymentResultCodePaymentSrcNoTrust:
-					return PaymentSrcNoTrust
-				case xdr.PaymentResultCodePaymentSrcNotAuthorized:
-					return PaymentSrcNotAuthorized
-				case xdr.PaymentResultCodePaymentNoDestination:
-					return PaymentNoDestination
-				case xdr.PaymentResultCodePaymentNoTrust:
-					return PaymentNoTrust
-				case xdr.PaymentResultCodePaymentNotAuthorized:
-					return PaymentNotAuthorized
-				case xdr.PaymentResultCodePaymentLineFull:
-					return PaymentLineFull
-				case xdr.PaymentResultCodePaymentNoIssuer:
-					return PaymentNoIssuer
-				default:
-					return protocols.InternalServerError
-				}
-			} else if operationsResult.Tr.PathPaymentResult != nil {
-				switch operationsResult.Tr.PathPaymentResult.Code {
-				case xdr.PathPaymentResultCodePathPaymentMalformed:
-					return PaymentMalformed
-				case xdr.PathPaymentResultCodePathPaymentUnderfunded:
-					return PaymentUnderfunded
-				case xdr.PathPaymentResultCodePathPaymentSrcNoTrust:
-					return PaymentSrcNoTrust
-				case xdr.PathPaymentResultCodePathPaymentSrcNotAuthorized:
-					return PaymentSrcNotAuthorized
-				case xdr.PathPaymentResultCodePathPaymentNoDestination:
-					return PaymentNoDestination
-				case xdr.PathPaymentResultCodePathPaymentNoTrust:
-					return PaymentNoTrust
-				case xdr.PathPaymentResultCodePathPaymentNotAuthorized:
-					return PaymentNotAuthorized
-				case xdr.PathPaymentResultCodePathPaymentLineFull:
-					return PaymentLineFull
-				case xdr.PathPaymentResultCodePathPaymentNoIssuer:
-					return PaymentNoIssuer
-				case xdr.PathPaymentResultCodePathPaymentTooFewOffers:
-					return PaymentTooFewOffers
-				case xdr.PathPaymentResultCodePathPaymentOfferCrossSelf:
-					return PaymentOfferCrossSelf
-				case xdr.PathPaymentResultCodePathPaymentOverSendmax:
-					return PaymentOverSendmax
-				default:
-					return protocols.InternalServerError
-				}
-			}
-		} else {
-			return protocols.InternalServerError
-		}
+	if resultCodes == nil {
+		return nil
 	}
 
-	return nil
-}
+	errorResponse, ok := errorResponses[response.ErrorCode()]
+	if !ok {
+		return protocols.NewInternalServerError(
+			"Unknown transaction result codes",
+			map[string]interface{}{
+				"transaction": resultCodes.TransactionCode,
+				"operations":  resultCodes.OperationCodes,
+			},
+		)
+	}
 
-func unmarshalTransactionResult(transactionResult string) (txResult xdr.TransactionResult, err error) {
-	reader := strings.NewReader(transactionResult)
-	b64r := base64.NewDecoder(base64.StdEncoding, reader)
-	_, err = xdr.Unmarshal(b64r, &txResult)
-	return
+	return errorResponse
 }
@@ -0,0 +1,140 @@
+package bridge
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+var (
+	// ExpectedPaymentExists is an error response
+	ExpectedPaymentExists = &protocols.ErrorResponse{Code: "expected_payment_exists", Message: "Expected payment with this ID already exists.", Status: http.StatusConflict}
+	// ExpectedPaymentNotFound is an error response
+	ExpectedPaymentNotFound = &protocols.ErrorResponse{Code: "expected_payment_not_found", Message: "Expected payment not found.", Status: http.StatusNotFound}
+	// ExpectedPaymentNotOpen is an error response
+	ExpectedPaymentNotOpen = &protocols.ErrorResponse{Code: "expected_payment_not_open", Message: "Expected payment is paid or cancelled.", Status: http.StatusConflict}
+	// PaymentMatchNotFound is an error response
+	PaymentMatchNotFound = &protocols.ErrorResponse{Code: "received_payment_not_found", Message: "Received payment not found.", Status: http.StatusNotFound}
+	// PaymentAlreadyMatched is an error response
+	PaymentAlreadyMatched = &protocols.ErrorResponse{Code: "payment_already_matched", Message: "Received payment has been matched already.", Status: http.StatusConflict}
+)
+
+// expectedPaymentID matches IDs of expected payments, ex. invoice-2018-001
+var expectedPaymentID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,63}$`)
+
+// ExpectedPaymentRequest represents request made to /expected-payments
+// endpoint of bridge server. Received payments of the asset with the memo are
+// matched with the expected payment.
+type ExpectedPaymentRequest struct {
+	// ID is chosen by the caller (ex. an invoice number)
+	ID          string `name:"id" required:""`
+	Amount      string `name:"amount" required:""`
+	AssetCode   string `name:"asset_code" required:""`
+	AssetIssuer string `name:"asset_issuer"`
+	MemoType    string `name:"memo_type" required:""`
+	// Memo of hash and return types is hex or base64 encoded
+	Memo string `name:"memo" required:""`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *ExpectedPaymentRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *ExpectedPaymentRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+// Hash memos are converted to base64 and leading zeros of id memos are
+// removed, so they are equal to memos of received payments.
+func (request *ExpectedPaymentRequest) Validate() error {
+	err := request.FormRequest.CheckRequired(request)
+	if err != nil {
+		return err
+	}
+
+	if !expectedPaymentID.MatchString(request.ID) {
+		return protocols.NewInvalidParameterError("id", request.ID, "ID can contain letters, digits, `.`, `_`, `:` and `-` and be at most 64 characters long.")
+	}
+
+	amount, err := amounts.ParseAmount(request.Amount)
+	if err != nil || amount <= 0 {
+		return protocols.NewInvalidParameterError("amount", request.Amount, "Amount must be greater than zero.")
+	}
+
+	err = validateAsset(request.AssetCode, request.AssetIssuer)
+	if err != nil {
+		return err
+	}
+
+	request.Memo, err = normalizeMemo(request.MemoType, request.Memo)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateAsset checks asset_code and asset_issuer params, native asset is
+// XLM without issuer
+func validateAsset(code, issuer string) error {
+	if code == "XLM" && issuer == "" {
+		return nil
+	}
+	if issuer == "" {
+		return protocols.NewMissingParameter("asset_issuer")
+	}
+	asset := protocols.Asset{Code: code, Issuer: issuer}
+	if !asset.Validate() {
+		return protocols.NewInvalidParameterError("asset", asset.String(), "Invalid asset.")
+	}
+	return nil
+}
+
+// normalizeMemo checks memo_type and memo params and returns memo in the
+// format of memos of received payments: hash memos are converted to base64
+// and leading zeros of id memos are removed
+func normalizeMemo(memoType, memo string) (string, error) {
+	switch memoType {
+	case "id":
+		id, err := strconv.ParseUint(memo, 10, 64)
+		if err != nil {
+			return "", protocols.NewInvalidParameterError("memo", memo, "Memo.id must be a number.")
+		}
+		return strconv.FormatUint(id, 10), nil
+	case "text":
+		if len(memo) > MaxTextMemoLength {
+			return "", protocols.NewInvalidParameterError("memo", memo, "Memo.text can be at most "+strconv.Itoa(MaxTextMemoLength)+" bytes long.")
+		}
+		return memo, nil
+	case "hash", "return":
+		hash, ok := base64Hash(memo)
+		if !ok {
+			return "", protocols.NewInvalidParameterError("memo", memo, "Memo must be 32 bytes, hex or base64 encoded.")
+		}
+		return hash, nil
+	}
+	return "", protocols.NewInvalidParameterError("memo_type", memoType, "Memo type must be one of: id, text, hash, return.")
+}
+
+// base64Hash returns a hex or base64 encoded 32 bytes hash base64 encoded
+func base64Hash(value string) (string, bool) {
+	hash, err := hex.DecodeString(value)
+	if err != nil {
+		hash, err = base64.StdEncoding.DecodeString(value)
+	}
+	if err != nil || len(hash) != 32 {
+		return "", false
+	}
+	return base64.StdEncoding.EncodeToString(hash), true
+}
@@ -0,0 +1,14 @@
+package bridge
+
+import (
+	"net/http"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+var (
+	// FailedCallbackNotFound is an error response
+	FailedCallbackNotFound = &protocols.ErrorResponse{Code: "failed_callback_not_found", Message: "Failed callback of the received payment not found.", Status: http.StatusNotFound}
+	// FailedCallbackNotFailed is an error response
+	FailedCallbackNotFailed = &protocols.ErrorResponse{Code: "failed_callback_not_failed", Message: "Callback has been delivered or discarded already.", Status: http.StatusConflict}
+)
@@ -0,0 +1,39 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+// FeatureFlagRequest represents request made to /admin/feature-flags endpoint of bridge server
+type FeatureFlagRequest struct {
+	Name string `name:"name" required:""`
+	// Tenant the flag is set for. The flag is set for all tenants when empty.
+	Tenant    string `name:"tenant"`
+	Enabled   bool   `name:"enabled"`
+	UpdatedBy string `name:"updated_by" required:""`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *FeatureFlagRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *FeatureFlagRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *FeatureFlagRequest) Validate() error {
+	err := request.FormRequest.CheckRequired(request)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
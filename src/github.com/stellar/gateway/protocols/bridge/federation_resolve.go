@@ -0,0 +1,62 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/go/address"
+)
+
+// Sources of federation responses
+const (
+	// FederationSourceCache is a response served from bridge server cache
+	FederationSourceCache = "cache"
+	// FederationSourceRemote is a response returned by federation server of the domain
+	FederationSourceRemote = "remote"
+)
+
+// FederationResolveRequest represents request made to /federation/resolve endpoint of bridge server
+type FederationResolveRequest struct {
+	Address string
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *FederationResolveRequest) FromRequest(r *http.Request) {
+	request.Address = r.URL.Query().Get("address")
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *FederationResolveRequest) Validate() error {
+	if request.Address == "" {
+		return protocols.NewMissingParameter("address")
+	}
+
+	_, _, err := address.Split(request.Address)
+	if err != nil {
+		return protocols.NewInvalidParameterError("address", request.Address, "Address must be a Stellar address (ex. name*domain.com).")
+	}
+
+	return nil
+}
+
+// FederationResolveResponse represents a response returned by /federation/resolve endpoint
+type FederationResolveResponse struct {
+	Address   string `json:"address"`
+	AccountID string `json:"account_id"`
+	MemoType  string `json:"memo_type,omitempty"`
+	Memo      string `json:"memo,omitempty"`
+	// Source is FederationSourceCache or FederationSourceRemote
+	Source string `json:"source"`
+}
+
+// HTTPStatus returns http status
+func (response *FederationResolveResponse) HTTPStatus() int {
+	return http.StatusOK
+}
+
+// Marshal marshals FederationResolveResponse
+func (response *FederationResolveResponse) Marshal() []byte {
+	json, _ := json.MarshalIndent(response, "", "  ")
+	return json
+}
@@ -0,0 +1,32 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HeldPaymentResponse represents a response returned by /payment endpoint when
+// payment is held during settlement window or until it's approved, by
+// DELETE /payments/{id} and POST /admin/payments/{id}/approve endpoints
+type HeldPaymentResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// SettleAt is set when a payment pending approval is approved
+	SettleAt time.Time `json:"settle_at"`
+}
+
+// HTTPStatus returns http.StatusAccepted for held and pending payments and
+// http.StatusOK otherwise
+func (response *HeldPaymentResponse) HTTPStatus() int {
+	if response.Status == "held" || response.Status == "pending" {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
+// Marshal marshals HeldPaymentResponse
+func (response *HeldPaymentResponse) Marshal() []byte {
+	json, _ := json.MarshalIndent(response, "", "  ")
+	return json
+}
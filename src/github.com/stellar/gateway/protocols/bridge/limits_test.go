@@ -0,0 +1,278 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/protocols"
+	b "github.com/stellar/go/build"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSource      = "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5"
+	testDestination = "GCOEGO43PFSLE4K7WRZQNRO3PIOTRLKRASP32W7DSPBF65XFT4V6PSV3"
+	testSigner      = "SABY7FRMMJWPBTKQQ2ZN43AUJQ3Z2ZAK36VYSG2SPE2ABNQXA66H5E5G"
+)
+
+func newPaymentRequest(t testing.TB, values url.Values) (*PaymentRequest, error) {
+	r := httptest.NewRequest("POST", "/payment", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	request := &PaymentRequest{}
+	err := request.FromRequest(r)
+	require.NoError(t, err)
+	return request, request.Validate()
+}
+
+func TestPaymentRequestLimits(t *testing.T) {
+	values := url.Values{
+		"destination": {testDestination},
+		"amount":      {"10"},
+	}
+	for i := 0; i <= protocols.MaxPathLength; i++ {
+		values.Set(fmt.Sprintf("path[%d][asset_code]", i), "")
+		values.Set(fmt.Sprintf("path[%d][asset_issuer]", i), "")
+	}
+
+	request, err := newPaymentRequest(t, values)
+	require.Error(t, err)
+	assert.Len(t, request.Path, protocols.MaxPathLength+1)
+	assert.Equal(t, "path", err.(*protocols.ErrorResponse).Data["name"])
+
+	values.Del(fmt.Sprintf("path[%d][asset_code]", protocols.MaxPathLength))
+	_, err = newPaymentRequest(t, values)
+	assert.NoError(t, err)
+
+	for _, field := range []string{"memo", "extra_memo", "sender_info", "receiver_info", "note"} {
+		invalid := url.Values{}
+		for key, value := range values {
+			invalid[key] = value
+		}
+		invalid.Set(field, strings.Repeat("a", protocols.MaxMetadataSize+1))
+
+		_, err = newPaymentRequest(t, invalid)
+		require.Error(t, err, field)
+		errorResponse := err.(*protocols.ErrorResponse)
+		assert.Equal(t, http.StatusBadRequest, errorResponse.Status)
+		assert.Equal(t, field, errorResponse.Data["name"])
+		// Large values are not logged
+		assert.Equal(t, "", errorResponse.LogData["value"])
+	}
+
+	values.Set("memo_type", "text")
+	values.Set("memo", strings.Repeat("a", MaxTextMemoLength+1))
+	_, err = newPaymentRequest(t, values)
+	assert.Error(t, err)
+}
+
+func TestBuilderRequestLimits(t *testing.T) {
+	operation := Operation{
+		Type:    OperationTypePayment,
+		RawBody: json.RawMessage(`{"destination": "` + testDestination + `", "amount": "1"}`),
+	}
+
+	request := BuilderRequest{Source: testSource}
+	for i := 0; i < protocols.MaxOperations; i++ {
+		request.Operations = append(request.Operations, operation)
+	}
+	require.NoError(t, request.Process())
+	require.NoError(t, request.Validate())
+
+	request.Operations = append(request.Operations, operation)
+	err := request.Process()
+	require.Error(t, err)
+	assert.Equal(t, "operations", err.(*protocols.ErrorResponse).Data["name"])
+
+	request = BuilderRequest{Source: testSource}
+	for i := 0; i <= protocols.MaxSignatures; i++ {
+		request.Signers = append(request.Signers, testSigner)
+	}
+	err = request.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "signers", err.(*protocols.ErrorResponse).Data["name"])
+
+	pathPayment := PathPaymentOperationBody{
+		SendMax:           "10",
+		Destination:       testDestination,
+		DestinationAmount: "1",
+		Path:              make([]protocols.Asset, protocols.MaxPathLength+1),
+	}
+	err = pathPayment.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "path", err.(*protocols.ErrorResponse).Data["name"])
+
+	homeDomain := strings.Repeat("a", 33)
+	err = SetOptionsOperationBody{HomeDomain: &homeDomain}.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "home_domain", err.(*protocols.ErrorResponse).Data["name"])
+}
+
+func FuzzPaymentRequest(f *testing.F) {
+	f.Add("destination=" + testDestination + "&amount=10&memo_type=text&memo=deposit")
+	f.Add("destination=" + testDestination + "&amount=10&path[0][asset_code]=USD&path[0][asset_issuer]=" + testSource)
+	f.Add("destination=bob*stellar.org&amount=1&extra_memo=note&sender_info={}&forward_destination[domain]=stellar.org&forward_destination[fields][acct]=1")
+	f.Add("amount=1&memo_type=hash&memo=" + strings.Repeat("ab", 32))
+
+	f.Fuzz(func(t *testing.T, body string) {
+		r := httptest.NewRequest("POST", "/payment", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		request := &PaymentRequest{}
+		if request.FromRequest(r) != nil {
+			return
+		}
+		assert.True(t, len(request.Path) <= protocols.MaxPathLength+1)
+
+		if request.Validate() != nil {
+			return
+		}
+
+		assert.True(t, len(request.Path) <= protocols.MaxPathLength)
+		assert.True(t, len(request.Memo) <= protocols.MaxMemoLength)
+		for _, value := range []string{request.ExtraMemo, request.SenderInfo, request.ReceiverInfo, request.Note} {
+			assert.True(t, len(value) <= protocols.MaxMetadataSize)
+		}
+	})
+}
+
+func FuzzBuilderRequest(f *testing.F) {
+	f.Add(`{"source": "` + testSource + `", "sequence_number": "1", "operations": [{"type": "payment", "body": {"destination": "` + testDestination + `", "amount": "1"}}], "signers": ["` + testSigner + `"]}`)
+	f.Add(`{"source": "` + testSource + `", "operations": [{"type": "path_payment", "body": {"destination": "` + testDestination + `", "send_max": "1", "destination_amount": "1", "path": [{}]}}]}`)
+	f.Add(`{"source": "` + testSource + `", "operations": [{"type": "manage_data", "body": {"name": "a", "data": "YQ=="}}, {"type": "set_options", "body": {"home_domain": "stellar.org", "set_flags": [1]}}]}`)
+	f.Add(`{"source": "` + testSource + `", "operations": [{"type": "manage_offer", "body": {"selling": {}, "buying": {}, "amount": "1", "price": "1"}}]}`)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		var request BuilderRequest
+		if json.Unmarshal([]byte(body), &request) != nil {
+			return
+		}
+		if request.Process() != nil || request.Validate() != nil {
+			return
+		}
+
+		assert.True(t, len(request.Operations) <= protocols.MaxOperations)
+		assert.True(t, len(request.Signers) <= protocols.MaxSignatures)
+
+		// Building a transaction of a valid request must not panic
+		mutators := []b.TransactionMutator{
+			b.SourceAccount{request.Source},
+			b.Sequence{1},
+			b.TestNetwork,
+		}
+		for _, operation := range request.Operations {
+			mutators = append(mutators, operation.Body.ToTransactionMutator())
+		}
+		b.Transaction(mutators...)
+	})
+}
+
+func TestPaymentRequestAmounts(t *testing.T) {
+	for _, test := range []struct {
+		amount, sendMax string
+		code, name      string
+	}{
+		{amount: "10"},
+		{amount: "0.0000001", sendMax: "1"},
+		{amount: "0", code: "amount_zero", name: "amount"},
+		{amount: "0.0000000", code: "amount_zero", name: "amount"},
+		{amount: "-10", code: "amount_negative", name: "amount"},
+		{amount: "10", sendMax: "0", code: "amount_zero", name: "send_max"},
+		{amount: "10", sendMax: "-1.5", code: "amount_negative", name: "send_max"},
+		{amount: "--1", code: "invalid_parameter", name: "amount"},
+		{amount: "abc", code: "invalid_parameter", name: "amount"},
+		{amount: "1e-8", code: "invalid_parameter", name: "amount"},
+		{amount: "1e12", code: "invalid_parameter", name: "amount"},
+	} {
+		values := url.Values{"destination": {testDestination}, "amount": {test.amount}}
+		if test.sendMax != "" {
+			values.Set("send_max", test.sendMax)
+		}
+
+		_, err := newPaymentRequest(t, values)
+		if test.code == "" {
+			assert.NoError(t, err, test.amount)
+			continue
+		}
+		require.Error(t, err, test.amount)
+		assert.Equal(t, test.code, err.(*protocols.ErrorResponse).Code, test.amount)
+		assert.Equal(t, test.name, err.(*protocols.ErrorResponse).Data["name"], test.amount)
+	}
+}
+
+func TestPaymentRequestAmountsNormalized(t *testing.T) {
+	request, err := newPaymentRequest(t, url.Values{
+		"destination": {testDestination},
+		"amount":      {"1.5e1"},
+		"send_max":    {"20.25"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "15.0000000", request.Amount)
+	assert.Equal(t, "20.2500000", request.SendMax)
+}
+
+func TestPaymentRequestValidateAsset(t *testing.T) {
+	assets := []config.Asset{{Code: "XLM"}, {Code: "USD", Issuer: testSource}}
+
+	request, err := newPaymentRequest(t, url.Values{"destination": {testDestination}, "amount": {"10"}})
+	require.NoError(t, err)
+	assert.NoError(t, request.ValidateAsset(assets))
+
+	request.AssetCode, request.AssetIssuer = "USD", testSource
+	assert.NoError(t, request.ValidateAsset(assets))
+
+	request.AssetIssuer = testDestination
+	err = request.ValidateAsset(assets)
+	require.Error(t, err)
+	assert.Equal(t, "asset_code_not_allowed", err.(*protocols.ErrorResponse).Code)
+	assert.Equal(t, "asset_code", err.(*protocols.ErrorResponse).Data["name"])
+
+	// Send asset of a path payment must be allowed too
+	request.AssetIssuer = testSource
+	request.SendMax, request.SendAssetCode, request.SendAssetIssuer = "10", "EUR", testSource
+	err = request.ValidateAsset(assets)
+	require.Error(t, err)
+	assert.Equal(t, "send_asset_code", err.(*protocols.ErrorResponse).Data["name"])
+
+	asset, ok := request.AssetConfig(assets)
+	assert.True(t, ok)
+	assert.Equal(t, assets[1], asset)
+}
+
+func TestPaymentRequestValidateAmount(t *testing.T) {
+	limits := []AmountLimits{
+		{AssetCode: "XLM", MinAmount: "1"},
+		{AssetCode: "USD", AssetIssuer: testSource, MinAmount: "1", MaxAmount: "1000", Step: "0.01"},
+	}
+
+	for _, test := range []struct {
+		assetCode   string
+		assetIssuer string
+		amount      string
+		code        string
+	}{
+		{"", "", "0.5", "amount_below_minimum"},
+		{"", "", "5000", ""},
+		{"USD", testSource, "0.5", "amount_below_minimum"},
+		{"USD", testSource, "1000.01", "amount_above_maximum"},
+		{"USD", testSource, "10.005", "amount_invalid_step"},
+		{"USD", testSource, "10.05", ""},
+		{"EUR", testSource, "0.5", ""},
+	} {
+		request := &PaymentRequest{AssetCode: test.assetCode, AssetIssuer: test.assetIssuer, Amount: test.amount}
+		err := request.ValidateAmount(limits)
+		if test.code == "" {
+			assert.NoError(t, err, test.amount)
+			continue
+		}
+		require.Error(t, err, test.amount)
+		assert.Equal(t, test.code, err.(*protocols.ErrorResponse).Code, test.amount)
+	}
+}
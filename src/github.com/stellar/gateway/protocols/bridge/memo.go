@@ -0,0 +1,27 @@
+package bridge
+
+import (
+	"encoding/hex"
+	"strconv"
+)
+
+// MaxTextMemoLength is a max length of text memo in bytes
+const MaxTextMemoLength = 28
+
+// InferMemoType returns type of a memo sent without memo_type: id when memo
+// is a uint64 number without leading zeros, hash when memo is 32 bytes hex
+// encoded and text otherwise. ok is false when the memo is too long to be
+// sent as text.
+func InferMemoType(memo string) (memoType string, ok bool) {
+	if _, err := strconv.ParseUint(memo, 10, 64); err == nil && (memo == "0" || memo[0] != '0') {
+		return "id", true
+	}
+
+	if len(memo) == 64 {
+		if _, err := hex.DecodeString(memo); err == nil {
+			return "hash", true
+		}
+	}
+
+	return "text", len(memo) <= MaxTextMemoLength
+}
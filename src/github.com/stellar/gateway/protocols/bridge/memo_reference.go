@@ -0,0 +1,37 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/references"
+)
+
+// MemoReferenceRequest represents request made to /memo-references endpoint
+// of bridge server
+type MemoReferenceRequest struct {
+	Reference string `name:"reference" required:""`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *MemoReferenceRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *MemoReferenceRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *MemoReferenceRequest) Validate() error {
+	err := request.FormRequest.CheckRequired(request)
+	if err != nil {
+		return err
+	}
+
+	return protocols.CheckSize("reference", request.Reference, references.MaxLength)
+}
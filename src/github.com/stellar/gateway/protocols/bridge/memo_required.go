@@ -0,0 +1,48 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+var (
+	// MemoRequiredDestinationNotFound is an error response
+	MemoRequiredDestinationNotFound = &protocols.ErrorResponse{Code: "memo_required_destination_not_found", Message: "Destination not found in memo required destinations.", Status: http.StatusNotFound}
+)
+
+// MemoRequiredDestinationRequest represents request made to /admin/memo-required-destinations endpoint of bridge server
+type MemoRequiredDestinationRequest struct {
+	AccountID string `name:"account_id" required:""`
+	Reason    string `name:"reason"`
+	// Profile is a name of a destination profile payments to the destination
+	// are checked against, see GET /admin/destination-profiles
+	Profile string `name:"profile"`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *MemoRequiredDestinationRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *MemoRequiredDestinationRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *MemoRequiredDestinationRequest) Validate() error {
+	err := request.FormRequest.CheckRequired(request)
+	if err != nil {
+		return err
+	}
+
+	if !protocols.IsValidAccountID(request.AccountID) {
+		return protocols.NewInvalidParameterError("account_id", request.AccountID, "Account ID must be a valid Stellar public key.")
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferMemoType(t *testing.T) {
+	tests := []struct {
+		memo     string
+		memoType string
+		ok       bool
+	}{
+		{"0", "id", true},
+		{"123", "id", true},
+		{"18446744073709551615", "id", true},
+		{"18446744073709551616", "text", true},
+		{"0123", "text", true},
+		{"-1", "text", true},
+		{strings.Repeat("ab", 32), "hash", true},
+		{strings.Repeat("zz", 32), "text", false},
+		{"deposit 12", "text", true},
+		{strings.Repeat("a", 28), "text", true},
+		{strings.Repeat("a", 29), "text", false},
+		// Multibyte characters count as more than one byte
+		{strings.Repeat("ą", 15), "text", false},
+	}
+
+	for _, test := range tests {
+		memoType, ok := InferMemoType(test.memo)
+		assert.Equal(t, test.memoType, memoType, test.memo)
+		assert.Equal(t, test.ok, ok, test.memo)
+	}
+}
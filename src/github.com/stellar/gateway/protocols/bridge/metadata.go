@@ -0,0 +1,80 @@
+package bridge
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+// Encodings of payment metadata on the ledger
+const (
+	// MetadataEncodingManageData sets every key/value as a data entry of the
+	// source account in the payment transaction
+	MetadataEncodingManageData = "manage_data"
+	// MetadataEncodingMemo sends url encoded metadata as a text memo
+	MetadataEncodingMemo = "memo"
+)
+
+// Limits of payment metadata
+const (
+	// MaxMetadataKeys is a max number of metadata keys of a payment
+	MaxMetadataKeys = 20
+	// MaxMetadataValueLength is a max length of a metadata value in bytes
+	MaxMetadataValueLength = 256
+	// MaxManageDataLength is a max length of a name or value of a data entry
+	// in bytes (Stellar protocol limit)
+	MaxManageDataLength = 64
+)
+
+var metadataKey = regexp.MustCompile("^[A-Za-z0-9_.:-]{1,64}$")
+
+// validateMetadata checks keys and values of metadata and that they can be
+// encoded on the ledger using encoding
+func validateMetadata(metadata map[string]string, encoding string) error {
+	if len(metadata) > MaxMetadataKeys {
+		return protocols.NewInvalidParameterError("metadata", strconv.Itoa(len(metadata)), "Metadata can contain at most "+strconv.Itoa(MaxMetadataKeys)+" keys.")
+	}
+
+	for key, value := range metadata {
+		if !metadataKey.MatchString(key) {
+			return protocols.NewInvalidParameterError("metadata", key, "Metadata keys can contain only letters, digits, `_`, `.`, `:` and `-`, at most 64 characters.")
+		}
+
+		err := protocols.CheckSize("metadata["+key+"]", value, MaxMetadataValueLength)
+		if err != nil {
+			return err
+		}
+
+		if encoding == MetadataEncodingManageData && (value == "" || len(value) > MaxManageDataLength) {
+			return protocols.NewInvalidParameterError("metadata["+key+"]", value, "Values encoded as data entries must be between 1 and "+strconv.Itoa(MaxManageDataLength)+" bytes long.")
+		}
+	}
+
+	switch encoding {
+	case "", MetadataEncodingManageData:
+	case MetadataEncodingMemo:
+		if len(MetadataMemo(metadata)) > MaxTextMemoLength {
+			return protocols.NewInvalidParameterError("metadata_encoding", encoding, "Metadata is too large to be sent as a memo, url encoded metadata can be at most "+strconv.Itoa(MaxTextMemoLength)+" bytes long.")
+		}
+	default:
+		return protocols.NewInvalidParameterError("metadata_encoding", encoding, "Metadata encoding must be manage_data or memo.")
+	}
+
+	if encoding != "" && len(metadata) == 0 {
+		return protocols.NewMissingParameter("metadata")
+	}
+
+	return nil
+}
+
+// MetadataMemo returns metadata url encoded (keys are sorted), the value of
+// the text memo of memo encoding
+func MetadataMemo(metadata map[string]string) string {
+	values := url.Values{}
+	for key, value := range metadata {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
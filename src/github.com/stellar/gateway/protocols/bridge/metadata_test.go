@@ -0,0 +1,75 @@
+package bridge
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentRequestMetadata(t *testing.T) {
+	values := url.Values{
+		"destination":         {testDestination},
+		"amount":              {"10"},
+		"metadata[order_id]":  {"1234"},
+		"metadata[customer]":  {"jed"},
+		"metadata_encoding":   {MetadataEncodingMemo},
+		"metadata[[invalid]]": {"ignored"},
+	}
+
+	request, err := newPaymentRequest(t, values)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"order_id": "1234", "customer": "jed"}, request.Metadata)
+	assert.Equal(t, "customer=jed&order_id=1234", MetadataMemo(request.Metadata))
+
+	// Metadata survives ToValues (held payments are replayed from values)
+	assert.Equal(t, "1234", request.ToValues().Get("metadata[order_id]"))
+
+	tests := []struct {
+		key      string
+		value    string
+		encoding string
+		name     string
+	}{
+		{"order id", "1", "", "metadata"},
+		{"order_id", strings.Repeat("a", MaxMetadataValueLength+1), "", "metadata[order_id]"},
+		{"order_id", strings.Repeat("a", MaxManageDataLength+1), MetadataEncodingManageData, "metadata[order_id]"},
+		{"order_id", "", MetadataEncodingManageData, "metadata[order_id]"},
+		{"order_id", strings.Repeat("a", MaxTextMemoLength), MetadataEncodingMemo, "metadata_encoding"},
+		{"order_id", "1", "ledger", "metadata_encoding"},
+	}
+
+	for _, test := range tests {
+		_, err := newPaymentRequest(t, url.Values{
+			"destination":                {testDestination},
+			"amount":                     {"10"},
+			"metadata[" + test.key + "]": {test.value},
+			"metadata_encoding":          {test.encoding},
+		})
+		require.Error(t, err, test.key+"="+test.value)
+		assert.Equal(t, test.name, err.(*protocols.ErrorResponse).Data["name"], test.key+"="+test.value)
+	}
+
+	// Encoding requires metadata
+	_, err = newPaymentRequest(t, url.Values{
+		"destination":       {testDestination},
+		"amount":            {"10"},
+		"metadata_encoding": {MetadataEncodingManageData},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "metadata", err.(*protocols.ErrorResponse).Data["name"])
+
+	// Memo encoding cannot be used with a memo
+	_, err = newPaymentRequest(t, url.Values{
+		"destination":        {testDestination},
+		"amount":             {"10"},
+		"memo_type":          {"id"},
+		"memo":               {"1"},
+		"metadata[order_id]": {"1"},
+		"metadata_encoding":  {MetadataEncodingMemo},
+	})
+	assert.Error(t, err)
+}
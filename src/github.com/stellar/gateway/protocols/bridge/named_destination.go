@@ -0,0 +1,100 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/go/address"
+)
+
+var (
+	// NamedDestinationNotFound is an error response
+	NamedDestinationNotFound = &protocols.ErrorResponse{Code: "named_destination_not_found", Message: "Destination name not found in the address book.", Status: http.StatusNotFound}
+)
+
+// namedDestinationName matches names of named destinations, ex. acme-settlement
+var namedDestinationName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// NamedDestinationRequest represents request made to /admin/destinations
+// endpoint of bridge server. A destination with the same name is replaced.
+type NamedDestinationRequest struct {
+	Name string `name:"name" required:""`
+	// Destination is an account ID or a Stellar address (like bob*stellar.org)
+	Destination string `name:"destination" required:""`
+	MemoType    string `name:"memo_type"`
+	Memo        string `name:"memo"`
+	AssetCode   string `name:"asset_code"`
+	AssetIssuer string `name:"asset_issuer"`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *NamedDestinationRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *NamedDestinationRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *NamedDestinationRequest) Validate() error {
+	err := request.FormRequest.CheckRequired(request)
+	if err != nil {
+		return err
+	}
+
+	if !IsValidDestinationName(request.Name) {
+		return protocols.NewInvalidParameterError("name", request.Name, "Name can contain lowercase letters, digits, `.`, `_` and `-` and be at most 64 characters long.")
+	}
+
+	if !protocols.IsValidAccountID(request.Destination) {
+		_, _, err = address.Split(request.Destination)
+		if err != nil {
+			return protocols.NewInvalidParameterError("destination", request.Destination, "Destination must be an account ID or a Stellar address.")
+		}
+	}
+
+	switch request.MemoType {
+	case "":
+		if request.Memo != "" {
+			return protocols.NewMissingParameter("memo_type")
+		}
+	case "id", "text", "hash", "return":
+		if request.Memo == "" {
+			return protocols.NewMissingParameter("memo")
+		}
+	default:
+		return protocols.NewInvalidParameterError("memo_type", request.MemoType, "Memo type must be one of: id, text, hash, return.")
+	}
+
+	if request.MemoType == "text" && len(request.Memo) > MaxTextMemoLength {
+		return protocols.NewInvalidParameterError("memo", request.Memo, "Memo.text can be at most "+strconv.Itoa(MaxTextMemoLength)+" bytes long.")
+	}
+
+	if request.AssetCode == "" && request.AssetIssuer != "" {
+		return protocols.NewMissingParameter("asset_code")
+	}
+
+	if request.AssetCode != "" && request.AssetIssuer == "" {
+		return protocols.NewMissingParameter("asset_issuer")
+	}
+
+	asset := protocols.Asset{Code: request.AssetCode, Issuer: request.AssetIssuer}
+	if !asset.Validate() {
+		return protocols.NewInvalidParameterError("asset", asset.String(), "Invalid asset.")
+	}
+
+	return nil
+}
+
+// IsValidDestinationName returns true when name can be a name of a named
+// destination
+func IsValidDestinationName(name string) bool {
+	return namedDestinationName.MatchString(name)
+}
@@ -0,0 +1,95 @@
+package bridge
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+var (
+	// OfflineTransactionNotFound is an error response
+	OfflineTransactionNotFound = &protocols.ErrorResponse{Code: "offline_transaction_not_found", Message: "Offline transaction not found.", Status: http.StatusNotFound}
+	// OfflineTransactionExists is an error response
+	OfflineTransactionExists = &protocols.ErrorResponse{Code: "offline_transaction_exists", Message: "Offline transaction with this ID already exists.", Status: http.StatusConflict}
+	// OfflineTransactionSubmitted is an error response
+	OfflineTransactionSubmitted = &protocols.ErrorResponse{Code: "offline_transaction_submitted", Message: "Offline transaction has already been submitted.", Status: http.StatusConflict}
+	// OfflineTransactionHashMismatch is an error response
+	OfflineTransactionHashMismatch = &protocols.ErrorResponse{Code: "offline_transaction_hash_mismatch", Message: "Signed transaction is different than the built one.", Status: http.StatusBadRequest}
+)
+
+// NewOfflineTransactionMissingSignature returns an error response for a
+// signed envelope without a valid signature of a required signer
+func NewOfflineTransactionMissingSignature(signer string) *protocols.ErrorResponse {
+	return &protocols.ErrorResponse{
+		Code:    "offline_transaction_missing_signature",
+		Message: "Transaction is not signed by a required signer.",
+		Status:  http.StatusBadRequest,
+		Data:    map[string]interface{}{"signer": signer},
+	}
+}
+
+// OfflineTransactionRequest represents request made to /offline-transactions
+// endpoint of bridge server. The transaction is built like in /builder but it
+// is not signed: it's stored until the envelope signed outside of the bridge
+// server is sent to /offline-transactions/{id}/submit.
+type OfflineTransactionRequest struct {
+	// ID of the transaction, used to submit it
+	ID string `json:"id"`
+	BuilderRequest
+	// RequiredSigners are public keys that must sign the transaction,
+	// source accounts of the transaction and its operations by default
+	RequiredSigners []string `json:"required_signers"`
+}
+
+// Validate validates if the request is correct. Call it after Process.
+func (r OfflineTransactionRequest) Validate() error {
+	if r.ID == "" {
+		return protocols.NewMissingParameter("id")
+	}
+
+	if len(r.ID) > 255 {
+		return protocols.NewInvalidParameterError("id", r.ID, "ID can be at most 255 characters long.")
+	}
+
+	if len(r.Signers) > 0 {
+		return protocols.NewInvalidParameterError("signers", "", "Offline transactions are signed outside of the bridge server.")
+	}
+
+	if len(r.RequiredSigners) > protocols.MaxSignatures {
+		return protocols.NewInvalidParameterError("required_signers", strconv.Itoa(len(r.RequiredSigners)), "Transaction can contain at most "+strconv.Itoa(protocols.MaxSignatures)+" signatures.")
+	}
+
+	for i, signer := range r.RequiredSigners {
+		if !protocols.IsValidAccountID(signer) {
+			return protocols.NewInvalidParameterError("required_signers["+strconv.Itoa(i)+"]", signer, "Signer must start with `G`.")
+		}
+	}
+
+	return r.BuilderRequest.Validate()
+}
+
+// ExpectedSigners returns RequiredSigners or, when empty, source accounts of the
+// transaction and its operations without duplicates. Call it after Process.
+func (r OfflineTransactionRequest) ExpectedSigners() []string {
+	if len(r.RequiredSigners) > 0 {
+		return r.RequiredSigners
+	}
+
+	var signers []string
+	seen := map[string]bool{}
+	for _, account := range r.SourceAccounts() {
+		if !seen[account] {
+			seen[account] = true
+			signers = append(signers, account)
+		}
+	}
+	return signers
+}
+
+// OfflineTransactionSubmitRequest represents request made to
+// /offline-transactions/{id}/submit endpoint of bridge server
+type OfflineTransactionSubmitRequest struct {
+	// EnvelopeXdr is the base64 encoded signed transaction envelope
+	EnvelopeXdr string `json:"envelope_xdr"`
+}
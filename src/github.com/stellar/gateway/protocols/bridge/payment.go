@@ -3,9 +3,12 @@ package bridge
 import (
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/stellar/gateway/bridge/config"
 	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
 	callback "github.com/stellar/gateway/protocols/compliance"
 	"github.com/stellar/go/keypair"
 )
@@ -17,10 +20,59 @@ var (
 	PaymentCannotResolveDestination = &protocols.ErrorResponse{Code: "cannot_resolve_destination", Message: "Cannot resolve federated Stellar address.", Status: http.StatusBadRequest}
 	// PaymentCannotUseMemo is an error response
 	PaymentCannotUseMemo = &protocols.ErrorResponse{Code: "cannot_use_memo", Message: "Memo given in request but federation returned memo fields.", Status: http.StatusBadRequest}
+	// PaymentMemoRequired is an error response
+	PaymentMemoRequired = &protocols.ErrorResponse{Code: "memo_required", Message: "Destination requires memo. Previous payments without memo have been returned.", Status: http.StatusBadRequest}
+	// PaymentComplianceRequired is an error response
+	PaymentComplianceRequired = &protocols.ErrorResponse{Code: "compliance_required", Message: "Payments to federation addresses must be sent using compliance protocol with `sender` param.", Status: http.StatusBadRequest}
 	// PaymentSourceNotExist is an error response
 	PaymentSourceNotExist = &protocols.ErrorResponse{Code: "source_not_exist", Message: "Source account does not exist.", Status: http.StatusBadRequest}
+	// PaymentSourceSignerChanged is an error response
+	PaymentSourceSignerChanged = &protocols.ErrorResponse{Code: "source_signer_changed", Message: "Signing key is no longer a signer of source account with sufficient weight.", Status: http.StatusConflict}
 	// PaymentAssetCodeNotAllowed is an error response
 	PaymentAssetCodeNotAllowed = &protocols.ErrorResponse{Code: "asset_code_not_allowed", Message: "Given asset_code not allowed.", Status: http.StatusBadRequest}
+	// PaymentAmountBelowMinimum is an error response
+	PaymentAmountBelowMinimum = &protocols.ErrorResponse{Code: "amount_below_minimum", Message: "Amount is lower than minimum amount allowed for this asset.", Status: http.StatusBadRequest}
+	// PaymentAmountAboveMaximum is an error response
+	PaymentAmountAboveMaximum = &protocols.ErrorResponse{Code: "amount_above_maximum", Message: "Amount is greater than maximum amount allowed for this asset.", Status: http.StatusBadRequest}
+	// PaymentAmountInvalidStep is an error response
+	PaymentAmountInvalidStep = &protocols.ErrorResponse{Code: "amount_invalid_step", Message: "Amount is not a multiple of step size allowed for this asset.", Status: http.StatusBadRequest}
+	// PaymentLimitExceeded is an error response
+	PaymentLimitExceeded = &protocols.ErrorResponse{Code: "payment_limit_exceeded", Message: "Payment would exceed daily limit of amounts sent in this asset.", Status: http.StatusBadRequest}
+	// PaymentAmountZero is an error response
+	PaymentAmountZero = &protocols.ErrorResponse{Code: "amount_zero", Message: "Amount must be greater than zero.", Status: http.StatusBadRequest}
+	// PaymentAmountNegative is an error response
+	PaymentAmountNegative = &protocols.ErrorResponse{Code: "amount_negative", Message: "Amount cannot be negative.", Status: http.StatusBadRequest}
+	// PaymentSelfPayment is an error response
+	PaymentSelfPayment = &protocols.ErrorResponse{Code: "self_payment", Message: "Destination is the source account. Self payments are not allowed.", Status: http.StatusBadRequest}
+	// PaymentProfileViolation is an error response
+	PaymentProfileViolation = &protocols.ErrorResponse{Code: "destination_profile_violation", Message: "Payment does not meet requirements of the destination profile.", Status: http.StatusBadRequest}
+	// PaymentRiskDenied is an error response
+	PaymentRiskDenied = &protocols.ErrorResponse{Code: "risk_denied", Message: "Payment has been denied by a risk check.", Status: http.StatusForbidden}
+	// PaymentRiskCheckFailed is an error response
+	PaymentRiskCheckFailed = &protocols.ErrorResponse{Code: "risk_check_failed", Message: "Payment could not be checked by a risk hook. Try again later.", Status: http.StatusServiceUnavailable}
+	// PaymentFeeExceedsAmount is an error response
+	PaymentFeeExceedsAmount = &protocols.ErrorResponse{Code: "fee_exceeds_amount", Message: "Fee deducted from the payment is not lower than the amount.", Status: http.StatusBadRequest}
+	// PaymentFeeNotSupported is an error response
+	PaymentFeeNotSupported = &protocols.ErrorResponse{Code: "fee_not_supported", Message: "Fees can't be charged for payments sent using compliance protocol.", Status: http.StatusBadRequest}
+
+	// settlement
+
+	// PaymentCancelled is an error response
+	PaymentCancelled = &protocols.ErrorResponse{Code: "payment_cancelled", Message: "Payment has been cancelled during settlement window.", Status: http.StatusConflict}
+	// PaymentNotFound is an error response
+	PaymentNotFound = &protocols.ErrorResponse{Code: "payment_not_found", Message: "Held payment with given ID not found.", Status: http.StatusNotFound}
+	// PaymentCannotCancel is an error response
+	PaymentCannotCancel = &protocols.ErrorResponse{Code: "cannot_cancel", Message: "Settlement window has ended, payment cannot be cancelled.", Status: http.StatusConflict}
+	// PaymentCannotHold is an error response
+	PaymentCannotHold = &protocols.ErrorResponse{Code: "cannot_hold", Message: "Payments from accounts other than accounts.base_seed cannot be held when settlement.handoff_key is not set.", Status: http.StatusInternalServerError}
+	// PaymentNotPending is an error response
+	PaymentNotPending = &protocols.ErrorResponse{Code: "payment_not_pending", Message: "Payment is not pending approval.", Status: http.StatusConflict}
+	// PaymentApprovalSameKey is an error response
+	PaymentApprovalSameKey = &protocols.ErrorResponse{Code: "approval_same_key", Message: "Payment must be approved with an API key other than the key that sent it.", Status: http.StatusForbidden}
+	// PaymentWrongRegion is an error response
+	PaymentWrongRegion = &protocols.ErrorResponse{Code: "wrong_region", Message: "Payments from this source account are not sent by this region.", Status: http.StatusMisdirectedRequest}
+	// PaymentInProgress is an error response
+	PaymentInProgress = &protocols.ErrorResponse{Code: "payment_in_progress", Message: "Payment with given ID is being sent. Repeat your request later.", Status: http.StatusConflict}
 
 	// compliance
 
@@ -55,6 +107,10 @@ var (
 	PaymentOfferCrossSelf = &protocols.ErrorResponse{Code: "payment_offer_cross_self", Message: "would cross one of its own offers.", Status: http.StatusBadRequest}
 	// PaymentOverSendmax is an error response
 	PaymentOverSendmax = &protocols.ErrorResponse{Code: "payment_over_sendmax", Message: "Could not satisfy sendmax.", Status: http.StatusBadRequest}
+	// PaymentLowReserve is an error response
+	PaymentLowReserve = &protocols.ErrorResponse{Code: "payment_low_reserve", Message: "Amount is lower than minimum balance of a new account.", Status: http.StatusBadRequest}
+	// PaymentAlreadyExists is an error response
+	PaymentAlreadyExists = &protocols.ErrorResponse{Code: "payment_already_exists", Message: "Created account already exists.", Status: http.StatusBadRequest}
 )
 
 // PaymentRequest represents request made to /payment endpoint of the bridge server
@@ -67,6 +123,9 @@ type PaymentRequest struct {
 	Sender string `name:"sender"`
 	// Destination address (like bob*stellar.org)
 	Destination string `name:"destination"`
+	// DestinationName is a name of a destination in the address book,
+	// resolved to Destination and memo and asset defaults
+	DestinationName string `name:"destination_name"`
 	// ForwardDestination
 	ForwardDestination *protocols.ForwardDestination `name:"forward_destination"`
 	// Memo type
@@ -91,6 +150,46 @@ type PaymentRequest struct {
 	UseCompliance bool `name:"use_compliance"`
 	// Extra memo. If set, UseCompliance value will be ignored and it will use compliance.
 	ExtraMemo string `name:"extra_memo"`
+	// ID of the sender sent to fetch_info callback of compliance server
+	SenderID string `name:"sender_id"`
+	// JSON object overriding fields returned by fetch_info callback
+	SenderInfo string `name:"sender_info"`
+	// JSON object with receiver KYC info, stored with the attachment
+	ReceiverInfo string `name:"receiver_info"`
+	// Overrides route returned by federation server
+	Route string `name:"route"`
+	// Note sent in the compliance attachment
+	Note string `name:"note"`
+	// PrivateNote is sent in the compliance attachment encrypted to
+	// ENCRYPTION_KEY of the receiving organization. If set, UseCompliance
+	// value will be ignored and it will use compliance.
+	PrivateNote string `name:"private_note"`
+	// DryRun builds the transaction and returns its predicted failures
+	// instead of submitting it
+	DryRun bool `name:"dry_run"`
+	// Network is the name of an additional network the payment is sent to,
+	// the main network when empty
+	Network string `name:"network"`
+	// OrderingKey makes payments with the same key submitted in order when
+	// transactions are submitted concurrently, ex. the destination
+	OrderingKey string `name:"ordering_key"`
+	// QuoteID is an ID of a quote returned by /quote. Path payment is sent
+	// using its path and send amount as send_max.
+	QuoteID string `name:"quote_id"`
+	// Reference is an internal reference (ex. ID of an invoice) sent as a
+	// hash memo derived from it, so it's not visible on the ledger
+	Reference string `name:"reference"`
+	// Metadata are key/values sent in metadata[key] params (ex. an order
+	// ID), stored with the sent transaction and sent in its status events
+	Metadata map[string]string `name:"metadata"`
+	// MetadataEncoding puts Metadata on the ledger too: "manage_data" or
+	// "memo", not encoded when empty
+	MetadataEncoding string `name:"metadata_encoding"`
+	// InferredMemoType is set when MemoType was inferred from Memo
+	InferredMemoType string
+	// Synchronous disables async_submission feature, set for held payments
+	// released in the background
+	Synchronous bool
 
 	protocols.FormRequest
 }
@@ -122,6 +221,12 @@ func (request *PaymentRequest) ToComplianceSendRequest() callback.SendRequest {
 		SendAssetIssuer:    request.SendAssetIssuer,
 		Path:               request.Path,
 		ExtraMemo:          request.ExtraMemo,
+		SenderID:           request.SenderID,
+		SenderInfo:         request.SenderInfo,
+		ReceiverInfo:       request.ReceiverInfo,
+		Route:              request.Route,
+		Note:               request.Note,
+		PrivateNote:        request.PrivateNote,
 	}
 }
 
@@ -143,17 +248,39 @@ func (request *PaymentRequest) Validate() error {
 		return protocols.NewMissingParameter("destination")
 	}
 
-	if !protocols.IsValidAmount(request.Amount) {
-		return protocols.NewInvalidParameterError("amount", request.Amount, "Invalid amount.")
+	if request.ForwardDestination != nil {
+		err = request.ForwardDestination.Validate()
+		if err != nil {
+			return err
+		}
 	}
 
+	err = validatePositiveAmount("amount", request.Amount)
+	if err != nil {
+		return err
+	}
+	// Amounts are sent to Horizon, the compliance server and callbacks in the
+	// format used on the ledger, so they don't differ in precision
+	request.Amount = amounts.String(amounts.MustParse(request.Amount))
+
 	if request.SendMax != "" {
-		if !protocols.IsValidAmount(request.SendMax) {
-			return protocols.NewInvalidParameterError("send_max", request.SendMax, "Invalid amount.")
+		err = validatePositiveAmount("send_max", request.SendMax)
+		if err != nil {
+			return err
 		}
+		request.SendMax = amounts.String(amounts.MustParse(request.SendMax))
 	}
 
 	// Memo
+	err = protocols.CheckSize("memo", request.Memo, protocols.MaxMemoLength)
+	if err != nil {
+		return err
+	}
+
+	if request.MemoType == "text" && len(request.Memo) > MaxTextMemoLength {
+		return protocols.NewInvalidParameterError("memo", request.Memo, "Memo.text can be at most "+strconv.Itoa(MaxTextMemoLength)+" bytes long.")
+	}
+
 	if request.MemoType == "" && request.Memo != "" {
 		return protocols.NewMissingParameter("memo_type")
 	}
@@ -210,14 +337,236 @@ func (request *PaymentRequest) Validate() error {
 		}
 	}
 
+	err = protocols.CheckPathLength("path", request.Path)
+	if err != nil {
+		return err
+	}
+
+	err = validateMetadata(request.Metadata, request.MetadataEncoding)
+	if err != nil {
+		return err
+	}
+
+	if request.MetadataEncoding == MetadataEncodingMemo && request.MemoType != "" {
+		return protocols.NewInvalidParameterError("metadata_encoding", request.MetadataEncoding, "Metadata cannot be encoded in a memo of a payment with memo.")
+	}
+
+	err = protocols.CheckMetadataSize(request.ExtraMemo, request.SenderInfo, request.ReceiverInfo, request.Note)
+	if err != nil {
+		return err
+	}
+
+	err = protocols.CheckSize("private_note", request.PrivateNote, protocols.MaxMetadataSize)
+	if err != nil {
+		return err
+	}
+
+	// Compliance overrides
+	_, err = callback.ParseInfo("sender_info", request.SenderInfo)
+	if err != nil {
+		return err
+	}
+
+	_, err = callback.ParseInfo("receiver_info", request.ReceiverInfo)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AmountLimits are limits of amounts of payments of an asset (XLM with empty
+// issuer is the native asset). Empty limits are not checked.
+type AmountLimits struct {
+	AssetCode   string
+	AssetIssuer string
+	MinAmount   string
+	MaxAmount   string
+	Step        string
+}
+
+// ValidateAmount checks if amount is within limits of the destination asset.
+// It should be called after Validate.
+func (request *PaymentRequest) ValidateAmount(limits []AmountLimits) error {
+	for _, limit := range limits {
+		native := request.AssetCode == "" && limit.AssetCode == "XLM" && limit.AssetIssuer == ""
+		if !native && (limit.AssetCode != request.AssetCode || limit.AssetIssuer != request.AssetIssuer) {
+			continue
+		}
+
+		// Amount is checked in Validate
+		value := amounts.MustParse(request.Amount)
+
+		if limit.MinAmount != "" && value < amounts.MustParse(limit.MinAmount) {
+			return newAmountLimitError(PaymentAmountBelowMinimum, request.Amount, limit)
+		}
+
+		if limit.MaxAmount != "" && value > amounts.MustParse(limit.MaxAmount) {
+			return newAmountLimitError(PaymentAmountAboveMaximum, request.Amount, limit)
+		}
+
+		if limit.Step != "" && value%amounts.MustParse(limit.Step) != 0 {
+			return newAmountLimitError(PaymentAmountInvalidStep, request.Amount, limit)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAsset checks if the asset of the payment (and the send asset of a
+// path payment) is listed in assets. It's used when restrict_assets is set.
+func (request *PaymentRequest) ValidateAsset(assets []config.Asset) error {
+	_, ok := findAsset(assets, request.AssetCode, request.AssetIssuer)
+	if !ok {
+		return newAssetNotAllowedError("asset_code", request.AssetCode, request.AssetIssuer)
+	}
+
+	if request.SendMax != "" {
+		_, ok = findAsset(assets, request.SendAssetCode, request.SendAssetIssuer)
+		if !ok {
+			return newAssetNotAllowedError("send_asset_code", request.SendAssetCode, request.SendAssetIssuer)
+		}
+	}
+
 	return nil
 }
 
+// AssetConfig returns the entry of the destination asset in assets
+func (request *PaymentRequest) AssetConfig(assets []config.Asset) (config.Asset, bool) {
+	return findAsset(assets, request.AssetCode, request.AssetIssuer)
+}
+
+// NewPaymentLimitExceededError returns PaymentLimitExceeded error of a daily
+// limit (param name) of asset. remaining is the amount that can still be sent
+// today.
+func NewPaymentLimitExceededError(limit, maxAmount, remaining string, asset config.Asset) *protocols.ErrorResponse {
+	return &protocols.ErrorResponse{
+		Status:  PaymentLimitExceeded.Status,
+		Code:    PaymentLimitExceeded.Code,
+		Message: PaymentLimitExceeded.Message,
+		Data: map[string]interface{}{
+			"limit":      limit,
+			"max_amount": maxAmount,
+			"remaining":  remaining,
+		},
+		LogData: map[string]interface{}{"asset_code": asset.Code, "asset_issuer": asset.Issuer},
+	}
+}
+
+// findAsset returns the entry of asset in assets. Native asset is listed as
+// XLM without an issuer.
+func findAsset(assets []config.Asset, code, issuer string) (config.Asset, bool) {
+	for _, asset := range assets {
+		native := code == "" && asset.Code == "XLM" && asset.Issuer == ""
+		if native || (asset.Code == code && asset.Issuer == issuer) {
+			return asset, true
+		}
+	}
+	return config.Asset{}, false
+}
+
+func newAssetNotAllowedError(name, code, issuer string) *protocols.ErrorResponse {
+	return &protocols.ErrorResponse{
+		Status:  PaymentAssetCodeNotAllowed.Status,
+		Code:    PaymentAssetCodeNotAllowed.Code,
+		Message: PaymentAssetCodeNotAllowed.Message,
+		Data:    map[string]interface{}{"name": name},
+		LogData: map[string]interface{}{"asset_code": code, "asset_issuer": issuer},
+	}
+}
+
+// RequiresRecheck returns true when the amount is above recheck_amount of the
+// asset so account state must be checked again right before signing
+func (request *PaymentRequest) RequiresRecheck(assets []config.Asset) bool {
+	for _, asset := range assets {
+		native := request.AssetCode == "" && asset.Code == "XLM" && asset.Issuer == ""
+		if !native && (asset.Code != request.AssetCode || asset.Issuer != request.AssetIssuer) {
+			continue
+		}
+
+		if asset.RecheckAmount != "" && amounts.MustParse(request.Amount) > amounts.MustParse(asset.RecheckAmount) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newAmountLimitError(errorResponse *protocols.ErrorResponse, value string, limit AmountLimits) *protocols.ErrorResponse {
+	data := map[string]interface{}{}
+	if limit.MinAmount != "" {
+		data["min_amount"] = limit.MinAmount
+	}
+	if limit.MaxAmount != "" {
+		data["max_amount"] = limit.MaxAmount
+	}
+	if limit.Step != "" {
+		data["step"] = limit.Step
+	}
+
+	return &protocols.ErrorResponse{
+		Status:  errorResponse.Status,
+		Code:    errorResponse.Code,
+		Message: errorResponse.Message,
+		Data:    data,
+		LogData: map[string]interface{}{"amount": value, "asset_code": limit.AssetCode, "asset_issuer": limit.AssetIssuer},
+	}
+}
+
+// validatePositiveAmount returns distinct errors for zero and negative
+// amounts, they are otherwise rejected by Horizon as a malformed operation
+func validatePositiveAmount(name, value string) error {
+	var errorResponse *protocols.ErrorResponse
+	switch {
+	case strings.HasPrefix(value, "-") && protocols.IsValidAmount(value[1:]):
+		errorResponse = PaymentAmountNegative
+	case !protocols.IsValidAmount(value):
+		return protocols.NewInvalidParameterError(name, value, "Invalid amount.")
+	case amounts.MustParse(value) == 0:
+		errorResponse = PaymentAmountZero
+	default:
+		return nil
+	}
+
+	return &protocols.ErrorResponse{
+		Status:  errorResponse.Status,
+		Code:    errorResponse.Code,
+		Message: errorResponse.Message,
+		Data:    map[string]interface{}{"name": name},
+		LogData: map[string]interface{}{name: value},
+	}
+}
+
 func validateStellarAddress(address string) bool {
 	tokens := strings.Split(address, "*")
 	return len(tokens) == 2
 }
 
+// NewPaymentProfileViolationError creates a new PaymentProfileViolation error
+// of a profile
+func NewPaymentProfileViolationError(profile string, reason error) *protocols.ErrorResponse {
+	return &protocols.ErrorResponse{
+		Status:   PaymentProfileViolation.Status,
+		Code:     PaymentProfileViolation.Code,
+		Message:  PaymentProfileViolation.Message,
+		MoreInfo: reason.Error(),
+		Data:     map[string]interface{}{"profile": profile},
+		LogData:  map[string]interface{}{"profile": profile, "reason": reason.Error()},
+	}
+}
+
+// NewPaymentRiskDeniedError creates a new PaymentRiskDenied error of a risk
+// hook decision
+func NewPaymentRiskDeniedError(hook, reason string) *protocols.ErrorResponse {
+	return &protocols.ErrorResponse{
+		Status:   PaymentRiskDenied.Status,
+		Code:     PaymentRiskDenied.Code,
+		Message:  PaymentRiskDenied.Message,
+		MoreInfo: reason,
+		LogData:  map[string]interface{}{"hook": hook, "reason": reason},
+	}
+}
+
 // NewPaymentPendingError creates a new PaymentPending error
 func NewPaymentPendingError(seconds int) *protocols.ErrorResponse {
 	return &protocols.ErrorResponse{
@@ -0,0 +1,28 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// PaymentAcceptedResponse represents a response returned by /payment endpoint
+// when async_submission feature is enabled: the transaction has been saved and
+// is submitted in the background
+type PaymentAcceptedResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// Hash is the hash of the transaction. It changes when the transaction
+	// is resubmitted after tx_bad_seq.
+	Hash string `json:"hash"`
+}
+
+// HTTPStatus returns http.StatusAccepted
+func (response *PaymentAcceptedResponse) HTTPStatus() int {
+	return http.StatusAccepted
+}
+
+// Marshal marshals PaymentAcceptedResponse
+func (response *PaymentAcceptedResponse) Marshal() []byte {
+	json, _ := json.MarshalIndent(response, "", "  ")
+	return json
+}
@@ -0,0 +1,36 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// PaymentDryRunResponse represents a response returned by /payment endpoint
+// when dry_run param is true
+type PaymentDryRunResponse struct {
+	// Valid is false when the transaction is expected to fail
+	Valid    bool               `json:"valid"`
+	Failures []PreflightFailure `json:"failures,omitempty"`
+}
+
+// PreflightFailure is a predicted failure of a transaction
+type PreflightFailure struct {
+	// Operation is the index of the failed operation or -1 when the whole
+	// transaction is expected to fail
+	Operation int `json:"operation"`
+	// Code and Message of the error response that would be returned when the
+	// transaction was submitted
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// HTTPStatus returns http.StatusOK
+func (response *PaymentDryRunResponse) HTTPStatus() int {
+	return http.StatusOK
+}
+
+// Marshal marshals PaymentDryRunResponse
+func (response *PaymentDryRunResponse) Marshal() []byte {
+	json, _ := json.MarshalIndent(response, "", "  ")
+	return json
+}
@@ -0,0 +1,90 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+var (
+	// QuoteNotFound is an error response
+	QuoteNotFound = &protocols.ErrorResponse{Code: "quote_not_found", Message: "Quote not found.", Status: http.StatusNotFound}
+	// QuoteExpired is an error response
+	QuoteExpired = &protocols.ErrorResponse{Code: "quote_expired", Message: "Quote has expired, request a new one.", Status: http.StatusBadRequest}
+	// QuoteNoPath is an error response
+	QuoteNoPath = &protocols.ErrorResponse{Code: "quote_no_path", Message: "No path between the assets found for the amount.", Status: http.StatusBadRequest}
+)
+
+// QuoteRequest represents request made to /quote endpoint of bridge server.
+// When Amount is set the quote is the amount of the send asset needed to
+// deliver it (strict receive), when SendAmount is set the amount delivered by
+// sending it (strict send). Native asset is used when asset code and issuer
+// are empty.
+type QuoteRequest struct {
+	SendAssetCode   string `name:"send_asset_code"`
+	SendAssetIssuer string `name:"send_asset_issuer"`
+	SendAmount      string `name:"send_amount"`
+	AssetCode       string `name:"asset_code"`
+	AssetIssuer     string `name:"asset_issuer"`
+	Amount          string `name:"amount"`
+	// Network is the name of an additional network, the main network when
+	// empty
+	Network string `name:"network"`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *QuoteRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *QuoteRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// StrictSend returns true when the quote is for SendAmount
+func (request *QuoteRequest) StrictSend() bool {
+	return request.SendAmount != ""
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *QuoteRequest) Validate() error {
+	switch {
+	case request.Amount == "" && request.SendAmount == "":
+		return protocols.NewMissingParameter("amount")
+	case request.Amount != "" && request.SendAmount != "":
+		return protocols.NewInvalidParameterError("send_amount", request.SendAmount, "Only one of amount and send_amount can be set.")
+	case request.Amount != "":
+		err := validatePositiveAmount("amount", request.Amount)
+		if err != nil {
+			return err
+		}
+		request.Amount = amounts.String(amounts.MustParse(request.Amount))
+	default:
+		err := validatePositiveAmount("send_amount", request.SendAmount)
+		if err != nil {
+			return err
+		}
+		request.SendAmount = amounts.String(amounts.MustParse(request.SendAmount))
+	}
+
+	sendAsset := protocols.Asset{Code: request.SendAssetCode, Issuer: request.SendAssetIssuer}
+	if !sendAsset.Validate() {
+		return protocols.NewInvalidParameterError("send_asset_code", sendAsset.String(), "Invalid send asset.")
+	}
+
+	asset := protocols.Asset{Code: request.AssetCode, Issuer: request.AssetIssuer}
+	if !asset.Validate() {
+		return protocols.NewInvalidParameterError("asset_code", asset.String(), "Invalid asset.")
+	}
+
+	if sendAsset == asset {
+		return protocols.NewInvalidParameterError("send_asset_code", sendAsset.String(), "Send asset must be different than the asset.")
+	}
+
+	return nil
+}
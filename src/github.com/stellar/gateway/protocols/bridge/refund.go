@@ -0,0 +1,70 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+var (
+	// RefundPaymentNotFound is an error response
+	RefundPaymentNotFound = &protocols.ErrorResponse{Code: "received_payment_not_found", Message: "Received payment with given operation ID not found.", Status: http.StatusNotFound}
+	// RefundNotFound is an error response
+	RefundNotFound = &protocols.ErrorResponse{Code: "refund_not_found", Message: "Received payment has not been refunded.", Status: http.StatusNotFound}
+	// RefundNotSupported is an error response
+	RefundNotSupported = &protocols.ErrorResponse{Code: "refund_not_supported", Message: "Only payment, path_payment and account_merge operations can be refunded.", Status: http.StatusBadRequest}
+	// RefundAmountExceeded is an error response
+	RefundAmountExceeded = &protocols.ErrorResponse{Code: "refund_amount_exceeded", Message: "Refund amount is greater than the received amount.", Status: http.StatusBadRequest}
+	// RefundAlreadySent is an error response
+	RefundAlreadySent = &protocols.ErrorResponse{Code: "refund_already_sent", Message: "Received payment has been refunded already.", Status: http.StatusConflict}
+)
+
+// RefundRequest represents request made to /payments/{id}/refund endpoint of
+// bridge server. The refund is sent to the sender of the received payment in
+// the received asset.
+type RefundRequest struct {
+	// Source is a secret seed of the account sending the refund,
+	// accounts.base_seed by default
+	Source string `name:"source"`
+	// Amount of a partial refund, the received amount by default
+	Amount string `name:"amount"`
+	// MemoType and Memo replace the return memo containing the hash of the
+	// refunded transaction
+	MemoType string `name:"memo_type"`
+	Memo     string `name:"memo"`
+	Reason   string `name:"reason"`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *RefundRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *RefundRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *RefundRequest) Validate() error {
+	if request.Amount != "" {
+		amount, err := amounts.ParseAmount(request.Amount)
+		if err != nil || amount <= 0 {
+			return protocols.NewInvalidParameterError("amount", request.Amount, "Amount must be greater than zero.")
+		}
+	}
+
+	if request.MemoType == "" && request.Memo != "" {
+		return protocols.NewMissingParameter("memo_type")
+	}
+
+	if request.MemoType != "" && request.Memo == "" {
+		return protocols.NewMissingParameter("memo")
+	}
+
+	return protocols.CheckSize("reason", request.Reason, 255)
+}
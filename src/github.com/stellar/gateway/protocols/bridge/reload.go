@@ -0,0 +1,46 @@
+package bridge
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+var (
+	// ConfigReloadNotAvailable is an error response
+	ConfigReloadNotAvailable = &protocols.ErrorResponse{Code: "config_reload_not_available", Message: "Config cannot be reloaded by this server.", Status: http.StatusBadRequest}
+	// ConfigReloadFailed is an error response
+	ConfigReloadFailed = &protocols.ErrorResponse{Code: "config_reload_failed", Message: "Config cannot be loaded, current config is still used.", Status: http.StatusBadRequest}
+)
+
+// NewConfigReloadFailed creates ConfigReloadFailed error with the reason in
+// more_info field
+func NewConfigReloadFailed(reason string) *protocols.ErrorResponse {
+	return &protocols.ErrorResponse{
+		Code:     ConfigReloadFailed.Code,
+		Message:  ConfigReloadFailed.Message,
+		Status:   ConfigReloadFailed.Status,
+		MoreInfo: reason,
+	}
+}
+
+// ReloadResponse represents response returned by /admin/reload endpoint
+type ReloadResponse struct {
+	Status string `json:"status"`
+	// Reloaded contains names of changed params that have been applied
+	Reloaded []string `json:"reloaded"`
+	// Ignored contains names of changed params that require a restart
+	Ignored []string `json:"ignored"`
+}
+
+// HTTPStatus implements server.Response
+func (r ReloadResponse) HTTPStatus() int {
+	return http.StatusOK
+}
+
+// Marshal marshals ReloadResponse
+func (r ReloadResponse) Marshal() []byte {
+	json, _ := json.MarshalIndent(r, "", "  ")
+	return json
+}
@@ -0,0 +1,168 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+var (
+	// SEP24TransactionExists is an error response
+	SEP24TransactionExists = &protocols.ErrorResponse{Code: "sep24_transaction_exists", Message: "SEP-24 transaction with this ID already exists.", Status: http.StatusConflict}
+	// SEP24TransactionNotFound is an error response
+	SEP24TransactionNotFound = &protocols.ErrorResponse{Code: "sep24_transaction_not_found", Message: "SEP-24 transaction not found.", Status: http.StatusNotFound}
+)
+
+// transactionHash matches hex encoded hashes of Stellar transactions
+var transactionHash = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// SEP24TransactionRequest represents request made to /sep24/transactions
+// endpoint of bridge server. It creates a deposit or withdrawal started in the
+// anchor's interactive flow.
+type SEP24TransactionRequest struct {
+	// ID is generated when empty
+	ID   string `name:"id"`
+	Kind string `name:"kind" required:""`
+	// Account is the Stellar account of the user
+	Account     string `name:"account" required:""`
+	AssetCode   string `name:"asset_code" required:""`
+	AssetIssuer string `name:"asset_issuer"`
+	// Status is incomplete when empty
+	Status   string `name:"status"`
+	AmountIn string `name:"amount_in"`
+	// MemoType and Memo of withdrawals are generated (id memo) when empty
+	MemoType    string `name:"memo_type"`
+	Memo        string `name:"memo"`
+	MoreInfoURL string `name:"more_info_url"`
+	Message     string `name:"message"`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *SEP24TransactionRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *SEP24TransactionRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+// Memos are normalized like memos of expected payments.
+func (request *SEP24TransactionRequest) Validate() error {
+	err := request.FormRequest.CheckRequired(request)
+	if err != nil {
+		return err
+	}
+
+	if request.ID != "" && !expectedPaymentID.MatchString(request.ID) {
+		return protocols.NewInvalidParameterError("id", request.ID, "ID can contain letters, digits, `.`, `_`, `:` and `-` and be at most 64 characters long.")
+	}
+
+	if request.Kind != entities.SEP24KindDeposit && request.Kind != entities.SEP24KindWithdrawal {
+		return protocols.NewInvalidParameterError("kind", request.Kind, "Kind must be deposit or withdrawal.")
+	}
+
+	if !protocols.IsValidAccountID(request.Account) {
+		return protocols.NewInvalidParameterError("account", request.Account, "Account parameter is not a valid account ID.")
+	}
+
+	err = validateAsset(request.AssetCode, request.AssetIssuer)
+	if err != nil {
+		return err
+	}
+
+	if request.Status != "" && !entities.IsSEP24Status(request.Status) {
+		return protocols.NewInvalidParameterError("status", request.Status, "Invalid SEP-24 status.")
+	}
+
+	if request.AmountIn != "" {
+		amount, err := amounts.ParseAmount(request.AmountIn)
+		if err != nil || amount <= 0 {
+			return protocols.NewInvalidParameterError("amount_in", request.AmountIn, "Amount must be greater than zero.")
+		}
+	}
+
+	if request.MemoType != "" || request.Memo != "" {
+		request.Memo, err = normalizeMemo(request.MemoType, request.Memo)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = protocols.CheckSize("more_info_url", request.MoreInfoURL, 255)
+	if err != nil {
+		return err
+	}
+	return protocols.CheckSize("message", request.Message, 255)
+}
+
+// SEP24UpdateRequest represents request made to /sep24/transactions/{id}
+// endpoint of bridge server. Empty params are not changed.
+type SEP24UpdateRequest struct {
+	Status                string `name:"status"`
+	AmountIn              string `name:"amount_in"`
+	AmountOut             string `name:"amount_out"`
+	AmountFee             string `name:"amount_fee"`
+	StellarTransactionID  string `name:"stellar_transaction_id"`
+	ExternalTransactionID string `name:"external_transaction_id"`
+	MoreInfoURL           string `name:"more_info_url"`
+	Message               string `name:"message"`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *SEP24UpdateRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *SEP24UpdateRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *SEP24UpdateRequest) Validate() error {
+	if request.Status != "" && !entities.IsSEP24Status(request.Status) {
+		return protocols.NewInvalidParameterError("status", request.Status, "Invalid SEP-24 status.")
+	}
+
+	params := []struct{ name, value string }{
+		{"amount_in", request.AmountIn},
+		{"amount_out", request.AmountOut},
+		{"amount_fee", request.AmountFee},
+	}
+	for _, param := range params {
+		if param.value == "" {
+			continue
+		}
+		amount, err := amounts.ParseAmount(param.value)
+		if err != nil || amount < 0 {
+			return protocols.NewInvalidParameterError(param.name, param.value, "Amount cannot be negative.")
+		}
+	}
+
+	if request.StellarTransactionID != "" && !transactionHash.MatchString(request.StellarTransactionID) {
+		return protocols.NewInvalidParameterError("stellar_transaction_id", request.StellarTransactionID, "Transaction ID must be a hex encoded hash.")
+	}
+
+	params = []struct{ name, value string }{
+		{"external_transaction_id", request.ExternalTransactionID},
+		{"more_info_url", request.MoreInfoURL},
+		{"message", request.Message},
+	}
+	for _, param := range params {
+		err := protocols.CheckSize(param.name, param.value, 255)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
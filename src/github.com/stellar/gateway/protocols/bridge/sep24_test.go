@@ -0,0 +1,70 @@
+package bridge
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSEP24TransactionRequestValidate(t *testing.T) {
+	newRequest := func(values url.Values) *SEP24TransactionRequest {
+		r := httptest.NewRequest("POST", "/sep24/transactions", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request := &SEP24TransactionRequest{}
+		require.NoError(t, request.FromRequest(r))
+		return request
+	}
+
+	valid := url.Values{
+		"kind":       {"withdrawal"},
+		"account":    {testSource},
+		"asset_code": {"XLM"},
+		"memo_type":  {"id"},
+		"memo":       {"007"},
+	}
+	request := newRequest(valid)
+	require.NoError(t, request.Validate())
+	assert.Equal(t, "7", request.Memo)
+
+	for name, change := range map[string]url.Values{
+		"kind":         {"kind": {"transfer"}},
+		"account":      {"account": {"GBAD"}},
+		"asset_issuer": {"asset_code": {"USD"}},
+		"status":       {"status": {"paid"}},
+		"amount_in":    {"amount_in": {"0"}},
+		"memo":         {"memo_type": {"text"}, "memo": {strings.Repeat("a", 29)}},
+		"id":           {"id": {"bad id"}},
+	} {
+		values := url.Values{}
+		for key, value := range valid {
+			values[key] = value
+		}
+		for key, value := range change {
+			values[key] = value
+		}
+		err := newRequest(values).Validate()
+		require.Error(t, err, name)
+		assert.Equal(t, name, err.(*protocols.ErrorResponse).Data["name"], name)
+	}
+}
+
+func TestSEP24UpdateRequestValidate(t *testing.T) {
+	assert.NoError(t, (&SEP24UpdateRequest{}).Validate())
+	assert.NoError(t, (&SEP24UpdateRequest{Status: "completed", AmountOut: "9.5", AmountFee: "0", StellarTransactionID: strings.Repeat("ab", 32)}).Validate())
+
+	for name, request := range map[string]SEP24UpdateRequest{
+		"status":                 {Status: "paid"},
+		"amount_fee":             {AmountFee: "-1"},
+		"stellar_transaction_id": {StellarTransactionID: "abc"},
+		"message":                {Message: strings.Repeat("a", 256)},
+	} {
+		err := request.Validate()
+		require.Error(t, err, name)
+		assert.Equal(t, name, err.(*protocols.ErrorResponse).Data["name"], name)
+	}
+}
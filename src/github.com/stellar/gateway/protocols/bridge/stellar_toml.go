@@ -0,0 +1,32 @@
+package bridge
+
+import (
+	"net/http"
+
+	"github.com/stellar/gateway/external"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/go/support/errors"
+)
+
+// stellarTomlErrors are error responses for codes of external.StellarTomlError
+var stellarTomlErrors = map[string]*protocols.ErrorResponse{
+	external.StellarTomlInsecure:           {Code: external.StellarTomlInsecure, Message: "stellar.toml of destination domain redirects to non-HTTPS URL.", Status: http.StatusBadRequest},
+	external.StellarTomlInvalidCertificate: {Code: external.StellarTomlInvalidCertificate, Message: "TLS certificate of destination domain cannot be verified.", Status: http.StatusBadRequest},
+	external.StellarTomlTooManyRedirects:   {Code: external.StellarTomlTooManyRedirects, Message: "stellar.toml of destination domain redirects too many times.", Status: http.StatusBadRequest},
+	external.StellarTomlTooLarge:           {Code: external.StellarTomlTooLarge, Message: "stellar.toml of destination domain is too large.", Status: http.StatusBadRequest},
+	external.StellarTomlNotFound:           {Code: external.StellarTomlNotFound, Message: "stellar.toml of destination domain not found.", Status: http.StatusBadRequest},
+	external.StellarTomlInvalid:            {Code: external.StellarTomlInvalid, Message: "stellar.toml of destination domain is invalid.", Status: http.StatusBadRequest},
+	external.StellarTomlUnreachable:        {Code: external.StellarTomlUnreachable, Message: "stellar.toml of destination domain cannot be loaded.", Status: http.StatusBadGateway},
+}
+
+// ErrorFromResolveError returns an error response describing why stellar.toml
+// of destination domain cannot be loaded or PaymentCannotResolveDestination
+// when err was returned by federation server.
+func ErrorFromResolveError(err error) *protocols.ErrorResponse {
+	if stellarTomlErr, ok := errors.Cause(err).(*external.StellarTomlError); ok {
+		if response, ok := stellarTomlErrors[stellarTomlErr.Code]; ok {
+			return response
+		}
+	}
+	return PaymentCannotResolveDestination
+}
@@ -0,0 +1,106 @@
+package bridge
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/gateway/cloudevents"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/webhooks"
+)
+
+var (
+	// WebhookNotFound is an error response
+	WebhookNotFound = &protocols.ErrorResponse{Code: "webhook_not_found", Message: "Webhook not found.", Status: http.StatusNotFound}
+)
+
+// WebhookRequest represents request made to /admin/webhooks endpoint of
+// bridge server. Empty retry policy params are set to defaults of `webhooks`
+// config group.
+type WebhookRequest struct {
+	URL string `name:"url" required:""`
+	// Events is a comma separated list of event types
+	Events string `name:"events" required:""`
+	// Secret authenticates event bodies, generated when empty
+	Secret      string `name:"secret"`
+	MaxAttempts string `name:"max_attempts"`
+	// RetryInterval is the number of seconds before the first retry
+	RetryInterval string `name:"retry_interval"`
+	// Enabled is "true" (default) or "false"
+	Enabled string `name:"enabled"`
+	// Format is "json" (default) or "cloudevents"
+	Format string `name:"format"`
+	// SchemaVersion is a comma separated list of schema versions of event
+	// data accepted by the webhook, the newest supported one is used
+	SchemaVersion string `name:"schema_version"`
+
+	protocols.FormRequest
+}
+
+// FromRequest will populate request fields using http.Request.
+func (request *WebhookRequest) FromRequest(r *http.Request) error {
+	return request.FormRequest.FromRequest(r, request)
+}
+
+// ToValues will create url.Values from request.
+func (request *WebhookRequest) ToValues() url.Values {
+	return request.FormRequest.ToValues(request)
+}
+
+// Validate validates if request fields are valid. Useful when checking if a request is correct.
+func (request *WebhookRequest) Validate() error {
+	err := request.FormRequest.CheckRequired(request)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(request.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return protocols.NewInvalidParameterError("url", request.URL, "URL must be an absolute http or https URL.")
+	}
+
+	for _, event := range request.EventTypes() {
+		if !webhooks.IsValidEvent(event) {
+			return protocols.NewInvalidParameterError("events", request.Events, "Events must be a comma separated list of: "+strings.Join(webhooks.Events, ", ")+".")
+		}
+	}
+
+	if request.MaxAttempts != "" {
+		if value, err := strconv.Atoi(request.MaxAttempts); err != nil || value <= 0 {
+			return protocols.NewInvalidParameterError("max_attempts", request.MaxAttempts, "Max attempts must be a positive integer.")
+		}
+	}
+
+	if request.RetryInterval != "" {
+		if value, err := strconv.Atoi(request.RetryInterval); err != nil || value < 0 {
+			return protocols.NewInvalidParameterError("retry_interval", request.RetryInterval, "Retry interval must be a number of seconds.")
+		}
+	}
+
+	if request.Enabled != "" {
+		if _, err := strconv.ParseBool(request.Enabled); err != nil {
+			return protocols.NewInvalidParameterError("enabled", request.Enabled, "Enabled must be true or false.")
+		}
+	}
+
+	if !cloudevents.IsValidFormat(request.Format) {
+		return protocols.NewInvalidParameterError("format", request.Format, "Format must be json or cloudevents.")
+	}
+
+	if _, ok := cloudevents.Negotiate(request.SchemaVersion); !ok {
+		return protocols.NewInvalidParameterError("schema_version", request.SchemaVersion, "None of the schema versions is supported, supported versions: "+strings.Join(cloudevents.SchemaVersions, ", ")+".")
+	}
+
+	return nil
+}
+
+// EventTypes returns event types of Events
+func (request *WebhookRequest) EventTypes() []string {
+	var events []string
+	for _, event := range strings.Split(request.Events, ",") {
+		events = append(events, strings.TrimSpace(event))
+	}
+	return events
+}
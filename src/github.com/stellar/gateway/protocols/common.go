@@ -13,7 +13,8 @@ import (
 	"github.com/stellar/go/support/errors"
 )
 
-var federationDestinationFieldName = regexp.MustCompile("forward_destination\\[fields\\]\\[([a-z_-]+)\\]")
+var federationDestinationFieldName = regexp.MustCompile("forward_destination\\[fields\\]\\[([^\\]]+)\\]")
+var metadataFieldName = regexp.MustCompile("^metadata\\[([^\\]]+)\\]$")
 
 // Asset represents native or credit asset
 type Asset struct {
@@ -47,12 +48,41 @@ func (a Asset) Validate() bool {
 	}
 }
 
-// ForwardDestination contains fields required to create forward federation request
+// ForwardDestination contains fields required to create forward federation
+// request (SEP-2 `forward` type query). Fields are passed to the federation
+// server of the domain as is, ex. forward_type, swift, acct.
 type ForwardDestination struct {
 	Domain string     `name:"domain"`
 	Fields url.Values `name:"fields"`
 }
 
+// Validate checks if domain and fields are set. `type` field is reserved
+// because it's set to `forward` in federation request.
+func (d *ForwardDestination) Validate() error {
+	if d.Domain == "" {
+		return NewMissingParameter("forward_destination[domain]")
+	}
+
+	if len(d.Fields) == 0 {
+		return NewMissingParameter("forward_destination[fields]")
+	}
+
+	if _, ok := d.Fields["type"]; ok {
+		return NewInvalidParameterError("forward_destination[fields][type]", d.Fields.Get("type"), "type field is reserved.")
+	}
+
+	return nil
+}
+
+// Query returns a copy of Fields that can be used to build federation request
+func (d *ForwardDestination) Query() url.Values {
+	query := url.Values{}
+	for key, values := range d.Fields {
+		query[key] = append([]string(nil), values...)
+	}
+	return query
+}
+
 // FormRequest allows transforming http.Request url.Values from/to request structs
 type FormRequest struct {
 	HTTPRequest *http.Request
@@ -94,16 +124,40 @@ func (request *FormRequest) FromRequest(r *http.Request, destination interface{}
 				destination.Fields.Add(fieldName, r.PostFormValue(key))
 			}
 
+			// Incomplete destinations are rejected by ForwardDestination.Validate
 			ptr := rvalue.Field(i).Addr().Interface().(**ForwardDestination)
-			if destination.Domain != "" && len(destination.Fields) > 0 {
+			if destination.Domain != "" || len(destination.Fields) > 0 {
 				*ptr = &destination
 			} else {
 				*ptr = nil
 			}
+		case "metadata":
+			err := r.ParseForm()
+			if err != nil {
+				return err
+			}
+
+			var metadata map[string]string
+			for key := range r.PostForm {
+				matches := metadataFieldName.FindStringSubmatch(key)
+				if len(matches) < 2 {
+					continue
+				}
+
+				if metadata == nil {
+					metadata = make(map[string]string)
+				}
+				metadata[matches[1]] = r.PostFormValue(key)
+			}
+
+			ptr := rvalue.Field(i).Addr().Interface().(*map[string]string)
+			*ptr = metadata
 		case "path":
 			var path []Asset
 
-			for i := 0; i < 5; i++ {
+			// One asset over the limit is loaded, so Validate can reject
+			// the request instead of silently dropping assets
+			for i := 0; i <= MaxPathLength; i++ {
 				codeFieldName := fmt.Sprintf(pathCodeField, i)
 				issuerFieldName := fmt.Sprintf(pathIssuerField, i)
 
@@ -198,6 +252,10 @@ func (request *FormRequest) ToValues(object interface{}) (values url.Values) {
 				values.Set(fmt.Sprintf(pathCodeField, i), asset.Code)
 				values.Set(fmt.Sprintf(pathIssuerField, i), asset.Issuer)
 			}
+		case map[string]string:
+			for key, value := range rvalue.Field(i).Interface().(map[string]string) {
+				values.Set(fmt.Sprintf("%s[%s]", tag, key), value)
+			}
 		case *ForwardDestination:
 			destination := rvalue.Field(i).Interface().(*ForwardDestination)
 			if destination == nil {
@@ -2,7 +2,9 @@ package protocols_test
 
 import (
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -45,3 +47,40 @@ func TestProtocols(t *testing.T) {
 		})
 	})
 }
+
+func TestForwardDestination(t *testing.T) {
+	body := "forward_destination[domain]=bank.com&forward_destination[fields][forward_type]=bank_account&forward_destination[fields][swift]=BOPBPHMM&forward_destination[fields][acct_2]=2382376&amount=20"
+	r, err := http.NewRequest("POST", "/payment", strings.NewReader(body))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	request := &callback.PaymentRequest{}
+	require.NoError(t, request.FromRequest(r))
+	require.NotNil(t, request.ForwardDestination)
+	assert.Equal(t, "bank.com", request.ForwardDestination.Domain)
+	assert.Equal(t, url.Values{
+		"forward_type": {"bank_account"},
+		"swift":        {"BOPBPHMM"},
+		"acct_2":       {"2382376"},
+	}, request.ForwardDestination.Fields)
+	assert.NoError(t, request.ForwardDestination.Validate())
+
+	query := request.ForwardDestination.Query()
+	query.Add("type", "forward")
+	assert.NotContains(t, request.ForwardDestination.Fields, "type")
+
+	tests := []struct {
+		destination protocols.ForwardDestination
+		err         string
+	}{
+		{protocols.ForwardDestination{Fields: url.Values{"acct": {"1"}}}, "forward_destination[domain]"},
+		{protocols.ForwardDestination{Domain: "bank.com"}, "forward_destination[fields]"},
+		{protocols.ForwardDestination{Domain: "bank.com", Fields: url.Values{"type": {"name"}}}, "forward_destination[fields][type]"},
+	}
+
+	for _, test := range tests {
+		err := test.destination.Validate()
+		require.Error(t, err)
+		assert.Equal(t, test.err, err.(*protocols.ErrorResponse).Data["name"])
+	}
+}
@@ -0,0 +1,47 @@
+package compliance
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+
+	proto "github.com/stellar/go/protocols/compliance"
+)
+
+// Attachment is compliance.Attachment with an encrypted private note.
+// Attachments without a private note are marshalled exactly like
+// compliance.Attachment, so servers not supporting private notes can verify
+// their hashes.
+type Attachment struct {
+	Nonce       string            `json:"nonce"`
+	Transaction Transaction       `json:"transaction"`
+	Operations  []proto.Operation `json:"operations"`
+}
+
+// Transaction represents transaction field of Attachment
+type Transaction struct {
+	proto.Transaction
+	// EncryptedNote is a private note encrypted to ENCRYPTION_KEY of the
+	// receiving organization, see crypto.Encrypt. It's visible to the
+	// receiving compliance server only.
+	EncryptedNote string `json:"encrypted_note,omitempty"`
+}
+
+// ParseAttachment unmarshals Attachment from attachment field of AuthData
+func ParseAttachment(attachmentJSON string) (attachment Attachment, err error) {
+	err = json.Unmarshal([]byte(attachmentJSON), &attachment)
+	return
+}
+
+// Marshal marshals Attachment
+func (attachment *Attachment) Marshal() ([]byte, error) {
+	return json.Marshal(attachment)
+}
+
+// Hash returns sha-256 hash of the JSON marshalled attachment
+func (attachment *Attachment) Hash() ([32]byte, error) {
+	marshalled, err := attachment.Marshal()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(marshalled), nil
+}
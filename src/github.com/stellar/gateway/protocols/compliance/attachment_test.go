@@ -0,0 +1,37 @@
+package compliance
+
+import (
+	"testing"
+
+	proto "github.com/stellar/go/protocols/compliance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachment(t *testing.T) {
+	transaction := proto.Transaction{
+		SenderInfo: map[string]string{"first_name": "Alice"},
+		Route:      "bob",
+		Note:       "Invoice",
+		Extra:      "extra",
+	}
+
+	// Attachments without a private note have the same hash as the ones of
+	// servers not supporting private notes
+	expected := proto.Attachment{Nonce: "1", Transaction: transaction}
+	attachment := Attachment{Nonce: "1", Transaction: Transaction{Transaction: transaction}}
+	expectedHash, err := expected.Hash()
+	require.NoError(t, err)
+	hash, err := attachment.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, expectedHash, hash)
+
+	attachment.Transaction.EncryptedNote = "c2VjcmV0"
+	attachmentJSON, err := attachment.Marshal()
+	require.NoError(t, err)
+	assert.Contains(t, string(attachmentJSON), `"encrypted_note":"c2VjcmV0"`)
+
+	parsed, err := ParseAttachment(string(attachmentJSON))
+	require.NoError(t, err)
+	assert.Equal(t, attachment, parsed)
+}
@@ -12,10 +12,23 @@ var (
 	// TransactionNotFoundError is an error response
 	TransactionNotFoundError = &protocols.ErrorResponse{Code: "transaction_not_found", Message: "Transaction not found.", Status: http.StatusNotFound}
 
+	// /compliance/attachment
+
+	// AttachmentNotFound is an error response
+	AttachmentNotFound = &protocols.ErrorResponse{Code: "attachment_not_found", Message: "Attachment not found.", Status: http.StatusNotFound}
+
 	// /send
 
 	// CannotResolveDestination is an error response
 	CannotResolveDestination = &protocols.ErrorResponse{Code: "cannot_resolve_destination", Message: "Cannot resolve federated Stellar address.", Status: http.StatusBadRequest}
 	// AuthServerNotDefined is an error response
 	AuthServerNotDefined = &protocols.ErrorResponse{Code: "auth_server_not_defined", Message: "No AUTH_SERVER defined in stellar.toml file.", Status: http.StatusBadRequest}
+	// EncryptionKeyNotDefined is an error response
+	EncryptionKeyNotDefined = &protocols.ErrorResponse{Code: "encryption_key_not_defined", Message: "No valid ENCRYPTION_KEY defined in stellar.toml file, private_note cannot be sent.", Status: http.StatusBadRequest}
+	// AuthServerBusy is an error response
+	AuthServerBusy = &protocols.ErrorResponse{Code: "auth_server_busy", Message: "Too many requests to destination AUTH_SERVER are in progress. Please, try again later.", Status: http.StatusServiceUnavailable}
+	// DestinationSanctioned is an error response
+	DestinationSanctioned = &protocols.ErrorResponse{Code: "destination_sanctioned", Message: "Destination matched sanctions screening.", Status: http.StatusForbidden}
+	// DestinationSanctionsReview is an error response
+	DestinationSanctionsReview = &protocols.ErrorResponse{Code: "destination_sanctions_review", Message: "Destination requires a review by compliance team before payments can be sent.", Status: http.StatusForbidden}
 )
@@ -9,7 +9,9 @@ import (
 
 // FetchInfoRequest represents a request sent to fetch_info callback
 type FetchInfoRequest struct {
-	Address     string `name:"address" required:""`
+	Address string `name:"address" required:""`
+	// SenderID is sent when /send request contains sender_id
+	SenderID    string `name:"sender_id"`
 	formRequest protocols.FormRequest
 }
 
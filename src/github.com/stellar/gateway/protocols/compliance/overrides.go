@@ -0,0 +1,35 @@
+package compliance
+
+import (
+	"encoding/json"
+
+	"github.com/stellar/gateway/protocols"
+)
+
+// ParseInfo parses a JSON object with KYC fields (like `sender_info`) sent in
+// a request. Empty value returns nil map.
+func ParseInfo(name, value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var info map[string]string
+	err := json.Unmarshal([]byte(value), &info)
+	if err != nil {
+		return nil, protocols.NewInvalidParameterError(name, value, "Must be a JSON object with string values.")
+	}
+	return info, nil
+}
+
+// MergeInfo returns info with overrides applied. Fields of overrides take
+// precedence over fields returned by fetch_info callback.
+func MergeInfo(info, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(info)+len(overrides))
+	for k, v := range info {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,65 @@
+package compliance
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInfo(t *testing.T) {
+	info, err := ParseInfo("sender_info", "")
+	require.NoError(t, err)
+	assert.Nil(t, info)
+
+	info, err = ParseInfo("sender_info", `{"first_name": "Jane", "country": "DE"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"first_name": "Jane", "country": "DE"}, info)
+
+	_, err = ParseInfo("sender_info", `{"age": 42}`)
+	require.Error(t, err)
+	assert.Equal(t, "invalid_parameter", err.(*protocols.ErrorResponse).Code)
+	assert.Equal(t, "sender_info", err.(*protocols.ErrorResponse).Data["name"])
+
+	_, err = ParseInfo("receiver_info", `not json`)
+	assert.Error(t, err)
+}
+
+func TestMergeInfo(t *testing.T) {
+	info := map[string]string{"first_name": "John", "last_name": "Doe"}
+	merged := MergeInfo(info, map[string]string{"first_name": "Jane", "country": "DE"})
+	assert.Equal(t, map[string]string{"first_name": "Jane", "last_name": "Doe", "country": "DE"}, merged)
+	// info is not modified
+	assert.Equal(t, "John", info["first_name"])
+
+	assert.Equal(t, info, MergeInfo(info, nil))
+}
+
+func TestSendRequestValidateOverrides(t *testing.T) {
+	validate := func(request *SendRequest) error {
+		r := httptest.NewRequest("POST", "/send", strings.NewReader(request.ToValues().Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		parsed := &SendRequest{}
+		require.NoError(t, parsed.FromRequest(r))
+		return parsed.Validate()
+	}
+
+	request := &SendRequest{
+		Source:      "GBYJZW5XFAI6XV73H5SAIUYK6XZI4CGGVBUBO3ANA2SV7KKDAXTV6AEB",
+		Sender:      "alice*stellar.org",
+		Destination: "bob*stellar.org",
+		Amount:      "20",
+		AssetCode:   "USD",
+		AssetIssuer: "GBYJZW5XFAI6XV73H5SAIUYK6XZI4CGGVBUBO3ANA2SV7KKDAXTV6AEB",
+		SenderInfo:  `{"first_name": "Jane"}`,
+	}
+	assert.NoError(t, validate(request))
+
+	request.ReceiverInfo = `["Bob"]`
+	err := validate(request)
+	require.Error(t, err)
+	assert.Equal(t, "receiver_info", err.(*protocols.ErrorResponse).Data["name"])
+}
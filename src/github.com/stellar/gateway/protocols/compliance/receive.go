@@ -38,6 +38,8 @@ type ReceiveResponse struct {
 	protocols.SuccessResponse
 	// The AuthData hash of this memo.
 	Data string `json:"data"`
+	// PrivateNote is the decrypted private note of the attachment
+	PrivateNote string `json:"private_note,omitempty"`
 }
 
 // Marshal marshals ReceiveResponse
@@ -37,6 +37,19 @@ type SendRequest struct {
 	Path []protocols.Asset `name:"path"`
 	// Extra memo
 	ExtraMemo string `name:"extra_memo"`
+	// ID of the sender sent to fetch_info callback
+	SenderID string `name:"sender_id"`
+	// JSON object overriding fields returned by fetch_info callback
+	SenderInfo string `name:"sender_info"`
+	// JSON object with receiver KYC info, stored with the attachment
+	ReceiverInfo string `name:"receiver_info"`
+	// Overrides route returned by federation server
+	Route string `name:"route"`
+	// Note sent in the attachment
+	Note string `name:"note"`
+	// PrivateNote is sent in the attachment encrypted to ENCRYPTION_KEY of
+	// the receiving organization
+	PrivateNote string `name:"private_note"`
 
 	protocols.FormRequest
 }
@@ -70,6 +83,13 @@ func (request *SendRequest) Validate() error {
 		return protocols.NewMissingParameter("destination")
 	}
 
+	if request.ForwardDestination != nil {
+		err = request.ForwardDestination.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
 	if request.Destination != "" && !validateStellarAddress(request.Destination) {
 		return protocols.NewInvalidParameterError("destination", request.Destination, "Not a valid stellar address.")
 	}
@@ -79,6 +99,31 @@ func (request *SendRequest) Validate() error {
 		return protocols.NewInvalidParameterError("asset_issuer", request.AssetIssuer, "Asset issuer must be a public key (starting with `G`).")
 	}
 
+	err = protocols.CheckPathLength("path", request.Path)
+	if err != nil {
+		return err
+	}
+
+	err = protocols.CheckMetadataSize(request.ExtraMemo, request.SenderInfo, request.ReceiverInfo, request.Note)
+	if err != nil {
+		return err
+	}
+
+	err = protocols.CheckSize("private_note", request.PrivateNote, protocols.MaxMetadataSize)
+	if err != nil {
+		return err
+	}
+
+	_, err = ParseInfo("sender_info", request.SenderInfo)
+	if err != nil {
+		return err
+	}
+
+	_, err = ParseInfo("receiver_info", request.ReceiverInfo)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
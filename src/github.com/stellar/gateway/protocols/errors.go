@@ -12,6 +12,24 @@ var (
 	InvalidParameterError = &ErrorResponse{Code: "invalid_parameter", Message: "Invalid parameter.", Status: http.StatusBadRequest}
 	// MissingParameterError is an error response
 	MissingParameterError = &ErrorResponse{Code: "missing_parameter", Message: "Required parameter is missing.", Status: http.StatusBadRequest}
+	// RequestTooLargeError is an error response
+	RequestTooLargeError = &ErrorResponse{Code: "request_too_large", Message: "Request body is too large.", Status: http.StatusRequestEntityTooLarge}
+	// UnauthorizedError is an error response
+	UnauthorizedError = &ErrorResponse{Code: "unauthorized", Message: "Missing or invalid API key or signature.", Status: http.StatusUnauthorized}
+	// InvalidAPIKeyError is an error response
+	InvalidAPIKeyError = &ErrorResponse{Code: "forbidden", Message: "Invalid API key.", Status: http.StatusForbidden}
+	// RateLimitExceededError is an error response
+	RateLimitExceededError = &ErrorResponse{Code: "rate_limit_exceeded", Message: "Rate limit of the API key exceeded, please try again later.", Status: http.StatusTooManyRequests}
+	// EndpointRateLimitExceededError is an error response
+	EndpointRateLimitExceededError = &ErrorResponse{Code: "rate_limit_exceeded", Message: "Rate limit of the endpoint exceeded, please try again later.", Status: http.StatusTooManyRequests}
+	// IPNotAllowedError is an error response
+	IPNotAllowedError = &ErrorResponse{Code: "ip_not_allowed", Message: "Requests from this IP address are not allowed.", Status: http.StatusForbidden}
+	// ServiceUnavailableError is an error response
+	ServiceUnavailableError = &ErrorResponse{Code: "service_unavailable", Message: "Server is busy, please try again later.", Status: http.StatusServiceUnavailable}
+	// TimeoutError is an error response
+	TimeoutError = &ErrorResponse{Code: "timeout", Message: "External service did not respond in time, please try again.", Status: http.StatusGatewayTimeout}
+	// SourceNotAllowedError is an error response
+	SourceNotAllowedError = &ErrorResponse{Code: "source_not_allowed", Message: "Source account is not allowed for the API key.", Status: http.StatusForbidden}
 )
 
 // NewInternalServerError creates and returns a new InternalServerError
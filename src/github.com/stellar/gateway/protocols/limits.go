@@ -0,0 +1,65 @@
+package protocols
+
+import (
+	"strconv"
+)
+
+// Limits of request sizes. Requests exceeding them are rejected before a
+// transaction is built, so callers cannot make servers allocate unbounded
+// memory.
+const (
+	// MaxRequestBodySize is a max size of a request body in bytes
+	MaxRequestBodySize = 1 << 20
+	// MaxPathLength is a max number of assets in a payment path (Stellar
+	// protocol limit)
+	MaxPathLength = 5
+	// MaxOperations is a max number of operations in a transaction (Stellar
+	// protocol limit)
+	MaxOperations = 100
+	// MaxSignatures is a max number of signatures of a transaction (Stellar
+	// protocol limit)
+	MaxSignatures = 20
+	// MaxMemoLength is a max length of a memo value of any type: 64 characters
+	// of a hex encoded hash memo
+	MaxMemoLength = 64
+	// MaxMetadataSize is a max size in bytes of each of the fields sent in a
+	// compliance attachment: extra_memo, sender_info, receiver_info, note and
+	// private_note
+	MaxMetadataSize = 16 << 10
+)
+
+// CheckPathLength returns an error when path contains more than MaxPathLength
+// assets
+func CheckPathLength(name string, path []Asset) error {
+	if len(path) > MaxPathLength {
+		return NewInvalidParameterError(name, strconv.Itoa(len(path)), "Path can contain at most "+strconv.Itoa(MaxPathLength)+" assets.")
+	}
+	return nil
+}
+
+// CheckSize returns an error when value is longer than max bytes. The value is
+// not included in the error, so it's not logged.
+func CheckSize(name, value string, max int) error {
+	if len(value) > max {
+		return NewInvalidParameterError(name, "", "Value can be at most "+strconv.Itoa(max)+" bytes long.", map[string]interface{}{"size": len(value)})
+	}
+	return nil
+}
+
+// CheckMetadataSize returns an error when any of the fields sent in a
+// compliance attachment is larger than MaxMetadataSize
+func CheckMetadataSize(extraMemo, senderInfo, receiverInfo, note string) error {
+	fields := []struct{ name, value string }{
+		{"extra_memo", extraMemo},
+		{"sender_info", senderInfo},
+		{"receiver_info", receiverInfo},
+		{"note", note},
+	}
+	for _, field := range fields {
+		err := CheckSize(field.name, field.value, MaxMetadataSize)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
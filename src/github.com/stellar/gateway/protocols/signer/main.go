@@ -0,0 +1,55 @@
+// Package signer contains the wire protocol between the bridge server and an
+// external signing service. The bridge server sends SignRequest in a POST
+// request body authenticated with HMAC-SHA256 in SignatureHeader and receives
+// a detached signature in SignResponse. The envelope is assembled by the
+// bridge server.
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// SignatureHeader contains hex encoded HMAC-SHA256 of the request body
+// computed using the shared secret
+const SignatureHeader = "X-Signature"
+
+// SignRequest is sent to the signing service
+type SignRequest struct {
+	AccountID         string `json:"account_id"`
+	NetworkPassphrase string `json:"network_passphrase"`
+	// Transaction is a base64 encoded xdr.Transaction
+	Transaction string `json:"transaction"`
+	// Hash is a hex encoded transaction hash to sign
+	Hash string `json:"hash"`
+	// Timestamp allows the signing service to reject replayed requests
+	Timestamp int64 `json:"timestamp"`
+}
+
+// SignResponse is returned by the signing service
+type SignResponse struct {
+	// Signature is a base64 encoded ed25519 signature of the hash
+	Signature string `json:"signature,omitempty"`
+	// Error is a reason the transaction has been rejected by the signing service
+	Error string `json:"error,omitempty"`
+}
+
+// Marshal marshals SignResponse
+func (response *SignResponse) Marshal() []byte {
+	json, _ := json.Marshal(response)
+	return json
+}
+
+// MAC returns hex encoded HMAC-SHA256 of body sent in SignatureHeader
+func MAC(secret string, body []byte) string {
+	macer := hmac.New(sha256.New, []byte(secret))
+	macer.Write(body)
+	return hex.EncodeToString(macer.Sum(nil))
+}
+
+// VerifyMAC returns true when mac is a valid MAC of body
+func VerifyMAC(secret string, body []byte, mac string) bool {
+	return hmac.Equal([]byte(MAC(secret, body)), []byte(mac))
+}
@@ -1,7 +1,7 @@
 package protocols
 
 import (
-	"github.com/stellar/go/amount"
+	"github.com/stellar/gateway/protocols/amounts"
 	"github.com/stellar/go/keypair"
 )
 
@@ -43,7 +43,7 @@ func IsValidAssetCode(code string) bool {
 
 // IsValidAmount returns true if amount is valid
 func IsValidAmount(a string) bool {
-	_, err := amount.Parse(a)
+	_, err := amounts.Parse(a)
 	if err != nil {
 		return false
 	}
@@ -0,0 +1,38 @@
+package queue
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/stellar/gateway/bridge/config"
+)
+
+// Publisher publishes messages to a cloud queue. Attributes are attached to
+// the message so subscribers can filter messages without decoding them.
+type Publisher interface {
+	Publish(message []byte, attributes map[string]string) error
+}
+
+// HTTP represents an http client that publishers can use to make HTTP requests.
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+const (
+	// TransportSQS publishes messages to AWS SQS queue
+	TransportSQS = "sqs"
+	// TransportPubSub publishes messages to Google Cloud Pub/Sub topic
+	TransportPubSub = "pubsub"
+)
+
+// NewPublisher creates a new Publisher for a given transport
+func NewPublisher(transport string, sqs config.SQS, pubsub config.PubSub, client HTTP) (Publisher, error) {
+	switch transport {
+	case TransportSQS:
+		return NewSQSPublisher(sqs, client)
+	case TransportPubSub:
+		return NewPubSubPublisher(pubsub, client), nil
+	default:
+		return nil, fmt.Errorf("Unknown queue transport: %s", transport)
+	}
+}
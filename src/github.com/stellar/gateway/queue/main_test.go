@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQSPublisher(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.True(t, strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Contains(t, req.Header.Get("Authorization"), "/us-east-1/sqs/aws4_request")
+
+		require.NoError(t, req.ParseForm())
+		assert.Equal(t, "SendMessage", req.PostForm.Get("Action"))
+		assert.Equal(t, `{"id":"1"}`, req.PostForm.Get("MessageBody"))
+		assert.Equal(t, "account", req.PostForm.Get("MessageAttribute.1.Name"))
+		assert.Equal(t, "GABC", req.PostForm.Get("MessageAttribute.1.Value.StringValue"))
+		assert.Equal(t, "asset_code", req.PostForm.Get("MessageAttribute.2.Name"))
+		assert.Equal(t, "USD", req.PostForm.Get("MessageAttribute.2.Value.StringValue"))
+
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			w.Write([]byte(`<ErrorResponse><Error><Code>AWS.SimpleQueueService.NonExistentQueue</Code><Message>Queue does not exist</Message></Error></ErrorResponse>`))
+		}
+	}))
+	defer srv.Close()
+
+	publisher := NewSQSPublisherWithCredentials(
+		srv.URL+"/123456789012/payments",
+		"us-east-1",
+		credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		http.DefaultClient,
+	)
+
+	attributes := map[string]string{"asset_code": "USD", "account": "GABC"}
+	assert.NoError(t, publisher.Publish([]byte(`{"id":"1"}`), attributes))
+
+	status = http.StatusBadRequest
+	assert.EqualError(t, publisher.Publish([]byte(`{"id":"1"}`), attributes), "SQS error: AWS.SimpleQueueService.NonExistentQueue Queue does not exist")
+}
+
+func TestSQSRegion(t *testing.T) {
+	assert.Equal(t, "eu-west-1", sqsRegion("https://sqs.eu-west-1.amazonaws.com/123456789012/payments"))
+	assert.Equal(t, "", sqsRegion("http://localhost:9324/queue/payments"))
+}
+
+func TestPubSubPublisher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1/projects/bridge/topics/payments:publish", req.URL.Path)
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+		var body struct {
+			Messages []pubSubMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		require.Len(t, body.Messages, 1)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(`{"id":"1"}`)), body.Messages[0].Data)
+		assert.Equal(t, "USD", body.Messages[0].Attributes["asset_code"])
+
+		w.Write([]byte(`{"messageIds":["123"]}`))
+	}))
+	defer srv.Close()
+
+	publisher := NewPubSubPublisher(config.PubSub{
+		Project:     "bridge",
+		Topic:       "payments",
+		AccessToken: "token",
+		Endpoint:    srv.URL,
+	}, http.DefaultClient)
+
+	assert.NoError(t, publisher.Publish([]byte(`{"id":"1"}`), map[string]string{"asset_code": "USD"}))
+}
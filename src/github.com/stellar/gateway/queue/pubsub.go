@@ -0,0 +1,162 @@
+package queue
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	pubSubEndpoint = "https://pubsub.googleapis.com"
+	// Access tokens of a default service account are served by metadata server
+	// on GCE, GKE and Cloud Run.
+	metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// PubSubPublisher publishes messages to Google Cloud Pub/Sub topic using REST API.
+// When AccessToken is empty, token is obtained from GCE metadata server. No
+// authorization is used when Endpoint points to Pub/Sub emulator.
+type PubSubPublisher struct {
+	Client      HTTP
+	Endpoint    string
+	Project     string
+	Topic       string
+	AccessToken string
+
+	tokenMutex  sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	now         func() time.Time
+}
+
+// NewPubSubPublisher creates a new PubSubPublisher
+func NewPubSubPublisher(pubsub config.PubSub, client HTTP) *PubSubPublisher {
+	endpoint := pubsub.Endpoint
+	if endpoint == "" {
+		endpoint = pubSubEndpoint
+	}
+
+	return &PubSubPublisher{
+		Client:      client,
+		Endpoint:    strings.TrimRight(endpoint, "/"),
+		Project:     pubsub.Project,
+		Topic:       pubsub.Topic,
+		AccessToken: pubsub.AccessToken,
+		now:         clock.Now,
+	}
+}
+
+type pubSubMessage struct {
+	Data       string            `json:"data"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Publish publishes a message to the topic
+func (p *PubSubPublisher) Publish(message []byte, attributes map[string]string) error {
+	body, err := json.Marshal(struct {
+		Messages []pubSubMessage `json:"messages"`
+	}{[]pubSubMessage{{
+		Data:       base64.StdEncoding.EncodeToString(message),
+		Attributes: attributes,
+	}}})
+	if err != nil {
+		return errors.Wrap(err, "Error encoding Pub/Sub request")
+	}
+
+	endpoint := p.Endpoint + "/v1/projects/" + url.PathEscape(p.Project) + "/topics/" + url.PathEscape(p.Topic) + ":publish"
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "configure http request failed")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if p.Endpoint == pubSubEndpoint || p.AccessToken != "" {
+		token, err := p.accessToken()
+		if err != nil {
+			return errors.Wrap(err, "Cannot obtain Pub/Sub access token")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error sending request to Pub/Sub")
+	}
+
+	defer resp.Body.Close()
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "Error reading Pub/Sub response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Error response from Pub/Sub: %d %s", resp.StatusCode, responseBody)
+	}
+
+	var publishResponse struct {
+		MessageIds []string `json:"messageIds"`
+	}
+	err = json.Unmarshal(responseBody, &publishResponse)
+	if err != nil {
+		return errors.Wrap(err, "Cannot unmarshal Pub/Sub response")
+	}
+
+	if len(publishResponse.MessageIds) != 1 {
+		return errors.New("Pub/Sub did not return message ID")
+	}
+
+	return nil
+}
+
+func (p *PubSubPublisher) accessToken() (string, error) {
+	if p.AccessToken != "" {
+		return p.AccessToken, nil
+	}
+
+	p.tokenMutex.Lock()
+	defer p.tokenMutex.Unlock()
+
+	if p.token != "" && p.now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	req, err := http.NewRequest("GET", metadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "Error sending request to metadata server")
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("Error response from metadata server: %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", errors.Wrap(err, "Cannot unmarshal metadata server response")
+	}
+
+	p.token = token.AccessToken
+	// Refresh a minute before token expires
+	p.tokenExpiry = p.now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return p.token, nil
+}
@@ -0,0 +1,145 @@
+package queue
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/stellar/gateway/bridge/config"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/go/support/errors"
+)
+
+// SQSPublisher publishes messages to AWS SQS queue using SQS Query API.
+// Requests are signed using AWS Signature Version 4.
+type SQSPublisher struct {
+	Client   HTTP
+	QueueURL string
+	Region   string
+	Signer   *v4.Signer
+	now      func() time.Time
+}
+
+// NewSQSPublisher creates a new SQSPublisher. Credentials are loaded from
+// default AWS credentials chain (env variables, shared credentials file,
+// EC2/ECS roles).
+func NewSQSPublisher(sqs config.SQS, client HTTP) (*SQSPublisher, error) {
+	region := sqs.Region
+	if region == "" {
+		region = sqsRegion(sqs.QueueURL)
+	}
+
+	if region == "" {
+		return nil, errors.New("Cannot determine SQS region")
+	}
+
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot create AWS session")
+	}
+
+	return &SQSPublisher{
+		Client:   client,
+		QueueURL: sqs.QueueURL,
+		Region:   region,
+		Signer:   v4.NewSigner(sess.Config.Credentials),
+		now:      clock.Now,
+	}, nil
+}
+
+// NewSQSPublisherWithCredentials creates a new SQSPublisher using given credentials
+func NewSQSPublisherWithCredentials(queueURL, region string, creds *credentials.Credentials, client HTTP) *SQSPublisher {
+	return &SQSPublisher{
+		Client:   client,
+		QueueURL: queueURL,
+		Region:   region,
+		Signer:   v4.NewSigner(creds),
+		now:      clock.Now,
+	}
+}
+
+type sqsErrorResponse struct {
+	Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// Publish sends a message to the queue
+func (p *SQSPublisher) Publish(message []byte, attributes map[string]string) error {
+	form := url.Values{
+		"Action":      {"SendMessage"},
+		"Version":     {"2012-11-05"},
+		"MessageBody": {string(message)},
+	}
+
+	// SQS requires attributes to be numbered, sort names to get stable requests
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		prefix := fmt.Sprintf("MessageAttribute.%d.", i+1)
+		form.Set(prefix+"Name", name)
+		form.Set(prefix+"Value.DataType", "String")
+		form.Set(prefix+"Value.StringValue", attributes[name])
+	}
+
+	body := strings.NewReader(form.Encode())
+	req, err := http.NewRequest("POST", p.QueueURL, body)
+	if err != nil {
+		return errors.Wrap(err, "configure http request failed")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, err = p.Signer.Sign(req, body, "sqs", p.Region, p.now())
+	if err != nil {
+		return errors.Wrap(err, "Cannot sign SQS request")
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Error sending request to SQS")
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		responseBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrap(err, "Error reading SQS response")
+		}
+
+		var errorResponse sqsErrorResponse
+		if xml.Unmarshal(responseBody, &errorResponse) == nil && errorResponse.Error.Code != "" {
+			return errors.Errorf("SQS error: %s %s", errorResponse.Error.Code, errorResponse.Error.Message)
+		}
+		return errors.Errorf("Error response from SQS: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// sqsRegion returns region from SQS queue URL, ex.
+// https://sqs.us-east-1.amazonaws.com/123456789012/payments
+func sqsRegion(queueURL string) string {
+	u, err := url.Parse(queueURL)
+	if err != nil {
+		return ""
+	}
+
+	parts := strings.Split(u.Hostname(), ".")
+	if len(parts) < 4 || parts[0] != "sqs" {
+		return ""
+	}
+	return parts[1]
+}
@@ -0,0 +1,124 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/go/support/errors"
+)
+
+// maxResponseSize is the max size of a response of the rates API
+const maxResponseSize = 64 * 1024
+
+// HTTP represents an http client that HTTPProvider uses to make HTTP requests
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// HTTPProvider gets rates from an external API: it sends a GET request to
+// URL with asset_code, asset_issuer and currency query params and expects
+// `{"rate": "1.08"}` in a `200 OK` response, `404 Not Found` when there is no
+// rate. Rates are cached for CacheTTL.
+type HTTPProvider struct {
+	URL      string
+	Client   HTTP
+	CacheTTL time.Duration
+
+	mutex sync.Mutex
+	cache map[string]cachedRate
+}
+
+type cachedRate struct {
+	rate      string
+	expiresAt time.Time
+}
+
+// NewHTTPProvider creates a new HTTPProvider
+func NewHTTPProvider(url string, client HTTP, cacheTTL time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		URL:      url,
+		Client:   client,
+		CacheTTL: cacheTTL,
+		cache:    map[string]cachedRate{},
+	}
+}
+
+// Rate implements Provider
+func (p *HTTPProvider) Rate(ctx context.Context, assetCode, assetIssuer, currency string) (string, error) {
+	key := assetCode + ":" + assetIssuer + ":" + currency
+	now := clock.Now()
+
+	p.mutex.Lock()
+	cached, ok := p.cache[key]
+	p.mutex.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.rate, nil
+	}
+
+	rate, err := p.get(ctx, assetCode, assetIssuer, currency)
+	if err != nil {
+		return "", err
+	}
+
+	if p.CacheTTL > 0 {
+		p.mutex.Lock()
+		p.cache[key] = cachedRate{rate: rate, expiresAt: now.Add(p.CacheTTL)}
+		p.mutex.Unlock()
+	}
+	return rate, nil
+}
+
+func (p *HTTPProvider) get(ctx context.Context, assetCode, assetIssuer, currency string) (string, error) {
+	query := url.Values{
+		"asset_code":   {assetCode},
+		"asset_issuer": {assetIssuer},
+		"currency":     {currency},
+	}
+
+	req, err := http.NewRequest("GET", p.URL+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "Error sending request to rates API")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrNoRate
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Rates API returned status %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Rate string `json:"rate"`
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return "", errors.Wrap(err, "Cannot decode rates API response")
+	}
+
+	_, err = ParseRate(response.Rate)
+	if err != nil {
+		return "", err
+	}
+	return response.Rate, nil
+}
@@ -0,0 +1,65 @@
+// Package rates provides exchange rates of assets to fiat currencies and
+// records fiat-equivalent values of received and sent payments at the rate of
+// the time they were processed, for accounting. Rates are provided by a fixed
+// table (Fixed) or an external HTTP API (HTTPProvider).
+package rates
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// ErrNoRate is returned by providers that don't have a rate of an asset in a
+// currency
+var ErrNoRate = errors.New("No exchange rate of the asset")
+
+// Provider returns the price of one unit of an asset (asset code XLM and an
+// empty issuer for lumens) in a currency as a decimal string
+type Provider interface {
+	Rate(ctx context.Context, assetCode, assetIssuer, currency string) (string, error)
+}
+
+// ParseRate parses a positive decimal rate
+func ParseRate(rate string) (*big.Rat, error) {
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok || strings.ContainsAny(rate, "/eE") || value.Sign() <= 0 {
+		return nil, errors.New("Invalid exchange rate: " + rate)
+	}
+	return value, nil
+}
+
+// FixedRate is a rate of Fixed provider. Rates with an empty AssetIssuer
+// match all issuers of the asset code.
+type FixedRate struct {
+	AssetCode   string
+	AssetIssuer string
+	Currency    string
+	Rate        string
+}
+
+// Fixed is a Provider returning rates from a fixed table
+type Fixed []FixedRate
+
+// Rate implements Provider. A rate of the issuer is preferred to a rate
+// matching all issuers.
+func (f Fixed) Rate(ctx context.Context, assetCode, assetIssuer, currency string) (string, error) {
+	rate := ""
+	for _, fixed := range f {
+		if fixed.AssetCode != assetCode || !strings.EqualFold(fixed.Currency, currency) {
+			continue
+		}
+		if fixed.AssetIssuer == assetIssuer {
+			return fixed.Rate, nil
+		}
+		if fixed.AssetIssuer == "" {
+			rate = fixed.Rate
+		}
+	}
+
+	if rate == "" {
+		return "", ErrNoRate
+	}
+	return rate, nil
+}
@@ -0,0 +1,169 @@
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const issuer = "GD4I7AFSLZGTDL34TQLWJOM2NHLIIOEKD5RHHZUW54HERBLSIRKUOXRR"
+
+func TestFixed(t *testing.T) {
+	fixed := Fixed{
+		{AssetCode: "USD", Currency: "EUR", Rate: "0.9"},
+		{AssetCode: "USD", AssetIssuer: issuer, Currency: "EUR", Rate: "0.92"},
+		{AssetCode: "XLM", Currency: "EUR", Rate: "0.1"},
+	}
+
+	rate, err := fixed.Rate(context.Background(), "USD", issuer, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "0.92", rate)
+
+	rate, err = fixed.Rate(context.Background(), "USD", "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE", "eur")
+	require.NoError(t, err)
+	assert.Equal(t, "0.9", rate)
+
+	_, err = fixed.Rate(context.Background(), "XLM", "", "USD")
+	assert.Equal(t, ErrNoRate, err)
+}
+
+func TestParseRate(t *testing.T) {
+	rate, err := ParseRate("1.08")
+	require.NoError(t, err)
+	assert.Equal(t, "27/25", rate.String())
+
+	for _, invalid := range []string{"", "0", "-1", "1/3", "1e3", "rate"} {
+		_, err = ParseRate(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	fiat, err := FiatAmount("10.5", "0.333")
+	require.NoError(t, err)
+	assert.Equal(t, "3.4965000", fiat)
+}
+
+func TestHTTPProvider(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, issuer, r.URL.Query().Get("asset_issuer"))
+		switch r.URL.Query().Get("currency") {
+		case "EUR":
+			w.Write([]byte(`{"rate": "0.92"}`))
+		case "JPY":
+			w.Write([]byte(`{"rate": "-1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(server.URL, http.DefaultClient, time.Minute)
+	for i := 0; i < 2; i++ {
+		rate, err := provider.Rate(context.Background(), "USD", issuer, "EUR")
+		require.NoError(t, err)
+		assert.Equal(t, "0.92", rate)
+	}
+	// Second rate is cached
+	assert.Equal(t, 1, requests)
+
+	_, err := provider.Rate(context.Background(), "USD", issuer, "GBP")
+	assert.Equal(t, ErrNoRate, err)
+
+	_, err = provider.Rate(context.Background(), "USD", issuer, "JPY")
+	assert.Error(t, err)
+}
+
+func TestRecorder(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+	recorder := NewRecorder(Fixed{
+		{AssetCode: "USD", Currency: "EUR", Rate: "0.9"},
+		{AssetCode: "XLM", Currency: "EUR", Rate: "0.1"},
+		{AssetCode: "XLM", Currency: "USD", Rate: "0.12"},
+	}, repository, db.NewEntityManager(driver), "EUR", map[string]string{"acme": "USD"})
+
+	processedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	payment := horizon.PaymentResponse{
+		ID:          "1",
+		Type:        "payment",
+		AssetType:   "credit_alphanum4",
+		AssetCode:   "USD",
+		AssetIssuer: issuer,
+		Amount:      "10.5",
+	}
+	value := recorder.RecordReceived(context.Background(), payment, processedAt)
+	require.NotNil(t, value)
+	assert.Equal(t, "EUR", value.Currency)
+	assert.Equal(t, "0.9", value.Rate)
+	assert.Equal(t, "9.4500000", value.FiatAmount)
+
+	// Reprocessed payment keeps the value of the first time
+	recorder.provider = Fixed{{AssetCode: "USD", Currency: "EUR", Rate: "1"}}
+	value = recorder.RecordReceived(context.Background(), payment, processedAt.Add(time.Hour))
+	require.NotNil(t, value)
+	assert.Equal(t, "9.4500000", value.FiatAmount)
+	assert.Equal(t, processedAt, value.CreatedAt.UTC())
+
+	// No rate
+	assert.Nil(t, recorder.RecordReceived(context.Background(), horizon.PaymentResponse{ID: "2", AssetCode: "BTC", Amount: "1"}, processedAt))
+
+	// Tenant of the API key is read from the context of the request
+	ctx := auth.WithKey(context.Background(), &auth.Key{ID: "acme-key", Tenant: "acme"})
+
+	recorder.provider = Fixed{{AssetCode: "XLM", Currency: "USD", Rate: "0.12"}}
+	recorder.RecordSent(ctx, &entities.SentTransaction{
+		TransactionID: "tx",
+		Status:        entities.SentTransactionStatusSuccess,
+		SucceededAt:   &processedAt,
+		EnvelopeXdr:   nativePaymentEnvelope(t, 1000000000),
+	})
+
+	value, err = repository.GetFiatValue(context.Background(), entities.FiatValueSent, "tx:0")
+	require.NoError(t, err)
+	require.NotNil(t, value)
+	assert.Equal(t, "acme", value.Tenant)
+	assert.Equal(t, "USD", value.Currency)
+	assert.Equal(t, "12.0000000", value.FiatAmount)
+
+	// nil Recorder does nothing
+	var disabled *Recorder
+	assert.Nil(t, disabled.RecordReceived(context.Background(), payment, processedAt))
+	disabled.RecordSent(ctx, &entities.SentTransaction{Status: entities.SentTransactionStatusSuccess})
+}
+
+func nativePaymentEnvelope(t *testing.T, amount int64) string {
+	var source, destination xdr.AccountId
+	require.NoError(t, source.SetAddress(issuer))
+	require.NoError(t, destination.SetAddress("GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"))
+
+	body, err := xdr.NewOperationBody(xdr.OperationTypePayment, xdr.PaymentOp{
+		Destination: destination,
+		Asset:       xdr.Asset{Type: xdr.AssetTypeAssetTypeNative},
+		Amount:      xdr.Int64(amount),
+	})
+	require.NoError(t, err)
+
+	encoded, err := xdr.MarshalBase64(xdr.TransactionEnvelope{Tx: xdr.Transaction{
+		SourceAccount: source,
+		Operations:    []xdr.Operation{{Body: body}},
+	}})
+	require.NoError(t, err)
+	return encoded
+}
@@ -0,0 +1,169 @@
+package rates
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/export"
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+var recorderMetrics = struct {
+	errors *metrics.Counter
+}{
+	errors: metrics.NewCounter("bridge_fiat_value_errors_total", "Number of payments without a fiat value because of an error."),
+}
+
+// Recorder records fiat values of payments in the base currency of their
+// tenant. Errors are logged and never block payments. A nil *Recorder does
+// nothing, so it can be used when rates are not configured.
+type Recorder struct {
+	provider      Provider
+	repository    db.RepositoryInterface
+	entityManager db.EntityManagerInterface
+	baseCurrency  string
+	// tenantCurrencies are base currencies of tenants by tenant ID
+	tenantCurrencies map[string]string
+	log              *logrus.Entry
+}
+
+// NewRecorder creates a new Recorder
+func NewRecorder(provider Provider, repository db.RepositoryInterface, entityManager db.EntityManagerInterface, baseCurrency string, tenantCurrencies map[string]string) *Recorder {
+	return &Recorder{
+		provider:         provider,
+		repository:       repository,
+		entityManager:    entityManager,
+		baseCurrency:     baseCurrency,
+		tenantCurrencies: tenantCurrencies,
+		log:              logrus.WithFields(logrus.Fields{"service": "Recorder"}),
+	}
+}
+
+// Currency returns the base currency of the tenant
+func (r *Recorder) Currency(tenant string) string {
+	if currency, ok := r.tenantCurrencies[tenant]; ok {
+		return currency
+	}
+	return r.baseCurrency
+}
+
+// RecordReceived records the fiat value of a payment received at processedAt
+// and returns it, nil when it cannot be recorded. The value recorded the first
+// time is returned for reprocessed payments. Amount and asset of
+// account_merge operations must be loaded.
+func (r *Recorder) RecordReceived(ctx context.Context, payment horizon.PaymentResponse, processedAt time.Time) *entities.FiatValue {
+	if r == nil {
+		return nil
+	}
+
+	fields := logrus.Fields{"operation_id": payment.ID}
+	amount, err := amounts.Parse(payment.Amount)
+	if err != nil {
+		r.fail(err, fields)
+		return nil
+	}
+
+	assetCode := payment.AssetCode
+	if payment.AssetType == "native" || payment.Type == "account_merge" {
+		assetCode = "XLM"
+	}
+
+	return r.record(ctx, &entities.FiatValue{
+		Direction:   entities.FiatValueReceived,
+		Reference:   payment.ID,
+		AssetCode:   assetCode,
+		AssetIssuer: payment.AssetIssuer,
+		Amount:      amounts.String(amount),
+		Currency:    r.Currency(""),
+		CreatedAt:   processedAt,
+	}, fields)
+}
+
+// RecordSent records fiat values of payments of a successful transaction. The
+// tenant is read from ctx, see features.TenantFromContext.
+func (r *Recorder) RecordSent(ctx context.Context, transaction *entities.SentTransaction) {
+	if r == nil || transaction.Status != entities.SentTransactionStatusSuccess {
+		return
+	}
+
+	fields := logrus.Fields{"transaction_id": transaction.TransactionID}
+	entries, err := export.SentEntries(transaction)
+	if err != nil {
+		r.fail(err, fields)
+		return
+	}
+
+	tenant := features.TenantFromContext(ctx)
+	for _, entry := range entries {
+		r.record(ctx, &entities.FiatValue{
+			Direction:   entities.FiatValueSent,
+			Reference:   entry.Reference,
+			Tenant:      tenant,
+			AssetCode:   entry.AssetCode,
+			AssetIssuer: entry.AssetIssuer,
+			Amount:      amounts.String(entry.Amount),
+			Currency:    r.Currency(tenant),
+			CreatedAt:   entry.Time,
+		}, fields)
+	}
+}
+
+func (r *Recorder) record(ctx context.Context, value *entities.FiatValue, fields logrus.Fields) *entities.FiatValue {
+	existing, err := r.repository.GetFiatValue(ctx, value.Direction, value.Reference)
+	if err != nil {
+		r.fail(err, fields)
+		return nil
+	}
+
+	if existing != nil {
+		return existing
+	}
+
+	value.Rate, err = r.provider.Rate(ctx, value.AssetCode, value.AssetIssuer, value.Currency)
+	if err != nil {
+		r.fail(err, fields)
+		return nil
+	}
+
+	value.FiatAmount, err = FiatAmount(value.Amount, value.Rate)
+	if err != nil {
+		r.fail(err, fields)
+		return nil
+	}
+
+	err = r.entityManager.Persist(value)
+	if err != nil {
+		r.fail(err, fields)
+		return nil
+	}
+	return value
+}
+
+// FiatAmount returns amount multiplied by rate with 7 decimal places
+func FiatAmount(amount, rate string) (string, error) {
+	value, err := amounts.Parse(amount)
+	if err != nil {
+		return "", err
+	}
+
+	parsedRate, err := ParseRate(rate)
+	if err != nil {
+		return "", err
+	}
+
+	fiat := new(big.Rat).Mul(big.NewRat(value, amounts.One), parsedRate)
+	return fiat.FloatString(7), nil
+}
+
+func (r *Recorder) fail(err error, fields logrus.Fields) {
+	recorderMetrics.errors.Inc()
+	fields["err"] = err
+	r.log.WithFields(fields).Error("Error recording fiat value")
+}
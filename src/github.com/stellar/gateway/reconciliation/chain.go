@@ -0,0 +1,274 @@
+package reconciliation
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/audit"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/go/xdr"
+)
+
+// chainPageSize is the number of sent transactions loaded from DB at once
+const chainPageSize = 100
+
+var (
+	mismatchesCounter = metrics.NewCounter("bridge_chain_mismatches_total", "Number of sent transactions with status corrected after comparing with Horizon.")
+	unconfirmedGauge  = metrics.NewGauge("bridge_chain_unconfirmed_transactions", "Number of sent transactions whose ledger state could not be confirmed in the last check.")
+)
+
+// StatusPublisher publishes statuses of sent transactions. It's implemented
+// by submitter.TransactionSubmitter.
+type StatusPublisher interface {
+	PublishStatusEvent(sentTransaction *entities.SentTransaction)
+}
+
+// Mismatch is a sent transaction whose status in DB did not match the ledger
+// state. Status is the status before the correction.
+type Mismatch struct {
+	TransactionID string                         `json:"transaction_id"`
+	PaymentID     *string                        `json:"payment_id"`
+	Status        entities.SentTransactionStatus `json:"status"`
+	ChainStatus   entities.SentTransactionStatus `json:"chain_status"`
+}
+
+// ChainReconciler periodically compares statuses of recently sent
+// transactions with Horizon. Transactions included in a ledger are marked as
+// succeeded or failed depending on their result. Transactions not found in
+// Horizon are marked as failed when the sequence number of the source account
+// has already passed them, so they cannot be included in a ledger anymore.
+// Every correction is logged, published to StatusEvents and sent as
+// a security event because it means the books have drifted from the ledger.
+type ChainReconciler struct {
+	Repository    db.RepositoryInterface
+	EntityManager db.EntityManagerInterface
+	Horizon       horizon.HorizonInterface
+	// StatusEvents receives corrected transactions, nil when not used
+	StatusEvents StatusPublisher
+	// Audit receives mismatches, nil when security events are not sent
+	Audit *audit.Emitter
+	// Lookback is the max age of checked transactions
+	Lookback time.Duration
+	// MinAge is the min age of checked transactions. Younger transactions can
+	// still be submitted.
+	MinAge time.Duration
+
+	now func() time.Time
+	log *logrus.Entry
+	// verified contains IDs of transactions in final state confirmed in Horizon
+	verified map[int64]bool
+}
+
+// NewChainReconciler creates a new ChainReconciler
+func NewChainReconciler(
+	repository db.RepositoryInterface,
+	entityManager db.EntityManagerInterface,
+	h horizon.HorizonInterface,
+	lookback time.Duration,
+	minAge time.Duration,
+) *ChainReconciler {
+	return &ChainReconciler{
+		Repository:    repository,
+		EntityManager: entityManager,
+		Horizon:       h,
+		Lookback:      lookback,
+		MinAge:        minAge,
+		now:           clock.Now,
+		log:           logrus.WithFields(logrus.Fields{"service": "ChainReconciler"}),
+		verified:      map[int64]bool{},
+	}
+}
+
+// Run checks sent transactions submitted between Lookback and MinAge ago that
+// have not been confirmed yet and corrects their statuses
+func (r *ChainReconciler) Run(ctx context.Context) ([]Mismatch, error) {
+	now := r.now()
+	from := now.Add(-r.Lookback)
+	to := now.Add(-r.MinAge)
+	filter := db.SentTransactionsFilter{From: &from, To: &to}
+
+	mismatches := []Mismatch{}
+	verified := map[int64]bool{}
+	sequences := map[string]xdr.SequenceNumber{}
+	var unconfirmed int
+
+	for page := 1; ; page++ {
+		transactions, err := r.Repository.GetSentTransactions(ctx, filter, page, chainPageSize)
+		if err != nil {
+			r.log.WithFields(logrus.Fields{"err": err}).Error("Error loading sent transactions")
+			return nil, err
+		}
+
+		for _, transaction := range transactions {
+			if transaction.ID == nil {
+				continue
+			}
+			if r.verified[*transaction.ID] {
+				verified[*transaction.ID] = true
+				continue
+			}
+
+			status, response, final, err := r.chainStatus(transaction, sequences)
+			if err != nil {
+				r.log.WithFields(logrus.Fields{"err": err, "transaction_id": transaction.TransactionID}).Warn("Error checking sent transaction in Horizon")
+			}
+			if !final {
+				unconfirmed++
+				continue
+			}
+
+			if status != transaction.Status {
+				mismatch, err := r.correct(transaction, status, response)
+				if err != nil {
+					r.log.WithFields(logrus.Fields{"err": err, "transaction_id": transaction.TransactionID}).Error("Error correcting sent transaction")
+					continue
+				}
+				mismatches = append(mismatches, mismatch)
+			}
+			verified[*transaction.ID] = true
+		}
+
+		if len(transactions) < chainPageSize {
+			break
+		}
+	}
+
+	// Transactions older than Lookback are dropped
+	r.verified = verified
+	unconfirmedGauge.Set(float64(unconfirmed))
+	return mismatches, nil
+}
+
+// chainStatus returns the status of transaction based on the ledger state
+// and the transaction loaded from Horizon (nil when it's not in a ledger).
+// final is false when the transaction can still be included in a ledger or
+// Horizon could not be reached.
+func (r *ChainReconciler) chainStatus(
+	transaction *entities.SentTransaction,
+	sequences map[string]xdr.SequenceNumber,
+) (status entities.SentTransactionStatus, response *horizon.SubmitTransactionResponse, final bool, err error) {
+	loaded, err := r.Horizon.LoadTransaction(transaction.TransactionID)
+	if err == nil {
+		status = entities.SentTransactionStatusFailure
+		if successful(loaded) {
+			status = entities.SentTransactionStatusSuccess
+		}
+		return status, &loaded, true, nil
+	}
+	if err != horizon.ErrTransactionNotFound {
+		return
+	}
+
+	var envelope xdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(transaction.EnvelopeXdr, &envelope)
+	if err != nil {
+		return
+	}
+
+	source := envelope.Tx.SourceAccount.Address()
+	sequence, ok := sequences[source]
+	if !ok {
+		var account horizon.AccountResponse
+		account, err = r.Horizon.LoadAccountFresh(source)
+		if err != nil {
+			return
+		}
+
+		var value int64
+		value, err = strconv.ParseInt(account.SequenceNumber, 10, 64)
+		if err != nil {
+			return
+		}
+		sequence = xdr.SequenceNumber(value)
+		sequences[source] = sequence
+	}
+
+	if sequence < envelope.Tx.SeqNum {
+		// Transaction can still be included in a ledger
+		return "", nil, false, nil
+	}
+
+	return entities.SentTransactionStatusFailure, nil, true, nil
+}
+
+// correct saves status of transaction and reports the mismatch
+func (r *ChainReconciler) correct(
+	transaction *entities.SentTransaction,
+	status entities.SentTransactionStatus,
+	response *horizon.SubmitTransactionResponse,
+) (mismatch Mismatch, err error) {
+	mismatch = Mismatch{
+		TransactionID: transaction.TransactionID,
+		PaymentID:     transaction.PaymentID,
+		Status:        transaction.Status,
+		ChainStatus:   status,
+	}
+
+	transaction.Status = status
+	transaction.Ledger = nil
+	transaction.SucceededAt = nil
+	if response != nil {
+		transaction.Ledger = response.Ledger
+		transaction.ResultXdr = response.ResultXdr
+	}
+	if status == entities.SentTransactionStatusSuccess {
+		succeededAt := r.now()
+		transaction.SucceededAt = &succeededAt
+	} else if transaction.ResultXdr == nil {
+		result := "<empty>"
+		transaction.ResultXdr = &result
+	}
+
+	err = r.EntityManager.Persist(transaction)
+	if err != nil {
+		return
+	}
+
+	mismatchesCounter.Inc()
+	r.log.WithFields(logrus.Fields{
+		"transaction_id": transaction.TransactionID,
+		"payment_id":     transaction.PaymentID,
+		"status":         mismatch.Status,
+		"chain_status":   mismatch.ChainStatus,
+	}).Error("Sent transaction status does not match the ledger, status corrected")
+
+	if r.StatusEvents != nil {
+		r.StatusEvents.PublishStatusEvent(transaction)
+	}
+
+	details := map[string]string{
+		"transaction_id": transaction.TransactionID,
+		"status":         string(mismatch.Status),
+		"chain_status":   string(mismatch.ChainStatus),
+	}
+	if transaction.PaymentID != nil {
+		details["payment_id"] = *transaction.PaymentID
+	}
+	r.Audit.Emit(audit.Event{
+		Type:    audit.ChainMismatch,
+		Account: transaction.Source,
+		Details: details,
+	})
+	return
+}
+
+// successful returns true when the transaction loaded from Horizon succeeded.
+// Old Horizon versions return successful transactions only.
+func successful(response horizon.SubmitTransactionResponse) bool {
+	if response.ResultXdr == nil {
+		return true
+	}
+
+	var result xdr.TransactionResult
+	err := xdr.SafeUnmarshalBase64(*response.ResultXdr, &result)
+	if err != nil {
+		return true
+	}
+	return result.Result.Code == xdr.TransactionResultCodeTxSuccess
+}
@@ -0,0 +1,146 @@
+// Package reconciliation detects payments submitted by more than one region
+// (for example during split-brain periods of cross-region deployments) after
+// regional databases are merged or replicated.
+package reconciliation
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+)
+
+const (
+	// ResolutionResolved is a resolution of a conflict where exactly one transaction succeeded
+	ResolutionResolved = "resolved"
+	// ResolutionDuplicate is a resolution of a conflict where more than one transaction succeeded.
+	// The payment has been sent more than once and needs manual action.
+	ResolutionDuplicate = "duplicate"
+	// ResolutionPending is a resolution of a conflict where no transaction succeeded and some are still sending
+	ResolutionPending = "pending"
+	// ResolutionFailed is a resolution of a conflict where all transactions failed
+	ResolutionFailed = "failed"
+)
+
+var (
+	conflictsGauge  = metrics.NewGauge("bridge_region_conflicts", "Number of payment IDs submitted by more than one region.")
+	duplicatesGauge = metrics.NewGauge("bridge_region_duplicate_payments", "Number of payment IDs successfully sent by more than one region.")
+)
+
+// Conflict represents payment ID submitted by more than one region
+type Conflict struct {
+	PaymentID    string                      `json:"payment_id"`
+	Regions      []string                    `json:"regions"`
+	Resolution   string                      `json:"resolution"`
+	Transactions []*entities.SentTransaction `json:"transactions"`
+}
+
+// Reconciler periodically checks sent transactions for conflicts
+type Reconciler struct {
+	Repository db.RepositoryInterface
+	log        *logrus.Entry
+}
+
+// NewReconciler creates a new Reconciler
+func NewReconciler(repository db.RepositoryInterface) *Reconciler {
+	return &Reconciler{
+		Repository: repository,
+		log:        logrus.WithFields(logrus.Fields{"service": "Reconciler"}),
+	}
+}
+
+// Run loads conflicting sent transactions, logs conflicts that need attention
+// and updates metrics
+func (r *Reconciler) Run(ctx context.Context) ([]Conflict, error) {
+	transactions, err := r.Repository.GetSentTransactionConflicts(ctx)
+	if err != nil {
+		r.log.WithFields(logrus.Fields{"err": err}).Error("Error loading sent transaction conflicts")
+		return nil, err
+	}
+
+	conflicts := Group(transactions)
+
+	var duplicates int
+	for _, conflict := range conflicts {
+		fields := logrus.Fields{
+			"payment_id": conflict.PaymentID,
+			"regions":    conflict.Regions,
+			"resolution": conflict.Resolution,
+		}
+
+		switch conflict.Resolution {
+		case ResolutionDuplicate:
+			duplicates++
+			r.log.WithFields(fields).Error("Payment sent by more than one region")
+		case ResolutionPending:
+			r.log.WithFields(fields).Warn("Payment submitted by more than one region is still sending")
+		}
+	}
+
+	conflictsGauge.Set(float64(len(conflicts)))
+	duplicatesGauge.Set(float64(duplicates))
+	return conflicts, nil
+}
+
+// Group groups transactions (ordered by payment ID) into conflicts
+func Group(transactions []*entities.SentTransaction) []Conflict {
+	conflicts := []Conflict{}
+
+	for _, transaction := range transactions {
+		if transaction.PaymentID == nil {
+			continue
+		}
+
+		last := len(conflicts) - 1
+		if last < 0 || conflicts[last].PaymentID != *transaction.PaymentID {
+			conflicts = append(conflicts, Conflict{PaymentID: *transaction.PaymentID})
+			last++
+		}
+
+		conflict := &conflicts[last]
+		conflict.Transactions = append(conflict.Transactions, transaction)
+		if !contains(conflict.Regions, transaction.Region) {
+			conflict.Regions = append(conflict.Regions, transaction.Region)
+		}
+	}
+
+	for i := range conflicts {
+		conflicts[i].Resolution = resolve(conflicts[i].Transactions)
+	}
+
+	return conflicts
+}
+
+func resolve(transactions []*entities.SentTransaction) string {
+	var succeeded, sending int
+	for _, transaction := range transactions {
+		switch transaction.Status {
+		case entities.SentTransactionStatusSuccess:
+			succeeded++
+		case entities.SentTransactionStatusSending:
+			sending++
+		}
+	}
+
+	switch {
+	case succeeded > 1:
+		return ResolutionDuplicate
+	case succeeded == 1:
+		return ResolutionResolved
+	case sending > 0:
+		return ResolutionPending
+	default:
+		return ResolutionFailed
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
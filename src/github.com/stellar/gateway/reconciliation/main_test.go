@@ -0,0 +1,158 @@
+package reconciliation
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func sentTransaction(paymentID, region string, status entities.SentTransactionStatus) *entities.SentTransaction {
+	return &entities.SentTransaction{PaymentID: &paymentID, Region: region, Status: status}
+}
+
+func TestReconciler(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+	reconciler := NewReconciler(mockRepository)
+
+	mockRepository.On("GetSentTransactionConflicts").Return([]*entities.SentTransaction{
+		sentTransaction("a", "eu", entities.SentTransactionStatusSuccess),
+		sentTransaction("a", "us", entities.SentTransactionStatusSuccess),
+		sentTransaction("b", "eu", entities.SentTransactionStatusSuccess),
+		sentTransaction("b", "us", entities.SentTransactionStatusFailure),
+		sentTransaction("c", "eu", entities.SentTransactionStatusSending),
+		sentTransaction("c", "us", entities.SentTransactionStatusFailure),
+		sentTransaction("d", "eu", entities.SentTransactionStatusFailure),
+		sentTransaction("d", "us", entities.SentTransactionStatusFailure),
+		sentTransaction("d", "us", entities.SentTransactionStatusFailure),
+	}, nil).Once()
+
+	conflicts, err := reconciler.Run(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, conflicts, 4) {
+		assert.Equal(t, ResolutionDuplicate, conflicts[0].Resolution)
+		assert.Equal(t, []string{"eu", "us"}, conflicts[0].Regions)
+		assert.Equal(t, ResolutionResolved, conflicts[1].Resolution)
+		assert.Equal(t, ResolutionPending, conflicts[2].Resolution)
+		assert.Equal(t, ResolutionFailed, conflicts[3].Resolution)
+		assert.Len(t, conflicts[3].Transactions, 3)
+	}
+
+	assert.Equal(t, float64(4), conflictsGauge.Value())
+	assert.Equal(t, float64(1), duplicatesGauge.Value())
+	mockRepository.AssertExpectations(t)
+}
+
+type statusPublisherMock struct {
+	published []*entities.SentTransaction
+}
+
+func (p *statusPublisherMock) PublishStatusEvent(sentTransaction *entities.SentTransaction) {
+	p.published = append(p.published, sentTransaction)
+}
+
+func chainTransaction(t *testing.T, id int64, status entities.SentTransactionStatus, seqNum int64) *entities.SentTransaction {
+	var source xdr.AccountId
+	require.NoError(t, source.SetAddress("GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"))
+
+	envelope, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Tx: xdr.Transaction{SourceAccount: source, SeqNum: xdr.SequenceNumber(seqNum)},
+	})
+	require.NoError(t, err)
+
+	transaction := &entities.SentTransaction{
+		ID:            &id,
+		TransactionID: "tx" + strconv.FormatInt(id, 10),
+		Status:        status,
+		Source:        "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE",
+		EnvelopeXdr:   envelope,
+	}
+	transaction.SetExists()
+	return transaction
+}
+
+func transactionResult(t *testing.T, code xdr.TransactionResultCode) *string {
+	result, err := xdr.NewTransactionResultResult(code, []xdr.OperationResult{})
+	require.NoError(t, err)
+	encoded, err := xdr.MarshalBase64(xdr.TransactionResult{FeeCharged: 100, Result: result})
+	require.NoError(t, err)
+	return &encoded
+}
+
+func TestChainReconciler(t *testing.T) {
+	mockRepository := new(mocks.MockRepository)
+	mockEntityManager := new(mocks.MockEntityManager)
+	mockHorizon := new(mocks.MockHorizon)
+	publisher := &statusPublisherMock{}
+
+	now := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	reconciler := NewChainReconciler(mockRepository, mockEntityManager, mockHorizon, 24*time.Hour, 10*time.Minute)
+	reconciler.now = func() time.Time { return now }
+	reconciler.StatusEvents = publisher
+
+	from := now.Add(-24 * time.Hour)
+	to := now.Add(-10 * time.Minute)
+	filter := db.SentTransactionsFilter{From: &from, To: &to}
+	ledger := uint64(10)
+
+	landed := chainTransaction(t, 1, entities.SentTransactionStatusFailure, 5)
+	lost := chainTransaction(t, 2, entities.SentTransactionStatusSending, 6)
+	pending := chainTransaction(t, 3, entities.SentTransactionStatusSending, 8)
+	failedOnChain := chainTransaction(t, 4, entities.SentTransactionStatusSuccess, 4)
+	confirmed := chainTransaction(t, 5, entities.SentTransactionStatusSuccess, 3)
+	transactions := []*entities.SentTransaction{confirmed, failedOnChain, pending, lost, landed}
+
+	mockRepository.On("GetSentTransactions", filter, 1, chainPageSize).Return(transactions, nil).Twice()
+	mockHorizon.On("LoadTransaction", "tx1").Return(horizon.SubmitTransactionResponse{Ledger: &ledger}, nil).Once()
+	mockHorizon.On("LoadTransaction", "tx2").Return(horizon.SubmitTransactionResponse{}, horizon.ErrTransactionNotFound).Once()
+	mockHorizon.On("LoadTransaction", "tx3").Return(horizon.SubmitTransactionResponse{}, horizon.ErrTransactionNotFound).Twice()
+	mockHorizon.On("LoadTransaction", "tx4").Return(horizon.SubmitTransactionResponse{
+		Ledger:    &ledger,
+		ResultXdr: transactionResult(t, xdr.TransactionResultCodeTxFailed),
+	}, nil).Once()
+	mockHorizon.On("LoadTransaction", "tx5").Return(horizon.SubmitTransactionResponse{
+		Ledger:    &ledger,
+		ResultXdr: transactionResult(t, xdr.TransactionResultCodeTxSuccess),
+	}, nil).Once()
+	// Sequence number is loaded once per account in a single run
+	mockHorizon.On("LoadAccountFresh", "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE").
+		Return(horizon.AccountResponse{SequenceNumber: "7"}, nil).Twice()
+	mockEntityManager.On("Persist", mock.AnythingOfType("*entities.SentTransaction")).Return(nil).Times(3)
+
+	mismatches, err := reconciler.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Mismatch{
+		{TransactionID: "tx4", Status: entities.SentTransactionStatusSuccess, ChainStatus: entities.SentTransactionStatusFailure},
+		{TransactionID: "tx2", Status: entities.SentTransactionStatusSending, ChainStatus: entities.SentTransactionStatusFailure},
+		{TransactionID: "tx1", Status: entities.SentTransactionStatusFailure, ChainStatus: entities.SentTransactionStatusSuccess},
+	}, mismatches)
+
+	assert.Equal(t, entities.SentTransactionStatusSuccess, landed.Status)
+	assert.Equal(t, &ledger, landed.Ledger)
+	assert.Equal(t, &now, landed.SucceededAt)
+	assert.Equal(t, entities.SentTransactionStatusFailure, lost.Status)
+	assert.Equal(t, "<empty>", *lost.ResultXdr)
+	assert.Equal(t, entities.SentTransactionStatusFailure, failedOnChain.Status)
+	assert.Nil(t, failedOnChain.SucceededAt)
+	assert.Equal(t, entities.SentTransactionStatusSending, pending.Status)
+	assert.Equal(t, []*entities.SentTransaction{failedOnChain, lost, landed}, publisher.published)
+	assert.Equal(t, float64(1), unconfirmedGauge.Value())
+
+	// Only unconfirmed transactions are checked again
+	mismatches, err = reconciler.Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	mockRepository.AssertExpectations(t)
+	mockHorizon.AssertExpectations(t)
+	mockEntityManager.AssertExpectations(t)
+}
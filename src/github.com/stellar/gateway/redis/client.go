@@ -0,0 +1,290 @@
+// Package redis shares state of horizontally scaled bridge servers in Redis:
+// idempotency keys of payments, sequence numbers of source accounts and
+// token buckets of rate limits. It implements a minimal client of Redis
+// protocol (RESP2) with a connection pool, state changes are made by Lua
+// scripts so they are atomic.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/gateway/metrics"
+)
+
+var commandErrors = metrics.NewCounter("bridge_redis_errors_total", "Number of Redis commands failed because Redis was unavailable or returned an error.")
+
+// Error is an error reply of Redis
+type Error string
+
+func (e Error) Error() string {
+	return "Redis error: " + string(e)
+}
+
+// ErrUnexpectedReply is returned when a reply has an unexpected type
+var ErrUnexpectedReply = errors.New("Unexpected Redis reply")
+
+// Client sends commands to a Redis server. Connections are reused, at most
+// poolSize of them are kept open while idle.
+type Client struct {
+	address  string
+	password string
+	db       int
+	tls      *tls.Config
+	timeout  time.Duration
+	pool     chan *conn
+}
+
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Script is a Lua script sent with EVALSHA (and EVAL when Redis does not
+// have it cached yet)
+type Script struct {
+	source string
+	sha    string
+}
+
+// NewScript creates a Script of source
+func NewScript(source string) *Script {
+	sum := sha1.Sum([]byte(source))
+	return &Script{source: source, sha: hex.EncodeToString(sum[:])}
+}
+
+// NewClient creates a Client of server at rawURL:
+// redis://[:password@]host[:port][/db], rediss:// for TLS connections.
+// Every command is limited by timeout.
+func NewClient(rawURL string, poolSize int, timeout time.Duration) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		address: u.Host,
+		timeout: timeout,
+		pool:    make(chan *conn, poolSize),
+	}
+
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		c.tls = &tls.Config{ServerName: u.Hostname()}
+	default:
+		return nil, errors.New("Redis URL scheme must be redis or rediss")
+	}
+
+	if u.Port() == "" {
+		c.address = net.JoinHostPort(u.Hostname(), "6379")
+	}
+
+	if u.User != nil {
+		c.password, _ = u.User.Password()
+	}
+
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		c.db, err = strconv.Atoi(path)
+		if err != nil {
+			return nil, errors.New("Redis database must be a number")
+		}
+	}
+	return c, nil
+}
+
+// Do sends a command and returns its reply: string, int64, []interface{}
+// or nil. Error replies are returned as Error.
+func (c *Client) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		commandErrors.Inc()
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, c.timeout, args...)
+	if _, ok := err.(Error); err != nil && !ok {
+		// Connection is in unknown state
+		commandErrors.Inc()
+		cn.Close()
+		return nil, err
+	}
+	if err != nil {
+		commandErrors.Inc()
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// Eval runs script with keys and args
+func (c *Client) Eval(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	command := []interface{}{"EVALSHA", script.sha, len(keys)}
+	for _, key := range keys {
+		command = append(command, key)
+	}
+	command = append(command, args...)
+
+	reply, err := c.Do(ctx, command...)
+	if redisErr, ok := err.(Error); ok && strings.HasPrefix(string(redisErr), "NOSCRIPT") {
+		command[0], command[1] = "EVAL", script.source
+		return c.Do(ctx, command...)
+	}
+	return reply, err
+}
+
+// Ping checks if Redis is available, it's a health.Check
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.pool:
+		return cn, nil
+	default:
+	}
+
+	dialer := &net.Dialer{Timeout: c.timeout}
+	var netConn net.Conn
+	var err error
+	if c.tls != nil {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: c.tls}).DialContext(ctx, "tcp", c.address)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if c.password != "" {
+		_, err = cn.do(ctx, c.timeout, "AUTH", c.password)
+		if err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	if c.db != 0 {
+		_, err = cn.do(ctx, c.timeout, "SELECT", c.db)
+		if err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.pool <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+func (cn *conn) do(ctx context.Context, timeout time.Duration, args ...interface{}) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if timeout > 0 && (!ok || time.Now().Add(timeout).Before(deadline)) {
+		deadline, ok = time.Now().Add(timeout), true
+	}
+	if ok {
+		cn.SetDeadline(deadline)
+	} else {
+		cn.SetDeadline(time.Time{})
+	}
+
+	_, err := cn.Write(encodeCommand(args))
+	if err != nil {
+		return nil, err
+	}
+	return readReply(cn.reader)
+}
+
+// encodeCommand encodes args as an array of bulk strings
+func encodeCommand(args []interface{}) []byte {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		var value string
+		switch arg := arg.(type) {
+		case string:
+			value = arg
+		case []byte:
+			value = string(arg)
+		case int:
+			value = strconv.Itoa(arg)
+		case int64:
+			value = strconv.FormatInt(arg, 10)
+		case uint64:
+			value = strconv.FormatUint(arg, 10)
+		default:
+			value = fmt.Sprint(arg)
+		}
+		buf = append(buf, "$"+strconv.Itoa(len(value))+"\r\n"+value+"\r\n"...)
+	}
+	return buf
+}
+
+func readReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, ErrUnexpectedReply
+	}
+	prefix, value := line[0], line[1:len(line)-2]
+
+	switch prefix {
+	case '+':
+		return value, nil
+	case '-':
+		return nil, Error(value)
+	case ':':
+		return strconv.ParseInt(value, 10, 64)
+	case '$':
+		length, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, ErrUnexpectedReply
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		data := make([]byte, length+2)
+		_, err = io.ReadFull(reader, data)
+		if err != nil {
+			return nil, err
+		}
+		return string(data[:length]), nil
+	case '*':
+		length, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, ErrUnexpectedReply
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		elements := make([]interface{}, length)
+		for i := range elements {
+			elements[i], err = readReply(reader)
+			if redisErr, ok := err.(Error); ok {
+				elements[i] = redisErr
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		return elements, nil
+	}
+	return nil, ErrUnexpectedReply
+}
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+// Results of Idempotency.Claim
+const (
+	// ClaimNew means the payment ID has not been used within the TTL
+	ClaimNew = "new"
+	// ClaimSeen means a request with the payment ID has finished (or has
+	// been abandoned), the sent transaction must be loaded from the database
+	ClaimSeen = "seen"
+	// ClaimInProgress means a request with the payment ID is being handled
+	// by this or another bridge server
+	ClaimInProgress = "in_progress"
+)
+
+// claimTimeout is the time after which a claim of a request that did not
+// finish (ex. the server was killed) is abandoned
+const claimTimeout = 5 * time.Minute
+
+var claimScript = NewScript(`
+local value = redis.call('GET', KEYS[1])
+if value and string.sub(value, 1, 8) == 'pending:' and tonumber(string.sub(value, 9)) > tonumber(ARGV[2]) then
+  return 'in_progress'
+end
+redis.call('SET', KEYS[1], 'pending:' .. ARGV[1], 'PX', ARGV[3])
+if value then
+  return 'seen'
+end
+return 'new'
+`)
+
+// Idempotency tracks IDs of payments (`id` param of /payment) used within
+// the TTL, so bridge servers do not query the database for new IDs and do not
+// submit two requests with the same ID concurrently. A nil *Idempotency
+// reports every ID as seen, so the database is always checked.
+type Idempotency struct {
+	client *Client
+	prefix string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewIdempotency creates an Idempotency keeping IDs for ttl
+func NewIdempotency(client *Client, prefix string, ttl time.Duration, now func() time.Time) *Idempotency {
+	return &Idempotency{
+		client: client,
+		prefix: prefix + "idempotency:",
+		ttl:    ttl,
+		now:    now,
+	}
+}
+
+// Claim marks a request with payment id in progress and returns ClaimNew,
+// ClaimSeen or ClaimInProgress (the request is not claimed then). Claimed
+// requests must be finished with Finish.
+func (i *Idempotency) Claim(ctx context.Context, id string) (string, error) {
+	if i == nil {
+		return ClaimSeen, nil
+	}
+
+	now := i.now()
+	reply, err := i.client.Eval(ctx, claimScript, []string{i.prefix + id},
+		milliseconds(now),
+		milliseconds(now.Add(-claimTimeout)),
+		int64(i.ttl/time.Millisecond),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	switch result, _ := reply.(string); result {
+	case ClaimNew, ClaimSeen, ClaimInProgress:
+		return result, nil
+	}
+	return "", ErrUnexpectedReply
+}
+
+// Finish marks a claimed request with payment id finished, next requests
+// with the same id are reported as seen until the TTL passes
+func (i *Idempotency) Finish(ctx context.Context, id string) error {
+	if i == nil {
+		return nil
+	}
+
+	_, err := i.client.Do(ctx, "SET", i.prefix+id, "done", "PX", int64(i.ttl/time.Millisecond))
+	return err
+}
+
+func milliseconds(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
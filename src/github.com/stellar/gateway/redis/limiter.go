@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+// tokenBucketScript takes a token from a bucket refilled with ARGV[1] tokens
+// per minute (up to ARGV[1]). It returns 0 when a token was taken, the
+// number of milliseconds until the next token otherwise.
+var tokenBucketScript = NewScript(`
+local capacity = tonumber(ARGV[1])
+local now = tonumber(ARGV[2])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or capacity
+local updated = tonumber(bucket[2]) or now
+local rate = capacity / 60000
+tokens = math.min(capacity, tokens + math.max(0, now - updated) * rate)
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+else
+  wait = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], 60000)
+return wait
+`)
+
+// Limiter is a token bucket rate limiter keeping buckets in Redis, so the
+// limits are shared by all bridge servers. It implements
+// access.SharedBuckets.
+type Limiter struct {
+	client *Client
+	prefix string
+}
+
+// NewLimiter creates a Limiter
+func NewLimiter(client *Client, prefix string) *Limiter {
+	return &Limiter{client: client, prefix: prefix + "rate_limit:"}
+}
+
+// Allow takes a token from the bucket of key refilled with perMinute tokens
+// per minute. When the bucket is empty it returns false and the time after
+// which the next request is allowed.
+func (l *Limiter) Allow(ctx context.Context, key string, perMinute int, now time.Time) (bool, time.Duration, error) {
+	reply, err := l.client.Eval(ctx, tokenBucketScript, []string{l.prefix + key}, perMinute, milliseconds(now))
+	if err != nil {
+		return false, 0, err
+	}
+
+	wait, ok := reply.(int64)
+	if !ok {
+		return false, 0, ErrUnexpectedReply
+	}
+	if wait > 0 {
+		return false, time.Duration(wait) * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
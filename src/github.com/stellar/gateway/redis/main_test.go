@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a Redis server replying to commands with raw replies
+// returned by reply
+type fakeServer struct {
+	listener net.Listener
+	commands []string
+	reply    func(command []string) string
+}
+
+func newFakeServer(t *testing.T, reply func(command []string) string) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeServer{listener: listener, reply: reply}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		request, err := readReply(reader)
+		if err != nil {
+			return
+		}
+
+		var command []string
+		for _, arg := range request.([]interface{}) {
+			command = append(command, arg.(string))
+		}
+		s.commands = append(s.commands, strings.Join(command, " "))
+		conn.Write([]byte(s.reply(command)))
+	}
+}
+
+func (s *fakeServer) url(userinfo, path string) string {
+	return "redis://" + userinfo + s.listener.Addr().String() + path
+}
+
+func TestClient(t *testing.T) {
+	server := newFakeServer(t, func(command []string) string {
+		switch command[0] {
+		case "GET":
+			return "$-1\r\n"
+		case "INCR":
+			return ":5\r\n"
+		case "LRANGE":
+			return "*2\r\n$1\r\na\r\n$1\r\nb\r\n"
+		case "EVALSHA":
+			return "-NOSCRIPT No matching script\r\n"
+		case "EVAL":
+			return "$2\r\nok\r\n"
+		case "FLUSHALL":
+			return "-ERR unknown command\r\n"
+		}
+		return "+OK\r\n"
+	})
+	defer server.listener.Close()
+
+	client, err := NewClient(server.url(":secret@", "/2"), 1, time.Second)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, client.Ping(ctx))
+	reply, err := client.Do(ctx, "GET", "key")
+	assert.NoError(t, err)
+	assert.Nil(t, reply)
+	reply, err = client.Do(ctx, "INCR", "key")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), reply)
+	reply, err = client.Do(ctx, "LRANGE", "key", 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, reply)
+	_, err = client.Do(ctx, "FLUSHALL")
+	assert.Equal(t, Error("ERR unknown command"), err)
+
+	// Scripts not cached by Redis are sent with EVAL
+	reply, err = client.Eval(ctx, NewScript("return 'ok'"), []string{"key"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", reply)
+
+	// The connection is authenticated once and reused
+	sha := NewScript("return 'ok'").sha
+	assert.Equal(t, []string{
+		"AUTH secret",
+		"SELECT 2",
+		"PING",
+		"GET key",
+		"INCR key",
+		"LRANGE key 0 -1",
+		"FLUSHALL",
+		"EVALSHA " + sha + " 1 key 1",
+		"EVAL return 'ok' 1 key 1",
+	}, server.commands)
+
+	_, err = NewClient("http://localhost", 1, time.Second)
+	assert.Error(t, err)
+	client, err = NewClient("redis://localhost", 1, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:6379", client.address)
+}
+
+func TestStores(t *testing.T) {
+	var reply string
+	server := newFakeServer(t, func(command []string) string {
+		return reply
+	})
+	defer server.listener.Close()
+
+	client, err := NewClient(server.url("", ""), 1, time.Second)
+	require.NoError(t, err)
+	ctx := context.Background()
+	now := time.Unix(1514862245, 0)
+
+	idempotency := NewIdempotency(client, "bridge:", time.Hour, func() time.Time { return now })
+	reply = "$3\r\nnew\r\n"
+	result, err := idempotency.Claim(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, ClaimNew, result)
+	assert.Equal(t, "EVALSHA "+claimScript.sha+" 1 bridge:idempotency:42 1514862245000 1514861945000 3600000", server.commands[0])
+
+	reply = "$11\r\nin_progress\r\n"
+	result, err = idempotency.Claim(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, ClaimInProgress, result)
+
+	reply = "+OK\r\n"
+	require.NoError(t, idempotency.Finish(ctx, "42"))
+	assert.Equal(t, "SET bridge:idempotency:42 done PX 3600000", server.commands[2])
+
+	var disabled *Idempotency
+	result, err = disabled.Claim(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, ClaimSeen, result)
+
+	sequences := NewSequences(client, "bridge:")
+	reply = ":101\r\n"
+	sequence, err := sequences.Next(ctx, "GABC", 100)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(101), sequence)
+	assert.Equal(t, "EVALSHA "+nextSequenceScript.sha+" 1 bridge:sequence:GABC 100", server.commands[3])
+
+	reply = "$-1\r\n"
+	sequence, err = sequences.Last(ctx, "GABC")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), sequence)
+
+	limiter := NewLimiter(client, "bridge:")
+	reply = ":0\r\n"
+	allowed, _, err := limiter.Allow(ctx, "/payment ip:127.0.0.1", 60, now)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, "EVALSHA "+tokenBucketScript.sha+" 1 bridge:rate_limit:/payment ip:127.0.0.1 60 1514862245000", server.commands[5])
+
+	reply = ":1500\r\n"
+	allowed, retryAfter, err := limiter.Allow(ctx, "/payment ip:127.0.0.1", 60, now)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 1500*time.Millisecond, retryAfter)
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+)
+
+var nextSequenceScript = NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  redis.call('SET', KEYS[1], ARGV[1])
+end
+return redis.call('INCR', KEYS[1])
+`)
+
+// Sequences hands out sequence numbers of source accounts, so bridge
+// servers submitting transactions from the same account do not use the same
+// sequence number. It implements submitter.Sequences.
+type Sequences struct {
+	client *Client
+	prefix string
+}
+
+// NewSequences creates Sequences
+func NewSequences(client *Client, prefix string) *Sequences {
+	return &Sequences{client: client, prefix: prefix + "sequence:"}
+}
+
+// Next returns the next sequence number of accountID. The counter starts
+// from last (the sequence number loaded from Horizon) when it does not exist.
+func (s *Sequences) Next(ctx context.Context, accountID string, last uint64) (uint64, error) {
+	reply, err := s.client.Eval(ctx, nextSequenceScript, []string{s.prefix + accountID}, last)
+	if err != nil {
+		return 0, err
+	}
+
+	sequence, ok := reply.(int64)
+	if !ok {
+		return 0, ErrUnexpectedReply
+	}
+	return uint64(sequence), nil
+}
+
+// Last returns the last sequence number handed out for accountID, 0 when
+// the counter does not exist
+func (s *Sequences) Last(ctx context.Context, accountID string) (uint64, error) {
+	reply, err := s.client.Do(ctx, "GET", s.prefix+accountID)
+	if err != nil || reply == nil {
+		return 0, err
+	}
+
+	value, ok := reply.(string)
+	if !ok {
+		return 0, ErrUnexpectedReply
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// Reset sets the last sequence number of accountID, ex. after it has been
+// resynced with the ledger
+func (s *Sequences) Reset(ctx context.Context, accountID string, last uint64) error {
+	_, err := s.client.Do(ctx, "SET", s.prefix+accountID, last)
+	return err
+}
@@ -0,0 +1,125 @@
+// Package references derives hash memos of transactions from internal
+// references (ex. ID of a customer or an invoice), so the references are not
+// leaked on the public ledger. A memo is HMAC-SHA256 of the reference, the
+// bridge server stores derived memos to match memos of received payments
+// back to references.
+//
+// Existing flows sending references as plain text or ID memos can migrate
+// gradually: when plain text memos are allowed, memos of received payments
+// not derived from a reference are reported as references too.
+package references
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+)
+
+// MemoType is the type of memos derived from references
+const MemoType = "hash"
+
+// MaxLength is the max length of a reference
+const MaxLength = 255
+
+var resolvedReferences = metrics.NewCounter("bridge_memo_references_resolved_total", "Number of received payments with memos matched to references.")
+
+// ErrInvalidReference is returned when a reference is empty or too long
+var ErrInvalidReference = errors.New("Reference must be between 1 and 255 bytes long")
+
+// Store derives memos of references and resolves memos of received payments.
+// A nil *Store does nothing, so it can be used when memo references are
+// disabled.
+type Store struct {
+	key            []byte
+	allowPlaintext bool
+	repository     db.RepositoryInterface
+	entityManager  db.EntityManagerInterface
+	log            *logrus.Entry
+}
+
+// NewStore creates a new Store deriving memos using key. When allowPlaintext
+// is set, text and ID memos of received payments are resolved to themselves.
+func NewStore(key []byte, allowPlaintext bool, repository db.RepositoryInterface, entityManager db.EntityManagerInterface) *Store {
+	return &Store{
+		key:            key,
+		allowPlaintext: allowPlaintext,
+		repository:     repository,
+		entityManager:  entityManager,
+		log:            logrus.WithFields(logrus.Fields{"service": "MemoReferences"}),
+	}
+}
+
+// AllowsPlaintext returns true when text and ID memos can be sent, true when
+// s is nil
+func (s *Store) AllowsPlaintext() bool {
+	return s == nil || s.allowPlaintext
+}
+
+// Derive returns the hex encoded hash memo of reference
+func (s *Store) Derive(reference string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(reference))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Register returns the memo of reference, storing it when it's new so memos
+// of received payments can be resolved
+func (s *Store) Register(ctx context.Context, reference string) (string, error) {
+	if reference == "" || len(reference) > MaxLength {
+		return "", ErrInvalidReference
+	}
+
+	memo := s.Derive(reference)
+	existing, err := s.repository.GetMemoReference(ctx, memo)
+	if err != nil {
+		return "", err
+	}
+
+	if existing != nil {
+		return memo, nil
+	}
+
+	err = s.entityManager.Persist(&entities.MemoReference{
+		Memo:      memo,
+		Reference: reference,
+		CreatedAt: clock.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	s.log.WithFields(logrus.Fields{"memo": memo}).Info("Registered memo reference")
+	return memo, nil
+}
+
+// Resolve returns the reference of a memo of a received payment or an empty
+// string when it's not derived from a reference. Returns an empty string when
+// s is nil.
+func (s *Store) Resolve(ctx context.Context, memoType, memo string) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	switch memoType {
+	case MemoType:
+		memoReference, err := s.repository.GetMemoReference(ctx, memo)
+		if err != nil || memoReference == nil {
+			return "", err
+		}
+		resolvedReferences.Inc()
+		return memoReference.Reference, nil
+	case "text", "id":
+		if s.allowPlaintext {
+			return memo, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,63 @@
+package references
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	store := NewStore([]byte("0123456789abcdef0123456789abcdef"), false, db.NewRepository(driver), db.NewEntityManager(driver))
+
+	memo, err := store.Register(ctx, "customer-42")
+	require.NoError(t, err)
+	assert.Len(t, memo, 64)
+	assert.Equal(t, store.Derive("customer-42"), memo)
+	assert.NotEqual(t, store.Derive("customer-43"), memo)
+
+	// Registering a reference again returns the same memo
+	again, err := store.Register(ctx, "customer-42")
+	require.NoError(t, err)
+	assert.Equal(t, memo, again)
+
+	_, err = store.Register(ctx, "")
+	assert.Equal(t, ErrInvalidReference, err)
+	_, err = store.Register(ctx, strings.Repeat("a", MaxLength+1))
+	assert.Equal(t, ErrInvalidReference, err)
+
+	reference, err := store.Resolve(ctx, MemoType, memo)
+	require.NoError(t, err)
+	assert.Equal(t, "customer-42", reference)
+
+	reference, err = store.Resolve(ctx, MemoType, store.Derive("unknown"))
+	require.NoError(t, err)
+	assert.Equal(t, "", reference)
+
+	// Plain text memos are reported as references only when allowed
+	assert.False(t, store.AllowsPlaintext())
+	reference, err = store.Resolve(ctx, "text", "customer-42")
+	require.NoError(t, err)
+	assert.Equal(t, "", reference)
+
+	store.allowPlaintext = true
+	reference, err = store.Resolve(ctx, "id", "42")
+	require.NoError(t, err)
+	assert.Equal(t, "42", reference)
+
+	var nilStore *Store
+	assert.True(t, nilStore.AllowsPlaintext())
+	reference, err = nilStore.Resolve(ctx, MemoType, memo)
+	require.NoError(t, err)
+	assert.Equal(t, "", reference)
+}
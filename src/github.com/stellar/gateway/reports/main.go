@@ -0,0 +1,117 @@
+// Package reports maintains daily aggregates of received and sent payments
+// (per asset, tenant and direction) and builds reports from them, so reports
+// of long periods don't scan payment tables. Aggregates are updated
+// incrementally by the payment listener and the transaction submitter.
+package reports
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/export"
+	"github.com/stellar/gateway/features"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+// DayFormat is the format of days of aggregates
+const DayFormat = "2006-01-02"
+
+var aggregatorMetrics = struct {
+	errors *metrics.Counter
+}{
+	errors: metrics.NewCounter("bridge_report_aggregate_errors_total", "Number of payments not added to daily aggregates because of an error."),
+}
+
+// Aggregator adds payments to daily aggregates. A nil *Aggregator does
+// nothing, so it can be used when the bridge runs without a database.
+type Aggregator struct {
+	repository db.RepositoryInterface
+	log        *logrus.Entry
+}
+
+// NewAggregator creates a new Aggregator
+func NewAggregator(repository db.RepositoryInterface) *Aggregator {
+	return &Aggregator{
+		repository: repository,
+		log:        logrus.WithFields(logrus.Fields{"service": "Aggregator"}),
+	}
+}
+
+// Day returns the day of an aggregate containing t
+func Day(t time.Time) string {
+	return t.UTC().Format(DayFormat)
+}
+
+// RecordReceived adds a payment received at processedAt. Amount and asset of
+// account_merge operations must be loaded.
+func (a *Aggregator) RecordReceived(ctx context.Context, payment horizon.PaymentResponse, processedAt time.Time) {
+	if a == nil {
+		return
+	}
+
+	amount, err := amounts.Parse(payment.Amount)
+	if err != nil {
+		a.fail(err, logrus.Fields{"operation_id": payment.ID})
+		return
+	}
+
+	assetCode := payment.AssetCode
+	if payment.AssetType == "native" || payment.Type == "account_merge" {
+		assetCode = "XLM"
+	}
+
+	a.add(ctx, &entities.DailyAggregate{
+		Day:         Day(processedAt),
+		Direction:   entities.DailyAggregateReceived,
+		AssetCode:   assetCode,
+		AssetIssuer: payment.AssetIssuer,
+		Count:       1,
+		Amount:      amount,
+	}, logrus.Fields{"operation_id": payment.ID})
+}
+
+// RecordSent adds payments of a successful transaction. The tenant is read
+// from ctx, see features.TenantFromContext.
+func (a *Aggregator) RecordSent(ctx context.Context, transaction *entities.SentTransaction) {
+	if a == nil || transaction.Status != entities.SentTransactionStatusSuccess {
+		return
+	}
+
+	fields := logrus.Fields{"transaction_id": transaction.TransactionID}
+	entries, err := export.SentEntries(transaction)
+	if err != nil {
+		a.fail(err, fields)
+		return
+	}
+
+	tenant := features.TenantFromContext(ctx)
+	for _, entry := range entries {
+		a.add(ctx, &entities.DailyAggregate{
+			Day:         Day(entry.Time),
+			Tenant:      tenant,
+			Direction:   entities.DailyAggregateSent,
+			AssetCode:   entry.AssetCode,
+			AssetIssuer: entry.AssetIssuer,
+			Count:       1,
+			Amount:      entry.Amount,
+		}, fields)
+	}
+}
+
+func (a *Aggregator) add(ctx context.Context, aggregate *entities.DailyAggregate, fields logrus.Fields) {
+	err := a.repository.AddToDailyAggregate(ctx, aggregate)
+	if err != nil {
+		a.fail(err, fields)
+	}
+}
+
+func (a *Aggregator) fail(err error, fields logrus.Fields) {
+	aggregatorMetrics.errors.Inc()
+	fields["err"] = err
+	a.log.WithFields(fields).Error("Error updating daily aggregates")
+}
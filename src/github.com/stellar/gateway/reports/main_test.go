@@ -0,0 +1,125 @@
+package reports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/auth"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	account     = "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"
+	destination = "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"
+)
+
+func nativePaymentEnvelope(t *testing.T, amounts ...int64) string {
+	var source, dest xdr.AccountId
+	require.NoError(t, source.SetAddress(account))
+	require.NoError(t, dest.SetAddress(destination))
+
+	envelope := xdr.TransactionEnvelope{Tx: xdr.Transaction{SourceAccount: source}}
+	for _, amount := range amounts {
+		body, err := xdr.NewOperationBody(xdr.OperationTypePayment, xdr.PaymentOp{
+			Destination: dest,
+			Asset:       xdr.Asset{Type: xdr.AssetTypeAssetTypeNative},
+			Amount:      xdr.Int64(amount),
+		})
+		require.NoError(t, err)
+		envelope.Tx.Operations = append(envelope.Tx.Operations, xdr.Operation{Body: body})
+	}
+
+	encoded, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	return encoded
+}
+
+func TestAggregator(t *testing.T) {
+	repository := &mocks.MockRepository{}
+	aggregator := NewAggregator(repository)
+	processedAt := time.Date(2026, 10, 15, 23, 59, 0, 0, time.FixedZone("UTC-1", -3600))
+
+	repository.On("AddToDailyAggregate", &entities.DailyAggregate{
+		Day:         "2026-10-16",
+		Direction:   entities.DailyAggregateReceived,
+		AssetCode:   "USD",
+		AssetIssuer: destination,
+		Count:       1,
+		Amount:      105000000,
+	}).Return(nil).Once()
+	aggregator.RecordReceived(context.Background(), horizon.PaymentResponse{
+		ID:          "1",
+		Type:        "payment",
+		AssetType:   "credit_alphanum4",
+		AssetCode:   "USD",
+		AssetIssuer: destination,
+		Amount:      "10.5",
+	}, processedAt)
+
+	// Tenant of the API key is read from the context of the request
+	ctx := auth.WithKey(context.Background(), &auth.Key{ID: "acme-key", Tenant: "acme"})
+
+	succeededAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	for _, amount := range []int64{10000000, 20000000} {
+		repository.On("AddToDailyAggregate", &entities.DailyAggregate{
+			Day:       "2026-10-15",
+			Tenant:    "acme",
+			Direction: entities.DailyAggregateSent,
+			AssetCode: "XLM",
+			Count:     1,
+			Amount:    amount,
+		}).Return(nil).Once()
+	}
+	aggregator.RecordSent(ctx, &entities.SentTransaction{
+		TransactionID: "tx",
+		Status:        entities.SentTransactionStatusSuccess,
+		SucceededAt:   &succeededAt,
+		EnvelopeXdr:   nativePaymentEnvelope(t, 10000000, 20000000),
+	})
+
+	// Failed transactions are not aggregated
+	aggregator.RecordSent(ctx, &entities.SentTransaction{
+		TransactionID: "failed",
+		Status:        entities.SentTransactionStatusFailure,
+		EnvelopeXdr:   nativePaymentEnvelope(t, 10000000),
+	})
+
+	repository.AssertExpectations(t)
+
+	// nil Aggregator does nothing
+	var disabled *Aggregator
+	disabled.RecordReceived(context.Background(), horizon.PaymentResponse{Amount: "1"}, processedAt)
+	disabled.RecordSent(ctx, &entities.SentTransaction{Status: entities.SentTransactionStatusSuccess})
+}
+
+func TestReports(t *testing.T) {
+	repository := &mocks.MockRepository{}
+	filter := db.DailyAggregatesFilter{From: "2026-10-01", To: "2026-10-31"}
+	repository.On("GetDailyAggregates", filter).Return([]*entities.DailyAggregate{
+		{Day: "2026-10-01", Direction: "sent", AssetCode: "XLM", Count: 2, Amount: 30000000},
+		{Day: "2026-10-01", Tenant: "acme", Direction: "sent", AssetCode: "XLM", Count: 1, Amount: 5},
+		{Day: "2026-10-02", Direction: "received", AssetCode: "XLM", Count: 1, Amount: 10000000},
+		{Day: "2026-10-02", Direction: "sent", AssetCode: "XLM", Count: 1, Amount: 15000000},
+	}, nil)
+
+	daily, err := Daily(context.Background(), repository, filter)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-01", daily.From)
+	require.Len(t, daily.Rows, 4)
+	assert.Equal(t, Row{Day: "2026-10-01", Direction: "sent", AssetCode: "XLM", Count: 2, Amount: "3.0000000"}, daily.Rows[0])
+
+	totals, err := Totals(context.Background(), repository, filter)
+	require.NoError(t, err)
+	assert.Equal(t, []Row{
+		{Direction: "received", AssetCode: "XLM", Count: 1, Amount: "1.0000000"},
+		{Direction: "sent", AssetCode: "XLM", Count: 3, Amount: "4.5000000"},
+		{Tenant: "acme", Direction: "sent", AssetCode: "XLM", Count: 1, Amount: "0.0000005"},
+	}, totals.Rows)
+}
@@ -0,0 +1,104 @@
+package reports
+
+import (
+	"context"
+	"sort"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+// Row is a row of a report: the number and the sum of payments of an asset
+// in a direction made by a tenant. Day is empty in totals.
+type Row struct {
+	Day         string `json:"day,omitempty"`
+	Tenant      string `json:"tenant"`
+	Direction   string `json:"direction"`
+	AssetCode   string `json:"asset_code"`
+	AssetIssuer string `json:"asset_issuer"`
+	Count       int64  `json:"count"`
+	Amount      string `json:"amount"`
+}
+
+// Report contains rows of days in [From, To]
+type Report struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Rows []Row  `json:"rows"`
+}
+
+// Daily returns a report with a row of every aggregate matching filter
+func Daily(ctx context.Context, repository db.RepositoryInterface, filter db.DailyAggregatesFilter) (*Report, error) {
+	aggregates, err := repository.GetDailyAggregates(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{From: filter.From, To: filter.To, Rows: make([]Row, 0, len(aggregates))}
+	for _, aggregate := range aggregates {
+		row := newRow(aggregate)
+		row.Day = aggregate.Day
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+// Totals returns a report with sums of aggregates matching filter by tenant,
+// direction and asset
+func Totals(ctx context.Context, repository db.RepositoryInterface, filter db.DailyAggregatesFilter) (*Report, error) {
+	aggregates, err := repository.GetDailyAggregates(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ tenant, direction, assetCode, assetIssuer string }
+	sums := map[key]*entities.DailyAggregate{}
+	var keys []key
+	for _, aggregate := range aggregates {
+		k := key{aggregate.Tenant, aggregate.Direction, aggregate.AssetCode, aggregate.AssetIssuer}
+		sum, ok := sums[k]
+		if !ok {
+			sum = &entities.DailyAggregate{Tenant: k.tenant, Direction: k.direction, AssetCode: k.assetCode, AssetIssuer: k.assetIssuer}
+			sums[k] = sum
+			keys = append(keys, k)
+		}
+
+		sum.Count += aggregate.Count
+		sum.Amount, err = amounts.Add(sum.Amount, aggregate.Amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.tenant != b.tenant {
+			return a.tenant < b.tenant
+		}
+		if a.direction != b.direction {
+			return a.direction < b.direction
+		}
+		if a.assetCode != b.assetCode {
+			return a.assetCode < b.assetCode
+		}
+		return a.assetIssuer < b.assetIssuer
+	})
+
+	report := &Report{From: filter.From, To: filter.To, Rows: make([]Row, 0, len(keys))}
+	for _, k := range keys {
+		report.Rows = append(report.Rows, newRow(sums[k]))
+	}
+	return report, nil
+}
+
+func newRow(aggregate *entities.DailyAggregate) Row {
+	return Row{
+		Tenant:      aggregate.Tenant,
+		Direction:   aggregate.Direction,
+		AssetCode:   aggregate.AssetCode,
+		AssetIssuer: aggregate.AssetIssuer,
+		Count:       aggregate.Count,
+		Amount:      amounts.String(aggregate.Amount),
+	}
+}
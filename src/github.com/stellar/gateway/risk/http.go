@@ -0,0 +1,77 @@
+package risk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/stellar/gateway/protocols/signer"
+	"github.com/stellar/go/support/errors"
+)
+
+// maxResponseSize is the max size of a response of an HTTP hook
+const maxResponseSize = 64 * 1024
+
+// HTTP represents an http client that HTTPHook uses to make HTTP requests
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// HTTPHook sends Payment in a POST request body to URL and expects Result in
+// a `200 OK` response. When Secret is set, the body is authenticated with hex
+// encoded HMAC-SHA256 sent in X-Signature header, like requests to the
+// external signing service.
+type HTTPHook struct {
+	URL    string
+	Secret string
+	Client HTTP
+}
+
+// NewHTTPHook creates a new HTTPHook
+func NewHTTPHook(url, secret string, client HTTP) *HTTPHook {
+	return &HTTPHook{URL: url, Secret: secret, Client: client}
+}
+
+// Check implements Hook
+func (h *HTTPHook) Check(ctx context.Context, payment Payment) (Result, error) {
+	body, err := json.Marshal(payment)
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequest("POST", h.URL, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if h.Secret != "" {
+		req.Header.Set(signer.SignatureHeader, signer.MAC(h.Secret, body))
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "Error sending request to risk hook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("Risk hook returned status %d", resp.StatusCode)
+	}
+
+	responseBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	err = json.Unmarshal(responseBody, &result)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "Cannot decode risk hook response")
+	}
+	return result, nil
+}
@@ -0,0 +1,189 @@
+// Package risk contains pre-submission hooks of outbound payments. Hooks are
+// invoked with the context of a payment sent using /payment before it's held
+// or submitted and can approve, deny or hold it, so fraud and compliance teams
+// can gate outbound flow without changing the payment handler.
+//
+// Hooks are HTTP services (HTTPHook), Go plugins (LoadPlugin), the built-in
+// VelocityChecker or any Hook implementation added by programs embedding the
+// bridge server.
+package risk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/metrics"
+)
+
+// Decisions of hooks
+const (
+	// DecisionApprove lets the payment continue to the next hook
+	DecisionApprove = "approve"
+	// DecisionDeny rejects the payment
+	DecisionDeny = "deny"
+	// DecisionHold holds the payment, see Result.HoldFor
+	DecisionHold = "hold"
+)
+
+var riskMetrics = struct {
+	denied *metrics.Counter
+	held   *metrics.Counter
+	errors *metrics.Counter
+}{
+	denied: metrics.NewCounter("bridge_risk_denied_total", "Number of payments denied by risk hooks."),
+	held:   metrics.NewCounter("bridge_risk_held_total", "Number of payments held by risk hooks."),
+	errors: metrics.NewCounter("bridge_risk_errors_total", "Number of risk hook calls that returned an error."),
+}
+
+// Payment is the context of a payment passed to hooks
+type Payment struct {
+	// ID is the payment ID, empty when not sent in the request
+	ID string `json:"id,omitempty"`
+	// Source is the source account ID
+	Source string `json:"source"`
+	// Destination is an account ID or a Stellar address as sent in the
+	// request (or resolved from destination_name)
+	Destination     string `json:"destination"`
+	DestinationName string `json:"destination_name,omitempty"`
+	Amount          string `json:"amount"`
+	// AssetCode is XLM (without issuer) for native payments
+	AssetCode       string `json:"asset_code"`
+	AssetIssuer     string `json:"asset_issuer,omitempty"`
+	SendMax         string `json:"send_max,omitempty"`
+	SendAssetCode   string `json:"send_asset_code,omitempty"`
+	SendAssetIssuer string `json:"send_asset_issuer,omitempty"`
+	MemoType        string `json:"memo_type,omitempty"`
+	Memo            string `json:"memo,omitempty"`
+	// Compliance is true when the payment is sent using compliance protocol
+	Compliance bool `json:"compliance,omitempty"`
+	// APIKey is the ID of the API key that sent the payment (auth)
+	APIKey   string `json:"api_key,omitempty"`
+	Tenant   string `json:"tenant,omitempty"`
+	ClientIP string `json:"client_ip,omitempty"`
+	// Timestamp is the time of the request in Unix seconds
+	Timestamp int64 `json:"timestamp"`
+}
+
+// Result is a decision of a hook
+type Result struct {
+	Decision string `json:"decision"`
+	// Reason is logged and returned in more_info of the error of a denied
+	// payment
+	Reason string `json:"reason,omitempty"`
+	// HoldFor is the time in seconds a held payment waits before it's
+	// submitted, the default hold duration is used when 0
+	HoldFor int64 `json:"hold_for,omitempty"`
+	// Hook is the name of the hook that denied or held the payment, set by
+	// Chain
+	Hook string `json:"-"`
+}
+
+// HoldDuration returns HoldFor as time.Duration
+func (r Result) HoldDuration() time.Duration {
+	return time.Duration(r.HoldFor) * time.Second
+}
+
+// Hook checks a payment before it's held or submitted. An error means the
+// hook could not make a decision (ex. HTTP service unavailable).
+type Hook interface {
+	Check(ctx context.Context, payment Payment) (Result, error)
+}
+
+// HookFunc is a function implementing Hook
+type HookFunc func(ctx context.Context, payment Payment) (Result, error)
+
+// Check implements Hook
+func (f HookFunc) Check(ctx context.Context, payment Payment) (Result, error) {
+	return f(ctx, payment)
+}
+
+type namedHook struct {
+	name string
+	hook Hook
+}
+
+// Chain runs hooks in order they were added. The first deny rejects the
+// payment without calling the next hooks. When any hook holds the payment,
+// it's held for the longest HoldFor of all holds.
+type Chain struct {
+	// FailOpen approves payments when a hook returns an error, the payment
+	// is rejected otherwise
+	FailOpen bool
+	hooks    []namedHook
+	log      *logrus.Entry
+}
+
+// NewChain creates a new empty Chain
+func NewChain(failOpen bool) *Chain {
+	return &Chain{
+		FailOpen: failOpen,
+		log:      logrus.WithFields(logrus.Fields{"service": "Risk"}),
+	}
+}
+
+// Add appends a hook to the chain. name is used in logs and results.
+func (c *Chain) Add(name string, hook Hook) {
+	c.hooks = append(c.hooks, namedHook{name, hook})
+}
+
+// Len returns the number of hooks
+func (c *Chain) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.hooks)
+}
+
+// Check runs hooks and returns the final decision. Returns an error when a
+// hook fails and FailOpen is false.
+func (c *Chain) Check(ctx context.Context, payment Payment) (Result, error) {
+	final := Result{Decision: DecisionApprove}
+
+	for _, h := range c.hooks {
+		result, err := h.hook.Check(ctx, payment)
+		if err == nil {
+			err = result.validate()
+		}
+
+		if err != nil {
+			riskMetrics.errors.Inc()
+			if !c.FailOpen {
+				return Result{}, fmt.Errorf("Risk hook %s failed: %s", h.name, err)
+			}
+			c.log.WithFields(logrus.Fields{"hook": h.name, "id": payment.ID, "err": err}).Warn("Risk hook failed, payment approved")
+			continue
+		}
+
+		switch result.Decision {
+		case DecisionDeny:
+			result.Hook = h.name
+			riskMetrics.denied.Inc()
+			return result, nil
+		case DecisionHold:
+			if final.Decision != DecisionHold || result.HoldFor > final.HoldFor {
+				final = result
+				final.Hook = h.name
+			}
+		}
+	}
+
+	if final.Decision == DecisionHold {
+		riskMetrics.held.Inc()
+	}
+	return final, nil
+}
+
+func (r Result) validate() error {
+	switch r.Decision {
+	case DecisionApprove, DecisionDeny, DecisionHold:
+	default:
+		return fmt.Errorf("invalid decision: %q", r.Decision)
+	}
+
+	if r.HoldFor < 0 {
+		return fmt.Errorf("invalid hold_for: %d", r.HoldFor)
+	}
+	return nil
+}
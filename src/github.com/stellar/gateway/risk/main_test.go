@@ -0,0 +1,183 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decide(decision string, holdFor int64) Hook {
+	return HookFunc(func(ctx context.Context, payment Payment) (Result, error) {
+		return Result{Decision: decision, Reason: decision + " reason", HoldFor: holdFor}, nil
+	})
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	payment := Payment{ID: "payment-1", Amount: "10"}
+
+	chain := NewChain(false)
+	chain.Add("approve", decide(DecisionApprove, 0))
+	result, err := chain.Check(ctx, payment)
+	require.NoError(t, err)
+	assert.Equal(t, DecisionApprove, result.Decision)
+
+	// The longest hold wins
+	chain.Add("hold-short", decide(DecisionHold, 60))
+	chain.Add("hold-long", decide(DecisionHold, 600))
+	result, err = chain.Check(ctx, payment)
+	require.NoError(t, err)
+	assert.Equal(t, DecisionHold, result.Decision)
+	assert.Equal(t, "hold-long", result.Hook)
+	assert.Equal(t, 10*time.Minute, result.HoldDuration())
+
+	// Deny stops the chain
+	called := false
+	chain.Add("deny", decide(DecisionDeny, 0))
+	chain.Add("last", HookFunc(func(ctx context.Context, payment Payment) (Result, error) {
+		called = true
+		return Result{Decision: DecisionApprove}, nil
+	}))
+	result, err = chain.Check(ctx, payment)
+	require.NoError(t, err)
+	assert.Equal(t, DecisionDeny, result.Decision)
+	assert.Equal(t, "deny", result.Hook)
+	assert.Equal(t, "deny reason", result.Reason)
+	assert.False(t, called)
+
+	failing := HookFunc(func(ctx context.Context, payment Payment) (Result, error) {
+		return Result{}, errors.New("unavailable")
+	})
+
+	chain = NewChain(false)
+	chain.Add("failing", failing)
+	_, err = chain.Check(ctx, payment)
+	assert.EqualError(t, err, "Risk hook failing failed: unavailable")
+
+	chain = NewChain(false)
+	chain.Add("invalid", decide("maybe", 0))
+	_, err = chain.Check(ctx, payment)
+	assert.EqualError(t, err, `Risk hook invalid failed: invalid decision: "maybe"`)
+
+	chain = NewChain(true)
+	chain.Add("failing", failing)
+	result, err = chain.Check(ctx, payment)
+	require.NoError(t, err)
+	assert.Equal(t, DecisionApprove, result.Decision)
+
+	assert.Equal(t, 0, (*Chain)(nil).Len())
+}
+
+func TestHTTPHook(t *testing.T) {
+	var received Payment
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !signer.VerifyMAC("secret", body, r.Header.Get(signer.SignatureHeader)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		json.Unmarshal(body, &received)
+		if received.Amount == "500" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"decision":"hold","reason":"manual review","hold_for":3600}`))
+	}))
+	defer server.Close()
+
+	hook := NewHTTPHook(server.URL, "secret", http.DefaultClient)
+	result, err := hook.Check(context.Background(), Payment{ID: "payment-1", Source: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ", Amount: "10", AssetCode: "XLM"})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Decision: DecisionHold, Reason: "manual review", HoldFor: 3600}, result)
+	assert.Equal(t, "payment-1", received.ID)
+	assert.Equal(t, "XLM", received.AssetCode)
+
+	_, err = hook.Check(context.Background(), Payment{Amount: "500"})
+	assert.EqualError(t, err, "Risk hook returned status 500")
+
+	hook.Secret = "invalid"
+	_, err = hook.Check(context.Background(), Payment{Amount: "10"})
+	assert.EqualError(t, err, "Risk hook returned status 403")
+}
+
+func TestVelocityChecker(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+	entityManager := db.NewEntityManager(driver)
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	checker := NewVelocityChecker([]VelocityRule{
+		{Scope: ScopeSource, Window: time.Hour, MaxCount: 3, Decision: DecisionHold},
+		{Scope: ScopeDestination, Window: 24 * time.Hour, MaxAmount: 100 * 10000000, AssetCode: "USD", AssetIssuer: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632", Decision: DecisionDeny},
+	}, db.NewRepository(driver))
+	checker.now = func() time.Time { return now }
+
+	source := "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ"
+	send := func(destination string, amount int64, assetCode, assetIssuer string, at time.Time) {
+		require.NoError(t, entityManager.Persist(&entities.PaymentVelocity{
+			Source:      source,
+			Destination: destination,
+			AssetCode:   assetCode,
+			AssetIssuer: assetIssuer,
+			Amount:      amount * 10000000,
+			CreatedAt:   at,
+		}))
+	}
+	usd := Payment{Source: source, Destination: "bob*stellar.org", Amount: "40", AssetCode: "USD", AssetIssuer: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"}
+
+	send("bob*stellar.org", 60, usd.AssetCode, usd.AssetIssuer, now.Add(-23*time.Hour))
+	send("alice*stellar.org", 1, "XLM", "", now.Add(-2*time.Hour))
+
+	result, err := checker.Check(context.Background(), usd)
+	require.NoError(t, err)
+	assert.Equal(t, DecisionApprove, result.Decision)
+
+	usd.Amount = "40.0000001"
+	result, err = checker.Check(context.Background(), usd)
+	require.NoError(t, err)
+	assert.Equal(t, DecisionDeny, result.Decision)
+	assert.Equal(t, "Payments to bob*stellar.org would exceed 100.0000000 USD in 24h0m0s.", result.Reason)
+
+	// Payments of other assets are not counted in sums
+	result, err = checker.Check(context.Background(), Payment{Source: source, Destination: "bob*stellar.org", Amount: "1000", AssetCode: "XLM"})
+	require.NoError(t, err)
+	assert.Equal(t, DecisionApprove, result.Decision)
+
+	send("alice*stellar.org", 1, "XLM", "", now.Add(-10*time.Minute))
+	send("carol*stellar.org", 1, "XLM", "", now.Add(-5*time.Minute))
+	result, err = checker.Check(context.Background(), Payment{Source: source, Destination: "alice*stellar.org", Amount: "1", AssetCode: "XLM"})
+	require.NoError(t, err)
+	assert.Equal(t, DecisionApprove, result.Decision)
+
+	send("dave*stellar.org", 1, "XLM", "", now.Add(-time.Minute))
+	result, err = checker.Check(context.Background(), Payment{Source: source, Destination: "alice*stellar.org", Amount: "1", AssetCode: "XLM"})
+	require.NoError(t, err)
+	assert.Equal(t, DecisionHold, result.Decision)
+	assert.Equal(t, "More than 3 payments from "+source+" in 1h0m0s.", result.Reason)
+
+	assert.Equal(t, 24*time.Hour, checker.MaxWindow())
+	now = now.Add(2 * time.Hour)
+	removed, err := checker.Prune(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+}
+
+func TestLoadPlugin(t *testing.T) {
+	_, err := LoadPlugin("/nonexistent/hook.so")
+	assert.Error(t, err)
+}
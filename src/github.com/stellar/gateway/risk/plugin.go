@@ -0,0 +1,41 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"plugin"
+)
+
+// PluginSymbol is the name of the symbol looked up in Go plugins
+const PluginSymbol = "Hook"
+
+// LoadPlugin opens a Go plugin built with `go build -buildmode=plugin` (with
+// the same Go version and versions of shared packages as the server). The
+// plugin must export `Hook` variable implementing Hook or `Hook` function
+// with the signature of Hook.Check. Plugins are supported on Linux, FreeBSD
+// and macOS only.
+func LoadPlugin(path string) (Hook, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	switch hook := symbol.(type) {
+	case *Hook:
+		if *hook == nil {
+			return nil, fmt.Errorf("%s of plugin %s is nil", PluginSymbol, path)
+		}
+		return *hook, nil
+	case Hook:
+		return hook, nil
+	case func(context.Context, Payment) (Result, error):
+		return HookFunc(hook), nil
+	default:
+		return nil, fmt.Errorf("%s of plugin %s does not implement risk.Hook (%T)", PluginSymbol, path, symbol)
+	}
+}
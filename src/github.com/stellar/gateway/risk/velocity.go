@@ -0,0 +1,131 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/protocols/amounts"
+)
+
+// Scopes of velocity rules
+const (
+	// ScopeSource counts payments of the source account
+	ScopeSource = "source"
+	// ScopeDestination counts payments to the destination, compared as sent
+	// in the request
+	ScopeDestination = "destination"
+)
+
+// VelocityRule limits payments of a single source account or to a single
+// destination in a sliding window
+type VelocityRule struct {
+	// Scope is ScopeSource or ScopeDestination
+	Scope  string
+	Window time.Duration
+	// MaxCount is the max number of payments in the window including the
+	// checked payment, no limit when 0
+	MaxCount int64
+	// MaxAmount is the max sum of amounts in stroops including the checked
+	// payment, no limit when 0. Requires AssetCode.
+	MaxAmount int64
+	// AssetCode and AssetIssuer limit the rule to payments of a single asset
+	// (XLM without issuer for native), payments of all assets are counted
+	// when empty
+	AssetCode   string
+	AssetIssuer string
+	// Decision is DecisionDeny or DecisionHold
+	Decision string
+}
+
+// VelocityStore contains payments counted by VelocityChecker, implemented by
+// db.Repository. Payments are recorded by the payment handler after they are
+// sent.
+type VelocityStore interface {
+	GetPaymentVelocity(ctx context.Context, filter db.PaymentVelocityFilter) (count, amount int64, err error)
+	DeletePaymentVelocityBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// VelocityChecker is a Hook checking counts and sums of amounts of payments
+// sent in sliding windows. The first exceeded rule decides.
+type VelocityChecker struct {
+	Rules []VelocityRule
+	Store VelocityStore
+	now   func() time.Time
+}
+
+// NewVelocityChecker creates a new VelocityChecker
+func NewVelocityChecker(rules []VelocityRule, store VelocityStore) *VelocityChecker {
+	return &VelocityChecker{Rules: rules, Store: store, now: clock.Now}
+}
+
+// Check implements Hook
+func (v *VelocityChecker) Check(ctx context.Context, payment Payment) (Result, error) {
+	amount, err := amounts.Parse(payment.Amount)
+	if err != nil {
+		return Result{}, err
+	}
+
+	now := v.now()
+	for _, rule := range v.Rules {
+		if rule.AssetCode != "" && (rule.AssetCode != payment.AssetCode || rule.AssetIssuer != payment.AssetIssuer) {
+			continue
+		}
+
+		filter := db.PaymentVelocityFilter{
+			AssetCode:   rule.AssetCode,
+			AssetIssuer: rule.AssetIssuer,
+			Since:       now.Add(-rule.Window),
+		}
+		direction := "from " + payment.Source
+		if rule.Scope == ScopeDestination {
+			filter.Destination = payment.Destination
+			direction = "to " + payment.Destination
+		} else {
+			filter.Source = payment.Source
+		}
+
+		count, sum, err := v.Store.GetPaymentVelocity(ctx, filter)
+		if err != nil {
+			return Result{}, err
+		}
+
+		if rule.MaxCount > 0 && count+1 > rule.MaxCount {
+			return Result{
+				Decision: rule.Decision,
+				Reason:   fmt.Sprintf("More than %d payments %s in %s.", rule.MaxCount, direction, rule.Window),
+			}, nil
+		}
+
+		if rule.MaxAmount > 0 {
+			total, err := amounts.Add(sum, amount)
+			if err != nil || total > rule.MaxAmount {
+				return Result{
+					Decision: rule.Decision,
+					Reason:   fmt.Sprintf("Payments %s would exceed %s %s in %s.", direction, amounts.String(rule.MaxAmount), rule.AssetCode, rule.Window),
+				}, nil
+			}
+		}
+	}
+
+	return Result{Decision: DecisionApprove}, nil
+}
+
+// MaxWindow returns the longest window of all rules
+func (v *VelocityChecker) MaxWindow() time.Duration {
+	var max time.Duration
+	for _, rule := range v.Rules {
+		if rule.Window > max {
+			max = rule.Window
+		}
+	}
+	return max
+}
+
+// Prune removes payments outside of all windows and returns the number of
+// removed payments
+func (v *VelocityChecker) Prune(ctx context.Context) (int64, error) {
+	return v.Store.DeletePaymentVelocityBefore(ctx, v.now().Add(-v.MaxWindow()))
+}
@@ -0,0 +1,93 @@
+package sanctions
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ListMatcher is a Screener matching subjects against a local list. Entries
+// containing `*` or starting with G and 56 characters long are matched with
+// Address and AccountID of the subject (ignoring case), other entries are
+// names matched with Subject.Name ignoring case, punctuation and order of
+// words.
+type ListMatcher struct {
+	accounts map[string]string
+	names    map[string]string
+}
+
+// LoadList loads a list from a file, see ReadList
+func LoadList(path string) (*ListMatcher, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadList(file)
+}
+
+// ReadList reads a list with one entry per line. Empty lines and lines
+// starting with # are ignored.
+func ReadList(reader io.Reader) (*ListMatcher, error) {
+	list := &ListMatcher{
+		accounts: map[string]string{},
+		names:    map[string]string{},
+	}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		if isAccount(entry) {
+			list.accounts[strings.ToLower(entry)] = entry
+		} else if name := normalizeName(entry); name != "" {
+			list.names[name] = entry
+		}
+	}
+	return list, scanner.Err()
+}
+
+// Len returns the number of entries
+func (l *ListMatcher) Len() int {
+	return len(l.accounts) + len(l.names)
+}
+
+// Screen implements Screener
+func (l *ListMatcher) Screen(ctx context.Context, subject Subject) (Result, error) {
+	var matches []string
+
+	for _, account := range []string{subject.Address, subject.AccountID} {
+		if entry, ok := l.accounts[strings.ToLower(account)]; ok && account != "" {
+			matches = append(matches, entry)
+		}
+	}
+
+	if entry, ok := l.names[normalizeName(subject.Name())]; ok {
+		matches = append(matches, entry)
+	}
+
+	if len(matches) > 0 {
+		return Result{Status: StatusMatch, Provider: "list", Matches: matches}, nil
+	}
+	return Result{Status: StatusClear, Provider: "list"}, nil
+}
+
+func isAccount(entry string) bool {
+	return strings.Contains(entry, "*") || (len(entry) == 56 && entry[0] == 'G')
+}
+
+// normalizeName returns lower case words of name sorted alphabetically
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,192 @@
+// Package sanctions screens senders of received payments and receivers of sent
+// payments against sanctions lists. Screeners are a local list (ListMatcher),
+// an external sanctions API (RESTScreener) or any Screener implementation
+// added by programs embedding the compliance server. Results of external APIs
+// are cached by Cache. The compliance server persists results as
+// SanctionsScreening entities together with the ID of the transaction.
+package sanctions
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/stellar/gateway/cache"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+)
+
+// Statuses of results
+const (
+	// StatusClear means the subject did not match any list
+	StatusClear = "clear"
+	// StatusMatch means the subject matched a list, payments are denied
+	StatusMatch = "match"
+	// StatusReview means the subject needs a manual review, received
+	// payments are pending and sent payments are rejected
+	StatusReview = "review"
+)
+
+// Roles of subjects
+const (
+	RoleSender   = "sender"
+	RoleReceiver = "receiver"
+)
+
+var sanctionsMetrics = struct {
+	matches *metrics.Counter
+	reviews *metrics.Counter
+	errors  *metrics.Counter
+}{
+	matches: metrics.NewCounter("compliance_sanctions_matches_total", "Number of screened subjects that matched sanctions lists."),
+	reviews: metrics.NewCounter("compliance_sanctions_reviews_total", "Number of screened subjects that need a manual review."),
+	errors:  metrics.NewCounter("compliance_sanctions_errors_total", "Number of screenings that returned an error."),
+}
+
+// Subject is a sender or a receiver of a payment
+type Subject struct {
+	// Role is RoleSender or RoleReceiver
+	Role string `json:"role"`
+	// Address is a Stellar address (ex. bob*stellar.org) or an account ID
+	Address string `json:"address"`
+	// AccountID is the Stellar account of the subject when known
+	AccountID string `json:"account_id,omitempty"`
+	// Info contains KYC fields of the subject (sender_info or receiver_info)
+	Info map[string]string `json:"info,omitempty"`
+}
+
+// Name returns the name of the subject from Info: name, company_name or
+// first_name, middle_name and last_name
+func (s Subject) Name() string {
+	if s.Info["name"] != "" {
+		return s.Info["name"]
+	}
+	if s.Info["company_name"] != "" {
+		return s.Info["company_name"]
+	}
+
+	var parts []string
+	for _, field := range []string{"first_name", "middle_name", "last_name"} {
+		if s.Info[field] != "" {
+			parts = append(parts, s.Info[field])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Result is a result of screening
+type Result struct {
+	Status string `json:"status"`
+	// Provider is the name of the screener that returned the result
+	Provider string `json:"provider"`
+	// Matches are matched list entries
+	Matches []string `json:"matches,omitempty"`
+	// Details is a raw response of an external API
+	Details string `json:"details,omitempty"`
+	// Cached is true when the result was returned by Cache
+	Cached bool `json:"cached"`
+}
+
+// Entity returns an entity of the compliance audit trail
+func (r Result) Entity(transactionID string, subject Subject, screenedAt time.Time) (*entities.SanctionsScreening, error) {
+	matches, err := json.Marshal(r.Matches)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.SanctionsScreening{
+		TransactionID: transactionID,
+		Role:          subject.Role,
+		Address:       subject.Address,
+		AccountID:     subject.AccountID,
+		Name:          subject.Name(),
+		Provider:      r.Provider,
+		Status:        r.Status,
+		Matches:       string(matches),
+		Details:       r.Details,
+		Cached:        r.Cached,
+		ScreenedAt:    screenedAt,
+	}, nil
+}
+
+// Screener screens a subject against sanctions lists
+type Screener interface {
+	Screen(ctx context.Context, subject Subject) (Result, error)
+}
+
+// Screeners is a Screener running all screeners in order. The first match is
+// returned without running the next screeners, a review is returned when
+// any screener returned it.
+type Screeners []Screener
+
+// Screen implements Screener
+func (s Screeners) Screen(ctx context.Context, subject Subject) (Result, error) {
+	final := Result{Status: StatusClear, Cached: len(s) > 0}
+	var providers []string
+
+	for _, screener := range s {
+		result, err := screener.Screen(ctx, subject)
+		if err != nil {
+			sanctionsMetrics.errors.Inc()
+			return Result{}, err
+		}
+
+		switch result.Status {
+		case StatusMatch:
+			sanctionsMetrics.matches.Inc()
+			return result, nil
+		case StatusReview:
+			if final.Status != StatusReview {
+				final = result
+			}
+		}
+		providers = append(providers, result.Provider)
+		final.Cached = final.Cached && result.Cached
+	}
+
+	if final.Status == StatusReview {
+		sanctionsMetrics.reviews.Inc()
+		return final, nil
+	}
+
+	final.Provider = strings.Join(providers, ",")
+	return final, nil
+}
+
+// Cache is a Screener caching results of another Screener. Errors are not
+// cached.
+type Cache struct {
+	Screener Screener
+	lru      *cache.LRU
+}
+
+// NewCache creates a new Cache of size results expiring after ttl
+func NewCache(screener Screener, size int, ttl time.Duration) *Cache {
+	return &Cache{
+		Screener: screener,
+		lru:      cache.NewLRU(size, ttl, 0),
+	}
+}
+
+// Screen implements Screener
+func (c *Cache) Screen(ctx context.Context, subject Subject) (Result, error) {
+	key, err := json.Marshal(subject)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if value, _, ok := c.lru.Get(string(key)); ok {
+		result := value.(Result)
+		result.Cached = true
+		return result, nil
+	}
+
+	result, err := c.Screener.Screen(ctx, subject)
+	if err != nil {
+		return Result{}, err
+	}
+
+	c.lru.Add(string(key), result, nil)
+	return result, nil
+}
@@ -0,0 +1,193 @@
+package sanctions
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const list = `
+# Sanctioned entities
+Doe, John
+evil*example.com
+GBAD3PHXHBMDRY7YSGBYRGZWTQTHZT4R4QY7RSSMZZ2SKOKC2QYB4FTZ
+`
+
+type screenerFunc func(ctx context.Context, subject Subject) (Result, error)
+
+func (f screenerFunc) Screen(ctx context.Context, subject Subject) (Result, error) {
+	return f(ctx, subject)
+}
+
+func TestSubjectName(t *testing.T) {
+	assert.Equal(t, "", Subject{}.Name())
+	assert.Equal(t, "Acme Ltd", Subject{Info: map[string]string{"company_name": "Acme Ltd", "first_name": "John"}}.Name())
+	assert.Equal(t, "John Q Doe", Subject{Info: map[string]string{"first_name": "John", "middle_name": "Q", "last_name": "Doe"}}.Name())
+	assert.Equal(t, "John Doe", Subject{Info: map[string]string{"name": "John Doe", "first_name": "Jack"}}.Name())
+}
+
+func TestListMatcher(t *testing.T) {
+	matcher, err := ReadList(strings.NewReader(list))
+	require.NoError(t, err)
+	assert.Equal(t, 3, matcher.Len())
+	ctx := context.Background()
+
+	result, err := matcher.Screen(ctx, Subject{Address: "bob*stellar.org", Info: map[string]string{"first_name": "Bob"}})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Status: StatusClear, Provider: "list"}, result)
+
+	// Names are matched ignoring case, punctuation and order of words
+	result, err = matcher.Screen(ctx, Subject{Address: "john*stellar.org", Info: map[string]string{"first_name": "JOHN", "last_name": "doe"}})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Status: StatusMatch, Provider: "list", Matches: []string{"Doe, John"}}, result)
+
+	result, err = matcher.Screen(ctx, Subject{Address: "EVIL*example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusMatch, result.Status)
+
+	result, err = matcher.Screen(ctx, Subject{Address: "alice*example.com", AccountID: "GBAD3PHXHBMDRY7YSGBYRGZWTQTHZT4R4QY7RSSMZZ2SKOKC2QYB4FTZ"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GBAD3PHXHBMDRY7YSGBYRGZWTQTHZT4R4QY7RSSMZZ2SKOKC2QYB4FTZ"}, result.Matches)
+}
+
+func TestRESTScreener(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r)
+		bodies = append(bodies, string(body))
+
+		switch r.URL.Query().Get("name") {
+		case "John Doe":
+			w.Write([]byte(`{"result": {"matches": [{"name": "DOE, John"}]}}`))
+		case "Broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"result": {"matches": []}}`))
+		}
+	}))
+	defer server.Close()
+
+	screener, err := NewRESTScreener(RESTConfig{
+		URL:        server.URL + "/screen?name={{query .Name}}&country={{.Info.country}}",
+		Secret:     "api-key",
+		Headers:    map[string]string{"Authorization": "Bearer {{secret}}"},
+		Body:       `{"address": {{json .Address}}}`,
+		MatchField: "result.matches",
+	}, http.DefaultClient)
+	require.NoError(t, err)
+
+	subject := Subject{Address: `bob"*stellar.org`, Info: map[string]string{"first_name": "Bob"}}
+	result, err := screener.Screen(context.Background(), subject)
+	require.NoError(t, err)
+	assert.Equal(t, StatusClear, result.Status)
+	assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), result.Provider)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "POST", requests[0].Method)
+	assert.Equal(t, "Bearer api-key", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, "/screen?name=Bob&country=", requests[0].URL.RequestURI())
+	assert.Equal(t, `{"address": "bob\"*stellar.org"}`, bodies[0])
+
+	subject.Info = map[string]string{"first_name": "John", "last_name": "Doe"}
+	result, err = screener.Screen(context.Background(), subject)
+	require.NoError(t, err)
+	assert.Equal(t, StatusMatch, result.Status)
+	assert.Equal(t, `{"result": {"matches": [{"name": "DOE, John"}]}}`, result.Details)
+
+	subject.Info = map[string]string{"name": "Broken"}
+	_, err = screener.Screen(context.Background(), subject)
+	assert.EqualError(t, err, "Sanctions API returned status 500")
+
+	_, err = NewRESTScreener(RESTConfig{URL: "{{.Name"}, nil)
+	assert.Error(t, err)
+}
+
+func TestRESTScreenerStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("address") {
+		case "review*stellar.org":
+			w.WriteHeader(http.StatusAccepted)
+		case "match*stellar.org":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	screener, err := NewRESTScreener(RESTConfig{URL: server.URL + "?address={{query .Address}}"}, http.DefaultClient)
+	require.NoError(t, err)
+
+	for address, status := range map[string]string{
+		"bob*stellar.org":    StatusClear,
+		"review*stellar.org": StatusReview,
+		"match*stellar.org":  StatusMatch,
+	} {
+		result, err := screener.Screen(context.Background(), Subject{Address: address})
+		require.NoError(t, err)
+		assert.Equal(t, status, result.Status, address)
+	}
+}
+
+func TestScreeners(t *testing.T) {
+	ctx := context.Background()
+	matcher, err := ReadList(strings.NewReader(list))
+	require.NoError(t, err)
+
+	calls := 0
+	rest := screenerFunc(func(ctx context.Context, subject Subject) (Result, error) {
+		calls++
+		if subject.Address == "error*stellar.org" {
+			return Result{}, errors.New("unavailable")
+		}
+		if subject.Address == "review*stellar.org" {
+			return Result{Status: StatusReview, Provider: "api"}, nil
+		}
+		return Result{Status: StatusClear, Provider: "api"}, nil
+	})
+	screeners := Screeners{matcher, NewCache(rest, 10, time.Hour)}
+
+	result, err := screeners.Screen(ctx, Subject{Address: "bob*stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Status: StatusClear, Provider: "list,api"}, result)
+
+	// A match stops screening
+	result, err = screeners.Screen(ctx, Subject{Address: "evil*example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusMatch, result.Status)
+	assert.Equal(t, 1, calls)
+
+	result, err = screeners.Screen(ctx, Subject{Address: "review*stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Status: StatusReview, Provider: "api"}, result)
+
+	_, err = screeners.Screen(ctx, Subject{Address: "error*stellar.org"})
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 3, calls)
+
+	// Results are cached, errors are not
+	result, err = screeners.Screen(ctx, Subject{Address: "review*stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, Result{Status: StatusReview, Provider: "api", Cached: true}, result)
+	_, err = screeners.Screen(ctx, Subject{Address: "error*stellar.org"})
+	assert.Error(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestResultEntity(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	subject := Subject{Role: RoleSender, Address: "john*stellar.org", Info: map[string]string{"first_name": "John", "last_name": "Doe"}}
+	entity, err := Result{Status: StatusMatch, Provider: "list", Matches: []string{"Doe, John"}}.Entity("tx", subject, now)
+	require.NoError(t, err)
+	assert.Equal(t, "tx", entity.TransactionID)
+	assert.Equal(t, "John Doe", entity.Name)
+	assert.Equal(t, `["Doe, John"]`, entity.Matches)
+	assert.Equal(t, now, entity.ScreenedAt)
+}
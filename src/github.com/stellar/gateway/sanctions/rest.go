@@ -0,0 +1,220 @@
+package sanctions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// maxResponseSize limits responses of sanctions APIs
+const maxResponseSize = 64 * 1024
+
+// HTTP is an HTTP client sending requests to sanctions APIs
+type HTTP interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// RESTConfig configures a RESTScreener. URL, Body and Headers values are
+// text/template templates executed with Subject (ex. `{{.Name}}`,
+// `{{.Info.country}}`). `query` and `json` functions escape values for URL
+// query params and JSON strings, `secret` function returns Secret (ex. an API
+// key sent in a header).
+type RESTConfig struct {
+	URL    string
+	Secret string
+	// Method is GET when Body is empty, POST otherwise
+	Method  string
+	Headers map[string]string
+	Body    string
+	// MatchField is a dot separated path of a field of the JSON response
+	// (ex. `result.matches`) that is true, a non-zero number or a non-empty
+	// string, array or object for subjects matching a list. When empty,
+	// response status decides: 200 OK is clear, 202 Accepted is review and
+	// 403 Forbidden is match.
+	MatchField string
+}
+
+// RESTScreener is a Screener sending templated requests to a sanctions API
+type RESTScreener struct {
+	Config  RESTConfig
+	Client  HTTP
+	url     *template.Template
+	body    *template.Template
+	headers map[string]*template.Template
+}
+
+// NewRESTScreener creates a new RESTScreener. Returns an error when templates
+// cannot be parsed.
+func NewRESTScreener(config RESTConfig, client HTTP) (*RESTScreener, error) {
+	screener := &RESTScreener{
+		Config:  config,
+		Client:  client,
+		headers: map[string]*template.Template{},
+	}
+
+	funcs := template.FuncMap{
+		"query": url.QueryEscape,
+		"json": func(value string) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+		"secret": func() string {
+			return config.Secret
+		},
+	}
+
+	var err error
+	screener.url, err = parseTemplate("url", config.URL, funcs)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot parse url template: %s", err)
+	}
+
+	screener.body, err = parseTemplate("body", config.Body, funcs)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot parse body template: %s", err)
+	}
+
+	for name, value := range config.Headers {
+		screener.headers[name], err = parseTemplate(name, value, funcs)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot parse %s header template: %s", name, err)
+		}
+	}
+	return screener, nil
+}
+
+// Screen implements Screener
+func (s *RESTScreener) Screen(ctx context.Context, subject Subject) (Result, error) {
+	requestURL, err := execute(s.url, subject)
+	if err != nil {
+		return Result{}, err
+	}
+
+	method := s.Config.Method
+	var body io.Reader
+	if s.Config.Body != "" {
+		requestBody, err := execute(s.body, subject)
+		if err != nil {
+			return Result{}, err
+		}
+		body = strings.NewReader(requestBody)
+		if method == "" {
+			method = http.MethodPost
+		}
+	}
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return Result{}, err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, header := range s.headers {
+		value, err := execute(header, subject)
+		if err != nil {
+			return Result{}, err
+		}
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Status: StatusClear, Provider: req.URL.Host, Details: string(responseBody)}
+
+	if s.Config.MatchField == "" {
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusAccepted:
+			result.Status = StatusReview
+		case http.StatusForbidden:
+			result.Status = StatusMatch
+		default:
+			return Result{}, fmt.Errorf("Sanctions API returned status %d", resp.StatusCode)
+		}
+		return result, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("Sanctions API returned status %d", resp.StatusCode)
+	}
+
+	var response interface{}
+	err = json.Unmarshal(responseBody, &response)
+	if err != nil {
+		return Result{}, fmt.Errorf("Cannot decode sanctions API response: %s", err)
+	}
+
+	if truthy(field(response, s.Config.MatchField)) {
+		result.Status = StatusMatch
+	}
+	return result, nil
+}
+
+// parseTemplate parses a template, missing keys of Info are empty
+func parseTemplate(name, text string, funcs template.FuncMap) (*template.Template, error) {
+	return template.New(name).Option("missingkey=zero").Funcs(funcs).Parse(text)
+}
+
+func execute(t *template.Template, subject Subject) (string, error) {
+	var buffer bytes.Buffer
+	err := t.Execute(&buffer, subject)
+	return buffer.String(), err
+}
+
+// field returns a value of a dot separated path in a decoded JSON value.
+// Elements of arrays are selected by index.
+func field(value interface{}, path string) interface{} {
+	for _, name := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[name]
+		case []interface{}:
+			index, err := strconv.Atoi(name)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil
+			}
+			value = v[index]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+// Package secrets loads secret config params (seeds, passwords, MAC keys,
+// database URLs) from environment variables, files and HashiCorp Vault, so
+// they don't have to be stored in config files. Secret params are string
+// fields of config structs tagged with `secret:""`. A secret param, ex.
+// `base_seed` of `accounts` group of the bridge server, is loaded from (in
+// order of precedence):
+//
+//   - BRIDGE_ACCOUNTS_BASE_SEED environment variable,
+//   - a file at `base_seed_file` param (ex. a mounted Kubernetes secret),
+//   - `base_seed` param.
+//
+// When the value is a `vault:<path>#<field>` reference, ex.
+// `vault:secret/data/bridge#base_seed`, the field of the secret at path is
+// read from Vault (KV engine version 1 or 2).
+//
+// Elements of arrays of tables are numbered from 0 in environment variable
+// names, ex. BRIDGE_SIGNERS_0_SEED.
+package secrets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stellar/go/support/errors"
+)
+
+// FileSuffix is added to the name of a secret param to read it from a file
+const FileSuffix = "_file"
+
+// Redaction replaces values of secret params in Redacted
+const Redaction = "<redacted>"
+
+// elements is a path segment matching all elements of an array
+const elements = "[]"
+
+// HTTP represents an http client that Loader uses to make requests to Vault
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// Settings represents config params read from a config file, implemented by
+// *viper.Viper
+type Settings interface {
+	AllSettings() map[string]interface{}
+	Set(key string, value interface{})
+}
+
+// GlobalViper is Settings of the global viper instance
+var GlobalViper Settings = globalViper{}
+
+type globalViper struct{}
+
+func (globalViper) AllSettings() map[string]interface{} { return viper.AllSettings() }
+
+func (globalViper) Set(key string, value interface{}) { viper.Set(key, value) }
+
+// Loader loads secret params
+type Loader struct {
+	// EnvPrefix is the prefix of environment variables, ex. "BRIDGE"
+	EnvPrefix string
+	// VaultURL and VaultToken are taken from VAULT_ADDR and VAULT_TOKEN
+	// environment variables by default
+	VaultURL   string
+	VaultToken string
+	Client     HTTP
+	lookupEnv  func(key string) (string, bool)
+}
+
+// NewLoader creates a new Loader of secrets of an app using environment
+// variables starting with envPrefix
+func NewLoader(envPrefix string) *Loader {
+	return &Loader{
+		EnvPrefix:  envPrefix,
+		VaultURL:   os.Getenv("VAULT_ADDR"),
+		VaultToken: os.Getenv("VAULT_TOKEN"),
+		Client:     &http.Client{Timeout: 10 * time.Second},
+		lookupEnv:  os.LookupEnv,
+	}
+}
+
+// Load sets values of secret params of config (a pointer to a config struct)
+// in v. It must be called before config is unmarshalled.
+func (l *Loader) Load(v Settings, config interface{}) error {
+	settings := v.AllSettings()
+	vault := newVaultClient(l.VaultURL, l.VaultToken, l.Client)
+
+	// Groups are set as a whole, viper doesn't merge nested keys
+	changed := map[string]bool{}
+	for _, path := range Params(config) {
+		ok, err := l.resolve(settings, path, l.EnvPrefix, vault)
+		if err != nil {
+			return err
+		}
+		if ok {
+			changed[lookupKey(settings, path[0])] = true
+		}
+	}
+
+	for key := range changed {
+		v.Set(key, settings[key])
+	}
+	return nil
+}
+
+// resolve loads the param at path of node. Returns true when node has been
+// changed.
+func (l *Loader) resolve(node map[string]interface{}, path []string, envName string, vault *vaultClient) (bool, error) {
+	key := lookupKey(node, path[0])
+	envName += "_" + strings.ToUpper(path[0])
+
+	if len(path) == 1 {
+		return l.resolveValue(node, key, envName, vault)
+	}
+
+	if path[1] == elements {
+		list, ok := node[key].([]interface{})
+		if !ok {
+			list = toList(node[key])
+			if list == nil {
+				return false, nil
+			}
+			node[key] = list
+		}
+
+		changed := false
+		for i, element := range list {
+			child, ok := element.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ok, err := l.resolve(child, path[2:], envName+"_"+strconv.Itoa(i), vault)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || ok
+		}
+		return changed, nil
+	}
+
+	child, ok := node[key].(map[string]interface{})
+	if !ok {
+		// Group may be missing in the config file when the param is set in
+		// an environment variable
+		child = map[string]interface{}{}
+	}
+	changed, err := l.resolve(child, path[1:], envName, vault)
+	if changed {
+		node[key] = child
+	}
+	return changed, err
+}
+
+func (l *Loader) resolveValue(node map[string]interface{}, key, envName string, vault *vaultClient) (bool, error) {
+	name := strings.ToLower(strings.TrimPrefix(envName, l.EnvPrefix+"_"))
+	value, _ := node[key].(string)
+	original := value
+
+	if env, ok := l.lookupEnv(envName); ok {
+		value = env
+	} else if file, _ := node[lookupKey(node, key+FileSuffix)].(string); file != "" {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return false, fmt.Errorf("Cannot read %s param file: %s", name, err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+
+	if strings.HasPrefix(value, VaultPrefix) {
+		var err error
+		value, err = vault.get(value)
+		if err != nil {
+			return false, errors.Wrap(err, "Cannot load "+name+" param from Vault")
+		}
+	}
+
+	if value == original {
+		return false, nil
+	}
+	node[key] = value
+	return true, nil
+}
+
+// lookupKey returns the key of node matching name case-insensitively. Viper
+// lowercases keys of groups but not keys of arrays of tables.
+func lookupKey(node map[string]interface{}, name string) string {
+	if _, ok := node[name]; ok {
+		return name
+	}
+	for key := range node {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
+
+// toList converts arrays of tables (ex. []map[string]interface{}) to
+// []interface{}, returns nil for other values
+func toList(value interface{}) []interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		list[i] = v.Index(i).Interface()
+	}
+	return list
+}
+
+// Params returns paths of secret params of config (a config struct or a
+// pointer to it), ex. ["accounts", "base_seed"]. Elements of arrays are
+// matched by "[]" segment, ex. ["signers", "[]", "seed"].
+func Params(config interface{}) [][]string {
+	var result [][]string
+	params(reflect.TypeOf(config), nil, &result)
+	return result
+}
+
+func params(t reflect.Type, prefix []string, result *[][]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		path := prefix
+		name, squash := mapstructureName(field)
+		if !squash {
+			path = append(append([]string{}, prefix...), name)
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		switch {
+		case isSecret(field) && fieldType.Kind() == reflect.String:
+			*result = append(*result, path)
+		case fieldType.Kind() == reflect.Struct:
+			params(fieldType, path, result)
+		case fieldType.Kind() == reflect.Slice:
+			params(fieldType.Elem(), append(path, elements), result)
+		}
+	}
+}
+
+// mapstructureName returns the name of a field in config files and true
+// when the fields of an embedded struct are squashed
+func mapstructureName(field reflect.StructField) (string, bool) {
+	parts := strings.Split(field.Tag.Get("mapstructure"), ",")
+	for _, option := range parts[1:] {
+		if option == "squash" {
+			return "", true
+		}
+	}
+	if parts[0] != "" {
+		return parts[0], false
+	}
+	return strings.ToLower(field.Name), false
+}
+
+func isSecret(field reflect.StructField) bool {
+	_, ok := field.Tag.Lookup("secret")
+	return ok
+}
+
+// Redacted returns a copy of config (a config struct or a pointer to it)
+// with non-empty values of secret params replaced with Redaction. Use it
+// before config is logged or dumped.
+func Redacted(config interface{}) interface{} {
+	if config == nil {
+		return nil
+	}
+	return redacted(reflect.ValueOf(config)).Interface()
+}
+
+func redacted(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		result := reflect.New(v.Type().Elem())
+		result.Elem().Set(redacted(v.Elem()))
+		return result
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		result := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			result.Index(i).Set(redacted(v.Index(i)))
+		}
+		return result
+	case reflect.Struct:
+		result := reflect.New(v.Type()).Elem()
+		result.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if isSecret(field) && field.Type.Kind() == reflect.String {
+				if v.Field(i).String() != "" {
+					result.Field(i).SetString(Redaction)
+				}
+				continue
+			}
+			result.Field(i).Set(redacted(v.Field(i)))
+		}
+		return result
+	}
+	return v
+}
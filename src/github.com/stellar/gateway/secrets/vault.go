@@ -0,0 +1,97 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// VaultPrefix starts values of params referencing secrets in Vault, ex.
+// `vault:secret/data/bridge#base_seed`
+const VaultPrefix = "vault:"
+
+// vaultClient reads secrets from Vault HTTP API. Each secret is read once.
+type vaultClient struct {
+	url    string
+	token  string
+	client HTTP
+	cache  map[string]map[string]interface{}
+}
+
+func newVaultClient(url, token string, client HTTP) *vaultClient {
+	return &vaultClient{
+		url:    strings.TrimRight(url, "/"),
+		token:  token,
+		client: client,
+		cache:  map[string]map[string]interface{}{},
+	}
+}
+
+// get returns the value of a `vault:<path>#<field>` reference
+func (v *vaultClient) get(reference string) (string, error) {
+	tokens := strings.SplitN(strings.TrimPrefix(reference, VaultPrefix), "#", 2)
+	if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+		return "", errors.New("Invalid reference, expected vault:<path>#<field>")
+	}
+	path, field := strings.Trim(tokens[0], "/"), tokens[1]
+
+	data, err := v.read(path)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("Secret %s has no %s field", path, field)
+	}
+	return value, nil
+}
+
+func (v *vaultClient) read(path string) (map[string]interface{}, error) {
+	if data, ok := v.cache[path]; ok {
+		return data, nil
+	}
+
+	if v.url == "" {
+		return nil, errors.New("VAULT_ADDR environment variable is not set")
+	}
+
+	req, err := http.NewRequest("GET", v.url+"/v1/"+path, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot create request")
+	}
+	if v.token != "" {
+		req.Header.Set("X-Vault-Token", v.token)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Reading secret %s returned status %d", path, resp.StatusCode)
+	}
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "Cannot decode response")
+	}
+
+	data := response.Data
+	// KV version 2 engine wraps secret data with metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	v.cache[path] = data
+	return data, nil
+}
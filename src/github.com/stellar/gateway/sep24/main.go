@@ -0,0 +1,214 @@
+// Package sep24 stores deposits and withdrawals of anchors running SEP-24
+// interactive flows in the bridge database and serves their status in the
+// format of SEP-24 /transactions API, so the anchor's SEP-24 server does not
+// keep its own records.
+//
+// The anchor creates a transaction when the user starts the interactive flow
+// and updates it while the transfer is processed. A withdrawal has a memo the
+// user sends the payment with: when it's waiting for the user's transfer
+// (pending_user_transfer_start), a payment received by the receiving account
+// with the memo and asset of the withdrawal moves it to pending_anchor. Status
+// changes are sent to webhooks as sep24_transaction_updated events.
+package sep24
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/webhooks"
+	"github.com/stellar/go/support/errors"
+)
+
+var matchedWithdrawals = metrics.NewCounter("bridge_sep24_withdrawals_matched_total", "Number of received payments matched with SEP-24 withdrawals.")
+
+// Transaction is a SEP-24 transaction in the format of SEP-24 /transaction
+// and /transactions endpoints
+type Transaction struct {
+	ID                    string     `json:"id"`
+	Kind                  string     `json:"kind"`
+	Status                string     `json:"status"`
+	MoreInfoURL           string     `json:"more_info_url,omitempty"`
+	AmountIn              string     `json:"amount_in,omitempty"`
+	AmountInAsset         string     `json:"amount_in_asset,omitempty"`
+	AmountOut             string     `json:"amount_out,omitempty"`
+	AmountOutAsset        string     `json:"amount_out_asset,omitempty"`
+	AmountFee             string     `json:"amount_fee,omitempty"`
+	StartedAt             time.Time  `json:"started_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+	CompletedAt           *time.Time `json:"completed_at"`
+	StellarTransactionID  string     `json:"stellar_transaction_id,omitempty"`
+	ExternalTransactionID string     `json:"external_transaction_id,omitempty"`
+	Message               string     `json:"message,omitempty"`
+	Refunded              bool       `json:"refunded"`
+	From                  string     `json:"from,omitempty"`
+	To                    string     `json:"to,omitempty"`
+	WithdrawAnchorAccount string     `json:"withdraw_anchor_account,omitempty"`
+	WithdrawMemoType      string     `json:"withdraw_memo_type,omitempty"`
+	WithdrawMemo          string     `json:"withdraw_memo,omitempty"`
+	DepositMemoType       string     `json:"deposit_memo_type,omitempty"`
+	DepositMemo           string     `json:"deposit_memo,omitempty"`
+}
+
+// NewTransaction converts a stored transaction to SEP-24 format. Amounts
+// that are not known yet (zero) are omitted.
+func NewTransaction(transaction *entities.SEP24Transaction) Transaction {
+	t := Transaction{
+		ID:                    transaction.TransactionID,
+		Kind:                  transaction.Kind,
+		Status:                transaction.Status,
+		MoreInfoURL:           transaction.MoreInfoURL,
+		AmountIn:              amount(transaction.AmountIn),
+		AmountOut:             amount(transaction.AmountOut),
+		AmountFee:             amount(transaction.AmountFee),
+		StartedAt:             transaction.StartedAt,
+		UpdatedAt:             transaction.UpdatedAt,
+		CompletedAt:           transaction.CompletedAt,
+		StellarTransactionID:  transaction.StellarTransactionID,
+		ExternalTransactionID: transaction.ExternalTransactionID,
+		Message:               transaction.Message,
+		Refunded:              transaction.Status == entities.SEP24StatusRefunded,
+	}
+
+	// Asset in SEP-38 format
+	asset := "stellar:native"
+	if transaction.AssetIssuer != "" {
+		asset = "stellar:" + transaction.AssetCode + ":" + transaction.AssetIssuer
+	}
+
+	if transaction.Kind == entities.SEP24KindWithdrawal {
+		t.From = transaction.Account
+		t.AmountInAsset = asset
+		t.WithdrawAnchorAccount = transaction.AnchorAccount
+		t.WithdrawMemoType = transaction.MemoType
+		t.WithdrawMemo = transaction.Memo
+	} else {
+		t.To = transaction.Account
+		t.AmountOutAsset = asset
+		t.DepositMemoType = transaction.MemoType
+		t.DepositMemo = transaction.Memo
+	}
+	return t
+}
+
+func amount(value amounts.Amount) string {
+	if value == 0 {
+		return ""
+	}
+	return value.String()
+}
+
+// Tracker saves SEP-24 transactions and matches received payments with
+// withdrawals. A nil *Tracker does nothing, so it can be used when SEP-24
+// transactions are disabled.
+type Tracker struct {
+	repository    db.RepositoryInterface
+	entityManager db.EntityManagerInterface
+	webhooks      *webhooks.Dispatcher
+	log           *logrus.Entry
+}
+
+// NewTracker creates a new Tracker sending status changes to dispatcher, it
+// can be nil when webhooks are disabled
+func NewTracker(repository db.RepositoryInterface, entityManager db.EntityManagerInterface, dispatcher *webhooks.Dispatcher) *Tracker {
+	return &Tracker{
+		repository:    repository,
+		entityManager: entityManager,
+		webhooks:      dispatcher,
+		log:           logrus.WithFields(logrus.Fields{"service": "SEP24"}),
+	}
+}
+
+// Save persists transaction changed at now. When its status is different from
+// previousStatus (empty for new transactions) the change is sent to webhooks
+// and completed_at is set when the transaction is completed or refunded.
+func (t *Tracker) Save(ctx context.Context, transaction *entities.SEP24Transaction, previousStatus string, now time.Time) error {
+	if t == nil {
+		return nil
+	}
+
+	changed := transaction.Status != previousStatus
+	if changed && transaction.Completed() {
+		transaction.CompletedAt = &now
+	}
+	transaction.UpdatedAt = now
+
+	err := t.entityManager.Persist(transaction)
+	if err != nil {
+		return errors.Wrap(err, "Error persisting SEP24Transaction")
+	}
+
+	if changed {
+		t.log.WithFields(logrus.Fields{
+			"id":              transaction.TransactionID,
+			"kind":            transaction.Kind,
+			"status":          transaction.Status,
+			"previous_status": previousStatus,
+		}).Info("SEP-24 transaction status changed")
+		t.webhooks.Publish(ctx, webhooks.EventSEP24TransactionUpdated, NewTransaction(transaction))
+	}
+	return nil
+}
+
+// Match matches a payment received by the receiving account with a
+// withdrawal waiting for the user's transfer with the same asset and memo.
+// Memo must be loaded. A payment matched before (ex. reprocessed) returns
+// the withdrawal it was matched with. Returns nil when the payment does not
+// match any withdrawal or t is nil.
+func (t *Tracker) Match(ctx context.Context, payment horizon.PaymentResponse, now time.Time) (*entities.SEP24Transaction, error) {
+	if t == nil || payment.Memo.Type == "" || payment.Memo.Type == "none" {
+		return nil, nil
+	}
+
+	transaction, err := t.repository.GetSEP24TransactionByStellarID(ctx, payment.TransactionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting SEP24Transaction")
+	}
+	if transaction != nil && transaction.Kind == entities.SEP24KindWithdrawal {
+		return transaction, nil
+	}
+
+	assetCode, assetIssuer := payment.AssetCode, payment.AssetIssuer
+	if payment.AssetType == "native" || payment.Type == "account_merge" {
+		assetCode, assetIssuer = "XLM", ""
+	}
+
+	transaction, err = t.repository.GetPendingSEP24Withdrawal(ctx, payment.To, assetCode, assetIssuer, payment.Memo.Type, payment.Memo.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting SEP24Transaction")
+	}
+	if transaction == nil {
+		return nil, nil
+	}
+
+	received, err := amounts.ParseAmount(payment.Amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid payment amount")
+	}
+
+	logger := t.log.WithFields(logrus.Fields{"id": transaction.TransactionID, "operation_id": payment.ID})
+	if transaction.AmountIn != 0 && transaction.AmountIn != received {
+		logger.WithFields(logrus.Fields{
+			"amount_in": transaction.AmountIn.String(),
+			"received":  received.String(),
+		}).Warn("Received amount of SEP-24 withdrawal differs from amount_in")
+	}
+
+	previousStatus := transaction.Status
+	transaction.Status = entities.SEP24StatusPendingAnchor
+	transaction.AmountIn = received
+	transaction.StellarTransactionID = payment.TransactionID
+	err = t.Save(ctx, transaction, previousStatus, now)
+	if err != nil {
+		return nil, err
+	}
+
+	matchedWithdrawals.Inc()
+	logger.Info("Received payment matched SEP-24 withdrawal")
+	return transaction, nil
+}
@@ -0,0 +1,138 @@
+package sep24
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	issuer        = "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"
+	anchorAccount = "GAMVF7G4GJC4A7JMFJWLUAEIBFQD5RT3DCB5DC5TJDEKQBBACQ4JZVEE"
+	userAccount   = "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"
+)
+
+func payment(id, amount, memo string) horizon.PaymentResponse {
+	payment := horizon.PaymentResponse{
+		ID:            id,
+		Type:          "payment",
+		From:          userAccount,
+		To:            anchorAccount,
+		Amount:        amount,
+		AssetType:     "credit_alphanum4",
+		AssetCode:     "USD",
+		AssetIssuer:   issuer,
+		TransactionID: "tx" + id,
+	}
+	payment.Memo.Type = "id"
+	payment.Memo.Value = memo
+	return payment
+}
+
+func TestNewTransaction(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	transaction := NewTransaction(&entities.SEP24Transaction{
+		TransactionID: "withdrawal-1",
+		Kind:          entities.SEP24KindWithdrawal,
+		Status:        entities.SEP24StatusRefunded,
+		Account:       userAccount,
+		AssetCode:     "USD",
+		AssetIssuer:   issuer,
+		AmountIn:      amounts.Amount(amounts.MustParse("10")),
+		AnchorAccount: anchorAccount,
+		MemoType:      "id",
+		Memo:          "1",
+		StartedAt:     now,
+		UpdatedAt:     now,
+	})
+	assert.Equal(t, "10.0000000", transaction.AmountIn)
+	assert.Equal(t, "stellar:USD:"+issuer, transaction.AmountInAsset)
+	assert.Empty(t, transaction.AmountOut)
+	assert.Equal(t, userAccount, transaction.From)
+	assert.Equal(t, anchorAccount, transaction.WithdrawAnchorAccount)
+	assert.Equal(t, "1", transaction.WithdrawMemo)
+	assert.True(t, transaction.Refunded)
+
+	transaction = NewTransaction(&entities.SEP24Transaction{
+		Kind:      entities.SEP24KindDeposit,
+		Account:   userAccount,
+		AssetCode: "XLM",
+	})
+	assert.Equal(t, "stellar:native", transaction.AmountOutAsset)
+	assert.Equal(t, userAccount, transaction.To)
+	assert.Empty(t, transaction.From)
+}
+
+func TestTracker(t *testing.T) {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	repository := db.NewRepository(driver)
+	entityManager := db.NewEntityManager(driver)
+	tracker := NewTracker(repository, entityManager, nil)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	for _, transaction := range []*entities.SEP24Transaction{
+		{TransactionID: "withdrawal-1", Status: entities.SEP24StatusPendingUserTransferStart, MemoType: "id", Memo: "1"},
+		{TransactionID: "withdrawal-2", Status: entities.SEP24StatusIncomplete, MemoType: "id", Memo: "2"},
+	} {
+		transaction.Kind = entities.SEP24KindWithdrawal
+		transaction.Account = userAccount
+		transaction.AssetCode = "USD"
+		transaction.AssetIssuer = issuer
+		transaction.AnchorAccount = anchorAccount
+		transaction.StartedAt = now
+		require.NoError(t, tracker.Save(ctx, transaction, "", now))
+	}
+
+	// Not waiting for the user's transfer
+	match, err := tracker.Match(ctx, payment("1", "10", "2"), now)
+	require.NoError(t, err)
+	assert.Nil(t, match)
+
+	match, err = tracker.Match(ctx, payment("2", "10", "3"), now)
+	require.NoError(t, err)
+	assert.Nil(t, match)
+
+	later := now.Add(time.Minute)
+	match, err = tracker.Match(ctx, payment("3", "10", "1"), later)
+	require.NoError(t, err)
+	require.NotNil(t, match)
+	assert.Equal(t, "withdrawal-1", match.TransactionID)
+
+	transaction, err := repository.GetSEP24Transaction(ctx, "withdrawal-1")
+	require.NoError(t, err)
+	assert.Equal(t, entities.SEP24StatusPendingAnchor, transaction.Status)
+	assert.Equal(t, "10.0000000", transaction.AmountIn.String())
+	assert.Equal(t, "tx3", transaction.StellarTransactionID)
+	assert.True(t, transaction.UpdatedAt.Equal(later))
+	assert.Nil(t, transaction.CompletedAt)
+
+	// Reprocessed payments return the matched withdrawal
+	match, err = tracker.Match(ctx, payment("3", "10", "1"), later)
+	require.NoError(t, err)
+	require.NotNil(t, match)
+	assert.Equal(t, "withdrawal-1", match.TransactionID)
+
+	previousStatus := transaction.Status
+	transaction.Status = entities.SEP24StatusCompleted
+	require.NoError(t, tracker.Save(ctx, transaction, previousStatus, later))
+	require.NotNil(t, transaction.CompletedAt)
+	assert.True(t, transaction.CompletedAt.Equal(later))
+
+	var disabled *Tracker
+	match, err = disabled.Match(ctx, payment("4", "10", "2"), now)
+	assert.NoError(t, err)
+	assert.Nil(t, match)
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"encoding/json"
 	"net/http"
+
+	"github.com/stellar/gateway/protocols"
 )
 
 // Response represents response that can be returned by a server
@@ -10,10 +13,120 @@ type Response interface {
 	Marshal() []byte
 }
 
+// Envelope is the body of responses written to requests with envelope
+// enabled (see EnvelopeMiddleware). Exactly one of Result and Error is null.
+type Envelope struct {
+	Result json.RawMessage `json:"result"`
+	Error  *EnvelopeError  `json:"error"`
+}
+
+// EnvelopeError is an error of Envelope. Extras contain data of the error
+// response and `more_info` when sent.
+type EnvelopeError struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Extras  map[string]interface{} `json:"extras,omitempty"`
+}
+
 // Write writes a response to the given http.ResponseWriter
 func Write(w http.ResponseWriter, response Response) {
+	body := response.Marshal()
+	if Enveloped(w) {
+		body = envelope(response, body)
+	}
+
 	if response.HTTPStatus() != 200 {
 		w.WriteHeader(response.HTTPStatus())
 	}
-	w.Write(response.Marshal())
+	w.Write(body)
+}
+
+// WriteJSON writes value encoded to JSON with 200 status. Nothing is written
+// when value cannot be encoded.
+func WriteJSON(w http.ResponseWriter, value interface{}) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	Write(w, jsonResponse(body))
+	return nil
+}
+
+// jsonResponse is a Response of JSON encoded value
+type jsonResponse []byte
+
+func (response jsonResponse) HTTPStatus() int {
+	return http.StatusOK
+}
+
+func (response jsonResponse) Marshal() []byte {
+	return append(response, '\n')
+}
+
+// envelope wraps body of response in Envelope
+func envelope(response Response, body []byte) []byte {
+	var e Envelope
+	if errorResponse, ok := response.(*protocols.ErrorResponse); ok {
+		e.Error = &EnvelopeError{Code: errorResponse.Code, Message: errorResponse.Message}
+		if len(errorResponse.Data) > 0 || errorResponse.MoreInfo != "" {
+			e.Error.Extras = map[string]interface{}{}
+			for key, value := range errorResponse.Data {
+				e.Error.Extras[key] = value
+			}
+			if errorResponse.MoreInfo != "" {
+				e.Error.Extras["more_info"] = errorResponse.MoreInfo
+			}
+		}
+	} else {
+		e.Result = body
+	}
+
+	enveloped, err := json.Marshal(e)
+	if err != nil {
+		// Ex. response is not JSON
+		return body
+	}
+	return append(enveloped, '\n')
+}
+
+// envelopeWriter is added by EnvelopeMiddleware. enabled is checked once,
+// when a response is written.
+type envelopeWriter struct {
+	http.ResponseWriter
+	request *http.Request
+	check   func(r *http.Request) bool
+	enabled *bool
+}
+
+func (w *envelopeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *envelopeWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *envelopeWriter) isEnabled() bool {
+	if w.enabled == nil {
+		enabled := w.check(w.request)
+		w.enabled = &enabled
+	}
+	return *w.enabled
+}
+
+// Enveloped returns true when responses written to w are wrapped in Envelope.
+// Writers added by other middlewares are unwrapped using `Unwrap()` method.
+func Enveloped(w http.ResponseWriter) bool {
+	for {
+		switch writer := w.(type) {
+		case *envelopeWriter:
+			return writer.isEnabled()
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = writer.Unwrap()
+		default:
+			return false
+		}
+	}
 }
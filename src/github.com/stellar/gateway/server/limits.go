@@ -0,0 +1,188 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols"
+	"github.com/zenazn/goji/graceful"
+)
+
+// Limits contains timeouts and the size of the worker pool of a single
+// listener, ex. `http.api` config group. Every listener is served by its own
+// http.Server, so a flood of requests to one of them does not exhaust
+// capacity of the others. Empty values mean no limit.
+type Limits struct {
+	// ReadTimeout is the max time of reading a request including its body
+	ReadTimeout string `mapstructure:"read_timeout"`
+	// WriteTimeout is the max time from the end of reading a request headers
+	// to the end of writing the response
+	WriteTimeout string `mapstructure:"write_timeout"`
+	// IdleTimeout is the max time of waiting for the next request on a
+	// keep-alive connection
+	IdleTimeout string `mapstructure:"idle_timeout"`
+	// RequestTimeout is the max time of handling a request, the client gets
+	// 503 Service Unavailable after it
+	RequestTimeout string `mapstructure:"request_timeout"`
+	// MaxConcurrent is the number of workers: the max number of requests
+	// handled at once
+	MaxConcurrent int `mapstructure:"max_concurrent"`
+	// QueueTimeout is the max time a request waits for a free worker, the
+	// client gets 503 Service Unavailable after it (default "1s")
+	QueueTimeout string `mapstructure:"queue_timeout"`
+}
+
+// DefaultQueueTimeout is the default max time a request waits for a free
+// worker
+const DefaultQueueTimeout = time.Second
+
+func parseDuration(value string) time.Duration {
+	// Values are checked in Validate
+	duration, _ := time.ParseDuration(value)
+	return duration
+}
+
+// RequestTimeoutDuration returns RequestTimeout duration or 0 when it's empty
+func (l Limits) RequestTimeoutDuration() time.Duration {
+	return parseDuration(l.RequestTimeout)
+}
+
+// QueueTimeoutOrDefault returns QueueTimeout or DefaultQueueTimeout when
+// it's empty
+func (l Limits) QueueTimeoutOrDefault() time.Duration {
+	if l.QueueTimeout == "" {
+		return DefaultQueueTimeout
+	}
+	return parseDuration(l.QueueTimeout)
+}
+
+// Validate checks if limits of a config group name are correct
+func (l Limits) Validate(name string) error {
+	for param, value := range map[string]string{
+		"read_timeout":    l.ReadTimeout,
+		"write_timeout":   l.WriteTimeout,
+		"idle_timeout":    l.IdleTimeout,
+		"request_timeout": l.RequestTimeout,
+		"queue_timeout":   l.QueueTimeout,
+	} {
+		if value == "" {
+			continue
+		}
+		if duration, err := time.ParseDuration(value); err != nil || duration <= 0 {
+			return errors.New("Cannot parse " + name + "." + param + " param")
+		}
+	}
+
+	if l.MaxConcurrent < 0 {
+		return errors.New(name + ".max_concurrent param cannot be negative")
+	}
+
+	return nil
+}
+
+// NewServer creates a server of handler listening on addr with the limits.
+// Requests over the limits are rejected with ServiceUnavailableError.
+// listener names the server in metrics, ex. "bridge_api".
+func (l Limits) NewServer(listener, addr string, handler http.Handler) *graceful.Server {
+	if l.MaxConcurrent > 0 {
+		handler = NewWorkerPool(listener, l.MaxConcurrent, l.QueueTimeoutOrDefault()).Middleware(handler)
+	}
+	if timeout := l.RequestTimeoutDuration(); timeout > 0 {
+		handler = TimeoutMiddleware(timeout)(handler)
+	}
+
+	return &graceful.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  parseDuration(l.ReadTimeout),
+		WriteTimeout: parseDuration(l.WriteTimeout),
+		IdleTimeout:  parseDuration(l.IdleTimeout),
+	}
+}
+
+// WorkerPool limits the number of requests handled at once. Requests wait
+// up to QueueTimeout for a free worker.
+type WorkerPool struct {
+	QueueTimeout time.Duration
+
+	workers  chan struct{}
+	running  *metrics.Gauge
+	rejected *metrics.Counter
+}
+
+// NewWorkerPool creates a new WorkerPool of size workers
+func NewWorkerPool(listener string, size int, queueTimeout time.Duration) *WorkerPool {
+	return &WorkerPool{
+		QueueTimeout: queueTimeout,
+		workers:      make(chan struct{}, size),
+		running: metrics.NewGauge(
+			metrics.Label("http_requests_running", "listener", listener),
+			"Number of requests being handled by the listener.",
+		),
+		rejected: metrics.NewCounter(
+			metrics.Label("http_requests_rejected_total", "listener", listener),
+			"Number of requests rejected because all workers of the listener were busy.",
+		),
+	}
+}
+
+// Middleware rejects requests that did not get a free worker in time.
+// Streams (see IsStream) don't use workers, they would hold them until
+// closed.
+func (p *WorkerPool) Middleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if IsStream(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		timer := time.NewTimer(p.QueueTimeout)
+		defer timer.Stop()
+
+		select {
+		case p.workers <- struct{}{}:
+		case <-timer.C:
+			p.rejected.Inc()
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(int(p.QueueTimeout/time.Second)+1))
+			Write(w, protocols.ServiceUnavailableError)
+			return
+		case <-r.Context().Done():
+			return
+		}
+
+		p.running.Set(float64(len(p.workers)))
+		defer func() {
+			<-p.workers
+			p.running.Set(float64(len(p.workers)))
+		}()
+
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// TimeoutMiddleware responds with ServiceUnavailableError when a request is
+// not handled in timeout. The request context is cancelled then, so handlers
+// using it stop early. gRPC requests are not limited, they can stream
+// responses and have their own deadlines (grpc-timeout header). Streams (see
+// IsStream) are not limited either.
+func TimeoutMiddleware(timeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		timeoutHandler := http.TimeoutHandler(next, timeout, string(protocols.ServiceUnavailableError.Marshal()))
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if IsGRPC(r) || IsStream(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// TimeoutHandler does not set headers of the timeout response
+			w.Header().Set("Content-Type", "application/json")
+			timeoutHandler.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool := NewWorkerPool("test", 1, 50*time.Millisecond)
+	handler := pool.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			started <- struct{}{}
+			<-release
+		}
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/slow", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}()
+	<-started
+
+	// The only worker is busy
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/fast", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"code": "service_unavailable"`)
+	assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, int64(1), pool.rejected.Value())
+
+	close(release)
+	wg.Wait()
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/fast", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	handler := TimeoutMiddleware(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `"code": "service_unavailable"`)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/fast", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "{}", recorder.Body.String())
+
+	// gRPC calls have their own deadlines
+	request := httptest.NewRequest("POST", "/slow", nil)
+	request.Header.Set("Content-Type", "application/grpc")
+	ctx, cancel := context.WithTimeout(request.Context(), 40*time.Millisecond)
+	defer cancel()
+	recorder = httptest.NewRecorder()
+	start := time.Now()
+	handler.ServeHTTP(recorder, request.WithContext(ctx))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+
+	// Streams are open until closed by the client
+	request = httptest.NewRequest("GET", "/slow", nil)
+	request.Header.Set("Accept", "text/event-stream")
+	ctx, cancel = context.WithTimeout(request.Context(), 40*time.Millisecond)
+	defer cancel()
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request.WithContext(ctx))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestLimits(t *testing.T) {
+	limits := Limits{ReadTimeout: "5s", WriteTimeout: "30s", RequestTimeout: "25s", MaxConcurrent: 10}
+	require.NoError(t, limits.Validate("http.api"))
+	assert.Equal(t, DefaultQueueTimeout, limits.QueueTimeoutOrDefault())
+
+	server := limits.NewServer("test", ":8000", http.NotFoundHandler())
+	assert.Equal(t, ":8000", server.Addr)
+	assert.Equal(t, 5*time.Second, server.ReadTimeout)
+	assert.Equal(t, 30*time.Second, server.WriteTimeout)
+	assert.Equal(t, time.Duration(0), server.IdleTimeout)
+
+	assert.EqualError(t, Limits{RequestTimeout: "soon"}.Validate("http.api"), "Cannot parse http.api.request_timeout param")
+	assert.EqualError(t, Limits{MaxConcurrent: -1}.Validate("http.admin"), "http.admin.max_concurrent param cannot be negative")
+}
@@ -1,8 +1,18 @@
 package server
 
 import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+
+	"github.com/stellar/gateway/protocols"
 )
 
 // StripTrailingSlashMiddleware strips trailing slash.
@@ -49,19 +59,204 @@ func HeadersMiddleware() func(next http.Handler) http.Handler {
 	}
 }
 
+// IsGRPC returns true for gRPC requests
+func IsGRPC(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// IsStream returns true for requests opening a Server-Sent Events stream or a
+// WebSocket connection
+func IsStream(r *http.Request) bool {
+	if r.Method != "GET" {
+		return false
+	}
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
 // APIKeyMiddleware checks for apiKey in a request and writes http.StatusForbidden if it's incorrect.
-func APIKeyMiddleware(apiKey string) func(next http.Handler) http.Handler {
+// gRPC requests send the key in `apikey` metadata (header) and DELETE
+// requests in `Apikey` header, so it's not logged with the URL. Streams send
+// it in `apiKey` query param, browsers can't set headers of EventSource and
+// WebSocket requests.
+// onForbidden is called (when not nil) for every rejected request.
+func APIKeyMiddleware(apiKey string, onForbidden func(r *http.Request)) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
-			if r.Method == "POST" {
-				k := r.PostFormValue("apiKey")
-				if k != apiKey {
+			var k string
+			switch {
+			case IsGRPC(r), r.Method == "DELETE":
+				k = r.Header.Get("Apikey")
+			case r.Method == "POST":
+				k = r.PostFormValue("apiKey")
+			case IsStream(r):
+				k = r.URL.Query().Get("apiKey")
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if k != apiKey {
+				if onForbidden != nil {
+					onForbidden(r)
+				}
+				if Enveloped(w) {
+					Write(w, protocols.InvalidAPIKeyError)
+				} else {
 					http.Error(w, "Forbidden", http.StatusForbidden)
+				}
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// BasicAuthMiddleware checks HTTP basic auth credentials of every request and
+// writes http.StatusUnauthorized if they're incorrect. onForbidden is called
+// (when not nil) for every rejected request.
+func BasicAuthMiddleware(username, password string, onForbidden func(r *http.Request)) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			u, p, ok := r.BasicAuth()
+			validUsername := subtle.ConstantTimeCompare([]byte(u), []byte(username)) == 1
+			validPassword := subtle.ConstantTimeCompare([]byte(p), []byte(password)) == 1
+			if !ok || !validUsername || !validPassword {
+				if onForbidden != nil {
+					onForbidden(r)
+				}
+				w.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// BodyLimitMiddleware writes http.StatusRequestEntityTooLarge when a request
+// body is larger than maxBytes. Bodies are read before calling next, so
+// handlers never read more than maxBytes, even when Content-Length is not
+// sent.
+func BodyLimitMiddleware(maxBytes int64) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > maxBytes {
+				Write(w, protocols.RequestTooLargeError)
+				return
+			}
+
+			body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+			r.Body.Close()
+			if err != nil {
+				Write(w, protocols.NewInvalidParameterError("", "", "Error reading request body."))
+				return
+			}
+
+			if int64(len(body)) > maxBytes {
+				Write(w, protocols.RequestTooLargeError)
+				return
+			}
+
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// JSONFormMiddleware parses `application/json` bodies of POST requests to
+// r.PostForm and r.Form, so handlers reading form values accept JSON requests.
+// Nested objects and arrays are flattened to names used by forms, ex.
+// `path[0][asset_code]` or `forward_destination[fields][swift]`. The body is
+// not consumed: handlers can still decode it.
+func JSONFormMiddleware() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if r.Method != "POST" || mediaType != "application/json" || r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				Write(w, protocols.NewInvalidParameterError("", "", "Error reading request body."))
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			form := url.Values{}
+			if len(bytes.TrimSpace(body)) > 0 {
+				var object map[string]interface{}
+				decoder := json.NewDecoder(bytes.NewReader(body))
+				decoder.UseNumber()
+				if decoder.Decode(&object) != nil {
+					Write(w, protocols.NewInvalidParameterError("", "", "Request body is not a valid JSON"))
 					return
 				}
+				flattenJSON("", object, form)
+			}
+
+			r.PostForm = form
+			r.Form = url.Values{}
+			for key, values := range form {
+				r.Form[key] = append(r.Form[key], values...)
+			}
+			for key, values := range r.URL.Query() {
+				r.Form[key] = append(r.Form[key], values...)
 			}
 			next.ServeHTTP(w, r)
 		}
 		return http.HandlerFunc(fn)
 	}
 }
+
+// flattenJSON adds values of a decoded JSON value to form. null values are
+// skipped like missing form fields.
+func flattenJSON(name string, value interface{}, form url.Values) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, element := range value {
+			if name != "" {
+				key = name + "[" + key + "]"
+			}
+			flattenJSON(key, element, form)
+		}
+	case []interface{}:
+		for i, element := range value {
+			flattenJSON(name+"["+strconv.Itoa(i)+"]", element, form)
+		}
+	case string:
+		form.Add(name, value)
+	case json.Number:
+		form.Add(name, value.String())
+	case bool:
+		form.Add(name, strconv.FormatBool(value))
+	}
+}
+
+// EnvelopeMiddleware wraps responses written using Write and WriteJSON in
+// Envelope when enabled returns true for the request. enabled is called at
+// most once per request, when the response is written. gRPC responses are
+// never wrapped.
+func EnvelopeMiddleware(enabled func(r *http.Request) bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if IsGRPC(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(&envelopeWriter{ResponseWriter: w, request: r, check: enabled}, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
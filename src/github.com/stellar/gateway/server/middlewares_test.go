@@ -0,0 +1,214 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	var body string
+	handler := BodyLimitMiddleware(10)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := ioutil.ReadAll(r.Body)
+		body = string(raw)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/", strings.NewReader("0123456789")))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "0123456789", body)
+
+	body = ""
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/", strings.NewReader("0123456789a")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "request_too_large")
+	assert.Equal(t, "", body)
+
+	// Content-Length is not known for chunked requests
+	request := httptest.NewRequest("POST", "/", ioutil.NopCloser(strings.NewReader("0123456789a")))
+	request.ContentLength = -1
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Equal(t, "", body)
+}
+
+func TestBasicAuthMiddleware(t *testing.T) {
+	var rejected int
+	handler := BasicAuthMiddleware("admin", "secret", func(r *http.Request) {
+		rejected++
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := httptest.NewRequest("GET", "/metrics", nil)
+	request.SetBasicAuth("admin", "secret")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	for _, credentials := range [][]string{nil, {"admin", "wrong"}, {"wrong", "secret"}} {
+		request = httptest.NewRequest("GET", "/metrics", nil)
+		if credentials != nil {
+			request.SetBasicAuth(credentials[0], credentials[1])
+		}
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, `Basic realm="admin"`, recorder.Header().Get("WWW-Authenticate"))
+	}
+	assert.Equal(t, 3, rejected)
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	handler := APIKeyMiddleware("key", nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, test := range []struct {
+		apiKey string
+		status int
+	}{{"key", http.StatusOK}, {"wrong", http.StatusForbidden}} {
+		request := httptest.NewRequest("POST", "/payment", strings.NewReader("apiKey="+test.apiKey))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, test.status, recorder.Code)
+
+		// gRPC requests send the key in metadata
+		request = httptest.NewRequest("POST", "/stellar.bridge.v1.Bridge/Payment", strings.NewReader("apiKey=key"))
+		request.Header.Set("Content-Type", "application/grpc")
+		request.Header.Set("Apikey", test.apiKey)
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, test.status, recorder.Code)
+
+		// DELETE requests send the key in header only
+		request = httptest.NewRequest("DELETE", "/payments/1", nil)
+		request.Header.Set("Apikey", test.apiKey)
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, test.status, recorder.Code)
+
+		request = httptest.NewRequest("DELETE", "/payments/1?apiKey="+test.apiKey, nil)
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+
+		// Streams send the key in query
+		request = httptest.NewRequest("GET", "/stream/payments?apiKey="+test.apiKey, nil)
+		request.Header.Set("Accept", "text/event-stream")
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, test.status, recorder.Code)
+	}
+}
+
+func TestJSONFormMiddleware(t *testing.T) {
+	var form url.Values
+	var body string
+	handler := JSONFormMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "10", r.PostFormValue("amount"))
+		form = r.PostForm
+		raw, _ := ioutil.ReadAll(r.Body)
+		body = string(raw)
+	}))
+
+	json := `{"amount": 10, "dry_run": true, "memo": null, "path": [{"asset_code": "USD", "asset_issuer": "GA"}],
+		"forward_destination": {"domain": "stellar.org", "fields": {"swift": "BOPBPHMM"}}}`
+	request := httptest.NewRequest("POST", "/payment?apiKey=key", strings.NewReader(json))
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, url.Values{
+		"amount":                             {"10"},
+		"dry_run":                            {"true"},
+		"path[0][asset_code]":                {"USD"},
+		"path[0][asset_issuer]":              {"GA"},
+		"forward_destination[domain]":        {"stellar.org"},
+		"forward_destination[fields][swift]": {"BOPBPHMM"},
+	}, form)
+	assert.Equal(t, json, body, "body is not consumed")
+	assert.Equal(t, "key", request.FormValue("apiKey"))
+
+	form = nil
+	request = httptest.NewRequest("POST", "/payment", strings.NewReader(`{"amount": `))
+	request.Header.Set("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "invalid_parameter")
+	assert.Nil(t, form)
+
+	// Form requests are not changed
+	request = httptest.NewRequest("POST", "/payment", strings.NewReader("amount=10"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, url.Values{"amount": {"10"}}, form)
+}
+
+func TestEnvelopeMiddleware(t *testing.T) {
+	var response Response
+	handler := EnvelopeMiddleware(func(r *http.Request) bool {
+		return r.Header.Get("X-Envelope") == "true"
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if response == nil {
+			WriteJSON(w, map[string]string{"status": "ok"})
+			return
+		}
+		Write(w, response)
+	}))
+
+	for _, test := range []struct {
+		response Response
+		envelope bool
+		status   int
+		body     string
+	}{
+		{nil, false, http.StatusOK, `{"status":"ok"}`},
+		{nil, true, http.StatusOK, `{"result":{"status":"ok"},"error":null}`},
+		{protocols.NewInvalidParameterError("amount", "-1", "Amount must be positive."), false, http.StatusBadRequest,
+			`{"code":"invalid_parameter","message":"Invalid parameter.","more_info":"Amount must be positive.","data":{"name":"amount"}}`},
+		{protocols.NewInvalidParameterError("amount", "-1", "Amount must be positive."), true, http.StatusBadRequest,
+			`{"result":null,"error":{"code":"invalid_parameter","message":"Invalid parameter.","extras":{"more_info":"Amount must be positive.","name":"amount"}}}`},
+		{protocols.InternalServerError, true, http.StatusInternalServerError,
+			`{"result":null,"error":{"code":"internal_server_error","message":"Internal Server Error, please try again."}}`},
+	} {
+		response = test.response
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("X-Envelope", strconv.FormatBool(test.envelope))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, test.status, recorder.Code)
+		assert.JSONEq(t, test.body, recorder.Body.String())
+	}
+
+	// Writers of other middlewares are unwrapped
+	assert.True(t, Enveloped(&unwrapper{&envelopeWriter{check: func(r *http.Request) bool { return true }}}))
+	assert.False(t, Enveloped(httptest.NewRecorder()))
+
+	// Invalid API keys are returned as error responses
+	handler = EnvelopeMiddleware(func(r *http.Request) bool { return true })(
+		APIKeyMiddleware("key", nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})),
+	)
+	request := httptest.NewRequest("POST", "/payment", strings.NewReader("apiKey=wrong"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.JSONEq(t, `{"result":null,"error":{"code":"forbidden","message":"Invalid API key."}}`, recorder.Body.String())
+}
+
+type unwrapper struct {
+	http.ResponseWriter
+}
+
+func (u *unwrapper) Unwrap() http.ResponseWriter {
+	return u.ResponseWriter
+}
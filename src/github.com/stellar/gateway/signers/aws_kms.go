@@ -0,0 +1,140 @@
+package signers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/go/support/errors"
+)
+
+// AWSKMSBackend signs using AWS KMS Sign API. The key must be of
+// ECC_NIST_EDWARDS25519 spec. Requests are signed using AWS Signature
+// Version 4.
+type AWSKMSBackend struct {
+	Client   HTTP
+	KeyID    string
+	Region   string
+	Endpoint string
+	Signer   *v4.Signer
+	now      func() time.Time
+}
+
+// NewAWSKMSBackend creates a new AWSKMSBackend. Credentials are loaded from
+// default AWS credentials chain (env variables, shared credentials file,
+// EC2/ECS roles).
+func NewAWSKMSBackend(config Config, client HTTP) (*AWSKMSBackend, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot create AWS session")
+	}
+	return NewAWSKMSBackendWithCredentials(config, sess.Config.Credentials, client), nil
+}
+
+// NewAWSKMSBackendWithCredentials creates a new AWSKMSBackend using given
+// credentials
+func NewAWSKMSBackendWithCredentials(config Config, creds *credentials.Credentials, client HTTP) *AWSKMSBackend {
+	region := config.Region
+	if region == "" {
+		region = awsRegion(config.Key)
+	}
+
+	endpoint := config.URL
+	if endpoint == "" {
+		endpoint = "https://kms." + region + ".amazonaws.com/"
+	}
+
+	return &AWSKMSBackend{
+		Client:   client,
+		KeyID:    config.Key,
+		Region:   region,
+		Endpoint: endpoint,
+		Signer:   v4.NewSigner(creds),
+		now:      clock.Now,
+	}
+}
+
+type awsKMSSignRequest struct {
+	KeyID            string `json:"KeyId"`
+	Message          []byte `json:"Message"`
+	MessageType      string `json:"MessageType"`
+	SigningAlgorithm string `json:"SigningAlgorithm"`
+}
+
+type awsKMSSignResponse struct {
+	Signature []byte `json:"Signature"`
+}
+
+type awsKMSErrorResponse struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Sign signs message with the KMS key
+func (b *AWSKMSBackend) Sign(message []byte) ([]byte, error) {
+	payload, err := json.Marshal(awsKMSSignRequest{
+		KeyID:            b.KeyID,
+		Message:          message,
+		MessageType:      "RAW",
+		SigningAlgorithm: "ED25519_SHA_512",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot marshal KMS request")
+	}
+
+	body := bytes.NewReader(payload)
+	req, err := http.NewRequest("POST", b.Endpoint, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "configure http request failed")
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Sign")
+
+	_, err = b.Signer.Sign(req, body, "kms", b.Region, b.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot sign KMS request")
+	}
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending request to KMS")
+	}
+
+	defer resp.Body.Close()
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading KMS response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse awsKMSErrorResponse
+		if json.Unmarshal(responseBody, &errorResponse) == nil && errorResponse.Type != "" {
+			return nil, errors.Errorf("KMS error: %s %s", errorResponse.Type, errorResponse.Message)
+		}
+		return nil, errors.Errorf("Error response from KMS: %d", resp.StatusCode)
+	}
+
+	var signResponse awsKMSSignResponse
+	err = json.Unmarshal(responseBody, &signResponse)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot unmarshal KMS response")
+	}
+	return signResponse.Signature, nil
+}
+
+// awsRegion returns region from KMS key ARN, ex.
+// arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab
+func awsRegion(keyARN string) string {
+	parts := strings.Split(keyARN, ":")
+	if len(parts) < 6 || parts[0] != "arn" || parts[2] != "kms" {
+		return ""
+	}
+	return parts[3]
+}
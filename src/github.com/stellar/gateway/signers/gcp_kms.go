@@ -0,0 +1,136 @@
+package signers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	gcpKMSEndpoint = "https://cloudkms.googleapis.com"
+	// Access tokens of a default service account are served by metadata server
+	// on GCE, GKE and Cloud Run.
+	metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCPKMSBackend signs using Google Cloud KMS asymmetricSign REST API. The key
+// must be of EC_SIGN_ED25519 algorithm. When AccessToken is empty, token is
+// obtained from GCE metadata server.
+type GCPKMSBackend struct {
+	Client HTTP
+	// KeyVersion is the resource name of the key version, ex.
+	// projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1
+	KeyVersion  string
+	Endpoint    string
+	AccessToken string
+
+	tokenMutex  sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	now         func() time.Time
+}
+
+// NewGCPKMSBackend creates a new GCPKMSBackend
+func NewGCPKMSBackend(config Config, client HTTP) *GCPKMSBackend {
+	endpoint := config.URL
+	if endpoint == "" {
+		endpoint = gcpKMSEndpoint
+	}
+
+	return &GCPKMSBackend{
+		Client:      client,
+		KeyVersion:  config.Key,
+		Endpoint:    strings.TrimRight(endpoint, "/"),
+		AccessToken: config.Token,
+		now:         clock.Now,
+	}
+}
+
+// Sign signs message with the KMS key version
+func (b *GCPKMSBackend) Sign(message []byte) ([]byte, error) {
+	payload, err := json.Marshal(map[string][]byte{"data": message})
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot marshal KMS request")
+	}
+
+	url := b.Endpoint + "/v1/" + b.KeyVersion + ":asymmetricSign"
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "configure http request failed")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := b.accessToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot obtain KMS access token")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending request to KMS")
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Error response from KMS: %d", resp.StatusCode)
+	}
+
+	var signResponse struct {
+		Signature []byte `json:"signature"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&signResponse)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot unmarshal KMS response")
+	}
+	return signResponse.Signature, nil
+}
+
+func (b *GCPKMSBackend) accessToken() (string, error) {
+	if b.AccessToken != "" {
+		return b.AccessToken, nil
+	}
+
+	b.tokenMutex.Lock()
+	defer b.tokenMutex.Unlock()
+
+	if b.token != "" && b.now().Before(b.tokenExpiry) {
+		return b.token, nil
+	}
+
+	req, err := http.NewRequest("GET", metadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "Error sending request to metadata server")
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("Error response from metadata server: %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", errors.Wrap(err, "Cannot unmarshal metadata server response")
+	}
+
+	b.token = token.AccessToken
+	// Refresh a minute before token expires
+	b.tokenExpiry = b.now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return b.token, nil
+}
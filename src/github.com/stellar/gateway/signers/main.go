@@ -0,0 +1,225 @@
+// Package signers contains signing backends of account keys, so secret seeds
+// of the base, authorizing and compliance signing accounts can be kept off the
+// server host: PKCS#11 HSMs, AWS KMS, Google Cloud KMS and HashiCorp Vault
+// transit engine. Keys must be Ed25519 keys, the public key of a key is the
+// account ID.
+//
+// A backend is selected per account, see Config. Every signature returned by
+// a backend is verified with the account public key before it is used.
+package signers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// Backends
+const (
+	// BackendSeed keeps the secret seed in memory, like accounts configured
+	// by seed
+	BackendSeed = "seed"
+	// BackendPKCS11 signs using an HSM through a PKCS#11 module. The server
+	// must be built with `pkcs11` build tag.
+	BackendPKCS11 = "pkcs11"
+	// BackendAWSKMS signs using AWS KMS key of ECC_NIST_EDWARDS25519 spec
+	BackendAWSKMS = "aws_kms"
+	// BackendGCPKMS signs using Google Cloud KMS key of EC_SIGN_ED25519
+	// algorithm
+	BackendGCPKMS = "gcp_kms"
+	// BackendVault signs using ed25519 key of HashiCorp Vault transit engine
+	BackendVault = "vault"
+)
+
+// Backend signs messages with the key of a single account
+type Backend interface {
+	// Sign returns an Ed25519 signature of message
+	Sign(message []byte) ([]byte, error)
+}
+
+// HTTP represents an http client that backends use to make HTTP requests
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// Config contains values of `signers` config group: the signing backend of
+// a single account
+type Config struct {
+	// Account is the account ID the key belongs to
+	Account string
+	// Backend is one of: seed, pkcs11, aws_kms, gcp_kms, vault
+	Backend string
+	// Seed is the secret seed of seed backend
+	Seed string `secret:""`
+	// Key identifies the key: key ID or ARN (aws_kms), key version resource
+	// name (gcp_kms), key name (vault) or key label (pkcs11)
+	Key string
+	// Region of AWS KMS, taken from the key ARN when empty
+	Region string
+	// URL is Vault server URL or API endpoint of AWS or Google Cloud KMS
+	// (default endpoints are used when empty)
+	URL string
+	// Token is Vault token (VAULT_TOKEN env variable when empty) or Google
+	// Cloud access token (GCE metadata server is used when empty)
+	Token string `secret:""`
+	// Mount is the path of Vault transit engine (default "transit")
+	Mount string
+	// Module is the path of PKCS#11 module library
+	Module string
+	// Slot is the PKCS#11 slot ID of the token
+	Slot uint
+	// PIN of the PKCS#11 token user (PKCS11_PIN env variable when empty)
+	PIN string `secret:""`
+}
+
+// Validate checks if the config is correct. name is the name of the config
+// group used in errors.
+func (c Config) Validate(name string) error {
+	if _, err := keypair.Parse(c.Account); err != nil || strings.HasPrefix(c.Account, "S") {
+		return fmt.Errorf("Invalid %s.account param: %s", name, c.Account)
+	}
+
+	switch c.Backend {
+	case BackendSeed:
+		kp, err := keypair.Parse(c.Seed)
+		if err != nil || kp.Address() != c.Account {
+			return fmt.Errorf("%s.seed of %s is invalid or does not match the account", name, c.Account)
+		}
+		return nil
+	case BackendPKCS11:
+		if c.Module == "" {
+			return fmt.Errorf("%s.module param of %s is required", name, c.Account)
+		}
+	case BackendAWSKMS:
+		if c.Region == "" && awsRegion(c.Key) == "" {
+			return fmt.Errorf("%s.region param of %s is required when key is not an ARN", name, c.Account)
+		}
+	case BackendGCPKMS:
+	case BackendVault:
+		if c.URL == "" {
+			return fmt.Errorf("%s.url param of %s is required", name, c.Account)
+		}
+	default:
+		return fmt.Errorf("Invalid %s.backend param: %s", name, c.Backend)
+	}
+
+	if c.Key == "" {
+		return fmt.Errorf("%s.key param of %s is required", name, c.Account)
+	}
+	return nil
+}
+
+// Keyring contains backends of accounts. Methods of a nil *Keyring behave
+// like an empty Keyring.
+type Keyring struct {
+	keys map[string]key
+}
+
+type key struct {
+	backendName string
+	backend     Backend
+	kp          keypair.KP
+}
+
+// NewKeyring creates an empty Keyring
+func NewKeyring() *Keyring {
+	return &Keyring{keys: map[string]key{}}
+}
+
+// Load creates a Keyring of backends configured in configs. Values are
+// checked in Validate.
+func Load(configs []Config, client HTTP) (*Keyring, error) {
+	keyring := NewKeyring()
+	for _, config := range configs {
+		backend, err := newBackend(config, client)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error creating "+config.Backend+" signer of "+config.Account)
+		}
+		err = keyring.Add(config.Account, config.Backend, backend)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keyring, nil
+}
+
+func newBackend(config Config, client HTTP) (Backend, error) {
+	switch config.Backend {
+	case BackendSeed:
+		return NewSeedBackend(config.Seed)
+	case BackendPKCS11:
+		return newPKCS11Backend(config)
+	case BackendAWSKMS:
+		return NewAWSKMSBackend(config, client)
+	case BackendGCPKMS:
+		return NewGCPKMSBackend(config, client), nil
+	case BackendVault:
+		return NewVaultBackend(config, client), nil
+	}
+	return nil, errors.New("Unknown backend " + config.Backend)
+}
+
+// Add adds the backend named backendName of accountID
+func (k *Keyring) Add(accountID, backendName string, backend Backend) error {
+	kp, err := keypair.Parse(accountID)
+	if err != nil {
+		return err
+	}
+	k.keys[kp.Address()] = key{backendName: backendName, backend: backend, kp: kp}
+	return nil
+}
+
+// Has returns true when the key of accountID is in the Keyring
+func (k *Keyring) Has(accountID string) bool {
+	if k == nil {
+		return false
+	}
+	_, ok := k.keys[accountID]
+	return ok
+}
+
+// Accounts returns sorted account IDs of keys in the Keyring
+func (k *Keyring) Accounts() []string {
+	if k == nil {
+		return nil
+	}
+	accounts := make([]string, 0, len(k.keys))
+	for account := range k.keys {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	return accounts
+}
+
+// BackendName returns the name of the backend of accountID or an empty
+// string when the account is not in the Keyring
+func (k *Keyring) BackendName(accountID string) string {
+	if k == nil {
+		return ""
+	}
+	return k.keys[accountID].backendName
+}
+
+// Sign signs message with the key of accountID. The signature is verified
+// with the account public key.
+func (k *Keyring) Sign(accountID string, message []byte) ([]byte, error) {
+	if !k.Has(accountID) {
+		return nil, errors.New("No signer of account " + accountID)
+	}
+
+	key := k.keys[accountID]
+	signature, err := key.backend.Sign(message)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error signing with "+key.backendName+" signer")
+	}
+
+	err = key.kp.Verify(message, signature)
+	if err != nil {
+		return nil, errors.New("Invalid signature returned by " + key.backendName + " signer of " + accountID)
+	}
+	return signature, nil
+}
@@ -0,0 +1,163 @@
+package signers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	seed    = "SDWLS4G3XCNIYPKXJWWGGJT6UDY63WV6PEFTWP7JZMQB4RE7EUJQN5XM"
+	message = "transaction hash"
+)
+
+func fullKeypair(t *testing.T) *keypair.Full {
+	kp, err := keypair.Parse(seed)
+	require.NoError(t, err)
+	return kp.(*keypair.Full)
+}
+
+func sign(t *testing.T, message []byte) []byte {
+	signature, err := fullKeypair(t).Sign(message)
+	require.NoError(t, err)
+	return signature
+}
+
+func TestKeyring(t *testing.T) {
+	kp := fullKeypair(t)
+	keyring, err := Load([]Config{{Account: kp.Address(), Backend: BackendSeed, Seed: seed}}, http.DefaultClient)
+	require.NoError(t, err)
+
+	assert.True(t, keyring.Has(kp.Address()))
+	assert.Equal(t, []string{kp.Address()}, keyring.Accounts())
+	assert.Equal(t, BackendSeed, keyring.BackendName(kp.Address()))
+
+	signature, err := keyring.Sign(kp.Address(), []byte(message))
+	require.NoError(t, err)
+	assert.NoError(t, kp.Verify([]byte(message), signature))
+
+	_, err = keyring.Sign("GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5", []byte(message))
+	assert.EqualError(t, err, "No signer of account GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5")
+
+	// Signatures of other keys are rejected
+	random, err := keypair.Random()
+	require.NoError(t, err)
+	other, err := NewSeedBackend(random.Seed())
+	require.NoError(t, err)
+	require.NoError(t, keyring.Add(kp.Address(), "vault", other))
+	_, err = keyring.Sign(kp.Address(), []byte(message))
+	assert.EqualError(t, err, "Invalid signature returned by vault signer of "+kp.Address())
+
+	var empty *Keyring
+	assert.False(t, empty.Has(kp.Address()))
+	assert.Empty(t, empty.Accounts())
+
+	_, err = Load([]Config{{Account: kp.Address(), Backend: BackendPKCS11, Module: "/usr/lib/softhsm/libsofthsm2.so", Key: "base"}}, http.DefaultClient)
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	account := fullKeypair(t).Address()
+	assert.NoError(t, Config{Account: account, Backend: BackendSeed, Seed: seed}.Validate("signers"))
+	assert.NoError(t, Config{Account: account, Backend: BackendVault, URL: "https://vault:8200", Key: "base"}.Validate("signers"))
+	assert.NoError(t, Config{Account: account, Backend: BackendAWSKMS, Key: "arn:aws:kms:eu-west-1:123456789012:key/base"}.Validate("signers"))
+
+	assert.EqualError(t, Config{Account: seed, Backend: BackendSeed, Seed: seed}.Validate("signers"), "Invalid signers.account param: "+seed)
+	assert.EqualError(t, Config{Account: account, Backend: "file"}.Validate("signers"), "Invalid signers.backend param: file")
+	assert.EqualError(t, Config{Account: "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5", Backend: BackendSeed, Seed: seed}.Validate("signers"),
+		"signers.seed of GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5 is invalid or does not match the account")
+	assert.EqualError(t, Config{Account: account, Backend: BackendAWSKMS, Key: "alias/base"}.Validate("signers"),
+		"signers.region param of "+account+" is required when key is not an ARN")
+	assert.EqualError(t, Config{Account: account, Backend: BackendGCPKMS}.Validate("signers"), "signers.key param of "+account+" is required")
+}
+
+func TestVaultBackend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/stellar-transit/sign/base", r.URL.Path)
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+
+		var request struct {
+			Input string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		input, err := base64.StdEncoding.DecodeString(request.Input)
+		require.NoError(t, err)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]string{"signature": "vault:v1:" + base64.StdEncoding.EncodeToString(sign(t, input))},
+		})
+	}))
+	defer server.Close()
+
+	backend := NewVaultBackend(Config{URL: server.URL + "/", Mount: "/stellar-transit/", Key: "base", Token: "vault-token"}, http.DefaultClient)
+	signature, err := backend.Sign([]byte(message))
+	require.NoError(t, err)
+	assert.Equal(t, sign(t, []byte(message)), signature)
+
+	backend.Key = "missing"
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": ["signing key not found"]}`))
+	})
+	_, err = backend.Sign([]byte(message))
+	assert.EqualError(t, err, "Vault error: signing key not found")
+}
+
+func TestGCPKMSBackend(t *testing.T) {
+	const keyVersion = "projects/p/locations/global/keyRings/r/cryptoKeys/base/cryptoKeyVersions/1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/"+keyVersion+":asymmetricSign", r.URL.Path)
+		assert.Equal(t, "Bearer gcp-token", r.Header.Get("Authorization"))
+
+		var request struct {
+			Data []byte `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		json.NewEncoder(w).Encode(map[string][]byte{"signature": sign(t, request.Data)})
+	}))
+	defer server.Close()
+
+	backend := NewGCPKMSBackend(Config{URL: server.URL, Key: keyVersion, Token: "gcp-token"}, http.DefaultClient)
+	signature, err := backend.Sign([]byte(message))
+	require.NoError(t, err)
+	assert.Equal(t, sign(t, []byte(message)), signature)
+}
+
+func TestAWSKMSBackend(t *testing.T) {
+	const keyARN = "arn:aws:kms:eu-west-1:123456789012:key/base"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "TrentService.Sign", r.Header.Get("X-Amz-Target"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/kms/aws4_request")
+
+		var request awsKMSSignRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, keyARN, request.KeyID)
+		assert.Equal(t, "ED25519_SHA_512", request.SigningAlgorithm)
+
+		if string(request.Message) != message {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "ValidationException", "message": "invalid message"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(awsKMSSignResponse{Signature: sign(t, request.Message)})
+	}))
+	defer server.Close()
+
+	creds := credentials.NewStaticCredentials("AKID", "SECRET", "")
+	backend := NewAWSKMSBackendWithCredentials(Config{URL: server.URL, Key: keyARN}, creds, http.DefaultClient)
+	assert.Equal(t, "eu-west-1", backend.Region)
+
+	signature, err := backend.Sign([]byte(message))
+	require.NoError(t, err)
+	assert.Equal(t, sign(t, []byte(message)), signature)
+
+	_, err = backend.Sign([]byte("other"))
+	assert.EqualError(t, err, "KMS error: ValidationException invalid message")
+}
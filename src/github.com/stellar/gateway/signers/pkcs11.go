@@ -0,0 +1,181 @@
+//go:build pkcs11
+// +build pkcs11
+
+package signers
+
+/*
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdlib.h>
+
+typedef unsigned long ck_ulong;
+typedef ck_ulong ck_rv;
+
+typedef struct {
+	ck_ulong type;
+	void *value;
+	ck_ulong value_len;
+} ck_attribute;
+
+typedef struct {
+	ck_ulong mechanism;
+	void *parameter;
+	ck_ulong parameter_len;
+} ck_mechanism;
+
+// CK_FUNCTION_LIST, functions are called by their index
+typedef struct {
+	unsigned char major;
+	unsigned char minor;
+	void *functions[68];
+} ck_function_list;
+
+#define CKR_OK 0x0
+#define CKR_USER_ALREADY_LOGGED_IN 0x100
+#define CKR_CRYPTOKI_ALREADY_INITIALIZED 0x191
+#define CKF_SERIAL_SESSION 0x4
+#define CKU_USER 1
+#define CKA_CLASS 0x0
+#define CKA_LABEL 0x3
+#define CKO_PRIVATE_KEY 0x3
+#define CKM_EDDSA 0x1057
+
+typedef ck_rv (*get_function_list_fn)(ck_function_list **);
+typedef ck_rv (*initialize_fn)(void *);
+typedef ck_rv (*open_session_fn)(ck_ulong, ck_ulong, void *, void *, ck_ulong *);
+typedef ck_rv (*login_fn)(ck_ulong, ck_ulong, unsigned char *, ck_ulong);
+typedef ck_rv (*find_objects_init_fn)(ck_ulong, ck_attribute *, ck_ulong);
+typedef ck_rv (*find_objects_fn)(ck_ulong, ck_ulong *, ck_ulong, ck_ulong *);
+typedef ck_rv (*find_objects_final_fn)(ck_ulong);
+typedef ck_rv (*sign_init_fn)(ck_ulong, ck_mechanism *, ck_ulong);
+typedef ck_rv (*sign_fn)(ck_ulong, unsigned char *, ck_ulong, unsigned char *, ck_ulong *);
+
+static ck_rv pkcs11_load(const char *path, ck_function_list **list) {
+	void *module = dlopen(path, RTLD_NOW);
+	if (module == NULL) {
+		return (ck_rv)-1;
+	}
+	get_function_list_fn get = (get_function_list_fn)dlsym(module, "C_GetFunctionList");
+	if (get == NULL) {
+		return (ck_rv)-1;
+	}
+	ck_rv rv = get(list);
+	if (rv != CKR_OK) {
+		return rv;
+	}
+	rv = ((initialize_fn)(*list)->functions[0])(NULL);
+	if (rv == CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		return CKR_OK;
+	}
+	return rv;
+}
+
+static ck_rv pkcs11_open_session(ck_function_list *list, ck_ulong slot, unsigned char *pin, ck_ulong pin_len, ck_ulong *session) {
+	ck_rv rv = ((open_session_fn)list->functions[12])(slot, CKF_SERIAL_SESSION, NULL, NULL, session);
+	if (rv != CKR_OK || pin_len == 0) {
+		return rv;
+	}
+	rv = ((login_fn)list->functions[18])(*session, CKU_USER, pin, pin_len);
+	if (rv == CKR_USER_ALREADY_LOGGED_IN) {
+		return CKR_OK;
+	}
+	return rv;
+}
+
+static ck_rv pkcs11_find_key(ck_function_list *list, ck_ulong session, unsigned char *label, ck_ulong label_len, ck_ulong *key, ck_ulong *count) {
+	ck_ulong class = CKO_PRIVATE_KEY;
+	ck_attribute template[2] = {
+		{CKA_CLASS, &class, sizeof(class)},
+		{CKA_LABEL, label, label_len},
+	};
+	ck_rv rv = ((find_objects_init_fn)list->functions[26])(session, template, 2);
+	if (rv != CKR_OK) {
+		return rv;
+	}
+	rv = ((find_objects_fn)list->functions[27])(session, key, 1, count);
+	((find_objects_final_fn)list->functions[28])(session);
+	return rv;
+}
+
+static ck_rv pkcs11_sign(ck_function_list *list, ck_ulong session, ck_ulong key, unsigned char *data, ck_ulong data_len, unsigned char *signature, ck_ulong *signature_len) {
+	ck_mechanism mechanism = {CKM_EDDSA, NULL, 0};
+	ck_rv rv = ((sign_init_fn)list->functions[42])(session, &mechanism, key);
+	if (rv != CKR_OK) {
+		return rv;
+	}
+	return ((sign_fn)list->functions[43])(session, data, data_len, signature, signature_len);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"unsafe"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// pkcs11Backend signs using a CKM_EDDSA private key of a PKCS#11 token. The
+// key is found by its label. A session is opened once and used for all
+// signatures of the key.
+type pkcs11Backend struct {
+	mutex   sync.Mutex
+	list    *C.ck_function_list
+	session C.ck_ulong
+	key     C.ck_ulong
+}
+
+func newPKCS11Backend(config Config) (Backend, error) {
+	pin := config.PIN
+	if pin == "" {
+		pin = os.Getenv("PKCS11_PIN")
+	}
+
+	module := C.CString(config.Module)
+	defer C.free(unsafe.Pointer(module))
+
+	b := &pkcs11Backend{}
+	if rv := C.pkcs11_load(module, &b.list); rv != C.CKR_OK {
+		return nil, fmt.Errorf("Cannot load PKCS#11 module %s: 0x%x", config.Module, uint64(rv))
+	}
+
+	cPIN := (*C.uchar)(C.CBytes([]byte(pin)))
+	defer C.free(unsafe.Pointer(cPIN))
+	if rv := C.pkcs11_open_session(b.list, C.ck_ulong(config.Slot), cPIN, C.ck_ulong(len(pin)), &b.session); rv != C.CKR_OK {
+		return nil, fmt.Errorf("Cannot open PKCS#11 session: 0x%x", uint64(rv))
+	}
+
+	label := (*C.uchar)(C.CBytes([]byte(config.Key)))
+	defer C.free(unsafe.Pointer(label))
+	var count C.ck_ulong
+	if rv := C.pkcs11_find_key(b.list, b.session, label, C.ck_ulong(len(config.Key)), &b.key, &count); rv != C.CKR_OK {
+		return nil, fmt.Errorf("Cannot find PKCS#11 key: 0x%x", uint64(rv))
+	}
+	if count == 0 {
+		return nil, errors.New("PKCS#11 key " + config.Key + " not found")
+	}
+
+	return b, nil
+}
+
+// Sign signs message with the token key
+func (b *pkcs11Backend) Sign(message []byte) ([]byte, error) {
+	// Operations of a single session cannot run concurrently
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	data := (*C.uchar)(C.CBytes(message))
+	defer C.free(unsafe.Pointer(data))
+
+	signature := (*C.uchar)(C.malloc(64))
+	defer C.free(unsafe.Pointer(signature))
+	signatureLen := C.ck_ulong(64)
+
+	rv := C.pkcs11_sign(b.list, b.session, b.key, data, C.ck_ulong(len(message)), signature, &signatureLen)
+	if rv != C.CKR_OK {
+		return nil, fmt.Errorf("PKCS#11 sign error: 0x%x", uint64(rv))
+	}
+	return C.GoBytes(unsafe.Pointer(signature), C.int(signatureLen)), nil
+}
@@ -0,0 +1,12 @@
+//go:build !pkcs11
+// +build !pkcs11
+
+package signers
+
+import (
+	"github.com/stellar/go/support/errors"
+)
+
+func newPKCS11Backend(config Config) (Backend, error) {
+	return nil, errors.New("Server built without PKCS#11 support (build with -tags pkcs11)")
+}
@@ -0,0 +1,30 @@
+package signers
+
+import (
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// SeedBackend signs using a secret seed kept in memory
+type SeedBackend struct {
+	kp *keypair.Full
+}
+
+// NewSeedBackend creates a new SeedBackend of seed
+func NewSeedBackend(seed string) (*SeedBackend, error) {
+	kp, err := keypair.Parse(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	full, ok := kp.(*keypair.Full)
+	if !ok {
+		return nil, errors.New("Seed is a public key")
+	}
+	return &SeedBackend{kp: full}, nil
+}
+
+// Sign signs message with the seed
+func (b *SeedBackend) Sign(message []byte) ([]byte, error) {
+	return b.kp.Sign(message)
+}
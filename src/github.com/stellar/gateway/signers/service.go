@@ -0,0 +1,130 @@
+package signers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/protocols/signer"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+// DefaultMaxClockSkew is the default max difference between a sign request
+// timestamp and the service time
+const DefaultMaxClockSkew = 30 * time.Second
+
+// Service is the reference signing service of protocols/signer. It signs
+// transactions of accounts in Keys after checking that the request is
+// authenticated with Secret, is not older than MaxClockSkew and the hash
+// matches the transaction of the account on the service network.
+// Deployments can wrap it to apply their own policies before signing.
+type Service struct {
+	Keys              *Keyring
+	Secret            string
+	NetworkPassphrase string
+	MaxClockSkew      time.Duration
+	log               *logrus.Entry
+	now               func() time.Time
+}
+
+// NewService creates a new Service
+func NewService(keys *Keyring, secret, networkPassphrase string) *Service {
+	return &Service{
+		Keys:              keys,
+		Secret:            secret,
+		NetworkPassphrase: networkPassphrase,
+		MaxClockSkew:      DefaultMaxClockSkew,
+		log:               logrus.WithFields(logrus.Fields{"service": "Signer"}),
+		now:               clock.Now,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.reject(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, protocols.MaxRequestBodySize))
+	if err != nil {
+		s.reject(w, http.StatusBadRequest, "cannot read request body")
+		return
+	}
+
+	if !signer.VerifyMAC(s.Secret, body, r.Header.Get(signer.SignatureHeader)) {
+		s.reject(w, http.StatusForbidden, "invalid request signature")
+		return
+	}
+
+	var request signer.SignRequest
+	err = json.Unmarshal(body, &request)
+	if err != nil {
+		s.reject(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	status, reason := s.check(request)
+	if reason != "" {
+		s.log.WithFields(logrus.Fields{"account_id": request.AccountID, "reason": reason}).Warn("Sign request rejected")
+		s.reject(w, status, reason)
+		return
+	}
+
+	hash, _ := hex.DecodeString(request.Hash)
+	signature, err := s.Keys.Sign(request.AccountID, hash)
+	if err != nil {
+		s.log.WithFields(logrus.Fields{"account_id": request.AccountID, "err": err}).Error("Error signing transaction")
+		s.reject(w, http.StatusInternalServerError, "cannot sign transaction")
+		return
+	}
+
+	s.log.WithFields(logrus.Fields{"account_id": request.AccountID, "hash": request.Hash}).Info("Transaction signed")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write((&signer.SignResponse{Signature: base64.StdEncoding.EncodeToString(signature)}).Marshal())
+}
+
+// check returns the status and the reason when request must be rejected
+func (s *Service) check(request signer.SignRequest) (int, string) {
+	if request.NetworkPassphrase != s.NetworkPassphrase {
+		return http.StatusBadRequest, "network passphrase mismatch"
+	}
+
+	skew := s.now().Sub(time.Unix(request.Timestamp, 0))
+	if skew > s.MaxClockSkew || -skew > s.MaxClockSkew {
+		return http.StatusForbidden, "request expired"
+	}
+
+	if !s.Keys.Has(request.AccountID) {
+		return http.StatusForbidden, "unknown account"
+	}
+
+	var tx xdr.Transaction
+	err := xdr.SafeUnmarshalBase64(request.Transaction, &tx)
+	if err != nil {
+		return http.StatusBadRequest, "invalid transaction"
+	}
+
+	if tx.SourceAccount.Address() != request.AccountID {
+		return http.StatusForbidden, "transaction source account does not match account_id"
+	}
+
+	hash, err := network.HashTransaction(&tx, s.NetworkPassphrase)
+	if err != nil || hex.EncodeToString(hash[:]) != request.Hash {
+		return http.StatusBadRequest, "hash does not match transaction"
+	}
+	return 0, ""
+}
+
+func (s *Service) reject(w http.ResponseWriter, status int, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write((&signer.SignResponse{Error: reason}).Marshal())
+}
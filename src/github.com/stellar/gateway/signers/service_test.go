@@ -0,0 +1,104 @@
+package signers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/protocols/signer"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	otherKP, err := keypair.Random()
+	require.NoError(t, err)
+
+	keys, err := Load([]Config{{Account: kp.Address(), Backend: BackendSeed, Seed: kp.Seed()}}, http.DefaultClient)
+	require.NoError(t, err)
+
+	now := time.Unix(1500000000, 0)
+	service := NewService(keys, "secret", network.TestNetworkPassphrase)
+	service.now = func() time.Time { return now }
+
+	newRequest := func(source string) signer.SignRequest {
+		tx := &xdr.Transaction{Fee: 100, SeqNum: 1, Memo: xdr.Memo{Type: xdr.MemoTypeMemoNone}}
+		require.NoError(t, tx.SourceAccount.SetAddress(source))
+		txB64, err := xdr.MarshalBase64(tx)
+		require.NoError(t, err)
+		hash, err := network.HashTransaction(tx, network.TestNetworkPassphrase)
+		require.NoError(t, err)
+		return signer.SignRequest{
+			AccountID:         source,
+			NetworkPassphrase: network.TestNetworkPassphrase,
+			Transaction:       txB64,
+			Hash:              hex.EncodeToString(hash[:]),
+			Timestamp:         now.Unix(),
+		}
+	}
+
+	send := func(request signer.SignRequest, secret string) (int, signer.SignResponse) {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		r := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+		r.Header.Set(signer.SignatureHeader, signer.MAC(secret, body))
+		recorder := httptest.NewRecorder()
+		service.ServeHTTP(recorder, r)
+
+		var response signer.SignResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		return recorder.Code, response
+	}
+
+	request := newRequest(kp.Address())
+	code, response := send(request, "secret")
+	require.Equal(t, http.StatusOK, code)
+	signature, err := base64.StdEncoding.DecodeString(response.Signature)
+	require.NoError(t, err)
+	hash, err := hex.DecodeString(request.Hash)
+	require.NoError(t, err)
+	assert.NoError(t, kp.Verify(hash, signature))
+
+	code, response = send(request, "wrong")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "invalid request signature", response.Error)
+
+	expired := request
+	expired.Timestamp = now.Add(-time.Minute).Unix()
+	code, response = send(expired, "secret")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "request expired", response.Error)
+
+	otherNetwork := request
+	otherNetwork.NetworkPassphrase = network.PublicNetworkPassphrase
+	code, response = send(otherNetwork, "secret")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "network passphrase mismatch", response.Error)
+
+	_, response = send(newRequest(otherKP.Address()), "secret")
+	assert.Equal(t, "unknown account", response.Error)
+
+	// Hash of other transaction cannot be signed
+	tampered := request
+	tampered.Hash = newRequest(otherKP.Address()).Hash
+	code, response = send(tampered, "secret")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "hash does not match transaction", response.Error)
+
+	// Transaction of other source account cannot be signed
+	otherSource := newRequest(otherKP.Address())
+	otherSource.AccountID = kp.Address()
+	code, response = send(otherSource, "secret")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, "transaction source account does not match account_id", response.Error)
+}
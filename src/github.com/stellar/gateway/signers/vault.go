@@ -0,0 +1,95 @@
+package signers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// DefaultVaultMount is the default path of Vault transit engine
+const DefaultVaultMount = "transit"
+
+// VaultBackend signs using sign endpoint of HashiCorp Vault transit engine.
+// The key must be of ed25519 type.
+type VaultBackend struct {
+	Client HTTP
+	URL    string
+	Mount  string
+	Key    string
+	Token  string
+}
+
+// NewVaultBackend creates a new VaultBackend. Token is taken from VAULT_TOKEN
+// env variable when config.Token is empty.
+func NewVaultBackend(config Config, client HTTP) *VaultBackend {
+	mount := config.Mount
+	if mount == "" {
+		mount = DefaultVaultMount
+	}
+
+	token := config.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+
+	return &VaultBackend{
+		Client: client,
+		URL:    strings.TrimRight(config.URL, "/"),
+		Mount:  strings.Trim(mount, "/"),
+		Key:    config.Key,
+		Token:  token,
+	}
+}
+
+// Sign signs message with the transit key
+func (b *VaultBackend) Sign(message []byte) ([]byte, error) {
+	payload, err := json.Marshal(map[string]string{
+		"input": base64.StdEncoding.EncodeToString(message),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot marshal Vault request")
+	}
+
+	url := b.URL + "/v1/" + b.Mount + "/sign/" + b.Key
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "configure http request failed")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", b.Token)
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending request to Vault")
+	}
+
+	defer resp.Body.Close()
+	var signResponse struct {
+		Errors []string `json:"errors"`
+		Data   struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&signResponse)
+	if resp.StatusCode != http.StatusOK {
+		if err == nil && len(signResponse.Errors) > 0 {
+			return nil, errors.Errorf("Vault error: %s", strings.Join(signResponse.Errors, ", "))
+		}
+		return nil, errors.Errorf("Error response from Vault: %d", resp.StatusCode)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot unmarshal Vault response")
+	}
+
+	// Signatures are prefixed with the key version, ex. vault:v1:<base64>
+	parts := strings.Split(signResponse.Data.Signature, ":")
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, errors.New("Invalid signature format in Vault response")
+	}
+	return base64.StdEncoding.DecodeString(parts[2])
+}
@@ -0,0 +1,300 @@
+// Package snapshots records federation and stellar.toml responses used to
+// resolve destinations of payments, so it can be proven later what a
+// destination domain advertised at the time of a (disputed) payment.
+//
+// Recorder wraps transports of HTTP clients loading stellar.toml files and
+// querying federation servers. The latest response of every URL is kept in
+// memory together with the TLS certificates presented by the server. When a
+// transaction is sent, snapshots of responses used to resolve its destination
+// are persisted. Responses served from cache.FederationResolver are matched
+// with the snapshot recorded when they were loaded.
+package snapshots
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/go/address"
+	"github.com/stellar/go/clients/stellartoml"
+)
+
+// Kinds of snapshots
+const (
+	KindStellarToml = "stellar_toml"
+	KindFederation  = "federation"
+)
+
+// DefaultMaxEntries is the default max number of responses kept in memory
+const DefaultMaxEntries = 10000
+
+// MaxBodySize is a max size of a recorded response body in bytes, longer
+// bodies are truncated
+const MaxBodySize = 64 * 1024
+
+// Certificate describes a certificate presented by a server
+type Certificate struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	// SHA256 is a hex encoded fingerprint of the certificate
+	SHA256 string `json:"sha256"`
+	// PEM is the PEM encoded certificate
+	PEM string `json:"pem"`
+}
+
+// Snapshot is a recorded response
+type Snapshot struct {
+	Kind string
+	// URL is the requested URL
+	URL string
+	// FinalURL differs from URL when redirects were followed
+	FinalURL   string
+	StatusCode int
+	Body       string
+	Truncated  bool
+	// TLSVersion, CipherSuite and Certificates are empty for plain HTTP
+	// responses
+	TLSVersion  string
+	CipherSuite string
+	// Certificates is the chain presented by the server, leaf first
+	Certificates []Certificate
+	FetchedAt    time.Time
+}
+
+// Entity returns the FederationSnapshot entity of s used by a transaction
+func (s *Snapshot) Entity(transactionID string, paymentID *string, recordedAt time.Time) (*entities.FederationSnapshot, error) {
+	certificates, err := json.Marshal(s.Certificates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.FederationSnapshot{
+		TransactionID: transactionID,
+		PaymentID:     paymentID,
+		Kind:          s.Kind,
+		URL:           s.URL,
+		FinalURL:      s.FinalURL,
+		StatusCode:    s.StatusCode,
+		Body:          s.Body,
+		Truncated:     s.Truncated,
+		TLSVersion:    s.TLSVersion,
+		CipherSuite:   s.CipherSuite,
+		Certificates:  string(certificates),
+		FetchedAt:     s.FetchedAt,
+		RecordedAt:    recordedAt,
+	}, nil
+}
+
+// Recorder keeps the latest response of every URL requested using its
+// transports
+type Recorder struct {
+	// MaxEntries is a max number of responses kept, the least recently
+	// recorded are dropped
+	MaxEntries int
+
+	mutex     sync.Mutex
+	snapshots map[string]*list.Element
+	order     *list.List
+	log       *logrus.Entry
+	now       func() time.Time
+}
+
+// NewRecorder creates a new Recorder. DefaultMaxEntries is used when
+// maxEntries is 0.
+func NewRecorder(maxEntries int) *Recorder {
+	if maxEntries == 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &Recorder{
+		MaxEntries: maxEntries,
+		snapshots:  map[string]*list.Element{},
+		order:      list.New(),
+		log:        logrus.WithFields(logrus.Fields{"service": "Snapshots"}),
+		now:        clock.Now,
+	}
+}
+
+// Client returns a copy of client recording responses as snapshots of kind
+func (r *Recorder) Client(kind string, client *http.Client) *http.Client {
+	result := *client
+	result.Transport = r.Transport(kind, client.Transport)
+	return &result
+}
+
+// Transport returns a transport recording responses of GET requests sent
+// using next (http.DefaultTransport when nil) as snapshots of kind
+func (r *Recorder) Transport(kind string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{recorder: r, kind: kind, next: next}
+}
+
+// Destination returns snapshots of responses used to resolve destination (a
+// stellar address) or forward destination when it's not nil. Returns nil for
+// account IDs. A warning is logged when snapshots are missing because they
+// have been dropped while responses were cached.
+func (r *Recorder) Destination(destination string, forward *protocols.ForwardDestination) []*Snapshot {
+	var used []*Snapshot
+	if forward != nil {
+		query := forward.Query()
+		query.Add("type", "forward")
+		used = r.Resolution(forward.Domain, query)
+	} else {
+		_, domain, err := address.Split(destination)
+		if err != nil {
+			return nil
+		}
+		used = r.Resolution(domain, url.Values{"q": {destination}, "type": {"name"}})
+	}
+
+	if len(used) < 2 {
+		r.log.WithFields(logrus.Fields{"destination": destination}).Warn("Federation snapshots of the destination not found")
+	}
+	return used
+}
+
+// Resolution returns snapshots of stellar.toml of domain and of the response
+// of its FEDERATION_SERVER to query (ex. q and type params of a name request).
+// Snapshots that have not been recorded are skipped.
+func (r *Recorder) Resolution(domain string, query url.Values) []*Snapshot {
+	stellarToml := r.Get("https://" + domain + stellartoml.WellKnownPath)
+	if stellarToml == nil {
+		return nil
+	}
+	result := []*Snapshot{stellarToml}
+
+	var response stellartoml.Response
+	_, err := toml.Decode(stellarToml.Body, &response)
+	if err != nil || response.FederationServer == "" {
+		return result
+	}
+
+	// The same URL is built by federation.Client
+	federation := r.Get(response.FederationServer + "?" + query.Encode())
+	if federation != nil {
+		result = append(result, federation)
+	}
+	return result
+}
+
+// Get returns the latest snapshot of URL, nil when it's not recorded
+func (r *Recorder) Get(url string) *Snapshot {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	element, ok := r.snapshots[url]
+	if !ok {
+		return nil
+	}
+	return element.Value.(*Snapshot)
+}
+
+func (r *Recorder) add(snapshot *Snapshot) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if element, ok := r.snapshots[snapshot.URL]; ok {
+		r.order.Remove(element)
+	}
+	r.snapshots[snapshot.URL] = r.order.PushBack(snapshot)
+
+	for r.order.Len() > r.MaxEntries {
+		oldest := r.order.Front()
+		r.order.Remove(oldest)
+		delete(r.snapshots, oldest.Value.(*Snapshot).URL)
+	}
+}
+
+type transport struct {
+	recorder *Recorder
+	kind     string
+	next     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet {
+		return resp, err
+	}
+
+	// Redirects are followed by http.Client, only the final response is
+	// recorded under the URL of the first request
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.Header.Get("Location") != "" {
+		return resp, nil
+	}
+	first := req
+	for first.Response != nil && first.Response.Request != nil {
+		first = first.Response.Request
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, MaxBodySize+1))
+	// The body is read again by the client
+	resp.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+	if err != nil {
+		return resp, nil
+	}
+
+	snapshot := &Snapshot{
+		Kind:       t.kind,
+		URL:        first.URL.String(),
+		FinalURL:   req.URL.String(),
+		StatusCode: resp.StatusCode,
+		FetchedAt:  t.recorder.now(),
+	}
+	if len(body) > MaxBodySize {
+		body = body[:MaxBodySize]
+		snapshot.Truncated = true
+	}
+	snapshot.Body = string(body)
+
+	if resp.TLS != nil {
+		snapshot.TLSVersion = tlsVersions[resp.TLS.Version]
+		snapshot.CipherSuite = tls.CipherSuiteName(resp.TLS.CipherSuite)
+		for _, certificate := range resp.TLS.PeerCertificates {
+			fingerprint := sha256.Sum256(certificate.Raw)
+			snapshot.Certificates = append(snapshot.Certificates, Certificate{
+				Subject:      certificate.Subject.String(),
+				Issuer:       certificate.Issuer.String(),
+				SerialNumber: certificate.SerialNumber.String(),
+				NotBefore:    certificate.NotBefore,
+				NotAfter:     certificate.NotAfter,
+				SHA256:       hex.EncodeToString(fingerprint[:]),
+				PEM:          string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})),
+			})
+		}
+	}
+
+	t.recorder.add(snapshot)
+	return resp, nil
+}
+
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,158 @@
+package snapshots
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/protocols"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serverURL is the URL of test servers, the httptest certificate is valid for
+// example.com
+const serverURL = "https://example.com"
+
+// newServer returns a TLS server and a client sending all requests to it
+func newServer() (*httptest.Server, *http.Client) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/stellar.toml":
+			w.Write([]byte(`FEDERATION_SERVER="` + serverURL + `/federation"`))
+		case "/federation":
+			w.Write([]byte(`{"stellar_address": "` + r.URL.Query().Get("q") + `", "account_id": "GAB"}`))
+		case "/redirect":
+			http.Redirect(w, r, "/federation?q=redirected", http.StatusFound)
+		case "/large":
+			w.Write([]byte(strings.Repeat("a", MaxBodySize+10)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	client := server.Client()
+	transport := client.Transport.(*http.Transport)
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	return server, client
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestRecorder(t *testing.T) {
+	server, httpClient := newServer()
+	defer server.Close()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	recorder := NewRecorder(0)
+	recorder.now = func() time.Time { return now }
+	client := recorder.Client(KindFederation, httpClient)
+
+	t.Run("TLS details", func(t *testing.T) {
+		body := get(t, client, serverURL+"/federation?q=bob*example.com")
+		assert.Contains(t, body, "bob*example.com", "body is passed to the client")
+
+		snapshot := recorder.Get(serverURL + "/federation?q=bob*example.com")
+		require.NotNil(t, snapshot)
+		assert.Equal(t, KindFederation, snapshot.Kind)
+		assert.Equal(t, body, snapshot.Body)
+		assert.Equal(t, http.StatusOK, snapshot.StatusCode)
+		assert.Equal(t, now, snapshot.FetchedAt)
+		assert.NotEmpty(t, snapshot.TLSVersion)
+		assert.NotEmpty(t, snapshot.CipherSuite)
+		require.Len(t, snapshot.Certificates, 1)
+		assert.Len(t, snapshot.Certificates[0].SHA256, 64)
+		assert.Contains(t, snapshot.Certificates[0].PEM, "BEGIN CERTIFICATE")
+
+		entity, err := snapshot.Entity("tx", nil, now)
+		require.NoError(t, err)
+		assert.Equal(t, "tx", entity.TransactionID)
+		assert.Contains(t, entity.Certificates, snapshot.Certificates[0].SHA256)
+	})
+
+	t.Run("redirects", func(t *testing.T) {
+		body := get(t, client, serverURL+"/redirect")
+		assert.Contains(t, body, "redirected")
+
+		snapshot := recorder.Get(serverURL + "/redirect")
+		require.NotNil(t, snapshot)
+		assert.Equal(t, serverURL+"/federation?q=redirected", snapshot.FinalURL)
+		assert.Equal(t, body, snapshot.Body)
+	})
+
+	t.Run("truncated body", func(t *testing.T) {
+		body := get(t, client, serverURL+"/large")
+		assert.Len(t, body, MaxBodySize+10, "body is passed to the client")
+
+		snapshot := recorder.Get(serverURL + "/large")
+		require.NotNil(t, snapshot)
+		assert.True(t, snapshot.Truncated)
+		assert.Len(t, snapshot.Body, MaxBodySize)
+	})
+
+	t.Run("not recorded", func(t *testing.T) {
+		assert.Nil(t, recorder.Get(serverURL+"/unknown"))
+	})
+}
+
+func TestRecorderMaxEntries(t *testing.T) {
+	server, httpClient := newServer()
+	defer server.Close()
+
+	recorder := NewRecorder(2)
+	client := recorder.Client(KindFederation, httpClient)
+	for _, q := range []string{"a", "b", "a", "c"} {
+		get(t, client, serverURL+"/federation?q="+q)
+	}
+
+	assert.NotNil(t, recorder.Get(serverURL+"/federation?q=a"))
+	assert.Nil(t, recorder.Get(serverURL+"/federation?q=b"))
+	assert.NotNil(t, recorder.Get(serverURL+"/federation?q=c"))
+}
+
+func TestRecorderDestination(t *testing.T) {
+	server, httpClient := newServer()
+	defer server.Close()
+
+	recorder := NewRecorder(0)
+	client := recorder.Client(KindFederation, httpClient)
+	domain := "example.com"
+	address := "bob*" + domain
+	assert.Empty(t, recorder.Destination(address, nil), "stellar.toml not recorded")
+
+	get(t, recorder.Client(KindStellarToml, httpClient), serverURL+"/.well-known/stellar.toml")
+	used := recorder.Destination(address, nil)
+	require.Len(t, used, 1, "federation response not recorded")
+	assert.Equal(t, KindStellarToml, used[0].Kind)
+
+	get(t, client, serverURL+"/federation?"+url.Values{"q": {address}, "type": {"name"}}.Encode())
+	used = recorder.Destination(address, nil)
+	require.Len(t, used, 2)
+	assert.Equal(t, KindFederation, used[1].Kind)
+	assert.Contains(t, used[1].Body, address)
+
+	forward := &protocols.ForwardDestination{Domain: domain, Fields: url.Values{"forward_type": {"bank_account"}}}
+	query := forward.Query()
+	query.Add("type", "forward")
+	get(t, client, serverURL+"/federation?"+query.Encode())
+	used = recorder.Destination("", forward)
+	require.Len(t, used, 2)
+	assert.Equal(t, serverURL+"/federation?"+query.Encode(), used[1].URL)
+
+	assert.Nil(t, recorder.Destination("GAB", nil), "account IDs are not resolved")
+}
@@ -0,0 +1,227 @@
+// Package sorobanrpc reads contract events from soroban-rpc server. Horizon
+// does not serve contract events, so transfers of Stellar Asset Contracts
+// (SAC) to contract addresses are only visible in soroban-rpc.
+package sorobanrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// Client calls JSON-RPC methods of soroban-rpc server
+type Client struct {
+	// URL of soroban-rpc server, ex. https://soroban-testnet.stellar.org
+	URL  string
+	HTTP *http.Client
+
+	// requestID is an ID of the last JSON-RPC request
+	requestID int64
+}
+
+// EventFilter selects events returned by getEvents. Topics are lists of
+// base64 encoded SCVal values or "*" matching any value of a topic.
+type EventFilter struct {
+	Type        string     `json:"type,omitempty"`
+	ContractIDs []string   `json:"contractIds,omitempty"`
+	Topics      [][]string `json:"topics,omitempty"`
+}
+
+// Pagination of getEvents, Cursor cannot be sent with StartLedger
+type Pagination struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// GetEventsRequest contains params of getEvents method
+type GetEventsRequest struct {
+	StartLedger uint32        `json:"startLedger,omitempty"`
+	Filters     []EventFilter `json:"filters"`
+	Pagination  *Pagination   `json:"pagination,omitempty"`
+}
+
+// Event is a contract event returned by getEvents. Topic and Value are base64
+// encoded SCVal values.
+type Event struct {
+	Type                     string   `json:"type"`
+	Ledger                   uint32   `json:"ledger"`
+	LedgerClosedAt           string   `json:"ledgerClosedAt"`
+	ContractID               string   `json:"contractId"`
+	ID                       string   `json:"id"`
+	Topic                    []string `json:"topic"`
+	Value                    string   `json:"value"`
+	InSuccessfulContractCall bool     `json:"inSuccessfulContractCall"`
+	TransactionHash          string   `json:"txHash"`
+}
+
+// GetEventsResponse is a result of getEvents method. Cursor is empty in
+// soroban-rpc versions that return paging tokens of events only.
+type GetEventsResponse struct {
+	Events       []Event `json:"events"`
+	LatestLedger uint32  `json:"latestLedger"`
+	Cursor       string  `json:"cursor"`
+}
+
+// SimulateTransactionResult is a result of the host function of a simulated
+// transaction. Auth are base64 encoded SorobanAuthorizationEntry values the
+// invocation requires, XDR is the base64 encoded SCVal returned.
+type SimulateTransactionResult struct {
+	Auth []string `json:"auth"`
+	XDR  string   `json:"xdr"`
+}
+
+// SimulateTransactionResponse is a result of simulateTransaction method.
+// TransactionData is base64 encoded SorobanTransactionData including the
+// footprint and the resource fee. RestorePreamble is set when archived
+// ledger entries must be restored before the transaction can be sent.
+type SimulateTransactionResponse struct {
+	Error           string                      `json:"error"`
+	TransactionData string                      `json:"transactionData"`
+	MinResourceFee  string                      `json:"minResourceFee"`
+	Results         []SimulateTransactionResult `json:"results"`
+	RestorePreamble *struct {
+		TransactionData string `json:"transactionData"`
+		MinResourceFee  string `json:"minResourceFee"`
+	} `json:"restorePreamble"`
+	LatestLedger uint32 `json:"latestLedger"`
+}
+
+// Statuses of transactions returned by getTransaction
+const (
+	TransactionStatusSuccess  = "SUCCESS"
+	TransactionStatusFailed   = "FAILED"
+	TransactionStatusNotFound = "NOT_FOUND"
+)
+
+// GetTransactionResponse is a result of getTransaction method. ResultXdr is
+// the base64 encoded TransactionResult, set when the transaction has been
+// included in a ledger.
+type GetTransactionResponse struct {
+	Status    string `json:"status"`
+	Ledger    uint32 `json:"ledger"`
+	ResultXdr string `json:"resultXdr"`
+}
+
+// LedgerEntry is a ledger entry returned by getLedgerEntries. Key is the
+// base64 encoded LedgerKey, XDR is the base64 encoded LedgerEntryData.
+type LedgerEntry struct {
+	Key                   string `json:"key"`
+	XDR                   string `json:"xdr"`
+	LastModifiedLedgerSeq uint32 `json:"lastModifiedLedgerSeq"`
+}
+
+// GetLedgerEntriesResponse is a result of getLedgerEntries method. Entries
+// that don't exist are not returned.
+type GetLedgerEntriesResponse struct {
+	Entries      []LedgerEntry `json:"entries"`
+	LatestLedger uint32        `json:"latestLedger"`
+}
+
+// Error is an error returned by soroban-rpc
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("soroban-rpc error %d: %s", e.Code, e.Message)
+}
+
+// New creates a Client of soroban-rpc server at url
+func New(url string, client *http.Client) *Client {
+	return &Client{URL: url, HTTP: client}
+}
+
+// GetEvents calls getEvents method
+func (c *Client) GetEvents(ctx context.Context, request GetEventsRequest) (response GetEventsResponse, err error) {
+	err = c.call(ctx, "getEvents", request, &response)
+	return
+}
+
+// SimulateTransaction calls simulateTransaction method with a base64 encoded
+// transaction envelope. Failed simulations are returned in response.Error.
+func (c *Client) SimulateTransaction(ctx context.Context, transaction string) (response SimulateTransactionResponse, err error) {
+	params := struct {
+		Transaction string `json:"transaction"`
+	}{transaction}
+	err = c.call(ctx, "simulateTransaction", params, &response)
+	return
+}
+
+// GetTransaction calls getTransaction method with a hex encoded transaction
+// hash. Transactions of all types are returned, not only Soroban ones.
+func (c *Client) GetTransaction(ctx context.Context, hash string) (response GetTransactionResponse, err error) {
+	params := struct {
+		Hash string `json:"hash"`
+	}{hash}
+	err = c.call(ctx, "getTransaction", params, &response)
+	return
+}
+
+// GetLedgerEntries calls getLedgerEntries method with base64 encoded
+// LedgerKey values
+func (c *Client) GetLedgerEntries(ctx context.Context, keys []string) (response GetLedgerEntriesResponse, err error) {
+	params := struct {
+		Keys []string `json:"keys"`
+	}{keys}
+	err = c.call(ctx, "getLedgerEntries", params, &response)
+	return
+}
+
+// GetLatestLedger returns the sequence of the latest ledger known to
+// soroban-rpc
+func (c *Client) GetLatestLedger(ctx context.Context) (uint32, error) {
+	var response struct {
+		Sequence uint32 `json:"sequence"`
+	}
+	err := c.call(ctx, "getLatestLedger", nil, &response)
+	return response.Sequence, err
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result interface{}) error {
+	body, err := json.Marshal(struct {
+		JSONRPC string      `json:"jsonrpc"`
+		ID      int64       `json:"id"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+	}{"2.0", atomic.AddInt64(&c.requestID, 1), method, params})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTP.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "Error sending request to soroban-rpc")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("soroban-rpc responded with status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *Error          `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return errors.Wrap(err, "Cannot decode soroban-rpc response")
+	}
+
+	if response.Error != nil {
+		return response.Error
+	}
+
+	return json.Unmarshal(response.Result, result)
+}
@@ -0,0 +1,371 @@
+package sorobanrpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/crc16"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// The vendored XDR predates Soroban, so the few SCVal values of SAC events
+// are encoded and decoded here. Types and arms follow Stellar-contract.x.
+const (
+	scvU64     = 5
+	scvI128    = 10
+	scvBytes   = 13
+	scvString  = 14
+	scvSymbol  = 15
+	scvMap     = 17
+	scvAddress = 18
+
+	scAddressAccount  = 0
+	scAddressContract = 1
+
+	// envelopeTypeContractID is ENVELOPE_TYPE_CONTRACT_ID of HashIDPreimage
+	envelopeTypeContractID = 8
+	// contractIDPreimageFromAsset is CONTRACT_ID_PREIMAGE_FROM_ASSET
+	contractIDPreimageFromAsset = 1
+
+	// versionByteContract is the strkey version byte of contract addresses,
+	// it's not known to the vendored strkey package
+	versionByteContract = 2 << 3 // Base32-encodes to 'C...'
+)
+
+// EncodeContractAddress returns strkey (C...) of a contract ID
+func EncodeContractAddress(id [32]byte) string {
+	raw := append([]byte{versionByteContract}, id[:]...)
+	raw = append(raw, crc16.Checksum(raw)...)
+	return base32.StdEncoding.EncodeToString(raw)
+}
+
+// DecodeContractAddress returns contract ID of strkey (C...)
+func DecodeContractAddress(address string) (id [32]byte, err error) {
+	raw, err := base32.StdEncoding.DecodeString(address)
+	if err != nil {
+		return id, err
+	}
+	if len(raw) != 35 || raw[0] != versionByteContract {
+		return id, errors.New("Not a contract address")
+	}
+	err = crc16.Validate(raw[:33], raw[33:])
+	if err != nil {
+		return id, err
+	}
+	copy(id[:], raw[1:33])
+	return id, nil
+}
+
+// IsContractAddress returns true when address is a valid contract address
+func IsContractAddress(address string) bool {
+	_, err := DecodeContractAddress(address)
+	return err == nil
+}
+
+// AssetContractAddress returns the address of the Stellar Asset Contract of
+// an asset (code is empty for native asset) on a network
+func AssetContractAddress(networkPassphrase, code, issuer string) (string, error) {
+	var asset xdr.Asset
+	if code == "" {
+		asset.SetNative()
+	} else {
+		issuerKeypair, err := keypair.Parse(issuer)
+		if err != nil {
+			return "", errors.Wrap(err, "Invalid asset issuer")
+		}
+		var issuerID xdr.AccountId
+		err = issuerID.SetAddress(issuerKeypair.Address())
+		if err != nil {
+			return "", err
+		}
+		err = asset.SetCredit(code, issuerID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var preimage bytes.Buffer
+	networkID := network.ID(networkPassphrase)
+	writeUint32(&preimage, envelopeTypeContractID)
+	preimage.Write(networkID[:])
+	writeUint32(&preimage, contractIDPreimageFromAsset)
+	_, err := xdr.Marshal(&preimage, asset)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeContractAddress(sha256.Sum256(preimage.Bytes())), nil
+}
+
+// SymbolTopic returns base64 encoded SCV_SYMBOL value used in event filters
+func SymbolTopic(symbol string) string {
+	var buf bytes.Buffer
+	writeUint32(&buf, scvSymbol)
+	writeString(&buf, symbol)
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// AddressTopic returns base64 encoded SCV_ADDRESS value of an account (G...)
+// or contract (C...) address used in event filters
+func AddressTopic(address string) (string, error) {
+	var buf bytes.Buffer
+	writeUint32(&buf, scvAddress)
+
+	if strings.HasPrefix(address, "C") {
+		id, err := DecodeContractAddress(address)
+		if err != nil {
+			return "", err
+		}
+		writeUint32(&buf, scAddressContract)
+		buf.Write(id[:])
+	} else {
+		key, err := strkey.Decode(strkey.VersionByteAccountID, address)
+		if err != nil {
+			return "", err
+		}
+		writeUint32(&buf, scAddressAccount)
+		// PUBLIC_KEY_TYPE_ED25519
+		writeUint32(&buf, 0)
+		buf.Write(key)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// scVal reads SCVal values from base64 encoded XDR
+type scVal struct {
+	r *bytes.Reader
+}
+
+func newSCVal(value string) (*scVal, error) {
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return &scVal{bytes.NewReader(raw)}, nil
+}
+
+func (v *scVal) expect(expected uint32) error {
+	typ, err := v.uint32()
+	if err != nil {
+		return err
+	}
+	if typ != expected {
+		return fmt.Errorf("Unexpected SCVal type %d, expected %d", typ, expected)
+	}
+	return nil
+}
+
+// str reads SCV_SYMBOL or SCV_STRING value of typ
+func (v *scVal) str(typ uint32) (string, error) {
+	err := v.expect(typ)
+	if err != nil {
+		return "", err
+	}
+	raw, err := v.opaque()
+	return string(raw), err
+}
+
+// address reads SCV_ADDRESS of an account or a contract
+func (v *scVal) address() (string, error) {
+	err := v.expect(scvAddress)
+	if err != nil {
+		return "", err
+	}
+
+	typ, err := v.uint32()
+	if err != nil {
+		return "", err
+	}
+
+	var key [32]byte
+	switch typ {
+	case scAddressAccount:
+		keyType, err := v.uint32()
+		if err != nil {
+			return "", err
+		}
+		if keyType != 0 {
+			return "", fmt.Errorf("Unknown public key type %d", keyType)
+		}
+		_, err = io.ReadFull(v.r, key[:])
+		if err != nil {
+			return "", err
+		}
+		return strkey.Encode(strkey.VersionByteAccountID, key[:])
+	case scAddressContract:
+		_, err = io.ReadFull(v.r, key[:])
+		if err != nil {
+			return "", err
+		}
+		return EncodeContractAddress(key), nil
+	default:
+		return "", fmt.Errorf("Unsupported SCAddress type %d", typ)
+	}
+}
+
+// amount reads SCV_I128 value of an amount in stroops. SAC amounts of
+// classic assets always fit in int64.
+func (v *scVal) amount() (int64, error) {
+	err := v.expect(scvI128)
+	if err != nil {
+		return 0, err
+	}
+
+	var parts struct {
+		Hi int64
+		Lo uint64
+	}
+	err = binary.Read(v.r, binary.BigEndian, &parts)
+	if err != nil {
+		return 0, err
+	}
+	if parts.Hi != 0 || parts.Lo > math.MaxInt64 {
+		return 0, errors.New("Amount out of range")
+	}
+	return int64(parts.Lo), nil
+}
+
+// transferMemo is a memo of a transfer made from its to_muxed_id
+type transferMemo struct {
+	Type  string
+	Value string
+}
+
+// transferData reads data of a SAC transfer event: SCV_I128 amount or, since
+// protocol 23, SCV_MAP with amount and to_muxed_id entries. Memo type is
+// "none" when there is no to_muxed_id.
+func (v *scVal) transferData() (stroops int64, memo transferMemo, err error) {
+	memo.Type = "none"
+
+	typ, err := v.peekUint32()
+	if err != nil {
+		return
+	}
+	if typ != scvMap {
+		stroops, err = v.amount()
+		return
+	}
+
+	_, _ = v.uint32()
+	present, err := v.uint32()
+	if err != nil {
+		return
+	}
+	if present == 0 {
+		err = errors.New("Empty transfer data")
+		return
+	}
+	count, err := v.uint32()
+	if err != nil {
+		return
+	}
+
+	found := false
+	for i := uint32(0); i < count; i++ {
+		var key string
+		key, err = v.str(scvSymbol)
+		if err != nil {
+			return
+		}
+
+		switch key {
+		case "amount":
+			stroops, err = v.amount()
+			found = true
+		case "to_muxed_id":
+			memo, err = v.muxedID()
+		default:
+			err = fmt.Errorf("Unexpected transfer data entry %s", key)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	if !found {
+		err = errors.New("Transfer data has no amount")
+	}
+	return
+}
+
+// muxedID reads to_muxed_id of transfer data, it's SCV_U64, SCV_BYTES or
+// SCV_STRING depending on the memo of the classic payment it replaces
+func (v *scVal) muxedID() (memo transferMemo, err error) {
+	typ, err := v.uint32()
+	if err != nil {
+		return
+	}
+
+	switch typ {
+	case scvU64:
+		var id uint64
+		err = binary.Read(v.r, binary.BigEndian, &id)
+		memo = transferMemo{"id", strconv.FormatUint(id, 10)}
+	case scvBytes:
+		var raw []byte
+		raw, err = v.opaque()
+		memo = transferMemo{"hash", base64.StdEncoding.EncodeToString(raw)}
+	case scvString:
+		var raw []byte
+		raw, err = v.opaque()
+		memo = transferMemo{"text", string(raw)}
+	default:
+		err = fmt.Errorf("Unsupported to_muxed_id type %d", typ)
+	}
+	return
+}
+
+func (v *scVal) uint32() (value uint32, err error) {
+	err = binary.Read(v.r, binary.BigEndian, &value)
+	return
+}
+
+func (v *scVal) peekUint32() (uint32, error) {
+	value, err := v.uint32()
+	if err != nil {
+		return 0, err
+	}
+	_, err = v.r.Seek(-4, io.SeekCurrent)
+	return value, err
+}
+
+// opaque reads variable length opaque (or string) with padding
+func (v *scVal) opaque() ([]byte, error) {
+	length, err := v.uint32()
+	if err != nil {
+		return nil, err
+	}
+	if int64(length) > int64(v.r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	raw := make([]byte, length)
+	_, err = io.ReadFull(v.r, raw)
+	if err != nil {
+		return nil, err
+	}
+	_, err = v.r.Seek(int64((4-length%4)%4), io.SeekCurrent)
+	return raw, err
+}
+
+func writeUint32(buf *bytes.Buffer, value uint32) {
+	_ = binary.Write(buf, binary.BigEndian, value)
+}
+
+func writeString(buf *bytes.Buffer, value string) {
+	writeUint32(buf, uint32(len(value)))
+	buf.WriteString(value)
+	buf.Write(make([]byte, (4-len(value)%4)%4))
+}
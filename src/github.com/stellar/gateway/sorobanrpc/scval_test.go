@@ -0,0 +1,132 @@
+package sorobanrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetContractAddress(t *testing.T) {
+	address, err := AssetContractAddress(network.PublicNetworkPassphrase, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "CAS3J7GYLGXMF6TDJBBYYSE3HQ6BBSMLNUQ34T6TZMYMW2EVH34XOWMA", address)
+
+	address, err = AssetContractAddress(network.TestNetworkPassphrase, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", address)
+
+	address, err = AssetContractAddress(network.PublicNetworkPassphrase, "USDC", "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN")
+	require.NoError(t, err)
+	assert.Equal(t, "CCW67TSZV3SSS2HXMBQ5JFGCKJNXKZM7UQUWUZPUTHXSTZLEO7SJMI75", address)
+
+	_, err = AssetContractAddress(network.PublicNetworkPassphrase, "USDC", "bad")
+	assert.Error(t, err)
+}
+
+func TestContractAddress(t *testing.T) {
+	id, err := DecodeContractAddress("CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC")
+	require.NoError(t, err)
+	assert.Equal(t, "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", EncodeContractAddress(id))
+
+	assert.False(t, IsContractAddress("CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSD"))
+	assert.False(t, IsContractAddress("GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2"))
+	assert.False(t, IsContractAddress(""))
+}
+
+func TestAddressTopic(t *testing.T) {
+	for _, address := range []string{
+		"GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2",
+		"CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC",
+	} {
+		topic, err := AddressTopic(address)
+		require.NoError(t, err)
+
+		decoded, err := decodeAddress(topic)
+		require.NoError(t, err)
+		assert.Equal(t, address, decoded)
+	}
+
+	_, err := AddressTopic("GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR3")
+	assert.Error(t, err)
+
+	symbol, err := decodeString(SymbolTopic("transfer"), scvSymbol)
+	require.NoError(t, err)
+	assert.Equal(t, "transfer", symbol)
+}
+
+func TestTransferData(t *testing.T) {
+	read := func(value string) (int64, transferMemo, error) {
+		v, err := newSCVal(value)
+		require.NoError(t, err)
+		return v.transferData()
+	}
+
+	stroops, memo, err := read(i128Value(1000000))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000000), stroops)
+	assert.Equal(t, transferMemo{Type: "none"}, memo)
+
+	stroops, memo, err = read(transferMap(t, 250, scvU64, 42))
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), stroops)
+	assert.Equal(t, transferMemo{"id", "42"}, memo)
+
+	stroops, memo, err = read(transferMap(t, 250, scvString, "route"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), stroops)
+	assert.Equal(t, transferMemo{"text", "route"}, memo)
+
+	// Amounts of custom tokens may not fit in int64
+	var buf bytes.Buffer
+	writeUint32(&buf, scvI128)
+	require.NoError(t, binary.Write(&buf, binary.BigEndian, [2]uint64{1, 0}))
+	_, _, err = read(base64.StdEncoding.EncodeToString(buf.Bytes()))
+	assert.Error(t, err)
+
+	_, _, err = read(SymbolTopic("transfer"))
+	assert.Error(t, err)
+}
+
+func i128Value(stroops int64) string {
+	var buf bytes.Buffer
+	writeUint32(&buf, scvI128)
+	_ = binary.Write(&buf, binary.BigEndian, [2]int64{0, stroops})
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func stringValue(value string) string {
+	var buf bytes.Buffer
+	writeUint32(&buf, scvString)
+	writeString(&buf, value)
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// transferMap returns protocol 23 transfer data with to_muxed_id
+func transferMap(t *testing.T, stroops int64, muxedType uint32, muxedID interface{}) string {
+	var buf bytes.Buffer
+	writeUint32(&buf, scvMap)
+	writeUint32(&buf, 1)
+	writeUint32(&buf, 2)
+
+	writeUint32(&buf, scvSymbol)
+	writeString(&buf, "amount")
+	amount, err := base64.StdEncoding.DecodeString(i128Value(stroops))
+	require.NoError(t, err)
+	buf.Write(amount)
+
+	writeUint32(&buf, scvSymbol)
+	writeString(&buf, "to_muxed_id")
+	writeUint32(&buf, muxedType)
+	switch id := muxedID.(type) {
+	case int:
+		require.NoError(t, binary.Write(&buf, binary.BigEndian, uint64(id)))
+	case string:
+		writeString(&buf, id)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
@@ -0,0 +1,287 @@
+package sorobanrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	// eventsPageSize is the max number of events loaded in one request
+	eventsPageSize = 100
+	// handlerRetryDelay is a delay between onPaymentHandler retries
+	handlerRetryDelay = 10 * time.Second
+	// maxFilters and maxTopicFilters are limits of getEvents filters
+	maxFilters      = 5
+	maxTopicFilters = 5
+
+	// MaxAddresses is the max number of addresses TransferStream can watch
+	MaxAddresses = maxFilters * maxTopicFilters
+
+	// toidOperationMask masks the operation part of a TOID
+	toidOperationMask = 1<<12 - 1
+)
+
+// TransferStream streams transfers of Stellar Asset Contracts (SAC) by
+// polling contract events from soroban-rpc. Unlike Horizon it sees transfers
+// to contract (C...) addresses. Transfers are sent as invoke_host_function
+// payments with a single asset balance change, so the listener handles them
+// like contract transfers streamed by Horizon.
+type TransferStream struct {
+	Client *Client
+	// NetworkPassphrase is used to check that events are emitted by SACs
+	NetworkPassphrase string
+	// HorizonURL is used in links of streamed payments
+	HorizonURL string
+	// PollInterval is how often soroban-rpc is checked for new events
+	PollInterval time.Duration
+
+	log *logrus.Entry
+	// sleep is used to wait between polls, replaced in tests
+	sleep func(time.Duration)
+}
+
+// NewTransferStream creates a TransferStream reading events from soroban-rpc
+// server at url
+func NewTransferStream(url, networkPassphrase, horizonURL string, pollInterval time.Duration) *TransferStream {
+	return &TransferStream{
+		Client:            New(url, &http.Client{Timeout: 30 * time.Second}),
+		NetworkPassphrase: networkPassphrase,
+		HorizonURL:        horizonURL,
+		PollInterval:      pollInterval,
+		log: logrus.WithFields(logrus.Fields{
+			"service": "TransferStream",
+		}),
+		sleep: time.Sleep,
+	}
+}
+
+// StreamTransfers sends SAC transfers to addresses (accounts or contracts) in
+// successful contract calls to onPaymentHandler. Cursor is an event ID, it's
+// used as a paging token of streamed payments. When it's empty or "now"
+// transfers are streamed from the latest ledger. It never returns unless
+// addresses or the cursor are invalid.
+func (s *TransferStream) StreamTransfers(addresses []string, cursor *string, onPaymentHandler horizon.PaymentHandler) error {
+	filters, err := transferFilters(addresses)
+	if err != nil {
+		return err
+	}
+
+	request := GetEventsRequest{
+		Filters:    filters,
+		Pagination: &Pagination{Limit: eventsPageSize},
+	}
+
+	if cursor == nil || *cursor == "" || *cursor == "now" {
+		for {
+			request.StartLedger, err = s.Client.GetLatestLedger(context.Background())
+			if err == nil {
+				break
+			}
+			s.log.WithFields(logrus.Fields{"err": err}).Error("Error loading latest ledger")
+			s.sleep(s.PollInterval)
+		}
+	} else {
+		if _, _, err = parseEventID(*cursor); err != nil {
+			return err
+		}
+		request.Pagination.Cursor = *cursor
+	}
+
+	for {
+		response, err := s.Client.GetEvents(context.Background(), request)
+		if err != nil {
+			s.log.WithFields(logrus.Fields{"err": err}).Error("Error loading events")
+			s.sleep(s.PollInterval)
+			continue
+		}
+
+		for _, event := range response.Events {
+			// Cursor is moved before the event is processed, events are
+			// retried until the handler succeeds
+			request.StartLedger = 0
+			request.Pagination.Cursor = event.ID
+
+			if !event.InSuccessfulContractCall {
+				continue
+			}
+
+			payment, err := s.payment(event)
+			if err != nil {
+				s.log.WithFields(logrus.Fields{"err": err, "id": event.ID}).Warn("Skipping event that is not a SAC transfer")
+				continue
+			}
+
+			for {
+				err = onPaymentHandler(payment)
+				if err != nil {
+					s.log.Error("Error from onPaymentHandler: ", err)
+					s.log.Info("Sleeping...")
+					s.sleep(handlerRetryDelay)
+				} else {
+					break
+				}
+			}
+		}
+
+		// Newer soroban-rpc versions return the cursor of the last ledger
+		// checked so empty ledgers are not scanned again
+		if response.Cursor != "" {
+			request.StartLedger = 0
+			request.Pagination.Cursor = response.Cursor
+		}
+
+		if len(response.Events) < eventsPageSize {
+			s.sleep(s.PollInterval)
+		}
+	}
+}
+
+// transferFilters returns getEvents filters of transfer events to addresses
+func transferFilters(addresses []string) ([]EventFilter, error) {
+	if len(addresses) == 0 || len(addresses) > MaxAddresses {
+		return nil, fmt.Errorf("Between 1 and %d addresses can be streamed", MaxAddresses)
+	}
+
+	transfer := SymbolTopic("transfer")
+	var filters []EventFilter
+	for i, address := range addresses {
+		topic, err := AddressTopic(address)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid address "+address)
+		}
+
+		if i%maxTopicFilters == 0 {
+			filters = append(filters, EventFilter{Type: "contract"})
+		}
+		filter := &filters[len(filters)-1]
+		filter.Topics = append(filter.Topics, []string{transfer, "*", topic, "*"})
+	}
+	return filters, nil
+}
+
+// payment converts SAC transfer event to a payment. Events of contracts that
+// are not SACs of the asset in the event topic are rejected, anyone can
+// deploy a contract emitting events like SAC transfers.
+func (s *TransferStream) payment(event Event) (payment horizon.PaymentResponse, err error) {
+	if len(event.Topic) != 4 {
+		return payment, errors.New("Unexpected number of topics")
+	}
+
+	name, err := decodeString(event.Topic[0], scvSymbol)
+	if err != nil || name != "transfer" {
+		return payment, errors.New("Not a transfer event")
+	}
+
+	change := horizon.AssetBalanceChange{Type: "transfer"}
+	change.From, err = decodeAddress(event.Topic[1])
+	if err != nil {
+		return payment, errors.Wrap(err, "Invalid from address")
+	}
+	change.To, err = decodeAddress(event.Topic[2])
+	if err != nil {
+		return payment, errors.Wrap(err, "Invalid to address")
+	}
+
+	asset, err := decodeString(event.Topic[3], scvString)
+	if err != nil {
+		return payment, errors.Wrap(err, "Invalid asset")
+	}
+	if asset == "native" {
+		change.AssetType = "native"
+	} else {
+		parts := strings.SplitN(asset, ":", 2)
+		if len(parts) != 2 {
+			return payment, errors.New("Invalid asset " + asset)
+		}
+		change.AssetCode, change.AssetIssuer = parts[0], parts[1]
+		change.AssetType = "credit_alphanum4"
+		if len(change.AssetCode) > 4 {
+			change.AssetType = "credit_alphanum12"
+		}
+	}
+
+	contractID, err := AssetContractAddress(s.NetworkPassphrase, change.AssetCode, change.AssetIssuer)
+	if err != nil {
+		return payment, err
+	}
+	if contractID != event.ContractID {
+		return payment, errors.New("Not emitted by the asset contract")
+	}
+
+	data, err := newSCVal(event.Value)
+	if err != nil {
+		return payment, err
+	}
+	stroops, memo, err := data.transferData()
+	if err != nil {
+		return payment, errors.Wrap(err, "Invalid transfer data")
+	}
+	change.Amount = amount.StringFromInt64(stroops)
+
+	operationID, index, err := parseEventID(event.ID)
+	if err != nil {
+		return payment, err
+	}
+
+	// An operation can transfer to watched addresses many times, transfers
+	// are keyed by the index of the event
+	payment.ID = horizon.ContractTransferID(strconv.FormatInt(operationID, 10), index)
+	payment.Type = "invoke_host_function"
+	payment.PagingToken = event.ID
+	payment.TransactionID = event.TransactionHash
+	payment.Links.Transaction.Href = s.HorizonURL + "/transactions/" + event.TransactionHash
+	payment.AssetBalanceChanges = []horizon.AssetBalanceChange{change}
+	// Soroban transactions have no memos, muxed ID of the destination is
+	// sent in transfer data instead
+	payment.Memo.Type, payment.Memo.Value = memo.Type, memo.Value
+	return payment, nil
+}
+
+// parseEventID returns the ID of the operation that emitted the event and the
+// index of the event. Event IDs start with TOID of the operation with 0-based
+// operation index, Horizon operation IDs use 1-based index.
+func parseEventID(id string) (operationID int64, index int64, err error) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("Invalid event ID " + id)
+	}
+
+	operationID, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "Invalid event ID "+id)
+	}
+	index, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "Invalid event ID "+id)
+	}
+
+	if operationID&toidOperationMask == 0 {
+		operationID++
+	}
+	return operationID, index, nil
+}
+
+func decodeString(value string, typ uint32) (string, error) {
+	v, err := newSCVal(value)
+	if err != nil {
+		return "", err
+	}
+	return v.str(typ)
+}
+
+func decodeAddress(value string) (string, error) {
+	v, err := newSCVal(value)
+	if err != nil {
+		return "", err
+	}
+	return v.address()
+}
@@ -0,0 +1,168 @@
+package sorobanrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	senderID   = "GCOGCYU77DLEVYCXDQM7F32M5PCKES6VU3Z5GURF6U6OA5LFOVTRYPOX"
+	receiverID = "GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2"
+	contractID = "CCW67TSZV3SSS2HXMBQ5JFGCKJNXKZM7UQUWUZPUTHXSTZLEO7SJMI75"
+	// xlmContractID is native SAC on the test network
+	xlmContractID = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+)
+
+// stopStream is a panic value used to stop StreamTransfers in tests
+type stopStream struct{}
+
+func transferEvent(t *testing.T, id, contract, to string, value string) Event {
+	from, err := AddressTopic(senderID)
+	require.NoError(t, err)
+	toTopic, err := AddressTopic(to)
+	require.NoError(t, err)
+
+	return Event{
+		Type:                     "contract",
+		ID:                       id,
+		ContractID:               contract,
+		Topic:                    []string{SymbolTopic("transfer"), from, toTopic, stringValue("native")},
+		Value:                    value,
+		InSuccessfulContractCall: true,
+		TransactionHash:          "abc",
+	}
+}
+
+func TestStreamTransfers(t *testing.T) {
+	var requests []GetEventsRequest
+	var pages [][]Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Method string           `json:"method"`
+			Params GetEventsRequest `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		var result interface{}
+		switch body.Method {
+		case "getLatestLedger":
+			result = map[string]interface{}{"sequence": 100}
+		case "getEvents":
+			requests = append(requests, body.Params)
+			var events []Event
+			if len(pages) > 0 {
+				events, pages = pages[0], pages[1:]
+			}
+			result = GetEventsResponse{Events: events, LatestLedger: 100}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	s := NewTransferStream(server.URL, network.TestNetworkPassphrase, "https://horizon-testnet.stellar.org", time.Second)
+	s.log = logrus.NewEntry(logrus.New())
+
+	stream := func(cursor *string, handler horizon.PaymentHandler) {
+		defer func() {
+			if r := recover(); r != nil && r != (stopStream{}) {
+				panic(r)
+			}
+		}()
+		err := s.StreamTransfers([]string{receiverID, contractID}, cursor, handler)
+		require.NoError(t, err)
+	}
+
+	t.Run("streams SAC transfers from the latest ledger", func(t *testing.T) {
+		requests = nil
+		pages = [][]Event{{
+			transferEvent(t, "0000000429496733696-0000000001", xlmContractID, contractID, transferMap(t, 1000000, scvU64, 7)),
+			// Not emitted by the asset contract
+			transferEvent(t, "0000000429496737792-0000000001", contractID, contractID, i128Value(1000000)),
+		}}
+		s.sleep = func(time.Duration) { panic(stopStream{}) }
+
+		var payments []horizon.PaymentResponse
+		stream(nil, func(payment horizon.PaymentResponse) error {
+			payments = append(payments, payment)
+			return nil
+		})
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, uint32(100), requests[0].StartLedger)
+		require.Len(t, requests[0].Filters, 1)
+		assert.Len(t, requests[0].Filters[0].Topics, 2)
+
+		require.Len(t, payments, 1)
+		payment := payments[0]
+		// Transfers are keyed by the event index in the operation
+		assert.Equal(t, "429496733697-1", payment.ID)
+		assert.Equal(t, "0000000429496733696-0000000001", payment.PagingToken)
+		assert.Equal(t, "invoke_host_function", payment.Type)
+		assert.Equal(t, "https://horizon-testnet.stellar.org/transactions/abc", payment.Links.Transaction.Href)
+		assert.Equal(t, "id", payment.Memo.Type)
+		assert.Equal(t, "7", payment.Memo.Value)
+
+		assert.True(t, payment.LoadContractTransfer(contractID))
+		assert.Equal(t, senderID, payment.From)
+		assert.Equal(t, contractID, payment.To)
+		assert.Equal(t, "native", payment.AssetType)
+		assert.Equal(t, "0.1000000", payment.Amount)
+	})
+
+	t.Run("resumes from cursor and retries handler", func(t *testing.T) {
+		requests = nil
+		pages = [][]Event{{
+			transferEvent(t, "0000000429496733696-0000000002", xlmContractID, receiverID, i128Value(5)),
+		}}
+		sleeps := 0
+		s.sleep = func(delay time.Duration) {
+			sleeps++
+			if delay != handlerRetryDelay {
+				panic(stopStream{})
+			}
+		}
+
+		calls := 0
+		cursor := "0000000429496733696-0000000001"
+		stream(&cursor, func(payment horizon.PaymentResponse) error {
+			calls++
+			if calls == 1 {
+				return assert.AnError
+			}
+			return nil
+		})
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, uint32(0), requests[0].StartLedger)
+		assert.Equal(t, cursor, requests[0].Pagination.Cursor)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 2, sleeps)
+	})
+
+	t.Run("rejects invalid cursor and addresses", func(t *testing.T) {
+		cursor := "100"
+		assert.Error(t, s.StreamTransfers([]string{receiverID}, &cursor, nil))
+		assert.Error(t, s.StreamTransfers([]string{"bad"}, nil, nil))
+		assert.Error(t, s.StreamTransfers(make([]string, MaxAddresses+1), nil, nil))
+	})
+}
+
+func TestParseEventID(t *testing.T) {
+	id, index, err := parseEventID("0000000429496733696-0000000003")
+	require.NoError(t, err)
+	assert.Equal(t, int64(429496733697), id)
+	assert.Equal(t, int64(3), index)
+
+	_, _, err = parseEventID("429496733697")
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+// Package stage bounds blocking calls made while handling a request (ex.
+// federation resolution or loading accounts from Horizon) by the request
+// context and a per-stage timeout, so handlers return as soon as the client
+// disconnects or a slow external service exceeds its time.
+package stage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stellar/gateway/metrics"
+)
+
+// Stages of handling a payment
+const (
+	// Federation resolves the destination, including loading stellar.toml
+	Federation = "federation"
+	// Horizon loads accounts from Horizon before the transaction is submitted
+	Horizon = "horizon"
+	// Compliance sends the payment to the compliance server
+	Compliance = "compliance"
+)
+
+// ErrTimeout is returned by Run when a stage does not finish in time
+var ErrTimeout = errors.New("Stage timed out")
+
+// Run calls fn with ctx limited by timeout (not limited when 0) and returns
+// its error. When ctx is cancelled or timeout passes before fn returns, Run
+// returns ctx.Err() or ErrTimeout without waiting for fn. fn should pass its
+// context to calls accepting one, other calls keep running in background
+// until their own client timeouts.
+func Run(ctx context.Context, name string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		cancellations(name).Inc()
+		return err
+	}
+
+	stageCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		stageCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(stageCtx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-stageCtx.Done():
+		if err := ctx.Err(); err != nil {
+			cancellations(name).Inc()
+			return err
+		}
+		timeouts(name).Inc()
+		return ErrTimeout
+	}
+}
+
+// Interrupted returns true if err was returned by Run because the stage
+// timed out or its context was cancelled
+func Interrupted(err error) bool {
+	return err == ErrTimeout || err == context.Canceled || err == context.DeadlineExceeded
+}
+
+func timeouts(name string) *metrics.Counter {
+	return metrics.NewCounter(metrics.Label("bridge_request_stage_timeouts_total", "stage", name), "Number of request stages that did not finish within their timeout.")
+}
+
+func cancellations(name string) *metrics.Counter {
+	return metrics.NewCounter(metrics.Label("bridge_request_stage_cancellations_total", "stage", name), "Number of request stages abandoned because the request was cancelled (ex. the client disconnected).")
+}
@@ -0,0 +1,54 @@
+package stage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	err := Run(context.Background(), Horizon, time.Second, func(ctx context.Context) error {
+		return errors.New("Not found")
+	})
+	assert.EqualError(t, err, "Not found")
+	assert.False(t, Interrupted(err))
+
+	// Slow calls are abandoned after the timeout, their context is cancelled
+	block := make(chan struct{})
+	defer close(block)
+	started := make(chan context.Context, 1)
+	err = Run(context.Background(), Federation, 10*time.Millisecond, func(ctx context.Context) error {
+		started <- ctx
+		<-block
+		return nil
+	})
+	assert.Equal(t, ErrTimeout, err)
+	assert.True(t, Interrupted(err))
+	assert.Error(t, (<-started).Err())
+	assert.Equal(t, int64(1), timeouts(Federation).Value())
+
+	// Cancelled requests do not wait for the timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	err = Run(ctx, Compliance, time.Minute, func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(1), cancellations(Compliance).Value())
+
+	// Stages of cancelled requests are not started
+	called := false
+	err = Run(ctx, Horizon, 0, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, called)
+}
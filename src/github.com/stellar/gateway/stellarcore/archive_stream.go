@@ -0,0 +1,396 @@
+package stellarcore
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// checkpointFrequency is the number of ledgers between history archive
+// checkpoints
+const checkpointFrequency = 64
+
+// ArchiveStream streams payments by reading transactions and results files
+// of a history archive. Any stellar-core publishing to an archive can be
+// used, including captive core run by Horizon, and the archive can be read
+// over plain HTTP (ex. https://history.stellar.org/prd/core-live/core_live_001).
+// Archives are published at checkpoints every 64 ledgers, so payments are
+// received with a delay of up to 64 ledgers (about 6 minutes).
+type ArchiveStream struct {
+	// URL of the history archive root
+	URL               string
+	HTTP              net.HTTPClientInterface
+	NetworkPassphrase string
+	// PollInterval is how often the archive is checked for new checkpoints
+	PollInterval time.Duration
+
+	log *logrus.Entry
+	// sleep is used to wait between polls, replaced in tests
+	sleep func(time.Duration)
+
+	mutex sync.Mutex
+	// updatedAt is the time of the last successful poll
+	updatedAt time.Time
+}
+
+// historyArchiveState is the part of stellar-history.json read by
+// ArchiveStream
+type historyArchiveState struct {
+	CurrentLedger uint32 `json:"currentLedger"`
+}
+
+// NewArchiveStream creates a new ArchiveStream
+func NewArchiveStream(archiveURL string, httpClient net.HTTPClientInterface, networkPassphrase string, pollInterval time.Duration) *ArchiveStream {
+	return &ArchiveStream{
+		URL:               strings.TrimRight(archiveURL, "/"),
+		HTTP:              httpClient,
+		NetworkPassphrase: networkPassphrase,
+		PollInterval:      pollInterval,
+		log: logrus.WithFields(logrus.Fields{
+			"service": "ArchiveStream",
+		}),
+		sleep: time.Sleep,
+	}
+}
+
+// StreamPayments sends payments received by accountID in successful
+// transactions to onPaymentHandler. Cursor is a paging token in the same
+// format as Horizon paging tokens so the bridge can switch between backends.
+// It never returns unless the cursor is invalid.
+func (s *ArchiveStream) StreamPayments(accountID string, cursor *string, onPaymentHandler horizon.PaymentHandler) error {
+	var ledger, index, operation uint32
+	var err error
+
+	now := cursor == nil || *cursor == "now"
+	if !now {
+		ledger, index, operation, err = parsePagingToken(*cursor)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		current, err := s.currentLedger()
+		if err != nil {
+			s.log.WithFields(logrus.Fields{"err": err}).Error("Error loading history archive state")
+			s.sleep(s.PollInterval)
+			continue
+		}
+		s.setUpdatedAt(time.Now())
+
+		// Start after the last published checkpoint
+		if now {
+			ledger, index, now = current+1, 0, false
+		}
+
+		checkpoint := checkpointLedger(ledger)
+		if checkpoint > current {
+			s.sleep(s.PollInterval)
+			continue
+		}
+
+		transactions, err := s.checkpointTransactions(checkpoint, func(record []byte) bool {
+			return touchesAccount(record, accountID)
+		})
+		if err != nil {
+			s.log.WithFields(logrus.Fields{"err": err, "checkpoint": checkpoint}).Error("Error loading checkpoint")
+			s.sleep(s.PollInterval)
+			continue
+		}
+
+		for _, tx := range transactions {
+			// Skip transactions processed before the cursor was saved
+			if tx.Ledger < ledger || (tx.Ledger == ledger && tx.Index < index) {
+				continue
+			}
+
+			payments, err := paymentsFromTransaction(tx, accountID)
+			if err != nil {
+				s.log.WithFields(logrus.Fields{"err": err, "hash": tx.ID}).Error("Error decoding transaction")
+				return err
+			}
+
+			for _, payment := range payments {
+				if tx.Ledger == ledger && tx.Index == index && operationIndex(payment.PagingToken) <= operation {
+					continue
+				}
+
+				for {
+					err = onPaymentHandler(payment)
+					if err != nil {
+						s.log.Error("Error from onPaymentHandler: ", err)
+						s.log.Info("Sleeping...")
+						s.sleep(handlerRetryDelay)
+					} else {
+						break
+					}
+				}
+			}
+
+			ledger, index, operation = tx.Ledger, tx.Index+1, 0
+		}
+
+		ledger, index, operation = checkpoint+1, 0, 0
+	}
+}
+
+// StreamUpdatedAt returns the time of the last successful poll of the
+// history archive or zero time before the first one
+func (s *ArchiveStream) StreamUpdatedAt() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.updatedAt
+}
+
+func (s *ArchiveStream) setUpdatedAt(t time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.updatedAt = t
+}
+
+// LoadMemo loads the memo of the payment transaction from the history
+// archive. Payments streamed by ArchiveStream already contain the memo.
+func (s *ArchiveStream) LoadMemo(p *horizon.PaymentResponse) error {
+	if p.Memo.Type != "" {
+		return nil
+	}
+
+	tx, err := s.loadTransaction(p)
+	if err != nil {
+		return err
+	}
+
+	p.Memo.Type, p.Memo.Value = memoValue(tx.Envelope.Transaction().Memo)
+	return nil
+}
+
+// LoadAccountMergeAmount loads `account_merge` operation amount from the
+// operation result. Payments streamed by ArchiveStream already contain it.
+func (s *ArchiveStream) LoadAccountMergeAmount(p *horizon.PaymentResponse) error {
+	if p.Type != "account_merge" {
+		return errors.New("Not `account_merge` operation")
+	}
+
+	if p.Amount != "" {
+		return nil
+	}
+
+	tx, err := s.loadTransaction(p)
+	if err != nil {
+		return err
+	}
+
+	results, _ := tx.Result.Results()
+	balance, ok := mergedBalance(results, int(operationIndex(p.ID))-1)
+	if !ok {
+		return errors.New("Operation result not found")
+	}
+	p.Amount = amounts.String(int64(balance))
+	return nil
+}
+
+// LoadLiquidityPools loads IDs of liquidity pools a path payment was routed
+// through from the operation result
+func (s *ArchiveStream) LoadLiquidityPools(p *horizon.PaymentResponse) error {
+	if !p.IsPathPayment() {
+		return errors.New("Not a path payment operation")
+	}
+
+	tx, err := s.loadTransaction(p)
+	if err != nil {
+		return err
+	}
+
+	results, _ := tx.Result.Results()
+	p.LiquidityPoolIDs = liquidityPools(results, int(operationIndex(p.ID))-1)
+	return nil
+}
+
+// loadTransaction loads the transaction of a payment from the checkpoint of
+// its ledger
+func (s *ArchiveStream) loadTransaction(p *horizon.PaymentResponse) (tx transaction, err error) {
+	// IDs of contract transfers are suffixed with the transfer index
+	ledger, _, _, err := parsePagingToken(strings.SplitN(p.ID, "-", 2)[0])
+	if err != nil {
+		return tx, errors.Wrap(err, "Invalid operation ID")
+	}
+
+	transactions, err := s.checkpointTransactions(checkpointLedger(ledger), func(record []byte) bool {
+		return recordLedger(record) == ledger
+	})
+	if err != nil {
+		return
+	}
+
+	for _, tx := range transactions {
+		if tx.ID == p.TransactionID {
+			return tx, nil
+		}
+	}
+	return tx, errors.New("Transaction not found in history archive")
+}
+
+func (s *ArchiveStream) currentLedger() (uint32, error) {
+	resp, err := s.HTTP.Get(s.URL + "/.well-known/stellar-history.json")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("History archive responded with status %d", resp.StatusCode)
+	}
+
+	var state historyArchiveState
+	err = json.NewDecoder(resp.Body).Decode(&state)
+	if err != nil {
+		return 0, errors.Wrap(err, "Error decoding history archive state")
+	}
+	return state.CurrentLedger, nil
+}
+
+// checkpointTransactions returns successful and failed transactions of a
+// checkpoint in the ledger apply order. Ledgers whose transactions entry is
+// not included are skipped without decoding them.
+func (s *ArchiveStream) checkpointTransactions(checkpoint uint32, include func(record []byte) bool) (transactions []transaction, err error) {
+	envelopes := map[uint32]map[xdr.Hash]stellarxdr.TransactionEnvelope{}
+	err = s.readCheckpointFile("transactions", checkpoint, func(record []byte) error {
+		if !include(record) {
+			return nil
+		}
+
+		var entry stellarxdr.TransactionHistoryEntry
+		err := xdr.SafeUnmarshal(record, &entry)
+		if err != nil {
+			return errors.Wrap(err, "Error decoding transactions entry")
+		}
+
+		ledger := map[xdr.Hash]stellarxdr.TransactionEnvelope{}
+		for _, envelope := range entry.Envelopes() {
+			hash, err := envelope.Hash(s.NetworkPassphrase)
+			if err != nil {
+				return err
+			}
+			ledger[hash] = envelope
+		}
+		envelopes[uint32(entry.LedgerSeq)] = ledger
+		return nil
+	})
+	if err != nil || len(envelopes) == 0 {
+		return
+	}
+
+	err = s.readCheckpointFile("results", checkpoint, func(record []byte) error {
+		if envelopes[recordLedger(record)] == nil {
+			return nil
+		}
+
+		var entry stellarxdr.TransactionHistoryResultEntry
+		err := xdr.SafeUnmarshal(record, &entry)
+		if err != nil {
+			return errors.Wrap(err, "Error decoding results entry")
+		}
+
+		ledger := uint32(entry.LedgerSeq)
+		for i, pair := range entry.TxResultSet.Results {
+			envelope, ok := envelopes[ledger][pair.TransactionHash]
+			if !ok {
+				return fmt.Errorf("Transaction %s not found in ledger %d", hex.EncodeToString(pair.TransactionHash[:]), ledger)
+			}
+			transactions = append(transactions, transaction{
+				Ledger:   ledger,
+				Index:    uint32(i) + 1,
+				ID:       hex.EncodeToString(pair.TransactionHash[:]),
+				Envelope: envelope,
+				Result:   pair.Result,
+			})
+		}
+		return nil
+	})
+	return
+}
+
+// readCheckpointFile calls onRecord with every XDR record of a gzipped
+// checkpoint file (ex. transactions/00/00/3f/transactions-00003f7f.xdr.gz).
+// Records are XDR entries prefixed with a record mark (RFC 5531): the last
+// fragment bit and 31 bits of the fragment length.
+func (s *ArchiveStream) readCheckpointFile(category string, checkpoint uint32, onRecord func([]byte) error) error {
+	name := fmt.Sprintf("%08x", checkpoint)
+	url := fmt.Sprintf("%s/%s/%s/%s/%s/%s-%s.xdr.gz", s.URL, category, name[0:2], name[2:4], name[4:6], category, name)
+
+	resp, err := s.HTTP.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("History archive responded with status %d to %s", resp.StatusCode, url)
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "Error reading "+url)
+	}
+	defer reader.Close()
+
+	buffered := bufio.NewReader(reader)
+	for {
+		var record []byte
+		for {
+			var mark uint32
+			err = binary.Read(buffered, binary.BigEndian, &mark)
+			if err == io.EOF && len(record) == 0 {
+				return nil
+			} else if err != nil {
+				return errors.Wrap(err, "Error reading "+url)
+			}
+
+			fragment := make([]byte, mark&0x7fffffff)
+			_, err = io.ReadFull(buffered, fragment)
+			if err != nil {
+				return errors.Wrap(err, "Error reading "+url)
+			}
+			record = append(record, fragment...)
+
+			if mark&0x80000000 != 0 {
+				break
+			}
+		}
+
+		err = onRecord(record)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// recordLedger returns the ledger sequence of a transactions or results
+// entry, its first field
+func recordLedger(record []byte) uint32 {
+	if len(record) < 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(record)
+}
+
+// checkpointLedger returns the checkpoint ledger containing ledger, the
+// first checkpoint is ledger 63
+func checkpointLedger(ledger uint32) uint32 {
+	return ledger | (checkpointFrequency - 1)
+}
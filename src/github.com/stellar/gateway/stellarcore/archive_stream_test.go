@@ -0,0 +1,174 @@
+package stellarcore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPassphrase = "Test SDF Network ; September 2015"
+
+// checkpointFile returns a gzipped checkpoint file of XDR records, []byte
+// records are written as they are
+func checkpointFile(t *testing.T, records ...interface{}) []byte {
+	var file bytes.Buffer
+	writer := gzip.NewWriter(&file)
+	for _, record := range records {
+		raw, ok := record.([]byte)
+		if !ok {
+			var buffer bytes.Buffer
+			_, err := xdr.Marshal(&buffer, record)
+			require.NoError(t, err)
+			raw = buffer.Bytes()
+		}
+		require.NoError(t, binary.Write(writer, binary.BigEndian, uint32(len(raw))|0x80000000))
+		writer.Write(raw)
+	}
+	require.NoError(t, writer.Close())
+	return file.Bytes()
+}
+
+func newArchive(t *testing.T) (*httptest.Server, stellarxdr.TransactionEnvelope) {
+	other := newEnvelope(t, paymentOp(t, otherID))
+	other.V1.Tx.SourceAccount = muxedAccount(t, otherID)
+	received := newEnvelope(t, paymentOp(t, receiverID))
+
+	// Soroban and classic phases of a generalized transaction set
+	components := []stellarxdr.TxSetComponent{{TxsMaybeDiscountedFee: &stellarxdr.TxSetComponentTxsMaybeDiscountedFee{
+		Txs: []stellarxdr.TransactionEnvelope{received},
+	}}}
+	transactions := checkpointFile(t,
+		stellarxdr.TransactionHistoryEntry{
+			LedgerSeq: 100,
+			Ext: stellarxdr.TransactionHistoryEntryExt{
+				V: 1,
+				GeneralizedTxSet: &stellarxdr.GeneralizedTransactionSet{
+					V: 1,
+					V1TxSet: &stellarxdr.TransactionSetV1{Phases: []stellarxdr.TransactionPhase{
+						{V: 0, V0Components: &components},
+						{V: 1, ParallelTxsComponent: &stellarxdr.ParallelTxsComponent{
+							ExecutionStages: [][][]stellarxdr.TransactionEnvelope{{{other}}},
+						}},
+					}},
+				},
+			},
+		},
+		// Ledgers of other accounts are not decoded
+		[]byte("unknown XDR of other accounts"),
+	)
+
+	// Results are in the apply order
+	results := checkpointFile(t,
+		stellarxdr.TransactionHistoryResultEntry{
+			LedgerSeq: 100,
+			TxResultSet: stellarxdr.TransactionResultSet{Results: []stellarxdr.TransactionResultPair{
+				newResult(t, other, stellarxdr.TransactionResultCodeTxSuccess),
+				newResult(t, received, stellarxdr.TransactionResultCodeTxSuccess),
+			}},
+		},
+		append([]byte{0, 0, 0, 101}, "unknown XDR"...),
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/stellar-history.json":
+			w.Write([]byte(`{"version": 1, "currentLedger": 127}`))
+		case "/transactions/00/00/00/transactions-0000007f.xdr.gz":
+			w.Write(transactions)
+		case "/results/00/00/00/results-0000007f.xdr.gz":
+			w.Write(results)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return server, received
+}
+
+// streamCheckpoint streams payments until the stream waits for the next
+// checkpoint
+func streamCheckpoint(t *testing.T, s *ArchiveStream, cursor string) (payments []horizon.PaymentResponse) {
+	waiting := make(chan bool)
+	s.sleep = func(time.Duration) {
+		waiting <- true
+		select {}
+	}
+
+	go s.StreamPayments(receiverID, &cursor, func(payment horizon.PaymentResponse) error {
+		payments = append(payments, payment)
+		return nil
+	})
+	<-waiting
+	return
+}
+
+func TestArchiveStreamPayments(t *testing.T) {
+	server, received := newArchive(t)
+	defer server.Close()
+
+	s := NewArchiveStream(server.URL+"/", http.DefaultClient, testPassphrase, time.Second)
+	// Cursor in the first ledger of the checkpoint
+	payments := streamCheckpoint(t, s, "274877906944")
+	require.Len(t, payments, 1)
+
+	payment := payments[0]
+	// Second transaction applied in ledger 100
+	assert.Equal(t, "429496737793", payment.ID)
+	assert.Equal(t, "payment", payment.Type)
+	assert.Equal(t, senderID, payment.From)
+	assert.Equal(t, receiverID, payment.To)
+	assert.Equal(t, "12.3000000", payment.Amount)
+	assert.Equal(t, "route", payment.Memo.Value)
+	hash, err := received.Hash(testPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, hexString(hash), payment.TransactionID)
+	assert.False(t, s.StreamUpdatedAt().IsZero())
+
+	// Payments before the cursor are skipped
+	assert.Empty(t, streamCheckpoint(t, s, payment.PagingToken))
+	assert.Len(t, streamCheckpoint(t, s, "429496737792"), 1)
+
+	// Streaming starts after the last checkpoint
+	assert.Empty(t, streamCheckpoint(t, s, "now"))
+}
+
+func TestArchiveStreamLoadMemo(t *testing.T) {
+	server, received := newArchive(t)
+	defer server.Close()
+
+	hash, err := received.Hash(testPassphrase)
+	require.NoError(t, err)
+
+	s := NewArchiveStream(server.URL, http.DefaultClient, testPassphrase, time.Second)
+	payment := horizon.PaymentResponse{ID: "429496737793", TransactionID: hexString(hash)}
+	require.NoError(t, s.LoadMemo(&payment))
+	assert.Equal(t, "text", payment.Memo.Type)
+	assert.Equal(t, "route", payment.Memo.Value)
+
+	payment = horizon.PaymentResponse{ID: "429496737793", TransactionID: "abc"}
+	assert.Error(t, s.LoadMemo(&payment))
+
+	// Checkpoint not published
+	payment = horizon.PaymentResponse{ID: "1099511627777", TransactionID: hexString(hash)}
+	assert.Error(t, s.LoadMemo(&payment))
+}
+
+func TestCheckpointLedger(t *testing.T) {
+	assert.Equal(t, uint32(63), checkpointLedger(1))
+	assert.Equal(t, uint32(63), checkpointLedger(63))
+	assert.Equal(t, uint32(127), checkpointLedger(64))
+}
+
+func hexString(hash xdr.Hash) string {
+	return hex.EncodeToString(hash[:])
+}
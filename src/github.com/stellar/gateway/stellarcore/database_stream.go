@@ -0,0 +1,285 @@
+package stellarcore
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	// ingestBatchSize is the max number of transactions loaded in one query
+	ingestBatchSize = 1000
+	// handlerRetryDelay is a delay between onPaymentHandler retries
+	handlerRetryDelay = 10 * time.Second
+)
+
+// DatabaseStream streams payments by reading ledger metadata (txhistory
+// table) from the postgres database of a stellar-core node. It requires a
+// stellar-core node with its own database, captive core used by Horizon does
+// not keep ledger metadata in a database. Transactions are processed in the
+// exact ledger-close order and Horizon is not used to stream them.
+type DatabaseStream struct {
+	DB *sqlx.DB
+	// PollInterval is how often the database is checked for new ledgers
+	PollInterval time.Duration
+
+	log *logrus.Entry
+	// sleep is used to wait between polls, replaced in tests
+	sleep func(time.Duration)
+
+	mutex sync.Mutex
+	// updatedAt is the time of the last successful poll
+	updatedAt time.Time
+}
+
+// txRow is a single row of txhistory table
+type txRow struct {
+	Ledger   uint32 `db:"ledgerseq"`
+	Index    uint32 `db:"txindex"`
+	ID       string `db:"txid"`
+	TxBody   string `db:"txbody"`
+	TxResult string `db:"txresult"`
+}
+
+// NewDatabaseStream connects to stellar-core postgres database
+func NewDatabaseStream(databaseURL string, pollInterval time.Duration) (*DatabaseStream, error) {
+	database, err := sqlx.Connect("postgres", databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &DatabaseStream{
+		DB:           database,
+		PollInterval: pollInterval,
+		log: logrus.WithFields(logrus.Fields{
+			"service": "DatabaseStream",
+		}),
+		sleep: time.Sleep,
+	}, nil
+}
+
+// StreamPayments sends payments received by accountID in successful
+// transactions to onPaymentHandler. Cursor is a paging token in the same
+// format as Horizon paging tokens so the bridge can switch between backends.
+// It never returns unless the cursor is invalid.
+func (s *DatabaseStream) StreamPayments(accountID string, cursor *string, onPaymentHandler horizon.PaymentHandler) error {
+	var ledger, index, operation uint32
+	var err error
+
+	if cursor == nil || *cursor == "now" {
+		err = s.DB.Get(&ledger, "SELECT COALESCE(MAX(ledgerseq), 0) FROM ledgerheaders")
+		if err != nil {
+			return errors.Wrap(err, "Error loading last ledger")
+		}
+		index = 1 << 20
+	} else {
+		ledger, index, operation, err = parsePagingToken(*cursor)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		var rows []txRow
+		err = s.DB.Select(
+			&rows,
+			`SELECT ledgerseq, txindex, txid, txbody, txresult FROM txhistory
+			WHERE ledgerseq > $1 OR (ledgerseq = $1 AND txindex >= $2)
+			ORDER BY ledgerseq, txindex LIMIT $3`,
+			ledger, index, ingestBatchSize,
+		)
+		if err != nil {
+			s.log.WithFields(logrus.Fields{"err": err}).Error("Error loading transactions")
+			s.sleep(s.PollInterval)
+			continue
+		}
+		s.setUpdatedAt(time.Now())
+
+		for _, row := range rows {
+			payments, err := paymentsFromRow(row, accountID)
+			if err != nil {
+				// Only transactions of the account are decoded, skipping
+				// them would lose payments
+				s.log.WithFields(logrus.Fields{"err": err, "hash": row.ID}).Error("Error decoding transaction")
+				return err
+			}
+
+			for _, payment := range payments {
+				// Skip operations processed before the cursor was saved
+				if row.Ledger == ledger && row.Index == index && operationIndex(payment.PagingToken) <= operation {
+					continue
+				}
+
+				for {
+					err = onPaymentHandler(payment)
+					if err != nil {
+						s.log.Error("Error from onPaymentHandler: ", err)
+						s.log.Info("Sleeping...")
+						s.sleep(handlerRetryDelay)
+					} else {
+						break
+					}
+				}
+			}
+
+			ledger, index, operation = row.Ledger, row.Index+1, 0
+		}
+
+		if len(rows) < ingestBatchSize {
+			s.sleep(s.PollInterval)
+		}
+	}
+}
+
+// StreamUpdatedAt returns the time of the last successful poll of
+// stellar-core database or zero time before the first one
+func (s *DatabaseStream) StreamUpdatedAt() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.updatedAt
+}
+
+func (s *DatabaseStream) setUpdatedAt(t time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.updatedAt = t
+}
+
+// LoadMemo loads the memo of the payment transaction from stellar-core
+// database. Payments streamed by DatabaseStream already contain the memo.
+func (s *DatabaseStream) LoadMemo(p *horizon.PaymentResponse) error {
+	if p.Memo.Type != "" {
+		return nil
+	}
+
+	tx, err := s.loadTransaction(p.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	p.Memo.Type, p.Memo.Value = memoValue(tx.Envelope.Transaction().Memo)
+	return nil
+}
+
+// LoadAccountMergeAmount loads `account_merge` operation amount from the
+// operation result. Payments streamed by DatabaseStream already contain it.
+func (s *DatabaseStream) LoadAccountMergeAmount(p *horizon.PaymentResponse) error {
+	if p.Type != "account_merge" {
+		return errors.New("Not `account_merge` operation")
+	}
+
+	if p.Amount != "" {
+		return nil
+	}
+
+	id, err := strconv.ParseUint(p.ID, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "Invalid operation ID")
+	}
+
+	tx, err := s.loadTransaction(p.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	results, _ := tx.Result.Results()
+	balance, ok := mergedBalance(results, int(id&0xfff)-1)
+	if !ok {
+		return errors.New("Operation result not found")
+	}
+	p.Amount = amounts.String(int64(balance))
+	return nil
+}
+
+// LoadLiquidityPools loads IDs of liquidity pools a path payment was routed
+// through from the operation result
+func (s *DatabaseStream) LoadLiquidityPools(p *horizon.PaymentResponse) error {
+	if !p.IsPathPayment() {
+		return errors.New("Not a path payment operation")
+	}
+
+	tx, err := s.loadTransaction(p.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	results, _ := tx.Result.Results()
+	p.LiquidityPoolIDs = liquidityPools(results, int(operationIndex(p.ID))-1)
+	return nil
+}
+
+func (s *DatabaseStream) loadTransaction(hash string) (tx transaction, err error) {
+	var row txRow
+	err = s.DB.Get(&row, "SELECT ledgerseq, txindex, txid, txbody, txresult FROM txhistory WHERE txid = $1", hash)
+	if err == sql.ErrNoRows {
+		err = errors.New("Transaction not found in stellar-core database")
+		return
+	} else if err != nil {
+		return
+	}
+
+	envelope, result, err := row.decodeBase64()
+	if err != nil {
+		return
+	}
+	return decodeTransaction(row.Ledger, row.Index, envelope, result)
+}
+
+// decodeBase64 returns XDR of the envelope and the result pair of the row
+func (row txRow) decodeBase64() (envelope, result []byte, err error) {
+	envelope, err = base64.StdEncoding.DecodeString(row.TxBody)
+	if err != nil {
+		return
+	}
+	result, err = base64.StdEncoding.DecodeString(row.TxResult)
+	return
+}
+
+// paymentsFromRow returns payments of a txhistory row sent to or from
+// accountID. Rows of transactions that don't contain the account are skipped
+// without decoding them.
+func paymentsFromRow(row txRow, accountID string) ([]horizon.PaymentResponse, error) {
+	envelope, result, err := row.decodeBase64()
+	if err != nil {
+		return nil, err
+	}
+
+	if !touchesAccount(envelope, accountID) {
+		return nil, nil
+	}
+
+	tx, err := decodeTransaction(row.Ledger, row.Index, envelope, result)
+	if err != nil {
+		return nil, err
+	}
+	return paymentsFromTransaction(tx, accountID)
+}
+
+// pagingToken returns operation ID used by Horizon: 32 bits of ledger
+// sequence, 20 bits of transaction index (starting from 1) and 12 bits of
+// operation index (starting from 1).
+func pagingToken(ledger, index, operation uint32) int64 {
+	return int64(ledger)<<32 | int64(index)<<12 | int64(operation)
+}
+
+func operationIndex(token string) uint32 {
+	_, _, operation, _ := parsePagingToken(token)
+	return operation
+}
+
+func parsePagingToken(token string) (ledger, index, operation uint32, err error) {
+	id, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || id < 0 {
+		err = fmt.Errorf("Invalid cursor: %s", token)
+		return
+	}
+	return uint32(id >> 32), uint32(id>>12) & 0xfffff, uint32(id) & 0xfff, nil
+}
@@ -0,0 +1,218 @@
+package stellarcore
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	senderID   = "GCOGCYU77DLEVYCXDQM7F32M5PCKES6VU3Z5GURF6U6OA5LFOVTRYPOX"
+	receiverID = "GAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQUR2"
+	otherID    = "GBWJES3WOKK7PRLJKZVGIPVFGQSSGCRMY7H3GCZ7BEG6ZTDB4FZXTPJ5"
+)
+
+func muxedAccount(t *testing.T, address string) stellarxdr.MuxedAccount {
+	var aid xdr.AccountId
+	require.NoError(t, aid.SetAddress(address))
+	return stellarxdr.MuxedAccount{Type: stellarxdr.CryptoKeyTypeEd25519, Ed25519: aid.Ed25519}
+}
+
+func newEnvelope(t *testing.T, operations ...stellarxdr.Operation) stellarxdr.TransactionEnvelope {
+	text := "route"
+	return stellarxdr.TransactionEnvelope{
+		Type: stellarxdr.EnvelopeTypeTx,
+		V1: &stellarxdr.TransactionV1Envelope{
+			Tx: stellarxdr.Transaction{
+				SourceAccount: muxedAccount(t, senderID),
+				Fee:           100,
+				SeqNum:        1,
+				Cond:          stellarxdr.Preconditions{Type: stellarxdr.PreconditionTypeNone},
+				Memo:          xdr.Memo{Type: xdr.MemoTypeMemoText, Text: &text},
+				Operations:    operations,
+			},
+		},
+	}
+}
+
+// newResult returns the result pair of the envelope, results of operations
+// are all successful payments unless set in results
+func newResult(t *testing.T, envelope stellarxdr.TransactionEnvelope, code stellarxdr.TransactionResultCode, results ...stellarxdr.OperationResult) stellarxdr.TransactionResultPair {
+	success := int32(0)
+	for i := len(results); i < len(envelope.Transaction().Operations); i++ {
+		results = append(results, stellarxdr.OperationResult{
+			Tr: &stellarxdr.OperationResultTr{Type: stellarxdr.OperationTypePayment, Code: &success},
+		})
+	}
+
+	hash, err := envelope.Hash("Test SDF Network ; September 2015")
+	require.NoError(t, err)
+	return stellarxdr.TransactionResultPair{
+		TransactionHash: hash,
+		Result: stellarxdr.TransactionResult{
+			FeeCharged: 100,
+			Result:     stellarxdr.TransactionResultResult{Code: code, Results: &results},
+		},
+	}
+}
+
+func newTxRow(t *testing.T, envelope stellarxdr.TransactionEnvelope, result stellarxdr.TransactionResultPair) txRow {
+	txBody, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	txResult, err := xdr.MarshalBase64(result)
+	require.NoError(t, err)
+
+	return txRow{Ledger: 100, Index: 2, ID: "abc", TxBody: txBody, TxResult: txResult}
+}
+
+func paymentOp(t *testing.T, destination string) stellarxdr.Operation {
+	var asset xdr.Asset
+	require.NoError(t, asset.SetNative())
+	return stellarxdr.Operation{
+		Body: stellarxdr.OperationBody{
+			Type: stellarxdr.OperationTypePayment,
+			PaymentOp: &stellarxdr.PaymentOp{
+				Destination: muxedAccount(t, destination),
+				Asset:       asset,
+				Amount:      123000000,
+			},
+		},
+	}
+}
+
+func TestPaymentsFromRow(t *testing.T) {
+	envelope := newEnvelope(t, paymentOp(t, senderID), paymentOp(t, receiverID))
+	row := newTxRow(t, envelope, newResult(t, envelope, stellarxdr.TransactionResultCodeTxSuccess))
+
+	payments, err := paymentsFromRow(row, receiverID)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+
+	payment := payments[0]
+	assert.Equal(t, "429496737794", payment.ID)
+	assert.Equal(t, payment.ID, payment.PagingToken)
+	assert.Equal(t, "payment", payment.Type)
+	assert.Equal(t, senderID, payment.From)
+	assert.Equal(t, receiverID, payment.To)
+	assert.Empty(t, payment.ToMuxed)
+	assert.Equal(t, "native", payment.AssetType)
+	assert.Equal(t, "12.3000000", payment.Amount)
+	assert.Equal(t, "text", payment.Memo.Type)
+	assert.Equal(t, "route", payment.Memo.Value)
+	hash, err := envelope.Hash("Test SDF Network ; September 2015")
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(hash[:]), payment.TransactionID)
+}
+
+func TestPaymentsFromFailedTransaction(t *testing.T) {
+	envelope := newEnvelope(t, paymentOp(t, receiverID))
+	row := newTxRow(t, envelope, newResult(t, envelope, stellarxdr.TransactionResultCodeTxFailed))
+
+	payments, err := paymentsFromRow(row, receiverID)
+	require.NoError(t, err)
+	assert.Empty(t, payments)
+}
+
+func TestPaymentsFromFeeBumpTransaction(t *testing.T) {
+	// Muxed destination of a path payment in a fee bump transaction
+	var destination xdr.AccountId
+	require.NoError(t, destination.SetAddress(receiverID))
+	var asset xdr.Asset
+	require.NoError(t, asset.SetNative())
+	inner := newEnvelope(t, stellarxdr.Operation{
+		Body: stellarxdr.OperationBody{
+			Type: stellarxdr.OperationTypePathPaymentStrictSend,
+			PathPaymentStrictSendOp: &stellarxdr.PathPaymentStrictSendOp{
+				SendAsset:  asset,
+				SendAmount: 100000000,
+				Destination: stellarxdr.MuxedAccount{
+					Type:     stellarxdr.CryptoKeyTypeMuxedEd25519,
+					Med25519: &stellarxdr.MuxedAccountMed25519{Id: 42, Ed25519: *destination.Ed25519},
+				},
+				DestAsset: asset,
+				DestMin:   1,
+			},
+		},
+	})
+	envelope := stellarxdr.TransactionEnvelope{
+		Type: stellarxdr.EnvelopeTypeTxFeeBump,
+		FeeBump: &stellarxdr.FeeBumpTransactionEnvelope{
+			Tx: stellarxdr.FeeBumpTransaction{
+				FeeSource: muxedAccount(t, otherID),
+				Fee:       400,
+				InnerTx:   stellarxdr.FeeBumpTransactionInnerTx{Type: stellarxdr.EnvelopeTypeTx, V1: inner.V1},
+			},
+		},
+	}
+
+	success := stellarxdr.PathPaymentResult{
+		Success: &stellarxdr.PathPaymentResultSuccess{
+			Last: stellarxdr.SimplePaymentResult{Destination: destination, Asset: asset, Amount: 99000000},
+		},
+	}
+	innerResult := newResult(t, inner, stellarxdr.TransactionResultCodeTxSuccess, stellarxdr.OperationResult{
+		Tr: &stellarxdr.OperationResultTr{
+			Type:                        stellarxdr.OperationTypePathPaymentStrictSend,
+			PathPaymentStrictSendResult: &success,
+		},
+	})
+	result := newResult(t, envelope, stellarxdr.TransactionResultCodeTxFeeBumpInnerSuccess)
+	result.Result.Result = stellarxdr.TransactionResultResult{
+		Code: stellarxdr.TransactionResultCodeTxFeeBumpInnerSuccess,
+		InnerResultPair: &stellarxdr.InnerTransactionResultPair{
+			TransactionHash: innerResult.TransactionHash,
+			Result: stellarxdr.InnerTransactionResult{
+				FeeCharged: 100,
+				Result: stellarxdr.InnerTransactionResultResult{
+					Code:    stellarxdr.TransactionResultCodeTxSuccess,
+					Results: innerResult.Result.Result.Results,
+				},
+			},
+		},
+	}
+
+	payments, err := paymentsFromRow(newTxRow(t, envelope, result), receiverID)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+
+	payment := payments[0]
+	assert.Equal(t, "path_payment_strict_send", payment.Type)
+	assert.Equal(t, senderID, payment.From)
+	assert.Equal(t, receiverID, payment.To)
+	assert.Equal(t, "MAJBUSUTGTS3MAU2KP6MWJFJACDN4ZJ5YCET23U6XYZZ7WUD2OYQQAAAAAAAAAAAFI5RU", payment.ToMuxed)
+	assert.Equal(t, "42", payment.ToMuxedID)
+	// Amount received from the result, not the minimum
+	assert.Equal(t, "9.9000000", payment.Amount)
+	assert.Equal(t, "route", payment.Memo.Value)
+}
+
+func TestPaymentsFromRowOfOtherAccount(t *testing.T) {
+	envelope := newEnvelope(t, paymentOp(t, otherID))
+	row := newTxRow(t, envelope, newResult(t, envelope, stellarxdr.TransactionResultCodeTxSuccess))
+
+	// Transactions of other accounts are skipped without decoding them
+	row.TxResult = base64.StdEncoding.EncodeToString([]byte("unknown"))
+	payments, err := paymentsFromRow(row, receiverID)
+	require.NoError(t, err)
+	assert.Empty(t, payments)
+
+	_, err = paymentsFromRow(row, otherID)
+	assert.Error(t, err)
+}
+
+func TestPagingToken(t *testing.T) {
+	ledger, index, operation, err := parsePagingToken("429496737794")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(100), ledger)
+	assert.Equal(t, uint32(2), index)
+	assert.Equal(t, uint32(2), operation)
+	assert.Equal(t, int64(429496737794), pagingToken(ledger, index, operation))
+
+	_, _, _, err = parsePagingToken("now")
+	assert.Error(t, err)
+}
@@ -0,0 +1,214 @@
+// Package stellarcore submits transactions directly to stellar-core HTTP port
+// instead of Horizon and streams received payments from stellar-core database
+// or history archives. Account details are still loaded from Horizon.
+package stellarcore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/net"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/xdr"
+)
+
+// Statuses returned by stellar-core `tx` command
+const (
+	StatusPending       = "PENDING"
+	StatusDuplicate     = "DUPLICATE"
+	StatusError         = "ERROR"
+	StatusTryAgainLater = "TRY_AGAIN_LATER"
+)
+
+var (
+	// ErrTryAgainLater is returned when stellar-core cannot accept a transaction at the moment
+	ErrTryAgainLater = errors.New("stellar-core responded with TRY_AGAIN_LATER")
+	// ErrNotIncluded is returned when a transaction accepted by stellar-core
+	// has not been included in a ledger before Client.Timeout. The
+	// transaction can be still included later.
+	ErrNotIncluded = errors.New("Transaction has not been included in a ledger")
+)
+
+// Client submits transactions to stellar-core and waits for their inclusion
+// in a ledger using Watcher
+type Client struct {
+	// URL of stellar-core HTTP port, ex. http://localhost:11626
+	URL               string
+	HTTP              net.HTTPClientInterface
+	Watcher           Watcher
+	NetworkPassphrase string
+	// PollInterval is how often Watcher is checked for the submitted transaction
+	PollInterval time.Duration
+	// Timeout is the max time to wait for inclusion of the submitted transaction
+	Timeout time.Duration
+
+	log *logrus.Entry
+	// sleep is used to wait between Watcher checks, replaced in tests
+	sleep func(time.Duration)
+}
+
+// TxResponse is a response of stellar-core `tx` command
+type TxResponse struct {
+	Status string `json:"status"`
+	// Error is a base64 encoded xdr.TransactionResult, only when Status is ERROR
+	Error string `json:"error"`
+}
+
+// NewClient creates a new Client
+func NewClient(coreURL string, httpClient net.HTTPClientInterface, watcher Watcher, networkPassphrase string, pollInterval, timeout time.Duration) *Client {
+	return &Client{
+		URL:               coreURL,
+		HTTP:              httpClient,
+		Watcher:           watcher,
+		NetworkPassphrase: networkPassphrase,
+		PollInterval:      pollInterval,
+		Timeout:           timeout,
+		log: logrus.WithFields(logrus.Fields{
+			"service": "StellarCore",
+		}),
+		sleep: time.Sleep,
+	}
+}
+
+// SubmitTransaction submits a transaction to stellar-core and waits until it's
+// included in a ledger. The result is returned in the same format as Horizon
+// responses so it can be handled like a transaction submitted to Horizon.
+func (c *Client) SubmitTransaction(txeBase64 string) (response horizon.SubmitTransactionResponse, err error) {
+	var envelope stellarxdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(txeBase64, &envelope)
+	if err != nil {
+		return
+	}
+
+	hash, err := envelope.Hash(c.NetworkPassphrase)
+	if err != nil {
+		return
+	}
+	response.Hash = hex.EncodeToString(hash[:])
+
+	resp, err := c.HTTP.Get(c.URL + "/tx?blob=" + url.QueryEscape(txeBase64))
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	var txResponse TxResponse
+	err = json.NewDecoder(resp.Body).Decode(&txResponse)
+	if err != nil {
+		return
+	}
+
+	c.log.WithFields(logrus.Fields{"hash": response.Hash, "status": txResponse.Status}).Info("Transaction submitted to stellar-core")
+
+	switch txResponse.Status {
+	case StatusError:
+		response.Extras = &horizon.SubmitTransactionResponseExtras{
+			EnvelopeXdr: txeBase64,
+			ResultXdr:   txResponse.Error,
+		}
+		return
+	case StatusTryAgainLater:
+		err = ErrTryAgainLater
+		return
+	case StatusPending, StatusDuplicate:
+		return c.waitForInclusion(response, txeBase64)
+	default:
+		err = fmt.Errorf("Unknown stellar-core tx status: %s", txResponse.Status)
+		return
+	}
+}
+
+func (c *Client) waitForInclusion(response horizon.SubmitTransactionResponse, txeBase64 string) (horizon.SubmitTransactionResponse, error) {
+	deadline := time.Now().Add(c.Timeout)
+
+	for {
+		result, found, err := c.Watcher.Result(response.Hash)
+		if err != nil {
+			c.log.WithFields(logrus.Fields{"hash": response.Hash, "err": err}).Warn("Error checking transaction inclusion")
+		} else if found {
+			if result.Success {
+				response.Ledger = &result.Ledger
+				response.ResultXdr = &result.ResultXdr
+			} else {
+				response.Extras = &horizon.SubmitTransactionResponseExtras{
+					EnvelopeXdr: txeBase64,
+					ResultXdr:   result.ResultXdr,
+				}
+			}
+			return response, nil
+		}
+
+		if time.Now().After(deadline) {
+			return response, ErrNotIncluded
+		}
+		c.sleep(c.PollInterval)
+	}
+}
+
+// Accounts loads sequence numbers of accounts from stellar-core database or
+// soroban-rpc
+type Accounts interface {
+	// SequenceNumber returns the sequence number of the account in the last
+	// ledger closed by stellar-core, found is false when the account does not
+	// exist
+	SequenceNumber(accountID string) (sequence uint64, found bool, err error)
+}
+
+// Horizon is a horizon.HorizonInterface that submits transactions to
+// stellar-core and uses Horizon for all other requests, so Horizon is still
+// required with stellar-core submission backend. Sequence numbers of
+// loaded accounts are taken from Accounts because Horizon ingests ledgers
+// closed by stellar-core with a delay, so its sequence numbers can be stale.
+type Horizon struct {
+	horizon.HorizonInterface
+	Core     *Client
+	Accounts Accounts
+}
+
+// LoadAccount loads an account from Horizon with the sequence number from
+// stellar-core
+func (h *Horizon) LoadAccount(accountID string) (horizon.AccountResponse, error) {
+	response, err := h.HorizonInterface.LoadAccount(accountID)
+	if err != nil {
+		return response, err
+	}
+	return h.withSequenceNumber(accountID, response)
+}
+
+// LoadAccountFresh loads an account from Horizon bypassing caches with the
+// sequence number from stellar-core
+func (h *Horizon) LoadAccountFresh(accountID string) (horizon.AccountResponse, error) {
+	response, err := h.HorizonInterface.LoadAccountFresh(accountID)
+	if err != nil {
+		return response, err
+	}
+	return h.withSequenceNumber(accountID, response)
+}
+
+func (h *Horizon) withSequenceNumber(accountID string, response horizon.AccountResponse) (horizon.AccountResponse, error) {
+	sequence, found, err := h.Accounts.SequenceNumber(accountID)
+	if err != nil {
+		return response, fmt.Errorf("Error loading sequence number from stellar-core: %s", err)
+	}
+
+	// Account can be missing only when it has been merged in a ledger not
+	// ingested by Horizon yet
+	if found {
+		response.SequenceNumber = strconv.FormatUint(sequence, 10)
+	}
+	return response, nil
+}
+
+// SubmitTransaction submits a transaction to stellar-core
+func (h *Horizon) SubmitTransaction(txeBase64 string) (horizon.SubmitTransactionResponse, error) {
+	return h.Core.SubmitTransaction(txeBase64)
+}
+
+var _ horizon.HorizonInterface = &Horizon{}
@@ -0,0 +1,134 @@
+package stellarcore
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/net"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	txB64  = "AAAAAJbmB/pwwloZXCaCr9WR3Fue2lNhHGaDWKVOWO7MPq4QAAAAZAAk2eQAAAABAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAAd2/WGgaQ6CJcXQtGRFodrubQZ9ci5ZPRlxpqNPWV1CAAAAAAAAAAADuaygAAAAAAAAAAAcw+rhAAAABAyFjIMIZOtstCWtZlVBDj1AhTmsk5v1i2GGY4by2b5mgZoXXGgFTB8sfbQav0LzFKCcxY8h+9xPMT2e9xznAfDw=="
+	txHash = "4f885999be6ea7891052a53e496bcfb5c5a1a5bfb31923f649b028fdc74dd050"
+)
+
+// testWatcher finds transaction after a given number of checks
+type testWatcher struct {
+	checks int
+	after  int
+	result Result
+}
+
+func (w *testWatcher) Result(hash string) (Result, bool, error) {
+	w.checks++
+	if w.checks < w.after {
+		return Result{}, false, nil
+	}
+	return w.result, true, nil
+}
+
+func newTestClient(response string, watcher Watcher) (*Client, *mocks.MockHTTPClient) {
+	mockHTTPClient := new(mocks.MockHTTPClient)
+	mockHTTPClient.On("Get", "http://core/tx?blob="+url.QueryEscape(txB64)).
+		Return(net.BuildHTTPResponse(200, response), nil).Once()
+
+	client := NewClient("http://core", mockHTTPClient, watcher, "Test SDF Network ; September 2015", time.Second, 10*time.Second)
+	client.sleep = func(time.Duration) {}
+	return client, mockHTTPClient
+}
+
+func TestSubmitTransactionError(t *testing.T) {
+	client, mockHTTPClient := newTestClient(`{"status": "ERROR", "error": "AAAAAAAAAAD////7AAAAAA=="}`, nil)
+
+	response, err := client.SubmitTransaction(txB64)
+	require.NoError(t, err)
+	mockHTTPClient.AssertExpectations(t)
+	assert.Equal(t, txHash, response.Hash)
+	assert.Nil(t, response.Ledger)
+	assert.Equal(t, "AAAAAAAAAAD////7AAAAAA==", response.Extras.ResultXdr)
+	assert.Equal(t, "transaction_bad_seq", response.ErrorCode())
+}
+
+func TestSubmitTransactionTryAgainLater(t *testing.T) {
+	client, _ := newTestClient(`{"status": "TRY_AGAIN_LATER"}`, nil)
+
+	_, err := client.SubmitTransaction(txB64)
+	assert.Equal(t, ErrTryAgainLater, err)
+}
+
+func TestSubmitTransactionIncluded(t *testing.T) {
+	watcher := &testWatcher{after: 3, result: Result{Ledger: 100, Success: true, ResultXdr: "AAAAAAAAAGQAAAAAAAAAAQAAAAAAAAABAAAAAAAAAAA="}}
+	client, _ := newTestClient(`{"status": "PENDING"}`, watcher)
+
+	response, err := client.SubmitTransaction(txB64)
+	require.NoError(t, err)
+	assert.Equal(t, 3, watcher.checks)
+	assert.Equal(t, uint64(100), *response.Ledger)
+	assert.Equal(t, "AAAAAAAAAGQAAAAAAAAAAQAAAAAAAAABAAAAAAAAAAA=", *response.ResultXdr)
+	assert.Nil(t, response.Extras)
+}
+
+func TestSubmitTransactionFailedInLedger(t *testing.T) {
+	watcher := &testWatcher{result: Result{Ledger: 100, ResultXdr: "AAAAAAAAAGT/////AAAAAQAAAAAAAAAB////+wAAAAA="}}
+	client, _ := newTestClient(`{"status": "DUPLICATE"}`, watcher)
+
+	response, err := client.SubmitTransaction(txB64)
+	require.NoError(t, err)
+	assert.Nil(t, response.Ledger)
+	assert.Equal(t, "payment_no_destination", response.ErrorCode())
+}
+
+func TestSubmitTransactionNotIncluded(t *testing.T) {
+	watcher := &testWatcher{after: 1000000}
+	client, _ := newTestClient(`{"status": "PENDING"}`, watcher)
+	client.Timeout = 0
+
+	_, err := client.SubmitTransaction(txB64)
+	assert.Equal(t, ErrNotIncluded, err)
+}
+
+type testAccounts map[string]uint64
+
+func (a testAccounts) SequenceNumber(accountID string) (uint64, bool, error) {
+	if accountID == "error" {
+		return 0, false, errors.New("connection refused")
+	}
+	sequence, found := a[accountID]
+	return sequence, found, nil
+}
+
+func TestHorizonLoadAccount(t *testing.T) {
+	mockHorizon := new(mocks.MockHorizon)
+	h := &Horizon{
+		HorizonInterface: mockHorizon,
+		Accounts:         testAccounts{"GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ": 105},
+	}
+
+	// Horizon has not ingested the last ledger yet
+	mockHorizon.On("LoadAccount", "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ").Return(
+		horizon.AccountResponse{AccountID: "GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ", SequenceNumber: "100"}, nil,
+	).Once()
+	account, err := h.LoadAccount("GAHA6GRCLCCN7XE2NEEUDSIVOFBOQ6GLSYXVLYCJXJKLPMDR5XB5XZZJ")
+	require.NoError(t, err)
+	assert.Equal(t, "105", account.SequenceNumber)
+
+	mockHorizon.On("LoadAccountFresh", "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632").Return(
+		horizon.AccountResponse{AccountID: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632", SequenceNumber: "7"}, nil,
+	).Once()
+	account, err = h.LoadAccountFresh("GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632")
+	require.NoError(t, err)
+	assert.Equal(t, "7", account.SequenceNumber)
+
+	// Stale sequence number from Horizon is not used when stellar-core
+	// database is not available
+	mockHorizon.On("LoadAccount", "error").Return(horizon.AccountResponse{SequenceNumber: "100"}, nil).Once()
+	_, err = h.LoadAccount("error")
+	assert.EqualError(t, err, "Error loading sequence number from stellar-core: connection refused")
+	mockHorizon.AssertExpectations(t)
+}
@@ -0,0 +1,96 @@
+package stellarcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+
+	"github.com/stellar/gateway/sorobanrpc"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// RPCWatcher finds submitted transactions (getTransaction) and sequence
+// numbers of accounts (getLedgerEntries) in soroban-rpc server. soroban-rpc
+// runs its own captive core and serves classic transactions too, so it can be
+// used with stellar-core versions that don't populate txhistory and accounts
+// tables.
+type RPCWatcher struct {
+	RPC *sorobanrpc.Client
+}
+
+// accountEntryPrefix are the leading fields of LedgerEntryData of an account.
+// Extensions of AccountEntry added in later protocols are not decoded.
+type accountEntryPrefix struct {
+	Type      stellarxdr.LedgerEntryType
+	AccountID xdr.AccountId
+	Balance   xdr.Int64
+	SeqNum    xdr.SequenceNumber
+}
+
+// NewRPCWatcher creates a new RPCWatcher
+func NewRPCWatcher(rpc *sorobanrpc.Client) *RPCWatcher {
+	return &RPCWatcher{RPC: rpc}
+}
+
+// Result implements Watcher
+func (w *RPCWatcher) Result(hash string) (result Result, found bool, err error) {
+	response, err := w.RPC.GetTransaction(context.Background(), hash)
+	if err != nil {
+		return
+	}
+
+	switch response.Status {
+	case sorobanrpc.TransactionStatusNotFound:
+		return result, false, nil
+	case sorobanrpc.TransactionStatusSuccess, sorobanrpc.TransactionStatusFailed:
+		result.Ledger = uint64(response.Ledger)
+		result.Success = response.Status == sorobanrpc.TransactionStatusSuccess
+		result.ResultXdr = response.ResultXdr
+		return result, true, nil
+	default:
+		return result, false, errors.Errorf("Unknown soroban-rpc transaction status: %s", response.Status)
+	}
+}
+
+// SequenceNumber implements Accounts
+func (w *RPCWatcher) SequenceNumber(accountID string) (sequence uint64, found bool, err error) {
+	var aid xdr.AccountId
+	err = aid.SetAddress(accountID)
+	if err != nil {
+		return
+	}
+	var key xdr.LedgerKey
+	err = key.SetAccount(aid)
+	if err != nil {
+		return
+	}
+	keyBase64, err := xdr.MarshalBase64(key)
+	if err != nil {
+		return
+	}
+
+	response, err := w.RPC.GetLedgerEntries(context.Background(), []string{keyBase64})
+	if err != nil {
+		return
+	}
+	if len(response.Entries) == 0 {
+		return 0, false, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(response.Entries[0].XDR)
+	if err != nil {
+		return
+	}
+
+	var entry accountEntryPrefix
+	_, err = xdr.Unmarshal(bytes.NewReader(raw), &entry)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error decoding account entry")
+	}
+	if entry.Type != stellarxdr.LedgerEntryTypeAccount {
+		return 0, false, errors.New("soroban-rpc returned a ledger entry of another type")
+	}
+	return uint64(entry.SeqNum), true, nil
+}
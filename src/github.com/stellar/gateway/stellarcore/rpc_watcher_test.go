@@ -0,0 +1,94 @@
+package stellarcore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/gateway/sorobanrpc"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRPCWatcher(t *testing.T, results map[string]interface{}) (*RPCWatcher, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		result, ok := results[body.Method+string(body.Params)]
+		require.True(t, ok, "Unexpected request %s %s", body.Method, body.Params)
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	return NewRPCWatcher(sorobanrpc.New(server.URL, http.DefaultClient)), server
+}
+
+func TestRPCWatcherResult(t *testing.T) {
+	watcher, server := newRPCWatcher(t, map[string]interface{}{
+		`getTransaction{"hash":"a"}`: map[string]interface{}{"status": "SUCCESS", "ledger": 100, "resultXdr": "AAAAAAAAAGQAAAAAAAAAAQAAAAAAAAABAAAAAAAAAAA="},
+		`getTransaction{"hash":"b"}`: map[string]interface{}{"status": "FAILED", "ledger": 101, "resultXdr": "AAAAAAAAAGT/////AAAAAQAAAAAAAAAB////+wAAAAA="},
+		`getTransaction{"hash":"c"}`: map[string]interface{}{"status": "NOT_FOUND", "latestLedger": 101},
+	})
+	defer server.Close()
+
+	result, found, err := watcher.Result("a")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Result{Ledger: 100, Success: true, ResultXdr: "AAAAAAAAAGQAAAAAAAAAAQAAAAAAAAABAAAAAAAAAAA="}, result)
+
+	result, found, err = watcher.Result("b")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Result{Ledger: 101, ResultXdr: "AAAAAAAAAGT/////AAAAAQAAAAAAAAAB////+wAAAAA="}, result)
+
+	_, found, err = watcher.Result("c")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRPCWatcherSequenceNumber(t *testing.T) {
+	var aid xdr.AccountId
+	require.NoError(t, aid.SetAddress(senderID))
+	var key xdr.LedgerKey
+	require.NoError(t, key.SetAccount(aid))
+	keyBase64, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+
+	// Extensions of the entry added in later protocols are not decoded
+	var entry bytes.Buffer
+	_, err = xdr.Marshal(&entry, accountEntryPrefix{Type: stellarxdr.LedgerEntryTypeAccount, AccountID: aid, Balance: 1000, SeqNum: 4242})
+	require.NoError(t, err)
+	entry.WriteString("extensions")
+
+	watcher, server := newRPCWatcher(t, map[string]interface{}{
+		`getLedgerEntries{"keys":["` + keyBase64 + `"]}`: map[string]interface{}{
+			"entries": []map[string]interface{}{{"key": keyBase64, "xdr": base64.StdEncoding.EncodeToString(entry.Bytes())}},
+		},
+	})
+	defer server.Close()
+
+	sequence, found, err := watcher.SequenceNumber(senderID)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(4242), sequence)
+
+	require.NoError(t, aid.SetAddress(receiverID))
+	require.NoError(t, key.SetAccount(aid))
+	keyBase64, err = xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	watcher, server = newRPCWatcher(t, map[string]interface{}{
+		`getLedgerEntries{"keys":["` + keyBase64 + `"]}`: map[string]interface{}{"entries": nil, "latestLedger": 100},
+	})
+	defer server.Close()
+
+	_, found, err = watcher.SequenceNumber(receiverID)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,225 @@
+package stellarcore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/protocols/amounts"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// transaction is a transaction included in a ledger, read from stellar-core
+// database or a history archive. Envelope and Result are decoded with the
+// current XDR (stellarxdr) so V1 and fee bump envelopes are supported.
+type transaction struct {
+	Ledger uint32
+	// Index is the position of the transaction in the ledger apply order,
+	// starting from 1
+	Index    uint32
+	ID       string
+	Envelope stellarxdr.TransactionEnvelope
+	Result   stellarxdr.TransactionResult
+}
+
+// touchesAccount returns true when the XDR encoded envelope contains the key
+// of accountID. It's used to skip transactions without decoding them: all
+// payments streamed (payments and path payments sent or received by the
+// account, including muxed destinations, and merges into it) contain the key
+// in the envelope.
+func touchesAccount(envelope []byte, accountID string) bool {
+	var aid xdr.AccountId
+	err := aid.SetAddress(accountID)
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(envelope, aid.Ed25519[:])
+}
+
+// paymentsFromTransaction returns payment operations of a successful
+// transaction sent to or from accountID, in the format returned by Horizon
+// payments stream.
+func paymentsFromTransaction(tx transaction, accountID string) (payments []horizon.PaymentResponse, err error) {
+	// Horizon streams payments of successful transactions only
+	results, ok := tx.Result.Results()
+	if !ok {
+		return
+	}
+
+	inner := tx.Envelope.Transaction()
+	memoType, memo := memoValue(inner.Memo)
+
+	for i, op := range inner.Operations {
+		source := inner.SourceAccount
+		if op.SourceAccount != nil {
+			source = *op.SourceAccount
+		}
+
+		payment := horizon.PaymentResponse{
+			From:          source.Address(),
+			TransactionID: tx.ID,
+		}
+
+		var destination stellarxdr.MuxedAccount
+		switch op.Body.Type {
+		case stellarxdr.OperationTypePayment:
+			body := op.Body.PaymentOp
+			payment.Type = "payment"
+			destination = body.Destination
+			payment.Amount = amounts.String(int64(body.Amount))
+			err = body.Asset.Extract(&payment.AssetType, &payment.AssetCode, &payment.AssetIssuer)
+		case stellarxdr.OperationTypePathPaymentStrictReceive:
+			body := op.Body.PathPaymentStrictReceiveOp
+			payment.Type = "path_payment_strict_receive"
+			destination = body.Destination
+			payment.Amount = amounts.String(int64(body.DestAmount))
+			err = body.DestAsset.Extract(&payment.AssetType, &payment.AssetCode, &payment.AssetIssuer)
+		case stellarxdr.OperationTypePathPaymentStrictSend:
+			body := op.Body.PathPaymentStrictSendOp
+			payment.Type = "path_payment_strict_send"
+			destination = body.Destination
+			// The received amount is known from the result only
+			amount, ok := pathPaymentAmount(results, i)
+			if !ok {
+				return nil, errors.New("Path payment result not found")
+			}
+			payment.Amount = amounts.String(int64(amount))
+			err = body.DestAsset.Extract(&payment.AssetType, &payment.AssetCode, &payment.AssetIssuer)
+		case stellarxdr.OperationTypeAccountMerge:
+			payment.Type = "account_merge"
+			payment.Account = payment.From
+			payment.Into = op.Body.Destination.Address()
+			payment.AssetType = "native"
+			if balance, ok := mergedBalance(results, i); ok {
+				payment.Amount = amounts.String(int64(balance))
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return
+		}
+
+		if payment.Type != "account_merge" {
+			payment.To = destination.Address()
+			if address, id, ok := destination.MuxedAddress(); ok {
+				payment.ToMuxed, payment.ToMuxedID = address, strconv.FormatUint(id, 10)
+			}
+		}
+
+		if payment.From != accountID && payment.To != accountID && payment.Into != accountID {
+			continue
+		}
+
+		payment.ID = strconv.FormatInt(pagingToken(tx.Ledger, tx.Index, uint32(i)+1), 10)
+		payment.PagingToken = payment.ID
+		payment.Memo.Type = memoType
+		payment.Memo.Value = memo
+		payments = append(payments, payment)
+	}
+
+	return
+}
+
+// decodeTransaction decodes XDR encoded envelope and TransactionResultPair of
+// a transaction
+func decodeTransaction(ledger, index uint32, envelope []byte, result []byte) (tx transaction, err error) {
+	tx.Ledger, tx.Index = ledger, index
+
+	err = xdr.SafeUnmarshal(envelope, &tx.Envelope)
+	if err != nil {
+		return tx, errors.Wrap(err, "Error decoding transaction envelope")
+	}
+
+	var pair stellarxdr.TransactionResultPair
+	err = xdr.SafeUnmarshal(result, &pair)
+	if err != nil {
+		return tx, errors.Wrap(err, "Error decoding transaction result")
+	}
+	tx.ID = hex.EncodeToString(pair.TransactionHash[:])
+	tx.Result = pair.Result
+	return
+}
+
+// operationResult returns the result of i-th operation
+func operationResult(results []stellarxdr.OperationResult, i int) *stellarxdr.OperationResultTr {
+	if i < 0 || i >= len(results) {
+		return nil
+	}
+	return results[i].Tr
+}
+
+// mergedBalance returns the balance of account merged in i-th operation
+func mergedBalance(results []stellarxdr.OperationResult, i int) (balance xdr.Int64, ok bool) {
+	tr := operationResult(results, i)
+	if tr == nil || tr.AccountMergeResult == nil || tr.AccountMergeResult.SourceAccountBalance == nil {
+		return
+	}
+	return *tr.AccountMergeResult.SourceAccountBalance, true
+}
+
+// pathPaymentResult returns the successful result of a path payment in i-th
+// operation
+func pathPaymentResult(results []stellarxdr.OperationResult, i int) *stellarxdr.PathPaymentResultSuccess {
+	tr := operationResult(results, i)
+	if tr == nil {
+		return nil
+	}
+
+	result := tr.PathPaymentStrictSendResult
+	if result == nil {
+		result = tr.PathPaymentStrictReceiveResult
+	}
+	if result == nil {
+		return nil
+	}
+	return result.Success
+}
+
+// pathPaymentAmount returns the amount received by the destination of a path
+// payment in i-th operation
+func pathPaymentAmount(results []stellarxdr.OperationResult, i int) (amount xdr.Int64, ok bool) {
+	result := pathPaymentResult(results, i)
+	if result == nil {
+		return
+	}
+	return result.Last.Amount, true
+}
+
+// liquidityPools returns hex encoded IDs of liquidity pools a path payment
+// in i-th operation was routed through
+func liquidityPools(results []stellarxdr.OperationResult, i int) (ids []string) {
+	result := pathPaymentResult(results, i)
+	if result == nil {
+		return
+	}
+
+	for _, atom := range result.Offers {
+		if atom.LiquidityPool != nil {
+			ids = append(ids, hex.EncodeToString(atom.LiquidityPool.LiquidityPoolId[:]))
+		}
+	}
+	return
+}
+
+// memoValue returns memo type and value in the format used by Horizon
+func memoValue(memo xdr.Memo) (memoType, value string) {
+	switch memo.Type {
+	case xdr.MemoTypeMemoText:
+		return "text", memo.MustText()
+	case xdr.MemoTypeMemoId:
+		return "id", strconv.FormatUint(uint64(memo.MustId()), 10)
+	case xdr.MemoTypeMemoHash:
+		hash := memo.MustHash()
+		return "hash", base64.StdEncoding.EncodeToString(hash[:])
+	case xdr.MemoTypeMemoReturn:
+		hash := memo.MustRetHash()
+		return "return", base64.StdEncoding.EncodeToString(hash[:])
+	default:
+		return "none", ""
+	}
+}
@@ -0,0 +1,85 @@
+package stellarcore
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	// postgres driver
+	_ "github.com/lib/pq"
+	"github.com/stellar/gateway/stellarxdr"
+	"github.com/stellar/go/xdr"
+)
+
+// Result is a result of transaction included in a ledger
+type Result struct {
+	Ledger  uint64
+	Success bool
+	// ResultXdr is a base64 encoded xdr.TransactionResult
+	ResultXdr string
+}
+
+// Watcher checks if transactions have been included in a ledger
+type Watcher interface {
+	// Result returns result of transaction with a given hash, found is false
+	// when the transaction has not been included in a ledger (yet)
+	Result(hash string) (result Result, found bool, err error)
+}
+
+// DatabaseWatcher reads ledger metadata (txhistory table) and sequence numbers
+// of accounts (accounts table) from stellar-core database. Recent stellar-core
+// versions can keep ledger entries outside of the database and be configured
+// not to populate txhistory, use RPCWatcher with them.
+type DatabaseWatcher struct {
+	DB *sqlx.DB
+}
+
+// NewDatabaseWatcher connects to stellar-core postgres database
+func NewDatabaseWatcher(databaseURL string) (*DatabaseWatcher, error) {
+	database, err := sqlx.Connect("postgres", databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &DatabaseWatcher{DB: database}, nil
+}
+
+// Result implements Watcher
+func (w *DatabaseWatcher) Result(hash string) (result Result, found bool, err error) {
+	var row struct {
+		Ledger   uint64 `db:"ledgerseq"`
+		TxResult string `db:"txresult"`
+	}
+
+	err = w.DB.Get(&row, "SELECT ledgerseq, txresult FROM txhistory WHERE txid = $1", hash)
+	if err == sql.ErrNoRows {
+		return result, false, nil
+	} else if err != nil {
+		return
+	}
+
+	// txresult column contains TransactionResultPair, decoded with the
+	// current XDR so results of fee bump transactions are supported
+	var pair stellarxdr.TransactionResultPair
+	err = xdr.SafeUnmarshalBase64(row.TxResult, &pair)
+	if err != nil {
+		return
+	}
+
+	result.Ledger = row.Ledger
+	_, result.Success = pair.Result.Results()
+	result.ResultXdr, err = xdr.MarshalBase64(pair.Result)
+	if err != nil {
+		return
+	}
+	return result, true, nil
+}
+
+// SequenceNumber implements Accounts
+func (w *DatabaseWatcher) SequenceNumber(accountID string) (sequence uint64, found bool, err error) {
+	err = w.DB.Get(&sequence, "SELECT seqnum FROM accounts WHERE accountid = $1", accountID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	} else if err != nil {
+		return
+	}
+	return sequence, true, nil
+}
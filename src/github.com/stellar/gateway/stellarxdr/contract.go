@@ -0,0 +1,622 @@
+package stellarxdr
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// ScValType is SCV_* of Stellar-contract.x
+type ScValType int32
+
+// SCVal types
+const (
+	ScValTypeScvBool                      ScValType = 0
+	ScValTypeScvVoid                      ScValType = 1
+	ScValTypeScvError                     ScValType = 2
+	ScValTypeScvU32                       ScValType = 3
+	ScValTypeScvI32                       ScValType = 4
+	ScValTypeScvU64                       ScValType = 5
+	ScValTypeScvI64                       ScValType = 6
+	ScValTypeScvTimepoint                 ScValType = 7
+	ScValTypeScvDuration                  ScValType = 8
+	ScValTypeScvU128                      ScValType = 9
+	ScValTypeScvI128                      ScValType = 10
+	ScValTypeScvU256                      ScValType = 11
+	ScValTypeScvI256                      ScValType = 12
+	ScValTypeScvBytes                     ScValType = 13
+	ScValTypeScvString                    ScValType = 14
+	ScValTypeScvSymbol                    ScValType = 15
+	ScValTypeScvVec                       ScValType = 16
+	ScValTypeScvMap                       ScValType = 17
+	ScValTypeScvAddress                   ScValType = 18
+	ScValTypeScvContractInstance          ScValType = 19
+	ScValTypeScvLedgerKeyContractInstance ScValType = 20
+	ScValTypeScvLedgerKeyNonce            ScValType = 21
+)
+
+// ScVal is a value of a contract: arguments, return values, storage keys
+// and values and event topics
+type ScVal struct {
+	Type      ScValType
+	B         *bool
+	Error     *ScError
+	U32       *xdr.Uint32
+	I32       *xdr.Int32
+	U64       *xdr.Uint64
+	I64       *xdr.Int64
+	Timepoint *xdr.Uint64
+	Duration  *xdr.Uint64
+	U128      *UInt128Parts
+	I128      *Int128Parts
+	U256      *UInt256Parts
+	I256      *Int256Parts
+	Bytes     *[]byte
+	Str       *string
+	Sym       *string
+	Vec       **[]ScVal
+	Map       **[]ScMapEntry
+	Address   *ScAddress
+	Instance  *ScContractInstance
+	NonceKey  *ScNonceKey
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ScVal) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ScVal
+func (u ScVal) ArmForSwitch(sw int32) (string, bool) {
+	switch ScValType(sw) {
+	case ScValTypeScvBool:
+		return "B", true
+	case ScValTypeScvVoid, ScValTypeScvLedgerKeyContractInstance:
+		return "", true
+	case ScValTypeScvError:
+		return "Error", true
+	case ScValTypeScvU32:
+		return "U32", true
+	case ScValTypeScvI32:
+		return "I32", true
+	case ScValTypeScvU64:
+		return "U64", true
+	case ScValTypeScvI64:
+		return "I64", true
+	case ScValTypeScvTimepoint:
+		return "Timepoint", true
+	case ScValTypeScvDuration:
+		return "Duration", true
+	case ScValTypeScvU128:
+		return "U128", true
+	case ScValTypeScvI128:
+		return "I128", true
+	case ScValTypeScvU256:
+		return "U256", true
+	case ScValTypeScvI256:
+		return "I256", true
+	case ScValTypeScvBytes:
+		return "Bytes", true
+	case ScValTypeScvString:
+		return "Str", true
+	case ScValTypeScvSymbol:
+		return "Sym", true
+	case ScValTypeScvVec:
+		return "Vec", true
+	case ScValTypeScvMap:
+		return "Map", true
+	case ScValTypeScvAddress:
+		return "Address", true
+	case ScValTypeScvContractInstance:
+		return "Instance", true
+	case ScValTypeScvLedgerKeyNonce:
+		return "NonceKey", true
+	}
+	return "-", false
+}
+
+// ScMapEntry is an entry of SCV_MAP values
+type ScMapEntry struct {
+	Key ScVal
+	Val ScVal
+}
+
+// UInt128Parts is an unsigned 128-bit integer
+type UInt128Parts struct {
+	Hi xdr.Uint64
+	Lo xdr.Uint64
+}
+
+// Int128Parts is a signed 128-bit integer, ex. amounts of token contracts
+type Int128Parts struct {
+	Hi xdr.Int64
+	Lo xdr.Uint64
+}
+
+// UInt256Parts is an unsigned 256-bit integer
+type UInt256Parts struct {
+	HiHi xdr.Uint64
+	HiLo xdr.Uint64
+	LoHi xdr.Uint64
+	LoLo xdr.Uint64
+}
+
+// Int256Parts is a signed 256-bit integer
+type Int256Parts struct {
+	HiHi xdr.Int64
+	HiLo xdr.Uint64
+	LoHi xdr.Uint64
+	LoLo xdr.Uint64
+}
+
+// ScError is an error of a contract (SCE_CONTRACT) or of the host. All arms
+// are 32-bit codes.
+type ScError struct {
+	Type         int32
+	ContractCode *xdr.Uint32
+	Code         *int32
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ScError) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ScError
+func (u ScError) ArmForSwitch(sw int32) (string, bool) {
+	switch {
+	case sw == 0:
+		return "ContractCode", true
+	case sw >= 1 && sw <= 9:
+		return "Code", true
+	}
+	return "-", false
+}
+
+// ScNonceKey is a key of nonces of address credentials
+type ScNonceKey struct {
+	Nonce xdr.Int64
+}
+
+// ScAddressType is SC_ADDRESS_TYPE_* of Stellar-contract.x
+type ScAddressType int32
+
+// SCAddress types
+const (
+	ScAddressTypeAccount          ScAddressType = 0
+	ScAddressTypeContract         ScAddressType = 1
+	ScAddressTypeMuxedAccount     ScAddressType = 2
+	ScAddressTypeClaimableBalance ScAddressType = 3
+	ScAddressTypeLiquidityPool    ScAddressType = 4
+)
+
+// ScAddress is an address of an account or a contract
+type ScAddress struct {
+	Type               ScAddressType
+	AccountId          *xdr.AccountId
+	ContractId         *xdr.Hash
+	MuxedAccount       *MuxedAccountMed25519
+	ClaimableBalanceId *ClaimableBalanceId
+	LiquidityPoolId    *xdr.PoolId
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ScAddress) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ScAddress
+func (u ScAddress) ArmForSwitch(sw int32) (string, bool) {
+	switch ScAddressType(sw) {
+	case ScAddressTypeAccount:
+		return "AccountId", true
+	case ScAddressTypeContract:
+		return "ContractId", true
+	case ScAddressTypeMuxedAccount:
+		return "MuxedAccount", true
+	case ScAddressTypeClaimableBalance:
+		return "ClaimableBalanceId", true
+	case ScAddressTypeLiquidityPool:
+		return "LiquidityPoolId", true
+	}
+	return "-", false
+}
+
+// ContractExecutable is the code of a contract: uploaded Wasm or the
+// built-in Stellar Asset Contract
+type ContractExecutable struct {
+	Type     int32
+	WasmHash *xdr.Hash
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ContractExecutable) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ContractExecutable
+func (u ContractExecutable) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "WasmHash", true
+	case 1:
+		return "", true
+	}
+	return "-", false
+}
+
+// ScContractInstance is the value of SCV_CONTRACT_INSTANCE
+type ScContractInstance struct {
+	Executable ContractExecutable
+	Storage    *[]ScMapEntry
+}
+
+// ContractIdPreimage derives an ID of a created contract
+type ContractIdPreimage struct {
+	Type        int32
+	FromAddress *ContractIdPreimageFromAddress
+	FromAsset   *xdr.Asset
+}
+
+// ContractIdPreimageFromAddress is CONTRACT_ID_PREIMAGE_FROM_ADDRESS arm of
+// ContractIdPreimage
+type ContractIdPreimageFromAddress struct {
+	Address ScAddress
+	Salt    xdr.Uint256
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ContractIdPreimage) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ContractIdPreimage
+func (u ContractIdPreimage) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "FromAddress", true
+	case 1:
+		return "FromAsset", true
+	}
+	return "-", false
+}
+
+// InvokeContractArgs calls function of a contract
+type InvokeContractArgs struct {
+	ContractAddress ScAddress
+	FunctionName    string
+	Args            []ScVal
+}
+
+// CreateContractArgs creates a contract
+type CreateContractArgs struct {
+	ContractIdPreimage ContractIdPreimage
+	Executable         ContractExecutable
+}
+
+// CreateContractArgsV2 creates a contract calling its constructor
+type CreateContractArgsV2 struct {
+	ContractIdPreimage ContractIdPreimage
+	Executable         ContractExecutable
+	ConstructorArgs    []ScVal
+}
+
+// HostFunctionType is HOST_FUNCTION_TYPE_* of Stellar-transaction.x
+type HostFunctionType int32
+
+// Host function types
+const (
+	HostFunctionTypeInvokeContract     HostFunctionType = 0
+	HostFunctionTypeCreateContract     HostFunctionType = 1
+	HostFunctionTypeUploadContractWasm HostFunctionType = 2
+	HostFunctionTypeCreateContractV2   HostFunctionType = 3
+)
+
+// HostFunction is the function run by invoke_host_function
+type HostFunction struct {
+	Type             HostFunctionType
+	InvokeContract   *InvokeContractArgs
+	CreateContract   *CreateContractArgs
+	Wasm             *[]byte
+	CreateContractV2 *CreateContractArgsV2
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u HostFunction) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of HostFunction
+func (u HostFunction) ArmForSwitch(sw int32) (string, bool) {
+	switch HostFunctionType(sw) {
+	case HostFunctionTypeInvokeContract:
+		return "InvokeContract", true
+	case HostFunctionTypeCreateContract:
+		return "CreateContract", true
+	case HostFunctionTypeUploadContractWasm:
+		return "Wasm", true
+	case HostFunctionTypeCreateContractV2:
+		return "CreateContractV2", true
+	}
+	return "-", false
+}
+
+// SorobanAuthorizedFunction is a function authorized by an address
+type SorobanAuthorizedFunction struct {
+	Type                   int32
+	ContractFn             *InvokeContractArgs
+	CreateContractHostFn   *CreateContractArgs
+	CreateContractV2HostFn *CreateContractArgsV2
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u SorobanAuthorizedFunction) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of SorobanAuthorizedFunction
+func (u SorobanAuthorizedFunction) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "ContractFn", true
+	case 1:
+		return "CreateContractHostFn", true
+	case 2:
+		return "CreateContractV2HostFn", true
+	}
+	return "-", false
+}
+
+// SorobanAuthorizedInvocation is a tree of invocations authorized by an
+// address
+type SorobanAuthorizedInvocation struct {
+	Function       SorobanAuthorizedFunction
+	SubInvocations []SorobanAuthorizedInvocation
+}
+
+// SorobanAddressCredentials are credentials of an address signing the
+// authorization entry
+type SorobanAddressCredentials struct {
+	Address                   ScAddress
+	Nonce                     xdr.Int64
+	SignatureExpirationLedger xdr.Uint32
+	Signature                 ScVal
+}
+
+// SorobanCredentialsType is SOROBAN_CREDENTIALS_* of Stellar-transaction.x
+type SorobanCredentialsType int32
+
+// Soroban credentials types
+const (
+	SorobanCredentialsTypeSourceAccount SorobanCredentialsType = 0
+	SorobanCredentialsTypeAddress       SorobanCredentialsType = 1
+)
+
+// SorobanCredentials of an authorization entry. SOROBAN_CREDENTIALS_SOURCE_ACCOUNT
+// entries are authorized by the transaction signatures.
+type SorobanCredentials struct {
+	Type    SorobanCredentialsType
+	Address *SorobanAddressCredentials
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u SorobanCredentials) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of SorobanCredentials
+func (u SorobanCredentials) ArmForSwitch(sw int32) (string, bool) {
+	switch SorobanCredentialsType(sw) {
+	case SorobanCredentialsTypeSourceAccount:
+		return "", true
+	case SorobanCredentialsTypeAddress:
+		return "Address", true
+	}
+	return "-", false
+}
+
+// SorobanAuthorizationEntry authorizes invocations of contracts by an
+// address
+type SorobanAuthorizationEntry struct {
+	Credentials    SorobanCredentials
+	RootInvocation SorobanAuthorizedInvocation
+}
+
+// TrustLineAsset is the asset of a trust line: an asset or pool shares
+type TrustLineAsset struct {
+	Type            xdr.AssetType
+	AlphaNum4       *xdr.AssetAlphaNum4
+	AlphaNum12      *xdr.AssetAlphaNum12
+	LiquidityPoolId *xdr.PoolId
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u TrustLineAsset) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of TrustLineAsset
+func (u TrustLineAsset) ArmForSwitch(sw int32) (string, bool) {
+	switch xdr.AssetType(sw) {
+	case xdr.AssetTypeAssetTypeNative:
+		return "", true
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		return "AlphaNum4", true
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		return "AlphaNum12", true
+	case xdr.AssetTypeAssetTypePoolShare:
+		return "LiquidityPoolId", true
+	}
+	return "-", false
+}
+
+// LedgerEntryType is the type of a ledger entry, values of the vendored
+// xdr.LedgerEntryType and entries added since then
+type LedgerEntryType int32
+
+// Ledger entry types
+const (
+	LedgerEntryTypeAccount          LedgerEntryType = 0
+	LedgerEntryTypeTrustline        LedgerEntryType = 1
+	LedgerEntryTypeOffer            LedgerEntryType = 2
+	LedgerEntryTypeData             LedgerEntryType = 3
+	LedgerEntryTypeClaimableBalance LedgerEntryType = 4
+	LedgerEntryTypeLiquidityPool    LedgerEntryType = 5
+	LedgerEntryTypeContractData     LedgerEntryType = 6
+	LedgerEntryTypeContractCode     LedgerEntryType = 7
+	LedgerEntryTypeConfigSetting    LedgerEntryType = 8
+	LedgerEntryTypeTtl              LedgerEntryType = 9
+)
+
+// LedgerKey identifies a ledger entry, ex. in footprints of Soroban
+// transactions
+type LedgerKey struct {
+	Type             LedgerEntryType
+	Account          *xdr.LedgerKeyAccount
+	TrustLine        *LedgerKeyTrustLine
+	Offer            *xdr.LedgerKeyOffer
+	Data             *xdr.LedgerKeyData
+	ClaimableBalance *LedgerKeyClaimableBalance
+	LiquidityPool    *LedgerKeyLiquidityPool
+	ContractData     *LedgerKeyContractData
+	ContractCode     *LedgerKeyContractCode
+	ConfigSetting    *int32
+	Ttl              *LedgerKeyTtl
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u LedgerKey) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of LedgerKey
+func (u LedgerKey) ArmForSwitch(sw int32) (string, bool) {
+	switch LedgerEntryType(sw) {
+	case LedgerEntryTypeAccount:
+		return "Account", true
+	case LedgerEntryTypeTrustline:
+		return "TrustLine", true
+	case LedgerEntryTypeOffer:
+		return "Offer", true
+	case LedgerEntryTypeData:
+		return "Data", true
+	case LedgerEntryTypeClaimableBalance:
+		return "ClaimableBalance", true
+	case LedgerEntryTypeLiquidityPool:
+		return "LiquidityPool", true
+	case LedgerEntryTypeContractData:
+		return "ContractData", true
+	case LedgerEntryTypeContractCode:
+		return "ContractCode", true
+	case LedgerEntryTypeConfigSetting:
+		return "ConfigSetting", true
+	case LedgerEntryTypeTtl:
+		return "Ttl", true
+	}
+	return "-", false
+}
+
+// LedgerKeyTrustLine is the key of a trust line
+type LedgerKeyTrustLine struct {
+	AccountId xdr.AccountId
+	Asset     TrustLineAsset
+}
+
+// LedgerKeyClaimableBalance is the key of a claimable balance
+type LedgerKeyClaimableBalance struct {
+	BalanceId ClaimableBalanceId
+}
+
+// LedgerKeyLiquidityPool is the key of a liquidity pool
+type LedgerKeyLiquidityPool struct {
+	LiquidityPoolId xdr.PoolId
+}
+
+// LedgerKeyContractData is the key of contract storage, Durability is
+// TEMPORARY (0) or PERSISTENT (1)
+type LedgerKeyContractData struct {
+	Contract   ScAddress
+	Key        ScVal
+	Durability int32
+}
+
+// LedgerKeyContractCode is the key of uploaded Wasm
+type LedgerKeyContractCode struct {
+	Hash xdr.Hash
+}
+
+// LedgerKeyTtl is the key of the TTL of a Soroban entry
+type LedgerKeyTtl struct {
+	KeyHash xdr.Hash
+}
+
+// LedgerFootprint are ledger entries read and written by a Soroban
+// transaction
+type LedgerFootprint struct {
+	ReadOnly  []LedgerKey
+	ReadWrite []LedgerKey
+}
+
+// SorobanResources are resources declared by a Soroban transaction
+type SorobanResources struct {
+	Footprint     LedgerFootprint
+	Instructions  xdr.Uint32
+	DiskReadBytes xdr.Uint32
+	WriteBytes    xdr.Uint32
+}
+
+// SorobanResourcesExtV0 lists archived entries restored automatically
+// (protocol 23)
+type SorobanResourcesExtV0 struct {
+	ArchivedSorobanEntries []xdr.Uint32
+}
+
+// SorobanTransactionDataExt is ext of SorobanTransactionData
+type SorobanTransactionDataExt struct {
+	V           int32
+	ResourceExt *SorobanResourcesExtV0
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u SorobanTransactionDataExt) SwitchFieldName() string {
+	return "V"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of SorobanTransactionDataExt
+func (u SorobanTransactionDataExt) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "", true
+	case 1:
+		return "ResourceExt", true
+	}
+	return "-", false
+}
+
+// SorobanTransactionData are resources and the resource fee of a Soroban
+// transaction, returned by simulateTransaction of soroban-rpc
+type SorobanTransactionData struct {
+	Ext         SorobanTransactionDataExt
+	Resources   SorobanResources
+	ResourceFee xdr.Int64
+}
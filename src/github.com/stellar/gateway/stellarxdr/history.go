@@ -0,0 +1,170 @@
+package stellarxdr
+
+import "github.com/stellar/go/xdr"
+
+// TransactionSet is the legacy transaction set of a ledger, empty in ledgers
+// with a generalized transaction set (protocol 20 and later)
+type TransactionSet struct {
+	PreviousLedgerHash xdr.Hash
+	Txs                []TransactionEnvelope
+}
+
+// TxSetComponent is a group of transactions of a transaction phase
+type TxSetComponent struct {
+	Type                  int32
+	TxsMaybeDiscountedFee *TxSetComponentTxsMaybeDiscountedFee
+}
+
+// TxSetComponentTxsMaybeDiscountedFee is TXSET_COMP_TXS_MAYBE_DISCOUNTED_FEE
+// arm of TxSetComponent
+type TxSetComponentTxsMaybeDiscountedFee struct {
+	BaseFee *xdr.Int64
+	Txs     []TransactionEnvelope
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u TxSetComponent) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of TxSetComponent
+func (u TxSetComponent) ArmForSwitch(sw int32) (string, bool) {
+	if sw == 0 {
+		return "TxsMaybeDiscountedFee", true
+	}
+	return "-", false
+}
+
+// ParallelTxsComponent contains Soroban transactions of the parallel
+// phase: stages of clusters of transactions
+type ParallelTxsComponent struct {
+	BaseFee         *xdr.Int64
+	ExecutionStages [][][]TransactionEnvelope
+}
+
+// TransactionPhase contains classic (V0Components) or Soroban transactions
+// of a generalized transaction set
+type TransactionPhase struct {
+	V                    int32
+	V0Components         *[]TxSetComponent
+	ParallelTxsComponent *ParallelTxsComponent
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u TransactionPhase) SwitchFieldName() string {
+	return "V"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of TransactionPhase
+func (u TransactionPhase) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "V0Components", true
+	case 1:
+		return "ParallelTxsComponent", true
+	}
+	return "-", false
+}
+
+// TransactionSetV1 is the v1 arm of GeneralizedTransactionSet
+type TransactionSetV1 struct {
+	PreviousLedgerHash xdr.Hash
+	Phases             []TransactionPhase
+}
+
+// GeneralizedTransactionSet is the transaction set of ledgers since
+// protocol 20
+type GeneralizedTransactionSet struct {
+	V       int32
+	V1TxSet *TransactionSetV1
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u GeneralizedTransactionSet) SwitchFieldName() string {
+	return "V"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of GeneralizedTransactionSet
+func (u GeneralizedTransactionSet) ArmForSwitch(sw int32) (string, bool) {
+	if sw == 1 {
+		return "V1TxSet", true
+	}
+	return "-", false
+}
+
+// TransactionHistoryEntryExt is the extension of TransactionHistoryEntry,
+// v1 contains the generalized transaction set
+type TransactionHistoryEntryExt struct {
+	V                int32
+	GeneralizedTxSet *GeneralizedTransactionSet
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u TransactionHistoryEntryExt) SwitchFieldName() string {
+	return "V"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of TransactionHistoryEntryExt
+func (u TransactionHistoryEntryExt) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "", true
+	case 1:
+		return "GeneralizedTxSet", true
+	}
+	return "-", false
+}
+
+// TransactionHistoryEntry is an entry of transactions files of history
+// archives: transactions of a ledger in no particular order
+type TransactionHistoryEntry struct {
+	LedgerSeq xdr.Uint32
+	TxSet     TransactionSet
+	Ext       TransactionHistoryEntryExt
+}
+
+// Envelopes returns all transactions of the entry
+func (e TransactionHistoryEntry) Envelopes() []TransactionEnvelope {
+	envelopes := e.TxSet.Txs
+	if e.Ext.GeneralizedTxSet == nil {
+		return envelopes
+	}
+
+	for _, phase := range e.Ext.GeneralizedTxSet.V1TxSet.Phases {
+		if phase.V0Components != nil {
+			for _, component := range *phase.V0Components {
+				envelopes = append(envelopes, component.TxsMaybeDiscountedFee.Txs...)
+			}
+		}
+		if phase.ParallelTxsComponent != nil {
+			for _, stage := range phase.ParallelTxsComponent.ExecutionStages {
+				for _, cluster := range stage {
+					envelopes = append(envelopes, cluster...)
+				}
+			}
+		}
+	}
+	return envelopes
+}
+
+// TransactionResultSet contains results of transactions of a ledger in the
+// order they were applied
+type TransactionResultSet struct {
+	Results []TransactionResultPair
+}
+
+// TransactionHistoryResultEntry is an entry of results files of history
+// archives
+type TransactionHistoryResultEntry struct {
+	LedgerSeq   xdr.Uint32
+	TxResultSet TransactionResultSet
+	Ext         ExtensionPoint
+}
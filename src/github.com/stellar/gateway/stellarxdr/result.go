@@ -0,0 +1,424 @@
+package stellarxdr
+
+import "github.com/stellar/go/xdr"
+
+// TransactionResultCode is the result code of a transaction
+type TransactionResultCode int32
+
+// Transaction result codes with results of operations, other codes have no
+// result
+const (
+	TransactionResultCodeTxFeeBumpInnerSuccess TransactionResultCode = 1
+	TransactionResultCodeTxSuccess             TransactionResultCode = 0
+	TransactionResultCodeTxFailed              TransactionResultCode = -1
+	TransactionResultCodeTxFeeBumpInnerFailed  TransactionResultCode = -13
+)
+
+// TransactionResultPair is the hash of a transaction (the fee bump
+// transaction of fee bump envelopes) and its result
+type TransactionResultPair struct {
+	TransactionHash xdr.Hash
+	Result          TransactionResult
+}
+
+// TransactionResult is the result of a transaction
+type TransactionResult struct {
+	FeeCharged xdr.Int64
+	Result     TransactionResultResult
+	Ext        ExtensionPoint
+}
+
+// Results returns results of operations of a successful transaction, the
+// inner transaction of successful fee bump transactions
+func (r TransactionResult) Results() ([]OperationResult, bool) {
+	switch r.Result.Code {
+	case TransactionResultCodeTxSuccess:
+		return *r.Result.Results, true
+	case TransactionResultCodeTxFeeBumpInnerSuccess:
+		inner := r.Result.InnerResultPair.Result
+		if inner.Result.Code == TransactionResultCodeTxSuccess {
+			return *inner.Result.Results, true
+		}
+	}
+	return nil, false
+}
+
+// TransactionResultResult is the result of a transaction: results of
+// operations or the result of the inner transaction of a fee bump
+type TransactionResultResult struct {
+	Code            TransactionResultCode
+	InnerResultPair *InnerTransactionResultPair
+	Results         *[]OperationResult
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u TransactionResultResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of TransactionResultResult
+func (u TransactionResultResult) ArmForSwitch(sw int32) (string, bool) {
+	switch TransactionResultCode(sw) {
+	case TransactionResultCodeTxFeeBumpInnerSuccess, TransactionResultCodeTxFeeBumpInnerFailed:
+		return "InnerResultPair", true
+	case TransactionResultCodeTxSuccess, TransactionResultCodeTxFailed:
+		return "Results", true
+	}
+	return "", true
+}
+
+// InnerTransactionResultPair is the hash and the result of the inner
+// transaction of a fee bump transaction
+type InnerTransactionResultPair struct {
+	TransactionHash xdr.Hash
+	Result          InnerTransactionResult
+}
+
+// InnerTransactionResult is the result of the inner transaction of a fee
+// bump transaction
+type InnerTransactionResult struct {
+	FeeCharged xdr.Int64
+	Result     InnerTransactionResultResult
+	Ext        ExtensionPoint
+}
+
+// InnerTransactionResultResult contains results of operations of successful
+// and failed inner transactions
+type InnerTransactionResultResult struct {
+	Code    TransactionResultCode
+	Results *[]OperationResult
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u InnerTransactionResultResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of InnerTransactionResultResult
+func (u InnerTransactionResultResult) ArmForSwitch(sw int32) (string, bool) {
+	switch TransactionResultCode(sw) {
+	case TransactionResultCodeTxSuccess, TransactionResultCodeTxFailed:
+		return "Results", true
+	}
+	return "", true
+}
+
+// OperationResultCodeOpInner is the code of operations that were applied,
+// other codes have no result
+const OperationResultCodeOpInner int32 = 0
+
+// OperationResult is the result of an operation
+type OperationResult struct {
+	Code int32
+	Tr   *OperationResultTr
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u OperationResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of OperationResult
+func (u OperationResult) ArmForSwitch(sw int32) (string, bool) {
+	if sw == OperationResultCodeOpInner {
+		return "Tr", true
+	}
+	return "", true
+}
+
+// OperationResultTr is the result of an applied operation. Results of
+// operations that carry no data besides the code are in Code.
+type OperationResultTr struct {
+	Type                           OperationType
+	Code                           *int32
+	PathPaymentStrictReceiveResult *PathPaymentResult
+	ManageSellOfferResult          *ManageOfferResult
+	CreatePassiveSellOfferResult   *ManageOfferResult
+	AccountMergeResult             *AccountMergeResult
+	InflationResult                *InflationResult
+	ManageBuyOfferResult           *ManageOfferResult
+	PathPaymentStrictSendResult    *PathPaymentResult
+	CreateClaimableBalanceResult   *CreateClaimableBalanceResult
+	InvokeHostFunctionResult       *InvokeHostFunctionResult
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u OperationResultTr) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of OperationResultTr
+func (u OperationResultTr) ArmForSwitch(sw int32) (string, bool) {
+	switch OperationType(sw) {
+	case OperationTypePathPaymentStrictReceive:
+		return "PathPaymentStrictReceiveResult", true
+	case OperationTypeManageSellOffer:
+		return "ManageSellOfferResult", true
+	case OperationTypeCreatePassiveSellOffer:
+		return "CreatePassiveSellOfferResult", true
+	case OperationTypeAccountMerge:
+		return "AccountMergeResult", true
+	case OperationTypeInflation:
+		return "InflationResult", true
+	case OperationTypeManageBuyOffer:
+		return "ManageBuyOfferResult", true
+	case OperationTypePathPaymentStrictSend:
+		return "PathPaymentStrictSendResult", true
+	case OperationTypeCreateClaimableBalance:
+		return "CreateClaimableBalanceResult", true
+	case OperationTypeInvokeHostFunction:
+		return "InvokeHostFunctionResult", true
+	}
+	if sw >= int32(OperationTypeCreateAccount) && sw <= int32(OperationTypeRestoreFootprint) {
+		return "Code", true
+	}
+	return "-", false
+}
+
+// ClaimAtomType is the type of a trade of a path payment or an offer
+type ClaimAtomType int32
+
+// Claim atom types
+const (
+	ClaimAtomTypeV0            ClaimAtomType = 0
+	ClaimAtomTypeOrderBook     ClaimAtomType = 1
+	ClaimAtomTypeLiquidityPool ClaimAtomType = 2
+)
+
+// ClaimAtom is a trade with an offer or a liquidity pool
+type ClaimAtom struct {
+	Type          ClaimAtomType
+	V0            *ClaimOfferAtomV0
+	OrderBook     *xdr.ClaimOfferAtom
+	LiquidityPool *ClaimLiquidityAtom
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ClaimAtom) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ClaimAtom
+func (u ClaimAtom) ArmForSwitch(sw int32) (string, bool) {
+	switch ClaimAtomType(sw) {
+	case ClaimAtomTypeV0:
+		return "V0", true
+	case ClaimAtomTypeOrderBook:
+		return "OrderBook", true
+	case ClaimAtomTypeLiquidityPool:
+		return "LiquidityPool", true
+	}
+	return "-", false
+}
+
+// ClaimOfferAtomV0 is a trade with an offer of an ed25519 seller
+type ClaimOfferAtomV0 struct {
+	SellerEd25519 xdr.Uint256
+	OfferId       xdr.Int64
+	AssetSold     xdr.Asset
+	AmountSold    xdr.Int64
+	AssetBought   xdr.Asset
+	AmountBought  xdr.Int64
+}
+
+// ClaimLiquidityAtom is a trade with a liquidity pool
+type ClaimLiquidityAtom struct {
+	LiquidityPoolId xdr.PoolId
+	AssetSold       xdr.Asset
+	AmountSold      xdr.Int64
+	AssetBought     xdr.Asset
+	AmountBought    xdr.Int64
+}
+
+// SimplePaymentResult is the payment to the destination of a path payment
+type SimplePaymentResult struct {
+	Destination xdr.AccountId
+	Asset       xdr.Asset
+	Amount      xdr.Int64
+}
+
+// PathPaymentResultCodeNoIssuer is the result code of path payments when an
+// asset of the path has no issuer
+const PathPaymentResultCodeNoIssuer int32 = -9
+
+// PathPaymentResult is the result of path_payment_strict_receive and
+// path_payment_strict_send operations
+type PathPaymentResult struct {
+	Code     int32
+	Success  *PathPaymentResultSuccess
+	NoIssuer *xdr.Asset
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u PathPaymentResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of PathPaymentResult
+func (u PathPaymentResult) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "Success", true
+	case PathPaymentResultCodeNoIssuer:
+		return "NoIssuer", true
+	}
+	return "", true
+}
+
+// PathPaymentResultSuccess contains trades of a path payment and the amount
+// received by the destination
+type PathPaymentResultSuccess struct {
+	Offers []ClaimAtom
+	Last   SimplePaymentResult
+}
+
+// ManageOfferResult is the result of manage_sell_offer, manage_buy_offer
+// and create_passive_sell_offer operations
+type ManageOfferResult struct {
+	Code    int32
+	Success *ManageOfferSuccessResult
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ManageOfferResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ManageOfferResult
+func (u ManageOfferResult) ArmForSwitch(sw int32) (string, bool) {
+	if sw == 0 {
+		return "Success", true
+	}
+	return "", true
+}
+
+// ManageOfferSuccessResult contains trades of an offer and the offer left
+type ManageOfferSuccessResult struct {
+	OffersClaimed []ClaimAtom
+	Offer         ManageOfferSuccessResultOffer
+}
+
+// ManageOfferSuccessResultOffer is the offer created or updated, it's
+// empty when the offer was deleted
+type ManageOfferSuccessResultOffer struct {
+	Effect int32
+	Offer  *xdr.OfferEntry
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ManageOfferSuccessResultOffer) SwitchFieldName() string {
+	return "Effect"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ManageOfferSuccessResultOffer
+func (u ManageOfferSuccessResultOffer) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0, 1:
+		return "Offer", true
+	case 2:
+		return "", true
+	}
+	return "-", false
+}
+
+// AccountMergeResult is the result of account_merge operation
+type AccountMergeResult struct {
+	Code                 int32
+	SourceAccountBalance *xdr.Int64
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u AccountMergeResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of AccountMergeResult
+func (u AccountMergeResult) ArmForSwitch(sw int32) (string, bool) {
+	if sw == 0 {
+		return "SourceAccountBalance", true
+	}
+	return "", true
+}
+
+// InflationResult is the result of inflation operation
+type InflationResult struct {
+	Code    int32
+	Payouts *[]xdr.InflationPayout
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u InflationResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of InflationResult
+func (u InflationResult) ArmForSwitch(sw int32) (string, bool) {
+	if sw == 0 {
+		return "Payouts", true
+	}
+	return "", true
+}
+
+// CreateClaimableBalanceResult is the result of create_claimable_balance
+// operation
+type CreateClaimableBalanceResult struct {
+	Code      int32
+	BalanceId *ClaimableBalanceId
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u CreateClaimableBalanceResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of CreateClaimableBalanceResult
+func (u CreateClaimableBalanceResult) ArmForSwitch(sw int32) (string, bool) {
+	if sw == 0 {
+		return "BalanceId", true
+	}
+	return "", true
+}
+
+// InvokeHostFunctionResult is the result of invoke_host_function operation,
+// the hash of the return value and events of successful invocations
+type InvokeHostFunctionResult struct {
+	Code    int32
+	Success *xdr.Hash
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u InvokeHostFunctionResult) SwitchFieldName() string {
+	return "Code"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of InvokeHostFunctionResult
+func (u InvokeHostFunctionResult) ArmForSwitch(sw int32) (string, bool) {
+	if sw == 0 {
+		return "Success", true
+	}
+	return "", true
+}
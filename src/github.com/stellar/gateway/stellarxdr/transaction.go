@@ -0,0 +1,807 @@
+package stellarxdr
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+
+	"github.com/stellar/go/crc16"
+	"github.com/stellar/go/xdr"
+)
+
+// CryptoKeyType is KEY_TYPE_* of Stellar-types.x, the discriminant of
+// MuxedAccount
+type CryptoKeyType int32
+
+// Key types of MuxedAccount
+const (
+	CryptoKeyTypeEd25519      CryptoKeyType = 0
+	CryptoKeyTypeMuxedEd25519 CryptoKeyType = 0x100
+)
+
+// MuxedAccount is an account with an optional multiplexed ID (M... address)
+type MuxedAccount struct {
+	Type     CryptoKeyType
+	Ed25519  *xdr.Uint256
+	Med25519 *MuxedAccountMed25519
+}
+
+// MuxedAccountMed25519 is the KEY_TYPE_MUXED_ED25519 arm of MuxedAccount
+type MuxedAccountMed25519 struct {
+	Id      xdr.Uint64
+	Ed25519 xdr.Uint256
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u MuxedAccount) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of MuxedAccount
+func (u MuxedAccount) ArmForSwitch(sw int32) (string, bool) {
+	switch CryptoKeyType(sw) {
+	case CryptoKeyTypeEd25519:
+		return "Ed25519", true
+	case CryptoKeyTypeMuxedEd25519:
+		return "Med25519", true
+	}
+	return "-", false
+}
+
+// versionByteMuxedAccount is the strkey version byte of muxed accounts, it's
+// not known to the vendored strkey package
+const versionByteMuxedAccount = 12 << 3 // Base32-encodes to 'M...'
+
+// AccountID returns the account of a muxed account
+func (m MuxedAccount) AccountID() xdr.AccountId {
+	key := m.ed25519()
+	return xdr.AccountId{Type: xdr.PublicKeyTypePublicKeyTypeEd25519, Ed25519: &key}
+}
+
+// Address returns the address (G...) of the account of a muxed account
+func (m MuxedAccount) Address() string {
+	accountID := m.AccountID()
+	return accountID.Address()
+}
+
+// MuxedAddress returns the muxed address (M...) and the ID of
+// KEY_TYPE_MUXED_ED25519 accounts, ok is false for other accounts
+func (m MuxedAccount) MuxedAddress() (address string, id uint64, ok bool) {
+	if m.Type != CryptoKeyTypeMuxedEd25519 {
+		return
+	}
+
+	raw := []byte{versionByteMuxedAccount}
+	raw = append(raw, m.Med25519.Ed25519[:]...)
+	raw = append(raw, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(raw[33:], uint64(m.Med25519.Id))
+	raw = append(raw, crc16.Checksum(raw)...)
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw), uint64(m.Med25519.Id), true
+}
+
+func (m MuxedAccount) ed25519() xdr.Uint256 {
+	if m.Type == CryptoKeyTypeMuxedEd25519 {
+		return m.Med25519.Ed25519
+	}
+	return *m.Ed25519
+}
+
+// SignerKeyType is SIGNER_KEY_TYPE_* of Stellar-types.x
+type SignerKeyType int32
+
+// Signer key types
+const (
+	SignerKeyTypeEd25519              SignerKeyType = 0
+	SignerKeyTypePreAuthTx            SignerKeyType = 1
+	SignerKeyTypeHashX                SignerKeyType = 2
+	SignerKeyTypeEd25519SignedPayload SignerKeyType = 3
+)
+
+// SignerKey is a signer of an account or an extra signer of a transaction
+type SignerKey struct {
+	Type                 SignerKeyType
+	Ed25519              *xdr.Uint256
+	PreAuthTx            *xdr.Uint256
+	HashX                *xdr.Uint256
+	Ed25519SignedPayload *SignerKeyEd25519SignedPayload
+}
+
+// SignerKeyEd25519SignedPayload is the SIGNER_KEY_TYPE_ED25519_SIGNED_PAYLOAD
+// arm of SignerKey
+type SignerKeyEd25519SignedPayload struct {
+	Ed25519 xdr.Uint256
+	Payload []byte
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u SignerKey) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of SignerKey
+func (u SignerKey) ArmForSwitch(sw int32) (string, bool) {
+	switch SignerKeyType(sw) {
+	case SignerKeyTypeEd25519:
+		return "Ed25519", true
+	case SignerKeyTypePreAuthTx:
+		return "PreAuthTx", true
+	case SignerKeyTypeHashX:
+		return "HashX", true
+	case SignerKeyTypeEd25519SignedPayload:
+		return "Ed25519SignedPayload", true
+	}
+	return "-", false
+}
+
+// Signer is a signer added by set_options
+type Signer struct {
+	Key    SignerKey
+	Weight xdr.Uint32
+}
+
+// LedgerBounds limits ledgers a transaction is valid in, MaxLedger 0 means
+// no upper bound
+type LedgerBounds struct {
+	MinLedger xdr.Uint32
+	MaxLedger xdr.Uint32
+}
+
+// PreconditionsV2 are preconditions of transactions added in protocol 19
+type PreconditionsV2 struct {
+	TimeBounds      *xdr.TimeBounds
+	LedgerBounds    *LedgerBounds
+	MinSeqNum       *xdr.SequenceNumber
+	MinSeqAge       xdr.Uint64
+	MinSeqLedgerGap xdr.Uint32
+	ExtraSigners    []SignerKey
+}
+
+// PreconditionType is PRECOND_* of Stellar-transaction.x
+type PreconditionType int32
+
+// Precondition types
+const (
+	PreconditionTypeNone PreconditionType = 0
+	PreconditionTypeTime PreconditionType = 1
+	PreconditionTypeV2   PreconditionType = 2
+)
+
+// Preconditions of a transaction. PRECOND_NONE and PRECOND_TIME encode like
+// optional time bounds of TransactionV0.
+type Preconditions struct {
+	Type       PreconditionType
+	TimeBounds *xdr.TimeBounds
+	V2         *PreconditionsV2
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u Preconditions) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of Preconditions
+func (u Preconditions) ArmForSwitch(sw int32) (string, bool) {
+	switch PreconditionType(sw) {
+	case PreconditionTypeNone:
+		return "", true
+	case PreconditionTypeTime:
+		return "TimeBounds", true
+	case PreconditionTypeV2:
+		return "V2", true
+	}
+	return "-", false
+}
+
+// ExtensionPoint is a union of void arm 0 only, ext fields of structs
+// without extensions
+type ExtensionPoint struct {
+	V int32
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ExtensionPoint) SwitchFieldName() string {
+	return "V"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ExtensionPoint
+func (u ExtensionPoint) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "", true
+	}
+	return "-", false
+}
+
+// TransactionExt is ext of Transaction, Soroban transactions use arm 1
+type TransactionExt struct {
+	V           int32
+	SorobanData *SorobanTransactionData
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u TransactionExt) SwitchFieldName() string {
+	return "V"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of TransactionExt
+func (u TransactionExt) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "", true
+	case 1:
+		return "SorobanData", true
+	}
+	return "-", false
+}
+
+// Transaction is a transaction of ENVELOPE_TYPE_TX envelopes
+type Transaction struct {
+	SourceAccount MuxedAccount
+	Fee           xdr.Uint32
+	SeqNum        xdr.SequenceNumber
+	Cond          Preconditions
+	Memo          xdr.Memo
+	Operations    []Operation
+	Ext           TransactionExt
+}
+
+// TransactionV0 is a transaction of ENVELOPE_TYPE_TX_V0 envelopes, sent by
+// clients before protocol 13. It encodes like xdr.Transaction of the
+// vendored package.
+type TransactionV0 struct {
+	SourceAccountEd25519 xdr.Uint256
+	Fee                  xdr.Uint32
+	SeqNum               xdr.SequenceNumber
+	TimeBounds           *xdr.TimeBounds
+	Memo                 xdr.Memo
+	Operations           []Operation
+	Ext                  ExtensionPoint
+}
+
+// TransactionV0Envelope is the ENVELOPE_TYPE_TX_V0 arm of TransactionEnvelope
+type TransactionV0Envelope struct {
+	Tx         TransactionV0
+	Signatures []xdr.DecoratedSignature
+}
+
+// TransactionV1Envelope is the ENVELOPE_TYPE_TX arm of TransactionEnvelope
+type TransactionV1Envelope struct {
+	Tx         Transaction
+	Signatures []xdr.DecoratedSignature
+}
+
+// FeeBumpTransactionInnerTx is the inner transaction of a fee bump
+// transaction, ENVELOPE_TYPE_TX only
+type FeeBumpTransactionInnerTx struct {
+	Type EnvelopeType
+	V1   *TransactionV1Envelope
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u FeeBumpTransactionInnerTx) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of FeeBumpTransactionInnerTx
+func (u FeeBumpTransactionInnerTx) ArmForSwitch(sw int32) (string, bool) {
+	switch EnvelopeType(sw) {
+	case EnvelopeTypeTx:
+		return "V1", true
+	}
+	return "-", false
+}
+
+// FeeBumpTransaction pays the fee of an inner transaction
+type FeeBumpTransaction struct {
+	FeeSource MuxedAccount
+	Fee       xdr.Int64
+	InnerTx   FeeBumpTransactionInnerTx
+	Ext       ExtensionPoint
+}
+
+// FeeBumpTransactionEnvelope is the ENVELOPE_TYPE_TX_FEE_BUMP arm of
+// TransactionEnvelope
+type FeeBumpTransactionEnvelope struct {
+	Tx         FeeBumpTransaction
+	Signatures []xdr.DecoratedSignature
+}
+
+// TransactionEnvelope is a signed transaction of any envelope type
+type TransactionEnvelope struct {
+	Type    EnvelopeType
+	V0      *TransactionV0Envelope
+	V1      *TransactionV1Envelope
+	FeeBump *FeeBumpTransactionEnvelope
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u TransactionEnvelope) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of TransactionEnvelope
+func (u TransactionEnvelope) ArmForSwitch(sw int32) (string, bool) {
+	switch EnvelopeType(sw) {
+	case EnvelopeTypeTxV0:
+		return "V0", true
+	case EnvelopeTypeTx:
+		return "V1", true
+	case EnvelopeTypeTxFeeBump:
+		return "FeeBump", true
+	}
+	return "-", false
+}
+
+// Hash returns the hash of the transaction (the fee bump transaction of fee
+// bump envelopes) on a network
+func (e TransactionEnvelope) Hash(networkPassphrase string) (xdr.Hash, error) {
+	if e.Type == EnvelopeTypeTxFeeBump {
+		return signaturePayload(networkPassphrase, EnvelopeTypeTxFeeBump, e.FeeBump.Tx)
+	}
+	return e.Transaction().Hash(networkPassphrase)
+}
+
+// Transaction returns the transaction of the envelope, the inner transaction
+// of fee bump envelopes. V0 transactions are returned as ENVELOPE_TYPE_TX
+// transactions of the same source account and time bounds, which is how
+// they are signed.
+func (e TransactionEnvelope) Transaction() Transaction {
+	switch e.Type {
+	case EnvelopeTypeTxV0:
+		tx := e.V0.Tx
+		source := tx.SourceAccountEd25519
+		cond := Preconditions{Type: PreconditionTypeNone}
+		if tx.TimeBounds != nil {
+			cond = Preconditions{Type: PreconditionTypeTime, TimeBounds: tx.TimeBounds}
+		}
+		return Transaction{
+			SourceAccount: MuxedAccount{Type: CryptoKeyTypeEd25519, Ed25519: &source},
+			Fee:           tx.Fee,
+			SeqNum:        tx.SeqNum,
+			Cond:          cond,
+			Memo:          tx.Memo,
+			Operations:    tx.Operations,
+		}
+	case EnvelopeTypeTx:
+		return e.V1.Tx
+	default:
+		return e.FeeBump.Tx.InnerTx.V1.Tx
+	}
+}
+
+// Hash returns the hash of the transaction on a network, signed by its
+// signers
+func (tx Transaction) Hash(networkPassphrase string) (xdr.Hash, error) {
+	return signaturePayload(networkPassphrase, EnvelopeTypeTx, tx)
+}
+
+// OperationType is the type of operation, values of the vendored
+// xdr.OperationType and operations added since then
+type OperationType int32
+
+// Operation types
+const (
+	OperationTypeCreateAccount                 OperationType = 0
+	OperationTypePayment                       OperationType = 1
+	OperationTypePathPaymentStrictReceive      OperationType = 2
+	OperationTypeManageSellOffer               OperationType = 3
+	OperationTypeCreatePassiveSellOffer        OperationType = 4
+	OperationTypeSetOptions                    OperationType = 5
+	OperationTypeChangeTrust                   OperationType = 6
+	OperationTypeAllowTrust                    OperationType = 7
+	OperationTypeAccountMerge                  OperationType = 8
+	OperationTypeInflation                     OperationType = 9
+	OperationTypeManageData                    OperationType = 10
+	OperationTypeBumpSequence                  OperationType = 11
+	OperationTypeManageBuyOffer                OperationType = 12
+	OperationTypePathPaymentStrictSend         OperationType = 13
+	OperationTypeCreateClaimableBalance        OperationType = 14
+	OperationTypeClaimClaimableBalance         OperationType = 15
+	OperationTypeBeginSponsoringFutureReserves OperationType = 16
+	OperationTypeEndSponsoringFutureReserves   OperationType = 17
+	OperationTypeRevokeSponsorship             OperationType = 18
+	OperationTypeClawback                      OperationType = 19
+	OperationTypeClawbackClaimableBalance      OperationType = 20
+	OperationTypeSetTrustLineFlags             OperationType = 21
+	OperationTypeLiquidityPoolDeposit          OperationType = 22
+	OperationTypeLiquidityPoolWithdraw         OperationType = 23
+	OperationTypeInvokeHostFunction            OperationType = 24
+	OperationTypeExtendFootprintTtl            OperationType = 25
+	OperationTypeRestoreFootprint              OperationType = 26
+)
+
+// Operation is an operation of a transaction, SourceAccount is nil when
+// the source account of the transaction is used
+type Operation struct {
+	SourceAccount *MuxedAccount
+	Body          OperationBody
+}
+
+// OperationBody contains an operation of any type
+type OperationBody struct {
+	Type                            OperationType
+	CreateAccountOp                 *xdr.CreateAccountOp
+	PaymentOp                       *PaymentOp
+	PathPaymentStrictReceiveOp      *PathPaymentStrictReceiveOp
+	ManageSellOfferOp               *xdr.ManageOfferOp
+	CreatePassiveSellOfferOp        *xdr.CreatePassiveOfferOp
+	SetOptionsOp                    *SetOptionsOp
+	ChangeTrustOp                   *xdr.ChangeTrustOp
+	AllowTrustOp                    *AllowTrustOp
+	Destination                     *MuxedAccount
+	ManageDataOp                    *xdr.ManageDataOp
+	BumpSequenceOp                  *BumpSequenceOp
+	ManageBuyOfferOp                *ManageBuyOfferOp
+	PathPaymentStrictSendOp         *PathPaymentStrictSendOp
+	CreateClaimableBalanceOp        *CreateClaimableBalanceOp
+	ClaimClaimableBalanceOp         *ClaimClaimableBalanceOp
+	BeginSponsoringFutureReservesOp *xdr.BeginSponsoringFutureReservesOp
+	RevokeSponsorshipOp             *RevokeSponsorshipOp
+	ClawbackOp                      *ClawbackOp
+	ClawbackClaimableBalanceOp      *ClawbackClaimableBalanceOp
+	SetTrustLineFlagsOp             *SetTrustLineFlagsOp
+	LiquidityPoolDepositOp          *xdr.LiquidityPoolDepositOp
+	LiquidityPoolWithdrawOp         *xdr.LiquidityPoolWithdrawOp
+	InvokeHostFunctionOp            *InvokeHostFunctionOp
+	ExtendFootprintTtlOp            *ExtendFootprintTtlOp
+	RestoreFootprintOp              *RestoreFootprintOp
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u OperationBody) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of OperationBody
+func (u OperationBody) ArmForSwitch(sw int32) (string, bool) {
+	switch OperationType(sw) {
+	case OperationTypeCreateAccount:
+		return "CreateAccountOp", true
+	case OperationTypePayment:
+		return "PaymentOp", true
+	case OperationTypePathPaymentStrictReceive:
+		return "PathPaymentStrictReceiveOp", true
+	case OperationTypeManageSellOffer:
+		return "ManageSellOfferOp", true
+	case OperationTypeCreatePassiveSellOffer:
+		return "CreatePassiveSellOfferOp", true
+	case OperationTypeSetOptions:
+		return "SetOptionsOp", true
+	case OperationTypeChangeTrust:
+		return "ChangeTrustOp", true
+	case OperationTypeAllowTrust:
+		return "AllowTrustOp", true
+	case OperationTypeAccountMerge:
+		return "Destination", true
+	case OperationTypeInflation:
+		return "", true
+	case OperationTypeManageData:
+		return "ManageDataOp", true
+	case OperationTypeBumpSequence:
+		return "BumpSequenceOp", true
+	case OperationTypeManageBuyOffer:
+		return "ManageBuyOfferOp", true
+	case OperationTypePathPaymentStrictSend:
+		return "PathPaymentStrictSendOp", true
+	case OperationTypeCreateClaimableBalance:
+		return "CreateClaimableBalanceOp", true
+	case OperationTypeClaimClaimableBalance:
+		return "ClaimClaimableBalanceOp", true
+	case OperationTypeBeginSponsoringFutureReserves:
+		return "BeginSponsoringFutureReservesOp", true
+	case OperationTypeEndSponsoringFutureReserves:
+		return "", true
+	case OperationTypeRevokeSponsorship:
+		return "RevokeSponsorshipOp", true
+	case OperationTypeClawback:
+		return "ClawbackOp", true
+	case OperationTypeClawbackClaimableBalance:
+		return "ClawbackClaimableBalanceOp", true
+	case OperationTypeSetTrustLineFlags:
+		return "SetTrustLineFlagsOp", true
+	case OperationTypeLiquidityPoolDeposit:
+		return "LiquidityPoolDepositOp", true
+	case OperationTypeLiquidityPoolWithdraw:
+		return "LiquidityPoolWithdrawOp", true
+	case OperationTypeInvokeHostFunction:
+		return "InvokeHostFunctionOp", true
+	case OperationTypeExtendFootprintTtl:
+		return "ExtendFootprintTtlOp", true
+	case OperationTypeRestoreFootprint:
+		return "RestoreFootprintOp", true
+	}
+	return "-", false
+}
+
+// PaymentOp is payment operation
+type PaymentOp struct {
+	Destination MuxedAccount
+	Asset       xdr.Asset
+	Amount      xdr.Int64
+}
+
+// PathPaymentStrictReceiveOp is path_payment_strict_receive operation
+// (path_payment before protocol 12)
+type PathPaymentStrictReceiveOp struct {
+	SendAsset   xdr.Asset
+	SendMax     xdr.Int64
+	Destination MuxedAccount
+	DestAsset   xdr.Asset
+	DestAmount  xdr.Int64
+	Path        []xdr.Asset
+}
+
+// PathPaymentStrictSendOp is path_payment_strict_send operation
+type PathPaymentStrictSendOp struct {
+	SendAsset   xdr.Asset
+	SendAmount  xdr.Int64
+	Destination MuxedAccount
+	DestAsset   xdr.Asset
+	DestMin     xdr.Int64
+	Path        []xdr.Asset
+}
+
+// SetOptionsOp is set_options operation
+type SetOptionsOp struct {
+	InflationDest *xdr.AccountId
+	ClearFlags    *xdr.Uint32
+	SetFlags      *xdr.Uint32
+	MasterWeight  *xdr.Uint32
+	LowThreshold  *xdr.Uint32
+	MedThreshold  *xdr.Uint32
+	HighThreshold *xdr.Uint32
+	HomeDomain    *xdr.String32
+	Signer        *Signer
+}
+
+// AssetCode is the asset of allow_trust, the issuer is the source account
+type AssetCode struct {
+	Type        xdr.AssetType
+	AssetCode4  *[4]byte
+	AssetCode12 *[12]byte
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u AssetCode) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of AssetCode
+func (u AssetCode) ArmForSwitch(sw int32) (string, bool) {
+	switch xdr.AssetType(sw) {
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		return "AssetCode4", true
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		return "AssetCode12", true
+	}
+	return "-", false
+}
+
+// AllowTrustOp is allow_trust operation. Authorize is a set of trust line
+// flags since protocol 13.
+type AllowTrustOp struct {
+	Trustor   xdr.AccountId
+	Asset     AssetCode
+	Authorize xdr.Uint32
+}
+
+// BumpSequenceOp is bump_sequence operation
+type BumpSequenceOp struct {
+	BumpTo xdr.SequenceNumber
+}
+
+// ManageBuyOfferOp is manage_buy_offer operation
+type ManageBuyOfferOp struct {
+	Selling   xdr.Asset
+	Buying    xdr.Asset
+	BuyAmount xdr.Int64
+	Price     xdr.Price
+	OfferId   xdr.Int64
+}
+
+// ClaimPredicateType is CLAIM_PREDICATE_* of Stellar-ledger-entries.x
+type ClaimPredicateType int32
+
+// Claim predicate types
+const (
+	ClaimPredicateTypeUnconditional      ClaimPredicateType = 0
+	ClaimPredicateTypeAnd                ClaimPredicateType = 1
+	ClaimPredicateTypeOr                 ClaimPredicateType = 2
+	ClaimPredicateTypeNot                ClaimPredicateType = 3
+	ClaimPredicateTypeBeforeAbsoluteTime ClaimPredicateType = 4
+	ClaimPredicateTypeBeforeRelativeTime ClaimPredicateType = 5
+)
+
+// ClaimPredicate is a condition of claiming a claimable balance
+type ClaimPredicate struct {
+	Type          ClaimPredicateType
+	AndPredicates *[]ClaimPredicate
+	OrPredicates  *[]ClaimPredicate
+	NotPredicate  **ClaimPredicate
+	AbsBefore     *xdr.Int64
+	RelBefore     *xdr.Int64
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ClaimPredicate) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ClaimPredicate
+func (u ClaimPredicate) ArmForSwitch(sw int32) (string, bool) {
+	switch ClaimPredicateType(sw) {
+	case ClaimPredicateTypeUnconditional:
+		return "", true
+	case ClaimPredicateTypeAnd:
+		return "AndPredicates", true
+	case ClaimPredicateTypeOr:
+		return "OrPredicates", true
+	case ClaimPredicateTypeNot:
+		return "NotPredicate", true
+	case ClaimPredicateTypeBeforeAbsoluteTime:
+		return "AbsBefore", true
+	case ClaimPredicateTypeBeforeRelativeTime:
+		return "RelBefore", true
+	}
+	return "-", false
+}
+
+// Claimant is a CLAIMANT_TYPE_V0 claimant of a claimable balance
+type Claimant struct {
+	Type int32
+	V0   *ClaimantV0
+}
+
+// ClaimantV0 is the CLAIMANT_TYPE_V0 arm of Claimant
+type ClaimantV0 struct {
+	Destination xdr.AccountId
+	Predicate   ClaimPredicate
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u Claimant) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of Claimant
+func (u Claimant) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "V0", true
+	}
+	return "-", false
+}
+
+// ClaimableBalanceId is a CLAIMABLE_BALANCE_ID_TYPE_V0 ID of a claimable
+// balance
+type ClaimableBalanceId struct {
+	Type int32
+	V0   *xdr.Hash
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u ClaimableBalanceId) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of ClaimableBalanceId
+func (u ClaimableBalanceId) ArmForSwitch(sw int32) (string, bool) {
+	switch sw {
+	case 0:
+		return "V0", true
+	}
+	return "-", false
+}
+
+// CreateClaimableBalanceOp is create_claimable_balance operation
+type CreateClaimableBalanceOp struct {
+	Asset     xdr.Asset
+	Amount    xdr.Int64
+	Claimants []Claimant
+}
+
+// ClaimClaimableBalanceOp is claim_claimable_balance operation
+type ClaimClaimableBalanceOp struct {
+	BalanceId ClaimableBalanceId
+}
+
+// RevokeSponsorshipType is REVOKE_SPONSORSHIP_* of Stellar-transaction.x
+type RevokeSponsorshipType int32
+
+// Revoke sponsorship types
+const (
+	RevokeSponsorshipTypeLedgerEntry RevokeSponsorshipType = 0
+	RevokeSponsorshipTypeSigner      RevokeSponsorshipType = 1
+)
+
+// RevokeSponsorshipOp is revoke_sponsorship operation
+type RevokeSponsorshipOp struct {
+	Type      RevokeSponsorshipType
+	LedgerKey *LedgerKey
+	Signer    *RevokeSponsorshipOpSigner
+}
+
+// RevokeSponsorshipOpSigner is the REVOKE_SPONSORSHIP_SIGNER arm of
+// RevokeSponsorshipOp
+type RevokeSponsorshipOpSigner struct {
+	AccountId xdr.AccountId
+	SignerKey SignerKey
+}
+
+// SwitchFieldName returns the field name in which this union's
+// discriminant is stored
+func (u RevokeSponsorshipOp) SwitchFieldName() string {
+	return "Type"
+}
+
+// ArmForSwitch returns which field name should be used for storing
+// the value for an instance of RevokeSponsorshipOp
+func (u RevokeSponsorshipOp) ArmForSwitch(sw int32) (string, bool) {
+	switch RevokeSponsorshipType(sw) {
+	case RevokeSponsorshipTypeLedgerEntry:
+		return "LedgerKey", true
+	case RevokeSponsorshipTypeSigner:
+		return "Signer", true
+	}
+	return "-", false
+}
+
+// ClawbackOp is clawback operation
+type ClawbackOp struct {
+	Asset  xdr.Asset
+	From   MuxedAccount
+	Amount xdr.Int64
+}
+
+// ClawbackClaimableBalanceOp is clawback_claimable_balance operation
+type ClawbackClaimableBalanceOp struct {
+	BalanceId ClaimableBalanceId
+}
+
+// SetTrustLineFlagsOp is set_trust_line_flags operation
+type SetTrustLineFlagsOp struct {
+	Trustor    xdr.AccountId
+	Asset      xdr.Asset
+	ClearFlags xdr.Uint32
+	SetFlags   xdr.Uint32
+}
+
+// InvokeHostFunctionOp is invoke_host_function operation
+type InvokeHostFunctionOp struct {
+	HostFunction HostFunction
+	Auth         []SorobanAuthorizationEntry
+}
+
+// ExtendFootprintTtlOp is extend_footprint_ttl operation
+type ExtendFootprintTtlOp struct {
+	Ext      ExtensionPoint
+	ExtendTo xdr.Uint32
+}
+
+// RestoreFootprintOp is restore_footprint operation
+type RestoreFootprintOp struct {
+	Ext ExtensionPoint
+}
@@ -0,0 +1,146 @@
+package stellarxdr
+
+import (
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	passphrase = "Test SDF Network ; September 2015"
+	// v0Envelope is a manage_data transaction encoded by the vendored
+	// go/build
+	v0Envelope = "AAAAAGySS3ZylffFaVZqZD6lNCUjCizHz7MLPwkN7Mxh4XN5AAAAZAAAAAAAAAB7AAAAAAAAAAAAAAABAAAAAAAAAAoAAAAJdGVzdF9kYXRhAAAAAAAAAQAAAAYBAgMEBQYAAAAAAAAAAAABn420/AAAAEBkO27ebDbsn1WzzLH5lUfJH3Y0Pgd1dlRx3Ip1dEZkvRPFFDLZuXi5DlW9uxNgeqThNsqnK7PPHfhyuWBVQpgN"
+)
+
+func TestTransactionEnvelopeV0(t *testing.T) {
+	var envelope TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(v0Envelope, &envelope))
+	require.Equal(t, EnvelopeTypeTxV0, envelope.Type)
+	assert.Equal(t, OperationTypeManageData, envelope.V0.Tx.Operations[0].Body.Type)
+	assert.Len(t, envelope.V0.Signatures, 1)
+
+	encoded, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, v0Envelope, encoded)
+
+	// V0 transactions have the hash of the vendored transaction
+	var vendored xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(v0Envelope, &vendored))
+	expected, err := network.HashTransaction(&vendored.Tx, passphrase)
+	require.NoError(t, err)
+
+	hash, err := envelope.Hash(passphrase)
+	require.NoError(t, err)
+	assert.Equal(t, xdr.Hash(expected), hash)
+}
+
+func TestTransactionEnvelopeV1(t *testing.T) {
+	source := xdr.Uint256{1, 2, 3}
+	contractID := xdr.Hash{4, 5, 6}
+	data := SorobanTransactionData{
+		Resources: SorobanResources{
+			Footprint: LedgerFootprint{
+				ReadOnly: []LedgerKey{{
+					Type: LedgerEntryTypeContractCode,
+					ContractCode: &LedgerKeyContractCode{
+						Hash: xdr.Hash{7},
+					},
+				}},
+			},
+			Instructions: 1000,
+		},
+		ResourceFee: 500,
+	}
+	symbol := "transfer"
+	tx := Transaction{
+		SourceAccount: MuxedAccount{
+			Type:     CryptoKeyTypeMuxedEd25519,
+			Med25519: &MuxedAccountMed25519{Id: 42, Ed25519: source},
+		},
+		Fee:    600,
+		SeqNum: 123,
+		Cond:   Preconditions{Type: PreconditionTypeNone},
+		Memo:   xdr.Memo{Type: xdr.MemoTypeMemoNone},
+		Operations: []Operation{{
+			Body: OperationBody{
+				Type: OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &InvokeHostFunctionOp{
+					HostFunction: HostFunction{
+						Type: HostFunctionTypeInvokeContract,
+						InvokeContract: &InvokeContractArgs{
+							ContractAddress: ScAddress{Type: ScAddressTypeContract, ContractId: &contractID},
+							FunctionName:    "hello",
+							Args:            []ScVal{{Type: ScValTypeScvSymbol, Sym: &symbol}},
+						},
+					},
+				},
+			},
+		}},
+		Ext: TransactionExt{V: 1, SorobanData: &data},
+	}
+
+	encoded, err := xdr.MarshalBase64(TransactionEnvelope{Type: EnvelopeTypeTx, V1: &TransactionV1Envelope{Tx: tx}})
+	require.NoError(t, err)
+
+	var envelope TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &envelope))
+	assert.Equal(t, tx, envelope.V1.Tx)
+
+	// Fee bump transactions have a different hash than the inner one
+	hash, err := envelope.Hash(passphrase)
+	require.NoError(t, err)
+	feeBump := TransactionEnvelope{
+		Type: EnvelopeTypeTxFeeBump,
+		FeeBump: &FeeBumpTransactionEnvelope{
+			Tx: FeeBumpTransaction{
+				FeeSource: MuxedAccount{Type: CryptoKeyTypeEd25519, Ed25519: &source},
+				Fee:       1200,
+				InnerTx:   FeeBumpTransactionInnerTx{Type: EnvelopeTypeTx, V1: envelope.V1},
+			},
+		},
+	}
+	feeBumpHash, err := feeBump.Hash(passphrase)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, feeBumpHash)
+
+	encoded, err = xdr.MarshalBase64(feeBump)
+	require.NoError(t, err)
+	var decoded TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(encoded, &decoded))
+	assert.Equal(t, tx, decoded.FeeBump.Tx.InnerTx.V1.Tx)
+}
+
+func TestSafeUnmarshalBase64(t *testing.T) {
+	// SCV_U32 1 is 8 bytes, encoded with padding
+	var value ScVal
+	require.NoError(t, SafeUnmarshalBase64("AAAAAwAAAAE=", &value))
+	assert.Equal(t, xdr.Uint32(1), *value.U32)
+
+	assert.Error(t, SafeUnmarshalBase64("AAAAAwAAAAEAAAAA", &value))
+	assert.Error(t, SafeUnmarshalBase64("AAAAAwAAAAE", &value))
+}
+
+func TestMuxedAddress(t *testing.T) {
+	var aid xdr.AccountId
+	require.NoError(t, aid.SetAddress("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"))
+
+	account := MuxedAccount{Type: CryptoKeyTypeEd25519, Ed25519: aid.Ed25519}
+	_, _, ok := account.MuxedAddress()
+	assert.False(t, ok)
+	assert.Equal(t, "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ", account.Address())
+
+	// SEP-23 test vector
+	account = MuxedAccount{
+		Type:     CryptoKeyTypeMuxedEd25519,
+		Med25519: &MuxedAccountMed25519{Id: 0, Ed25519: *aid.Ed25519},
+	}
+	address, id, ok := account.MuxedAddress()
+	require.True(t, ok)
+	assert.Equal(t, "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAAAAAAAACJUQ", address)
+	assert.Equal(t, uint64(0), id)
+	assert.Equal(t, "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ", account.Address())
+}
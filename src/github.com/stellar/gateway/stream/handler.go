@@ -0,0 +1,274 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// pageSize is the number of events loaded from the database at once
+const pageSize = 100
+
+var handlerMetrics = struct {
+	subscribers *metrics.Gauge
+}{
+	subscribers: metrics.NewGauge("bridge_stream_subscribers", "Number of open payment streams."),
+}
+
+// NotAcceptableError is returned when a request is neither an SSE nor a
+// WebSocket request
+var NotAcceptableError = &protocols.ErrorResponse{
+	Code:    "not_acceptable",
+	Message: "Send `Accept: text/event-stream` header or open a WebSocket connection.",
+	Status:  http.StatusNotAcceptable,
+}
+
+// Message is a WebSocket message of an event. SSE events have the cursor in
+// `id` field and the payment in `data` field.
+type Message struct {
+	Cursor string `json:"cursor"`
+	// Payment contains the same fields as receive callbacks
+	Payment json.RawMessage `json:"payment"`
+}
+
+// Handler serves events recorded by Publisher. Subscribers send the cursor of
+// the last received event in `cursor` query param or `Last-Event-ID` header
+// (sent by EventSource when it reconnects). Only new events are sent when the
+// cursor is empty or `now`, `0` replays all recorded events.
+type Handler struct {
+	Publisher  *Publisher
+	Repository db.RepositoryInterface
+	// MaxSubscribers is the max number of open streams
+	MaxSubscribers int
+	// PollInterval is the interval of checking the database for events
+	// recorded by other servers
+	PollInterval time.Duration
+	// Heartbeat is the interval of keep-alive messages
+	Heartbeat time.Duration
+
+	log         *logrus.Entry
+	mutex       sync.Mutex
+	subscribers int
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+// conn is an open stream
+type conn interface {
+	Send(event *entities.ReceivedPaymentEvent) error
+	Heartbeat() error
+	// Done is closed when the subscriber closes the stream
+	Done() <-chan struct{}
+	Close()
+}
+
+// NewHandler creates a new Handler
+func NewHandler(publisher *Publisher, repository db.RepositoryInterface, maxSubscribers int, pollInterval, heartbeat time.Duration) *Handler {
+	return &Handler{
+		Publisher:      publisher,
+		Repository:     repository,
+		MaxSubscribers: maxSubscribers,
+		PollInterval:   pollInterval,
+		Heartbeat:      heartbeat,
+		log:            logrus.WithFields(logrus.Fields{"service": "StreamHandler"}),
+		closed:         make(chan struct{}),
+	}
+}
+
+// Close ends all open streams, it should be called when the server stops
+func (h *Handler) Close() {
+	h.closeOnce.Do(func() {
+		close(h.closed)
+	})
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket := isWebSocket(r)
+	if !websocket && !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		server.Write(w, NotAcceptableError)
+		return
+	}
+
+	if !h.acquire() {
+		server.Write(w, protocols.ServiceUnavailableError)
+		return
+	}
+	defer h.release()
+
+	// Subscribed before loading the cursor, so events recorded meanwhile
+	// are not missed
+	notifications, unsubscribe := h.Publisher.subscribe()
+	defer unsubscribe()
+
+	cursor, errorResponse := h.cursor(r)
+	if errorResponse != nil {
+		server.Write(w, errorResponse)
+		return
+	}
+
+	var c conn
+	if websocket {
+		c = acceptWebSocket(w, r)
+	} else {
+		c = newSSEConn(w)
+	}
+	if c == nil {
+		return
+	}
+	defer c.Close()
+
+	h.stream(r.Context(), c, cursor, notifications)
+}
+
+// cursor returns the cursor sent in the request. Last-Event-ID has priority,
+// EventSource reconnects using the same URL.
+func (h *Handler) cursor(r *http.Request) (int64, *protocols.ErrorResponse) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("cursor")
+	}
+
+	if value == "" || value == "now" {
+		cursor, err := h.Repository.GetLastReceivedPaymentEventID(r.Context())
+		if err != nil {
+			return 0, protocols.NewInternalServerError("Error loading last stream event", map[string]interface{}{"err": err})
+		}
+		return cursor, nil
+	}
+
+	cursor, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || cursor < 0 {
+		return 0, protocols.NewInvalidParameterError("cursor", value, "Cursor must be an ID of an event or `now`.")
+	}
+	return cursor, nil
+}
+
+// stream sends events recorded after cursor until the stream is closed
+func (h *Handler) stream(ctx context.Context, c conn, cursor int64, notifications <-chan struct{}) {
+	poll := time.NewTicker(h.PollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+
+	load := true
+	for {
+		// Pages are loaded until all recorded events are sent
+		for load {
+			events, err := h.Repository.GetReceivedPaymentEvents(ctx, cursor, pageSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					// Loaded again at the next poll
+					h.log.WithFields(logrus.Fields{"err": err}).Error("Error loading stream events")
+				}
+				break
+			}
+
+			for _, event := range events {
+				err = c.Send(event)
+				if err != nil {
+					return
+				}
+				cursor = *event.ID
+			}
+			load = len(events) == pageSize
+		}
+
+		select {
+		case <-notifications:
+			load = true
+		case <-poll.C:
+			load = true
+		case <-heartbeat.C:
+			if c.Heartbeat() != nil {
+				return
+			}
+		case <-c.Done():
+			return
+		case <-ctx.Done():
+			return
+		case <-h.closed:
+			return
+		}
+	}
+}
+
+func (h *Handler) acquire() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.subscribers >= h.MaxSubscribers {
+		return false
+	}
+	h.subscribers++
+	handlerMetrics.subscribers.Set(float64(h.subscribers))
+	return true
+}
+
+func (h *Handler) release() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.subscribers--
+	handlerMetrics.subscribers.Set(float64(h.subscribers))
+}
+
+// sseConn is a Server-Sent Events stream
+type sseConn struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+// newSSEConn starts a stream. nil is returned when the response can't be
+// streamed.
+func newSSEConn(w http.ResponseWriter) conn {
+	c := &sseConn{w: w, controller: http.NewResponseController(w)}
+
+	// Streams are open longer than write timeout of the server
+	c.controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Disables buffering of nginx proxies
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if c.controller.Flush() != nil {
+		return nil
+	}
+	return c
+}
+
+func (c *sseConn) Send(event *entities.ReceivedPaymentEvent) error {
+	_, err := fmt.Fprintf(c.w, "id: %d\nevent: payment\ndata: %s\n\n", *event.ID, event.Payload)
+	if err != nil {
+		return err
+	}
+	return c.controller.Flush()
+}
+
+func (c *sseConn) Heartbeat() error {
+	_, err := c.w.Write([]byte(": heartbeat\n\n"))
+	if err != nil {
+		return err
+	}
+	return c.controller.Flush()
+}
+
+// Done returns nil, closed streams cancel the request context
+func (c *sseConn) Done() <-chan struct{} {
+	return nil
+}
+
+func (c *sseConn) Close() {}
@@ -0,0 +1,116 @@
+// Package stream pushes received payments to subscribers of /stream/payments,
+// an alternative to receive callbacks for dashboards and internal consumers.
+//
+// Publisher records every receive callback payload in ReceivedPaymentEvent
+// table. The ID of an event is its cursor. Handler serves events over
+// Server-Sent Events or WebSocket: events recorded after the cursor sent by
+// the subscriber are replayed from the database and new events are sent when
+// they are recorded. Events recorded by other servers sharing the database
+// are found by polling it.
+package stream
+
+import (
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+)
+
+var publisherMetrics = struct {
+	errors *metrics.Counter
+}{
+	errors: metrics.NewCounter("bridge_stream_event_errors_total", "Number of received payments not recorded for stream subscribers because of an error."),
+}
+
+// Publisher records receive callbacks for stream subscribers. A nil
+// *Publisher does nothing, so it can be used when streams are disabled.
+type Publisher struct {
+	entityManager db.EntityManagerInterface
+	now           func() time.Time
+	log           *logrus.Entry
+
+	mutex sync.Mutex
+	// subscribers are notified when an event is recorded
+	subscribers map[chan struct{}]bool
+}
+
+// NewPublisher creates a new Publisher
+func NewPublisher(entityManager db.EntityManagerInterface, now func() time.Time) *Publisher {
+	return &Publisher{
+		entityManager: entityManager,
+		now:           now,
+		log:           logrus.WithFields(logrus.Fields{"service": "StreamPublisher"}),
+		subscribers:   map[chan struct{}]bool{},
+	}
+}
+
+// Publish records payload of a receive callback of operationID. Errors are
+// logged only, the callback is sent anyway.
+func (p *Publisher) Publish(operationID string, payload url.Values) {
+	if p == nil {
+		return
+	}
+
+	// Same format as callbacks sent to message buses
+	message := make(map[string]string, len(payload))
+	for key := range payload {
+		message[key] = payload.Get(key)
+	}
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		p.fail(err, operationID)
+		return
+	}
+
+	err = p.entityManager.Persist(&entities.ReceivedPaymentEvent{
+		OperationID: operationID,
+		Payload:     string(encoded),
+		CreatedAt:   p.now(),
+	})
+	if err != nil {
+		p.fail(err, operationID)
+		return
+	}
+
+	p.notify()
+}
+
+func (p *Publisher) fail(err error, operationID string) {
+	publisherMetrics.errors.Inc()
+	p.log.WithFields(logrus.Fields{"err": err, "operation_id": operationID}).Error("Error recording stream event")
+}
+
+// subscribe returns a channel receiving a value when new events are recorded.
+// unsubscribe must be called when the channel is no longer read.
+func (p *Publisher) subscribe() (notifications <-chan struct{}, unsubscribe func()) {
+	// Holds one notification, subscribers load all new events at once
+	c := make(chan struct{}, 1)
+
+	p.mutex.Lock()
+	p.subscribers[c] = true
+	p.mutex.Unlock()
+
+	return c, func() {
+		p.mutex.Lock()
+		delete(p.subscribers, c)
+		p.mutex.Unlock()
+	}
+}
+
+func (p *Publisher) notify() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for c := range p.subscribers {
+		select {
+		case c <- struct{}{}:
+		default:
+			// Already notified
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/drivers/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T) *Handler {
+	driver := &sqlite.Driver{}
+	require.NoError(t, driver.Init(sqlite.MemoryURL))
+	_, err := driver.MigrateUp("gateway")
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	publisher := NewPublisher(db.NewEntityManager(driver), func() time.Time { return now })
+	return NewHandler(publisher, db.NewRepository(driver), 1, time.Minute, 50*time.Millisecond)
+}
+
+func publish(h *Handler, id string) {
+	h.Publisher.Publish(id, url.Values{"id": {id}, "amount": {"10"}})
+}
+
+// readEvent reads lines of an SSE event, heartbeats are skipped
+func readEvent(t *testing.T, reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && len(lines) > 0:
+			return lines
+		case line == "" || strings.HasPrefix(line, ":"):
+			continue
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestSSE(t *testing.T) {
+	h := newTestHandler(t)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	publish(h, "100")
+
+	request, err := http.NewRequest("GET", ts.URL+"?cursor=0", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept", "text/event-stream")
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(response.Body)
+	assert.Equal(t, []string{"id: 1", "event: payment", `data: {"amount":"10","id":"100"}`}, readEvent(t, reader))
+
+	publish(h, "101")
+	assert.Equal(t, []string{"id: 2", "event: payment", `data: {"amount":"10","id":"101"}`}, readEvent(t, reader))
+
+	// Only one subscriber is allowed
+	busy, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	busy.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, busy.StatusCode)
+
+	// Streams end when the server stops
+	h.Close()
+	_, err = ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+}
+
+func TestSSECursor(t *testing.T) {
+	h := newTestHandler(t)
+	h.MaxSubscribers = 10
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	publish(h, "100")
+	publish(h, "101")
+
+	open := func(query string, headers map[string]string) *http.Response {
+		request, err := http.NewRequest("GET", ts.URL+query, nil)
+		require.NoError(t, err)
+		request.Header.Set("Accept", "text/event-stream")
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		return response
+	}
+
+	// Last-Event-ID has priority over the cursor of the URL
+	response := open("?cursor=0", map[string]string{"Last-Event-ID": "1"})
+	assert.Equal(t, "id: 2", readEvent(t, bufio.NewReader(response.Body))[0])
+	response.Body.Close()
+
+	// Only new events are sent without cursor
+	response = open("", nil)
+	reader := bufio.NewReader(response.Body)
+	publish(h, "102")
+	assert.Equal(t, "id: 3", readEvent(t, reader)[0])
+	response.Body.Close()
+
+	response = open("?cursor=abc", nil)
+	response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotAcceptable, response.StatusCode)
+}
+
+// readFrame reads a server frame, frames of the server are not masked
+func readFrame(t *testing.T, reader *bufio.Reader) (byte, []byte) {
+	var header [2]byte
+	_, err := io.ReadFull(reader, header[:])
+	require.NoError(t, err)
+
+	length := int(header[1])
+	if length == 126 {
+		var extended [2]byte
+		_, err = io.ReadFull(reader, extended[:])
+		require.NoError(t, err)
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	require.NoError(t, err)
+	return header[0] & 0x0F, payload
+}
+
+// writeFrame writes a masked client frame
+func writeFrame(t *testing.T, conn net.Conn, opcode byte, payload []byte) {
+	mask := []byte{1, 2, 3, 4}
+	frame := append([]byte{0x80 | opcode, 0x80 | byte(len(payload))}, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := conn.Write(frame)
+	require.NoError(t, err)
+}
+
+func TestWebSocket(t *testing.T) {
+	h := newTestHandler(t)
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	publish(h, "100")
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	_, err = conn.Write([]byte("GET /?cursor=0 HTTP/1.1\r\nHost: bridge\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\n\r\n"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	assert.Equal(t, "BACScCJPNqyz+UBoqMH89VmURoA=", response.Header.Get("Sec-WebSocket-Accept"))
+
+	opcode, payload := readFrame(t, reader)
+	assert.Equal(t, byte(opText), opcode)
+	var message Message
+	require.NoError(t, json.Unmarshal(payload, &message))
+	assert.Equal(t, "1", message.Cursor)
+	assert.JSONEq(t, `{"amount":"10","id":"100"}`, string(message.Payment))
+
+	// Pings of the server are sent when there are no events
+	opcode, _ = readFrame(t, reader)
+	assert.Equal(t, byte(opPing), opcode)
+
+	writeFrame(t, conn, opPing, []byte("hi"))
+	opcode, payload = readFrame(t, reader)
+	for opcode == opPing {
+		opcode, payload = readFrame(t, reader)
+	}
+	assert.Equal(t, byte(opPong), opcode)
+	assert.Equal(t, "hi", string(payload))
+
+	writeFrame(t, conn, opClose, []byte{0x03, 0xE8})
+	opcode, payload = readFrame(t, reader)
+	for opcode == opPing {
+		opcode, payload = readFrame(t, reader)
+	}
+	assert.Equal(t, byte(opClose), opcode)
+	assert.Equal(t, []byte{0x03, 0xE8}, payload)
+}
+
+func TestWebSocketHandshake(t *testing.T) {
+	h := newTestHandler(t)
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Upgrade", "websocket")
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Sec-WebSocket-Key", "key")
+	request.Header.Set("Sec-WebSocket-Version", "8")
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "13", recorder.Header().Get("Sec-WebSocket-Version"))
+}
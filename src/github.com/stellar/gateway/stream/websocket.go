@@ -0,0 +1,232 @@
+package stream
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/protocols"
+	"github.com/stellar/gateway/server"
+)
+
+// WebSocket opcodes (RFC 6455)
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// acceptGUID is appended to Sec-WebSocket-Key to compute Sec-WebSocket-Accept
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxFrameSize is the max size of frames sent by subscribers. Messages of
+// subscribers are ignored, so only control frames are expected.
+const maxFrameSize = 4096
+
+// writeTimeout limits writing a single frame
+const writeTimeout = 10 * time.Second
+
+// HandshakeError is returned when a WebSocket handshake is invalid
+var HandshakeError = &protocols.ErrorResponse{
+	Code:    "invalid_handshake",
+	Message: "Invalid WebSocket handshake, version 13 is supported.",
+	Status:  http.StatusBadRequest,
+}
+
+var errFrameTooLarge = errors.New("frame too large")
+
+// isWebSocket returns true when r asks to upgrade the connection to WebSocket
+func isWebSocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// webSocketConn is a WebSocket connection. Frames are written by the stream
+// and by the goroutine reading frames of the subscriber (pongs), so writes
+// are synchronized.
+type webSocketConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+
+	writeMutex sync.Mutex
+	done       chan struct{}
+}
+
+// acceptWebSocket completes the handshake and hijacks the connection. An
+// error response is written and nil is returned when it fails.
+func acceptWebSocket(w http.ResponseWriter, r *http.Request) conn {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" || !hasToken(r.Header.Get("Connection"), "upgrade") {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		server.Write(w, HandshakeError)
+		return nil
+	}
+
+	netConn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		server.Write(w, protocols.NewInternalServerError("Error hijacking WebSocket connection", map[string]interface{}{"err": err}))
+		return nil
+	}
+	// Deadlines set by the server don't apply to streams
+	netConn.SetDeadline(time.Time{})
+
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n\r\n")
+	if rw.Flush() != nil {
+		netConn.Close()
+		return nil
+	}
+
+	c := &webSocketConn{conn: netConn, rw: rw, done: make(chan struct{})}
+	go c.read()
+	return c
+}
+
+// hasToken returns true when a comma separated header contains token
+func hasToken(header, token string) bool {
+	for _, value := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(value), token) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *webSocketConn) Send(event *entities.ReceivedPaymentEvent) error {
+	message, err := json.Marshal(Message{
+		Cursor:  strconv.FormatInt(*event.ID, 10),
+		Payment: json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(opText, message)
+}
+
+func (c *webSocketConn) Heartbeat() error {
+	return c.writeFrame(opPing, nil)
+}
+
+func (c *webSocketConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close sends a close frame (1001 going away) unless the subscriber closed
+// the connection and closes it
+func (c *webSocketConn) Close() {
+	select {
+	case <-c.done:
+	default:
+		c.writeFrame(opClose, []byte{0x03, 0xE9})
+	}
+	c.conn.Close()
+}
+
+// read reads frames of the subscriber until the connection is closed. Pings
+// are answered, close frames are echoed.
+func (c *webSocketConn) read() {
+	defer close(c.done)
+
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opClose:
+			if len(payload) > 2 {
+				payload = payload[:2]
+			}
+			c.writeFrame(opClose, payload)
+			return
+		case opPing:
+			c.writeFrame(opPong, payload)
+		}
+	}
+}
+
+// readFrame reads a single frame. Frames of subscribers must be masked.
+func (c *webSocketConn) readFrame() (opcode byte, payload []byte, err error) {
+	var header [2]byte
+	_, err = io.ReadFull(c.rw, header[:])
+	if err != nil {
+		return
+	}
+
+	opcode = header[0] & 0x0F
+	if header[1]&0x80 == 0 {
+		return 0, nil, errors.New("frame not masked")
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		_, err = io.ReadFull(c.rw, extended[:])
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		_, err = io.ReadFull(c.rw, extended[:])
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if err != nil {
+		return
+	}
+	if length > maxFrameSize {
+		return 0, nil, errFrameTooLarge
+	}
+
+	var mask [4]byte
+	_, err = io.ReadFull(c.rw, mask[:])
+	if err != nil {
+		return
+	}
+
+	payload = make([]byte, length)
+	_, err = io.ReadFull(c.rw, payload)
+	if err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// writeFrame writes a single unmasked frame
+func (c *webSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	header := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	c.rw.Write(header)
+	c.rw.Write(payload)
+	return c.rw.Flush()
+}
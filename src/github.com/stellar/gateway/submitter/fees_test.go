@@ -0,0 +1,114 @@
+package submitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testFeeSource struct {
+	fee        uint32
+	updatedFee uint32
+	notice     *horizon.CongestionNotice
+}
+
+func (s *testFeeSource) Fee() uint32                       { return s.fee }
+func (s *testFeeSource) Notice() *horizon.CongestionNotice { return s.notice }
+func (s *testFeeSource) Update() error {
+	s.fee = s.updatedFee
+	return nil
+}
+
+func TestCongestionFees(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+
+	mockHorizon := new(mocks.MockHorizon)
+	mockEntityManager := new(mocks.MockEntityManager)
+	ts := NewTransactionSubmitter(mockHorizon, mockEntityManager, "Test SDF Network ; September 2015", time.Now)
+	ts.Accounts[kp.Seed()] = &Account{Seed: kp.Seed(), Keypair: kp, SequenceNumber: 10}
+
+	notice := &horizon.CongestionNotice{Message: "congested", LedgerCapacityUsage: 0.95, FeePerOperation: 500}
+	ts.Fees = &testFeeSource{fee: 500, updatedFee: 800, notice: notice}
+
+	newTransaction := func() *xdr.Transaction {
+		tx := newTestTransaction(t, kp.Address())
+		tx.Operations = []xdr.Operation{
+			{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}},
+			{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}},
+		}
+		return tx
+	}
+
+	var submitted []xdr.TransactionEnvelope
+	decode := func(args mock.Arguments) {
+		var envelope xdr.TransactionEnvelope
+		require.NoError(t, xdr.SafeUnmarshalBase64(args.String(0), &envelope))
+		submitted = append(submitted, envelope)
+	}
+
+	mockEntityManager.On("Persist", mock.AnythingOfType("*entities.SentTransaction")).Return(nil)
+
+	ledger := uint64(100)
+	mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Run(decode).Return(
+		horizon.SubmitTransactionResponse{Ledger: &ledger}, nil,
+	).Once()
+
+	response, err := ts.SignAndSubmitRawTransaction(context.Background(), nil, kp.Seed(), newTransaction())
+	require.NoError(t, err)
+	assert.Equal(t, notice, response.Congestion)
+	require.Len(t, submitted, 1)
+	assert.Equal(t, xdr.Uint32(1000), submitted[0].Tx.Fee)
+
+	// tx_insufficient_fee: fees are reloaded and the transaction is resubmitted
+	// with the same sequence number
+	submitted = nil
+	insufficientFee := horizon.SubmitTransactionResponse{
+		Extras: &horizon.SubmitTransactionResponseExtras{
+			ResultCodes: &horizon.TransactionResultCodes{TransactionCode: horizon.TxInsufficientFee},
+		},
+	}
+	mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Run(decode).Return(insufficientFee, nil).Once()
+	mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Run(decode).Return(
+		horizon.SubmitTransactionResponse{Ledger: &ledger}, nil,
+	).Once()
+
+	response, err = ts.SignAndSubmitRawTransaction(context.Background(), nil, kp.Seed(), newTransaction())
+	require.NoError(t, err)
+	assert.NotNil(t, response.Ledger)
+	require.Len(t, submitted, 2)
+	assert.Equal(t, xdr.Uint32(1000), submitted[0].Tx.Fee)
+	assert.Equal(t, xdr.Uint32(1600), submitted[1].Tx.Fee)
+	assert.Equal(t, submitted[0].Tx.SeqNum, submitted[1].Tx.SeqNum)
+
+	// Not resubmitted when fees have not changed
+	submitted = nil
+	mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Run(decode).Return(insufficientFee, nil).Once()
+
+	response, err = ts.SignAndSubmitRawTransaction(context.Background(), nil, kp.Seed(), newTransaction())
+	require.NoError(t, err)
+	assert.Nil(t, response.Ledger)
+	assert.Len(t, submitted, 1)
+
+	mockHorizon.AssertExpectations(t)
+}
+
+func TestApplyFeeDoesNotLowerFee(t *testing.T) {
+	ts := TransactionSubmitter{Fees: &testFeeSource{fee: 100}}
+	tx := &xdr.Transaction{Fee: 5000, Operations: []xdr.Operation{{}}}
+	ts.applyFee(tx)
+	assert.Equal(t, xdr.Uint32(5000), tx.Fee)
+
+	ts.Fees = &testFeeSource{fee: 0}
+	tx.Fee = 100
+	ts.applyFee(tx)
+	assert.Equal(t, xdr.Uint32(100), tx.Fee)
+}
@@ -0,0 +1,53 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrNotLeader is returned by LeaderOnly when this replica is not the leader
+var ErrNotLeader = errors.New("Transactions are submitted by the leader replica only")
+
+var notLeaderRejected = metrics.NewCounter("bridge_submission_not_leader_total", "Number of transactions rejected because this replica is not the leader.")
+
+// Leadership tells if this replica is the leader, see leader.Elector
+type Leadership interface {
+	IsLeader() bool
+}
+
+// LeaderOnly submits transactions with Submitter only when this replica is
+// the leader, so replicas sharing accounts do not fight over sequence
+// numbers. Transactions can be built by all replicas.
+type LeaderOnly struct {
+	Submitter  TransactionSubmitterInterface
+	Leadership Leadership
+}
+
+// BuildTransaction builds a transaction with Submitter
+func (l *LeaderOnly) BuildTransaction(ctx context.Context, seed string, operation, memo interface{}) (*xdr.Transaction, error) {
+	return l.Submitter.BuildTransaction(ctx, seed, operation, memo)
+}
+
+// SubmitTransaction submits a transaction with Submitter, ErrNotLeader is
+// returned when this replica is not the leader
+func (l *LeaderOnly) SubmitTransaction(ctx context.Context, paymentID *string, seed string, operation, memo interface{}) (horizon.SubmitTransactionResponse, error) {
+	if !l.Leadership.IsLeader() {
+		notLeaderRejected.Inc()
+		return horizon.SubmitTransactionResponse{}, ErrNotLeader
+	}
+	return l.Submitter.SubmitTransaction(ctx, paymentID, seed, operation, memo)
+}
+
+// SignAndSubmitRawTransaction submits a transaction with Submitter,
+// ErrNotLeader is returned when this replica is not the leader
+func (l *LeaderOnly) SignAndSubmitRawTransaction(ctx context.Context, paymentID *string, seed string, tx *xdr.Transaction) (horizon.SubmitTransactionResponse, error) {
+	if !l.Leadership.IsLeader() {
+		notLeaderRejected.Inc()
+		return horizon.SubmitTransactionResponse{}, ErrNotLeader
+	}
+	return l.Submitter.SignAndSubmitRawTransaction(ctx, paymentID, seed, tx)
+}
@@ -0,0 +1,233 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrQueueFull is returned by Pool when queue_depth transactions are waiting
+// for submission
+var ErrQueueFull = errors.New("Submission queue is full")
+
+var poolQueued = metrics.NewGauge("bridge_submission_queue_depth", "Number of transactions waiting for a submission worker.")
+
+var poolRejected = metrics.NewCounter("bridge_submission_queue_rejected_total", "Number of transactions rejected because the submission queue was full.")
+
+// Pool submits transactions from multiple workers concurrently. Every worker
+// submits from its own channel account (see WithChannel) when channel seeds
+// are provided, so transactions of the same account do not wait for each
+// other's sequence numbers. Transactions submitted with an ordering key (see
+// WithOrderingKey) are always submitted by the same worker, in order.
+type Pool struct {
+	Submitter TransactionSubmitterInterface
+
+	shared     chan *poolJob
+	workers    []chan *poolJob
+	channels   []string
+	queueDepth int64
+	queued     int64
+	wg         sync.WaitGroup
+	stopOnce   sync.Once
+}
+
+type poolJob struct {
+	ctx       context.Context
+	paymentID *string
+	seed      string
+	tx        *xdr.Transaction
+	operation interface{}
+	memo      interface{}
+	result    chan poolResult
+}
+
+type poolResult struct {
+	response horizon.SubmitTransactionResponse
+	err      error
+}
+
+// NewPool creates a Pool of concurrency workers submitting transactions with
+// submitter. Transactions are rejected with ErrQueueFull when queueDepth
+// transactions are waiting. Worker i submits from channelSeeds[i %
+// len(channelSeeds)] when channelSeeds is not empty. Workers are started by
+// Start.
+func NewPool(submitter TransactionSubmitterInterface, concurrency, queueDepth int, channelSeeds []string) *Pool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	p := &Pool{
+		Submitter:  submitter,
+		shared:     make(chan *poolJob, queueDepth),
+		workers:    make([]chan *poolJob, concurrency),
+		channels:   channelSeeds,
+		queueDepth: int64(queueDepth),
+	}
+	for i := range p.workers {
+		p.workers[i] = make(chan *poolJob, queueDepth)
+	}
+	return p
+}
+
+// Start starts workers of the pool
+func (p *Pool) Start() {
+	for i := range p.workers {
+		p.wg.Add(1)
+		go p.work(i)
+	}
+}
+
+// Stop stops workers after transactions waiting in the queue are submitted
+func (p *Pool) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.shared)
+		for _, worker := range p.workers {
+			close(worker)
+		}
+	})
+	p.wg.Wait()
+}
+
+func (p *Pool) work(i int) {
+	defer p.wg.Done()
+
+	var channel string
+	if len(p.channels) > 0 {
+		channel = p.channels[i%len(p.channels)]
+	}
+
+	own, shared := p.workers[i], p.shared
+	for own != nil || shared != nil {
+		// Ordered transactions are submitted first, so they don't wait
+		// behind unordered ones
+		select {
+		case job, ok := <-own:
+			if !ok {
+				own = nil
+				continue
+			}
+			p.submit(job, channel)
+			continue
+		default:
+		}
+
+		select {
+		case job, ok := <-own:
+			if !ok {
+				own = nil
+				continue
+			}
+			p.submit(job, channel)
+		case job, ok := <-shared:
+			if !ok {
+				shared = nil
+				continue
+			}
+			p.submit(job, channel)
+		}
+	}
+}
+
+func (p *Pool) submit(job *poolJob, channel string) {
+	poolQueued.Set(float64(atomic.AddInt64(&p.queued, -1)))
+
+	// Requests can time out while waiting in the queue
+	if err := job.ctx.Err(); err != nil {
+		job.result <- poolResult{err: err}
+		return
+	}
+
+	ctx := job.ctx
+	if channel != "" {
+		ctx = WithChannel(ctx, channel)
+	}
+
+	var result poolResult
+	if job.tx != nil {
+		result.response, result.err = p.Submitter.SignAndSubmitRawTransaction(ctx, job.paymentID, job.seed, job.tx)
+	} else {
+		result.response, result.err = p.Submitter.SubmitTransaction(ctx, job.paymentID, job.seed, job.operation, job.memo)
+	}
+	job.result <- result
+}
+
+// enqueue adds job to the queue of the worker of its ordering key or to the
+// shared queue and waits for the result
+func (p *Pool) enqueue(job *poolJob) (horizon.SubmitTransactionResponse, error) {
+	queued := atomic.AddInt64(&p.queued, 1)
+	if queued > p.queueDepth {
+		atomic.AddInt64(&p.queued, -1)
+		poolRejected.Inc()
+		return horizon.SubmitTransactionResponse{}, ErrQueueFull
+	}
+	poolQueued.Set(float64(queued))
+
+	job.result = make(chan poolResult, 1)
+	queue := p.shared
+	if key := OrderingKey(job.ctx); key != "" {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		queue = p.workers[h.Sum32()%uint32(len(p.workers))]
+	}
+	// Queues are buffered to queueDepth so this never blocks
+	queue <- job
+
+	// The result is awaited even when ctx is done: the transaction can be
+	// already submitted
+	result := <-job.result
+	return result.response, result.err
+}
+
+// BuildTransaction builds the transaction with Submitter, it's not queued
+func (p *Pool) BuildTransaction(ctx context.Context, seed string, operation, memo interface{}) (*xdr.Transaction, error) {
+	return p.Submitter.BuildTransaction(ctx, seed, operation, memo)
+}
+
+// SubmitTransaction queues the transaction and waits until it's submitted.
+// ErrQueueFull is returned when the queue is full.
+func (p *Pool) SubmitTransaction(ctx context.Context, paymentID *string, seed string, operation, memo interface{}) (horizon.SubmitTransactionResponse, error) {
+	return p.enqueue(&poolJob{ctx: ctx, paymentID: paymentID, seed: seed, operation: operation, memo: memo})
+}
+
+// SignAndSubmitRawTransaction queues the transaction and waits until it's
+// submitted. ErrQueueFull is returned when the queue is full.
+func (p *Pool) SignAndSubmitRawTransaction(ctx context.Context, paymentID *string, seed string, tx *xdr.Transaction) (horizon.SubmitTransactionResponse, error) {
+	return p.enqueue(&poolJob{ctx: ctx, paymentID: paymentID, seed: seed, tx: tx})
+}
+
+type orderingKeyContextKey struct{}
+
+// WithOrderingKey returns ctx with a key of transactions that must be
+// submitted in order, ex. the destination of payments. Pool submits
+// transactions with the same key one by one.
+func WithOrderingKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, orderingKeyContextKey{}, key)
+}
+
+// OrderingKey returns the key added to ctx by WithOrderingKey
+func OrderingKey(ctx context.Context) string {
+	key, _ := ctx.Value(orderingKeyContextKey{}).(string)
+	return key
+}
+
+type channelContextKey struct{}
+
+// WithChannel returns ctx with a seed of a channel account: the source of
+// transactions submitted with it. The submitting account is kept as the
+// source of operations and cosigns transactions, so transactions of one
+// account can be submitted concurrently from many channels.
+func WithChannel(ctx context.Context, seed string) context.Context {
+	return context.WithValue(ctx, channelContextKey{}, seed)
+}
+
+// Channel returns the channel seed added to ctx by WithChannel
+func Channel(ctx context.Context) string {
+	seed, _ := ctx.Value(channelContextKey{}).(string)
+	return seed
+}
@@ -0,0 +1,168 @@
+package submitter
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testSubmitter records IDs of submitted payments and channels they were
+// submitted from, submissions wait for release when it's set
+type testSubmitter struct {
+	mutex     sync.Mutex
+	submitted []string
+	channels  map[string]bool
+	release   chan struct{}
+}
+
+func (s *testSubmitter) BuildTransaction(ctx context.Context, seed string, operation, memo interface{}) (*xdr.Transaction, error) {
+	return &xdr.Transaction{}, nil
+}
+
+func (s *testSubmitter) SubmitTransaction(ctx context.Context, paymentID *string, seed string, operation, memo interface{}) (horizon.SubmitTransactionResponse, error) {
+	return s.SignAndSubmitRawTransaction(ctx, paymentID, seed, nil)
+}
+
+func (s *testSubmitter) SignAndSubmitRawTransaction(ctx context.Context, paymentID *string, seed string, tx *xdr.Transaction) (horizon.SubmitTransactionResponse, error) {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.submitted = append(s.submitted, *paymentID)
+	s.channels[Channel(ctx)] = true
+	return horizon.SubmitTransactionResponse{Hash: *paymentID}, nil
+}
+
+func TestPoolOrdering(t *testing.T) {
+	s := &testSubmitter{channels: map[string]bool{}, release: make(chan struct{})}
+	pool := NewPool(s, 4, 100, []string{"channel-1", "channel-2"})
+	pool.Start()
+
+	var wg sync.WaitGroup
+	submit := func(ctx context.Context, paymentID string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := pool.SubmitTransaction(ctx, &paymentID, "seed", nil, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, paymentID, response.Hash)
+		}()
+	}
+
+	// Unordered transactions are taken by all workers
+	for i := 0; i < 4; i++ {
+		submit(context.Background(), "unordered-"+strconv.Itoa(i))
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	ctx := WithOrderingKey(context.Background(), "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632")
+	for i := 0; i < 10; i++ {
+		submit(ctx, strconv.Itoa(i))
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	close(s.release)
+	wg.Wait()
+	pool.Stop()
+
+	var ordered []string
+	for _, paymentID := range s.submitted {
+		if _, err := strconv.Atoi(paymentID); err == nil {
+			ordered = append(ordered, paymentID)
+		}
+	}
+	require.Len(t, ordered, 10)
+	for i, paymentID := range ordered {
+		assert.Equal(t, strconv.Itoa(i), paymentID)
+	}
+	assert.Len(t, s.submitted, 14)
+	assert.Equal(t, map[string]bool{"channel-1": true, "channel-2": true}, s.channels)
+}
+
+func TestPoolQueueFull(t *testing.T) {
+	s := &testSubmitter{channels: map[string]bool{}, release: make(chan struct{})}
+	pool := NewPool(s, 1, 2, nil)
+	pool.Start()
+
+	results := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		paymentID := strconv.Itoa(i)
+		go func() {
+			_, err := pool.SubmitTransaction(context.Background(), &paymentID, "seed", nil, nil)
+			results <- err
+		}()
+		// Wait until the transaction is taken by the worker or queued
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	paymentID := "rejected"
+	_, err := pool.SubmitTransaction(context.Background(), &paymentID, "seed", nil, nil)
+	assert.Equal(t, ErrQueueFull, err)
+
+	close(s.release)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, <-results)
+	}
+	pool.Stop()
+	assert.Len(t, s.submitted, 3)
+}
+
+func TestChannelAccounts(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	channel, err := keypair.Random()
+	require.NoError(t, err)
+
+	mockHorizon := new(mocks.MockHorizon)
+	mockEntityManager := new(mocks.MockEntityManager)
+	ts := NewTransactionSubmitter(mockHorizon, mockEntityManager, "Test SDF Network ; September 2015", time.Now)
+	ts.Accounts[kp.Seed()] = &Account{Seed: kp.Seed(), Keypair: kp, SequenceNumber: 10}
+	ts.Accounts[channel.Seed()] = &Account{Seed: channel.Seed(), Keypair: channel, SequenceNumber: 20}
+
+	var envelope xdr.TransactionEnvelope
+	ledger := uint64(100)
+	mockEntityManager.On("Persist", mock.AnythingOfType("*entities.SentTransaction")).Return(nil)
+	mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		envelope = xdr.TransactionEnvelope{}
+		require.NoError(t, xdr.SafeUnmarshalBase64(args.String(0), &envelope))
+	}).Return(horizon.SubmitTransactionResponse{Ledger: &ledger}, nil)
+
+	tx := newTestTransaction(t, kp.Address())
+	tx.Operations = []xdr.Operation{{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}}}
+	_, err = ts.SignAndSubmitRawTransaction(WithChannel(context.Background(), channel.Seed()), nil, kp.Seed(), tx)
+	require.NoError(t, err)
+
+	// Channel is the source of the transaction, the account is the source
+	// of its operations
+	assert.Equal(t, channel.Address(), envelope.Tx.SourceAccount.Address())
+	assert.Equal(t, xdr.SequenceNumber(21), envelope.Tx.SeqNum)
+	require.NotNil(t, envelope.Tx.Operations[0].SourceAccount)
+	assert.Equal(t, kp.Address(), envelope.Tx.Operations[0].SourceAccount.Address())
+	assert.Equal(t, uint64(10), ts.Accounts[kp.Seed()].SequenceNumber)
+
+	hash, err := TransactionHash(&envelope.Tx, "Test SDF Network ; September 2015")
+	require.NoError(t, err)
+	require.Len(t, envelope.Signatures, 2)
+	assert.NoError(t, channel.Verify(hash[:], envelope.Signatures[0].Signature))
+	assert.NoError(t, kp.Verify(hash[:], envelope.Signatures[1].Signature))
+
+	// Without a channel the account is the source
+	tx = newTestTransaction(t, kp.Address())
+	tx.Operations = []xdr.Operation{{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}}}
+	_, err = ts.SignAndSubmitRawTransaction(context.Background(), nil, kp.Seed(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, kp.Address(), envelope.Tx.SourceAccount.Address())
+	assert.Nil(t, envelope.Tx.Operations[0].SourceAccount)
+	assert.Len(t, envelope.Signatures, 1)
+}
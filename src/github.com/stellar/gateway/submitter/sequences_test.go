@@ -0,0 +1,67 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSequences struct {
+	last map[string]uint64
+	err  error
+}
+
+func (s *testSequences) Next(ctx context.Context, accountID string, last uint64) (uint64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	if _, ok := s.last[accountID]; !ok {
+		s.last[accountID] = last
+	}
+	s.last[accountID]++
+	return s.last[accountID], nil
+}
+
+func (s *testSequences) Last(ctx context.Context, accountID string) (uint64, error) {
+	return s.last[accountID], s.err
+}
+
+func (s *testSequences) Reset(ctx context.Context, accountID string, last uint64) error {
+	s.last[accountID] = last
+	return s.err
+}
+
+func TestNextSequence(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+
+	ts := NewTransactionSubmitter(new(mocks.MockHorizon), new(mocks.MockEntityManager), "Test SDF Network ; September 2015", time.Now)
+	account := &Account{Seed: kp.Seed(), Keypair: kp, SequenceNumber: 10}
+
+	require.NoError(t, ts.nextSequence(context.Background(), account))
+	assert.Equal(t, uint64(11), account.SequenceNumber)
+
+	// Another server used sequence numbers up to 20
+	sequences := &testSequences{last: map[string]uint64{kp.Address(): 20}}
+	ts.Sequences = sequences
+	require.NoError(t, ts.nextSequence(context.Background(), account))
+	assert.Equal(t, uint64(21), account.SequenceNumber)
+	assert.Equal(t, uint64(21), sequences.last[kp.Address()])
+
+	// Local counter could reuse sequence numbers of other servers
+	sequences.err = errors.New("connection refused")
+	assert.Error(t, ts.nextSequence(context.Background(), account))
+	assert.Equal(t, uint64(21), account.SequenceNumber)
+
+	// Transaction is not submitted
+	ts.Accounts[kp.Seed()] = account
+	_, err = ts.SignAndSubmitRawTransaction(context.Background(), nil, kp.Seed(), &xdr.Transaction{})
+	assert.Error(t, err)
+}
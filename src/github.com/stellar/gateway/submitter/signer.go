@@ -0,0 +1,182 @@
+package submitter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/protocols/signer"
+	"github.com/stellar/gateway/signers"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// Signer signs transactions of accounts configured by public key only, so
+// secret seeds never reach the bridge server host. RemoteSigner and
+// KeyringSigner are provided, programs embedding the bridge server can sign
+// with their own implementation.
+type Signer interface {
+	Sign(accountID string, tx *xdr.Transaction, hash [32]byte) (xdr.DecoratedSignature, error)
+}
+
+// backendNamer is implemented by signers that can name the backend signing
+// transactions of an account, the name is sent in key usage events
+type backendNamer interface {
+	BackendName(accountID string) string
+}
+
+// signerName returns the name of the backend of s signing transactions of
+// accountID
+func signerName(s Signer, accountID string) string {
+	if namer, ok := s.(backendNamer); ok {
+		return namer.BackendName(accountID)
+	}
+	return "remote"
+}
+
+// KeyringSigner signs transactions using signing backends (HSM, KMS) of
+// Keys. Accounts without a backend are signed by Next, it can be nil.
+type KeyringSigner struct {
+	Keys *signers.Keyring
+	Next Signer
+}
+
+// Sign implements Signer
+func (s *KeyringSigner) Sign(accountID string, tx *xdr.Transaction, hash [32]byte) (signature xdr.DecoratedSignature, err error) {
+	if !s.Keys.Has(accountID) && s.Next != nil {
+		return s.Next.Sign(accountID, tx, hash)
+	}
+
+	kp, err := keypair.Parse(accountID)
+	if err != nil {
+		return
+	}
+
+	rawSignature, err := s.Keys.Sign(accountID, hash[:])
+	if err != nil {
+		return
+	}
+
+	signature.Hint = xdr.SignatureHint(kp.Hint())
+	signature.Signature = xdr.Signature(rawSignature)
+	return
+}
+
+// BackendName returns the name of the backend signing transactions of
+// accountID
+func (s *KeyringSigner) BackendName(accountID string) string {
+	if !s.Keys.Has(accountID) && s.Next != nil {
+		return signerName(s.Next, accountID)
+	}
+	return s.Keys.BackendName(accountID)
+}
+
+// HTTP represents an http client that a remote signer can use to make HTTP
+// requests.
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// RemoteSigner requests signatures from an external signing service using
+// protocols/signer. Every request body is authenticated with HMAC-SHA256 sent
+// in X-Signature header, the client can also present a certificate (mutual
+// TLS) when Client is configured with one.
+// The signing service is expected to check the transaction against its own
+// policies (allowed destinations, assets, amounts) before signing it.
+type RemoteSigner struct {
+	URL               string
+	Secret            string
+	NetworkPassphrase string
+	Client            HTTP
+	now               func() time.Time
+}
+
+// NewRemoteSigner creates a new RemoteSigner
+func NewRemoteSigner(url, secret, networkPassphrase string, client HTTP) *RemoteSigner {
+	return &RemoteSigner{
+		URL:               url,
+		Secret:            secret,
+		NetworkPassphrase: networkPassphrase,
+		Client:            client,
+		now:               clock.Now,
+	}
+}
+
+// Sign implements Signer. The returned signature is verified using the
+// account public key.
+func (s *RemoteSigner) Sign(accountID string, tx *xdr.Transaction, hash [32]byte) (signature xdr.DecoratedSignature, err error) {
+	kp, err := keypair.Parse(accountID)
+	if err != nil {
+		return
+	}
+
+	txB64, err := xdr.MarshalBase64(tx)
+	if err != nil {
+		return
+	}
+
+	body, err := json.Marshal(signer.SignRequest{
+		AccountID:         kp.Address(),
+		NetworkPassphrase: s.NetworkPassphrase,
+		Transaction:       txB64,
+		Hash:              hex.EncodeToString(hash[:]),
+		Timestamp:         s.now().Unix(),
+	})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", s.URL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signer.SignatureHeader, signer.MAC(s.Secret, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		err = errors.Wrap(err, "Error sending request to signing service")
+		return
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	var response signer.SignResponse
+	err = json.Unmarshal(responseBody, &response)
+	if err != nil {
+		err = errors.Wrap(err, "Cannot decode signing service response")
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("Signing service rejected transaction (status %d): %s", resp.StatusCode, response.Error)
+		return
+	}
+
+	rawSignature, err := base64.StdEncoding.DecodeString(response.Signature)
+	if err != nil {
+		err = errors.Wrap(err, "Cannot decode signature")
+		return
+	}
+
+	err = kp.Verify(hash[:], rawSignature)
+	if err != nil {
+		err = errors.New("Invalid signature returned by signing service")
+		return
+	}
+
+	signature.Hint = xdr.SignatureHint(kp.Hint())
+	signature.Signature = xdr.Signature(rawSignature)
+	return
+}
@@ -0,0 +1,174 @@
+package submitter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/protocols/signer"
+	"github.com/stellar/gateway/signers"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigningService(t *testing.T, kp keypair.KP) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if !signer.VerifyMAC("secret", body, r.Header.Get(signer.SignatureHeader)) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": "invalid request signature"}`))
+			return
+		}
+
+		var request signer.SignRequest
+		require.NoError(t, json.Unmarshal(body, &request))
+		hash, err := hex.DecodeString(request.Hash)
+		require.NoError(t, err)
+
+		signature, err := kp.Sign(hash)
+		require.NoError(t, err)
+		w.Write([]byte(`{"signature": "` + base64.StdEncoding.EncodeToString(signature) + `"}`))
+	}))
+}
+
+func newTestTransaction(t *testing.T, source string) *xdr.Transaction {
+	tx := &xdr.Transaction{Fee: 100, SeqNum: 1, Memo: xdr.Memo{Type: xdr.MemoTypeMemoNone}}
+	require.NoError(t, tx.SourceAccount.SetAddress(source))
+	return tx
+}
+
+func TestRemoteSigner(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+
+	server := newTestSigningService(t, kp)
+	defer server.Close()
+
+	tx := newTestTransaction(t, kp.Address())
+	hash, err := TransactionHash(tx, "Test SDF Network ; September 2015")
+	require.NoError(t, err)
+
+	signer := NewRemoteSigner(server.URL, "secret", "Test SDF Network ; September 2015", http.DefaultClient)
+	signature, err := signer.Sign(kp.Address(), tx, hash)
+	require.NoError(t, err)
+	assert.Equal(t, xdr.SignatureHint(kp.Hint()), signature.Hint)
+	assert.NoError(t, kp.Verify(hash[:], signature.Signature))
+
+	signer.Secret = "wrong"
+	_, err = signer.Sign(kp.Address(), tx, hash)
+	assert.EqualError(t, err, "Signing service rejected transaction (status 403): invalid request signature")
+}
+
+func TestRemoteSignerInvalidSignature(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	otherKP, err := keypair.Random()
+	require.NoError(t, err)
+
+	server := newTestSigningService(t, otherKP)
+	defer server.Close()
+
+	tx := newTestTransaction(t, kp.Address())
+	hash, err := TransactionHash(tx, "Test SDF Network ; September 2015")
+	require.NoError(t, err)
+
+	signer := NewRemoteSigner(server.URL, "secret", "Test SDF Network ; September 2015", http.DefaultClient)
+	_, err = signer.Sign(kp.Address(), tx, hash)
+	assert.EqualError(t, err, "Invalid signature returned by signing service")
+}
+
+func TestSignWithKeyring(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	public, err := keypair.Parse(kp.Address())
+	require.NoError(t, err)
+
+	ts := NewTransactionSubmitter(new(mocks.MockHorizon), new(mocks.MockEntityManager), "Test SDF Network ; September 2015", time.Now)
+	// Signing backends take precedence over the remote signer
+	keys, err := signers.Load([]signers.Config{{Account: kp.Address(), Backend: signers.BackendSeed, Seed: kp.Seed()}}, http.DefaultClient)
+	require.NoError(t, err)
+	ts.Signer = &KeyringSigner{
+		Keys: keys,
+		Next: NewRemoteSigner("http://127.0.0.1:0", "secret", "Test SDF Network ; September 2015", http.DefaultClient),
+	}
+	assert.Equal(t, signers.BackendSeed, signerName(ts.Signer, kp.Address()))
+	assert.Equal(t, "remote", signerName(ts.Signer, "GAPCT362RATBUJ37RN2MOKQIZLHSJMO33MMCSRUXTTHIGVDYWOFG5HDS"))
+
+	tx := newTestTransaction(t, kp.Address())
+	hash, err := TransactionHash(tx, "Test SDF Network ; September 2015")
+	require.NoError(t, err)
+
+	transactionID, txeB64, err := ts.sign(&Account{Keypair: public}, tx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(hash[:]), transactionID)
+
+	var envelope xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(txeB64, &envelope))
+	require.Len(t, envelope.Signatures, 1)
+	assert.Equal(t, xdr.SignatureHint(kp.Hint()), envelope.Signatures[0].Hint)
+	assert.NoError(t, kp.Verify(hash[:], envelope.Signatures[0].Signature))
+}
+
+func TestSignWithCosigners(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	cosigner, err := keypair.Random()
+	require.NoError(t, err)
+
+	ctx := WithCosigners(context.Background(), cosigner)
+	assert.Equal(t, []*keypair.Full{cosigner}, Cosigners(ctx))
+	assert.Empty(t, Cosigners(context.Background()))
+
+	ts := NewTransactionSubmitter(new(mocks.MockHorizon), new(mocks.MockEntityManager), "Test SDF Network ; September 2015", time.Now)
+	tx := newTestTransaction(t, kp.Address())
+	hash, err := TransactionHash(tx, "Test SDF Network ; September 2015")
+	require.NoError(t, err)
+
+	_, txeB64, err := ts.sign(&Account{Keypair: kp}, tx, Cosigners(ctx))
+	require.NoError(t, err)
+
+	var envelope xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(txeB64, &envelope))
+	require.Len(t, envelope.Signatures, 2)
+	assert.NoError(t, kp.Verify(hash[:], envelope.Signatures[0].Signature))
+	assert.Equal(t, xdr.SignatureHint(cosigner.Hint()), envelope.Signatures[1].Hint)
+	assert.NoError(t, cosigner.Verify(hash[:], envelope.Signatures[1].Signature))
+}
+
+func TestRemoteSignerService(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	otherKP, err := keypair.Random()
+	require.NoError(t, err)
+
+	keys, err := signers.Load([]signers.Config{{Account: kp.Address(), Backend: signers.BackendSeed, Seed: kp.Seed()}}, http.DefaultClient)
+	require.NoError(t, err)
+	server := httptest.NewServer(signers.NewService(keys, "secret", "Test SDF Network ; September 2015"))
+	defer server.Close()
+
+	tx := newTestTransaction(t, kp.Address())
+	hash, err := TransactionHash(tx, "Test SDF Network ; September 2015")
+	require.NoError(t, err)
+
+	signer := NewRemoteSigner(server.URL, "secret", "Test SDF Network ; September 2015", http.DefaultClient)
+	signature, err := signer.Sign(kp.Address(), tx, hash)
+	require.NoError(t, err)
+	assert.NoError(t, kp.Verify(hash[:], signature.Signature))
+
+	tx = newTestTransaction(t, otherKP.Address())
+	hash, err = TransactionHash(tx, "Test SDF Network ; September 2015")
+	require.NoError(t, err)
+	_, err = signer.Sign(otherKP.Address(), tx, hash)
+	assert.EqualError(t, err, "Signing service rejected transaction (status 403): unknown account")
+}
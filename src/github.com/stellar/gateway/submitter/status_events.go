@@ -0,0 +1,164 @@
+package submitter
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/webhooks"
+	"github.com/stellar/go/xdr"
+)
+
+// StatusWatcher receives statuses of sent transactions, see
+// grpcserver.StatusHub. TransactionStatus must not block.
+type StatusWatcher interface {
+	TransactionStatus(sentTransaction *entities.SentTransaction)
+}
+
+// watchStatus sends a copy of sentTransaction to StatusWatcher, so later
+// changes of the transaction are not seen by watchers
+func (ts *TransactionSubmitter) watchStatus(sentTransaction *entities.SentTransaction) {
+	if ts.StatusWatcher == nil {
+		return
+	}
+	status := *sentTransaction
+	ts.StatusWatcher.TransactionStatus(&status)
+}
+
+type paymentFeeContextKey struct{}
+
+// WithPaymentFee returns ctx with the fee charged for the payment of
+// transactions submitted with it, the fee is added to their status events
+func WithPaymentFee(ctx context.Context, fee *horizon.PaymentFee) context.Context {
+	return context.WithValue(ctx, paymentFeeContextKey{}, fee)
+}
+
+// PaymentFee returns the fee added to ctx by WithPaymentFee
+func PaymentFee(ctx context.Context) *horizon.PaymentFee {
+	fee, _ := ctx.Value(paymentFeeContextKey{}).(*horizon.PaymentFee)
+	return fee
+}
+
+type metadataContextKey struct{}
+
+// WithMetadata returns ctx with metadata of the payment of transactions
+// submitted with it, metadata are stored with the sent transactions
+func WithMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	return context.WithValue(ctx, metadataContextKey{}, metadata)
+}
+
+// Metadata returns metadata added to ctx by WithMetadata
+func Metadata(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataContextKey{}).(map[string]string)
+	return metadata
+}
+
+type acceptedContextKey struct{}
+
+// WithAccepted returns ctx calling accepted with the transaction submitted
+// with it as soon as it's signed and saved, before it's submitted to the
+// network. It lets handlers respond before the submission finishes.
+func WithAccepted(ctx context.Context, accepted func(*entities.SentTransaction)) context.Context {
+	return context.WithValue(ctx, acceptedContextKey{}, accepted)
+}
+
+// notifyAccepted calls the function added to ctx by WithAccepted
+func notifyAccepted(ctx context.Context, sentTransaction *entities.SentTransaction) {
+	if accepted, ok := ctx.Value(acceptedContextKey{}).(func(*entities.SentTransaction)); ok {
+		status := *sentTransaction
+		accepted(&status)
+	}
+}
+
+// statusEvent is a message of StatusEvents queue
+type statusEvent struct {
+	*entities.SentTransaction
+	Fee *horizon.PaymentFee `json:"fee,omitempty"`
+}
+
+// publishStatusEvent publishes the status of a sent transaction to
+// StatusEvents queue and StatusWatcher. Message is a JSON-encoded
+// SentTransaction with the fee of the payment when it's known, wrapped in a
+// CloudEvent when StatusEventsFormat is set. Errors are
+// logged only because the transaction has already been submitted.
+func (ts *TransactionSubmitter) publishStatusEvent(tx *xdr.Transaction, sentTransaction *entities.SentTransaction, fee *horizon.PaymentFee) {
+	ts.watchStatus(sentTransaction)
+	if ts.StatusEvents == nil {
+		return
+	}
+
+	id := sentTransaction.TransactionID + "-" + string(sentTransaction.Status)
+	message, err := ts.StatusEventsFormat.Marshal(statusEventType(sentTransaction), id, statusEvent{sentTransaction, fee})
+	if err != nil {
+		ts.log.WithFields(logrus.Fields{"err": err}).Error("Cannot encode transaction status event")
+		return
+	}
+
+	var assetCode, assetIssuer string
+	for _, operation := range tx.Operations {
+		var asset xdr.Asset
+		if payment, ok := operation.Body.GetPaymentOp(); ok {
+			asset = payment.Asset
+		} else if pathPayment, ok := operation.Body.GetPathPaymentOp(); ok {
+			asset = pathPayment.DestAsset
+		} else {
+			continue
+		}
+
+		var assetType string
+		asset.Extract(&assetType, &assetCode, &assetIssuer)
+		break
+	}
+
+	values := map[string]string{
+		"asset_code":   assetCode,
+		"asset_issuer": assetIssuer,
+		"account":      sentTransaction.Source,
+	}
+
+	attributes := make(map[string]string)
+	for _, name := range ts.StatusEventAttributes {
+		// Queues do not allow empty attribute values (native asset has no issuer)
+		if values[name] != "" {
+			attributes[name] = values[name]
+		}
+	}
+
+	err = ts.StatusEvents.Publish(message, attributes)
+	if err != nil {
+		ts.log.WithFields(logrus.Fields{
+			"err":            err,
+			"transaction_id": sentTransaction.TransactionID,
+		}).Error("Error publishing transaction status event")
+	}
+}
+
+// publishWebhookEvent sends payment_sent event of a successful transaction or
+// submission_failed event of a failed one to webhooks. Data is the same as
+// the message of StatusEvents queue.
+func (ts *TransactionSubmitter) publishWebhookEvent(ctx context.Context, sentTransaction *entities.SentTransaction, fee *horizon.PaymentFee) {
+	ts.Webhooks.Publish(ctx, statusEventType(sentTransaction), statusEvent{sentTransaction, fee})
+}
+
+// statusEventType returns payment_sent or submission_failed event type of a
+// sent transaction
+func statusEventType(sentTransaction *entities.SentTransaction) string {
+	if sentTransaction.Status == entities.SentTransactionStatusFailure {
+		return webhooks.EventSubmissionFailed
+	}
+	return webhooks.EventPaymentSent
+}
+
+// PublishStatusEvent publishes the status of a sent transaction loaded from
+// the database, ex. after its status has been corrected
+func (ts *TransactionSubmitter) PublishStatusEvent(sentTransaction *entities.SentTransaction) {
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(sentTransaction.EnvelopeXdr, &envelope)
+	if err != nil {
+		ts.log.WithFields(logrus.Fields{"err": err}).Error("Cannot decode sent transaction envelope")
+		return
+	}
+
+	ts.publishStatusEvent(&envelope.Tx, sentTransaction, nil)
+}
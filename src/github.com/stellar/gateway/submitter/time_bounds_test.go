@@ -0,0 +1,52 @@
+package submitter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDriftDetector bool
+
+func (d testDriftDetector) Drifted() bool { return bool(d) }
+
+func TestTimeBounds(t *testing.T) {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ts := NewTransactionSubmitter(new(mocks.MockHorizon), new(mocks.MockEntityManager), "Test SDF Network ; September 2015", func() time.Time { return now })
+	ts.Accounts[kp.Seed()] = &Account{Seed: kp.Seed(), Keypair: kp, SequenceNumber: 10}
+
+	operation := build.Payment(
+		build.Destination{AddressOrSeed: "GDSIKW43UA6JTOA47WVEBCZ4MYC74M3GNKNXTVDXFHXYYTNO5GGVN632"},
+		build.NativeAmount{Amount: "10"},
+	)
+
+	tx, err := ts.BuildTransaction(context.Background(), kp.Seed(), operation, nil)
+	require.NoError(t, err)
+	assert.Nil(t, tx.TimeBounds)
+
+	ts.TimeBounds = 5 * time.Minute
+	tx, err = ts.BuildTransaction(context.Background(), kp.Seed(), operation, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &xdr.TimeBounds{MaxTime: xdr.Uint64(now.Add(5 * time.Minute).Unix())}, tx.TimeBounds)
+
+	ts.Drift = testDriftDetector(false)
+	tx, err = ts.BuildTransaction(context.Background(), kp.Seed(), operation, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, tx.TimeBounds)
+
+	// Time bounds computed from a drifting clock are not applied
+	ts.Drift = testDriftDetector(true)
+	tx, err = ts.BuildTransaction(context.Background(), kp.Seed(), operation, nil)
+	require.NoError(t, err)
+	assert.Nil(t, tx.TimeBounds)
+}
@@ -2,6 +2,7 @@ package submitter
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -10,9 +11,18 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/audit"
+	"github.com/stellar/gateway/cloudevents"
 	"github.com/stellar/gateway/db"
 	"github.com/stellar/gateway/db/entities"
 	"github.com/stellar/gateway/horizon"
+	"github.com/stellar/gateway/incident"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/queue"
+	"github.com/stellar/gateway/rates"
+	"github.com/stellar/gateway/reports"
+	"github.com/stellar/gateway/tracing"
+	"github.com/stellar/gateway/webhooks"
 	"github.com/stellar/go/build"
 	"github.com/stellar/go/hash"
 	"github.com/stellar/go/keypair"
@@ -21,8 +31,9 @@ import (
 
 // TransactionSubmitterInterface helps mocking TransactionSubmitter
 type TransactionSubmitterInterface interface {
-	SubmitTransaction(paymentID *string, seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error)
-	SignAndSubmitRawTransaction(paymentID *string, seed string, tx *xdr.Transaction) (response horizon.SubmitTransactionResponse, err error)
+	BuildTransaction(ctx context.Context, seed string, operation, memo interface{}) (tx *xdr.Transaction, err error)
+	SubmitTransaction(ctx context.Context, paymentID *string, seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error)
+	SignAndSubmitRawTransaction(ctx context.Context, paymentID *string, seed string, tx *xdr.Transaction) (response horizon.SubmitTransactionResponse, err error)
 }
 
 // TransactionSubmitter submits transactions to Stellar Network
@@ -32,8 +43,72 @@ type TransactionSubmitter struct {
 	AccountsMutex sync.Mutex
 	EntityManager db.EntityManagerInterface
 	Network       build.Network
-	log           *logrus.Entry
-	now           func() time.Time
+	// StatusEvents publishes status of sent transactions when set
+	StatusEvents          queue.Publisher
+	StatusEventAttributes []string
+	// StatusEventsFormat makes status events CloudEvents when set
+	StatusEventsFormat *cloudevents.Formatter
+	// StatusWatcher receives status changes of sent transactions when set
+	StatusWatcher StatusWatcher
+	// Region is saved with every sent transaction, see config.Region
+	Region string
+	// Signer signs transactions of accounts configured by public key only
+	Signer Signer
+	// Fees raises fees of transactions during network congestion when set
+	Fees FeeSource
+	// Audit receives key usage events, nil when security events are not sent
+	Audit *audit.Emitter
+	// Incidents captures a diagnostic bundle after repeated submission
+	// failures, nil when bundles are not configured
+	Incidents *incident.Recorder
+	// Aggregates receives successful transactions, nil when the bridge runs
+	// without a database
+	Aggregates *reports.Aggregator
+	// Rates records fiat values of payments of successful transactions, nil
+	// when rates are not configured
+	Rates *rates.Recorder
+	// Webhooks receives payment_sent and submission_failed events, nil when
+	// webhooks are disabled
+	Webhooks *webhooks.Dispatcher
+	// TimeBounds is how long built transactions are valid, transactions have
+	// no time bounds when 0
+	TimeBounds time.Duration
+	// Drift stops applying TimeBounds while the clock drifts when set
+	Drift DriftDetector
+	// Sequences shares sequence numbers with other bridge servers using the
+	// same accounts when set, see redis.Sequences
+	Sequences Sequences
+	log       *logrus.Entry
+	now       func() time.Time
+}
+
+// DriftDetector reports clock drift, see clock.DriftMonitor
+type DriftDetector interface {
+	Drifted() bool
+}
+
+// Sequences allocates sequence numbers of accounts shared by many bridge
+// servers, see redis.Sequences
+type Sequences interface {
+	// Next returns the next sequence number of accountID, starting after
+	// last when the account is not known yet
+	Next(ctx context.Context, accountID string, last uint64) (uint64, error)
+	// Last returns the last allocated sequence number or 0 when unknown
+	Last(ctx context.Context, accountID string) (uint64, error)
+	// Reset sets the last sequence number of accountID
+	Reset(ctx context.Context, accountID string, last uint64) error
+}
+
+// FeeSource provides fees of submitted transactions, see congestion.Monitor
+type FeeSource interface {
+	// Fee returns fee per operation or 0 when the transaction fee should not
+	// be changed
+	Fee() uint32
+	// Notice returns a notice added to responses when the network is
+	// congested or nil otherwise
+	Notice() *horizon.CongestionNotice
+	// Update reloads fee stats
+	Update() error
 }
 
 // Account represents account used to signing and sending transactions
@@ -112,73 +187,119 @@ func (ts *TransactionSubmitter) InitAccount(seed string) (err error) {
 	return
 }
 
+var badSeqResolutions = map[string]*metrics.Counter{}
+
+var insufficientFeeResubmissions = metrics.NewCounter("bridge_tx_insufficient_fee_resubmissions_total", "Number of transactions resubmitted with a higher fee after tx_insufficient_fee response.")
+
+var timeBoundsSkipped = metrics.NewCounter("bridge_tx_time_bounds_skipped_total", "Number of transactions built without time bounds because of clock drift.")
+
+func init() {
+	for _, resolution := range []string{
+		entities.BadSeqResolutionLanded,
+		entities.BadSeqResolutionResubmitted,
+		entities.BadSeqResolutionExternal,
+		entities.BadSeqResolutionUnverified,
+	} {
+		badSeqResolutions[resolution] = metrics.NewCounter(
+			metrics.Label("bridge_tx_bad_seq_total", "resolution", resolution),
+			"Number of tx_bad_seq responses from Horizon by the way they were resolved.",
+		)
+	}
+}
+
 // SignAndSubmitRawTransaction will:
 // - update sequence number of the transaction to the current one,
 // - sign it,
 // - submit it to the network.
-func (ts *TransactionSubmitter) SignAndSubmitRawTransaction(paymentID *string, seed string, tx *xdr.Transaction) (response horizon.SubmitTransactionResponse, err error) {
+// When Horizon responds with tx_bad_seq, see handleBadSequence.
+func (ts *TransactionSubmitter) SignAndSubmitRawTransaction(ctx context.Context, paymentID *string, seed string, tx *xdr.Transaction) (response horizon.SubmitTransactionResponse, err error) {
 	account, err := ts.LoadAccount(seed)
 	if err != nil {
 		return
 	}
 
-	account.Mutex.Lock()
-	account.SequenceNumber++
-	tx.SeqNum = xdr.SequenceNumber(account.SequenceNumber)
-	account.Mutex.Unlock()
-
-	hash, err := TransactionHash(tx, ts.Network.Passphrase)
+	// Source of the transaction: account or a channel account
+	source, cosigners, err := ts.useChannel(ctx, account, tx)
 	if err != nil {
-		ts.log.Print("Error calculating transaction hash")
 		return
 	}
 
-	sig, err := account.Keypair.SignDecorated(hash[:])
+	source.Mutex.Lock()
+	err = ts.nextSequence(ctx, source)
+	tx.SeqNum = xdr.SequenceNumber(source.SequenceNumber)
+	source.Mutex.Unlock()
 	if err != nil {
-		ts.log.Print("Error signing a transaction")
 		return
 	}
 
-	envelopeXdr := xdr.TransactionEnvelope{
-		Tx:         *tx,
-		Signatures: []xdr.DecoratedSignature{sig},
-	}
+	ts.applyFee(tx)
 
-	txeB64, err := xdr.MarshalBase64(envelopeXdr)
+	_, signSpan := tracing.Start(ctx, "transaction.sign")
+	transactionID, txeB64, err := ts.sign(source, tx, cosigners)
+	signSpan.SetError(err)
+	signSpan.Finish()
 	if err != nil {
-		ts.log.WithFields(logrus.Fields{"err": err}).Error("Cannot encode transaction envelope")
-		return
-	}
-
-	transactionHashBytes, err := TransactionHash(tx, ts.Network.Passphrase)
-	if err != nil {
-		ts.log.WithFields(logrus.Fields{"err": err}).Warn("Error calculating tx hash")
 		return
 	}
 
 	sentTransaction := &entities.SentTransaction{
 		PaymentID:     paymentID,
-		TransactionID: hex.EncodeToString(transactionHashBytes[:]),
+		Region:        ts.Region,
+		TransactionID: transactionID,
 		Status:        entities.SentTransactionStatusSending,
 		Source:        account.Keypair.Address(),
 		SubmittedAt:   ts.now(),
 		EnvelopeXdr:   txeB64,
+		Metadata:      Metadata(ctx),
 	}
 	err = ts.EntityManager.Persist(sentTransaction)
 	if err != nil {
 		return
 	}
+	ts.watchStatus(sentTransaction)
+	notifyAccepted(ctx, sentTransaction)
 
 	ts.log.WithFields(logrus.Fields{"tx": txeB64}).Info("Submitting transaction")
+	_, submitSpan := tracing.StartKind(ctx, "horizon.submit", tracing.SpanKindClient)
+	submitSpan.SetAttribute("transaction_id", transactionID)
+	defer submitSpan.Finish()
+
 	response, err = ts.Horizon.SubmitTransaction(txeB64)
 	if err != nil {
+		submitSpan.SetError(err)
 		ts.log.Error("Error submitting transaction ", err)
+		ts.Incidents.Failure(incident.SubmissionFailures, err)
 		return
 	}
 
+	if ts.Fees != nil && horizon.IsInsufficientFee(response) {
+		response, err = ts.handleInsufficientFee(source, tx, cosigners, sentTransaction, response)
+		if err != nil {
+			return
+		}
+	}
+
+	if horizon.IsBadSequence(response) {
+		var resubmission *entities.SentTransaction
+		response, resubmission, err = ts.handleBadSequence(ctx, source, tx, cosigners, sentTransaction, response)
+		if err != nil {
+			return
+		}
+		if resubmission != nil {
+			sentTransaction = resubmission
+		}
+	}
+
+	if ts.Fees != nil {
+		response.Congestion = ts.Fees.Notice()
+	}
+
 	if response.Ledger != nil {
+		submitSpan.SetAttribute("ledger", int64(*response.Ledger))
 		sentTransaction.MarkSucceeded(*response.Ledger)
+		ts.Incidents.Success(incident.SubmissionFailures)
 	} else {
+		submitSpan.SetError(errors.New("Transaction failed"))
 		var result string
 		if response.Extras != nil {
 			result = response.Extras.ResultXdr
@@ -186,29 +307,370 @@ func (ts *TransactionSubmitter) SignAndSubmitRawTransaction(paymentID *string, s
 			result = "<empty>"
 		}
 		sentTransaction.MarkFailed(result)
+		ts.Incidents.Failure(incident.SubmissionFailures, fmt.Errorf("Transaction failed: %s", result))
 	}
 	err = ts.EntityManager.Persist(sentTransaction)
 	if err != nil {
 		return
 	}
 
-	// Sync sequence number
-	if response.Extras != nil && response.Extras.ResultXdr == "AAAAAAAAAAD////7AAAAAA==" {
-		account.Mutex.Lock()
-		ts.log.Print("Syncing sequence number for ", account.Keypair.Address())
-		accountResponse, err2 := ts.Horizon.LoadAccount(account.Keypair.Address())
-		if err2 != nil {
-			ts.log.Error("Error updating sequence number ", err)
-		} else {
-			account.SequenceNumber, _ = strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
+	ts.Aggregates.RecordSent(ctx, sentTransaction)
+	ts.Rates.RecordSent(ctx, sentTransaction)
+	ts.publishStatusEvent(tx, sentTransaction, PaymentFee(ctx))
+	ts.publishWebhookEvent(ctx, sentTransaction, PaymentFee(ctx))
+	return
+}
+
+// handleBadSequence resolves tx_bad_seq response. It checks if the
+// transaction has actually been included in a ledger (ex. when it was
+// submitted to another Horizon endpoint before), then loads the account to
+// check if sequence number has been changed by someone else and resyncs it.
+// The transaction is rebuilt with a new sequence number and submitted again
+// only when the account is not used outside of the bridge server. The way it
+// was resolved is saved in sentTransaction.BadSeqResolution. A resubmitted
+// transaction is saved as a new SentTransaction linked to sentTransaction by
+// OriginalID and returned as resubmission; sentTransaction is kept as failed.
+func (ts *TransactionSubmitter) handleBadSequence(
+	ctx context.Context,
+	account *Account,
+	tx *xdr.Transaction,
+	cosigners []*keypair.Full,
+	sentTransaction *entities.SentTransaction,
+	badSeqResponse horizon.SubmitTransactionResponse,
+) (response horizon.SubmitTransactionResponse, resubmission *entities.SentTransaction, err error) {
+	response = badSeqResponse
+	resolution := entities.BadSeqResolutionUnverified
+	localLog := ts.log.WithFields(logrus.Fields{
+		"account": account.Keypair.Address(),
+		"tx_id":   sentTransaction.TransactionID,
+	})
+
+	defer func() {
+		sentTransaction.BadSeqResolution = &resolution
+		badSeqResolutions[resolution].Inc()
+		localLog.WithFields(logrus.Fields{"resolution": resolution}).Info("Resolved tx_bad_seq response")
+	}()
+
+	landed, loadErr := ts.Horizon.LoadTransaction(sentTransaction.TransactionID)
+	if loadErr == nil && landed.Ledger != nil {
+		resolution = entities.BadSeqResolutionLanded
+		response = landed
+		return
+	}
+
+	account.Mutex.Lock()
+	accountResponse, loadErr := ts.Horizon.LoadAccount(account.Keypair.Address())
+	if loadErr != nil {
+		account.Mutex.Unlock()
+		localLog.WithFields(logrus.Fields{"err": loadErr}).Error("Error updating sequence number")
+		return
+	}
+
+	sequenceNumber, loadErr := strconv.ParseUint(accountResponse.SequenceNumber, 10, 64)
+	if loadErr != nil {
+		account.Mutex.Unlock()
+		localLog.WithFields(logrus.Fields{"err": loadErr}).Error("Invalid sequence number")
+		return
+	}
+
+	// account.SequenceNumber is the last sequence number used by the bridge
+	// server so the greater one in a ledger can be only used by someone else.
+	// With shared sequences other servers may have used greater ones.
+	local := account.SequenceNumber
+	if ts.Sequences != nil {
+		// Without it the sequence number used by another server looks like
+		// an external one
+		shared, sharedErr := ts.Sequences.Last(ctx, account.Keypair.Address())
+		if sharedErr != nil {
+			account.Mutex.Unlock()
+			localLog.WithFields(logrus.Fields{"err": sharedErr}).Error("Error getting shared sequence number")
+			return
+		}
+		if shared > local {
+			local = shared
 		}
+	}
+	external := sequenceNumber > local
+	localLog.WithFields(logrus.Fields{
+		"local":  local,
+		"ledger": sequenceNumber,
+	}).Print("Syncing sequence number")
+	account.SequenceNumber = sequenceNumber
+	if ts.Sequences != nil {
+		resetErr := ts.Sequences.Reset(ctx, account.Keypair.Address(), sequenceNumber)
+		if resetErr != nil {
+			localLog.WithFields(logrus.Fields{"err": resetErr}).Warn("Error resetting shared sequence number")
+		}
+	}
+
+	if external {
 		account.Mutex.Unlock()
+		resolution = entities.BadSeqResolutionExternal
+		localLog.Warn("Account sequence number has been changed outside of the bridge server, not resubmitting transaction")
+		return
+	}
+
+	seqErr := ts.nextSequence(ctx, account)
+	tx.SeqNum = xdr.SequenceNumber(account.SequenceNumber)
+	account.Mutex.Unlock()
+	if seqErr != nil {
+		localLog.WithFields(logrus.Fields{"err": seqErr}).Error("Error getting sequence number, not resubmitting transaction")
+		return
+	}
+
+	transactionID, txeB64, err := ts.sign(account, tx, cosigners)
+	if err != nil {
+		return
+	}
+
+	resolution = entities.BadSeqResolutionResubmitted
+	result := "<empty>"
+	if badSeqResponse.Extras != nil {
+		result = badSeqResponse.Extras.ResultXdr
+	}
+
+	// Payment ID is unique, it's moved to the resubmission so the payment
+	// resolves to the transaction submitted last
+	paymentID := sentTransaction.PaymentID
+	sentTransaction.PaymentID = nil
+	sentTransaction.BadSeqResolution = &resolution
+	sentTransaction.MarkFailed(result)
+	err = ts.EntityManager.Persist(sentTransaction)
+	if err != nil {
+		return
+	}
+	ts.watchStatus(sentTransaction)
+
+	resubmission = &entities.SentTransaction{
+		PaymentID:     paymentID,
+		OriginalID:    sentTransaction.ID,
+		Region:        sentTransaction.Region,
+		TransactionID: transactionID,
+		Status:        entities.SentTransactionStatusSending,
+		Source:        sentTransaction.Source,
+		SubmittedAt:   ts.now(),
+		EnvelopeXdr:   txeB64,
+		Metadata:      sentTransaction.Metadata,
+	}
+	err = ts.EntityManager.Persist(resubmission)
+	if err != nil {
+		return
+	}
+	ts.watchStatus(resubmission)
+
+	localLog.WithFields(logrus.Fields{"tx": txeB64}).Info("Resubmitting transaction")
+	response, err = ts.Horizon.SubmitTransaction(txeB64)
+	if err != nil {
+		localLog.Error("Error submitting transaction ", err)
 	}
 	return
 }
 
+// nextSequence increments the sequence number of account, taking it from
+// ts.Sequences when set. It returns an error when shared sequences are
+// unavailable: the local counter can reuse sequence numbers taken by other
+// servers. account.Mutex must be held.
+func (ts *TransactionSubmitter) nextSequence(ctx context.Context, account *Account) error {
+	if ts.Sequences != nil {
+		next, err := ts.Sequences.Next(ctx, account.Keypair.Address(), account.SequenceNumber)
+		if err != nil {
+			return fmt.Errorf("Error getting shared sequence number: %s", err)
+		}
+		account.SequenceNumber = next
+		return nil
+	}
+	account.SequenceNumber++
+	return nil
+}
+
+// useChannel makes the channel account added to ctx by WithChannel the source
+// of tx. Operations without a source account keep account as their source, so
+// account cosigns the transaction. It returns the source account of tx and
+// its cosigners. Channels are used only for accounts configured by seed.
+func (ts *TransactionSubmitter) useChannel(ctx context.Context, account *Account, tx *xdr.Transaction) (*Account, []*keypair.Full, error) {
+	cosigners := Cosigners(ctx)
+	full, ok := account.Keypair.(*keypair.Full)
+	seed := Channel(ctx)
+	if seed == "" || seed == account.Seed || !ok {
+		return account, cosigners, nil
+	}
+
+	channel, err := ts.LoadAccount(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range tx.Operations {
+		if tx.Operations[i].SourceAccount == nil {
+			source := tx.SourceAccount
+			tx.Operations[i].SourceAccount = &source
+		}
+	}
+
+	err = tx.SourceAccount.SetAddress(channel.Keypair.Address())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return channel, append([]*keypair.Full{full}, cosigners...), nil
+}
+
+// applyFee raises the fee of the transaction to the fee returned by Fees
+// multiplied by the number of operations. Higher fees are never lowered.
+func (ts *TransactionSubmitter) applyFee(tx *xdr.Transaction) {
+	if ts.Fees == nil {
+		return
+	}
+
+	fee := xdr.Uint32(ts.Fees.Fee()) * xdr.Uint32(len(tx.Operations))
+	if fee > tx.Fee {
+		tx.Fee = fee
+	}
+}
+
+// handleInsufficientFee resolves tx_insufficient_fee response. Fee stats are
+// reloaded and the transaction is resubmitted once when the fee has been
+// raised. Rejected transactions do not consume sequence numbers so the same
+// sequence number is used.
+func (ts *TransactionSubmitter) handleInsufficientFee(
+	account *Account,
+	tx *xdr.Transaction,
+	cosigners []*keypair.Full,
+	sentTransaction *entities.SentTransaction,
+	insufficientFeeResponse horizon.SubmitTransactionResponse,
+) (response horizon.SubmitTransactionResponse, err error) {
+	response = insufficientFeeResponse
+	localLog := ts.log.WithFields(logrus.Fields{
+		"account": account.Keypair.Address(),
+		"tx_id":   sentTransaction.TransactionID,
+	})
+
+	updateErr := ts.Fees.Update()
+	if updateErr != nil {
+		localLog.WithFields(logrus.Fields{"err": updateErr}).Error("Error updating fees")
+		return
+	}
+
+	previousFee := tx.Fee
+	ts.applyFee(tx)
+	if tx.Fee == previousFee {
+		localLog.WithFields(logrus.Fields{"fee": tx.Fee}).Warn("Transaction fee is insufficient but fees have not changed, not resubmitting transaction")
+		return
+	}
+
+	transactionID, txeB64, err := ts.sign(account, tx, cosigners)
+	if err != nil {
+		return
+	}
+
+	sentTransaction.TransactionID = transactionID
+	sentTransaction.EnvelopeXdr = txeB64
+	err = ts.EntityManager.Persist(sentTransaction)
+	if err != nil {
+		return
+	}
+
+	insufficientFeeResubmissions.Inc()
+	localLog.WithFields(logrus.Fields{"fee": tx.Fee, "previous_fee": previousFee}).Info("Resubmitting transaction with a higher fee")
+	response, err = ts.Horizon.SubmitTransaction(txeB64)
+	if err != nil {
+		localLog.Error("Error submitting transaction ", err)
+	}
+	return
+}
+
+// sign signs the transaction with account keypair (or Signer when only the
+// public key is known) and cosigners and returns its hash and base64 encoded
+// envelope
+func (ts *TransactionSubmitter) sign(account *Account, tx *xdr.Transaction, cosigners []*keypair.Full) (transactionID, txeB64 string, err error) {
+	hash, err := TransactionHash(tx, ts.Network.Passphrase)
+	if err != nil {
+		ts.log.Print("Error calculating transaction hash")
+		return
+	}
+
+	var sig xdr.DecoratedSignature
+	signer := "local"
+	_, full := account.Keypair.(*keypair.Full)
+	if address := account.Keypair.Address(); !full && ts.Signer != nil {
+		signer = signerName(ts.Signer, address)
+		sig, err = ts.Signer.Sign(address, tx, hash)
+	} else {
+		sig, err = account.Keypair.SignDecorated(hash[:])
+	}
+	if err != nil {
+		ts.log.WithFields(logrus.Fields{"err": err}).Error("Error signing a transaction")
+		return
+	}
+
+	ts.Audit.Emit(audit.Event{
+		Type:    audit.KeyUsage,
+		Account: account.Keypair.Address(),
+		Details: map[string]string{
+			"signer":         signer,
+			"transaction_id": hex.EncodeToString(hash[:]),
+		},
+	})
+
+	envelopeXdr := xdr.TransactionEnvelope{
+		Tx:         *tx,
+		Signatures: []xdr.DecoratedSignature{sig},
+	}
+
+	for _, cosigner := range cosigners {
+		sig, err = cosigner.SignDecorated(hash[:])
+		if err != nil {
+			ts.log.WithFields(logrus.Fields{"err": err}).Error("Error signing a transaction")
+			return
+		}
+		envelopeXdr.Signatures = append(envelopeXdr.Signatures, sig)
+	}
+
+	txeB64, err = xdr.MarshalBase64(envelopeXdr)
+	if err != nil {
+		ts.log.WithFields(logrus.Fields{"err": err}).Error("Cannot encode transaction envelope")
+		return
+	}
+
+	transactionID = hex.EncodeToString(hash[:])
+	return
+}
+
+type cosignersContextKey struct{}
+
+// WithCosigners returns ctx with keys signing transactions submitted with it
+// in addition to the source account, ex. a new account setting its options in
+// the transaction creating it. Cosigners sign again when transactions are
+// resubmitted.
+func WithCosigners(ctx context.Context, cosigners ...*keypair.Full) context.Context {
+	return context.WithValue(ctx, cosignersContextKey{}, cosigners)
+}
+
+// Cosigners returns keys added to ctx by WithCosigners
+func Cosigners(ctx context.Context) []*keypair.Full {
+	cosigners, _ := ctx.Value(cosignersContextKey{}).([]*keypair.Full)
+	return cosigners
+}
+
 // SubmitTransaction builds and submits transaction to Stellar network
-func (ts *TransactionSubmitter) SubmitTransaction(paymentID *string, seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error) {
+func (ts *TransactionSubmitter) SubmitTransaction(ctx context.Context, paymentID *string, seed string, operation, memo interface{}) (response horizon.SubmitTransactionResponse, err error) {
+	tx, err := ts.BuildTransaction(ctx, seed, operation, memo)
+	if err != nil {
+		return
+	}
+
+	return ts.SignAndSubmitRawTransaction(ctx, paymentID, seed, tx)
+}
+
+// BuildTransaction builds an unsigned transaction of seed account. Sequence
+// number and fee are set in SignAndSubmitRawTransaction.
+func (ts *TransactionSubmitter) BuildTransaction(ctx context.Context, seed string, operation, memo interface{}) (tx *xdr.Transaction, err error) {
+	_, span := tracing.Start(ctx, "transaction.build")
+	defer func() {
+		span.SetError(err)
+		span.Finish()
+	}()
+
 	account, err := ts.LoadAccount(seed)
 	if err != nil {
 		return
@@ -238,12 +700,33 @@ func (ts *TransactionSubmitter) SubmitTransaction(paymentID *string, seed string
 	}
 
 	txBuilder, err := build.Transaction(mutators...)
-
 	if err != nil {
 		return
 	}
 
-	return ts.SignAndSubmitRawTransaction(paymentID, seed, txBuilder.TX)
+	tx = txBuilder.TX
+	ts.applyTimeBounds(tx)
+	return tx, nil
+}
+
+// applyTimeBounds sets max time of the transaction to TimeBounds from now.
+// Time bounds computed from a drifting clock could make the transaction
+// expired before it reaches the network, so they are not applied while Drift
+// reports drift.
+func (ts *TransactionSubmitter) applyTimeBounds(tx *xdr.Transaction) {
+	if ts.TimeBounds <= 0 {
+		return
+	}
+
+	if ts.Drift != nil && ts.Drift.Drifted() {
+		timeBoundsSkipped.Inc()
+		ts.log.Warn("Clock drift detected, building transaction without time bounds")
+		return
+	}
+
+	tx.TimeBounds = &xdr.TimeBounds{
+		MaxTime: xdr.Uint64(ts.now().Add(ts.TimeBounds).Unix()),
+	}
 }
 
 // BuildTransaction is used in compliance server. The sequence number in built transaction will be equal 0!
@@ -1,6 +1,7 @@
 package submitter
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -138,7 +139,7 @@ func TestTransactionSubmitter(t *testing.T) {
 						nil,
 					).Once()
 
-					_, err = transactionSubmitter.SubmitTransaction((*string)(nil), seed, operation, nil)
+					_, err = transactionSubmitter.SubmitTransaction(context.Background(), (*string)(nil), seed, operation, nil)
 					assert.Nil(t, err)
 					mockHorizon.AssertExpectations(t)
 				})
@@ -165,6 +166,7 @@ func TestTransactionSubmitter(t *testing.T) {
 					err := transactionSubmitter.InitAccount(seed)
 					assert.Nil(t, err)
 
+					txID := "4f885999be6ea7891052a53e496bcfb5c5a1a5bfb31923f649b028fdc74dd050"
 					txB64 := "AAAAAJbmB/pwwloZXCaCr9WR3Fue2lNhHGaDWKVOWO7MPq4QAAAAZAAk2eQAAAABAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAAd2/WGgaQ6CJcXQtGRFodrubQZ9ci5ZPRlxpqNPWV1CAAAAAAAAAAADuaygAAAAAAAAAAAcw+rhAAAABAyFjIMIZOtstCWtZlVBDj1AhTmsk5v1i2GGY4by2b5mgZoXXGgFTB8sfbQav0LzFKCcxY8h+9xPMT2e9xznAfDw=="
 
 					// Persist sending transaction
@@ -173,26 +175,13 @@ func TestTransactionSubmitter(t *testing.T) {
 						mock.AnythingOfType("*entities.SentTransaction"),
 					).Return(nil).Once().Run(func(args mock.Arguments) {
 						transaction := args.Get(0).(*entities.SentTransaction)
-						assert.Equal(t, "4f885999be6ea7891052a53e496bcfb5c5a1a5bfb31923f649b028fdc74dd050", transaction.TransactionID)
+						assert.Equal(t, txID, transaction.TransactionID)
 						assert.Equal(t, "sending", string(transaction.Status))
 						assert.Equal(t, "GCLOMB72ODBFUGK4E2BK7VMR3RNZ5WSTMEOGNA2YUVHFR3WMH2XBAB6H", transaction.Source)
 						assert.Equal(t, mocks.PredefinedTime, transaction.SubmittedAt)
 						assert.Equal(t, txB64, transaction.EnvelopeXdr)
 					})
 
-					// Persist failure
-					mockEntityManager.On(
-						"Persist",
-						mock.AnythingOfType("*entities.SentTransaction"),
-					).Return(nil).Once().Run(func(args mock.Arguments) {
-						transaction := args.Get(0).(*entities.SentTransaction)
-						assert.Equal(t, "4f885999be6ea7891052a53e496bcfb5c5a1a5bfb31923f649b028fdc74dd050", transaction.TransactionID)
-						assert.Equal(t, "failure", string(transaction.Status))
-						assert.Equal(t, "GCLOMB72ODBFUGK4E2BK7VMR3RNZ5WSTMEOGNA2YUVHFR3WMH2XBAB6H", transaction.Source)
-						assert.Equal(t, mocks.PredefinedTime, transaction.SubmittedAt)
-						assert.Equal(t, txB64, transaction.EnvelopeXdr)
-					})
-
 					mockHorizon.On("SubmitTransaction", txB64).Return(
 						horizon.SubmitTransactionResponse{
 							Ledger: nil,
@@ -203,22 +192,169 @@ func TestTransactionSubmitter(t *testing.T) {
 						nil,
 					).Once()
 
-					// Updating sequence number
-					mockHorizon.On(
-						"LoadAccount",
-						accountID,
-					).Return(
-						horizon.AccountResponse{
-							AccountID:      accountID,
-							SequenceNumber: "100",
-						},
-						nil,
-					).Once()
+					Convey("transaction has already landed", func() {
+						ledger := uint64(1234)
+						mockHorizon.On("LoadTransaction", txID).Return(
+							horizon.SubmitTransactionResponse{Hash: txID, Ledger: &ledger},
+							nil,
+						).Once()
+
+						// Persist success
+						mockEntityManager.On(
+							"Persist",
+							mock.AnythingOfType("*entities.SentTransaction"),
+						).Return(nil).Once().Run(func(args mock.Arguments) {
+							transaction := args.Get(0).(*entities.SentTransaction)
+							assert.Equal(t, txID, transaction.TransactionID)
+							assert.Equal(t, "success", string(transaction.Status))
+							assert.Equal(t, ledger, *transaction.Ledger)
+							assert.Equal(t, entities.BadSeqResolutionLanded, *transaction.BadSeqResolution)
+						})
+
+						response, err := transactionSubmitter.SubmitTransaction(context.Background(), (*string)(nil), seed, operation, nil)
+						assert.Nil(t, err)
+						assert.Equal(t, ledger, *response.Ledger)
+						assert.Equal(t, uint64(10372672437354497), transactionSubmitter.Accounts[seed].SequenceNumber)
+						mockHorizon.AssertExpectations(t)
+					})
 
-					_, err = transactionSubmitter.SubmitTransaction((*string)(nil), seed, operation, nil)
-					assert.Nil(t, err)
-					assert.Equal(t, uint64(100), transactionSubmitter.Accounts[seed].SequenceNumber)
-					mockHorizon.AssertExpectations(t)
+					Convey("sequence number is behind", func() {
+						mockHorizon.On("LoadTransaction", txID).Return(
+							horizon.SubmitTransactionResponse{},
+							errors.New("StatusCode indicates error"),
+						).Once()
+
+						// Updating sequence number
+						mockHorizon.On(
+							"LoadAccount",
+							accountID,
+						).Return(
+							horizon.AccountResponse{
+								AccountID:      accountID,
+								SequenceNumber: "100",
+							},
+							nil,
+						).Once()
+
+						// Rejected transaction is kept as failed, payment ID moves
+						// to the resubmission
+						originalID := int64(5)
+						mockEntityManager.On(
+							"Persist",
+							mock.AnythingOfType("*entities.SentTransaction"),
+						).Return(nil).Once().Run(func(args mock.Arguments) {
+							transaction := args.Get(0).(*entities.SentTransaction)
+							assert.Equal(t, txID, transaction.TransactionID)
+							assert.Equal(t, txB64, transaction.EnvelopeXdr)
+							assert.Equal(t, "failure", string(transaction.Status))
+							assert.Equal(t, "AAAAAAAAAAD////7AAAAAA==", *transaction.ResultXdr)
+							assert.Nil(t, transaction.PaymentID)
+							assert.Equal(t, entities.BadSeqResolutionResubmitted, *transaction.BadSeqResolution)
+							transaction.SetID(originalID)
+						})
+
+						// Persist rebuilt transaction
+						mockEntityManager.On(
+							"Persist",
+							mock.AnythingOfType("*entities.SentTransaction"),
+						).Return(nil).Once().Run(func(args mock.Arguments) {
+							transaction := args.Get(0).(*entities.SentTransaction)
+							assert.NotEqual(t, txID, transaction.TransactionID)
+							assert.NotEqual(t, txB64, transaction.EnvelopeXdr)
+							assert.Equal(t, "sending", string(transaction.Status))
+							assert.Equal(t, "payment-1", *transaction.PaymentID)
+							assert.Equal(t, originalID, *transaction.OriginalID)
+						})
+
+						ledger := uint64(1235)
+						mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).Return(
+							horizon.SubmitTransactionResponse{Ledger: &ledger},
+							nil,
+						).Once()
+
+						// Persist success
+						mockEntityManager.On(
+							"Persist",
+							mock.AnythingOfType("*entities.SentTransaction"),
+						).Return(nil).Once().Run(func(args mock.Arguments) {
+							transaction := args.Get(0).(*entities.SentTransaction)
+							assert.Equal(t, "success", string(transaction.Status))
+							assert.NotEqual(t, txID, transaction.TransactionID)
+							assert.Equal(t, originalID, *transaction.OriginalID)
+						})
+
+						paymentID := "payment-1"
+						response, err := transactionSubmitter.SubmitTransaction(context.Background(), &paymentID, seed, operation, nil)
+						assert.Nil(t, err)
+						assert.Equal(t, ledger, *response.Ledger)
+						assert.Equal(t, uint64(101), transactionSubmitter.Accounts[seed].SequenceNumber)
+						mockHorizon.AssertExpectations(t)
+					})
+
+					Convey("account is used by someone else", func() {
+						mockHorizon.On("LoadTransaction", txID).Return(
+							horizon.SubmitTransactionResponse{},
+							errors.New("StatusCode indicates error"),
+						).Once()
+
+						mockHorizon.On(
+							"LoadAccount",
+							accountID,
+						).Return(
+							horizon.AccountResponse{
+								AccountID:      accountID,
+								SequenceNumber: "10372672437354500",
+							},
+							nil,
+						).Once()
+
+						// Persist failure
+						mockEntityManager.On(
+							"Persist",
+							mock.AnythingOfType("*entities.SentTransaction"),
+						).Return(nil).Once().Run(func(args mock.Arguments) {
+							transaction := args.Get(0).(*entities.SentTransaction)
+							assert.Equal(t, txID, transaction.TransactionID)
+							assert.Equal(t, "failure", string(transaction.Status))
+							assert.Equal(t, txB64, transaction.EnvelopeXdr)
+							assert.Equal(t, entities.BadSeqResolutionExternal, *transaction.BadSeqResolution)
+						})
+
+						_, err = transactionSubmitter.SubmitTransaction(context.Background(), (*string)(nil), seed, operation, nil)
+						assert.Nil(t, err)
+						assert.Equal(t, uint64(10372672437354500), transactionSubmitter.Accounts[seed].SequenceNumber)
+						mockHorizon.AssertExpectations(t)
+					})
+
+					Convey("account cannot be loaded", func() {
+						mockHorizon.On("LoadTransaction", txID).Return(
+							horizon.SubmitTransactionResponse{},
+							errors.New("StatusCode indicates error"),
+						).Once()
+
+						mockHorizon.On(
+							"LoadAccount",
+							accountID,
+						).Return(
+							horizon.AccountResponse{},
+							errors.New("Horizon unavailable"),
+						).Once()
+
+						// Persist failure
+						mockEntityManager.On(
+							"Persist",
+							mock.AnythingOfType("*entities.SentTransaction"),
+						).Return(nil).Once().Run(func(args mock.Arguments) {
+							transaction := args.Get(0).(*entities.SentTransaction)
+							assert.Equal(t, "failure", string(transaction.Status))
+							assert.Equal(t, entities.BadSeqResolutionUnverified, *transaction.BadSeqResolution)
+						})
+
+						_, err = transactionSubmitter.SubmitTransaction(context.Background(), (*string)(nil), seed, operation, nil)
+						assert.Nil(t, err)
+						assert.Equal(t, uint64(10372672437354497), transactionSubmitter.Accounts[seed].SequenceNumber)
+						mockHorizon.AssertExpectations(t)
+					})
 				})
 
 				Convey("Successfully submits a transaction", func() {
@@ -277,12 +413,118 @@ func TestTransactionSubmitter(t *testing.T) {
 						nil,
 					).Once()
 
-					response, err := transactionSubmitter.SubmitTransaction((*string)(nil), seed, operation, nil)
+					response, err := transactionSubmitter.SubmitTransaction(context.Background(), (*string)(nil), seed, operation, nil)
 					assert.Nil(t, err)
 					assert.Equal(t, *response.Ledger, ledger)
 					assert.Equal(t, uint64(10372672437354497), transactionSubmitter.Accounts[seed].SequenceNumber)
 					mockHorizon.AssertExpectations(t)
 				})
+
+				Convey("Publishes transaction status event", func() {
+					mockPublisher := new(mocks.MockPublisher)
+					transactionSubmitter := NewTransactionSubmitter(
+						mockHorizon,
+						mockEntityManager,
+						"Test SDF Network ; September 2015",
+						mocks.Now,
+					)
+					transactionSubmitter.StatusEvents = mockPublisher
+					transactionSubmitter.StatusEventAttributes = []string{"asset_code", "asset_issuer", "account"}
+
+					mockHorizon.On(
+						"LoadAccount",
+						accountID,
+					).Return(
+						horizon.AccountResponse{
+							AccountID:      accountID,
+							SequenceNumber: "10372672437354496",
+						},
+						nil,
+					).Once()
+
+					err := transactionSubmitter.InitAccount(seed)
+					assert.Nil(t, err)
+
+					txB64 := "AAAAAJbmB/pwwloZXCaCr9WR3Fue2lNhHGaDWKVOWO7MPq4QAAAAZAAk2eQAAAABAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAAd2/WGgaQ6CJcXQtGRFodrubQZ9ci5ZPRlxpqNPWV1CAAAAAAAAAAADuaygAAAAAAAAAAAcw+rhAAAABAyFjIMIZOtstCWtZlVBDj1AhTmsk5v1i2GGY4by2b5mgZoXXGgFTB8sfbQav0LzFKCcxY8h+9xPMT2e9xznAfDw=="
+
+					mockEntityManager.On(
+						"Persist",
+						mock.AnythingOfType("*entities.SentTransaction"),
+					).Return(nil).Twice()
+
+					ledger := uint64(1486276)
+					mockHorizon.On("SubmitTransaction", txB64).Return(
+						horizon.SubmitTransactionResponse{Ledger: &ledger},
+						nil,
+					).Once()
+
+					// Native asset has no code and issuer attributes
+					mockPublisher.On(
+						"Publish",
+						mock.AnythingOfType("[]uint8"),
+						map[string]string{"account": accountID},
+					).Return(nil).Once().Run(func(args mock.Arguments) {
+						message := string(args.Get(0).([]byte))
+						assert.Contains(t, message, `"transaction_id":"4f885999be6ea7891052a53e496bcfb5c5a1a5bfb31923f649b028fdc74dd050"`)
+						assert.Contains(t, message, `"status":"success"`)
+					})
+
+					_, err = transactionSubmitter.SubmitTransaction(context.Background(), (*string)(nil), seed, operation, nil)
+					assert.Nil(t, err)
+					mockHorizon.AssertExpectations(t)
+					mockPublisher.AssertExpectations(t)
+				})
+
+				Convey("Notifies when transaction is accepted", func() {
+					transactionSubmitter := NewTransactionSubmitter(
+						mockHorizon,
+						mockEntityManager,
+						"Test SDF Network ; September 2015",
+						mocks.Now,
+					)
+
+					mockHorizon.On(
+						"LoadAccount",
+						accountID,
+					).Return(
+						horizon.AccountResponse{
+							AccountID:      accountID,
+							SequenceNumber: "10372672437354496",
+						},
+						nil,
+					).Once()
+
+					err := transactionSubmitter.InitAccount(seed)
+					assert.Nil(t, err)
+
+					txB64 := "AAAAAJbmB/pwwloZXCaCr9WR3Fue2lNhHGaDWKVOWO7MPq4QAAAAZAAk2eQAAAABAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAAd2/WGgaQ6CJcXQtGRFodrubQZ9ci5ZPRlxpqNPWV1CAAAAAAAAAAADuaygAAAAAAAAAAAcw+rhAAAABAyFjIMIZOtstCWtZlVBDj1AhTmsk5v1i2GGY4by2b5mgZoXXGgFTB8sfbQav0LzFKCcxY8h+9xPMT2e9xznAfDw=="
+
+					mockEntityManager.On(
+						"Persist",
+						mock.AnythingOfType("*entities.SentTransaction"),
+					).Return(nil).Twice()
+
+					var accepted *entities.SentTransaction
+					ledger := uint64(1486276)
+					mockHorizon.On("SubmitTransaction", txB64).Return(
+						horizon.SubmitTransactionResponse{Ledger: &ledger},
+						nil,
+					).Once().Run(func(args mock.Arguments) {
+						// Accepted before the transaction is submitted
+						assert.NotNil(t, accepted)
+					})
+
+					ctx := WithAccepted(context.Background(), func(sentTransaction *entities.SentTransaction) {
+						accepted = sentTransaction
+					})
+					_, err = transactionSubmitter.SubmitTransaction(ctx, (*string)(nil), seed, operation, nil)
+					assert.Nil(t, err)
+					if assert.NotNil(t, accepted) {
+						assert.Equal(t, "4f885999be6ea7891052a53e496bcfb5c5a1a5bfb31923f649b028fdc74dd050", accepted.TransactionID)
+						assert.Equal(t, "sending", string(accepted.Status))
+					}
+					mockHorizon.AssertExpectations(t)
+				})
 			})
 
 			Convey("Submits transaction with a memo", func() {
@@ -349,7 +591,7 @@ func TestTransactionSubmitter(t *testing.T) {
 						nil,
 					).Once()
 
-					response, err := transactionSubmitter.SubmitTransaction((*string)(nil), seed, operation, memo)
+					response, err := transactionSubmitter.SubmitTransaction(context.Background(), (*string)(nil), seed, operation, memo)
 					assert.Nil(t, err)
 					assert.Equal(t, *response.Ledger, ledger)
 					assert.Equal(t, uint64(10372672437354497), transactionSubmitter.Accounts[seed].SequenceNumber)
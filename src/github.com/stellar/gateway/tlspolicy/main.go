@@ -0,0 +1,232 @@
+// Package tlspolicy configures TLS of outbound connections to partners
+// (compliance servers, federation servers, stellar.toml and callbacks): min TLS
+// version, allowed cipher suites, certificate pinning per domain and a client
+// certificate for partners requiring mutual TLS. ServerConfig configures TLS
+// of inbound connections.
+package tlspolicy
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Config contains values of `outbound_tls` config group
+type Config struct {
+	// MinVersion is the min TLS version: "1.0", "1.1", "1.2" or "1.3"
+	MinVersion string `mapstructure:"min_version"`
+	// CipherSuites are names of allowed TLS 1.0-1.2 cipher suites, ex.
+	// "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256". TLS 1.3 suites are not
+	// configurable.
+	CipherSuites []string `mapstructure:"cipher_suites"`
+	Pins         []Pin
+	// CertificateFile and PrivateKeyFile are PEM files of a client
+	// certificate presented to servers requesting one (mutual TLS)
+	CertificateFile string `mapstructure:"certificate_file"`
+	PrivateKeyFile  string `mapstructure:"private_key_file"`
+}
+
+// Pin contains values of `outbound_tls.pins` config group
+type Pin struct {
+	// Domain is a host name or a wildcard matching its subdomains, ex.
+	// "*.example.com". IP addresses cannot be pinned because they are not sent
+	// in SNI.
+	Domain string
+	// SHA256 contains base64 encoded SHA-256 hashes of SubjectPublicKeyInfo of
+	// accepted certificates. One of the certificates in the verified chain must
+	// match.
+	SHA256 []string `mapstructure:"sha256"`
+}
+
+// ErrInsecure is returned when a plain HTTP request is sent to a pinned domain
+var ErrInsecure = errors.New("Plain HTTP request to pinned domain rejected")
+
+// PinError is returned when the certificate of a pinned domain does not match
+// any of the pins
+type PinError struct {
+	Host string
+}
+
+func (e *PinError) Error() string {
+	return fmt.Sprintf("Certificate of %s does not match pinned public keys", e.Host)
+}
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Enabled returns true when any of the params is set
+func (c Config) Enabled() bool {
+	return c.MinVersion != "" || len(c.CipherSuites) > 0 || len(c.Pins) > 0 || c.CertificateFile != ""
+}
+
+// Validate returns an error when any of the params is invalid
+func (c Config) Validate() error {
+	_, err := c.TLSConfig()
+	return err
+}
+
+// TLSConfig returns tls.Config enforcing the policy
+func (c Config) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{}
+
+	if c.MinVersion != "" {
+		version, ok := versions[c.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("Invalid outbound_tls.min_version param: %s", c.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	for _, name := range c.CipherSuites {
+		id, ok := cipherSuite(name)
+		if !ok {
+			return nil, fmt.Errorf("Invalid or insecure cipher suite in outbound_tls.cipher_suites param: %s", name)
+		}
+		config.CipherSuites = append(config.CipherSuites, id)
+	}
+
+	pins := map[string]map[string]bool{}
+	for _, pin := range c.Pins {
+		domain := strings.ToLower(pin.Domain)
+		if domain == "" || len(pin.SHA256) == 0 {
+			return nil, errors.New("outbound_tls.pins require domain and sha256 params")
+		}
+
+		if pins[domain] == nil {
+			pins[domain] = map[string]bool{}
+		}
+		for _, hash := range pin.SHA256 {
+			decoded, err := base64.StdEncoding.DecodeString(hash)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("Invalid outbound_tls.pins.sha256 param of %s: %s", pin.Domain, hash)
+			}
+			pins[domain][hash] = true
+		}
+	}
+
+	if c.CertificateFile != "" || c.PrivateKeyFile != "" {
+		if c.CertificateFile == "" || c.PrivateKeyFile == "" {
+			return nil, errors.New("outbound_tls.certificate_file and outbound_tls.private_key_file params must be set together")
+		}
+		certificate, err := tls.LoadX509KeyPair(c.CertificateFile, c.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot load outbound_tls client certificate: %s", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	if len(pins) > 0 {
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyPins(pins, state)
+		}
+	}
+
+	return config, nil
+}
+
+// Transport returns http.RoundTripper using the policy and rootCAs (system
+// roots when nil). Plain HTTP requests to pinned domains are rejected. Values
+// are checked in Validate.
+func (c Config) Transport(rootCAs *x509.CertPool) http.RoundTripper {
+	return c.Wrap(http.DefaultTransport.(*http.Transport).Clone(), rootCAs)
+}
+
+// Wrap applies the policy and rootCAs (system roots when nil) to transport,
+// so proxies and timeouts of transport are kept. Values are checked in
+// Validate.
+func (c Config) Wrap(transport *http.Transport, rootCAs *x509.CertPool) http.RoundTripper {
+	config, _ := c.TLSConfig()
+	config.RootCAs = rootCAs
+	transport.TLSClientConfig = config
+
+	if len(c.Pins) == 0 {
+		return transport
+	}
+
+	domains := map[string]map[string]bool{}
+	for _, pin := range c.Pins {
+		domains[strings.ToLower(pin.Domain)] = map[string]bool{}
+	}
+	return &pinnedTransport{transport: transport, domains: domains}
+}
+
+// Client returns http.Client using the policy or http.DefaultTransport when
+// the policy is not enabled
+func (c Config) Client(client http.Client) *http.Client {
+	if c.Enabled() {
+		client.Transport = c.Transport(nil)
+	}
+	return &client
+}
+
+// PublicKeyHash returns base64 encoded SHA-256 hash of SubjectPublicKeyInfo of
+// the certificate, the value used in outbound_tls.pins.sha256 param
+func PublicKeyHash(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func verifyPins(pins map[string]map[string]bool, state tls.ConnectionState) error {
+	hashes := pinsOf(pins, state.ServerName)
+	if hashes == nil {
+		return nil
+	}
+
+	for _, chain := range state.VerifiedChains {
+		for _, cert := range chain {
+			if hashes[PublicKeyHash(cert)] {
+				return nil
+			}
+		}
+	}
+	return &PinError{Host: state.ServerName}
+}
+
+// pinsOf returns pinned hashes of host: exact domain first, then wildcards
+func pinsOf(pins map[string]map[string]bool, host string) map[string]bool {
+	host = strings.ToLower(host)
+	if hashes, ok := pins[host]; ok {
+		return hashes
+	}
+
+	for i := strings.Index(host, "."); i >= 0; i = strings.Index(host, ".") {
+		host = host[i+1:]
+		if hashes, ok := pins["*."+host]; ok {
+			return hashes
+		}
+	}
+	return nil
+}
+
+func cipherSuite(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// pinnedTransport rejects plain HTTP requests to pinned domains, so pinning
+// cannot be bypassed by http:// URLs (ex. in stellar.toml or redirects)
+type pinnedTransport struct {
+	transport http.RoundTripper
+	// domains has the same keys as pins passed to verifyPins
+	domains map[string]map[string]bool
+}
+
+func (t *pinnedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" && pinsOf(t.domains, req.URL.Hostname()) != nil {
+		return nil, ErrInsecure
+	}
+	return t.transport.RoundTrip(req)
+}
@@ -0,0 +1,218 @@
+package tlspolicy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	assert.False(t, Config{}.Enabled())
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{
+		MinVersion:   "1.2",
+		CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		Pins:         []Pin{{Domain: "*.example.com", SHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}}},
+	}.Validate())
+
+	assert.EqualError(t, Config{MinVersion: "1.4"}.Validate(), "Invalid outbound_tls.min_version param: 1.4")
+	// Insecure cipher suites are not allowed
+	assert.Error(t, Config{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}.Validate())
+	assert.Error(t, Config{Pins: []Pin{{Domain: "example.com"}}}.Validate())
+	assert.Error(t, Config{Pins: []Pin{{Domain: "example.com", SHA256: []string{"dGVzdA=="}}}}.Validate())
+}
+
+func TestTLSConfig(t *testing.T) {
+	config, err := Config{
+		MinVersion:   "1.2",
+		CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	}.TLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, config.CipherSuites)
+	assert.Nil(t, config.VerifyConnection)
+}
+
+func TestPinning(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(srv.Certificate())
+	hash := PublicKeyHash(srv.Certificate())
+	otherHash := "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+
+	// Connections to example.com are sent to the test server
+	addr := srv.Listener.Addr().String()
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+	get := func(config Config, url string) error {
+		transport := config.Transport(rootCAs)
+		if pinned, ok := transport.(*pinnedTransport); ok {
+			pinned.transport.(*http.Transport).DialContext = dial
+		} else {
+			transport.(*http.Transport).DialContext = dial
+		}
+
+		client := &http.Client{Transport: transport}
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.NoError(t, get(Config{Pins: []Pin{{Domain: "example.com", SHA256: []string{otherHash, hash}}}}, "https://example.com"))
+	// Pins of other domains are not checked
+	assert.NoError(t, get(Config{Pins: []Pin{{Domain: "example.org", SHA256: []string{otherHash}}}}, "https://example.com"))
+
+	err := get(Config{Pins: []Pin{{Domain: "example.com", SHA256: []string{otherHash}}}}, "https://example.com")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Certificate of example.com does not match pinned public keys")
+	}
+
+	err = get(Config{Pins: []Pin{{Domain: "example.com", SHA256: []string{hash}}}}, "http://example.com")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), ErrInsecure.Error())
+	}
+
+	// TLS 1.3 is used by httptest server
+	assert.NoError(t, get(Config{MinVersion: "1.3"}, "https://example.com"))
+}
+
+func TestPinsOf(t *testing.T) {
+	pins := map[string]map[string]bool{
+		"example.com":     {"a": true},
+		"*.example.com":   {"b": true},
+		"api.example.com": {"c": true},
+	}
+
+	assert.Equal(t, map[string]bool{"a": true}, pinsOf(pins, "Example.com"))
+	assert.Equal(t, map[string]bool{"b": true}, pinsOf(pins, "www.example.com"))
+	assert.Equal(t, map[string]bool{"b": true}, pinsOf(pins, "a.b.example.com"))
+	assert.Equal(t, map[string]bool{"c": true}, pinsOf(pins, "api.example.com"))
+	assert.Nil(t, pinsOf(pins, "example.org"))
+}
+
+// writeCertificate creates a certificate signed by parent (self-signed when
+// nil) and writes it and its key to PEM files in directory
+func writeCertificate(t *testing.T, directory, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(directory, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(directory, name+"-key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate, key
+}
+
+func TestMutualTLS(t *testing.T) {
+	directory, err := ioutil.TempDir("", "tlspolicy")
+	require.NoError(t, err)
+	defer os.RemoveAll(directory)
+	file := func(name string) string { return filepath.Join(directory, name) }
+
+	ca, caKey := writeCertificate(t, directory, "ca", nil, nil, true)
+	writeCertificate(t, directory, "server", ca, caKey, false)
+	writeCertificate(t, directory, "client", ca, caKey, false)
+
+	serverConfig := ServerConfig{
+		CertificateFile: file("server.pem"),
+		PrivateKeyFile:  file("server-key.pem"),
+		ClientCAFile:    file("ca.pem"),
+		ClientAuth:      ClientAuthRequire,
+	}
+	require.NoError(t, serverConfig.Validate())
+	tlsConfig, err := serverConfig.TLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+
+	withoutCA := serverConfig
+	withoutCA.ClientCAFile = ""
+	assert.EqualError(t, withoutCA.Validate(), "tls.client_ca_file param is required when tls.client_auth is set")
+
+	var subject string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if certificate := ClientCertificate(r); certificate != nil {
+			subject = certificate.Subject.CommonName
+		}
+	}))
+	srv.TLS = tlsConfig
+	srv.StartTLS()
+	defer srv.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca)
+	get := func(config Config) error {
+		client := &http.Client{Transport: config.Transport(rootCAs)}
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.Error(t, get(Config{}))
+	assert.Empty(t, subject)
+
+	clientConfig := Config{CertificateFile: file("client.pem"), PrivateKeyFile: file("client-key.pem")}
+	assert.True(t, clientConfig.Enabled())
+	require.NoError(t, clientConfig.Validate())
+	assert.NoError(t, get(clientConfig))
+	assert.Equal(t, "client", subject)
+
+	// Certificates not signed by the client CA are rejected
+	writeCertificate(t, directory, "other", nil, nil, false)
+	assert.Error(t, get(Config{CertificateFile: file("other.pem"), PrivateKeyFile: file("other-key.pem")}))
+}
+
+func TestServerConfigValidate(t *testing.T) {
+	assert.False(t, ServerConfig{}.Enabled())
+	assert.NoError(t, ServerConfig{}.Validate())
+	assert.EqualError(t, ServerConfig{ClientAuth: ClientAuthRequire}.Validate(), "tls.certificate_file param is required when TLS params are set")
+	assert.EqualError(t, ServerConfig{CertificateFile: "cert.pem"}.Validate(), "tls.certificate_file and tls.private_key_file params are required")
+	assert.Error(t, ServerConfig{CertificateFile: "missing.pem", PrivateKeyFile: "missing-key.pem"}.Validate())
+
+	assert.EqualError(t, Config{CertificateFile: "cert.pem"}.Validate(), "outbound_tls.certificate_file and outbound_tls.private_key_file params must be set together")
+	assert.Error(t, Config{CertificateFile: "missing.pem", PrivateKeyFile: "missing-key.pem"}.Validate())
+}
@@ -0,0 +1,145 @@
+package tlspolicy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/zenazn/goji/graceful"
+)
+
+// Client certificate verification modes of ServerConfig.ClientAuth
+const (
+	// ClientAuthNone does not request client certificates
+	ClientAuthNone = "none"
+	// ClientAuthOptional verifies client certificates when sent
+	ClientAuthOptional = "optional"
+	// ClientAuthRequire rejects connections without a valid client certificate
+	ClientAuthRequire = "require"
+)
+
+// ServerConfig contains values of `tls` config group: the certificate served
+// by the server and optional verification of client certificates (mutual TLS)
+type ServerConfig struct {
+	CertificateFile string `mapstructure:"certificate_file"`
+	PrivateKeyFile  string `mapstructure:"private_key_file"`
+	// ClientCAFile is a PEM file with CA certificates client certificates
+	// must be signed by
+	ClientCAFile string `mapstructure:"client_ca_file"`
+	// ClientAuth is one of ClientAuth* constants, "none" when empty
+	ClientAuth string `mapstructure:"client_auth"`
+	// MinVersion is the min TLS version: "1.0", "1.1", "1.2" (default) or "1.3"
+	MinVersion string `mapstructure:"min_version"`
+}
+
+// Enabled returns true when the server should serve TLS
+func (c ServerConfig) Enabled() bool {
+	return c.CertificateFile != ""
+}
+
+// Validate returns an error when any of the params is invalid
+func (c ServerConfig) Validate() error {
+	if !c.Enabled() {
+		if c.PrivateKeyFile != "" || c.ClientCAFile != "" || c.ClientAuth != "" {
+			return errors.New("tls.certificate_file param is required when TLS params are set")
+		}
+		return nil
+	}
+	_, err := c.TLSConfig()
+	return err
+}
+
+// TLSConfig returns tls.Config of the server
+func (c ServerConfig) TLSConfig() (*tls.Config, error) {
+	if c.CertificateFile == "" || c.PrivateKeyFile == "" {
+		return nil, errors.New("tls.certificate_file and tls.private_key_file params are required")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(c.CertificateFile, c.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot load tls certificate: %s", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"http/1.1"},
+	}
+
+	if c.MinVersion != "" {
+		version, ok := versions[c.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("Invalid tls.min_version param: %s", c.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	switch c.ClientAuth {
+	case "", ClientAuthNone:
+		config.ClientAuth = tls.NoClientCert
+	case ClientAuthOptional:
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("Invalid tls.client_auth param: %s", c.ClientAuth)
+	}
+
+	if config.ClientAuth != tls.NoClientCert {
+		if c.ClientCAFile == "" {
+			return nil, errors.New("tls.client_ca_file param is required when tls.client_auth is set")
+		}
+
+		data, err := ioutil.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot read tls.client_ca_file: %s", err)
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(data) {
+			return nil, errors.New("No certificates found in tls.client_ca_file")
+		}
+	}
+
+	return config, nil
+}
+
+// ListenAndServe serves handler on addr over TLS when the config is enabled
+// or plain HTTP otherwise. Values are checked in Validate.
+func (c ServerConfig) ListenAndServe(addr string, handler http.Handler) error {
+	return c.Serve(&graceful.Server{Addr: addr, Handler: handler})
+}
+
+// Serve starts server over TLS when the config is enabled or plain HTTP
+// otherwise. Values are checked in Validate.
+func (c ServerConfig) Serve(server *graceful.Server) error {
+	if !c.Enabled() {
+		return server.ListenAndServe()
+	}
+
+	config, err := c.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	server.TLSConfig = config
+	if server.Protocols != nil && server.Protocols.UnencryptedHTTP2() {
+		// graceful wraps TLS connections, so HTTP/2 negotiated with ALPN is
+		// served as unencrypted HTTP/2 over the TLS connection
+		config.NextProtos = []string{"h2", "http/1.1"}
+	}
+	// graceful loads the certificate from the files again, TLSConfig has
+	// already checked them
+	return server.ListenAndServeTLS(c.CertificateFile, c.PrivateKeyFile)
+}
+
+// ClientCertificate returns the verified client certificate of the request
+// or nil when the client did not send one
+func ClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Middleware starts a server span of every request, continuing the trace of
+// the caller when the request has `traceparent` header
+func Middleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := StartKind(Extract(r.Context(), r.Header), r.Method+" "+r.URL.Path, SpanKindServer)
+		if span == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		defer span.Finish()
+
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.target", r.URL.Path)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		span.SetAttribute("http.status_code", recorder.status)
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetError(fmt.Errorf("%d %s", recorder.status, http.StatusText(recorder.status)))
+		}
+	}
+	return http.HandlerFunc(fn)
+}
+
+// Do sends req as a client span, a child of the span in the request context.
+// `traceparent` header is added to req, so the receiver can continue the
+// trace.
+func Do(client HTTP, name string, req *http.Request) (*http.Response, error) {
+	ctx, span := StartKind(req.Context(), name, SpanKindClient)
+	defer span.Finish()
+
+	if span != nil {
+		req = req.WithContext(ctx)
+		Inject(ctx, req.Header)
+		span.SetAttribute("http.method", req.Method)
+		span.SetAttribute("http.url", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		span.SetError(err)
+		return resp, err
+	}
+
+	span.SetAttribute("http.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetError(fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)))
+	}
+	return resp, nil
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
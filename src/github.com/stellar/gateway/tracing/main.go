@@ -0,0 +1,263 @@
+// Package tracing creates OpenTelemetry compatible spans of requests handled
+// by the server and sends them to an OTLP collector. Trace context is
+// propagated in W3C `traceparent` header.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpanKind describes the relationship of a span to its parent and children
+type SpanKind int
+
+// Span kinds, values match OTLP
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// Exporter receives finished spans
+type Exporter interface {
+	Export(span *Span)
+}
+
+// Tracer creates spans and sends finished spans to Exporter
+type Tracer struct {
+	exporter Exporter
+	// sampleRatio is the fraction of traces started by the server that are
+	// recorded. Traces started by callers are recorded when the caller
+	// recorded them.
+	sampleRatio float64
+}
+
+// NewTracer creates a new Tracer. sampleRatio must be between 0 and 1.
+func NewTracer(exporter Exporter, sampleRatio float64) *Tracer {
+	return &Tracer{exporter: exporter, sampleRatio: sampleRatio}
+}
+
+var (
+	defaultTracerLock sync.RWMutex
+	defaultTracer     *Tracer
+)
+
+// SetDefault sets Tracer used by Start. Spans are not recorded when it's nil.
+func SetDefault(tracer *Tracer) {
+	defaultTracerLock.Lock()
+	defer defaultTracerLock.Unlock()
+	defaultTracer = tracer
+}
+
+// Span is a single operation of a trace. A nil Span is a span that is not
+// recorded, all methods are no-ops then.
+type Span struct {
+	TraceID  [16]byte
+	SpanID   [8]byte
+	ParentID [8]byte
+	Name     string
+	Kind     SpanKind
+	Start    time.Time
+	End      time.Time
+	// Error is a description of the error that made the operation fail
+	Error string
+
+	lock       sync.Mutex
+	attributes map[string]interface{}
+	tracer     *Tracer
+	ended      bool
+}
+
+type spanContextKey struct{}
+
+// remoteParent is a parent span of a trace started by a caller
+type remoteParent struct {
+	traceID [16]byte
+	spanID  [8]byte
+	sampled bool
+}
+
+type remoteParentKey struct{}
+
+// Start starts a new internal span, a child of the span in ctx
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return StartKind(ctx, name, SpanKindInternal)
+}
+
+// StartKind starts a new span of kind, a child of the span in ctx. The
+// returned span is nil when tracing is disabled or the trace is not sampled.
+func StartKind(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	defaultTracerLock.RLock()
+	tracer := defaultTracer
+	defaultTracerLock.RUnlock()
+	if tracer == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		Name:   name,
+		Kind:   kind,
+		Start:  time.Now(),
+		tracer: tracer,
+	}
+
+	if parent := FromContext(ctx); parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else if remote, ok := ctx.Value(remoteParentKey{}).(remoteParent); ok {
+		if !remote.sampled {
+			return ctx, nil
+		}
+		span.TraceID = remote.traceID
+		span.ParentID = remote.spanID
+	} else {
+		span.TraceID = newTraceID()
+		if !tracer.sampled(span.TraceID) {
+			return ctx, nil
+		}
+	}
+	span.SpanID = newSpanID()
+
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// FromContext returns the span in ctx or nil
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// SetAttribute sets a string, int, int64, float64 or bool attribute of span.
+// Other values are converted to strings.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.attributes == nil {
+		s.attributes = map[string]interface{}{}
+	}
+	s.attributes[key] = value
+}
+
+// Attributes returns a copy of span attributes
+func (s *Span) Attributes() map[string]interface{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	attributes := make(map[string]interface{}, len(s.attributes))
+	for key, value := range s.attributes {
+		attributes[key] = value
+	}
+	return attributes
+}
+
+// SetError marks span as failed when err is not nil
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Error = err.Error()
+}
+
+// Finish ends span and sends it to the exporter. Calls after the first one
+// are ignored.
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.End = time.Now()
+	s.lock.Unlock()
+
+	s.tracer.exporter.Export(s)
+}
+
+// Inject sets `traceparent` header of the span in ctx
+func Inject(ctx context.Context, header http.Header) {
+	span := FromContext(ctx)
+	if span == nil {
+		return
+	}
+	header.Set("traceparent", fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(span.TraceID[:]), hex.EncodeToString(span.SpanID[:])))
+}
+
+// Extract returns ctx with the remote parent span from `traceparent` header,
+// so spans started with it continue the trace of the caller. ctx is returned
+// unchanged when the header is missing or invalid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	parts := strings.Split(strings.TrimSpace(header.Get("traceparent")), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return ctx
+	}
+
+	var remote remoteParent
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(remote.traceID) || isZero(traceID) {
+		return ctx
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(remote.spanID) || isZero(spanID) {
+		return ctx
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return ctx
+	}
+
+	copy(remote.traceID[:], traceID)
+	copy(remote.spanID[:], spanID)
+	remote.sampled = flags[0]&1 == 1
+	return context.WithValue(ctx, remoteParentKey{}, remote)
+}
+
+// sampled decides if a new trace is recorded using the low 8 bytes of the
+// trace ID, so the decision is random but the same for the whole trace
+func (t *Tracer) sampled(traceID [16]byte) bool {
+	if t.sampleRatio >= 1 {
+		return true
+	}
+	value := binary.BigEndian.Uint64(traceID[8:]) >> 1
+	return float64(value) < t.sampleRatio*float64(math.MaxInt64)
+}
+
+func newTraceID() (id [16]byte) {
+	for isZero(id[:]) {
+		rand.Read(id[:])
+	}
+	return
+}
+
+func newSpanID() (id [8]byte) {
+	for isZero(id[:]) {
+		rand.Read(id[:])
+	}
+	return
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
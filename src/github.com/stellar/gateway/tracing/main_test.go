@@ -0,0 +1,173 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingExporter struct {
+	lock  sync.Mutex
+	spans []*Span
+}
+
+func (e *recordingExporter) Export(span *Span) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func TestSpans(t *testing.T) {
+	ctx, span := Start(context.Background(), "disabled")
+	assert.Nil(t, span)
+	assert.Nil(t, FromContext(ctx))
+	// Methods of nil spans are no-ops
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("error"))
+	span.Finish()
+
+	exporter := &recordingExporter{}
+	SetDefault(NewTracer(exporter, 1))
+	defer SetDefault(nil)
+
+	ctx, parent := Start(context.Background(), "parent")
+	require.NotNil(t, parent)
+	_, child := StartKind(ctx, "child", SpanKindClient)
+	require.NotNil(t, child)
+	child.SetAttribute("ledger", int64(10))
+	child.SetError(errors.New("tx_failed"))
+	child.Finish()
+	child.Finish()
+	parent.Finish()
+
+	require.Len(t, exporter.spans, 2)
+	assert.Equal(t, parent.TraceID, child.TraceID)
+	assert.Equal(t, parent.SpanID, child.ParentID)
+	assert.Equal(t, [8]byte{}, parent.ParentID)
+	assert.Equal(t, "tx_failed", child.Error)
+	assert.Equal(t, map[string]interface{}{"ledger": int64(10)}, child.Attributes())
+
+	// Traces are not recorded with 0 sample ratio unless the caller recorded them
+	SetDefault(NewTracer(exporter, 0))
+	_, span = Start(context.Background(), "not sampled")
+	assert.Nil(t, span)
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, span = Start(Extract(context.Background(), header), "sampled by caller")
+	require.NotNil(t, span)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(span.TraceID[:]))
+	assert.Equal(t, "00f067aa0ba902b7", hex.EncodeToString(span.ParentID[:]))
+
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	_, span = Start(Extract(context.Background(), header), "not sampled by caller")
+	assert.Nil(t, span)
+
+	for _, invalid := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",
+	} {
+		header.Set("traceparent", invalid)
+		assert.Equal(t, context.Background(), Extract(context.Background(), header), invalid)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	exporter := &recordingExporter{}
+	SetDefault(NewTracer(exporter, 1))
+	defer SetDefault(nil)
+
+	var traceparent string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer downstream.Close()
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequest("GET", downstream.URL+"/callback", nil)
+		require.NoError(t, err)
+		resp, err := Do(http.DefaultClient, "callback.deliver", req.WithContext(r.Context()))
+		require.NoError(t, err)
+		resp.Body.Close()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	req := httptest.NewRequest("POST", "/payment", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	require.Len(t, exporter.spans, 2)
+	client, server := exporter.spans[0], exporter.spans[1]
+	assert.Equal(t, "POST /payment", server.Name)
+	assert.Equal(t, SpanKindServer, server.Kind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(server.TraceID[:]))
+	assert.Equal(t, "500 Internal Server Error", server.Error)
+	assert.Equal(t, 500, server.Attributes()["http.status_code"])
+
+	assert.Equal(t, SpanKindClient, client.Kind)
+	assert.Equal(t, server.SpanID, client.ParentID)
+	assert.Equal(t, "502 Bad Gateway", client.Error)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+hex.EncodeToString(client.SpanID[:])+"-01", traceparent)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var request *http.Request
+	var body map[string]interface{}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		raw, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPExporter(collector.URL+"/", "bridge", map[string]string{"Authorization": "Bearer token"}, http.DefaultClient)
+	SetDefault(NewTracer(exporter, 1))
+	defer SetDefault(nil)
+
+	_, span := StartKind(context.Background(), "horizon.submit", SpanKindClient)
+	span.SetAttribute("transaction_id", "abc")
+	span.SetAttribute("ledger", int64(10))
+	span.SetError(errors.New("tx_failed"))
+	span.Finish()
+
+	require.NoError(t, exporter.Send([]*Span{span}))
+	assert.Equal(t, "/v1/traces", request.URL.Path)
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+
+	resource := body["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"key":   "service.name",
+		"value": map[string]interface{}{"stringValue": "bridge"},
+	}}, resource["resource"].(map[string]interface{})["attributes"])
+
+	encoded := resource["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, hex.EncodeToString(span.TraceID[:]), encoded["traceId"])
+	assert.Equal(t, hex.EncodeToString(span.SpanID[:]), encoded["spanId"])
+	assert.NotContains(t, encoded, "parentSpanId")
+	assert.Equal(t, "horizon.submit", encoded["name"])
+	assert.Equal(t, float64(SpanKindClient), encoded["kind"])
+	assert.Equal(t, map[string]interface{}{"code": float64(2), "message": "tx_failed"}, encoded["status"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "ledger", "value": map[string]interface{}{"intValue": "10"}},
+		map[string]interface{}{"key": "transaction_id", "value": map[string]interface{}{"stringValue": "abc"}},
+	}, encoded["attributes"])
+
+	collector.Close()
+	assert.Error(t, exporter.Send([]*Span{span}))
+}
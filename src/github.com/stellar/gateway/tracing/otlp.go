@@ -0,0 +1,244 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/metrics"
+)
+
+const (
+	// queueSize is the max number of finished spans waiting for export. New
+	// spans are dropped when the queue is full.
+	queueSize = 2048
+	// batchSize is the max number of spans sent in a single request
+	batchSize = 512
+	// flushInterval is the max time a finished span waits for export
+	flushInterval = 5 * time.Second
+)
+
+var exporterMetrics = struct {
+	exported *metrics.Counter
+	dropped  *metrics.Counter
+	failures *metrics.Counter
+}{
+	exported: metrics.NewCounter("tracing_spans_exported_total", "Number of spans sent to OTLP collector."),
+	dropped:  metrics.NewCounter("tracing_spans_dropped_total", "Number of spans dropped because the export queue was full or the collector failed."),
+	failures: metrics.NewCounter("tracing_export_failures_total", "Number of failed requests to OTLP collector."),
+}
+
+// HTTP represents an http client that OTLPExporter can use to make HTTP requests
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// OTLPExporter sends spans in batches to an OpenTelemetry collector using
+// OTLP/HTTP protocol with JSON encoding. Spans that cannot be sent are
+// dropped, so a collector outage never blocks requests.
+type OTLPExporter struct {
+	// Endpoint is a base URL of the collector, spans are sent to
+	// Endpoint + "/v1/traces"
+	Endpoint    string
+	ServiceName string
+	// Headers are added to every request, ex. authentication of a hosted
+	// collector
+	Headers map[string]string
+	Client  HTTP
+
+	queue chan *Span
+	log   *logrus.Entry
+}
+
+// NewOTLPExporter creates a new OTLPExporter. Call Start to send spans.
+func NewOTLPExporter(endpoint, serviceName string, headers map[string]string, client HTTP) *OTLPExporter {
+	return &OTLPExporter{
+		Endpoint:    strings.TrimSuffix(endpoint, "/"),
+		ServiceName: serviceName,
+		Headers:     headers,
+		Client:      client,
+		queue:       make(chan *Span, queueSize),
+		log:         logrus.WithFields(logrus.Fields{"service": "OTLPExporter"}),
+	}
+}
+
+// Export adds span to the export queue
+func (e *OTLPExporter) Export(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		exporterMetrics.dropped.Inc()
+	}
+}
+
+// Start sends queued spans in a goroutine
+func (e *OTLPExporter) Start() {
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+
+		batch := make([]*Span, 0, batchSize)
+		for {
+			select {
+			case span := <-e.queue:
+				batch = append(batch, span)
+				if len(batch) < batchSize {
+					continue
+				}
+			case <-ticker.C:
+				if len(batch) == 0 {
+					continue
+				}
+			}
+
+			err := e.Send(batch)
+			if err != nil {
+				exporterMetrics.failures.Inc()
+				exporterMetrics.dropped.Add(int64(len(batch)))
+				e.log.WithFields(logrus.Fields{"err": err, "spans": len(batch)}).Warn("Error sending spans to OTLP collector")
+			} else {
+				exporterMetrics.exported.Add(int64(len(batch)))
+			}
+			batch = batch[:0]
+		}
+	}()
+}
+
+// Send sends spans to the collector in a single request
+func (e *OTLPExporter) Send(spans []*Span) error {
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", e.Endpoint+"/v1/traces", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range e.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("OTLP collector responded with %d status", resp.StatusCode)
+	}
+	return nil
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID      string          `json:"traceId"`
+	SpanID       string          `json:"spanId"`
+	ParentSpanID string          `json:"parentSpanId,omitempty"`
+	Name         string          `json:"name"`
+	Kind         SpanKind        `json:"kind"`
+	Start        string          `json:"startTimeUnixNano"`
+	End          string          `json:"endTimeUnixNano"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	Status       *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	// Code 2 is STATUS_CODE_ERROR
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type otlpAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+func (e *OTLPExporter) request(spans []*Span) otlpRequest {
+	var resource otlpResourceSpans
+	resource.Resource.Attributes = []otlpAttribute{attribute("service.name", e.ServiceName)}
+
+	var scope otlpScopeSpans
+	scope.Scope.Name = "github.com/stellar/gateway"
+	for _, span := range spans {
+		encoded := otlpSpan{
+			TraceID: hex.EncodeToString(span.TraceID[:]),
+			SpanID:  hex.EncodeToString(span.SpanID[:]),
+			Name:    span.Name,
+			Kind:    span.Kind,
+			Start:   strconv.FormatInt(span.Start.UnixNano(), 10),
+			End:     strconv.FormatInt(span.End.UnixNano(), 10),
+		}
+		if !isZero(span.ParentID[:]) {
+			encoded.ParentSpanID = hex.EncodeToString(span.ParentID[:])
+		}
+
+		attributes := span.Attributes()
+		keys := make([]string, 0, len(attributes))
+		for key := range attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			encoded.Attributes = append(encoded.Attributes, attribute(key, attributes[key]))
+		}
+
+		if span.Error != "" {
+			encoded.Status = &otlpStatus{Code: 2, Message: span.Error}
+		}
+		scope.Spans = append(scope.Spans, encoded)
+	}
+
+	resource.ScopeSpans = []otlpScopeSpans{scope}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{resource}}
+}
+
+// attribute encodes an attribute value as OTLP AnyValue. 64-bit integers are
+// encoded as strings like in OTLP JSON.
+func attribute(key string, value interface{}) otlpAttribute {
+	var encoded map[string]interface{}
+	switch value := value.(type) {
+	case string:
+		encoded = map[string]interface{}{"stringValue": value}
+	case bool:
+		encoded = map[string]interface{}{"boolValue": value}
+	case int:
+		encoded = map[string]interface{}{"intValue": strconv.Itoa(value)}
+	case int64:
+		encoded = map[string]interface{}{"intValue": strconv.FormatInt(value, 10)}
+	case float64:
+		encoded = map[string]interface{}{"doubleValue": value}
+	default:
+		encoded = map[string]interface{}{"stringValue": fmt.Sprint(value)}
+	}
+	return otlpAttribute{Key: key, Value: encoded}
+}
@@ -0,0 +1,190 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/gateway/clock"
+	"github.com/stellar/gateway/cloudevents"
+	"github.com/stellar/gateway/db"
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/metrics"
+	"github.com/stellar/gateway/protocols/signer"
+)
+
+// maxResponseSize is the max size of a response body read from an endpoint
+const maxResponseSize = 64 * 1024
+
+var dispatcherMetrics = struct {
+	delivered *metrics.Counter
+	failed    *metrics.Counter
+}{
+	delivered: metrics.NewCounter("bridge_webhook_deliveries_total", "Number of events delivered to webhooks."),
+	failed:    metrics.NewCounter("bridge_webhook_failures_total", "Number of events not delivered to webhooks after all attempts."),
+}
+
+// HTTP represents an http client that Dispatcher uses to make HTTP requests
+type HTTP interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
+// Dispatcher sends events to enabled webhooks subscribed to their types.
+// Every webhook receives events in a separate goroutine, so slow or failing
+// endpoints never block payments. A nil *Dispatcher does nothing, so it can be
+// used when webhooks are disabled.
+type Dispatcher struct {
+	repository db.RepositoryInterface
+	client     HTTP
+	// source is the source of events in cloudevents format
+	source string
+	// sleep waits between attempts, replaced in tests
+	sleep func(time.Duration)
+	wg    sync.WaitGroup
+	log   *logrus.Entry
+}
+
+// NewDispatcher creates a new Dispatcher. source is the source of events sent
+// to webhooks in cloudevents format.
+func NewDispatcher(repository db.RepositoryInterface, client HTTP, source string) *Dispatcher {
+	return &Dispatcher{
+		repository: repository,
+		client:     client,
+		source:     source,
+		sleep:      time.Sleep,
+		log:        logrus.WithFields(logrus.Fields{"service": "WebhookDispatcher"}),
+	}
+}
+
+// Publish sends an event of eventType with data to subscribed webhooks.
+// Webhooks are loaded synchronously, deliveries and their retries run in the
+// background.
+func (d *Dispatcher) Publish(ctx context.Context, eventType string, data interface{}) {
+	if d == nil {
+		return
+	}
+
+	webhooks, err := d.repository.GetWebhooks(ctx)
+	if err != nil {
+		d.log.WithFields(logrus.Fields{"err": err, "event": eventType}).Error("Error loading webhooks")
+		return
+	}
+
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	if err != nil {
+		d.log.WithFields(logrus.Fields{"err": err, "event": eventType}).Error("Error generating event ID")
+		return
+	}
+
+	// All webhooks receive the same event, bodies are encoded once per
+	// format and schema version
+	event := Event{ID: hex.EncodeToString(id), Type: eventType, CreatedAt: clock.Now(), Data: data}
+	bodies := map[string][]byte{}
+	for _, webhook := range webhooks {
+		if !webhook.Enabled || !webhook.Subscribed(eventType) {
+			continue
+		}
+
+		key := webhook.Format + "/" + webhook.SchemaVersion
+		body, ok := bodies[key]
+		if !ok {
+			body, err = d.encode(webhook, event)
+			if err != nil {
+				d.log.WithFields(logrus.Fields{"err": err, "event": eventType}).Error("Error encoding event")
+				return
+			}
+			bodies[key] = body
+		}
+
+		d.wg.Add(1)
+		go func(webhook *entities.Webhook) {
+			defer d.wg.Done()
+			d.deliver(webhook, eventType, body)
+		}(webhook)
+	}
+}
+
+// encode returns the body of the event in the format and schema version of
+// the webhook
+func (d *Dispatcher) encode(webhook *entities.Webhook, event Event) ([]byte, error) {
+	if webhook.Format != cloudevents.FormatCloudEvents {
+		event.SchemaVersion = webhook.SchemaVersion
+		return json.Marshal(event)
+	}
+
+	cloudEvent := cloudevents.NewFormatter(d.source, webhook.SchemaVersion).Event(event.Type, event.ID, event.Data)
+	cloudEvent.Time = event.CreatedAt
+	return json.Marshal(cloudEvent)
+}
+
+// Wait blocks until all deliveries in progress are finished
+func (d *Dispatcher) Wait() {
+	if d == nil {
+		return
+	}
+	d.wg.Wait()
+}
+
+// deliver sends body to the webhook retrying with exponential backoff
+func (d *Dispatcher) deliver(webhook *entities.Webhook, eventType string, body []byte) {
+	fields := logrus.Fields{"webhook_id": webhook.WebhookID, "event": eventType}
+	interval := time.Duration(webhook.RetryInterval) * time.Second
+
+	var err error
+	for attempt := 1; attempt <= webhook.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			d.sleep(interval)
+			interval *= 2
+		}
+
+		err = d.send(webhook, eventType, body)
+		if err == nil {
+			dispatcherMetrics.delivered.Inc()
+			return
+		}
+
+		d.log.WithFields(fields).WithFields(logrus.Fields{"err": err, "attempt": attempt}).Warn("Error delivering event to webhook")
+	}
+
+	dispatcherMetrics.failed.Inc()
+	d.log.WithFields(fields).WithFields(logrus.Fields{"err": err}).Error("Event not delivered to webhook")
+}
+
+// send makes a single delivery attempt, every 2xx response is a success
+func (d *Dispatcher) send(webhook *entities.Webhook, eventType string, body []byte) error {
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if webhook.Format == cloudevents.FormatCloudEvents {
+		req.Header.Set("Content-Type", cloudevents.ContentType)
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set(EventHeader, eventType)
+	if webhook.Secret != "" {
+		req.Header.Set(signer.SignatureHeader, signer.MAC(webhook.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellar/gateway/db/entities"
+	"github.com/stellar/gateway/mocks"
+	"github.com/stellar/gateway/protocols/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher(t *testing.T) {
+	var mutex sync.Mutex
+	requests := map[string][]*http.Request{}
+	bodies := map[string][][]byte{}
+	statuses := map[string]int{"/ok": http.StatusNoContent, "/failing": http.StatusInternalServerError, "/cloudevents": http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mutex.Lock()
+		requests[r.URL.Path] = append(requests[r.URL.Path], r)
+		bodies[r.URL.Path] = append(bodies[r.URL.Path], body)
+		mutex.Unlock()
+		w.WriteHeader(statuses[r.URL.Path])
+	}))
+	defer server.Close()
+
+	repository := new(mocks.MockRepository)
+	repository.On("GetWebhooks").Return([]*entities.Webhook{
+		{WebhookID: "ok", URL: server.URL + "/ok", Events: "payment_received,payment_sent", Secret: "secret", MaxAttempts: 3, RetryInterval: 1, Enabled: true},
+		{WebhookID: "failing", URL: server.URL + "/failing", Events: "payment_received", MaxAttempts: 3, RetryInterval: 1, Enabled: true},
+		{WebhookID: "disabled", URL: server.URL + "/disabled", Events: "payment_received", MaxAttempts: 3, Enabled: false},
+		{WebhookID: "other", URL: server.URL + "/other", Events: "submission_failed", MaxAttempts: 3, Enabled: true},
+		{WebhookID: "cloudevents", URL: server.URL + "/cloudevents", Events: "payment_received", Format: "cloudevents", SchemaVersion: "1", MaxAttempts: 1, Enabled: true},
+	}, nil)
+
+	var sleeps []time.Duration
+	dispatcher := NewDispatcher(repository, http.DefaultClient, "bridge-1")
+	dispatcher.sleep = func(d time.Duration) {
+		mutex.Lock()
+		sleeps = append(sleeps, d)
+		mutex.Unlock()
+	}
+
+	dispatcher.Publish(context.Background(), EventPaymentReceived, map[string]string{"id": "1"})
+	dispatcher.Wait()
+
+	require.Len(t, requests["/ok"], 1)
+	assert.Equal(t, EventPaymentReceived, requests["/ok"][0].Header.Get(EventHeader))
+	assert.Equal(t, signer.MAC("secret", bodies["/ok"][0]), requests["/ok"][0].Header.Get(signer.SignatureHeader))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(bodies["/ok"][0], &event))
+	assert.Equal(t, EventPaymentReceived, event.Type)
+	assert.Len(t, event.ID, 32)
+	assert.Equal(t, map[string]interface{}{"id": "1"}, event.Data)
+
+	// Failed deliveries are retried with exponential backoff
+	require.Len(t, requests["/failing"], 3)
+	assert.Equal(t, "", requests["/failing"][0].Header.Get(signer.SignatureHeader))
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sleeps)
+
+	// CloudEvents have the same ID as events of other webhooks
+	require.Len(t, requests["/cloudevents"], 1)
+	assert.Equal(t, "application/cloudevents+json", requests["/cloudevents"][0].Header.Get("Content-Type"))
+	var cloudEvent map[string]interface{}
+	require.NoError(t, json.Unmarshal(bodies["/cloudevents"][0], &cloudEvent))
+	assert.Equal(t, "1.0", cloudEvent["specversion"])
+	assert.Equal(t, event.ID, cloudEvent["id"])
+	assert.Equal(t, "bridge-1", cloudEvent["source"])
+	assert.Equal(t, "org.stellar.bridge.payment_received", cloudEvent["type"])
+	assert.Equal(t, "1", cloudEvent["schemaversion"])
+	assert.Equal(t, map[string]interface{}{"id": "1"}, cloudEvent["data"])
+
+	assert.Empty(t, requests["/disabled"])
+	assert.Empty(t, requests["/other"])
+
+	// A nil Dispatcher does nothing
+	var disabled *Dispatcher
+	disabled.Publish(context.Background(), EventPaymentSent, nil)
+	disabled.Wait()
+}
+
+func TestIsValidEvent(t *testing.T) {
+	assert.True(t, IsValidEvent(EventCompliancePending))
+	assert.False(t, IsValidEvent("payment"))
+}
@@ -0,0 +1,65 @@
+// Package webhooks delivers events of the bridge server to webhook endpoints
+// subscribed using /admin/webhooks. Every endpoint selects event types it
+// receives and has its own secret and retry policy.
+package webhooks
+
+import (
+	"time"
+)
+
+// Types of events
+const (
+	// EventPaymentReceived is sent when the payment listener processes a
+	// payment received by the receiving account
+	EventPaymentReceived = "payment_received"
+	// EventPaymentSent is sent when a transaction is included in a ledger
+	EventPaymentSent = "payment_sent"
+	// EventCompliancePending is sent when the compliance server of the
+	// receiver returns a pending response to /payment
+	EventCompliancePending = "compliance_pending"
+	// EventSubmissionFailed is sent when a submitted transaction fails
+	EventSubmissionFailed = "submission_failed"
+	// EventBalanceLow is sent when a balance monitored by balance_monitor
+	// falls below its thresholds
+	EventBalanceLow = "balance_low"
+	// EventBalanceRecovered is sent when a balance that was below its
+	// thresholds is above them again
+	EventBalanceRecovered = "balance_recovered"
+	// EventSEP24TransactionUpdated is sent when a SEP-24 transaction is
+	// created or its status changes
+	EventSEP24TransactionUpdated = "sep24_transaction_updated"
+)
+
+// Events are all types of events
+var Events = []string{
+	EventPaymentReceived,
+	EventPaymentSent,
+	EventCompliancePending,
+	EventSubmissionFailed,
+	EventBalanceLow,
+	EventBalanceRecovered,
+	EventSEP24TransactionUpdated,
+}
+
+// EventHeader is the name of the header with the type of the event
+const EventHeader = "X-Webhook-Event"
+
+// IsValidEvent returns true when eventType is one of Events
+func IsValidEvent(eventType string) bool {
+	for _, event := range Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a body of webhook requests in json format. Data depends on Type
+// and SchemaVersion.
+type Event struct {
+	ID            string      `json:"id"`
+	Type          string      `json:"type"`
+	SchemaVersion string      `json:"schema_version"`
+	CreatedAt     time.Time   `json:"created_at"`
+	Data          interface{} `json:"data"`
+}